@@ -0,0 +1,119 @@
+//go:build js && wasm
+
+// Command goreview-wasm exposes goreview's core to JavaScript, for the
+// browser playground and editor extensions. Build it with "make build-wasm"
+// and load it with the Go distribution's wasm_exec.js; it defines a global
+// goreview object whose functions return {result} or {error}:
+//
+//	goreview.buildPrompt(requestJSON)            {system, user}
+//	goreview.chunkDiff(diff, language, maxTokens) [{content, start_line, ...}]
+//	goreview.parseFile(code, filePath, language)  the file's structure
+//	goreview.estimateTokens(text, model)          a number
+//	goreview.report(resultJSON, format)           the rendered report
+//	goreview.formats()                            the report formats
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"syscall/js"
+
+	"github.com/JNZader/goreview/goreview/pkg/core"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+// chunk is the JSON form of a core.Chunk.
+type chunk struct {
+	Content    string `json:"content"`
+	StartLine  int    `json:"start_line"`
+	EndLine    int    `json:"end_line"`
+	Type       string `json:"type"`
+	Name       string `json:"name,omitempty"`
+	TokenCount int    `json:"token_count"`
+}
+
+func main() {
+	js.Global().Set("goreview", js.ValueOf(map[string]any{
+		"buildPrompt":    export(buildPrompt),
+		"chunkDiff":      export(chunkDiff),
+		"parseFile":      export(parseFile),
+		"estimateTokens": export(estimateTokens),
+		"report":         export(renderReport),
+		"formats":        export(formats),
+	}))
+	select {} // Keep the functions callable
+}
+
+// export wraps fn as a JavaScript function returning {result} or {error}.
+// Results are passed through JSON, so they arrive as plain objects.
+func export(fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		result, err := fn(args)
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		return map[string]any{"result": js.Global().Get("JSON").Call("parse", string(data))}
+	})
+}
+
+// arg returns the i-th argument, or undefined when missing.
+func arg(args []js.Value, i int) js.Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return js.Undefined()
+}
+
+// str returns the i-th argument as a string, empty when missing.
+func str(args []js.Value, i int) string {
+	if v := arg(args, i); v.Type() == js.TypeString {
+		return v.String()
+	}
+	return ""
+}
+
+func buildPrompt(args []js.Value) (any, error) {
+	var req core.ReviewRequest
+	if err := json.Unmarshal([]byte(str(args, 0)), &req); err != nil {
+		return nil, err
+	}
+	system, user := core.BuildPrompt(&req)
+	return map[string]string{"system": system, "user": user}, nil
+}
+
+func chunkDiff(args []js.Value) (any, error) {
+	maxTokens := 0
+	if v := arg(args, 2); v.Type() == js.TypeNumber {
+		maxTokens = v.Int()
+	}
+	chunks := core.ChunkDiff(str(args, 0), str(args, 1), maxTokens)
+	out := make([]chunk, len(chunks))
+	for i, c := range chunks {
+		out[i] = chunk{c.Content, c.StartLine, c.EndLine, c.Type.String(), c.Name, c.TokenCount}
+	}
+	return out, nil
+}
+
+func parseFile(args []js.Value) (any, error) {
+	return core.ParseFile(str(args, 0), str(args, 1), str(args, 2))
+}
+
+func estimateTokens(args []js.Value) (any, error) {
+	return core.EstimateTokens(str(args, 0), str(args, 1)), nil
+}
+
+func renderReport(args []js.Value) (any, error) {
+	result, err := reviewtypes.Decode(strings.NewReader(str(args, 0)))
+	if err != nil {
+		return nil, err
+	}
+	return core.Report(result, str(args, 1))
+}
+
+func formats(_ []js.Value) (any, error) {
+	return core.ReportFormats(), nil
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/artifact"
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/manifest"
+)
+
+// uploadReport uploads the report to an s3:// or gs:// destination.
+func uploadReport(ctx context.Context, cfg *config.Config, dest, output, format string) error {
+	opts := artifact.Options{
+		ContentType: artifact.ContentType(format),
+		Region:      cfg.Output.Artifacts.Region,
+		Endpoint:    cfg.Output.Artifacts.Endpoint,
+	}
+	if err := artifact.Upload(ctx, dest, []byte(output), opts); err != nil {
+		return fmt.Errorf("uploading report: %w", err)
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Report uploaded to %s\n", dest)
+	return nil
+}
+
+// uploadArtifact uploads the report to the store configured in
+// output.artifacts. Like the exporters, a failure is reported but doesn't
+// fail the review.
+func uploadArtifact(ctx context.Context, cfg *config.Config, output, format string) {
+	if cfg.Output.Artifacts.URL == "" {
+		return
+	}
+	dest := artifactURL(cfg.Output.Artifacts.URL, manifestRepository(ctx), format, time.Now())
+	if err := uploadReport(ctx, cfg, dest, output, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// artifactURL expands the placeholders of an artifact URL template. A
+// template ending in "/" is a prefix, completed with a file name unique to
+// the run.
+func artifactURL(tmpl string, repo manifest.Repository, format string, now time.Time) string {
+	if strings.HasSuffix(tmpl, "/") {
+		tmpl += "goreview-{timestamp}.{ext}"
+	}
+	commit := repo.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	return strings.NewReplacer(
+		"{repo}", pathSegment(repo.Name),
+		"{branch}", pathSegment(repo.Branch),
+		"{commit}", pathSegment(commit),
+		"{timestamp}", now.UTC().Format("20060102T150405Z"),
+		"{ext}", reportExtension(format),
+	).Replace(tmpl)
+}
+
+// pathSegment makes a value safe to use as a single object key segment.
+func pathSegment(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return strings.ReplaceAll(s, "/", "-")
+}
+
+// reportExtension is the file extension of a report format.
+func reportExtension(format string) string {
+	if format == "markdown" {
+		return "md"
+	}
+	return format
+}
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/audit"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/internal/tokenizer"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [directories...]",
+	Short: "Review whole files of a directory",
+	Long: `Review the complete content of the files under one or more directories
+(default: the current one), not just a diff, and roll the scores up by
+directory.
+
+Hidden directories, vendor, node_modules and testdata are skipped, as are
+binary files. --lang keeps only files in the given languages, by name or
+extension. The --max-files and --max-tokens caps keep the same files on
+every run: files are ordered by path and prioritized with source code first,
+then tests, configuration and documentation.
+
+Every file is reviewed like 'goreview review --full', so --context-radius
+sends neighboring files of the same package as context.
+
+Examples:
+  # Audit the payments module, Go and SQL only
+  goreview audit ./internal/payments --lang go,sql --max-files 200
+
+  # Audit two directories within a token budget, as JSON
+  goreview audit ./cmd ./pkg --max-tokens 200000 --format json -o audit.json`,
+	RunE: runAudit,
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().StringSlice("lang", nil, "Only audit files in these languages (e.g. go,sql)")
+	auditCmd.Flags().Int("max-files", 200, "Maximum files to review (0=unlimited)")
+	auditCmd.Flags().Int("max-tokens", 0, "Maximum estimated tokens of the reviewed files (0=unlimited)")
+	auditCmd.Flags().Int("context-radius", 0, "Neighboring files of the same package sent as context on each side")
+
+	auditCmd.Flags().StringP("format", "f", "markdown", "Output format (markdown, json, sarif)")
+	auditCmd.Flags().StringP("output", "o", "", "Write report to file, or upload it to an s3:// or gs:// URL")
+	auditCmd.Flags().String("fail-on", "", "Exit non-zero when issues reach this severity (default: review.fail_on)")
+
+	addProviderFlags(auditCmd)
+	addPersonalityFlag(auditCmd)
+	addTimeoutFlag(auditCmd, 30*time.Minute)
+	auditCmd.Flags().Int("concurrency", 0, "Max concurrent file reviews (0=auto)")
+	auditCmd.Flags().Bool("no-cache", false, "Disable caching")
+	auditCmd.Flags().String("preset", "standard", "Rule preset (minimal, standard, strict)")
+	auditCmd.Flags().String("progress", "auto", "Progress display on stderr (auto, tty, log, off)")
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	maxFiles, _ := cmd.Flags().GetInt("max-files")
+	maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+	radius, _ := cmd.Flags().GetInt("context-radius")
+	if maxFiles < 0 || maxTokens < 0 || radius < 0 {
+		return fmt.Errorf("--max-files, --max-tokens and --context-radius must not be negative")
+	}
+	dirs := args
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := applyProviderFlags(cmd, cfg); err != nil {
+		return err
+	}
+	if concurrency, _ := cmd.Flags().GetInt("concurrency"); concurrency > 0 {
+		cfg.Review.MaxConcurrency = concurrency
+	}
+	useReportFlags(cmd, cfg)
+	if preset, _ := cmd.Flags().GetString("preset"); cmd.Flags().Changed("preset") {
+		cfg.Rules.Preset = preset
+	}
+
+	languages, _ := cmd.Flags().GetStringSlice("lang")
+	m, err := loadIgnore(cfg)
+	if err != nil {
+		return err
+	}
+	opts := audit.Options{Languages: languages, MaxFiles: maxFiles, MaxTokens: maxTokens}
+	opts.Skip = func(path string) bool { return m.MatchFile(path).Ignored() }
+	sel, err := audit.Select(dirs, opts, tokenizer.NewEstimatorForModel(cfg.Provider.Model))
+	if err != nil {
+		return fmt.Errorf("selecting files: %w", err)
+	}
+	if isVerbose() {
+		for _, path := range sel.Skipped {
+			fmt.Fprintf(os.Stderr, "Skipping %s: %s\n", path, describeDecision(m.MatchFile(path)))
+		}
+		for _, f := range sel.Files {
+			fmt.Fprintf(os.Stderr, "Auditing %s (%s, ~%d tokens)\n", f.Path, f.Language, f.TokenCount)
+		}
+	}
+	if len(sel.Capped) > 0 && !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Warning: %d files left out by --max-files/--max-tokens\n", len(sel.Capped))
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	if len(sel.Files) == 0 {
+		return outputReport(ctx, cfg, &review.Result{Summary: "No files to audit."})
+	}
+
+	cfg.Review.Mode = "files"
+	cfg.Review.Files = sel.Paths()
+	cfg.Review.Full = true
+	cfg.Review.ContextRadius = radius
+	result, err := executeReview(ctx, cmd, cfg, nil)
+	if err != nil {
+		return err
+	}
+	printQuality(result.Quality)
+
+	rollup := audit.Rollup(result)
+	result.Summary = fmt.Sprintf("Audited %d files in %d directories (~%d tokens), %d left out by caps",
+		len(sel.Files), len(rollup), sel.Tokens, len(sel.Capped))
+	if !isQuiet() {
+		printRollup(rollup)
+	}
+	if err := outputReport(ctx, cfg, result); err != nil {
+		return err
+	}
+
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if failOn == "" {
+		failOn = cfg.Review.FailOn
+	}
+	checkFailThreshold(result, failOn)
+	return nil
+}
+
+// printRollup prints the per-directory scores to stderr, so they don't mix
+// with a report written to stdout.
+func printRollup(rollup []audit.DirScore) {
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DIRECTORY\tFILES\tSCORE\tISSUES\tCRITICAL\tERRORS\tFAILED")
+	for _, d := range rollup {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\n", d.Dir, d.Files, d.Score, d.Issues, d.Critical, d.Errors, d.Failed)
+	}
+	_ = w.Flush()
+}
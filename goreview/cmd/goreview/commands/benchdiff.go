@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/benchdiff"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+var benchdiffCmd = &cobra.Command{
+	Use:   "benchdiff",
+	Short: "Compare benchmarks of changed packages against a base branch",
+	Long: `Run the Go benchmarks of the packages touched by the diff on the base
+branch and on the working tree, and report significant regressions.
+
+The base is checked out at its merge-base with HEAD in a temporary git
+worktree. Each benchmark runs --count times on both sides; medians are
+compared with the Mann-Whitney U test, like benchstat. A metric (time/op,
+B/op or allocs/op) that gets worse by at least --threshold percent with a
+p-value below --alpha is reported as a performance issue.
+
+Examples:
+  # Compare against main
+  goreview benchdiff --base main
+
+  # Only the parser benchmarks, more runs, as SARIF
+  goreview benchdiff --base main --bench Parse --count 10 --format sarif -o bench.sarif`,
+	Args: cobra.NoArgs,
+	RunE: runBenchdiff,
+}
+
+func init() {
+	rootCmd.AddCommand(benchdiffCmd)
+
+	benchdiffCmd.Flags().String("base", "main", "Branch or commit to compare against")
+	benchdiffCmd.Flags().String("bench", ".", "Benchmarks to run (go test -bench pattern)")
+	benchdiffCmd.Flags().Int("count", benchdiff.DefaultCount, "Runs of each benchmark on each side")
+	benchdiffCmd.Flags().Float64("threshold", benchdiff.DefaultThreshold, "Minimum regression to report, in percent")
+	benchdiffCmd.Flags().Float64("alpha", benchdiff.DefaultAlpha, "Significance level of the comparison")
+	benchdiffCmd.Flags().StringP("format", "f", "markdown", "Output format (markdown, json, sarif)")
+	benchdiffCmd.Flags().StringP("output", "o", "", "Write report to file, or upload it to an s3:// or gs:// URL")
+	benchdiffCmd.Flags().String("fail-on", "", "Exit non-zero when regressions reach this severity (default: review.fail_on)")
+}
+
+func runBenchdiff(cmd *cobra.Command, _ []string) error {
+	base, _ := cmd.Flags().GetString("base")
+	pattern, _ := cmd.Flags().GetString("bench")
+	count, _ := cmd.Flags().GetInt("count")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	alpha, _ := cmd.Flags().GetFloat64("alpha")
+	if count < 2 {
+		return fmt.Errorf("--count must be at least 2 to compare samples")
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return fmt.Errorf("--alpha must be between 0 and 1")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	useReportFlags(cmd, cfg)
+	root, err := runGitCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("not a git repository")
+	}
+	repo := strings.TrimSpace(root)
+	mergeBase, err := runGitCommand("merge-base", base, "HEAD")
+	if err != nil {
+		return fmt.Errorf("finding merge-base with %s: %w", base, err)
+	}
+	baseRef := strings.TrimSpace(mergeBase)
+	diff, err := runGitCommand("diff", "--name-only", baseRef)
+	if err != nil {
+		return fmt.Errorf("listing changed files: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	packages := changedGoPackages(repo, strings.Split(diff, "\n"))
+	result := &review.Result{}
+	if len(packages) == 0 {
+		result.Summary = "No changed Go packages to benchmark"
+		return outputReport(ctx, cfg, result)
+	}
+
+	opts := benchdiff.Options{Root: repo, BaseRef: baseRef, Packages: packages, Bench: pattern, Count: count}
+	if !isQuiet() {
+		opts.Progress = func(pkg, side string) {
+			fmt.Fprintf(os.Stderr, "Benchmarking %s (%s)...\n", pkg, side)
+		}
+	}
+	before, after, err := benchdiff.Measure(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	comparisons := benchdiff.Compare(before, after)
+	if isVerbose() {
+		printBenchComparisons(comparisons, alpha)
+	}
+	regressions := benchdiff.Apply(result, comparisons, alpha, threshold)
+	result.Summary = fmt.Sprintf("%d benchmark regressions in %d metrics of %d packages compared with %s",
+		regressions, len(comparisons), len(packages), base)
+	if err := outputReport(ctx, cfg, result); err != nil {
+		return err
+	}
+
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if failOn == "" {
+		failOn = cfg.Review.FailOn
+	}
+	checkFailThreshold(result, failOn)
+	return nil
+}
+
+// changedGoPackages returns the directories of the changed Go files that
+// still exist in the working tree, sorted. Test data and vendored code are
+// skipped.
+func changedGoPackages(repo string, files []string) []string {
+	seen := make(map[string]bool)
+	var packages []string
+	for _, f := range files {
+		f = strings.TrimSpace(f)
+		if path.Ext(f) != ".go" || strings.HasPrefix(f, "vendor/") || strings.Contains("/"+f, "/testdata/") {
+			continue
+		}
+		dir := path.Dir(f)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if info, err := os.Stat(filepath.Join(repo, filepath.FromSlash(dir))); err == nil && info.IsDir() {
+			packages = append(packages, dir)
+		}
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// printBenchComparisons prints a benchstat-like table to stderr; "~" marks
+// differences that aren't significant.
+func printBenchComparisons(comparisons []benchdiff.Comparison, alpha float64) {
+	for _, c := range comparisons {
+		delta := "~"
+		if c.Significant(alpha) {
+			delta = benchdiff.FormatDelta(c.Delta)
+		}
+		fmt.Fprintf(os.Stderr, "%-40s %-10s %12s %12s %8s (p=%.3f n=%d+%d)\n",
+			c.Package+"/"+c.Name, benchdiff.UnitLabel(c.Unit),
+			benchdiff.FormatValue(c.Base, c.Unit), benchdiff.FormatValue(c.Head, c.Unit), delta, c.P, c.BaseN, c.HeadN)
+	}
+}
@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/cache"
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the review cache",
+	Long: `Inspect and manage the on-disk review cache, stored under the reviews
+directory of cache.dir.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show entry counts, size on disk, hit rate and TTL distribution",
+	Long: `Show the number of cached reviews, their size on disk, the hits and misses
+of all runs since the cache was last cleared, and how long the entries
+have left to live.
+
+Examples:
+  goreview cache stats
+  goreview cache stats --json`,
+	Args: cobra.NoArgs,
+	RunE: runCacheStats,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached review and reset the counters",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheClear,
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached reviews by age or path",
+	Long: `Remove cached reviews older than a duration, or of files matching a glob.
+When both are given, entries must match both. Without filters, expired
+entries are removed.
+
+Examples:
+  # Remove expired entries
+  goreview cache prune
+
+  # Remove entries older than a week
+  goreview cache prune --older-than 168h
+
+  # Remove the entries of generated files, showing what would go first
+  goreview cache prune --path "**/*.pb.go" --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runCachePrune,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd, cachePruneCmd)
+
+	cacheStatsCmd.Flags().Bool("json", false, "Output as JSON")
+
+	cachePruneCmd.Flags().Duration("older-than", 0, "Remove entries created longer ago than this")
+	cachePruneCmd.Flags().String("path", "", "Remove entries of files matching this glob")
+	cachePruneCmd.Flags().Bool("dry-run", false, "Show what would be removed without removing it")
+}
+
+// reviewCacheDir is where reviews are cached, apart from the other data
+// kept in cache.dir.
+func reviewCacheDir(cfg *config.Config) string {
+	return filepath.Join(cfg.Cache.Dir, "reviews")
+}
+
+// openReviewCache opens the review cache for management.
+func openReviewCache() (*cache.FileCache, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	return cache.NewFileCache(reviewCacheDir(cfg), cfg.Cache.TTL)
+}
+
+// cacheReport is the output of cache stats.
+type cacheReport struct {
+	Dir       string            `json:"dir"`
+	Entries   int               `json:"entries"`
+	SizeBytes int64             `json:"size_bytes"`
+	Hits      int64             `json:"hits"`
+	Misses    int64             `json:"misses"`
+	HitRate   float64           `json:"hit_rate"`
+	Since     *time.Time        `json:"since,omitempty"`
+	TTL       []cache.TTLBucket `json:"ttl"`
+}
+
+func runCacheStats(cmd *cobra.Command, _ []string) error {
+	fc, err := openReviewCache()
+	if err != nil {
+		return err
+	}
+	entries, err := fc.Entries()
+	if err != nil {
+		return err
+	}
+	counters, err := fc.Counters()
+	if err != nil {
+		return fmt.Errorf("reading cache counters: %w", err)
+	}
+
+	report := cacheReport{
+		Dir:     fc.Dir(),
+		Entries: len(entries),
+		Hits:    counters.Hits,
+		Misses:  counters.Misses,
+		HitRate: counters.HitRate(),
+		TTL:     cache.TTLDistribution(entries, time.Now()),
+	}
+	for _, e := range entries {
+		report.SizeBytes += e.Size
+	}
+	if !counters.Since.IsZero() {
+		report.Since = &counters.Since
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Cache: %s\n", report.Dir)
+	fmt.Printf("Entries: %d (%s)\n", report.Entries, formatSize(report.SizeBytes))
+	fmt.Printf("Hits: %d  Misses: %d  Hit rate: %.1f%%", report.Hits, report.Misses, report.HitRate)
+	if report.Since != nil {
+		fmt.Printf("  (since %s)", report.Since.Local().Format("2006-01-02"))
+	}
+	fmt.Println()
+	fmt.Println("\nTime to live:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, b := range report.TTL {
+		_, _ = fmt.Fprintf(w, "  %s\t%d\n", b.Label, b.Entries)
+	}
+	return w.Flush()
+}
+
+func runCacheClear(_ *cobra.Command, _ []string) error {
+	fc, err := openReviewCache()
+	if err != nil {
+		return err
+	}
+	n := len(mustEntries(fc))
+	if err := fc.Clear(); err != nil {
+		return fmt.Errorf("clearing cache: %w", err)
+	}
+	if !isQuiet() {
+		fmt.Printf("Removed %d cached reviews\n", n)
+	}
+	return nil
+}
+
+func runCachePrune(cmd *cobra.Command, _ []string) error {
+	olderThan, _ := cmd.Flags().GetDuration("older-than")
+	pattern, _ := cmd.Flags().GetString("path")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if olderThan < 0 {
+		return fmt.Errorf("--older-than must not be negative")
+	}
+
+	fc, err := openReviewCache()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	match := func(e cache.EntryInfo) bool {
+		if olderThan == 0 && pattern == "" {
+			return now.After(e.ExpiresAt)
+		}
+		if olderThan > 0 && now.Sub(e.CreatedAt) < olderThan {
+			return false
+		}
+		return pattern == "" || (e.File != "" && rules.MatchGlob(pattern, e.File))
+	}
+
+	if dryRun {
+		var n int
+		var size int64
+		for _, e := range mustEntries(fc) {
+			if match(e) {
+				n++
+				size += e.Size
+				if isVerbose() {
+					fmt.Printf("  %s %s\n", e.Key, e.File)
+				}
+			}
+		}
+		fmt.Printf("Would remove %d cached reviews (%s)\n", n, formatSize(size))
+		return nil
+	}
+
+	removed, freed, err := fc.Prune(match)
+	if err != nil {
+		return fmt.Errorf("pruning cache: %w", err)
+	}
+	if !isQuiet() {
+		fmt.Printf("Removed %d cached reviews (%s)\n", removed, formatSize(freed))
+	}
+	return nil
+}
+
+// mustEntries lists the cache entries, treating an unreadable cache as
+// empty.
+func mustEntries(fc *cache.FileCache) []cache.EntryInfo {
+	entries, _ := fc.Entries()
+	return entries
+}
+
+// formatSize formats a byte count for humans.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
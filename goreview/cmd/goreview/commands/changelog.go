@@ -40,7 +40,18 @@ Examples:
   goreview changelog --output=CHANGELOG.md
 
   # Append to existing changelog
-  goreview changelog --append`,
+  goreview changelog --append
+
+  # Monorepo: a section per package touched by the commits
+  goreview changelog --scope-from-paths
+
+  # Monorepo: append to packages/<name>/CHANGELOG.md of each package
+  goreview changelog --scope-from-paths --package-files --append
+
+Packages are the directories listed in changelog.packages of .goreview.yaml,
+plus those matching changelog.package_globs (default: packages/*, apps/*).
+A commit touching several packages is in each of their changelogs; commits
+touching none are listed under "repository".`,
 	RunE: runChangelog,
 }
 
@@ -61,10 +72,18 @@ func init() {
 	changelogCmd.Flags().Bool("no-header", false, "Skip the version header")
 	changelogCmd.Flags().Bool("no-date", false, "Skip the date in header")
 	changelogCmd.Flags().Bool("no-links", false, "Skip commit links")
+
+	// Monorepo flags
+	changelogCmd.Flags().Bool("scope-from-paths", false, "Split the changelog by the packages each commit touches")
+	changelogCmd.Flags().Bool("package-files", false, "With --scope-from-paths, write each package's CHANGELOG.md")
+
+	// Changelogs are built from commit messages without a provider, so only
+	// the timeout is shared with the provider-backed commands
+	addTimeoutFlag(changelogCmd, 30*time.Second)
 }
 
 func runChangelog(cmd *cobra.Command, _ []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := commandContext(cmd)
 	defer cancel()
 
 	gitRepo, err := git.NewRepo(".")
@@ -94,14 +113,16 @@ func runChangelog(cmd *cobra.Command, _ []string) error {
 		fmt.Fprintf(os.Stderr, "Found %d commits\n", len(commits))
 	}
 
-	grouped := groupCommitsByType(commits)
 	opts := changelogOptions{
 		Version:  version,
 		NoHeader: flags.noHeader,
 		NoDate:   flags.noDate,
 		NoLinks:  flags.noLinks,
 	}
-	changelog := generateChangelog(grouped, opts)
+	if flags.scopeFromPaths {
+		return runPackageChangelog(ctx, gitRepo, commits, flags, opts)
+	}
+	changelog := generateChangelog(groupCommitsByType(commits), opts)
 
 	if flags.output != "" {
 		return writeChangelog(flags.output, changelog, flags.appendFile)
@@ -121,6 +142,9 @@ type changelogFlags struct {
 	noHeader   bool
 	noDate     bool
 	noLinks    bool
+
+	scopeFromPaths bool
+	packageFiles   bool
 }
 
 func parseChangelogFlags(cmd *cobra.Command) changelogFlags {
@@ -133,6 +157,8 @@ func parseChangelogFlags(cmd *cobra.Command) changelogFlags {
 	noHeader, _ := cmd.Flags().GetBool("no-header")
 	noDate, _ := cmd.Flags().GetBool("no-date")
 	noLinks, _ := cmd.Flags().GetBool("no-links")
+	scopeFromPaths, _ := cmd.Flags().GetBool("scope-from-paths")
+	packageFiles, _ := cmd.Flags().GetBool("package-files")
 
 	return changelogFlags{
 		from:       from,
@@ -144,7 +170,41 @@ func parseChangelogFlags(cmd *cobra.Command) changelogFlags {
 		noHeader:   noHeader,
 		noDate:     noDate,
 		noLinks:    noLinks,
+
+		scopeFromPaths: scopeFromPaths,
+		packageFiles:   packageFiles,
+	}
+}
+
+// runPackageChangelog writes the changelog of each package the commits
+// touch, as sections of one changelog or as files in the packages.
+func runPackageChangelog(ctx context.Context, gitRepo *git.Repo, commits []git.Commit, flags changelogFlags, opts changelogOptions) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	packages, err := splitByPackage(ctx, gitRepo, commits, newPackageMapper(cfg.Changelog))
+	if err != nil {
+		return err
+	}
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Commits touch %d packages\n", len(packages))
+	}
+
+	if flags.packageFiles {
+		root, err := gitRepo.GetRepoRoot(ctx)
+		if err != nil {
+			return fmt.Errorf("getting repository root: %w", err)
+		}
+		return writePackageChangelogs(root, packages, opts, flags.output, flags.appendFile)
 	}
+
+	changelog := generatePackageChangelogs(packages, opts)
+	if flags.output != "" {
+		return writeChangelog(flags.output, changelog, flags.appendFile)
+	}
+	fmt.Print(changelog)
+	return nil
 }
 
 func resolveChangelogRange(ctx context.Context, gitRepo *git.Repo, flags changelogFlags) (from, version string, err error) {
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// rootPackage titles the commits that touch no package.
+const rootPackage = "repository"
+
+// changelogPackage is a package of a monorepo and the commits touching it.
+type changelogPackage struct {
+	Name    string
+	Dir     string
+	Commits []git.Commit
+}
+
+// packageMapper finds the package containing a path, from the configured
+// packages or else from the package globs.
+type packageMapper struct {
+	packages []config.ChangelogPackage
+	globs    []string
+}
+
+func newPackageMapper(cfg config.ChangelogConfig) *packageMapper {
+	packages := make([]config.ChangelogPackage, len(cfg.Packages))
+	for i, p := range cfg.Packages {
+		packages[i] = config.ChangelogPackage{Name: p.PackageName(), Path: path.Clean(strings.TrimPrefix(p.Path, "./"))}
+	}
+	// The deepest package wins when packages are nested
+	sort.SliceStable(packages, func(i, j int) bool { return len(packages[i].Path) > len(packages[j].Path) })
+	return &packageMapper{packages: packages, globs: cfg.PackageGlobs}
+}
+
+// packageOf returns the name and directory of the package containing file.
+func (m *packageMapper) packageOf(file string) (name, dir string, ok bool) {
+	for _, p := range m.packages {
+		if strings.HasPrefix(file, p.Path+"/") {
+			return p.Name, p.Path, true
+		}
+	}
+	parts := strings.Split(file, "/")
+	for _, g := range m.globs {
+		n := strings.Count(path.Clean(g), "/") + 1
+		// The file must be inside the matched directory
+		if len(parts) <= n {
+			continue
+		}
+		dir := strings.Join(parts[:n], "/")
+		if matched, _ := path.Match(g, dir); matched {
+			return path.Base(dir), dir, true
+		}
+	}
+	return "", "", false
+}
+
+// splitByPackage groups the commits by the packages their files touch. A
+// commit touching several packages is in each; commits touching none are in
+// the root package, which sorts last.
+func splitByPackage(ctx context.Context, repo *git.Repo, commits []git.Commit, mapper *packageMapper) ([]changelogPackage, error) {
+	byName := make(map[string]*changelogPackage)
+	add := func(name, dir string, c git.Commit) {
+		p := byName[name]
+		if p == nil {
+			p = &changelogPackage{Name: name, Dir: dir}
+			byName[name] = p
+		}
+		p.Commits = append(p.Commits, c)
+	}
+
+	for _, c := range commits {
+		files, err := repo.GetCommitFiles(ctx, c.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("getting files of commit %s: %w", c.ShortHash, err)
+		}
+		touched := make(map[string]bool)
+		for _, f := range files {
+			if name, dir, ok := mapper.packageOf(f); ok && !touched[name] {
+				touched[name] = true
+				add(name, dir, c)
+			}
+		}
+		if len(touched) == 0 {
+			add(rootPackage, "", c)
+		}
+	}
+
+	packages := make([]changelogPackage, 0, len(byName))
+	for _, p := range byName {
+		packages = append(packages, *p)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		if (packages[i].Dir == "") != (packages[j].Dir == "") {
+			return packages[j].Dir == ""
+		}
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+// generatePackageChangelogs writes a section per package, titled with the
+// package's name and the version.
+func generatePackageChangelogs(packages []changelogPackage, opts changelogOptions) string {
+	var sb strings.Builder
+	for _, p := range packages {
+		sb.WriteString(generateChangelog(groupCommitsByType(p.Commits), packageChangelogOptions(p, opts)))
+	}
+	return sb.String()
+}
+
+func packageChangelogOptions(p changelogPackage, opts changelogOptions) changelogOptions {
+	opts.NoHeader = false
+	opts.Version = strings.TrimSpace(p.Name + " " + opts.Version)
+	return opts
+}
+
+// writePackageChangelogs writes each package's changelog to CHANGELOG.md in
+// its directory under the repository root. The commits touching no package
+// go to output, or stdout.
+func writePackageChangelogs(root string, packages []changelogPackage, opts changelogOptions, output string, appendFile bool) error {
+	for _, p := range packages {
+		content := generateChangelog(groupCommitsByType(p.Commits), opts)
+		if p.Dir == "" {
+			if output == "" {
+				fmt.Print(content)
+				continue
+			}
+			if err := writeChangelog(output, content, appendFile); err != nil {
+				return err
+			}
+			continue
+		}
+		dir := filepath.Join(root, filepath.FromSlash(p.Dir))
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("creating %s: %w", p.Dir, err)
+		}
+		if err := writeChangelog(filepath.Join(dir, "CHANGELOG.md"), content, appendFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package commands
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/JNZader/goreview/goreview/internal/config"
 	"github.com/JNZader/goreview/goreview/internal/git"
 )
 
@@ -294,6 +296,50 @@ func TestFilterNonBreaking(t *testing.T) {
 	}
 }
 
+func TestPackageMapper(t *testing.T) {
+	mapper := newPackageMapper(config.ChangelogConfig{
+		Packages: []config.ChangelogPackage{
+			{Path: "./services"},
+			{Name: "billing-api", Path: "services/billing/"},
+		},
+		PackageGlobs: []string{"packages/*", "apps/*"},
+	})
+
+	tests := []struct {
+		file, name, dir string
+	}{
+		{"services/billing/main.go", "billing-api", "services/billing"},
+		{"services/auth/main.go", "services", "services"},
+		{"packages/ui/src/button.tsx", "ui", "packages/ui"},
+		{"apps/web/package.json", "web", "apps/web"},
+		{"packages/README.md", "", ""},
+		{"go.mod", "", ""},
+	}
+	for _, tt := range tests {
+		name, dir, ok := mapper.packageOf(tt.file)
+		if name != tt.name || dir != tt.dir || ok != (tt.name != "") {
+			t.Errorf("packageOf(%q) = %q, %q, %v; want %q, %q", tt.file, name, dir, ok, tt.name, tt.dir)
+		}
+	}
+}
+
+func TestGeneratePackageChangelogs(t *testing.T) {
+	packages := []changelogPackage{
+		{Name: "ui", Dir: "packages/ui", Commits: []git.Commit{{ShortHash: "a1", Subject: "feat: add button"}}},
+		{Name: rootPackage, Commits: []git.Commit{{ShortHash: "b2", Subject: "ci: cache modules"}}},
+	}
+	out := generatePackageChangelogs(packages, changelogOptions{Version: "v1.2.0", NoHeader: true, NoDate: true})
+
+	for _, want := range []string{"## ui v1.2.0\n", "- add button (a1)", "## repository v1.2.0\n", "- cache modules (b2)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("changelog missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "## ui") > strings.Index(out, "## repository") {
+		t.Errorf("root section should come last:\n%s", out)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
 }
@@ -306,3 +352,88 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestKeepChangelogUpdate(t *testing.T) {
+	existing := `# Changelog
+
+Notable changes.
+
+## [Unreleased]
+
+### Fixed
+
+- handle empty diffs (a1)
+
+## [v1.0.0] - 2026-01-10
+
+### Added
+
+- first release (b2)
+  with a wrapped line
+
+[Unreleased]: https://github.com/o/r/compare/v1.0.0...HEAD
+[v1.0.0]: https://github.com/o/r/releases/tag/v1.0.0
+`
+	cl := parseKeepChangelog(existing)
+	added := cl.addUnreleased(keepChangelogEntries([]git.Commit{
+		{ShortHash: "d4", Subject: "chore: bump deps"},
+		{ShortHash: "c3", Subject: "feat(api)!: drop v1 routes"},
+		{ShortHash: "b2", Subject: "feat: first release"},
+		{ShortHash: "a1", Subject: "fix: handle empty diffs"},
+		{ShortHash: "e5", Subject: "feat: add search"},
+	}, false))
+	if added != 2 {
+		t.Errorf("added %d entries, want 2 (search, breaking routes)", added)
+	}
+
+	if err := cl.release("v1.1.0", "2026-02-01"); err != nil {
+		t.Fatal(err)
+	}
+	want := `# Changelog
+
+Notable changes.
+
+## [Unreleased]
+
+## [v1.1.0] - 2026-02-01
+
+### Added
+
+- add search (e5)
+
+### Changed
+
+- **api:** **BREAKING:** drop v1 routes (c3)
+
+### Fixed
+
+- handle empty diffs (a1)
+
+## [v1.0.0] - 2026-01-10
+
+### Added
+
+- first release (b2)
+  with a wrapped line
+
+[Unreleased]: https://github.com/o/r/compare/v1.1.0...HEAD
+[v1.1.0]: https://github.com/o/r/compare/v1.0.0...v1.1.0
+[v1.0.0]: https://github.com/o/r/releases/tag/v1.0.0
+`
+	if got := cl.String(); got != want {
+		t.Errorf("changelog =\n%s\nwant\n%s", got, want)
+	}
+
+	if err := cl.release("v1.2.0", "2026-03-01"); err == nil {
+		t.Error("released with no unreleased changes")
+	}
+}
+
+func TestKeepChangelogCreatesFile(t *testing.T) {
+	cl := parseKeepChangelog("")
+	cl.addUnreleased(keepChangelogEntries([]git.Commit{{ShortHash: "a1", Subject: "fix: crash"}}, true))
+	got := cl.String()
+	if !strings.HasPrefix(got, "# Changelog\n") || !strings.Contains(got, "## [Unreleased]\n\n### Fixed\n\n- crash\n") {
+		t.Errorf("new changelog =\n%s", got)
+	}
+}
@@ -0,0 +1,378 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+var changelogUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Add new commits to a Keep a Changelog file",
+	Long: `Maintain a CHANGELOG.md in the Keep a Changelog format
+(https://keepachangelog.com).
+
+The commits since the last tag are added under [Unreleased], in the group
+of their Conventional Commits type: feat under Added, fix under Fixed,
+perf, refactor and non-conventional commits under Changed, revert under
+Removed. Breaking changes go under Changed whatever their type; other types
+(docs, test, ci, chore...) are left out.
+
+Entries already in the file are not added again, so the command can run on
+every merge. The file is created when missing.
+
+With --release, the unreleased entries then move to a new section for the
+version, dated today, leaving [Unreleased] empty. Compare links at the end
+of the file are updated too.
+
+Examples:
+  # Add the commits since the last tag to [Unreleased]
+  goreview changelog update
+
+  # Cut a release
+  goreview changelog update --release v1.4.0
+
+  # Another file, from a given ref
+  goreview changelog update --file docs/CHANGELOG.md --from v1.2.0`,
+	Args: cobra.NoArgs,
+	RunE: runChangelogUpdate,
+}
+
+func init() {
+	changelogCmd.AddCommand(changelogUpdateCmd)
+
+	changelogUpdateCmd.Flags().String("file", "CHANGELOG.md", "Changelog file to update")
+	changelogUpdateCmd.Flags().String("from", "", "Start reference (default: last tag)")
+	changelogUpdateCmd.Flags().String("to", "HEAD", "End reference")
+	changelogUpdateCmd.Flags().String("release", "", "Move the unreleased entries to this version")
+	changelogUpdateCmd.Flags().Bool("no-links", false, "Skip commit hashes in entries")
+	addTimeoutFlag(changelogUpdateCmd, 30*time.Second)
+}
+
+func runChangelogUpdate(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	file, _ := cmd.Flags().GetString("file")
+	release, _ := cmd.Flags().GetString("release")
+	noLinks, _ := cmd.Flags().GetBool("no-links")
+	flags := changelogFlags{unreleased: true}
+	flags.from, _ = cmd.Flags().GetString("from")
+	flags.to, _ = cmd.Flags().GetString("to")
+	if flags.from != "" {
+		flags.unreleased = false
+	}
+
+	gitRepo, err := git.NewRepo(".")
+	if err != nil {
+		return fmt.Errorf("initializing git: %w", err)
+	}
+	from, _, err := resolveChangelogRange(ctx, gitRepo, flags)
+	if err != nil {
+		return err
+	}
+	commits, err := gitRepo.GetCommits(ctx, from, flags.to)
+	if err != nil {
+		return fmt.Errorf("getting commits: %w", err)
+	}
+
+	content, err := os.ReadFile(filepath.Clean(file)) // #nosec G304 - user-specified changelog
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading changelog: %w", err)
+	}
+	cl := parseKeepChangelog(string(content))
+
+	added := cl.addUnreleased(keepChangelogEntries(commits, noLinks))
+	if release != "" {
+		if err := cl.release(release, time.Now().Format("2006-01-02")); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(filepath.Clean(file), []byte(cl.String()), 0600); err != nil {
+		return fmt.Errorf("writing changelog: %w", err)
+	}
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Added %d entries to %s", added, file)
+		if release != "" {
+			fmt.Fprintf(os.Stderr, ", released as %s", release)
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+	return nil
+}
+
+// keepChangelogGroups are the Keep a Changelog change groups, in order.
+var keepChangelogGroups = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// keepChangelogGroup maps Conventional Commits types to change groups.
+// Types left out (docs, test, ci, chore...) are not notable to users and
+// only get an entry when breaking.
+var keepChangelogGroup = map[string]string{
+	"feat":     "Added",
+	"fix":      "Fixed",
+	"perf":     "Changed",
+	"refactor": "Changed",
+	"revert":   "Removed",
+	"other":    "Changed",
+}
+
+const keepChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// unreleasedName names the section of changes not released yet.
+const unreleasedName = "Unreleased"
+
+var (
+	releaseHeadingRegex = regexp.MustCompile(`^##\s+\[?([^\]\s]+)\]?`)
+	linkRefRegex        = regexp.MustCompile(`^\[([^\]]+)\]:\s*(\S+)`)
+	compareLinkRegex    = regexp.MustCompile(`^(.*/compare/)(.+)\.\.\.HEAD$`)
+)
+
+// keepChangelog is a parsed Keep a Changelog file.
+type keepChangelog struct {
+	// header is the text before the first release
+	header []string
+	// releases are the sections, newest first
+	releases []*changelogRelease
+	// links are the link reference definitions closing the file
+	links []string
+}
+
+// changelogRelease is the section of a version, or of the unreleased changes.
+type changelogRelease struct {
+	name    string
+	heading string
+	// notes is the text before the first group
+	notes  []string
+	groups []*changelogGroup
+}
+
+// changelogGroup is a group of entries, like "### Added".
+type changelogGroup struct {
+	title   string
+	entries []string
+}
+
+func parseKeepChangelog(content string) *keepChangelog {
+	cl := &keepChangelog{}
+	if strings.TrimSpace(content) == "" {
+		cl.header = strings.Split(strings.TrimRight(keepChangelogHeader, "\n"), "\n")
+		return cl
+	}
+
+	var rel *changelogRelease
+	var group *changelogGroup
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		switch {
+		case linkRefRegex.MatchString(line):
+			cl.links = append(cl.links, line)
+		case releaseHeadingRegex.MatchString(line):
+			rel = &changelogRelease{name: releaseHeadingRegex.FindStringSubmatch(line)[1], heading: line}
+			group = nil
+			cl.releases = append(cl.releases, rel)
+		case rel == nil:
+			cl.header = append(cl.header, line)
+		case strings.HasPrefix(line, "### "):
+			group = rel.group(strings.TrimSpace(strings.TrimPrefix(line, "### ")))
+		case group == nil:
+			rel.notes = append(rel.notes, line)
+		case strings.TrimSpace(line) == "":
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || len(group.entries) == 0:
+			group.entries = append(group.entries, line)
+		default:
+			// A continuation of the previous entry
+			group.entries[len(group.entries)-1] += "\n" + line
+		}
+	}
+	return cl
+}
+
+// group returns the release's group with the title, adding it in Keep a
+// Changelog order when missing.
+func (r *changelogRelease) group(title string) *changelogGroup {
+	for _, g := range r.groups {
+		if g.title == title {
+			return g
+		}
+	}
+	g := &changelogGroup{title: title}
+	pos := len(r.groups)
+	if rank := groupRank(title); rank >= 0 {
+		for i, existing := range r.groups {
+			if other := groupRank(existing.title); other > rank {
+				pos = i
+				break
+			}
+		}
+	}
+	r.groups = append(r.groups[:pos], append([]*changelogGroup{g}, r.groups[pos:]...)...)
+	return g
+}
+
+func groupRank(title string) int {
+	for i, t := range keepChangelogGroups {
+		if strings.EqualFold(t, title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// unreleased returns the unreleased section, adding it first when missing.
+func (cl *keepChangelog) unreleased() *changelogRelease {
+	for _, r := range cl.releases {
+		if strings.EqualFold(r.name, unreleasedName) {
+			return r
+		}
+	}
+	r := &changelogRelease{name: unreleasedName, heading: "## [" + unreleasedName + "]"}
+	cl.releases = append([]*changelogRelease{r}, cl.releases...)
+	return r
+}
+
+// addUnreleased adds the entries missing from the whole file under
+// [Unreleased], returning how many were added.
+func (cl *keepChangelog) addUnreleased(entries []keepChangelogEntry) int {
+	existing := make(map[string]bool)
+	for _, r := range cl.releases {
+		for _, g := range r.groups {
+			for _, e := range g.entries {
+				existing[entryKey(e)] = true
+			}
+		}
+	}
+
+	added := 0
+	for _, e := range entries {
+		if existing[entryKey(e.text)] {
+			continue
+		}
+		existing[entryKey(e.text)] = true
+		g := cl.unreleased().group(e.group)
+		g.entries = append(g.entries, e.text)
+		added++
+	}
+	return added
+}
+
+// entryKey identifies an entry regardless of list marker and spacing.
+func entryKey(entry string) string {
+	first, _, _ := strings.Cut(entry, "\n")
+	first = strings.TrimSpace(first)
+	first = strings.TrimPrefix(strings.TrimPrefix(first, "- "), "* ")
+	return strings.Join(strings.Fields(first), " ")
+}
+
+// release moves the unreleased entries to a new section for the version,
+// dated date, and points the compare links at it.
+func (cl *keepChangelog) release(version, date string) error {
+	for _, r := range cl.releases {
+		if r.name == version {
+			return fmt.Errorf("version %s is already in the changelog", version)
+		}
+	}
+	unreleased := cl.unreleased()
+	if len(unreleased.groups) == 0 {
+		return fmt.Errorf("no unreleased changes to release")
+	}
+
+	rel := &changelogRelease{
+		name:    version,
+		heading: fmt.Sprintf("## [%s] - %s", version, date),
+		notes:   unreleased.notes,
+		groups:  unreleased.groups,
+	}
+	unreleased.notes, unreleased.groups = nil, nil
+	for i, r := range cl.releases {
+		if r == unreleased {
+			cl.releases = append(cl.releases[:i+1], append([]*changelogRelease{rel}, cl.releases[i+1:]...)...)
+			break
+		}
+	}
+
+	// [Unreleased]: .../compare/v1.0.0...HEAD becomes .../compare/<version>...HEAD,
+	// and [<version>]: .../compare/v1.0.0...<version> is added
+	for i, l := range cl.links {
+		m := linkRefRegex.FindStringSubmatch(l)
+		if !strings.EqualFold(m[1], unreleasedName) {
+			continue
+		}
+		if c := compareLinkRegex.FindStringSubmatch(m[2]); c != nil {
+			cl.links[i] = fmt.Sprintf("[%s]: %s%s...HEAD", m[1], c[1], version)
+			link := fmt.Sprintf("[%s]: %s%s...%s", version, c[1], c[2], version)
+			cl.links = append(cl.links[:i+1], append([]string{link}, cl.links[i+1:]...)...)
+		}
+		break
+	}
+	return nil
+}
+
+// String renders the changelog.
+func (cl *keepChangelog) String() string {
+	var sb strings.Builder
+	writeLines(&sb, cl.header)
+	for _, r := range cl.releases {
+		sb.WriteString(r.heading + "\n\n")
+		writeLines(&sb, r.notes)
+		for _, g := range r.groups {
+			if len(g.entries) == 0 {
+				continue
+			}
+			sb.WriteString("### " + g.title + "\n\n")
+			sb.WriteString(strings.Join(g.entries, "\n") + "\n\n")
+		}
+	}
+	if len(cl.links) > 0 {
+		sb.WriteString(strings.Join(cl.links, "\n") + "\n")
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// writeLines writes lines without their surrounding blank lines, followed by
+// one blank line.
+func writeLines(sb *strings.Builder, lines []string) {
+	text := strings.Trim(strings.Join(lines, "\n"), "\n")
+	if strings.TrimSpace(text) != "" {
+		sb.WriteString(text + "\n\n")
+	}
+}
+
+// keepChangelogEntry is a changelog line for a commit.
+type keepChangelogEntry struct {
+	group string
+	text  string
+}
+
+// keepChangelogEntries returns the entries for the commits, oldest first.
+func keepChangelogEntries(commits []git.Commit, noLinks bool) []keepChangelogEntry {
+	entries := make([]keepChangelogEntry, 0, len(commits))
+	for i := len(commits) - 1; i >= 0; i-- {
+		cc := parseConventionalCommitMsg(commits[i])
+		group, ok := keepChangelogGroup[cc.Type]
+		if cc.Breaking {
+			group, ok = "Changed", true
+			cc.Description = "**BREAKING:** " + cc.Description
+		}
+		if !ok {
+			continue
+		}
+		var sb strings.Builder
+		writeCommitLine(&sb, cc, noLinks)
+		entries = append(entries, keepChangelogEntry{group: group, text: strings.TrimRight(sb.String(), "\n")})
+	}
+	return entries
+}
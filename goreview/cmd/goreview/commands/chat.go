@@ -0,0 +1,555 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/memory"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+var chatCmd = &cobra.Command{
+	Use:   "chat [files...]",
+	Short: "Pair-program on the current changes in an interactive session",
+	Long: `Start an interactive session about the current changes. The diff, the
+structure of the changed code and the conversation stay loaded, so
+questions can build on earlier answers.
+
+Slash commands:
+  /review       Review the changes and list the issues by number
+  /fix <n>      Show the fix of issue n of the last review and apply it
+  /explain <n>  Explain issue n of the last review
+  /doc          Document the changes
+  /reload       Load the diff again, e.g. after editing
+  /help         List the commands
+  /quit         End the session
+
+The conversation is saved to session memory (memory.dir/sessions), linked
+to the current branch and commit. Resume it with --session, or find it with
+goreview memory recall --branch.
+
+Examples:
+  # Talk about the staged changes
+  goreview chat
+
+  # About the changes of the branch against main
+  goreview chat --branch main
+
+  # Resume an earlier session
+  goreview chat --list
+  goreview chat --session 0b8e2c4a-...`,
+	RunE: runChat,
+}
+
+// Limits of the context sent with each chat message
+const (
+	chatDiffBudget    = 12000
+	chatASTBudget     = 4000
+	chatHistoryTurns  = 12
+	chatEntryType     = "chat"
+	chatEntryIDFormat = "chat-%04d"
+)
+
+func init() {
+	rootCmd.AddCommand(chatCmd)
+
+	chatCmd.Flags().Bool("staged", false, "Talk about staged changes (default)")
+	chatCmd.Flags().String("commit", "", "Talk about a specific commit")
+	chatCmd.Flags().String("branch", "", "Talk about the changes compared to branch")
+	chatCmd.Flags().String("session", "", "Resume a saved session")
+	chatCmd.Flags().Bool("list", false, "List the saved sessions")
+	chatCmd.Flags().Duration("timeout", 5*time.Minute, "Maximum time for each request (e.g. 30s, 5m)")
+	addProviderFlags(chatCmd)
+}
+
+// chatTurn is a message of the conversation.
+type chatTurn struct {
+	// Role is "user" or "assistant"
+	Role    string
+	Content string
+}
+
+// chatIssue is an issue of the last review, numbered for /fix and /explain.
+type chatIssue struct {
+	File  string
+	Issue providers.Issue
+}
+
+// chatSession is the state of a chat: the changes, the conversation and
+// the issues of the last review.
+type chatSession struct {
+	cfg      *config.Config
+	provider providers.Provider
+	repo     git.Repository
+	root     string
+	mem      *memory.SessionMem
+
+	diff       *git.Diff
+	astContext string
+	turns      []chatTurn
+	issues     []chatIssue
+
+	// newContext bounds each request
+	newContext func() (context.Context, context.CancelFunc)
+	// review reviews the changes
+	review func(ctx context.Context) (*review.Result, error)
+
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func runChat(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	mem, err := memory.NewSessionMemory(filepath.Join(cfg.Memory.Dir, "sessions"),
+		cfg.Memory.Session.MaxSessions, cfg.Memory.Session.SessionTTL)
+	if err != nil {
+		return err
+	}
+	if list, _ := cmd.Flags().GetBool("list"); list {
+		return listChatSessions(mem)
+	}
+
+	if err := applyFixFlagOverrides(cmd, cfg, args); err != nil {
+		return err
+	}
+	repo, err := git.NewRepo(".")
+	if err != nil {
+		return fmt.Errorf("initializing git: %w", err)
+	}
+	provider, err := providers.NewProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("initializing provider: %w", err)
+	}
+	defer func() { _ = provider.Close() }()
+
+	s := &chatSession{
+		cfg: cfg, provider: provider, repo: repo, root: ".", mem: mem,
+		newContext: func() (context.Context, context.CancelFunc) { return commandContext(cmd) },
+		review:     func(ctx context.Context) (*review.Result, error) { return executeFixReview(ctx, cfg, provider) },
+		in:         bufio.NewReader(os.Stdin),
+		out:        os.Stdout,
+	}
+	if root, err := repo.GetRepoRoot(context.Background()); err == nil {
+		s.root = root
+	}
+	if id, _ := cmd.Flags().GetString("session"); id != "" {
+		if err := s.resume(id); err != nil {
+			return err
+		}
+	}
+	if err := s.reload(); err != nil {
+		return err
+	}
+	linkSession(context.Background(), repo, mem, cfg.Review.Commit)
+	defer func() {
+		if err := mem.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: session not saved: %v\n", err)
+		} else if len(s.turns) > 0 {
+			fmt.Fprintf(os.Stderr, "Session saved, resume it with: goreview chat --session %s\n", mem.SessionID())
+		}
+	}()
+
+	fmt.Fprintf(s.out, "Chat about %d changed files (+%d/-%d). Type /help for commands.\n",
+		len(s.diff.Files), s.diff.Stats.Additions, s.diff.Stats.Deletions)
+	return s.loop()
+}
+
+func listChatSessions(mem *memory.SessionMem) error {
+	sessions, err := mem.ListSessions(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No saved sessions.")
+		return nil
+	}
+	for _, id := range sessions {
+		fmt.Println(id)
+	}
+	return nil
+}
+
+// linkSession links the session to the current branch, HEAD and the commit
+// talked about, if any, so goreview memory recall finds it by them.
+func linkSession(ctx context.Context, repo git.Repository, mem *memory.SessionMem, commit string) {
+	branch, _ := repo.GetCurrentBranch(ctx)
+	if branch == "HEAD" {
+		branch = "" // Detached
+	}
+	head, _ := runGitCommand("rev-parse", "HEAD")
+	mem.Link(strings.TrimSpace(branch), strings.TrimSpace(head))
+	if commit != "" {
+		if sha, err := runGitCommand("rev-parse", commit); err == nil {
+			commit = sha
+		}
+		mem.Link("", strings.TrimSpace(commit))
+	}
+}
+
+// resume loads the conversation of a saved session.
+func (s *chatSession) resume(id string) error {
+	if err := s.mem.LoadSession(context.Background(), id); err != nil {
+		return err
+	}
+	results, err := s.mem.Search(context.Background(), &memory.Query{Type: chatEntryType})
+	if err != nil {
+		return err
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Entry.ID < results[j].Entry.ID })
+	for _, r := range results {
+		role, _ := r.Entry.Metadata["role"].(string)
+		s.turns = append(s.turns, chatTurn{Role: role, Content: r.Entry.Content})
+	}
+	return nil
+}
+
+// reload loads the diff and the structure of the changed code.
+func (s *chatSession) reload() error {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	diff, err := chatDiff(ctx, s.repo, s.cfg)
+	if err != nil {
+		return fmt.Errorf("getting diff: %w", err)
+	}
+	if len(diff.Files) == 0 {
+		return fmt.Errorf("no changes found to talk about")
+	}
+	s.diff = diff
+	s.astContext = buildChatASTContext(s.root, diff)
+	return nil
+}
+
+// chatDiff returns the changes selected by the review mode.
+func chatDiff(ctx context.Context, repo git.Repository, cfg *config.Config) (*git.Diff, error) {
+	switch cfg.Review.Mode {
+	case "commit":
+		return repo.GetCommitDiff(ctx, cfg.Review.Commit)
+	case "branch":
+		return repo.GetBranchDiff(ctx, cfg.Git.BaseBranch)
+	case "files":
+		return repo.GetFileDiff(ctx, cfg.Review.Files)
+	default:
+		return repo.GetStagedDiff(ctx)
+	}
+}
+
+// loop reads messages until /quit or the end of the input.
+func (s *chatSession) loop() error {
+	for {
+		fmt.Fprint(s.out, "\n> ")
+		line, err := s.in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line != "" && s.handle(line) {
+			return nil
+		}
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(s.out)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handle runs a slash command or answers a message, reporting whether the
+// session ends.
+func (s *chatSession) handle(line string) bool {
+	if !strings.HasPrefix(line, "/") {
+		s.report(s.ask(line))
+		return false
+	}
+
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "/quit", "/exit":
+		return true
+	case "/help":
+		fmt.Fprint(s.out, chatHelp)
+	case "/review":
+		s.report(s.runReview())
+	case "/fix":
+		s.report(s.withIssue(fields, s.runFix))
+	case "/explain":
+		s.report(s.withIssue(fields, s.runExplain))
+	case "/doc":
+		s.report(s.runDoc())
+	case "/reload":
+		if err := s.reload(); err != nil {
+			s.report(err)
+		} else {
+			fmt.Fprintf(s.out, "Reloaded %d changed files.\n", len(s.diff.Files))
+		}
+	default:
+		fmt.Fprintf(s.out, "Unknown command %s\n%s", fields[0], chatHelp)
+	}
+	return false
+}
+
+const chatHelp = `Commands:
+  /review       Review the changes
+  /fix <n>      Apply the fix of issue n
+  /explain <n>  Explain issue n
+  /doc          Document the changes
+  /reload       Load the diff again
+  /quit         End the session
+Anything else is a question about the changes.
+`
+
+func (s *chatSession) report(err error) {
+	if err != nil {
+		fmt.Fprintf(s.out, "Error: %v\n", err)
+	}
+}
+
+// record adds a message to the conversation and to session memory.
+func (s *chatSession) record(role, content string) {
+	s.turns = append(s.turns, chatTurn{Role: role, Content: content})
+	entry := &memory.Entry{
+		ID:       fmt.Sprintf(chatEntryIDFormat, len(s.turns)),
+		Type:     chatEntryType,
+		Content:  content,
+		Tags:     []string{chatEntryType, role},
+		Metadata: map[string]interface{}{"role": role},
+	}
+	if err := s.mem.Store(context.Background(), entry); err != nil && isVerbose() {
+		fmt.Fprintf(os.Stderr, "Warning: message not saved: %v\n", err)
+	}
+}
+
+// ask answers a message about the changes.
+func (s *chatSession) ask(question string) error {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	answer, err := s.provider.GenerateDocumentation(ctx, "", s.buildPrompt(question))
+	if err != nil {
+		return fmt.Errorf("getting AI response: %w", err)
+	}
+	answer = strings.TrimSpace(answer)
+	s.record("user", question)
+	s.record("assistant", answer)
+	fmt.Fprintln(s.out, answer)
+	return nil
+}
+
+func (s *chatSession) buildPrompt(question string) string {
+	var sb strings.Builder
+	sb.WriteString("You are a senior engineer pair-programming with a developer on the changes below. ")
+	sb.WriteString("Answer the developer's last message concisely, referring to files, functions and lines. Use Markdown.\n\n")
+
+	sb.WriteString("## Changes\n\n")
+	sb.WriteString(truncate(chatDiffText(s.diff), chatDiffBudget) + "\n")
+	if s.astContext != "" {
+		sb.WriteString("## Structure of the changed code\n\n" + s.astContext + "\n")
+	}
+
+	turns := s.turns
+	if len(turns) > chatHistoryTurns {
+		turns = turns[len(turns)-chatHistoryTurns:]
+	}
+	sb.WriteString("## Conversation\n\n")
+	for _, t := range turns {
+		sb.WriteString(chatSpeaker(t.Role) + ": " + t.Content + "\n\n")
+	}
+	sb.WriteString("Developer: " + question + "\n\nAssistant:")
+	return sb.String()
+}
+
+func chatSpeaker(role string) string {
+	if role == "user" {
+		return "Developer"
+	}
+	return "Assistant"
+}
+
+// chatDiffText renders the diff as unified hunks per file.
+func chatDiffText(diff *git.Diff) string {
+	var sb strings.Builder
+	for _, f := range diff.Files {
+		if f.IsBinary {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("--- %s (%s)\n", f.Path, f.Status))
+		sb.WriteString(fileDiffText(f))
+	}
+	return sb.String()
+}
+
+func fileDiffText(f git.FileDiff) string {
+	var sb strings.Builder
+	for _, hunk := range f.Hunks {
+		sb.WriteString(hunk.Header + "\n")
+		for _, line := range hunk.Lines {
+			prefix := " "
+			switch line.Type {
+			case git.LineAddition:
+				prefix = "+"
+			case git.LineDeletion:
+				prefix = "-"
+			}
+			sb.WriteString(prefix + line.Content + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// buildChatASTContext describes the functions and types touched by the
+// diff, read from the files under root.
+func buildChatASTContext(root string, diff *git.Diff) string {
+	builder := ast.NewContextBuilder(chatASTBudget)
+	var sb strings.Builder
+	for _, f := range diff.Files {
+		if f.IsBinary || f.Status == git.FileDeleted {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(f.Path))) // #nosec G304 - path from the diff
+		if err != nil {
+			continue
+		}
+		dc, err := ast.NewParser(f.Language).ParseDiff(fileDiffText(f), string(content), f.Path)
+		if err != nil || (len(dc.ChangedFunctions) == 0 && len(dc.ChangedClasses) == 0) {
+			continue
+		}
+		sb.WriteString(builder.BuildPromptContext(dc.FullContext, dc) + "\n")
+		if sb.Len() > chatASTBudget {
+			break
+		}
+	}
+	return truncate(sb.String(), chatASTBudget)
+}
+
+// runReview reviews the changes and lists the issues by number.
+func (s *chatSession) runReview() error {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	fmt.Fprintln(s.out, "Reviewing...")
+	result, err := s.review(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.issues = nil
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			s.issues = append(s.issues, chatIssue{File: f.File, Issue: issue})
+		}
+	}
+
+	var sb strings.Builder
+	if len(s.issues) == 0 {
+		sb.WriteString("The review found no issues.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("The review found %d issues:\n", len(s.issues)))
+		for i, ci := range s.issues {
+			sb.WriteString(fmt.Sprintf("%d. [%s] %s%s: %s\n", i+1, ci.Issue.Severity, ci.File, lineRef(ci.Issue), ci.Issue.Message))
+		}
+	}
+	fmt.Fprint(s.out, sb.String())
+	s.record("user", "/review")
+	s.record("assistant", sb.String())
+	return nil
+}
+
+func lineRef(issue providers.Issue) string {
+	if issue.Location == nil || issue.Location.StartLine <= 0 {
+		return ""
+	}
+	return ":" + strconv.Itoa(issue.Location.StartLine)
+}
+
+// withIssue runs fn with the issue numbered by the command's argument.
+func (s *chatSession) withIssue(fields []string, fn func(n int, ci chatIssue) error) error {
+	if len(fields) != 2 {
+		return fmt.Errorf("usage: %s <issue number>", fields[0])
+	}
+	if len(s.issues) == 0 {
+		return fmt.Errorf("no issues to pick from, run /review first")
+	}
+	n, err := strconv.Atoi(fields[1])
+	if err != nil || n < 1 || n > len(s.issues) {
+		return fmt.Errorf("issue number must be between 1 and %d", len(s.issues))
+	}
+	return fn(n, s.issues[n-1])
+}
+
+// runFix shows the fix of an issue and applies it when confirmed.
+func (s *chatSession) runFix(n int, ci chatIssue) error {
+	if !isFixableIssue(ci.Issue, nil, nil) {
+		return fmt.Errorf("issue %d has no suggested fix", n)
+	}
+	fix := createFixableIssue(ci.File, ci.Issue)
+	displayFixDetails(fix)
+	ctx, cancel := s.newContext()
+	defer cancel()
+	fix, shouldApply, _ := decideFix(ctx, fix, false, s.in, alternativeFix(s.provider))
+	outcome := "not applied"
+	if tryApplyFix(fix, shouldApply) {
+		outcome = "applied"
+	}
+	s.record("user", fmt.Sprintf("/fix %d", n))
+	s.record("assistant", fmt.Sprintf("Fix for issue %d (%s) %s.", n, ci.Issue.Message, outcome))
+	return nil
+}
+
+// runExplain explains an issue like 'goreview explain'.
+func (s *chatSession) runExplain(n int, ci chatIssue) error {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	record := &history.ReviewRecord{
+		FilePath: ci.File, IssueType: string(ci.Issue.Type), Severity: string(ci.Issue.Severity),
+		Message: ci.Issue.Message, Suggestion: ci.Issue.Suggestion,
+	}
+	if ci.Issue.Location != nil {
+		record.Line = ci.Issue.Location.StartLine
+	}
+	code := readCodeAround(filepath.Join(s.root, filepath.FromSlash(record.FilePath)), record.Line, explainContextLines)
+	sections := retrieveGuides(s.root, record, code, 3)
+
+	explanation, err := explainIssue(ctx, s.provider, record, code, sections)
+	if err != nil {
+		return err
+	}
+	explanation.Guides = guideLinks(s.root, sections)
+	text := fmt.Sprintf("## Issue %d: %s\n\n%s", n, ci.Issue.Message, formatExplanationBody(explanation))
+	fmt.Fprint(s.out, text)
+	s.record("user", fmt.Sprintf("/explain %d", n))
+	s.record("assistant", text)
+	return nil
+}
+
+// runDoc documents the changes like 'goreview doc'.
+func (s *chatSession) runDoc() error {
+	ctx, cancel := s.newContext()
+	defer cancel()
+	doc, err := s.provider.GenerateDocumentation(ctx, formatDiffForDoc(s.diff), buildDocContext(s.diff, nil, "changes", "markdown", ""))
+	if err != nil {
+		return fmt.Errorf("generating documentation: %w", err)
+	}
+	doc = strings.TrimSpace(doc)
+	fmt.Fprintln(s.out, doc)
+	s.record("user", "/doc")
+	s.record("assistant", doc)
+	return nil
+}
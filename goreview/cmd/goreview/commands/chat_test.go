@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/memory"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// chatProvider answers every request with the same text and records the
+// prompts
+type chatProvider struct {
+	planProvider
+	answer string
+}
+
+func (p *chatProvider) GenerateDocumentation(_ context.Context, _, prompt string) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	return p.answer, nil
+}
+
+func newTestChatSession(t *testing.T, dir, input string, p providers.Provider) (*chatSession, *bytes.Buffer) {
+	t.Helper()
+	mem, err := memory.NewSessionMemory(dir, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("NewSessionMemory() error = %v", err)
+	}
+	out := &bytes.Buffer{}
+	diff := &git.Diff{Files: []git.FileDiff{{
+		Path: "store/save.go", Status: git.FileModified, Language: "go",
+		Hunks: []git.Hunk{{Header: "@@ -1,1 +1,1 @@", Lines: []git.Line{{Type: git.LineAddition, Content: "f.Close()"}}}},
+	}}}
+	return &chatSession{
+		provider: p, root: t.TempDir(), mem: mem, diff: diff,
+		newContext: func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+		review: func(context.Context) (*review.Result, error) {
+			return &review.Result{Files: []review.FileResult{{
+				File: "store/save.go",
+				Response: &providers.ReviewResponse{Issues: []providers.Issue{{
+					Type: providers.IssueTypeBug, Severity: providers.SeverityError, Message: "error from Close ignored",
+					Location: &providers.Location{StartLine: 1},
+				}}},
+			}}}, nil
+		},
+		in:  bufio.NewReader(strings.NewReader(input)),
+		out: out,
+	}, out
+}
+
+func TestChatSession(t *testing.T) {
+	dir := t.TempDir()
+	p := &chatProvider{answer: "Check the error."}
+	s, out := newTestChatSession(t, dir, "/review\nwhat about issue 1?\n/fix 9\n/bogus\n/quit\nignored\n", p)
+
+	if err := s.loop(); err != nil {
+		t.Fatalf("loop() error = %v", err)
+	}
+	for _, want := range []string{
+		"1. [error] store/save.go:1: error from Close ignored",
+		"Check the error.",
+		"issue number must be between 1 and 1",
+		"Unknown command /bogus",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// The question comes with the diff and the review in the conversation
+	if len(p.prompts) != 1 {
+		t.Fatalf("prompts = %d, want 1 (the session ends at /quit)", len(p.prompts))
+	}
+	for _, want := range []string{"+f.Close()", "Developer: /review", "error from Close ignored", "Developer: what about issue 1?"} {
+		if !strings.Contains(p.prompts[0], want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+
+	// The saved conversation resumes in order
+	if err := s.mem.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	resumed, _ := newTestChatSession(t, dir, "", p)
+	if err := resumed.resume(s.mem.SessionID()); err != nil {
+		t.Fatalf("resume() error = %v", err)
+	}
+	if len(resumed.turns) != 4 || resumed.turns[2].Role != "user" || resumed.turns[2].Content != "what about issue 1?" {
+		t.Errorf("resumed turns = %+v", resumed.turns)
+	}
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/ignore"
+)
+
+var checkIgnoreCmd = &cobra.Command{
+	Use:   "check-ignore <path>...",
+	Short: "Explain why files are skipped",
+	Long: `Tell for each path whether review, fix, doc and audit look at it, and
+if not, which pattern leaves it out: an include pattern that doesn't match
+(git.include_patterns, --include), an exclude pattern (git.ignore_patterns,
+--exclude) or a line of the ignore file (git.ignore_file, .goreviewignore
+by default).
+
+Paths are relative to the current directory, as on the command line.
+
+Examples:
+  goreview check-ignore build/main.go docs/guide.md
+  goreview check-ignore --include "internal/**" cmd/goreview/main.go`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCheckIgnore,
+}
+
+func init() {
+	rootCmd.AddCommand(checkIgnoreCmd)
+
+	checkIgnoreCmd.Flags().StringSlice("include", nil, "Include only these file patterns, as with review")
+	checkIgnoreCmd.Flags().StringSlice("exclude", nil, "Exclude these file patterns, as with review")
+}
+
+func runCheckIgnore(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	includes, _ := cmd.Flags().GetStringSlice("include")
+	excludes, _ := cmd.Flags().GetStringSlice("exclude")
+	cfg.Git.IncludePatterns = append(cfg.Git.IncludePatterns, includes...)
+	cfg.Git.IgnorePatterns = append(cfg.Git.IgnorePatterns, excludes...)
+
+	m, err := loadIgnore(cfg)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	for _, path := range args {
+		if d := m.MatchFile(path); d.Ignored() {
+			_, _ = fmt.Fprintf(out, "%s: skipped, %s\n", path, describeDecision(d))
+		} else {
+			_, _ = fmt.Fprintf(out, "%s: reviewed\n", path)
+		}
+	}
+	return nil
+}
+
+// describeDecision tells why a file is skipped.
+func describeDecision(d ignore.Decision) string {
+	switch d.Reason {
+	case ignore.NotIncluded:
+		return "matches no include pattern (" + d.Pattern + ")"
+	case ignore.Excluded:
+		return "excluded by pattern " + d.Pattern
+	}
+	return "ignored by " + d.Pattern
+}
+
+// loadIgnore returns the ignore matcher of the repository in the current
+// directory, or of the directory itself outside a repository.
+func loadIgnore(cfg *config.Config) (*ignore.Matcher, error) {
+	root := ""
+	if out, err := runGitCommand("rev-parse", "--show-toplevel"); err == nil {
+		root = strings.TrimSpace(out)
+	}
+	return ignore.New(cfg.Git, root)
+}
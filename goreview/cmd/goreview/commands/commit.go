@@ -12,6 +12,7 @@ import (
 
 	"github.com/JNZader/goreview/goreview/internal/config"
 	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/memory"
 	"github.com/JNZader/goreview/goreview/internal/providers"
 )
 
@@ -31,7 +32,14 @@ Examples:
   goreview commit --type feat
 
   # Amend last commit with new message
-  goreview commit --amend`,
+  goreview commit --amend
+
+The scope is inferred from the changed paths: first from the commit.scopes
+rules in .goreview.yaml, then from the scopes of earlier commits made with
+goreview commit (learned in memory.dir), then from the directory names.
+Changes spanning up to commit.max_scopes scopes list them all, as in
+"feat(api,web): ...". Set commit.infer_scope to false to keep the model's
+scope, or force one with --scope.`,
 	RunE: runCommit,
 }
 
@@ -51,17 +59,24 @@ func init() {
 
 	// Output flags
 	commitCmd.Flags().Bool("dry-run", false, "Show message without committing")
+
+	// Provider flags
+	addProviderFlags(commitCmd)
+	addTimeoutFlag(commitCmd, 2*time.Minute)
 }
 
 func runCommit(cmd *cobra.Command, args []string) error {
 	// Load configuration
-	cfg, err := config.LoadDefault()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
+	if err = applyProviderFlags(cmd, cfg); err != nil {
+		return err
+	}
 
 	// Create context
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cancel := commandContext(cmd)
 	defer cancel()
 
 	// Initialize git repo
@@ -98,8 +113,18 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("generating commit message: %w", err)
 	}
 
+	// Infer the scope from the changed paths, unless forced
+	learned := loadScopeMemory(ctx, cfg, gitRepo)
+	inferred := ""
+	if cfg.Commit.InferScope {
+		inferred = newScopeInferrer(cfg.Commit, learned).infer(diff)
+		if isVerbose() && inferred != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "Inferred scope: %s\n", inferred)
+		}
+	}
+
 	// Apply overrides
-	message = applyCommitOverrides(cmd, message)
+	message = applyCommitOverrides(cmd, message, inferred)
 
 	// Add body and footer
 	body, _ := cmd.Flags().GetString("body")
@@ -123,7 +148,16 @@ func runCommit(cmd *cobra.Command, args []string) error {
 	amend, _ := cmd.Flags().GetBool("amend")
 
 	if execute || amend {
-		return executeGitCommit(message, amend)
+		if err := executeGitCommit(message, amend); err != nil {
+			return err
+		}
+		if learned != nil && cfg.Commit.LearnScopes {
+			learnScope(learned, diff, parseConventionalCommit(message).Scope)
+			if err := learned.Save(); err != nil && isVerbose() {
+				_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		return nil
 	}
 
 	// Default: print message for user to copy
@@ -131,6 +165,26 @@ func runCommit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// loadScopeMemory loads the scopes learned for the repository, or returns
+// nil when scopes aren't learned or can't be loaded.
+func loadScopeMemory(ctx context.Context, cfg *config.Config, gitRepo *git.Repo) *memory.ScopeMemory {
+	if !cfg.Commit.LearnScopes {
+		return nil
+	}
+	root, err := gitRepo.GetRepoRoot(ctx)
+	if err != nil {
+		return nil
+	}
+	learned, err := memory.LoadScopeMemory(cfg.Memory.Dir, root)
+	if err != nil {
+		if isVerbose() {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return nil
+	}
+	return learned
+}
+
 func formatDiffForCommit(diff *git.Diff) string {
 	var sb strings.Builder
 	for _, file := range diff.Files {
@@ -152,10 +206,15 @@ func formatDiffForCommit(diff *git.Diff) string {
 	return sb.String()
 }
 
-func applyCommitOverrides(cmd *cobra.Command, message string) string {
+// applyCommitOverrides applies the flags to the generated message. The
+// inferred scope, if any, replaces the model's unless --scope is given.
+func applyCommitOverrides(cmd *cobra.Command, message, inferredScope string) string {
 	commitType, _ := cmd.Flags().GetString("type")
 	scope, _ := cmd.Flags().GetString("scope")
 	breaking, _ := cmd.Flags().GetBool("breaking")
+	if scope == "" {
+		scope = inferredScope
+	}
 
 	// Parse existing message
 	parts := parseConventionalCommit(message)
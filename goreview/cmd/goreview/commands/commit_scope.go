@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/memory"
+)
+
+// genericDirs are directory names that say nothing about what changed, so
+// guessed scopes skip them.
+var genericDirs = map[string]bool{
+	"src": true, "lib": true, "internal": true, "pkg": true, "cmd": true,
+	"app": true, "apps": true, "packages": true, "source": true, "main": true,
+}
+
+// scopeInferrer finds the conventional commit scope of changed files: from
+// the configured rules, then the scopes learned from earlier commits, then
+// the name of the file's directory.
+type scopeInferrer struct {
+	rules     []config.ScopeRule
+	learned   *memory.ScopeMemory
+	maxScopes int
+}
+
+func newScopeInferrer(cfg config.CommitConfig, learned *memory.ScopeMemory) *scopeInferrer {
+	rules := make([]config.ScopeRule, len(cfg.Scopes))
+	for i, r := range cfg.Scopes {
+		rules[i] = config.ScopeRule{Path: path.Clean(strings.TrimPrefix(r.Path, "./")), Scope: r.Scope}
+	}
+	// The deepest rule wins when rules are nested
+	sort.SliceStable(rules, func(i, j int) bool {
+		return strings.Count(rules[i].Path, "/") > strings.Count(rules[j].Path, "/")
+	})
+	return &scopeInferrer{rules: rules, learned: learned, maxScopes: max(cfg.MaxScopes, 1)}
+}
+
+// scopeOf returns the scope of a file, or "" for files at the root.
+func (s *scopeInferrer) scopeOf(file string) string {
+	parts := strings.Split(file, "/")
+	for _, r := range s.rules {
+		n := strings.Count(r.Path, "/") + 1
+		if len(parts) <= n {
+			continue
+		}
+		if matched, _ := path.Match(r.Path, strings.Join(parts[:n], "/")); matched {
+			if r.Scope != "" {
+				return r.Scope
+			}
+			return parts[n-1]
+		}
+	}
+
+	if s.learned != nil {
+		for dir := path.Dir(file); dir != "."; dir = path.Dir(dir) {
+			if scope := s.learned.Scope(dir); scope != "" {
+				return scope
+			}
+		}
+	}
+
+	for i := len(parts) - 2; i >= 0; i-- {
+		if !genericDirs[parts[i]] {
+			return parts[i]
+		}
+	}
+	return ""
+}
+
+// infer returns the scope of the diff. Files are weighted by their changed
+// lines; when the diff spans more than maxScopes scopes, a scope covering
+// two thirds of the changes is used, or none.
+func (s *scopeInferrer) infer(diff *git.Diff) string {
+	weights := make(map[string]int)
+	total := 0
+	for _, f := range diff.Files {
+		scope := s.scopeOf(f.Path)
+		if scope == "" {
+			continue
+		}
+		w := max(f.Additions+f.Deletions, 1)
+		weights[scope] += w
+		total += w
+	}
+	if len(weights) == 0 {
+		return ""
+	}
+
+	scopes := make([]string, 0, len(weights))
+	for scope := range weights {
+		scopes = append(scopes, scope)
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		if weights[scopes[i]] != weights[scopes[j]] {
+			return weights[scopes[i]] > weights[scopes[j]]
+		}
+		return scopes[i] < scopes[j]
+	})
+
+	if len(scopes) <= s.maxScopes {
+		return strings.Join(scopes, ",")
+	}
+	if weights[scopes[0]]*3 >= total*2 {
+		return scopes[0]
+	}
+	return ""
+}
+
+// learnScope records the scope of a commit for the directories it touched.
+// Multi-scope commits are left out, as they don't tell which directory has
+// which scope.
+func learnScope(learned *memory.ScopeMemory, diff *git.Diff, scope string) {
+	if scope == "" || strings.Contains(scope, ",") {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, f := range diff.Files {
+		dir := path.Dir(f.Path)
+		if dir != "." && !seen[dir] {
+			seen[dir] = true
+			learned.Learn(dir, scope)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package commands
 
 import (
 	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/memory"
 )
 
 func TestParseConventionalCommit(t *testing.T) {
@@ -142,3 +146,76 @@ func TestTruncate(t *testing.T) {
 		})
 	}
 }
+
+func TestScopeInferrer(t *testing.T) {
+	learned, err := memory.LoadScopeMemory(t.TempDir(), "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	learned.Learn("web/src/components", "ui")
+
+	s := newScopeInferrer(config.CommitConfig{
+		MaxScopes: 2,
+		Scopes: []config.ScopeRule{
+			{Path: "cmd/goreview/commands", Scope: "cli"},
+			{Path: "services/*"},
+		},
+	}, learned)
+
+	for file, want := range map[string]string{
+		"cmd/goreview/commands/commit.go": "cli",
+		"services/billing/main.go":        "billing",
+		"web/src/components/button.tsx":   "ui",
+		"internal/providers/openai.go":    "providers",
+		"src/main.go":                     "",
+		"go.mod":                          "",
+	} {
+		if got := s.scopeOf(file); got != want {
+			t.Errorf("scopeOf(%q) = %q, want %q", file, got, want)
+		}
+	}
+
+	diff := func(files ...git.FileDiff) *git.Diff { return &git.Diff{Files: files} }
+	tests := []struct {
+		name string
+		diff *git.Diff
+		want string
+	}{
+		{"single scope", diff(git.FileDiff{Path: "internal/providers/openai.go", Additions: 3}, git.FileDiff{Path: "go.mod", Additions: 1}), "providers"},
+		{"two scopes by weight", diff(git.FileDiff{Path: "internal/cache/lru.go", Additions: 2}, git.FileDiff{Path: "internal/review/engine.go", Additions: 9}), "review,cache"},
+		{"dominant of many", diff(
+			git.FileDiff{Path: "internal/review/engine.go", Additions: 40},
+			git.FileDiff{Path: "internal/cache/lru.go", Additions: 1},
+			git.FileDiff{Path: "internal/git/repo.go", Additions: 1}), "review"},
+		{"scattered", diff(
+			git.FileDiff{Path: "internal/review/engine.go", Additions: 5},
+			git.FileDiff{Path: "internal/cache/lru.go", Additions: 5},
+			git.FileDiff{Path: "internal/git/repo.go", Additions: 5}), ""},
+	}
+	for _, tt := range tests {
+		if got := s.infer(tt.diff); got != tt.want {
+			t.Errorf("%s: infer = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLearnScope(t *testing.T) {
+	dir := t.TempDir()
+	learned, _ := memory.LoadScopeMemory(dir, "/repo")
+	changes := &git.Diff{Files: []git.FileDiff{{Path: "internal/providers/openai.go"}, {Path: "internal/providers/groq.go"}}}
+	learnScope(learned, changes, "llm")
+	learnScope(learned, changes, "llm,cli") // Multi-scope commits aren't learned
+	if err := learned.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Learned scopes persist and win over directory names, per repository
+	reloaded, _ := memory.LoadScopeMemory(dir, "/repo")
+	if got := newScopeInferrer(config.CommitConfig{MaxScopes: 1}, reloaded).scopeOf("internal/providers/mistral.go"); got != "llm" {
+		t.Errorf("scope after learning = %q, want llm", got)
+	}
+	other, _ := memory.LoadScopeMemory(dir, "/other")
+	if got := other.Scope("internal/providers"); got != "" {
+		t.Errorf("scope leaked to another repository: %q", got)
+	}
+}
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/failure"
+	"github.com/JNZader/goreview/goreview/internal/manifest"
+	"github.com/JNZader/goreview/goreview/internal/report"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+var complianceCmd = &cobra.Command{
+	Use:   "compliance",
+	Short: "Render a review run as audit evidence",
+	Long: `Render a review run as evidence of code review for auditors (SOC 2,
+ISO 27001): what was reviewed, by whom and when, the gates applied, the
+outcome and the findings, with digests of the files backing it and lines
+for the approvers to sign.
+
+The run is read from the JSON report of 'goreview review --format json' and,
+for who ran it and when, the run manifest of 'goreview review --manifest'.
+Both files are hashed with SHA-256; when the environment variable named by
+--key-env holds a key, they are also signed with HMAC-SHA256, which can be
+checked with 'openssl dgst -sha256 -hmac "$KEY" <file>'.
+
+Examples:
+  # Review in CI, keeping the report and the manifest
+  goreview review --branch main --format json -o review.json --manifest run.json
+
+  # Render the evidence as PDF for the audit folder
+  goreview compliance --report review.json --manifest run.json -f pdf -o evidence.pdf
+
+  # HTML with the approvers to sign it off
+  goreview compliance --report review.json --approver "Ana Lopez" --approver "QA Lead" -o evidence.html`,
+	RunE: runCompliance,
+}
+
+func init() {
+	rootCmd.AddCommand(complianceCmd)
+
+	complianceCmd.Flags().String("report", "", "JSON report of the review (required)")
+	complianceCmd.Flags().String("manifest", "", "Run manifest of the review")
+	complianceCmd.Flags().StringP("format", "f", "html", "Output format (html, pdf, markdown)")
+	complianceCmd.Flags().StringP("output", "o", "", "Write the evidence to file (default: stdout)")
+	complianceCmd.Flags().String("title", "Code Review Evidence", "Title of the evidence")
+	complianceCmd.Flags().StringArray("approver", nil, "Person expected to sign the review off (repeatable)")
+	complianceCmd.Flags().String("key-env", "GOREVIEW_COMPLIANCE_KEY", "Environment variable holding the HMAC signing key")
+	_ = complianceCmd.MarkFlagRequired("report")
+}
+
+func runCompliance(cmd *cobra.Command, _ []string) error {
+	reportPath, _ := cmd.Flags().GetString("report")
+	manifestPath, _ := cmd.Flags().GetString("manifest")
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	keyEnv, _ := cmd.Flags().GetString("key-env")
+
+	evidence, err := loadEvidence(reportPath, manifestPath, []byte(os.Getenv(keyEnv)))
+	if err != nil {
+		return err
+	}
+	evidence.Title, _ = cmd.Flags().GetString("title")
+	evidence.Approvers, _ = cmd.Flags().GetStringArray("approver")
+	evidence.GeneratedAt = time.Now()
+
+	var buf bytes.Buffer
+	if err := report.WriteCompliance(evidence, format, &buf); err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = io.Copy(cmd.OutOrStdout(), &buf)
+		return err
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("writing evidence: %w", err)
+	}
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Compliance evidence (%s) written to %s: %s\n", format, output, evidence.Outcome())
+	}
+	return nil
+}
+
+// loadEvidence reads the review's report and, when given, its run
+// manifest, and signs both.
+func loadEvidence(reportPath, manifestPath string, key []byte) (*report.Evidence, error) {
+	data, err := os.ReadFile(reportPath) //nolint:gosec // Path given by the user
+	if err != nil {
+		return nil, fmt.Errorf("reading report: %w", err)
+	}
+	result, err := reviewtypes.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, &failure.ParseError{Code: failure.ParseReport, File: reportPath, Err: fmt.Errorf("decoding report %s: %w", reportPath, err)}
+	}
+	evidence := &report.Evidence{Result: result, Signatures: signEvidence(filepath.Base(reportPath), data, key)}
+
+	if manifestPath == "" {
+		return evidence, nil
+	}
+	data, err = os.ReadFile(manifestPath) //nolint:gosec // Path given by the user
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	var m manifest.Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("decoding manifest %s: %w", manifestPath, err)
+	}
+	evidence.Repository, evidence.Remote = m.Repository.Name, m.Repository.Remote
+	evidence.Branch, evidence.Commit, evidence.Author = m.Repository.Branch, m.Repository.Commit, m.Repository.Author
+	evidence.RunBy = m.RunBy
+	evidence.StartedAt, evidence.FinishedAt = m.StartedAt, m.FinishedAt
+	evidence.ConfigDigest = m.ConfigDigest
+	evidence.Signatures = append(evidence.Signatures, signEvidence(filepath.Base(manifestPath), data, key)...)
+	return evidence, nil
+}
+
+// signEvidence returns the SHA-256 digest of a file and, with a key, its
+// HMAC-SHA256.
+func signEvidence(subject string, data, key []byte) []report.Signature {
+	sum := sha256.Sum256(data)
+	sigs := []report.Signature{{Subject: subject, Algorithm: "SHA-256", Value: hex.EncodeToString(sum[:])}}
+	if len(key) > 0 {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(data)
+		sigs = append(sigs, report.Signature{Subject: subject, Algorithm: "HMAC-SHA256", Value: hex.EncodeToString(mac.Sum(nil))})
+	}
+	return sigs
+}
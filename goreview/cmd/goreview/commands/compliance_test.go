@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/report"
+)
+
+func TestComplianceEvidence(t *testing.T) {
+	dir := t.TempDir()
+	reportPath := filepath.Join(dir, "review.json")
+	manifestPath := filepath.Join(dir, "run.json")
+	writeFile := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(reportPath, `{"schema_version": "1.2", "total_issues": 1, "duration": 1500000000,
+		"files": [{"file": "api/users.go", "cached": false, "response": {"issues": [
+			{"id": "1", "type": "security", "severity": "critical", "message": "SQL built from input", "cwe": "CWE-89",
+			 "location": {"file": "api/users.go", "start_line": 17, "end_line": 17},
+			 "root_cause": {"description": "Filters are passed through unchecked", "origin_file": "api/filters.go", "origin_line": 8}}], "summary": "", "score": 40}}],
+		"stats": {"files_changed": 1, "additions": 12, "deletions": 3},
+		"gates": [{"name": "no-critical", "expr": "critical == 0", "passed": false}]}`)
+	writeFile(manifestPath, `{"manifest_version": "1", "started_at": "2026-10-16T09:00:00Z", "finished_at": "2026-10-16T09:00:02Z",
+		"run_by": "ci-bot", "repository": {"name": "shop", "branch": "main", "commit": "abc123", "author": "Ana <ana@example.com>"},
+		"config_digest": "d1g3st"}`)
+
+	evidence, err := loadEvidence(reportPath, manifestPath, []byte("secret"))
+	if err != nil {
+		t.Fatalf("loadEvidence() error = %v", err)
+	}
+	evidence.Title, evidence.Approvers = "Evidence", []string{"QA Lead"}
+	if evidence.Outcome() != "FAILED" || evidence.RunBy != "ci-bot" || len(evidence.Signatures) != 4 {
+		t.Errorf("evidence = %+v", evidence)
+	}
+	// openssl dgst -sha256 -hmac secret review.json gives the same value
+	if sig := evidence.Signatures[1]; sig.Subject != "review.json" || sig.Algorithm != "HMAC-SHA256" || len(sig.Value) != 64 {
+		t.Errorf("signature = %+v", sig)
+	}
+
+	var md bytes.Buffer
+	if err := report.WriteCompliance(evidence, "markdown", &md); err != nil {
+		t.Fatalf("WriteCompliance() error = %v", err)
+	}
+	for _, want := range []string{
+		"- **Commit author:** Ana <ana@example.com>",
+		"- **Run by:** ci-bot",
+		"- **Started:** 2026-10-16 09:00:00 UTC",
+		"- **Result:** FAILED",
+		"| no-critical | critical == 0 | failed |",
+		"| critical | api/users.go:17 | security (CWE-89) | SQL built from input | open |",
+		"| QA Lead |  |  |",
+		"| Filters are passed through unchecked | api/filters.go:8 | api/users.go:17 SQL built from input |",
+		"```mermaid\nflowchart LR\n",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("evidence missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := report.WriteCompliance(evidence, "html", &html); err != nil {
+		t.Fatalf("WriteCompliance(html) error = %v", err)
+	}
+	if !strings.Contains(html.String(), `<pre class="mermaid">flowchart LR`) {
+		t.Errorf("HTML evidence doesn't graph the root causes:\n%s", html.String())
+	}
+
+	var pdf bytes.Buffer
+	if err := report.WriteCompliance(evidence, "pdf", &pdf); err != nil {
+		t.Fatalf("WriteCompliance(pdf) error = %v", err)
+	}
+	if !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf.Bytes(), []byte("%%EOF\n")) ||
+		!bytes.Contains(pdf.Bytes(), []byte("(Run by:                ci-bot) Tj")) {
+		t.Errorf("invalid PDF:\n%s", pdf.String())
+	}
+
+	if err := report.WriteCompliance(evidence, "docx", &pdf); err == nil {
+		t.Error("WriteCompliance() accepted an unknown format")
+	}
+}
@@ -3,6 +3,8 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -31,6 +33,28 @@ Examples:
 	RunE: runConfigShow,
 }
 
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the config file",
+	Long: `Print a JSON Schema of .goreview.yaml, generated from the options
+goreview reads, with their descriptions and defaults. Editors use it to
+complete and check the config file; with --markdown, the options are
+listed as a reference table instead.
+
+Unknown keys in the config file are warned about when loading it, or fail
+the command with --strict-config or strict_config: true.
+
+Examples:
+  # Schema for editors (yaml-language-server, VS Code, JetBrains)
+  goreview config schema -o goreview.schema.json
+
+  # Option reference
+  goreview config schema --markdown > CONFIG.md`,
+
+	Args: cobra.NoArgs,
+	RunE: runConfigSchema,
+}
+
 var (
 	configShowJSON bool
 )
@@ -38,17 +62,58 @@ var (
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configSchemaCmd)
 
 	configShowCmd.Flags().BoolVar(&configShowJSON, "json", false, "output as JSON")
+	configSchemaCmd.Flags().Bool("markdown", false, "print the option reference as a Markdown table")
+	configSchemaCmd.Flags().StringP("output", "o", "", "write to this file instead of stdout")
 }
 
-func runConfigShow(cmd *cobra.Command, args []string) error {
-	loader := config.NewLoader()
+func runConfigSchema(cmd *cobra.Command, _ []string) error {
+	markdown, _ := cmd.Flags().GetBool("markdown")
+	output, _ := cmd.Flags().GetString("output")
+
+	var content string
+	if markdown {
+		content = optionReference(config.Options())
+	} else {
+		data, err := json.MarshalIndent(config.Schema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal schema: %w", err)
+		}
+		content = string(data) + "\n"
+	}
 
-	// Use config file from flag if provided
-	if cfgFile != "" {
-		loader.SetConfigFile(cfgFile)
+	if output == "" {
+		fmt.Print(content)
+		return nil
+	}
+	if err := os.WriteFile(output, []byte(content), 0600); err != nil {
+		return fmt.Errorf("writing %s: %w", output, err)
+	}
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Schema written to: %s\n", output)
 	}
+	return nil
+}
+
+// optionReference renders the config options as a Markdown table.
+func optionReference(options []config.Option) string {
+	var sb strings.Builder
+	sb.WriteString("| Key | Type | Default | Description |\n|---|---|---|---|\n")
+	escape := strings.NewReplacer("|", "\\|", "\n", " ")
+	for _, o := range options {
+		def := ""
+		if o.Default != "" {
+			def = "`" + escape.Replace(o.Default) + "`"
+		}
+		fmt.Fprintf(&sb, "| `%s` | %s | %s | %s |\n", o.Key, o.Type, def, escape.Replace(o.Description))
+	}
+	return sb.String()
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	loader := newConfigLoader()
 
 	cfg, err := loader.Load()
 	if err != nil {
@@ -61,7 +126,11 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	// Show config file location
 	if !isQuiet() {
 		if configFile := loader.ConfigFileUsed(); configFile != "" {
-			fmt.Printf("# Config file: %s\n\n", configFile)
+			fmt.Printf("# Config file: %s\n", configFile)
+			if cfg.Profile != "" {
+				fmt.Printf("# Profile: %s\n", cfg.Profile)
+			}
+			fmt.Println()
 		} else {
 			fmt.Println("# No config file found, using defaults")
 			fmt.Println()
@@ -83,6 +152,16 @@ func maskSensitiveConfig(cfg *config.Config) *config.Config {
 	if masked.Provider.APIKey != "" {
 		masked.Provider.APIKey = "***REDACTED***"
 	}
+	if masked.Export.VCS.Token != "" {
+		masked.Export.VCS.Token = "***REDACTED***"
+	}
+	if len(masked.Serve.Tenants) > 0 {
+		// Copied so the loaded config keeps its keys
+		masked.Serve.Tenants = append([]config.TenantConfig(nil), masked.Serve.Tenants...)
+		for i := range masked.Serve.Tenants {
+			masked.Serve.Tenants[i].APIKeys = []string{"***REDACTED***"}
+		}
+	}
 
 	return &masked
 }
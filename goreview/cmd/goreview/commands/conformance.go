@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/conformance"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+var conformanceCmd = &cobra.Command{
+	Use:   "conformance",
+	Short: "Check the repository against an organization template policy",
+	Long: `Check the repository against an organization template policy.
+
+The policy is a YAML file listing what every repository must have:
+- required_files: files that must exist (globs match any file)
+- ci_workflows: CI workflow files that must exist
+- makefile.targets: targets the Makefile must define
+- license_header: a regexp the first lines of matching files must match
+
+Violations are reported as issues, like review findings. No AI provider
+is used, so results are deterministic. Set review.conformance_policy (or
+pass --conformance to review) to add them to every review.
+
+Examples:
+  # Check against a policy
+  goreview conformance --policy .github/goreview-policy.yaml
+
+  # Use review.conformance_policy from the config, as SARIF
+  goreview conformance --format sarif -o conformance.sarif`,
+	RunE: runConformance,
+}
+
+func init() {
+	rootCmd.AddCommand(conformanceCmd)
+
+	conformanceCmd.Flags().String("policy", "", "Policy file (default: review.conformance_policy)")
+	conformanceCmd.Flags().StringP("format", "f", "markdown", "Output format (markdown, json, sarif)")
+	conformanceCmd.Flags().StringP("output", "o", "", "Write report to file, or upload it to an s3:// or gs:// URL")
+	conformanceCmd.Flags().String("fail-on", "", "Exit non-zero when violations reach this severity (default: review.fail_on)")
+}
+
+func runConformance(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	useReportFlags(cmd, cfg)
+
+	policyPath, _ := cmd.Flags().GetString("policy")
+	if policyPath == "" {
+		policyPath = cfg.Review.ConformancePolicy
+	}
+	if policyPath == "" {
+		return fmt.Errorf("no policy: pass --policy or set review.conformance_policy")
+	}
+
+	violations, err := checkConformance(policyPath, nil)
+	if err != nil {
+		return err
+	}
+
+	result := &review.Result{}
+	conformance.Apply(result, violations)
+	result.Summary = fmt.Sprintf("%d conformance violations", len(violations))
+	if err := outputReport(context.Background(), cfg, result); err != nil {
+		return err
+	}
+
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if failOn == "" {
+		failOn = cfg.Review.FailOn
+	}
+	checkFailThreshold(result, failOn)
+	return nil
+}
+
+// checkConformance checks the current repository against a policy. License
+// headers are checked in headerFiles, or in every tracked file when nil.
+func checkConformance(policyPath string, headerFiles []string) ([]conformance.Violation, error) {
+	policy, err := conformance.LoadPolicy(policyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := runGitCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository")
+	}
+	out, err := runGitCommand("ls-files", "--full-name", "--", ":/")
+	if err != nil {
+		return nil, fmt.Errorf("listing files: %w", err)
+	}
+
+	var files []string
+	for _, f := range strings.Split(out, "\n") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	checker := conformance.NewChecker(policy, strings.TrimSpace(root), files)
+	return checker.Check(headerFiles), nil
+}
+
+// applyConformance adds template conformance violations to a review when
+// review.conformance_policy is set. License headers are only checked in the
+// reviewed files, so a review doesn't report on code it didn't touch.
+func applyConformance(cfg *config.Config, result *review.Result) error {
+	if cfg.Review.ConformancePolicy == "" {
+		return nil
+	}
+
+	files := make([]string, 0, len(result.Files))
+	for _, f := range result.Files {
+		files = append(files, f.File)
+	}
+	violations, err := checkConformance(cfg.Review.ConformancePolicy, files)
+	if err != nil {
+		return fmt.Errorf("conformance: %w", err)
+	}
+	conformance.Apply(result, violations)
+	if len(violations) > 0 && isVerbose() {
+		fmt.Fprintf(os.Stderr, "Conformance: %d violations of %s\n", len(violations), cfg.Review.ConformancePolicy)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/memory"
+)
+
+var conventionsCmd = &cobra.Command{
+	Use:   "conventions",
+	Short: "View and edit the team conventions learned from goreview fix",
+	Long: `View and edit the team conventions learned from the fixes accepted and
+rejected in goreview fix.
+
+Each kind of issue (those of a rule, or of a type with a similar message)
+becomes a convention once its fixes are decided: keep reporting it when
+its fixes are mostly accepted, don't when they are mostly rejected. The
+conventions with at least memory.conventions.min_decisions decisions, and
+those edited by hand, are given to the reviewer in every review.
+
+Conventions are kept per repository in the long-term memory of memory.dir.`,
+}
+
+var conventionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the conventions learned for this repository",
+	Long: `List the conventions learned for this repository, the most decided first.
+Those given to the reviewer are marked with *.
+
+Examples:
+  goreview conventions list
+  goreview conventions list --prompt   # Only what reviews get, as they get it
+  goreview conventions list --json`,
+	Args: cobra.NoArgs,
+	RunE: runConventionsList,
+}
+
+var conventionsEditCmd = &cobra.Command{
+	Use:   "edit <id> <text>",
+	Short: "Reword a convention",
+	Long: `Replace the text of a convention. Edited conventions keep their text as
+more fixes are decided, and are given to the reviewer whatever their
+number of decisions.
+
+Example:
+  goreview conventions edit conv-1a2b3c4d5e6f "Wrap errors with %w, never %v"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConventionsEdit,
+}
+
+var conventionsRemoveCmd = &cobra.Command{
+	Use:   "remove <id>...",
+	Short: "Forget conventions and their decisions",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runConventionsRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(conventionsCmd)
+	conventionsCmd.AddCommand(conventionsListCmd, conventionsEditCmd, conventionsRemoveCmd)
+
+	conventionsListCmd.Flags().Bool("prompt", false, "Print only the conventions given to the reviewer")
+	conventionsListCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// openConventions opens the conventions of the repository in the current
+// directory.
+func openConventions(cfg *config.Config) (*memory.Conventions, error) {
+	root, err := runGitCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+	return memory.OpenConventions(cfg.Memory, strings.TrimSpace(root))
+}
+
+// loadConventions returns the conventions to give the reviewer, or nil
+// when they are disabled or can't be loaded.
+func loadConventions(ctx context.Context, cfg *config.Config) []string {
+	if !cfg.Memory.Conventions.Enabled {
+		return nil
+	}
+	conventions, err := openConventions(cfg)
+	if err != nil {
+		if isVerbose() {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: team conventions: %v\n", err)
+		}
+		return nil
+	}
+	defer func() { _ = conventions.Close() }()
+
+	learned, err := conventions.List(ctx)
+	if err != nil {
+		if isVerbose() {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: team conventions: %v\n", err)
+		}
+		return nil
+	}
+	var texts []string
+	for _, conv := range memory.Distill(learned, cfg.Memory.Conventions.MinDecisions, cfg.Memory.Conventions.MaxPrompt) {
+		texts = append(texts, conv.Text)
+	}
+	return texts
+}
+
+func runConventionsList(cmd *cobra.Command, _ []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	conventions, err := openConventions(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conventions.Close() }()
+
+	learned, err := conventions.List(cmd.Context())
+	if err != nil {
+		return err
+	}
+	distilled := memory.Distill(learned, cfg.Memory.Conventions.MinDecisions, cfg.Memory.Conventions.MaxPrompt)
+	prompted := make(map[string]bool)
+	for _, conv := range distilled {
+		prompted[conv.ID] = true
+	}
+
+	out := cmd.OutOrStdout()
+	if promptOnly, _ := cmd.Flags().GetBool("prompt"); promptOnly {
+		for _, conv := range distilled {
+			_, _ = fmt.Fprintf(out, "- %s\n", conv.Text)
+		}
+		return nil
+	}
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(learned)
+	}
+
+	if len(learned) == 0 {
+		_, _ = fmt.Fprintln(out, "No conventions learned yet: accept or reject fixes in goreview fix.")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "\tID\tACCEPTED\tREJECTED\tCONVENTION")
+	for _, conv := range learned {
+		mark := ""
+		if prompted[conv.ID] {
+			mark = "*"
+		}
+		text := conv.Text
+		if conv.Edited {
+			text += " (edited)"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", mark, conv.ID, conv.Accepted, conv.Rejected, text)
+	}
+	return w.Flush()
+}
+
+func runConventionsEdit(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	text := strings.TrimSpace(args[1])
+	if text == "" {
+		return fmt.Errorf("convention text is empty; use goreview conventions remove to forget it")
+	}
+	conventions, err := openConventions(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conventions.Close() }()
+	return conventions.Edit(cmd.Context(), args[0], text)
+}
+
+func runConventionsRemove(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	conventions, err := openConventions(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conventions.Close() }()
+	for _, id := range args {
+		if err := conventions.Delete(cmd.Context(), id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+var debtCmd = &cobra.Command{
+	Use:   "debt",
+	Short: "Track TODO/FIXME/HACK comments as technical debt",
+	Long: `Track the technical debt comments added in reviewed changes.
+
+With review.debt.enabled, 'goreview review' records every TODO, FIXME and
+HACK comment added by the diff in the history database, and reports those
+missing the owner or ticket reference required by review.debt.require.`,
+}
+
+var debtListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded debt, oldest first",
+	Long: `List the debt comments recorded for this repository, grouped by file or
+author, with the age of each item since it was first seen.
+
+Items whose comment is no longer in the file are hidden; --prune also
+removes them from the history database.
+
+Examples:
+  # Debt by file
+  goreview debt list
+
+  # Debt by author, as JSON
+  goreview debt list --by author --format json`,
+	Args: cobra.NoArgs,
+	RunE: runDebtList,
+}
+
+func init() {
+	rootCmd.AddCommand(debtCmd)
+	debtCmd.AddCommand(debtListCmd)
+
+	debtListCmd.Flags().String("by", "file", "Group items by file or author")
+	debtListCmd.Flags().StringP("format", "f", "text", "Output format (text, json)")
+	debtListCmd.Flags().Bool("prune", false, "Remove items whose comment is no longer in the code")
+}
+
+// recordDebt stores the debt comments found by the review in the history
+// database. Failures are reported but don't fail the review.
+func recordDebt(ctx context.Context, cfg *config.Config, result *review.Result) {
+	if !cfg.Review.Debt.Enabled {
+		return
+	}
+	root, err := runGitCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return
+	}
+	repo := strings.TrimSpace(root)
+	author, commit := debtOrigin(cfg)
+	branch := history.GetCurrentBranch(repo)
+
+	var items []*history.DebtItem
+	for _, f := range result.Files {
+		for _, d := range f.Debt {
+			items = append(items, &history.DebtItem{
+				Repo: repo, FilePath: d.File, Line: d.Line, Marker: d.Marker, Text: d.Text,
+				Owner: d.Owner, Ticket: d.Ticket, Author: author, CommitHash: commit, Branch: branch,
+			})
+		}
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	store, err := history.NewStore(history.StoreConfig{Path: getHistoryDBPath(cfg)})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record debt: %v\n", err)
+		return
+	}
+	defer store.Close()
+	if err := store.StoreDebt(ctx, items); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record debt: %v\n", err)
+	}
+}
+
+// debtOrigin returns who added the reviewed changes and in which commit: the
+// commit's author when reviewing a commit, otherwise the current git user.
+func debtOrigin(cfg *config.Config) (author, commit string) {
+	if cfg.Review.Mode == "commit" && cfg.Review.Commit != "" {
+		if out, err := runGitCommand("log", "-1", "--format=%an%n%H", cfg.Review.Commit); err == nil {
+			if parts := strings.SplitN(strings.TrimSpace(out), "\n", 2); len(parts) == 2 {
+				return parts[0], parts[1]
+			}
+		}
+	}
+	if out, err := runGitCommand("config", "user.name"); err == nil {
+		author = strings.TrimSpace(out)
+	}
+	if out, err := runGitCommand("rev-parse", "HEAD"); err == nil {
+		commit = strings.TrimSpace(out)
+	}
+	return author, commit
+}
+
+func runDebtList(cmd *cobra.Command, _ []string) error {
+	by, _ := cmd.Flags().GetString("by")
+	if by != "file" && by != "author" {
+		return fmt.Errorf("invalid --by %q, must be file or author", by)
+	}
+	format, _ := cmd.Flags().GetString("format")
+	prune, _ := cmd.Flags().GetBool("prune")
+
+	root, err := runGitCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("not a git repository")
+	}
+	repo := strings.TrimSpace(root)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	store, err := history.NewStore(history.StoreConfig{Path: getHistoryDBPath(cfg)})
+	if err != nil {
+		return fmt.Errorf("opening history database: %w", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	all, err := store.ListDebt(ctx, repo)
+	if err != nil {
+		return err
+	}
+	items, gone := splitResolvedDebt(repo, all)
+	if prune && len(gone) > 0 {
+		if err := store.DeleteDebt(ctx, gone); err != nil {
+			return err
+		}
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "Pruned %d resolved debt items\n", len(gone))
+		}
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling debt: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	printDebt(items, by, time.Now())
+	return nil
+}
+
+// splitResolvedDebt separates the items still in the code from the IDs of
+// those whose file no longer holds the comment.
+func splitResolvedDebt(repo string, items []history.DebtItem) ([]history.DebtItem, []int64) {
+	contents := make(map[string]string)
+	var open []history.DebtItem
+	var gone []int64
+	for _, item := range items {
+		content, ok := contents[item.FilePath]
+		if !ok {
+			data, err := os.ReadFile(filepath.Join(repo, item.FilePath)) //nolint:gosec // Path recorded from git diff output
+			if err == nil {
+				content = string(data)
+			}
+			contents[item.FilePath] = content
+		}
+		if strings.Contains(content, item.Marker) && strings.Contains(content, item.Text) {
+			open = append(open, item)
+		} else {
+			gone = append(gone, item.ID)
+		}
+	}
+	return open, gone
+}
+
+// printDebt prints the items grouped by file or author, groups ordered by
+// their oldest item.
+func printDebt(items []history.DebtItem, by string, now time.Time) {
+	if len(items) == 0 {
+		fmt.Println("No technical debt recorded.")
+		return
+	}
+	fmt.Printf("Technical debt: %d items, oldest %s\n", len(items), debtAge(items[0].CreatedAt, now))
+
+	var keys []string
+	groups := make(map[string][]history.DebtItem)
+	for _, item := range items {
+		key := item.FilePath
+		if by == "author" {
+			key = item.Author
+			if key == "" {
+				key = "(unknown author)"
+			}
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+
+	for _, key := range keys {
+		fmt.Printf("\n%s (%d)\n", key, len(groups[key]))
+		for _, item := range groups[key] {
+			where := fmt.Sprintf("L%d", item.Line)
+			if by == "author" {
+				where = fmt.Sprintf("%s:%d", item.FilePath, item.Line)
+			}
+			fmt.Printf("  %5s  %-24s %s %s\n", debtAge(item.CreatedAt, now), where, debtLabel(item), item.Text)
+		}
+	}
+}
+
+// debtLabel renders the marker with its references, like TODO(alice, PROJ-1).
+func debtLabel(item history.DebtItem) string {
+	var refs []string
+	for _, r := range []string{item.Owner, item.Ticket} {
+		if r != "" {
+			refs = append(refs, r)
+		}
+	}
+	if len(refs) == 0 {
+		return item.Marker
+	}
+	return item.Marker + "(" + strings.Join(refs, ", ") + ")"
+}
+
+// debtAge formats the time since an item was first seen, in days.
+func debtAge(since, now time.Time) string {
+	days := int(now.Sub(since).Hours() / 24)
+	if days < 1 {
+		return "<1d"
+	}
+	return fmt.Sprintf("%dd", days)
+}
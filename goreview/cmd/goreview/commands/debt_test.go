@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/history"
+)
+
+func TestSplitResolvedDebt(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "a.go"), []byte("// TODO(alice): retry\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []history.DebtItem{
+		{ID: 1, FilePath: "a.go", Marker: "TODO", Text: "retry"},
+		{ID: 2, FilePath: "a.go", Marker: "FIXME", Text: "leak"},
+		{ID: 3, FilePath: "deleted.go", Marker: "TODO", Text: "gone"},
+	}
+	open, gone := splitResolvedDebt(repo, items)
+	if len(open) != 1 || open[0].ID != 1 {
+		t.Errorf("open = %+v, want item 1", open)
+	}
+	if len(gone) != 2 || gone[0] != 2 || gone[1] != 3 {
+		t.Errorf("gone = %v, want [2 3]", gone)
+	}
+}
+
+func TestDebtLabelAndAge(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		item      history.DebtItem
+		wantLabel string
+		wantAge   string
+	}{
+		{history.DebtItem{Marker: "TODO", CreatedAt: now.Add(-time.Hour)}, "TODO", "<1d"},
+		{history.DebtItem{Marker: "FIXME", Owner: "alice", CreatedAt: now.Add(-50 * time.Hour)}, "FIXME(alice)", "2d"},
+		{history.DebtItem{Marker: "HACK", Owner: "bob", Ticket: "PROJ-1", CreatedAt: now.AddDate(0, 0, -90)}, "HACK(bob, PROJ-1)", "90d"},
+	}
+	for _, tt := range tests {
+		if got := debtLabel(tt.item); got != tt.wantLabel {
+			t.Errorf("debtLabel() = %q, want %q", got, tt.wantLabel)
+		}
+		if got := debtAge(tt.item.CreatedAt, now); got != tt.wantAge {
+			t.Errorf("debtAge() = %q, want %q", got, tt.wantAge)
+		}
+	}
+}
@@ -3,14 +3,16 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/diffsummary"
 	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/ignore"
 	"github.com/JNZader/goreview/goreview/internal/providers"
 )
 
@@ -23,6 +25,9 @@ Examples:
   # Generate docs for staged changes
   goreview doc --staged
 
+  # Generate docs for all uncommitted work, including new files
+  goreview doc --worktree
+
   # Generate docs for specific files
   goreview doc src/main.go src/utils.go
 
@@ -42,6 +47,8 @@ func init() {
 
 	// Input flags
 	docCmd.Flags().Bool("staged", false, "Document staged changes")
+	docCmd.Flags().Bool("worktree", false, "Document all uncommitted changes, staged or not, and the untracked files")
+	docCmd.Flags().Bool("include-untracked", false, "With --staged, also document the untracked files")
 	docCmd.Flags().String("commit", "", "Document a specific commit")
 	docCmd.Flags().String("range", "", "Document commit range (from..to)")
 
@@ -53,6 +60,10 @@ func init() {
 	docCmd.Flags().String("context", "", "Additional context for generation")
 	docCmd.Flags().String("template", "", "Custom template file")
 
+	// Provider flags
+	addProviderFlags(docCmd)
+	addTimeoutFlag(docCmd, 5*time.Minute)
+
 	// Output flags
 	docCmd.Flags().StringP("output", "o", "", "Write to file")
 	docCmd.Flags().Bool("append", false, "Append to existing file")
@@ -61,13 +72,16 @@ func init() {
 
 func runDoc(cmd *cobra.Command, args []string) error {
 	// Load configuration
-	cfg, err := config.LoadDefault()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
+	if err = applyProviderFlags(cmd, cfg); err != nil {
+		return err
+	}
 
 	// Create context
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := commandContext(cmd)
 	defer cancel()
 
 	// Initialize git repo
@@ -82,6 +96,12 @@ func runDoc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	m, err := loadIgnore(cfg)
+	if err != nil {
+		return err
+	}
+	diff.Files = documentedFiles(diff.Files, m)
+
 	if len(diff.Files) == 0 {
 		return fmt.Errorf("no changes found to document")
 	}
@@ -98,7 +118,13 @@ func runDoc(cmd *cobra.Command, args []string) error {
 	style, _ := cmd.Flags().GetString("style")
 	customContext, _ := cmd.Flags().GetString("context")
 
-	docContext := buildDocContext(diff, docType, style, customContext)
+	// The working tree gives the summaries whole functions; files that
+	// don't match the diff are summarized from its hunks
+	var files fs.FS
+	if root, err := gitRepo.GetRepoRoot(ctx); err == nil {
+		files = os.DirFS(root)
+	}
+	docContext := buildDocContext(diff, files, docType, style, customContext)
 
 	// Generate documentation
 	diffText := formatDiffForDoc(diff)
@@ -123,9 +149,40 @@ func runDoc(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// documentedFiles drops the files left out by the include patterns,
+// exclude patterns and ignore file, as review does.
+func documentedFiles(files []git.FileDiff, m *ignore.Matcher) []git.FileDiff {
+	kept := make([]git.FileDiff, 0, len(files))
+	for _, f := range files {
+		if d := m.Match(f.Path); d.Ignored() {
+			if isVerbose() {
+				fmt.Fprintf(os.Stderr, "Skipping %s: %s\n", f.Path, describeDecision(d))
+			}
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
 func getDocDiff(cmd *cobra.Command, args []string, repo git.Repository, ctx context.Context) (*git.Diff, error) {
+	if err := validateIncludeUntracked(cmd); err != nil {
+		return nil, err
+	}
 	if staged, _ := cmd.Flags().GetBool("staged"); staged {
-		return repo.GetStagedDiff(ctx)
+		diff, err := repo.GetStagedDiff(ctx)
+		if include, _ := cmd.Flags().GetBool("include-untracked"); err != nil || !include {
+			return diff, err
+		}
+		return git.AddUntracked(ctx, repo, diff)
+	}
+
+	if worktree, _ := cmd.Flags().GetBool("worktree"); worktree {
+		wt, ok := repo.(git.WorktreeRepository)
+		if !ok {
+			return nil, git.ErrNoWorktree
+		}
+		return wt.GetWorktreeDiff(ctx)
 	}
 
 	if commit, _ := cmd.Flags().GetString("commit"); commit != "" {
@@ -133,13 +190,17 @@ func getDocDiff(cmd *cobra.Command, args []string, repo git.Repository, ctx cont
 	}
 
 	if len(args) > 0 {
-		return repo.GetFileDiff(ctx, args)
+		diff, err := repo.GetFileDiff(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return git.AddUntracked(ctx, repo, diff, args...)
 	}
 
-	return nil, fmt.Errorf("specify --staged, --commit, or file arguments")
+	return nil, fmt.Errorf("specify --staged, --worktree, --commit, or file arguments")
 }
 
-func buildDocContext(diff *git.Diff, docType, style, customContext string) string {
+func buildDocContext(diff *git.Diff, files fs.FS, docType, style, customContext string) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("Generate %s documentation in %s format.\n\n", docType, style))
@@ -177,6 +238,11 @@ func buildDocContext(diff *git.Diff, docType, style, customContext string) strin
 	sb.WriteString("\n\nFiles changed:\n")
 	for _, f := range diff.Files {
 		sb.WriteString(fmt.Sprintf("- %s (%s)\n", f.Path, f.Status))
+		for _, line := range strings.Split(strings.TrimSuffix(changeSummary(f, files), "\n"), "\n") {
+			if line != "" {
+				sb.WriteString("  " + line + "\n")
+			}
+		}
 	}
 
 	return sb.String()
@@ -264,3 +330,15 @@ func writeDocOutput(path, content string, appendMode, prependMode bool) error {
 	}
 	return nil
 }
+
+// changeSummary returns the structured summary of a file's change, read
+// with the file's content in files when there.
+func changeSummary(f git.FileDiff, files fs.FS) string {
+	content := ""
+	if files != nil {
+		if data, err := fs.ReadFile(files, f.Path); err == nil {
+			content = string(data)
+		}
+	}
+	return diffsummary.Summarize(f, content).String()
+}
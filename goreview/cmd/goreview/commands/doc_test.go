@@ -3,6 +3,7 @@ package commands
 import (
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/JNZader/goreview/goreview/internal/git"
 )
@@ -28,7 +29,7 @@ func TestBuildDocContext(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.docType, func(t *testing.T) {
-			result := buildDocContext(diff, tt.docType, tt.style, "")
+			result := buildDocContext(diff, nil, tt.docType, tt.style, "")
 			if !strings.Contains(result, tt.wantContain) {
 				t.Errorf("buildDocContext() should contain %q, got %q", tt.wantContain, result)
 			}
@@ -44,7 +45,7 @@ func TestBuildDocContextWithCustomContext(t *testing.T) {
 	}
 
 	customCtx := "This is custom context for testing"
-	result := buildDocContext(diff, "changes", "markdown", customCtx)
+	result := buildDocContext(diff, nil, "changes", "markdown", customCtx)
 
 	if !strings.Contains(result, "Additional context:") {
 		t.Error("Should contain 'Additional context:' section")
@@ -63,7 +64,7 @@ func TestBuildDocContextFileSummary(t *testing.T) {
 		},
 	}
 
-	result := buildDocContext(diff, "changes", "markdown", "")
+	result := buildDocContext(diff, nil, "changes", "markdown", "")
 
 	if !strings.Contains(result, "Files changed:") {
 		t.Error("Should contain 'Files changed:' section")
@@ -221,6 +222,29 @@ func TestLoadTemplateNotFound(t *testing.T) {
 	}
 }
 
+func TestBuildDocContextChangeSummary(t *testing.T) {
+	diff, err := git.ParseDiff("diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n" +
+		"@@ -5,3 +5,6 @@\n \tb()\n-\tx()\n+\tc()\n }\n+\n+func Added() {}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff.Files[0].Language = "go"
+
+	result := buildDocContext(diff, nil, "changes", "markdown", "")
+	if !strings.Contains(result, "- a.go (modified)\n  Functions added: func Added()\n") {
+		t.Errorf("buildDocContext() should summarize the change from the diff, got %q", result)
+	}
+	if strings.Contains(result, "Functions modified") {
+		t.Errorf("buildDocContext() without the file can't see modified functions, got %q", result)
+	}
+
+	files := fstest.MapFS{"a.go": {Data: []byte("package a\n\nfunc Long() {\n\ta()\n\tb()\n\tc()\n}\n\nfunc Added() {}\n")}}
+	result = buildDocContext(diff, files, "changes", "markdown", "")
+	if !strings.Contains(result, "  Functions modified: Long\n") {
+		t.Errorf("buildDocContext() should summarize the change with the file, got %q", result)
+	}
+}
+
 func TestFormatDiffForDoc(t *testing.T) {
 	diff := &git.Diff{
 		Files: []git.FileDiff{
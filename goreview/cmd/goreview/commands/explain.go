@@ -0,0 +1,398 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/export"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rag"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <issue-id|file:line>",
+	Short: "Explain a finding for learning",
+	Long: `Explain a finding of an earlier review the way a mentor would: why it is
+a problem, an example of the bug or exploit it allows, the idiomatic fix
+and what to take away from it. Aimed at developers new to the codebase
+or the language.
+
+The finding is an issue ID shown by reports and 'goreview triage list',
+or the file and line of a recorded issue; issues are recorded with
+review.triage.enabled. The sections of the repository's style guides
+(STYLEGUIDE.md, CONTRIBUTING.md...) relevant to the finding are given to
+the model and linked at the end.
+
+With --obsidian the explanation is also appended to the project's
+learning note in the Obsidian vault (export.obsidian).
+
+Examples:
+  # Explain issue 42
+  goreview explain 42
+
+  # Explain the issue recorded at a line
+  goreview explain internal/api/handler.go:87
+
+  # Keep it in the Obsidian vault too
+  goreview explain 42 --obsidian`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplain,
+}
+
+// explainContextLines is how many lines around the finding are shown to the
+// model.
+const explainContextLines = 8
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().StringP("format", "f", "markdown", "Output format (markdown, json)")
+	explainCmd.Flags().Int("guides", 3, "Maximum style guide sections to use and link")
+	explainCmd.Flags().Bool("obsidian", false, "Append the explanation to the Obsidian learning note")
+	explainCmd.Flags().String("vault", "", "Obsidian vault path (overrides config)")
+	addProviderFlags(explainCmd)
+	addTimeoutFlag(explainCmd, 2*time.Minute)
+}
+
+// Explanation is the educational explanation of a finding.
+type Explanation struct {
+	IssueID   int64    `json:"issue_id"`
+	File      string   `json:"file"`
+	Line      int      `json:"line,omitempty"`
+	Severity  string   `json:"severity"`
+	Type      string   `json:"type"`
+	Message   string   `json:"message"`
+	Why       string   `json:"why"`
+	Example   string   `json:"example"`
+	Fix       string   `json:"fix"`
+	FixedCode string   `json:"fixed_code,omitempty"`
+	Takeaways []string `json:"takeaways,omitempty"`
+	// Guides are the style guide sections relevant to the finding
+	Guides []GuideLink `json:"guides,omitempty"`
+}
+
+// GuideLink links a style guide section.
+type GuideLink struct {
+	Title string `json:"title"`
+	Link  string `json:"link"`
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if format != "markdown" && format != "json" {
+		return fmt.Errorf("invalid --format %q (valid: markdown, json)", format)
+	}
+	guides, _ := cmd.Flags().GetInt("guides")
+	toObsidian, _ := cmd.Flags().GetBool("obsidian")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err = applyProviderFlags(cmd, cfg); err != nil {
+		return err
+	}
+	if vault, _ := cmd.Flags().GetString("vault"); vault != "" {
+		cfg.Export.Obsidian.VaultPath = vault
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	record, err := findExplainIssue(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	root := "."
+	if out, err := runGitCommand("rev-parse", "--show-toplevel"); err == nil && strings.TrimSpace(out) != "" {
+		root = strings.TrimSpace(out)
+	}
+	code := readCodeAround(filepath.Join(root, filepath.FromSlash(record.FilePath)), record.Line, explainContextLines)
+
+	var sections []rag.RetrievalResult
+	if guides > 0 {
+		sections = retrieveGuides(root, record, code, guides)
+	}
+
+	provider, err := providers.NewProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("initializing provider: %w", err)
+	}
+	defer func() { _ = provider.Close() }()
+
+	explanation, err := explainIssue(ctx, provider, record, code, sections)
+	if err != nil {
+		return err
+	}
+	explanation.Guides = guideLinks(root, sections)
+
+	if format == "json" {
+		data, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling explanation: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(formatExplanation(explanation))
+	}
+
+	if toObsidian {
+		return appendExplanationToObsidian(ctx, cfg, explanation)
+	}
+	return nil
+}
+
+// findExplainIssue looks up the finding named by an issue ID or file:line
+// in the history database.
+func findExplainIssue(ctx context.Context, target string) (*history.ReviewRecord, error) {
+	id, file, line, err := parseExplainTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	store, err := openTriageStore()
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	var record *history.ReviewRecord
+	if id > 0 {
+		record, err = store.Issue(ctx, id)
+	} else {
+		record, err = store.IssueAt(ctx, file, line)
+	}
+	if errors.Is(err, history.ErrIssueNotFound) {
+		return nil, fmt.Errorf("no recorded issue %s (issues are recorded by reviews with review.triage.enabled)", target)
+	}
+	return record, err
+}
+
+// parseExplainTarget parses an issue ID, or a file and line.
+func parseExplainTarget(target string) (id int64, file string, line int, err error) {
+	if id, err := strconv.ParseInt(strings.TrimPrefix(target, "#"), 10, 64); err == nil && id > 0 {
+		return id, "", 0, nil
+	}
+	i := strings.LastIndex(target, ":")
+	if i <= 0 {
+		return 0, "", 0, fmt.Errorf("invalid finding %q, want an issue ID or file:line", target)
+	}
+	line, err = strconv.Atoi(target[i+1:])
+	if err != nil || line <= 0 {
+		return 0, "", 0, fmt.Errorf("invalid line in %q", target)
+	}
+	return 0, filepath.ToSlash(filepath.Clean(target[:i])), line, nil
+}
+
+// readCodeAround returns the lines of a file around line, or "" when the
+// file can't be read.
+func readCodeAround(path string, line, around int) string {
+	data, err := os.ReadFile(path) // #nosec G304 - path of a reviewed file
+	if err != nil || line <= 0 {
+		return ""
+	}
+	lines := strings.Split(string(data), "\n")
+	if line > len(lines) {
+		return ""
+	}
+	start := max(line-1-around, 0)
+	end := min(line+around, len(lines))
+	var sb strings.Builder
+	for i := start; i < end; i++ {
+		marker := "  "
+		if i == line-1 {
+			marker = "> "
+		}
+		sb.WriteString(fmt.Sprintf("%s%4d | %s\n", marker, i+1, lines[i]))
+	}
+	return sb.String()
+}
+
+// retrieveGuides returns the sections of the repository's style guides
+// most relevant to the finding.
+func retrieveGuides(root string, record *history.ReviewRecord, code string, limit int) []rag.RetrievalResult {
+	index := rag.NewIndex()
+	if err := index.LoadFromDirectory(root); err != nil && isVerbose() {
+		fmt.Fprintf(os.Stderr, "Warning: style guides not loaded: %v\n", err)
+	}
+	return index.Retrieve(rag.RetrievalQuery{
+		Language:    git.DetectLanguage(record.FilePath, code),
+		FilePath:    record.FilePath,
+		CodeContext: record.Message + "\n" + code,
+		Tags:        []string{strings.ToLower(record.IssueType)},
+	}, limit)
+}
+
+// explainIssue asks the provider for the explanation of a finding.
+func explainIssue(ctx context.Context, provider providers.Provider, record *history.ReviewRecord, code string, sections []rag.RetrievalResult) (*Explanation, error) {
+	stringList := map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+	text := map[string]interface{}{"type": "string"}
+	schema := providers.JSONSchema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"why": text, "example": text, "fix": text, "fixed_code": text, "takeaways": stringList,
+		},
+		"required": []string{"why", "example", "fix"},
+	}
+
+	response, err := providers.GenerateJSON(ctx, provider, buildExplainPrompt(record, code, sections), schema)
+	if err != nil {
+		return nil, fmt.Errorf("getting AI response: %w", err)
+	}
+	explanation, err := parseExplanation(response)
+	if err != nil {
+		return nil, err
+	}
+	explanation.IssueID = record.ID
+	explanation.File = record.FilePath
+	explanation.Line = record.Line
+	explanation.Severity = record.Severity
+	explanation.Type = record.IssueType
+	explanation.Message = record.Message
+	return explanation, nil
+}
+
+func buildExplainPrompt(record *history.ReviewRecord, code string, sections []rag.RetrievalResult) string {
+	var sb strings.Builder
+	sb.WriteString(`You are a patient senior engineer mentoring a junior developer. A code review found the issue below. Explain it so the developer learns from it, in plain language and without jargon they may not know.
+
+`)
+	sb.WriteString(fmt.Sprintf("Issue: %s\nType: %s\nSeverity: %s\nFile: %s\n", record.Message, record.IssueType, record.Severity, record.FilePath))
+	if record.Line > 0 {
+		sb.WriteString(fmt.Sprintf("Line: %d\n", record.Line))
+	}
+	if record.Suggestion != "" {
+		sb.WriteString(fmt.Sprintf("Reviewer suggestion: %s\n", record.Suggestion))
+	}
+	if code != "" {
+		sb.WriteString("\nCode (the line marked with > is the finding):\n---\n" + code + "---\n")
+	}
+	if guide := rag.FormatForPrompt(sections, 3000); guide != "" {
+		sb.WriteString("\nThe project's style guide says:\n\n" + guide)
+		sb.WriteString("Refer to these rules where they apply.\n")
+	}
+	sb.WriteString(`
+Return a JSON object with:
+- why: why this is a problem, and what principle it breaks
+- example: a concrete scenario where it causes a bug or can be exploited, with inputs and outcome
+- fix: the idiomatic fix for this language and codebase, and why it is better
+- fixed_code: the corrected code for the snippet, if a code change applies
+- takeaways: two or three short lessons to remember
+
+Provide valid JSON only. No other text.`)
+	return sb.String()
+}
+
+func parseExplanation(response string) (*Explanation, error) {
+	response = strings.TrimSpace(response)
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start == -1 || end <= start {
+		return nil, fmt.Errorf("no JSON object found in response")
+	}
+	var explanation Explanation
+	if err := json.Unmarshal([]byte(response[start:end+1]), &explanation); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	if strings.TrimSpace(explanation.Why) == "" || strings.TrimSpace(explanation.Fix) == "" {
+		return nil, fmt.Errorf("explanation is missing why or fix")
+	}
+	return &explanation, nil
+}
+
+var anchorStrip = regexp.MustCompile(`[^\p{L}\p{N} _-]+`)
+
+// guideLinks links the style guide sections, relative to the repository
+// root, with Markdown heading anchors.
+func guideLinks(root string, sections []rag.RetrievalResult) []GuideLink {
+	links := make([]GuideLink, 0, len(sections))
+	for _, s := range sections {
+		source := s.Source
+		if rel, err := filepath.Rel(root, source); err == nil {
+			source = rel
+		}
+		anchor := strings.ReplaceAll(anchorStrip.ReplaceAllString(strings.ToLower(s.Section.Title), ""), " ", "-")
+		links = append(links, GuideLink{Title: s.Section.Title, Link: filepath.ToSlash(source) + "#" + anchor})
+	}
+	return links
+}
+
+// explanationTitle is the heading of an explanation.
+func explanationTitle(e *Explanation) string {
+	location := e.File
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", e.File, e.Line)
+	}
+	return fmt.Sprintf("#%d %s (%s)", e.IssueID, e.Message, location)
+}
+
+// formatExplanation renders an explanation as Markdown.
+func formatExplanation(e *Explanation) string {
+	var sb strings.Builder
+	sb.WriteString("# " + explanationTitle(e) + "\n\n")
+	sb.WriteString(formatExplanationBody(e))
+	return sb.String()
+}
+
+// formatExplanationBody renders the sections of an explanation.
+func formatExplanationBody(e *Explanation) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**Severity:** %s | **Type:** %s\n\n", e.Severity, e.Type))
+	sb.WriteString("### Why it's a problem\n\n" + strings.TrimSpace(e.Why) + "\n\n")
+	if e.Example != "" {
+		sb.WriteString("### What can go wrong\n\n" + strings.TrimSpace(e.Example) + "\n\n")
+	}
+	sb.WriteString("### How to fix it\n\n" + strings.TrimSpace(e.Fix) + "\n\n")
+	if e.FixedCode != "" {
+		lang := git.DetectLanguage(e.File, e.FixedCode)
+		if lang == "unknown" {
+			lang = ""
+		}
+		sb.WriteString("```" + lang + "\n" + strings.TrimSpace(e.FixedCode) + "\n```\n\n")
+	}
+	if len(e.Takeaways) > 0 {
+		sb.WriteString("### Takeaways\n\n")
+		for _, t := range e.Takeaways {
+			sb.WriteString("- " + t + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	if len(e.Guides) > 0 {
+		sb.WriteString("### Style guide\n\n")
+		for _, g := range e.Guides {
+			sb.WriteString(fmt.Sprintf("- [%s](%s)\n", g.Title, g.Link))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// appendExplanationToObsidian appends the explanation to the project's
+// learning note in the Obsidian vault.
+func appendExplanationToObsidian(ctx context.Context, cfg *config.Config, e *Explanation) error {
+	exporter, err := export.NewObsidianExporter(&cfg.Export.Obsidian)
+	if err != nil {
+		return fmt.Errorf("obsidian export: %w", err)
+	}
+	note, err := exporter.AppendLearning(explanationTitle(e), formatExplanationBody(e), buildExportMetadata(ctx, cfg))
+	if err != nil {
+		return err
+	}
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Explanation appended to %s\n", note)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/rag"
+)
+
+func TestParseExplainTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		id      int64
+		file    string
+		line    int
+		wantErr bool
+	}{
+		{target: "42", id: 42},
+		{target: "#42", id: 42},
+		{target: "./internal/api/handler.go:87", file: "internal/api/handler.go", line: 87},
+		{target: "handler.go", wantErr: true},
+		{target: "handler.go:0", wantErr: true},
+		{target: ":12", wantErr: true},
+	}
+	for _, tt := range tests {
+		id, file, line, err := parseExplainTarget(tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseExplainTarget(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			continue
+		}
+		if id != tt.id || file != tt.file || line != tt.line {
+			t.Errorf("parseExplainTarget(%q) = %d, %q, %d", tt.target, id, file, line)
+		}
+	}
+}
+
+func TestFormatExplanation(t *testing.T) {
+	explanation, err := parseExplanation("```json\n" + `{"why": "Errors are lost.", "example": "A full disk goes unnoticed.",
+		"fix": "Return the error.", "fixed_code": "if err != nil {\n\treturn err\n}", "takeaways": ["Handle every error"]}` + "\n```")
+	if err != nil {
+		t.Fatalf("parseExplanation() error = %v", err)
+	}
+	explanation.IssueID, explanation.File, explanation.Line = 7, "store/save.go", 31
+	explanation.Message = "unchecked error"
+
+	root := t.TempDir()
+	explanation.Guides = guideLinks(root, []rag.RetrievalResult{{
+		Section: rag.RuleSection{Title: "Error Handling (Go)"},
+		Source:  filepath.Join(root, "docs", "style-guide.md"),
+	}})
+
+	out := formatExplanation(explanation)
+	for _, want := range []string{
+		"# #7 unchecked error (store/save.go:31)",
+		"### What can go wrong\n\nA full disk goes unnoticed.",
+		"```go\nif err != nil {",
+		"- Handle every error",
+		"- [Error Handling (Go)](docs/style-guide.md#error-handling-go)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("explanation missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := parseExplanation(`{"why": "Errors are lost."}`); err == nil {
+		t.Error("parseExplanation() accepted an explanation without a fix")
+	}
+}
@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,16 +13,24 @@ import (
 
 	"github.com/JNZader/goreview/goreview/internal/config"
 	"github.com/JNZader/goreview/goreview/internal/export"
+	"github.com/JNZader/goreview/goreview/internal/failure"
 	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
 )
 
 var exportCmd = &cobra.Command{
-	Use:   "export [format]",
+	Use:   "export <exporter>...",
 	Short: "Export review results to various formats",
-	Long: `Export code review results to different formats.
+	Long: `Export code review results to one or more destinations.
 
-Supported formats:
+Exporters run concurrently; a failing exporter doesn't stop the others.
+
+Supported exporters:
   obsidian - Export to Obsidian vault with full metadata and wiki features
+  slack    - Post a summary to a Slack incoming webhook (export.slack)
+  vcs      - Comment issues on the GitHub pull request or GitLab merge request
+             (export.vcs); reruns update and resolve the earlier comments
+  webhook  - POST the full result as JSON to an HTTP endpoint (export.webhook)
 
 Examples:
   # Export from a JSON report
@@ -35,16 +43,29 @@ Examples:
   cat report.json | goreview export obsidian
 
   # Export with custom tags
-  goreview export obsidian --from report.json --tags sprint-42,backend`,
+  goreview export obsidian --from report.json --tags sprint-42,backend
+
+  # Send to several destinations and print the outcomes as JSON
+  goreview export obsidian slack webhook --from report.json --json`,
 	RunE: runExport,
 }
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
 
+	// The VCS exporter records its comments in the history database
+	export.Register("vcs",
+		func(cfg *config.ExportConfig) (export.Exporter, error) {
+			return export.NewVCSExporter(&cfg.VCS, cfg.Timeout, getHistoryDBPath(nil))
+		},
+		func(cfg *config.ExportConfig) bool { return cfg.VCS.Enabled })
+
 	// Input source
 	exportCmd.Flags().String("from", "", "Source file to export (JSON report)")
 
+	// Output
+	exportCmd.Flags().Bool("json", false, "Print the export summary as JSON")
+
 	// Obsidian options
 	exportCmd.Flags().String("vault", "", "Obsidian vault path (overrides config)")
 	exportCmd.Flags().String("folder", "", "Folder name within vault (default: GoReview)")
@@ -62,22 +83,11 @@ func init() {
 
 func runExport(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("export format required. Use: obsidian")
+		return fmt.Errorf("exporter required. Available: %s", strings.Join(export.Available(), ", "))
 	}
 
-	format := args[0]
-
-	switch format {
-	case "obsidian":
-		return runObsidianExport(cmd)
-	default:
-		return fmt.Errorf("unknown export format: %s. Supported: obsidian", format)
-	}
-}
-
-func runObsidianExport(cmd *cobra.Command) error {
 	// Load config
-	cfg, err := config.LoadDefault()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
@@ -91,27 +101,23 @@ func runObsidianExport(cmd *cobra.Command) error {
 		return fmt.Errorf("loading review result: %w", err)
 	}
 
-	// Validate vault path
-	if cfg.Export.Obsidian.VaultPath == "" {
-		return fmt.Errorf("obsidian vault path not configured (use --vault or config)")
-	}
-
 	// Build metadata
 	metadata := buildExportMetadataForExport(cmd, cfg)
 
-	// Create exporter
-	exporter, err := export.NewObsidianExporter(&cfg.Export.Obsidian)
-	if err != nil {
-		return fmt.Errorf("creating exporter: %w", err)
-	}
-
 	// Export
-	if err := exporter.Export(result, metadata); err != nil {
-		return fmt.Errorf("exporting: %w", err)
+	summary := export.NewPipeline(&cfg.Export, args).Run(result, metadata)
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		if err := summary.WriteJSON(os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		summary.WriteText(os.Stdout)
 	}
 
-	outputPath := exporter.GetOutputPath(metadata)
-	fmt.Printf("Successfully exported to Obsidian: %s\n", outputPath)
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d of %d export(s) failed", summary.Failed, len(summary.Outcomes))
+	}
 	return nil
 }
 
@@ -149,21 +155,21 @@ func loadReviewResult(cmd *cobra.Command) (*review.Result, error) {
 			return nil, fmt.Errorf("reading file: %w", err)
 		}
 
-		var result review.Result
-		if err := json.Unmarshal(data, &result); err != nil {
-			return nil, fmt.Errorf("parsing JSON: %w", err)
+		result, err := reviewtypes.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, &failure.ParseError{Code: failure.ParseReport, File: fromFile, Err: fmt.Errorf("parsing JSON: %w", err)}
 		}
-		return &result, nil
+		return review.FromPublic(result), nil
 	}
 
 	// Try to read from stdin
 	fi, _ := os.Stdin.Stat()
 	if (fi.Mode() & os.ModeCharDevice) == 0 {
-		var result review.Result
-		if err := json.NewDecoder(os.Stdin).Decode(&result); err != nil {
-			return nil, fmt.Errorf("reading stdin: %w", err)
+		result, err := reviewtypes.Decode(os.Stdin)
+		if err != nil {
+			return nil, &failure.ParseError{Code: failure.ParseReport, Err: fmt.Errorf("reading stdin: %w", err)}
 		}
-		return &result, nil
+		return review.FromPublic(result), nil
 	}
 
 	return nil, fmt.Errorf("no input source. Use --from or pipe JSON to stdin")
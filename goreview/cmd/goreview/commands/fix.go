@@ -3,6 +3,7 @@ package commands
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/JNZader/goreview/goreview/internal/config"
 	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/memory"
 	"github.com/JNZader/goreview/goreview/internal/providers"
 	"github.com/JNZader/goreview/goreview/internal/review"
 	"github.com/JNZader/goreview/goreview/internal/rules"
@@ -23,6 +25,16 @@ var fixCmd = &cobra.Command{
 	Short: "Review and automatically fix code issues",
 	Long: `Review code changes and automatically apply fixes for issues that have suggestions.
 
+In interactive mode, each fix can be applied (y), skipped (n), edited as a
+patch in $VISUAL or $EDITOR before deciding (e), or replaced by an
+alternative fix asked from the provider with extra instructions (r).
+
+With --commit-fixes, the accepted fixes are committed on their own as
+"chore: apply goreview fixes", leaving the other changes of the fixed files
+uncommitted. With --stash, they are stored as a stash entry instead, to
+apply later with "git stash pop", and the files are restored as they were.
+Either way, the fixes stay apart from your own changes in history.
+
 Examples:
   # Fix issues in staged changes (interactive mode)
   goreview fix --staged
@@ -36,6 +48,15 @@ Examples:
   # Dry-run: show what would be fixed without applying
   goreview fix --staged --dry-run
 
+  # Commit the fixes separately from your changes
+  goreview fix --staged --commit-fixes
+
+  # Stash the fixes to review them later
+  goreview fix --staged --auto --stash
+
+  # Fix all uncommitted work, including new files not added yet
+  goreview fix --worktree
+
   # Fix specific files
   goreview fix file1.go file2.go`,
 	RunE: runFix,
@@ -46,6 +67,8 @@ func init() {
 
 	// Mode flags (same as review)
 	fixCmd.Flags().Bool("staged", false, "Fix issues in staged changes")
+	fixCmd.Flags().Bool("worktree", false, "Fix issues in all uncommitted changes, staged or not, and the untracked files")
+	fixCmd.Flags().Bool("include-untracked", false, "With --staged, also fix issues in the untracked files")
 	fixCmd.Flags().String("commit", "", "Fix issues in a specific commit")
 	fixCmd.Flags().String("branch", "", "Fix issues compared to branch")
 
@@ -54,10 +77,12 @@ func init() {
 	fixCmd.Flags().Bool("dry-run", false, "Show what would be fixed without applying")
 	fixCmd.Flags().StringSlice("types", nil, "Fix only these issue types (bug, security, performance, style)")
 	fixCmd.Flags().StringSlice("severity", nil, "Fix only issues with these severities (info, warning, error, critical)")
+	fixCmd.Flags().Bool("commit-fixes", false, "Commit the applied fixes on their own (\""+fixCommitMessage+"\")")
+	fixCmd.Flags().Bool("stash", false, "Store the applied fixes as a stash entry instead of leaving them in the working tree")
 
 	// Provider flags
-	fixCmd.Flags().String("provider", "", "AI provider to use (ollama, openai)")
-	fixCmd.Flags().String("model", "", "Model to use")
+	addProviderFlags(fixCmd)
+	addTimeoutFlag(fixCmd, 10*time.Minute)
 }
 
 // FixableIssue represents an issue that can be fixed
@@ -67,6 +92,9 @@ type FixableIssue struct {
 	FixedCode string
 	StartLine int
 	EndLine   int
+	// Edited is set when the user edited the fix, whose FixedCode may then
+	// be empty to remove the lines
+	Edited bool
 }
 
 func runFix(cmd *cobra.Command, args []string) error {
@@ -76,19 +104,27 @@ func runFix(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load configuration
-	cfg, err := config.LoadDefault()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
-	applyFixFlagOverrides(cmd, cfg, args)
+	if err = applyFixFlagOverrides(cmd, cfg, args); err != nil {
+		return err
+	}
 
 	// Create context
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	ctx, cancel := commandContext(cmd)
 	defer cancel()
 
+	provider, err := providers.NewProvider(cfg)
+	if err != nil {
+		return fmt.Errorf("initializing provider: %w", err)
+	}
+	defer func() { _ = provider.Close() }()
+
 	// Run review first
 	fmt.Println("Analyzing code for fixable issues...")
-	result, err := executeFixReview(ctx, cfg)
+	result, err := executeFixReview(ctx, cfg, provider)
 	if err != nil {
 		return err
 	}
@@ -107,14 +143,53 @@ func runFix(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Apply fixes
+	// Apply fixes, learning team conventions from the user's decisions
 	autoFix, _ := cmd.Flags().GetBool("auto")
-	applyFixes(fixableIssues, autoFix)
+	var learned *memory.Conventions
+	if !autoFix && cfg.Memory.Conventions.Enabled {
+		if learned, err = openConventions(cfg); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: not learning team conventions: %v\n", err)
+		} else {
+			defer func() { _ = learned.Close() }()
+		}
+	}
+
+	// Keep the files as they were to set the fixes apart afterwards
+	commitFixed, _ := cmd.Flags().GetBool("commit-fixes")
+	stash, _ := cmd.Flags().GetBool("stash")
+	var snapshot *fixSnapshot
+	if commitFixed || stash {
+		if snapshot, err = snapshotFixFiles(ctx, fixableIssues); err != nil {
+			return err
+		}
+	}
+
+	applyFixes(ctx, fixableIssues, autoFix, learned, alternativeFix(provider))
+
+	switch {
+	case commitFixed:
+		commit, err := commitFixes(ctx, snapshot)
+		if err != nil {
+			return fmt.Errorf("committing fixes: %w", err)
+		}
+		if commit != "" {
+			fmt.Printf("Fixes committed as %s (%s)\n", commit[:7], fixCommitMessage)
+		}
+	case stash:
+		n, err := stashFixes(ctx, snapshot)
+		if err != nil {
+			return fmt.Errorf("stashing fixes: %w", err)
+		}
+		if n > 0 {
+			fmt.Printf("Fixes to %d files stashed. Commit your changes, then apply them with: git stash pop\n", n)
+		}
+	}
 	return nil
 }
 
 func validateFixFlags(cmd *cobra.Command, args []string) error {
 	staged, _ := cmd.Flags().GetBool("staged")
+	worktree, _ := cmd.Flags().GetBool("worktree")
 	commit, _ := cmd.Flags().GetString("commit")
 	branch, _ := cmd.Flags().GetString("branch")
 
@@ -122,6 +197,9 @@ func validateFixFlags(cmd *cobra.Command, args []string) error {
 	if staged {
 		modeCount++
 	}
+	if worktree {
+		modeCount++
+	}
 	if commit != "" {
 		modeCount++
 	}
@@ -133,16 +211,25 @@ func validateFixFlags(cmd *cobra.Command, args []string) error {
 	}
 
 	if modeCount == 0 {
-		return fmt.Errorf("must specify mode: --staged, --commit, --branch, or file arguments")
+		return fmt.Errorf("must specify mode: --staged, --worktree, --commit, --branch, or file arguments")
 	}
 	if modeCount > 1 {
 		return fmt.Errorf("only one mode allowed at a time")
 	}
+	if err := validateIncludeUntracked(cmd); err != nil {
+		return err
+	}
+
+	commitFixed, _ := cmd.Flags().GetBool("commit-fixes")
+	stash, _ := cmd.Flags().GetBool("stash")
+	if commitFixed && stash {
+		return fmt.Errorf("--commit-fixes and --stash cannot be used together")
+	}
 
 	return nil
 }
 
-func applyFixFlagOverrides(cmd *cobra.Command, cfg *config.Config, args []string) {
+func applyFixFlagOverrides(cmd *cobra.Command, cfg *config.Config, args []string) error {
 	mode, value := determineReviewMode(cmd, args)
 	cfg.Review.Mode = mode
 
@@ -160,35 +247,27 @@ func applyFixFlagOverrides(cmd *cobra.Command, cfg *config.Config, args []string
 			cfg.Review.Files = v
 		}
 	}
-
-	if provider, _ := cmd.Flags().GetString("provider"); provider != "" {
-		cfg.Provider.Name = provider
-	}
-	if model, _ := cmd.Flags().GetString("model"); model != "" {
-		cfg.Provider.Model = model
+	if include, _ := cmd.Flags().GetBool("include-untracked"); include {
+		cfg.Review.IncludeUntracked = true
 	}
+
+	return applyProviderFlags(cmd, cfg)
 }
 
-func executeFixReview(ctx context.Context, cfg *config.Config) (*review.Result, error) {
+func executeFixReview(ctx context.Context, cfg *config.Config, provider providers.Provider) (*review.Result, error) {
 	gitRepo, err := git.NewRepo(".")
 	if err != nil {
 		return nil, fmt.Errorf("initializing git: %w", err)
 	}
 
-	provider, err := providers.NewProvider(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("initializing provider: %w", err)
-	}
-	defer func() { _ = provider.Close() }()
-
 	if healthErr := provider.HealthCheck(ctx); healthErr != nil {
 		return nil, fmt.Errorf("provider not available: %w", healthErr)
 	}
 
 	rulesLoader := rules.NewLoader(cfg.Rules.RulesDir)
-	allRules, err := rulesLoader.Load()
+	allRules, err := loadAllRules(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("loading rules: %w", err)
+		return nil, err
 	}
 
 	presetConfig, err := rulesLoader.LoadPreset("standard")
@@ -198,6 +277,8 @@ func executeFixReview(ctx context.Context, cfg *config.Config) (*review.Result,
 	activeRules := rules.ApplyPreset(allRules, presetConfig)
 
 	engine := review.NewEngine(cfg, gitRepo, provider, nil, activeRules)
+	engine.SetVersion(Version)
+	engine.SetConventions(loadConventions(ctx, cfg))
 	result, err := engine.Run(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("review failed: %w", err)
@@ -300,7 +381,10 @@ func showDryRun(issues []FixableIssue) {
 	fmt.Println("Run without --dry-run to apply fixes.")
 }
 
-func applyFixes(issues []FixableIssue, autoFix bool) {
+// applyFixes applies the fixes, asking first unless autoFix is set. Each
+// fix the user accepts or rejects is recorded in learned, when not nil;
+// alternative asks the provider for another fix.
+func applyFixes(ctx context.Context, issues []FixableIssue, autoFix bool, learned *memory.Conventions, alternative alternativeFunc) {
 	applied := 0
 	skipped := 0
 	reader := bufio.NewReader(os.Stdin)
@@ -308,11 +392,13 @@ func applyFixes(issues []FixableIssue, autoFix bool) {
 	for _, fix := range issues {
 		displayFixDetails(fix)
 
-		shouldApply, quit := determineApplyAction(autoFix, reader)
+		var shouldApply, quit bool
+		fix, shouldApply, quit = decideFix(ctx, fix, autoFix, reader, alternative)
 		if quit {
 			fmt.Printf("\nApplied %d fixes, skipped %d\n", applied, skipped)
 			return
 		}
+		recordFixDecision(ctx, learned, fix, shouldApply)
 
 		wasApplied := tryApplyFix(fix, shouldApply)
 		if wasApplied {
@@ -325,6 +411,23 @@ func applyFixes(issues []FixableIssue, autoFix bool) {
 	fmt.Printf("\nSummary: Applied %d fixes, skipped %d\n", applied, skipped)
 }
 
+// recordFixDecision learns from the user accepting or rejecting a fix.
+func recordFixDecision(ctx context.Context, learned *memory.Conventions, fix FixableIssue, accepted bool) {
+	if learned == nil {
+		return
+	}
+	_, err := learned.Record(ctx, memory.FixDecision{
+		IssueType:  string(fix.Issue.Type),
+		RuleID:     fix.Issue.RuleID,
+		Message:    fix.Issue.Message,
+		Suggestion: fix.Issue.Suggestion,
+		Accepted:   accepted,
+	})
+	if err != nil && isVerbose() {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
 func displayFixDetails(fix FixableIssue) {
 	fmt.Printf("\n[%s] %s\n", fix.Issue.Severity, fix.Issue.Message)
 	fmt.Printf("File: %s", fix.FilePath)
@@ -359,22 +462,73 @@ func showProposedFix(fixedCode string) {
 	fmt.Println(strings.Repeat("-", 40))
 }
 
-func determineApplyAction(autoFix bool, reader *bufio.Reader) (shouldApply, quit bool) {
+// Answers to "Apply this fix?"
+const (
+	fixApply       = "apply"
+	fixSkip        = "skip"
+	fixQuit        = "quit"
+	fixEdit        = "edit"
+	fixAlternative = "alternative"
+)
+
+// decideFix asks what to do with a fix until it is applied, skipped or the
+// user quits. Edited and alternative fixes replace the fix and are asked
+// about again.
+func decideFix(ctx context.Context, fix FixableIssue, autoFix bool, reader *bufio.Reader, alternative alternativeFunc) (_ FixableIssue, shouldApply, quit bool) {
+	for {
+		switch determineApplyAction(autoFix, reader) {
+		case fixApply:
+			return fix, true, false
+		case fixQuit:
+			return fix, false, true
+		case fixEdit:
+			edited, err := editFix(fix)
+			switch {
+			case errors.Is(err, errEmptyPatch):
+				fmt.Println("Empty patch, fix skipped")
+				return fix, false, false
+			case err != nil:
+				fmt.Printf("Cannot edit the fix: %v\n", err)
+				continue
+			}
+			fix = edited
+		case fixAlternative:
+			fmt.Print("Instructions for the alternative fix (optional): ")
+			instructions, _ := reader.ReadString('\n')
+			fmt.Println("Asking for an alternative fix...")
+			code, err := alternative(ctx, fix, strings.TrimSpace(instructions))
+			if err != nil {
+				fmt.Printf("No alternative fix: %v\n", err)
+				continue
+			}
+			fix.FixedCode, fix.Issue.FixedCode, fix.Edited = code, code, false
+		default:
+			return fix, false, false
+		}
+		showProposedFix(fix.FixedCode)
+	}
+}
+
+func determineApplyAction(autoFix bool, reader *bufio.Reader) string {
 	if autoFix {
-		return true, false
+		return fixApply
 	}
 
-	fmt.Print("Apply this fix? [y/n/q] ")
+	fmt.Print("Apply this fix? [y/n/e/r/q] (e: edit as a patch, r: ask for another fix) ")
 	input, _ := reader.ReadString('\n')
 	input = strings.TrimSpace(strings.ToLower(input))
 
 	switch input {
 	case "y", "yes":
-		return true, false
+		return fixApply
 	case "q", "quit":
-		return false, true
+		return fixQuit
+	case "e", "edit":
+		return fixEdit
+	case "r", "retry":
+		return fixAlternative
 	default:
-		return false, false
+		return fixSkip
 	}
 }
 
@@ -383,7 +537,7 @@ func tryApplyFix(fix FixableIssue, shouldApply bool) bool {
 		return false
 	}
 
-	if fix.Issue.FixedCode == "" || fix.StartLine <= 0 {
+	if (fix.Issue.FixedCode == "" && !fix.Edited) || fix.StartLine <= 0 {
 		fmt.Println("Cannot auto-apply: no line information or fixed code")
 		return false
 	}
@@ -419,8 +573,11 @@ func applyFixToFile(fix FixableIssue) error {
 		fix.EndLine = fix.StartLine
 	}
 
-	// Replace the lines
-	fixedLines := strings.Split(fix.FixedCode, "\n")
+	// Replace the lines; edited fixes may remove them
+	var fixedLines []string
+	if fix.FixedCode != "" || !fix.Edited {
+		fixedLines = strings.Split(fix.FixedCode, "\n")
+	}
 
 	newLines := make([]string, 0, len(lines)-fix.EndLine+fix.StartLine-1+len(fixedLines))
 	newLines = append(newLines, lines[:fix.StartLine-1]...)
@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// errEmptyPatch is returned by editFix when the user emptied the patch.
+var errEmptyPatch = errors.New("empty patch")
+
+// alternativeFunc asks for another fix of an issue, following the user's
+// instructions, and returns its code.
+type alternativeFunc func(ctx context.Context, fix FixableIssue, instructions string) (string, error)
+
+// fixContextLines are the lines around a fix shown to the provider when
+// asking for an alternative
+const fixContextLines = 3
+
+// patchHelp heads the patch opened in the editor
+const patchHelp = `# Edit the fix below, then save and close the editor.
+# "+" lines are the fix: change, add or remove them. "-" lines are the
+# current code and must stay as they are. Lines starting with "#" are
+# ignored; delete every line to skip the fix.
+`
+
+// editFix opens the fix in the user's editor as a patch of the file and
+// returns the fix as edited.
+func editFix(fix FixableIssue) (FixableIssue, error) {
+	if fix.StartLine <= 0 {
+		return fix, errors.New("no line information")
+	}
+	current, err := fixTarget(fix)
+	if err != nil {
+		return fix, err
+	}
+
+	f, err := os.CreateTemp("", "goreview-fix-*.patch")
+	if err != nil {
+		return fix, err
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+	_, err = f.WriteString(patchHelp + fixPatch(fix, current))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fix, err
+	}
+
+	if err := runEditor(path); err != nil {
+		return fix, err
+	}
+	edited, err := os.ReadFile(path) // #nosec G304 - the temporary patch written above
+	if err != nil {
+		return fix, err
+	}
+	lines, err := parseFixPatch(string(edited), current)
+	if err != nil {
+		return fix, err
+	}
+	fix.FixedCode = strings.Join(lines, "\n")
+	fix.Issue.FixedCode = fix.FixedCode
+	fix.Edited = true
+	return fix, nil
+}
+
+// fixTarget returns the lines of the file the fix replaces.
+func fixTarget(fix FixableIssue) ([]string, error) {
+	content, err := os.ReadFile(fix.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(content), "\n")
+	if fix.StartLine > len(lines) {
+		return nil, fmt.Errorf("invalid start line %d", fix.StartLine)
+	}
+	end := fix.EndLine
+	if end < fix.StartLine || end > len(lines) {
+		end = fix.StartLine
+	}
+	return lines[fix.StartLine-1 : end], nil
+}
+
+// fixPatch returns the fix as a unified diff replacing the current lines.
+func fixPatch(fix FixableIssue, current []string) string {
+	var fixed []string
+	if fix.FixedCode != "" || !fix.Edited {
+		fixed = strings.Split(fix.FixedCode, "\n")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n", fix.FilePath, fix.FilePath)
+	fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", fix.StartLine, len(current), fix.StartLine, len(fixed))
+	for _, l := range current {
+		sb.WriteString("-" + l + "\n")
+	}
+	for _, l := range fixed {
+		sb.WriteString("+" + l + "\n")
+	}
+	return sb.String()
+}
+
+// parseFixPatch returns the lines of an edited fix patch, checking its
+// current lines are still those of the file. The hunk header's counts
+// are ignored, so lines can be added and removed freely.
+func parseFixPatch(patch string, current []string) ([]string, error) {
+	var kept []string
+	for _, l := range strings.Split(patch, "\n") {
+		if !strings.HasPrefix(l, "#") {
+			kept = append(kept, l)
+		}
+	}
+	diff, err := git.ParseDiff(strings.Join(kept, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	if len(diff.Files) == 0 || len(diff.Files[0].Hunks) == 0 {
+		return nil, errEmptyPatch
+	}
+	if len(diff.Files) > 1 || len(diff.Files[0].Hunks) > 1 {
+		return nil, errors.New("the patch must keep a single hunk")
+	}
+
+	var old, fixed []string
+	for _, l := range diff.Files[0].Hunks[0].Lines {
+		if l.Type != git.LineAddition {
+			old = append(old, l.Content)
+		}
+		if l.Type != git.LineDeletion {
+			fixed = append(fixed, l.Content)
+		}
+	}
+	if strings.Join(old, "\n") != strings.Join(current, "\n") {
+		return nil, errors.New(`the "-" and context lines no longer match the file`)
+	}
+	return fixed, nil
+}
+
+// runEditor opens a file in $VISUAL or $EDITOR, vi when neither is set.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...) // #nosec G204 - the user's own editor
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// alternativeFix returns a function asking the provider for another fix of
+// an issue, shown the lines around it, the rejected fix and the user's
+// instructions.
+func alternativeFix(provider providers.Provider) alternativeFunc {
+	return func(ctx context.Context, fix FixableIssue, instructions string) (string, error) {
+		if fix.StartLine <= 0 {
+			return "", errors.New("no line information")
+		}
+		content, err := os.ReadFile(fix.FilePath)
+		if err != nil {
+			return "", err
+		}
+		current, err := fixTarget(fix)
+		if err != nil {
+			return "", err
+		}
+		end := fix.StartLine + len(current) - 1
+
+		resp, err := provider.Review(ctx, &providers.ReviewRequest{
+			Diff:        contextHunk(string(content), fix.StartLine, end),
+			Language:    git.DetectLanguage(fix.FilePath, string(content)),
+			FilePath:    fix.FilePath,
+			FileContent: string(content),
+			Context:     alternativeContext(fix, end, instructions),
+		})
+		if err != nil {
+			return "", err
+		}
+
+		var code string
+		for _, issue := range resp.Issues {
+			if issue.FixedCode == "" || issue.FixedCode == fix.FixedCode {
+				continue
+			}
+			if issue.Location != nil && issue.Location.StartLine <= end && issue.Location.EndLine >= fix.StartLine {
+				return issue.FixedCode, nil
+			}
+			if code == "" {
+				code = issue.FixedCode
+			}
+		}
+		if code == "" {
+			return "", errors.New("the provider proposed no other fix")
+		}
+		return code, nil
+	}
+}
+
+// contextHunk shows the lines from start to end of a file, and a few
+// around them, as unchanged lines of a diff.
+func contextHunk(content string, start, end int) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	from := max(1, start-fixContextLines)
+	to := min(len(lines), end+fixContextLines)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", from, to-from+1, from, to-from+1)
+	for _, l := range lines[from-1 : to] {
+		sb.WriteString(" " + l + "\n")
+	}
+	return sb.String()
+}
+
+// alternativeContext tells the provider which fix to replace and how.
+func alternativeContext(fix FixableIssue, end int, instructions string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Propose a different fix for this %s issue at lines %d-%d: %s\n", fix.Issue.Type, fix.StartLine, end, fix.Issue.Message)
+	fmt.Fprintf(&sb, "Report only this issue, at those lines, with fixed_code replacing exactly lines %d-%d.\n", fix.StartLine, end)
+	if fix.FixedCode != "" {
+		fmt.Fprintf(&sb, "This fix was rejected, do not propose it again:\n```\n%s\n```\n", fix.FixedCode)
+	}
+	if instructions != "" {
+		fmt.Fprintf(&sb, "Instructions from the developer: %s\n", instructions)
+	}
+	return sb.String()
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// fixProvider answers reviews with canned issues and records the requests
+type fixProvider struct {
+	planProvider
+	issues   []providers.Issue
+	requests []*providers.ReviewRequest
+}
+
+func (p *fixProvider) Review(_ context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+	p.requests = append(p.requests, req)
+	return &providers.ReviewResponse{Issues: p.issues}, nil
+}
+
+func writeFixFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "save.go")
+	content := "package store\n\nfunc save(f *os.File) error {\n\tf.Close()\n\treturn nil\n}\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// useEditor sets the editor to a script running the shell commands on the
+// patch, "$1".
+func useEditor(t *testing.T, script string) {
+	t.Helper()
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\n"+script+"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+}
+
+func TestEditFix(t *testing.T) {
+	path := writeFixFile(t)
+	fix := FixableIssue{FilePath: path, StartLine: 4, EndLine: 5, FixedCode: "\tif err := f.Close(); err != nil {\n\t\treturn err\n\t}\n\treturn nil"}
+	fix.Issue.FixedCode = fix.FixedCode
+
+	t.Run("edited", func(t *testing.T) {
+		// Wrap the error and drop the last line of the fix
+		useEditor(t, `sed -i -e 's/return err$/return fmt.Errorf("closing: %w", err)/' -e '/^+\treturn nil$/d' "$1"`)
+		edited, err := editFix(fix)
+		if err != nil {
+			t.Fatalf("editFix() error = %v", err)
+		}
+		want := "\tif err := f.Close(); err != nil {\n\t\treturn fmt.Errorf(\"closing: %w\", err)\n\t}"
+		if !edited.Edited || edited.FixedCode != want || edited.Issue.FixedCode != want {
+			t.Errorf("edited fix = %q", edited.FixedCode)
+		}
+		if !tryApplyFix(edited, true) {
+			t.Fatal("edited fix not applied")
+		}
+		data, _ := os.ReadFile(path)
+		if !strings.Contains(string(data), "\t}\n}\n") || strings.Contains(string(data), "return nil") {
+			t.Errorf("file =\n%s", data)
+		}
+	})
+
+	t.Run("current lines changed", func(t *testing.T) {
+		path := writeFixFile(t)
+		fix := fix
+		fix.FilePath = path
+		useEditor(t, `sed -i 's/^-\tf.Close()$/-\tf.Sync()/' "$1"`)
+		if _, err := editFix(fix); err == nil || errors.Is(err, errEmptyPatch) {
+			t.Errorf("editFix() error = %v, want the current lines to be checked", err)
+		}
+	})
+
+	t.Run("emptied", func(t *testing.T) {
+		useEditor(t, `: > "$1"`)
+		if _, err := editFix(fix); !errors.Is(err, errEmptyPatch) {
+			t.Errorf("editFix() error = %v, want errEmptyPatch", err)
+		}
+	})
+}
+
+func TestDecideFixAlternative(t *testing.T) {
+	path := writeFixFile(t)
+	p := &fixProvider{issues: []providers.Issue{
+		{Message: "elsewhere", FixedCode: "// other", Location: &providers.Location{StartLine: 1, EndLine: 1}},
+		{Message: "error from Close ignored", FixedCode: "\treturn f.Close()", Location: &providers.Location{StartLine: 4, EndLine: 5}},
+	}}
+	fix := FixableIssue{FilePath: path, StartLine: 4, EndLine: 5, FixedCode: "\t_ = f.Close()\n\treturn nil"}
+	fix.Issue = providers.Issue{Type: providers.IssueTypeBug, Message: "error from Close ignored", FixedCode: fix.FixedCode}
+
+	reader := bufio.NewReader(strings.NewReader("r\nreturn the error of Close\ny\n"))
+	got, apply, quit := decideFix(context.Background(), fix, false, reader, alternativeFix(p))
+	if !apply || quit || got.FixedCode != "\treturn f.Close()" || got.Issue.FixedCode != got.FixedCode {
+		t.Fatalf("decideFix() = %q, apply %v, quit %v", got.FixedCode, apply, quit)
+	}
+
+	req := p.requests[0]
+	if !strings.HasPrefix(req.Diff, "@@ -1,6 +1,6 @@\n package store\n") || req.Language != "go" {
+		t.Errorf("request diff = %q, language %q", req.Diff, req.Language)
+	}
+	for _, want := range []string{"lines 4-5: error from Close ignored", "\t_ = f.Close()\n\treturn nil", "Instructions from the developer: return the error of Close"} {
+		if !strings.Contains(req.Context, want) {
+			t.Errorf("request context missing %q:\n%s", want, req.Context)
+		}
+	}
+
+	// Without another fix, the current one is asked about again
+	p.issues = []providers.Issue{{FixedCode: got.FixedCode}}
+	reader = bufio.NewReader(strings.NewReader("r\n\nn\n"))
+	if again, apply, _ := decideFix(context.Background(), got, false, reader, alternativeFix(p)); apply || again.FixedCode != got.FixedCode {
+		t.Errorf("decideFix() = %q, apply %v; want the fix kept and skipped", again.FixedCode, apply)
+	}
+}
@@ -0,0 +1,282 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fixCommitMessage is the message of the commit and stash entry holding
+// the fixes
+const fixCommitMessage = "chore: apply goreview fixes"
+
+// fixSnapshot holds the files to fix as they were before any fix, to tell
+// the fixes apart from the changes already in them.
+type fixSnapshot struct {
+	root   string
+	before map[string][]byte // path relative to root -> content
+}
+
+// fixedFile is a file changed by the fixes.
+type fixedFile struct {
+	path          string
+	before, after []byte
+}
+
+// snapshotFixFiles records the files the fixes would change.
+func snapshotFixFiles(ctx context.Context, issues []FixableIssue) (*fixSnapshot, error) {
+	root, err := gitIn(ctx, ".", "", nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("finding the repository: %w", err)
+	}
+	s := &fixSnapshot{root: strings.TrimSpace(root), before: make(map[string][]byte)}
+	if s.root, err = filepath.EvalSymlinks(s.root); err != nil {
+		return nil, err
+	}
+
+	for _, fix := range issues {
+		abs, err := filepath.Abs(fix.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+		rel, err := filepath.Rel(s.root, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("%s is outside the repository", fix.FilePath)
+		}
+		rel = filepath.ToSlash(rel)
+		if _, ok := s.before[rel]; ok {
+			continue
+		}
+		content, err := os.ReadFile(abs) // #nosec G304 - a file under review
+		if err != nil {
+			continue
+		}
+		s.before[rel] = content
+	}
+	return s, nil
+}
+
+// changed returns the files the fixes changed, by path.
+func (s *fixSnapshot) changed() ([]fixedFile, error) {
+	var files []fixedFile
+	for path, before := range s.before {
+		after, err := os.ReadFile(filepath.Join(s.root, filepath.FromSlash(path))) // #nosec G304 - a fixed file
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(before, after) {
+			files = append(files, fixedFile{path: path, before: before, after: after})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// commitFixes commits the fixes alone on top of HEAD, leaving the other
+// changes of the fixed files uncommitted. The fixes stay in the working
+// tree and are added to the index, so the index keeps its other changes.
+func commitFixes(ctx context.Context, s *fixSnapshot) (string, error) {
+	files, err := s.changed()
+	if err != nil || len(files) == 0 {
+		return "", err
+	}
+	head, err := s.git(ctx, "", nil, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return "", errors.New("no commit to add the fixes to")
+	}
+
+	committed := make(map[string][]byte, len(files))
+	staged := make(map[string][]byte, len(files))
+	for _, f := range files {
+		current, err := s.show(ctx, "HEAD:"+f.path)
+		if err != nil {
+			return "", fmt.Errorf("%s is not committed yet", f.path)
+		}
+		if committed[f.path], err = s.mergeFix(ctx, f, current); err != nil {
+			return "", err
+		}
+		if current, err = s.show(ctx, ":"+f.path); err == nil {
+			if staged[f.path], err = s.mergeFix(ctx, f, current); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	tree, err := s.tree(ctx, committed)
+	if err != nil {
+		return "", err
+	}
+	commit, err := s.git(ctx, "", nil, "commit-tree", tree, "-p", head, "-m", fixCommitMessage)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.git(ctx, "", nil, "update-ref", "-m", "goreview fix: "+fixCommitMessage, "HEAD", commit, head); err != nil {
+		return "", err
+	}
+	if err := s.updateIndex(ctx, "", staged); err != nil {
+		return commit, fmt.Errorf("fixes committed as %s, but not staged: %w", commit[:7], err)
+	}
+	return commit, nil
+}
+
+// stashFixes moves the fixes to a stash entry, restoring the fixed files as
+// they were. Once the other changes are committed, "git stash pop" applies
+// the fixes alone on top of them.
+func stashFixes(ctx context.Context, s *fixSnapshot) (int, error) {
+	files, err := s.changed()
+	if err != nil || len(files) == 0 {
+		return 0, err
+	}
+	head, err := s.git(ctx, "", nil, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return 0, errors.New("no commit to stash the fixes on")
+	}
+	branch, err := s.git(ctx, "", nil, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return 0, err
+	}
+
+	// The stash entry's base holds the files before the fixes, so that
+	// popping it applies the fixes alone
+	before := make(map[string][]byte, len(files))
+	after := make(map[string][]byte, len(files))
+	for _, f := range files {
+		before[f.path], after[f.path] = f.before, f.after
+	}
+	baseTree, err := s.tree(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	fixedTree, err := s.tree(ctx, after)
+	if err != nil {
+		return 0, err
+	}
+	message := fmt.Sprintf("On %s: %s", branch, fixCommitMessage)
+	base, err := s.git(ctx, "", nil, "commit-tree", baseTree, "-p", head, "-m", "goreview: before fixes")
+	if err != nil {
+		return 0, err
+	}
+	index, err := s.git(ctx, "", nil, "commit-tree", baseTree, "-p", base, "-m", "index on "+message)
+	if err != nil {
+		return 0, err
+	}
+	stash, err := s.git(ctx, "", nil, "commit-tree", fixedTree, "-p", base, "-p", index, "-m", message)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := s.git(ctx, "", nil, "stash", "store", "-m", message, stash); err != nil {
+		return 0, err
+	}
+
+	for _, f := range files {
+		path := filepath.Join(s.root, filepath.FromSlash(f.path))
+		if err := os.WriteFile(path, f.before, 0600); err != nil {
+			return 0, fmt.Errorf("fixes stashed, but %s not restored: %w", f.path, err)
+		}
+	}
+	return len(files), nil
+}
+
+// mergeFix applies the changes of a fixed file to another version of it.
+func (s *fixSnapshot) mergeFix(ctx context.Context, f fixedFile, other []byte) ([]byte, error) {
+	if bytes.Equal(other, f.before) {
+		return f.after, nil
+	}
+	dir, err := os.MkdirTemp("", "goreview-fix-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	paths := make([]string, 3)
+	for i, content := range [][]byte{other, f.before, f.after} {
+		paths[i] = filepath.Join(dir, fmt.Sprint(i))
+		if err := os.WriteFile(paths[i], content, 0600); err != nil {
+			return nil, err
+		}
+	}
+	merged, err := gitIn(ctx, s.root, "", nil, append([]string{"merge-file", "-p", "-q"}, paths...)...)
+	if err != nil {
+		return nil, fmt.Errorf("the fixes to %s conflict with its uncommitted changes", f.path)
+	}
+	return []byte(merged), nil
+}
+
+// tree writes the tree of HEAD with some files replaced.
+func (s *fixSnapshot) tree(ctx context.Context, files map[string][]byte) (string, error) {
+	dir, err := os.MkdirTemp("", "goreview-index-")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	index := filepath.Join(dir, "index")
+
+	if _, err := s.git(ctx, index, nil, "read-tree", "HEAD"); err != nil {
+		return "", err
+	}
+	if err := s.updateIndex(ctx, index, files); err != nil {
+		return "", err
+	}
+	return s.git(ctx, index, nil, "write-tree")
+}
+
+// updateIndex stores files in an index, the repository's when index is "",
+// keeping their modes.
+func (s *fixSnapshot) updateIndex(ctx context.Context, index string, files map[string][]byte) error {
+	for path, content := range files {
+		blob, err := s.git(ctx, index, content, "hash-object", "-w", "--stdin")
+		if err != nil {
+			return err
+		}
+		mode := "100644"
+		if entry, err := s.git(ctx, index, nil, "ls-files", "-s", "--", path); err == nil && entry != "" {
+			mode, _, _ = strings.Cut(entry, " ")
+		}
+		if _, err := s.git(ctx, index, nil, "update-index", "--add", "--cacheinfo", mode+","+blob+","+path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fixSnapshot) git(ctx context.Context, index string, stdin []byte, args ...string) (string, error) {
+	out, err := gitIn(ctx, s.root, index, stdin, args...)
+	return strings.TrimSpace(out), err
+}
+
+// show returns the content of a file in git, like "HEAD:main.go".
+func (s *fixSnapshot) show(ctx context.Context, object string) ([]byte, error) {
+	out, err := gitIn(ctx, s.root, "", nil, "show", object)
+	return []byte(out), err
+}
+
+// gitIn runs git in dir, on the index file when it is set, and returns its
+// output.
+func gitIn(ctx context.Context, dir, index string, stdin []byte, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - git command with controlled args
+	cmd.Dir = dir
+	if index != "" {
+		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+index)
+	}
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return stdout.String(), fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// fixRepo makes a repository with a committed main.go and chdirs into it.
+func fixRepo(t *testing.T) func(args ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Chdir(t.TempDir())
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return string(out)
+	}
+	git("init", "-q")
+	git("config", "user.name", "Dev")
+	git("config", "user.email", "dev@example.com")
+	writeFile(t, "main.go", "package main\n\nfunc main() {\n\trun()\n}\n\nfunc run() {\n\tpanic(\"todo\")\n}\n")
+	git("add", ".")
+	git("commit", "-qm", "initial")
+	return git
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// changeMain makes a change of the developer at the top of main.go.
+func changeMain(t *testing.T) {
+	t.Helper()
+	writeFile(t, "main.go", "package main\n\n// main runs\nfunc main() {\n\trun()\n}\n\nfunc run() {\n\tpanic(\"todo\")\n}\n")
+}
+
+// fixMain applies a fix of the panic in the changed main.go.
+func fixMain(t *testing.T) *fixSnapshot {
+	t.Helper()
+	fix := FixableIssue{FilePath: "main.go", StartLine: 9, EndLine: 9, FixedCode: "\treturn"}
+	fix.Issue.FixedCode = fix.FixedCode
+	s, err := snapshotFixFiles(context.Background(), []FixableIssue{fix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tryApplyFix(fix, true) {
+		t.Fatal("fix not applied")
+	}
+	return s
+}
+
+func TestCommitFixes(t *testing.T) {
+	git := fixRepo(t)
+	changeMain(t)
+	git("add", "main.go")
+	s := fixMain(t)
+
+	commit, err := commitFixes(context.Background(), s)
+	if err != nil || commit == "" {
+		t.Fatalf("commitFixes() = %q, %v", commit, err)
+	}
+
+	if msg := git("log", "-1", "--format=%s"); strings.TrimSpace(msg) != fixCommitMessage {
+		t.Errorf("commit message = %q", msg)
+	}
+	committed := git("show", "--format=", "HEAD")
+	if !strings.Contains(committed, "-\tpanic(\"todo\")\n+\treturn\n") || strings.Contains(committed, "// main runs") {
+		t.Errorf("fix commit =\n%s", committed)
+	}
+	// The developer's change is still staged, apart from the fix
+	if staged := git("diff", "--cached"); !strings.Contains(staged, "+// main runs") || strings.Contains(staged, "return") {
+		t.Errorf("staged changes =\n%s", staged)
+	}
+	if unstaged := git("diff"); unstaged != "" {
+		t.Errorf("unstaged changes =\n%s", unstaged)
+	}
+}
+
+func TestCommitFixesConflict(t *testing.T) {
+	fixRepo(t)
+	writeFile(t, "main.go", "package main\n\nfunc main() {\n\trun()\n}\n\nfunc run() {\n\tpanic(\"not yet\")\n}\n")
+	fix := FixableIssue{FilePath: "main.go", StartLine: 8, EndLine: 8, FixedCode: "\treturn"}
+	fix.Issue.FixedCode = fix.FixedCode
+	s, err := snapshotFixFiles(context.Background(), []FixableIssue{fix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tryApplyFix(fix, true)
+
+	if _, err := commitFixes(context.Background(), s); err == nil || !strings.Contains(err.Error(), "conflict") {
+		t.Errorf("commitFixes() error = %v, want a conflict", err)
+	}
+}
+
+func TestStashFixes(t *testing.T) {
+	git := fixRepo(t)
+	changeMain(t)
+	s := fixMain(t)
+
+	n, err := stashFixes(context.Background(), s)
+	if err != nil || n != 1 {
+		t.Fatalf("stashFixes() = %d, %v", n, err)
+	}
+
+	if list := git("stash", "list"); !strings.Contains(list, fixCommitMessage) {
+		t.Errorf("stash list = %q", list)
+	}
+	if stashed := git("stash", "show", "-p"); !strings.Contains(stashed, "+\treturn") || strings.Contains(stashed, "// main runs") {
+		t.Errorf("stash =\n%s", stashed)
+	}
+	if diff := git("diff"); !strings.Contains(diff, "+// main runs") || strings.Contains(diff, "return") {
+		t.Errorf("working tree changes =\n%s", diff)
+	}
+
+	// Once the developer's change is committed, the fixes apply on top
+	git("commit", "-qam", "document main")
+	git("stash", "pop", "-q")
+	data, _ := os.ReadFile("main.go")
+	if want := "package main\n\n// main runs\nfunc main() {\n\trun()\n}\n\nfunc run() {\n\treturn\n}\n"; string(data) != want {
+		t.Errorf("main.go after pop =\n%s", data)
+	}
+}
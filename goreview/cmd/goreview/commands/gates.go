@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/JNZader/goreview/goreview/internal/gate"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// applyGates evaluates the CI gates against the result and records their
+// outcomes, which then decide the exit code. Failures are printed unless
+// --quiet; every outcome is printed with --verbose.
+func applyGates(gates []gate.Gate, result *review.Result) {
+	if len(gates) == 0 {
+		return
+	}
+	result.Gates = gate.Evaluate(gates, result)
+	if isQuiet() {
+		return
+	}
+	for _, g := range result.Gates {
+		switch {
+		case g.Error != "":
+			fmt.Fprintf(os.Stderr, "Gate %s: error: %s\n", g.Name, g.Error)
+		case !g.Passed:
+			fmt.Fprintf(os.Stderr, "Gate %s: failed (%s)\n", g.Name, g.Expr)
+		case isVerbose():
+			fmt.Fprintf(os.Stderr, "Gate %s: passed\n", g.Name)
+		}
+	}
+}
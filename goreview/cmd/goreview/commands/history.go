@@ -3,10 +3,14 @@ package commands
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/JNZader/goreview/goreview/internal/config"
 	"github.com/JNZader/goreview/goreview/internal/history"
 )
 
@@ -33,11 +37,38 @@ Examples:
 	RunE: runHistory,
 }
 
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export review history as CSV or Parquet",
+	Long: `Export every stored issue, one row per issue, for analysis in notebooks
+or warehouse tools.
+
+Columns: id, project, commit_hash, branch, author, file_path, line,
+issue_type, severity, message, suggestion, created_at, resolved,
+resolved_at, review_round.
+
+Examples:
+  # CSV to stdout
+  goreview history export --format csv
+
+  # Parquet file for a date range
+  goreview history export --format parquet -o issues.parquet --since 2024-01-01`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryExport,
+}
+
 func init() {
 	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyExportCmd)
 
 	historyCmd.Flags().Bool("detailed", false, "Show detailed issue list")
 	historyCmd.Flags().Int("limit", 20, "Number of issues to show in detailed mode")
+
+	historyExportCmd.Flags().StringP("format", "f", "csv", "Export format (csv, parquet)")
+	historyExportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	historyExportCmd.Flags().String("project", "", "Value of the project column (default: repository name)")
+	historyExportCmd.Flags().String("since", "", "Only issues created on or after date (YYYY-MM-DD)")
+	historyExportCmd.Flags().String("until", "", "Only issues created before date (YYYY-MM-DD)")
 }
 
 func runHistory(cmd *cobra.Command, args []string) error {
@@ -46,7 +77,7 @@ func runHistory(cmd *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
-	cfg, err := config.LoadDefault()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
@@ -83,6 +114,89 @@ func runHistory(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runHistoryExport(cmd *cobra.Command, _ []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if !slices.Contains(history.ExportFormats, format) {
+		return fmt.Errorf("unknown export format: %s (supported: %s)", format, strings.Join(history.ExportFormats, ", "))
+	}
+
+	since, until, err := parseDateRange(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	store, err := history.NewStore(history.StoreConfig{Path: getHistoryDBPath(cfg)})
+	if err != nil {
+		return fmt.Errorf("opening history database: %w", err)
+	}
+	defer store.Close()
+
+	all, err := store.All(context.Background())
+	if err != nil {
+		return fmt.Errorf("reading history: %w", err)
+	}
+	records := all[:0]
+	for _, r := range all {
+		if (!since.IsZero() && r.CreatedAt.Before(since)) || (!until.IsZero() && !r.CreatedAt.Before(until)) {
+			continue
+		}
+		records = append(records, r)
+	}
+
+	project, _ := cmd.Flags().GetString("project")
+	if project == "" {
+		project = historyProjectName()
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		return history.Export(os.Stdout, format, records, project)
+	}
+
+	f, err := os.Create(output) // #nosec G304 - user-provided output path
+	if err != nil {
+		return fmt.Errorf("creating output: %w", err)
+	}
+	if err := history.Export(f, format, records, project); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("exporting history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d issue(s) to %s\n", len(records), output)
+	return nil
+}
+
+// parseDateRange reads the --since and --until flags
+func parseDateRange(cmd *cobra.Command) (since, until time.Time, err error) {
+	if s, _ := cmd.Flags().GetString("since"); s != "" {
+		if since, err = time.Parse(dateFormat, s); err != nil {
+			return since, until, fmt.Errorf("invalid since date: %w", err)
+		}
+	}
+	if s, _ := cmd.Flags().GetString("until"); s != "" {
+		if until, err = time.Parse(dateFormat, s); err != nil {
+			return since, until, fmt.Errorf("invalid until date: %w", err)
+		}
+	}
+	return since, until, nil
+}
+
+// historyProjectName returns the repository name, or the current directory name
+func historyProjectName() string {
+	if root, err := runGitCommand("rev-parse", "--show-toplevel"); err == nil && strings.TrimSpace(root) != "" {
+		return filepath.Base(strings.TrimSpace(root))
+	}
+	cwd, _ := os.Getwd()
+	return filepath.Base(cwd)
+}
+
 func printHistoryHeader(path string) {
 	fmt.Printf("📁 Review History: %s\n", path)
 	fmt.Println(repeatChar('=', 50))
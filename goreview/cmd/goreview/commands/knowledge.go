@@ -0,0 +1,307 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/knowledge"
+)
+
+var knowledgeCmd = &cobra.Command{
+	Use:   "knowledge",
+	Short: "Search team knowledge sources",
+	Long: `Search the knowledge sources configured in .goreview.yaml: Notion,
+Confluence, Obsidian vaults, local docs and GitHub repositories.
+
+  knowledge:
+    enabled: true
+    sources:
+      - name: adr
+        type: local
+        enabled: true
+        local_path: docs/adr
+      - name: wiki
+        type: confluence
+        enabled: true
+        confluence_url: https://acme.atlassian.net/wiki
+        confluence_user: ci@acme.com
+        confluence_token: ${CONFLUENCE_TOKEN}
+        confluence_space: ENG`,
+}
+
+var knowledgeSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search knowledge sources",
+	Long: `Search the enabled knowledge sources and print the matching
+documents, best first, with a snippet and where they came from.
+
+Examples:
+  # Search all sources
+  goreview knowledge search "retry policy"
+
+  # Search only Obsidian notes tagged #architecture
+  goreview knowledge search "event sourcing" --source obsidian --tags architecture`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runKnowledgeSearch,
+}
+
+var knowledgeSourcesCmd = &cobra.Command{
+	Use:   "sources",
+	Short: "Manage knowledge sources",
+}
+
+var knowledgeSourcesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured knowledge sources",
+	RunE:  runKnowledgeSourcesList,
+}
+
+var knowledgeSourcesTestCmd = &cobra.Command{
+	Use:   "test [source...]",
+	Short: "Check that knowledge sources are reachable",
+	Long: `Validate the settings of knowledge sources and fetch their
+documents. Without arguments, all enabled sources are tested; named
+sources are tested even when disabled.
+
+Examples:
+  # Test all enabled sources
+  goreview knowledge sources test
+
+  # Test one source
+  goreview knowledge sources test wiki`,
+	RunE: runKnowledgeSourcesTest,
+}
+
+var (
+	knowledgeSource string
+	knowledgeTags   []string
+	knowledgeLimit  int
+	knowledgeJSON   bool
+)
+
+func init() {
+	rootCmd.AddCommand(knowledgeCmd)
+	knowledgeCmd.AddCommand(knowledgeSearchCmd)
+	knowledgeCmd.AddCommand(knowledgeSourcesCmd)
+	knowledgeSourcesCmd.AddCommand(knowledgeSourcesListCmd)
+	knowledgeSourcesCmd.AddCommand(knowledgeSourcesTestCmd)
+
+	knowledgeSearchCmd.Flags().StringVar(&knowledgeSource, "source", "", "only search sources of this type (notion, confluence, obsidian, local, github)")
+	knowledgeSearchCmd.Flags().StringSliceVar(&knowledgeTags, "tags", nil, "only show documents with one of these tags")
+	knowledgeSearchCmd.Flags().IntVar(&knowledgeLimit, "limit", 10, "maximum number of results")
+	knowledgeCmd.PersistentFlags().BoolVar(&knowledgeJSON, "json", false, "output as JSON")
+}
+
+func runKnowledgeSearch(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if !cfg.Knowledge.Enabled {
+		return fmt.Errorf("knowledge sources are disabled, set knowledge.enabled in the config")
+	}
+
+	kcfg := knowledge.ConfigFrom(cfg.Knowledge)
+	if knowledgeSource != "" {
+		if !validKnowledgeSourceType(knowledgeSource) {
+			return fmt.Errorf("invalid source %q, must be one of: notion, confluence, obsidian, local, github", knowledgeSource)
+		}
+		kcfg.Sources = sourcesOfType(kcfg.Sources, knowledge.SourceType(knowledgeSource))
+		if len(kcfg.Sources) == 0 {
+			return fmt.Errorf("no enabled %s sources configured", knowledgeSource)
+		}
+	}
+	fetcher, err := knowledge.NewFetcher(kcfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	results, err := fetcher.Search(ctx, knowledge.SearchQuery{
+		Text:       strings.Join(args, " "),
+		Tags:       knowledgeTags,
+		SourceType: knowledge.SourceType(knowledgeSource),
+		Limit:      knowledgeLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	if knowledgeJSON {
+		return printJSON(results)
+	}
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		return nil
+	}
+	for i, r := range results {
+		printKnowledgeResult(i+1, r)
+	}
+	return nil
+}
+
+func validKnowledgeSourceType(t string) bool {
+	switch knowledge.SourceType(t) {
+	case knowledge.SourceTypeNotion, knowledge.SourceTypeConfluence, knowledge.SourceTypeObsidian, knowledge.SourceTypeLocal, knowledge.SourceTypeGitHub:
+		return true
+	}
+	return false
+}
+
+// sourcesOfType returns the enabled sources of type t.
+func sourcesOfType(sources []knowledge.Source, t knowledge.SourceType) []knowledge.Source {
+	var selected []knowledge.Source
+	for _, s := range sources {
+		if s.Enabled && s.Type == t {
+			selected = append(selected, s)
+		}
+	}
+	return selected
+}
+
+func printKnowledgeResult(rank int, r knowledge.SearchResult) {
+	doc := r.Document
+	fmt.Printf("%d. %s (%.2f)\n", rank, doc.Title, r.Score)
+	fmt.Printf("   %s: %s\n", doc.Source, knowledgeDocLocation(doc))
+	if len(doc.Tags) > 0 {
+		fmt.Printf("   Tags: %s\n", strings.Join(doc.Tags, ", "))
+	}
+	snippet := strings.Join(strings.Fields(r.Snippet), " ")
+	fmt.Printf("   %s\n\n", snippet)
+}
+
+// knowledgeDocLocation tells where a document can be found: its URL, or
+// its path within the source.
+func knowledgeDocLocation(doc knowledge.Document) string {
+	location := doc.URL
+	if location == "" {
+		location = doc.Metadata["path"]
+	}
+	if doc.SourceName == "" {
+		return location
+	}
+	if location == "" {
+		return doc.SourceName
+	}
+	return doc.SourceName + " " + location
+}
+
+func runKnowledgeSourcesList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	sources := knowledge.ConfigFrom(cfg.Knowledge).Sources
+	if knowledgeJSON {
+		return printJSON(knowledgeSourceInfos(sources))
+	}
+	if len(sources) == 0 {
+		fmt.Println("No knowledge sources configured (knowledge.sources).")
+		return nil
+	}
+	if !cfg.Knowledge.Enabled {
+		fmt.Println("Knowledge sources are disabled (knowledge.enabled).")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tENABLED\tLOCATION")
+	for _, s := range sources {
+		fmt.Fprintf(w, "%s\t%s\t%v\t%s\n", s.Name, s.Type, s.Enabled, s.Location())
+	}
+	return w.Flush()
+}
+
+// knowledgeSourceInfo is a source as listed in JSON, without credentials.
+type knowledgeSourceInfo struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Enabled  bool   `json:"enabled"`
+	Location string `json:"location"`
+}
+
+func knowledgeSourceInfos(sources []knowledge.Source) []knowledgeSourceInfo {
+	infos := make([]knowledgeSourceInfo, 0, len(sources))
+	for _, s := range sources {
+		infos = append(infos, knowledgeSourceInfo{Name: s.Name, Type: string(s.Type), Enabled: s.Enabled, Location: s.Location()})
+	}
+	return infos
+}
+
+func runKnowledgeSourcesTest(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	kcfg := knowledge.ConfigFrom(cfg.Knowledge)
+	selected, err := selectKnowledgeSources(kcfg.Sources, args)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		fmt.Println("No enabled knowledge sources to test.")
+		return nil
+	}
+
+	fetcher, err := knowledge.NewFetcher(kcfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var failed int
+	for _, s := range selected {
+		count, err := fetcher.Test(ctx, s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %s (%s): %v\n", s.Name, s.Type, err)
+			failed++
+			continue
+		}
+		if !isQuiet() {
+			fmt.Printf("✓ %s (%s): %d documents\n", s.Name, s.Type, count)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d knowledge sources failed", failed, len(selected))
+	}
+	return nil
+}
+
+// selectKnowledgeSources returns the sources named in names, or all enabled
+// sources when there are no names.
+func selectKnowledgeSources(sources []knowledge.Source, names []string) ([]knowledge.Source, error) {
+	if len(names) == 0 {
+		var enabled []knowledge.Source
+		for _, s := range sources {
+			if s.Enabled {
+				enabled = append(enabled, s)
+			}
+		}
+		return enabled, nil
+	}
+
+	byName := make(map[string]knowledge.Source, len(sources))
+	for _, s := range sources {
+		byName[s.Name] = s
+	}
+	selected := make([]knowledge.Source, 0, len(names))
+	for _, name := range names {
+		s, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("knowledge source not found: %s", name)
+		}
+		selected = append(selected, s)
+	}
+	return selected, nil
+}
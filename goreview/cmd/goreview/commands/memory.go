@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/memory"
+)
+
+var memoryCmd = &cobra.Command{
+	Use:   "memory",
+	Short: "Recall and maintain the memory of past sessions",
+	Long: `Recall and maintain the memory kept in memory.dir.
+
+Chat sessions are saved to session memory linked to the branch and commits
+they were about, so what was learned while working on a branch can be
+recalled later.`,
+}
+
+var memoryRecallCmd = &cobra.Command{
+	Use:   "recall [query]",
+	Short: "Show the sessions linked to a branch or commit",
+	Long: `Show the saved sessions linked to a branch or commit, newest first, with
+their messages. Without --branch or --commit, the sessions of the current
+branch are shown. A query keeps only the messages containing it.
+
+Only the last memory.session.max_sessions sessions are kept.
+
+Examples:
+  goreview memory recall --branch feature/x
+  goreview memory recall --commit a1b2c3d
+  goreview memory recall "retry" --branch feature/x --json`,
+	RunE: runMemoryRecall,
+}
+
+var memoryMaintainCmd = &cobra.Command{
+	Use:   "maintain",
+	Short: "Consolidate memory and run its maintenance",
+	Long: `Consolidate the important entries of working memory into long-term
+memory, clean expired entries, decay associations and garbage collect
+long-term memory. goreview serve does it every memory.maintenance_interval;
+this runs it once, e.g. from cron. Needs memory.enabled.`,
+	Args: cobra.NoArgs,
+	RunE: runMemoryMaintain,
+}
+
+func init() {
+	rootCmd.AddCommand(memoryCmd)
+	memoryCmd.AddCommand(memoryRecallCmd, memoryMaintainCmd)
+
+	memoryRecallCmd.Flags().String("branch", "", "Show the sessions linked to this branch")
+	memoryRecallCmd.Flags().String("commit", "", "Show the sessions linked to this commit (or a prefix of it)")
+	memoryRecallCmd.Flags().Int("limit", 10, "Maximum number of sessions")
+	memoryRecallCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func runMemoryRecall(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	branch, _ := cmd.Flags().GetString("branch")
+	commit, _ := cmd.Flags().GetString("commit")
+	limit, _ := cmd.Flags().GetInt("limit")
+	if branch == "" && commit == "" {
+		repo, err := git.NewRepo(".")
+		if err != nil {
+			return fmt.Errorf("initializing git: %w", err)
+		}
+		if branch, err = repo.GetCurrentBranch(context.Background()); err != nil || branch == "HEAD" {
+			return fmt.Errorf("no current branch, use --branch or --commit")
+		}
+	}
+
+	mem, err := memory.NewSessionMemory(filepath.Join(cfg.Memory.Dir, "sessions"),
+		cfg.Memory.Session.MaxSessions, cfg.Memory.Session.SessionTTL)
+	if err != nil {
+		return err
+	}
+	sessions, err := mem.LinkedSessions(context.Background(), branch, commit)
+	if err != nil {
+		return err
+	}
+	sessions = filterRecall(sessions, strings.Join(args, " "))
+	if limit > 0 && len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sessions)
+	}
+	printRecall(os.Stdout, sessions)
+	return nil
+}
+
+// filterRecall keeps the entries containing query, and the sessions with
+// any left.
+func filterRecall(sessions []memory.LinkedSession, query string) []memory.LinkedSession {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return sessions
+	}
+	kept := sessions[:0]
+	for _, s := range sessions {
+		var entries []memory.Entry
+		for _, e := range s.Entries {
+			if strings.Contains(strings.ToLower(e.Content), query) {
+				entries = append(entries, e)
+			}
+		}
+		if len(entries) > 0 {
+			s.Entries = entries
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// printRecall prints the sessions with a line per entry.
+func printRecall(w io.Writer, sessions []memory.LinkedSession) {
+	if len(sessions) == 0 {
+		_, _ = fmt.Fprintln(w, "No linked sessions found.")
+		return
+	}
+	for _, s := range sessions {
+		commits := make([]string, len(s.Commits))
+		for i, c := range s.Commits {
+			commits[i] = shortSHA(c)
+		}
+		_, _ = fmt.Fprintf(w, "Session %s  %s  %s", s.ID, s.SavedAt.Format(dateTimeFormat), s.Branch)
+		if len(commits) > 0 {
+			_, _ = fmt.Fprintf(w, " (%s)", strings.Join(commits, ", "))
+		}
+		_, _ = fmt.Fprintln(w)
+		for _, e := range s.Entries {
+			label := e.Type
+			if role, ok := e.Metadata["role"].(string); ok && role != "" {
+				label = role
+			}
+			_, _ = fmt.Fprintf(w, "  %s: %s\n", label, truncate(strings.Join(strings.Fields(e.Content), " "), 160))
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+func runMemoryMaintain(_ *cobra.Command, _ []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if !cfg.Memory.Enabled {
+		return fmt.Errorf("memory is disabled (memory.enabled)")
+	}
+	store, err := memory.NewStore(cfg.Memory)
+	if err != nil {
+		return fmt.Errorf("opening memory: %w", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	ctx := context.Background()
+	if err := store.Maintain(ctx); err != nil {
+		return fmt.Errorf("maintaining memory: %w", err)
+	}
+	stats, err := store.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Memory maintained: %d long-term entries, %d associations\n", stats.LongTermEntries, stats.Associations)
+	return nil
+}
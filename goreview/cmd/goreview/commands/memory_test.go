@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/memory"
+)
+
+func TestPrintRecall(t *testing.T) {
+	sessions := []memory.LinkedSession{
+		{ID: "s2", Branch: "feature/x", Commits: []string{"abc1234567"}, SavedAt: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), Entries: []memory.Entry{
+			{Type: "chat", Content: "why the retry\nloop?", Metadata: map[string]interface{}{"role": "user"}},
+			{Type: "chat", Content: "the cache is cold", Metadata: map[string]interface{}{"role": "assistant"}},
+		}},
+		{ID: "s1", Branch: "feature/x", Entries: []memory.Entry{{Type: "note", Content: "nothing about it"}}},
+	}
+
+	sessions = filterRecall(sessions, "RETRY")
+	if len(sessions) != 1 || len(sessions[0].Entries) != 1 {
+		t.Fatalf("filterRecall() = %+v, want the retry message only", sessions)
+	}
+	var buf bytes.Buffer
+	printRecall(&buf, sessions)
+	for _, want := range []string{"Session s2  2026-03-02 10:00  feature/x (abc1234)", "  user: why the retry loop?"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("printRecall() = %q, want %q", buf.String(), want)
+		}
+	}
+
+	buf.Reset()
+	printRecall(&buf, nil)
+	if !strings.Contains(buf.String(), "No linked sessions") {
+		t.Errorf("printRecall(nil) = %q", buf.String())
+	}
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/failure"
+	"github.com/JNZader/goreview/goreview/internal/gate"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+var mergeResultsCmd = &cobra.Command{
+	Use:   "merge-results <report.json>...",
+	Short: "Merge the JSON reports of sharded reviews",
+	Long: `Merge the JSON reports of a review split across CI jobs with --shard
+into a single report, as if one job had reviewed every file.
+
+The shards must come from the same diff and all be given, each once. The
+merged result then goes through what a shard run leaves out: the gates and
+review.fail_on, the history database (triage and debt, when enabled) and
+the exporters.
+
+Reports of unsharded reviews can be merged too; a file in several of them
+keeps its last review.
+
+Examples:
+  # In each of 4 CI jobs
+  goreview review --branch main --shard 2/4 --format json -o shard2.json
+
+  # In a final job, once all shards are done
+  goreview merge-results shard*.json -o merged.json
+  goreview merge-results shard*.json -f markdown -o review.md --export slack`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMergeResults,
+}
+
+func init() {
+	rootCmd.AddCommand(mergeResultsCmd)
+
+	mergeResultsCmd.Flags().StringP("format", "f", "json", "Output format (markdown, json, sarif, pdf, codeactions, dot, mermaid)")
+	mergeResultsCmd.Flags().StringP("output", "o", "", "Write the merged report to file, or upload it to an s3:// or gs:// URL")
+	mergeResultsCmd.Flags().StringSlice("export", nil, "Exporters to run in addition to those enabled in config")
+}
+
+func runMergeResults(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	useReportFlags(cmd, cfg)
+	gates, err := gate.CompileAll(cfg.Gates)
+	if err != nil {
+		return fmt.Errorf("compiling gates: %w", err)
+	}
+
+	results := make([]*review.Result, 0, len(args))
+	for _, path := range args {
+		data, err := os.ReadFile(path) //nolint:gosec // Path given by the user
+		if err != nil {
+			return fmt.Errorf("reading report: %w", err)
+		}
+		result, err := reviewtypes.Decode(bytes.NewReader(data))
+		if err != nil {
+			return &failure.ParseError{Code: failure.ParseReport, File: path, Err: fmt.Errorf("decoding report %s: %w", path, err)}
+		}
+		results = append(results, review.FromPublic(result))
+	}
+	result, err := review.Merge(results...)
+	if err != nil {
+		return err
+	}
+	// Themes span shards, so they are found again over the merged issues
+	review.AssignThemes(result, cfg.Review.Themes)
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Merged %d reports: %d files, %d issues\n", len(results), len(result.Files), result.TotalIssues)
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	recordDebt(ctx, cfg, result)
+	recordTriage(ctx, cfg, result)
+	applyGates(gates, result)
+	if err := outputReport(ctx, cfg, result); err != nil {
+		return err
+	}
+	runExports(ctx, cmd, cfg, result)
+	checkFailThreshold(result, cfg.Review.FailOn)
+	return nil
+}
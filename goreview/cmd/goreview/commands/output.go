@@ -39,8 +39,14 @@ func DetectFormatFromPath(path string) string {
 		return "json"
 	case ".sarif":
 		return "sarif"
+	case ".pdf":
+		return "pdf"
 	case ".md", ".markdown":
 		return "markdown"
+	case ".dot", ".gv":
+		return "dot"
+	case ".mmd":
+		return "mermaid"
 	default:
 		return ""
 	}
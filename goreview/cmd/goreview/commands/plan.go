@@ -6,12 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/JNZader/goreview/goreview/internal/config"
 	"github.com/JNZader/goreview/goreview/internal/providers"
 )
 
@@ -41,7 +41,11 @@ Examples:
   goreview plan ./docs/RFC-001.md ./docs/RFC-002.md
 
   # Output as JSON
-  goreview plan ./docs/design.md --format json`,
+  goreview plan ./docs/design.md --format json
+
+  # Generate a checklist and track its progress
+  goreview plan ./docs/design.md --checklist
+  goreview plan status ./docs/design.md`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runPlan,
 }
@@ -52,6 +56,9 @@ var (
 	planOutput    string
 	planVerbose   bool
 	planChecklist bool
+
+	// planPersonality is the reviewer personality, from config or --personality
+	planPersonality string
 )
 
 func init() {
@@ -62,6 +69,9 @@ func init() {
 	planCmd.Flags().StringVarP(&planOutput, "output", "o", "", "Write review to file")
 	planCmd.Flags().BoolVarP(&planVerbose, "verbose", "V", false, "Include detailed analysis")
 	planCmd.Flags().BoolVar(&planChecklist, "checklist", false, "Generate implementation checklist")
+	addProviderFlags(planCmd)
+	addPersonalityFlag(planCmd)
+	addTimeoutFlag(planCmd, 5*time.Minute)
 }
 
 // PlanReview represents the review of a design document.
@@ -105,12 +115,16 @@ type ChecklistItem struct {
 }
 
 func runPlan(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadDefault()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
+	if err = applyProviderFlags(cmd, cfg); err != nil {
+		return err
+	}
+	planPersonality = cfg.Review.Personality
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := commandContext(cmd)
 	defer cancel()
 
 	provider, err := providers.NewProvider(cfg)
@@ -137,6 +151,10 @@ func runPlan(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no documents could be reviewed")
 	}
 
+	if planChecklist {
+		savePlanChecklists(reviews)
+	}
+
 	output, err := formatPlanOutput(reviews)
 	if err != nil {
 		return err
@@ -154,6 +172,24 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// savePlanChecklists saves the generated checklists for 'goreview plan status'.
+// Saving is best effort: the review output is still printed on failure.
+func savePlanChecklists(reviews []*PlanReview) {
+	dir, err := planStateDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: checklist not saved: %v\n", err)
+		return
+	}
+	for _, review := range reviews {
+		if len(review.Checklist) == 0 {
+			continue
+		}
+		if err := savePlanState(dir, newPlanState(review)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: checklist not saved for %s: %v\n", review.Document, err)
+		}
+	}
+}
+
 func reviewDocument(ctx context.Context, provider providers.Provider, docPath string) (*PlanReview, error) {
 	cleanPath := filepath.Clean(docPath)
 	content, err := os.ReadFile(cleanPath) // #nosec G304 - path from CLI args
@@ -162,21 +198,23 @@ func reviewDocument(ctx context.Context, provider providers.Provider, docPath st
 	}
 
 	prompt := buildPlanPrompt(string(content), docPath)
+	schema := planReviewSchema(planChecklist)
 
-	// Use GenerateDocumentation as it handles free-form text generation
-	response, err := provider.GenerateDocumentation(ctx, string(content), prompt)
+	// Native JSON mode where the provider supports it
+	response, err := providers.GenerateJSON(ctx, provider, prompt, schema)
 	if err != nil {
 		return nil, fmt.Errorf("getting AI response: %w", err)
 	}
 
-	review, err := parsePlanResponse(response)
-	if err != nil {
-		// Fallback to basic review structure
-		review = &PlanReview{
-			Summary: response,
-			Score: PlanScore{
-				Overall: 70,
-			},
+	review, parseErr := parsePlanResponse(response)
+	if parseErr != nil {
+		// Repair pass: send the invalid output back with the validation error
+		repaired, err := providers.GenerateJSON(ctx, provider, buildPlanRepairPrompt(response, parseErr), schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plan review (%v), repair request failed: %w", parseErr, err)
+		}
+		if review, err = parsePlanResponse(repaired); err != nil {
+			return nil, fmt.Errorf("invalid plan review after repair: %w", err)
 		}
 	}
 
@@ -186,12 +224,86 @@ func reviewDocument(ctx context.Context, provider providers.Provider, docPath st
 	return review, nil
 }
 
+// planReviewSchema returns the JSON schema of the review expected from the provider
+func planReviewSchema(withChecklist bool) providers.JSONSchema {
+	score := map[string]interface{}{"type": "number", "minimum": 0, "maximum": 100}
+	stringList := map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+
+	properties := map[string]interface{}{
+		"summary": map[string]interface{}{"type": "string"},
+		"score": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"overall": score, "completeness": score, "clarity": score, "feasibility": score,
+				"security": score, "performance": score, "scalability": score,
+			},
+			"required": []string{"overall", "completeness", "clarity", "feasibility"},
+		},
+		"strengths": stringList,
+		"concerns": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"category":    map[string]interface{}{"type": "string"},
+					"severity":    map[string]interface{}{"type": "string", "enum": planSeverities},
+					"description": map[string]interface{}{"type": "string"},
+					"suggestion":  map[string]interface{}{"type": "string"},
+					"section":     map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"category", "severity", "description"},
+			},
+		},
+		"suggestions": stringList,
+	}
+	required := []string{"summary", "score", "strengths", "concerns", "suggestions"}
+
+	if withChecklist {
+		properties["checklist"] = map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task":     map[string]interface{}{"type": "string"},
+					"priority": map[string]interface{}{"type": "string", "enum": planPriorities},
+					"category": map[string]interface{}{"type": "string"},
+					"depends":  stringList,
+				},
+				"required": []string{"task", "priority", "category"},
+			},
+		}
+		required = append(required, "checklist")
+	}
+
+	return providers.JSONSchema{"type": "object", "properties": properties, "required": required}
+}
+
+// buildPlanRepairPrompt asks the provider to fix a response that failed validation
+func buildPlanRepairPrompt(response string, parseErr error) string {
+	return fmt.Sprintf(`Your previous answer was not a valid design review: %v
+
+Previous answer:
+---
+%s
+---
+
+Return the corrected review as a single JSON object with the fields summary, score (overall, completeness, clarity, feasibility, and optionally security, performance, scalability, all 0-100), strengths, concerns (category, severity: critical|high|medium|low, description, suggestion, section) and suggestions%s. Keep the original assessment. No other text.`,
+		parseErr, response, getChecklistSummary())
+}
+
+func getChecklistSummary() string {
+	if !planChecklist {
+		return ""
+	}
+	return ", plus checklist (task, priority: high|medium|low, category, depends)"
+}
+
 func buildPlanPrompt(content, docPath string) string {
 	docType := detectDocumentType(docPath)
 	focusInstructions := getFocusInstructions()
 
 	prompt := fmt.Sprintf(`You are a senior software architect reviewing a %s document.
-
+%s
 Document path: %s
 
 %s
@@ -239,7 +351,7 @@ Document content:
 %s
 ---
 
-Provide your review as valid JSON only. No other text.`, docType, docPath, focusInstructions, getChecklistInstruction(), content)
+Provide your review as valid JSON only. No other text.`, docType, getPersonalityInstructions(), docPath, focusInstructions, getChecklistInstruction(), content)
 
 	return prompt
 }
@@ -274,6 +386,14 @@ func detectDocumentType(path string) string {
 	}
 }
 
+// getPersonalityInstructions returns the review style for a non-default personality
+func getPersonalityInstructions() string {
+	if planPersonality == "" || planPersonality == string(providers.PersonalityDefault) {
+		return ""
+	}
+	return "\n" + providers.GetPersonalityPrompt(planPersonality) + "\n"
+}
+
 func getFocusInstructions() string {
 	if planFocus == "" || planFocus == "all" {
 		return "Review all aspects of the design comprehensively."
@@ -307,8 +427,15 @@ func getChecklistInstruction() string {
   ]`
 }
 
+// Accepted values for concern severities and checklist priorities
+var (
+	planSeverities = []string{"critical", "high", "medium", "low"}
+	planPriorities = []string{"high", "medium", "low"}
+)
+
 func parsePlanResponse(response string) (*PlanReview, error) {
-	// Try to extract JSON from the response
+	// Native JSON mode returns a bare object; other providers may wrap it in
+	// text or code fences
 	response = strings.TrimSpace(response)
 
 	// Find JSON object in response
@@ -325,9 +452,62 @@ func parsePlanResponse(response string) (*PlanReview, error) {
 		return nil, fmt.Errorf("parsing JSON: %w", err)
 	}
 
+	// A missing score would otherwise decode as 0
+	var presence struct {
+		Score *struct {
+			Overall *float64 `json:"overall"`
+		} `json:"score"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &presence); err != nil || presence.Score == nil || presence.Score.Overall == nil {
+		return nil, fmt.Errorf("missing score.overall")
+	}
+
+	if err := validatePlanReview(&review); err != nil {
+		return nil, err
+	}
 	return &review, nil
 }
 
+// validatePlanReview checks the fields the output formats rely on
+func validatePlanReview(review *PlanReview) error {
+	if strings.TrimSpace(review.Summary) == "" {
+		return fmt.Errorf("missing summary")
+	}
+
+	scores := map[string]float64{
+		"overall": review.Score.Overall, "completeness": review.Score.Completeness,
+		"clarity": review.Score.Clarity, "feasibility": review.Score.Feasibility,
+		"security": review.Score.Security, "performance": review.Score.Performance,
+		"scalability": review.Score.Scalability,
+	}
+	for name, v := range scores {
+		if v < 0 || v > 100 {
+			return fmt.Errorf("score.%s out of range 0-100: %v", name, v)
+		}
+	}
+
+	for i, c := range review.Concerns {
+		if strings.TrimSpace(c.Description) == "" {
+			return fmt.Errorf("concerns[%d]: missing description", i)
+		}
+		if !slices.Contains(planSeverities, strings.ToLower(c.Severity)) {
+			return fmt.Errorf("concerns[%d]: invalid severity %q (want one of %s)", i, c.Severity, strings.Join(planSeverities, ", "))
+		}
+		review.Concerns[i].Severity = strings.ToLower(c.Severity)
+	}
+
+	for i, item := range review.Checklist {
+		if strings.TrimSpace(item.Task) == "" {
+			return fmt.Errorf("checklist[%d]: missing task", i)
+		}
+		if !slices.Contains(planPriorities, strings.ToLower(item.Priority)) {
+			return fmt.Errorf("checklist[%d]: invalid priority %q (want one of %s)", i, item.Priority, strings.Join(planPriorities, ", "))
+		}
+		review.Checklist[i].Priority = strings.ToLower(item.Priority)
+	}
+	return nil
+}
+
 func formatPlanOutput(reviews []*PlanReview) (string, error) {
 	switch planFormat {
 	case "json":
@@ -0,0 +1,365 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+var planStatusCmd = &cobra.Command{
+	Use:   "status <document>",
+	Short: "Show implementation progress of a plan checklist",
+	Long: `Show how much of a plan's implementation checklist is done.
+
+The checklist is saved by 'goreview plan --checklist' under
+.git/goreview/plans/. Each item is checked against the repository:
+- Files and functions mentioned in the task exist
+- No TODO/FIXME markers still reference them
+- Commits that mention the item ID (e.g. "design-3") mark it done
+
+Examples:
+  # Generate and save the checklist
+  goreview plan ./docs/design.md --checklist
+
+  # Check progress
+  goreview plan status ./docs/design.md
+
+  # Output as JSON
+  goreview plan status ./docs/design.md --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPlanStatus,
+}
+
+var planStatusFormat string
+
+func init() {
+	planCmd.AddCommand(planStatusCmd)
+
+	planStatusCmd.Flags().StringVarP(&planStatusFormat, "format", "f", "text", "Output format: text, json")
+}
+
+// Checklist item statuses
+const (
+	itemPending    = "pending"
+	itemInProgress = "in_progress"
+	itemDone       = "done"
+)
+
+// errNoPlanState is returned when a document has no saved checklist.
+var errNoPlanState = errors.New("no saved checklist for document")
+
+// PlanState is the saved checklist of a plan and its last known progress.
+type PlanState struct {
+	Document   string        `json:"document"`
+	CreatedAt  time.Time     `json:"created_at"`
+	CheckedAt  time.Time     `json:"checked_at,omitempty"`
+	Completion float64       `json:"completion"`
+	Items      []TrackedItem `json:"items"`
+}
+
+// TrackedItem is a checklist item with its progress evidence.
+type TrackedItem struct {
+	ID string `json:"id"`
+	ChecklistItem
+	Status   string   `json:"status"`
+	Evidence []string `json:"evidence,omitempty"`
+}
+
+// planRepo answers the questions asked about the repository when
+// evaluating checklist items.
+type planRepo interface {
+	FileExists(path string) bool
+	HasSymbol(name string) bool
+	TodosMentioning(ref string) []string
+	CommitsMentioning(id string) []string
+}
+
+func runPlanStatus(cmd *cobra.Command, args []string) error {
+	dir, err := planStateDir()
+	if err != nil {
+		return err
+	}
+
+	state, err := loadPlanState(dir, args[0])
+	if err != nil {
+		if errors.Is(err, errNoPlanState) {
+			return fmt.Errorf("%w: run 'goreview plan %s --checklist' first", err, args[0])
+		}
+		return err
+	}
+
+	evaluatePlan(state, gitPlanRepo{})
+	if err := savePlanState(dir, state); err != nil {
+		return err
+	}
+
+	if planStatusFormat == "json" {
+		data, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Print(formatPlanStatus(state))
+	return nil
+}
+
+// newPlanState creates the tracked state for a reviewed document's checklist.
+func newPlanState(review *PlanReview) *PlanState {
+	state := &PlanState{Document: filepath.ToSlash(filepath.Clean(review.Document)), CreatedAt: review.ReviewedAt}
+	prefix := planSlug(review.Document)
+	for i, item := range review.Checklist {
+		state.Items = append(state.Items, TrackedItem{
+			ID:            fmt.Sprintf("%s-%d", prefix, i+1),
+			ChecklistItem: item,
+			Status:        itemPending,
+		})
+	}
+	return state
+}
+
+// evaluatePlan updates each item's status from the repository and computes
+// the completion percentage. In-progress items count as half done.
+func evaluatePlan(state *PlanState, repo planRepo) {
+	var progress float64
+	for i := range state.Items {
+		item := &state.Items[i]
+		item.Status, item.Evidence = evaluateItem(item, repo)
+		switch item.Status {
+		case itemDone:
+			progress++
+		case itemInProgress:
+			progress += 0.5
+		}
+	}
+
+	state.CheckedAt = time.Now()
+	state.Completion = 0
+	if len(state.Items) > 0 {
+		state.Completion = progress / float64(len(state.Items)) * 100
+	}
+}
+
+func evaluateItem(item *TrackedItem, repo planRepo) (string, []string) {
+	if commits := repo.CommitsMentioning(item.ID); len(commits) > 0 {
+		return itemDone, []string{fmt.Sprintf("commit %s", strings.Join(commits, ", "))}
+	}
+
+	files, symbols := taskReferences(item.Task)
+	if len(files)+len(symbols) == 0 {
+		return itemPending, nil
+	}
+
+	var evidence []string
+	found := 0
+	for _, f := range files {
+		if repo.FileExists(f) {
+			found++
+			evidence = append(evidence, "file "+f)
+		}
+	}
+	for _, s := range symbols {
+		if repo.HasSymbol(s) {
+			found++
+			evidence = append(evidence, "symbol "+s)
+		}
+	}
+
+	todos := 0
+	for _, ref := range append(files, symbols...) {
+		for _, todo := range repo.TodosMentioning(ref) {
+			todos++
+			evidence = append(evidence, "todo "+todo)
+		}
+	}
+
+	switch {
+	case found == len(files)+len(symbols) && todos == 0:
+		return itemDone, evidence
+	case found > 0 || todos > 0:
+		return itemInProgress, evidence
+	default:
+		return itemPending, evidence
+	}
+}
+
+var (
+	backtickRef = regexp.MustCompile("`([^`]+)`")
+	pathRef     = regexp.MustCompile(`[\w.-]+(?:/[\w.-]+)+`)
+	funcRef     = regexp.MustCompile(`\b([A-Za-z_][\w.]*)\(\)`)
+)
+
+// taskReferences extracts the file paths and symbols mentioned in a task.
+// Symbols are identifiers written as calls (Foo()) or quoted in backticks;
+// paths are quoted names with a known extension, or any slash-separated path.
+func taskReferences(task string) (files, symbols []string) {
+	seen := make(map[string]bool)
+	add := func(list *[]string, ref string) {
+		if seen[ref] {
+			return
+		}
+		seen[ref] = true
+		*list = append(*list, ref)
+	}
+
+	for _, m := range funcRef.FindAllStringSubmatch(task, -1) {
+		add(&symbols, lastSegment(m[1]))
+	}
+	for _, m := range backtickRef.FindAllStringSubmatch(task, -1) {
+		ref := strings.TrimSuffix(strings.TrimSpace(m[1]), "()")
+		switch {
+		case ref == "" || strings.ContainsAny(ref, " \t"):
+			continue
+		case looksLikePath(ref):
+			add(&files, ref)
+		default:
+			add(&symbols, lastSegment(ref))
+		}
+	}
+	for _, ref := range pathRef.FindAllString(backtickRef.ReplaceAllString(task, ""), -1) {
+		// Unquoted, "read/write" is not a path but "internal/api/server.go" is
+		if git.DetectLanguage(ref, "") != "unknown" {
+			add(&files, ref)
+		}
+	}
+	return files, symbols
+}
+
+func looksLikePath(ref string) bool {
+	return strings.Contains(ref, "/") || git.DetectLanguage(ref, "") != "unknown"
+}
+
+// lastSegment returns the name after the last dot of a qualified identifier.
+func lastSegment(ref string) string {
+	if i := strings.LastIndex(ref, "."); i >= 0 && i < len(ref)-1 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+func formatPlanStatus(state *PlanState) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Plan: %s\n", state.Document))
+	sb.WriteString(fmt.Sprintf("Completion: %.0f%% (%d items)\n\n", state.Completion, len(state.Items)))
+
+	for _, item := range state.Items {
+		sb.WriteString(fmt.Sprintf("%s %s %s %s\n", statusMark(item.Status), item.ID, getPriorityEmoji(item.Priority), item.Task))
+		for _, e := range item.Evidence {
+			sb.WriteString(fmt.Sprintf("      %s\n", e))
+		}
+	}
+	return sb.String()
+}
+
+func statusMark(status string) string {
+	switch status {
+	case itemDone:
+		return "[x]"
+	case itemInProgress:
+		return "[~]"
+	default:
+		return "[ ]"
+	}
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// planSlug derives the state file name and item ID prefix from a document path.
+func planSlug(docPath string) string {
+	base := strings.TrimSuffix(filepath.Base(docPath), filepath.Ext(docPath))
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(base), "-"), "-")
+	if slug == "" {
+		return "plan"
+	}
+	return slug
+}
+
+// planStateDir returns .git/goreview/plans of the current repository.
+func planStateDir() (string, error) {
+	out, err := runGitCommand("rev-parse", "--git-dir")
+	if err != nil {
+		return "", fmt.Errorf("not a git repository")
+	}
+	return filepath.Join(strings.TrimSpace(out), "goreview", "plans"), nil
+}
+
+func planStateFile(dir, docPath string) string {
+	return filepath.Join(dir, planSlug(docPath)+".json")
+}
+
+func savePlanState(dir string, state *PlanState) error {
+	if err := os.MkdirAll(dir, 0750); err != nil { // #nosec G301
+		return fmt.Errorf("creating plans directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling plan state: %w", err)
+	}
+	if err := os.WriteFile(planStateFile(dir, state.Document), data, 0600); err != nil {
+		return fmt.Errorf("writing plan state: %w", err)
+	}
+	return nil
+}
+
+func loadPlanState(dir, docPath string) (*PlanState, error) {
+	data, err := os.ReadFile(planStateFile(dir, docPath)) // #nosec G304 - path built from controlled components
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errNoPlanState
+		}
+		return nil, fmt.Errorf("reading plan state: %w", err)
+	}
+	var state PlanState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing plan state: %w", err)
+	}
+	return &state, nil
+}
+
+// gitPlanRepo answers planRepo queries with git in the current repository.
+type gitPlanRepo struct{}
+
+func (gitPlanRepo) FileExists(path string) bool {
+	out, err := runGitCommand("ls-files", "--", path, "*/"+path)
+	return err == nil && strings.TrimSpace(out) != ""
+}
+
+func (gitPlanRepo) HasSymbol(name string) bool {
+	_, err := runGitCommand("grep", "-q", "-w", "-F", "--", name)
+	return err == nil
+}
+
+func (gitPlanRepo) TodosMentioning(ref string) []string {
+	out, err := runGitCommand("grep", "-n", "-I", "-E", "(TODO|FIXME).*"+regexp.QuoteMeta(ref))
+	if err != nil {
+		return nil
+	}
+	var todos []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		// Keep file:line, drop the matched text
+		if parts := strings.SplitN(line, ":", 3); len(parts) == 3 {
+			todos = append(todos, parts[0]+":"+parts[1])
+		}
+	}
+	return todos
+}
+
+func (gitPlanRepo) CommitsMentioning(id string) []string {
+	// Match the whole ID, so "design-1" doesn't match "design-12"
+	pattern := fmt.Sprintf("(^|[^[:alnum:]-])%s($|[^[:alnum:]-])", regexp.QuoteMeta(id))
+	out, err := runGitCommand("log", "--format=%h", "-E", "--grep="+pattern)
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(out)
+}
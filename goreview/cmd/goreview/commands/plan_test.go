@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+const validPlanJSON = `{
+  "summary": "Solid design",
+  "score": {"overall": 82, "completeness": 80, "clarity": 85, "feasibility": 80},
+  "strengths": ["Clear scope"],
+  "concerns": [{"category": "security", "severity": "High", "description": "No auth on admin API"}],
+  "suggestions": ["Add rate limiting"]
+}`
+
+func TestParsePlanResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  string
+	}{
+		{"bare JSON", validPlanJSON, ""},
+		{"wrapped in a code fence", "```json\n" + validPlanJSON + "\n```", ""},
+		{"no JSON", "Looks good to me!", "no JSON object"},
+		{"missing score", `{"summary": "ok", "strengths": [], "concerns": [], "suggestions": []}`, "missing score.overall"},
+		{"missing summary", `{"score": {"overall": 80}}`, "missing summary"},
+		{"score out of range", `{"summary": "ok", "score": {"overall": 850}}`, "score.overall out of range"},
+		{"invalid severity", `{"summary": "ok", "score": {"overall": 80}, "concerns": [{"severity": "urgent", "description": "x"}]}`, "invalid severity"},
+		{"invalid priority", `{"summary": "ok", "score": {"overall": 80}, "checklist": [{"task": "x", "priority": "p0"}]}`, "invalid priority"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review, err := parsePlanResponse(tt.response)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parsePlanResponse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePlanResponse() error = %v", err)
+			}
+			if review.Score.Overall != 82 || review.Concerns[0].Severity != "high" {
+				t.Errorf("review = %+v", review)
+			}
+		})
+	}
+}
+
+// planProvider returns canned structured responses and records the prompts
+type planProvider struct {
+	responses []string
+	prompts   []string
+	schemas   []providers.JSONSchema
+}
+
+func (p *planProvider) Name() string { return "plan-mock" }
+func (p *planProvider) Review(context.Context, *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+	return nil, nil
+}
+func (p *planProvider) GenerateCommitMessage(context.Context, string) (string, error) { return "", nil }
+func (p *planProvider) GenerateDocumentation(context.Context, string, string) (string, error) {
+	return "", nil
+}
+func (p *planProvider) HealthCheck(context.Context) error { return nil }
+func (p *planProvider) Close() error                      { return nil }
+
+func (p *planProvider) GenerateJSON(_ context.Context, prompt string, schema providers.JSONSchema) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	p.schemas = append(p.schemas, schema)
+	resp := p.responses[0]
+	p.responses = p.responses[1:]
+	return resp, nil
+}
+
+func TestReviewDocumentRepairPass(t *testing.T) {
+	doc := filepath.Join(t.TempDir(), "design.md")
+	if err := os.WriteFile(doc, []byte("# Design\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("repairs invalid output", func(t *testing.T) {
+		p := &planProvider{responses: []string{`{"summary": "ok"}`, validPlanJSON}}
+		review, err := reviewDocument(context.Background(), p, doc)
+		if err != nil {
+			t.Fatalf("reviewDocument() error = %v", err)
+		}
+		if review.Score.Overall != 82 || review.Document != doc {
+			t.Errorf("review = %+v", review)
+		}
+		if len(p.prompts) != 2 || !strings.Contains(p.prompts[1], "missing score.overall") {
+			t.Errorf("repair prompt does not include the validation error: %v", p.prompts)
+		}
+		if p.schemas[0]["type"] != "object" {
+			t.Errorf("schema not passed to the provider: %v", p.schemas[0])
+		}
+	})
+
+	t.Run("fails instead of inventing a score", func(t *testing.T) {
+		p := &planProvider{responses: []string{"not json", "still not json"}}
+		if _, err := reviewDocument(context.Background(), p, doc); err == nil || !strings.Contains(err.Error(), "after repair") {
+			t.Errorf("reviewDocument() error = %v, want a repair failure", err)
+		}
+	})
+}
+
+func TestTaskReferences(t *testing.T) {
+	tests := []struct {
+		task        string
+		wantFiles   []string
+		wantSymbols []string
+	}{
+		{"Add rate limiter in internal/api/limiter.go", []string{"internal/api/limiter.go"}, nil},
+		{"Implement `config.Load` and ParseFlags()", nil, []string{"ParseFlags", "Load"}},
+		{"Document the API in `README.md`", []string{"README.md"}, nil},
+		{"Support read/write locking", nil, nil},
+		{"Write load tests", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.task, func(t *testing.T) {
+			files, symbols := taskReferences(tt.task)
+			if !slices.Equal(files, tt.wantFiles) || !slices.Equal(symbols, tt.wantSymbols) {
+				t.Errorf("taskReferences() = %v, %v, want %v, %v", files, symbols, tt.wantFiles, tt.wantSymbols)
+			}
+		})
+	}
+}
+
+// fakePlanRepo answers planRepo queries from fixed data
+type fakePlanRepo struct {
+	files   map[string]bool
+	symbols map[string]bool
+	todos   map[string][]string
+	commits map[string][]string
+}
+
+func (r fakePlanRepo) FileExists(path string) bool          { return r.files[path] }
+func (r fakePlanRepo) HasSymbol(name string) bool           { return r.symbols[name] }
+func (r fakePlanRepo) TodosMentioning(ref string) []string  { return r.todos[ref] }
+func (r fakePlanRepo) CommitsMentioning(id string) []string { return r.commits[id] }
+
+func TestEvaluatePlan(t *testing.T) {
+	review := &PlanReview{
+		Document: "docs/Design Doc.md",
+		Checklist: []ChecklistItem{
+			{Task: "Create internal/api/server.go", Priority: "high"},
+			{Task: "Implement Authenticate()", Priority: "high"},
+			{Task: "Add NewLimiter() in internal/api/limiter.go", Priority: "medium"},
+			{Task: "Write integration tests", Priority: "low"},
+		},
+	}
+	state := newPlanState(review)
+	if state.Items[0].ID != "design-doc-1" {
+		t.Fatalf("item ID = %q, want design-doc-1", state.Items[0].ID)
+	}
+
+	repo := fakePlanRepo{
+		files:   map[string]bool{"internal/api/server.go": true, "internal/api/limiter.go": true},
+		symbols: map[string]bool{"Authenticate": true},
+		todos:   map[string][]string{"Authenticate": {"internal/api/auth.go:12"}},
+		commits: map[string][]string{"design-doc-4": {"abc1234"}},
+	}
+	evaluatePlan(state, repo)
+
+	want := []string{itemDone, itemInProgress, itemInProgress, itemDone}
+	for i, item := range state.Items {
+		if item.Status != want[i] {
+			t.Errorf("item %s status = %s, want %s (evidence %v)", item.ID, item.Status, want[i], item.Evidence)
+		}
+	}
+	if state.Completion != 75 {
+		t.Errorf("Completion = %v, want 75", state.Completion)
+	}
+}
+
+func TestPlanStateRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	state := newPlanState(&PlanReview{Document: "docs/rfc.md", Checklist: []ChecklistItem{{Task: "x", Priority: "low"}}})
+	if err := savePlanState(dir, state); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadPlanState(dir, "./docs/rfc.md")
+	if err != nil {
+		t.Fatalf("loadPlanState() error = %v", err)
+	}
+	if loaded.Document != "docs/rfc.md" || len(loaded.Items) != 1 || loaded.Items[0].Task != "x" {
+		t.Errorf("loaded = %+v", loaded)
+	}
+
+	if _, err := loadPlanState(dir, "other.md"); !errors.Is(err, errNoPlanState) {
+		t.Errorf("loadPlanState() error = %v, want errNoPlanState", err)
+	}
+}
@@ -3,9 +3,12 @@ package commands
 import (
 	"fmt"
 	"os"
-	"strings"
-	"sync"
 	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/progress"
+	"github.com/JNZader/goreview/goreview/internal/review"
 )
 
 // UI constants (SonarQube S1192)
@@ -13,70 +16,28 @@ const (
 	summarySeparator = "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
 )
 
-// ProgressReporter handles CLI progress output.
-type ProgressReporter struct {
-	total     int
-	current   int
-	mu        sync.Mutex
-	startTime time.Time
-	spinner   int
-	done      chan struct{}
-}
-
-// NewProgressReporter creates a new progress reporter.
-func NewProgressReporter(total int) *ProgressReporter {
-	return &ProgressReporter{
-		total:     total,
-		startTime: time.Now(),
-		done:      make(chan struct{}),
+// startProgress attaches a progress tracker to the engine and starts
+// rendering it on stderr according to --progress. The returned function
+// stops the display.
+func startProgress(cmd *cobra.Command, engine *review.Engine) (func(), error) {
+	value, _ := cmd.Flags().GetString("progress")
+	mode, err := progress.ParseMode(value)
+	if err != nil {
+		return nil, err
+	}
+	if isQuiet() {
+		mode = progress.ModeOff
+	}
+	mode = progress.ResolveMode(mode, os.Stderr)
+	if mode == progress.ModeOff {
+		return func() {}, nil
 	}
-}
-
-// Start begins the progress display.
-func (p *ProgressReporter) Start() {
-	go func() {
-		spinnerChars := []rune{'⠋', '⠙', '⠹', '⠸', '⠼', '⠴', '⠦', '⠧', '⠇', '⠏'}
-		ticker := time.NewTicker(100 * time.Millisecond)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-p.done:
-				return
-			case <-ticker.C:
-				p.mu.Lock()
-				spinner := spinnerChars[p.spinner%len(spinnerChars)]
-				p.spinner++
-				progress := float64(p.current) / float64(p.total) * 100
-				bar := p.renderBar(progress)
-				_, _ = fmt.Fprintf(os.Stderr, "\r%c Reviewing files %s %.0f%% (%d/%d)",
-					spinner, bar, progress, p.current, p.total)
-				p.mu.Unlock()
-			}
-		}
-	}()
-}
-
-// Increment advances the progress by one.
-func (p *ProgressReporter) Increment(filename string) {
-	p.mu.Lock()
-	p.current++
-	p.mu.Unlock()
-}
-
-// Finish completes the progress display.
-func (p *ProgressReporter) Finish() {
-	close(p.done)
-	duration := time.Since(p.startTime)
-	_, _ = fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", 80))
-	_, _ = fmt.Fprintf(os.Stderr, "Reviewed %d files in %s\n", p.total, duration.Round(time.Millisecond))
-}
 
-func (p *ProgressReporter) renderBar(progress float64) string {
-	width := 20
-	filled := int(progress / 100 * float64(width))
-	empty := width - filled
-	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", empty) + "]"
+	tracker := progress.NewTracker()
+	engine.SetProgress(tracker)
+	display := progress.NewDisplay(tracker, os.Stderr, mode)
+	display.Start()
+	return display.Stop, nil
 }
 
 // PrintSummary prints a summary of the review results.
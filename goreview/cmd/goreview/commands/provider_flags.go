@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// addProviderFlags registers the --provider and --model overrides for
+// commands that call an AI provider.
+func addProviderFlags(cmd *cobra.Command) {
+	cmd.Flags().String("provider", "", "AI provider to use (ollama, openai, anthropic, gemini, groq, mistral, fallback, auto)")
+	cmd.Flags().String("model", "", "Model to use")
+}
+
+// addPersonalityFlag registers --personality for commands whose prompts
+// take the reviewer personality into account.
+func addPersonalityFlag(cmd *cobra.Command) {
+	cmd.Flags().String("personality", "", "Reviewer personality (default, senior, strict, friendly, security-expert)")
+}
+
+// addTimeoutFlag registers --timeout with the command's default.
+func addTimeoutFlag(cmd *cobra.Command, def time.Duration) {
+	cmd.Flags().Duration("timeout", def, "Maximum time for the command (e.g. 30s, 5m)")
+}
+
+// applyProviderFlags copies the provider overrides given on the command line
+// into cfg. Flags the command doesn't register are ignored.
+func applyProviderFlags(cmd *cobra.Command, cfg *config.Config) error {
+	if provider, _ := cmd.Flags().GetString("provider"); provider != "" {
+		cfg.Provider.Name = provider
+	}
+	if model, _ := cmd.Flags().GetString("model"); model != "" {
+		cfg.Provider.Model = model
+	}
+	if personality, _ := cmd.Flags().GetString("personality"); personality != "" {
+		if !providers.IsValidPersonality(personality) {
+			return fmt.Errorf("invalid personality %q (valid: %v)", personality, providers.ValidPersonalities())
+		}
+		cfg.Review.Personality = personality
+	}
+	return nil
+}
+
+// commandContext returns a context bounded by the command's --timeout.
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil || timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
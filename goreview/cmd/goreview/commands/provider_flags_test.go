@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+func newProviderFlagsCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	addProviderFlags(cmd)
+	addPersonalityFlag(cmd)
+	addTimeoutFlag(cmd, time.Minute)
+	return cmd
+}
+
+func TestApplyProviderFlags(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		wantProvider    string
+		wantModel       string
+		wantPersonality string
+		wantErr         bool
+	}{
+		{"no flags keeps config", nil, "ollama", "qwen", "default", false},
+		{"overrides", []string{"--provider", "groq", "--model", "llama", "--personality", "strict"}, "groq", "llama", "strict", false},
+		{"invalid personality", []string{"--personality", "grumpy"}, "ollama", "qwen", "default", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newProviderFlagsCmd()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			cfg := &config.Config{}
+			cfg.Provider.Name, cfg.Provider.Model, cfg.Review.Personality = "ollama", "qwen", "default"
+
+			err := applyProviderFlags(cmd, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyProviderFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if cfg.Provider.Name != tt.wantProvider || cfg.Provider.Model != tt.wantModel || cfg.Review.Personality != tt.wantPersonality {
+				t.Errorf("got %s/%s/%s", cfg.Provider.Name, cfg.Provider.Model, cfg.Review.Personality)
+			}
+		})
+	}
+}
+
+func TestApplyProviderFlagsIgnoresUnregistered(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	addProviderFlags(cmd)
+	if err := cmd.ParseFlags([]string{"--model", "gpt-4o"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	cfg.Review.Personality = "senior"
+	if err := applyProviderFlags(cmd, cfg); err != nil {
+		t.Fatalf("applyProviderFlags() error = %v", err)
+	}
+	if cfg.Provider.Model != "gpt-4o" || cfg.Review.Personality != "senior" {
+		t.Errorf("got model %q, personality %q", cfg.Provider.Model, cfg.Review.Personality)
+	}
+}
+
+func TestCommandContext(t *testing.T) {
+	cmd := newProviderFlagsCmd()
+	if err := cmd.ParseFlags([]string{"--timeout", "2s"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > 2*time.Second {
+		t.Errorf("deadline = %v, %v; want within 2s", deadline, ok)
+	}
+
+	// Commands without --timeout get no deadline
+	ctx, cancel = commandContext(&cobra.Command{Use: "bare"})
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("expected no deadline without --timeout")
+	}
+}
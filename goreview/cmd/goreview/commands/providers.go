@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Inspect the configured AI providers",
+}
+
+var providersStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check the health of the configured providers",
+	Long: `Check every provider a review may call: the configured provider or, with
+provider.name fallback or auto, each provider whose API key is set (and
+the local Ollama for auto). For each one, show whether it answers, whether
+it serves the model, how long the check took, the request quota left
+according to its rate limit headers and, when the audit middleware is
+configured, the calls and errors recorded in the last --since.
+
+Exits with an error when a provider is unhealthy, for monitoring scripts.
+
+Examples:
+  goreview providers status
+  goreview providers status --provider openai --model gpt-4o
+  goreview providers status --json --since 1h`,
+	Args: cobra.NoArgs,
+	RunE: runProvidersStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(providersCmd)
+	providersCmd.AddCommand(providersStatusCmd)
+
+	addProviderFlags(providersStatusCmd)
+	addTimeoutFlag(providersStatusCmd, 30*time.Second)
+	providersStatusCmd.Flags().Duration("since", 24*time.Hour, "Window of the recent calls read from the audit logs")
+	providersStatusCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// providersReport is the output of providers status.
+type providersReport struct {
+	CheckedAt time.Time          `json:"checked_at"`
+	Healthy   bool               `json:"healthy"`
+	Providers []providers.Status `json:"providers"`
+}
+
+func runProvidersStatus(cmd *cobra.Command, _ []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := applyProviderFlags(cmd, cfg); err != nil {
+		return err
+	}
+	since, _ := cmd.Flags().GetDuration("since")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	configured, failed := providers.ConfiguredProviders(cfg)
+	statuses := make([]providers.Status, len(configured))
+	var wg sync.WaitGroup
+	for i, p := range configured {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = providers.CheckStatus(ctx, p)
+			_ = p.Close()
+		}()
+	}
+	wg.Wait()
+	statuses = append(statuses, failed...)
+
+	report := providersReport{CheckedAt: time.Now().UTC(), Healthy: len(statuses) > 0, Providers: statuses}
+	recent, err := providers.RecentCalls(providers.AuditLogs(&cfg.Provider), time.Now().Add(-since))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: recent calls unavailable: %v\n", err)
+	}
+	for i := range report.Providers {
+		st := &report.Providers[i]
+		st.Recent = recent[st.Provider]
+		report.Healthy = report.Healthy && st.Healthy()
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else if err := printProviderStatus(os.Stdout, report.Providers); err != nil {
+		return err
+	}
+	if !report.Healthy {
+		return fmt.Errorf("%d of %d providers unhealthy", countUnhealthy(report.Providers), len(report.Providers))
+	}
+	return nil
+}
+
+func countUnhealthy(statuses []providers.Status) int {
+	n := 0
+	for i := range statuses {
+		if !statuses[i].Healthy() {
+			n++
+		}
+	}
+	return n
+}
+
+// printProviderStatus writes the statuses as a table, with the errors of
+// the unhealthy providers below it.
+func printProviderStatus(w io.Writer, statuses []providers.Status) error {
+	if len(statuses) == 0 {
+		_, err := fmt.Fprintln(w, "No providers configured")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "PROVIDER\tMODEL\tSTATUS\tLATENCY\tRATE LIMIT\tRECENT ERRORS")
+	for i := range statuses {
+		st := &statuses[i]
+		model, state, latency, quota, errorRate := st.Model, "ok", "-", "-", "-"
+		switch {
+		case !st.Reachable:
+			state = "down"
+		case !st.Healthy():
+			state = "error"
+		}
+		if model == "" {
+			model = "-"
+		} else if st.ModelAvailable != nil && !*st.ModelAvailable {
+			model += " (missing)"
+		}
+		if st.Reachable {
+			latency = fmt.Sprintf("%dms", st.LatencyMS)
+		}
+		if r := st.RateLimit; r != nil {
+			quota = fmt.Sprintf("%d/%d (%.0f%%)", r.Remaining, r.Limit, 100*r.Headroom())
+		}
+		if c := st.Recent; c != nil {
+			errorRate = fmt.Sprintf("%d/%d (%.0f%%)", c.Errors, c.Calls, 100*c.ErrorRate())
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", st.Provider, model, state, latency, quota, errorRate)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var notes []string
+	for i := range statuses {
+		st := &statuses[i]
+		if st.Error != "" {
+			notes = append(notes, fmt.Sprintf("%s: %s", st.Provider, st.Error))
+		}
+		if c := st.Recent; c != nil && c.LastError != "" {
+			notes = append(notes, fmt.Sprintf("%s last error: %s", st.Provider, truncate(c.LastError, 120)))
+		}
+	}
+	if len(notes) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%s\n", strings.Join(notes, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestPrintProviderStatus(t *testing.T) {
+	missing := false
+	statuses := []providers.Status{
+		{Provider: "groq", Model: "llama-3.3-70b", Reachable: true, LatencyMS: 120,
+			RateLimit: &providers.RateLimit{Limit: 30, Remaining: 27},
+			Recent:    &providers.CallStats{Calls: 10, Errors: 1, LastError: "429 too many requests"}},
+		{Provider: "ollama", Model: "llama3", Reachable: true, LatencyMS: 3, ModelAvailable: &missing, Error: "model llama3 not available"},
+		{Provider: "openai", Error: "OpenAI API key is required"},
+	}
+
+	var sb strings.Builder
+	if err := printProviderStatus(&sb, statuses); err != nil {
+		t.Fatal(err)
+	}
+	want := `PROVIDER  MODEL             STATUS  LATENCY  RATE LIMIT   RECENT ERRORS
+groq      llama-3.3-70b     ok      120ms    27/30 (90%)  1/10 (10%)
+ollama    llama3 (missing)  error   3ms      -            -
+openai    -                 down    -        -            -
+
+groq last error: 429 too many requests
+ollama: model llama3 not available
+openai: OpenAI API key is required
+`
+	if sb.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", sb.String(), want)
+	}
+	if n := countUnhealthy(statuses); n != 2 {
+		t.Errorf("countUnhealthy = %d, want 2", n)
+	}
+}
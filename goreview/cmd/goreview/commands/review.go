@@ -2,7 +2,9 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,15 +14,24 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/JNZader/goreview/goreview/internal/artifact"
 	"github.com/JNZader/goreview/goreview/internal/cache"
 	"github.com/JNZader/goreview/goreview/internal/config"
 	"github.com/JNZader/goreview/goreview/internal/export"
+	"github.com/JNZader/goreview/goreview/internal/failure"
+	"github.com/JNZader/goreview/goreview/internal/gate"
 	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/manifest"
+	"github.com/JNZader/goreview/goreview/internal/privacy"
 	"github.com/JNZader/goreview/goreview/internal/profiler"
 	"github.com/JNZader/goreview/goreview/internal/providers"
 	"github.com/JNZader/goreview/goreview/internal/report"
 	"github.com/JNZader/goreview/goreview/internal/review"
 	"github.com/JNZader/goreview/goreview/internal/rules"
+	"github.com/JNZader/goreview/goreview/internal/testcheck"
+	"github.com/JNZader/goreview/goreview/internal/transcript"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
 )
 
 var reviewCmd = &cobra.Command{
@@ -32,15 +43,31 @@ Examples:
   # Review staged changes
   goreview review --staged
 
+  # Review staged changes and the new files not added yet
+  goreview review --staged --include-untracked
+
+  # Review all uncommitted work: staged, unstaged and untracked files
+  goreview review --worktree
+
   # Review a specific commit
   goreview review --commit abc123
 
   # Review changes from current branch vs main
   goreview review --branch main
 
+  # Re-review the branch, sending only hunks changed since the last review
+  goreview review --branch main --incremental
+
   # Review specific files
   goreview review file1.go file2.go
 
+  # Review a whole file, with two neighboring files of its package as context
+  goreview review legacy/billing.go --full --context-radius 2
+
+  # Review a diff piped in, without a git repository
+  git format-patch -1 --stdout | goreview review --stdin
+  goreview review --patch change.patch
+
   # Output as JSON
   goreview review --staged --format json
 
@@ -54,27 +81,52 @@ func init() {
 
 	// Mode flags (mutually exclusive)
 	reviewCmd.Flags().Bool("staged", false, "Review staged changes")
-	reviewCmd.Flags().String("commit", "", "Review a specific commit")
+	reviewCmd.Flags().Bool("worktree", false, "Review all uncommitted changes, staged or not, and the untracked files")
+	reviewCmd.Flags().Bool("include-untracked", false, "With --staged, also review the untracked files as new files")
+	reviewCmd.Flags().String("commit", "", "Review a specific commit, or a range like main..feature")
 	reviewCmd.Flags().String("branch", "", "Review changes compared to branch")
+	reviewCmd.Flags().Bool("stdin", false, "Review a unified diff read from stdin")
+	reviewCmd.Flags().String("patch", "", "Review a unified diff or patch file")
+	reviewCmd.Flags().String("from-report", "", "JSON report of a previous review, for --only-flagged")
+	reviewCmd.Flags().String("only-flagged", "", "Review only the files of --from-report with issues at or above this severity (default info); without a mode, review their uncommitted changes")
+	reviewCmd.Flags().Lookup("only-flagged").NoOptDefVal = "info"
 
 	// Output flags
-	reviewCmd.Flags().StringP("format", "f", "markdown", "Output format (markdown, json, sarif)")
-	reviewCmd.Flags().StringP("output", "o", "", "Write report to file")
+	reviewCmd.Flags().StringP("format", "f", "markdown", "Output format (markdown, json, sarif, pdf, codeactions, dot, mermaid)")
+	reviewCmd.Flags().StringP("output", "o", "", "Write report to file, or upload it to an s3:// or gs:// URL")
+	reviewCmd.Flags().String("manifest", "", "Write a JSON run manifest (inputs, files, config digest, timings, cache hits, provider calls) to this file")
+	reviewCmd.Flags().String("save-transcripts", "", "Save each file's prompts and raw provider answers, redacted per the privacy config, to this directory")
 
 	// Filter flags
 	reviewCmd.Flags().StringSlice("include", nil, "Include only these file patterns")
 	reviewCmd.Flags().StringSlice("exclude", nil, "Exclude these file patterns")
+	reviewCmd.Flags().StringSlice("focus-symbols", nil, "Review only the changes inside these functions and types (Parse,Engine.Run)")
 
 	// Provider flags
-	reviewCmd.Flags().String("provider", "", "AI provider to use (ollama, openai)")
-	reviewCmd.Flags().String("model", "", "Model to use")
+	addProviderFlags(reviewCmd)
+	addPersonalityFlag(reviewCmd)
+	addTimeoutFlag(reviewCmd, 10*time.Minute)
 
 	// Behavior flags
 	reviewCmd.Flags().Int("concurrency", 0, "Max concurrent file reviews (0=auto)")
+	reviewCmd.Flags().Duration("time-budget", 0, "Review the riskiest files first and stop after this long (3m), listing the files left unreviewed")
+	reviewCmd.Flags().Int("token-budget", 0, "Review the highest-priority files up to this many estimated diff tokens and summarize the rest, flagged as not reviewed")
+	reviewCmd.Flags().String("shard", "", "Review only this shard of the changed files, as index/total (2/4); merge the reports with merge-results")
+	reviewCmd.Flags().Bool("adaptive-concurrency", false, "Tune concurrency during the run from provider latency, throttling and memory")
 	reviewCmd.Flags().Bool("no-cache", false, "Disable caching")
+	reviewCmd.Flags().Bool("incremental", false, "With --branch, only review hunks changed since the last review of the branch")
+	reviewCmd.Flags().Bool("pr-context", false, "Give the reviewer the description, linked issues and review comments of the change's open pull request")
+	reviewCmd.Flags().Bool("full", false, "With file arguments, review the whole files instead of their diff")
+	reviewCmd.Flags().Int("context-radius", 0, "With --full, send this many neighboring files of the same package on each side as context")
 	reviewCmd.Flags().String("preset", "standard", "Rule preset (minimal, standard, strict)")
-	reviewCmd.Flags().String("personality", "default", "Reviewer personality (default, senior, strict, friendly, security-expert)")
-	reviewCmd.Flags().String("mode", "default", "Review focus mode (default, security, perf, clean, docs, tests). Combine with commas: security,perf")
+	reviewCmd.Flags().String("mode", "default", "Review focus mode (default, security, perf, clean, docs, tests, errors, concurrency). Combine with commas: security,perf")
+	reviewCmd.Flags().String("fail-on", "", "Exit non-zero when issues reach this severity (info, warning, error, critical)")
+	reviewCmd.Flags().String("min-severity", "", "Drop issues below this severity (info, warning, error, critical)")
+	reviewCmd.Flags().String("conformance", "", "Also check the repository against this template policy file")
+	reviewCmd.Flags().String("progress", "auto", "Progress output on stderr (auto, tty, log, off)")
+	reviewCmd.Flags().Bool("size-impact", false, "Build affected Go binaries before and after the change and report size growth")
+	reviewCmd.Flags().String("generated-policy", "", "Detect AI-generated and pasted code and label it (label) or also review it strictly (strict)")
+	reviewCmd.Flags().Bool("strict-scope", false, "Fail when the change bundles unrelated file categories beyond review.scope.max_unrelated")
 
 	// TDD workflow flags
 	reviewCmd.Flags().Bool("require-tests", false, "Fail if reviewed code lacks corresponding tests")
@@ -82,6 +134,7 @@ func init() {
 
 	// Analysis flags
 	reviewCmd.Flags().Bool("trace", false, "Enable root cause tracing for each issue")
+	reviewCmd.Flags().Bool("ast-diagnostics", false, "Report per file the parser used, the functions and classes it recognized and its parse errors")
 
 	// Profiling flags
 	reviewCmd.Flags().String("cpuprofile", "", "Write CPU profile to file")
@@ -89,6 +142,7 @@ func init() {
 	reviewCmd.Flags().String("pprof-addr", "", "Enable pprof HTTP server (e.g., :6060)")
 
 	// Export flags
+	reviewCmd.Flags().StringSlice("export", nil, "Exporters to run in addition to those enabled in config (obsidian, slack, webhook)")
 	reviewCmd.Flags().Bool("export-obsidian", false, "Export results to Obsidian vault")
 	reviewCmd.Flags().String("obsidian-vault", "", "Override Obsidian vault path")
 
@@ -101,11 +155,25 @@ func init() {
 	_ = viper.BindPFlag("review.concurrency", reviewCmd.Flags().Lookup("concurrency"))
 }
 
-func runReview(cmd *cobra.Command, args []string) error {
+func runReview(cmd *cobra.Command, args []string) (err error) {
+	// A run failing before its report still writes one with --format json
+	var cfg *config.Config
+	var result *review.Result
+	reported := false
+	defer func() {
+		if err != nil && !reported {
+			writeFailureReport(cmd, cfg, result, err)
+		}
+	}()
+
 	if err := validateReviewFlags(cmd, args); err != nil {
-		return err
+		return &failure.ConfigError{Code: failure.ConfigInvalid, Err: err}
 	}
 
+	// Record the run manifest; early returns still write it, with the error
+	rec := startManifest(cmd, args)
+	defer func() { writeManifest(cmd, rec, result, err) }()
+
 	// Initialize profiler if requested
 	cleanupProfiler, err := setupProfiler(cmd)
 	if err != nil {
@@ -116,22 +184,78 @@ func runReview(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load configuration
-	cfg, err := config.LoadDefault()
+	cfg, err = loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
-	applyFlagOverrides(cmd, cfg, args)
+	if err = applyFlagOverrides(cmd, cfg, args); err != nil {
+		if errors.Is(err, errNothingFlagged) {
+			fmt.Fprintf(os.Stderr, "Nothing to re-review: %v\n", err)
+			return nil
+		}
+		return err
+	}
+	gates, err := gate.CompileAll(cfg.Gates)
+	if err != nil {
+		return fmt.Errorf("compiling gates: %w", err)
+	}
+	// Catch a bad upload URL before the review rather than after it
+	for _, dest := range reportDestinations(cfg) {
+		if artifact.IsRemote(dest) {
+			if _, err = artifact.Parse(dest); err != nil {
+				return err
+			}
+		}
+	}
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	ctx, cancel := commandContext(cmd)
 	defer cancel()
 
+	if rec != nil {
+		rec.SetConfig(cfg, cfg.Output.Format)
+		rec.SetRepository(manifestRepository(ctx))
+		rec.SetRunBy(manifestRunBy())
+	}
+	rec.EndPhase("setup")
+
 	// Initialize dependencies
-	result, err := executeReview(ctx, cmd, cfg)
+	result, err = executeReview(ctx, cmd, cfg, rec)
 	if err != nil {
 		return err
 	}
 
+	printQuality(result.Quality)
+	printContextBudgets(result)
+	printFiltered(result.Filtered)
+	printUnreviewed(result.Unreviewed)
+	printRemainder(result.Remainder)
+	if !isQuiet() {
+		printASTCoverage(os.Stderr, result.Files)
+	}
+	// A shard is part of a review: merge-results records the whole one,
+	// runs the exporters and applies the gates
+	sharded := result.Shard != nil
+	if !sharded {
+		recordDebt(ctx, cfg, result)
+	}
+
+	// Add template conformance violations alongside the AI findings
+	if err := applyConformance(cfg, result); err != nil {
+		return err
+	}
+	applySizeImpact(ctx, cfg, result)
+	if !sharded {
+		recordTriage(ctx, cfg, result)
+	}
+
+	// Fail on unrelated changes bundled together
+	if cfg.Review.Scope.Strict {
+		if err := checkScope(result.Scope, cfg.Review.Scope.MaxUnrelated); err != nil {
+			return err
+		}
+	}
+
 	// Check TDD requirements
 	requireTests, _ := cmd.Flags().GetBool("require-tests")
 	if requireTests {
@@ -140,25 +264,160 @@ func runReview(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if !sharded {
+		applyGates(gates, result)
+	}
+	rec.EndPhase("post")
+
 	// Generate and write report
-	if err := outputReport(cmd, result); err != nil {
+	reported = true
+	if err := outputReport(ctx, cfg, result); err != nil {
+		return err
+	}
+	if err := outputExtraReports(ctx, cfg, result); err != nil {
 		return err
 	}
+	rec.EndPhase("report")
 
-	// Export to Obsidian if requested
-	exportObsidian, _ := cmd.Flags().GetBool("export-obsidian")
-	if exportObsidian || cfg.Export.Obsidian.Enabled {
-		if err := exportToObsidian(ctx, cmd, cfg, result); err != nil {
-			// Non-fatal - log warning but don't fail
-			fmt.Fprintf(os.Stderr, "Warning: Obsidian export failed: %v\n", err)
-		}
+	// Run exporters; failures are reported but don't fail the review
+	if !sharded {
+		runExports(ctx, cmd, cfg, result)
 	}
+	rec.EndPhase("export")
 
-	// Exit with error code if critical issues found
-	checkCriticalIssues(result)
+	// checkFailThreshold may exit, so the manifest is written first
+	writeManifest(cmd, rec, result, nil)
+	if sharded {
+		return nil
+	}
+
+	// Exit with error code if a gate failed or, without gates, if issues at
+	// or above review.fail_on were found
+	checkFailThreshold(result, cfg.Review.FailOn)
 	return nil
 }
 
+// printQuality reports the review's quality metadata on stderr: in full
+// with --verbose, or as a one-line warning when results are degraded.
+func printQuality(q *review.Quality) {
+	if q == nil || isQuiet() {
+		return
+	}
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Review quality: %d/100 (%d issues, %.0f%% located, %.0f%% with fixes, %d parse failures, %d truncated chunks, %d failed files)\n",
+			q.Score, q.Issues, q.LocationRate, q.FixRate, q.ParseFailures, q.TruncatedChunks, q.FailedFiles)
+		for _, w := range q.Warnings {
+			fmt.Fprintf(os.Stderr, "  - %s\n", w)
+		}
+		return
+	}
+	if q.Degraded {
+		fmt.Fprintf(os.Stderr, "Warning: review quality degraded (%d/100), run with --verbose for details\n", q.Score)
+	}
+}
+
+// printContextBudgets reports with --verbose how each file's prompt spent
+// the context window, to tell why context was truncated.
+func printContextBudgets(result *review.Result) {
+	if !isVerbose() || isQuiet() {
+		return
+	}
+	for _, f := range result.Files {
+		b := f.Budget
+		if b == nil {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Context budget %s: prompt %d + response %d of %d tokens (instructions %d, diff %d in %d chunk(s) of max %d, rules %d, knowledge %d, focus %d)",
+			f.File, b.Prompt, b.ResponseTokens, b.ContextWindow, b.Instructions, b.Diff, b.Chunks, b.ChunkLimit, b.Rules, b.Knowledge, b.Focus)
+		if len(b.Truncated) > 0 {
+			fmt.Fprintf(os.Stderr, ", truncated: %s", strings.Join(b.Truncated, ", "))
+		}
+		if b.Overflow > 0 {
+			fmt.Fprintf(os.Stderr, ", over the window by %d", b.Overflow)
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// printFiltered reports with --verbose the changed files left out of the
+// review and the pattern that left each out.
+func printFiltered(filtered []review.FilteredFile) {
+	if !isVerbose() || isQuiet() {
+		return
+	}
+	for _, f := range filtered {
+		if f.Pattern != "" {
+			fmt.Fprintf(os.Stderr, "Filtered %s: %s (%s)\n", f.File, f.Reason, f.Pattern)
+		} else {
+			fmt.Fprintf(os.Stderr, "Filtered %s: %s\n", f.File, f.Reason)
+		}
+	}
+}
+
+// printUnreviewed warns on stderr about the files left unreviewed,
+// riskiest first.
+func printUnreviewed(files []review.UnreviewedFile) {
+	if len(files) == 0 || isQuiet() {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %d files not reviewed:\n", len(files))
+	for _, f := range files {
+		fmt.Fprintf(os.Stderr, "  %s (risk %d, %s)\n", f.File, f.Risk, unreviewedReasons[f.Reason])
+	}
+}
+
+// printRemainder warns on stderr about the files over the token budget,
+// summarized instead of reviewed.
+func printRemainder(rem *review.Remainder) {
+	if rem == nil || isQuiet() {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: token budget of %d exceeded, %d files summarized, not reviewed:\n", rem.Budget, len(rem.Files))
+	for _, f := range rem.Files {
+		fmt.Fprintf(os.Stderr, "  %s (~%d tokens)\n", f.File, f.Tokens)
+	}
+}
+
+// unreviewedReasons explain why files were left unreviewed.
+var unreviewedReasons = map[string]string{
+	review.UnreviewedTimeBudget: "time budget ran out",
+	review.UnreviewedBlocked:    "blocked by the provider's safety filters",
+}
+
+// printASTCoverage reports, with --ast-diagnostics, how much structure the
+// AST parser extracted from each file, flagging the files whose review got
+// degraded context from the generic parser or parse errors.
+func printASTCoverage(w io.Writer, files []review.FileResult) {
+	degraded, total := 0, 0
+	for _, f := range files {
+		c := f.AST
+		if c == nil {
+			continue
+		}
+		if total == 0 {
+			fmt.Fprintln(w, "AST coverage:")
+		}
+		total++
+		status := "ok"
+		switch {
+		case c.Parser == "generic":
+			status = "degraded, generic parser"
+		case len(c.Errors) > 0:
+			status = "degraded, parse errors"
+		}
+		if c.Degraded() {
+			degraded++
+		}
+		fmt.Fprintf(w, "  %s: %s parser, %d functions, %d classes (%s)\n", f.File, c.Parser, c.Functions, c.Classes, status)
+		for _, e := range c.Errors {
+			fmt.Fprintf(w, "    %s\n", e)
+		}
+	}
+	if total > 0 {
+		fmt.Fprintf(w, "%d of %d files with degraded AST context\n", degraded, total)
+	}
+}
+
 // setupProfiler initializes profiler if flags are set, returns cleanup function
 func setupProfiler(cmd *cobra.Command) (func(), error) {
 	cpuProfile, _ := cmd.Flags().GetString("cpuprofile")
@@ -192,11 +451,12 @@ func setupProfiler(cmd *cobra.Command) (func(), error) {
 	}, nil
 }
 
-// executeReview initializes dependencies and runs the review
-func executeReview(ctx context.Context, cmd *cobra.Command, cfg *config.Config) (*review.Result, error) {
-	gitRepo, err := git.NewRepo(".")
+// executeReview initializes dependencies and runs the review,
+// and records it in the run manifest
+func executeReview(ctx context.Context, cmd *cobra.Command, cfg *config.Config, rec *manifest.Recorder) (*review.Result, error) {
+	gitRepo, err := openReviewRepo(cmd, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("initializing git: %w", err)
+		return nil, err
 	}
 
 	provider, err := providers.NewProvider(cfg)
@@ -206,51 +466,264 @@ func executeReview(ctx context.Context, cmd *cobra.Command, cfg *config.Config)
 	defer func() { _ = provider.Close() }()
 
 	if healthErr := provider.HealthCheck(ctx); healthErr != nil {
-		return nil, fmt.Errorf("provider not available: %w", healthErr)
+		return nil, fmt.Errorf("provider not available: %w", providers.ClassifyError(provider.Name(), healthErr))
 	}
 
 	reviewCache := initCache(cmd, cfg)
-	activeRules, err := loadActiveRules(cmd, cfg)
+	if closer, ok := reviewCache.(io.Closer); ok {
+		defer func() { _ = closer.Close() }() // Persists the hit and miss counters
+	}
+	activeRules, err := loadActiveRules(cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	engine := review.NewEngine(cfg, gitRepo, provider, reviewCache, activeRules)
+	engine.SetVersion(Version)
+	if cfg.Review.Mode != "patch" {
+		engine.SetConventions(loadConventions(ctx, cfg))
+	}
+	if diagnose, _ := cmd.Flags().GetBool("ast-diagnostics"); diagnose {
+		engine.DiagnoseAST()
+	}
+	if cfg.Review.PRContext {
+		primeIntent(ctx, gitRepo, cfg, engine)
+	}
+	if err := setupTranscripts(cmd, cfg, engine); err != nil {
+		return nil, err
+	}
+	if closeTriage := setupTriage(cfg, getHistoryDBPath(cfg), engine); closeTriage != nil {
+		defer closeTriage()
+	}
+	saveBranchReview := prepareIncremental(ctx, gitRepo, cfg, engine)
+
+	stopProgress, err := startProgress(cmd, engine)
+	if err != nil {
+		return nil, err
+	}
 	result, err := engine.Run(ctx)
+	stopProgress()
+	rec.EndPhase("review")
+	rec.SetResult(result, engine.RunStats())
 	if err != nil {
 		return nil, fmt.Errorf("review failed: %w", err)
 	}
+	saveBranchReview(result)
 	return result, nil
 }
 
-// initCache creates a cache if enabled
+// openReviewRepo returns the repository to review: the git repository in
+// the current directory or, in patch mode, the patch read from stdin or
+// --patch, which needs no repository.
+func openReviewRepo(cmd *cobra.Command, cfg *config.Config) (git.Repository, error) {
+	if cfg.Review.Mode != "patch" {
+		gitRepo, err := git.NewRepo(".")
+		if err != nil {
+			return nil, fmt.Errorf("initializing git: %w", err)
+		}
+		return gitRepo, nil
+	}
+
+	var data []byte
+	var err error
+	if path, _ := cmd.Flags().GetString("patch"); path != "" {
+		data, err = os.ReadFile(path) //nolint:gosec // Patch path is user-provided
+	} else {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading patch: %w", err)
+	}
+	diff, err := git.ParsePatch(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing patch: %w", err)
+	}
+
+	// Inside a checkout, the changed files can still be read to verify
+	// issue locations
+	root := ""
+	if out, err := runGitCommand("rev-parse", "--show-toplevel"); err == nil {
+		root = strings.TrimSpace(out)
+	}
+	return git.NewPatchRepo(diff, root), nil
+}
+
+// setupTranscripts saves the provider transcripts of the review when
+// --save-transcripts is set.
+func setupTranscripts(cmd *cobra.Command, cfg *config.Config, engine *review.Engine) error {
+	dir, _ := cmd.Flags().GetString("save-transcripts")
+	if dir == "" {
+		return nil
+	}
+	redactor, err := privacy.New(cfg.Privacy)
+	if err != nil {
+		return err
+	}
+	writer, err := transcript.NewWriter(dir, cfg.Provider.Name, cfg.Provider.Model, redactor)
+	if err != nil {
+		return err
+	}
+	engine.SaveTranscripts(writer)
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Saving provider transcripts to %s\n", dir)
+	}
+	return nil
+}
+
+// primeIntent gives the reviewer the context of the open pull or merge
+// request of the change. Failures only warn: the review runs without it.
+func primeIntent(ctx context.Context, gitRepo git.Repository, cfg *config.Config, engine *review.Engine) {
+	var branch string
+	if repoRoot, err := gitRepo.GetRepoRoot(ctx); err == nil {
+		branch = history.GetCurrentBranch(repoRoot)
+	}
+	if branch == "HEAD" {
+		branch = ""
+	}
+	rc, err := export.FetchRequestContext(ctx, cfg.Export.VCS, branch, cfg.Export.Timeout)
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Warning: pull request context unavailable: %v\n", err)
+	case rc == nil:
+		if isVerbose() {
+			fmt.Fprintf(os.Stderr, "No open pull request found for %s\n", branch)
+		}
+	default:
+		engine.SetIntent(rc.String())
+		if isVerbose() {
+			fmt.Fprintf(os.Stderr, "Primed with request #%d (%d linked issues, %d review comments)\n",
+				rc.Number, len(rc.Issues), len(rc.Comments))
+		}
+	}
+}
+
+// prepareIncremental loads the previous review of the current branch into
+// the engine and returns a function that stores the new one. Incremental
+// review only applies to branch reviews; failures fall back to a full review.
+func prepareIncremental(ctx context.Context, gitRepo git.Repository, cfg *config.Config, engine *review.Engine) func(*review.Result) {
+	noop := func(*review.Result) {}
+	if !cfg.Review.Incremental || cfg.Review.Mode != "branch" {
+		return noop
+	}
+
+	repoRoot, err := gitRepo.GetRepoRoot(ctx)
+	if err != nil {
+		return noop
+	}
+	store, err := history.NewCommitStore(repoRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: incremental review disabled: %v\n", err)
+		return noop
+	}
+	branch := history.GetCurrentBranch(repoRoot)
+	if branch == "" || branch == "HEAD" {
+		return noop
+	}
+
+	prior, err := store.LoadBranchReview(branch)
+	switch {
+	case errors.Is(err, history.ErrNoBranchReview):
+		// First review of the branch
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Warning: ignoring previous review of %s: %v\n", branch, err)
+	case !engine.SetPriorReview(prior):
+		if isVerbose() {
+			fmt.Fprintf(os.Stderr, "Previous review of %s used other settings, reviewing all hunks\n", branch)
+		}
+	}
+
+	return func(result *review.Result) {
+		snapshot := engine.BranchReview(result, branch)
+		snapshot.HeadCommit = history.GetHeadCommit(repoRoot)
+		if err := store.SaveBranchReview(snapshot); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save branch review: %v\n", err)
+		}
+	}
+}
+
+// initCache creates a cache if enabled. Reviews are cached on disk so they
+// survive between runs, falling back to memory when the directory can't be
+// created.
 func initCache(cmd *cobra.Command, cfg *config.Config) cache.Cache {
 	noCache, _ := cmd.Flags().GetBool("no-cache")
 	if noCache || !cfg.Cache.Enabled {
 		return nil
 	}
-	return cache.NewLRUCache(cfg.Cache.MaxEntries, cfg.Cache.TTL)
+	fileCache, err := cache.NewFileCache(reviewCacheDir(cfg), cfg.Cache.TTL)
+	if err != nil {
+		if !isQuiet() {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: using an in-memory cache: %v\n", err)
+		}
+		return cache.NewLRUCache(cfg.Cache.MaxEntries, cfg.Cache.TTL)
+	}
+	return fileCache
 }
 
-// loadActiveRules loads and applies rule preset
-func loadActiveRules(cmd *cobra.Command, cfg *config.Config) ([]rules.Rule, error) {
+// loadActiveRules loads the rules and applies the rules.preset preset
+func loadActiveRules(cfg *config.Config) ([]rules.Rule, error) {
 	rulesLoader := rules.NewLoader(cfg.Rules.RulesDir)
-	allRules, err := rulesLoader.Load()
+	allRules, err := loadAllRules(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("loading rules: %w", err)
+		return nil, err
 	}
 
-	preset, _ := cmd.Flags().GetString("preset")
-	presetConfig, err := rulesLoader.LoadPreset(preset)
+	presetConfig, err := rulesLoader.LoadPreset(cfg.Rules.Preset)
 	if err != nil {
 		return nil, fmt.Errorf("loading preset: %w", err)
 	}
 	return rules.ApplyPreset(allRules, presetConfig), nil
 }
 
-// outputReport generates and writes the review report
-func outputReport(cmd *cobra.Command, result *review.Result) error {
-	format, _ := cmd.Flags().GetString("format")
+// outputReport writes the report in output.format to output.file
+func outputReport(ctx context.Context, cfg *config.Config, result *review.Result) error {
+	return writeReport(ctx, cfg, result.Public(), cfg.Output.Format, cfg.Output.File)
+}
+
+// writeFailureReport writes, with --format json, the report of a review
+// that failed: the run's error is added to its errors array, so wrappers
+// read why from the same document as the results. Reports in other formats
+// aren't written; the error is printed on stderr.
+func writeFailureReport(cmd *cobra.Command, cfg *config.Config, result *review.Result, runErr error) {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	applyReportFlags(cmd, cfg)
+	if cfg.Output.Format != "json" {
+		return
+	}
+	doc := &reviewtypes.Result{SchemaVersion: reviewtypes.SchemaVersion, Files: []reviewtypes.FileResult{}}
+	if result != nil {
+		doc = result.Public()
+	}
+	doc.Errors = append(doc.Errors, reviewtypes.Failure(failure.Describe(runErr, "")))
+	if err := writeReport(context.Background(), cfg, doc, cfg.Output.Format, cfg.Output.File); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: writing the failure report: %v\n", err)
+	}
+}
+
+// outputExtraReports writes the additional output.reports of a review
+func outputExtraReports(ctx context.Context, cfg *config.Config, result *review.Result) error {
+	public := result.Public()
+	for _, r := range cfg.Output.Reports {
+		if err := writeReport(ctx, cfg, public, r.Format, r.File); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportDestinations returns where the reports are written; "" is stdout.
+func reportDestinations(cfg *config.Config) []string {
+	dests := []string{cfg.Output.File}
+	for _, r := range cfg.Output.Reports {
+		dests = append(dests, r.File)
+	}
+	return dests
+}
+
+// writeReport generates a report and writes it to the output file, uploads
+// it or prints it, then uploads it to the configured artifact store
+func writeReport(ctx context.Context, cfg *config.Config, result *reviewtypes.Result, format, outputFile string) error {
 	reporter, err := report.NewReporter(format)
 	if err != nil {
 		return err
@@ -261,90 +734,180 @@ func outputReport(cmd *cobra.Command, result *review.Result) error {
 		return fmt.Errorf("generating report: %w", err)
 	}
 
-	outputFile, _ := cmd.Flags().GetString("output")
-	if outputFile != "" {
+	switch {
+	case artifact.IsRemote(outputFile):
+		if err := uploadReport(ctx, cfg, outputFile, output, format); err != nil {
+			return err
+		}
+	case outputFile != "":
 		if err := os.WriteFile(outputFile, []byte(output), 0600); err != nil {
 			return fmt.Errorf("writing output: %w", err)
 		}
 		_, _ = fmt.Fprintf(os.Stderr, "Report written to %s\n", outputFile)
-	} else {
+	default:
 		fmt.Print(output)
 	}
+
+	uploadArtifact(ctx, cfg, output, format)
 	return nil
 }
 
-// checkCriticalIssues exits with code 1 if critical issues found
-func checkCriticalIssues(result *review.Result) {
-	if result.TotalIssues == 0 {
+// checkFailThreshold exits with code 1 if any gate failed or, when there are
+// no gates, if any issue reaches the threshold severity
+func checkFailThreshold(result *review.Result, failOn string) {
+	if len(result.Gates) > 0 {
+		if len(result.GatesFailed()) > 0 {
+			os.Exit(1)
+		}
 		return
 	}
-	for _, f := range result.Files {
-		if f.Response == nil {
-			continue
-		}
-		for _, issue := range f.Response.Issues {
-			if issue.Severity == providers.SeverityCritical {
-				os.Exit(1)
-			}
-		}
+	if result.TotalIssues > 0 && result.ExceedsSeverity(failOn) {
+		os.Exit(1)
 	}
 }
 
 func validateReviewFlags(cmd *cobra.Command, args []string) error {
-	staged, _ := cmd.Flags().GetBool("staged")
-	commit, _ := cmd.Flags().GetString("commit")
-	branch, _ := cmd.Flags().GetString("branch")
-
-	// Count active modes
-	modeCount := 0
-	if staged {
-		modeCount++
+	stdin, _ := cmd.Flags().GetBool("stdin")
+	patch, _ := cmd.Flags().GetString("patch")
+	if stdin && patch != "" {
+		return fmt.Errorf("--stdin and --patch are mutually exclusive")
 	}
-	if commit != "" {
-		modeCount++
-	}
-	if branch != "" {
-		modeCount++
+
+	fromReport, _ := cmd.Flags().GetString("from-report")
+	flagged, _ := cmd.Flags().GetString("only-flagged")
+	if (flagged != "") != (fromReport != "") {
+		return fmt.Errorf("--only-flagged and --from-report must be used together")
 	}
-	if len(args) > 0 {
-		modeCount++
+	if _, ok := providers.ParseSeverity(flagged); flagged != "" && !ok {
+		return fmt.Errorf("invalid --only-flagged severity %q, must be: info, warning, error, or critical", flagged)
 	}
 
-	// Must have exactly one mode
-	if modeCount == 0 {
-		return fmt.Errorf("must specify review mode: --staged, --commit, --branch, or file arguments")
+	// Must have exactly one mode; the files flagged in a report are
+	// reviewed without one
+	modeCount := countReviewModes(cmd, args)
+	if modeCount == 0 && fromReport == "" {
+		return fmt.Errorf("must specify review mode: --staged, --worktree, --commit, --branch, --stdin, --patch, or file arguments")
 	}
 	if modeCount > 1 {
 		return fmt.Errorf("only one review mode allowed at a time")
 	}
+	if err := validateIncludeUntracked(cmd); err != nil {
+		return err
+	}
+
+	full, _ := cmd.Flags().GetBool("full")
+	if full && len(args) == 0 {
+		return fmt.Errorf("--full requires file arguments")
+	}
+	radius, _ := cmd.Flags().GetInt("context-radius")
+	if radius < 0 {
+		return fmt.Errorf("--context-radius must not be negative")
+	}
+	if radius > 0 && !full {
+		return fmt.Errorf("--context-radius requires --full")
+	}
 
 	// Validate format
 	format, _ := cmd.Flags().GetString("format")
-	validFormats := map[string]bool{"markdown": true, "json": true, "sarif": true}
+	validFormats := map[string]bool{"markdown": true, "json": true, "sarif": true, "pdf": true, "codeactions": true, "dot": true, "mermaid": true}
 	if !validFormats[format] {
-		return fmt.Errorf("invalid format %q, must be: markdown, json, or sarif", format)
+		return fmt.Errorf("invalid format %q, must be: markdown, json, sarif, pdf, codeactions, dot, or mermaid", format)
 	}
 
 	return nil
 }
 
+// countReviewModes returns the number of review modes the flags and
+// arguments select.
+func countReviewModes(cmd *cobra.Command, args []string) int {
+	staged, _ := cmd.Flags().GetBool("staged")
+	worktree, _ := cmd.Flags().GetBool("worktree")
+	commit, _ := cmd.Flags().GetString("commit")
+	branch, _ := cmd.Flags().GetString("branch")
+	stdin, _ := cmd.Flags().GetBool("stdin")
+	patch, _ := cmd.Flags().GetString("patch")
+
+	count := 0
+	for _, selected := range []bool{stdin || patch != "", staged, worktree, commit != "", branch != "", len(args) > 0} {
+		if selected {
+			count++
+		}
+	}
+	return count
+}
+
+// validateIncludeUntracked rejects --include-untracked outside --staged:
+// --worktree and file arguments take the untracked files already.
+func validateIncludeUntracked(cmd *cobra.Command) error {
+	include, _ := cmd.Flags().GetBool("include-untracked")
+	staged, _ := cmd.Flags().GetBool("staged")
+	if include && !staged {
+		return fmt.Errorf("--include-untracked requires --staged; --worktree and file arguments include untracked files already")
+	}
+	return nil
+}
+
 func determineReviewMode(cmd *cobra.Command, args []string) (string, interface{}) {
 	if staged, _ := cmd.Flags().GetBool("staged"); staged {
 		return "staged", nil
 	}
+	if worktree, _ := cmd.Flags().GetBool("worktree"); worktree {
+		return "worktree", nil
+	}
 	if commit, _ := cmd.Flags().GetString("commit"); commit != "" {
 		return "commit", commit
 	}
 	if branch, _ := cmd.Flags().GetString("branch"); branch != "" {
 		return "branch", branch
 	}
+	if stdin, _ := cmd.Flags().GetBool("stdin"); stdin {
+		return "patch", "-"
+	}
+	if patch, _ := cmd.Flags().GetString("patch"); patch != "" {
+		return "patch", patch
+	}
 	if len(args) > 0 {
 		return "files", args
 	}
 	return "staged", nil // Default
 }
 
-func applyFlagOverrides(cmd *cobra.Command, cfg *config.Config, args []string) {
+// errNothingFlagged reports that the report given to --only-flagged has no
+// file to re-review.
+var errNothingFlagged = errors.New("no flagged files")
+
+// applyOnlyFlagged restricts the review to the files of the --from-report
+// report with issues at or above the --only-flagged severity. Without a
+// review mode, their uncommitted changes are reviewed.
+func applyOnlyFlagged(cmd *cobra.Command, cfg *config.Config, args []string) error {
+	path, _ := cmd.Flags().GetString("from-report")
+	if path == "" {
+		return nil
+	}
+	severity, _ := cmd.Flags().GetString("only-flagged")
+	f, err := os.Open(path) // #nosec G304 - user-provided report path
+	if err != nil {
+		return fmt.Errorf("reading report: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	previous, err := reviewtypes.Decode(f)
+	if err != nil {
+		return &failure.ParseError{Code: failure.ParseReport, File: path, Err: fmt.Errorf("parsing report %s: %w", path, err)}
+	}
+
+	flagged := review.FromPublic(previous).FlaggedFiles(severity)
+	if len(flagged) == 0 {
+		return fmt.Errorf("%w: %s has no issues at or above %s", errNothingFlagged, path, severity)
+	}
+	if countReviewModes(cmd, args) == 0 {
+		cfg.Review.Mode = "files"
+		cfg.Review.Files = flagged
+	}
+	cfg.Review.OnlyFiles = flagged
+	return nil
+}
+
+func applyFlagOverrides(cmd *cobra.Command, cfg *config.Config, args []string) error {
 	mode, value := determineReviewMode(cmd, args)
 	cfg.Review.Mode = mode
 
@@ -360,33 +923,110 @@ func applyFlagOverrides(cmd *cobra.Command, cfg *config.Config, args []string) {
 		cfg.Review.Files = value.([]string)
 	}
 
-	if provider, _ := cmd.Flags().GetString("provider"); provider != "" {
-		cfg.Provider.Name = provider
+	if err := applyOnlyFlagged(cmd, cfg, args); err != nil {
+		return err
 	}
-	if model, _ := cmd.Flags().GetString("model"); model != "" {
-		cfg.Provider.Model = model
+	if err := applyProviderFlags(cmd, cfg); err != nil {
+		return err
 	}
 	if concurrency, _ := cmd.Flags().GetInt("concurrency"); concurrency > 0 {
 		cfg.Review.MaxConcurrency = concurrency
 	}
-	if personality, _ := cmd.Flags().GetString("personality"); personality != "" {
-		cfg.Review.Personality = personality
+	if budget, _ := cmd.Flags().GetDuration("time-budget"); budget > 0 {
+		cfg.Review.TimeBudget = budget
+	}
+	if budget, _ := cmd.Flags().GetInt("token-budget"); budget > 0 {
+		cfg.Review.TokenBudget = budget
+	}
+	if shard, _ := cmd.Flags().GetString("shard"); shard != "" {
+		if _, _, err := config.ParseShard(shard); err != nil {
+			return err
+		}
+		cfg.Review.Shard = shard
+	}
+	if incremental, _ := cmd.Flags().GetBool("incremental"); incremental {
+		cfg.Review.Incremental = true
+	}
+	if full, _ := cmd.Flags().GetBool("full"); full {
+		cfg.Review.Full = true
+	}
+	if include, _ := cmd.Flags().GetBool("include-untracked"); include {
+		cfg.Review.IncludeUntracked = true
+	}
+	if prContext, _ := cmd.Flags().GetBool("pr-context"); prContext {
+		cfg.Review.PRContext = true
 	}
-	if mode, _ := cmd.Flags().GetString("mode"); mode != "" {
+	if radius, _ := cmd.Flags().GetInt("context-radius"); radius > 0 {
+		cfg.Review.ContextRadius = radius
+	}
+	if sizeImpact, _ := cmd.Flags().GetBool("size-impact"); sizeImpact {
+		cfg.Review.SizeImpact.Enabled = true
+	}
+	if policy, _ := cmd.Flags().GetString("generated-policy"); policy != "" {
+		if policy != config.GeneratedPolicyLabel && policy != config.GeneratedPolicyStrict {
+			return fmt.Errorf("invalid --generated-policy %q, must be: label or strict", policy)
+		}
+		cfg.Review.Generated.Enabled = true
+		cfg.Review.Generated.Policy = policy
+	}
+	if strict, _ := cmd.Flags().GetBool("strict-scope"); strict {
+		cfg.Review.Scope.Strict = true
+	}
+	if adaptive, _ := cmd.Flags().GetBool("adaptive-concurrency"); adaptive {
+		cfg.Review.AdaptiveConcurrency.Enabled = true
+	}
+	// Flags left at their defaults keep the config values, which a profile
+	// may set
+	if mode, _ := cmd.Flags().GetString("mode"); cmd.Flags().Changed("mode") {
 		cfg.Review.Modes = mode
 	}
+	applyReportFlags(cmd, cfg)
+	if policy, _ := cmd.Flags().GetString("conformance"); policy != "" {
+		cfg.Review.ConformancePolicy = policy
+	}
 	if trace, _ := cmd.Flags().GetBool("trace"); trace {
 		cfg.Review.RootCauseTracing = true
 	}
+	if failOn, _ := cmd.Flags().GetString("fail-on"); failOn != "" {
+		cfg.Review.FailOn = failOn
+	}
+	if minSeverity, _ := cmd.Flags().GetString("min-severity"); minSeverity != "" {
+		cfg.Review.MinSeverity = minSeverity
+	}
 
 	// Include/exclude patterns
 	if includes, _ := cmd.Flags().GetStringSlice("include"); len(includes) > 0 {
-		// Store in config for later use
-		_ = includes
+		cfg.Git.IncludePatterns = append(cfg.Git.IncludePatterns, includes...)
 	}
 	if excludes, _ := cmd.Flags().GetStringSlice("exclude"); len(excludes) > 0 {
 		cfg.Git.IgnorePatterns = append(cfg.Git.IgnorePatterns, excludes...)
 	}
+	if symbols, _ := cmd.Flags().GetStringSlice("focus-symbols"); len(symbols) > 0 {
+		cfg.Review.FocusSymbols = symbols
+	}
+	return nil
+}
+
+// applyReportFlags applies --preset, --format and --output when given, so
+// that the config values, which a profile may set, apply otherwise.
+func applyReportFlags(cmd *cobra.Command, cfg *config.Config) {
+	if preset, _ := cmd.Flags().GetString("preset"); cmd.Flags().Changed("preset") {
+		cfg.Rules.Preset = preset
+	}
+	if format, _ := cmd.Flags().GetString("format"); cmd.Flags().Changed("format") {
+		cfg.Output.Format = format
+	}
+	if output, _ := cmd.Flags().GetString("output"); cmd.Flags().Changed("output") {
+		cfg.Output.File = output
+	}
+}
+
+// useReportFlags makes a command other than review write its report as
+// --format to --output, which default to markdown on stdout whatever
+// output.format and output.file say: those configure review reports.
+func useReportFlags(cmd *cobra.Command, cfg *config.Config) {
+	cfg.Output.Format, _ = cmd.Flags().GetString("format")
+	cfg.Output.File, _ = cmd.Flags().GetString("output")
 }
 
 // checkTestCoverage ensures all reviewed files have corresponding tests
@@ -419,36 +1059,7 @@ func checkTestCoverage(result *review.Result) error {
 
 // isTestFile checks if the file is a test file
 func isTestFile(path string) bool {
-	base := filepath.Base(path)
-	ext := filepath.Ext(path)
-	nameWithoutExt := strings.TrimSuffix(base, ext)
-
-	// Go tests
-	if strings.HasSuffix(base, "_test.go") {
-		return true
-	}
-
-	// JavaScript/TypeScript tests
-	jsTestSuffixes := []string{".test", ".spec", "_test", "_spec"}
-	for _, suffix := range jsTestSuffixes {
-		if strings.HasSuffix(nameWithoutExt, suffix) {
-			return true
-		}
-	}
-
-	// Test directories - normalize path separators for cross-platform
-	normalizedPath := filepath.ToSlash(path)
-	testDirs := []string{"test", "tests", "__tests__", "spec", "specs"}
-	for _, d := range testDirs {
-		// Check if directory is in path
-		if strings.Contains(normalizedPath, "/"+d+"/") ||
-			strings.HasPrefix(normalizedPath, d+"/") ||
-			strings.Contains(normalizedPath, d+"/") {
-			return true
-		}
-	}
-
-	return false
+	return testcheck.IsTestFile(path)
 }
 
 // hasCorrespondingTest checks if a source file has a corresponding test file
@@ -466,57 +1077,7 @@ func hasCorrespondingTest(path string) bool {
 
 // getTestPathVariants returns possible test file paths for a source file
 func getTestPathVariants(path string) []string {
-	dir := filepath.Dir(path)
-	base := filepath.Base(path)
-	ext := filepath.Ext(path)
-	nameWithoutExt := strings.TrimSuffix(base, ext)
-
-	var variants []string
-
-	switch ext {
-	case ".go":
-		// Go: file.go -> file_test.go
-		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test.go"))
-
-	case ".js", ".jsx", ".ts", ".tsx":
-		// JS/TS: file.js -> file.test.js, file.spec.js
-		variants = append(variants, filepath.Join(dir, nameWithoutExt+".test"+ext))
-		variants = append(variants, filepath.Join(dir, nameWithoutExt+".spec"+ext))
-		variants = append(variants, filepath.Join(dir, "__tests__", base))
-		// Also check for .ts tests if .js file
-		if ext == ".js" || ext == ".jsx" {
-			tsExt := strings.Replace(ext, ".js", ".ts", 1)
-			variants = append(variants, filepath.Join(dir, nameWithoutExt+".test"+tsExt))
-			variants = append(variants, filepath.Join(dir, nameWithoutExt+".spec"+tsExt))
-		}
-
-	case ".py":
-		// Python: file.py -> test_file.py, file_test.py
-		variants = append(variants, filepath.Join(dir, "test_"+base))
-		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test.py"))
-		variants = append(variants, filepath.Join(dir, "tests", "test_"+base))
-
-	case ".java":
-		// Java: File.java -> FileTest.java
-		variants = append(variants, filepath.Join(dir, nameWithoutExt+"Test.java"))
-		variants = append(variants, strings.Replace(path, "/main/", "/test/", 1))
-
-	case ".rs":
-		// Rust: usually in same file or mod tests
-		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test.rs"))
-
-	case ".rb":
-		// Ruby: file.rb -> file_spec.rb, file_test.rb
-		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_spec.rb"))
-		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test.rb"))
-		variants = append(variants, filepath.Join(dir, "spec", base))
-
-	default:
-		// Generic: try _test suffix
-		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test"+ext))
-	}
-
-	return variants
+	return testcheck.TestPaths(path)
 }
 
 // getExpectedTestPath returns the most likely expected test path
@@ -528,35 +1089,33 @@ func getExpectedTestPath(path string) string {
 	return path + " (test file)"
 }
 
-// exportToObsidian exports the review result to an Obsidian vault
-func exportToObsidian(ctx context.Context, cmd *cobra.Command, cfg *config.Config, result *review.Result) error {
+// runExports runs the exporters enabled in config or requested by flags and
+// prints a summary of their outcomes to stderr
+func runExports(ctx context.Context, cmd *cobra.Command, cfg *config.Config, result *review.Result) {
 	// Override vault path from flag if provided
 	if vaultPath, _ := cmd.Flags().GetString("obsidian-vault"); vaultPath != "" {
 		cfg.Export.Obsidian.VaultPath = vaultPath
 	}
 
-	if cfg.Export.Obsidian.VaultPath == "" {
-		return fmt.Errorf("obsidian vault path not configured (use --obsidian-vault or config)")
+	names := export.EnabledNames(&cfg.Export)
+	requested, _ := cmd.Flags().GetStringSlice("export")
+	names = append(names, requested...)
+	if exportObsidian, _ := cmd.Flags().GetBool("export-obsidian"); exportObsidian {
+		names = append(names, "obsidian")
 	}
 
-	// Create exporter
-	exporter, err := export.NewObsidianExporter(&cfg.Export.Obsidian)
-	if err != nil {
-		return err
+	pipeline := export.NewPipeline(&cfg.Export, names)
+	if pipeline.Empty() {
+		return
 	}
+	summary := pipeline.Run(result, buildExportMetadata(ctx, cfg))
 
-	// Build metadata
-	metadata := buildExportMetadata(ctx, cfg)
-
-	// Export
-	if err := exporter.Export(result, metadata); err != nil {
-		return err
+	// Keep stderr machine-readable when the report itself is JSON
+	if cfg.Output.Format == "json" {
+		_ = summary.WriteJSON(os.Stderr)
+	} else {
+		summary.WriteText(os.Stderr)
 	}
-
-	outputPath := exporter.GetOutputPath(metadata)
-	fmt.Fprintf(os.Stderr, "Exported to Obsidian: %s\n", outputPath)
-
-	return nil
 }
 
 // buildExportMetadata builds metadata for the export
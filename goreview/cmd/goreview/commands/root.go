@@ -10,17 +10,31 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/failure"
 )
 
 var (
 	// cfgFile holds the path to the config file (from --config flag)
 	cfgFile string
 
+	// cfgProfile selects a named profile from the config file (from --profile flag)
+	cfgProfile string
+
 	// verbose enables detailed output
 	verbose bool
 
 	// quiet suppresses all output except errors
 	quiet bool
+
+	// recordDir and replayDir record provider answers or replay them
+	// (from --record and --replay)
+	recordDir string
+	replayDir string
+
+	// strictConfig fails on unknown config keys (from --strict-config)
+	strictConfig bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -46,7 +60,11 @@ Examples:
   goreview commit
 
   # Show current configuration
-  goreview config show`,
+  goreview config show
+
+  # Use another config file or a named profile
+  goreview review --config ~/work.goreview.yaml
+  goreview review --profile oss`,
 
 	// SilenceUsage prevents printing usage on errors
 	// We want clean error messages, not the full help text
@@ -64,15 +82,24 @@ Examples:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Errors are printed on stderr with their code, see internal/failure.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error [%s]: %v\n", failure.CodeOf(err), err)
+	}
+	return err
 }
 
 func init() {
 	// Persistent flags are available to this command and all subcommands
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is .goreview.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgProfile, "profile", "", "config profile to apply (from the profiles section of the config file)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress all output except errors")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "record provider answers to this directory, keyed by prompt hash")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "answer from the provider recordings in this directory, without network")
+	rootCmd.PersistentFlags().BoolVar(&strictConfig, "strict-config", false, "fail on unknown keys in the config file instead of warning")
 
 	// Bind flags to viper for config file support
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
@@ -114,6 +141,52 @@ func initializeConfig() error {
 	return nil
 }
 
+// newConfigLoader returns a config loader honoring the global --config,
+// --profile and --strict-config flags.
+func newConfigLoader() *config.Loader {
+	loader := config.NewLoader()
+	loader.SetStrict(strictConfig)
+	loader.SetWarn(func(msg string) {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		}
+	})
+	if cfgFile != "" {
+		loader.SetConfigFile(cfgFile)
+	}
+	if cfgProfile != "" {
+		loader.SetProfile(cfgProfile)
+	}
+	return loader
+}
+
+// loadConfig loads the configuration honoring the global --config,
+// --profile, --record and --replay flags.
+func loadConfig() (*config.Config, error) {
+	cfg, err := newConfigLoader().Load()
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCassetteFlags(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyCassetteFlags applies --record and --replay, which take precedence
+// over provider.record and provider.replay.
+func applyCassetteFlags(cfg *config.Config) error {
+	switch {
+	case recordDir != "" && replayDir != "":
+		return fmt.Errorf("--record and --replay are mutually exclusive")
+	case recordDir != "":
+		cfg.Provider.Record, cfg.Provider.Replay = recordDir, ""
+	case replayDir != "":
+		cfg.Provider.Record, cfg.Provider.Replay = "", replayDir
+	}
+	return nil
+}
+
 // isVerbose returns true if verbose mode is enabled
 func isVerbose() bool {
 	return verbose && !quiet
@@ -0,0 +1,379 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage review rules and rule packs",
+	Long: `List review rules and manage remote rule packs.
+
+Rule packs are declared in .goreview.yaml under rules.packs and pinned
+to a version (and optionally a sha256 checksum):
+
+  rules:
+    packs:
+      - name: company
+        source: https://rules.example.com/goreview/{version}/rules.yaml
+        version: v1.4.0
+        checksum: sha256:9f86d081884c7d659a2feaa0c55ad015...
+      - name: security
+        source: git+https://github.com/example/goreview-rules.git
+        version: v2.0.1
+        path: packs/security.yaml`,
+}
+
+var rulesUpdateCmd = &cobra.Command{
+	Use:   "update [pack...]",
+	Short: "Fetch configured rule packs",
+	Long: `Fetch the rule packs configured in rules.packs, verify their
+checksums and store them in the local pack cache.
+
+Examples:
+  # Update all packs
+  goreview rules update
+
+  # Update a single pack
+  goreview rules update company`,
+	RunE: runRulesUpdate,
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available rules",
+	Long: `List the rules available to reviews: built-in rules, custom rules
+from rules_dir, and installed rule packs.
+
+Examples:
+  # List all rules
+  goreview rules list
+
+  # List installed rule packs instead of rules
+  goreview rules list --packs`,
+	RunE: runRulesList,
+}
+
+var rulesShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a rule's details",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRulesShow,
+}
+
+var rulesNewCmd = &cobra.Command{
+	Use:   "new <id>",
+	Short: "Scaffold a custom rule with test fixtures",
+	Long: `Create a commented rule YAML with prompt guidance and matchers,
+plus a fixture spec and empty good/bad fixture files for "rules test".
+
+Examples:
+  # Scaffold a Go rule in the configured rules_dir
+  goreview rules new DB-001 --name "No raw SQL concatenation" --lang go
+
+  # Scaffold into a specific directory
+  goreview rules new SEC-100 --dir ./rule-packs/security --severity critical`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRulesNew,
+}
+
+var rulesTestCmd = &cobra.Command{
+	Use:   "test <spec.yaml>...",
+	Short: "Run rules against fixture files",
+	Long: `Run rules against fixture files and check the expected findings
+declared in a fixture spec (see "goreview rules new").
+
+Scope assertions (languages, path globs, conditions) are always checked.
+Finding assertions call the configured provider unless --scope-only is set.
+
+Examples:
+  # Run all assertions
+  goreview rules test rules/db-001.test.yaml
+
+  # Only check scoping (no provider calls, deterministic for CI)
+  goreview rules test --scope-only rules/*.test.yaml`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRulesTest,
+}
+
+var (
+	rulesListPacks bool
+	rulesJSON      bool
+
+	rulesNewName     string
+	rulesNewCategory string
+	rulesNewSeverity string
+	rulesNewLangs    []string
+	rulesNewDir      string
+	rulesNewForce    bool
+
+	rulesTestScopeOnly bool
+)
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesUpdateCmd)
+	rulesCmd.AddCommand(rulesListCmd)
+	rulesCmd.AddCommand(rulesShowCmd)
+
+	rulesCmd.AddCommand(rulesNewCmd)
+	rulesCmd.AddCommand(rulesTestCmd)
+
+	rulesListCmd.Flags().BoolVar(&rulesListPacks, "packs", false, "list installed rule packs")
+
+	rulesNewCmd.Flags().StringVar(&rulesNewName, "name", "", "rule name (default: the rule ID)")
+	rulesNewCmd.Flags().StringVar(&rulesNewCategory, "category", string(rules.CategoryBestPractice), "rule category")
+	rulesNewCmd.Flags().StringVar(&rulesNewSeverity, "severity", string(rules.SeverityWarning), "rule severity")
+	rulesNewCmd.Flags().StringSliceVar(&rulesNewLangs, "lang", nil, "languages the rule applies to")
+	rulesNewCmd.Flags().StringVar(&rulesNewDir, "dir", "", "output directory (default: rules.rules_dir or current directory)")
+	rulesNewCmd.Flags().BoolVar(&rulesNewForce, "force", false, "overwrite existing files")
+
+	rulesTestCmd.Flags().BoolVar(&rulesTestScopeOnly, "scope-only", false, "only check scope assertions, don't call the provider")
+	rulesCmd.PersistentFlags().BoolVar(&rulesJSON, "json", false, "output as JSON")
+}
+
+func runRulesUpdate(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	specs := packSpecs(cfg)
+	if len(specs) == 0 {
+		fmt.Println("No rule packs configured (rules.packs).")
+		return nil
+	}
+
+	selected, err := selectPacks(specs, args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	registry := rules.NewRegistry(cfg.Rules.PacksDir)
+	var failed int
+	for _, spec := range selected {
+		info, err := registry.Update(ctx, spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %s: %v\n", spec.Name, err)
+			failed++
+			continue
+		}
+		if !isQuiet() {
+			fmt.Printf("✓ %s@%s (%d rules, %s)\n", info.Name, info.Version, info.RuleCount, info.Checksum)
+		}
+		if spec.Checksum == "" && !isQuiet() {
+			fmt.Printf("  pin with: checksum: %s\n", info.Checksum)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rule packs failed to update", failed, len(selected))
+	}
+	return nil
+}
+
+func runRulesList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if rulesListPacks {
+		return listInstalledPacks(cfg)
+	}
+
+	allRules, err := loadAllRules(cfg)
+	if err != nil {
+		return err
+	}
+	sort.Slice(allRules, func(i, j int) bool { return allRules[i].ID < allRules[j].ID })
+
+	if rulesJSON {
+		return printJSON(allRules)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSEVERITY\tCATEGORY\tSOURCE\tENABLED\tNAME")
+	for _, r := range allRules {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\t%s\n", r.ID, r.Severity, r.Category, ruleSource(r), r.Enabled, r.Name)
+	}
+	return w.Flush()
+}
+
+func runRulesShow(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	allRules, err := loadAllRules(cfg)
+	if err != nil {
+		return err
+	}
+
+	id := args[0]
+	for _, r := range allRules {
+		if !strings.EqualFold(r.ID, id) {
+			continue
+		}
+		if rulesJSON {
+			return printJSON(r)
+		}
+		printRule(r)
+		return nil
+	}
+	return fmt.Errorf("rule not found: %s", id)
+}
+
+func listInstalledPacks(cfg *config.Config) error {
+	infos, err := rules.NewRegistry(cfg.Rules.PacksDir).Installed()
+	if err != nil {
+		return fmt.Errorf("listing packs: %w", err)
+	}
+
+	if rulesJSON {
+		return printJSON(infos)
+	}
+	if len(infos) == 0 {
+		fmt.Println("No rule packs installed. Run 'goreview rules update'.")
+		return nil
+	}
+
+	pinned := make(map[string]string)
+	for _, spec := range packSpecs(cfg) {
+		pinned[spec.Name] = spec.Version
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tRULES\tFETCHED\tSTATUS")
+	for _, info := range infos {
+		status := "unused"
+		if v, ok := pinned[info.Name]; ok {
+			status = "stale"
+			if v == info.Version || (v == "" && info.Version == "latest") {
+				status = "active"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", info.Name, info.Version, info.RuleCount, info.FetchedAt.Format(dateTimeFormat), status)
+	}
+	return w.Flush()
+}
+
+func printRule(r rules.Rule) {
+	fmt.Printf("%s: %s\n", r.ID, r.Name)
+	fmt.Printf("  Severity:    %s\n", r.Severity)
+	fmt.Printf("  Category:    %s\n", r.Category)
+	fmt.Printf("  Source:      %s\n", ruleSource(r))
+	fmt.Printf("  Enabled:     %v\n", r.Enabled)
+	if len(r.Languages) > 0 {
+		fmt.Printf("  Languages:   %s\n", strings.Join(r.Languages, ", "))
+	}
+	if len(r.Patterns) > 0 {
+		fmt.Printf("  Patterns:    %s\n", strings.Join(r.Patterns, ", "))
+	}
+	if len(r.Excludes) > 0 {
+		fmt.Printf("  Excludes:    %s\n", strings.Join(r.Excludes, ", "))
+	}
+	if r.When != nil && len(r.When.Imports) > 0 {
+		fmt.Printf("  When import: %s\n", strings.Join(r.When.Imports, ", "))
+	}
+	if r.When != nil && len(r.When.Contains) > 0 {
+		fmt.Printf("  When match:  %s\n", strings.Join(r.When.Contains, ", "))
+	}
+	if r.Description != "" {
+		fmt.Printf("\n%s\n", r.Description)
+	}
+	if r.Message != "" {
+		fmt.Printf("\nMessage:    %s\n", r.Message)
+	}
+	if r.Suggestion != "" {
+		fmt.Printf("Suggestion: %s\n", r.Suggestion)
+	}
+}
+
+func ruleSource(r rules.Rule) string {
+	if r.Pack != "" {
+		return "pack:" + r.Pack
+	}
+	return "local"
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// loadAllRules loads built-in, custom and installed pack rules.
+// Pack rules override local rules with the same ID.
+func loadAllRules(cfg *config.Config) ([]rules.Rule, error) {
+	local, err := rules.NewLoader(cfg.Rules.RulesDir).Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading rules: %w", err)
+	}
+
+	specs := packSpecs(cfg)
+	if len(specs) == 0 {
+		return local, nil
+	}
+
+	packRules, warnings := rules.NewRegistry(cfg.Rules.PacksDir).LoadAll(specs)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", w)
+	}
+	return rules.MergeRuleSets(local, packRules), nil
+}
+
+// packSpecs converts configured rule packs to registry specs.
+func packSpecs(cfg *config.Config) []rules.PackSpec {
+	specs := make([]rules.PackSpec, 0, len(cfg.Rules.Packs))
+	for _, p := range cfg.Rules.Packs {
+		specs = append(specs, rules.PackSpec{
+			Name:     p.Name,
+			Source:   p.Source,
+			Version:  p.Version,
+			Checksum: p.Checksum,
+			Path:     p.Path,
+		})
+	}
+	return specs
+}
+
+func selectPacks(specs []rules.PackSpec, names []string) ([]rules.PackSpec, error) {
+	if len(names) == 0 {
+		return specs, nil
+	}
+
+	byName := make(map[string]rules.PackSpec, len(specs))
+	for _, s := range specs {
+		byName[s.Name] = s
+	}
+
+	selected := make([]rules.PackSpec, 0, len(names))
+	for _, name := range names {
+		spec, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown rule pack: %s", name)
+		}
+		selected = append(selected, spec)
+	}
+	return selected, nil
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+func runRulesNew(cmd *cobra.Command, args []string) error {
+	id := strings.ToUpper(args[0])
+
+	dir := rulesNewDir
+	if dir == "" {
+		dir = "."
+		if cfg, err := loadConfig(); err == nil && cfg.Rules.RulesDir != "" {
+			dir = cfg.Rules.RulesDir
+		}
+	}
+
+	ruleYAML, err := rules.ScaffoldRule(rules.ScaffoldOptions{
+		ID:        id,
+		Name:      rulesNewName,
+		Category:  rules.Category(rulesNewCategory),
+		Severity:  rules.Severity(rulesNewSeverity),
+		Languages: rulesNewLangs,
+	})
+	if err != nil {
+		return err
+	}
+
+	base := rules.FixtureBase(id)
+	ruleFile := base + ".yaml"
+	specFile := base + ".test.yaml"
+	ext := ".txt"
+	if len(rulesNewLangs) > 0 {
+		if e := git.LanguageExtension(rulesNewLangs[0]); e != "" {
+			ext = e
+		}
+	}
+
+	specYAML, err := rules.ScaffoldTest(id, ruleFile, filepath.Join(dir, specFile), ext)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		path    string
+		content []byte
+	}{
+		{filepath.Join(dir, ruleFile), ruleYAML},
+		{filepath.Join(dir, specFile), specYAML},
+		{filepath.Join(dir, "testdata", base+"_bad"+ext), nil},
+		{filepath.Join(dir, "testdata", base+"_good"+ext), nil},
+	}
+
+	for _, f := range files {
+		if _, err := os.Stat(f.path); err == nil && !rulesNewForce {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", f.path)
+		}
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0750); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.path, f.content, 0600); err != nil {
+			return err
+		}
+		if !isQuiet() {
+			fmt.Printf("Created %s\n", f.path)
+		}
+	}
+
+	if !isQuiet() {
+		fmt.Printf("\nNext: fill in the prompt and fixtures, then run:\n  goreview rules test %s\n", filepath.Join(dir, specFile))
+	}
+	return nil
+}
+
+func runRulesTest(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	var review rules.ReviewFunc
+	if !rulesTestScopeOnly {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		provider, err := providers.NewProvider(cfg)
+		if err != nil {
+			return fmt.Errorf("initializing provider: %w", err)
+		}
+		defer func() { _ = provider.Close() }()
+		review = providerRuleReview(provider)
+	}
+
+	var passed, failed, skipped int
+	for _, path := range args {
+		spec, specRules, err := rules.LoadTestSpec(path)
+		if err != nil {
+			return err
+		}
+
+		if !isQuiet() {
+			fmt.Printf("%s\n", path)
+		}
+		for _, res := range rules.RunTests(ctx, spec, specRules, git.DetectLanguage, review) {
+			switch {
+			case !res.Passed():
+				failed++
+				fmt.Printf("  FAIL %s [%s]\n", res.Name, res.Rule)
+				for _, f := range res.Failures {
+					fmt.Printf("       %s\n", f)
+				}
+			case res.Skipped:
+				skipped++
+				if !isQuiet() {
+					fmt.Printf("  ok   %s [%s] (scope only)\n", res.Name, res.Rule)
+				}
+			default:
+				passed++
+				if !isQuiet() {
+					fmt.Printf("  ok   %s [%s]\n", res.Name, res.Rule)
+				}
+			}
+		}
+	}
+
+	if !isQuiet() {
+		fmt.Printf("\n%d passed, %d scope-only, %d failed\n", passed, skipped, failed)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d rule test(s) failed", failed)
+	}
+	return nil
+}
+
+// providerRuleReview reviews a whole fixture file against a single rule.
+func providerRuleReview(provider providers.Provider) rules.ReviewFunc {
+	return func(ctx context.Context, file *rules.FileContext, rule rules.Rule) ([]rules.Finding, error) {
+		resp, err := provider.Review(ctx, &providers.ReviewRequest{
+			Diff:        fixtureDiff(file.Content),
+			Language:    file.Language,
+			FilePath:    file.Path,
+			FileContent: file.Content,
+			Rules:       []string{rule.Guidance()},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var findings []rules.Finding
+		for _, issue := range resp.Issues {
+			// Only one rule is in the prompt, so untagged issues count too
+			if issue.RuleID != "" && !strings.EqualFold(issue.RuleID, rule.ID) {
+				continue
+			}
+			f := rules.Finding{Message: issue.Message}
+			if issue.Location != nil {
+				f.StartLine, f.EndLine = issue.Location.StartLine, issue.Location.EndLine
+			}
+			findings = append(findings, f)
+		}
+		return findings, nil
+	}
+}
+
+// fixtureDiff presents a fixture as a newly added file so line numbers match.
+func fixtureDiff(content string) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "@@ -0,0 +1,%d @@\n", len(lines))
+	for _, l := range lines {
+		sb.WriteString("+")
+		sb.WriteString(l)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
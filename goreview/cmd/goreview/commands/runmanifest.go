@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/manifest"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// startManifest starts recording the run manifest when --manifest is set.
+// It returns nil otherwise, which records nothing.
+func startManifest(cmd *cobra.Command, args []string) *manifest.Recorder {
+	if path, _ := cmd.Flags().GetString("manifest"); path == "" {
+		return nil
+	}
+	return manifest.NewRecorder(Version, args)
+}
+
+// writeManifest writes the run manifest, if one is being recorded. A
+// failure to write it is reported but doesn't fail the review.
+func writeManifest(cmd *cobra.Command, rec *manifest.Recorder, result *review.Result, runErr error) {
+	if rec == nil {
+		return
+	}
+	path, _ := cmd.Flags().GetString("manifest")
+	if err := rec.Write(path, result, runErr); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if isVerbose() {
+		fmt.Fprintf(os.Stderr, "Run manifest written to %s\n", path)
+	}
+}
+
+// manifestRepository identifies the repository in the current directory.
+func manifestRepository(ctx context.Context) manifest.Repository {
+	gitRepo, err := git.NewRepo(".")
+	if err != nil {
+		return manifest.Repository{}
+	}
+	branch, _ := gitRepo.GetCurrentBranch(ctx)
+	repo := manifest.Repository{
+		Branch: branch,
+		Commit: getGitCommitHash(),
+	}
+	if author, err := runGitCommand("log", "-1", "--format=%an <%ae>"); err == nil {
+		repo.Author = strings.TrimSpace(author)
+	}
+	if root, err := gitRepo.GetRepoRoot(ctx); err == nil {
+		repo.Name = filepath.Base(root)
+	}
+	if remote, err := runGitCommand("remote", "get-url", "origin"); err == nil {
+		repo.Remote = redactRemote(strings.TrimSpace(remote))
+	}
+	return repo
+}
+
+// ciActorVars name the user who triggered a CI run, by CI system
+var ciActorVars = []string{"GITHUB_ACTOR", "GITLAB_USER_LOGIN", "BUILD_REQUESTEDFOREMAIL", "BITBUCKET_STEP_TRIGGERER_UUID", "CIRCLE_USERNAME", "BUILDKITE_BUILD_CREATOR"}
+
+// manifestRunBy identifies who ran the review: the user who triggered the
+// CI run, or the git user when run locally.
+func manifestRunBy() string {
+	for _, name := range ciActorVars {
+		if actor := os.Getenv(name); actor != "" {
+			return actor
+		}
+	}
+	if email, err := runGitCommand("config", "user.email"); err == nil && strings.TrimSpace(email) != "" {
+		name, _ := runGitCommand("config", "user.name")
+		if name = strings.TrimSpace(name); name != "" {
+			return fmt.Sprintf("%s <%s>", name, strings.TrimSpace(email))
+		}
+		return strings.TrimSpace(email)
+	}
+	return os.Getenv("USER")
+}
+
+// redactRemote strips the credentials CI systems often embed in HTTPS
+// remotes. SSH remotes carry none.
+func redactRemote(remote string) string {
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil {
+		return remote
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/JNZader/goreview/goreview/internal/scope"
+)
+
+// checkScope fails the review, in strict scope mode, when the change
+// bundles more unrelated file categories than allowed, printing how to
+// split it.
+func checkScope(report *scope.Report, maxUnrelated int) error {
+	if report == nil || report.Unrelated <= maxUnrelated {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "\n❌ Scope: the change bundles %d unrelated file categories (max %d)\n", report.Unrelated, maxUnrelated)
+	for _, s := range report.Suggestions {
+		fmt.Fprintf(os.Stderr, "   • %s\n", s)
+	}
+	fmt.Fprintln(os.Stderr)
+	return fmt.Errorf("--strict-scope: %d unrelated file categories, max %d", report.Unrelated, maxUnrelated)
+}
@@ -54,7 +54,7 @@ func init() {
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadDefault()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
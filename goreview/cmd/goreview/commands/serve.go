@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/cache"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/memory"
+	"github.com/JNZader/goreview/goreview/internal/progress"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+	"github.com/JNZader/goreview/goreview/internal/server"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve reviews over HTTP to several teams",
+	Long: `Start an HTTP service reviewing unified diffs for the tenants in
+serve.tenants.
+
+Every request authenticates with one of its tenant's API keys, as a bearer
+token or in the X-API-Key header. Each tenant reviews with its own profile
+(provider credentials, rules), keeps its history, cache and memory in its own
+directory under serve.data_dir, and is held to its own request quota.
+
+Endpoints:
+  GET  /healthz          Liveness, without authentication
+  GET  /v1/tenant        The calling tenant and its quota usage
+  POST /v1/review        Review the unified diff in the body (or in "diff" of
+                         a JSON body), returning the JSON report
+  POST /v1/reviews       Queue a review of the diff, returning its job ID
+  GET  /v1/reviews/{id}  The job's status, progress and report; streamed as
+                         server-sent events with Accept: text/event-stream
+
+Queued reviews are kept in serve.data_dir/jobs.db and run on serve.workers
+workers, so reviews queued or running when the service stops run on restart.
+
+Examples:
+  goreview serve --addr :8080
+
+  git diff main | curl -s -H "Authorization: Bearer $KEY" \
+    --data-binary @- http://localhost:8080/v1/review
+
+  # Queue a big review and follow it
+  git diff main | curl -s -H "Authorization: Bearer $KEY" \
+    --data-binary @- http://localhost:8080/v1/reviews
+  curl -N -H "Authorization: Bearer $KEY" -H "Accept: text/event-stream" \
+    http://localhost:8080/v1/reviews/<id>`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", "", "Address to listen on (overrides serve.addr)")
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if addr, _ := cmd.Flags().GetString("addr"); addr != "" {
+		cfg.Serve.Addr = addr
+	}
+	if len(cfg.Serve.Tenants) == 0 {
+		return fmt.Errorf("no tenants configured (serve.tenants)")
+	}
+
+	tenants := make([]*server.Tenant, 0, len(cfg.Serve.Tenants))
+	for _, tc := range cfg.Serve.Tenants {
+		loader := newConfigLoader()
+		if tc.Profile != "" {
+			loader.SetProfile(tc.Profile)
+		}
+		tenantCfg, err := loader.Load()
+		if err != nil {
+			return fmt.Errorf("loading config of tenant %s: %w", tc.ID, err)
+		}
+		tenantCfg.Review.Mode = "patch"
+		t, err := server.NewTenant(tc, tenantCfg, cfg.Serve.DataDir)
+		if err != nil {
+			return err
+		}
+		tenants = append(tenants, t)
+	}
+
+	if err := os.MkdirAll(cfg.Serve.DataDir, 0750); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	jobs, err := server.OpenJobStore(filepath.Join(cfg.Serve.DataDir, "jobs.db"))
+	if err != nil {
+		return err
+	}
+	defer jobs.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	closeMemory := scheduleMemory(ctx, tenants)
+	defer closeMemory()
+
+	queue := server.NewQueue(jobs, tenants, serveReview, cfg.Serve.Workers)
+	queue.Start(ctx)
+	defer func() {
+		stop() // Also stops the workers when serving fails
+		queue.Wait()
+	}()
+
+	api := server.New(tenants, serveReview, cfg.Serve.MaxBodyBytes)
+	api.UseQueue(queue)
+	srv := &http.Server{
+		Addr:              cfg.Serve.Addr,
+		Handler:           api.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "GoReview serving %d tenants on %s\n", len(tenants), cfg.Serve.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, "GoReview server stopped")
+	return nil
+}
+
+// scheduleMemory consolidates the memory of the tenants with memory.enabled
+// and runs its maintenance every memory.maintenance_interval until ctx is
+// done, returning a function closing their memory once ctx is done.
+func scheduleMemory(ctx context.Context, tenants []*server.Tenant) func() {
+	var wg sync.WaitGroup
+	var stores []*memory.Store
+	for _, t := range tenants {
+		mc := t.Config.Memory
+		if !mc.Enabled || mc.MaintenanceInterval <= 0 {
+			continue
+		}
+		store, err := memory.NewStore(mc)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: memory of tenant %s not maintained: %v\n", t.ID, err)
+			continue
+		}
+		stores = append(stores, store)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Schedule(ctx, mc.MaintenanceInterval, func(err error) {
+				fmt.Fprintf(os.Stderr, "Warning: memory maintenance of tenant %s: %v\n", t.ID, err)
+			})
+		}()
+	}
+	return func() {
+		wg.Wait()
+		for _, store := range stores {
+			_ = store.Close()
+		}
+	}
+}
+
+// serveReview reviews a diff submitted to the server with the tenant's
+// configuration, recording triage in the tenant's history database.
+func serveReview(ctx context.Context, t *server.Tenant, diff *git.Diff, tracker *progress.Tracker) (*review.Result, error) {
+	cfg := *t.Config // The review records its settings in the config
+
+	provider, err := providers.NewProvider(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("initializing provider: %w", err)
+	}
+	defer func() { _ = provider.Close() }()
+
+	var reviewCache cache.Cache
+	if cfg.Cache.Enabled {
+		if fc, err := cache.NewFileCache(reviewCacheDir(&cfg), cfg.Cache.TTL); err == nil {
+			defer func() { _ = fc.Close() }()
+			reviewCache = fc
+		}
+	}
+
+	allRules, err := loadAllRules(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	preset, err := rules.NewLoader(cfg.Rules.RulesDir).LoadPreset(cfg.Rules.Preset)
+	if err != nil {
+		return nil, fmt.Errorf("loading preset: %w", err)
+	}
+
+	engine := review.NewEngine(&cfg, git.NewPatchRepo(diff, ""), provider, reviewCache, rules.ApplyPreset(allRules, preset))
+	engine.SetVersion(Version)
+	engine.SetProgress(tracker)
+	if closeTriage := setupTriage(&cfg, t.HistoryPath(), engine); closeTriage != nil {
+		defer closeTriage()
+	}
+	result, err := engine.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Review.Triage.Enabled {
+		storeTriage(ctx, t.HistoryPath(), result, "", "", "")
+	}
+	return result, nil
+}
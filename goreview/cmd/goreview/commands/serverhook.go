@@ -0,0 +1,296 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/gate"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+var serverHookCmd = &cobra.Command{
+	Use:   "git-server-hook",
+	Short: "Review pushes on a git server as a pre-receive hook",
+	Long: `Review pushed commits on a git server, as a pre-receive hook, and reject
+pushes that violate the gates.
+
+git runs the hook in the bare repository with a line "<old> <new> <ref>"
+on stdin per updated ref. Each updated branch is reviewed from its old
+commit to its new one, and a new branch from its first commit not yet in
+the repository. Deleted branches and tags are not reviewed. The findings
+are printed back to the pusher, and the whole push is rejected when a gate
+fails or, without gates, when an issue reaches review.fail_on.
+
+The config is the server's (.goreview.yaml in the repository, the home
+directory or /etc/goreview), never one in the pushed commits. Unless
+--profile is given, the profile "hook" applies when the config defines
+it, to review pushes with a fast model and fewer checks.
+
+Install it as hooks/pre-receive of the bare repository:
+
+  #!/bin/sh
+  exec goreview git-server-hook --timeout 2m`,
+	Args: cobra.NoArgs,
+	RunE: runServerHook,
+}
+
+// hookProfile is the config profile applied to pushes when defined
+const hookProfile = "hook"
+
+func init() {
+	rootCmd.AddCommand(serverHookCmd)
+
+	serverHookCmd.Flags().Bool("fail-open", false, "Accept pushes that cannot be reviewed, as when the provider is down")
+	addProviderFlags(serverHookCmd)
+	addTimeoutFlag(serverHookCmd, 5*time.Minute)
+}
+
+// refUpdate is a line of pre-receive input.
+type refUpdate struct {
+	Old, New, Ref string
+}
+
+func runServerHook(cmd *cobra.Command, _ []string) error {
+	updates, err := parseRefUpdates(cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	cfg, err := loadHookConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := applyProviderFlags(cmd, cfg); err != nil {
+		return err
+	}
+	gates, err := gate.CompileAll(cfg.Gates)
+	if err != nil {
+		return fmt.Errorf("compiling gates: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	failOpen, _ := cmd.Flags().GetBool("fail-open")
+	out := cmd.OutOrStdout()
+
+	pusher, err := newPushReviewer(ctx, cfg)
+	if err != nil {
+		if failOpen {
+			fmt.Fprintf(out, "goreview: push not reviewed: %v\n", err)
+			return nil
+		}
+		return err
+	}
+	defer pusher.Close()
+
+	var rejected []string
+	for _, u := range updates {
+		rng, err := reviewRange(u)
+		if err == nil && rng == "" {
+			continue
+		}
+		var result *review.Result
+		if err == nil {
+			result, err = pusher.Review(ctx, rng)
+		}
+		if err != nil {
+			if failOpen {
+				fmt.Fprintf(out, "goreview: %s not reviewed: %v\n", u.Ref, err)
+				continue
+			}
+			return fmt.Errorf("reviewing %s: %w", u.Ref, err)
+		}
+
+		printPushFindings(out, u.Ref, rng, result)
+		if reason := pushViolation(gates, cfg.Review.FailOn, result); reason != "" {
+			rejected = append(rejected, u.Ref+": "+reason)
+		}
+	}
+	if len(rejected) > 0 {
+		return fmt.Errorf("push rejected by goreview: %s", strings.Join(rejected, "; "))
+	}
+	return nil
+}
+
+// parseRefUpdates reads the "<old> <new> <ref>" lines given to pre-receive
+// hooks.
+func parseRefUpdates(r io.Reader) ([]refUpdate, error) {
+	var updates []refUpdate
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid ref update %q: want \"<old> <new> <ref>\"", scanner.Text())
+		}
+		updates = append(updates, refUpdate{Old: fields[0], New: fields[1], Ref: fields[2]})
+	}
+	return updates, scanner.Err()
+}
+
+// loadHookConfig loads the config with the hook profile, when defined and
+// no other profile was chosen.
+func loadHookConfig() (*config.Config, error) {
+	if cfgProfile != "" {
+		return loadConfig()
+	}
+	loader := newConfigLoader()
+	cfg, err := loader.Load()
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(loader.Profiles(), hookProfile) {
+		loader = newConfigLoader()
+		loader.SetWarn(func(string) {}) // Warned about on the first load
+		loader.SetProfile(hookProfile)
+		if cfg, err = loader.Load(); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyCassetteFlags(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// isZeroSHA reports whether a ref update's object is git's null object,
+// for created and deleted refs.
+func isZeroSHA(sha string) bool {
+	return strings.Trim(sha, "0") == ""
+}
+
+// reviewRange returns the range of commits a ref update pushes, "" when
+// there is nothing to review: deleted refs, tags and new branches of
+// commits already in the repository.
+func reviewRange(u refUpdate) (string, error) {
+	if isZeroSHA(u.New) || !strings.HasPrefix(u.Ref, "refs/heads/") {
+		return "", nil
+	}
+	if !isZeroSHA(u.Old) {
+		return u.Old + ".." + u.New, nil
+	}
+
+	// A new branch starts at the parent of its first new commit. The refs
+	// are not updated yet, so --all leaves out the pushed commits.
+	out, err := runGitCommand("rev-list", "--reverse", "--topo-order", u.New, "--not", "--all")
+	if err != nil {
+		return "", fmt.Errorf("listing pushed commits: %w", err)
+	}
+	first, _, _ := strings.Cut(out, "\n")
+	if first = strings.TrimSpace(first); first == "" {
+		return "", nil
+	}
+	base, err := runGitCommand("rev-parse", "--verify", "--quiet", first+"^")
+	if err != nil {
+		// A root commit, compared with the empty tree
+		if base, err = runGitCommand("hash-object", "-t", "tree", "--stdin"); err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(base) + ".." + u.New, nil
+}
+
+// pushReviewer reviews pushed ranges of the repository in the current
+// directory.
+type pushReviewer struct {
+	cfg      *config.Config
+	repo     *git.Repo
+	provider providers.Provider
+	rules    []rules.Rule
+}
+
+func newPushReviewer(ctx context.Context, cfg *config.Config) (*pushReviewer, error) {
+	repo, err := git.NewRepo(".")
+	if err != nil {
+		return nil, fmt.Errorf("initializing git: %w", err)
+	}
+	activeRules, err := loadActiveRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := providers.NewProvider(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("initializing provider: %w", err)
+	}
+	if err := provider.HealthCheck(ctx); err != nil {
+		_ = provider.Close()
+		return nil, fmt.Errorf("provider not available: %w", err)
+	}
+	return &pushReviewer{cfg: cfg, repo: repo, provider: provider, rules: activeRules}, nil
+}
+
+// Review reviews the change of a range of commits.
+func (p *pushReviewer) Review(ctx context.Context, rng string) (*review.Result, error) {
+	cfg := *p.cfg
+	cfg.Review.Mode = "commit"
+	cfg.Review.Commit = rng
+	engine := review.NewEngine(&cfg, p.repo, p.provider, nil, p.rules)
+	engine.SetVersion(Version)
+	return engine.Run(ctx)
+}
+
+func (p *pushReviewer) Close() {
+	_ = p.provider.Close()
+}
+
+// pushViolation returns why a reviewed push is rejected, "" when it isn't:
+// failed gates or, without gates, an issue at or above failOn.
+func pushViolation(gates []gate.Gate, failOn string, result *review.Result) string {
+	if len(gates) > 0 {
+		result.Gates = gate.Evaluate(gates, result)
+		if failed := result.GatesFailed(); len(failed) > 0 {
+			return "failed gates " + strings.Join(failed, ", ")
+		}
+		return ""
+	}
+	if result.TotalIssues > 0 && result.ExceedsSeverity(failOn) {
+		return "issues at or above " + failOn
+	}
+	return ""
+}
+
+// printPushFindings prints the issues of a pushed range for the pusher,
+// who sees them prefixed with "remote:".
+func printPushFindings(w io.Writer, ref, rng string, result *review.Result) {
+	from, to, _ := strings.Cut(rng, "..")
+	fmt.Fprintf(w, "goreview: %s (%s..%s): %d issues\n", ref, shortSHA(from), shortSHA(to), result.TotalIssues)
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			loc := f.File
+			if issue.Location != nil && issue.Location.StartLine > 0 {
+				loc = fmt.Sprintf("%s:%d", f.File, issue.Location.StartLine)
+			}
+			fmt.Fprintf(w, "  [%s] %s %s\n", issue.Severity, loc, issue.Message)
+		}
+	}
+	for _, g := range result.Gates {
+		switch {
+		case g.Error != "":
+			fmt.Fprintf(w, "  gate %s: error: %s\n", g.Name, g.Error)
+		case !g.Passed:
+			fmt.Fprintf(w, "  gate %s failed (%s)\n", g.Name, g.Expr)
+		}
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/gate"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+func TestParseRefUpdates(t *testing.T) {
+	updates, err := parseRefUpdates(strings.NewReader("a1 b2 refs/heads/main\n\n" + zeroSHA + " c3 refs/tags/v1\n"))
+	if err != nil || len(updates) != 2 || updates[0] != (refUpdate{Old: "a1", New: "b2", Ref: "refs/heads/main"}) {
+		t.Errorf("parseRefUpdates() = %+v, %v", updates, err)
+	}
+	if _, err := parseRefUpdates(strings.NewReader("a1 refs/heads/main\n")); err == nil {
+		t.Error("parseRefUpdates() accepted a line without the new object")
+	}
+}
+
+func TestReviewRange(t *testing.T) {
+	git := fixRepo(t)
+	head := strings.TrimSpace(git("rev-parse", "HEAD"))
+	tree := strings.TrimSpace(git("rev-parse", "HEAD^{tree}"))
+	// Pushed commits are in the repository but no ref points at them yet
+	pushed := strings.TrimSpace(git("commit-tree", tree, "-p", head, "-m", "pushed"))
+	root := strings.TrimSpace(git("commit-tree", tree, "-m", "unrelated history"))
+	emptyTree := strings.TrimSpace(git("hash-object", "-t", "tree", "/dev/null"))
+
+	tests := []struct {
+		name   string
+		update refUpdate
+		want   string
+	}{
+		{"updated branch", refUpdate{head, pushed, "refs/heads/main"}, head + ".." + pushed},
+		{"new branch", refUpdate{zeroSHA, pushed, "refs/heads/feature"}, head + ".." + pushed},
+		{"new branch of known commits", refUpdate{zeroSHA, head, "refs/heads/copy"}, ""},
+		{"new root", refUpdate{zeroSHA, root, "refs/heads/orphan"}, emptyTree + ".." + root},
+		{"deleted branch", refUpdate{head, zeroSHA, "refs/heads/main"}, ""},
+		{"tag", refUpdate{zeroSHA, pushed, "refs/tags/v1"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reviewRange(tt.update)
+			if err != nil || got != tt.want {
+				t.Errorf("reviewRange() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPushViolation(t *testing.T) {
+	result := func() *review.Result {
+		return &review.Result{TotalIssues: 1, Files: []review.FileResult{{
+			File: "main.go",
+			Response: &providers.ReviewResponse{Issues: []providers.Issue{
+				{Severity: providers.SeverityError, Message: "hardcoded password", Location: &providers.Location{StartLine: 4}},
+			}},
+		}}}
+	}
+
+	if got := pushViolation(nil, "critical", result()); got != "" {
+		t.Errorf("below fail_on: %q", got)
+	}
+	if got := pushViolation(nil, "error", result()); got != "issues at or above error" {
+		t.Errorf("at fail_on: %q", got)
+	}
+
+	gates, err := gate.CompileAll([]config.GateConfig{{Name: "no-errors", Expr: "issues.error == 0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := result()
+	if got := pushViolation(gates, "critical", r); got != "failed gates no-errors" {
+		t.Errorf("failed gate: %q", got)
+	}
+
+	var out bytes.Buffer
+	printPushFindings(&out, "refs/heads/main", "0123456789..abcdef0123", r)
+	want := "goreview: refs/heads/main (0123456..abcdef0): 1 issues\n" +
+		"  [error] main.go:4 hardcoded password\n" +
+		"  gate no-errors failed (issues.error == 0)\n"
+	if out.String() != want {
+		t.Errorf("findings =\n%s\nwant\n%s", out.String(), want)
+	}
+}
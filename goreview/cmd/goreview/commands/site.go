@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/site"
+)
+
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Generate a static website from review history",
+	Long:  `Render the review history into a static website, suitable for publishing as a team code-health portal.`,
+}
+
+var siteBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build the static review history site",
+	Long: `Build a static HTML site from the review history database.
+
+The site has an overview with trend charts, indexes by date, file,
+severity and author, and a page per review. It only uses relative links,
+so it can be published as-is (e.g. with GitHub Pages).
+
+Examples:
+  # Build into ./review-site
+  goreview site build -o ./review-site
+
+  # Custom title
+  goreview site build -o ./docs --title "Backend Code Health"`,
+	RunE: runSiteBuild,
+}
+
+func init() {
+	rootCmd.AddCommand(siteCmd)
+	siteCmd.AddCommand(siteBuildCmd)
+
+	siteBuildCmd.Flags().StringP("output", "o", "review-site", "Output directory")
+	siteBuildCmd.Flags().String("title", "", "Site title (default: GoReview Code Health)")
+}
+
+func runSiteBuild(cmd *cobra.Command, _ []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	store, err := history.NewStore(history.StoreConfig{Path: getHistoryDBPath(cfg)})
+	if err != nil {
+		return fmt.Errorf("opening history database: %w", err)
+	}
+	defer store.Close()
+
+	records, err := store.All(context.Background())
+	if err != nil {
+		return fmt.Errorf("reading history: %w", err)
+	}
+
+	outDir, _ := cmd.Flags().GetString("output")
+	title, _ := cmd.Flags().GetString("title")
+	if err := site.Build(records, outDir, site.Options{Title: title}); err != nil {
+		return fmt.Errorf("building site: %w", err)
+	}
+
+	fmt.Printf("Built site from %d issue(s) in %s\n", len(records), filepath.Join(outDir, "index.html"))
+	return nil
+}
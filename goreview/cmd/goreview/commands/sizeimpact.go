@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/internal/sizeimpact"
+)
+
+// applySizeImpact measures the build size impact of the reviewed change and
+// adds an issue for each output that grows past the threshold. Measurement
+// failures are reported but don't fail the review.
+func applySizeImpact(ctx context.Context, cfg *config.Config, result *review.Result) {
+	sc := cfg.Review.SizeImpact
+	if !sc.Enabled {
+		return
+	}
+	root, err := runGitCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return
+	}
+	repo := strings.TrimSpace(root)
+
+	changed := make([]string, 0, len(result.Files))
+	for _, f := range result.Files {
+		changed = append(changed, f.File)
+	}
+	before, after := sizeImpactRefs(cfg)
+	deltas, err := sizeimpact.MeasureGo(ctx, sizeimpact.GoOptions{Root: repo, BeforeRef: before, AfterRef: after, Changed: changed})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: binary size impact: %v\n", err)
+	}
+	if sc.BundleReport != "" {
+		bundle, err := sizeimpact.MeasureBundle(repo, sc.BundleReport, sc.BundleBaseline)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Warning: bundle size impact: %v\n", err)
+		case bundle != nil:
+			deltas = append(deltas, *bundle)
+		}
+	}
+
+	if isVerbose() {
+		for _, d := range deltas {
+			fmt.Fprintf(os.Stderr, "Size impact: %s %s %s -> %s (%+d bytes)\n", d.Kind, d.Target,
+				sizeimpact.FormatBytes(d.Before), sizeimpact.FormatBytes(d.After), d.Growth())
+		}
+	}
+	sizeimpact.Apply(result, deltas, int64(sc.MinGrowthKB)*1024)
+}
+
+// sizeImpactRefs returns the refs to build before and after the change; an
+// empty after ref is the working tree.
+func sizeImpactRefs(cfg *config.Config) (before, after string) {
+	switch cfg.Review.Mode {
+	case "commit":
+		return cfg.Review.Commit + "^", cfg.Review.Commit
+	case "branch":
+		if out, err := runGitCommand("merge-base", cfg.Git.BaseBranch, "HEAD"); err == nil {
+			return strings.TrimSpace(out), ""
+		}
+		return cfg.Git.BaseBranch, ""
+	default:
+		return "HEAD", ""
+	}
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/privacy"
+	"github.com/JNZader/goreview/goreview/internal/supportbundle"
+)
+
+// maxLogLines is how much of each log file goes into a support bundle.
+const maxLogLines = 2000
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle",
+	Short: "Collect diagnostics into a zip for bug reports",
+	Long: `Collect what maintainers need to debug a problem into a zip to attach to
+an issue: the effective configuration and the config file, version and
+environment details, the logs given with --log and the manifest of a
+failing run (see 'goreview review --manifest').
+
+Secrets are redacted before anything is written: configuration keys and
+environment variables holding credentials are masked, and every file goes
+through the privacy redaction (known token formats plus
+privacy.redact_patterns), even with privacy.redact_secrets disabled.
+Review the bundle before sharing it.
+
+The bundle is written even when the configuration doesn't load; the error
+is included instead.
+
+Examples:
+  # Bundle with the manifest and log of a failing CI run
+  goreview review --staged --manifest run.json 2> review.log
+  goreview support-bundle --manifest run.json --log review.log
+
+  # Choose the output file
+  goreview support-bundle -o bug-123.zip`,
+	Args: cobra.NoArgs,
+	RunE: runSupportBundle,
+}
+
+func init() {
+	rootCmd.AddCommand(supportBundleCmd)
+
+	supportBundleCmd.Flags().StringP("output", "o", "", "Zip file to write (default: goreview-support-<timestamp>.zip)")
+	supportBundleCmd.Flags().String("manifest", "", "Run manifest of the failing run to include")
+	supportBundleCmd.Flags().StringSlice("log", nil, "Log files to include, last 2000 lines each (repeatable)")
+}
+
+func runSupportBundle(cmd *cobra.Command, _ []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		output = fmt.Sprintf("goreview-support-%s.zip", time.Now().UTC().Format("20060102T150405Z"))
+	}
+
+	loader := newConfigLoader()
+	cfg, cfgErr := loader.Load()
+
+	// Secrets are always redacted in bundles; configured patterns are added
+	privacyCfg := config.PrivacyConfig{RedactSecrets: true}
+	if cfgErr == nil {
+		privacyCfg.RedactPatterns = cfg.Privacy.RedactPatterns
+	}
+	redactor, err := privacy.New(privacyCfg)
+	if err != nil {
+		return err
+	}
+	bundle := supportbundle.New(redactor)
+	var missing []string
+
+	configFile := loader.ConfigFileUsed()
+	if cfgErr != nil {
+		bundle.Add("config-error.txt", []byte(cfgErr.Error()+"\n"))
+	} else if data, err := yaml.Marshal(maskSensitiveConfig(cfg)); err == nil {
+		bundle.AddYAML("config.yaml", data)
+	}
+	if configFile != "" {
+		if data, err := os.ReadFile(configFile); err == nil { //nolint:gosec // The config file in use
+			bundle.AddYAML("config-file.yaml", data)
+		} else {
+			missing = append(missing, fmt.Sprintf("config file %s: %v", configFile, err))
+		}
+	}
+
+	env := supportbundle.Environment{
+		Version:    Version,
+		Commit:     Commit,
+		BuildDate:  BuildDate,
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		CPUs:       runtime.NumCPU(),
+		CI:         supportbundle.DetectCI(os.Getenv),
+		ConfigFile: configFile,
+		Profile:    cfgProfile,
+		Variables:  supportbundle.Variables(os.Environ()),
+	}
+	if out, err := runGitCommand("--version"); err == nil {
+		env.Git = strings.TrimSpace(out)
+	}
+	if err := bundle.AddJSON("environment.json", env); err != nil {
+		return err
+	}
+
+	if path, _ := cmd.Flags().GetString("manifest"); path != "" {
+		if data, err := os.ReadFile(path); err == nil { //nolint:gosec // Manifest path is user-provided
+			bundle.Add("manifest.json", data)
+		} else {
+			missing = append(missing, fmt.Sprintf("manifest %s: %v", path, err))
+		}
+	}
+	logs, _ := cmd.Flags().GetStringSlice("log")
+	for i, path := range logs {
+		name := fmt.Sprintf("logs/%d-%s", i+1, filepath.Base(path))
+		if err := bundle.AddFileTail(name, path, maxLogLines); err != nil {
+			missing = append(missing, fmt.Sprintf("log %s: %v", path, err))
+		}
+	}
+
+	if err := bundle.AddIndex(missing); err != nil {
+		return err
+	}
+	f, err := os.Create(output) //nolint:gosec // Output path is user-provided
+	if err != nil {
+		return fmt.Errorf("creating bundle: %w", err)
+	}
+	if err := bundle.Write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+
+	for _, m := range missing {
+		fmt.Fprintf(os.Stderr, "Warning: not included: %s\n", m)
+	}
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Support bundle written to %s; review it before sharing\n", output)
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Assign and triage issues found by reviews",
+	Long: `Assign and triage the issues recorded in the history database.
+
+With review.triage.enabled, 'goreview review' records every issue it finds
+and reports show each issue's triage status and ID. The triage of an issue
+carries over to later reviews that find it again, so acknowledged and
+won't-fix issues are marked as such instead of reported as new.
+
+Statuses: open, acknowledged, wontfix, resolved. A resolved issue found
+again is reopened.`,
+}
+
+var triageListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded issues with their triage",
+	Long: `List the latest record of each issue, most recent first.
+
+Examples:
+  # Open issues
+  goreview triage list
+
+  # Issues assigned to alice, in any status
+  goreview triage list --status all --assignee alice
+
+  # Won't-fix issues under internal/, as JSON
+  goreview triage list --status wontfix --file "internal/*" --format json`,
+	Args: cobra.NoArgs,
+	RunE: runTriageList,
+}
+
+var triageAssignCmd = &cobra.Command{
+	Use:   "assign <id> <assignee>",
+	Short: "Assign an issue",
+	Long: `Assign an issue to someone. An empty assignee unassigns it.
+
+Examples:
+  goreview triage assign 42 alice
+  goreview triage assign 42 "" --note "nobody owns this yet"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTriageAssign,
+}
+
+var triageTransitionCmd = &cobra.Command{
+	Use:   "transition <id> <status>",
+	Short: "Change the status of an issue",
+	Long: `Move an issue to open, acknowledged, wontfix or resolved. The note is
+added to the issue's triage log.
+
+Examples:
+  goreview triage transition 42 acknowledged --note "tracked in PROJ-12"
+  goreview triage transition 42 wontfix --note "deliberate, see ADR 7"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTriageTransition,
+}
+
+func init() {
+	rootCmd.AddCommand(triageCmd)
+	triageCmd.AddCommand(triageListCmd, triageAssignCmd, triageTransitionCmd)
+
+	triageListCmd.Flags().String("status", history.StatusOpen, "Status to list (open, acknowledged, wontfix, resolved, all)")
+	triageListCmd.Flags().String("assignee", "", "Only issues assigned to this person")
+	triageListCmd.Flags().String("file", "", "Only issues in files matching this pattern")
+	triageListCmd.Flags().Int("limit", 50, "Maximum number of issues")
+	triageListCmd.Flags().StringP("format", "f", "text", "Output format (text, json)")
+
+	triageAssignCmd.Flags().String("note", "", "Note for the triage log")
+	triageTransitionCmd.Flags().String("note", "", "Note for the triage log")
+}
+
+// recordTriage stores the issues found by the review in the history
+// database and marks each with its triage. Failures are reported but don't
+// fail the review.
+func recordTriage(ctx context.Context, cfg *config.Config, result *review.Result) {
+	if !cfg.Review.Triage.Enabled {
+		return
+	}
+	author, commit := debtOrigin(cfg)
+	branch := ""
+	if root, err := runGitCommand("rev-parse", "--show-toplevel"); err == nil {
+		branch = history.GetCurrentBranch(strings.TrimSpace(root))
+	}
+	storeTriage(ctx, getHistoryDBPath(cfg), result, author, commit, branch)
+}
+
+// storeTriage records the issues of a result in the history database at
+// dbPath, attributed to the given author, commit and branch.
+func storeTriage(ctx context.Context, dbPath string, result *review.Result, author, commit, branch string) {
+	var issues []*providers.Issue
+	var records []*history.ReviewRecord
+	now := time.Now()
+	for i := range result.Files {
+		f := &result.Files[i]
+		if f.Response == nil {
+			continue
+		}
+		for j := range f.Response.Issues {
+			issue := &f.Response.Issues[j]
+			line := 0
+			if issue.Location != nil {
+				line = issue.Location.StartLine
+			}
+			issues = append(issues, issue)
+			records = append(records, &history.ReviewRecord{
+				CommitHash: commit, FilePath: f.File, IssueType: string(issue.Type),
+				Severity: string(issue.Severity), Message: issue.Message, Suggestion: issue.Suggestion,
+				Line: line, Author: author, Branch: branch, CreatedAt: now, Environment: result.Environment,
+				Fingerprint: history.IssueFingerprint(f.File, string(issue.Type), issue.Message),
+				RuleID:      issue.RuleID,
+			})
+		}
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	store, err := history.NewStore(history.StoreConfig{Path: dbPath})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record issues: %v\n", err)
+		return
+	}
+	defer store.Close()
+	if err := store.RecordIssues(ctx, records); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record issues: %v\n", err)
+		return
+	}
+	for i, r := range records {
+		recurrence := ""
+		if issues[i].Triage != nil {
+			recurrence = issues[i].Triage.Recurrence
+		}
+		issues[i].Triage = &providers.Triage{
+			IssueID: r.ID, Status: r.Status, Assignee: r.Assignee, Notes: r.Notes, Recurrence: recurrence,
+		}
+	}
+}
+
+// setupTriage lets the review recognize issues triaged in earlier reviews,
+// as recorded in the history database at dbPath, returning a function
+// closing it.
+func setupTriage(cfg *config.Config, dbPath string, engine *review.Engine) func() {
+	if !cfg.Review.Triage.Enabled {
+		return nil
+	}
+	store, err := history.NewStore(history.StoreConfig{Path: dbPath})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: triaged issues not recognized: %v\n", err)
+		return nil
+	}
+	engine.UseTriage(store)
+	if cfg.Review.Triage.Calibration.Enabled {
+		engine.UseCalibration(store)
+	}
+	return func() { _ = store.Close() }
+}
+
+// openTriageStore opens the history database for the triage commands.
+func openTriageStore() (*history.Store, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	store, err := history.NewStore(history.StoreConfig{Path: getHistoryDBPath(cfg)})
+	if err != nil {
+		return nil, fmt.Errorf("opening history database: %w", err)
+	}
+	return store, nil
+}
+
+func runTriageList(cmd *cobra.Command, _ []string) error {
+	q := history.TriageQuery{}
+	q.Status, _ = cmd.Flags().GetString("status")
+	q.Assignee, _ = cmd.Flags().GetString("assignee")
+	q.File, _ = cmd.Flags().GetString("file")
+	q.Limit, _ = cmd.Flags().GetInt("limit")
+	if q.Status == "all" {
+		q.Status = ""
+	} else if !history.ValidStatus(q.Status) {
+		return fmt.Errorf("invalid --status %q (valid: %s, all)", q.Status, strings.Join(history.TriageStatuses, ", "))
+	}
+	format, _ := cmd.Flags().GetString("format")
+
+	store, err := openTriageStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	records, err := store.ListTriage(context.Background(), q)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling issues: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(records) == 0 {
+		fmt.Println("No issues found.")
+		return nil
+	}
+	for _, r := range records {
+		location := r.FilePath
+		if r.Line > 0 {
+			location = fmt.Sprintf("%s:%d", r.FilePath, r.Line)
+		}
+		assignee := r.Assignee
+		if assignee == "" {
+			assignee = "unassigned"
+		}
+		fmt.Printf("#%d %s [%s] %s\n", r.ID, getSeverityEmoji(r.Severity), strings.ToUpper(r.Severity), location)
+		fmt.Printf("   %s\n", truncate(r.Message, 80))
+		fmt.Printf("   %s | %s | seen in %d reviews, last %s\n\n",
+			r.Status, assignee, r.ReviewRound, r.CreatedAt.Format("2006-01-02"))
+	}
+	return nil
+}
+
+func runTriageAssign(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid issue ID %q", args[0])
+	}
+	note, _ := cmd.Flags().GetString("note")
+
+	store, err := openTriageStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if err := store.Assign(context.Background(), id, args[1], note); err != nil {
+		return err
+	}
+	if !isQuiet() {
+		if args[1] == "" {
+			fmt.Printf("Unassigned #%d\n", id)
+		} else {
+			fmt.Printf("Assigned #%d to %s\n", id, args[1])
+		}
+	}
+	return nil
+}
+
+func runTriageTransition(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid issue ID %q", args[0])
+	}
+	status := args[1]
+	note, _ := cmd.Flags().GetString("note")
+
+	store, err := openTriageStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if err := store.Transition(context.Background(), id, status, note); err != nil {
+		return err
+	}
+	if !isQuiet() {
+		fmt.Printf("Moved #%d to %s\n", id, status)
+	}
+	return nil
+}
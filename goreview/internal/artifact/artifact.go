@@ -0,0 +1,119 @@
+// Package artifact uploads review reports to object storage: Amazon S3 (or
+// an S3-compatible store) and Google Cloud Storage. Destinations are URLs
+// such as s3://bucket/path/report.json or gs://bucket/path/report.sarif,
+// and credentials come from the environment, as for the vendors' CLIs.
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Location is an object in a bucket.
+type Location struct {
+	// Scheme is "s3" or "gs"
+	Scheme string
+	Bucket string
+	Key    string
+}
+
+// String returns the location as a URL.
+func (l Location) String() string {
+	return l.Scheme + "://" + l.Bucket + "/" + l.Key
+}
+
+// IsRemote reports whether path is an object storage URL rather than a
+// local file.
+func IsRemote(path string) bool {
+	return strings.HasPrefix(path, "s3://") || strings.HasPrefix(path, "gs://")
+}
+
+// Parse parses an s3:// or gs:// URL.
+func Parse(raw string) (Location, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid artifact URL %q: %w", raw, err)
+	}
+	if u.Scheme != "s3" && u.Scheme != "gs" {
+		return Location{}, fmt.Errorf("unsupported artifact URL %q, must start with s3:// or gs://", raw)
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" || strings.HasSuffix(key, "/") {
+		return Location{}, fmt.Errorf("artifact URL %q must name a bucket and an object", raw)
+	}
+	return Location{Scheme: u.Scheme, Bucket: u.Host, Key: key}, nil
+}
+
+// Options configures an upload.
+type Options struct {
+	// ContentType is the object's content type
+	ContentType string
+
+	// Region is the S3 region; AWS_REGION or AWS_DEFAULT_REGION by default
+	Region string
+
+	// Endpoint overrides the storage endpoint, for S3-compatible stores
+	// such as MinIO, or for emulators
+	Endpoint string
+
+	// Client is the HTTP client; one with a 2 minute timeout by default
+	Client *http.Client
+}
+
+// Upload uploads data to the object at rawURL.
+func Upload(ctx context.Context, rawURL string, data []byte, opts Options) error {
+	loc, err := Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 2 * time.Minute}
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = "application/octet-stream"
+	}
+
+	switch loc.Scheme {
+	case "s3":
+		err = uploadS3(ctx, loc, data, opts)
+	default:
+		err = uploadGCS(ctx, loc, data, opts)
+	}
+	if err != nil {
+		return fmt.Errorf("uploading %s: %w", loc, err)
+	}
+	return nil
+}
+
+// ContentType returns the content type of a report format.
+func ContentType(format string) string {
+	switch format {
+	case "json", "codeactions":
+		return "application/json"
+	case "sarif":
+		return "application/sarif+json"
+	case "pdf":
+		return "application/pdf"
+	case "markdown":
+		return "text/markdown; charset=utf-8"
+	case "dot":
+		return "text/vnd.graphviz; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// checkResponse fails on non-2xx responses, including the start of the
+// body, which carries the storage service's error.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,169 @@
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	gcsEndpoint = "https://storage.googleapis.com"
+	gcsScope    = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+// serviceAccount is the part of a service account key file used to get an
+// access token.
+type serviceAccount struct {
+	Type        string `json:"type"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+func uploadGCS(ctx context.Context, loc Location, data []byte, opts Options) error {
+	token, err := gcsToken(ctx, opts.Client)
+	if err != nil {
+		return err
+	}
+
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+			endpoint = "http://" + strings.TrimPrefix(host, "http://")
+		} else {
+			endpoint = gcsEndpoint
+		}
+	}
+	target := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		strings.TrimSuffix(endpoint, "/"), url.PathEscape(loc.Bucket), url.QueryEscape(loc.Key))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", opts.ContentType)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return checkResponse(resp)
+}
+
+// gcsToken returns an OAuth access token: GOOGLE_OAUTH_ACCESS_TOKEN when
+// set, or one obtained for the service account key file named by
+// GOOGLE_APPLICATION_CREDENTIALS. Emulators need none.
+func gcsToken(ctx context.Context, client *http.Client) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	keyFile := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if keyFile == "" {
+		if os.Getenv("STORAGE_EMULATOR_HOST") != "" {
+			return "", nil
+		}
+		return "", fmt.Errorf("GOOGLE_APPLICATION_CREDENTIALS or GOOGLE_OAUTH_ACCESS_TOKEN is required")
+	}
+
+	data, err := os.ReadFile(keyFile) // #nosec G304 - path from the user's environment
+	if err != nil {
+		return "", fmt.Errorf("reading service account key: %w", err)
+	}
+	var sa serviceAccount
+	if err := json.Unmarshal(data, &sa); err != nil {
+		return "", fmt.Errorf("parsing service account key: %w", err)
+	}
+	if sa.Type != "service_account" {
+		return "", fmt.Errorf("%s is not a service account key", keyFile)
+	}
+	return exchangeJWT(ctx, client, sa, time.Now())
+}
+
+// exchangeJWT exchanges a signed JWT assertion for an access token, as in
+// the OAuth 2.0 flow for service accounts.
+func exchangeJWT(ctx context.Context, client *http.Client, sa serviceAccount, now time.Time) (string, error) {
+	if sa.TokenURI == "" {
+		sa.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	assertion, err := signJWT(sa, now)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sa.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting access token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkResponse(resp); err != nil {
+		return "", fmt.Errorf("requesting access token: %w", err)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("invalid access token response")
+	}
+	return token.AccessToken, nil
+}
+
+// signJWT builds the RS256-signed assertion for the service account.
+func signJWT(sa serviceAccount, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(sa.PrivateKey))
+	if block == nil {
+		return "", fmt.Errorf("service account private key is not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("parsing service account private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", fmt.Errorf("service account private key is not RSA")
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   sa.ClientEmail,
+		"scope": gcsScope,
+		"aud":   sa.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing token request: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(signature), nil
+}
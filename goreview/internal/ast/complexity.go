@@ -0,0 +1,152 @@
+package ast
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FunctionMetrics holds the size and complexity of a function
+type FunctionMetrics struct {
+	Name      string `json:"name"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	// Length is the number of lines of the function, including its signature
+	Length int `json:"length"`
+	// Cyclomatic is 1 plus the number of decision points
+	Cyclomatic int `json:"cyclomatic"`
+	// Cognitive weighs control flow by how deeply it is nested
+	Cognitive int `json:"cognitive"`
+}
+
+// Keywords that affect complexity, across the supported languages
+var (
+	// decisionPattern matches the branches counted by cyclomatic complexity
+	decisionPattern = regexp.MustCompile(`\b(?:if|elif|elsif|unless|for|foreach|while|until|case|when|catch|except|rescue)\b|&&|\|\||\band\b|\bor\b`)
+	// nestingPattern matches the structures that add nesting-weighted
+	// cognitive complexity
+	nestingPattern = regexp.MustCompile(`\b(?:if|unless|for|foreach|while|until|switch|catch|except|rescue)\b`)
+	// branchPattern matches continuations of a structure, which add
+	// cognitive complexity without nesting weight
+	branchPattern = regexp.MustCompile(`\b(?:else|elif|elsif)\b`)
+	// logicalPattern matches boolean operators; each run of the same
+	// operator adds cognitive complexity once
+	logicalPattern = regexp.MustCompile(`&&|\|\||\band\b|\bor\b`)
+	// caseLine matches switch labels, which sit at their switch's indentation
+	caseLine = regexp.MustCompile(`^(?:case\b|default\b|when\b)`)
+
+	literalPattern = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|'(?:[^'\\\\]|\\\\.)*'|`[^`]*`")
+)
+
+// Measure computes the metrics of the functions in ctx, parsed from code.
+// Metrics are approximated from keywords and indentation, so they work the
+// same way for every language the parser supports.
+func Measure(ctx *Context, code string) []FunctionMetrics {
+	lines := strings.Split(code, "\n")
+	marker := "//"
+	if hashComments[ctx.Language] {
+		marker = "#"
+	}
+
+	metrics := make([]FunctionMetrics, 0, len(ctx.Functions))
+	for _, fn := range ctx.Functions {
+		if fn.StartLine < 1 || fn.StartLine > len(lines) {
+			continue
+		}
+		end := min(max(fn.EndLine, fn.StartLine), len(lines))
+		for end > fn.StartLine && strings.TrimSpace(lines[end-1]) == "" {
+			end-- // Block ends found by indentation include trailing blank lines
+		}
+		cyclomatic, cognitive := complexity(lines[fn.StartLine-1:end], marker)
+		metrics = append(metrics, FunctionMetrics{
+			Name:       fn.Name,
+			StartLine:  fn.StartLine,
+			EndLine:    end,
+			Length:     end - fn.StartLine + 1,
+			Cyclomatic: cyclomatic,
+			Cognitive:  cognitive,
+		})
+	}
+	return metrics
+}
+
+// hashComments lists the languages whose line comments start with '#'
+var hashComments = map[string]bool{"python": true, "py": true, "ruby": true, "rb": true}
+
+// complexity computes the cyclomatic and cognitive complexity of the lines
+// of a function. Nesting is tracked by indentation: a structure nests the
+// more indented lines that follow it.
+func complexity(lines []string, marker string) (cyclomatic, cognitive int) {
+	cyclomatic = 1
+	var open []int // Indentation of the enclosing structures
+
+	for i, line := range lines {
+		code := stripComment(literalPattern.ReplaceAllString(line, `""`), marker)
+		trimmed := strings.TrimSpace(code)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if !caseLine.MatchString(trimmed) {
+			for len(open) > 0 && indent <= open[len(open)-1] {
+				open = open[:len(open)-1]
+			}
+		}
+		if i == 0 {
+			// The signature only counts its default-value logic
+			trimmed = logicalOnly(trimmed)
+		}
+
+		cyclomatic += len(decisionPattern.FindAllString(trimmed, -1))
+
+		structures := len(nestingPattern.FindAllString(trimmed, -1))
+		branches := len(branchPattern.FindAllString(trimmed, -1))
+		if branches > 0 && structures > 0 {
+			structures-- // "else if" adds no nesting weight
+		}
+		cognitive += structures*(1+len(open)) + branches + logicalRuns(trimmed)
+
+		if structures > 0 || branches > 0 {
+			open = append(open, indent)
+		}
+	}
+	return cyclomatic, cognitive
+}
+
+// logicalOnly keeps only the boolean operators of a line.
+func logicalOnly(line string) string {
+	return strings.Join(logicalPattern.FindAllString(line, -1), " ")
+}
+
+// logicalRuns counts the runs of identical boolean operators: "a && b && c"
+// is one, "a && b || c" is two.
+func logicalRuns(line string) int {
+	runs := 0
+	last := ""
+	for _, op := range logicalPattern.FindAllString(line, -1) {
+		op = normalizeOperator(op)
+		if op != last {
+			runs++
+			last = op
+		}
+	}
+	return runs
+}
+
+func normalizeOperator(op string) string {
+	switch op {
+	case "and":
+		return "&&"
+	case "or":
+		return "||"
+	}
+	return op
+}
+
+// stripComment removes a trailing line comment. String literals must
+// already be blanked out.
+func stripComment(line, marker string) string {
+	if i := strings.Index(line, marker); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
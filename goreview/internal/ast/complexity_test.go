@@ -0,0 +1,84 @@
+package ast
+
+import "testing"
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name           string
+		language       string
+		code           string
+		wantCyclomatic int
+		wantCognitive  int
+		wantLength     int
+	}{
+		{
+			name:     "go branches and nesting",
+			language: "go",
+			code: `package main
+
+func classify(items []int, strict bool) string {
+	count := 0
+	for _, item := range items {
+		if item > 0 && strict { // if counts once
+			count++
+		} else if item < 0 {
+			count--
+		}
+	}
+	switch {
+	case count > 10:
+		return "many if"
+	case count > 0:
+		return "some"
+	}
+	return "none"
+}`,
+			wantCyclomatic: 7,
+			wantCognitive:  6,
+			wantLength:     17,
+		},
+		{
+			name:     "python",
+			language: "python",
+			code: `def grade(score):
+    if score > 90 and score <= 100:
+        return "A"
+    elif score > 80:
+        for bonus in range(3):
+            if bonus:
+                score += 1
+    # if in a comment
+    return "B"
+`,
+			wantCyclomatic: 6,
+			wantCognitive:  8,
+			wantLength:     9,
+		},
+		{
+			name:           "straight line",
+			language:       "go",
+			code:           "func add(a, b int) int {\n\treturn a + b\n}",
+			wantCyclomatic: 1,
+			wantCognitive:  0,
+			wantLength:     3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewParser(tt.language).Parse(tt.code, "file")
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			metrics := Measure(ctx, tt.code)
+			if len(metrics) != 1 {
+				t.Fatalf("metrics = %+v, want 1 function", metrics)
+			}
+			m := metrics[0]
+			if m.Cyclomatic != tt.wantCyclomatic || m.Cognitive != tt.wantCognitive || m.Length != tt.wantLength {
+				t.Errorf("metrics = cyclomatic %d, cognitive %d, length %d; want %d, %d, %d",
+					m.Cyclomatic, m.Cognitive, m.Length, tt.wantCyclomatic, tt.wantCognitive, tt.wantLength)
+			}
+		})
+	}
+}
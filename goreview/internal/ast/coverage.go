@@ -0,0 +1,76 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Coverage tells how much structure the parser extracted from a file, to
+// spot files whose review got little or no structural context.
+type Coverage struct {
+	// Parser is the parser used, "generic" for the fallback of languages
+	// without one
+	Parser string `json:"parser"`
+	// Functions counts the functions and methods recognized
+	Functions int `json:"functions"`
+	// Classes counts the classes, structs and interfaces recognized
+	Classes int `json:"classes"`
+	// Errors are the problems that make the extraction unreliable, like
+	// unbalanced braces that leave function ends unknown
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Degraded reports whether the extraction fell back to the generic parser
+// or found errors.
+func (c *Coverage) Degraded() bool {
+	return c.Parser == "generic" || len(c.Errors) > 0
+}
+
+// maxCoverageErrors caps the errors reported per file
+const maxCoverageErrors = 5
+
+// Diagnose parses code and reports the coverage of the extraction.
+// Languages delimiting blocks with braces are checked for unbalanced
+// braces outside strings and comments; Python and Ruby blocks are not
+// checked.
+func (p *Parser) Diagnose(code, filePath string) *Coverage {
+	ctx, err := p.Parse(code, filePath)
+	c := &Coverage{Parser: p.Name()}
+	if err != nil {
+		c.Errors = append(c.Errors, err.Error())
+		return c
+	}
+	c.Functions = len(ctx.Functions)
+	c.Classes = len(ctx.Classes) + len(ctx.Interfaces)
+	if !hashComments[p.language] {
+		c.Errors = append(c.Errors, braceErrors(strings.Split(code, "\n"))...)
+	}
+	return c
+}
+
+// braceErrors returns where the braces of the lines don't balance.
+func braceErrors(lines []string) []string {
+	var errs []string
+	var open []int // Lines of the unclosed braces
+	for i, line := range lines {
+		code := stripComment(literalPattern.ReplaceAllString(line, `""`), "//")
+		for _, ch := range code {
+			switch ch {
+			case '{':
+				open = append(open, i+1)
+			case '}':
+				if len(open) == 0 {
+					if len(errs) < maxCoverageErrors {
+						errs = append(errs, fmt.Sprintf("line %d: unmatched }", i+1))
+					}
+					continue
+				}
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	if len(open) > 0 && len(errs) < maxCoverageErrors {
+		errs = append(errs, fmt.Sprintf("line %d: { never closed (%d unclosed)", open[0], len(open)))
+	}
+	return errs
+}
@@ -0,0 +1,57 @@
+package ast
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		code     string
+		want     Coverage
+	}{
+		{
+			name:     "go",
+			language: "go",
+			code:     "package a\n\ntype T struct {\n\tA int\n}\n\nfunc (t *T) M() string {\n\treturn \"}\" // }\n}\n\nfunc F() {}\n",
+			want:     Coverage{Parser: "go", Functions: 2, Classes: 1},
+		},
+		{
+			name:     "unclosed brace",
+			language: "go",
+			code:     "package a\n\nfunc F() {\n\tif x {\n\t\treturn\n}\n",
+			want:     Coverage{Parser: "go", Functions: 1, Errors: []string{"line 3: { never closed (1 unclosed)"}},
+		},
+		{
+			name:     "unmatched brace",
+			language: "javascript",
+			code:     "function f() {\n}\n}\n",
+			want:     Coverage{Parser: "javascript", Functions: 1, Errors: []string{"line 3: unmatched }"}},
+		},
+		{
+			name:     "python blocks are not checked",
+			language: "py",
+			code:     "class A:\n    pass\n\ndef f():\n    return '{'\n",
+			want:     Coverage{Parser: "python", Functions: 1, Classes: 1},
+		},
+		{
+			name:     "generic fallback",
+			language: "elixir",
+			code:     "defmodule A do\n  def f, do: 1\nend\n",
+			want:     Coverage{Parser: "generic"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewParser(tt.language).Diagnose(tt.code, "file")
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Diagnose() = %+v, want %+v", *got, tt.want)
+			}
+			if degraded := tt.want.Parser == "generic" || len(tt.want.Errors) > 0; got.Degraded() != degraded {
+				t.Errorf("Degraded() = %v, want %v", got.Degraded(), degraded)
+			}
+		})
+	}
+}
@@ -101,6 +101,36 @@ func NewParser(language string) *Parser {
 	}
 }
 
+// Name returns the parser used for the language, "generic" when it has
+// none and only common function and class patterns are recognized.
+func (p *Parser) Name() string {
+	switch p.language {
+	case "go", "golang":
+		return "go"
+	case "javascript", "js":
+		return "javascript"
+	case "typescript", "ts":
+		return "typescript"
+	case "python", "py":
+		return "python"
+	case "java":
+		return "java"
+	case "rust", "rs":
+		return "rust"
+	case "csharp", "cs", "c#":
+		return "csharp"
+	case "php":
+		return "php"
+	case "ruby", "rb":
+		return "ruby"
+	case "swift":
+		return "swift"
+	case "kotlin", "kt":
+		return "kotlin"
+	}
+	return "generic"
+}
+
 // Parse extracts context from source code
 func (p *Parser) Parse(code, filePath string) (*Context, error) {
 	ctx := &Context{
@@ -110,19 +140,29 @@ func (p *Parser) Parse(code, filePath string) (*Context, error) {
 
 	lines := strings.Split(code, "\n")
 
-	switch p.language {
-	case "go", "golang":
+	switch p.Name() {
+	case "go":
 		p.parseGo(lines, ctx)
-	case "javascript", "js":
+	case "javascript":
 		p.parseJavaScript(lines, ctx)
-	case "typescript", "ts":
+	case "typescript":
 		p.parseTypeScript(lines, ctx)
-	case "python", "py":
+	case "python":
 		p.parsePython(lines, ctx)
 	case "java":
 		p.parseJava(lines, ctx)
-	case "rust", "rs":
+	case "rust":
 		p.parseRust(lines, ctx)
+	case "csharp":
+		p.parseCSharp(lines, ctx)
+	case "php":
+		p.parsePHP(lines, ctx)
+	case "ruby":
+		p.parseRuby(lines, ctx)
+	case "swift":
+		p.parseSwift(lines, ctx)
+	case "kotlin":
+		p.parseKotlin(lines, ctx)
 	default:
 		// Generic parsing
 		p.parseGeneric(lines, ctx)
@@ -436,7 +476,7 @@ func (p *Parser) parseTypeScript(lines []string, ctx *Context) {
 }
 
 func (p *Parser) parseJSTS(lines []string, ctx *Context) {
-	importPattern := regexp.MustCompile(`^import\s+(?:{[^}]+}|[\w,\s]+)\s+from\s+['"]([^'"]+)['"]`)
+	importPattern := regexp.MustCompile(`^import\s+(?:type\s+)?(?:[\w$]+\s*,\s*)?(?:{[^}]+}|\*\s+as\s+[\w$]+|[\w$]+)\s+from\s+['"]([^'"]+)['"]`)
 	funcPattern := regexp.MustCompile(`^(?:export\s+)?(?:async\s+)?function\s+(\w+)`)
 	arrowPattern := regexp.MustCompile(`^(?:export\s+)?(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s+)?\([^)]*\)\s*(?::\s*\w+)?\s*=>`)
 	classPattern := regexp.MustCompile(`^(?:export\s+)?class\s+(\w+)`)
@@ -454,6 +494,7 @@ func (p *Parser) parseJSTS(lines []string, ctx *Context) {
 		if matches := funcPattern.FindStringSubmatch(line); len(matches) > 1 {
 			ctx.Functions = append(ctx.Functions, Function{
 				Name:       matches[1],
+				Parameters: parseJSParams(lines, i),
 				StartLine:  lineNum,
 				EndLine:    findFunctionEnd(lines, i) + 1,
 				IsExported: strings.Contains(line, "export"),
@@ -465,6 +506,7 @@ func (p *Parser) parseJSTS(lines []string, ctx *Context) {
 		if matches := arrowPattern.FindStringSubmatch(line); len(matches) > 1 {
 			ctx.Functions = append(ctx.Functions, Function{
 				Name:       matches[1],
+				Parameters: parseJSParams(lines, i),
 				StartLine:  lineNum,
 				EndLine:    findFunctionEnd(lines, i) + 1,
 				IsExported: strings.Contains(line, "export"),
@@ -484,6 +526,96 @@ func (p *Parser) parseJSTS(lines []string, ctx *Context) {
 	}
 }
 
+// parseJSParams parses the parameter list of the function declared at
+// lines[idx], which may span several lines, or nil when the list isn't
+// found. TypeScript types are kept; destructured parameters have no name and
+// the pattern as type.
+func parseJSParams(lines []string, idx int) []Param {
+	list, ok := paramList(lines, idx)
+	if !ok {
+		return nil
+	}
+	result := []Param{}
+	for _, part := range splitParams(list) {
+		part = strings.TrimPrefix(part, "...")
+		if strings.HasPrefix(part, "{") || strings.HasPrefix(part, "[") {
+			result = append(result, Param{Type: part})
+			continue
+		}
+		part = strings.TrimSpace(strings.SplitN(part, "=", 2)[0])
+		name, typ, _ := strings.Cut(part, ":")
+		result = append(result, Param{
+			Name: strings.TrimSuffix(strings.TrimSpace(name), "?"),
+			Type: strings.TrimSpace(typ),
+		})
+	}
+	return result
+}
+
+// paramList returns the text between the first parenthesis at lines[idx]
+// and the one closing it, looking at most maxParamLines ahead.
+func paramList(lines []string, idx int) (string, bool) {
+	const maxParamLines = 20
+	var sb strings.Builder
+	depth := 0
+	for i := idx; i < len(lines) && i < idx+maxParamLines; i++ {
+		for _, r := range lines[i] {
+			switch {
+			case r == '(':
+				depth++
+				if depth == 1 {
+					continue
+				}
+			case r == ')':
+				depth--
+				if depth == 0 {
+					return sb.String(), true
+				}
+			}
+			if depth > 0 {
+				sb.WriteRune(r)
+			}
+		}
+		if depth > 0 {
+			sb.WriteByte(' ')
+		}
+	}
+	return "", false
+}
+
+// splitParams splits a parameter list at its top-level commas, skipping
+// those inside brackets, generics and destructuring patterns.
+func splitParams(list string) []string {
+	var result []string
+	depth := 0
+	start := 0
+	prev := ' '
+	for i, r := range list {
+		switch r {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '>':
+			if prev != '=' { // Arrow functions in default values
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				if t := strings.TrimSpace(list[start:i]); t != "" {
+					result = append(result, t)
+				}
+				start = i + 1
+			}
+		}
+		prev = r
+	}
+	if t := strings.TrimSpace(list[start:]); t != "" {
+		result = append(result, t)
+	}
+	return result
+}
+
 // Python parsing
 func (p *Parser) parsePython(lines []string, ctx *Context) {
 	importPattern := regexp.MustCompile(`^(?:from\s+(\S+)\s+)?import\s+(\S+)`)
@@ -0,0 +1,530 @@
+package ast
+
+import (
+	"regexp"
+	"strings"
+)
+
+// C# parsing patterns
+var (
+	csNamespacePattern = regexp.MustCompile(`^\s*namespace\s+([\w.]+)`)
+	csUsingPattern     = regexp.MustCompile(`^\s*using\s+(?:static\s+)?(?:(\w+)\s*=\s*)?([\w.]+)\s*;`)
+	csClassPattern     = regexp.MustCompile(`^\s*((?:(?:public|private|protected|internal|static|abstract|sealed|partial)\s+)*)(?:class|struct|record)\s+(\w+)(?:<[^>]+>)?(?:\s*:\s*([\w.,<>\s]+))?`)
+	csInterfacePattern = regexp.MustCompile(`^\s*((?:(?:public|private|protected|internal|partial)\s+)*)interface\s+(\w+)`)
+	csMethodPattern    = regexp.MustCompile(`^\s*((?:(?:public|private|protected|internal|static|virtual|override|abstract|async|sealed|extern|new)\s+)+)([\w.<>\[\],?]+)\s+(\w+)\s*(?:<[^>]+>)?\s*\(([^)]*)\)?`)
+)
+
+// C# parsing
+func (p *Parser) parseCSharp(lines []string, ctx *Context) {
+	for i, line := range lines {
+		lineNum := i + 1
+
+		if matches := csNamespacePattern.FindStringSubmatch(line); len(matches) > 1 {
+			ctx.Package = matches[1]
+			continue
+		}
+
+		if matches := csUsingPattern.FindStringSubmatch(line); len(matches) > 2 {
+			ctx.Imports = append(ctx.Imports, Import{Alias: matches[1], Path: matches[2]})
+			continue
+		}
+
+		if matches := csInterfacePattern.FindStringSubmatch(line); len(matches) > 2 {
+			ctx.Interfaces = append(ctx.Interfaces, Interface{
+				Name:       matches[2],
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: strings.Contains(matches[1], "public"),
+			})
+			continue
+		}
+
+		if matches := csClassPattern.FindStringSubmatch(line); len(matches) > 2 {
+			cls := Class{
+				Name:       matches[2],
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: strings.Contains(matches[1], "public"),
+			}
+			// C# doesn't distinguish base class from interfaces syntactically;
+			// by convention interfaces start with "I" followed by an uppercase letter.
+			for _, base := range splitTypeList(matches[3]) {
+				if isCSharpInterfaceName(base) {
+					cls.Implements = append(cls.Implements, base)
+				} else if cls.Extends == "" {
+					cls.Extends = base
+				}
+			}
+			ctx.Classes = append(ctx.Classes, cls)
+			continue
+		}
+
+		if matches := csMethodPattern.FindStringSubmatch(line); len(matches) > 3 {
+			if isControlKeyword(matches[3]) {
+				continue
+			}
+			ctx.Functions = append(ctx.Functions, Function{
+				Name:       matches[3],
+				Parameters: parseTypedParams(matches[4]),
+				Returns:    []string{matches[2]},
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: strings.Contains(matches[1], "public"),
+			})
+		}
+	}
+}
+
+func isCSharpInterfaceName(name string) bool {
+	return len(name) > 1 && name[0] == 'I' && name[1] >= 'A' && name[1] <= 'Z'
+}
+
+// PHP parsing patterns
+var (
+	phpNamespacePattern = regexp.MustCompile(`^\s*namespace\s+([\w\\]+)\s*;`)
+	phpUsePattern       = regexp.MustCompile(`^use\s+(?:function\s+|const\s+)?([\w\\]+)(?:\s+as\s+(\w+))?\s*;`)
+	phpClassPattern     = regexp.MustCompile(`^\s*(?:(?:abstract|final|readonly)\s+)*(?:class|trait|enum)\s+(\w+)(?:\s+extends\s+([\w\\]+))?(?:\s+implements\s+([\w\\,\s]+))?`)
+	phpInterfacePattern = regexp.MustCompile(`^\s*interface\s+(\w+)`)
+	phpFuncPattern      = regexp.MustCompile(`^\s*((?:(?:public|private|protected|static|abstract|final)\s+)*)function\s+&?(\w+)\s*\(([^)]*)\)?(?:\s*:\s*\??([\w\\|]+))?`)
+)
+
+// PHP parsing
+func (p *Parser) parsePHP(lines []string, ctx *Context) {
+	for i, line := range lines {
+		lineNum := i + 1
+
+		if matches := phpNamespacePattern.FindStringSubmatch(line); len(matches) > 1 {
+			ctx.Package = matches[1]
+			continue
+		}
+
+		if matches := phpUsePattern.FindStringSubmatch(line); len(matches) > 1 {
+			ctx.Imports = append(ctx.Imports, Import{Path: matches[1], Alias: matches[2]})
+			continue
+		}
+
+		if matches := phpInterfacePattern.FindStringSubmatch(line); len(matches) > 1 {
+			ctx.Interfaces = append(ctx.Interfaces, Interface{
+				Name:       matches[1],
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: true,
+			})
+			continue
+		}
+
+		if matches := phpClassPattern.FindStringSubmatch(line); len(matches) > 1 {
+			ctx.Classes = append(ctx.Classes, Class{
+				Name:       matches[1],
+				Extends:    matches[2],
+				Implements: splitTypeList(matches[3]),
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: true,
+			})
+			continue
+		}
+
+		if matches := phpFuncPattern.FindStringSubmatch(line); len(matches) > 2 {
+			fn := Function{
+				Name:       matches[2],
+				Parameters: parsePHPParams(matches[3]),
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				// Members default to public in PHP
+				IsExported: !strings.Contains(matches[1], "private") && !strings.Contains(matches[1], "protected"),
+			}
+			if matches[4] != "" {
+				fn.Returns = []string{matches[4]}
+			}
+			ctx.Functions = append(ctx.Functions, fn)
+		}
+	}
+}
+
+// parsePHPParams parses "Type $name = default" parameter lists.
+func parsePHPParams(params string) []Param {
+	var result []Param
+	for _, part := range strings.Split(params, ",") {
+		part = strings.TrimSpace(strings.SplitN(part, "=", 2)[0])
+		if part == "" {
+			continue
+		}
+		fields := strings.Fields(part)
+		name := strings.TrimPrefix(fields[len(fields)-1], "&")
+		param := Param{Name: strings.TrimPrefix(strings.TrimPrefix(name, "..."), "$")}
+		if len(fields) > 1 {
+			param.Type = fields[len(fields)-2]
+		}
+		result = append(result, param)
+	}
+	return result
+}
+
+// Ruby parsing patterns
+var (
+	rubyRequirePattern = regexp.MustCompile(`^\s*require(?:_relative)?\s*\(?\s*['"]([^'"]+)['"]`)
+	rubyModulePattern  = regexp.MustCompile(`^\s*module\s+([\w:]+)`)
+	rubyClassPattern   = regexp.MustCompile(`^\s*class\s+([\w:]+)(?:\s*<\s*([\w:]+))?`)
+	rubyDefPattern     = regexp.MustCompile(`^\s*def\s+(?:(self)\.)?([\w?!=]+)\s*(?:\(([^)]*)\))?`)
+	rubyIncludePattern = regexp.MustCompile(`^\s*include\s+([\w:]+)`)
+	rubyVisibility     = regexp.MustCompile(`^\s*(private|protected|public)\s*$`)
+)
+
+// rubyParseState tracks visibility sections while parsing Ruby.
+type rubyParseState struct {
+	ctx        *Context
+	visibility string
+	classStack []int // Indexes into ctx.Classes, innermost last
+}
+
+// Ruby parsing
+func (p *Parser) parseRuby(lines []string, ctx *Context) {
+	state := &rubyParseState{ctx: ctx, visibility: "public"}
+
+	for i, line := range lines {
+		state.parseLine(lines, i, line)
+	}
+}
+
+func (s *rubyParseState) parseLine(lines []string, i int, line string) {
+	lineNum := i + 1
+	s.popFinishedClasses(lineNum)
+
+	if matches := rubyRequirePattern.FindStringSubmatch(line); len(matches) > 1 {
+		s.ctx.Imports = append(s.ctx.Imports, Import{Path: matches[1]})
+		return
+	}
+
+	if matches := rubyModulePattern.FindStringSubmatch(line); len(matches) > 1 {
+		if s.ctx.Module == "" {
+			s.ctx.Module = matches[1]
+		}
+		return
+	}
+
+	if matches := rubyClassPattern.FindStringSubmatch(line); len(matches) > 1 {
+		s.ctx.Classes = append(s.ctx.Classes, Class{
+			Name:       matches[1],
+			Extends:    matches[2],
+			StartLine:  lineNum,
+			EndLine:    findRubyBlockEnd(lines, i) + 1,
+			IsExported: true,
+		})
+		s.classStack = append(s.classStack, len(s.ctx.Classes)-1)
+		s.visibility = "public"
+		return
+	}
+
+	if matches := rubyIncludePattern.FindStringSubmatch(line); len(matches) > 1 {
+		if cls := s.currentClass(); cls != nil {
+			cls.Implements = append(cls.Implements, matches[1])
+		}
+		return
+	}
+
+	if matches := rubyVisibility.FindStringSubmatch(line); len(matches) > 1 {
+		s.visibility = matches[1]
+		return
+	}
+
+	if matches := rubyDefPattern.FindStringSubmatch(line); len(matches) > 2 {
+		fn := Function{
+			Name:       matches[2],
+			Parameters: parseUntypedParams(matches[3]),
+			StartLine:  lineNum,
+			EndLine:    findRubyBlockEnd(lines, i) + 1,
+			IsExported: s.visibility == "public",
+		}
+		if cls := s.currentClass(); cls != nil {
+			fn.Receiver = cls.Name
+			cls.Methods = append(cls.Methods, fn.Name)
+		}
+		s.ctx.Functions = append(s.ctx.Functions, fn)
+	}
+}
+
+func (s *rubyParseState) currentClass() *Class {
+	if len(s.classStack) == 0 {
+		return nil
+	}
+	return &s.ctx.Classes[s.classStack[len(s.classStack)-1]]
+}
+
+func (s *rubyParseState) popFinishedClasses(lineNum int) {
+	for len(s.classStack) > 0 && s.currentClass().EndLine < lineNum {
+		s.classStack = s.classStack[:len(s.classStack)-1]
+		s.visibility = "public"
+	}
+}
+
+// findRubyBlockEnd finds the "end" closing a def/class/module, which in
+// idiomatic Ruby sits at the same indentation as the opening keyword.
+func findRubyBlockEnd(lines []string, startIdx int) int {
+	defLine := lines[startIdx]
+	defIndent := len(defLine) - len(strings.TrimLeft(defLine, " \t"))
+
+	// One-liners: "def foo; 42; end"
+	if strings.HasSuffix(strings.TrimSpace(defLine), " end") {
+		return startIdx
+	}
+
+	for i := startIdx + 1; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == defIndent && (trimmed == "end" || strings.HasPrefix(trimmed, "end ")) {
+			return i
+		}
+		if trimmed != "" && indent < defIndent {
+			return i - 1
+		}
+	}
+	return len(lines) - 1
+}
+
+// Swift parsing patterns
+var (
+	swiftImportPattern   = regexp.MustCompile(`^\s*import\s+(?:(?:class|struct|enum|protocol|func|var|typealias)\s+)?([\w.]+)`)
+	swiftTypePattern     = regexp.MustCompile(`^\s*((?:(?:public|open|internal|private|fileprivate|final)\s+)*)(class|struct|enum|actor|extension)\s+(\w+)(?:<[^>]+>)?(?:\s*:\s*([\w.,<>\s]+?))?\s*(?:where\b.*)?\{?\s*$`)
+	swiftProtocolPattern = regexp.MustCompile(`^\s*((?:(?:public|open|internal|private|fileprivate)\s+)*)protocol\s+(\w+)`)
+	swiftFuncPattern     = regexp.MustCompile(`^\s*((?:(?:public|open|internal|private|fileprivate|static|class|final|override|mutating|@\w+)\s+)*)func\s+(\w+)\s*(?:<[^>]+>)?\s*\(([^)]*)\)?(?:\s*(?:async\s+)?(?:throws\s+|rethrows\s+)?->\s*([^{]+))?`)
+)
+
+// Swift parsing
+func (p *Parser) parseSwift(lines []string, ctx *Context) {
+	for i, line := range lines {
+		lineNum := i + 1
+
+		if matches := swiftImportPattern.FindStringSubmatch(line); len(matches) > 1 {
+			ctx.Imports = append(ctx.Imports, Import{Path: matches[1]})
+			continue
+		}
+
+		if matches := swiftProtocolPattern.FindStringSubmatch(line); len(matches) > 2 {
+			ctx.Interfaces = append(ctx.Interfaces, Interface{
+				Name:       matches[2],
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: isSwiftExported(matches[1]),
+			})
+			continue
+		}
+
+		if matches := swiftTypePattern.FindStringSubmatch(line); len(matches) > 3 {
+			name := matches[3]
+			if matches[2] == "extension" {
+				name += " (extension)"
+			}
+			cls := Class{
+				Name:       name,
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: isSwiftExported(matches[1]),
+			}
+			// The first inherited type of a class is its superclass; the rest are protocols
+			bases := splitTypeList(matches[4])
+			if matches[2] == "class" && len(bases) > 0 {
+				cls.Extends = bases[0]
+				bases = bases[1:]
+			}
+			cls.Implements = bases
+			ctx.Classes = append(ctx.Classes, cls)
+			continue
+		}
+
+		if matches := swiftFuncPattern.FindStringSubmatch(line); len(matches) > 2 {
+			fn := Function{
+				Name:       matches[2],
+				Parameters: parseColonParams(matches[3]),
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: isSwiftExported(matches[1]),
+			}
+			if ret := strings.TrimSpace(matches[4]); ret != "" {
+				fn.Returns = []string{ret}
+			}
+			ctx.Functions = append(ctx.Functions, fn)
+		}
+	}
+}
+
+// isSwiftExported reports whether declaration modifiers make it visible
+// outside the module (Swift defaults to internal).
+func isSwiftExported(modifiers string) bool {
+	return strings.Contains(modifiers, "public") || strings.Contains(modifiers, "open")
+}
+
+// Kotlin parsing patterns
+var (
+	ktPackagePattern   = regexp.MustCompile(`^\s*package\s+([\w.]+)`)
+	ktImportPattern    = regexp.MustCompile(`^\s*import\s+([\w.*]+)(?:\s+as\s+(\w+))?`)
+	ktClassPattern     = regexp.MustCompile(`^\s*((?:(?:public|private|protected|internal|open|abstract|sealed|data|enum|inner|annotation|value)\s+)*)(?:class|object)\s+(\w+)(?:<[^>]+>)?(?:\s*(?:\w+\s+)*constructor)?\s*(?:\([^)]*\))?\s*(?::\s*([^{]+))?`)
+	ktInterfacePattern = regexp.MustCompile(`^\s*((?:(?:public|private|protected|internal|sealed|fun)\s+)*)interface\s+(\w+)`)
+	ktFunPattern       = regexp.MustCompile(`^\s*((?:(?:public|private|protected|internal|open|override|abstract|suspend|inline|operator|infix|tailrec|external)\s+)*)fun\s+(?:<[^>]+>\s+)?(?:([\w.<>]+)\.)?(\w+)\s*\(([^)]*)\)?(?:\s*:\s*([\w.<>?,\s]+?))?\s*(?:[={].*)?$`)
+)
+
+// Kotlin parsing
+func (p *Parser) parseKotlin(lines []string, ctx *Context) {
+	for i, line := range lines {
+		lineNum := i + 1
+
+		if matches := ktPackagePattern.FindStringSubmatch(line); len(matches) > 1 {
+			ctx.Package = matches[1]
+			continue
+		}
+
+		if matches := ktImportPattern.FindStringSubmatch(line); len(matches) > 1 {
+			ctx.Imports = append(ctx.Imports, Import{Path: matches[1], Alias: matches[2]})
+			continue
+		}
+
+		if matches := ktInterfacePattern.FindStringSubmatch(line); len(matches) > 2 {
+			ctx.Interfaces = append(ctx.Interfaces, Interface{
+				Name:       matches[2],
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: isKotlinExported(matches[1]),
+			})
+			continue
+		}
+
+		if matches := ktClassPattern.FindStringSubmatch(line); len(matches) > 2 {
+			cls := Class{
+				Name:       matches[2],
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: isKotlinExported(matches[1]),
+			}
+			// A supertype with a constructor call "Base()" is the superclass
+			for _, base := range splitTypeList(matches[3]) {
+				if strings.Contains(base, "(") && cls.Extends == "" {
+					cls.Extends = base[:strings.Index(base, "(")]
+				} else {
+					cls.Implements = append(cls.Implements, base)
+				}
+			}
+			ctx.Classes = append(ctx.Classes, cls)
+			continue
+		}
+
+		if matches := ktFunPattern.FindStringSubmatch(line); len(matches) > 3 {
+			fn := Function{
+				Name:       matches[3],
+				Receiver:   matches[2],
+				Parameters: parseColonParams(matches[4]),
+				StartLine:  lineNum,
+				EndLine:    findFunctionEnd(lines, i) + 1,
+				IsExported: isKotlinExported(matches[1]),
+			}
+			if ret := strings.TrimSpace(matches[5]); ret != "" {
+				fn.Returns = []string{ret}
+			}
+			// Expression bodies ("fun x() = 42") end on the same line
+			if !strings.Contains(line, "{") {
+				fn.EndLine = lineNum
+			}
+			ctx.Functions = append(ctx.Functions, fn)
+		}
+	}
+}
+
+// isKotlinExported reports whether modifiers leave a declaration public
+// (Kotlin defaults to public).
+func isKotlinExported(modifiers string) bool {
+	return !strings.Contains(modifiers, "private") &&
+		!strings.Contains(modifiers, "protected") &&
+		!strings.Contains(modifiers, "internal")
+}
+
+// splitTypeList splits "Base, IFoo, Bar<T>" into trimmed type names.
+func splitTypeList(list string) []string {
+	var result []string
+	depth := 0
+	start := 0
+	for i, r := range list {
+		switch r {
+		case '<', '(':
+			depth++
+		case '>', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				if t := strings.TrimSpace(list[start:i]); t != "" {
+					result = append(result, t)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if t := strings.TrimSpace(list[start:]); t != "" {
+		result = append(result, t)
+	}
+	return result
+}
+
+// parseTypedParams parses C-style "Type name" parameter lists.
+func parseTypedParams(params string) []Param {
+	var result []Param
+	for _, part := range splitTypeList(params) {
+		part = strings.TrimSpace(strings.SplitN(part, "=", 2)[0])
+		fields := strings.Fields(part)
+		switch {
+		case len(fields) >= 2:
+			result = append(result, Param{
+				Name: fields[len(fields)-1],
+				Type: strings.Join(fields[:len(fields)-1], " "),
+			})
+		case len(fields) == 1:
+			result = append(result, Param{Type: fields[0]})
+		}
+	}
+	return result
+}
+
+// parseColonParams parses "name: Type" parameter lists (Swift, Kotlin).
+func parseColonParams(params string) []Param {
+	var result []Param
+	for _, part := range splitTypeList(params) {
+		part = strings.TrimSpace(strings.SplitN(part, "=", 2)[0])
+		name, typ, found := strings.Cut(part, ":")
+		if !found {
+			continue
+		}
+		// Swift argument labels: "for key: String" -> internal name "key"
+		nameFields := strings.Fields(name)
+		if len(nameFields) == 0 {
+			continue
+		}
+		result = append(result, Param{
+			Name: nameFields[len(nameFields)-1],
+			Type: strings.TrimSpace(typ),
+		})
+	}
+	return result
+}
+
+// parseUntypedParams parses dynamic-language parameter lists (Ruby).
+func parseUntypedParams(params string) []Param {
+	var result []Param
+	for _, part := range strings.Split(params, ",") {
+		fields := strings.Fields(strings.SplitN(part, "=", 2)[0])
+		if len(fields) == 0 {
+			continue
+		}
+		// Keyword arguments ("key: default") and splats ("*args", "&block")
+		if name := strings.TrimSuffix(strings.TrimLeft(fields[0], "*&"), ":"); name != "" {
+			result = append(result, Param{Name: name})
+		}
+	}
+	return result
+}
+
+// isControlKeyword filters out statements that look like method declarations.
+func isControlKeyword(name string) bool {
+	switch name {
+	case "if", "for", "foreach", "while", "switch", "catch", "using", "lock", "return", "new":
+		return true
+	}
+	return false
+}
@@ -72,6 +72,8 @@ func main() {
 func TestParseJavaScript(t *testing.T) {
 	code := `import { useState } from 'react';
 import axios from 'axios';
+import React, { useEffect } from 'react';
+import * as path from 'path';
 
 export function fetchData(url) {
 	return axios.get(url);
@@ -99,8 +101,8 @@ class DataService {
 		t.Fatalf("Parse failed: %v", err)
 	}
 
-	if len(ctx.Imports) < 2 {
-		t.Errorf("Expected at least 2 imports, got %d", len(ctx.Imports))
+	if len(ctx.Imports) != 4 || ctx.Imports[2].Path != "react" || ctx.Imports[3].Path != "path" {
+		t.Errorf("Expected 4 imports with default and namespace imports, got %+v", ctx.Imports)
 	}
 
 	if len(ctx.Functions) < 2 {
@@ -112,6 +114,32 @@ class DataService {
 	}
 }
 
+func TestParseJSParams(t *testing.T) {
+	code := `export function render(
+	el,
+	{ title, body } = {},
+	...children
+) {
+}
+
+export const save = async (id: number, opts?: Map<string, number>, done = () => {}) => {
+};
+`
+	ctx, err := NewParser("typescript").Parse(code, "view.ts")
+	if err != nil || len(ctx.Functions) != 2 {
+		t.Fatalf("Parse = %+v, %v", ctx, err)
+	}
+
+	render := ctx.Functions[0].Parameters
+	if len(render) != 3 || render[0].Name != "el" || render[1].Name != "" || render[1].Type != "{ title, body } = {}" || render[2].Name != "children" {
+		t.Errorf("render parameters = %+v", render)
+	}
+	save := ctx.Functions[1].Parameters
+	if len(save) != 3 || save[0].Name != "id" || save[0].Type != "number" || save[1].Name != "opts" || save[1].Type != "Map<string, number>" || save[2].Name != "done" {
+		t.Errorf("save parameters = %+v", save)
+	}
+}
+
 func TestParsePython(t *testing.T) {
 	code := `from typing import List, Optional
 import json
@@ -323,3 +351,276 @@ func test() {
 		_, _ = parser.Parse(code, "test.go")
 	}
 }
+
+func TestParseCSharp(t *testing.T) {
+	code := `using System;
+using System.Collections.Generic;
+using IO = System.IO;
+
+namespace Acme.Billing
+{
+    public interface IInvoiceService
+    {
+        decimal Total(int id);
+    }
+
+    public class InvoiceService : BaseService, IInvoiceService
+    {
+        public decimal Total(int id)
+        {
+            return 0;
+        }
+
+        private static async Task<bool> Validate(string code, int retries)
+        {
+            if (code == null)
+            {
+                return false;
+            }
+            return true;
+        }
+    }
+}
+`
+
+	ctx, err := NewParser("csharp").Parse(code, "InvoiceService.cs")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if ctx.Package != "Acme.Billing" {
+		t.Errorf("Expected namespace 'Acme.Billing', got '%s'", ctx.Package)
+	}
+	if len(ctx.Imports) != 3 || ctx.Imports[2].Alias != "IO" {
+		t.Errorf("Expected 3 imports with alias IO, got %+v", ctx.Imports)
+	}
+	if len(ctx.Interfaces) != 1 || ctx.Interfaces[0].Name != "IInvoiceService" {
+		t.Errorf("Expected interface IInvoiceService, got %+v", ctx.Interfaces)
+	}
+	if len(ctx.Classes) != 1 {
+		t.Fatalf("Expected 1 class, got %d", len(ctx.Classes))
+	}
+	cls := ctx.Classes[0]
+	if cls.Extends != "BaseService" || len(cls.Implements) != 1 || cls.Implements[0] != "IInvoiceService" {
+		t.Errorf("Unexpected inheritance: extends=%q implements=%v", cls.Extends, cls.Implements)
+	}
+	if len(ctx.Functions) != 2 {
+		t.Fatalf("Expected 2 methods (control statements ignored), got %d", len(ctx.Functions))
+	}
+	validate := ctx.Functions[1]
+	if validate.Name != "Validate" || validate.IsExported || len(validate.Parameters) != 2 {
+		t.Errorf("Unexpected method: %+v", validate)
+	}
+}
+
+func TestParsePHP(t *testing.T) {
+	code := `<?php
+namespace App\Http\Controllers;
+
+use App\Models\User;
+use Illuminate\Http\Request as HttpRequest;
+
+interface Renderable
+{
+    public function render(): string;
+}
+
+final class UserController extends Controller implements Renderable
+{
+    public function show(int $id, ?string $format = null): User
+    {
+        return User::find($id);
+    }
+
+    private function authorize(User $user)
+    {
+    }
+}
+`
+
+	ctx, err := NewParser("php").Parse(code, "UserController.php")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if ctx.Package != `App\Http\Controllers` {
+		t.Errorf("Expected namespace, got '%s'", ctx.Package)
+	}
+	if len(ctx.Imports) != 2 || ctx.Imports[1].Alias != "HttpRequest" {
+		t.Errorf("Expected 2 imports with alias, got %+v", ctx.Imports)
+	}
+	if len(ctx.Classes) != 1 || ctx.Classes[0].Extends != "Controller" {
+		t.Errorf("Expected UserController extending Controller, got %+v", ctx.Classes)
+	}
+	if len(ctx.Functions) != 3 {
+		t.Fatalf("Expected 3 functions, got %d", len(ctx.Functions))
+	}
+	show := ctx.Functions[1]
+	if show.Name != "show" || len(show.Parameters) != 2 || show.Parameters[0].Name != "id" || show.Returns[0] != "User" {
+		t.Errorf("Unexpected function: %+v", show)
+	}
+	if ctx.Functions[2].IsExported {
+		t.Error("authorize should not be exported (private)")
+	}
+}
+
+func TestParseRuby(t *testing.T) {
+	code := `require 'json'
+require_relative "helpers"
+
+module Billing
+  class Invoice < ApplicationRecord
+    include Comparable
+
+    def initialize(amount, currency: "USD")
+      @amount = amount
+    end
+
+    def self.build(*args)
+      new(*args)
+    end
+
+    private
+
+    def secret?
+      true
+    end
+  end
+end
+`
+
+	ctx, err := NewParser("ruby").Parse(code, "invoice.rb")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(ctx.Imports) != 2 {
+		t.Errorf("Expected 2 imports, got %d", len(ctx.Imports))
+	}
+	if ctx.Module != "Billing" {
+		t.Errorf("Expected module 'Billing', got '%s'", ctx.Module)
+	}
+	if len(ctx.Classes) != 1 {
+		t.Fatalf("Expected 1 class, got %d", len(ctx.Classes))
+	}
+	cls := ctx.Classes[0]
+	if cls.Extends != "ApplicationRecord" || len(cls.Methods) != 3 || cls.EndLine != 21 {
+		t.Errorf("Unexpected class: %+v", cls)
+	}
+	if len(ctx.Functions) != 3 {
+		t.Fatalf("Expected 3 methods, got %d", len(ctx.Functions))
+	}
+	init := ctx.Functions[0]
+	if init.EndLine != 10 || len(init.Parameters) != 2 || init.Parameters[1].Name != "currency" {
+		t.Errorf("Unexpected initialize: %+v", init)
+	}
+	if ctx.Functions[2].IsExported {
+		t.Error("secret? should not be exported (private section)")
+	}
+}
+
+func TestParseSwift(t *testing.T) {
+	code := `import Foundation
+import UIKit
+
+public protocol Cache {
+    func get(_ key: String) -> Data?
+}
+
+public final class DiskCache: BaseCache, Cache {
+    public func get(_ key: String) -> Data? {
+        return nil
+    }
+
+    private func path(for key: String, in dir: URL) throws -> URL {
+        return dir
+    }
+}
+
+extension DiskCache: CustomStringConvertible {
+}
+`
+
+	ctx, err := NewParser("swift").Parse(code, "DiskCache.swift")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(ctx.Imports) != 2 {
+		t.Errorf("Expected 2 imports, got %d", len(ctx.Imports))
+	}
+	if len(ctx.Interfaces) != 1 || ctx.Interfaces[0].Name != "Cache" {
+		t.Errorf("Expected protocol Cache, got %+v", ctx.Interfaces)
+	}
+	if len(ctx.Classes) != 2 {
+		t.Fatalf("Expected class and extension, got %d", len(ctx.Classes))
+	}
+	if ctx.Classes[0].Extends != "BaseCache" || !ctx.Classes[0].IsExported {
+		t.Errorf("Unexpected class: %+v", ctx.Classes[0])
+	}
+	// Protocol requirement + two methods
+	if len(ctx.Functions) != 3 {
+		t.Fatalf("Expected 3 functions, got %d", len(ctx.Functions))
+	}
+	path := ctx.Functions[2]
+	if path.Name != "path" || path.IsExported || len(path.Parameters) != 2 || path.Parameters[0].Name != "key" || path.Returns[0] != "URL" {
+		t.Errorf("Unexpected function: %+v", path)
+	}
+}
+
+func TestParseKotlin(t *testing.T) {
+	code := `package com.acme.orders
+
+import kotlinx.coroutines.flow.Flow
+import com.acme.Money as Cash
+
+interface Repository {
+    fun find(id: Long): Order?
+}
+
+data class Order(val id: Long, val total: Cash) : Entity(), Serializable
+
+class OrderService(private val repo: Repository) : Repository {
+    override fun find(id: Long): Order? {
+        return null
+    }
+
+    internal suspend fun sync(force: Boolean = false) {
+    }
+
+    fun String.isOrderId(): Boolean = startsWith("ORD")
+}
+`
+
+	ctx, err := NewParser("kotlin").Parse(code, "OrderService.kt")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if ctx.Package != "com.acme.orders" {
+		t.Errorf("Expected package, got '%s'", ctx.Package)
+	}
+	if len(ctx.Imports) != 2 || ctx.Imports[1].Alias != "Cash" {
+		t.Errorf("Expected 2 imports with alias, got %+v", ctx.Imports)
+	}
+	if len(ctx.Interfaces) != 1 {
+		t.Errorf("Expected 1 interface, got %d", len(ctx.Interfaces))
+	}
+	if len(ctx.Classes) != 2 {
+		t.Fatalf("Expected 2 classes, got %d", len(ctx.Classes))
+	}
+	order := ctx.Classes[0]
+	if order.Extends != "Entity" || len(order.Implements) != 1 || order.Implements[0] != "Serializable" {
+		t.Errorf("Unexpected inheritance: %+v", order)
+	}
+	if len(ctx.Functions) != 4 {
+		t.Fatalf("Expected 4 functions, got %d", len(ctx.Functions))
+	}
+	if ctx.Functions[2].Name != "sync" || ctx.Functions[2].IsExported {
+		t.Errorf("Unexpected function: %+v", ctx.Functions[2])
+	}
+	ext := ctx.Functions[3]
+	if ext.Receiver != "String" || ext.Name != "isOrderId" || ext.EndLine != ext.StartLine {
+		t.Errorf("Unexpected extension function: %+v", ext)
+	}
+}
@@ -0,0 +1,230 @@
+// Package audit selects the files of a directory-scoped audit, which reviews
+// whole files rather than a diff, and rolls the review scores up by
+// directory.
+package audit
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/internal/testcheck"
+	"github.com/JNZader/goreview/goreview/internal/tokenizer"
+)
+
+// Options selects the files of an audit.
+type Options struct {
+	// Languages keeps only files in these languages; empty keeps all
+	Languages []string
+	// MaxFiles caps the number of files (0 = unlimited)
+	MaxFiles int
+	// MaxTokens caps the estimated tokens of all files (0 = unlimited)
+	MaxTokens int
+	// Skip leaves out the files it returns true for, like the ones the
+	// ignore patterns exclude; nil keeps all
+	Skip func(path string) bool
+}
+
+// Selection is the outcome of Select.
+type Selection struct {
+	// Files are the selected files, in review priority order
+	Files []tokenizer.FileInfo
+	// Tokens is the estimated size of the selected files
+	Tokens int
+	// Capped lists the files left out by MaxFiles or MaxTokens
+	Capped []string
+	// Skipped lists the files left out by Skip
+	Skipped []string
+}
+
+// Paths returns the paths of the selected files.
+func (s *Selection) Paths() []string {
+	paths := make([]string, len(s.Files))
+	for i, f := range s.Files {
+		paths[i] = f.Path
+	}
+	return paths
+}
+
+// skippedDirs are never audited.
+var skippedDirs = map[string]bool{"vendor": true, "node_modules": true, "testdata": true}
+
+// Select walks dirs and returns the files to audit. Files are sorted by path,
+// then by tokenizer.PrioritizeFiles, so the caps always keep the same files:
+// source code first, then tests, configuration and documentation. A file
+// that doesn't fit in what is left of MaxTokens is skipped, and smaller
+// files after it may still be selected.
+func Select(dirs []string, opts Options, estimator *tokenizer.Estimator) (*Selection, error) {
+	languages := make(map[string]bool, len(opts.Languages))
+	for _, l := range opts.Languages {
+		languages[NormalizeLanguage(l)] = true
+	}
+
+	var candidates []tokenizer.FileInfo
+	var skipped []string
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			name := d.Name()
+			if d.IsDir() {
+				if p != dir && (strings.HasPrefix(name, ".") || skippedDirs[name]) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || seen[p] {
+				return nil
+			}
+			seen[p] = true
+			if opts.Skip != nil && opts.Skip(p) {
+				skipped = append(skipped, p)
+				return nil
+			}
+
+			data, err := os.ReadFile(p) //nolint:gosec // Walking the audited directories
+			if err != nil {
+				return err
+			}
+			if isBinary(data) {
+				return nil
+			}
+			lang := git.DetectLanguage(p, string(data))
+			if len(languages) > 0 && !languages[lang] {
+				return nil
+			}
+			candidates = append(candidates, tokenizer.FileInfo{
+				Path:       p,
+				Language:   lang,
+				TokenCount: estimator.EstimateTokens(string(data)),
+				IsTest:     testcheck.IsTestFile(p),
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Path < candidates[j].Path })
+	sel := &Selection{Skipped: skipped}
+	for _, f := range tokenizer.PrioritizeFiles(candidates) {
+		if (opts.MaxFiles > 0 && len(sel.Files) >= opts.MaxFiles) ||
+			(opts.MaxTokens > 0 && sel.Tokens+f.TokenCount > opts.MaxTokens) {
+			sel.Capped = append(sel.Capped, f.Path)
+			continue
+		}
+		sel.Files = append(sel.Files, f)
+		sel.Tokens += f.TokenCount
+	}
+	return sel, nil
+}
+
+// NormalizeLanguage returns the detector's name for a language given by
+// name or extension: "go", "js" and "py" become go, javascript and python.
+func NormalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(lang), "."))
+	if detected := git.DetectLanguage("file."+lang, ""); detected != "unknown" {
+		return detected
+	}
+	return lang
+}
+
+// isBinary reports whether data looks binary, like git does: a NUL byte in
+// the first 8000 bytes.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+}
+
+// DirScore is the rollup of a directory's reviewed files, its
+// subdirectories included.
+type DirScore struct {
+	Dir      string `json:"dir"`
+	Files    int    `json:"files"`
+	Issues   int    `json:"issues"`
+	Critical int    `json:"critical"`
+	Errors   int    `json:"errors"`
+	// Score is the average score of the files reviewed without error
+	Score int `json:"score"`
+	// Failed counts the files whose review failed
+	Failed int `json:"failed,omitempty"`
+}
+
+// Rollup returns the scores of every directory holding reviewed files, from
+// the files' deepest common directory down, sorted by path.
+func Rollup(result *review.Result) []DirScore {
+	if len(result.Files) == 0 {
+		return nil
+	}
+	common := path.Dir(result.Files[0].File)
+	for _, f := range result.Files[1:] {
+		common = commonDir(common, path.Dir(f.File))
+	}
+
+	type totals struct {
+		DirScore
+		scoreSum int
+	}
+	byDir := make(map[string]*totals)
+	for _, f := range result.Files {
+		for dir := path.Dir(f.File); ; dir = path.Dir(dir) {
+			t := byDir[dir]
+			if t == nil {
+				t = &totals{DirScore: DirScore{Dir: dir}}
+				byDir[dir] = t
+			}
+			t.Files++
+			if f.Error != nil || f.Response == nil {
+				t.Failed++
+			} else {
+				t.scoreSum += f.Response.Score
+				t.Issues += len(f.Response.Issues)
+				for _, issue := range f.Response.Issues {
+					switch issue.Severity {
+					case providers.SeverityCritical:
+						t.Critical++
+					case providers.SeverityError:
+						t.Errors++
+					}
+				}
+			}
+			if dir == common {
+				break
+			}
+		}
+	}
+
+	scores := make([]DirScore, 0, len(byDir))
+	for _, t := range byDir {
+		if reviewed := t.Files - t.Failed; reviewed > 0 {
+			t.Score = (t.scoreSum + reviewed/2) / reviewed
+		}
+		scores = append(scores, t.DirScore)
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Dir < scores[j].Dir })
+	return scores
+}
+
+// commonDir returns the deepest directory containing both a and b.
+func commonDir(a, b string) string {
+	for a != b {
+		if len(a) >= len(b) {
+			if a == "." {
+				return a
+			}
+			a = path.Dir(a)
+		} else {
+			b = path.Dir(b)
+		}
+	}
+	return a
+}
@@ -0,0 +1,173 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/internal/tokenizer"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func relPaths(t *testing.T, root string, paths []string) []string {
+	t.Helper()
+	rel := make([]string, len(paths))
+	for i, p := range paths {
+		r, err := filepath.Rel(root, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel[i] = filepath.ToSlash(r)
+	}
+	return rel
+}
+
+func TestSelect(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"b.go":               "package p\n",
+		"a.go":               "package p\n",
+		"a_test.go":          "package p\n",
+		"schema.sql":         "CREATE TABLE t (id int);\n",
+		"README.md":          "# p\n",
+		"config.yaml":        "a: 1\n",
+		"script.py":          "print(1)\n",
+		"image.bin":          "\x00\x01\x02",
+		"vendor/x/x.go":      "package x\n",
+		".hidden/h.go":       "package h\n",
+		"testdata/t.go":      "package t\n",
+		"sub/c.go":           "package sub\n",
+		"node_modules/m.js":  "module.exports = 1\n",
+		"sub/deeper/d.sql":   "SELECT 1;\n",
+		"sub/deeper/notes.t": "notes\n",
+	})
+	estimator := tokenizer.NewEstimator()
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "languages in priority order",
+			opts: Options{Languages: []string{"go", "sql"}},
+			want: []string{"a.go", "b.go", "schema.sql", "sub/c.go", "sub/deeper/d.sql", "a_test.go"},
+		},
+		{
+			name: "language by extension",
+			opts: Options{Languages: []string{".py"}},
+			want: []string{"script.py"},
+		},
+		{
+			name: "max files keeps the highest priority",
+			opts: Options{Languages: []string{"go", "sql", "markdown"}, MaxFiles: 3},
+			want: []string{"a.go", "b.go", "schema.sql"},
+		},
+		{
+			name: "documentation last",
+			opts: Options{Languages: []string{"go", "markdown", "yaml"}},
+			want: []string{"a.go", "b.go", "sub/c.go", "a_test.go", "config.yaml", "README.md"},
+		},
+		{
+			name: "skipped files",
+			opts: Options{Languages: []string{"go"}, Skip: func(p string) bool { return strings.HasSuffix(p, "_test.go") || strings.Contains(p, "sub") }},
+			want: []string{"a.go", "b.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel, err := Select([]string{root}, tt.opts, estimator)
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if got := relPaths(t, root, sel.Paths()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectMaxTokens(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"a.go": "package p\n\nfunc A() { println(\"a long function body that costs tokens\") }\n",
+		"b.go": "package p\n",
+		"c.go": "package p\n",
+	})
+	estimator := tokenizer.NewEstimator()
+	small := estimator.EstimateTokens("package p\n")
+
+	sel, err := Select([]string{root}, Options{MaxTokens: 2 * small}, estimator)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if got := relPaths(t, root, sel.Paths()); !reflect.DeepEqual(got, []string{"b.go", "c.go"}) {
+		t.Errorf("selected %v, want the files fitting the budget", got)
+	}
+	if got := relPaths(t, root, sel.Capped); !reflect.DeepEqual(got, []string{"a.go"}) {
+		t.Errorf("capped %v, want [a.go]", got)
+	}
+	if sel.Tokens != 2*small {
+		t.Errorf("Tokens = %d, want %d", sel.Tokens, 2*small)
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	for in, want := range map[string]string{"go": "go", "JS": "javascript", ".py": "python", "sql": "sql", "cobol": "cobol"} {
+		if got := NormalizeLanguage(in); got != want {
+			t.Errorf("NormalizeLanguage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRollup(t *testing.T) {
+	result := &review.Result{Files: []review.FileResult{
+		{File: "internal/payments/a.go", Response: &providers.ReviewResponse{Score: 90, Issues: []providers.Issue{
+			{Severity: providers.SeverityCritical}, {Severity: providers.SeverityWarning},
+		}}},
+		{File: "internal/payments/b.go", Response: &providers.ReviewResponse{Score: 70}},
+		{File: "internal/payments/stripe/c.go", Response: &providers.ReviewResponse{Score: 50, Issues: []providers.Issue{
+			{Severity: providers.SeverityError},
+		}}},
+		{File: "internal/payments/stripe/d.go", Error: errors.New("timeout")},
+	}}
+
+	want := []DirScore{
+		{Dir: "internal/payments", Files: 4, Issues: 3, Critical: 1, Errors: 1, Score: 70, Failed: 1},
+		{Dir: "internal/payments/stripe", Files: 2, Issues: 1, Errors: 1, Score: 50, Failed: 1},
+	}
+	if got := Rollup(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("Rollup() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	tests := []struct{ a, b, want string }{
+		{"a/b/c", "a/b", "a/b"},
+		{"a/b", "a/c", "a"},
+		{"a", "b/c", "."},
+		{".", "a", "."},
+	}
+	for _, tt := range tests {
+		if got := commonDir(tt.a, tt.b); got != tt.want {
+			t.Errorf("commonDir(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
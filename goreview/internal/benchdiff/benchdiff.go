@@ -0,0 +1,267 @@
+// Package benchdiff runs the Go benchmarks of changed packages before and
+// after a change and reports statistically significant regressions, the way
+// benchstat compares two sets of `go test -bench` results.
+package benchdiff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// RuleRegression is the rule ID of benchmark regression issues
+const RuleRegression = "bench/regression"
+
+// Defaults for the comparison
+const (
+	DefaultCount     = 6
+	DefaultAlpha     = 0.05
+	DefaultThreshold = 5.0
+)
+
+// Comparison is a benchmark metric measured before and after the change.
+type Comparison struct {
+	Key
+	// Base and Head are the sample medians
+	Base float64 `json:"base"`
+	Head float64 `json:"head"`
+	// Delta is the change from Base to Head, in percent
+	Delta float64 `json:"delta"`
+	// P is the p-value of the Mann-Whitney U test
+	P     float64 `json:"p"`
+	BaseN int     `json:"base_n"`
+	HeadN int     `json:"head_n"`
+}
+
+// Significant reports whether the difference is unlikely to be noise.
+func (c Comparison) Significant(alpha float64) bool {
+	return c.P < alpha
+}
+
+// Regression reports whether the metric got significantly worse by at least
+// threshold percent. Every go test metric is a cost, so higher is worse.
+func (c Comparison) Regression(alpha, threshold float64) bool {
+	return c.Significant(alpha) && c.Delta >= threshold
+}
+
+// Compare compares the metrics measured both before and after the change,
+// sorted by package, benchmark and unit.
+func Compare(base, head Set) []Comparison {
+	var comparisons []Comparison
+	for key, after := range head {
+		before, ok := base[key]
+		if !ok {
+			continue
+		}
+		c := Comparison{
+			Key:   key,
+			Base:  median(before),
+			Head:  median(after),
+			P:     mannWhitney(before, after),
+			BaseN: len(before),
+			HeadN: len(after),
+		}
+		switch {
+		case c.Base != 0:
+			c.Delta = (c.Head - c.Base) / c.Base * 100
+		case c.Head != 0:
+			c.Delta = math.Inf(1)
+		}
+		comparisons = append(comparisons, c)
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		a, b := comparisons[i].Key, comparisons[j].Key
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Unit < b.Unit
+	})
+	return comparisons
+}
+
+// Issue describes the regression as a review issue.
+func (c Comparison) Issue() providers.Issue {
+	return providers.Issue{
+		ID:       fmt.Sprintf("%s:%s/%s:%s", RuleRegression, c.Package, c.Name, c.Unit),
+		Type:     providers.IssueTypePerformance,
+		Severity: providers.SeverityWarning,
+		Message: fmt.Sprintf("%s %s regressed %s: %s → %s (p=%.3f n=%d+%d)",
+			c.Name, UnitLabel(c.Unit), FormatDelta(c.Delta), FormatValue(c.Base, c.Unit), FormatValue(c.Head, c.Unit), c.P, c.BaseN, c.HeadN),
+		Suggestion: "Profile the benchmark (go test -bench -cpuprofile) to find what the change made slower",
+		RuleID:     RuleRegression,
+	}
+}
+
+// FormatDelta renders a change in percent with its sign, like "+12.3%".
+func FormatDelta(delta float64) string {
+	if math.IsInf(delta, 1) {
+		return "+inf%"
+	}
+	return fmt.Sprintf("%+.1f%%", delta)
+}
+
+// UnitLabel names a unit like benchstat does: time/op for ns/op.
+func UnitLabel(unit string) string {
+	if unit == "ns/op" {
+		return "time/op"
+	}
+	return unit
+}
+
+// FormatValue renders a metric value: durations for ns/op, whole counts
+// as integers and three significant digits otherwise.
+func FormatValue(value float64, unit string) string {
+	switch {
+	case unit == "ns/op":
+		return time.Duration(value).Round(roundTo(value)).String()
+	case value == math.Trunc(value) && math.Abs(value) < 1e15:
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(value, 'g', 3, 64)
+}
+
+// roundTo keeps three significant digits of a duration in nanoseconds.
+func roundTo(ns float64) time.Duration {
+	if ns < 1000 {
+		return 1
+	}
+	return time.Duration(math.Pow(10, math.Floor(math.Log10(ns))-2))
+}
+
+// Apply adds an issue for each regression to the review result, under the
+// benchmark's package directory, and returns how many were added.
+func Apply(result *review.Result, comparisons []Comparison, alpha, threshold float64) int {
+	added := 0
+	for _, c := range comparisons {
+		if !c.Regression(alpha, threshold) {
+			continue
+		}
+		idx := -1
+		for i := range result.Files {
+			if result.Files[i].File == c.Package && result.Files[i].Response != nil {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			result.Files = append(result.Files, review.FileResult{
+				File:     c.Package,
+				Response: &providers.ReviewResponse{Summary: "Benchmark comparison"},
+			})
+			idx = len(result.Files) - 1
+		}
+		result.Files[idx].Response.Issues = append(result.Files[idx].Response.Issues, c.Issue())
+		result.TotalIssues++
+		added++
+	}
+	return added
+}
+
+// Runner runs a command in a directory and returns its standard output.
+type Runner func(ctx context.Context, dir, name string, args ...string) ([]byte, error)
+
+// ExecRunner runs commands with os/exec, including stderr in errors.
+func ExecRunner(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 - git and go with controlled args
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// Options configures a benchmark run.
+type Options struct {
+	// Root is the repository root
+	Root string
+	// BaseRef is the git ref the change is compared against; the working
+	// tree is the head
+	BaseRef string
+	// Packages lists the package directories to benchmark, relative to Root
+	Packages []string
+	// Bench is the -bench pattern (default: ".")
+	Bench string
+	// Count is the number of runs of each benchmark (default: DefaultCount)
+	Count int
+	// Run runs git and go; nil uses ExecRunner
+	Run Runner
+	// Progress, when set, is called before each package is benchmarked
+	Progress func(pkg, side string)
+}
+
+// Measure benchmarks the packages in a temporary git worktree of the base
+// ref and in the working tree. Packages the change adds are only measured
+// in the head set.
+func Measure(ctx context.Context, opts Options) (base, head Set, err error) {
+	if opts.Run == nil {
+		opts.Run = ExecRunner
+	}
+	if opts.Bench == "" {
+		opts.Bench = "."
+	}
+	if opts.Count <= 0 {
+		opts.Count = DefaultCount
+	}
+	if opts.BaseRef == "" {
+		return nil, nil, errors.New("no base ref to compare against")
+	}
+
+	tmp, err := os.MkdirTemp("", "goreview-bench-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	baseTree := filepath.Join(tmp, "base")
+	if _, err := opts.Run(ctx, opts.Root, "git", "worktree", "add", "--detach", baseTree, opts.BaseRef); err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		// Remove the worktree even after cancellation
+		_, _ = opts.Run(context.Background(), opts.Root, "git", "worktree", "remove", "--force", baseTree)
+	}()
+
+	base, head = make(Set), make(Set)
+	for _, pkg := range opts.Packages {
+		if _, statErr := os.Stat(filepath.Join(baseTree, pkg)); statErr == nil {
+			if err := bench(ctx, opts, baseTree, pkg, "base", base); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := bench(ctx, opts, opts.Root, pkg, "head", head); err != nil {
+			return nil, nil, err
+		}
+	}
+	return base, head, nil
+}
+
+// bench runs the benchmarks of one package of a tree into the set.
+func bench(ctx context.Context, opts Options, tree, pkg, side string, set Set) error {
+	if opts.Progress != nil {
+		opts.Progress(pkg, side)
+	}
+	out, err := opts.Run(ctx, filepath.Join(tree, pkg), "go", "test", "-run", "^$",
+		"-bench", opts.Bench, "-benchmem", "-count", strconv.Itoa(opts.Count), ".")
+	if err != nil {
+		return fmt.Errorf("benchmarking %s (%s): %w", pkg, side, err)
+	}
+	return set.Parse(filepath.ToSlash(pkg), bytes.NewReader(out))
+}
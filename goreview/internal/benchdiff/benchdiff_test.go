@@ -0,0 +1,190 @@
+package benchdiff
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+const sampleOutput = `goos: linux
+goarch: amd64
+pkg: example.com/app/parser
+cpu: Intel(R) Xeon(R)
+BenchmarkParse-8     	   10000	    120000 ns/op	    2048 B/op	      12 allocs/op
+BenchmarkParse-8     	   10000	    118000 ns/op	    2048 B/op	      12 allocs/op
+BenchmarkParse/large-8	     100	   9000000 ns/op
+BenchmarkNoProcs     	 1000000	      1050 ns/op
+PASS
+ok  	example.com/app/parser	3.210s
+`
+
+func TestParse(t *testing.T) {
+	set := make(Set)
+	if err := set.Parse("parser", strings.NewReader(sampleOutput)); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		key  Key
+		want []float64
+	}{
+		{Key{"parser", "BenchmarkParse", "ns/op"}, []float64{120000, 118000}},
+		{Key{"parser", "BenchmarkParse", "B/op"}, []float64{2048, 2048}},
+		{Key{"parser", "BenchmarkParse", "allocs/op"}, []float64{12, 12}},
+		{Key{"parser", "BenchmarkParse/large", "ns/op"}, []float64{9000000}},
+		{Key{"parser", "BenchmarkNoProcs", "ns/op"}, []float64{1050}},
+	}
+	for _, tt := range tests {
+		got := set[tt.key]
+		if len(got) != len(tt.want) {
+			t.Errorf("%v = %v, want %v", tt.key, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%v = %v, want %v", tt.key, got, tt.want)
+			}
+		}
+	}
+	if len(set) != len(tests) {
+		t.Errorf("Parse() found %d metrics, want %d", len(set), len(tests))
+	}
+}
+
+func TestMannWhitney(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		{"separated 3+3", []float64{1, 2, 3}, []float64{4, 5, 6}, 0.1},
+		{"separated 6+6", []float64{1, 2, 3, 4, 5, 6}, []float64{7, 8, 9, 10, 11, 12}, 2.0 / 924},
+		{"interleaved", []float64{1, 4, 5}, []float64{2, 3, 6}, 1},
+		{"all equal", []float64{2, 2, 2}, []float64{2, 2, 2}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mannWhitney(tt.x, tt.y); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("mannWhitney() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Constant but different samples, like allocs/op, use the normal
+	// approximation with the tie correction
+	if p := mannWhitney([]float64{2, 2, 2, 2, 2, 2}, []float64{3, 3, 3, 3, 3, 3}); p > 0.005 {
+		t.Errorf("mannWhitney() with ties = %v, want significant", p)
+	}
+}
+
+func TestCompareAndApply(t *testing.T) {
+	slow := Key{"parser", "BenchmarkParse", "ns/op"}
+	noisy := Key{"parser", "BenchmarkLex", "ns/op"}
+	faster := Key{"parser", "BenchmarkParse", "allocs/op"}
+	added := Key{"parser", "BenchmarkNew", "ns/op"}
+	base := Set{
+		slow:   {100, 101, 99, 100, 102, 98},
+		noisy:  {100, 150, 90, 120, 80, 110},
+		faster: {12, 12, 12, 12, 12, 12},
+	}
+	head := Set{
+		slow:   {130, 131, 129, 128, 132, 130},
+		noisy:  {105, 140, 95, 130, 85, 100},
+		faster: {10, 10, 10, 10, 10, 10},
+		added:  {50, 50, 50, 50, 50, 50},
+	}
+
+	comparisons := Compare(base, head)
+	if len(comparisons) != 3 {
+		t.Fatalf("Compare() = %d comparisons, want 3", len(comparisons))
+	}
+	if c := comparisons[1]; c.Key != faster || c.Delta >= 0 {
+		t.Errorf("comparisons[1] = %+v, want the allocs improvement", c)
+	}
+
+	result := &review.Result{}
+	if n := Apply(result, comparisons, DefaultAlpha, DefaultThreshold); n != 1 {
+		t.Fatalf("Apply() added %d issues, want 1", n)
+	}
+	issue := result.Files[0].Response.Issues[0]
+	if result.Files[0].File != "parser" || issue.RuleID != RuleRegression {
+		t.Errorf("issue in %s = %+v", result.Files[0].File, issue)
+	}
+	if want := "BenchmarkParse time/op regressed +30.0%: 100ns → 130ns (p=0.005 n=6+6)"; issue.Message != want {
+		t.Errorf("message = %q, want %q", issue.Message, want)
+	}
+
+	// A higher threshold hides the regression
+	if n := Apply(&review.Result{}, comparisons, DefaultAlpha, 50); n != 0 {
+		t.Errorf("Apply() with 50%% threshold added %d issues, want 0", n)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value float64
+		unit  string
+		want  string
+	}{
+		{1234567, "ns/op", "1.23ms"},
+		{512, "ns/op", "512ns"},
+		{2580, "B/op", "2580"},
+		{12.5, "allocs/op", "12.5"},
+	}
+	for _, tt := range tests {
+		if got := FormatValue(tt.value, tt.unit); got != tt.want {
+			t.Errorf("FormatValue(%v, %s) = %q, want %q", tt.value, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"parser", "lexer"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var removed bool
+	run := func(_ context.Context, dir, name string, args ...string) ([]byte, error) {
+		switch {
+		case name == "git" && args[1] == "add":
+			// The base doesn't have the lexer package yet
+			return nil, os.MkdirAll(filepath.Join(args[3], "parser"), 0o755)
+		case name == "git" && args[1] == "remove":
+			removed = true
+		case name == "go":
+			ns := "100"
+			if strings.HasPrefix(dir, root) {
+				ns = "130"
+			}
+			return []byte("BenchmarkRun-8  1000  " + ns + " ns/op\nBenchmarkRun-8  1000  " + ns + " ns/op\n"), nil
+		}
+		return nil, nil
+	}
+
+	base, head, err := Measure(context.Background(), Options{
+		Root: root, BaseRef: "main", Packages: []string{"lexer", "parser"}, Count: 2, Run: run,
+	})
+	if err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+	if !removed {
+		t.Error("Measure() didn't remove the worktree")
+	}
+	if len(base) != 1 || len(head) != 2 {
+		t.Fatalf("Measure() = %d base and %d head metrics, want 1 and 2", len(base), len(head))
+	}
+	if got := head[Key{"parser", "BenchmarkRun", "ns/op"}]; len(got) != 2 || got[0] != 130 {
+		t.Errorf("head parser samples = %v", got)
+	}
+	if got := base[Key{"parser", "BenchmarkRun", "ns/op"}]; len(got) != 2 || got[0] != 100 {
+		t.Errorf("base parser samples = %v", got)
+	}
+}
@@ -0,0 +1,60 @@
+package benchdiff
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Key identifies a benchmark metric.
+type Key struct {
+	// Package is the package directory relative to the repository root
+	Package string `json:"package"`
+	// Name is the benchmark name without the GOMAXPROCS suffix
+	Name string `json:"name"`
+	// Unit is the metric unit, like ns/op, B/op or allocs/op
+	Unit string `json:"unit"`
+}
+
+// Set holds the samples of each benchmark metric, one per -count run.
+type Set map[Key][]float64
+
+// Parse reads `go test -bench` output for the package in pkg and adds its
+// samples to the set. Lines other than benchmark results are ignored.
+func (s Set) Parse(pkg string, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// BenchmarkName-8   1000   1234 ns/op   56 B/op   2 allocs/op
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		name := trimProcs(fields[0])
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			key := Key{Package: pkg, Name: name, Unit: fields[i+1]}
+			s[key] = append(s[key], value)
+		}
+	}
+	return scanner.Err()
+}
+
+// trimProcs removes the -N GOMAXPROCS suffix from a benchmark name.
+func trimProcs(name string) string {
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
@@ -0,0 +1,130 @@
+package benchdiff
+
+import (
+	"math"
+	"sort"
+)
+
+// maxExactPairs bounds the sample sizes (n1*n2) for which the exact U
+// distribution is computed; larger samples use the normal approximation.
+const maxExactPairs = 400
+
+// median returns the median of the samples.
+func median(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// mannWhitney returns the two-sided p-value of the Mann-Whitney U test, the
+// test benchstat uses: the probability of samples at least this different
+// if both came from the same distribution. It makes no assumption about the
+// shape of the distribution, which suits noisy benchmark timings.
+func mannWhitney(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	// Rank the pooled samples, ties getting their average rank
+	type sample struct {
+		value float64
+		fromX bool
+	}
+	pooled := make([]sample, 0, n1+n2)
+	for _, v := range x {
+		pooled = append(pooled, sample{v, true})
+	}
+	for _, v := range y {
+		pooled = append(pooled, sample{v, false})
+	}
+	sort.Slice(pooled, func(i, j int) bool { return pooled[i].value < pooled[j].value })
+
+	var rankX, tieTerm float64
+	ties := false
+	for i := 0; i < len(pooled); {
+		j := i
+		for j < len(pooled) && pooled[j].value == pooled[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // Average of ranks i+1..j
+		for k := i; k < j; k++ {
+			if pooled[k].fromX {
+				rankX += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+	u := rankX - float64(n1*(n1+1))/2
+
+	if !ties && n1*n2 <= maxExactPairs {
+		return exactP(int(u), n1, n2)
+	}
+
+	n := float64(n1 + n2)
+	mu := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return 1 // All samples are equal
+	}
+	z := (math.Abs(u-mu) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		return 1
+	}
+	return math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// exactP is the two-sided p-value of u under the exact U distribution for
+// samples of n1 and n2 values without ties.
+func exactP(u, n1, n2 int) float64 {
+	counts := uCounts(n1, n2)
+	var total, below, above float64
+	for v, c := range counts {
+		total += c
+		if v <= u {
+			below += c
+		}
+		if v >= u {
+			above += c
+		}
+	}
+	return math.Min(1, 2*math.Min(below, above)/total)
+}
+
+// uCounts returns, for each U from 0 to n1*n2, how many orderings of the
+// pooled samples produce it. It fills the table of the recurrence
+// f(n1, n2, u) = f(n1-1, n2, u-n2) + f(n1, n2-1, u) one n1 row at a time.
+func uCounts(n1, n2 int) []float64 {
+	// prev[j] holds f(i-1, j, ·), cur[j] holds f(i, j, ·)
+	prev := make([][]float64, n2+1)
+	for j := range prev {
+		prev[j] = []float64{1} // f(0, j, 0) = 1
+	}
+	for i := 1; i <= n1; i++ {
+		cur := make([][]float64, n2+1)
+		cur[0] = []float64{1} // f(i, 0, 0) = 1
+		for j := 1; j <= n2; j++ {
+			counts := make([]float64, i*j+1)
+			for v, c := range prev[j] {
+				counts[v+j] += c
+			}
+			for v, c := range cur[j-1] {
+				counts[v] += c
+			}
+			cur[j] = counts
+		}
+		prev = cur
+	}
+	return prev[n2]
+}
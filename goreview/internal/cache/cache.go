@@ -40,12 +40,36 @@ type Stats struct {
 
 // ComputeKey generates a SHA-256 hash key from a review request.
 func ComputeKey(req *providers.ReviewRequest) string {
-	data, err := json.Marshal(map[string]interface{}{
+	fields := map[string]interface{}{
 		"diff":     req.Diff,
 		"language": req.Language,
 		"path":     req.FilePath,
 		"rules":    req.Rules,
-	})
+		"types":    req.IssueTypes,
+	}
+	// Optional inputs are only hashed when set, so other keys stay unchanged
+	if req.Context != "" {
+		fields["context"] = req.Context
+	}
+	if len(req.Focus) > 0 {
+		fields["focus"] = req.Focus
+	}
+	if req.Related != "" {
+		fields["related"] = req.Related
+	}
+	if req.ChangeSummary != "" {
+		fields["change_summary"] = req.ChangeSummary
+	}
+	if req.Intent != "" {
+		fields["intent"] = req.Intent
+	}
+	if len(req.Conventions) > 0 {
+		fields["conventions"] = req.Conventions
+	}
+	if req.Format != "" {
+		fields["format"] = req.Format
+	}
+	data, err := json.Marshal(fields)
 	if err != nil {
 		// Fallback to hashing the raw diff if marshal fails
 		data = []byte(req.Diff)
@@ -169,6 +169,11 @@ func TestComputeKey(t *testing.T) {
 		t.Error("Different requests should have different keys")
 	}
 
+	focused := &providers.ReviewRequest{Diff: "diff1", Language: "go", Focus: []string{"line 3 (concurrency/mutex): Lock is never unlocked"}}
+	if ComputeKey(focused) == key1 {
+		t.Error("Focus regions should change the key")
+	}
+
 	// Key should be 64 chars (SHA-256 hex)
 	if len(key1) != 64 {
 		t.Errorf("Key length = %d, want 64", len(key1))
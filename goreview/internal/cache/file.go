@@ -22,6 +22,9 @@ type FileCache struct {
 type fileEntry struct {
 	Response  *providers.ReviewResponse `json:"response"`
 	ExpiresAt time.Time                 `json:"expires_at"`
+	// File is the reviewed file, when the caller recorded it
+	File      string    `json:"file,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // NewFileCache creates a new file-based cache.
@@ -65,9 +68,18 @@ func (c *FileCache) Get(key string) (*providers.ReviewResponse, bool, error) {
 }
 
 func (c *FileCache) Set(key string, response *providers.ReviewResponse) error {
+	return c.SetFile(key, "", response)
+}
+
+// SetFile stores a response, recording the reviewed file so entries can be
+// pruned by path.
+func (c *FileCache) SetFile(key, file string, response *providers.ReviewResponse) error {
+	now := time.Now()
 	entry := fileEntry{
 		Response:  response,
-		ExpiresAt: time.Now().Add(c.ttl),
+		ExpiresAt: now.Add(c.ttl),
+		File:      file,
+		CreatedAt: now,
 	}
 
 	data, err := json.Marshal(entry)
@@ -101,15 +113,22 @@ func (c *FileCache) ComputeKey(req *providers.ReviewRequest) string {
 }
 
 func (c *FileCache) Stats() Stats {
-	var entryCount int
-	if entries, err := os.ReadDir(c.dir); err == nil {
-		entryCount = len(entries)
+	stats := Stats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
 	}
-	return Stats{
-		Hits:    atomic.LoadInt64(&c.hits),
-		Misses:  atomic.LoadInt64(&c.misses),
-		Entries: entryCount,
+	if entries, err := os.ReadDir(c.dir); err == nil {
+		for _, entry := range entries {
+			if !isEntryFile(entry) {
+				continue
+			}
+			stats.Entries++
+			if info, err := entry.Info(); err == nil {
+				stats.SizeBytes += info.Size()
+			}
+		}
 	}
+	return stats
 }
 
 func (c *FileCache) keyPath(key string) string {
@@ -124,7 +143,7 @@ func (c *FileCache) Cleanup() error {
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if !isEntryFile(entry) {
 			continue
 		}
 
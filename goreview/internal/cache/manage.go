@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// countersFile holds the hits and misses of all runs in the cache directory.
+const countersFile = "stats.json"
+
+// FileSetter is implemented by caches that record the reviewed file of each
+// entry, so entries can be inspected and pruned by path.
+type FileSetter interface {
+	SetFile(key, file string, response *providers.ReviewResponse) error
+}
+
+// SetForFile stores a response for a file, recording the file when the
+// cache supports it.
+func SetForFile(c Cache, key, file string, response *providers.ReviewResponse) error {
+	if fs, ok := c.(FileSetter); ok {
+		return fs.SetFile(key, file, response)
+	}
+	return c.Set(key, response)
+}
+
+// EntryInfo describes an entry of a FileCache.
+type EntryInfo struct {
+	Key       string    `json:"key"`
+	File      string    `json:"file,omitempty"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Dir returns the directory of the cache.
+func (c *FileCache) Dir() string {
+	return c.dir
+}
+
+// Entries lists the entries of the cache. Entries written before creation
+// times were recorded use their file's modification time.
+func (c *FileCache) Entries() ([]EntryInfo, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []EntryInfo
+	for _, de := range dirEntries {
+		if !isEntryFile(de) {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.dir, de.Name())) //nolint:gosec // Path is constructed from trusted cache directory
+		if err != nil {
+			continue
+		}
+		var fe fileEntry
+		if err := json.Unmarshal(data, &fe); err != nil {
+			continue
+		}
+		created := fe.CreatedAt
+		if created.IsZero() {
+			created = info.ModTime()
+		}
+		entries = append(entries, EntryInfo{
+			Key:       strings.TrimSuffix(de.Name(), ".json"),
+			File:      fe.File,
+			Size:      info.Size(),
+			CreatedAt: created,
+			ExpiresAt: fe.ExpiresAt,
+		})
+	}
+	return entries, nil
+}
+
+// Prune removes the entries for which remove returns true and returns how
+// many were removed and the bytes freed.
+func (c *FileCache) Prune(remove func(EntryInfo) bool) (int, int64, error) {
+	entries, err := c.Entries()
+	if err != nil {
+		return 0, 0, err
+	}
+	removed, freed := 0, int64(0)
+	for _, e := range entries {
+		if !remove(e) {
+			continue
+		}
+		if err := c.Delete(e.Key); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, freed, err
+		}
+		removed++
+		freed += e.Size
+	}
+	return removed, freed, nil
+}
+
+// Counters are the cache hits and misses of all runs since the counters were
+// started or the cache was cleared.
+type Counters struct {
+	Hits   int64     `json:"hits"`
+	Misses int64     `json:"misses"`
+	Since  time.Time `json:"since"`
+}
+
+// HitRate is the percentage of lookups that were hits.
+func (c Counters) HitRate() float64 {
+	if total := c.Hits + c.Misses; total > 0 {
+		return float64(c.Hits) / float64(total) * 100
+	}
+	return 0
+}
+
+// Counters returns the persisted counters, without this run's.
+func (c *FileCache) Counters() (Counters, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, countersFile)) //nolint:gosec // Path is constructed from trusted cache directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return Counters{}, nil
+	}
+	if err != nil {
+		return Counters{}, err
+	}
+	var counters Counters
+	err = json.Unmarshal(data, &counters)
+	return counters, err
+}
+
+// Flush adds the hits and misses of this run to the persisted counters.
+func (c *FileCache) Flush() error {
+	hits, misses := atomic.SwapInt64(&c.hits, 0), atomic.SwapInt64(&c.misses, 0)
+	if hits == 0 && misses == 0 {
+		return nil
+	}
+	counters, err := c.Counters()
+	if err != nil {
+		counters = Counters{}
+	}
+	if counters.Since.IsZero() {
+		counters.Since = time.Now().UTC()
+	}
+	counters.Hits += hits
+	counters.Misses += misses
+	data, err := json.Marshal(counters)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.dir, countersFile), data, 0600)
+}
+
+// Close flushes the counters.
+func (c *FileCache) Close() error {
+	return c.Flush()
+}
+
+// TTLBucket counts entries by remaining time to live.
+type TTLBucket struct {
+	Label   string `json:"label"`
+	Entries int    `json:"entries"`
+}
+
+// ttlBounds are the upper bounds of the TTL buckets; the last one is open.
+var ttlBounds = []struct {
+	label string
+	max   time.Duration
+}{
+	{"expired", 0},
+	{"< 1h", time.Hour},
+	{"< 24h", 24 * time.Hour},
+	{"< 7d", 7 * 24 * time.Hour},
+	{">= 7d", -1},
+}
+
+// TTLDistribution counts the entries by remaining time to live at now.
+func TTLDistribution(entries []EntryInfo, now time.Time) []TTLBucket {
+	buckets := make([]TTLBucket, len(ttlBounds))
+	for i, b := range ttlBounds {
+		buckets[i].Label = b.label
+	}
+	for _, e := range entries {
+		left := e.ExpiresAt.Sub(now)
+		for i, b := range ttlBounds {
+			if b.max < 0 || left <= b.max {
+				buckets[i].Entries++
+				break
+			}
+		}
+	}
+	return buckets
+}
+
+// isEntryFile reports whether a directory entry is a cache entry.
+func isEntryFile(de fs.DirEntry) bool {
+	return !de.IsDir() && strings.HasSuffix(de.Name(), ".json") && de.Name() != countersFile
+}
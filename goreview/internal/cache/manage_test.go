@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestFileCachePrune(t *testing.T) {
+	c, err := NewFileCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("NewFileCache() error = %v", err)
+	}
+	resp := &providers.ReviewResponse{Score: 90}
+	_ = SetForFile(c, "a", "api/handler.go", resp)
+	_ = SetForFile(c, "b", "api/handler.pb.go", resp)
+	_ = c.Set("c", resp)
+
+	entries, err := c.Entries()
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Entries() = %d entries, %v; want 3", len(entries), err)
+	}
+
+	removed, freed, err := c.Prune(func(e EntryInfo) bool { return e.File == "api/handler.pb.go" })
+	if err != nil || removed != 1 || freed == 0 {
+		t.Fatalf("Prune() = %d, %d, %v; want one entry removed", removed, freed, err)
+	}
+	if _, found, _ := c.Get("b"); found {
+		t.Error("pruned entry still cached")
+	}
+	if _, found, _ := c.Get("a"); !found {
+		t.Error("kept entry missing")
+	}
+}
+
+func TestFileCacheCounters(t *testing.T) {
+	dir := t.TempDir()
+	for run := 0; run < 2; run++ {
+		c, err := NewFileCache(dir, time.Hour)
+		if err != nil {
+			t.Fatalf("NewFileCache() error = %v", err)
+		}
+		_ = c.Set("k", &providers.ReviewResponse{})
+		_, _, _ = c.Get("k")
+		_, _, _ = c.Get("missing")
+		if err := c.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	c, _ := NewFileCache(dir, time.Hour)
+	counters, err := c.Counters()
+	if err != nil {
+		t.Fatalf("Counters() error = %v", err)
+	}
+	if counters.Hits != 2 || counters.Misses != 2 || counters.HitRate() != 50 || counters.Since.IsZero() {
+		t.Errorf("Counters() = %+v", counters)
+	}
+	if stats := c.Stats(); stats.Entries != 1 {
+		t.Errorf("Stats().Entries = %d, want the counters file not counted", stats.Entries)
+	}
+
+	_ = c.Clear()
+	if counters, _ := c.Counters(); counters.Hits != 0 {
+		t.Errorf("Counters() after Clear() = %+v, want reset", counters)
+	}
+}
+
+func TestTTLDistribution(t *testing.T) {
+	now := time.Now()
+	entries := []EntryInfo{
+		{ExpiresAt: now.Add(-time.Minute)},
+		{ExpiresAt: now.Add(30 * time.Minute)},
+		{ExpiresAt: now.Add(2 * time.Hour)},
+		{ExpiresAt: now.Add(3 * time.Hour)},
+		{ExpiresAt: now.Add(30 * 24 * time.Hour)},
+	}
+	want := []int{1, 1, 2, 0, 1}
+	for i, b := range TTLDistribution(entries, now) {
+		if b.Entries != want[i] {
+			t.Errorf("bucket %s = %d, want %d", b.Label, b.Entries, want[i])
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// commentStyle describes a language's comment syntax
+type commentStyle struct {
+	line       []string // Line comment markers
+	blockStart string
+	blockEnd   string
+}
+
+var (
+	cStyle    = commentStyle{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	hashStyle = commentStyle{line: []string{"#"}}
+)
+
+// commentStyles maps languages to their comment syntax
+var commentStyles = map[string]commentStyle{
+	"go":         cStyle,
+	"javascript": cStyle,
+	"typescript": cStyle,
+	"java":       cStyle,
+	"kotlin":     cStyle,
+	"swift":      cStyle,
+	"c":          cStyle,
+	"cpp":        cStyle,
+	"csharp":     cStyle,
+	"rust":       cStyle,
+	"scala":      cStyle,
+	"dart":       cStyle,
+	"objectivec": cStyle,
+	"php":        {line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/"},
+	"python":     hashStyle,
+	"ruby":       hashStyle,
+	"shell":      hashStyle,
+	"yaml":       hashStyle,
+	"toml":       hashStyle,
+	"dockerfile": hashStyle,
+	"makefile":   hashStyle,
+	"perl":       hashStyle,
+	"r":          hashStyle,
+	"sql":        {line: []string{"--"}, blockStart: "/*", blockEnd: "*/"},
+	"lua":        {line: []string{"--"}},
+}
+
+// indentSensitive languages keep leading whitespace when normalizing
+var indentSensitive = map[string]bool{
+	"python":   true,
+	"yaml":     true,
+	"makefile": true,
+}
+
+// importPatterns match single-line import statements per language, after
+// normalizeWhitespace has dropped the space before quotes
+var importPatterns = map[string]*regexp.Regexp{
+	"go":         regexp.MustCompile(`^import\s*(\w+\s*)?"[^"]+"$`),
+	"python":     regexp.MustCompile(`^(import\s+[\w.]+(\s+as\s+\w+)?|from\s+[\w.]+\s+import\s+.+)$`),
+	"javascript": regexp.MustCompile(`^import\b.+\bfrom\s*['"][^'"]+['"];?$|^import\s*['"][^'"]+['"];?$`),
+	"typescript": regexp.MustCompile(`^import\b.+\bfrom\s*['"][^'"]+['"];?$|^import\s*['"][^'"]+['"];?$`),
+	"java":       regexp.MustCompile(`^import\s+(static\s+)?[\w.*]+;$`),
+	"kotlin":     regexp.MustCompile(`^import\s+[\w.*]+(\s+as\s+\w+)?$`),
+	"csharp":     regexp.MustCompile(`^using\s+[\w.]+;$`),
+	"rust":       regexp.MustCompile(`^use\s+.+;$`),
+}
+
+// goImportSpec matches a spec inside a Go import block
+var goImportSpec = regexp.MustCompile(`^(\w+|\.|_)?\s*"[^"]+"$`)
+
+// normalizedLine is a diff line reduced to its semantic content
+type normalizedLine struct {
+	marker byte // '+', '-' or ' '
+	text   string
+}
+
+// NormalizeDiff reduces a unified diff to the changes that matter for a
+// review: whitespace-only and comment-only edits are dropped and imports are
+// compared regardless of order. Hunk headers are ignored so shifted line
+// numbers don't change the result.
+func NormalizeDiff(diff, language string) string {
+	style, hasComments := commentStyles[language]
+	keepIndent := indentSensitive[language]
+
+	var hunks [][]normalizedLine
+	var current []normalizedLine
+	inBlock := false
+
+	for _, raw := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(raw, "@@") {
+			if len(current) > 0 {
+				hunks = append(hunks, current)
+			}
+			current = nil
+			continue
+		}
+		if raw == "" {
+			continue
+		}
+
+		marker, content := raw[0], raw[1:]
+		if marker != '+' && marker != '-' && marker != ' ' {
+			marker, content = ' ', raw
+		}
+
+		if hasComments {
+			content, inBlock = stripComments(content, style, inBlock)
+		}
+		text := normalizeWhitespace(content, keepIndent)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		current = append(current, normalizedLine{marker: marker, text: text})
+	}
+	if len(current) > 0 {
+		hunks = append(hunks, current)
+	}
+
+	var imports []string
+	var sb strings.Builder
+	for _, hunk := range hunks {
+		hunk = cancelUnchanged(hunk, language)
+		if !hasChanges(hunk) {
+			continue
+		}
+		hunk, hunkImports := extractImports(hunk, language)
+		imports = append(imports, hunkImports...)
+		for _, l := range hunk {
+			sb.WriteByte(l.marker)
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+		}
+		sb.WriteString("@@\n")
+	}
+
+	if len(imports) == 0 {
+		return sb.String()
+	}
+	sort.Strings(imports)
+	return "imports:\n" + strings.Join(imports, "\n") + "\n@@\n" + sb.String()
+}
+
+// ComputeNormalizedKey generates a cache key from a review request using the
+// normalized diff, so trivial edits after a cached review still hit.
+func ComputeNormalizedKey(req *providers.ReviewRequest) string {
+	normalized := NormalizeDiff(req.Diff, req.Language)
+	if normalized == "" {
+		// Nothing survived normalization: only the same trivial diff may
+		// reuse the review, not any other change that normalizes away
+		normalized = req.Diff
+	}
+	fields := map[string]interface{}{
+		"normalized": normalized,
+		"language":   req.Language,
+		"path":       req.FilePath,
+		"rules":      req.Rules,
+		"types":      req.IssueTypes,
+	}
+	if req.Context != "" {
+		fields["context"] = req.Context
+	}
+	if len(req.Focus) > 0 {
+		fields["focus"] = req.Focus
+	}
+	if req.Related != "" {
+		fields["related"] = req.Related
+	}
+	if req.ChangeSummary != "" {
+		fields["change_summary"] = req.ChangeSummary
+	}
+	if req.Intent != "" {
+		fields["intent"] = req.Intent
+	}
+	if len(req.Conventions) > 0 {
+		fields["conventions"] = req.Conventions
+	}
+	if req.Format != "" {
+		fields["format"] = req.Format
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		data = []byte(req.Diff)
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// stripComments removes comments from a line, skipping markers inside string
+// literals. inBlock tracks an open block comment across lines.
+func stripComments(line string, style commentStyle, inBlock bool) (string, bool) {
+	var sb strings.Builder
+	var quote byte
+
+	for i := 0; i < len(line); i++ {
+		if inBlock {
+			if style.blockEnd != "" && strings.HasPrefix(line[i:], style.blockEnd) {
+				inBlock = false
+				i += len(style.blockEnd) - 1
+			}
+			continue
+		}
+
+		c := line[i]
+		if quote != 0 {
+			sb.WriteByte(c)
+			if c == '\\' && i+1 < len(line) {
+				i++
+				sb.WriteByte(line[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+			sb.WriteByte(c)
+		case style.blockStart != "" && strings.HasPrefix(line[i:], style.blockStart):
+			inBlock = true
+			i += len(style.blockStart) - 1
+		case hasLineComment(line[i:], style):
+			return sb.String(), false
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), inBlock
+}
+
+func hasLineComment(s string, style commentStyle) bool {
+	for _, marker := range style.line {
+		if strings.HasPrefix(s, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeWhitespace drops whitespace next to brackets and separators and
+// between words and operators, so "foo( x )" and "foo(x)" compare equal.
+// Whitespace between two words or two operators is kept as one space, since
+// it separates tokens: "a - -b" and "a--b" differ. Indentation is kept for
+// indentation-sensitive languages.
+func normalizeWhitespace(s string, keepIndent bool) string {
+	var sb strings.Builder
+	if keepIndent {
+		indent := s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+		sb.WriteString(strings.ReplaceAll(indent, "\t", "    "))
+	}
+
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		if i > 0 && separates(fields[i-1][len(fields[i-1])-1], f[0]) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(f)
+	}
+	return sb.String()
+}
+
+// separates reports whether whitespace between bytes a and b splits
+// tokens that would otherwise merge.
+func separates(a, b byte) bool {
+	if isTightByte(a) || isTightByte(b) {
+		return false
+	}
+	return isWordByte(a) == isWordByte(b)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// isTightByte reports whether c is a bracket or separator, which never
+// merges with its neighbors.
+func isTightByte(c byte) bool {
+	return strings.IndexByte("()[]{},;", c) >= 0
+}
+
+// cancelUnchanged turns removed/added pairs with identical normalized text
+// into context lines, dropping whitespace-only and comment-only edits. Only
+// the lines facing each other in a block of changes are paired, so moving a
+// statement still counts as a change; import lines pair anywhere in the
+// hunk, since their order doesn't matter.
+func cancelUnchanged(hunk []normalizedLine, language string) []normalizedLine {
+	drop := make([]bool, len(hunk))
+	keep := make([]bool, len(hunk))
+
+	// Pair the n-th removed line of each block with its n-th added line
+	for start := 0; start < len(hunk); {
+		if hunk[start].marker == ' ' {
+			start++
+			continue
+		}
+		end := start
+		var removed, added []int
+		for ; end < len(hunk) && hunk[end].marker != ' '; end++ {
+			if hunk[end].marker == '-' {
+				removed = append(removed, end)
+			} else {
+				added = append(added, end)
+			}
+		}
+		for i := 0; i < min(len(removed), len(added)); i++ {
+			if hunk[removed[i]].text == hunk[added[i]].text {
+				drop[removed[i]], keep[added[i]] = true, true
+			}
+		}
+		start = end
+	}
+
+	// Pair the remaining import lines by text
+	removedImports := make(map[string][]int)
+	for i, l := range hunk {
+		if l.marker == '-' && !drop[i] && isImport(l.text, language) {
+			removedImports[l.text] = append(removedImports[l.text], i)
+		}
+	}
+	for i, l := range hunk {
+		if l.marker != '+' || keep[i] || len(removedImports[l.text]) == 0 {
+			continue
+		}
+		drop[removedImports[l.text][0]], keep[i] = true, true
+		removedImports[l.text] = removedImports[l.text][1:]
+	}
+
+	out := make([]normalizedLine, 0, len(hunk))
+	for i, l := range hunk {
+		if drop[i] {
+			continue
+		}
+		if keep[i] {
+			l.marker = ' '
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+func hasChanges(hunk []normalizedLine) bool {
+	for _, l := range hunk {
+		if l.marker != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+// extractImports removes import lines from the hunk and returns them with
+// their markers, so import order doesn't affect the normalized diff.
+func extractImports(hunk []normalizedLine, language string) ([]normalizedLine, []string) {
+	pattern := importPatterns[language]
+	if pattern == nil {
+		return hunk, nil
+	}
+
+	var imports []string
+	out := make([]normalizedLine, 0, len(hunk))
+	for _, l := range hunk {
+		text := strings.TrimSpace(l.text)
+		if language == "go" && text == "import (" {
+			continue
+		}
+		if isImport(text, language) {
+			imports = append(imports, string(l.marker)+text)
+			continue
+		}
+		out = append(out, l)
+	}
+	return out, imports
+}
+
+// isImport reports whether a normalized line is an import statement. Go
+// import block specs are matched on their own, since the hunk may not
+// include the "import (" line.
+func isImport(text, language string) bool {
+	pattern := importPatterns[language]
+	if pattern == nil {
+		return false
+	}
+	text = strings.TrimSpace(text)
+	return pattern.MatchString(text) || (language == "go" && goImportSpec.MatchString(text))
+}
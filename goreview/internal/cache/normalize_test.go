@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+const baseGoDiff = `@@ -1,8 +1,10 @@
+ import (
+ 	"fmt"
++	"os"
+ 	"strings"
+ )
+ 
+ func run() {
++	os.Exit(run2())
+ }
+`
+
+func TestNormalizeDiffEquivalent(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		a, b     string
+	}{
+		{
+			name:     "shifted hunk header",
+			language: "go",
+			a:        baseGoDiff,
+			b:        "@@ -10,8 +12,10 @@\n" + baseGoDiff[len("@@ -1,8 +1,10 @@\n"):],
+		},
+		{
+			name:     "whitespace only",
+			language: "go",
+			a:        "@@ -1 +1 @@\n-x := 1\n+x := compute(a, b)\n",
+			b:        "@@ -1 +1 @@\n-x := 1\n+x  :=   compute(a, b)   \n",
+		},
+		{
+			name:     "comment only",
+			language: "go",
+			a:        "@@ -1,2 +1,2 @@\n-x := 1\n+x := 2\n",
+			b:        "@@ -1,3 +1,4 @@\n-x := 1\n+// bump x\n+x := 2 // was 1\n /* unchanged */\n",
+		},
+		{
+			name:     "whitespace-only hunk dropped",
+			language: "javascript",
+			a:        "@@ -1 +1 @@\n-let a = 1;\n+let a = 2;\n",
+			b:        "@@ -1 +1 @@\n-let a = 1;\n+let a = 2;\n@@ -20 +20 @@\n-foo( x );\n+foo(x);\n",
+		},
+		{
+			name:     "reordered imports",
+			language: "python",
+			a:        "@@ -1,3 +1,4 @@\n import os\n import sys\n+import json\n+x = json.dumps(os.environ)\n",
+			b:        "@@ -1,3 +1,4 @@\n+import json\n import os\n import sys\n+x = json.dumps(os.environ)\n",
+		},
+		{
+			name:     "reordered javascript imports",
+			language: "javascript",
+			a:        "@@ -1,2 +1,3 @@\n-import { a } from 'a';\n import b from 'b';\n+import { a } from 'a';\n+a(b);\n",
+			b:        "@@ -1,2 +1,3 @@\n import { a } from 'a';\n import b from 'b';\n+a(b);\n",
+		},
+		{
+			name:     "moved go import spec",
+			language: "go",
+			a:        "@@ -1,4 +1,5 @@\n \"fmt\"\n+\"os\"\n \"strings\"\n",
+			b:        "@@ -1,4 +1,5 @@\n+\"os\"\n \"fmt\"\n \"strings\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NormalizeDiff(tt.a, tt.language)
+			b := NormalizeDiff(tt.b, tt.language)
+			if a != b {
+				t.Errorf("normalized diffs differ:\n%s\n---\n%s", a, b)
+			}
+		})
+	}
+}
+
+func TestNormalizeDiffDifferent(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		a, b     string
+	}{
+		{
+			name:     "code change",
+			language: "go",
+			a:        "@@ -1 +1 @@\n-x := 1\n+x := 2\n",
+			b:        "@@ -1 +1 @@\n-x := 1\n+x := 3\n",
+		},
+		{
+			name:     "comment marker inside string",
+			language: "go",
+			a:        "@@ -1 +1 @@\n+url := \"http://a\"\n",
+			b:        "@@ -1 +1 @@\n+url := \"http://b\"\n",
+		},
+		{
+			name:     "python indentation",
+			language: "python",
+			a:        "@@ -1,2 +1,2 @@\n if x:\n+    y()\n",
+			b:        "@@ -1,2 +1,2 @@\n if x:\n+y()\n",
+		},
+		{
+			name:     "statement moved",
+			language: "go",
+			a:        "@@ -1,3 +1,3 @@\n mu.Lock()\n-use(x)\n mu.Unlock()\n+use(x)\n",
+			b:        "@@ -1,3 +1,3 @@\n mu.Lock()\n-use(x)\n+use(x)\n mu.Unlock()\n",
+		},
+		{
+			name:     "statements swapped",
+			language: "go",
+			a:        "@@ -1,2 +1,2 @@\n-a()\n-b()\n+b()\n+a()\n",
+			b:        "@@ -1,2 +1,2 @@\n-a()\n-b()\n+a()\n+b()\n",
+		},
+		{
+			name:     "operator spacing",
+			language: "c",
+			a:        "@@ -1 +1 @@\n-x = a - -b;\n+x = a--b;\n",
+			b:        "@@ -1 +1 @@\n-x = a - -b;\n+x = a - -b;\n",
+		},
+		{
+			name:     "added import",
+			language: "python",
+			a:        "@@ -1 +1,2 @@\n import os\n+import json\n",
+			b:        "@@ -1 +1,2 @@\n import os\n+import sys\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if NormalizeDiff(tt.a, tt.language) == NormalizeDiff(tt.b, tt.language) {
+				t.Errorf("normalized diffs should differ:\n%s", NormalizeDiff(tt.a, tt.language))
+			}
+		})
+	}
+}
+
+func TestComputeNormalizedKey(t *testing.T) {
+	req1 := &providers.ReviewRequest{Diff: "@@ -1 +1 @@\n+x := 1 // one\n", Language: "go", FilePath: "a.go"}
+	req2 := &providers.ReviewRequest{Diff: "@@ -3 +3 @@\n+x :=  1\n", Language: "go", FilePath: "a.go"}
+	req3 := &providers.ReviewRequest{Diff: req2.Diff, Language: "go", FilePath: "b.go"}
+
+	if ComputeKey(req1) == ComputeKey(req2) {
+		t.Error("exact keys should differ for different diffs")
+	}
+	if ComputeNormalizedKey(req1) != ComputeNormalizedKey(req2) {
+		t.Error("normalized keys should match for trivially different diffs")
+	}
+	if ComputeNormalizedKey(req2) == ComputeNormalizedKey(req3) {
+		t.Error("normalized keys should differ for different paths")
+	}
+}
+
+func TestComputeNormalizedKeyEmpty(t *testing.T) {
+	whitespace := &providers.ReviewRequest{Diff: "@@ -1 +1 @@\n-foo( x )\n+foo(x)\n", Language: "go", FilePath: "a.go"}
+	moved := &providers.ReviewRequest{Diff: "@@ -1,3 +1,3 @@\n mu.Lock()\n-use(x)\n mu.Unlock()\n+use(x)\n", Language: "go", FilePath: "a.go"}
+	spacing := &providers.ReviewRequest{Diff: "@@ -1 +1 @@\n-x := a - -b\n+x := a--b\n", Language: "go", FilePath: "a.go"}
+	comment := &providers.ReviewRequest{Diff: "@@ -1 +1 @@\n-x := 1 // one\n+x := 1 // uno\n", Language: "go", FilePath: "a.go"}
+
+	if NormalizeDiff(whitespace.Diff, "go") != "" {
+		t.Fatalf("whitespace-only diff normalized to %q, want empty", NormalizeDiff(whitespace.Diff, "go"))
+	}
+	for name, req := range map[string]*providers.ReviewRequest{"moved": moved, "spacing": spacing, "comment": comment} {
+		if ComputeNormalizedKey(req) == ComputeNormalizedKey(whitespace) {
+			t.Errorf("%s diff shares the normalized key of a whitespace-only diff", name)
+		}
+	}
+	if ComputeNormalizedKey(whitespace) != ComputeNormalizedKey(&providers.ReviewRequest{Diff: whitespace.Diff, Language: "go", FilePath: "a.go"}) {
+		t.Error("the same trivial diff should keep its normalized key")
+	}
+}
@@ -0,0 +1,308 @@
+// Package causegraph links the issues of a review to the root causes found
+// by root cause tracing (--trace), through the propagation steps between
+// them, and renders the links as a Graphviz DOT or Mermaid graph. Issues
+// with the same cause, in any file, share its node, so causes upstream of
+// many issues stand out.
+package causegraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLabel caps the runes of a node label
+const maxLabel = 80
+
+// Node kinds
+const (
+	KindIssue = "issue"
+	KindCause = "cause"
+	KindStep  = "step"
+)
+
+// Issue is an issue and its root cause, as graphed.
+type Issue struct {
+	// ID identifies the issue within its file
+	ID       string
+	File     string
+	Line     int
+	Severity string
+	Message  string
+	Cause    *Cause
+}
+
+// Cause is the root cause of an issue.
+type Cause struct {
+	Description string
+	// File and Line are where the cause is, when known; File defaults to
+	// the issue's
+	File string
+	Line int
+	// Path are the steps from the cause to the issue
+	Path []string
+	// Related are the IDs of issues of the same file with the same cause
+	Related []string
+}
+
+// Node is a node of the graph.
+type Node struct {
+	ID    string
+	Kind  string
+	Label string
+	// File groups the node with those of its file; steps have none
+	File     string
+	Severity string
+}
+
+// Edge links a cause or step to what it leads to, or an issue to a
+// related one.
+type Edge struct {
+	From, To string
+	Related  bool
+}
+
+// Graph is the graph of a review's root causes.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Build graphs the issues with a root cause; the others are left out.
+func Build(issues []Issue) *Graph {
+	g := &Graph{}
+	ids := make(map[string]string)      // Node key -> node ID
+	issueIDs := make(map[string]string) // file#id -> node ID
+	edges := make(map[Edge]bool)
+	node := func(key string, n Node) string {
+		if id, ok := ids[key]; ok {
+			return id
+		}
+		n.ID = fmt.Sprintf("n%d", len(g.Nodes)+1)
+		ids[key] = n.ID
+		g.Nodes = append(g.Nodes, n)
+		return n.ID
+	}
+	edge := func(e Edge) {
+		if e.From != e.To && !edges[e] {
+			edges[e] = true
+			g.Edges = append(g.Edges, e)
+		}
+	}
+
+	for i, issue := range issues {
+		if issue.Cause == nil {
+			continue
+		}
+		label := issue.Message
+		if issue.Severity != "" {
+			label = "[" + issue.Severity + "] " + label
+		}
+		issueID := node(fmt.Sprintf("issue %d", i), Node{
+			Kind: KindIssue, Label: label + "\n" + location(issue.File, issue.Line), File: issue.File, Severity: issue.Severity,
+		})
+		if issue.ID != "" {
+			issueIDs[issue.File+"#"+issue.ID] = issueID
+		}
+
+		c := issue.Cause
+		file := c.File
+		if file == "" {
+			file = issue.File
+		}
+		// Causes are the same when they are at the same place or, without
+		// a place, described the same
+		key := "cause " + normalize(c.Description)
+		label = c.Description
+		if c.Line > 0 {
+			key = "cause " + location(file, c.Line)
+			label += "\n" + location(file, c.Line)
+		}
+		if strings.TrimSpace(label) == "" {
+			continue
+		}
+		from := node(key, Node{Kind: KindCause, Label: label, File: file})
+		for _, step := range c.Path {
+			if normalize(step) == "" {
+				continue
+			}
+			to := node("step "+normalize(step), Node{Kind: KindStep, Label: step})
+			edge(Edge{From: from, To: to})
+			from = to
+		}
+		edge(Edge{From: from, To: issueID})
+	}
+
+	// Related issues are linked once both are in the graph
+	for _, issue := range issues {
+		if issue.Cause == nil {
+			continue
+		}
+		from := issueIDs[issue.File+"#"+issue.ID]
+		for _, related := range issue.Cause.Related {
+			if to, ok := issueIDs[issue.File+"#"+related]; ok && from != "" {
+				a, b := from, to
+				if a > b {
+					a, b = b, a
+				}
+				edge(Edge{From: a, To: b, Related: true})
+			}
+		}
+	}
+	return g
+}
+
+// Empty reports whether no issue has a root cause.
+func (g *Graph) Empty() bool {
+	return len(g.Nodes) == 0
+}
+
+// DOT renders the graph in the Graphviz DOT language, with a cluster per
+// file.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph causes {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\", fontsize=10];\n")
+	for i, file := range g.files() {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n    label=%s;\n", i, dotString(file))
+		for _, n := range g.Nodes {
+			if n.File == file {
+				b.WriteString("    " + dotNode(n) + "\n")
+			}
+		}
+		b.WriteString("  }\n")
+	}
+	for _, n := range g.Nodes {
+		if n.File == "" {
+			b.WriteString("  " + dotNode(n) + "\n")
+		}
+	}
+	for _, e := range g.Edges {
+		attrs := ""
+		if e.Related {
+			attrs = " [style=dashed, dir=none]"
+		}
+		fmt.Fprintf(&b, "  %s -> %s%s;\n", e.From, e.To, attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, with a subgraph per
+// file.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, file := range g.files() {
+		fmt.Fprintf(&b, "  subgraph f%d[%s]\n", i, mermaidString(file))
+		for _, n := range g.Nodes {
+			if n.File == file {
+				b.WriteString("    " + mermaidNode(n) + "\n")
+			}
+		}
+		b.WriteString("  end\n")
+	}
+	for _, n := range g.Nodes {
+		if n.File == "" {
+			b.WriteString("  " + mermaidNode(n) + "\n")
+		}
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Related {
+			arrow = "-.-"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", e.From, arrow, e.To)
+	}
+	b.WriteString("  classDef cause fill:#fff3cd,stroke:#b08800\n")
+	b.WriteString("  classDef critical fill:#ffebe9,stroke:#cf222e\n")
+	for _, kind := range []string{KindCause, "critical"} {
+		var ids []string
+		for _, n := range g.Nodes {
+			if n.Kind == kind || (kind == "critical" && n.Kind == KindIssue && (n.Severity == "critical" || n.Severity == "error")) {
+				ids = append(ids, n.ID)
+			}
+		}
+		if len(ids) > 0 {
+			fmt.Fprintf(&b, "  class %s %s\n", strings.Join(ids, ","), kind)
+		}
+	}
+	return b.String()
+}
+
+// files returns the files of the nodes, sorted.
+func (g *Graph) files() []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, n := range g.Nodes {
+		if n.File != "" && !seen[n.File] {
+			seen[n.File] = true
+			files = append(files, n.File)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func dotNode(n Node) string {
+	attrs := "shape=box"
+	switch {
+	case n.Kind == KindCause:
+		attrs = "shape=box, style=\"rounded,filled\", fillcolor=\"#fff3cd\""
+	case n.Kind == KindStep:
+		attrs = "shape=ellipse"
+	case n.Severity == "critical" || n.Severity == "error":
+		attrs = "shape=box, style=filled, fillcolor=\"#ffebe9\""
+	}
+	return fmt.Sprintf("%s [label=%s, %s];", n.ID, dotString(n.Label), attrs)
+}
+
+func mermaidNode(n Node) string {
+	label := mermaidString(n.Label)
+	switch n.Kind {
+	case KindCause:
+		return n.ID + "[/" + label + "/]"
+	case KindStep:
+		return n.ID + "(" + label + ")"
+	}
+	return n.ID + "[" + label + "]"
+}
+
+// dotString quotes a label for DOT, its lines centered.
+func dotString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(truncateLines(s))
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// mermaidString quotes a label for Mermaid, which takes HTML entities and
+// line breaks.
+func mermaidString(s string) string {
+	s = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(truncateLines(s))
+	return `"` + strings.ReplaceAll(s, "\n", "<br/>") + `"`
+}
+
+// truncateLines cuts each line of a label to maxLabel runes.
+func truncateLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if utf8.RuneCountInString(l) > maxLabel {
+			lines[i] = string([]rune(l)[:maxLabel-3]) + "..."
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func location(file string, line int) string {
+	if line > 0 {
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+	return file
+}
+
+// normalize folds case and spacing, so rewordings of a step or cause that
+// only differ in them match.
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
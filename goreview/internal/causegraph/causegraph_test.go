@@ -0,0 +1,99 @@
+package causegraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	shared := func() *Cause {
+		return &Cause{
+			Description: "Config is read without validation",
+			File:        "internal/config/load.go",
+			Line:        12,
+			Path:        []string{"Load returns the raw value", "  load RETURNS the raw value "},
+		}
+	}
+	g := Build([]Issue{
+		{ID: "a", File: "cmd/serve.go", Line: 30, Severity: "error", Message: "nil dereference of cfg.Server", Cause: shared()},
+		{ID: "b", File: "cmd/worker.go", Line: 8, Severity: "warning", Message: "port may be 0", Cause: shared()},
+		{ID: "c", File: "cmd/worker.go", Line: 20, Severity: "warning", Message: "timeout may be negative",
+			Cause: &Cause{Description: "Timeout isn't checked", Related: []string{"b", "missing"}}},
+		{ID: "d", File: "cmd/worker.go", Line: 40, Message: "no root cause"},
+	})
+
+	kinds := make(map[string]int)
+	for _, n := range g.Nodes {
+		kinds[n.Kind]++
+	}
+	// Both issues share the cause and its step, whose rewording folds into
+	// the same node; the issue without a cause is left out
+	if kinds[KindIssue] != 3 || kinds[KindCause] != 2 || kinds[KindStep] != 1 {
+		t.Fatalf("nodes = %+v", g.Nodes)
+	}
+	if len(g.Edges) != 5 {
+		t.Fatalf("edges = %+v", g.Edges)
+	}
+	var related int
+	for _, e := range g.Edges {
+		if e.Related {
+			related++
+		}
+	}
+	if related != 1 {
+		t.Errorf("related edges = %d, want 1", related)
+	}
+	if c := g.Nodes[1]; c.Kind != KindCause || c.File != "internal/config/load.go" {
+		t.Errorf("cause = %+v", c)
+	}
+	// Without a place, the cause is in the issue's file
+	for _, n := range g.Nodes {
+		if n.Kind == KindCause && n.Label == "Timeout isn't checked" && n.File != "cmd/worker.go" {
+			t.Errorf("cause without a place = %+v", n)
+		}
+	}
+
+	if !Build([]Issue{{File: "a.go", Message: "x"}}).Empty() {
+		t.Error("graph of issues without causes isn't empty")
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := Build([]Issue{{
+		ID: "a", File: "main.go", Line: 3, Severity: "critical", Message: `uses "unsafe" <here>`,
+		Cause: &Cause{Description: "Input isn't sanitized", File: "input.go", Line: 9, Path: []string{"read"}},
+	}})
+
+	dot := g.DOT()
+	for _, want := range []string{
+		"digraph causes {",
+		`label="input.go";`,
+		`[label="[critical] uses \"unsafe\" <here>\nmain.go:3", shape=box, style=filled`,
+		"n2 -> n3;",
+		"n3 -> n1;",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT() missing %q:\n%s", want, dot)
+		}
+	}
+
+	mermaid := g.Mermaid()
+	for _, want := range []string{
+		"flowchart LR",
+		`subgraph f1["main.go"]`,
+		`n1["[critical] uses #quot;unsafe#quot; #lt;here#gt;<br/>main.go:3"]`,
+		`n2[/"Input isn't sanitized<br/>input.go:9"/]`,
+		`n3("read")`,
+		"class n2 cause",
+		"class n1 critical",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid() missing %q:\n%s", want, mermaid)
+		}
+	}
+
+	long := strings.Repeat("x", 100)
+	if got := truncateLines(long + "\nshort"); got != strings.Repeat("x", maxLabel-3)+"...\nshort" {
+		t.Errorf("truncateLines() = %q", got)
+	}
+}
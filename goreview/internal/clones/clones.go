@@ -0,0 +1,202 @@
+// Package clones detects duplicated code. Source is tokenized with literals
+// normalized, hashed into k-gram shingles and reduced to a winnowing
+// fingerprint set; code whose fingerprints mostly appear elsewhere in the
+// repository is reported as a clone of that location.
+package clones
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+const (
+	// kgram is the number of tokens per shingle
+	kgram = 5
+	// window is the winnowing window, in shingles; any match of at least
+	// kgram+window-1 tokens is guaranteed to share a fingerprint
+	window = 4
+)
+
+// fingerprint is a selected shingle hash and the lines its tokens span.
+type fingerprint struct {
+	hash      uint64
+	startLine int
+	endLine   int
+}
+
+// posting is an indexed fingerprint occurrence.
+type posting struct {
+	file      string
+	startLine int
+	endLine   int
+}
+
+// Location is a range of lines in a file.
+type Location struct {
+	File      string
+	StartLine int
+	EndLine   int
+}
+
+// Match is existing code that duplicates a queried block.
+type Match struct {
+	Location
+	// Similarity is the share of the block's fingerprints found in Location
+	Similarity float64
+}
+
+// Index holds the fingerprints of the indexed files, by language.
+type Index struct {
+	postings map[string]map[uint64][]posting
+}
+
+// NewIndex creates an empty index.
+func NewIndex() *Index {
+	return &Index{postings: make(map[string]map[uint64][]posting)}
+}
+
+// Add indexes a file. marker is the language's line comment marker, used to
+// skip comments.
+func (x *Index) Add(file, language, marker, content string) {
+	byHash := x.postings[language]
+	if byHash == nil {
+		byHash = make(map[uint64][]posting)
+		x.postings[language] = byHash
+	}
+	for _, fp := range winnow(tokenize(content, marker, 1)) {
+		byHash[fp.hash] = append(byHash[fp.hash], posting{file: file, startLine: fp.startLine, endLine: fp.endLine})
+	}
+}
+
+// Query describes a block of new code to look up.
+type Query struct {
+	// File, Language and Marker describe the block's file, as in Add
+	File     string
+	Language string
+	Marker   string
+	// Code is the block's source and StartLine its first line in File
+	Code      string
+	StartLine int
+	// MinTokens skips blocks shorter than this many tokens
+	MinTokens int
+	// MinSimilarity is the lowest Similarity reported, between 0 and 1
+	MinSimilarity float64
+}
+
+// Find returns the existing code that the block duplicates most closely, or
+// nil when no location reaches the minimum similarity. The block's own lines
+// are never matched against themselves.
+func (x *Index) Find(q Query) *Match {
+	tokens := tokenize(q.Code, q.Marker, q.StartLine)
+	if len(tokens) == 0 || len(tokens) < q.MinTokens {
+		return nil
+	}
+	blockEnd := tokens[len(tokens)-1].line
+	fps := winnow(tokens)
+	byHash := x.postings[q.Language]
+	if len(fps) == 0 || byHash == nil {
+		return nil
+	}
+
+	// Occurrences of the block's fingerprints, by candidate file
+	hits := make(map[string][]hit)
+	for i, fp := range fps {
+		for _, p := range byHash[fp.hash] {
+			if p.file == q.File && p.endLine >= q.StartLine && p.startLine <= blockEnd {
+				continue
+			}
+			hits[p.file] = append(hits[p.file], hit{posting: p, fp: i})
+		}
+	}
+
+	var best *Match
+	for _, file := range sortedKeys(hits) {
+		loc, matched := densestRegion(hits[file], blockEnd-q.StartLine+1)
+		similarity := float64(matched) / float64(len(fps))
+		if similarity < q.MinSimilarity || (best != nil && similarity <= best.Similarity) {
+			continue
+		}
+		loc.File = file
+		best = &Match{Location: loc, Similarity: similarity}
+	}
+	return best
+}
+
+// hit is an occurrence of the block fingerprint fp.
+type hit struct {
+	posting
+	fp int
+}
+
+// densestRegion finds the range of lines matching the most distinct block
+// fingerprints. The range may be somewhat longer than the block, but
+// fingerprints scattered across a file don't count as one copy.
+func densestRegion(hits []hit, blockLines int) (Location, int) {
+	sort.Slice(hits, func(i, j int) bool { return hits[i].startLine < hits[j].startLine })
+	maxSpan := 2*blockLines + 5
+
+	var best Location
+	bestCount := 0
+	counts := make(map[int]int)
+	end := 0
+	for start := range hits {
+		for end < len(hits) && (end == start || hits[end].endLine-hits[start].startLine < maxSpan) {
+			counts[hits[end].fp]++
+			end++
+		}
+		if len(counts) > bestCount {
+			bestCount = len(counts)
+			best = Location{StartLine: hits[start].startLine}
+			for _, h := range hits[start:end] {
+				best.EndLine = max(best.EndLine, h.endLine)
+			}
+		}
+		if counts[hits[start].fp]--; counts[hits[start].fp] == 0 {
+			delete(counts, hits[start].fp)
+		}
+	}
+	return best, bestCount
+}
+
+// winnow hashes the k-grams of the tokens and keeps the minimum hash of
+// every window, the rightmost one on ties.
+func winnow(tokens []token) []fingerprint {
+	if len(tokens) < kgram {
+		return nil
+	}
+	grams := make([]fingerprint, 0, len(tokens)-kgram+1)
+	for i := 0; i+kgram <= len(tokens); i++ {
+		h := fnv.New64a()
+		for _, t := range tokens[i : i+kgram] {
+			_, _ = h.Write([]byte(t.text))
+			_, _ = h.Write([]byte{0})
+		}
+		grams = append(grams, fingerprint{hash: h.Sum64(), startLine: tokens[i].line, endLine: tokens[i+kgram-1].line})
+	}
+
+	w := min(window, len(grams))
+	var fps []fingerprint
+	last := -1
+	for i := 0; i+w <= len(grams); i++ {
+		pick := i
+		for j := i; j < i+w; j++ {
+			if grams[j].hash <= grams[pick].hash {
+				pick = j
+			}
+		}
+		if pick != last {
+			fps = append(fps, grams[pick])
+			last = pick
+		}
+	}
+	return fps
+}
+
+func sortedKeys(m map[string][]hit) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,118 @@
+package clones
+
+import (
+	"strings"
+	"testing"
+)
+
+const original = `package store
+
+// load reads the records of a user
+func load(db *DB, id int) ([]Record, error) {
+	rows, err := db.Query("SELECT * FROM records WHERE user = ?", id)
+	if err != nil {
+		return nil, fmt.Errorf("query records: %w", err)
+	}
+	defer rows.Close()
+	var records []Record
+	for rows.Next() {
+		var r Record
+		if err := rows.Scan(&r.ID, &r.Name, &r.Value); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+`
+
+// copied is the body of load with other literals and a comment
+const copied = `	rows, err := db.Query("SELECT * FROM records WHERE owner = ?", id)
+	if err != nil {
+		// wrap the error
+		return nil, fmt.Errorf("list records: %w", err)
+	}
+	defer rows.Close()
+	var records []Record
+	for rows.Next() {
+		var r Record
+		if err := rows.Scan(&r.ID, &r.Name, &r.Value); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()`
+
+func TestFind(t *testing.T) {
+	index := NewIndex()
+	index.Add("store/load.go", "go", "//", original)
+	index.Add("other.py", "python", "#", original)
+
+	query := Query{File: "api/list.go", Language: "go", Marker: "//", Code: copied, StartLine: 40, MinTokens: 20, MinSimilarity: 0.8}
+	match := index.Find(query)
+	if match == nil {
+		t.Fatal("Find() = nil, want a match")
+	}
+	// Fingerprints sample the code, so the range may stop short of its last line
+	if match.File != "store/load.go" || match.StartLine != 5 || match.EndLine < 17 || match.EndLine > 19 {
+		t.Errorf("match = %+v, want store/load.go:5-19", match.Location)
+	}
+	if match.Similarity < 0.99 {
+		t.Errorf("similarity = %.2f, want 1", match.Similarity)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(q *Query)
+	}{
+		{"too short", func(q *Query) { q.MinTokens = 1000 }},
+		{"other language", func(q *Query) { q.Language = "rust" }},
+		{"own lines", func(q *Query) { q.File, q.StartLine = "store/load.go", 5 }},
+		{"different code", func(q *Query) {
+			q.Code = strings.Repeat("total += compute(item.Price, item.Count) * rate\n", 3) + "return total, nil"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := query
+			tt.modify(&q)
+			if m := index.Find(q); m != nil {
+				t.Errorf("Find() = %+v, want nil", m)
+			}
+		})
+	}
+}
+
+func TestFindPartialCopy(t *testing.T) {
+	index := NewIndex()
+	index.Add("store/load.go", "go", "//", original)
+
+	// Half of the block is new code
+	code := copied + "\n" + strings.Repeat("\tcache.Put(key(id), records, ttl)\n\tmetrics.Inc(counterName)\n", 6)
+	q := Query{File: "api/list.go", Language: "go", Marker: "//", Code: code, StartLine: 1, MinTokens: 20}
+	match := index.Find(q)
+	if match == nil || match.Similarity > 0.8 || match.Similarity < 0.3 {
+		t.Fatalf("Find() = %+v, want a partial match", match)
+	}
+	q.MinSimilarity = 0.8
+	if m := index.Find(q); m != nil {
+		t.Errorf("Find() = %+v, want nil below the threshold", m)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	src := "x := \"a // b\" + 42 // note\n/* block\ncomment */ y = 'c'"
+	var got []string
+	for _, tok := range tokenize(src, "//", 1) {
+		got = append(got, tok.text)
+	}
+	want := []string{"x", ":", "=", stringToken, "+", numberToken, "y", "=", stringToken}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("tokens = %q, want %q", got, want)
+	}
+
+	tokens := tokenize(src, "//", 1)
+	if last := tokens[len(tokens)-1]; last.line != 3 {
+		t.Errorf("last token line = %d, want 3", last.line)
+	}
+}
@@ -0,0 +1,90 @@
+package clones
+
+import (
+	"strings"
+	"unicode"
+)
+
+// token is a normalized source token and the 1-based line it starts on.
+type token struct {
+	text string
+	line int
+}
+
+// Normalized literals, so copies that only change a string or a number
+// still match
+const (
+	stringToken = "\x00str"
+	numberToken = "\x00num"
+)
+
+// tokenize splits source code into tokens, skipping whitespace and
+// comments. marker is the language's line comment marker; C-style block
+// comments are skipped when it is "//".
+func tokenize(src, marker string, firstLine int) []token {
+	var tokens []token
+	line := firstLine
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		switch {
+		case ch == '\n':
+			line++
+			i++
+		case unicode.IsSpace(ch):
+			i++
+		case marker != "" && hasPrefix(runes[i:], marker):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case marker == "//" && hasPrefix(runes[i:], "/*"):
+			i += 2
+			for i < len(runes) && !hasPrefix(runes[i:], "*/") {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i += 2
+		case ch == '"' || ch == '\'' || ch == '`':
+			start := line
+			i++
+			// Only raw strings span lines; others end at an unterminated newline
+			for i < len(runes) && runes[i] != ch && (ch == '`' || runes[i] != '\n') {
+				if runes[i] == '\\' && ch != '`' && i+1 < len(runes) && runes[i+1] != '\n' {
+					i++
+				} else if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i < len(runes) && runes[i] == ch {
+				i++
+			}
+			tokens = append(tokens, token{text: stringToken, line: start})
+		case unicode.IsDigit(ch):
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{text: numberToken, line: line})
+		case unicode.IsLetter(ch) || ch == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{text: string(runes[start:i]), line: line})
+		default:
+			tokens = append(tokens, token{text: string(ch), line: line})
+			i++
+		}
+	}
+	return tokens
+}
+
+func hasPrefix(runes []rune, prefix string) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	return strings.HasPrefix(string(runes[:len(prefix)]), prefix)
+}
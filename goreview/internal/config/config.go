@@ -8,6 +8,12 @@
 package config
 
 import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -40,6 +46,45 @@ type Config struct {
 
 	// Export configures export behavior to external systems
 	Export ExportConfig `mapstructure:"export" yaml:"export"`
+
+	// Knowledge configures the external knowledge sources searched with
+	// "goreview knowledge search"
+	Knowledge KnowledgeConfig `mapstructure:"knowledge" yaml:"knowledge"`
+
+	// Privacy configures what is redacted from saved provider transcripts
+	Privacy PrivacyConfig `mapstructure:"privacy" yaml:"privacy"`
+
+	// Serve configures "goreview serve", the HTTP review service
+	Serve ServeConfig `mapstructure:"serve" yaml:"serve"`
+
+	// Commit configures "goreview commit"
+	Commit CommitConfig `mapstructure:"commit" yaml:"commit"`
+
+	// Changelog configures "goreview changelog"
+	Changelog ChangelogConfig `mapstructure:"changelog" yaml:"changelog"`
+
+	// Gates are CI gate policies evaluated against the review result; when
+	// set, they decide the exit code instead of review.fail_on
+	Gates []GateConfig `mapstructure:"gates" yaml:"gates,omitempty"`
+
+	// Profile is the active profile, selected with --profile or this key.
+	// Profiles are defined under "profiles:" and override the settings above.
+	Profile string `mapstructure:"profile" yaml:"profile,omitempty"`
+
+	// StrictConfig fails loading on unknown keys in the config file, like
+	// --strict-config; otherwise they are warned about
+	StrictConfig bool `mapstructure:"strict_config" yaml:"strict_config,omitempty"`
+}
+
+// GateConfig is a CI gate: a named boolean expression over the review
+// result, see internal/gate for the language.
+type GateConfig struct {
+	// Name identifies the gate in reports
+	Name string `mapstructure:"name" yaml:"name"`
+
+	// Expr must be true for the gate to pass
+	// Example: "issues.critical == 0 && files.all(f, f.score > 60)"
+	Expr string `mapstructure:"expr" yaml:"expr"`
 }
 
 // RAGConfig configures the RAG system for external documentation.
@@ -61,6 +106,80 @@ type RAGConfig struct {
 
 	// Sources is the list of external documentation sources
 	Sources []RAGSource `mapstructure:"sources" yaml:"sources"`
+
+	// Packs configures the API knowledge packs
+	Packs KnowledgePacksConfig `mapstructure:"packs" yaml:"packs"`
+}
+
+// KnowledgePacksConfig configures knowledge packs: YAML files listing the
+// pitfalls and correct usage of an API, added to the review prompt of files
+// importing it. Built-in packs cover database/sql, net/http, React hooks
+// and Python requests.
+type KnowledgePacksConfig struct {
+	// Enabled turns the packs on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// Dirs are directories of custom packs; a custom pack replaces the
+	// built-in pack of the same name
+	Dirs []string `mapstructure:"dirs" yaml:"dirs"`
+
+	// Exclude lists built-in packs not to use, by name
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+}
+
+// KnowledgeConfig configures the external knowledge sources: team docs in
+// Notion, Confluence, Obsidian vaults, local directories or GitHub repos.
+type KnowledgeConfig struct {
+	// Enabled turns the sources on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// Sources are the knowledge sources
+	Sources []KnowledgeSourceConfig `mapstructure:"sources" yaml:"sources"`
+
+	// CacheDir is the directory for fetched documents
+	CacheDir string `mapstructure:"cache_dir" yaml:"cache_dir,omitempty"`
+
+	// MaxDocs is the most documents added to a review's context
+	MaxDocs int `mapstructure:"max_docs" yaml:"max_docs"`
+}
+
+// KnowledgeSourceConfig is a knowledge source. Which fields apply depends on
+// Type; tokens may reference environment variables as ${VAR}.
+type KnowledgeSourceConfig struct {
+	// Type is notion, confluence, obsidian, local or github
+	Type    string `mapstructure:"type" yaml:"type"`
+	Name    string `mapstructure:"name" yaml:"name"`
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled"`
+
+	NotionToken      string `mapstructure:"notion_token" yaml:"notion_token,omitempty"`
+	NotionDatabaseID string `mapstructure:"notion_database_id" yaml:"notion_database_id,omitempty"`
+	NotionPageID     string `mapstructure:"notion_page_id" yaml:"notion_page_id,omitempty"`
+
+	ConfluenceURL   string `mapstructure:"confluence_url" yaml:"confluence_url,omitempty"`
+	ConfluenceUser  string `mapstructure:"confluence_user" yaml:"confluence_user,omitempty"`
+	ConfluenceToken string `mapstructure:"confluence_token" yaml:"confluence_token,omitempty"`
+	ConfluenceSpace string `mapstructure:"confluence_space" yaml:"confluence_space,omitempty"`
+
+	ObsidianVaultPath string   `mapstructure:"obsidian_vault_path" yaml:"obsidian_vault_path,omitempty"`
+	ObsidianTags      []string `mapstructure:"obsidian_tags" yaml:"obsidian_tags,omitempty"`
+
+	LocalPath    string `mapstructure:"local_path" yaml:"local_path,omitempty"`
+	LocalPattern string `mapstructure:"local_pattern" yaml:"local_pattern,omitempty"`
+
+	GitHubOwner string `mapstructure:"github_owner" yaml:"github_owner,omitempty"`
+	GitHubRepo  string `mapstructure:"github_repo" yaml:"github_repo,omitempty"`
+	GitHubPath  string `mapstructure:"github_path" yaml:"github_path,omitempty"`
+}
+
+// PrivacyConfig configures the redaction of sensitive data from what
+// goreview saves outside the report, such as provider transcripts.
+type PrivacyConfig struct {
+	// RedactSecrets masks API keys, tokens, passwords and private keys
+	RedactSecrets bool `mapstructure:"redact_secrets" yaml:"redact_secrets"`
+
+	// RedactPatterns are regular expressions whose matches are also masked;
+	// when a pattern has a capture group, only the first group is masked
+	RedactPatterns []string `mapstructure:"redact_patterns" yaml:"redact_patterns,omitempty"`
 }
 
 // RAGSource represents an external documentation source.
@@ -75,7 +194,7 @@ type RAGSource struct {
 
 // ProviderConfig configures the AI provider.
 type ProviderConfig struct {
-	// Name is the provider name: "ollama", "openai"
+	// Name is the provider name: "ollama", "openai", "anthropic"
 	Name string `mapstructure:"name" yaml:"name"`
 
 	// Model is the model to use (e.g., "qwen2.5-coder:14b", "gpt-4")
@@ -88,7 +207,8 @@ type ProviderConfig struct {
 	// This should be set via environment variable, not config file
 	APIKey string `mapstructure:"api_key" yaml:"api_key"`
 
-	// Timeout is the request timeout
+	// Timeout is the request timeout; streamed answers (anthropic) wait
+	// at most this long for the headers and for each event
 	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout"`
 
 	// MaxTokens is the maximum tokens in response
@@ -99,6 +219,88 @@ type ProviderConfig struct {
 
 	// RateLimitRPS is requests per second limit (0 = unlimited)
 	RateLimitRPS int `mapstructure:"rate_limit_rps" yaml:"rate_limit_rps"`
+
+	// Capabilities overrides the auto-detected model capabilities
+	Capabilities CapabilitiesConfig `mapstructure:"capabilities" yaml:"capabilities"`
+
+	// Vertex authenticates Gemini through Vertex AI with a service account
+	// instead of an API key
+	Vertex VertexConfig `mapstructure:"vertex" yaml:"vertex,omitempty"`
+
+	// Record saves every provider answer to this directory, keyed by a
+	// hash of the prompt
+	Record string `mapstructure:"record" yaml:"record,omitempty"`
+
+	// Replay answers from the recordings in this directory instead of
+	// calling the provider
+	Replay string `mapstructure:"replay" yaml:"replay,omitempty"`
+
+	// Middleware run around every provider call, in order: the first sees
+	// the request first and the response last
+	Middleware []MiddlewareConfig `mapstructure:"middleware" yaml:"middleware,omitempty"`
+}
+
+// MiddlewareConfig configures one step of the provider middleware chain.
+type MiddlewareConfig struct {
+	// Type is "instructions", "redact", "audit" or "exec"
+	Type string `mapstructure:"type" yaml:"type"`
+
+	// Instructions are added to every prompt (instructions)
+	Instructions []string `mapstructure:"instructions" yaml:"instructions,omitempty"`
+
+	// Patterns are regular expressions masked in prompts and answers, on
+	// top of secrets (redact)
+	Patterns []string `mapstructure:"patterns" yaml:"patterns,omitempty"`
+
+	// Path is the JSON Lines file every call is appended to (audit)
+	Path string `mapstructure:"path" yaml:"path,omitempty"`
+
+	// Command is the plugin program and its arguments; it reads the call as
+	// JSON on stdin and may write it back changed on stdout (exec)
+	Command []string `mapstructure:"command" yaml:"command,omitempty"`
+
+	// Phase limits the plugin to "before" the call or "after" its answer;
+	// empty runs it on both (exec)
+	Phase string `mapstructure:"phase" yaml:"phase,omitempty"`
+
+	// Timeout bounds each run of the plugin (exec, default 30s)
+	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout,omitempty"`
+}
+
+// VertexConfig configures Gemini on Vertex AI. It is used when Project or
+// Credentials is set.
+type VertexConfig struct {
+	// Project is the Google Cloud project (default: the service account's)
+	Project string `mapstructure:"project" yaml:"project,omitempty"`
+
+	// Location is the Vertex AI region (default: us-central1)
+	Location string `mapstructure:"location" yaml:"location,omitempty"`
+
+	// Credentials is the service account key file
+	// (default: $GOOGLE_APPLICATION_CREDENTIALS)
+	Credentials string `mapstructure:"credentials" yaml:"credentials,omitempty"`
+}
+
+// Enabled reports whether Vertex AI is configured.
+func (v VertexConfig) Enabled() bool {
+	return v.Project != "" || v.Credentials != ""
+}
+
+// CapabilitiesConfig overrides entries of the built-in model capabilities registry.
+// Zero values (and nil pointers) mean "use the detected value".
+type CapabilitiesConfig struct {
+	// ContextWindow is the model context window in tokens
+	ContextWindow int `mapstructure:"context_window" yaml:"context_window,omitempty"`
+
+	// MaxOutputTokens is the maximum number of tokens the model can generate
+	MaxOutputTokens int `mapstructure:"max_output_tokens" yaml:"max_output_tokens,omitempty"`
+
+	// JSONMode forces native JSON output mode on or off
+	JSONMode *bool `mapstructure:"json_mode" yaml:"json_mode,omitempty"`
+
+	// SystemPrompt forces system prompt support on or off; without it, the
+	// system prompt is prepended to the user prompt
+	SystemPrompt *bool `mapstructure:"system_prompt" yaml:"system_prompt,omitempty"`
 }
 
 // GitConfig configures git-related settings.
@@ -111,11 +313,20 @@ type GitConfig struct {
 
 	// IgnorePatterns are file patterns to ignore during review
 	IgnorePatterns []string `mapstructure:"ignore_patterns" yaml:"ignore_patterns"`
+
+	// IncludePatterns, when set, restrict the review to the matching files
+	IncludePatterns []string `mapstructure:"include_patterns" yaml:"include_patterns,omitempty"`
+
+	// IgnoreFile is a .gitignore-style file, relative to the repository
+	// root, listing more files to ignore (default: .goreviewignore)
+	IgnoreFile string `mapstructure:"ignore_file" yaml:"ignore_file"`
 }
 
 // ReviewConfig configures review behavior.
 type ReviewConfig struct {
-	// Mode is the review mode: "staged", "commit", "branch", "files"
+	// Mode is the review mode: "staged", "commit", "branch", "files",
+	// "worktree" for the uncommitted changes, staged or not, and the
+	// untracked files, or "patch" for a diff read from stdin or a file
 	Mode string `mapstructure:"mode" yaml:"mode"`
 
 	// Commit is the commit SHA to review (for mode=commit)
@@ -124,6 +335,42 @@ type ReviewConfig struct {
 	// Files is the list of files to review (for mode=files)
 	Files []string `mapstructure:"files" yaml:"files"`
 
+	// OnlyFiles restricts the review, in any mode, to these changed files;
+	// the others are listed as filtered (set by --only-flagged)
+	OnlyFiles []string `mapstructure:"only_files" yaml:"only_files"`
+
+	// FocusSymbols restricts the review to the changes inside these
+	// functions and types, like "Parse" or "Engine.Run"; files without
+	// changes in them are listed as filtered (set by --focus-symbols)
+	FocusSymbols []string `mapstructure:"focus_symbols" yaml:"focus_symbols"`
+
+	// Full reviews the whole content of the files instead of their diff
+	// (for mode=files)
+	Full bool `mapstructure:"full" yaml:"full"`
+
+	// IncludeUntracked adds the untracked files, not ignored by
+	// .gitignore, to a staged review as whole-file additions
+	// (for mode=staged; worktree mode always includes them)
+	IncludeUntracked bool `mapstructure:"include_untracked" yaml:"include_untracked"`
+
+	// ContextRadius is the number of neighboring files of the same package,
+	// on each side in name order, sent as context with full reviews
+	ContextRadius int `mapstructure:"context_radius" yaml:"context_radius"`
+
+	// TimeBudget caps a review's duration (0 = unlimited): the riskiest
+	// files are reviewed first, and those not reviewed in time are listed
+	// as unreviewed
+	TimeBudget time.Duration `mapstructure:"time_budget" yaml:"time_budget"`
+
+	// TokenBudget caps the estimated diff tokens reviewed in a run
+	// (0 = unlimited): the highest-priority files are reviewed, and the
+	// rest are summarized together and flagged as not reviewed
+	TokenBudget int `mapstructure:"token_budget" yaml:"token_budget"`
+
+	// Shard reviews only one part of the changed files, as "index/total"
+	// like "2/4", for CI jobs whose reports are combined with merge-results
+	Shard string `mapstructure:"shard" yaml:"shard"`
+
 	// MinSeverity is the minimum severity to report: "info", "warning", "error", "critical"
 	MinSeverity string `mapstructure:"min_severity" yaml:"min_severity"`
 
@@ -133,28 +380,192 @@ type ReviewConfig struct {
 	// MaxConcurrency is the maximum parallel file reviews (0 = auto)
 	MaxConcurrency int `mapstructure:"max_concurrency" yaml:"max_concurrency"`
 
+	// AdaptiveConcurrency scales the number of concurrent reviews during a run
+	AdaptiveConcurrency AdaptiveConcurrencyConfig `mapstructure:"adaptive_concurrency" yaml:"adaptive_concurrency"`
+
+	// MaxChunkTokens is the maximum diff size per provider request in tokens
+	// (0 = derived from the model context window)
+	MaxChunkTokens int `mapstructure:"max_chunk_tokens" yaml:"max_chunk_tokens"`
+
 	// Context is additional context to include in prompts
 	Context string `mapstructure:"context" yaml:"context"`
 
 	// Personality is the reviewer personality style: "default", "senior", "strict", "friendly", "security-expert"
 	Personality string `mapstructure:"personality" yaml:"personality"`
 
-	// Modes specifies specialized review focus areas: "security", "perf", "clean", "docs", "tests"
+	// Modes specifies specialized review focus areas: "security", "perf", "clean", "docs", "tests", "errors", "concurrency"
 	// Multiple modes can be combined with commas: "security,perf"
 	Modes string `mapstructure:"modes" yaml:"modes"`
 
 	// RootCauseTracing enables root cause analysis for each issue
 	RootCauseTracing bool `mapstructure:"root_cause_tracing" yaml:"root_cause_tracing"`
+
+	// ChangeSummary adds to prompts the functions, signatures and
+	// dependencies each change adds, removes or modifies, computed locally
+	ChangeSummary bool `mapstructure:"change_summary" yaml:"change_summary"`
+
+	// PRContext adds to prompts the title, description, linked issues and
+	// human review comments of the open pull or merge request of the
+	// change, fetched with the export.vcs settings
+	PRContext bool `mapstructure:"pr_context" yaml:"pr_context"`
+
+	// Incremental re-reviews only hunks that changed since the last stored
+	// review of the branch, carrying over earlier findings (mode=branch)
+	Incremental bool `mapstructure:"incremental" yaml:"incremental"`
+
+	// FailOn is the minimum severity that makes the review exit non-zero
+	FailOn string `mapstructure:"fail_on" yaml:"fail_on"`
+
+	// ConformancePolicy is a template policy file; when set, the repository
+	// is checked against it and violations are added to the review
+	ConformancePolicy string `mapstructure:"conformance_policy" yaml:"conformance_policy"`
+
+	// SeverityOverrides remaps issue severities by issue type or rule
+	SeverityOverrides SeverityOverridesConfig `mapstructure:"severity_overrides" yaml:"severity_overrides"`
+
+	// ProtectedPaths escalates issue severities in sensitive paths
+	ProtectedPaths []ProtectedPathConfig `mapstructure:"protected_paths" yaml:"protected_paths,omitempty"`
+
+	// IssueTypes defines custom issue types, added to the built-in ones
+	IssueTypes []IssueTypeConfig `mapstructure:"issue_types" yaml:"issue_types"`
+
+	// Spelling configures the local spell and terminology checker
+	Spelling SpellingConfig `mapstructure:"spelling" yaml:"spelling"`
+
+	// Duplicates configures duplicate code detection for added code
+	Duplicates DuplicatesConfig `mapstructure:"duplicates" yaml:"duplicates"`
+
+	// Themes configures grouping the issues of big reviews into themes
+	Themes ThemesConfig `mapstructure:"themes" yaml:"themes"`
+
+	// Complexity configures complexity metrics and thresholds for changed functions
+	Complexity ComplexityConfig `mapstructure:"complexity" yaml:"complexity"`
+
+	// DocStyle configures the documentation checks of the docs review mode
+	DocStyle DocStyleConfig `mapstructure:"doc_style" yaml:"doc_style"`
+
+	// Scope configures the check for unrelated changes bundled together
+	Scope ScopeConfig `mapstructure:"scope" yaml:"scope"`
+
+	// Guardrails summarize or skip large files, lockfiles and vendored code
+	// instead of reviewing them
+	Guardrails GuardrailsConfig `mapstructure:"guardrails" yaml:"guardrails"`
+
+	// Trivial approves comment-only and formatting-only changes and version
+	// bumps without sending them to the model
+	Trivial TrivialConfig `mapstructure:"trivial" yaml:"trivial"`
+
+	// Debt configures the TODO/FIXME/HACK debt tracker
+	Debt DebtConfig `mapstructure:"debt" yaml:"debt"`
+
+	// Triage configures recording issues for the triage workflow
+	Triage TriageConfig `mapstructure:"triage" yaml:"triage"`
+
+	// SizeImpact configures binary and bundle size impact estimation
+	SizeImpact SizeImpactConfig `mapstructure:"size_impact" yaml:"size_impact"`
+
+	// Generated configures the policy for AI-generated and pasted code
+	Generated GeneratedConfig `mapstructure:"generated" yaml:"generated"`
+}
+
+// AdaptiveConcurrencyConfig configures concurrency auto-tuning. Workers are
+// added while the provider keeps up and removed on throttling (HTTP 429),
+// rising latency or memory pressure.
+type AdaptiveConcurrencyConfig struct {
+	// Enabled turns on auto-tuning; max_concurrency is then the starting point
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// MinWorkers is the lower bound on concurrent reviews
+	MinWorkers int `mapstructure:"min_workers" yaml:"min_workers"`
+
+	// MaxWorkers is the upper bound on concurrent reviews
+	MaxWorkers int `mapstructure:"max_workers" yaml:"max_workers"`
+
+	// TargetLatency is the per-request latency above which workers are removed
+	// (0 = relative to the fastest latency seen during the run)
+	TargetLatency time.Duration `mapstructure:"target_latency" yaml:"target_latency"`
+
+	// MaxMemoryMB is the heap size above which workers are removed (0 = unlimited)
+	MaxMemoryMB int `mapstructure:"max_memory_mb" yaml:"max_memory_mb"`
+}
+
+// SeverityOverridesConfig remaps issue severities after review.
+// Rule overrides win over type overrides; minimums are applied last.
+type SeverityOverridesConfig struct {
+	// Types sets a fixed severity per issue type
+	// Example: {"style": "info"}
+	Types map[string]string `mapstructure:"types" yaml:"types"`
+
+	// Rules sets a fixed severity per rule ID
+	// Example: {"SEC-001": "critical"}
+	Rules map[string]string `mapstructure:"rules" yaml:"rules"`
+
+	// Minimum raises issues of a type to at least this severity
+	// Example: {"security": "error"}
+	Minimum map[string]string `mapstructure:"minimum" yaml:"minimum"`
+}
+
+// ProtectedPathConfig escalates the severity of issues in sensitive paths,
+// so changes there get stricter gates. Escalation runs after the severity
+// overrides and before the exit code is decided.
+type ProtectedPathConfig struct {
+	// Name labels the area in reports; defaults to the first path
+	Name string `mapstructure:"name" yaml:"name,omitempty"`
+
+	// Paths are globs in the syntax of rule paths
+	// Example: ["auth/**", "billing/**"]
+	Paths []string `mapstructure:"paths" yaml:"paths"`
+
+	// Escalate maps a severity to the one its issues get in the paths
+	// Example: {"warning": "error"}
+	Escalate map[string]string `mapstructure:"escalate" yaml:"escalate"`
+}
+
+// Label returns the name of the area in reports.
+func (p ProtectedPathConfig) Label() string {
+	if p.Name != "" || len(p.Paths) == 0 {
+		return p.Name
+	}
+	return p.Paths[0]
+}
+
+// SpellingConfig configures the spell and terminology checker. It runs
+// locally on the comments, identifiers and markdown added in the diff and
+// reports info-level issues.
+type SpellingConfig struct {
+	// Enabled turns the checker on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// Dictionary lists project words that are never reported
+	Dictionary []string `mapstructure:"dictionary" yaml:"dictionary"`
+
+	// Terms maps banned terms to their preferred replacement; an empty
+	// replacement reports the term without suggesting one
+	// Example: {"whitelist": "allowlist"}
+	Terms map[string]string `mapstructure:"terms" yaml:"terms"`
+}
+
+// IssueTypeConfig defines a custom issue type.
+type IssueTypeConfig struct {
+	// Name is the type identifier used in issues, e.g. "accessibility"
+	Name string `mapstructure:"name" yaml:"name"`
+
+	// Description tells the reviewer what belongs in this type
+	Description string `mapstructure:"description" yaml:"description"`
 }
 
 // OutputConfig configures output formatting.
 type OutputConfig struct {
-	// Format is the output format: "markdown", "json", "sarif"
+	// Format is the output format: "markdown", "json", "sarif", "pdf",
+	// "codeactions", "dot", "mermaid"
 	Format string `mapstructure:"format" yaml:"format"`
 
 	// File is the output file path (empty = stdout)
 	File string `mapstructure:"file" yaml:"file"`
 
+	// Reports are more reports written after the main one, in other formats
+	Reports []ReportOutputConfig `mapstructure:"reports" yaml:"reports,omitempty"`
+
 	// IncludeCode includes code snippets in output
 	IncludeCode bool `mapstructure:"include_code" yaml:"include_code"`
 
@@ -166,6 +577,39 @@ type OutputConfig struct {
 
 	// Quiet suppresses all output except errors
 	Quiet bool `mapstructure:"quiet" yaml:"quiet"`
+
+	// Artifacts uploads every report to an object store
+	Artifacts ArtifactsConfig `mapstructure:"artifacts" yaml:"artifacts,omitempty"`
+}
+
+// ReportOutputConfig is an additional review report.
+type ReportOutputConfig struct {
+	// Format is the report format: "markdown", "json", "sarif", "pdf",
+	// "codeactions", "dot", "mermaid"
+	Format string `mapstructure:"format" yaml:"format"`
+
+	// File is where the report is written, or an s3:// or gs:// URL it is
+	// uploaded to
+	File string `mapstructure:"file" yaml:"file"`
+}
+
+// ArtifactsConfig configures the object store reports are uploaded to after
+// each review. Credentials come from the environment: AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY for S3, GOOGLE_APPLICATION_CREDENTIALS or
+// GOOGLE_OAUTH_ACCESS_TOKEN for GCS.
+type ArtifactsConfig struct {
+	// URL is where reports are uploaded, as s3://bucket/key or gs://bucket/key
+	// (empty = disabled). It may contain {repo}, {branch}, {commit},
+	// {timestamp} and {ext}; a URL ending in "/" is a prefix and gets a
+	// generated file name.
+	URL string `mapstructure:"url" yaml:"url,omitempty"`
+
+	// Region is the S3 region (default: AWS_REGION, then us-east-1)
+	Region string `mapstructure:"region" yaml:"region,omitempty"`
+
+	// Endpoint overrides the storage endpoint, for S3-compatible stores
+	// such as MinIO
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint,omitempty"`
 }
 
 // CacheConfig configures caching behavior.
@@ -184,6 +628,10 @@ type CacheConfig struct {
 
 	// MaxEntries is the maximum number of cache entries (for LRU)
 	MaxEntries int `mapstructure:"max_entries" yaml:"max_entries"`
+
+	// Normalize also looks up results by a normalized diff that ignores
+	// whitespace-only and comment-only changes and import order
+	Normalize bool `mapstructure:"normalize" yaml:"normalize"`
 }
 
 // RulesConfig configures the rule system.
@@ -208,6 +656,31 @@ type RulesConfig struct {
 	// Override contains rule property overrides for this project
 	// Example: {"SEC-001": {"severity": "critical"}}
 	Override map[string]interface{} `mapstructure:"override" yaml:"override"`
+
+	// Packs are versioned remote rule packs, fetched with "goreview rules update"
+	Packs []RulePackConfig `mapstructure:"packs" yaml:"packs"`
+
+	// PacksDir is the local cache for downloaded rule packs
+	PacksDir string `mapstructure:"packs_dir" yaml:"packs_dir"`
+}
+
+// RulePackConfig pins a remote rule pack.
+type RulePackConfig struct {
+	// Name identifies the pack locally
+	Name string `mapstructure:"name" yaml:"name"`
+
+	// Source is an HTTPS URL or a git repository (git+https://... or *.git).
+	// "{version}" in an HTTPS URL is replaced with Version.
+	Source string `mapstructure:"source" yaml:"source"`
+
+	// Version is the pinned version (git tag/ref, or release for URLs)
+	Version string `mapstructure:"version" yaml:"version"`
+
+	// Checksum is the expected "sha256:<hex>" of the rules file
+	Checksum string `mapstructure:"checksum" yaml:"checksum,omitempty"`
+
+	// Path is the rules file inside a git repository (default: rules.yaml)
+	Path string `mapstructure:"path" yaml:"path,omitempty"`
 }
 
 // MemoryConfig configures the cognitive memory system.
@@ -229,6 +702,30 @@ type MemoryConfig struct {
 
 	// Hebbian configures Hebbian learning (association strengthening)
 	Hebbian HebbianConfig `mapstructure:"hebbian" yaml:"hebbian"`
+
+	// Conventions configures the team conventions learned from goreview fix
+	Conventions ConventionsConfig `mapstructure:"conventions" yaml:"conventions"`
+
+	// MaintenanceInterval is how often goreview serve consolidates memory
+	// and runs its maintenance (0 = never)
+	MaintenanceInterval time.Duration `mapstructure:"maintenance_interval" yaml:"maintenance_interval"`
+}
+
+// ConventionsConfig configures the team conventions learned from the fixes
+// accepted and rejected in goreview fix. They are kept in long-term memory
+// even when the memory system is disabled.
+type ConventionsConfig struct {
+	// Enabled learns conventions in goreview fix and gives them to the
+	// reviewer
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// MinDecisions is how many fixes of a kind must be decided before the
+	// convention reaches reviews; edited conventions always do
+	MinDecisions int `mapstructure:"min_decisions" yaml:"min_decisions"`
+
+	// MaxPrompt is the most conventions given to the reviewer, the most
+	// decided first (0 = all)
+	MaxPrompt int `mapstructure:"max_prompt" yaml:"max_prompt"`
 }
 
 // WorkingMemoryConfig configures working memory.
@@ -280,8 +777,264 @@ type HebbianConfig struct {
 type ExportConfig struct {
 	// Obsidian configures Obsidian vault export
 	Obsidian ObsidianExportConfig `mapstructure:"obsidian" yaml:"obsidian"`
+
+	// Slack configures posting review summaries to a Slack incoming webhook
+	Slack SlackExportConfig `mapstructure:"slack" yaml:"slack"`
+
+	// Webhook configures posting the full review result to an HTTP endpoint
+	Webhook WebhookExportConfig `mapstructure:"webhook" yaml:"webhook"`
+
+	// VCS configures posting issues as review comments on the pull or merge
+	// request, kept in sync across runs
+	VCS VCSExportConfig `mapstructure:"vcs" yaml:"vcs"`
+
+	// Timeout bounds each HTTP-based export (default: 30s)
+	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout"`
+}
+
+// SlackExportConfig configures Slack export settings.
+type SlackExportConfig struct {
+	// Enabled enables automatic Slack export after reviews
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// WebhookURL is the Slack incoming webhook URL
+	WebhookURL string `mapstructure:"webhook_url" yaml:"webhook_url"`
+
+	// Channel overrides the webhook's default channel
+	Channel string `mapstructure:"channel" yaml:"channel"`
+
+	// MaxIssues limits the issues listed in the message (default: 10)
+	MaxIssues int `mapstructure:"max_issues" yaml:"max_issues"`
+}
+
+// WebhookExportConfig configures generic webhook export settings.
+type WebhookExportConfig struct {
+	// Enabled enables automatic webhook export after reviews
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// URL is the endpoint receiving the JSON payload
+	URL string `mapstructure:"url" yaml:"url"`
+
+	// Headers are extra HTTP headers sent with the request (e.g. Authorization)
+	Headers map[string]string `mapstructure:"headers" yaml:"headers"`
+}
+
+// VCSExportConfig configures review comments on GitHub pull requests and
+// GitLab merge requests. Unset values are taken from the CI environment.
+type VCSExportConfig struct {
+	// Enabled enables automatic comment sync after reviews
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// Platform is github or gitlab (default: detected from the CI environment)
+	Platform string `mapstructure:"platform" yaml:"platform"`
+
+	// Repo is owner/name on GitHub, or the project ID or path on GitLab
+	Repo string `mapstructure:"repo" yaml:"repo"`
+
+	// Number is the pull or merge request number
+	Number int `mapstructure:"number" yaml:"number"`
+
+	// Token authenticates the API calls (default: GITHUB_TOKEN or GITLAB_TOKEN)
+	Token string `mapstructure:"token" yaml:"token"`
+
+	// APIURL is the API base URL, for GitHub Enterprise or self-hosted GitLab
+	APIURL string `mapstructure:"api_url" yaml:"api_url"`
+
+	// Labels configures labeling the request by change type
+	Labels VCSLabelsConfig `mapstructure:"labels" yaml:"labels"`
+}
+
+// VCSLabelChangeTypes are the change types the VCS exporter labels
+// requests with.
+var VCSLabelChangeTypes = []string{"feat", "bugfix", "security", "migration", "docs", "breaking-change"}
+
+// VCSLabelsConfig configures labeling pull and merge requests by change
+// type, derived from the review and the request's commit messages.
+type VCSLabelsConfig struct {
+	// Enabled adds the labels on each sync
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// Mapping maps change types to label names; a type mapped to "" gets no
+	// label, and unmapped types use their own name
+	Mapping map[string]string `mapstructure:"mapping" yaml:"mapping,omitempty"`
+}
+
+// ServeConfig configures the HTTP review service started by "goreview
+// serve". Every request authenticates as a tenant with one of its API keys.
+type ServeConfig struct {
+	// Addr is the address to listen on
+	Addr string `mapstructure:"addr" yaml:"addr"`
+
+	// DataDir holds a directory per tenant with its history database, cache
+	// and memory, so tenants never see each other's data
+	DataDir string `mapstructure:"data_dir" yaml:"data_dir"`
+
+	// MaxBodyBytes limits the size of a submitted diff
+	MaxBodyBytes int64 `mapstructure:"max_body_bytes" yaml:"max_body_bytes"`
+
+	// Workers is how many queued reviews run at once
+	Workers int `mapstructure:"workers" yaml:"workers"`
+
+	// Tenants are the teams served
+	Tenants []TenantConfig `mapstructure:"tenants" yaml:"tenants"`
+}
+
+// TenantConfig is a team served by "goreview serve".
+type TenantConfig struct {
+	// ID names the tenant and its data directory
+	ID string `mapstructure:"id" yaml:"id"`
+
+	// APIKeys authenticate the tenant. A key is written as "sha256:" and the
+	// hex digest of the key, or as ${VAR} to read it from the environment
+	APIKeys []string `mapstructure:"api_keys" yaml:"api_keys"`
+
+	// Profile is the profile applied to the tenant's reviews, with its
+	// provider credentials and rules (default: the base configuration)
+	Profile string `mapstructure:"profile" yaml:"profile,omitempty"`
+
+	// Quota limits the tenant's requests
+	Quota QuotaConfig `mapstructure:"quota" yaml:"quota"`
+}
+
+// QuotaConfig limits the requests of a tenant. Zero means unlimited.
+type QuotaConfig struct {
+	// RequestsPerMinute is the most reviews in a minute
+	RequestsPerMinute int `mapstructure:"requests_per_minute" yaml:"requests_per_minute"`
+
+	// RequestsPerDay is the most reviews in a day
+	RequestsPerDay int `mapstructure:"requests_per_day" yaml:"requests_per_day"`
+
+	// MaxFiles is the most files in a reviewed diff
+	MaxFiles int `mapstructure:"max_files" yaml:"max_files"`
+}
+
+// validate checks the tenants have unique IDs usable as directory names
+// and at least one API key.
+func (s *ServeConfig) validate() error {
+	if s.MaxBodyBytes < 0 {
+		return &ValidationError{Field: "serve.max_body_bytes", Message: "must not be negative"}
+	}
+	if s.Workers < 1 {
+		return &ValidationError{Field: "serve.workers", Message: "must be at least 1"}
+	}
+	ids := make(map[string]bool, len(s.Tenants))
+	for i, t := range s.Tenants {
+		field := fmt.Sprintf("serve.tenants[%d]", i)
+		switch {
+		case !tenantIDPattern.MatchString(t.ID):
+			return &ValidationError{Field: field + ".id", Message: "tenant ID must be letters, digits, '-' or '_'"}
+		case ids[t.ID]:
+			return &ValidationError{Field: field + ".id", Message: fmt.Sprintf("duplicate tenant %q", t.ID)}
+		case len(t.APIKeys) == 0:
+			return &ValidationError{Field: field + ".api_keys", Message: "at least one API key is required"}
+		case t.Quota.RequestsPerMinute < 0 || t.Quota.RequestsPerDay < 0 || t.Quota.MaxFiles < 0:
+			return &ValidationError{Field: field + ".quota", Message: "limits must not be negative"}
+		}
+		ids[t.ID] = true
+	}
+	return nil
+}
+
+// CommitConfig configures "goreview commit".
+type CommitConfig struct {
+	// InferScope sets the commit scope from the changed paths instead of
+	// the one chosen by the model
+	InferScope bool `mapstructure:"infer_scope" yaml:"infer_scope"`
+
+	// Scopes map paths to scopes, ahead of learned and guessed scopes
+	Scopes []ScopeRule `mapstructure:"scopes" yaml:"scopes,omitempty"`
+
+	// MaxScopes is the most scopes listed in one message, as in
+	// "feat(api,web): ..."; commits touching more get the dominant scope or
+	// none
+	MaxScopes int `mapstructure:"max_scopes" yaml:"max_scopes"`
+
+	// LearnScopes remembers the scope of each commit made by goreview for
+	// the directories it touched, in memory.dir, so later commits reuse it
+	LearnScopes bool `mapstructure:"learn_scopes" yaml:"learn_scopes"`
+}
+
+// ScopeRule maps the files under a path to a commit scope.
+type ScopeRule struct {
+	// Path is a directory relative to the repository root, whose segments
+	// may be globs, such as "internal/*"
+	Path string `mapstructure:"path" yaml:"path"`
+
+	// Scope is the scope of the files under Path (default: the name of the
+	// matched directory)
+	Scope string `mapstructure:"scope" yaml:"scope,omitempty"`
 }
 
+// validate checks the scope rules have valid paths.
+func (c *CommitConfig) validate() error {
+	if c.MaxScopes < 1 {
+		return &ValidationError{Field: "commit.max_scopes", Message: "must be at least 1"}
+	}
+	for i, r := range c.Scopes {
+		field := fmt.Sprintf("commit.scopes[%d].path", i)
+		if strings.Trim(r.Path, "/. ") == "" {
+			return &ValidationError{Field: field, Message: "path is required"}
+		}
+		if _, err := path.Match(r.Path, ""); err != nil {
+			return &ValidationError{Field: field, Message: fmt.Sprintf("invalid glob %q", r.Path)}
+		}
+	}
+	return nil
+}
+
+// ChangelogConfig configures "goreview changelog".
+type ChangelogConfig struct {
+	// Packages maps the directories of a monorepo to packages, for
+	// changelogs split with --scope-from-paths
+	Packages []ChangelogPackage `mapstructure:"packages" yaml:"packages,omitempty"`
+
+	// PackageGlobs find the packages not listed in Packages: each directory
+	// matching a glob is a package named after the directory
+	PackageGlobs []string `mapstructure:"package_globs" yaml:"package_globs"`
+}
+
+// ChangelogPackage is a package of a monorepo.
+type ChangelogPackage struct {
+	// Name titles the package's changelog (default: the directory's name)
+	Name string `mapstructure:"name" yaml:"name,omitempty"`
+
+	// Path is the package's directory, relative to the repository root
+	Path string `mapstructure:"path" yaml:"path"`
+}
+
+// validate checks the packages have paths and unique names, and the globs
+// are valid.
+func (c *ChangelogConfig) validate() error {
+	names := make(map[string]bool, len(c.Packages))
+	for i, p := range c.Packages {
+		field := fmt.Sprintf("changelog.packages[%d]", i)
+		if strings.Trim(p.Path, "/. ") == "" {
+			return &ValidationError{Field: field + ".path", Message: "package path is required"}
+		}
+		name := p.PackageName()
+		if names[name] {
+			return &ValidationError{Field: field + ".name", Message: fmt.Sprintf("duplicate package %q", name)}
+		}
+		names[name] = true
+	}
+	for _, g := range c.PackageGlobs {
+		if _, err := path.Match(g, ""); err != nil {
+			return &ValidationError{Field: "changelog.package_globs", Message: fmt.Sprintf("invalid glob %q", g)}
+		}
+	}
+	return nil
+}
+
+// PackageName returns the package's name, defaulting to its directory's.
+func (p ChangelogPackage) PackageName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return path.Base(path.Clean(p.Path))
+}
+
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 // ObsidianExportConfig configures Obsidian export settings.
 type ObsidianExportConfig struct {
 	// Enabled enables automatic Obsidian export after reviews
@@ -310,6 +1063,12 @@ type ObsidianExportConfig struct {
 
 	// TemplateFile is an optional custom template file path
 	TemplateFile string `mapstructure:"template_file" yaml:"template_file"`
+
+	// IssueNotes creates a task note per critical or error issue, linked to the review note
+	IssueNotes bool `mapstructure:"issue_notes" yaml:"issue_notes"`
+
+	// Canvas generates an Obsidian Canvas linking the review, files and issues
+	Canvas bool `mapstructure:"canvas" yaml:"canvas"`
 }
 
 // Validate validates the configuration and returns an error if invalid.
@@ -323,20 +1082,126 @@ func (c *Config) Validate() error {
 		return &ValidationError{Field: "provider.model", Message: "model is required"}
 	}
 
-	if c.Provider.Name == "openai" && c.Provider.APIKey == "" {
+	if c.Provider.Record != "" && c.Provider.Replay != "" {
+		return &ValidationError{Field: "provider.replay", Message: "record and replay are mutually exclusive"}
+	}
+
+	if c.Provider.Name == "openai" && c.Provider.APIKey == "" && c.Provider.Replay == "" {
 		return &ValidationError{Field: "provider.api_key", Message: "API key is required for OpenAI"}
 	}
 
+	if c.Provider.Name == "anthropic" && c.Provider.APIKey == "" && c.Provider.Replay == "" {
+		return &ValidationError{Field: "provider.api_key", Message: "API key is required for Anthropic"}
+	}
+
+	for i, m := range c.Provider.Middleware {
+		if err := m.validate(fmt.Sprintf("provider.middleware[%d]", i)); err != nil {
+			return err
+		}
+	}
+
 	// Review validation
-	validModes := map[string]bool{"staged": true, "commit": true, "branch": true, "files": true}
+	validModes := map[string]bool{"staged": true, "commit": true, "branch": true, "files": true, "worktree": true, "patch": true}
 	if !validModes[c.Review.Mode] {
-		return &ValidationError{Field: "review.mode", Message: "invalid mode, must be one of: staged, commit, branch, files"}
+		return &ValidationError{Field: "review.mode", Message: "invalid mode, must be one of: staged, commit, branch, files, worktree, patch"}
+	}
+
+	if c.Review.ContextRadius < 0 {
+		return &ValidationError{Field: "review.context_radius", Message: "must not be negative"}
+	}
+
+	if conv := c.Memory.Conventions; conv.Enabled && (conv.MinDecisions < 1 || conv.MaxPrompt < 0) {
+		return &ValidationError{Field: "memory.conventions", Message: "min_decisions must be at least 1 and max_prompt not negative"}
+	}
+
+	if c.Memory.MaintenanceInterval < 0 {
+		return &ValidationError{Field: "memory.maintenance_interval", Message: "must not be negative"}
+	}
+
+	if c.Review.TimeBudget < 0 {
+		return &ValidationError{Field: "review.time_budget", Message: "must not be negative"}
+	}
+
+	if c.Review.TokenBudget < 0 {
+		return &ValidationError{Field: "review.token_budget", Message: "must not be negative"}
+	}
+
+	if c.Review.Shard != "" {
+		if _, _, err := ParseShard(c.Review.Shard); err != nil {
+			return &ValidationError{Field: "review.shard", Message: err.Error()}
+		}
+	}
+
+	if err := c.Review.validateSeverities(); err != nil {
+		return err
+	}
+	if ac := c.Review.AdaptiveConcurrency; ac.Enabled && (ac.MinWorkers < 1 || ac.MaxWorkers < ac.MinWorkers) {
+		return &ValidationError{Field: "review.adaptive_concurrency", Message: "min_workers must be at least 1 and not above max_workers"}
+	}
+
+	if th := c.Review.Themes; th.Enabled && (th.MinIssues < 1 || th.Similarity <= 0 || th.Similarity > 1) {
+		return &ValidationError{Field: "review.themes", Message: "min_issues must be at least 1 and similarity between 0 and 1"}
+	}
+
+	if d := c.Review.Duplicates; d.Enabled && (d.MinTokens < 1 || d.Similarity <= 0 || d.Similarity > 1) {
+		return &ValidationError{Field: "review.duplicates", Message: "min_tokens must be at least 1 and similarity between 0 and 1"}
+	}
+
+	if cx := c.Review.Complexity; cx.MaxCyclomatic < 0 || cx.MaxCognitive < 0 || cx.MaxFunctionLines < 0 {
+		return &ValidationError{Field: "review.complexity", Message: "thresholds must not be negative"}
+	}
+
+	if s := c.Review.DocStyle.Python; s != "" && s != "google" && s != "numpy" {
+		return &ValidationError{Field: "review.doc_style.python", Message: "must be google or numpy"}
+	}
+
+	if c.Review.Scope.MaxUnrelated < 0 {
+		return &ValidationError{Field: "review.scope.max_unrelated", Message: "must not be negative"}
+	}
+
+	if g := c.Review.Guardrails; g.MaxLines < 0 || g.MaxTokens < 0 {
+		return &ValidationError{Field: "review.guardrails", Message: "max_lines and max_tokens must not be negative"}
+	}
+	if a := c.Review.Guardrails.Action; a != "" && a != GuardrailSummarize && a != GuardrailSkip {
+		return &ValidationError{Field: "review.guardrails.action", Message: "must be summarize or skip"}
+	}
+	for _, kind := range c.Review.Trivial.Kinds {
+		if !slices.Contains(TrivialKinds, kind) {
+			return &ValidationError{Field: "review.trivial.kinds", Message: fmt.Sprintf("unknown kind %q, must be one of: %s", kind, strings.Join(TrivialKinds, ", "))}
+		}
+	}
+
+	if err := c.Review.Debt.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Review.Triage.Recurrences.validate(); err != nil {
+		return err
+	}
+	if err := c.Review.Triage.Calibration.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Review.Generated.validate(); err != nil {
+		return err
 	}
 
 	// Output validation
-	validFormats := map[string]bool{"markdown": true, "json": true, "sarif": true}
+	validFormats := map[string]bool{"markdown": true, "json": true, "sarif": true, "pdf": true, "codeactions": true, "dot": true, "mermaid": true}
 	if !validFormats[c.Output.Format] {
-		return &ValidationError{Field: "output.format", Message: "invalid format, must be one of: markdown, json, sarif"}
+		return &ValidationError{Field: "output.format", Message: "invalid format, must be one of: markdown, json, sarif, pdf, codeactions, dot, mermaid"}
+	}
+	for i, r := range c.Output.Reports {
+		field := fmt.Sprintf("output.reports[%d]", i)
+		if !validFormats[r.Format] {
+			return &ValidationError{Field: field + ".format", Message: "invalid format, must be one of: markdown, json, sarif, pdf, codeactions, dot, mermaid"}
+		}
+		if r.File == "" {
+			return &ValidationError{Field: field + ".file", Message: "file is required"}
+		}
+	}
+	if u := c.Output.Artifacts.URL; u != "" && !strings.HasPrefix(u, "s3://") && !strings.HasPrefix(u, "gs://") {
+		return &ValidationError{Field: "output.artifacts.url", Message: "must be an s3:// or gs:// URL"}
 	}
 
 	// Cache validation
@@ -344,6 +1209,473 @@ func (c *Config) Validate() error {
 		return &ValidationError{Field: "cache.dir", Message: "cache directory is required when cache is enabled"}
 	}
 
+	// Gate validation; expressions are compiled when the gates run
+	names := make(map[string]bool, len(c.Gates))
+	for i, g := range c.Gates {
+		field := fmt.Sprintf("gates[%d]", i)
+		switch {
+		case strings.TrimSpace(g.Name) == "":
+			return &ValidationError{Field: field + ".name", Message: "gate name is required"}
+		case names[g.Name]:
+			return &ValidationError{Field: field + ".name", Message: fmt.Sprintf("duplicate gate %q", g.Name)}
+		case strings.TrimSpace(g.Expr) == "":
+			return &ValidationError{Field: field + ".expr", Message: "gate expression is required"}
+		}
+		names[g.Name] = true
+	}
+
+	// Knowledge source validation; type-specific settings are checked by
+	// "goreview knowledge sources test"
+	validSourceTypes := map[string]bool{"notion": true, "confluence": true, "obsidian": true, "local": true, "github": true}
+	sourceNames := make(map[string]bool, len(c.Knowledge.Sources))
+	for i, s := range c.Knowledge.Sources {
+		field := fmt.Sprintf("knowledge.sources[%d]", i)
+		switch {
+		case strings.TrimSpace(s.Name) == "":
+			return &ValidationError{Field: field + ".name", Message: "source name is required"}
+		case sourceNames[s.Name]:
+			return &ValidationError{Field: field + ".name", Message: fmt.Sprintf("duplicate source %q", s.Name)}
+		case !validSourceTypes[s.Type]:
+			return &ValidationError{Field: field + ".type", Message: "invalid type, must be one of: notion, confluence, obsidian, local, github"}
+		}
+		sourceNames[s.Name] = true
+	}
+
+	for _, p := range c.Privacy.RedactPatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return &ValidationError{Field: "privacy.redact_patterns", Message: err.Error()}
+		}
+	}
+
+	// Export validation
+	if c.Export.Slack.Enabled && c.Export.Slack.WebhookURL == "" {
+		return &ValidationError{Field: "export.slack.webhook_url", Message: "webhook URL is required when Slack export is enabled"}
+	}
+	if c.Export.Webhook.Enabled && c.Export.Webhook.URL == "" {
+		return &ValidationError{Field: "export.webhook.url", Message: "URL is required when webhook export is enabled"}
+	}
+	if p := c.Export.VCS.Platform; p != "" && p != "github" && p != "gitlab" {
+		return &ValidationError{Field: "export.vcs.platform", Message: "invalid platform, must be github or gitlab"}
+	}
+	for changeType := range c.Export.VCS.Labels.Mapping {
+		if !slices.Contains(VCSLabelChangeTypes, changeType) {
+			return &ValidationError{Field: "export.vcs.labels.mapping", Message: fmt.Sprintf("unknown change type %q, must be one of: %s", changeType, strings.Join(VCSLabelChangeTypes, ", "))}
+		}
+	}
+
+	if err := c.Serve.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Commit.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Changelog.validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// severityRank orders the accepted severity names
+var severityRank = map[string]int{"info": 1, "warning": 2, "error": 3, "critical": 4}
+
+// validateSeverities checks every configured severity name.
+func (r *ReviewConfig) validateSeverities() error {
+	check := func(field, value string) error {
+		if value != "" && severityRank[strings.ToLower(value)] == 0 {
+			return &ValidationError{Field: field, Message: "invalid severity, must be one of: info, warning, error, critical"}
+		}
+		return nil
+	}
+
+	if err := check("review.min_severity", r.MinSeverity); err != nil {
+		return err
+	}
+	if err := check("review.fail_on", r.FailOn); err != nil {
+		return err
+	}
+	if err := check("review.generated.min_severity", r.Generated.MinSeverity); err != nil {
+		return err
+	}
+	overrides := map[string]map[string]string{
+		"types":   r.SeverityOverrides.Types,
+		"rules":   r.SeverityOverrides.Rules,
+		"minimum": r.SeverityOverrides.Minimum,
+	}
+	for group, m := range overrides {
+		for key, value := range m {
+			if err := check("review.severity_overrides."+group+"."+key, value); err != nil {
+				return err
+			}
+		}
+	}
+	for i, p := range r.ProtectedPaths {
+		field := fmt.Sprintf("review.protected_paths[%d]", i)
+		if len(p.Paths) == 0 {
+			return &ValidationError{Field: field + ".paths", Message: "at least one path is required"}
+		}
+		for from, to := range p.Escalate {
+			if err := check(field+".escalate", from); err != nil {
+				return err
+			}
+			if err := check(field+".escalate."+from, to); err != nil {
+				return err
+			}
+			if severityRank[strings.ToLower(to)] < severityRank[strings.ToLower(from)] {
+				return &ValidationError{Field: field + ".escalate." + from, Message: "must not lower the severity"}
+			}
+		}
+	}
+	for i, t := range r.IssueTypes {
+		if strings.TrimSpace(t.Name) == "" {
+			return &ValidationError{Field: fmt.Sprintf("review.issue_types[%d].name", i), Message: "issue type name is required"}
+		}
+	}
+	return nil
+}
+
+// DuplicatesConfig configures duplicate code detection. Blocks of added
+// code are compared locally against the rest of the repository and close
+// copies are reported as maintenance issues.
+type DuplicatesConfig struct {
+	// Enabled turns detection on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// MinTokens is the size, in tokens, below which added blocks are ignored
+	MinTokens int `mapstructure:"min_tokens" yaml:"min_tokens"`
+
+	// Similarity is the share of a block's fingerprints, between 0 and 1,
+	// that must match existing code to report it
+	Similarity float64 `mapstructure:"similarity" yaml:"similarity"`
+}
+
+// ThemesConfig configures grouping related issues into themes, shown at the
+// top of reports so reviews with many findings stay readable.
+type ThemesConfig struct {
+	// Enabled turns grouping on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// MinIssues is the fewest issues a review needs to be grouped
+	MinIssues int `mapstructure:"min_issues" yaml:"min_issues"`
+
+	// Similarity is how close, between 0 and 1, an issue's message must be
+	// to a theme's to join it
+	Similarity float64 `mapstructure:"similarity" yaml:"similarity"`
+}
+
+// ComplexityConfig configures complexity metrics. The functions changed by
+// the diff are measured locally; their metrics are added to the report and
+// each threshold a function exceeds is reported as a maintenance issue.
+// A threshold of 0 disables it.
+type ComplexityConfig struct {
+	// Enabled turns the metrics on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// MaxCyclomatic is the highest cyclomatic complexity allowed
+	MaxCyclomatic int `mapstructure:"max_cyclomatic" yaml:"max_cyclomatic"`
+
+	// MaxCognitive is the highest cognitive complexity allowed
+	MaxCognitive int `mapstructure:"max_cognitive" yaml:"max_cognitive"`
+
+	// MaxFunctionLines is the longest function allowed, in lines
+	MaxFunctionLines int `mapstructure:"max_function_lines" yaml:"max_function_lines"`
+}
+
+// DocStyleConfig configures the documentation checks the docs review mode
+// runs before the model: Go doc comments, Python docstrings and JSDoc
+// @param tags.
+type DocStyleConfig struct {
+	// Python is the docstring style of Python code: "google" (default) or
+	// "numpy"
+	Python string `mapstructure:"python" yaml:"python"`
+}
+
+// ScopeConfig configures the change scope check. Every review flags
+// formatting-only hunks and drive-by renames mixed with logic changes, and
+// file categories unrelated to the main one; in strict mode the review
+// fails when the unrelated categories exceed MaxUnrelated.
+type ScopeConfig struct {
+	// Strict fails the review on unrelated changes, like --strict-scope
+	Strict bool `mapstructure:"strict" yaml:"strict"`
+
+	// MaxUnrelated is the number of unrelated file categories strict mode
+	// tolerates (default 0)
+	MaxUnrelated int `mapstructure:"max_unrelated" yaml:"max_unrelated"`
+}
+
+// Guardrail actions
+const (
+	GuardrailSummarize = "summarize"
+	GuardrailSkip      = "skip"
+)
+
+// GuardrailsConfig keeps reports focused and cheap: the files a guard
+// catches are not reviewed but listed in the report, with a one-paragraph
+// summary from the model for the summarize action.
+type GuardrailsConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// MaxLines catches files with more changed lines (0 = no limit)
+	MaxLines int `mapstructure:"max_lines" yaml:"max_lines"`
+
+	// MaxTokens catches files whose diff has more estimated tokens
+	// (0 = no limit)
+	MaxTokens int `mapstructure:"max_tokens" yaml:"max_tokens"`
+
+	// Lockfiles catches dependency lockfiles, like go.sum or yarn.lock
+	Lockfiles bool `mapstructure:"lockfiles" yaml:"lockfiles"`
+
+	// VendorDirs catches files under directories with these names
+	VendorDirs []string `mapstructure:"vendor_dirs" yaml:"vendor_dirs"`
+
+	// Action is summarize (default) or skip
+	Action string `mapstructure:"action" yaml:"action"`
+}
+
+// TrivialKinds are the kinds of trivial changes: comment-only,
+// formatting-only (whitespace, line wrapping and trailing commas, as left
+// by gofmt or prettier) and version bumps in manifests and version files.
+var TrivialKinds = []string{"comments", "formatting", "version_bump"}
+
+// TrivialConfig skips the model for trivial changes. Their files are
+// auto-approved and listed in the report; the local checks, like debt and
+// spelling, still run on them.
+type TrivialConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// Kinds are the kinds of trivial changes approved: comments,
+	// formatting and version_bump (default all)
+	Kinds []string `mapstructure:"kinds" yaml:"kinds"`
+}
+
+// TriageConfig configures the triage workflow. The issues of each review are
+// recorded in the history database, where 'goreview triage' assigns and
+// transitions them, and reports show their triage status.
+type TriageConfig struct {
+	// Enabled turns recording on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// Recurrences is what happens to issues found again after being
+	// acknowledged or marked won't fix, by severity
+	Recurrences RecurrenceConfig `mapstructure:"recurrences" yaml:"recurrences"`
+
+	// Calibration adjusts the severity of rules the team keeps ignoring
+	Calibration CalibrationConfig `mapstructure:"calibration" yaml:"calibration"`
+}
+
+// CalibrationConfig configures severity calibration. The triaged issues of
+// each rule (or, for issues found by the model on its own, of each issue
+// type) in the history database tell how often the team fixes them or marks
+// them won't fix; findings of rules mostly marked won't fix are lowered one
+// severity level, or only annotated, so the reported urgency matches the
+// team's triage.
+type CalibrationConfig struct {
+	// Enabled turns calibration on; it needs triage enabled
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// MinTriaged is how many issues of a rule must have been resolved or
+	// marked won't fix before it is calibrated
+	MinTriaged int `mapstructure:"min_triaged" yaml:"min_triaged"`
+
+	// IgnoreRate is the fraction (0-1) of a rule's triaged issues marked
+	// won't fix from which its findings are calibrated
+	IgnoreRate float64 `mapstructure:"ignore_rate" yaml:"ignore_rate"`
+
+	// Action is downgrade (lower the severity one level, with a note) or
+	// note (only add the note)
+	Action string `mapstructure:"action" yaml:"action"`
+}
+
+// CalibrationActions are the valid calibration actions.
+var CalibrationActions = []string{"downgrade", "note"}
+
+// RecurrenceConfig sets the handling of recurring triaged issues of each
+// severity: report (as found), downgrade (to info, with a note) or suppress.
+type RecurrenceConfig struct {
+	Critical string `mapstructure:"critical" yaml:"critical"`
+	Error    string `mapstructure:"error" yaml:"error"`
+	Warning  string `mapstructure:"warning" yaml:"warning"`
+	Info     string `mapstructure:"info" yaml:"info"`
+}
+
+// For returns the handling of recurring issues of a severity, downgrade
+// when unset.
+func (r RecurrenceConfig) For(severity string) string {
+	action := map[string]string{"critical": r.Critical, "error": r.Error, "warning": r.Warning, "info": r.Info}[severity]
+	if action == "" {
+		return "downgrade"
+	}
+	return action
+}
+
+// DebtConfig configures the technical debt tracker. Debt comments added in
+// the diff are recorded in the history database, and those missing the
+// reference required by the policy are reported as issues.
+type DebtConfig struct {
+	// Enabled turns the tracker on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// Markers are the comment markers tracked as debt
+	Markers []string `mapstructure:"markers" yaml:"markers"`
+
+	// Require is what each debt comment must reference: none, owner,
+	// ticket or owner_or_ticket
+	Require string `mapstructure:"require" yaml:"require"`
+
+	// TicketPattern is the regexp of ticket references
+	TicketPattern string `mapstructure:"ticket_pattern" yaml:"ticket_pattern"`
+}
+
+// SizeImpactConfig configures build size impact estimation. The Go binaries
+// affected by the change are built before and after it, and bundle analyzer
+// reports are compared with their baseline; outputs growing by at least
+// MinGrowthKB are reported as warnings.
+type SizeImpactConfig struct {
+	// Enabled turns the estimation on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// MinGrowthKB is the growth, in KiB, from which an output is reported
+	MinGrowthKB int `mapstructure:"min_growth_kb" yaml:"min_growth_kb"`
+
+	// BundleReport is a webpack-bundle-analyzer JSON report or webpack
+	// stats file of the change, relative to the repository root
+	BundleReport string `mapstructure:"bundle_report" yaml:"bundle_report"`
+
+	// BundleBaseline is the same report for the base of the change
+	BundleBaseline string `mapstructure:"bundle_baseline" yaml:"bundle_baseline"`
+}
+
+// GeneratedConfig configures the policy for code that likely wasn't written
+// by hand: added blocks with an AI assistant marker, and long runs of added
+// lines that look pasted. Such blocks are labeled in the report; the strict
+// policy also reviews them with a lower severity threshold and requires the
+// file to have tests.
+type GeneratedConfig struct {
+	// Enabled turns detection on
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// Policy is "label" to only label the blocks, or "strict"
+	Policy string `mapstructure:"policy" yaml:"policy"`
+
+	// Markers are regexps of AI markers, matched case-insensitively against
+	// added lines; empty uses the built-in list
+	Markers []string `mapstructure:"markers" yaml:"markers,omitempty"`
+
+	// MinPasteLines is the length of a run of added lines in an existing
+	// file that counts as a paste; 0 disables paste detection
+	MinPasteLines int `mapstructure:"min_paste_lines" yaml:"min_paste_lines"`
+
+	// MinSeverity replaces review.min_severity for issues in generated
+	// blocks under the strict policy
+	MinSeverity string `mapstructure:"min_severity" yaml:"min_severity"`
+
+	// RequireTests reports files with generated blocks and no tests under
+	// the strict policy
+	RequireTests bool `mapstructure:"require_tests" yaml:"require_tests"`
+}
+
+// Generated code policies
+const (
+	GeneratedPolicyLabel  = "label"
+	GeneratedPolicyStrict = "strict"
+)
+
+// validate checks the generated code policy and marker patterns.
+func (g *GeneratedConfig) validate() error {
+	if !g.Enabled {
+		return nil
+	}
+	if g.Policy != GeneratedPolicyLabel && g.Policy != GeneratedPolicyStrict {
+		return &ValidationError{Field: "review.generated.policy", Message: "invalid policy, must be one of: label, strict"}
+	}
+	if g.MinPasteLines < 0 {
+		return &ValidationError{Field: "review.generated.min_paste_lines", Message: "must not be negative"}
+	}
+	for _, m := range g.Markers {
+		if _, err := regexp.Compile(m); err != nil {
+			return &ValidationError{Field: "review.generated.markers", Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// validate checks the recurrence handling of each severity.
+func (r *RecurrenceConfig) validate() error {
+	for severity, action := range map[string]string{"critical": r.Critical, "error": r.Error, "warning": r.Warning, "info": r.Info} {
+		if action != "" && action != "report" && action != "downgrade" && action != "suppress" {
+			return &ValidationError{
+				Field:   "review.triage.recurrences." + severity,
+				Message: "invalid action, must be one of: report, downgrade, suppress",
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the calibration thresholds and action.
+func (c *CalibrationConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MinTriaged < 1 {
+		return &ValidationError{Field: "review.triage.calibration.min_triaged", Message: "must be at least 1"}
+	}
+	if c.IgnoreRate <= 0 || c.IgnoreRate > 1 {
+		return &ValidationError{Field: "review.triage.calibration.ignore_rate", Message: "must be greater than 0 and at most 1"}
+	}
+	if !slices.Contains(CalibrationActions, c.Action) {
+		return &ValidationError{Field: "review.triage.calibration.action", Message: fmt.Sprintf("must be one of: %s", strings.Join(CalibrationActions, ", "))}
+	}
+	return nil
+}
+
+// validate checks the debt policy and ticket pattern.
+func (d *DebtConfig) validate() error {
+	if !d.Enabled {
+		return nil
+	}
+	validPolicies := map[string]bool{"": true, "none": true, "owner": true, "ticket": true, "owner_or_ticket": true}
+	if !validPolicies[d.Require] {
+		return &ValidationError{Field: "review.debt.require", Message: "invalid policy, must be one of: none, owner, ticket, owner_or_ticket"}
+	}
+	if _, err := regexp.Compile(d.TicketPattern); err != nil {
+		return &ValidationError{Field: "review.debt.ticket_pattern", Message: err.Error()}
+	}
+	return nil
+}
+
+// validate checks that the middleware has what its type needs.
+func (m *MiddlewareConfig) validate(field string) error {
+	switch m.Type {
+	case "instructions":
+		if len(m.Instructions) == 0 {
+			return &ValidationError{Field: field + ".instructions", Message: "at least one instruction is required"}
+		}
+	case "redact":
+		for _, p := range m.Patterns {
+			if _, err := regexp.Compile(p); err != nil {
+				return &ValidationError{Field: field + ".patterns", Message: err.Error()}
+			}
+		}
+	case "audit":
+		if m.Path == "" {
+			return &ValidationError{Field: field + ".path", Message: "path is required"}
+		}
+	case "exec":
+		if len(m.Command) == 0 {
+			return &ValidationError{Field: field + ".command", Message: "command is required"}
+		}
+		if m.Phase != "" && m.Phase != "before" && m.Phase != "after" {
+			return &ValidationError{Field: field + ".phase", Message: "invalid phase, must be one of: before, after"}
+		}
+		if m.Timeout < 0 {
+			return &ValidationError{Field: field + ".timeout", Message: "must not be negative"}
+		}
+	default:
+		return &ValidationError{Field: field + ".type", Message: "invalid type, must be one of: instructions, redact, audit, exec"}
+	}
 	return nil
 }
 
@@ -356,3 +1688,15 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return "config validation error: " + e.Field + ": " + e.Message
 }
+
+// ParseShard parses a shard like "2/4" into its 1-based index and the
+// number of shards.
+func ParseShard(s string) (index, total int, err error) {
+	i, n, ok := strings.Cut(s, "/")
+	index, errIndex := strconv.Atoi(strings.TrimSpace(i))
+	total, errTotal := strconv.Atoi(strings.TrimSpace(n))
+	if !ok || errIndex != nil || errTotal != nil || total < 1 || index < 1 || index > total {
+		return 0, 0, fmt.Errorf("invalid shard %q, must be index/total like 2/4", s)
+	}
+	return index, total, nil
+}
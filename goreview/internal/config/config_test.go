@@ -1,10 +1,14 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -92,6 +96,80 @@ func TestConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "review.mode",
 		},
+		{
+			name: "record and replay",
+			modify: func(c *Config) {
+				c.Provider.Record = "cassettes"
+				c.Provider.Replay = "cassettes"
+			},
+			wantErr: true,
+			errMsg:  "provider.replay",
+		},
+		{
+			name: "exec middleware without command",
+			modify: func(c *Config) {
+				c.Provider.Middleware = []MiddlewareConfig{{Type: "audit", Path: "audit.jsonl"}, {Type: "exec"}}
+			},
+			wantErr: true,
+			errMsg:  "provider.middleware[1].command",
+		},
+		{
+			name: "negative context radius",
+			modify: func(c *Config) {
+				c.Review.ContextRadius = -1
+			},
+			wantErr: true,
+			errMsg:  "review.context_radius",
+		},
+		{
+			name: "conventions without decisions",
+			modify: func(c *Config) {
+				c.Memory.Conventions.MinDecisions = 0
+			},
+			wantErr: true,
+			errMsg:  "memory.conventions",
+		},
+		{
+			name: "themes similarity above 1",
+			modify: func(c *Config) {
+				c.Review.Themes.Similarity = 1.5
+			},
+			wantErr: true,
+			errMsg:  "review.themes",
+		},
+		{
+			name: "negative time budget",
+			modify: func(c *Config) {
+				c.Review.TimeBudget = -time.Second
+			},
+			wantErr: true,
+			errMsg:  "review.time_budget",
+		},
+		{
+			name: "invalid shard",
+			modify: func(c *Config) {
+				c.Review.Shard = "5/4"
+			},
+			wantErr: true,
+			errMsg:  "review.shard",
+		},
+		{
+			name: "invalid triage recurrence action",
+			modify: func(c *Config) {
+				c.Review.Triage.Recurrences.Warning = "hide"
+			},
+			wantErr: true,
+			errMsg:  "review.triage.recurrences.warning",
+		},
+		{
+			name: "invalid calibration ignore rate",
+			modify: func(c *Config) {
+				c.Review.Triage.Calibration.Enabled = true
+				c.Review.Triage.Calibration.IgnoreRate = 80
+			},
+			wantErr: true,
+			errMsg:  "review.triage.calibration.ignore_rate",
+		},
 		{
 			name: "invalid output format",
 			modify: func(c *Config) {
@@ -100,6 +178,151 @@ func TestConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "output.format",
 		},
+		{
+			name: "invalid redact pattern",
+			modify: func(c *Config) {
+				c.Privacy.RedactPatterns = []string{"internal-[0-9+"}
+			},
+			wantErr: true,
+			errMsg:  "privacy.redact_patterns",
+		},
+		{
+			name: "artifact URL outside an object store",
+			modify: func(c *Config) {
+				c.Output.Artifacts.URL = "https://example.com/reports/"
+			},
+			wantErr: true,
+			errMsg:  "output.artifacts.url",
+		},
+		{
+			name: "invalid fail_on severity",
+			modify: func(c *Config) {
+				c.Review.FailOn = "fatal"
+			},
+			wantErr: true,
+			errMsg:  "review.fail_on",
+		},
+		{
+			name: "invalid severity override",
+			modify: func(c *Config) {
+				c.Review.SeverityOverrides.Types = map[string]string{"style": "low"}
+			},
+			wantErr: true,
+			errMsg:  "review.severity_overrides.types.style",
+		},
+		{
+			name: "invalid generated code policy",
+			modify: func(c *Config) {
+				c.Review.Generated.Enabled = true
+				c.Review.Generated.Policy = "block"
+			},
+			wantErr: true,
+			errMsg:  "review.generated.policy",
+		},
+		{
+			name: "protected path lowering a severity",
+			modify: func(c *Config) {
+				c.Review.ProtectedPaths = []ProtectedPathConfig{{Paths: []string{"auth/**"}, Escalate: map[string]string{"error": "warning"}}}
+			},
+			wantErr: true,
+			errMsg:  "review.protected_paths[0].escalate.error",
+		},
+		{
+			name: "protected path without paths",
+			modify: func(c *Config) {
+				c.Review.ProtectedPaths = []ProtectedPathConfig{{Name: "auth", Escalate: map[string]string{"warning": "error"}}}
+			},
+			wantErr: true,
+			errMsg:  "review.protected_paths[0].paths",
+		},
+		{
+			name: "duplicate gate name",
+			modify: func(c *Config) {
+				c.Gates = []GateConfig{{Name: "critical", Expr: "issues.critical == 0"}, {Name: "critical", Expr: "true"}}
+			},
+			wantErr: true,
+			errMsg:  "gates[1].name",
+		},
+		{
+			name: "knowledge source with unknown type",
+			modify: func(c *Config) {
+				c.Knowledge.Sources = []KnowledgeSourceConfig{{Name: "drive", Type: "gdrive"}}
+			},
+			wantErr: true,
+			errMsg:  "knowledge.sources[0].type",
+		},
+		{
+			name: "slack export without webhook url",
+			modify: func(c *Config) {
+				c.Export.Slack.Enabled = true
+			},
+			wantErr: true,
+			errMsg:  "export.slack.webhook_url",
+		},
+		{
+			name: "unknown vcs platform",
+			modify: func(c *Config) {
+				c.Export.VCS.Platform = "bitbucket"
+			},
+			wantErr: true,
+			errMsg:  "export.vcs.platform",
+		},
+		{
+			name: "unknown vcs label change type",
+			modify: func(c *Config) {
+				c.Export.VCS.Labels.Mapping = map[string]string{"feature": "enhancement"}
+			},
+			wantErr: true,
+			errMsg:  "export.vcs.labels.mapping",
+		},
+		{
+			name: "tenant without api keys",
+			modify: func(c *Config) {
+				c.Serve.Tenants = []TenantConfig{{ID: "team-a"}}
+			},
+			wantErr: true,
+			errMsg:  "serve.tenants[0].api_keys",
+		},
+		{
+			name: "tenant ID with path separator",
+			modify: func(c *Config) {
+				c.Serve.Tenants = []TenantConfig{{ID: "../team-a", APIKeys: []string{"k"}}}
+			},
+			wantErr: true,
+			errMsg:  "serve.tenants[0].id",
+		},
+		{
+			name: "commit scope rule with bad glob",
+			modify: func(c *Config) {
+				c.Commit.Scopes = []ScopeRule{{Path: "internal/[", Scope: "x"}}
+			},
+			wantErr: true,
+			errMsg:  "commit.scopes[0].path",
+		},
+		{
+			name: "changelog packages with the same name",
+			modify: func(c *Config) {
+				c.Changelog.Packages = []ChangelogPackage{{Path: "services/api"}, {Path: "packages/api"}}
+			},
+			wantErr: true,
+			errMsg:  "changelog.packages[1].name",
+		},
+		{
+			name: "unknown guardrail action",
+			modify: func(c *Config) {
+				c.Review.Guardrails.Action = "truncate"
+			},
+			wantErr: true,
+			errMsg:  "review.guardrails.action",
+		},
+		{
+			name: "unknown trivial kind",
+			modify: func(c *Config) {
+				c.Review.Trivial.Kinds = []string{"comments", "renames"}
+			},
+			wantErr: true,
+			errMsg:  "review.trivial.kinds",
+		},
 		{
 			name: "cache enabled without dir",
 			modify: func(c *Config) {
@@ -172,6 +395,84 @@ func TestLoaderEnvOverride(t *testing.T) {
 	}
 }
 
+func TestLoaderProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goreview.yaml")
+	content := `provider:
+  name: ollama
+  model: qwen2.5-coder:14b
+review:
+  personality: senior
+profiles:
+  oss:
+    provider:
+      name: openai
+      model: gpt-4o
+      api_key: sk-test
+  strict:
+    review:
+      personality: strict
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		profile         string
+		wantProvider    string
+		wantModel       string
+		wantPersonality string
+		wantErr         string
+	}{
+		{"no profile", "", "ollama", "qwen2.5-coder:14b", "senior", ""},
+		{"provider profile", "oss", "openai", "gpt-4o", "senior", ""},
+		{"partial profile", "strict", "ollama", "qwen2.5-coder:14b", "strict", ""},
+		{"unknown profile", "work", "", "", "", "available: oss, strict"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := NewLoader()
+			loader.SetConfigFile(path)
+			loader.SetProfile(tt.profile)
+
+			cfg, err := loader.Load()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Provider.Name != tt.wantProvider || cfg.Provider.Model != tt.wantModel || cfg.Review.Personality != tt.wantPersonality {
+				t.Errorf("got %s/%s/%s", cfg.Provider.Name, cfg.Provider.Model, cfg.Review.Personality)
+			}
+			if cfg.Profile != tt.profile {
+				t.Errorf("Profile = %q, want %q", cfg.Profile, tt.profile)
+			}
+		})
+	}
+}
+
+func TestLoaderProfileFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goreview.yaml")
+	content := "profiles:\n  local:\n    provider:\n      model: codellama:7b\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOREVIEW_PROFILE", "local")
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if cfg.Provider.Model != "codellama:7b" || cfg.Profile != "local" {
+		t.Errorf("Model = %q, Profile = %q", cfg.Provider.Model, cfg.Profile)
+	}
+}
+
 func TestValidationError(t *testing.T) {
 	err := &ValidationError{
 		Field:   "test.field",
@@ -203,3 +504,173 @@ func TestDefaultIgnorePatterns(t *testing.T) {
 		}
 	}
 }
+
+func TestLoaderReviewProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goreview.yaml")
+	content := `profiles:
+  pre-merge:
+    review:
+      modes: security,tests
+      personality: strict
+      fail_on: error
+    rules:
+      preset: strict
+    gates:
+      - name: no-critical
+        expr: issues.critical == 0
+    output:
+      format: sarif
+      file: review.sarif
+      reports:
+        - format: markdown
+          file: review.md
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader()
+	loader.SetConfigFile(path)
+	loader.SetProfile("pre-merge")
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Review.Modes != "security,tests" || cfg.Review.Personality != "strict" || cfg.Review.FailOn != "error" || cfg.Rules.Preset != "strict" {
+		t.Errorf("review = %+v, preset = %q", cfg.Review, cfg.Rules.Preset)
+	}
+	if len(cfg.Gates) != 1 || cfg.Gates[0].Name != "no-critical" {
+		t.Errorf("Gates = %+v", cfg.Gates)
+	}
+	if cfg.Output.Format != "sarif" || len(cfg.Output.Reports) != 1 || cfg.Output.Reports[0] != (ReportOutputConfig{Format: "markdown", File: "review.md"}) {
+		t.Errorf("Output = %+v", cfg.Output)
+	}
+}
+
+func TestLoaderUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".goreview.yaml")
+	content := `reveiw:
+  mode: staged
+review:
+  max_isues: 10
+  format: json
+  themes:
+    enabled: true
+gates:
+  - name: no-critical
+    expr: "issues.critical == 0"
+    descripton: blocks merges
+profiles:
+  ci:
+    output:
+      colr: false
+    review:
+      format: sarif
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var warnings []string
+	loader := NewLoader()
+	loader.SetConfigFile(path)
+	loader.SetWarn(func(msg string) { warnings = append(warnings, msg) })
+	if _, err := loader.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []UnknownKey{
+		{Key: "gates[0].descripton"},
+		{Key: "profiles.ci.output.colr", Suggestion: "profiles.ci.output.color"},
+		{Key: "profiles.ci.review.format", Suggestion: "profiles.ci.output.format"},
+		{Key: "reveiw", Suggestion: "review"},
+		{Key: "review.format", Suggestion: "output.format"},
+		{Key: "review.max_isues", Suggestion: "review.max_issues"},
+	}
+	got := loader.UnknownKeys()
+	if len(got) != len(want) {
+		t.Fatalf("UnknownKeys() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("UnknownKeys()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if len(warnings) != len(want) || warnings[5] != `unknown config key "review.max_isues" (did you mean "review.max_issues"?)` {
+		t.Errorf("warnings = %q", warnings)
+	}
+
+	loader = NewLoader()
+	loader.SetConfigFile(path)
+	loader.SetStrict(true)
+	_, err := loader.Load()
+	var unknown *UnknownKeysError
+	if !errors.As(err, &unknown) || len(unknown.Keys) != len(want) {
+		t.Errorf("strict Load() error = %v, want the unknown keys", err)
+	}
+
+	// strict_config in the file is strict too
+	if err := os.WriteFile(path, []byte("strict_config: true\noutput:\n  formt: json\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFromFile(path); !errors.As(err, &unknown) {
+		t.Errorf("Load() with strict_config error = %v, want the unknown keys", err)
+	}
+}
+
+func TestUnknownKeysOfDefaults(t *testing.T) {
+	// Every option of the default config, as written to a file, is known
+	data, err := yaml.Marshal(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings map[string]any
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if unknown := UnknownKeys(settings); len(unknown) != 0 {
+		t.Errorf("UnknownKeys(defaults) = %+v", unknown)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+	review := schema["properties"].(map[string]any)["review"].(map[string]any)
+	maxIssues := review["properties"].(map[string]any)["max_issues"].(map[string]any)
+	if maxIssues["type"] != "integer" || maxIssues["default"] != DefaultConfig().Review.MaxIssues || maxIssues["description"] == "" {
+		t.Errorf("review.max_issues = %+v", maxIssues)
+	}
+	if review["additionalProperties"] != false {
+		t.Error("review accepts unknown keys")
+	}
+	if _, ok := schema["properties"].(map[string]any)[profilesKey]; !ok {
+		t.Error("schema has no profiles")
+	}
+
+	options := Options()
+	var timeout *Option
+	for i, o := range options {
+		if o.Key == "provider.timeout" {
+			timeout = &options[i]
+		}
+	}
+	if timeout == nil || timeout.Type != "duration" || timeout.Default == "" || timeout.Description == "" {
+		t.Errorf("provider.timeout = %+v", timeout)
+	}
+}
+
+func TestSchemaPathDefaults(t *testing.T) {
+	t.Setenv("HOME", "/home/ci-runner")
+	cache := Schema()["properties"].(map[string]any)["cache"].(map[string]any)
+	if dir := cache["properties"].(map[string]any)["dir"].(map[string]any); dir["default"] != "~/.cache/goreview" {
+		t.Errorf("cache.dir default = %v, want the placeholder", dir["default"])
+	}
+	for _, o := range Options() {
+		if strings.Contains(o.Default, "ci-runner") {
+			t.Errorf("%s default = %q, want no host path", o.Key, o.Default)
+		}
+		if o.Key == "memory.dir" && o.Default != "~/.cache/goreview/memory" {
+			t.Errorf("memory.dir default = %q", o.Default)
+		}
+	}
+}
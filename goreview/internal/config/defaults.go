@@ -17,9 +17,23 @@ func DefaultConfig() *Config {
 		Review:   defaultReviewConfig(),
 		Output:   defaultOutputConfig(),
 		Cache:    defaultCacheConfig(cacheDir),
-		Rules:    RulesConfig{Preset: "standard"},
+		Rules:    RulesConfig{Preset: "standard", PacksDir: filepath.Join(cacheDir, "rule-packs")},
 		Memory:   defaultMemoryConfig(cacheDir),
+		RAG:      RAGConfig{Packs: KnowledgePacksConfig{Dirs: []string{".goreview/packs"}}},
 		Export:   defaultExportConfig(),
+		Knowledge: KnowledgeConfig{
+			CacheDir: filepath.Join(cacheDir, "knowledge"),
+			MaxDocs:  10,
+		},
+		Privacy: PrivacyConfig{RedactSecrets: true},
+		Serve: ServeConfig{
+			Addr:         "127.0.0.1:8080",
+			DataDir:      filepath.Join(cacheDir, "tenants"),
+			MaxBodyBytes: 5 << 20,
+			Workers:      2,
+		},
+		Commit:    CommitConfig{InferScope: true, MaxScopes: 2, LearnScopes: true},
+		Changelog: ChangelogConfig{PackageGlobs: []string{"packages/*", "apps/*"}},
 	}
 }
 
@@ -57,6 +71,7 @@ func defaultGitConfig() GitConfig {
 			"dist/*", "build/*", "node_modules/*", "vendor/*",
 			"*.json", "*.yaml", "*.yml", "*.toml",
 		},
+		IgnoreFile: ".goreviewignore",
 	}
 }
 
@@ -65,9 +80,68 @@ func defaultReviewConfig() ReviewConfig {
 	return ReviewConfig{
 		Mode:           "staged",
 		MinSeverity:    "warning",
+		FailOn:         "critical",
 		MaxIssues:      50,
 		MaxConcurrency: 0,
 		Personality:    "default",
+		ChangeSummary:  true,
+		Themes: ThemesConfig{
+			Enabled:    true,
+			MinIssues:  20,
+			Similarity: 0.3,
+		},
+		Duplicates: DuplicatesConfig{
+			Enabled:    false,
+			MinTokens:  50,
+			Similarity: 0.8,
+		},
+		Complexity: ComplexityConfig{
+			Enabled:          false,
+			MaxCyclomatic:    15,
+			MaxCognitive:     20,
+			MaxFunctionLines: 80,
+		},
+		DocStyle: DocStyleConfig{
+			Python: "google",
+		},
+		Debt: DebtConfig{
+			Enabled:       false,
+			Markers:       []string{"TODO", "FIXME", "HACK"},
+			Require:       "owner_or_ticket",
+			TicketPattern: `[A-Z][A-Z0-9]+-\d+|#\d+`,
+		},
+		Guardrails: GuardrailsConfig{
+			Enabled:    false,
+			MaxLines:   2000,
+			MaxTokens:  0,
+			Lockfiles:  true,
+			VendorDirs: []string{"vendor", "node_modules", "third_party"},
+			Action:     GuardrailSummarize,
+		},
+		Trivial: TrivialConfig{
+			Enabled: false,
+			Kinds:   []string{"comments", "formatting", "version_bump"},
+		},
+		Triage: TriageConfig{
+			Recurrences: RecurrenceConfig{Critical: "downgrade", Error: "downgrade", Warning: "downgrade", Info: "downgrade"},
+			Calibration: CalibrationConfig{MinTriaged: 10, IgnoreRate: 0.8, Action: "downgrade"},
+		},
+		SizeImpact: SizeImpactConfig{
+			Enabled:     false,
+			MinGrowthKB: 100,
+		},
+		Generated: GeneratedConfig{
+			Enabled:       false,
+			Policy:        GeneratedPolicyLabel,
+			MinPasteLines: 60,
+			MinSeverity:   "info",
+			RequireTests:  true,
+		},
+		AdaptiveConcurrency: AdaptiveConcurrencyConfig{
+			Enabled:    false,
+			MinWorkers: 1,
+			MaxWorkers: 16,
+		},
 	}
 }
 
@@ -90,6 +164,7 @@ func defaultCacheConfig(cacheDir string) CacheConfig {
 		TTL:        24 * time.Hour,
 		MaxSizeMB:  100,
 		MaxEntries: 1000,
+		Normalize:  true,
 	}
 }
 
@@ -117,6 +192,12 @@ func defaultMemoryConfig(cacheDir string) MemoryConfig {
 			DecayRate:    0.01,
 			MinStrength:  0.1,
 		},
+		Conventions: ConventionsConfig{
+			Enabled:      true,
+			MinDecisions: 2,
+			MaxPrompt:    15,
+		},
+		MaintenanceInterval: 30 * time.Minute,
 	}
 }
 
@@ -133,7 +214,18 @@ func defaultExportConfig() ExportConfig {
 			LinkToPreviousReviews: true,
 			CustomTags:            []string{},
 			TemplateFile:          "",
+			IssueNotes:            false,
+			Canvas:                false,
+		},
+		Slack: SlackExportConfig{
+			Enabled:   false,
+			MaxIssues: 10,
+		},
+		Webhook: WebhookExportConfig{
+			Enabled: false,
+			Headers: map[string]string{},
 		},
+		Timeout: 30 * time.Second,
 	}
 }
 
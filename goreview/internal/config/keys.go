@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownKey is a key of the config file that no option reads.
+type UnknownKey struct {
+	// Key is the dotted key, like "review.max_isues"
+	Key string
+	// Suggestion is the option most likely meant, or ""
+	Suggestion string
+}
+
+func (k UnknownKey) String() string {
+	if k.Suggestion != "" {
+		return fmt.Sprintf("unknown config key %q (did you mean %q?)", k.Key, k.Suggestion)
+	}
+	return fmt.Sprintf("unknown config key %q", k.Key)
+}
+
+// UnknownKeysError is returned by strict loads of a config file with
+// unknown keys.
+type UnknownKeysError struct {
+	File string
+	Keys []UnknownKey
+}
+
+func (e *UnknownKeysError) Error() string {
+	lines := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		lines[i] = k.String()
+	}
+	return fmt.Sprintf("%s: %s", e.File, strings.Join(lines, "; "))
+}
+
+// UnknownKeys returns the keys of settings, a config file's contents, that
+// no option reads, sorted. Profiles are checked like the top level.
+func UnknownKeys(settings map[string]any) []UnknownKey {
+	var unknown []UnknownKey
+	root := reflect.TypeOf(Config{})
+	for key, value := range settings {
+		if strings.EqualFold(key, profilesKey) {
+			profiles, _ := value.(map[string]any)
+			for name, profile := range profiles {
+				if m, ok := profile.(map[string]any); ok {
+					checkKeys(m, root, profilesKey+"."+name+".", &unknown)
+				}
+			}
+			continue
+		}
+		checkKeys(map[string]any{key: value}, root, "", &unknown)
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Key < unknown[j].Key })
+	return unknown
+}
+
+func checkKeys(settings map[string]any, t reflect.Type, prefix string, unknown *[]UnknownKey) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fields := make(map[string]reflect.Type)
+	var names []string
+	for _, f := range configFields(t) {
+		fields[f.name] = f.typ
+		names = append(names, f.name)
+	}
+
+	for key, value := range settings {
+		ft, ok := fields[strings.ToLower(key)]
+		if !ok {
+			*unknown = append(*unknown, UnknownKey{Key: prefix + key, Suggestion: suggestKey(prefix, key, names)})
+			continue
+		}
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft.Kind() == reflect.Struct && ft != durationType:
+			if m, ok := value.(map[string]any); ok {
+				checkKeys(m, ft, prefix+key+".", unknown)
+			}
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct:
+			items, _ := value.([]any)
+			for i, item := range items {
+				if m, ok := item.(map[string]any); ok {
+					checkKeys(m, ft.Elem(), fmt.Sprintf("%s%s[%d].", prefix, key, i), unknown)
+				}
+			}
+		case ft.Kind() == reflect.Map && ft.Elem().Kind() == reflect.Struct:
+			entries, _ := value.(map[string]any)
+			for name, entry := range entries {
+				if m, ok := entry.(map[string]any); ok {
+					checkKeys(m, ft.Elem(), prefix+key+"."+name+".", unknown)
+				}
+			}
+		}
+	}
+}
+
+// suggestKey returns the key most likely meant by an unknown one: a
+// sibling a typo away or, failing that, an option of the same name in
+// another section.
+func suggestKey(prefix, key string, siblings []string) string {
+	key = strings.ToLower(key)
+	best, bestDistance := "", max(1, len(key)/4)
+	for _, name := range siblings {
+		if d := editDistance(key, name); d <= bestDistance && (best == "" || d < editDistance(key, best)) {
+			best = name
+		}
+	}
+	if best != "" {
+		return prefix + best
+	}
+
+	// Within a profile, the option is the profile's
+	root := ""
+	if rest, ok := strings.CutPrefix(prefix, profilesKey+"."); ok {
+		name, _, _ := strings.Cut(rest, ".")
+		root = profilesKey + "." + name + "."
+	}
+	for _, opt := range Options() {
+		if strings.HasSuffix(opt.Key, "."+key) && !strings.Contains(opt.Key, "[]") && !strings.Contains(opt.Key, "<name>") {
+			return root + opt.Key
+		}
+	}
+	return ""
+}
+
+// editDistance is the edit distance between two keys, counting swapped
+// letters as one edit, like in "reveiw".
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
@@ -1,23 +1,34 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/viper"
+
+	"github.com/JNZader/goreview/goreview/internal/failure"
 )
 
 // Config file constants (SonarQube S1192)
 const (
 	configFileName = ".goreview.yaml"
+
+	// profilesKey is the config section holding named profiles
+	profilesKey = "profiles"
 )
 
 // Loader handles configuration loading from multiple sources.
 type Loader struct {
 	v          *viper.Viper
 	configFile string
+	profile    string
+	strict     bool
+	warn       func(string)
+	unknown    []UnknownKey
 }
 
 // NewLoader creates a new configuration loader.
@@ -47,12 +58,36 @@ func (l *Loader) SetConfigFile(path string) {
 	l.v.SetConfigFile(path)
 }
 
+// SetProfile selects a named profile from the config file's profiles
+// section. It overrides the profile key of the config file.
+func (l *Loader) SetProfile(name string) {
+	l.profile = name
+}
+
+// SetStrict makes Load fail on unknown keys in the config file, like the
+// strict_config key.
+func (l *Loader) SetStrict(strict bool) {
+	l.strict = strict
+}
+
+// SetWarn sets where Load reports unknown keys of the config file when it
+// isn't strict.
+func (l *Loader) SetWarn(warn func(msg string)) {
+	l.warn = warn
+}
+
+// UnknownKeys returns the unknown keys of the config file found by Load.
+func (l *Loader) UnknownKeys() []UnknownKey {
+	return l.unknown
+}
+
 // Load loads the configuration from all sources.
 // Priority (highest to lowest):
 // 1. Explicit config file (if set via SetConfigFile)
 // 2. Environment variables (GOREVIEW_*)
-// 3. Config file from search paths (.goreview.yaml)
-// 4. Default values
+// 3. Selected profile (profiles.<name> in the config file)
+// 4. Config file from search paths (.goreview.yaml)
+// 5. Default values
 func (l *Loader) Load() (*Config, error) {
 	// Start with defaults
 	cfg := DefaultConfig()
@@ -64,19 +99,30 @@ func (l *Loader) Load() (*Config, error) {
 	if err := l.v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			// Config file found but error reading it
-			return nil, fmt.Errorf("error reading config file: %w", err)
+			return nil, &failure.ConfigError{Code: failure.ConfigUnreadable, Err: fmt.Errorf("error reading config file: %w", err)}
 		}
 		// Config file not found - that's ok, we'll use defaults
+	} else if err := l.checkKeys(); err != nil {
+		return nil, &failure.ConfigError{Code: failure.ConfigUnknownKey, Err: err}
+	}
+
+	if err := l.applyProfile(); err != nil {
+		return nil, &failure.ConfigError{Code: failure.ConfigInvalid, Field: profilesKey, Err: err}
 	}
 
 	// Unmarshal into config struct
 	if err := l.v.Unmarshal(cfg); err != nil {
-		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+		return nil, &failure.ConfigError{Code: failure.ConfigUnreadable, Err: fmt.Errorf("error unmarshaling config: %w", err)}
 	}
 
 	// Validate the final config
 	if err := cfg.Validate(); err != nil {
-		return nil, err
+		cerr := &failure.ConfigError{Code: failure.ConfigInvalid, Err: err}
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			cerr.Field = verr.Field
+		}
+		return nil, cerr
 	}
 
 	return cfg, nil
@@ -92,17 +138,87 @@ func (l *Loader) setDefaults(cfg *Config) {
 	l.v.SetDefault("provider.max_tokens", cfg.Provider.MaxTokens)
 	l.v.SetDefault("provider.temperature", cfg.Provider.Temperature)
 	l.v.SetDefault("provider.rate_limit_rps", cfg.Provider.RateLimitRPS)
+	l.v.SetDefault("provider.record", cfg.Provider.Record)
+	l.v.SetDefault("provider.replay", cfg.Provider.Replay)
+	l.v.SetDefault("provider.capabilities.context_window", cfg.Provider.Capabilities.ContextWindow)
+	l.v.SetDefault("provider.capabilities.max_output_tokens", cfg.Provider.Capabilities.MaxOutputTokens)
+	l.v.SetDefault("provider.vertex.project", cfg.Provider.Vertex.Project)
+	l.v.SetDefault("provider.vertex.location", cfg.Provider.Vertex.Location)
+	l.v.SetDefault("provider.vertex.credentials", cfg.Provider.Vertex.Credentials)
 
 	// Git defaults
 	l.v.SetDefault("git.repo_path", cfg.Git.RepoPath)
 	l.v.SetDefault("git.base_branch", cfg.Git.BaseBranch)
 	l.v.SetDefault("git.ignore_patterns", cfg.Git.IgnorePatterns)
+	l.v.SetDefault("git.include_patterns", cfg.Git.IncludePatterns)
+	l.v.SetDefault("git.ignore_file", cfg.Git.IgnoreFile)
 
 	// Review defaults
 	l.v.SetDefault("review.mode", cfg.Review.Mode)
 	l.v.SetDefault("review.min_severity", cfg.Review.MinSeverity)
+	l.v.SetDefault("review.fail_on", cfg.Review.FailOn)
+	l.v.SetDefault("review.conformance_policy", cfg.Review.ConformancePolicy)
 	l.v.SetDefault("review.max_issues", cfg.Review.MaxIssues)
 	l.v.SetDefault("review.max_concurrency", cfg.Review.MaxConcurrency)
+	l.v.SetDefault("review.max_chunk_tokens", cfg.Review.MaxChunkTokens)
+	l.v.SetDefault("review.incremental", cfg.Review.Incremental)
+	l.v.SetDefault("review.change_summary", cfg.Review.ChangeSummary)
+	l.v.SetDefault("review.pr_context", cfg.Review.PRContext)
+	l.v.SetDefault("review.full", cfg.Review.Full)
+	l.v.SetDefault("review.include_untracked", cfg.Review.IncludeUntracked)
+	l.v.SetDefault("review.context_radius", cfg.Review.ContextRadius)
+	l.v.SetDefault("review.time_budget", cfg.Review.TimeBudget)
+	l.v.SetDefault("review.token_budget", cfg.Review.TokenBudget)
+	l.v.SetDefault("review.shard", cfg.Review.Shard)
+	l.v.SetDefault("review.spelling.enabled", cfg.Review.Spelling.Enabled)
+	l.v.SetDefault("review.themes.enabled", cfg.Review.Themes.Enabled)
+	l.v.SetDefault("review.themes.min_issues", cfg.Review.Themes.MinIssues)
+	l.v.SetDefault("review.themes.similarity", cfg.Review.Themes.Similarity)
+	l.v.SetDefault("review.duplicates.enabled", cfg.Review.Duplicates.Enabled)
+	l.v.SetDefault("review.duplicates.min_tokens", cfg.Review.Duplicates.MinTokens)
+	l.v.SetDefault("review.duplicates.similarity", cfg.Review.Duplicates.Similarity)
+	l.v.SetDefault("review.complexity.enabled", cfg.Review.Complexity.Enabled)
+	l.v.SetDefault("review.complexity.max_cyclomatic", cfg.Review.Complexity.MaxCyclomatic)
+	l.v.SetDefault("review.complexity.max_cognitive", cfg.Review.Complexity.MaxCognitive)
+	l.v.SetDefault("review.complexity.max_function_lines", cfg.Review.Complexity.MaxFunctionLines)
+	l.v.SetDefault("review.doc_style.python", cfg.Review.DocStyle.Python)
+	l.v.SetDefault("review.scope.strict", cfg.Review.Scope.Strict)
+	l.v.SetDefault("review.scope.max_unrelated", cfg.Review.Scope.MaxUnrelated)
+	l.v.SetDefault("review.guardrails.enabled", cfg.Review.Guardrails.Enabled)
+	l.v.SetDefault("review.guardrails.max_lines", cfg.Review.Guardrails.MaxLines)
+	l.v.SetDefault("review.guardrails.max_tokens", cfg.Review.Guardrails.MaxTokens)
+	l.v.SetDefault("review.guardrails.lockfiles", cfg.Review.Guardrails.Lockfiles)
+	l.v.SetDefault("review.guardrails.vendor_dirs", cfg.Review.Guardrails.VendorDirs)
+	l.v.SetDefault("review.guardrails.action", cfg.Review.Guardrails.Action)
+	l.v.SetDefault("review.trivial.enabled", cfg.Review.Trivial.Enabled)
+	l.v.SetDefault("review.trivial.kinds", cfg.Review.Trivial.Kinds)
+	l.v.SetDefault("review.debt.enabled", cfg.Review.Debt.Enabled)
+	l.v.SetDefault("review.debt.markers", cfg.Review.Debt.Markers)
+	l.v.SetDefault("review.debt.require", cfg.Review.Debt.Require)
+	l.v.SetDefault("review.debt.ticket_pattern", cfg.Review.Debt.TicketPattern)
+	l.v.SetDefault("review.triage.enabled", cfg.Review.Triage.Enabled)
+	l.v.SetDefault("review.triage.recurrences.critical", cfg.Review.Triage.Recurrences.Critical)
+	l.v.SetDefault("review.triage.recurrences.error", cfg.Review.Triage.Recurrences.Error)
+	l.v.SetDefault("review.triage.recurrences.warning", cfg.Review.Triage.Recurrences.Warning)
+	l.v.SetDefault("review.triage.recurrences.info", cfg.Review.Triage.Recurrences.Info)
+	l.v.SetDefault("review.triage.calibration.enabled", cfg.Review.Triage.Calibration.Enabled)
+	l.v.SetDefault("review.triage.calibration.min_triaged", cfg.Review.Triage.Calibration.MinTriaged)
+	l.v.SetDefault("review.triage.calibration.ignore_rate", cfg.Review.Triage.Calibration.IgnoreRate)
+	l.v.SetDefault("review.triage.calibration.action", cfg.Review.Triage.Calibration.Action)
+	l.v.SetDefault("review.size_impact.enabled", cfg.Review.SizeImpact.Enabled)
+	l.v.SetDefault("review.size_impact.min_growth_kb", cfg.Review.SizeImpact.MinGrowthKB)
+	l.v.SetDefault("review.size_impact.bundle_report", cfg.Review.SizeImpact.BundleReport)
+	l.v.SetDefault("review.size_impact.bundle_baseline", cfg.Review.SizeImpact.BundleBaseline)
+	l.v.SetDefault("review.generated.enabled", cfg.Review.Generated.Enabled)
+	l.v.SetDefault("review.generated.policy", cfg.Review.Generated.Policy)
+	l.v.SetDefault("review.generated.min_paste_lines", cfg.Review.Generated.MinPasteLines)
+	l.v.SetDefault("review.generated.min_severity", cfg.Review.Generated.MinSeverity)
+	l.v.SetDefault("review.generated.require_tests", cfg.Review.Generated.RequireTests)
+	l.v.SetDefault("review.adaptive_concurrency.enabled", cfg.Review.AdaptiveConcurrency.Enabled)
+	l.v.SetDefault("review.adaptive_concurrency.min_workers", cfg.Review.AdaptiveConcurrency.MinWorkers)
+	l.v.SetDefault("review.adaptive_concurrency.max_workers", cfg.Review.AdaptiveConcurrency.MaxWorkers)
+	l.v.SetDefault("review.adaptive_concurrency.target_latency", cfg.Review.AdaptiveConcurrency.TargetLatency)
+	l.v.SetDefault("review.adaptive_concurrency.max_memory_mb", cfg.Review.AdaptiveConcurrency.MaxMemoryMB)
 
 	// Output defaults
 	l.v.SetDefault("output.format", cfg.Output.Format)
@@ -110,6 +226,9 @@ func (l *Loader) setDefaults(cfg *Config) {
 	l.v.SetDefault("output.color", cfg.Output.Color)
 	l.v.SetDefault("output.verbose", cfg.Output.Verbose)
 	l.v.SetDefault("output.quiet", cfg.Output.Quiet)
+	l.v.SetDefault("output.artifacts.url", cfg.Output.Artifacts.URL)
+	l.v.SetDefault("output.artifacts.region", cfg.Output.Artifacts.Region)
+	l.v.SetDefault("output.artifacts.endpoint", cfg.Output.Artifacts.Endpoint)
 
 	// Cache defaults
 	l.v.SetDefault("cache.enabled", cfg.Cache.Enabled)
@@ -117,9 +236,27 @@ func (l *Loader) setDefaults(cfg *Config) {
 	l.v.SetDefault("cache.ttl", cfg.Cache.TTL)
 	l.v.SetDefault("cache.max_size_mb", cfg.Cache.MaxSizeMB)
 	l.v.SetDefault("cache.max_entries", cfg.Cache.MaxEntries)
+	l.v.SetDefault("cache.normalize", cfg.Cache.Normalize)
 
 	// Rules defaults
 	l.v.SetDefault("rules.preset", cfg.Rules.Preset)
+	l.v.SetDefault("rules.packs_dir", cfg.Rules.PacksDir)
+
+	// RAG defaults
+	l.v.SetDefault("rag.packs.enabled", cfg.RAG.Packs.Enabled)
+	l.v.SetDefault("rag.packs.dirs", cfg.RAG.Packs.Dirs)
+
+	// Knowledge defaults
+	l.v.SetDefault("knowledge.enabled", cfg.Knowledge.Enabled)
+	l.v.SetDefault("knowledge.cache_dir", cfg.Knowledge.CacheDir)
+	l.v.SetDefault("knowledge.max_docs", cfg.Knowledge.MaxDocs)
+	l.v.SetDefault("privacy.redact_secrets", cfg.Privacy.RedactSecrets)
+
+	// Memory defaults
+	l.v.SetDefault("memory.conventions.enabled", cfg.Memory.Conventions.Enabled)
+	l.v.SetDefault("memory.conventions.min_decisions", cfg.Memory.Conventions.MinDecisions)
+	l.v.SetDefault("memory.conventions.max_prompt", cfg.Memory.Conventions.MaxPrompt)
+	l.v.SetDefault("memory.maintenance_interval", cfg.Memory.MaintenanceInterval)
 
 	// Export defaults
 	l.v.SetDefault("export.obsidian.enabled", cfg.Export.Obsidian.Enabled)
@@ -131,6 +268,95 @@ func (l *Loader) setDefaults(cfg *Config) {
 	l.v.SetDefault("export.obsidian.link_to_previous", cfg.Export.Obsidian.LinkToPreviousReviews)
 	l.v.SetDefault("export.obsidian.custom_tags", cfg.Export.Obsidian.CustomTags)
 	l.v.SetDefault("export.obsidian.template_file", cfg.Export.Obsidian.TemplateFile)
+	l.v.SetDefault("export.obsidian.issue_notes", cfg.Export.Obsidian.IssueNotes)
+	l.v.SetDefault("export.obsidian.canvas", cfg.Export.Obsidian.Canvas)
+	l.v.SetDefault("export.slack.enabled", cfg.Export.Slack.Enabled)
+	l.v.SetDefault("export.slack.webhook_url", cfg.Export.Slack.WebhookURL)
+	l.v.SetDefault("export.slack.channel", cfg.Export.Slack.Channel)
+	l.v.SetDefault("export.slack.max_issues", cfg.Export.Slack.MaxIssues)
+	l.v.SetDefault("export.webhook.enabled", cfg.Export.Webhook.Enabled)
+	l.v.SetDefault("export.webhook.url", cfg.Export.Webhook.URL)
+	l.v.SetDefault("export.webhook.headers", cfg.Export.Webhook.Headers)
+	l.v.SetDefault("export.vcs.enabled", cfg.Export.VCS.Enabled)
+	l.v.SetDefault("export.vcs.platform", cfg.Export.VCS.Platform)
+	l.v.SetDefault("export.vcs.repo", cfg.Export.VCS.Repo)
+	l.v.SetDefault("export.vcs.number", cfg.Export.VCS.Number)
+	l.v.SetDefault("export.vcs.token", cfg.Export.VCS.Token)
+	l.v.SetDefault("export.vcs.api_url", cfg.Export.VCS.APIURL)
+	l.v.SetDefault("export.vcs.labels.enabled", cfg.Export.VCS.Labels.Enabled)
+	l.v.SetDefault("export.timeout", cfg.Export.Timeout)
+
+	// Serve defaults
+	l.v.SetDefault("serve.addr", cfg.Serve.Addr)
+	l.v.SetDefault("serve.data_dir", cfg.Serve.DataDir)
+	l.v.SetDefault("serve.max_body_bytes", cfg.Serve.MaxBodyBytes)
+	l.v.SetDefault("serve.workers", cfg.Serve.Workers)
+
+	// Commit defaults
+	l.v.SetDefault("commit.infer_scope", cfg.Commit.InferScope)
+	l.v.SetDefault("commit.max_scopes", cfg.Commit.MaxScopes)
+	l.v.SetDefault("commit.learn_scopes", cfg.Commit.LearnScopes)
+
+	// Changelog defaults
+	l.v.SetDefault("changelog.package_globs", cfg.Changelog.PackageGlobs)
+
+	l.v.SetDefault("strict_config", cfg.StrictConfig)
+}
+
+// checkKeys looks for keys of the config file no option reads, which
+// would otherwise be silently ignored, like misspelled ones.
+func (l *Loader) checkKeys() error {
+	l.unknown = UnknownKeys(l.v.AllSettings())
+	if len(l.unknown) == 0 {
+		return nil
+	}
+	if l.strict || l.v.GetBool("strict_config") {
+		return &UnknownKeysError{File: l.v.ConfigFileUsed(), Keys: l.unknown}
+	}
+	if l.warn != nil {
+		for _, k := range l.unknown {
+			l.warn(k.String())
+		}
+	}
+	return nil
+}
+
+// applyProfile merges the selected profile over the config file values.
+// The profile comes from SetProfile, or else the profile key (which can also
+// be set with GOREVIEW_PROFILE).
+func (l *Loader) applyProfile() error {
+	name := l.profile
+	if name == "" {
+		name = l.v.GetString("profile")
+	}
+	if name == "" {
+		return nil
+	}
+
+	sub := l.v.Sub(profilesKey + "." + name)
+	if sub == nil {
+		available := l.Profiles()
+		if len(available) == 0 {
+			return fmt.Errorf("unknown profile %q: no profiles defined in config file", name)
+		}
+		return fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(available, ", "))
+	}
+	if err := l.v.MergeConfigMap(sub.AllSettings()); err != nil {
+		return fmt.Errorf("applying profile %q: %w", name, err)
+	}
+	l.v.Set("profile", name)
+	return nil
+}
+
+// Profiles returns the names of the profiles defined in the config file.
+func (l *Loader) Profiles() []string {
+	profiles := l.v.GetStringMap(profilesKey)
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // ConfigFileUsed returns the path of the config file used, if any.
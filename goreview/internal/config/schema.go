@@ -0,0 +1,289 @@
+package config
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// configSource is this package's config structs, whose doc comments
+// describe the options in the schema and the option reference.
+//
+//go:embed config.go
+var configSource string
+
+// durationPattern matches Go durations like "30s" or "1h30m"
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// cacheDirPlaceholder stands for the default cache directory in the schema
+// and the option reference, which would otherwise show this host's home.
+const cacheDirPlaceholder = "~/.cache/goreview"
+
+var (
+	docsOnce sync.Once
+	docs     map[string]string // "Type.Field" or "Type" -> doc comment
+)
+
+// Option is a config option, as listed in the option reference.
+type Option struct {
+	// Key is the option's dotted key, like "review.max_issues"; list items
+	// and map values add "[]" and "<name>" segments
+	Key         string
+	Type        string
+	Default     string
+	Description string
+}
+
+// Schema returns a JSON Schema of the config file, generated from the
+// config structs, their doc comments and the defaults.
+func Schema() map[string]any {
+	schema := typeSchema(reflect.TypeOf(Config{}), reflect.ValueOf(*DefaultConfig()))
+	props := schema["properties"].(map[string]any)
+	props[profilesKey] = map[string]any{
+		"type":                 "object",
+		"description":          "Named profiles, selected with --profile or the profile key. Each overrides the settings above.",
+		"additionalProperties": map[string]any{"$ref": "#"},
+	}
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = "https://github.com/JNZader/goreview/goreview/config.schema.json"
+	schema["title"] = "goreview configuration (" + configFileName + ")"
+	return schema
+}
+
+// Options returns the config options, with their types, defaults and
+// descriptions, sorted by key.
+func Options() []Option {
+	var options []Option
+	collectOptions(reflect.TypeOf(Config{}), reflect.ValueOf(*DefaultConfig()), "", &options)
+	sort.Slice(options, func(i, j int) bool { return options[i].Key < options[j].Key })
+	return options
+}
+
+func collectOptions(t reflect.Type, def reflect.Value, prefix string, options *[]Option) {
+	for _, f := range configFields(t) {
+		key := prefix + f.name
+		fv := fieldValue(def, f.index)
+		ft := f.typ
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft.Kind() == reflect.Struct:
+			collectOptions(ft, fv, key+".", options)
+			continue
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct:
+			collectOptions(ft.Elem(), reflect.Value{}, key+"[].", options)
+			continue
+		case ft.Kind() == reflect.Map && ft.Elem().Kind() == reflect.Struct:
+			collectOptions(ft.Elem(), reflect.Value{}, key+".<name>.", options)
+			continue
+		}
+		opt := Option{Key: key, Type: typeName(ft), Description: doc(t.Name(), f.goName)}
+		if d, ok := defaultValue(fv); ok {
+			opt.Default = fmt.Sprint(d)
+		}
+		*options = append(*options, opt)
+	}
+}
+
+// typeSchema returns the schema of a config type, with the defaults of
+// def when it is valid.
+func typeSchema(t reflect.Type, def reflect.Value) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == durationType {
+		return map[string]any{"type": "string", "pattern": durationPattern}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), reflect.Value{})}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), reflect.Value{})}
+	case reflect.Struct:
+		props := make(map[string]any)
+		for _, f := range configFields(t) {
+			fv := fieldValue(def, f.index)
+			s := typeSchema(f.typ, fv)
+			if d := doc(t.Name(), f.goName); d != "" {
+				s["description"] = d
+			} else if d := doc(derefName(f.typ), ""); d != "" {
+				s["description"] = d
+			}
+			if d, ok := defaultValue(fv); ok {
+				s["default"] = d
+			}
+			props[f.name] = s
+		}
+		return map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	}
+	// interface{} values take anything
+	return map[string]any{}
+}
+
+// configField is a field of a config struct, by its key.
+type configField struct {
+	name   string
+	goName string
+	index  int
+	typ    reflect.Type
+}
+
+// configFields returns the fields of a config struct that are read from
+// the config file, by their mapstructure keys.
+func configFields(t reflect.Type) []configField {
+	var fields []configField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		fields = append(fields, configField{name: name, goName: f.Name, index: i, typ: f.Type})
+	}
+	return fields
+}
+
+func fieldValue(v reflect.Value, index int) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v.Field(index)
+}
+
+// defaultValue returns a non-zero default of a scalar or list of scalars.
+func defaultValue(v reflect.Value) (any, bool) {
+	if !v.IsValid() || v.IsZero() {
+		return nil, false
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), true
+	}
+	if v.Kind() == reflect.String {
+		return portablePath(v.String()), true
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Pointer, reflect.Interface:
+		return nil, false
+	case reflect.Slice:
+		if k := v.Type().Elem().Kind(); v.Len() == 0 || k == reflect.Struct || k == reflect.Map || k == reflect.Slice {
+			return nil, false
+		}
+	}
+	return v.Interface(), true
+}
+
+// portablePath replaces the default cache directory at the start of a
+// path with cacheDirPlaceholder.
+func portablePath(path string) string {
+	rest, ok := strings.CutPrefix(path, defaultCacheDir())
+	if !ok || (rest != "" && rest[0] != filepath.Separator) {
+		return path
+	}
+	return cacheDirPlaceholder + filepath.ToSlash(rest)
+}
+
+func typeName(t reflect.Type) string {
+	if t == durationType {
+		return "duration"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "list of " + typeName(t.Elem())
+	case reflect.Map:
+		return "map of " + typeName(t.Elem())
+	case reflect.Pointer:
+		return typeName(t.Elem())
+	}
+	return "any"
+}
+
+func derefName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// doc returns the doc comment of a config struct field, or of the struct
+// when field is "", on one line.
+func doc(typ, field string) string {
+	docsOnce.Do(parseDocs)
+	if field != "" {
+		return docs[typ+"."+field]
+	}
+	return docs[typ]
+}
+
+func parseDocs() {
+	docs = make(map[string]string)
+	file, err := parser.ParseFile(token.NewFileSet(), "config.go", configSource, parser.ParseComments)
+	if err != nil {
+		return
+	}
+	oneLine := func(g *ast.CommentGroup) string {
+		return strings.Join(strings.Fields(g.Text()), " ")
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			if gen.Doc != nil {
+				docs[ts.Name.Name] = oneLine(gen.Doc)
+			}
+			for _, f := range st.Fields.List {
+				g := f.Doc
+				if g == nil {
+					g = f.Comment
+				}
+				if g == nil {
+					continue
+				}
+				for _, name := range f.Names {
+					docs[ts.Name.Name+"."+name.Name] = oneLine(g)
+				}
+			}
+		}
+	}
+}
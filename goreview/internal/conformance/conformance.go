@@ -0,0 +1,323 @@
+// Package conformance checks a repository against an organization template
+// policy: required files, CI workflows, Makefile targets and license headers.
+// Violations are reported as regular review issues, so they appear alongside
+// AI findings in every report format.
+package conformance
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+// Rule IDs of the conformance checks
+const (
+	RuleRequiredFile  = "conformance/required-file"
+	RuleCIWorkflow    = "conformance/ci-workflow"
+	RuleMakefile      = "conformance/makefile-target"
+	RuleLicenseHeader = "conformance/license-header"
+)
+
+// Defaults for optional policy settings
+const (
+	defaultMakefile    = "Makefile"
+	defaultHeaderLines = 20
+)
+
+// Policy is an organization template a repository must conform to.
+type Policy struct {
+	// Name identifies the policy in issue messages
+	Name string `yaml:"name"`
+
+	// Severity of violations (default: warning)
+	Severity providers.Severity `yaml:"severity"`
+
+	// RequiredFiles must exist; a glob is satisfied by any matching file
+	RequiredFiles []string `yaml:"required_files"`
+
+	// CIWorkflows are workflow files that must exist, e.g.
+	// ".github/workflows/ci.yml" or ".github/workflows/*.yml" for any
+	CIWorkflows []string `yaml:"ci_workflows"`
+
+	// Makefile lists targets the Makefile must define
+	Makefile *MakefilePolicy `yaml:"makefile"`
+
+	// LicenseHeader requires a header in matching source files
+	LicenseHeader *HeaderPolicy `yaml:"license_header"`
+}
+
+// MakefilePolicy lists the required Makefile targets.
+type MakefilePolicy struct {
+	// Path of the Makefile (default: Makefile)
+	Path    string   `yaml:"path"`
+	Targets []string `yaml:"targets"`
+}
+
+// HeaderPolicy requires a license header in source files.
+type HeaderPolicy struct {
+	// Pattern is a regexp the header must match
+	Pattern string `yaml:"pattern"`
+	// Include and Exclude select the files to check, as rule path globs
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	// Lines is how many leading lines are searched (default: 20)
+	Lines int `yaml:"lines"`
+
+	re *regexp.Regexp
+}
+
+// LoadPolicy reads and validates a policy file.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path from user config
+	if err != nil {
+		return nil, fmt.Errorf("reading policy: %w", err)
+	}
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing policy %s: %w", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+func (p *Policy) validate() error {
+	if p.Name == "" {
+		p.Name = "template"
+	}
+	switch p.Severity {
+	case "":
+		p.Severity = providers.SeverityWarning
+	case providers.SeverityInfo, providers.SeverityWarning, providers.SeverityError, providers.SeverityCritical:
+	default:
+		return fmt.Errorf("unknown severity %q", p.Severity)
+	}
+	if p.Makefile != nil && p.Makefile.Path == "" {
+		p.Makefile.Path = defaultMakefile
+	}
+	if h := p.LicenseHeader; h != nil {
+		if h.Pattern == "" || len(h.Include) == 0 {
+			return fmt.Errorf("license_header needs a pattern and include globs")
+		}
+		re, err := regexp.Compile(h.Pattern)
+		if err != nil {
+			return fmt.Errorf("license_header.pattern: %w", err)
+		}
+		h.re = re
+		if h.Lines <= 0 {
+			h.Lines = defaultHeaderLines
+		}
+	}
+	return nil
+}
+
+// Violation is a conformance issue found in a file.
+type Violation struct {
+	File  string
+	Issue providers.Issue
+}
+
+// Checker checks a repository against a policy.
+type Checker struct {
+	policy *Policy
+	root   string
+	files  []string
+}
+
+// NewChecker creates a checker for the repository at root. files are the
+// repository's files relative to root, used to resolve globs.
+func NewChecker(policy *Policy, root string, files []string) *Checker {
+	normalized := make([]string, len(files))
+	for i, f := range files {
+		normalized[i] = filepath.ToSlash(f)
+	}
+	return &Checker{policy: policy, root: root, files: normalized}
+}
+
+// Check runs all checks. headerFiles limits the license header check to
+// those files (e.g. the files under review); nil checks every file.
+func (c *Checker) Check(headerFiles []string) []Violation {
+	var violations []Violation
+	for _, pattern := range c.policy.RequiredFiles {
+		if !c.exists(pattern) {
+			violations = append(violations, c.violation(pattern, RuleRequiredFile, 0,
+				fmt.Sprintf("Required file %s is missing", pattern),
+				fmt.Sprintf("Add %s as required by the %s policy", pattern, c.policy.Name)))
+		}
+	}
+	for _, pattern := range c.policy.CIWorkflows {
+		if !c.exists(pattern) {
+			violations = append(violations, c.violation(pattern, RuleCIWorkflow, 0,
+				fmt.Sprintf("CI workflow %s is missing", pattern),
+				"Add the organization's CI workflow"))
+		}
+	}
+	violations = append(violations, c.checkMakefile()...)
+
+	if headerFiles == nil {
+		headerFiles = c.files
+	}
+	violations = append(violations, c.checkHeaders(headerFiles)...)
+
+	for i := range violations {
+		violations[i].Issue.ID = fmt.Sprintf("conformance-%d", i+1)
+	}
+	return violations
+}
+
+// exists reports whether a repository file matches the path or glob.
+func (c *Checker) exists(pattern string) bool {
+	if !strings.ContainsAny(pattern, "*?[") {
+		_, err := os.Stat(filepath.Join(c.root, pattern))
+		return err == nil
+	}
+	for _, f := range c.files {
+		if rules.MatchGlob(pattern, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) checkMakefile() []Violation {
+	mk := c.policy.Makefile
+	if mk == nil || len(mk.Targets) == 0 {
+		return nil
+	}
+	targets, err := makefileTargets(filepath.Join(c.root, mk.Path))
+	if err != nil {
+		return []Violation{c.violation(mk.Path, RuleMakefile, 0,
+			fmt.Sprintf("Makefile %s is missing", mk.Path),
+			fmt.Sprintf("Add a Makefile with targets: %s", strings.Join(mk.Targets, ", ")))}
+	}
+
+	var violations []Violation
+	for _, target := range mk.Targets {
+		if !targets[target] {
+			violations = append(violations, c.violation(mk.Path, RuleMakefile, 0,
+				fmt.Sprintf("Makefile target %q is missing", target),
+				fmt.Sprintf("Add a %q target to %s", target, mk.Path)))
+		}
+	}
+	return violations
+}
+
+var makeTargetLine = regexp.MustCompile(`^([^\s:#=][^:#=]*?)\s*::?(?:[^=]|$)`)
+
+// makefileTargets returns the targets defined in a Makefile.
+func makefileTargets(path string) (map[string]bool, error) {
+	f, err := os.Open(path) // #nosec G304 - path inside the repository
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	targets := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := makeTargetLine.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		for _, target := range strings.Fields(m[1]) {
+			targets[target] = true
+		}
+	}
+	return targets, scanner.Err()
+}
+
+func (c *Checker) checkHeaders(files []string) []Violation {
+	h := c.policy.LicenseHeader
+	if h == nil {
+		return nil
+	}
+
+	var violations []Violation
+	for _, file := range files {
+		file = filepath.ToSlash(file)
+		if !matchesAny(h.Include, file) || matchesAny(h.Exclude, file) {
+			continue
+		}
+		header, err := readHeader(filepath.Join(c.root, file), h.Lines)
+		if err != nil || h.re.MatchString(header) {
+			continue
+		}
+		violations = append(violations, c.violation(file, RuleLicenseHeader, 1,
+			"Missing license header",
+			fmt.Sprintf("Add the license header required by the %s policy", c.policy.Name)))
+	}
+	return violations
+}
+
+// readHeader returns the first n lines of a file.
+func readHeader(path string, n int) (string, error) {
+	f, err := os.Open(path) // #nosec G304 - path inside the repository
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	var sb strings.Builder
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < n && scanner.Scan(); i++ {
+		sb.WriteString(scanner.Text())
+		sb.WriteString("\n")
+	}
+	return sb.String(), scanner.Err()
+}
+
+func matchesAny(patterns []string, file string) bool {
+	for _, p := range patterns {
+		if rules.MatchGlob(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) violation(file, ruleID string, line int, message, suggestion string) Violation {
+	issue := providers.Issue{
+		Type:       providers.IssueTypeBestPractice,
+		Severity:   c.policy.Severity,
+		Message:    message,
+		Suggestion: suggestion,
+		RuleID:     ruleID,
+	}
+	if line > 0 {
+		issue.Location = &providers.Location{File: file, StartLine: line, EndLine: line}
+	}
+	return Violation{File: file, Issue: issue}
+}
+
+// Apply adds the violations to a review result, next to the AI findings for
+// the same file. Files without review results get their own entry.
+func Apply(result *review.Result, violations []Violation) {
+	for _, v := range violations {
+		idx := -1
+		for i := range result.Files {
+			if result.Files[i].File == v.File && result.Files[i].Response != nil {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			result.Files = append(result.Files, review.FileResult{
+				File:     v.File,
+				Response: &providers.ReviewResponse{Summary: "Template conformance check"},
+			})
+			idx = len(result.Files) - 1
+		}
+		result.Files[idx].Response.Issues = append(result.Files[idx].Response.Issues, v.Issue)
+		result.TotalIssues++
+	}
+}
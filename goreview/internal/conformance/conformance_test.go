@@ -0,0 +1,142 @@
+package conformance
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) []string {
+	t.Helper()
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, name)
+	}
+	return paths
+}
+
+func TestLoadPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"defaults", "required_files: [README.md]\n", ""},
+		{"bad severity", "severity: fatal\n", "unknown severity"},
+		{"header without include", "license_header:\n  pattern: Copyright\n", "license_header"},
+		{"bad header pattern", "license_header:\n  pattern: \"(\"\n  include: [\"*.go\"]\n", "license_header"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0600); err != nil {
+				t.Fatal(err)
+			}
+			p, err := LoadPolicy(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadPolicy() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadPolicy() error = %v", err)
+			}
+			if p.Severity != providers.SeverityWarning || p.Name != "template" {
+				t.Errorf("defaults not applied: %+v", p)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	root := t.TempDir()
+	files := writeFiles(t, root, map[string]string{
+		"README.md":                "# repo",
+		".github/workflows/ci.yml": "name: ci",
+		"Makefile":                 "VERSION := 1\n.PHONY: build test\nbuild test: deps\n\tgo build\nlint::\n",
+		"main.go":                  "// Copyright 2024 Acme Inc.\npackage main\n",
+		"util.go":                  "package main\n",
+		"vendor/lib.go":            "package lib\n",
+	})
+
+	policy := &Policy{
+		RequiredFiles: []string{"README.md", "LICENSE", "docs/*.md"},
+		CIWorkflows:   []string{".github/workflows/*.yml"},
+		Makefile:      &MakefilePolicy{Targets: []string{"build", "test", "lint", "release", "VERSION"}},
+		LicenseHeader: &HeaderPolicy{
+			Pattern: `Copyright \d{4} Acme`,
+			Include: []string{"*.go"},
+			Exclude: []string{"vendor/**"},
+		},
+	}
+	if err := policy.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	violations := NewChecker(policy, root, files).Check(nil)
+
+	got := make(map[string]int)
+	for _, v := range violations {
+		got[v.Issue.RuleID+" "+v.File]++
+		if v.Issue.Severity != providers.SeverityWarning {
+			t.Errorf("severity = %s, want warning", v.Issue.Severity)
+		}
+	}
+	want := map[string]int{
+		RuleRequiredFile + " LICENSE":   1,
+		RuleRequiredFile + " docs/*.md": 1,
+		RuleMakefile + " Makefile":      2, // release, VERSION
+		RuleLicenseHeader + " util.go":  1,
+	}
+	if len(got) != len(want) {
+		t.Errorf("violations = %v, want %v", got, want)
+	}
+	for k, n := range want {
+		if got[k] != n {
+			t.Errorf("%s: %d violations, want %d (all: %v)", k, got[k], n, got)
+		}
+	}
+
+	// Headers are only checked in the given files
+	for _, v := range NewChecker(policy, root, files).Check([]string{"main.go"}) {
+		if v.Issue.RuleID == RuleLicenseHeader {
+			t.Errorf("unexpected header violation in %s", v.File)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	result := &review.Result{
+		TotalIssues: 1,
+		Files: []review.FileResult{{
+			File:     "main.go",
+			Response: &providers.ReviewResponse{Issues: []providers.Issue{{ID: "ai-1"}}},
+		}},
+	}
+	Apply(result, []Violation{
+		{File: "main.go", Issue: providers.Issue{ID: "conformance-1"}},
+		{File: "LICENSE", Issue: providers.Issue{ID: "conformance-2"}},
+	})
+
+	if result.TotalIssues != 3 {
+		t.Errorf("TotalIssues = %d, want 3", result.TotalIssues)
+	}
+	if len(result.Files) != 2 || len(result.Files[0].Response.Issues) != 2 {
+		t.Fatalf("violations not merged: %+v", result.Files)
+	}
+	if result.Files[1].File != "LICENSE" {
+		t.Errorf("new file entry = %s, want LICENSE", result.Files[1].File)
+	}
+}
@@ -0,0 +1,140 @@
+// Package debt finds technical debt markers such as TODO, FIXME and HACK in
+// comments, with the owner and ticket they reference, and checks them
+// against the configured ownership policy.
+package debt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Ownership policies: what a debt comment must reference
+const (
+	RequireNone          = "none"
+	RequireOwner         = "owner"
+	RequireTicket        = "ticket"
+	RequireOwnerOrTicket = "owner_or_ticket"
+)
+
+// Policies lists the accepted ownership policies.
+var Policies = []string{RequireNone, RequireOwner, RequireTicket, RequireOwnerOrTicket}
+
+// DefaultTicketPattern matches issue tracker references like PROJ-123 or #42
+const DefaultTicketPattern = `[A-Z][A-Z0-9]+-\d+|#\d+`
+
+// Item is a debt comment.
+type Item struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Marker string `json:"marker"`
+	// Text is the comment after the marker and its reference
+	Text   string `json:"text"`
+	Owner  string `json:"owner,omitempty"`
+	Ticket string `json:"ticket,omitempty"`
+}
+
+// Scanner finds debt markers and checks their references.
+type Scanner struct {
+	marker  *regexp.Regexp
+	ticket  *regexp.Regexp
+	require string
+}
+
+// ownerPattern matches a @mention in the comment text
+var ownerPattern = regexp.MustCompile(`(?:^|\s)@([\w.-]+)`)
+
+// NewScanner creates a scanner for the given markers, ownership policy and
+// ticket pattern. Empty values fall back to TODO/FIXME/HACK, owner_or_ticket
+// and DefaultTicketPattern.
+func NewScanner(markers []string, require, ticketPattern string) (*Scanner, error) {
+	if len(markers) == 0 {
+		markers = []string{"TODO", "FIXME", "HACK"}
+	}
+	quoted := make([]string, len(markers))
+	for i, m := range markers {
+		quoted[i] = regexp.QuoteMeta(m)
+	}
+	if require == "" {
+		require = RequireOwnerOrTicket
+	}
+	if !validPolicy(require) {
+		return nil, fmt.Errorf("invalid debt policy %q, must be one of: %s", require, strings.Join(Policies, ", "))
+	}
+	if ticketPattern == "" {
+		ticketPattern = DefaultTicketPattern
+	}
+	ticket, err := regexp.Compile(ticketPattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket pattern: %w", err)
+	}
+
+	return &Scanner{
+		// TODO(alice, PROJ-1): text / FIXME: text / HACK text
+		marker:  regexp.MustCompile(`\b(` + strings.Join(quoted, "|") + `)\b(?:\(([^)]*)\))?:?\s*(.*)$`),
+		ticket:  ticket,
+		require: require,
+	}, nil
+}
+
+func validPolicy(require string) bool {
+	for _, p := range Policies {
+		if p == require {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan finds a debt marker in the text of a comment.
+func (s *Scanner) Scan(comment string) (Item, bool) {
+	m := s.marker.FindStringSubmatch(comment)
+	if m == nil {
+		return Item{}, false
+	}
+	item := Item{Marker: m[1], Text: strings.TrimSpace(m[3])}
+
+	// The parenthesized reference holds owners and tickets
+	for _, ref := range strings.FieldsFunc(m[2], func(r rune) bool { return r == ',' || r == ' ' }) {
+		switch {
+		case item.Ticket == "" && s.isTicket(ref):
+			item.Ticket = ref
+		case item.Owner == "" && !s.isTicket(ref):
+			item.Owner = strings.TrimPrefix(ref, "@")
+		}
+	}
+	if item.Owner == "" {
+		if om := ownerPattern.FindStringSubmatch(item.Text); om != nil {
+			item.Owner = om[1]
+		}
+	}
+	if item.Ticket == "" {
+		item.Ticket = s.ticket.FindString(item.Text)
+	}
+	return item, true
+}
+
+func (s *Scanner) isTicket(ref string) bool {
+	loc := s.ticket.FindStringIndex(ref)
+	return loc != nil && loc[0] == 0 && loc[1] == len(ref)
+}
+
+// Missing describes the reference the policy requires but the item lacks,
+// such as "owner or ticket", or returns "" when the item complies.
+func (s *Scanner) Missing(item Item) string {
+	switch s.require {
+	case RequireOwner:
+		if item.Owner == "" {
+			return "owner"
+		}
+	case RequireTicket:
+		if item.Ticket == "" {
+			return "ticket"
+		}
+	case RequireOwnerOrTicket:
+		if item.Owner == "" && item.Ticket == "" {
+			return "owner or ticket"
+		}
+	}
+	return ""
+}
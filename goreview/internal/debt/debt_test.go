@@ -0,0 +1,67 @@
+package debt
+
+import "testing"
+
+func TestScan(t *testing.T) {
+	s, err := NewScanner(nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		comment string
+		want    Item
+		found   bool
+	}{
+		{" TODO: handle retries", Item{Marker: "TODO", Text: "handle retries"}, true},
+		{" FIXME(alice): leaks", Item{Marker: "FIXME", Text: "leaks", Owner: "alice"}, true},
+		{" HACK(@bob, PROJ-12) skip auth", Item{Marker: "HACK", Text: "skip auth", Owner: "bob", Ticket: "PROJ-12"}, true},
+		{" TODO(#42): flaky", Item{Marker: "TODO", Text: "flaky", Ticket: "#42"}, true},
+		{" TODO remove after JIRA-7 ships, ask @carol", Item{Marker: "TODO", Text: "remove after JIRA-7 ships, ask @carol", Owner: "carol", Ticket: "JIRA-7"}, true},
+		{" todos are tracked elsewhere", Item{}, false},
+		{" MYTODO is not a marker", Item{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			got, found := s.Scan(tt.comment)
+			if found != tt.found || got != tt.want {
+				t.Errorf("Scan() = %+v, %v; want %+v, %v", got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestMissing(t *testing.T) {
+	owned := Item{Marker: "TODO", Owner: "alice"}
+	ticketed := Item{Marker: "TODO", Ticket: "PROJ-1"}
+	bare := Item{Marker: "TODO"}
+
+	tests := []struct {
+		require string
+		item    Item
+		want    string
+	}{
+		{RequireOwnerOrTicket, owned, ""},
+		{RequireOwnerOrTicket, ticketed, ""},
+		{RequireOwnerOrTicket, bare, "owner or ticket"},
+		{RequireOwner, ticketed, "owner"},
+		{RequireTicket, owned, "ticket"},
+		{RequireNone, bare, ""},
+	}
+	for _, tt := range tests {
+		s, err := NewScanner(nil, tt.require, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Missing(tt.item); got != tt.want {
+			t.Errorf("Missing(%+v) with %s = %q, want %q", tt.item, tt.require, got, tt.want)
+		}
+	}
+
+	if _, err := NewScanner(nil, "sometimes", ""); err == nil {
+		t.Error("NewScanner() accepted an invalid policy")
+	}
+	if _, err := NewScanner(nil, "", "("); err == nil {
+		t.Error("NewScanner() accepted an invalid ticket pattern")
+	}
+}
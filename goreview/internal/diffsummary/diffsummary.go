@@ -0,0 +1,299 @@
+// Package diffsummary summarizes how a diff changes the structure of a
+// file: the functions added, removed and modified, the signatures changed
+// and the dependencies added and removed. Summaries are computed locally
+// from the code on both sides of the diff, to ground the model's review
+// and the documentation generated for a change.
+package diffsummary
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// Summary is the structured change of a file.
+type Summary struct {
+	// Added and Removed are the signatures of the functions added and
+	// removed
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Modified are the names of the functions whose body changed
+	Modified []string `json:"modified,omitempty"`
+	// Signatures are the functions whose signature changed
+	Signatures []SignatureChange `json:"signatures,omitempty"`
+	// Dependencies are the imports added and removed
+	DependenciesAdded   []string `json:"dependencies_added,omitempty"`
+	DependenciesRemoved []string `json:"dependencies_removed,omitempty"`
+}
+
+// SignatureChange is a function whose signature changed.
+type SignatureChange struct {
+	Name string `json:"name"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// maxListed caps the functions listed per kind of change
+const maxListed = 15
+
+// Summarize returns the structured change of a file's diff. content is the
+// file after the change, "" when unavailable; without it, or when it
+// doesn't match the diff, both sides are read from the hunks alone and
+// functions whose declaration lies outside them go unnoticed.
+func Summarize(file git.FileDiff, content string) *Summary {
+	if file.IsBinary || len(file.Hunks) == 0 {
+		return &Summary{}
+	}
+	oldLines, newLines := sides(file, content)
+	oldCtx, _ := ast.NewParser(file.Language).Parse(strings.Join(oldLines, "\n"), file.Path)
+	newCtx, _ := ast.NewParser(file.Language).Parse(strings.Join(newLines, "\n"), file.Path)
+
+	added, deleted := map[int]bool{}, map[int]bool{}
+	var addedText, deletedText []string
+	for _, hunk := range file.Hunks {
+		for _, l := range hunk.Lines {
+			switch l.Type {
+			case git.LineAddition:
+				added[l.NewNumber] = true
+				addedText = append(addedText, l.Content)
+			case git.LineDeletion:
+				deleted[l.OldNumber] = true
+				deletedText = append(deletedText, l.Content)
+			}
+		}
+	}
+
+	s := &Summary{}
+	oldFuncs := functions(oldCtx, oldLines)
+	newFuncs := functions(newCtx, newLines)
+	for _, fn := range newFuncs.list {
+		old, existed := oldFuncs.byKey[fn.key]
+		switch {
+		case !existed && added[fn.StartLine]:
+			s.Added = append(s.Added, fn.signature)
+		case !existed:
+		case old.signature != fn.signature:
+			s.Signatures = append(s.Signatures, SignatureChange{Name: fn.name, Old: old.signature, New: fn.signature})
+		case touches(added, fn.StartLine, fn.EndLine) || touches(deleted, old.StartLine, old.EndLine):
+			s.Modified = append(s.Modified, fn.name)
+		}
+	}
+	for _, fn := range oldFuncs.list {
+		if _, kept := newFuncs.byKey[fn.key]; !kept && deleted[fn.StartLine] {
+			s.Removed = append(s.Removed, fn.signature)
+		}
+	}
+
+	s.DependenciesAdded = importChanges(newCtx, oldCtx, addedText, deletedText)
+	s.DependenciesRemoved = importChanges(oldCtx, newCtx, deletedText, addedText)
+	return s
+}
+
+// sides returns the lines of the file before and after the change. With
+// the content after the change, the file before is the content with the
+// diff reverted; otherwise both sides only hold the hunks' lines, at their
+// line numbers.
+func sides(file git.FileDiff, content string) (oldLines, newLines []string) {
+	if content != "" {
+		newLines = strings.Split(content, "\n")
+		if oldLines, ok := revert(file, newLines); ok {
+			return oldLines, newLines
+		}
+	}
+	oldLines, newLines = nil, nil
+	put := func(lines []string, n int, text string) []string {
+		for len(lines) < n {
+			lines = append(lines, "")
+		}
+		lines[n-1] = text
+		return lines
+	}
+	for _, hunk := range file.Hunks {
+		for _, l := range hunk.Lines {
+			if l.Type != git.LineAddition && l.OldNumber > 0 {
+				oldLines = put(oldLines, l.OldNumber, l.Content)
+			}
+			if l.Type != git.LineDeletion && l.NewNumber > 0 {
+				newLines = put(newLines, l.NewNumber, l.Content)
+			}
+		}
+	}
+	return oldLines, newLines
+}
+
+// revert returns the file before the change from the file after it, or
+// false when the file doesn't match the new side of the diff.
+func revert(file git.FileDiff, newLines []string) ([]string, bool) {
+	var oldLines []string
+	next := 1 // next line of newLines to copy
+	for _, hunk := range file.Hunks {
+		for _, l := range hunk.Lines {
+			if l.Type == git.LineDeletion {
+				oldLines = append(oldLines, l.Content)
+				continue
+			}
+			if l.NewNumber < next || l.NewNumber > len(newLines) || newLines[l.NewNumber-1] != l.Content {
+				return nil, false
+			}
+			oldLines = append(oldLines, newLines[next-1:l.NewNumber-1]...)
+			if l.Type == git.LineContext {
+				oldLines = append(oldLines, l.Content)
+			}
+			next = l.NewNumber + 1
+		}
+	}
+	if next <= len(newLines) {
+		oldLines = append(oldLines, newLines[next-1:]...)
+	}
+	return oldLines, true
+}
+
+// function is a function of one side of the diff.
+type function struct {
+	ast.Function
+	key       string // receiver type and name, like "Engine.Run"
+	name      string // name to show
+	signature string
+}
+
+type functionSet struct {
+	list  []function
+	byKey map[string]function
+}
+
+// goReceiver matches the receiver type of a Go method
+var goReceiver = regexp.MustCompile(`^func\s*\(\s*(?:\w+\s+)?\*?\s*(\w+)`)
+
+// functions returns the functions of a parsed side, by key. Overloads
+// keep the first declaration.
+func functions(ctx *ast.Context, lines []string) functionSet {
+	set := functionSet{byKey: map[string]function{}}
+	if ctx == nil {
+		return set
+	}
+	for _, fn := range ctx.Functions {
+		f := function{Function: fn, key: fn.Name, name: fn.Name}
+		if fn.StartLine >= 1 && fn.StartLine <= len(lines) {
+			f.signature = declaration(lines, fn.StartLine-1)
+			if m := goReceiver.FindStringSubmatch(lines[fn.StartLine-1]); m != nil {
+				f.key = m[1] + "." + fn.Name
+				f.name = f.key
+			}
+		}
+		if f.signature == "" {
+			f.signature = fn.Name
+		}
+		if _, dup := set.byKey[f.key]; dup {
+			continue
+		}
+		set.byKey[f.key] = f
+		set.list = append(set.list, f)
+	}
+	return set
+}
+
+// declaration returns the declaration of the function starting at line i,
+// up to its body, on one line.
+func declaration(lines []string, i int) string {
+	var parts []string
+	for j := i; j < len(lines) && j < i+6; j++ {
+		line := strings.TrimSpace(lines[j])
+		if k := strings.Index(line, " {"); k >= 0 {
+			parts = append(parts, line[:k])
+			break
+		}
+		if strings.HasSuffix(line, "{") || strings.HasSuffix(line, ":") && !strings.HasSuffix(line, "::") {
+			parts = append(parts, line[:len(line)-1])
+			break
+		}
+		parts = append(parts, line)
+		if strings.HasSuffix(line, ";") || strings.HasSuffix(line, "=>") || line == "" {
+			break
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+// touches reports whether any of the lines is within [start, end].
+func touches(lines map[int]bool, start, end int) bool {
+	for n := range lines {
+		if n >= start && n <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// importChanges returns the imports of a side that the other side lacks,
+// mentioned by its changed lines and not by the other side's.
+func importChanges(side, other *ast.Context, changed, otherChanged []string) []string {
+	if side == nil {
+		return nil
+	}
+	had := map[string]bool{}
+	if other != nil {
+		for _, imp := range other.Imports {
+			had[imp.Path] = true
+		}
+	}
+	mentions := func(lines []string, path string) bool {
+		for _, l := range lines {
+			if strings.Contains(l, path) {
+				return true
+			}
+		}
+		return false
+	}
+	var paths []string
+	seen := map[string]bool{}
+	for _, imp := range side.Imports {
+		if had[imp.Path] || seen[imp.Path] || !mentions(changed, imp.Path) || mentions(otherChanged, imp.Path) {
+			continue
+		}
+		seen[imp.Path] = true
+		paths = append(paths, imp.Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Empty reports whether the summary found no structural change.
+func (s *Summary) Empty() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0 && len(s.Modified) == 0 &&
+		len(s.Signatures) == 0 && len(s.DependenciesAdded) == 0 && len(s.DependenciesRemoved) == 0
+}
+
+// String returns the summary as lines of text, one per kind of change, ""
+// when it is empty.
+func (s *Summary) String() string {
+	var sb strings.Builder
+	list := func(label string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		shown := items
+		if len(shown) > maxListed {
+			shown = shown[:maxListed]
+		}
+		fmt.Fprintf(&sb, "%s: %s", label, strings.Join(shown, "; "))
+		if len(items) > len(shown) {
+			fmt.Fprintf(&sb, " (and %d more)", len(items)-len(shown))
+		}
+		sb.WriteByte('\n')
+	}
+	list("Functions added", s.Added)
+	list("Functions removed", s.Removed)
+	list("Functions modified", s.Modified)
+	signatures := make([]string, len(s.Signatures))
+	for i, c := range s.Signatures {
+		signatures[i] = c.Old + " -> " + c.New
+	}
+	list("Signatures changed", signatures)
+	list("Dependencies added", s.DependenciesAdded)
+	list("Dependencies removed", s.DependenciesRemoved)
+	return sb.String()
+}
@@ -0,0 +1,129 @@
+package diffsummary
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// fileDiff parses a diff of a modified Go file.
+func fileDiff(t *testing.T, body string) git.FileDiff {
+	t.Helper()
+	diff, err := git.ParseDiff("diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n" + body)
+	if err != nil || len(diff.Files) != 1 {
+		t.Fatalf("ParseDiff() = %v, %v", diff, err)
+	}
+	file := diff.Files[0]
+	file.Language = "go"
+	return file
+}
+
+const after = `package a
+
+import (
+	"fmt"
+	"strings"
+)
+
+func Keep() int {
+	return 2
+}
+
+func (e *Engine) Run(ctx context.Context, n int) error {
+	return nil
+}
+
+func New() string {
+	return strings.ToUpper(fmt.Sprint(1))
+}
+`
+
+// diffToAfter changes Keep's body, Run's signature, replaces Old with New
+// and imports strings instead of os.
+const diffToAfter = `@@ -2,19 +2,19 @@
+ 
+ import (
+ 	"fmt"
+-	"os"
++	"strings"
+ )
+ 
+ func Keep() int {
+-	return 1
++	return 2
+ }
+ 
+-func (e *Engine) Run(n int) error {
++func (e *Engine) Run(ctx context.Context, n int) error {
+ 	return nil
+ }
+ 
+-func Old() {
+-	os.Exit(1)
++func New() string {
++	return strings.ToUpper(fmt.Sprint(1))
+ }
+`
+
+func TestSummarize(t *testing.T) {
+	want := &Summary{
+		Added:    []string{"func New() string"},
+		Removed:  []string{"func Old()"},
+		Modified: []string{"Keep"},
+		Signatures: []SignatureChange{{
+			Name: "Engine.Run",
+			Old:  "func (e *Engine) Run(n int) error",
+			New:  "func (e *Engine) Run(ctx context.Context, n int) error",
+		}},
+		DependenciesAdded:   []string{"strings"},
+		DependenciesRemoved: []string{"os"},
+	}
+	file := fileDiff(t, diffToAfter)
+	for _, content := range []string{after, ""} {
+		got := Summarize(file, content)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Summarize() with content %v = %+v, want %+v", content != "", got, want)
+		}
+	}
+}
+
+func TestSummarizeFunctionOutsideHunks(t *testing.T) {
+	content := "package a\n\nfunc Long() {\n\ta()\n\tb()\n\tc()\n}\n"
+	file := fileDiff(t, "@@ -5,3 +5,3 @@\n \tb()\n-\tx()\n+\tc()\n }\n")
+	if got := Summarize(file, content); !reflect.DeepEqual(got.Modified, []string{"Long"}) {
+		t.Errorf("Summarize() with content modified = %v, want [Long]", got.Modified)
+	}
+	if got := Summarize(file, ""); !got.Empty() {
+		t.Errorf("Summarize() without content = %+v, want empty", got)
+	}
+	// Content that doesn't match the diff is ignored
+	if got := Summarize(file, "package b\n"); !got.Empty() {
+		t.Errorf("Summarize() with stale content = %+v, want empty", got)
+	}
+}
+
+func TestSummaryString(t *testing.T) {
+	if s := (&Summary{}).String(); s != "" {
+		t.Errorf("String() of an empty summary = %q", s)
+	}
+	s := Summarize(fileDiff(t, diffToAfter), after).String()
+	for _, want := range []string{
+		"Functions added: func New() string\n",
+		"Functions removed: func Old()\n",
+		"Functions modified: Keep\n",
+		"Signatures changed: func (e *Engine) Run(n int) error -> func (e *Engine) Run(ctx context.Context, n int) error\n",
+		"Dependencies added: strings\n",
+		"Dependencies removed: os\n",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("String() = %q, want it to contain %q", s, want)
+		}
+	}
+
+	many := &Summary{Modified: make([]string, maxListed+2)}
+	if s := many.String(); !strings.Contains(s, "(and 2 more)") {
+		t.Errorf("String() of a long list = %q", s)
+	}
+}
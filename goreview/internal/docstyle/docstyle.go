@@ -0,0 +1,533 @@
+// Package docstyle checks documentation conventions that don't need
+// judgement: Go doc comments that start with the name they document,
+// Python docstrings in the project's style (Google or NumPy) that document
+// the parameters, and JSDoc @param tags that match the signature. The docs
+// review mode runs it before asking the model about the rest.
+package docstyle
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+
+	srcast "github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// Rule IDs of the documentation checks
+const (
+	RuleGoDoc       = "docs/go-doc"
+	RulePyDocstring = "docs/python-docstring"
+	RulePyParams    = "docs/python-params"
+	RuleJSDocParams = "docs/jsdoc-params"
+)
+
+// Python docstring styles
+const (
+	StyleGoogle = "google"
+	StyleNumPy  = "numpy"
+)
+
+// Styles lists the supported Python docstring styles.
+var Styles = []string{StyleGoogle, StyleNumPy}
+
+// Finding is a documentation problem at a line of the file.
+type Finding struct {
+	Rule       string
+	Line       int
+	Severity   providers.Severity
+	Message    string
+	Suggestion string
+}
+
+// Check returns the documentation findings of a file in the given
+// language, in source order. Languages other than Go, Python, JavaScript
+// and TypeScript have none; pythonStyle is one of Styles.
+func Check(filename, language string, src []byte, pythonStyle string) ([]Finding, error) {
+	switch language {
+	case "go":
+		return checkGo(filename, src)
+	case "python":
+		return checkPython(string(src), pythonStyle), nil
+	case "javascript", "typescript":
+		return checkJSDoc(string(src), language)
+	}
+	return nil, nil
+}
+
+// checkGo checks that exported declarations have a doc comment starting
+// with their name. Commands (package main) export nothing.
+func checkGo(filename string, src []byte) ([]Finding, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	if file.Name.Name == "main" {
+		return nil, nil
+	}
+
+	var findings []Finding
+	check := func(kind string, name *ast.Ident, doc *ast.CommentGroup, articles bool) {
+		if !name.IsExported() {
+			return
+		}
+		line := fset.Position(name.Pos()).Line
+		if doc == nil {
+			findings = append(findings, Finding{
+				Rule:       RuleGoDoc,
+				Line:       line,
+				Severity:   providers.SeverityWarning,
+				Message:    fmt.Sprintf("exported %s %s has no doc comment", kind, name.Name),
+				Suggestion: fmt.Sprintf("Add a comment starting with %q that says what it does.", name.Name+" "),
+			})
+			return
+		}
+		if !startsWithName(doc.Text(), name.Name, articles) {
+			findings = append(findings, Finding{
+				Rule:       RuleGoDoc,
+				Line:       line,
+				Severity:   providers.SeverityWarning,
+				Message:    fmt.Sprintf("doc comment of %s %s should start with its name", kind, name.Name),
+				Suggestion: fmt.Sprintf("Start the comment with %q, so it reads well in go doc and pkg.go.dev.", name.Name+" "),
+			})
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			kind := "function"
+			if d.Recv != nil {
+				if !exportedReceiver(d.Recv) {
+					continue
+				}
+				kind = "method"
+			}
+			check(kind, d.Name, d.Doc, false)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					doc := s.Doc
+					if doc == nil && !d.Lparen.IsValid() {
+						doc = d.Doc
+					}
+					check("type", s.Name, doc, true)
+				case *ast.ValueSpec:
+					kind := "var"
+					if d.Tok == token.CONST {
+						kind = "const"
+					}
+					doc := s.Doc
+					if doc == nil {
+						if d.Lparen.IsValid() && d.Doc != nil {
+							continue // Documented by the group's comment
+						}
+						doc = d.Doc
+					}
+					for _, name := range s.Names {
+						check(kind, name, doc, false)
+					}
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// startsWithName reports whether a doc comment starts with name; type
+// comments may put an article first, as in "A Reader reads".
+func startsWithName(text, name string, articles bool) bool {
+	words := strings.Fields(text)
+	if len(words) > 1 && articles && slices.Contains([]string{"A", "An", "The"}, words[0]) {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return false
+	}
+	return strings.TrimSuffix(strings.TrimRight(words[0], ".,:;"), "'s") == name
+}
+
+// exportedReceiver reports whether a method's receiver type is exported.
+func exportedReceiver(recv *ast.FieldList) bool {
+	if len(recv.List) == 0 {
+		return false
+	}
+	typ := recv.List[0].Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.IndexListExpr:
+			typ = t.X
+		case *ast.Ident:
+			return t.IsExported()
+		default:
+			return false
+		}
+	}
+}
+
+var (
+	pyDef = regexp.MustCompile(`^(\s*)(?:async\s+)?def\s+(\w+)\s*\(`)
+	// pyGoogleSection is the parameters header of Google docstrings
+	pyGoogleSection = regexp.MustCompile(`^(\s*)(?:Args|Arguments):\s*$`)
+	pyGoogleEntry   = regexp.MustCompile(`^\*{0,2}(\w+)\s*(?:\([^)]*\))?\s*:`)
+	// pyNumPySection is the parameters header of NumPy docstrings,
+	// underlined with dashes on the next line
+	pyNumPySection = regexp.MustCompile(`^(\s*)Parameters\s*$`)
+	pyNumPyEntry   = regexp.MustCompile(`^\*{0,2}(\w+)\s*(?::.*)?$`)
+	pyUnderline    = regexp.MustCompile(`^\s*-{3,}\s*$`)
+)
+
+// pyFunction is a Python function with its docstring.
+type pyFunction struct {
+	name   string
+	line   int
+	params []string
+	// docstring holds the lines of the docstring, nil without one
+	docstring []string
+}
+
+// checkPython checks that public functions have a docstring and that it
+// documents the parameters in the configured style.
+func checkPython(src, style string) []Finding {
+	var findings []Finding
+	for _, fn := range pythonFunctions(strings.Split(src, "\n")) {
+		if strings.HasPrefix(fn.name, "_") {
+			continue
+		}
+		if fn.docstring == nil {
+			findings = append(findings, Finding{
+				Rule:       RulePyDocstring,
+				Line:       fn.line,
+				Severity:   providers.SeverityWarning,
+				Message:    fmt.Sprintf("public function %s has no docstring", fn.name),
+				Suggestion: fmt.Sprintf("Add a docstring in %s style that says what it does.", styleName(style)),
+			})
+			continue
+		}
+		if len(fn.params) == 0 {
+			continue
+		}
+
+		documented, found := docstringParams(fn.docstring, style)
+		if !found {
+			other := StyleNumPy
+			if style == StyleNumPy {
+				other = StyleGoogle
+			}
+			message := fmt.Sprintf("docstring of %s doesn't document its parameters (%s)", fn.name, strings.Join(fn.params, ", "))
+			if _, ok := docstringParams(fn.docstring, other); ok {
+				message = fmt.Sprintf("docstring of %s uses %s style, the project uses %s", fn.name, styleName(other), styleName(style))
+			}
+			findings = append(findings, Finding{
+				Rule:       RulePyDocstring,
+				Line:       fn.line,
+				Severity:   providers.SeverityWarning,
+				Message:    message,
+				Suggestion: styleExample(style),
+			})
+			continue
+		}
+		if f, ok := compareParams(fn.name, fn.line, fn.params, documented, RulePyParams, 0); ok {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// pythonFunctions finds the functions and methods of a Python file.
+func pythonFunctions(lines []string) []pyFunction {
+	var functions []pyFunction
+	for i := 0; i < len(lines); i++ {
+		m := pyDef.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		fn := pyFunction{name: m[2], line: i + 1}
+
+		// The signature ends at the closing parenthesis, maybe lines below
+		depth, end := 0, -1
+		var params strings.Builder
+		for j := i; j < len(lines) && end < 0; j++ {
+			start := 0
+			if j == i {
+				start = strings.Index(lines[j], "(")
+			}
+			for _, r := range lines[j][start:] {
+				switch r {
+				case '(', '[', '{':
+					depth++
+				case ')', ']', '}':
+					depth--
+				}
+				if depth == 0 {
+					end = j
+					break
+				}
+				params.WriteRune(r)
+			}
+			params.WriteByte(' ')
+		}
+		if end < 0 {
+			continue
+		}
+		fn.params = pythonParams(strings.TrimPrefix(strings.TrimSpace(params.String()), "("))
+		fn.docstring = pythonDocstring(lines, end+1)
+		functions = append(functions, fn)
+		i = end
+	}
+	return functions
+}
+
+// pythonParams returns the names of the parameters, without self, cls and
+// the bare * and / separators.
+func pythonParams(list string) []string {
+	var names []string
+	depth, start := 0, 0
+	parts := []string{}
+	for i, r := range list {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, list[start:])
+	for _, part := range parts {
+		part = strings.SplitN(strings.SplitN(part, "=", 2)[0], ":", 2)[0]
+		name := strings.TrimLeft(strings.TrimSpace(part), "*")
+		if name == "" || name == "/" || name == "self" || name == "cls" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// pythonDocstring returns the lines of the docstring opening the body that
+// starts after line index sig, or nil when the body doesn't start with one.
+func pythonDocstring(lines []string, sig int) []string {
+	i := sig
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	if i >= len(lines) {
+		return nil
+	}
+	first := strings.TrimLeft(strings.TrimSpace(lines[i]), "rRuU")
+	var quote string
+	switch {
+	case strings.HasPrefix(first, `"""`):
+		quote = `"""`
+	case strings.HasPrefix(first, "'''"):
+		quote = "'''"
+	default:
+		return nil
+	}
+	rest := strings.TrimPrefix(first, quote)
+	if strings.Contains(rest, quote) {
+		return []string{strings.SplitN(rest, quote, 2)[0]}
+	}
+	doc := []string{rest}
+	for j := i + 1; j < len(lines); j++ {
+		if before, _, found := strings.Cut(lines[j], quote); found {
+			return append(doc, before)
+		}
+		doc = append(doc, lines[j])
+	}
+	return doc
+}
+
+// docstringParams returns the parameters documented in a docstring of the
+// given style, and whether it has a parameters section.
+func docstringParams(doc []string, style string) ([]string, bool) {
+	header, entry := pyGoogleSection, pyGoogleEntry
+	if style == StyleNumPy {
+		header, entry = pyNumPySection, pyNumPyEntry
+	}
+	for i, line := range doc {
+		m := header.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		body := i + 1
+		if style == StyleNumPy {
+			if body >= len(doc) || !pyUnderline.MatchString(doc[body]) {
+				continue
+			}
+			body++
+		}
+		return sectionEntries(doc[body:], len(m[1]), style, entry), true
+	}
+	return nil, false
+}
+
+// sectionEntries returns the names documented in a parameters section: in
+// Google style the lines indented one level below the header, in NumPy
+// style the lines at the header's indentation, until the next section.
+func sectionEntries(lines []string, headerIndent int, style string, entry *regexp.Regexp) []string {
+	var names []string
+	entryIndent := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if style == StyleGoogle {
+			if indent <= headerIndent {
+				break // Next section
+			}
+			if entryIndent < 0 {
+				entryIndent = indent
+			}
+			if indent != entryIndent {
+				continue // Description
+			}
+		} else {
+			if indent < headerIndent {
+				break
+			}
+			if indent > headerIndent {
+				continue // Description
+			}
+			if i+1 < len(lines) && pyUnderline.MatchString(lines[i+1]) {
+				break // Next section
+			}
+		}
+		if m := entry.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+func styleName(style string) string {
+	if style == StyleNumPy {
+		return "NumPy"
+	}
+	return "Google"
+}
+
+func styleExample(style string) string {
+	if style == StyleNumPy {
+		return "Document each parameter under a \"Parameters\" header underlined with dashes, as \"name : type\" followed by its description."
+	}
+	return "Document each parameter in an \"Args:\" section, as \"name (type): description\"."
+}
+
+var jsDocParam = regexp.MustCompile(`@(?:param|arg|argument)\s+(?:\{[^}]*\}\s*)?\[?([\w$.]+)`)
+
+// checkJSDoc checks that the @param tags of the JSDoc comment of each
+// function name its parameters in order. Functions without JSDoc are left
+// to the model.
+func checkJSDoc(src, language string) ([]Finding, error) {
+	ctx, err := srcast.NewParser(language).Parse(src, "")
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(src, "\n")
+	var findings []Finding
+	for _, fn := range ctx.Functions {
+		// Parameters is nil when the parameter list couldn't be parsed
+		doc, ok := jsDocBefore(lines, fn.StartLine-1)
+		if !ok || fn.Parameters == nil {
+			continue
+		}
+		tags := jsDocParam.FindAllStringSubmatch(doc, -1)
+		if len(tags) == 0 {
+			continue // A description only
+		}
+		var documented []string
+		for _, m := range tags {
+			if !strings.Contains(m[1], ".") { // Properties of a parameter
+				documented = append(documented, m[1])
+			}
+		}
+		var params []string
+		destructured := 0
+		for _, p := range fn.Parameters {
+			if p.Name == "" {
+				destructured++
+				continue
+			}
+			params = append(params, p.Name)
+		}
+		if f, ok := compareParams(fn.Name, fn.StartLine, params, documented, RuleJSDocParams, destructured); ok {
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// jsDocBefore returns the /** */ comment ending right above line index i.
+func jsDocBefore(lines []string, i int) (string, bool) {
+	end := i - 1
+	if end < 0 || !strings.HasSuffix(strings.TrimSpace(lines[end]), "*/") {
+		return "", false
+	}
+	for start := end; start >= 0; start-- {
+		trimmed := strings.TrimSpace(lines[start])
+		if strings.HasPrefix(trimmed, "/**") {
+			return strings.Join(lines[start:end+1], "\n"), true
+		}
+		if strings.HasPrefix(trimmed, "/*") {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// compareParams compares the documented parameters of a function with its
+// signature. Destructured parameters may be documented under any name.
+func compareParams(name string, line int, params, documented []string, rule string, destructured int) (Finding, bool) {
+	var missing, unknown []string
+	for _, p := range params {
+		if !slices.Contains(documented, p) {
+			missing = append(missing, p)
+		}
+	}
+	for _, d := range documented {
+		if !slices.Contains(params, d) {
+			unknown = append(unknown, d)
+		}
+	}
+	if len(unknown) <= destructured {
+		unknown = nil
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "doesn't document "+strings.Join(missing, ", "))
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, "documents "+strings.Join(unknown, ", ")+", not in the signature")
+	}
+	if len(problems) == 0 && destructured == 0 && !slices.Equal(params, documented) {
+		problems = append(problems, "documents the parameters out of order")
+	}
+	if len(problems) == 0 {
+		return Finding{}, false
+	}
+	return Finding{
+		Rule:       rule,
+		Line:       line,
+		Severity:   providers.SeverityWarning,
+		Message:    fmt.Sprintf("documentation of %s %s", name, strings.Join(problems, " and ")),
+		Suggestion: fmt.Sprintf("Document the parameters as the signature declares them: %s.", strings.Join(params, ", ")),
+	}, true
+}
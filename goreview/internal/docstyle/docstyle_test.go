@@ -0,0 +1,177 @@
+package docstyle
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// describe returns "line rule message" for each finding.
+func describe(findings []Finding) []string {
+	out := make([]string, len(findings))
+	for i, f := range findings {
+		out[i] = fmt.Sprintf("%d %s %s", f.Line, f.Rule, f.Message)
+	}
+	return out
+}
+
+func check(t *testing.T, filename, language, src, style string, want []string) {
+	t.Helper()
+	findings, err := Check(filename, language, []byte(src), style)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := describe(findings)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheckGo(t *testing.T) {
+	src := `package store
+
+// Open opens the store.
+func Open() {}
+
+// Opens the store read-only.
+func OpenReadOnly() {}
+
+func Close() {}
+
+func helper() {}
+
+// A Store keeps records.
+type Store struct{}
+
+// Get returns a record.
+func (s *Store) Get() {}
+
+func (s *Store) Put() {}
+
+type internal struct{}
+
+func (internal) Exported() {}
+
+// Limits of the store
+const (
+	MaxRecords = 100
+	MaxSize    = 1 << 20
+)
+
+// Version is the format version.
+var Version = 2
+
+var Default *Store
+`
+	check(t, "store.go", "go", src, "", []string{
+		"7 docs/go-doc doc comment of function OpenReadOnly should start with its name",
+		"9 docs/go-doc exported function Close has no doc comment",
+		"19 docs/go-doc exported method Put has no doc comment",
+		"34 docs/go-doc exported var Default has no doc comment",
+	})
+
+	check(t, "main.go", "go", "package main\n\nfunc Run() {}\n", "", nil)
+}
+
+func TestCheckPython(t *testing.T) {
+	src := `def load(path, mode="r"):
+    """Load a file.
+
+    Args:
+        path (str): File to load.
+            Relative to the root.
+        mode: Open mode.
+    """
+
+
+def save(self, data, *, force=False):
+    """Save data.
+
+    Args:
+        data: What to save.
+    """
+
+
+def parse(text):
+    """Parse text.
+
+    Parameters
+    ----------
+    text : str
+        The text.
+    """
+
+
+def clean(value):
+    return value
+
+
+def _private(x):
+    pass
+
+
+class Reader:
+    def read(self, n):
+        """Read n bytes."""
+`
+	check(t, "io.py", "python", src, StyleGoogle, []string{
+		"11 docs/python-params documentation of save doesn't document force",
+		"19 docs/python-docstring docstring of parse uses NumPy style, the project uses Google",
+		"29 docs/python-docstring public function clean has no docstring",
+		"38 docs/python-docstring docstring of read doesn't document its parameters (n)",
+	})
+
+	numpy := `def parse(text, strict=False):
+    """Parse text.
+
+    Parameters
+    ----------
+    strict : bool
+        Fail on errors.
+    text : str
+        The text.
+
+    Returns
+    -------
+    tree : Node
+    """
+`
+	check(t, "parse.py", "python", numpy, StyleNumPy, []string{
+		"1 docs/python-params documentation of parse documents the parameters out of order",
+	})
+}
+
+func TestCheckJSDoc(t *testing.T) {
+	src := `/**
+ * Render a view.
+ * @param {Element} el - Target.
+ * @param {Object} opts - Options.
+ * @param {string} opts.title - Title.
+ */
+export function render(el, { title }) {
+}
+
+/**
+ * Save a record.
+ * @param {number} id
+ * @param {boolean} force
+ */
+export const save = async (id, record) => {
+};
+
+/** Plain description. */
+function noop(a) {
+}
+
+/**
+ * @param b
+ * @param a
+ */
+function swap(a, b) {
+}
+`
+	check(t, "view.js", "javascript", src, "", []string{
+		"15 docs/jsdoc-params documentation of save doesn't document record and documents force, not in the signature",
+		"26 docs/jsdoc-params documentation of swap documents the parameters out of order",
+	})
+}
@@ -11,13 +11,15 @@ import (
 
 	"github.com/JNZader/goreview/goreview/internal/config"
 	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/report"
 	"github.com/JNZader/goreview/goreview/internal/review"
 )
 
 // ObsidianExporter exports review results to an Obsidian vault.
 type ObsidianExporter struct {
-	cfg      *config.ObsidianExportConfig
-	template *template.Template
+	cfg        *config.ObsidianExportConfig
+	template   *template.Template
+	lastOutput string // Path of the last exported note
 }
 
 // obsidianTemplateData holds all data passed to the template.
@@ -27,6 +29,9 @@ type obsidianTemplateData struct {
 	Metadata       *Metadata
 	Config         *config.ObsidianExportConfig
 	RelatedReviews []string
+	IssueNotes     []IssueNote
+	// CauseGraph is the Mermaid graph of the root causes, when traced
+	CauseGraph string
 }
 
 // NewObsidianExporter creates a new Obsidian exporter.
@@ -124,6 +129,13 @@ func (e *ObsidianExporter) Export(result *review.Result, metadata *Metadata) err
 		relatedReviews = e.findRelatedReviews(projectDir, filename)
 	}
 
+	// Name a note per critical/error issue so the review can link to them
+	reviewName := strings.TrimSuffix(filename, ".md")
+	var issueNotes []IssueNote
+	if e.cfg.IssueNotes {
+		issueNotes = buildIssueNotes(result, reviewName)
+	}
+
 	// Prepare template data
 	data := &obsidianTemplateData{
 		Frontmatter:    frontmatter,
@@ -131,6 +143,10 @@ func (e *ObsidianExporter) Export(result *review.Result, metadata *Metadata) err
 		Metadata:       metadata,
 		Config:         e.cfg,
 		RelatedReviews: relatedReviews,
+		IssueNotes:     issueNotes,
+	}
+	if g := report.CauseGraph(result.Public()); !g.Empty() {
+		data.CauseGraph = g.Mermaid()
 	}
 
 	// Execute template
@@ -145,9 +161,25 @@ func (e *ObsidianExporter) Export(result *review.Result, metadata *Metadata) err
 		return fmt.Errorf("writing export file: %w", err)
 	}
 
+	if err := e.writeIssueNotes(projectDir, reviewName, issueNotes, metadata); err != nil {
+		return err
+	}
+
+	if e.cfg.Canvas {
+		if err := e.writeCanvas(projectDir, reviewName, result, issueNotes); err != nil {
+			return err
+		}
+	}
+
+	e.lastOutput = outputPath
 	return nil
 }
 
+// Target returns the path of the last exported note.
+func (e *ObsidianExporter) Target() string {
+	return e.lastOutput
+}
+
 // GetOutputPath returns the path where the export file will be written.
 func (e *ObsidianExporter) GetOutputPath(metadata *Metadata) string {
 	projectDir := filepath.Join(e.cfg.VaultPath, e.cfg.FolderName, sanitizeFilename(metadata.ProjectName))
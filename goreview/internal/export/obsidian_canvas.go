@@ -0,0 +1,138 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// Canvas layout, in canvas units
+const (
+	canvasNodeWidth  = 400
+	canvasNodeHeight = 120
+	canvasColumnGap  = 200
+	canvasRowGap     = 40
+)
+
+// canvas is an Obsidian Canvas document (JSON Canvas format).
+type canvas struct {
+	Nodes []canvasNode `json:"nodes"`
+	Edges []canvasEdge `json:"edges"`
+}
+
+type canvasNode struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"` // "file" or "text"
+	File   string `json:"file,omitempty"`
+	Text   string `json:"text,omitempty"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Color  string `json:"color,omitempty"` // Preset "1" (red) to "6" (purple)
+}
+
+type canvasEdge struct {
+	ID       string `json:"id"`
+	FromNode string `json:"fromNode"`
+	FromSide string `json:"fromSide"`
+	ToNode   string `json:"toNode"`
+	ToSide   string `json:"toSide"`
+}
+
+// buildCanvas lays out the review, its files and their issues in three
+// columns linked by edges. Paths are relative to the vault root. Issues with
+// a note link to it; others are shown as text cards.
+func buildCanvas(result *review.Result, reviewPath string, notes []IssueNote, notesDir string) *canvas {
+	c := &canvas{}
+	noteFor := make(map[string][]IssueNote)
+	for _, n := range notes {
+		noteFor[n.File] = append(noteFor[n.File], n)
+	}
+
+	c.Nodes = append(c.Nodes, canvasNode{
+		ID: "review", Type: "file", File: reviewPath,
+		Width: canvasNodeWidth, Height: canvasNodeHeight * 3,
+	})
+
+	fileX := canvasNodeWidth + canvasColumnGap
+	issueX := fileX + canvasNodeWidth + canvasColumnGap
+	y := 0
+	for i, file := range result.Files {
+		if file.Response == nil || len(file.Response.Issues) == 0 {
+			continue
+		}
+
+		fileID := fmt.Sprintf("file-%d", i)
+		fileY := y
+		c.Nodes = append(c.Nodes, canvasNode{
+			ID: fileID, Type: "text", Text: fmt.Sprintf("**%s**\n%d issue(s)", file.File, len(file.Response.Issues)),
+			X: fileX, Y: fileY, Width: canvasNodeWidth, Height: canvasNodeHeight,
+		})
+		c.Edges = append(c.Edges, canvasEdge{
+			ID: "review-" + fileID, FromNode: "review", FromSide: "right", ToNode: fileID, ToSide: "left",
+		})
+
+		fileNotes := noteFor[file.File]
+		for j, issue := range file.Response.Issues {
+			issueID := fmt.Sprintf("%s-issue-%d", fileID, j)
+			node := canvasNode{
+				ID: issueID, X: issueX, Y: y, Width: canvasNodeWidth, Height: canvasNodeHeight,
+				Color: severityColor(issue.Severity),
+			}
+			if tracked(issue) && len(fileNotes) > 0 {
+				node.Type = "file"
+				node.File = filepath.ToSlash(filepath.Join(notesDir, fileNotes[0].Name+".md"))
+				fileNotes = fileNotes[1:]
+			} else {
+				node.Type = "text"
+				node.Text = fmt.Sprintf("%s **%s**%s\n%s", severityIcon(issue.Severity), issue.Severity, lineSuffix(issue), issue.Message)
+			}
+			c.Nodes = append(c.Nodes, node)
+			c.Edges = append(c.Edges, canvasEdge{
+				ID: fileID + "-" + issueID, FromNode: fileID, FromSide: "right", ToNode: issueID, ToSide: "left",
+			})
+			y += canvasNodeHeight + canvasRowGap
+		}
+		// Leave a gap between files
+		y = max(y, fileY+canvasNodeHeight+canvasRowGap) + canvasRowGap
+	}
+	return c
+}
+
+// writeCanvas writes the review canvas next to the review note.
+func (e *ObsidianExporter) writeCanvas(projectDir, reviewName string, result *review.Result, notes []IssueNote) error {
+	rel, err := filepath.Rel(e.cfg.VaultPath, projectDir)
+	if err != nil {
+		return fmt.Errorf("resolving vault path: %w", err)
+	}
+	reviewPath := filepath.ToSlash(filepath.Join(rel, reviewName+".md"))
+	notesDir := filepath.Join(rel, issueNotesDir)
+
+	data, err := json.MarshalIndent(buildCanvas(result, reviewPath, notes, notesDir), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling canvas: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, reviewName+".canvas"), data, 0600); err != nil {
+		return fmt.Errorf("writing canvas: %w", err)
+	}
+	return nil
+}
+
+// severityColor returns the canvas color preset for a severity.
+func severityColor(severity providers.Severity) string {
+	switch severity {
+	case providers.SeverityCritical:
+		return "1"
+	case providers.SeverityError:
+		return "2"
+	case providers.SeverityWarning:
+		return "3"
+	default:
+		return "5"
+	}
+}
@@ -0,0 +1,147 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// issueNotesDir is the project subfolder holding per-issue notes
+const issueNotesDir = "issues"
+
+// maxSlugLength limits the message part of issue note names
+const maxSlugLength = 40
+
+// IssueNote is a per-issue task note linked to its review note.
+type IssueNote struct {
+	// Name is the note name without extension, used in wiki links
+	Name string
+
+	// File is the reviewed file the issue was found in
+	File string
+
+	// Issue is the tracked issue
+	Issue providers.Issue
+}
+
+// tracked reports whether an issue gets its own note.
+func tracked(issue providers.Issue) bool {
+	return issue.Severity == providers.SeverityCritical || issue.Severity == providers.SeverityError
+}
+
+// buildIssueNotes names a note for each critical or error issue of the review.
+// Names are prefixed with the review note name so they are unique per review.
+func buildIssueNotes(result *review.Result, reviewName string) []IssueNote {
+	var notes []IssueNote
+	for _, file := range result.Files {
+		if file.Response == nil {
+			continue
+		}
+		for _, issue := range file.Response.Issues {
+			if !tracked(issue) {
+				continue
+			}
+			name := fmt.Sprintf("%s-issue-%02d", reviewName, len(notes)+1)
+			if slug := slugify(issue.Message); slug != "" {
+				name += "-" + slug
+			}
+			notes = append(notes, IssueNote{Name: name, File: file.File, Issue: issue})
+		}
+	}
+	return notes
+}
+
+// writeIssueNotes writes the issue notes into the project's issues folder.
+func (e *ObsidianExporter) writeIssueNotes(projectDir, reviewName string, notes []IssueNote, metadata *Metadata) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	dir := filepath.Join(projectDir, issueNotesDir)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("creating issues directory: %w", err)
+	}
+
+	for _, note := range notes {
+		content := renderIssueNote(note, reviewName, metadata, e.cfg.IncludeTags)
+		if err := os.WriteFile(filepath.Join(dir, note.Name+".md"), []byte(content), 0600); err != nil {
+			return fmt.Errorf("writing issue note: %w", err)
+		}
+	}
+	return nil
+}
+
+// renderIssueNote renders an issue as a task note with a backlink to the review.
+func renderIssueNote(note IssueNote, reviewName string, metadata *Metadata, includeTags bool) string {
+	issue := note.Issue
+	var sb strings.Builder
+
+	sb.WriteString("---\n")
+	sb.WriteString(fmt.Sprintf("date: %s\n", metadata.ReviewDate.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("project: %s\n", metadata.ProjectName))
+	sb.WriteString(fmt.Sprintf("review: \"%s\"\n", wikiLink(reviewName)))
+	sb.WriteString(fmt.Sprintf("file: %s\n", note.File))
+	if issue.Location != nil && issue.Location.StartLine > 0 {
+		sb.WriteString(fmt.Sprintf("line: %d\n", issue.Location.StartLine))
+	}
+	sb.WriteString(fmt.Sprintf("severity: %s\n", issue.Severity))
+	sb.WriteString(fmt.Sprintf("type: %s\n", issue.Type))
+	if issue.RuleID != "" {
+		sb.WriteString(fmt.Sprintf("rule: %s\n", issue.RuleID))
+	}
+	sb.WriteString("status: open\n")
+	sb.WriteString("tags:\n  - goreview\n  - goreview-issue\n")
+	sb.WriteString(fmt.Sprintf("  - %s\n", issue.Severity))
+	sb.WriteString("---\n\n")
+
+	sb.WriteString(fmt.Sprintf("# %s %s\n\n", severityIcon(issue.Severity), issue.Message))
+	if includeTags {
+		sb.WriteString(fmt.Sprintf("%s\n\n", formatTags([]string{"goreview-issue", string(issue.Severity), strings.ToLower(string(issue.Type))})))
+	}
+
+	sb.WriteString("## Task\n\n")
+	sb.WriteString(fmt.Sprintf("- [ ] Fix %s in `%s`%s\n\n", strings.ToLower(string(issue.Type)), note.File, lineSuffix(issue)))
+
+	sb.WriteString("## Details\n\n")
+	sb.WriteString(fmt.Sprintf("- **Review:** %s\n", wikiLink(reviewName)))
+	sb.WriteString(fmt.Sprintf("- **File:** `%s`%s\n", note.File, lineSuffix(issue)))
+	sb.WriteString(fmt.Sprintf("- **Severity:** %s\n", issue.Severity))
+	sb.WriteString(fmt.Sprintf("- **Type:** %s\n", issue.Type))
+	if issue.Suggestion != "" {
+		sb.WriteString(fmt.Sprintf("\n**Suggestion:** %s\n", issue.Suggestion))
+	}
+	if issue.Code != "" {
+		sb.WriteString("\n**Code:**\n```\n" + issue.Code + "\n```\n")
+	}
+	if issue.FixedCode != "" {
+		sb.WriteString("\n**Suggested Fix:**\n```\n" + issue.FixedCode + "\n```\n")
+	}
+	if issue.RootCause != nil {
+		sb.WriteString(fmt.Sprintf("\n**Root Cause:** %s\n", issue.RootCause.Description))
+	}
+	return sb.String()
+}
+
+func lineSuffix(issue providers.Issue) string {
+	if issue.Location == nil || issue.Location.StartLine <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (line %d)", issue.Location.StartLine)
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify turns an issue message into a short filename-safe slug.
+func slugify(s string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
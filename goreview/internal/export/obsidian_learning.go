@@ -0,0 +1,48 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// learningNoteName is the project note collecting issue explanations
+const learningNoteName = "learning.md"
+
+// AppendLearning appends an explanation, as a section with the title, to the
+// project's learning note, creating the note on first use. It returns the
+// note's path.
+func (e *ObsidianExporter) AppendLearning(title, content string, metadata *Metadata) (string, error) {
+	projectDir := filepath.Join(e.cfg.VaultPath, e.cfg.FolderName, sanitizeFilename(metadata.ProjectName))
+	if err := os.MkdirAll(projectDir, 0750); err != nil {
+		return "", fmt.Errorf("creating project directory: %w", err)
+	}
+	notePath := filepath.Join(projectDir, learningNoteName)
+
+	var sb strings.Builder
+	if _, err := os.Stat(notePath); os.IsNotExist(err) {
+		sb.WriteString("---\n")
+		sb.WriteString(fmt.Sprintf("project: %s\n", metadata.ProjectName))
+		sb.WriteString("tags:\n  - goreview\n  - goreview-learning\n")
+		sb.WriteString("---\n\n")
+		sb.WriteString(fmt.Sprintf("# Learning: %s\n\n", metadata.ProjectName))
+		if e.cfg.IncludeTags {
+			sb.WriteString(formatTags([]string{"goreview-learning"}) + "\n\n")
+		}
+	}
+	sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+	sb.WriteString(fmt.Sprintf("*%s*\n\n", metadata.ReviewDate.Format(time.DateOnly)))
+	sb.WriteString(strings.TrimSpace(content) + "\n\n")
+
+	f, err := os.OpenFile(notePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 - path inside the configured vault
+	if err != nil {
+		return "", fmt.Errorf("opening learning note: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(sb.String()); err != nil {
+		return "", fmt.Errorf("writing learning note: %w", err)
+	}
+	return notePath, nil
+}
@@ -68,7 +68,9 @@ aliases:
 {{- if and .Response (gt (len .Response.Issues) 0) }}
 
 ### ` + "`{{ .File }}`" + `
-{{- if .Cached }}
+{{- if .Normalized }}
+_Cached result (cache hit, normalized)_
+{{- else if .Cached }}
 _Cached result_
 {{- end }}
 
@@ -130,6 +132,23 @@ _Cached result_
 
 {{- end }}
 
+{{- if .CauseGraph }}
+
+## Root Causes
+
+` + "```mermaid" + `
+{{ .CauseGraph }}` + "```" + `
+{{- end }}
+
+{{- if .IssueNotes }}
+
+## Tracked Issues
+
+{{- range .IssueNotes }}
+- {{ severityIcon .Issue.Severity }} {{ wikiLink .Name }} - ` + "`{{ .File }}`" + `
+{{- end }}
+{{- end }}
+
 {{- if and .Config.IncludeLinks (gt (len .RelatedReviews) 0) }}
 
 ## Related Reviews
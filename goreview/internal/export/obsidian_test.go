@@ -0,0 +1,137 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+func TestObsidianExportIssueNotesAndCanvas(t *testing.T) {
+	vault := t.TempDir()
+	exporter, err := NewObsidianExporter(&config.ObsidianExportConfig{
+		VaultPath:  vault,
+		FolderName: "GoReview",
+		IssueNotes: true,
+		Canvas:     true,
+	})
+	if err != nil {
+		t.Fatalf("NewObsidianExporter() error = %v", err)
+	}
+
+	result := &review.Result{
+		Files: []review.FileResult{{
+			File: "main.go",
+			Response: &providers.ReviewResponse{Issues: []providers.Issue{
+				{Type: providers.IssueTypeSecurity, Severity: providers.SeverityCritical, Message: "SQL injection in query",
+					Location:  &providers.Location{StartLine: 12},
+					RootCause: &providers.RootCause{Description: "Filters are concatenated", OriginFile: "db.go", OriginLine: 40}},
+				{Type: providers.IssueTypeStyle, Severity: providers.SeverityInfo, Message: "Long line"},
+			}},
+		}},
+		TotalIssues: 2,
+	}
+	metadata := &Metadata{ProjectName: "demo", ReviewDate: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)}
+
+	if err := exporter.Export(result, metadata); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	projectDir := filepath.Join(vault, "GoReview", "demo")
+	noteName := "review-001-2024-01-15-issue-01-sql-injection-in-query"
+	note, err := os.ReadFile(filepath.Join(projectDir, issueNotesDir, noteName+".md"))
+	if err != nil {
+		t.Fatalf("issue note not written: %v", err)
+	}
+	for _, want := range []string{"[[review-001-2024-01-15]]", "- [ ] Fix security in `main.go` (line 12)", "status: open"} {
+		if !strings.Contains(string(note), want) {
+			t.Errorf("issue note missing %q", want)
+		}
+	}
+	if entries, _ := os.ReadDir(filepath.Join(projectDir, issueNotesDir)); len(entries) != 1 {
+		t.Errorf("issue notes = %d, want 1 (info issues are not tracked)", len(entries))
+	}
+
+	reviewNote, err := os.ReadFile(filepath.Join(projectDir, "review-001-2024-01-15.md"))
+	if err != nil {
+		t.Fatalf("review note not written: %v", err)
+	}
+	if !strings.Contains(string(reviewNote), "[["+noteName+"]]") {
+		t.Error("review note does not link the issue note")
+	}
+	if !strings.Contains(string(reviewNote), "## Root Causes\n\n```mermaid\nflowchart LR\n") {
+		t.Error("review note does not graph the root causes")
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectDir, "review-001-2024-01-15.canvas"))
+	if err != nil {
+		t.Fatalf("canvas not written: %v", err)
+	}
+	var c canvas
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("invalid canvas JSON: %v", err)
+	}
+	// Review, file and two issues
+	if len(c.Nodes) != 4 || len(c.Edges) != 3 {
+		t.Fatalf("canvas has %d nodes and %d edges, want 4 and 3", len(c.Nodes), len(c.Edges))
+	}
+	if c.Nodes[0].File != "GoReview/demo/review-001-2024-01-15.md" {
+		t.Errorf("review node file = %q", c.Nodes[0].File)
+	}
+	if c.Nodes[2].File != "GoReview/demo/issues/"+noteName+".md" {
+		t.Errorf("tracked issue node file = %q", c.Nodes[2].File)
+	}
+	if c.Nodes[3].Type != "text" {
+		t.Errorf("untracked issue node type = %q, want text", c.Nodes[3].Type)
+	}
+}
+
+func TestObsidianAppendLearning(t *testing.T) {
+	vault := t.TempDir()
+	exporter, err := NewObsidianExporter(&config.ObsidianExportConfig{VaultPath: vault, FolderName: "GoReview"})
+	if err != nil {
+		t.Fatalf("NewObsidianExporter() error = %v", err)
+	}
+	metadata := &Metadata{ProjectName: "demo", ReviewDate: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)}
+
+	for _, title := range []string{"#1 unchecked error", "#2 SQL injection"} {
+		if _, err := exporter.AppendLearning(title, "Why it matters.", metadata); err != nil {
+			t.Fatalf("AppendLearning() error = %v", err)
+		}
+	}
+
+	note, err := os.ReadFile(filepath.Join(vault, "GoReview", "demo", learningNoteName))
+	if err != nil {
+		t.Fatalf("learning note not written: %v", err)
+	}
+	if n := strings.Count(string(note), "goreview-learning"); n != 1 {
+		t.Errorf("note header written %d times, want once", n)
+	}
+	first, second := strings.Index(string(note), "## #1 unchecked error"), strings.Index(string(note), "## #2 SQL injection")
+	if first == -1 || second < first {
+		t.Errorf("explanations not appended in order:\n%s", note)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"SQL injection in query", "sql-injection-in-query"},
+		{"  Unchecked error: os.Open!  ", "unchecked-error-os-open"},
+		{"", ""},
+		{strings.Repeat("word ", 20), "word-word-word-word-word-word-word-word"},
+	}
+	for _, tt := range tests {
+		if got := slugify(tt.in); got != tt.want {
+			t.Errorf("slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// Targeted is implemented by exporters that can tell where their last
+// export was written or sent.
+type Targeted interface {
+	Target() string
+}
+
+// Outcome is the result of running a single exporter.
+type Outcome struct {
+	Exporter   string `json:"exporter"`
+	Success    bool   `json:"success"`
+	Target     string `json:"target,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Summary collects the outcomes of an export run.
+type Summary struct {
+	Outcomes  []Outcome `json:"outcomes"`
+	Succeeded int       `json:"succeeded"`
+	Failed    int       `json:"failed"`
+}
+
+// Pipeline runs several exporters on the same review result.
+type Pipeline struct {
+	names     []string
+	exporters []Exporter
+	failed    []Outcome // Exporters that could not be created
+}
+
+// NewPipeline creates the named exporters. An exporter that fails to build
+// does not prevent the others from running; it is reported in the summary.
+func NewPipeline(cfg *config.ExportConfig, names []string) *Pipeline {
+	p := &Pipeline{}
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		exporter, err := New(name, cfg)
+		if err != nil {
+			p.failed = append(p.failed, Outcome{Exporter: name, Error: err.Error()})
+			continue
+		}
+		p.names = append(p.names, name)
+		p.exporters = append(p.exporters, exporter)
+	}
+	return p
+}
+
+// Empty reports whether the pipeline has nothing to run or report.
+func (p *Pipeline) Empty() bool {
+	return len(p.exporters) == 0 && len(p.failed) == 0
+}
+
+// Run executes all exporters concurrently. A failing or panicking exporter
+// doesn't affect the others. Outcomes keep the order of the exporter names.
+func (p *Pipeline) Run(result *review.Result, metadata *Metadata) *Summary {
+	outcomes := make([]Outcome, len(p.exporters))
+
+	var wg sync.WaitGroup
+	for i, exporter := range p.exporters {
+		wg.Add(1)
+		go func(i int, exporter Exporter) {
+			defer wg.Done()
+			outcomes[i] = runExporter(p.names[i], exporter, result, metadata)
+		}(i, exporter)
+	}
+	wg.Wait()
+
+	summary := &Summary{Outcomes: append(outcomes, p.failed...)}
+	for _, o := range summary.Outcomes {
+		if o.Success {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+// runExporter runs one exporter, turning errors and panics into an outcome.
+func runExporter(name string, exporter Exporter, result *review.Result, metadata *Metadata) (outcome Outcome) {
+	start := time.Now()
+	outcome.Exporter = name
+	defer func() {
+		if r := recover(); r != nil {
+			outcome.Success = false
+			outcome.Error = fmt.Sprintf("panic: %v", r)
+		}
+		outcome.DurationMS = time.Since(start).Milliseconds()
+	}()
+
+	if err := exporter.Export(result, metadata); err != nil {
+		outcome.Error = err.Error()
+		return outcome
+	}
+	outcome.Success = true
+	if t, ok := exporter.(Targeted); ok {
+		outcome.Target = t.Target()
+	}
+	return outcome
+}
+
+// WriteText writes a human-readable summary, one line per exporter.
+func (s *Summary) WriteText(w io.Writer) {
+	for _, o := range s.Outcomes {
+		if !o.Success {
+			_, _ = fmt.Fprintf(w, "Export %s failed: %s\n", o.Exporter, o.Error)
+			continue
+		}
+		if o.Target != "" {
+			_, _ = fmt.Fprintf(w, "Exported to %s: %s (%dms)\n", o.Exporter, o.Target, o.DurationMS)
+		} else {
+			_, _ = fmt.Fprintf(w, "Exported to %s (%dms)\n", o.Exporter, o.DurationMS)
+		}
+	}
+}
+
+// WriteJSON writes the summary as JSON.
+func (s *Summary) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
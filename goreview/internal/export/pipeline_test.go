@@ -0,0 +1,160 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// stubExporter is a test exporter with a configurable behavior
+type stubExporter struct {
+	err   error
+	panic bool
+}
+
+func (s *stubExporter) Name() string { return "stub" }
+
+func (s *stubExporter) Export(*review.Result, *Metadata) error {
+	if s.panic {
+		panic("boom")
+	}
+	return s.err
+}
+
+func TestPipelineIsolatesFailures(t *testing.T) {
+	registry["ok"] = registration{factory: func(*config.ExportConfig) (Exporter, error) { return &stubExporter{}, nil }}
+	registry["fails"] = registration{factory: func(*config.ExportConfig) (Exporter, error) {
+		return &stubExporter{err: errors.New("unreachable")}, nil
+	}}
+	registry["panics"] = registration{factory: func(*config.ExportConfig) (Exporter, error) { return &stubExporter{panic: true}, nil }}
+	defer func() {
+		delete(registry, "ok")
+		delete(registry, "fails")
+		delete(registry, "panics")
+	}()
+
+	p := NewPipeline(&config.ExportConfig{}, []string{"fails", "ok", "panics", "ok", "missing"})
+	summary := p.Run(&review.Result{}, &Metadata{})
+
+	want := []struct {
+		name    string
+		success bool
+		err     string
+	}{
+		{"fails", false, "unreachable"},
+		{"ok", true, ""},
+		{"panics", false, "panic: boom"},
+		{"missing", false, "unknown exporter"},
+	}
+	if len(summary.Outcomes) != len(want) {
+		t.Fatalf("outcomes = %+v, want %d", summary.Outcomes, len(want))
+	}
+	for i, w := range want {
+		o := summary.Outcomes[i]
+		if o.Exporter != w.name || o.Success != w.success || !strings.Contains(o.Error, w.err) {
+			t.Errorf("outcome %d = %+v, want %s success=%v error~%q", i, o, w.name, w.success, w.err)
+		}
+	}
+	if summary.Succeeded != 1 || summary.Failed != 3 {
+		t.Errorf("succeeded/failed = %d/%d, want 1/3", summary.Succeeded, summary.Failed)
+	}
+
+	var buf bytes.Buffer
+	summary.WriteText(&buf)
+	if !strings.Contains(buf.String(), "Export fails failed: unreachable") {
+		t.Errorf("text summary = %q", buf.String())
+	}
+}
+
+func TestEnabledNames(t *testing.T) {
+	cfg := &config.ExportConfig{
+		Slack:   config.SlackExportConfig{Enabled: true},
+		Webhook: config.WebhookExportConfig{Enabled: true},
+	}
+	got := strings.Join(EnabledNames(cfg), ",")
+	if got != "slack,webhook" {
+		t.Errorf("EnabledNames() = %q, want slack,webhook", got)
+	}
+}
+
+func TestWebhookExporter(t *testing.T) {
+	var got webhookPayload
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	exporter, err := NewWebhookExporter(&config.WebhookExportConfig{
+		URL:     server.URL + "/hook?token=secret",
+		Headers: map[string]string{"Authorization": "Bearer abc"},
+	}, time.Second)
+	if err != nil {
+		t.Fatalf("NewWebhookExporter() error = %v", err)
+	}
+
+	if err := exporter.Export(&review.Result{TotalIssues: 3}, &Metadata{ProjectName: "demo"}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if got.Event != WebhookEvent || got.Metadata.ProjectName != "demo" || got.Result.TotalIssues != 3 {
+		t.Errorf("payload = %+v", got)
+	}
+	if auth != "Bearer abc" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if target := exporter.Target(); strings.Contains(target, "secret") {
+		t.Errorf("Target() leaks query string: %s", target)
+	}
+}
+
+func TestWebhookExporterHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	exporter, _ := NewWebhookExporter(&config.WebhookExportConfig{URL: server.URL}, time.Second)
+	err := exporter.Export(&review.Result{}, &Metadata{})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 400: bad payload") {
+		t.Errorf("Export() error = %v, want HTTP 400", err)
+	}
+}
+
+func TestFormatSlackMessage(t *testing.T) {
+	result := &review.Result{
+		TotalIssues: 3,
+		Files: []review.FileResult{{
+			File: "main.go",
+			Response: &providers.ReviewResponse{Issues: []providers.Issue{
+				{Severity: providers.SeverityInfo, Message: "Naming"},
+				{Severity: providers.SeverityCritical, Message: "SQL injection", Location: &providers.Location{StartLine: 7}},
+				{Severity: providers.SeverityWarning, Message: "Unused variable"},
+			}},
+		}},
+	}
+
+	msg := formatSlackMessage(result, &Metadata{ProjectName: "demo", Branch: "main"}, 2)
+	lines := strings.Split(strings.TrimSpace(msg), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("message = %q, want 5 lines", msg)
+	}
+	if !strings.Contains(lines[2], "SQL injection") || !strings.Contains(lines[2], "(line 7)") {
+		t.Errorf("most severe issue not listed first: %q", lines[2])
+	}
+	if lines[4] != "_…and 1 more_" {
+		t.Errorf("truncation line = %q", lines[4])
+	}
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// Factory creates an exporter from the export configuration.
+type Factory func(cfg *config.ExportConfig) (Exporter, error)
+
+// registration describes a registered exporter.
+type registration struct {
+	factory Factory
+	enabled func(cfg *config.ExportConfig) bool
+}
+
+// registry holds the known exporters by name
+var registry = map[string]registration{
+	"obsidian": {
+		factory: func(cfg *config.ExportConfig) (Exporter, error) { return NewObsidianExporter(&cfg.Obsidian) },
+		enabled: func(cfg *config.ExportConfig) bool { return cfg.Obsidian.Enabled },
+	},
+	"slack": {
+		factory: func(cfg *config.ExportConfig) (Exporter, error) { return NewSlackExporter(&cfg.Slack, cfg.Timeout) },
+		enabled: func(cfg *config.ExportConfig) bool { return cfg.Slack.Enabled },
+	},
+	"webhook": {
+		factory: func(cfg *config.ExportConfig) (Exporter, error) { return NewWebhookExporter(&cfg.Webhook, cfg.Timeout) },
+		enabled: func(cfg *config.ExportConfig) bool { return cfg.Webhook.Enabled },
+	},
+}
+
+// Register adds an exporter to the registry, replacing any exporter with the
+// same name. enabled reports whether the configuration turns it on.
+func Register(name string, factory Factory, enabled func(cfg *config.ExportConfig) bool) {
+	registry[name] = registration{factory: factory, enabled: enabled}
+}
+
+// Available returns the names of all registered exporters, sorted.
+func Available() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnabledNames returns the names of the exporters enabled in the configuration, sorted.
+func EnabledNames(cfg *config.ExportConfig) []string {
+	var names []string
+	for _, name := range Available() {
+		if r := registry[name]; r.enabled != nil && r.enabled(cfg) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// New creates the named exporter.
+func New(name string, cfg *config.ExportConfig) (Exporter, error) {
+	r, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown exporter: %s (available: %v)", name, Available())
+	}
+	return r.factory(cfg)
+}
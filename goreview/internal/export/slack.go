@@ -0,0 +1,119 @@
+package export
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// SlackExporter posts a review summary to a Slack incoming webhook.
+type SlackExporter struct {
+	cfg    *config.SlackExportConfig
+	client *http.Client
+}
+
+// slackMessage is the incoming webhook payload.
+type slackMessage struct {
+	Text    string `json:"text"`
+	Channel string `json:"channel,omitempty"`
+}
+
+// NewSlackExporter creates a new Slack exporter.
+func NewSlackExporter(cfg *config.SlackExportConfig, timeout time.Duration) (*SlackExporter, error) {
+	if cfg.WebhookURL == "" {
+		return nil, fmt.Errorf("slack webhook URL is required")
+	}
+	return &SlackExporter{cfg: cfg, client: &http.Client{Timeout: timeout}}, nil
+}
+
+// Name returns the exporter name.
+func (e *SlackExporter) Name() string {
+	return "slack"
+}
+
+// Export posts the review summary to Slack.
+func (e *SlackExporter) Export(result *review.Result, metadata *Metadata) error {
+	msg := &slackMessage{Text: formatSlackMessage(result, metadata, e.cfg.MaxIssues), Channel: e.cfg.Channel}
+	return postJSON(e.client, e.cfg.WebhookURL, nil, msg)
+}
+
+// Target returns the channel, since the webhook URL itself is a secret.
+func (e *SlackExporter) Target() string {
+	if e.cfg.Channel != "" {
+		return e.cfg.Channel
+	}
+	return "default channel"
+}
+
+// slackIssue is an issue with the file it was found in
+type slackIssue struct {
+	file  string
+	issue providers.Issue
+}
+
+// formatSlackMessage renders the review as Slack mrkdwn, listing the most
+// severe issues first.
+func formatSlackMessage(result *review.Result, metadata *Metadata, maxIssues int) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("*GoReview* · %s", metadata.ProjectName))
+	if metadata.Branch != "" {
+		sb.WriteString(fmt.Sprintf(" `%s`", metadata.Branch))
+	}
+	if metadata.CommitShort != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", metadata.CommitShort))
+	}
+	sb.WriteString("\n")
+
+	if result.TotalIssues == 0 {
+		sb.WriteString(fmt.Sprintf(":white_check_mark: No issues found in %d file(s)\n", len(result.Files)))
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("%d issue(s) in %d file(s)\n", result.TotalIssues, len(result.Files)))
+
+	var issues []slackIssue
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			issues = append(issues, slackIssue{file: f.File, issue: issue})
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].issue.Severity.Rank() > issues[j].issue.Severity.Rank()
+	})
+
+	shown := issues
+	if maxIssues > 0 && len(shown) > maxIssues {
+		shown = shown[:maxIssues]
+	}
+	for _, si := range shown {
+		sb.WriteString(fmt.Sprintf("%s *%s* `%s`%s: %s\n",
+			slackSeverityEmoji(si.issue.Severity), si.issue.Severity, si.file, lineSuffix(si.issue), si.issue.Message))
+	}
+	if len(issues) > len(shown) {
+		sb.WriteString(fmt.Sprintf("_…and %d more_\n", len(issues)-len(shown)))
+	}
+	return sb.String()
+}
+
+// slackSeverityEmoji returns the Slack emoji for a severity.
+func slackSeverityEmoji(severity providers.Severity) string {
+	switch severity {
+	case providers.SeverityCritical:
+		return ":red_circle:"
+	case providers.SeverityError:
+		return ":large_orange_circle:"
+	case providers.SeverityWarning:
+		return ":large_yellow_circle:"
+	default:
+		return ":large_blue_circle:"
+	}
+}
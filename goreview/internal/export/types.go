@@ -19,28 +19,28 @@ type Exporter interface {
 // Metadata contains metadata for the export.
 type Metadata struct {
 	// ProjectName is the name of the project being reviewed
-	ProjectName string
+	ProjectName string `json:"project"`
 
 	// Branch is the current git branch
-	Branch string
+	Branch string `json:"branch,omitempty"`
 
 	// CommitHash is the full commit hash
-	CommitHash string
+	CommitHash string `json:"commit,omitempty"`
 
 	// CommitShort is the short commit hash (7 chars)
-	CommitShort string
+	CommitShort string `json:"commit_short,omitempty"`
 
 	// Author is the commit author
-	Author string
+	Author string `json:"author,omitempty"`
 
 	// ReviewDate is when the review was performed
-	ReviewDate time.Time
+	ReviewDate time.Time `json:"review_date"`
 
 	// ReviewMode is the review mode used (staged, commit, branch, files)
-	ReviewMode string
+	ReviewMode string `json:"review_mode,omitempty"`
 
 	// BaseBranch is the base branch for branch mode
-	BaseBranch string
+	BaseBranch string `json:"base_branch,omitempty"`
 }
 
 // ObsidianFrontmatter represents YAML frontmatter for Obsidian notes.
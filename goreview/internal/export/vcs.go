@@ -0,0 +1,388 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// commentThreads is the review comment API of a code hosting platform.
+type commentThreads interface {
+	// Create posts an inline comment and returns its comment and thread IDs
+	Create(ctx context.Context, c draftComment) (commentID, threadID string, err error)
+	// Update replaces the body of a posted comment
+	Update(ctx context.Context, posted *history.PRComment, body string) error
+	// Resolve resolves or reopens the thread of a posted comment, filling in
+	// its thread ID when unknown
+	Resolve(ctx context.Context, posted *history.PRComment, resolved bool) error
+}
+
+// commentStore keeps the comments posted on each request.
+type commentStore interface {
+	PRComments(ctx context.Context, platform, repo string, number int) ([]history.PRComment, error)
+	SavePRComment(ctx context.Context, c *history.PRComment) error
+}
+
+// draftComment is an inline comment for an issue.
+type draftComment struct {
+	Fingerprint string
+	Path        string
+	// Line is the line the comment is attached to; on GitHub, the last line
+	// of a multi-line comment starting at StartLine
+	Line int
+	// StartLine is the first line of a multi-line GitHub comment, 0 for
+	// single-line comments
+	StartLine int
+	Body      string
+}
+
+// SyncStats counts what a comment sync did.
+type SyncStats struct {
+	Created   int
+	Updated   int
+	Resolved  int
+	Reopened  int
+	Unchanged int
+}
+
+// VCSExporter posts issues as review comments on a GitHub pull request or
+// GitLab merge request. The posted comments are recorded in the history
+// database, so later runs update them instead of posting duplicates and
+// resolve the threads of issues no longer found. With labels enabled, the
+// request is also labeled by change type.
+type VCSExporter struct {
+	cfg     config.VCSExportConfig
+	client  *http.Client
+	dbPath  string
+	threads commentThreads
+	labels  requestLabels
+	last    SyncStats
+	labeled []string
+}
+
+// NewVCSExporter creates a VCS comment exporter recording its comments in
+// the history database at dbPath. Settings left unset are taken from the
+// GitHub Actions or GitLab CI environment.
+func NewVCSExporter(cfg *config.VCSExportConfig, timeout time.Duration, dbPath string) (*VCSExporter, error) {
+	resolved := resolveVCSConfig(*cfg, os.Getenv)
+	if resolved.Platform == "" {
+		return nil, fmt.Errorf("vcs platform not set and not detected from the CI environment")
+	}
+	if resolved.Repo == "" || resolved.Number <= 0 {
+		return nil, fmt.Errorf("vcs repository and request number are required outside pull request CI jobs")
+	}
+	if resolved.Token == "" {
+		return nil, fmt.Errorf("vcs token is required (export.vcs.token, GITHUB_TOKEN or GITLAB_TOKEN)")
+	}
+
+	e := &VCSExporter{cfg: resolved, client: &http.Client{Timeout: timeout}, dbPath: dbPath}
+	if resolved.Platform == "github" {
+		github := newGitHubThreads(e.client, resolved)
+		e.threads, e.labels = github, github
+	} else {
+		gitlab := newGitLabThreads(e.client, resolved)
+		e.threads, e.labels = gitlab, gitlab
+	}
+	return e, nil
+}
+
+// Name returns the exporter name.
+func (e *VCSExporter) Name() string {
+	return "vcs"
+}
+
+// Target names the request and what the sync did.
+func (e *VCSExporter) Target() string {
+	s := e.last
+	target := fmt.Sprintf("%s %s#%d (%d new, %d updated, %d resolved, %d reopened",
+		e.cfg.Platform, e.cfg.Repo, e.cfg.Number, s.Created, s.Updated, s.Resolved, s.Reopened)
+	if len(e.labeled) > 0 {
+		target += "; labeled " + strings.Join(e.labeled, ", ")
+	}
+	return target + ")"
+}
+
+// Export syncs the review comments of the request with the result and,
+// with labels enabled, labels the request.
+func (e *VCSExporter) Export(result *review.Result, _ *Metadata) error {
+	store, err := history.NewStore(history.StoreConfig{Path: e.dbPath})
+	if err != nil {
+		return fmt.Errorf("opening history database: %w", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	stats, err := syncComments(ctx, e.threads, store, e.cfg, result)
+	e.last = stats
+	if err != nil || !e.cfg.Labels.Enabled {
+		return err
+	}
+	e.labeled, err = applyLabels(ctx, e.labels, e.cfg.Labels, result)
+	if err != nil {
+		return fmt.Errorf("labeling: %w", err)
+	}
+	return nil
+}
+
+// syncComments posts the comments of new issues, updates those whose body
+// changed, reopens those found again after being resolved, and resolves
+// those of reviewed files whose issue is gone. Files not in the result keep
+// their threads, since they weren't reviewed.
+func syncComments(ctx context.Context, threads commentThreads, store commentStore, cfg config.VCSExportConfig, result *review.Result) (SyncStats, error) {
+	var stats SyncStats
+	posted, err := store.PRComments(ctx, cfg.Platform, cfg.Repo, cfg.Number)
+	if err != nil {
+		return stats, err
+	}
+	byFingerprint := make(map[string]*history.PRComment, len(posted))
+	for i := range posted {
+		byFingerprint[posted[i].Fingerprint] = &posted[i]
+	}
+
+	reviewed := make(map[string]bool, len(result.Files))
+	seen := make(map[string]bool)
+	for _, d := range draftComments(result, cfg.Platform) {
+		reviewed[d.Path] = true
+		if seen[d.Fingerprint] {
+			continue
+		}
+		seen[d.Fingerprint] = true
+
+		hash := bodyHash(d.Body)
+		c, ok := byFingerprint[d.Fingerprint]
+		if !ok {
+			commentID, threadID, err := threads.Create(ctx, d)
+			if err != nil {
+				return stats, fmt.Errorf("commenting on %s:%d: %w", d.Path, d.Line, err)
+			}
+			c = &history.PRComment{
+				Platform: cfg.Platform, Repo: cfg.Repo, Number: cfg.Number, Fingerprint: d.Fingerprint,
+				FilePath: d.Path, CommentID: commentID, ThreadID: threadID, BodyHash: hash,
+			}
+			stats.Created++
+		} else {
+			changed := false
+			if c.Resolved {
+				if err := threads.Resolve(ctx, c, false); err != nil {
+					return stats, fmt.Errorf("reopening comment on %s: %w", d.Path, err)
+				}
+				c.Resolved, changed = false, true
+				stats.Reopened++
+			}
+			if c.BodyHash != hash {
+				if err := threads.Update(ctx, c, d.Body); err != nil {
+					return stats, fmt.Errorf("updating comment on %s: %w", d.Path, err)
+				}
+				c.BodyHash, changed = hash, true
+				stats.Updated++
+			}
+			if !changed {
+				stats.Unchanged++
+				continue
+			}
+		}
+		if err := store.SavePRComment(ctx, c); err != nil {
+			return stats, err
+		}
+	}
+
+	for _, f := range result.Files {
+		reviewed[f.File] = true
+	}
+	for i := range posted {
+		c := &posted[i]
+		if c.Resolved || seen[c.Fingerprint] || !reviewed[c.FilePath] {
+			continue
+		}
+		if err := threads.Resolve(ctx, c, true); err != nil {
+			return stats, fmt.Errorf("resolving comment on %s: %w", c.FilePath, err)
+		}
+		c.Resolved = true
+		stats.Resolved++
+		if err := store.SavePRComment(ctx, c); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// draftComments returns a comment for each issue with a line in the diff
+// to attach it to; the platforms reject comments on other lines.
+func draftComments(result *review.Result, platform string) []draftComment {
+	var drafts []draftComment
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			loc := issue.Location
+			if loc == nil || loc.StartLine <= 0 || !f.InDiff(loc.StartLine, loc.StartLine) {
+				continue
+			}
+			fp := history.IssueFingerprint(f.File, string(issue.Type), issue.Message)
+			d := draftComment{Fingerprint: fp, Path: f.File, Line: loc.StartLine}
+			suggestion := ""
+			if start, end, ok := suggestionRange(f, issue); ok {
+				suggestion = suggestionBlock(platform, issue.FixedCode, end-start)
+				if platform == "github" && end > start {
+					d.StartLine, d.Line = start, end
+				}
+			}
+			d.Body = commentBody(issue, fp, suggestion)
+			drafts = append(drafts, d)
+		}
+	}
+	return drafts
+}
+
+// suggestionRange returns the lines a fix replaces, when it can be offered
+// as a suggestion: the issue has a fix, its location was verified against
+// the file and its lines are all in one hunk of the diff.
+func suggestionRange(f review.FileResult, issue providers.Issue) (start, end int, ok bool) {
+	loc := issue.Location
+	if issue.FixedCode == "" || loc.Unverified {
+		return 0, 0, false
+	}
+	start, end = loc.StartLine, max(loc.EndLine, loc.StartLine)
+	return start, end, f.InDiff(start, end)
+}
+
+// suggestionBlock renders a fix as a suggestion the platform applies with
+// one click. GitHub suggestions replace the lines the comment spans; GitLab
+// comments are single-line, so the block says how many lines below the
+// commented one it replaces.
+func suggestionBlock(platform, fixedCode string, extraLines int) string {
+	fence := "```suggestion"
+	if platform == "gitlab" {
+		fence = fmt.Sprintf("```suggestion:-0+%d", extraLines)
+	}
+	return fence + "\n" + strings.TrimRight(fixedCode, "\n") + "\n```"
+}
+
+// commentBody renders an issue as a review comment, with the fix as a
+// suggestion block when given, or else as a plain code block. The marker
+// lets people and tools tell goreview's comments apart.
+func commentBody(issue providers.Issue, fingerprint, suggestion string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s** [%s] %s\n", strings.ToUpper(string(issue.Severity)), issue.Type, issue.Message))
+	if issue.Suggestion != "" {
+		sb.WriteString("\n" + issue.Suggestion + "\n")
+	}
+	switch {
+	case suggestion != "":
+		sb.WriteString("\n" + suggestion + "\n")
+	case issue.FixedCode != "":
+		sb.WriteString("\n```\n" + strings.TrimRight(issue.FixedCode, "\n") + "\n```\n")
+	}
+	if t := issue.Triage; t != nil && t.Recurrence != "" {
+		sb.WriteString("\n_" + t.Recurrence + "_\n")
+	}
+	sb.WriteString("\n<!-- goreview:" + fingerprint + " -->")
+	return sb.String()
+}
+
+func bodyHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// resolveVCSConfig fills the settings left unset from the GitHub Actions or
+// GitLab CI environment.
+func resolveVCSConfig(cfg config.VCSExportConfig, getenv func(string) string) config.VCSExportConfig {
+	if cfg.Platform == "" {
+		switch {
+		case getenv("GITHUB_ACTIONS") == "true":
+			cfg.Platform = "github"
+		case getenv("GITLAB_CI") == "true":
+			cfg.Platform = "gitlab"
+		}
+	}
+
+	switch cfg.Platform {
+	case "github":
+		setDefault(&cfg.Repo, getenv("GITHUB_REPOSITORY"))
+		setDefault(&cfg.Token, getenv("GITHUB_TOKEN"))
+		setDefault(&cfg.APIURL, getenv("GITHUB_API_URL"))
+		setDefault(&cfg.APIURL, "https://api.github.com")
+		if cfg.Number == 0 {
+			// refs/pull/<number>/merge in pull request workflows
+			if parts := strings.Split(getenv("GITHUB_REF"), "/"); len(parts) == 4 && parts[1] == "pull" {
+				cfg.Number, _ = strconv.Atoi(parts[2])
+			}
+		}
+	case "gitlab":
+		setDefault(&cfg.Repo, getenv("CI_PROJECT_ID"))
+		setDefault(&cfg.Token, getenv("GITLAB_TOKEN"))
+		setDefault(&cfg.APIURL, getenv("CI_API_V4_URL"))
+		setDefault(&cfg.APIURL, "https://gitlab.com/api/v4")
+		if cfg.Number == 0 {
+			cfg.Number, _ = strconv.Atoi(getenv("CI_MERGE_REQUEST_IID"))
+		}
+	}
+	cfg.APIURL = strings.TrimSuffix(cfg.APIURL, "/")
+	return cfg
+}
+
+func setDefault(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+// apiRequest sends a JSON API request and decodes the JSON response into
+// out, when given. Non-2xx responses are errors.
+func apiRequest(ctx context.Context, client *http.Client, method, target string, headers map[string]string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshaling payload: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request to %s: %w", redactURL(target), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if text := strings.TrimSpace(string(msg)); text != "" {
+			return fmt.Errorf("%s returned HTTP %d: %s", redactURL(target), resp.StatusCode, text)
+		}
+		return fmt.Errorf("%s returned HTTP %d", redactURL(target), resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response from %s: %w", redactURL(target), err)
+	}
+	return nil
+}
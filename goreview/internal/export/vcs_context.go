@@ -0,0 +1,180 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// Limits of the request context given to the reviewer
+const (
+	maxContextDescription = 3000
+	maxContextIssueBody   = 1000
+	maxContextIssues      = 3
+	maxContextComment     = 400
+	maxContextComments    = 20
+)
+
+// RequestContext is what a pull or merge request tells about the intent of
+// its change: its title and description, the issues it links and what
+// people said in its review so far.
+type RequestContext struct {
+	Platform    string
+	Number      int
+	Title       string
+	Description string
+	Issues      []LinkedIssue
+	// Comments are the human review comments, oldest first, without
+	// goreview's own and those of bots
+	Comments []RequestComment
+}
+
+// LinkedIssue is an issue a request refers to.
+type LinkedIssue struct {
+	Number int
+	Title  string
+	Body   string
+}
+
+// RequestComment is a human comment on a request.
+type RequestComment struct {
+	Author string
+	// Path and Line locate inline comments
+	Path string
+	Line int
+	Body string
+}
+
+// requestContexts reads the context of requests on a platform.
+type requestContexts interface {
+	// FindRequest returns the number of the open request of a branch, 0
+	// when it has none
+	FindRequest(ctx context.Context, branch string) (int, error)
+	// RequestContext returns the context of a request
+	RequestContext(ctx context.Context, number int) (*RequestContext, error)
+}
+
+// FetchRequestContext returns the context of the pull or merge request
+// configured in export.vcs or, in CI, detected from the environment.
+// Outside a request's CI job, the open request of branch is looked up; nil
+// is returned when the branch has none.
+func FetchRequestContext(ctx context.Context, cfg config.VCSExportConfig, branch string, timeout time.Duration) (*RequestContext, error) {
+	resolved := resolveVCSConfig(cfg, os.Getenv)
+	if resolved.Platform == "" {
+		return nil, fmt.Errorf("vcs platform not set and not detected from the CI environment")
+	}
+	if resolved.Repo == "" {
+		return nil, fmt.Errorf("vcs repository is required outside pull request CI jobs")
+	}
+	if resolved.Token == "" {
+		return nil, fmt.Errorf("vcs token is required (export.vcs.token, GITHUB_TOKEN or GITLAB_TOKEN)")
+	}
+
+	client := &http.Client{Timeout: timeout}
+	var api requestContexts = newGitLabThreads(client, resolved)
+	if resolved.Platform == "github" {
+		api = newGitHubThreads(client, resolved)
+	}
+	return fetchRequestContext(ctx, api, resolved.Number, branch)
+}
+
+// fetchRequestContext returns the context of the request with the number
+// or, without one, of the open request of the branch.
+func fetchRequestContext(ctx context.Context, api requestContexts, number int, branch string) (*RequestContext, error) {
+	if number <= 0 {
+		if branch == "" {
+			return nil, nil
+		}
+		var err error
+		if number, err = api.FindRequest(ctx, branch); err != nil || number == 0 {
+			return nil, err
+		}
+	}
+	return api.RequestContext(ctx, number)
+}
+
+// issueRef matches the issue references of a description, like "#12" or
+// "Fixes #12"
+var issueRef = regexp.MustCompile(`(?:^|[^\w/&])#(\d+)\b`)
+
+// issueRefs returns the issues a description refers to, other than the
+// request itself, at most maxContextIssues.
+func issueRefs(description string, self int) []int {
+	var refs []int
+	for _, m := range issueRef.FindAllStringSubmatch(description, -1) {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n == self || slices.Contains(refs, n) {
+			continue
+		}
+		refs = append(refs, n)
+		if len(refs) == maxContextIssues {
+			break
+		}
+	}
+	return refs
+}
+
+// humanComment reports whether a comment was written by a person, not by
+// goreview or a bot.
+func humanComment(body string, bot bool) bool {
+	return !bot && strings.TrimSpace(body) != "" && !strings.Contains(body, "<!-- goreview:")
+}
+
+// addComment appends a comment, keeping the latest maxContextComments.
+func (c *RequestContext) addComment(comment RequestComment) {
+	c.Comments = append(c.Comments, comment)
+	if len(c.Comments) > maxContextComments {
+		c.Comments = c.Comments[1:]
+	}
+}
+
+// String returns the context as text for the reviewer.
+func (c *RequestContext) String() string {
+	var sb strings.Builder
+	kind := "Pull request"
+	if c.Platform == "gitlab" {
+		kind = "Merge request"
+	}
+	fmt.Fprintf(&sb, "%s #%d: %s\n", kind, c.Number, c.Title)
+	if d := strings.TrimSpace(c.Description); d != "" {
+		fmt.Fprintf(&sb, "\nDescription:\n%s\n", clip(d, maxContextDescription))
+	}
+	for _, issue := range c.Issues {
+		fmt.Fprintf(&sb, "\nLinked issue #%d: %s\n", issue.Number, issue.Title)
+		if b := strings.TrimSpace(issue.Body); b != "" {
+			fmt.Fprintf(&sb, "%s\n", clip(b, maxContextIssueBody))
+		}
+	}
+	if len(c.Comments) > 0 {
+		sb.WriteString("\nReview comments so far:\n")
+		for _, comment := range c.Comments {
+			where := ""
+			if comment.Path != "" {
+				where = fmt.Sprintf(" on %s:%d", comment.Path, comment.Line)
+			}
+			body := strings.Join(strings.Fields(comment.Body), " ")
+			fmt.Fprintf(&sb, "- %s%s: %s\n", comment.Author, where, clip(body, maxContextComment))
+		}
+	}
+	return sb.String()
+}
+
+// clip cuts text to n bytes at a rune boundary, marking the cut.
+func clip(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n] + " [...]"
+}
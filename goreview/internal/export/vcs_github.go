@@ -0,0 +1,279 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/history"
+)
+
+// githubThreads posts review comments on a GitHub pull request. Comments are
+// created and edited with the REST API; threads are resolved with GraphQL,
+// which is the only API exposing them.
+type githubThreads struct {
+	client  *http.Client
+	cfg     config.VCSExportConfig
+	headSHA string
+}
+
+func newGitHubThreads(client *http.Client, cfg config.VCSExportConfig) *githubThreads {
+	return &githubThreads{client: client, cfg: cfg}
+}
+
+func (g *githubThreads) headers() map[string]string {
+	return map[string]string{
+		"Authorization":        "Bearer " + g.cfg.Token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+}
+
+func (g *githubThreads) pullURL() string {
+	return fmt.Sprintf("%s/repos/%s/pulls/%d", g.cfg.APIURL, g.cfg.Repo, g.cfg.Number)
+}
+
+// Create posts a comment on the line, or lines, of the pull request head.
+func (g *githubThreads) Create(ctx context.Context, c draftComment) (string, string, error) {
+	if g.headSHA == "" {
+		var pull struct {
+			Head struct {
+				SHA string `json:"sha"`
+			} `json:"head"`
+		}
+		if err := apiRequest(ctx, g.client, http.MethodGet, g.pullURL(), g.headers(), nil, &pull); err != nil {
+			return "", "", err
+		}
+		g.headSHA = pull.Head.SHA
+	}
+
+	payload := map[string]interface{}{
+		"body":      c.Body,
+		"commit_id": g.headSHA,
+		"path":      c.Path,
+		"line":      c.Line,
+		"side":      "RIGHT",
+	}
+	if c.StartLine > 0 {
+		payload["start_line"] = c.StartLine
+		payload["start_side"] = "RIGHT"
+	}
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	if err := apiRequest(ctx, g.client, http.MethodPost, g.pullURL()+"/comments", g.headers(), payload, &created); err != nil {
+		return "", "", err
+	}
+	// The thread ID is only known to GraphQL; it's looked up when resolving
+	return strconv.FormatInt(created.ID, 10), "", nil
+}
+
+// CommitMessages returns the messages of the pull request's first 250
+// commits, the most the API lists.
+func (g *githubThreads) CommitMessages(ctx context.Context) ([]string, error) {
+	var messages []string
+	for page := 1; page <= 3; page++ {
+		var commits []struct {
+			Commit struct {
+				Message string `json:"message"`
+			} `json:"commit"`
+		}
+		target := fmt.Sprintf("%s/commits?per_page=100&page=%d", g.pullURL(), page)
+		if err := apiRequest(ctx, g.client, http.MethodGet, target, g.headers(), nil, &commits); err != nil {
+			return nil, err
+		}
+		for _, c := range commits {
+			messages = append(messages, c.Commit.Message)
+		}
+		if len(commits) < 100 {
+			break
+		}
+	}
+	return messages, nil
+}
+
+// AddLabels adds labels to the pull request, creating the ones the
+// repository doesn't have.
+func (g *githubThreads) AddLabels(ctx context.Context, labels []string) error {
+	target := fmt.Sprintf("%s/repos/%s/issues/%d/labels", g.cfg.APIURL, g.cfg.Repo, g.cfg.Number)
+	return apiRequest(ctx, g.client, http.MethodPost, target, g.headers(), map[string][]string{"labels": labels}, nil)
+}
+
+// Update edits the body of a comment.
+func (g *githubThreads) Update(ctx context.Context, posted *history.PRComment, body string) error {
+	target := fmt.Sprintf("%s/repos/%s/pulls/comments/%s", g.cfg.APIURL, g.cfg.Repo, posted.CommentID)
+	return apiRequest(ctx, g.client, http.MethodPatch, target, g.headers(), map[string]string{"body": body}, nil)
+}
+
+// Resolve resolves or unresolves the thread of a comment.
+func (g *githubThreads) Resolve(ctx context.Context, posted *history.PRComment, resolved bool) error {
+	if posted.ThreadID == "" {
+		id, err := g.findThread(ctx, posted.CommentID)
+		if err != nil {
+			return err
+		}
+		posted.ThreadID = id
+	}
+
+	mutation := `mutation($id: ID!) { unresolveReviewThread(input: {threadId: $id}) { thread { id } } }`
+	if resolved {
+		mutation = `mutation($id: ID!) { resolveReviewThread(input: {threadId: $id}) { thread { id } } }`
+	}
+	return g.graphQL(ctx, mutation, map[string]interface{}{"id": posted.ThreadID}, nil)
+}
+
+// findThread returns the ID of the review thread starting with a comment.
+func (g *githubThreads) findThread(ctx context.Context, commentID string) (string, error) {
+	const query = `query($owner: String!, $name: String!, $number: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $after) {
+        nodes { id comments(first: 1) { nodes { databaseId } } }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+	owner, name, _ := strings.Cut(g.cfg.Repo, "/")
+	vars := map[string]interface{}{"owner": owner, "name": name, "number": g.cfg.Number}
+	for {
+		var data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							ID       string `json:"id"`
+							Comments struct {
+								Nodes []struct {
+									DatabaseID int64 `json:"databaseId"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		if err := g.graphQL(ctx, query, vars, &data); err != nil {
+			return "", err
+		}
+		threads := data.Repository.PullRequest.ReviewThreads
+		for _, t := range threads.Nodes {
+			if len(t.Comments.Nodes) > 0 && strconv.FormatInt(t.Comments.Nodes[0].DatabaseID, 10) == commentID {
+				return t.ID, nil
+			}
+		}
+		if !threads.PageInfo.HasNextPage {
+			return "", fmt.Errorf("no review thread found for comment %s", commentID)
+		}
+		vars["after"] = threads.PageInfo.EndCursor
+	}
+}
+
+// graphQL runs a GraphQL query, decoding its data into out when given.
+func (g *githubThreads) graphQL(ctx context.Context, query string, vars map[string]interface{}, out interface{}) error {
+	var resp struct {
+		Data   interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp.Data = out
+	payload := map[string]interface{}{"query": query, "variables": vars}
+	if err := apiRequest(ctx, g.client, http.MethodPost, graphQLURL(g.cfg.APIURL), g.headers(), payload, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("graphql: %s", resp.Errors[0].Message)
+	}
+	return nil
+}
+
+// graphQLURL derives the GraphQL endpoint from the REST API URL, which is
+// https://api.github.com on github.com and https://host/api/v3 on GitHub
+// Enterprise Server.
+func graphQLURL(apiURL string) string {
+	if base, ok := strings.CutSuffix(apiURL, "/v3"); ok {
+		return base + "/graphql"
+	}
+	return apiURL + "/graphql"
+}
+
+// FindRequest returns the number of the open pull request of a branch of
+// the repository.
+func (g *githubThreads) FindRequest(ctx context.Context, branch string) (int, error) {
+	owner, _, _ := strings.Cut(g.cfg.Repo, "/")
+	var pulls []struct {
+		Number int `json:"number"`
+	}
+	target := fmt.Sprintf("%s/repos/%s/pulls?state=open&head=%s", g.cfg.APIURL, g.cfg.Repo, url.QueryEscape(owner+":"+branch))
+	if err := apiRequest(ctx, g.client, http.MethodGet, target, g.headers(), nil, &pulls); err != nil {
+		return 0, err
+	}
+	if len(pulls) == 0 {
+		return 0, nil
+	}
+	return pulls[0].Number, nil
+}
+
+// githubUser is the author of a GitHub issue, pull request or comment.
+type githubUser struct {
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+// RequestContext returns the title and description of a pull request, the
+// issues its description refers to and its human comments: the
+// conversation, the reviews and the inline comments, in that order.
+func (g *githubThreads) RequestContext(ctx context.Context, number int) (*RequestContext, error) {
+	repoURL := fmt.Sprintf("%s/repos/%s", g.cfg.APIURL, g.cfg.Repo)
+	var pull struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	if err := apiRequest(ctx, g.client, http.MethodGet, fmt.Sprintf("%s/pulls/%d", repoURL, number), g.headers(), nil, &pull); err != nil {
+		return nil, err
+	}
+	rc := &RequestContext{Platform: "github", Number: number, Title: pull.Title, Description: pull.Body}
+
+	for _, n := range issueRefs(pull.Body, number) {
+		var issue struct {
+			Title string `json:"title"`
+			Body  string `json:"body"`
+		}
+		// References to other pull requests or missing issues are skipped
+		if err := apiRequest(ctx, g.client, http.MethodGet, fmt.Sprintf("%s/issues/%d", repoURL, n), g.headers(), nil, &issue); err == nil {
+			rc.Issues = append(rc.Issues, LinkedIssue{Number: n, Title: issue.Title, Body: issue.Body})
+		}
+	}
+
+	var comments []struct {
+		Body string     `json:"body"`
+		User githubUser `json:"user"`
+		Path string     `json:"path"`
+		Line int        `json:"line"`
+	}
+	for _, endpoint := range []string{
+		fmt.Sprintf("%s/issues/%d/comments", repoURL, number),
+		fmt.Sprintf("%s/pulls/%d/reviews", repoURL, number),
+		fmt.Sprintf("%s/pulls/%d/comments", repoURL, number),
+	} {
+		comments = comments[:0]
+		if err := apiRequest(ctx, g.client, http.MethodGet, endpoint+"?per_page=100", g.headers(), nil, &comments); err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			if humanComment(c.Body, c.User.Type == "Bot") {
+				rc.addComment(RequestComment{Author: c.User.Login, Path: c.Path, Line: c.Line, Body: c.Body})
+			}
+		}
+	}
+	return rc, nil
+}
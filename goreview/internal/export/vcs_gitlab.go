@@ -0,0 +1,200 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/history"
+)
+
+// gitlabThreads posts review comments as diff discussions on a GitLab merge
+// request.
+type gitlabThreads struct {
+	client   *http.Client
+	cfg      config.VCSExportConfig
+	diffRefs *gitlabDiffRefs
+}
+
+type gitlabDiffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	StartSHA string `json:"start_sha"`
+	HeadSHA  string `json:"head_sha"`
+}
+
+func newGitLabThreads(client *http.Client, cfg config.VCSExportConfig) *gitlabThreads {
+	return &gitlabThreads{client: client, cfg: cfg}
+}
+
+func (g *gitlabThreads) headers() map[string]string {
+	return map[string]string{"PRIVATE-TOKEN": g.cfg.Token}
+}
+
+// mrURL accepts both numeric project IDs and paths like group/project.
+func (g *gitlabThreads) mrURL() string {
+	return fmt.Sprintf("%s/projects/%s/merge_requests/%d", g.cfg.APIURL, url.PathEscape(g.cfg.Repo), g.cfg.Number)
+}
+
+// Create starts a discussion on the line of the merge request head.
+func (g *gitlabThreads) Create(ctx context.Context, c draftComment) (string, string, error) {
+	if g.diffRefs == nil {
+		var mr struct {
+			DiffRefs gitlabDiffRefs `json:"diff_refs"`
+		}
+		if err := apiRequest(ctx, g.client, http.MethodGet, g.mrURL(), g.headers(), nil, &mr); err != nil {
+			return "", "", err
+		}
+		g.diffRefs = &mr.DiffRefs
+	}
+
+	payload := map[string]interface{}{
+		"body": c.Body,
+		"position": map[string]interface{}{
+			"position_type": "text",
+			"base_sha":      g.diffRefs.BaseSHA,
+			"start_sha":     g.diffRefs.StartSHA,
+			"head_sha":      g.diffRefs.HeadSHA,
+			"new_path":      c.Path,
+			"new_line":      c.Line,
+		},
+	}
+	var created struct {
+		ID    string `json:"id"`
+		Notes []struct {
+			ID int64 `json:"id"`
+		} `json:"notes"`
+	}
+	if err := apiRequest(ctx, g.client, http.MethodPost, g.mrURL()+"/discussions", g.headers(), payload, &created); err != nil {
+		return "", "", err
+	}
+	if len(created.Notes) == 0 {
+		return "", "", fmt.Errorf("discussion %s created without a note", created.ID)
+	}
+	return strconv.FormatInt(created.Notes[0].ID, 10), created.ID, nil
+}
+
+// CommitMessages returns the messages of the merge request's commits.
+func (g *gitlabThreads) CommitMessages(ctx context.Context) ([]string, error) {
+	var messages []string
+	for page := 1; ; page++ {
+		var commits []struct {
+			Message string `json:"message"`
+		}
+		target := fmt.Sprintf("%s/commits?per_page=100&page=%d", g.mrURL(), page)
+		if err := apiRequest(ctx, g.client, http.MethodGet, target, g.headers(), nil, &commits); err != nil {
+			return nil, err
+		}
+		for _, c := range commits {
+			messages = append(messages, c.Message)
+		}
+		if len(commits) < 100 {
+			return messages, nil
+		}
+	}
+}
+
+// AddLabels adds labels to the merge request, creating the ones the
+// project doesn't have.
+func (g *gitlabThreads) AddLabels(ctx context.Context, labels []string) error {
+	payload := map[string]string{"add_labels": strings.Join(labels, ",")}
+	return apiRequest(ctx, g.client, http.MethodPut, g.mrURL(), g.headers(), payload, nil)
+}
+
+// Update edits the note starting a discussion.
+func (g *gitlabThreads) Update(ctx context.Context, posted *history.PRComment, body string) error {
+	target := fmt.Sprintf("%s/discussions/%s/notes/%s", g.mrURL(), posted.ThreadID, posted.CommentID)
+	return apiRequest(ctx, g.client, http.MethodPut, target, g.headers(), map[string]string{"body": body}, nil)
+}
+
+// Resolve resolves or unresolves a discussion.
+func (g *gitlabThreads) Resolve(ctx context.Context, posted *history.PRComment, resolved bool) error {
+	target := fmt.Sprintf("%s/discussions/%s", g.mrURL(), posted.ThreadID)
+	return apiRequest(ctx, g.client, http.MethodPut, target, g.headers(), map[string]bool{"resolved": resolved}, nil)
+}
+
+// FindRequest returns the IID of the open merge request of a branch of the
+// project.
+func (g *gitlabThreads) FindRequest(ctx context.Context, branch string) (int, error) {
+	var mrs []struct {
+		IID int `json:"iid"`
+	}
+	target := fmt.Sprintf("%s/projects/%s/merge_requests?state=opened&source_branch=%s",
+		g.cfg.APIURL, url.PathEscape(g.cfg.Repo), url.QueryEscape(branch))
+	if err := apiRequest(ctx, g.client, http.MethodGet, target, g.headers(), nil, &mrs); err != nil {
+		return 0, err
+	}
+	if len(mrs) == 0 {
+		return 0, nil
+	}
+	return mrs[0].IID, nil
+}
+
+// RequestContext returns the title and description of a merge request,
+// the issues it closes or its description refers to, and its human notes.
+func (g *gitlabThreads) RequestContext(ctx context.Context, number int) (*RequestContext, error) {
+	projectURL := fmt.Sprintf("%s/projects/%s", g.cfg.APIURL, url.PathEscape(g.cfg.Repo))
+	mrURL := fmt.Sprintf("%s/merge_requests/%d", projectURL, number)
+	var mr struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	if err := apiRequest(ctx, g.client, http.MethodGet, mrURL, g.headers(), nil, &mr); err != nil {
+		return nil, err
+	}
+	rc := &RequestContext{Platform: "gitlab", Number: number, Title: mr.Title, Description: mr.Description}
+
+	type issue struct {
+		IID         int    `json:"iid"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	var closes []issue
+	if err := apiRequest(ctx, g.client, http.MethodGet, mrURL+"/closes_issues", g.headers(), nil, &closes); err != nil {
+		return nil, err
+	}
+	for _, n := range issueRefs(mr.Description, 0) {
+		if !slices.ContainsFunc(closes, func(i issue) bool { return i.IID == n }) {
+			var ref issue
+			if err := apiRequest(ctx, g.client, http.MethodGet, fmt.Sprintf("%s/issues/%d", projectURL, n), g.headers(), nil, &ref); err == nil {
+				closes = append(closes, ref)
+			}
+		}
+	}
+	for _, i := range closes {
+		if len(rc.Issues) < maxContextIssues {
+			rc.Issues = append(rc.Issues, LinkedIssue{Number: i.IID, Title: i.Title, Body: i.Description})
+		}
+	}
+
+	var notes []struct {
+		Body   string `json:"body"`
+		System bool   `json:"system"`
+		Author struct {
+			Username string `json:"username"`
+			Bot      bool   `json:"bot"`
+		} `json:"author"`
+		Position *struct {
+			NewPath string `json:"new_path"`
+			NewLine int    `json:"new_line"`
+		} `json:"position"`
+	}
+	if err := apiRequest(ctx, g.client, http.MethodGet, mrURL+"/notes?sort=asc&per_page=100", g.headers(), nil, &notes); err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		if n.System || !humanComment(n.Body, n.Author.Bot) {
+			continue
+		}
+		comment := RequestComment{Author: n.Author.Username, Body: n.Body}
+		if n.Position != nil {
+			comment.Path, comment.Line = n.Position.NewPath, n.Position.NewLine
+		}
+		rc.addComment(comment)
+	}
+	return rc, nil
+}
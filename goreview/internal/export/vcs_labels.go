@@ -0,0 +1,124 @@
+package export
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/internal/scope"
+)
+
+// requestLabels is the label API of a code hosting platform.
+type requestLabels interface {
+	// CommitMessages returns the messages of the request's commits
+	CommitMessages(ctx context.Context) ([]string, error)
+	// AddLabels adds labels to the request, keeping the ones it has
+	AddLabels(ctx context.Context, labels []string) error
+}
+
+// conventionalSubject matches "type(scope)!: description"
+var conventionalSubject = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?(!)?:\s`)
+
+// commitChangeTypes maps conventional commit types to change types
+var commitChangeTypes = map[string]string{
+	"feat":     "feat",
+	"fix":      "bugfix",
+	"docs":     "docs",
+	"security": "security",
+}
+
+// migrationDirs are directory names holding database migrations
+var migrationDirs = map[string]bool{"migrations": true, "migration": true, "migrate": true}
+
+// changeTypes derives the change types of a request, in the order of
+// config.VCSLabelChangeTypes: feat, bugfix and breaking-change from the
+// conventional commit messages; security from security issues of warning
+// severity or above; migration from files in migration directories; and
+// docs from docs commits or changed documentation files.
+func changeTypes(result *review.Result, messages []string) []string {
+	found := make(map[string]bool)
+	for _, msg := range messages {
+		subject, body, _ := strings.Cut(msg, "\n")
+		if m := conventionalSubject.FindStringSubmatch(strings.TrimSpace(subject)); m != nil {
+			if t, ok := commitChangeTypes[strings.ToLower(m[1])]; ok {
+				found[t] = true
+			}
+			if m[2] != "" {
+				found["breaking-change"] = true
+			}
+		}
+		if strings.Contains(body, "BREAKING CHANGE:") || strings.Contains(body, "BREAKING-CHANGE:") {
+			found["breaking-change"] = true
+		}
+	}
+
+	for _, f := range result.Files {
+		for _, dir := range strings.Split(path.Dir(f.File), "/") {
+			if migrationDirs[strings.ToLower(dir)] {
+				found["migration"] = true
+			}
+		}
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			severity, _ := providers.ParseSeverity(string(issue.Severity))
+			if issue.Type == providers.IssueTypeSecurity && severity.Rank() >= providers.SeverityWarning.Rank() {
+				found["security"] = true
+			}
+		}
+	}
+	if result.Scope != nil {
+		for _, c := range result.Scope.Categories {
+			if c.Category == scope.CategoryDocs {
+				found["docs"] = true
+			}
+		}
+	}
+
+	var types []string
+	for _, t := range config.VCSLabelChangeTypes {
+		if found[t] {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// labelNames maps change types to label names. Types mapped to "" get no
+// label; unmapped types are their own label.
+func labelNames(types []string, mapping map[string]string) []string {
+	var labels []string
+	for _, t := range types {
+		label, ok := mapping[t]
+		if !ok {
+			label = t
+		}
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// applyLabels adds the labels of the request's change types and returns
+// them. Labels are only added: ones applied by earlier runs or by people
+// are kept.
+func applyLabels(ctx context.Context, api requestLabels, cfg config.VCSLabelsConfig, result *review.Result) ([]string, error) {
+	messages, err := api.CommitMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	labels := labelNames(changeTypes(result, messages), cfg.Mapping)
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	if err := api.AddLabels(ctx, labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
@@ -0,0 +1,417 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// fakeThreads records the calls made to a platform
+type fakeThreads struct {
+	created  int
+	updated  []string
+	resolved map[string]bool
+}
+
+func (f *fakeThreads) Create(_ context.Context, c draftComment) (string, string, error) {
+	f.created++
+	return fmt.Sprintf("c%d", f.created), fmt.Sprintf("t%d", f.created), nil
+}
+
+func (f *fakeThreads) Update(_ context.Context, posted *history.PRComment, _ string) error {
+	f.updated = append(f.updated, posted.CommentID)
+	return nil
+}
+
+func (f *fakeThreads) Resolve(_ context.Context, posted *history.PRComment, resolved bool) error {
+	if f.resolved == nil {
+		f.resolved = map[string]bool{}
+	}
+	f.resolved[posted.CommentID] = resolved
+	return nil
+}
+
+func vcsResult(issues map[string][]providers.Issue) *review.Result {
+	result := &review.Result{}
+	for file, list := range issues {
+		result.Files = append(result.Files, review.FileResult{File: file, Response: &providers.ReviewResponse{Issues: list}})
+	}
+	return result
+}
+
+func lineIssue(msg string, line int, sev providers.Severity) providers.Issue {
+	return providers.Issue{
+		Type: providers.IssueTypeBug, Severity: sev, Message: msg,
+		Location: &providers.Location{StartLine: line},
+	}
+}
+
+func TestSyncCommentsIsIdempotent(t *testing.T) {
+	store, err := history.NewStore(history.StoreConfig{Path: filepath.Join(t.TempDir(), "history.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	cfg := config.VCSExportConfig{Platform: "github", Repo: "acme/app", Number: 7}
+	threads := &fakeThreads{}
+
+	first := vcsResult(map[string][]providers.Issue{
+		"a.go": {lineIssue("nil map write", 10, providers.SeverityError), lineIssue("unchecked error", 20, providers.SeverityWarning)},
+		"b.go": {lineIssue("no location", 0, providers.SeverityInfo)},
+	})
+	stats, err := syncComments(ctx, threads, store, cfg, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Created != 2 || threads.created != 2 {
+		t.Fatalf("first run = %+v, want 2 created", stats)
+	}
+
+	// The same issues on moved lines post nothing
+	moved := vcsResult(map[string][]providers.Issue{
+		"a.go": {lineIssue("nil map write", 12, providers.SeverityError), lineIssue("unchecked error", 22, providers.SeverityWarning)},
+	})
+	stats, err = syncComments(ctx, threads, store, cfg, moved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Unchanged != 2 || threads.created != 2 || len(threads.updated) != 0 {
+		t.Fatalf("rerun = %+v, threads = %+v", stats, threads)
+	}
+
+	// A fixed issue is resolved and a changed one updated
+	changed := vcsResult(map[string][]providers.Issue{
+		"a.go": {lineIssue("nil map write", 12, providers.SeverityCritical)},
+	})
+	stats, err = syncComments(ctx, threads, store, cfg, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Updated != 1 || stats.Resolved != 1 || !threads.resolved["c2"] {
+		t.Fatalf("changed run = %+v, threads = %+v", stats, threads)
+	}
+
+	// Reviewing another file leaves a.go's threads alone; finding the fixed
+	// issue again reopens its thread
+	stats, err = syncComments(ctx, threads, store, cfg, vcsResult(map[string][]providers.Issue{"b.go": nil}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Resolved != 0 {
+		t.Fatalf("unrelated run resolved %d threads", stats.Resolved)
+	}
+	stats, err = syncComments(ctx, threads, store, cfg, moved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Reopened != 1 || threads.resolved["c2"] || stats.Created != 0 {
+		t.Fatalf("reopen run = %+v, threads = %+v", stats, threads)
+	}
+
+	comments, err := store.PRComments(ctx, "github", "acme/app", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 {
+		t.Errorf("stored %d comments, want 2", len(comments))
+	}
+}
+
+func TestDraftCommentSuggestions(t *testing.T) {
+	fix := func(msg string, start, end int, unverified bool) providers.Issue {
+		issue := lineIssue(msg, start, providers.SeverityWarning)
+		issue.Location.EndLine = end
+		issue.Location.Unverified = unverified
+		issue.FixedCode = "return nil\n"
+		return issue
+	}
+	result := &review.Result{Files: []review.FileResult{{
+		File:  "main.go",
+		Hunks: []review.LineRange{{Start: 10, End: 20}, {Start: 40, End: 45}},
+		Response: &providers.ReviewResponse{Issues: []providers.Issue{
+			fix("multi", 12, 14, false),
+			fix("single", 41, 0, false),
+			fix("unverified", 15, 16, true),
+			fix("spans hunks", 19, 41, false),
+			fix("outside", 30, 31, false),
+		}},
+	}}}
+
+	drafts := draftComments(result, "github")
+	var got []string
+	for _, d := range drafts {
+		got = append(got, fmt.Sprintf("%d-%d %t", d.StartLine, d.Line, strings.Contains(d.Body, "```suggestion\nreturn nil\n```")))
+	}
+	want := "12-14 true|0-41 true|0-15 false|0-19 false"
+	if strings.Join(got, "|") != want {
+		t.Errorf("github drafts = %q, want %q", strings.Join(got, "|"), want)
+	}
+	if !strings.Contains(drafts[2].Body, "```\nreturn nil\n```") {
+		t.Errorf("unverified fix body = %q, want plain code block", drafts[2].Body)
+	}
+
+	drafts = draftComments(result, "gitlab")
+	if d := drafts[0]; d.Line != 12 || d.StartLine != 0 || !strings.Contains(d.Body, "```suggestion:-0+2\n") {
+		t.Errorf("gitlab draft = %+v, want line 12 with a 3-line suggestion", d)
+	}
+}
+
+func TestResolveVCSConfig(t *testing.T) {
+	env := map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REPOSITORY": "acme/app",
+		"GITHUB_REF":        "refs/pull/42/merge",
+		"GITHUB_TOKEN":      "ghs_x",
+	}
+	cfg := resolveVCSConfig(config.VCSExportConfig{}, func(k string) string { return env[k] })
+	if cfg.Platform != "github" || cfg.Repo != "acme/app" || cfg.Number != 42 || cfg.APIURL != "https://api.github.com" {
+		t.Errorf("github config = %+v", cfg)
+	}
+
+	env = map[string]string{"GITLAB_CI": "true", "CI_PROJECT_ID": "99", "CI_MERGE_REQUEST_IID": "5"}
+	cfg = resolveVCSConfig(config.VCSExportConfig{Token: "glpat", APIURL: "https://git.example.com/api/v4/"},
+		func(k string) string { return env[k] })
+	if cfg.Platform != "gitlab" || cfg.Repo != "99" || cfg.Number != 5 || cfg.APIURL != "https://git.example.com/api/v4" {
+		t.Errorf("gitlab config = %+v", cfg)
+	}
+
+	if graphQLURL("https://ghe.example.com/api/v3") != "https://ghe.example.com/api/graphql" {
+		t.Errorf("enterprise graphql url = %s", graphQLURL("https://ghe.example.com/api/v3"))
+	}
+}
+
+func TestGitLabThreads(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath())
+		if r.Header.Get("PRIVATE-TOKEN") != "glpat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/commits"):
+			_, _ = w.Write([]byte(`[{"message":"feat: add export"}]`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"diff_refs":{"base_sha":"b","start_sha":"s","head_sha":"h"}}`))
+		case r.Method == http.MethodPost:
+			var payload struct {
+				Position map[string]interface{} `json:"position"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			if payload.Position["head_sha"] != "h" || payload.Position["new_path"] != "a.go" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"d1","notes":[{"id":11}]}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	g := newGitLabThreads(srv.Client(), config.VCSExportConfig{APIURL: srv.URL, Repo: "group/app", Number: 3, Token: "glpat"})
+	ctx := context.Background()
+	commentID, threadID, err := g.Create(ctx, draftComment{Path: "a.go", Line: 4, Body: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commentID != "11" || threadID != "d1" {
+		t.Fatalf("Create = %s, %s", commentID, threadID)
+	}
+	posted := &history.PRComment{CommentID: commentID, ThreadID: threadID}
+	if err := g.Update(ctx, posted, "y"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Resolve(ctx, posted, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.CommitMessages(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddLabels(ctx, []string{"bug", "docs"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"GET /projects/group%2Fapp/merge_requests/3",
+		"POST /projects/group%2Fapp/merge_requests/3/discussions",
+		"PUT /projects/group%2Fapp/merge_requests/3/discussions/d1/notes/11",
+		"PUT /projects/group%2Fapp/merge_requests/3/discussions/d1",
+		"GET /projects/group%2Fapp/merge_requests/3/commits",
+		"PUT /projects/group%2Fapp/merge_requests/3",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls =\n%s\nwant\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestChangeTypes(t *testing.T) {
+	result := vcsResult(map[string][]providers.Issue{
+		"db/migrations/0042_users.sql": nil,
+		"auth/login.go": {
+			{Type: providers.IssueTypeSecurity, Severity: "WARNING", Message: "weak hash"},
+		},
+	})
+	messages := []string{
+		"feat(auth)!: require MFA",
+		"fix: handle empty password\n\nBREAKING CHANGE: logins without MFA fail",
+		"chore: bump deps",
+		"Merge branch 'main'",
+	}
+	got := changeTypes(result, messages)
+	want := []string{"feat", "bugfix", "security", "migration", "breaking-change"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("changeTypes = %v, want %v", got, want)
+	}
+
+	// Info security issues and non-conventional commits add nothing
+	result = vcsResult(map[string][]providers.Issue{
+		"api.go": {{Type: providers.IssueTypeSecurity, Severity: providers.SeverityInfo}},
+	})
+	if got := changeTypes(result, []string{"Add feature"}); len(got) != 0 {
+		t.Errorf("changeTypes = %v, want none", got)
+	}
+
+	labels := labelNames([]string{"feat", "bugfix", "docs"}, map[string]string{"feat": "enhancement", "docs": ""})
+	if strings.Join(labels, ",") != "enhancement,bugfix" {
+		t.Errorf("labelNames = %v", labels)
+	}
+}
+
+func TestGitHubLabels(t *testing.T) {
+	var calls []string
+	var added []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.RequestURI())
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[{"commit":{"message":"fix(db): close rows"}},{"commit":{"message":"docs: explain pooling"}}]`))
+		case http.MethodPost:
+			var payload struct {
+				Labels []string `json:"labels"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			added = payload.Labels
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+
+	g := newGitHubThreads(srv.Client(), config.VCSExportConfig{APIURL: srv.URL, Repo: "acme/app", Number: 9, Token: "ghs"})
+	cfg := config.VCSLabelsConfig{Enabled: true, Mapping: map[string]string{"bugfix": "bug"}}
+	labels, err := applyLabels(context.Background(), g, cfg, vcsResult(map[string][]providers.Issue{"db/pool.go": nil}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(labels, ",") != "bug,docs" || strings.Join(added, ",") != "bug,docs" {
+		t.Errorf("labels = %v, added = %v", labels, added)
+	}
+	want := []string{
+		"GET /repos/acme/app/pulls/9/commits?per_page=100&page=1",
+		"POST /repos/acme/app/issues/9/labels",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls =\n%s\nwant\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestGitHubRequestContext(t *testing.T) {
+	responses := map[string]string{
+		"/repos/acme/app/pulls?state=open&head=acme%3Afeature%2Fpool": `[{"number":9}]`,
+		"/repos/acme/app/pulls?state=open&head=acme%3Amain":           `[]`,
+		"/repos/acme/app/pulls/9":                                     `{"title":"Pool connections","body":"Fixes #4, see #9 and #4.\nKeeps idle connections open on purpose."}`,
+		"/repos/acme/app/issues/4":                                    `{"title":"Too many connections","body":"Each query opens a connection."}`,
+		"/repos/acme/app/issues/9/comments?per_page=100": `[{"body":"Why 10 idle?","user":{"login":"ana","type":"User"}},` +
+			`{"body":"<!-- goreview:summary -->","user":{"login":"ana","type":"User"}},{"body":"coverage 80%","user":{"login":"ci","type":"Bot"}}]`,
+		"/repos/acme/app/pulls/9/reviews?per_page=100":  `[{"body":"","user":{"login":"bo","type":"User"}}]`,
+		"/repos/acme/app/pulls/9/comments?per_page=100": `[{"body":"Close on\nshutdown","path":"db/pool.go","line":12,"user":{"login":"bo","type":"User"}}]`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	g := newGitHubThreads(srv.Client(), config.VCSExportConfig{APIURL: srv.URL, Repo: "acme/app", Token: "ghs"})
+	rc, err := fetchRequestContext(context.Background(), g, 0, "feature/pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Pull request #9: Pool connections
+
+Description:
+Fixes #4, see #9 and #4.
+Keeps idle connections open on purpose.
+
+Linked issue #4: Too many connections
+Each query opens a connection.
+
+Review comments so far:
+- ana: Why 10 idle?
+- bo on db/pool.go:12: Close on shutdown
+`
+	if rc == nil || rc.String() != want {
+		t.Errorf("context =\n%v\nwant\n%s", rc, want)
+	}
+
+	// Branches without an open pull request have no context
+	if rc, err := fetchRequestContext(context.Background(), g, 0, "main"); err != nil || rc != nil {
+		t.Errorf("fetchRequestContext(main) = %v, %v; want nil", rc, err)
+	}
+}
+
+func TestGitLabRequestContext(t *testing.T) {
+	responses := map[string]string{
+		"/projects/group%2Fapp/merge_requests?state=opened&source_branch=fix-login": `[{"iid":3}]`,
+		"/projects/group%2Fapp/merge_requests/3":                                    `{"title":"Fix login","description":"Closes #5. Related to #6."}`,
+		"/projects/group%2Fapp/merge_requests/3/closes_issues":                      `[{"iid":5,"title":"Login fails","description":"With SSO."}]`,
+		"/projects/group%2Fapp/issues/6":                                            `{"iid":6,"title":"Audit logins","description":""}`,
+		"/projects/group%2Fapp/merge_requests/3/notes?sort=asc&per_page=100": `[{"body":"added 1 commit","system":true,"author":{"username":"ana"}},` +
+			`{"body":"Retry once?","author":{"username":"ana"},"position":{"new_path":"auth/sso.go","new_line":30}},` +
+			`{"body":"lgtm","author":{"username":"renovate","bot":true}}]`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	g := newGitLabThreads(srv.Client(), config.VCSExportConfig{APIURL: srv.URL, Repo: "group/app", Token: "glpat"})
+	rc, err := fetchRequestContext(context.Background(), g, 0, "fix-login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Number != 3 || len(rc.Issues) != 2 || rc.Issues[0].Number != 5 || rc.Issues[1].Number != 6 {
+		t.Fatalf("context = %+v", rc)
+	}
+	if len(rc.Comments) != 1 || rc.Comments[0] != (RequestComment{Author: "ana", Path: "auth/sso.go", Line: 30, Body: "Retry once?"}) {
+		t.Errorf("comments = %+v, want ana's inline note only", rc.Comments)
+	}
+	if !strings.HasPrefix(rc.String(), "Merge request #3: Fix login\n") {
+		t.Errorf("String() = %q", rc.String())
+	}
+
+	if got := clip("añadir", 2); got != "a [...]" {
+		t.Errorf("clip = %q, want the cut at a rune boundary", got)
+	}
+}
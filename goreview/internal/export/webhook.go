@@ -0,0 +1,98 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+// WebhookEvent is the event name sent in webhook payloads
+const WebhookEvent = "review.completed"
+
+// WebhookExporter posts the full review result as JSON, in the public result
+// schema, to an HTTP endpoint.
+type WebhookExporter struct {
+	cfg    *config.WebhookExportConfig
+	client *http.Client
+}
+
+// webhookPayload is the JSON body sent to the webhook.
+type webhookPayload struct {
+	Event    string              `json:"event"`
+	Metadata *Metadata           `json:"metadata"`
+	Result   *reviewtypes.Result `json:"result"`
+}
+
+// NewWebhookExporter creates a new webhook exporter.
+func NewWebhookExporter(cfg *config.WebhookExportConfig, timeout time.Duration) (*WebhookExporter, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	return &WebhookExporter{cfg: cfg, client: &http.Client{Timeout: timeout}}, nil
+}
+
+// Name returns the exporter name.
+func (e *WebhookExporter) Name() string {
+	return "webhook"
+}
+
+// Export posts the review result to the webhook.
+func (e *WebhookExporter) Export(result *review.Result, metadata *Metadata) error {
+	payload := &webhookPayload{Event: WebhookEvent, Metadata: metadata, Result: result.Public()}
+	return postJSON(e.client, e.cfg.URL, e.cfg.Headers, payload)
+}
+
+// Target returns the webhook URL without query string or credentials.
+func (e *WebhookExporter) Target() string {
+	return redactURL(e.cfg.URL)
+}
+
+// postJSON posts a JSON payload and fails on non-2xx responses.
+func postJSON(client *http.Client, target string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request to %s: %w", redactURL(target), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if text := strings.TrimSpace(string(msg)); text != "" {
+			return fmt.Errorf("%s returned HTTP %d: %s", redactURL(target), resp.StatusCode, text)
+		}
+		return fmt.Errorf("%s returned HTTP %d", redactURL(target), resp.StatusCode)
+	}
+	return nil
+}
+
+// redactURL strips credentials and the query string, which often carry tokens.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "webhook"
+	}
+	return u.Scheme + "://" + u.Host + u.Path
+}
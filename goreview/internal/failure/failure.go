@@ -0,0 +1,163 @@
+// Package failure classifies goreview's errors by what failed, with stable
+// codes that wrappers and CI can branch on instead of matching error text.
+//
+// Errors are coded where they happen, by wrapping them in one of the typed
+// errors: ConfigError, ProviderError, GitError or ParseError. Wrapping them
+// further with %w keeps the code. Codes are part of the JSON report and
+// don't change between releases; new ones may be added.
+package failure
+
+import (
+	"errors"
+	"strings"
+)
+
+// Code identifies a kind of failure, as "category.reason".
+type Code string
+
+// Category returns the part of the code before the dot.
+func (c Code) Category() string {
+	category, _, _ := strings.Cut(string(c), ".")
+	return category
+}
+
+const (
+	// ConfigInvalid is a configuration value that failed validation
+	ConfigInvalid Code = "config.invalid"
+	// ConfigUnreadable is a config file that couldn't be read or parsed
+	ConfigUnreadable Code = "config.unreadable"
+	// ConfigUnknownKey is a config file with unknown keys, with --strict-config
+	ConfigUnknownKey Code = "config.unknown_key"
+
+	// ProviderNotConfigured is a provider that couldn't be created, like
+	// one without its API key
+	ProviderNotConfigured Code = "provider.not_configured"
+	// ProviderAuth is a provider rejecting the credentials
+	ProviderAuth Code = "provider.auth"
+	// ProviderRateLimited is a provider throttling the requests
+	ProviderRateLimited Code = "provider.rate_limited"
+	// ProviderUnavailable is a provider that can't be reached or answers
+	// with a server error
+	ProviderUnavailable Code = "provider.unavailable"
+	// ProviderTimeout is a provider call that ran out of time
+	ProviderTimeout Code = "provider.timeout"
+	// ProviderRejected is a request the provider refused, other than for
+	// credentials or throttling
+	ProviderRejected Code = "provider.rejected"
+	// ProviderBlocked is a request the provider's safety filters refused to
+	// answer
+	ProviderBlocked Code = "provider.blocked"
+	// ProviderFailed is any other failed provider call
+	ProviderFailed Code = "provider.failed"
+
+	// GitNotRepository is a path outside a git repository
+	GitNotRepository Code = "git.not_a_repository"
+	// GitUnknownRevision is a commit, branch or range git can't resolve
+	GitUnknownRevision Code = "git.unknown_revision"
+	// GitNoWorktree is a bare repository asked for staged or file diffs
+	GitNoWorktree Code = "git.no_worktree"
+	// GitFailed is any other failed git command
+	GitFailed Code = "git.failed"
+
+	// ParseResponse is a provider answer that wasn't valid JSON
+	ParseResponse Code = "parse.response"
+	// ParseDiff is a diff or patch that couldn't be parsed
+	ParseDiff Code = "parse.diff"
+	// ParseReport is a JSON report that couldn't be decoded
+	ParseReport Code = "parse.report"
+
+	// Unclassified is an error without a code
+	Unclassified Code = "unclassified"
+)
+
+// ConfigError is an error in the configuration.
+type ConfigError struct {
+	Code Code
+	// Field is the config key at fault, if known
+	Field string
+	Err   error
+}
+
+func (e *ConfigError) Error() string   { return e.Err.Error() }
+func (e *ConfigError) Unwrap() error   { return e.Err }
+func (e *ConfigError) ErrorCode() Code { return e.Code }
+
+// ProviderError is a failed call to an AI provider.
+type ProviderError struct {
+	Code     Code
+	Provider string
+	// StatusCode is the HTTP status of the answer, 0 without one
+	StatusCode int
+	Err        error
+}
+
+func (e *ProviderError) Error() string   { return e.Err.Error() }
+func (e *ProviderError) Unwrap() error   { return e.Err }
+func (e *ProviderError) ErrorCode() Code { return e.Code }
+
+// GitError is a failed git operation.
+type GitError struct {
+	Code Code
+	// Op is the git subcommand, like "diff"
+	Op  string
+	Err error
+}
+
+func (e *GitError) Error() string   { return e.Err.Error() }
+func (e *GitError) Unwrap() error   { return e.Err }
+func (e *GitError) ErrorCode() Code { return e.Code }
+
+// ParseError is input that couldn't be parsed.
+type ParseError struct {
+	Code Code
+	// File is the file the input is about, if any
+	File string
+	Err  error
+}
+
+func (e *ParseError) Error() string   { return e.Err.Error() }
+func (e *ParseError) Unwrap() error   { return e.Err }
+func (e *ParseError) ErrorCode() Code { return e.Code }
+
+// CodeOf returns the code of the first typed error in err's chain, or
+// Unclassified.
+func CodeOf(err error) Code {
+	var coded interface{ ErrorCode() Code }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return Unclassified
+}
+
+// Info is the machine-readable form of an error, as written in the errors
+// array of the JSON report.
+type Info struct {
+	Code     string `json:"code"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"`
+}
+
+// Describe returns the machine-readable form of an error about a file,
+// or about the whole run when file is "".
+func Describe(err error, file string) Info {
+	code := CodeOf(err)
+	return Info{Code: string(code), Category: code.Category(), Message: err.Error(), File: file}
+}
+
+// FromCode rebuilds a typed error from its code and message, as read back
+// from a report.
+func FromCode(code Code, message string) error {
+	err := errors.New(message)
+	switch code.Category() {
+	case "config":
+		return &ConfigError{Code: code, Err: err}
+	case "provider":
+		return &ProviderError{Code: code, Err: err}
+	case "git":
+		return &GitError{Code: code, Err: err}
+	case "parse":
+		return &ParseError{Code: code, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"config", &ConfigError{Code: ConfigInvalid, Field: "review.mode", Err: errors.New("bad mode")}, ConfigInvalid},
+		{"wrapped provider", fmt.Errorf("review failed for a.go: %w", &ProviderError{Code: ProviderRateLimited, Err: errors.New("HTTP 429")}), ProviderRateLimited},
+		{"git", &GitError{Code: GitNotRepository, Op: "rev-parse", Err: errors.New("not a git repository")}, GitNotRepository},
+		{"parse", &ParseError{Code: ParseDiff, Err: errors.New("bad hunk")}, ParseDiff},
+		{"untyped", errors.New("boom"), Unclassified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	err := fmt.Errorf("chunk 1/2: %w", &ProviderError{Code: ProviderAuth, Provider: "openai", StatusCode: 401, Err: errors.New("HTTP 401 Unauthorized")})
+	got := Describe(err, "a.go")
+	want := Info{Code: "provider.auth", Category: "provider", Message: "chunk 1/2: HTTP 401 Unauthorized", File: "a.go"}
+	if got != want {
+		t.Errorf("Describe() = %+v, want %+v", got, want)
+	}
+
+	// Read back from a report, the error keeps its code and message
+	back := FromCode(Code(got.Code), got.Message)
+	if CodeOf(back) != ProviderAuth || back.Error() != got.Message {
+		t.Errorf("FromCode() = %v (%s)", back, CodeOf(back))
+	}
+	if got := Describe(errors.New("boom"), ""); got.Category != "unclassified" {
+		t.Errorf("Describe() of an untyped error = %+v", got)
+	}
+}
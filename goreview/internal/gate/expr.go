@@ -0,0 +1,72 @@
+package gate
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/parser"
+)
+
+// Program is a compiled gate expression.
+type Program struct {
+	source  string
+	program cel.Program
+}
+
+// variables are the variables gates see, see Env. Fields are looked up at
+// evaluation, so a missing one fails the gate rather than its compilation.
+var variables = []cel.EnvOption{
+	cel.Variable("issues", cel.MapType(cel.StringType, cel.DynType)),
+	cel.Variable("findings", cel.ListType(cel.DynType)),
+	cel.Variable("files", cel.ListType(cel.DynType)),
+	cel.Variable("stats", cel.MapType(cel.StringType, cel.DynType)),
+	cel.Variable("quality", cel.MapType(cel.StringType, cel.DynType)),
+	cel.Variable("scope", cel.MapType(cel.StringType, cel.DynType)),
+}
+
+// env is the CEL environment of gate expressions: the standard library,
+// the variables, cross-type numeric comparisons so "f.score > 60.5" works
+// on integer scores, and every() as an alias of all().
+var env = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(append(variables,
+		cel.CrossTypeNumericComparisons(true),
+		cel.Macros(cel.ReceiverMacro("every", 2, parser.MakeAll)),
+	)...)
+})
+
+// Compile parses and type-checks a gate expression.
+func Compile(source string) (*Program, error) {
+	e, err := env()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := e.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if t := ast.OutputType(); t != cel.BoolType && t != cel.DynType {
+		return nil, fmt.Errorf("expression yields %s, not bool", t)
+	}
+	program, err := e.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{source: source, program: program}, nil
+}
+
+// String returns the expression's source.
+func (p *Program) String() string { return p.source }
+
+// Eval evaluates the expression with the variables. It must yield a bool.
+func (p *Program) Eval(vars map[string]any) (bool, error) {
+	out, _, err := p.program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression yields %s, not bool", out.Type())
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package gate
+
+import (
+	"strings"
+	"testing"
+)
+
+// testVars are gate variables as Env returns them.
+func testVars() map[string]any {
+	return map[string]any{
+		"issues":   map[string]any{"total": int64(3), "critical": int64(0), "error": int64(1)},
+		"findings": []any{map[string]any{"file": "auth/login.go", "type": "security", "line": int64(12)}},
+		"files": []any{
+			map[string]any{"path": "a.go", "score": int64(80)},
+			map[string]any{"path": "b.go", "score": int64(65)},
+		},
+		"stats":   map[string]any{"files": int64(2)},
+		"quality": map[string]any{"score": int64(90), "degraded": false},
+		"scope":   map[string]any{"categories": []any{"source", "ci"}},
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"issues.total == 3 && issues.critical != 1", true},
+		{"!(issues.total < 3) && issues.total <= 3 && issues.total >= 3", true},
+		{"issues.total * 2 + 1 == 7 && issues.total / 2 == 1 && issues.total % 2 == 1", true},
+		{"quality.score > 89.5 && quality.score < 90.5", true}, // Cross-type comparisons
+		{"issues.total > 5 ? false : true", true},
+		{`findings[0].file.startsWith("auth/") && findings[0].file.endsWith('.go') && findings[0].file.contains("login")`, true},
+		{`findings[0].file.matches("^auth/.*\\.go$")`, true},
+		{`"ci" in scope.categories && !("docs" in scope.categories) && "total" in issues`, true},
+		{"size(files) == 2 && files.size() == 2 && issues['error'] == 1", true},
+		{"files.all(f, f.score > 60) && files.every(f, f.score > 60)", true},
+		{"files.exists(f, f.score < 70) && files.exists_one(f, f.score > 70)", true},
+		{"files.filter(f, f.score > 70).map(f, f.path) == ['a.go']", true},
+		{"files.all(f, f.score > 70)", false},
+		{"!quality.degraded && has(quality.score) && !has(quality.missing)", true},
+	}
+	for _, tt := range tests {
+		program, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%q) error = %v", tt.expr, err)
+			continue
+		}
+		got, err := program.Eval(testVars())
+		if err != nil {
+			t.Errorf("Eval(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{"", "issues ==", "(issues", "issues # 2", "'open", "files.all(1, true)", "missing == 1", "size(files)", "'a' + 1 == 'a1'"} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"issues.fatal == 1", "no such key"},
+		{"issues.total", "not bool"},
+		{"issues.total / 0 == 1", "division by zero"},
+		{"issues.total < 'x'", "no such overload"},
+	}
+	for _, tt := range tests {
+		program, err := Compile(tt.expr)
+		if err != nil {
+			t.Fatalf("Compile(%q) error = %v", tt.expr, err)
+		}
+		if _, err := program.Eval(testVars()); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Eval(%q) error = %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
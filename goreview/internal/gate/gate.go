@@ -0,0 +1,179 @@
+// Package gate evaluates CI gate policies against a review result. Gates
+// are boolean CEL (Common Expression Language) expressions, evaluated with
+// cel-go, such as
+//
+//	issues.critical == 0 && files.all(f, f.score > 60)
+//
+// Counts, scores and lines are integers. Besides the CEL standard library,
+// every() is an alias of the all() macro.
+package gate
+
+import (
+	"fmt"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	changescope "github.com/JNZader/goreview/goreview/internal/scope"
+)
+
+// Gate is a compiled gate.
+type Gate struct {
+	Name    string
+	program *Program
+}
+
+// CompileAll compiles the configured gates, failing on the first invalid
+// expression.
+func CompileAll(gates []config.GateConfig) ([]Gate, error) {
+	compiled := make([]Gate, 0, len(gates))
+	for _, g := range gates {
+		program, err := Compile(g.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("gate %s: %w", g.Name, err)
+		}
+		compiled = append(compiled, Gate{Name: g.Name, program: program})
+	}
+	return compiled, nil
+}
+
+// Evaluate evaluates the gates against the result. A gate whose expression
+// fails to evaluate fails, with the error recorded.
+func Evaluate(gates []Gate, result *review.Result) []review.GateResult {
+	vars := Env(result)
+	results := make([]review.GateResult, 0, len(gates))
+	for _, g := range gates {
+		passed, err := g.program.Eval(vars)
+		r := review.GateResult{Name: g.Name, Expr: g.program.String(), Passed: passed && err == nil}
+		if err != nil {
+			r.Error = err.Error()
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Env returns the variables gates see for a review result:
+//
+//	issues    issue counts: total, critical, error, warning, info
+//	findings  every issue: file, severity, type, rule, line, message
+//	files     every file: path, score, error, cached, protected,
+//	          generated (block count), issues (counts) and findings
+//	stats     files, additions, deletions
+//	quality   score, degraded
+//	scope     unrelated (file category count), categories (names),
+//	          formatting and renames (hunk counts)
+func Env(result *review.Result) map[string]any {
+	total := newCounts()
+	var files, findings []any
+	for _, f := range result.Files {
+		counts := newCounts()
+		var fileFindings []any
+		score := 0
+		if f.Response != nil {
+			score = f.Response.Score
+			for _, issue := range f.Response.Issues {
+				finding := findingVars(f.File, issue)
+				fileFindings = append(fileFindings, finding)
+				findings = append(findings, finding)
+				counts.add(issue.Severity)
+				total.add(issue.Severity)
+			}
+		}
+		files = append(files, map[string]any{
+			"path":      f.File,
+			"score":     int64(score),
+			"error":     f.Error != nil,
+			"cached":    f.Cached,
+			"protected": f.Protected,
+			"generated": int64(len(f.Generated)),
+			"issues":    counts.vars(),
+			"findings":  list(fileFindings),
+		})
+	}
+
+	quality := map[string]any{"score": int64(100), "degraded": false}
+	if q := result.Quality; q != nil {
+		quality = map[string]any{"score": int64(q.Score), "degraded": q.Degraded}
+	}
+	return map[string]any{
+		"issues":   total.vars(),
+		"findings": list(findings),
+		"files":    list(files),
+		"scope":    scopeVars(result.Scope),
+		"stats": map[string]any{
+			"files":     int64(result.Stats.FilesChanged),
+			"additions": int64(result.Stats.Additions),
+			"deletions": int64(result.Stats.Deletions),
+		},
+		"quality": quality,
+	}
+}
+
+func scopeVars(report *changescope.Report) map[string]any {
+	vars := map[string]any{"unrelated": int64(0), "categories": []any{}, "formatting": int64(0), "renames": int64(0)}
+	if report == nil {
+		return vars
+	}
+	var categories []any
+	for _, c := range report.Categories {
+		categories = append(categories, string(c.Category))
+	}
+	formatting, renames := 0, 0
+	for _, h := range report.Hunks {
+		if h.Kind == changescope.HunkFormatting {
+			formatting++
+		} else {
+			renames++
+		}
+	}
+	vars["unrelated"] = int64(report.Unrelated)
+	vars["categories"] = list(categories)
+	vars["formatting"] = int64(formatting)
+	vars["renames"] = int64(renames)
+	return vars
+}
+
+func findingVars(file string, issue providers.Issue) map[string]any {
+	line := 0
+	if issue.Location != nil {
+		line = issue.Location.StartLine
+	}
+	severity, _ := providers.ParseSeverity(string(issue.Severity))
+	return map[string]any{
+		"file":     file,
+		"severity": string(severity),
+		"type":     string(issue.Type),
+		"rule":     issue.RuleID,
+		"line":     int64(line),
+		"message":  issue.Message,
+	}
+}
+
+// counts counts issues by severity.
+type counts map[providers.Severity]int
+
+func newCounts() counts { return make(counts) }
+
+func (c counts) add(s providers.Severity) {
+	severity, _ := providers.ParseSeverity(string(s))
+	c[severity]++
+	c["total"]++
+}
+
+func (c counts) vars() map[string]any {
+	return map[string]any{
+		"total":    int64(c["total"]),
+		"critical": int64(c[providers.SeverityCritical]),
+		"error":    int64(c[providers.SeverityError]),
+		"warning":  int64(c[providers.SeverityWarning]),
+		"info":     int64(c[providers.SeverityInfo]),
+	}
+}
+
+func list(values []any) []any {
+	if values == nil {
+		return []any{}
+	}
+	return values
+}
@@ -0,0 +1,53 @@
+package gate
+
+import (
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+	changescope "github.com/JNZader/goreview/goreview/internal/scope"
+)
+
+func TestEvaluate(t *testing.T) {
+	result := &review.Result{Files: []review.FileResult{
+		{File: "auth/login.go", Protected: "auth", Response: &providers.ReviewResponse{Score: 55, Issues: []providers.Issue{
+			{Severity: providers.SeverityWarning, Type: providers.IssueTypeSecurity, Location: &providers.Location{StartLine: 12}},
+			{Severity: "ERROR", Type: providers.IssueTypeBug},
+		}}},
+		{File: "api/handler.go", Response: &providers.ReviewResponse{Score: 90}},
+	}, Scope: &changescope.Report{Unrelated: 1, Categories: []changescope.CategoryChange{
+		{Category: changescope.CategorySource}, {Category: changescope.CategoryCI, Unrelated: true},
+	}}}
+
+	gates, err := CompileAll([]config.GateConfig{
+		{Name: "no-critical", Expr: "issues.critical == 0"},
+		{Name: "scores", Expr: "files.all(f, f.score > 60)"},
+		{Name: "protected", Expr: "files.filter(f, f.protected != '').all(f, f.issues.error == 0)"},
+		{Name: "security", Expr: "!findings.exists(i, i.type == 'security' && i.line > 0)"},
+		{Name: "broken", Expr: "issues.fatal == 0"},
+		{Name: "scope", Expr: "scope.unrelated == 0 || !('ci' in scope.categories)"},
+	})
+	if err != nil {
+		t.Fatalf("CompileAll() error = %v", err)
+	}
+	results := Evaluate(gates, result)
+
+	want := map[string]bool{"no-critical": true, "scores": false, "protected": false, "security": false, "broken": false, "scope": false}
+	for _, r := range results {
+		if r.Passed != want[r.Name] {
+			t.Errorf("gate %s passed = %v, want %v (error %q)", r.Name, r.Passed, want[r.Name], r.Error)
+		}
+	}
+	if results[4].Error == "" {
+		t.Error("gate broken has no error")
+	}
+	result.Gates = results
+	if failed := result.GatesFailed(); len(failed) != 5 {
+		t.Errorf("GatesFailed() = %v", failed)
+	}
+
+	if _, err := CompileAll([]config.GateConfig{{Name: "bad", Expr: "issues.critical =="}}); err == nil {
+		t.Error("CompileAll() accepted an invalid expression")
+	}
+}
@@ -0,0 +1,290 @@
+package git
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// languageUnknown is returned when no language could be detected
+const languageUnknown = "unknown"
+
+// maxDetectLines limits how much content is inspected by the heuristics
+const maxDetectLines = 200
+
+// filenameToLanguage maps well-known extensionless file names to languages
+var filenameToLanguage = map[string]string{
+	"dockerfile":  "dockerfile",
+	"makefile":    "makefile",
+	"gnumakefile": "makefile",
+	"gemfile":     "ruby",
+	"rakefile":    "ruby",
+	"podfile":     "ruby",
+	"vagrantfile": "ruby",
+	"jenkinsfile": "groovy",
+	"justfile":    "makefile",
+	".bashrc":     "shell",
+	".zshrc":      "shell",
+	".profile":    "shell",
+}
+
+// templateExtensions wrap another file type (e.g. config.yaml.tmpl)
+var templateExtensions = map[string]bool{
+	".tmpl":     true,
+	".tpl":      true,
+	".template": true,
+	".j2":       true,
+	".jinja":    true,
+	".erb":      true,
+	".in":       true,
+	".dist":     true,
+}
+
+// interpreterToLanguage maps shebang interpreters to languages
+var interpreterToLanguage = map[string]string{
+	"sh":      "shell",
+	"bash":    "shell",
+	"zsh":     "shell",
+	"ksh":     "shell",
+	"dash":    "shell",
+	"python":  "python",
+	"python2": "python",
+	"python3": "python",
+	"node":    "javascript",
+	"nodejs":  "javascript",
+	"deno":    "typescript",
+	"ts-node": "typescript",
+	"bun":     "javascript",
+	"ruby":    "ruby",
+	"php":     "php",
+	"perl":    "perl",
+	"lua":     "lua",
+	"kotlin":  "kotlin",
+	"swift":   "swift",
+	"go":      "go",
+}
+
+// contentHeuristic matches a language by content patterns
+type contentHeuristic struct {
+	language string
+	pattern  *regexp.Regexp
+}
+
+// ambiguousExtensions lists extensions shared by several languages, with
+// heuristics checked in order. The extToLanguage entry is the fallback.
+var ambiguousExtensions = map[string][]contentHeuristic{
+	".h": {
+		{"objectivec", regexp.MustCompile(`(?m)^\s*(@interface|@implementation|@protocol|@property|#import\s)`)},
+		{"cpp", regexp.MustCompile(`(?m)^\s*(class\s+\w+|namespace\s+\w+|template\s*<|using\s+namespace\s)|std::|#include\s*<(iostream|vector|string|map|memory)>`)},
+	},
+}
+
+// extensionlessHeuristics detect languages for files without a usable extension.
+// Ordered from most to least specific.
+var extensionlessHeuristics = []contentHeuristic{
+	{"php", regexp.MustCompile(`^\s*<\?php`)},
+	{"go", regexp.MustCompile(`(?m)^package\s+\w+\s*$[\s\S]*^func\s`)},
+	{"python", regexp.MustCompile(`(?m)^(def\s+\w+\(.*\):|from\s+[\w.]+\s+import\s|import\s+\w+\s*$|if\s+__name__\s*==)`)},
+	{"ruby", regexp.MustCompile(`(?m)^\s*(require\s+['"]|module\s+[A-Z]\w*\s*$|def\s+\w+[?!]?\s*$)`)},
+	{"javascript", regexp.MustCompile(`(?m)^\s*(const|let)\s+\w+\s*=\s*require\(|module\.exports\s*=`)},
+	{"shell", regexp.MustCompile(`(?m)^\s*(set\s+-[euxo]+|export\s+\w+=|if\s+\[\[?\s|fi\s*$|esac\s*$)`)},
+	{"dockerfile", regexp.MustCompile(`(?m)^FROM\s+\S+[\s\S]*^(RUN|CMD|COPY|ENTRYPOINT)\s`)},
+	{"makefile", regexp.MustCompile(`(?m)^\.PHONY:|^[\w.-]+:.*\n\t`)},
+}
+
+// modelineRegex matches vim and emacs modelines (vim: ft=python, -*- mode: ruby -*-)
+var modelineRegex = regexp.MustCompile(`(?:vim?:.*\b(?:ft|filetype|syntax)=(\w+)|-\*-.*\bmode:\s*([\w+-]+).*-\*-)`)
+
+// modelineAliases normalizes modeline names to detector language names
+var modelineAliases = map[string]string{
+	"sh":   "shell",
+	"bash": "shell",
+	"js":   "javascript",
+	"ts":   "typescript",
+	"py":   "python",
+	"rb":   "ruby",
+	"c++":  "cpp",
+	"make": "makefile",
+}
+
+// DetectLanguage detects a file's language from its path and, when the
+// extension is missing, unknown or ambiguous, from its content.
+func DetectLanguage(path, content string) string {
+	base := strings.ToLower(filepath.Base(path))
+	if lang, ok := filenameToLanguage[base]; ok {
+		return lang
+	}
+
+	ext := strings.ToLower(filepath.Ext(base))
+	if templateExtensions[ext] {
+		// config.yaml.tmpl -> config.yaml
+		if inner := DetectLanguage(strings.TrimSuffix(path, filepath.Ext(path)), content); inner != languageUnknown {
+			return inner
+		}
+	}
+
+	if heuristics, ok := ambiguousExtensions[ext]; ok {
+		if lang := matchHeuristics(heuristics, content); lang != "" {
+			return lang
+		}
+	}
+
+	if lang, ok := extToLanguage[ext]; ok {
+		return lang
+	}
+
+	return detectFromContent(content)
+}
+
+// LanguageExtension returns the canonical file extension for a language,
+// or "" if none is known. The shortest extension wins (".yml" over ".yaml").
+func LanguageExtension(language string) string {
+	best := ""
+	for ext, lang := range extToLanguage {
+		if lang != language {
+			continue
+		}
+		if best == "" || len(ext) < len(best) || (len(ext) == len(best) && ext < best) {
+			best = ext
+		}
+	}
+	return best
+}
+
+// detectFromContent detects a language using shebangs, modelines and heuristics.
+func detectFromContent(content string) string {
+	if content == "" {
+		return languageUnknown
+	}
+	content = headLines(content, maxDetectLines)
+
+	if lang := detectShebang(content); lang != "" {
+		return lang
+	}
+	if lang := detectModeline(content); lang != "" {
+		return lang
+	}
+	if lang := matchHeuristics(extensionlessHeuristics, content); lang != "" {
+		return lang
+	}
+	return languageUnknown
+}
+
+// detectShebang returns the language of a "#!" interpreter line.
+func detectShebang(content string) string {
+	if !strings.HasPrefix(content, "#!") {
+		return ""
+	}
+	line := content[2:]
+	if idx := strings.IndexByte(line, '\n'); idx >= 0 {
+		line = line[:idx]
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	// #!/usr/bin/env [-S] python3
+	if interpreter == "env" {
+		interpreter = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				interpreter = filepath.Base(f)
+				break
+			}
+		}
+	}
+
+	if lang, ok := interpreterToLanguage[interpreter]; ok {
+		return lang
+	}
+	// python3.12, ruby2.7, ...
+	trimmed := strings.TrimRight(interpreter, "0123456789.")
+	return interpreterToLanguage[trimmed]
+}
+
+// detectModeline returns the language declared by a vim or emacs modeline.
+func detectModeline(content string) string {
+	m := modelineRegex.FindStringSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	name := strings.ToLower(m[1] + m[2])
+	if alias, ok := modelineAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+func matchHeuristics(heuristics []contentHeuristic, content string) string {
+	if content == "" {
+		return ""
+	}
+	for _, h := range heuristics {
+		if h.pattern.MatchString(content) {
+			return h.language
+		}
+	}
+	return ""
+}
+
+func headLines(content string, n int) string {
+	idx := 0
+	for i := 0; i < n; i++ {
+		next := strings.IndexByte(content[idx:], '\n')
+		if next < 0 {
+			return content
+		}
+		idx += next + 1
+	}
+	return content[:idx]
+}
+
+// needsContentDetection reports whether the path alone can't settle the language.
+func needsContentDetection(path, language string) bool {
+	if language == languageUnknown || language == "" {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	_, ambiguous := ambiguousExtensions[ext]
+	return ambiguous || templateExtensions[ext]
+}
+
+// refineLanguages re-detects languages from diff content for files whose
+// path is extensionless, unknown or ambiguous.
+func refineLanguages(diff *Diff) {
+	for i := range diff.Files {
+		f := &diff.Files[i]
+		if f.IsBinary || !needsContentDetection(f.Path, f.Language) {
+			continue
+		}
+		f.Language = DetectLanguage(f.Path, diffContent(f))
+	}
+}
+
+// diffContent rebuilds the visible file content from the diff. New-side
+// lines are used, except for deleted files.
+func diffContent(f *FileDiff) string {
+	skip := LineDeletion
+	if f.Status == FileDeleted {
+		skip = LineAddition
+	}
+
+	var sb strings.Builder
+	count := 0
+	for _, hunk := range f.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type == skip {
+				continue
+			}
+			sb.WriteString(line.Content)
+			sb.WriteByte('\n')
+			count++
+			if count >= maxDetectLines {
+				return sb.String()
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+package git
+
+import "testing"
+
+func TestDetectLanguageFromContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+	}{
+		{"extension", "main.go", "", "go"},
+		{"known filename", "build/Dockerfile", "", "dockerfile"},
+		{"makefile", "Makefile", "", "makefile"},
+		{"shebang env", "scripts/deploy", "#!/usr/bin/env python3\nprint('hi')\n", "python"},
+		{"shebang env -S", "bin/run", "#!/usr/bin/env -S node --no-warnings\n", "javascript"},
+		{"shebang direct", "hooks/pre-commit", "#!/bin/bash\nset -e\n", "shell"},
+		{"versioned interpreter", "tool", "#!/usr/local/bin/python3.12\n", "python"},
+		{"vim modeline", "conf", "# vim: set ft=ruby:\nputs 1\n", "ruby"},
+		{"emacs modeline", "script", "# -*- mode: sh -*-\necho hi\n", "shell"},
+		{"php open tag", "index", "<?php\necho 'hi';\n", "php"},
+		{"go heuristic", "snippet", "package main\n\nfunc main() {}\n", "go"},
+		{"shell heuristic", "env", "export FOO=bar\nif [ -z \"$X\" ]; then\n  exit 1\nfi\n", "shell"},
+		{"header c", "lib.h", "#include <stdio.h>\nint add(int a, int b);\n", "c"},
+		{"header cpp", "lib.h", "#include <vector>\nnamespace util {\nclass Box {};\n}\n", "cpp"},
+		{"header objc", "View.h", "#import <UIKit/UIKit.h>\n@interface View : UIView\n@end\n", "objectivec"},
+		{"template", "config.yaml.tmpl", "", "yaml"},
+		{"template by content", "run.tmpl", "#!/bin/sh\n", "shell"},
+		{"unknown", "LICENSE", "Permission is hereby granted", "unknown"},
+		{"empty", "data", "", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.path, tt.content); got != tt.want {
+				t.Errorf("DetectLanguage(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDiffDetectsLanguageFromContent(t *testing.T) {
+	diffText := `diff --git a/scripts/release b/scripts/release
+new file mode 100755
+--- /dev/null
++++ b/scripts/release
+@@ -0,0 +1,3 @@
++#!/usr/bin/env bash
++set -euo pipefail
++echo "releasing"
+diff --git a/include/box.h b/include/box.h
+--- a/include/box.h
++++ b/include/box.h
+@@ -1,3 +1,4 @@
+ namespace geo {
++template <typename T>
+ class Box {};
+ }
+`
+
+	for name, parse := range map[string]func(string) (*Diff, error){
+		"regex":     ParseDiff,
+		"optimized": ParseDiffOptimized,
+	} {
+		t.Run(name, func(t *testing.T) {
+			diff, err := parse(diffText)
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			if len(diff.Files) != 2 {
+				t.Fatalf("len(Files) = %d, want 2", len(diff.Files))
+			}
+			if got := diff.Files[0].Language; got != "shell" {
+				t.Errorf("scripts/release Language = %q, want shell", got)
+			}
+			if got := diff.Files[1].Language; got != "cpp" {
+				t.Errorf("include/box.h Language = %q, want cpp", got)
+			}
+		})
+	}
+}
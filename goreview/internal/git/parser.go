@@ -42,6 +42,7 @@ func ParseDiff(diffText string) (*Diff, error) {
 	}
 
 	state.finalizeFile()
+	refineLanguages(diff)
 	diff.CalculateStats()
 	return diff, nil
 }
@@ -107,6 +108,7 @@ func (s *parseState) checkFileStatus(line string) bool {
 // startNewHunk begins parsing a new hunk
 func (s *parseState) startNewHunk(line string, matches []string) {
 	if s.currentHunk != nil {
+		s.currentHunk.NumberLines()
 		s.currentFile.Hunks = append(s.currentFile.Hunks, *s.currentHunk)
 	}
 
@@ -154,6 +156,7 @@ func (s *parseState) addDiffLine(line string) {
 func (s *parseState) finalizeFile() {
 	if s.currentFile != nil {
 		if s.currentHunk != nil {
+			s.currentHunk.NumberLines()
 			s.currentFile.Hunks = append(s.currentFile.Hunks, *s.currentHunk)
 		}
 		s.diff.Files = append(s.diff.Files, *s.currentFile)
@@ -73,6 +73,7 @@ func ParseDiffOptimized(diffText string) (*Diff, error) {
 	// Add last file and hunk
 	state.finalize()
 
+	refineLanguages(diff)
 	diff.CalculateStats()
 	return diff, nil
 }
@@ -109,6 +110,7 @@ func (s *diffParseState) handleNewFile(line string) {
 	// Save previous file
 	if s.currentFile != nil {
 		if s.currentHunk != nil {
+			s.currentHunk.NumberLines()
 			s.currentFile.Hunks = append(s.currentFile.Hunks, *s.currentHunk)
 		}
 		s.diff.Files = append(s.diff.Files, *s.currentFile)
@@ -129,6 +131,7 @@ func (s *diffParseState) handleNewFile(line string) {
 // handleHunkHeader processes a hunk header line
 func (s *diffParseState) handleHunkHeader(line string) {
 	if s.currentHunk != nil {
+		s.currentHunk.NumberLines()
 		s.currentFile.Hunks = append(s.currentFile.Hunks, *s.currentHunk)
 	}
 	s.currentHunk = parseHunkHeaderOptimized(line)
@@ -185,6 +188,7 @@ func (s *diffParseState) handleDiffLine(line string) {
 func (s *diffParseState) finalize() {
 	if s.currentFile != nil {
 		if s.currentHunk != nil {
+			s.currentHunk.NumberLines()
 			s.currentFile.Hunks = append(s.currentFile.Hunks, *s.currentHunk)
 		}
 		s.diff.Files = append(s.diff.Files, *s.currentFile)
@@ -0,0 +1,152 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/failure"
+)
+
+// ErrNoRepository is returned by a PatchRepo for what only a repository
+// can answer.
+var ErrNoRepository = errors.New("patch has no git repository")
+
+// ParsePatch parses a patch: git diff output, a "git format-patch" mail or a
+// plain unified diff as written by "diff -u". Mail headers, commit messages
+// and signatures around the diff are skipped.
+func ParsePatch(patch string) (*Diff, error) {
+	diff, err := ParseDiff(normalizePatch(patch))
+	if err != nil {
+		return nil, err
+	}
+	if len(diff.Files) == 0 && strings.TrimSpace(patch) != "" {
+		return nil, &failure.ParseError{Code: failure.ParseDiff, Err: fmt.Errorf("no file changes found in patch")}
+	}
+	return diff, nil
+}
+
+// normalizePatch rewrites a patch into the git diff format ParseDiff reads:
+// plain unified diffs get a "diff --git" header, and lines outside the
+// files' headers and hunks are dropped. Hunks are tracked by their line
+// counts, so removed lines starting with "--" aren't taken for headers.
+func normalizePatch(patch string) string {
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+	var out []string
+	// inHeader is set between a file header and its first hunk, where git
+	// adds its extended header lines (modes, renames, binary markers)
+	inHeader := false
+	oldLeft, newLeft := 0, 0
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "\\"):
+			case strings.HasPrefix(line, "+"):
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			default:
+				// Context line; some tools strip the space of empty ones
+				if line == "" {
+					line = " "
+				}
+				oldLeft--
+				newLeft--
+			}
+			out = append(out, line)
+			continue
+		}
+
+		switch {
+		case diffHeaderRegex.MatchString(line):
+			inHeader = true
+			out = append(out, line)
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			if !inHeader {
+				out = append(out, plainFileHeader(line[4:], lines[i+1][4:])...)
+				inHeader = true
+			}
+			i++
+		case hunkHeaderRegex.MatchString(line):
+			m := hunkHeaderRegex.FindStringSubmatch(line)
+			oldLeft, newLeft = parseIntOrDefault(m[2], 1), parseIntOrDefault(m[4], 1)
+			inHeader = false
+			out = append(out, line)
+		case inHeader:
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// plainFileHeader returns the git header of a plain unified diff's file,
+// from its "---" and "+++" paths.
+func plainFileHeader(oldPath, newPath string) []string {
+	oldPath, newPath = patchPath(oldPath, "a/"), patchPath(newPath, "b/")
+	switch {
+	case oldPath == "/dev/null":
+		return []string{fmt.Sprintf("diff --git a/%s b/%s", newPath, newPath), "new file mode 100644"}
+	case newPath == "/dev/null":
+		return []string{fmt.Sprintf("diff --git a/%s b/%s", oldPath, oldPath), "deleted file mode 100644"}
+	default:
+		return []string{fmt.Sprintf("diff --git a/%s b/%s", oldPath, newPath)}
+	}
+}
+
+// patchPath strips the timestamp "diff -u" appends after a tab and the
+// a/ or b/ prefix.
+func patchPath(path, prefix string) string {
+	if i := strings.IndexByte(path, '\t'); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.TrimSpace(path)
+	if path == "/dev/null" {
+		return path
+	}
+	return strings.TrimPrefix(path, prefix)
+}
+
+// PatchRepo is a Repository serving a patch read from outside git, such as
+// stdin. Every diff method returns the patch.
+type PatchRepo struct {
+	diff *Diff
+	root string
+}
+
+// NewPatchRepo returns a repository serving diff. root is the checkout the
+// patch applies to, used to read the changed files; empty when there is
+// none.
+func NewPatchRepo(diff *Diff, root string) *PatchRepo {
+	return &PatchRepo{diff: diff, root: root}
+}
+
+func (r *PatchRepo) GetStagedDiff(ctx context.Context) (*Diff, error) { return r.diff, nil }
+
+func (r *PatchRepo) GetCommitDiff(ctx context.Context, sha string) (*Diff, error) {
+	return r.diff, nil
+}
+
+func (r *PatchRepo) GetBranchDiff(ctx context.Context, baseBranch string) (*Diff, error) {
+	return r.diff, nil
+}
+
+func (r *PatchRepo) GetFileDiff(ctx context.Context, files []string) (*Diff, error) {
+	return r.diff, nil
+}
+
+func (r *PatchRepo) GetCurrentBranch(ctx context.Context) (string, error) {
+	return "", ErrNoRepository
+}
+
+func (r *PatchRepo) GetRepoRoot(ctx context.Context) (string, error) {
+	if r.root == "" {
+		return "", ErrNoRepository
+	}
+	return r.root, nil
+}
+
+func (r *PatchRepo) IsClean(ctx context.Context) (bool, error) { return true, nil }
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParsePatchPlainUnifiedDiff(t *testing.T) {
+	patch := `--- src/app.py	2026-01-10 10:00:00.000000000 +0100
++++ src/app.py	2026-01-10 10:05:00.000000000 +0100
+@@ -1,3 +1,3 @@
+ import os
+-DEBUG = True
++DEBUG = False
+
+--- /dev/null
++++ b/src/new.go
+@@ -0,0 +1,2 @@
++package src
++// -- not a header
+`
+	diff, err := ParsePatch(patch)
+	if err != nil {
+		t.Fatalf("ParsePatch() error = %v", err)
+	}
+	if len(diff.Files) != 2 {
+		t.Fatalf("files = %d, want 2", len(diff.Files))
+	}
+	app, added := diff.Files[0], diff.Files[1]
+	if app.Path != "src/app.py" || app.Language != "python" || app.Additions != 1 || app.Deletions != 1 {
+		t.Errorf("app.py = %+v", app)
+	}
+	if len(app.Hunks) != 1 || len(app.Hunks[0].Lines) != 4 {
+		t.Errorf("app.py hunk lines = %+v", app.Hunks)
+	}
+	if added.Path != "src/new.go" || added.Status != FileAdded || added.Additions != 2 {
+		t.Errorf("new.go = %+v", added)
+	}
+}
+
+func TestParsePatchMail(t *testing.T) {
+	patch := `From 1a2b3c Mon Sep 17 00:00:00 2001
+From: Dev <dev@example.com>
+Subject: [PATCH] Drop the separator
+
+---
+ notes.md | 1 -
+ 1 file changed, 1 deletion(-)
+
+diff --git a/notes.md b/notes.md
+index 1111111..2222222 100644
+--- a/notes.md
++++ b/notes.md
+@@ -1,3 +1,2 @@
+ # Notes
+--- separator
+ end
+-- 
+2.43.0
+`
+	diff, err := ParsePatch(patch)
+	if err != nil {
+		t.Fatalf("ParsePatch() error = %v", err)
+	}
+	if len(diff.Files) != 1 {
+		t.Fatalf("files = %d, want 1", len(diff.Files))
+	}
+	f := diff.Files[0]
+	if f.Path != "notes.md" || f.Deletions != 1 || f.Additions != 0 {
+		t.Fatalf("notes.md = %+v", f)
+	}
+	lines := f.Hunks[0].Lines
+	if len(lines) != 3 || lines[1].Content != "-- separator" || lines[2].Content != "end" {
+		t.Errorf("hunk lines = %+v", lines)
+	}
+}
+
+func TestParsePatchEmpty(t *testing.T) {
+	if _, err := ParsePatch("just some text\n"); err == nil {
+		t.Error("ParsePatch() accepted a patch without changes")
+	}
+	diff, err := ParsePatch("")
+	if err != nil || len(diff.Files) != 0 {
+		t.Errorf("ParsePatch(\"\") = %v, %v", diff, err)
+	}
+}
+
+func TestPatchRepo(t *testing.T) {
+	diff := &Diff{Files: []FileDiff{{Path: "a.go"}}}
+	repo := NewPatchRepo(diff, "")
+	ctx := context.Background()
+	if got, _ := repo.GetStagedDiff(ctx); got != diff {
+		t.Error("GetStagedDiff() didn't return the patch")
+	}
+	if _, err := repo.GetRepoRoot(ctx); !errors.Is(err, ErrNoRepository) {
+		t.Errorf("GetRepoRoot() error = %v", err)
+	}
+	if root, _ := NewPatchRepo(diff, "/src").GetRepoRoot(ctx); root != "/src" {
+		t.Errorf("GetRepoRoot() = %q", root)
+	}
+}
@@ -3,10 +3,13 @@ package git
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/failure"
 )
 
 // Git command constants (SonarQube S1192)
@@ -16,12 +19,23 @@ const (
 	noMergesFlag       = "--no-merges"
 )
 
+// ErrNoWorktree is returned by a bare repository for staged and file
+// diffs, which need a working tree.
+var ErrNoWorktree error = &failure.GitError{
+	Code: failure.GitNoWorktree,
+	Err:  errors.New("bare repository has no working tree: review a commit or a branch"),
+}
+
 // Repo implements Repository using git commands.
 type Repo struct {
 	path string
+	// bare is set for repositories without a working tree, whose files are
+	// read from commits
+	bare bool
 }
 
-// NewRepo creates a new Repo.
+// NewRepo creates a new Repo. path may be a bare repository, as in
+// server-side hooks.
 func NewRepo(path string) (*Repo, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
@@ -30,13 +44,23 @@ func NewRepo(path string) (*Repo, error) {
 
 	// Verify it's a git repository
 	repo := &Repo{path: absPath}
+	bare, err := repo.runGit(context.Background(), "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return nil, notRepository(err)
+	}
+	repo.bare = strings.TrimSpace(bare) == "true"
 	if _, err := repo.GetRepoRoot(context.Background()); err != nil {
-		return nil, fmt.Errorf("not a git repository: %w", err)
+		return nil, notRepository(err)
 	}
 
 	return repo, nil
 }
 
+// IsBare reports whether the repository has no working tree.
+func (r *Repo) IsBare() bool {
+	return r.bare
+}
+
 // runGit executes a git command and returns the output.
 func (r *Repo) runGit(ctx context.Context, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", args...)
@@ -49,16 +73,36 @@ func (r *Repo) runGit(ctx context.Context, args ...string) (string, error) {
 	if err := cmd.Run(); err != nil {
 		// Include stderr in error message for debugging
 		errMsg := strings.TrimSpace(stderr.String())
+		gitErr := &failure.GitError{Code: gitErrorCode(errMsg), Op: args[0], Err: fmt.Errorf("git %s: %w", args[0], err)}
 		if errMsg != "" {
-			return "", fmt.Errorf("git %s: %w: %s", args[0], err, errMsg)
+			gitErr.Err = fmt.Errorf("git %s: %w: %s", args[0], err, errMsg)
 		}
-		return "", fmt.Errorf("git %s: %w", args[0], err)
+		return "", gitErr
 	}
 
 	return stdout.String(), nil
 }
 
+func notRepository(err error) error {
+	return &failure.GitError{Code: failure.GitNotRepository, Op: "rev-parse", Err: fmt.Errorf("not a git repository: %w", err)}
+}
+
+// gitErrorCode codes a failed git command by its error output.
+func gitErrorCode(stderr string) failure.Code {
+	switch {
+	case strings.Contains(stderr, "not a git repository"):
+		return failure.GitNotRepository
+	case strings.Contains(stderr, "unknown revision"), strings.Contains(stderr, "bad revision"),
+		strings.Contains(stderr, "bad object"), strings.Contains(stderr, "ambiguous argument"):
+		return failure.GitUnknownRevision
+	}
+	return failure.GitFailed
+}
+
 func (r *Repo) GetStagedDiff(ctx context.Context) (*Diff, error) {
+	if r.bare {
+		return nil, ErrNoWorktree
+	}
 	// Get staged diff
 	output, err := r.runGit(ctx, "diff", "--cached", unifiedContextFlag)
 	if err != nil {
@@ -67,14 +111,20 @@ func (r *Repo) GetStagedDiff(ctx context.Context) (*Diff, error) {
 
 	diff, err := ParseDiff(output)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse diff: %w", err)
+		return nil, &failure.ParseError{Code: failure.ParseDiff, Err: fmt.Errorf("failed to parse diff: %w", err)}
 	}
 
 	return diff, nil
 }
 
+// GetCommitDiff returns the diff of a commit or, for a range like
+// "main..feature", of its whole change.
 func (r *Repo) GetCommitDiff(ctx context.Context, sha string) (*Diff, error) {
-	output, err := r.runGit(ctx, "show", sha, unifiedContextFlag, formatFlag)
+	args := []string{"show", sha, unifiedContextFlag, formatFlag}
+	if strings.Contains(sha, "..") {
+		args = []string{"diff", sha, unifiedContextFlag}
+	}
+	output, err := r.runGit(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +149,9 @@ func (r *Repo) GetBranchDiff(ctx context.Context, baseBranch string) (*Diff, err
 }
 
 func (r *Repo) GetFileDiff(ctx context.Context, files []string) (*Diff, error) {
+	if r.bare {
+		return nil, ErrNoWorktree
+	}
 	args := append([]string{"diff", unifiedContextFlag, "--"}, files...)
 	output, err := r.runGit(ctx, args...)
 	if err != nil {
@@ -116,7 +169,13 @@ func (r *Repo) GetCurrentBranch(ctx context.Context) (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// GetRepoRoot returns the root of the working tree, or the repository
+// directory of a bare repository.
 func (r *Repo) GetRepoRoot(ctx context.Context) (string, error) {
+	if r.bare {
+		output, err := r.runGit(ctx, "rev-parse", "--absolute-git-dir")
+		return strings.TrimSpace(output), err
+	}
 	output, err := r.runGit(ctx, "rev-parse", "--show-toplevel")
 	if err != nil {
 		return "", err
@@ -125,6 +184,9 @@ func (r *Repo) GetRepoRoot(ctx context.Context) (string, error) {
 }
 
 func (r *Repo) IsClean(ctx context.Context) (bool, error) {
+	if r.bare {
+		return true, nil
+	}
 	output, err := r.runGit(ctx, "status", "--porcelain")
 	if err != nil {
 		return false, err
@@ -158,6 +220,21 @@ func (r *Repo) GetCommits(ctx context.Context, from, to string) ([]Commit, error
 	return parseCommits(output, separator)
 }
 
+// GetCommitFiles returns the paths of the files changed by a commit.
+func (r *Repo) GetCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	output, err := r.runGit(ctx, "diff-tree", "--root", "--no-commit-id", "--name-only", "-r", "-z", hash)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(output, "\x00") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
 // GetTags returns all tags sorted by date (newest first).
 func (r *Repo) GetTags(ctx context.Context) ([]Tag, error) {
 	// Format: refname|hash|date|tagger
@@ -0,0 +1,217 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TreeFS is a read-only file system of the files of a commit, read with
+// git plumbing. It serves the files of repositories without a working
+// tree, like the bare repositories of server-side hooks.
+type TreeFS struct {
+	repo *Repo
+	rev  string
+
+	once    sync.Once
+	err     error
+	entries map[string]treeEntry // path -> file
+	dirs    map[string][]string  // directory -> sorted child names, "." the root
+}
+
+// treeEntry is a file of a tree, from "git ls-tree -l".
+type treeEntry struct {
+	mode   fs.FileMode
+	object string
+	size   int64
+}
+
+// Files returns the files of the commit rev.
+func (r *Repo) Files(rev string) *TreeFS {
+	return &TreeFS{repo: r, rev: rev}
+}
+
+// load lists the tree of the commit once.
+func (t *TreeFS) load() error {
+	t.once.Do(func() {
+		out, err := t.repo.runGit(context.Background(), "ls-tree", "-r", "-l", "-z", "--full-tree", t.rev)
+		if err != nil {
+			t.err = err
+			return
+		}
+		t.entries = make(map[string]treeEntry)
+		children := map[string]map[string]bool{".": {}}
+		for _, record := range strings.Split(out, "\x00") {
+			// <mode> SP <type> SP <object> SP <size> TAB <path>
+			meta, name, ok := strings.Cut(record, "\t")
+			fields := strings.Fields(meta)
+			if !ok || len(fields) != 4 || fields[1] != "blob" {
+				continue // Submodules have no content here
+			}
+			size, _ := strconv.ParseInt(fields[3], 10, 64)
+			t.entries[name] = treeEntry{mode: blobMode(fields[0]), object: fields[2], size: size}
+			for name != "." {
+				dir := path.Dir(name)
+				if children[dir] == nil {
+					children[dir] = make(map[string]bool)
+				}
+				children[dir][path.Base(name)] = true
+				name = dir
+			}
+		}
+		t.dirs = make(map[string][]string, len(children))
+		for dir, names := range children {
+			sorted := make([]string, 0, len(names))
+			for name := range names {
+				sorted = append(sorted, name)
+			}
+			sort.Strings(sorted)
+			t.dirs[dir] = sorted
+		}
+	})
+	return t.err
+}
+
+// blobMode converts a git file mode to an fs.FileMode.
+func blobMode(mode string) fs.FileMode {
+	switch mode {
+	case "100755":
+		return 0o755
+	case "120000":
+		return fs.ModeSymlink | 0o777
+	}
+	return 0o644
+}
+
+// Open opens a file or directory of the tree.
+func (t *TreeFS) Open(name string) (fs.File, error) {
+	info, err := t.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &treeDir{info: info, fsys: t, path: name}, nil
+	}
+	data, err := t.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return &treeFile{info: info, Reader: bytes.NewReader(data)}, nil
+}
+
+// ReadFile returns the content of a file of the tree.
+func (t *TreeFS) ReadFile(name string) ([]byte, error) {
+	info, err := t.stat("read", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+	out, err := t.repo.runGit(context.Background(), "cat-file", "blob", t.entries[name].object)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	return []byte(out), nil
+}
+
+// Stat describes a file or directory of the tree.
+func (t *TreeFS) Stat(name string) (fs.FileInfo, error) {
+	return t.stat("stat", name)
+}
+
+// ReadDir lists a directory of the tree, sorted by name.
+func (t *TreeFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	info, err := t.stat("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	names := t.dirs[name]
+	entries := make([]fs.DirEntry, 0, len(names))
+	for _, child := range names {
+		childInfo, _ := t.stat("readdir", path.Join(name, child))
+		entries = append(entries, fs.FileInfoToDirEntry(childInfo))
+	}
+	return entries, nil
+}
+
+func (t *TreeFS) stat(op, name string) (*treeInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if err := t.load(); err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if e, ok := t.entries[name]; ok {
+		return &treeInfo{name: path.Base(name), mode: e.mode, size: e.size}, nil
+	}
+	if _, ok := t.dirs[name]; ok {
+		return &treeInfo{name: path.Base(name), mode: fs.ModeDir | 0o755}, nil
+	}
+	return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// treeInfo describes a file or directory of a tree. Trees have no
+// modification times.
+type treeInfo struct {
+	name string
+	mode fs.FileMode
+	size int64
+}
+
+func (i *treeInfo) Name() string       { return i.name }
+func (i *treeInfo) Size() int64        { return i.size }
+func (i *treeInfo) Mode() fs.FileMode  { return i.mode }
+func (i *treeInfo) ModTime() time.Time { return time.Time{} }
+func (i *treeInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *treeInfo) Sys() any           { return nil }
+
+// treeFile is an open file of a tree.
+type treeFile struct {
+	*bytes.Reader
+	info *treeInfo
+}
+
+func (f *treeFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *treeFile) Close() error               { return nil }
+
+// treeDir is an open directory of a tree.
+type treeDir struct {
+	info   *treeInfo
+	fsys   *TreeFS
+	path   string
+	offset int
+}
+
+func (d *treeDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *treeDir) Close() error               { return nil }
+
+func (d *treeDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: fs.ErrInvalid}
+}
+
+func (d *treeDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries, err := d.fsys.ReadDir(d.path)
+	if err != nil {
+		return nil, err
+	}
+	entries = entries[d.offset:]
+	if n > 0 {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		entries = entries[:min(n, len(entries))]
+	}
+	d.offset += len(entries)
+	return entries, nil
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// bareRepo commits files to a new repository and returns a bare clone.
+func bareRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	work := filepath.Join(dir, "work")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = work
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	for name, content := range files {
+		path := filepath.Join(work, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("-c", "user.name=Dev", "-c", "user.email=dev@example.com", "commit", "-qm", "initial")
+	git("clone", "-q", "--bare", work, filepath.Join(dir, "repo.git"))
+	return filepath.Join(dir, "repo.git")
+}
+
+func TestBareRepo(t *testing.T) {
+	path := bareRepo(t, map[string]string{"main.go": "package main\n"})
+	repo, err := NewRepo(path)
+	if err != nil {
+		t.Fatalf("NewRepo() error = %v", err)
+	}
+	if !repo.IsBare() {
+		t.Error("IsBare() = false")
+	}
+
+	ctx := context.Background()
+	if _, err := repo.GetStagedDiff(ctx); !errors.Is(err, ErrNoWorktree) {
+		t.Errorf("GetStagedDiff() error = %v, want ErrNoWorktree", err)
+	}
+	if root, err := repo.GetRepoRoot(ctx); err != nil || filepath.Base(root) != "repo.git" {
+		t.Errorf("GetRepoRoot() = %q, %v", root, err)
+	}
+	diff, err := repo.GetCommitDiff(ctx, "HEAD")
+	if err != nil || len(diff.Files) != 1 || diff.Files[0].Path != "main.go" {
+		t.Errorf("GetCommitDiff() = %+v, %v", diff, err)
+	}
+}
+
+func TestTreeFS(t *testing.T) {
+	files := map[string]string{
+		"main.go":          "package main\n",
+		"pkg/util/util.go": "package util\n",
+		"pkg/doc.md":       "# Docs\n",
+	}
+	repo, err := NewRepo(bareRepo(t, files))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := repo.Files("HEAD")
+
+	if err := fstest.TestFS(tree, "main.go", "pkg/util/util.go", "pkg/doc.md"); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range files {
+		if got, err := fs.ReadFile(tree, name); err != nil || string(got) != want {
+			t.Errorf("ReadFile(%s) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := fs.Stat(tree, "missing.go"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat(missing.go) error = %v", err)
+	}
+	entries, err := fs.ReadDir(tree, "pkg")
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if err != nil || strings.Join(names, ",") != "doc.md,util" || !entries[1].IsDir() {
+		t.Errorf("ReadDir(pkg) = %v, %v", names, err)
+	}
+}
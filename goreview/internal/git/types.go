@@ -67,6 +67,27 @@ type Hunk struct {
 	Lines    []Line `json:"lines"`
 }
 
+// NumberLines assigns OldNumber/NewNumber to every line of the hunk,
+// counting from the hunk's start positions.
+func (h *Hunk) NumberLines() {
+	oldNum, newNum := h.OldStart, h.NewStart
+	for i := range h.Lines {
+		switch h.Lines[i].Type {
+		case LineAddition:
+			h.Lines[i].NewNumber = newNum
+			newNum++
+		case LineDeletion:
+			h.Lines[i].OldNumber = oldNum
+			oldNum++
+		default:
+			h.Lines[i].OldNumber = oldNum
+			h.Lines[i].NewNumber = newNum
+			oldNum++
+			newNum++
+		}
+	}
+}
+
 // Line represents a single line in a hunk.
 type Line struct {
 	Type      LineType `json:"type"`
@@ -0,0 +1,136 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/failure"
+)
+
+// emptyTree is git's empty tree, the base of the working tree diff of a
+// repository without commits
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// binarySniffLen is how much of a file is checked for NUL bytes, as git
+// does to tell binary files
+const binarySniffLen = 8000
+
+// WorktreeRepository is a repository with a working tree, whose
+// uncommitted and untracked files can be read, like a Repo.
+type WorktreeRepository interface {
+	// GetWorktreeDiff returns the uncommitted changes, staged or not, and
+	// the untracked files as additions.
+	GetWorktreeDiff(ctx context.Context) (*Diff, error)
+
+	// GetUntrackedDiff returns the untracked files under paths, or all of
+	// them, as additions of their whole content.
+	GetUntrackedDiff(ctx context.Context, paths ...string) (*Diff, error)
+}
+
+// AddUntracked adds the untracked files under paths, or all of them, to
+// diff. Repositories without working tree support have none to add.
+func AddUntracked(ctx context.Context, repo Repository, diff *Diff, paths ...string) (*Diff, error) {
+	wt, ok := repo.(WorktreeRepository)
+	if !ok {
+		return diff, nil
+	}
+	untracked, err := wt.GetUntrackedDiff(ctx, paths...)
+	if err != nil {
+		return nil, err
+	}
+	if len(untracked.Files) == 0 {
+		return diff, nil
+	}
+	diff.Files = append(diff.Files, untracked.Files...)
+	diff.CalculateStats()
+	return diff, nil
+}
+
+// GetWorktreeDiff returns the changes of the working tree against HEAD,
+// staged or not, and the untracked files not ignored by .gitignore.
+func (r *Repo) GetWorktreeDiff(ctx context.Context) (*Diff, error) {
+	if r.bare {
+		return nil, ErrNoWorktree
+	}
+	base := "HEAD"
+	if _, err := r.runGit(ctx, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		base = emptyTree
+	}
+	output, err := r.runGit(ctx, "diff", base, unifiedContextFlag)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := ParseDiff(output)
+	if err != nil {
+		return nil, &failure.ParseError{Code: failure.ParseDiff, Err: fmt.Errorf("failed to parse diff: %w", err)}
+	}
+	return AddUntracked(ctx, r, diff)
+}
+
+// GetUntrackedDiff returns the untracked files under paths, or all of
+// them, not ignored by .gitignore, as additions of their whole content.
+// Binary files are listed without hunks.
+func (r *Repo) GetUntrackedDiff(ctx context.Context, paths ...string) (*Diff, error) {
+	if r.bare {
+		return nil, ErrNoWorktree
+	}
+	if len(paths) == 0 {
+		paths = []string{":/"}
+	}
+	args := append([]string{"ls-files", "-z", "--others", "--exclude-standard", "--full-name", "--"}, paths...)
+	output, err := r.runGit(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	root, err := r.GetRepoRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &Diff{}
+	for _, path := range strings.Split(output, "\x00") {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path))) //nolint:gosec // Listed by git in the repository
+		if err != nil {
+			return nil, fmt.Errorf("reading untracked %s: %w", path, err)
+		}
+		if bytes.IndexByte(data[:min(len(data), binarySniffLen)], 0) >= 0 {
+			diff.Files = append(diff.Files, FileDiff{Path: path, Status: FileAdded, Language: DetectLanguage(path, ""), IsBinary: true})
+			continue
+		}
+		diff.Files = append(diff.Files, WholeFile(path, string(data)))
+	}
+	diff.CalculateStats()
+	return diff, nil
+}
+
+// WholeFile returns the diff adding every line of a file's content.
+func WholeFile(path, content string) FileDiff {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+	hunk := Hunk{
+		Header:   fmt.Sprintf("@@ -0,0 +1,%d @@", len(lines)),
+		NewStart: 1,
+		NewLines: len(lines),
+		Lines:    make([]Line, 0, len(lines)),
+	}
+	for _, l := range lines {
+		hunk.Lines = append(hunk.Lines, Line{Type: LineAddition, Content: l})
+	}
+	hunk.NumberLines()
+	return FileDiff{
+		Path:      path,
+		Status:    FileAdded,
+		Language:  DetectLanguage(path, content),
+		Hunks:     []Hunk{hunk},
+		Additions: len(lines),
+	}
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// workRepo returns a new repository with files written to its working
+// tree, committing them first when commit is set.
+func workRepo(t *testing.T, files map[string]string, commit bool) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	writeFiles(t, dir, files)
+	if commit {
+		git("add", ".")
+		git("-c", "user.name=Dev", "-c", "user.email=dev@example.com", "commit", "-qm", "initial")
+	}
+	return dir
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func filesByPath(diff *Diff) map[string]FileDiff {
+	files := make(map[string]FileDiff, len(diff.Files))
+	for _, f := range diff.Files {
+		files[f.Path] = f
+	}
+	return files
+}
+
+func TestWorktreeDiff(t *testing.T) {
+	dir := workRepo(t, map[string]string{".gitignore": "*.log\n", "main.go": "package main\n"}, true)
+	writeFiles(t, dir, map[string]string{
+		"main.go":       "package main\n\nfunc main() {}\n",
+		"util/new.go":   "package util\n\nfunc New() {}\n",
+		"logo.png":      "\x89PNG\x00\x00",
+		"debug.log":     "ignored\n",
+		"util/empty.go": "",
+	})
+	repo, err := NewRepo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	diff, err := repo.GetWorktreeDiff(ctx)
+	if err != nil {
+		t.Fatalf("GetWorktreeDiff() error = %v", err)
+	}
+	files := filesByPath(diff)
+	if len(files) != 4 {
+		t.Fatalf("GetWorktreeDiff() files = %v, want main.go, util/new.go, util/empty.go and logo.png", files)
+	}
+	if f := files["main.go"]; f.Status != FileModified || f.Additions != 2 {
+		t.Errorf("main.go = %+v, want modified with 2 additions", f)
+	}
+	if f := files["util/new.go"]; f.Status != FileAdded || f.Additions != 3 || len(f.Hunks) != 1 || f.Hunks[0].Lines[2].NewNumber != 3 {
+		t.Errorf("util/new.go = %+v, want added whole", f)
+	}
+	if f := files["logo.png"]; !f.IsBinary || len(f.Hunks) != 0 {
+		t.Errorf("logo.png = %+v, want binary without hunks", f)
+	}
+	if diff.Stats.Additions != 5 {
+		t.Errorf("Stats.Additions = %d, want 5", diff.Stats.Additions)
+	}
+
+	staged, err := repo.GetStagedDiff(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staged, err = AddUntracked(ctx, repo, staged, "util"); err != nil {
+		t.Fatalf("AddUntracked() error = %v", err)
+	}
+	if files := filesByPath(staged); len(files) != 2 || files["util/new.go"].Path == "" {
+		t.Errorf("AddUntracked(util) files = %v, want the untracked files under util", files)
+	}
+}
+
+func TestWorktreeDiffUnbornHead(t *testing.T) {
+	dir := workRepo(t, map[string]string{"main.go": "package main\n"}, false)
+	repo, err := NewRepo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := repo.GetWorktreeDiff(context.Background())
+	if err != nil {
+		t.Fatalf("GetWorktreeDiff() error = %v", err)
+	}
+	if len(diff.Files) != 1 || diff.Files[0].Path != "main.go" || diff.Files[0].Status != FileAdded {
+		t.Errorf("GetWorktreeDiff() files = %+v, want main.go added", diff.Files)
+	}
+}
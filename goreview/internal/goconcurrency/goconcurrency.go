@@ -0,0 +1,497 @@
+// Package goconcurrency finds Go code that deserves a concurrency review:
+// goroutines that can't be stopped, maps written from goroutines without a
+// lock, sync values copied, and WaitGroup and Mutex misuse. The checks are
+// syntactic and only pre-select regions; the concurrency review mode asks
+// the model to confirm or dismiss each one.
+package goconcurrency
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Rule IDs of the concurrency heuristics
+const (
+	RuleGoroutineLeak = "concurrency/goroutine-leak"
+	RuleSharedMap     = "concurrency/shared-map"
+	RuleSyncCopy      = "concurrency/sync-copy"
+	RuleWaitGroup     = "concurrency/waitgroup"
+	RuleMutex         = "concurrency/mutex"
+)
+
+// Region is a suspicious range of lines of the file.
+type Region struct {
+	Rule      string
+	StartLine int
+	EndLine   int
+	Message   string
+}
+
+// syncTypes are the sync types that must not be copied after first use
+var syncTypes = map[string]bool{
+	"Mutex": true, "RWMutex": true, "WaitGroup": true, "Once": true, "Cond": true, "Map": true, "Pool": true,
+}
+
+// Check parses a Go file and returns its suspicious regions, in source
+// order.
+func Check(filename string, src []byte) ([]Region, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{fset: fset, lockers: lockerTypes(file), slices: sliceNames(file)}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		c.checkSignature(fn)
+		if fn.Body != nil {
+			c.checkBody(fn.Body)
+		}
+	}
+	sort.SliceStable(c.regions, func(i, j int) bool { return c.regions[i].StartLine < c.regions[j].StartLine })
+	return c.regions, nil
+}
+
+type checker struct {
+	fset *token.FileSet
+	// lockers are the struct types of the file holding a sync value
+	lockers map[string]bool
+	// slices are the names declared with a slice type in the file; each
+	// goroutine writing its own index of a slice is safe
+	slices  map[string]bool
+	regions []Region
+}
+
+func (c *checker) add(node ast.Node, rule, message string) {
+	c.regions = append(c.regions, Region{
+		Rule:      rule,
+		StartLine: c.fset.Position(node.Pos()).Line,
+		EndLine:   c.fset.Position(node.End()).Line,
+		Message:   message,
+	})
+}
+
+// checkSignature finds value receivers and parameters that copy a sync
+// value.
+func (c *checker) checkSignature(fn *ast.FuncDecl) {
+	if fn.Recv != nil {
+		for _, field := range fn.Recv.List {
+			if name := c.copiedType(field.Type); name != "" {
+				c.add(field, RuleSyncCopy, "Value receiver copies "+name+", which holds a sync value")
+			}
+		}
+	}
+	c.checkParams(fn.Type.Params)
+}
+
+func (c *checker) checkParams(params *ast.FieldList) {
+	for _, field := range params.List {
+		if name := c.copiedType(field.Type); name != "" {
+			c.add(field, RuleSyncCopy, "Parameter copies "+name+", which holds a sync value")
+		}
+	}
+}
+
+// checkBody checks the goroutines and locks of a function body, and of the
+// function literals in it.
+func (c *checker) checkBody(body *ast.BlockStmt) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GoStmt:
+			if lit, ok := n.Call.Fun.(*ast.FuncLit); ok {
+				c.checkGoroutine(n, lit)
+			}
+		case *ast.FuncLit:
+			c.checkParams(n.Type.Params)
+			c.checkLocks(n.Body)
+		}
+		return true
+	})
+	c.checkLocks(body)
+}
+
+// checkGoroutine checks a goroutine started from a function literal.
+func (c *checker) checkGoroutine(g *ast.GoStmt, lit *ast.FuncLit) {
+	if blocks(lit.Body) && !cancellable(g) {
+		c.add(g, RuleGoroutineLeak, "Goroutine blocks or loops with no context or done channel to stop it")
+	}
+
+	locked := callsMethod(lit.Body, "Lock")
+	locals := declaredNames(lit)
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // Checked when started as a goroutine itself
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Add" && isWaitGroup(sel.X) {
+				c.add(n, RuleWaitGroup, "WaitGroup.Add is called inside the goroutine, so Wait may return before it runs")
+			}
+			if ident, ok := n.Fun.(*ast.Ident); ok && ident.Name == "delete" && len(n.Args) > 0 && !locked && shared(n.Args[0], locals) {
+				c.add(n, RuleSharedMap, "Goroutine deletes from a shared map without holding a lock")
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if index, ok := lhs.(*ast.IndexExpr); ok && !locked && c.sharedMap(index.X, locals) {
+					c.add(n, RuleSharedMap, "Goroutine writes to a shared map without holding a lock")
+					break
+				}
+			}
+		case *ast.IncDecStmt:
+			if index, ok := n.X.(*ast.IndexExpr); ok && !locked && c.sharedMap(index.X, locals) {
+				c.add(n, RuleSharedMap, "Goroutine writes to a shared map without holding a lock")
+			}
+		}
+		return true
+	})
+}
+
+// checkLocks finds Lock calls never unlocked in the function, and returns
+// between a Lock and an Unlock that isn't deferred.
+func (c *checker) checkLocks(body *ast.BlockStmt) {
+	type lock struct {
+		call *ast.CallExpr
+		key  string
+	}
+	var locks []lock
+	unlocks := make(map[string][]token.Pos)
+	deferred := make(map[string]bool)
+	var returns []token.Pos
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // Literals lock and unlock on their own
+		case *ast.DeferStmt:
+			if key, method := lockCall(n.Call); method == "Unlock" || method == "RUnlock" {
+				deferred[key+method] = true
+			}
+			return false
+		case *ast.ReturnStmt:
+			returns = append(returns, n.Pos())
+		case *ast.CallExpr:
+			switch key, method := lockCall(n); method {
+			case "Lock":
+				locks = append(locks, lock{n, key + "Unlock"})
+			case "RLock":
+				locks = append(locks, lock{n, key + "RUnlock"})
+			case "Unlock", "RUnlock":
+				unlocks[key+method] = append(unlocks[key+method], n.Pos())
+			}
+		}
+		return true
+	})
+
+	for _, l := range locks {
+		if deferred[l.key] {
+			continue
+		}
+		var next token.Pos
+		for _, pos := range unlocks[l.key] {
+			if pos > l.call.Pos() && (next == token.NoPos || pos < next) {
+				next = pos
+			}
+		}
+		if next == token.NoPos {
+			c.add(l.call, RuleMutex, "Lock is never unlocked in this function")
+			continue
+		}
+		for _, pos := range returns {
+			if pos > l.call.Pos() && pos < next {
+				c.add(l.call, RuleMutex, "Function can return while holding the lock")
+				break
+			}
+		}
+	}
+}
+
+// sharedMap reports whether an indexed expression is a map from outside
+// the goroutine, as far as names tell.
+func (c *checker) sharedMap(e ast.Expr, locals map[string]bool) bool {
+	return !c.slices[lastName(e)] && shared(e, locals)
+}
+
+// copiedType returns the name of the type passed by value when it holds a
+// sync value, or "".
+func (c *checker) copiedType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if c.lockers[t.Name] {
+			return t.Name
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "sync" && syncTypes[t.Sel.Name] {
+			return "sync." + t.Sel.Name
+		}
+	case *ast.IndexExpr: // Generic instantiation
+		return c.copiedType(t.X)
+	}
+	return ""
+}
+
+// lockerTypes returns the struct types of the file with a sync field held
+// by value, directly or through another such struct.
+func lockerTypes(file *ast.File) map[string]bool {
+	structs := make(map[string]*ast.StructType)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					structs[ts.Name.Name] = st
+				}
+			}
+		}
+	}
+
+	lockers := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for name, st := range structs {
+			if lockers[name] {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				if holdsSync(field.Type, lockers) {
+					lockers[name], changed = true, true
+					break
+				}
+			}
+		}
+	}
+	return lockers
+}
+
+// sliceNames returns the variables and fields of the file declared with a
+// slice or array type, or assigned a slice made or composed in place.
+func sliceNames(file *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Field:
+			if _, ok := n.Type.(*ast.ArrayType); ok {
+				for _, name := range n.Names {
+					names[name.Name] = true
+				}
+			}
+		case *ast.ValueSpec:
+			_, ok := n.Type.(*ast.ArrayType)
+			for i, name := range n.Names {
+				if ok || (i < len(n.Values) && isSliceValue(n.Values[i])) {
+					names[name.Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				break
+			}
+			for i, lhs := range n.Lhs {
+				if isSliceValue(n.Rhs[i]) {
+					names[lastName(lhs)] = true
+				}
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// isSliceValue reports whether an expression makes or composes a slice.
+func isSliceValue(e ast.Expr) bool {
+	switch v := e.(type) {
+	case *ast.CompositeLit:
+		_, ok := v.Type.(*ast.ArrayType)
+		return ok
+	case *ast.CallExpr:
+		if ident, ok := v.Fun.(*ast.Ident); ok && ident.Name == "make" && len(v.Args) > 0 {
+			_, ok := v.Args[0].(*ast.ArrayType)
+			return ok
+		}
+	}
+	return false
+}
+
+func holdsSync(expr ast.Expr, lockers map[string]bool) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return lockers[t.Name]
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		return ok && pkg.Name == "sync" && syncTypes[t.Sel.Name]
+	case *ast.ArrayType:
+		return t.Len != nil && holdsSync(t.Elt, lockers)
+	}
+	return false
+}
+
+// blocks reports whether a goroutine body can run indefinitely: an endless
+// loop, a loop over a channel, a select, or a channel operation.
+func blocks(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt:
+			found = found || n.Cond == nil
+		case *ast.RangeStmt:
+			found = found || isChannelName(n.X)
+		case *ast.SelectStmt, *ast.SendStmt:
+			found = true
+		case *ast.UnaryExpr:
+			found = found || n.Op == token.ARROW
+		}
+		return !found
+	})
+	return found
+}
+
+// cancellable reports whether a goroutine has a way to be stopped: it uses a
+// context, a done channel or a Done method.
+func cancellable(g *ast.GoStmt) bool {
+	found := false
+	ast.Inspect(g, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			found = found || isSignalName(n.Name)
+		case *ast.SelectorExpr:
+			found = found || n.Sel.Name == "Done" || isSignalName(n.Sel.Name)
+		}
+		return !found
+	})
+	return found
+}
+
+// isSignalName reports whether a name is conventionally a context or a
+// cancellation signal.
+func isSignalName(name string) bool {
+	lower := strings.ToLower(name)
+	if lower == "ctx" || strings.HasSuffix(lower, "ctx") || strings.HasSuffix(name, "Context") {
+		return true
+	}
+	for _, s := range []string{"done", "quit", "stop", "cancel", "close", "shutdown", "exit"} {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isChannelName reports whether a ranged expression is named like a
+// channel: ch, jobs, ticker.C, eventsChan.
+func isChannelName(e ast.Expr) bool {
+	name := lastName(e)
+	lower := strings.ToLower(name)
+	switch lower {
+	case "c", "ch", "jobs", "tasks", "queue", "events", "messages", "results":
+		return true
+	}
+	return strings.HasSuffix(name, "Ch") || strings.HasSuffix(name, "Chan") || strings.HasSuffix(lower, "queue")
+}
+
+// isWaitGroup reports whether an expression is named like a WaitGroup.
+func isWaitGroup(e ast.Expr) bool {
+	lower := strings.ToLower(lastName(e))
+	return strings.HasSuffix(lower, "wg") || strings.Contains(lower, "waitgroup")
+}
+
+// lastName returns the last name of a selector expression, like C for
+// ticker.C.
+func lastName(e ast.Expr) string {
+	name := types.ExprString(e)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// lockCall returns the locked value and method of a Lock, RLock, Unlock or
+// RUnlock call, or "" for other calls.
+func lockCall(call *ast.CallExpr) (key, method string) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) != 0 {
+		return "", ""
+	}
+	switch sel.Sel.Name {
+	case "Lock", "RLock", "Unlock", "RUnlock":
+		return types.ExprString(sel.X) + ".", sel.Sel.Name
+	}
+	return "", ""
+}
+
+// callsMethod reports whether the body calls a method with the name.
+func callsMethod(body *ast.BlockStmt, name string) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == name {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// declaredNames returns the names declared by a function literal: its
+// parameters and its local variables.
+func declaredNames(lit *ast.FuncLit) map[string]bool {
+	names := make(map[string]bool)
+	for _, field := range lit.Type.Params.List {
+		for _, name := range field.Names {
+			names[name.Name] = true
+		}
+	}
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				for _, lhs := range n.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						names[ident.Name] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				names[name.Name] = true
+			}
+		case *ast.RangeStmt:
+			for _, e := range []ast.Expr{n.Key, n.Value} {
+				if ident, ok := e.(*ast.Ident); ok && n.Tok == token.DEFINE {
+					names[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// shared reports whether an indexed expression refers to a value from
+// outside the goroutine.
+func shared(e ast.Expr, locals map[string]bool) bool {
+	for {
+		switch x := e.(type) {
+		case *ast.Ident:
+			return !locals[x.Name]
+		case *ast.SelectorExpr:
+			e = x.X
+		case *ast.ParenExpr:
+			e = x.X
+		case *ast.StarExpr:
+			e = x.X
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package goconcurrency
+
+import "testing"
+
+const sample = `package cache
+
+import (
+	"context"
+	"sync"
+)
+
+type Cache struct {
+	mu    sync.Mutex
+	items map[string]int
+}
+
+func (c Cache) Len() int {
+	return len(c.items)
+}
+
+func (c *Cache) Fill(keys []string, wg sync.WaitGroup) {
+	for _, k := range keys {
+		go func(k string) {
+			wg.Add(1)
+			defer wg.Done()
+			c.items[k] = len(k)
+		}(k)
+	}
+}
+
+func (c *Cache) Get(key string) (int, bool) {
+	c.mu.Lock()
+	v, ok := c.items[key]
+	if !ok {
+		return 0, false
+	}
+	c.mu.Unlock()
+	return v, true
+}
+
+func (c *Cache) Watch(events chan string) {
+	go func() {
+		for e := range events {
+			c.mu.Lock()
+			delete(c.items, e)
+			c.mu.Unlock()
+		}
+	}()
+}
+
+func (c *Cache) Refresh(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+}
+
+func (c *Cache) Leak() {
+	c.mu.Lock()
+	c.items = nil
+}
+
+func Sizes(keys []string) []int {
+	sizes := make([]int, len(keys))
+	for i, k := range keys {
+		go func(i int) {
+			sizes[i] = len(k)
+		}(i)
+	}
+	return sizes
+}
+`
+
+func TestCheck(t *testing.T) {
+	regions, err := Check("cache.go", []byte(sample))
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	want := []struct {
+		rule       string
+		start, end int
+	}{
+		{RuleSyncCopy, 13, 13},      // Value receiver of a struct with a Mutex
+		{RuleSyncCopy, 17, 17},      // WaitGroup parameter
+		{RuleWaitGroup, 20, 20},     // wg.Add inside the goroutine
+		{RuleSharedMap, 22, 22},     // c.items[k] without the lock
+		{RuleMutex, 28, 28},         // Early return holding the lock
+		{RuleGoroutineLeak, 38, 44}, // Range over a channel without a done signal
+		{RuleMutex, 61, 61},         // Never unlocked
+		// Goroutines writing their own index of a slice are fine
+	}
+	if len(regions) != len(want) {
+		for _, r := range regions {
+			t.Logf("%d-%d %s %s", r.StartLine, r.EndLine, r.Rule, r.Message)
+		}
+		t.Fatalf("Check() = %d regions, want %d", len(regions), len(want))
+	}
+	for i, w := range want {
+		if r := regions[i]; r.Rule != w.rule || r.StartLine != w.start || r.EndLine != w.end || r.Message == "" {
+			t.Errorf("region %d = %+v, want %s at lines %d-%d", i, r, w.rule, w.start, w.end)
+		}
+	}
+}
+
+func TestCheckInvalidSource(t *testing.T) {
+	if _, err := Check("broken.go", []byte("package x\nfunc {")); err == nil {
+		t.Error("Check() accepted invalid source")
+	}
+}
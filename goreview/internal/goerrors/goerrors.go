@@ -0,0 +1,305 @@
+// Package goerrors audits Go error handling with syntactic checks on the
+// AST: swallowed errors, errors formatted without %w, comparisons and type
+// assertions that miss wrapped errors, errors.Is/As misuse and panics in
+// library code. Whether a flagged case is intended depends on context the
+// AST doesn't have; the errors review mode leaves that to the model.
+package goerrors
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// Rule IDs of the error handling checks
+const (
+	RuleSwallowed     = "errors/swallowed"
+	RuleUnwrapped     = "errors/unwrapped"
+	RuleCompare       = "errors/compare"
+	RuleTypeAssertion = "errors/type-assertion"
+	RuleAsTarget      = "errors/as-target"
+	RuleIsTarget      = "errors/is-target"
+	RulePanic         = "errors/panic"
+)
+
+// Finding is an error handling problem at a line of the file.
+type Finding struct {
+	Rule       string
+	Line       int
+	Severity   providers.Severity
+	Message    string
+	Suggestion string
+}
+
+// okResults matches functions whose last result is commonly discarded but
+// isn't an error, like sync.Map.Load or utf8.DecodeRuneInString
+var okResults = regexp.MustCompile(`(?i)^(load|lookup|decode(last)?rune|cut|get|peek)`)
+
+// Check parses a Go file and returns the error handling findings, in
+// source order.
+func Check(filename string, src []byte) ([]Finding, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{fset: fset, library: file.Name.Name != "main"}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			c.function(fn.Name.Name, fn.Type, fn.Body)
+		}
+	}
+	return c.findings, nil
+}
+
+type checker struct {
+	fset     *token.FileSet
+	library  bool
+	findings []Finding
+}
+
+func (c *checker) add(node ast.Node, rule string, severity providers.Severity, message, suggestion string) {
+	c.findings = append(c.findings, Finding{
+		Rule:       rule,
+		Line:       c.fset.Position(node.Pos()).Line,
+		Severity:   severity,
+		Message:    message,
+		Suggestion: suggestion,
+	})
+}
+
+// function checks a function body. Function literals are checked with their
+// own results but the name of the declaration they're in.
+func (c *checker) function(name string, typ *ast.FuncType, body *ast.BlockStmt) {
+	returnsError := lastResultIsError(typ)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			c.function(name, n.Type, n.Body)
+			return false
+		case *ast.IfStmt:
+			c.checkIf(n, returnsError)
+		case *ast.AssignStmt:
+			c.checkAssign(n)
+		case *ast.CallExpr:
+			c.checkCall(n, name)
+		case *ast.BinaryExpr:
+			c.checkCompare(n)
+		case *ast.TypeAssertExpr:
+			if isErrName(n.X) {
+				c.add(n, RuleTypeAssertion, providers.SeverityWarning,
+					"Type assertion on an error misses wrapped errors",
+					"Use errors.As to find the error type anywhere in the chain")
+			}
+		}
+		return true
+	})
+}
+
+// checkIf finds `if err != nil` blocks that drop the error: empty ones, and
+// ones returning a nil error from a function that returns errors.
+func (c *checker) checkIf(n *ast.IfStmt, returnsError bool) {
+	cond, ok := n.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.NEQ || !isErrName(cond.X) || !isNil(cond.Y) {
+		return
+	}
+	if len(n.Body.List) == 0 {
+		c.add(n, RuleSwallowed, providers.SeverityWarning,
+			"Error is checked but ignored",
+			"Return, wrap or log the error, or explain in a comment why it can be ignored")
+		return
+	}
+	if !returnsError {
+		return
+	}
+	for _, stmt := range n.Body.List {
+		if ret, ok := stmt.(*ast.ReturnStmt); ok && len(ret.Results) > 0 && isNil(ret.Results[len(ret.Results)-1]) {
+			c.add(ret, RuleSwallowed, providers.SeverityWarning,
+				"Error is checked, then a nil error is returned",
+				"Return the error, wrapped with context, unless dropping it is intended")
+		}
+	}
+}
+
+// checkAssign finds results assigned to the blank identifier: `_ = err`
+// and `v, _ := f()` where the discarded last result is likely an error.
+func (c *checker) checkAssign(n *ast.AssignStmt) {
+	if len(n.Lhs) == 1 && len(n.Rhs) == 1 && isBlank(n.Lhs[0]) && isErrName(n.Rhs[0]) {
+		c.add(n, RuleSwallowed, providers.SeverityWarning,
+			"Error is assigned to the blank identifier",
+			"Handle the error, or explain in a comment why it can be ignored")
+		return
+	}
+	if len(n.Lhs) < 2 || len(n.Rhs) != 1 || !isBlank(n.Lhs[len(n.Lhs)-1]) {
+		return
+	}
+	call, ok := n.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return // Map lookups, type assertions and receives return a bool
+	}
+	allBlank := true
+	for _, lhs := range n.Lhs {
+		allBlank = allBlank && isBlank(lhs)
+	}
+	name := funcName(call.Fun)
+	if allBlank || okResults.MatchString(name) {
+		return
+	}
+	c.add(n, RuleSwallowed, providers.SeverityWarning,
+		"The error returned by "+name+" is discarded",
+		"Check the error; if it can't happen, say why in a comment")
+}
+
+// checkCall checks fmt.Errorf and errors.New wrapping, errors.Is/As
+// arguments and panics.
+func (c *checker) checkCall(n *ast.CallExpr, function string) {
+	switch callee(n.Fun) {
+	case "fmt.Errorf":
+		if len(n.Args) < 2 {
+			return
+		}
+		format, ok := stringLit(n.Args[0])
+		if !ok || strings.Contains(format, "%w") {
+			return
+		}
+		for _, arg := range n.Args[1:] {
+			if isErrName(arg) || isErrorCall(arg) {
+				c.add(n, RuleUnwrapped, providers.SeverityWarning,
+					"Error is formatted without %w, so callers can't match it with errors.Is or errors.As",
+					"Use %w for the error argument")
+				return
+			}
+		}
+	case "errors.New":
+		if len(n.Args) == 1 && isErrorCall(n.Args[0]) {
+			c.add(n, RuleUnwrapped, providers.SeverityWarning,
+				"errors.New(err.Error()) drops the original error",
+				`Wrap it with fmt.Errorf("...: %w", err)`)
+		}
+	case "errors.As":
+		if len(n.Args) == 2 {
+			if u, ok := n.Args[1].(*ast.UnaryExpr); !ok || u.Op != token.AND {
+				c.add(n, RuleAsTarget, providers.SeverityError,
+					"errors.As target is not a pointer to a variable, so it panics or never matches",
+					"Pass the address of a variable of the error type: errors.As(err, &target)")
+			}
+		}
+	case "errors.Is":
+		if len(n.Args) == 2 {
+			if target, ok := n.Args[1].(*ast.CallExpr); ok {
+				if name := callee(target.Fun); name == "errors.New" || name == "fmt.Errorf" {
+					c.add(n, RuleIsTarget, providers.SeverityError,
+						"errors.Is compares against a new error value, which never matches",
+						"Compare against a sentinel error declared once at package level")
+				}
+			}
+		}
+	case "panic":
+		if c.library && function != "init" && !strings.HasPrefix(function, "Must") && !strings.HasPrefix(function, "must") {
+			c.add(n, RulePanic, providers.SeverityWarning,
+				"panic in library code crashes the caller's program",
+				"Return an error instead; keep panics for init and Must* helpers")
+		}
+	}
+}
+
+// checkCompare finds errors compared with == or != against sentinel errors.
+func (c *checker) checkCompare(n *ast.BinaryExpr) {
+	if n.Op != token.EQL && n.Op != token.NEQ {
+		return
+	}
+	if (isErrName(n.X) && isSentinel(n.Y)) || (isErrName(n.Y) && isSentinel(n.X)) {
+		c.add(n, RuleCompare, providers.SeverityWarning,
+			"Comparing errors with "+n.Op.String()+" misses wrapped errors",
+			"Use errors.Is(err, target)")
+	}
+}
+
+// lastResultIsError reports whether the function's last result is an error.
+func lastResultIsError(typ *ast.FuncType) bool {
+	if typ.Results == nil || len(typ.Results.List) == 0 {
+		return false
+	}
+	ident, ok := typ.Results.List[len(typ.Results.List)-1].Type.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// isErrName reports whether the expression is a variable named like an
+// error: err, or ending in Err or err.
+func isErrName(e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+	return ok && (ident.Name == "err" || strings.HasSuffix(ident.Name, "Err") || strings.HasSuffix(ident.Name, "err"))
+}
+
+// isSentinel reports whether the expression names a sentinel error, like
+// ErrNotFound, sql.ErrNoRows or io.EOF.
+func isSentinel(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return strings.HasPrefix(e.Name, "Err")
+	case *ast.SelectorExpr:
+		return strings.HasPrefix(e.Sel.Name, "Err") || e.Sel.Name == "EOF"
+	}
+	return false
+}
+
+// isErrorCall reports whether the expression is a call of err.Error().
+func isErrorCall(e ast.Expr) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Error" && isErrName(sel.X)
+}
+
+func isNil(e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
+
+func isBlank(e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// callee returns the called function as written, like "panic" or
+// "fmt.Errorf", or "" for other callees.
+func callee(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := f.X.(*ast.Ident); ok {
+			return pkg.Name + "." + f.Sel.Name
+		}
+	}
+	return ""
+}
+
+// funcName returns the name of the called function or method.
+func funcName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	case *ast.IndexExpr: // Generic instantiation
+		return funcName(f.X)
+	}
+	return "the call"
+}
+
+func stringLit(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
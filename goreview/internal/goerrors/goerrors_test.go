@@ -0,0 +1,108 @@
+package goerrors
+
+import "testing"
+
+const sample = `package store
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type NotFoundError struct{}
+
+func (NotFoundError) Error() string { return "not found" }
+
+func Load(path string) (int, error) {
+	data, err := read(path)
+	if err != nil {
+	}
+	n, _ := strconv.Atoi(data)
+	if err := check(n); err != nil {
+		return 0, nil
+	}
+	if err == io.EOF || err == ErrNotFound {
+		return 0, fmt.Errorf("loading %s: %v", path, err)
+	}
+	if _, ok := err.(NotFoundError); ok {
+		return 0, errors.New(err.Error())
+	}
+	var target NotFoundError
+	if errors.As(err, target) || errors.Is(err, errors.New("not found")) {
+		panic("unreachable")
+	}
+	_ = err
+	return n, fmt.Errorf("loading %s: %w", path, err)
+}
+
+func MustLoad(path string) int {
+	n, err := Load(path)
+	if err != nil {
+		panic(err)
+	}
+	go func() {
+		if err := check(n); err != nil {
+			panic(err)
+		}
+	}()
+	return n
+}
+
+func cache(m map[string]int, key string) int {
+	v, _ := m[key]
+	r, _ := lookupValue(key)
+	_, _ = fmt.Println(v, r)
+	return v
+}
+`
+
+func TestCheck(t *testing.T) {
+	findings, err := Check("store.go", []byte(sample))
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	want := []struct {
+		rule string
+		line int
+	}{
+		{RuleSwallowed, 18},     // Empty if err != nil
+		{RuleSwallowed, 20},     // n, _ := strconv.Atoi
+		{RuleSwallowed, 22},     // return 0, nil
+		{RuleCompare, 24},       // err == io.EOF
+		{RuleCompare, 24},       // err == ErrNotFound
+		{RuleUnwrapped, 25},     // %v
+		{RuleTypeAssertion, 27}, // err.(NotFoundError)
+		{RuleUnwrapped, 28},     // errors.New(err.Error())
+		{RuleAsTarget, 31},      // errors.As without &
+		{RuleIsTarget, 31},      // errors.Is with a new error
+		{RulePanic, 32},         // panic in a library function
+		{RuleSwallowed, 34},     // _ = err
+	}
+	if len(findings) != len(want) {
+		for _, f := range findings {
+			t.Logf("%d %s %s", f.Line, f.Rule, f.Message)
+		}
+		t.Fatalf("Check() = %d findings, want %d", len(findings), len(want))
+	}
+	for i, w := range want {
+		if f := findings[i]; f.Rule != w.rule || f.Line != w.line || f.Message == "" || f.Suggestion == "" {
+			t.Errorf("finding %d = %+v, want %s at line %d", i, f, w.rule, w.line)
+		}
+	}
+}
+
+func TestCheckMainPackagePanics(t *testing.T) {
+	src := "package main\n\nfunc main() {\n\tpanic(\"boom\")\n}\n"
+	findings, err := Check("main.go", []byte(src))
+	if err != nil || len(findings) != 0 {
+		t.Errorf("Check() = %+v, %v; want no findings in package main", findings, err)
+	}
+	if _, err := Check("broken.go", []byte("package x\nfunc {")); err == nil {
+		t.Error("Check() accepted invalid source")
+	}
+}
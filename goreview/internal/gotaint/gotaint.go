@@ -0,0 +1,478 @@
+// Package gotaint tracks user input through Go functions to injection
+// sinks. Each function of a file is checked on its own: values read from an
+// HTTP request are followed through assignments, concatenation and
+// formatting into SQL queries, commands, HTML output and file paths. The
+// tracking is syntactic and names variables by identifier, so a flow is
+// only certain when every assignment of the variables along it carries the
+// input unchecked; the security review mode reports certain flows and asks
+// the model to judge the others.
+package gotaint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Rule IDs of the flows, one per injection class
+const (
+	RuleSQL     = "taint/sql"
+	RuleCommand = "taint/command"
+	RuleHTML    = "taint/html"
+	RulePath    = "taint/path"
+)
+
+// Flow is user input reaching a sink.
+type Flow struct {
+	// Rule is the injection class of the sink
+	Rule       string
+	Source     string
+	SourceLine int
+	Sink       string
+	SinkLine   int
+	// Via are the variables the input went through, in order
+	Via []string
+	// Certain is set when nothing along the flow may check or replace the
+	// input
+	Certain bool
+}
+
+// Describe summarizes the flow in a sentence.
+func (f Flow) Describe() string {
+	via := ""
+	if len(f.Via) > 0 {
+		via = " via " + strings.Join(f.Via, " -> ")
+	}
+	return fmt.Sprintf("user input from %s (line %d) reaches %s (line %d)%s", f.Source, f.SourceLine, f.Sink, f.SinkLine, via)
+}
+
+// requestTypes are the parameter types of HTTP handlers whose values are
+// user input
+var requestTypes = map[string]bool{
+	"*http.Request": true, "*gin.Context": true, "echo.Context": true, "*fiber.Ctx": true,
+}
+
+// requestMethods read user input from a request
+var requestMethods = map[string]bool{
+	"FormValue": true, "PostFormValue": true, "Referer": true, "UserAgent": true, "Cookie": true,
+	"Query": true, "DefaultQuery": true, "GetQuery": true, "QueryParam": true, "QueryParams": true,
+	"Param": true, "PostForm": true, "DefaultPostForm": true, "GetHeader": true, "FormParams": true, "Params": true,
+}
+
+// requestFields hold user input in an *http.Request
+var requestFields = map[string]bool{
+	"URL": true, "Form": true, "PostForm": true, "MultipartForm": true, "Header": true, "Body": true,
+	"RequestURI": true, "Host": true,
+}
+
+// routerSources are functions returning request parameters
+var routerSources = map[string]bool{"mux.Vars": true, "chi.URLParam": true}
+
+// propagators are functions whose result carries the input of any argument
+var propagators = map[string]bool{
+	"fmt.Sprintf": true, "fmt.Sprint": true, "fmt.Sprintln": true,
+	"strings.Join": true, "strings.ToLower": true, "strings.ToUpper": true, "strings.TrimSpace": true,
+	"strings.Trim": true, "strings.TrimPrefix": true, "strings.TrimSuffix": true, "strings.Replace": true,
+	"strings.ReplaceAll": true, "strings.Split": true, "strings.Fields": true, "strings.Repeat": true,
+	"path.Join": true, "filepath.Join": true, "filepath.Clean": true, "path.Clean": true,
+	"io.ReadAll": true, "ioutil.ReadAll": true, "url.PathUnescape": true, "url.QueryUnescape": true,
+}
+
+// taintedMethods on a value carrying input return the input
+var taintedMethods = map[string]bool{"Query": true, "Get": true, "Values": true, "String": true, "Bytes": true, "Value": true}
+
+// sqlMethods take a query as their first argument, after the context
+var sqlMethods = map[string]bool{
+	"Query": true, "QueryRow": true, "Exec": true, "Prepare": true,
+	"QueryContext": true, "QueryRowContext": true, "ExecContext": true, "PrepareContext": true,
+	"Raw": true, "Select": true, "Get": true, "NamedExec": true, "MustExec": true,
+}
+
+// pathSinks open or write the file at their first argument
+var pathSinks = map[string]bool{
+	"os.Open": true, "os.OpenFile": true, "os.ReadFile": true, "os.WriteFile": true, "os.Create": true,
+	"os.Remove": true, "os.RemoveAll": true, "ioutil.ReadFile": true, "ioutil.WriteFile": true, "http.ServeFile": true,
+}
+
+// shells run their -c argument as a script
+var shells = map[string]bool{"sh": true, "bash": true, "zsh": true, "/bin/sh": true, "/bin/bash": true, "cmd": true, "powershell": true}
+
+// Check parses a Go file and returns the flows of user input to sinks in
+// its functions, in source order.
+func Check(filename string, src []byte) ([]Flow, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var flows []Flow
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			if fn.Body != nil {
+				flows = append(flows, checkFunction(fset, fn.Type, fn.Body)...)
+			}
+		case *ast.FuncLit:
+			flows = append(flows, checkFunction(fset, fn.Type, fn.Body)...)
+		}
+		return true
+	})
+	sort.SliceStable(flows, func(i, j int) bool { return flows[i].SinkLine < flows[j].SinkLine })
+	return flows, nil
+}
+
+// taint is the input a variable or expression carries.
+type taint struct {
+	source     string
+	sourceLine int
+	via        []string
+	certain    bool
+}
+
+// function is the taint state of a function body.
+type function struct {
+	fset *token.FileSet
+	// requests are the parameters holding a request
+	requests map[string]bool
+	// writers are the http.ResponseWriter parameters
+	writers map[string]bool
+	vars    map[string]*taint
+	// checked are the variables used in conditions, which may validate them
+	checked map[string]bool
+}
+
+func checkFunction(fset *token.FileSet, typ *ast.FuncType, body *ast.BlockStmt) []Flow {
+	f := &function{
+		fset: fset, requests: map[string]bool{}, writers: map[string]bool{},
+		vars: map[string]*taint{}, checked: map[string]bool{},
+	}
+	for _, field := range typ.Params.List {
+		t := types.ExprString(field.Type)
+		for _, name := range field.Names {
+			f.requests[name.Name] = f.requests[name.Name] || requestTypes[t]
+			f.writers[name.Name] = f.writers[name.Name] || t == "http.ResponseWriter"
+		}
+	}
+	for _, isRequest := range f.requests {
+		if isRequest {
+			return f.check(body)
+		}
+	}
+	return nil
+}
+
+func (f *function) check(body *ast.BlockStmt) []Flow {
+	assigns := f.collect(body)
+	// Propagate until no variable changes; each round can only add taint
+	// or lose certainty, so this ends
+	for changed := true; changed; {
+		changed = false
+		for _, a := range assigns {
+			if f.assign(a) {
+				changed = true
+			}
+		}
+	}
+
+	var flows []Flow
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false // Checked as functions of their own
+		}
+		if call, ok := n.(*ast.CallExpr); ok {
+			flows = append(flows, f.sink(call)...)
+		}
+		return true
+	})
+	return flows
+}
+
+// assignment is a value assigned to a variable.
+type assignment struct {
+	name  string
+	value ast.Expr
+	// index is the position of the variable in an assignment from a call
+	// returning several values; only the first gets the input
+	index int
+}
+
+// collect returns the assignments of the body and records the variables
+// used in conditions.
+func (f *function) collect(body *ast.BlockStmt) []assignment {
+	var assigns []assignment
+	add := func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, l := range lhs {
+			id, ok := l.(*ast.Ident)
+			if !ok || id.Name == "_" {
+				continue
+			}
+			switch {
+			case len(rhs) == len(lhs):
+				assigns = append(assigns, assignment{name: id.Name, value: rhs[i]})
+			case len(rhs) == 1:
+				assigns = append(assigns, assignment{name: id.Name, value: rhs[0], index: i})
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			add(s.Lhs, s.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(s.Names))
+			for i, name := range s.Names {
+				lhs[i] = name
+			}
+			if len(s.Values) == 0 {
+				// A declaration without value is an assignment of the zero value
+				for _, name := range s.Names {
+					assigns = append(assigns, assignment{name: name.Name})
+				}
+			}
+			add(lhs, s.Values)
+		case *ast.RangeStmt:
+			if v, ok := s.Value.(*ast.Ident); ok {
+				assigns = append(assigns, assignment{name: v.Name, value: s.X})
+			}
+		case *ast.IfStmt:
+			f.markChecked(s.Cond)
+		case *ast.SwitchStmt:
+			if s.Tag != nil {
+				f.markChecked(s.Tag)
+			}
+		}
+		return true
+	})
+	return assigns
+}
+
+func (f *function) markChecked(cond ast.Expr) {
+	ast.Inspect(cond, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			f.checked[id.Name] = true
+		}
+		return true
+	})
+}
+
+// assign applies an assignment, reporting whether the variable's taint
+// changed. A variable is certain only while all its assignments carry
+// certain input.
+func (f *function) assign(a assignment) bool {
+	var t *taint
+	if a.value != nil && (a.index == 0 || !isCall(a.value)) {
+		t = f.taintOf(a.value)
+	}
+	cur, seen := f.vars[a.name]
+	if t == nil {
+		// An assignment of something else: the variable may not carry input
+		if seen && cur != nil && cur.certain {
+			cur.certain = false
+			return true
+		}
+		if !seen {
+			f.vars[a.name] = nil
+		}
+		return false
+	}
+	if cur == nil {
+		if seen {
+			// Assigned something else before
+			t = &taint{source: t.source, sourceLine: t.sourceLine, via: t.via}
+		}
+		nt := *t
+		nt.via = append(append([]string{}, t.via...), a.name)
+		nt.certain = t.certain && !f.checked[a.name] && !seen
+		f.vars[a.name] = &nt
+		return true
+	}
+	if cur.certain && (!t.certain || f.checked[a.name]) {
+		cur.certain = false
+		return true
+	}
+	return false
+}
+
+func isCall(e ast.Expr) bool {
+	_, ok := e.(*ast.CallExpr)
+	return ok
+}
+
+// taintOf returns the input an expression carries, or nil.
+func (f *function) taintOf(e ast.Expr) *taint {
+	switch x := e.(type) {
+	case *ast.Ident:
+		return f.vars[x.Name]
+	case *ast.ParenExpr:
+		return f.taintOf(x.X)
+	case *ast.StarExpr:
+		return f.taintOf(x.X)
+	case *ast.IndexExpr:
+		return f.taintOf(x.X)
+	case *ast.SliceExpr:
+		return f.taintOf(x.X)
+	case *ast.BinaryExpr:
+		if x.Op != token.ADD {
+			return nil
+		}
+		if t := f.taintOf(x.X); t != nil {
+			return t
+		}
+		return f.taintOf(x.Y)
+	case *ast.SelectorExpr:
+		if root, ok := x.X.(*ast.Ident); ok && f.requests[root.Name] && requestFields[x.Sel.Name] {
+			return f.source(x)
+		}
+		return f.taintOf(x.X)
+	case *ast.CallExpr:
+		return f.callTaint(x)
+	}
+	return nil
+}
+
+func (f *function) callTaint(call *ast.CallExpr) *taint {
+	name := types.ExprString(call.Fun)
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if root, ok := sel.X.(*ast.Ident); ok && f.requests[root.Name] && requestMethods[sel.Sel.Name] {
+			return f.source(call)
+		}
+		if routerSources[name] {
+			return f.source(call)
+		}
+		if taintedMethods[sel.Sel.Name] {
+			if t := f.taintOf(sel.X); t != nil {
+				return t
+			}
+		}
+	}
+	// Conversions like string(b) and []byte(s)
+	if len(call.Args) == 1 && (name == "string" || name == "[]byte" || name == "template.HTML") {
+		return f.taintOf(call.Args[0])
+	}
+	if propagators[name] {
+		for _, arg := range call.Args {
+			if t := f.taintOf(arg); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+func (f *function) source(e ast.Expr) *taint {
+	return &taint{source: types.ExprString(e), sourceLine: f.fset.Position(e.Pos()).Line, certain: true}
+}
+
+// sink returns the flows of input into a call, if it is a sink.
+func (f *function) sink(call *ast.CallExpr) []Flow {
+	name := types.ExprString(call.Fun)
+	flow := func(rule string, arg ast.Expr, certain bool) []Flow {
+		t := f.taintOf(arg)
+		if t == nil {
+			return nil
+		}
+		return []Flow{{
+			Rule: rule, Source: t.source, SourceLine: t.sourceLine, Sink: name,
+			SinkLine: f.fset.Position(call.Pos()).Line, Via: t.via, Certain: t.certain && certain,
+		}}
+	}
+
+	switch {
+	case name == "exec.Command" || name == "exec.CommandContext":
+		args := call.Args
+		if name == "exec.CommandContext" && len(args) > 0 {
+			args = args[1:]
+		}
+		return f.commandFlows(args, flow)
+	case pathSinks[name] && len(call.Args) > 0:
+		arg := call.Args[0]
+		if name == "http.ServeFile" && len(call.Args) == 3 {
+			arg = call.Args[2]
+		}
+		return flow(RulePath, arg, true)
+	case name == "template.HTML" && len(call.Args) == 1:
+		return flow(RuleHTML, call.Args[0], true)
+	case (name == "fmt.Fprintf" || name == "fmt.Fprint" || name == "fmt.Fprintln" || name == "io.WriteString") && len(call.Args) > 1:
+		if !f.isWriter(call.Args[0]) {
+			return nil
+		}
+		// Output with markup is HTML; otherwise the content type decides
+		markup := hasMarkup(call.Args[1])
+		for _, arg := range call.Args[1:] {
+			if flows := flow(RuleHTML, arg, markup); flows != nil {
+				return flows
+			}
+		}
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	if sel.Sel.Name == "Write" && f.isWriter(sel.X) && len(call.Args) == 1 {
+		return flow(RuleHTML, call.Args[0], false)
+	}
+	if sqlMethods[sel.Sel.Name] && !isPackage(sel.X) && len(call.Args) > 0 {
+		query := call.Args[0]
+		if strings.HasSuffix(sel.Sel.Name, "Context") && len(call.Args) > 1 {
+			query = call.Args[1]
+		}
+		if sel.Sel.Name == "Select" || sel.Sel.Name == "Get" {
+			// sqlx: Get(dest, query, args...)
+			if len(call.Args) < 2 {
+				return nil
+			}
+			query = call.Args[1]
+		}
+		// Input passed as a query parameter is safe; only the query itself
+		// is a sink
+		return flow(RuleSQL, query, true)
+	}
+	return nil
+}
+
+// commandFlows checks the name and arguments of a command. Input run by a
+// shell or as the program is certain; input as an argument of another
+// program may only inject options.
+func (f *function) commandFlows(args []ast.Expr, flow func(string, ast.Expr, bool) []Flow) []Flow {
+	if len(args) == 0 {
+		return nil
+	}
+	if flows := flow(RuleCommand, args[0], true); flows != nil {
+		return flows
+	}
+	program := strings.Trim(types.ExprString(args[0]), "\"`")
+	shell := shells[program] && len(args) > 2 && strings.Trim(types.ExprString(args[1]), "\"`") != ""
+	for _, arg := range args[1:] {
+		if flows := flow(RuleCommand, arg, shell); flows != nil {
+			return flows
+		}
+	}
+	return nil
+}
+
+func (f *function) isWriter(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && f.writers[id.Name]
+}
+
+// isPackage reports whether the receiver of a call looks like a package,
+// like strings in strings.Join, rather than a value.
+func isPackage(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && propagatorPackages[id.Name]
+}
+
+var propagatorPackages = map[string]bool{"strings": true, "fmt": true, "url": true, "path": true, "filepath": true, "http": true}
+
+// hasMarkup reports whether a literal format writes HTML tags.
+func hasMarkup(e ast.Expr) bool {
+	lit, ok := e.(*ast.BasicLit)
+	return ok && lit.Kind == token.STRING && strings.Contains(lit.Value, "<")
+}
@@ -0,0 +1,85 @@
+package gotaint
+
+import "testing"
+
+const sample = `package api
+
+import (
+	"database/sql"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+func Search(db *sql.DB, w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	query := "SELECT * FROM users WHERE name = '" + name + "'"
+	rows, err := db.Query(query)
+	_, _ = rows, err
+	db.QueryRow("SELECT * FROM users WHERE name = ?", name)
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	db.Exec(fmt.Sprintf("DELETE FROM users WHERE id = %d", id))
+	fmt.Fprintf(w, "<h1>Hello %s</h1>", name)
+}
+
+func Run(w http.ResponseWriter, r *http.Request) {
+	script := r.FormValue("script")
+	exec.Command("sh", "-c", script).Run()
+	exec.Command("git", "log", r.FormValue("ref")).Run()
+	file := filepath.Join("uploads", r.FormValue("file"))
+	if file == "" {
+		return
+	}
+	os.ReadFile(file)
+	var page string
+	page = r.FormValue("page")
+	page = "index"
+	_ = template.HTML(page)
+}
+
+func Helper(path string) {
+	os.ReadFile(path)
+}
+`
+
+func TestCheck(t *testing.T) {
+	flows, err := Check("api.go", []byte(sample))
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	want := []struct {
+		rule    string
+		line    int
+		certain bool
+	}{
+		{RuleSQL, 17, true},      // Concatenated query
+		{RuleHTML, 22, true},     // Markup written with the input
+		{RuleCommand, 27, true},  // Script run by a shell
+		{RuleCommand, 28, false}, // Argument of another program
+		{RulePath, 33, false},    // Path checked by a condition
+		{RuleHTML, 37, false},    // Variable reassigned to a constant
+	}
+	if len(flows) != len(want) {
+		for _, f := range flows {
+			t.Logf("%d %s %s", f.SinkLine, f.Rule, f.Describe())
+		}
+		t.Fatalf("Check() = %d flows, want %d", len(flows), len(want))
+	}
+	for i, w := range want {
+		if f := flows[i]; f.Rule != w.rule || f.SinkLine != w.line || f.Certain != w.certain {
+			t.Errorf("flow %d = %+v, want %s at line %d (certain %v)", i, f, w.rule, w.line, w.certain)
+		}
+	}
+
+	if got := flows[0].Describe(); got != `user input from r.URL (line 15) reaches db.Query (line 17) via name -> query` {
+		t.Errorf("Describe() = %q", got)
+	}
+	if _, err := Check("broken.go", []byte("package x\nfunc {")); err == nil {
+		t.Error("Check() accepted invalid source")
+	}
+}
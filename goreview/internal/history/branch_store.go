@@ -0,0 +1,73 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoBranchReview is returned when a branch has no stored review.
+var ErrNoBranchReview = errors.New("no stored review for branch")
+
+// branchesDir returns the directory holding branch review snapshots.
+func (cs *CommitStore) branchesDir() string {
+	return filepath.Join(filepath.Dir(cs.baseDir), "branches")
+}
+
+// branchFile returns the snapshot path for a branch. Slashes in branch
+// names (feature/x) are flattened.
+func (cs *CommitStore) branchFile(branch string) string {
+	name := strings.NewReplacer("/", "__", "\\", "__", "..", "_").Replace(branch)
+	return filepath.Join(cs.branchesDir(), name+".json")
+}
+
+// SaveBranchReview stores the latest review of a branch, replacing any previous one.
+func (cs *CommitStore) SaveBranchReview(review *BranchReview) error {
+	if review.Branch == "" {
+		return fmt.Errorf("branch name is required")
+	}
+	if err := os.MkdirAll(cs.branchesDir(), 0750); err != nil { // #nosec G301
+		return fmt.Errorf("creating branches directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(review, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling branch review: %w", err)
+	}
+	if err := os.WriteFile(cs.branchFile(review.Branch), data, 0600); err != nil {
+		return fmt.Errorf("writing branch review: %w", err)
+	}
+	return nil
+}
+
+// LoadBranchReview retrieves the latest review of a branch.
+func (cs *CommitStore) LoadBranchReview(branch string) (*BranchReview, error) {
+	data, err := os.ReadFile(cs.branchFile(branch)) // #nosec G304 - path built from controlled components
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoBranchReview
+		}
+		return nil, fmt.Errorf("reading branch review: %w", err)
+	}
+
+	var review BranchReview
+	if err := json.Unmarshal(data, &review); err != nil {
+		return nil, fmt.Errorf("parsing branch review: %w", err)
+	}
+	return &review, nil
+}
+
+// GetHeadCommit returns the commit hash of HEAD.
+func GetHeadCommit(repoRoot string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
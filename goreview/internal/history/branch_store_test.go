@@ -0,0 +1,55 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBranchReviewRoundTrip(t *testing.T) {
+	repoRoot := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repoRoot, ".git"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewCommitStore(repoRoot)
+	if err != nil {
+		t.Fatalf("NewCommitStore() error = %v", err)
+	}
+
+	if _, err := store.LoadBranchReview("feature/login"); !errors.Is(err, ErrNoBranchReview) {
+		t.Fatalf("LoadBranchReview() error = %v, want ErrNoBranchReview", err)
+	}
+
+	review := &BranchReview{
+		Branch:     "feature/login",
+		BaseBranch: "main",
+		ReviewedAt: time.Now(),
+		Files: []ReviewedFile{{
+			Path: "auth.go",
+			Hunks: []ReviewedHunk{
+				{Fingerprint: "abc", Issues: []Issue{{ID: "1", Message: "unchecked error", Line: 12, Code: "f()"}}},
+				{Fingerprint: "def"},
+			},
+		}},
+	}
+	if err := store.SaveBranchReview(review); err != nil {
+		t.Fatalf("SaveBranchReview() error = %v", err)
+	}
+
+	loaded, err := store.LoadBranchReview("feature/login")
+	if err != nil {
+		t.Fatalf("LoadBranchReview() error = %v", err)
+	}
+	if len(loaded.Files) != 1 || len(loaded.Files[0].Hunks) != 2 {
+		t.Fatalf("loaded files = %+v, want 1 file with 2 hunks", loaded.Files)
+	}
+	if issue := loaded.Files[0].Hunks[0].Issues[0]; issue.Code != "f()" || issue.Line != 12 {
+		t.Errorf("loaded issue = %+v", issue)
+	}
+
+	if _, err := os.Stat(filepath.Join(repoRoot, ".git", "goreview", "branches", "feature__login.json")); err != nil {
+		t.Errorf("snapshot file not found: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PRComment is a review comment posted on a pull or merge request for an
+// issue, so later runs update or resolve it instead of posting it again.
+type PRComment struct {
+	// Platform is github or gitlab
+	Platform string `json:"platform"`
+	// Repo is the repository or project the request belongs to
+	Repo string `json:"repo"`
+	// Number is the pull or merge request number
+	Number int `json:"number"`
+	// Fingerprint identifies the issue; see IssueFingerprint
+	Fingerprint string `json:"fingerprint"`
+	FilePath    string `json:"file_path"`
+	CommentID   string `json:"comment_id"`
+	// ThreadID is the thread or discussion resolved along with the issue
+	ThreadID string `json:"thread_id,omitempty"`
+	// BodyHash tells whether the comment needs updating
+	BodyHash  string    `json:"body_hash"`
+	Resolved  bool      `json:"resolved"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PRComments returns the comments posted on a pull or merge request.
+func (s *Store) PRComments(ctx context.Context, platform, repo string, number int) ([]PRComment, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT platform, repo, number, fingerprint, file_path, comment_id, thread_id, body_hash, resolved, updated_at
+		FROM pr_comments
+		WHERE platform = ? AND repo = ? AND number = ?
+		ORDER BY updated_at ASC
+	`, platform, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("querying comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := make([]PRComment, 0)
+	for rows.Next() {
+		var c PRComment
+		var thread, hash sql.NullString
+		if err := rows.Scan(
+			&c.Platform, &c.Repo, &c.Number, &c.Fingerprint, &c.FilePath, &c.CommentID,
+			&thread, &hash, &c.Resolved, &c.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning comment: %w", err)
+		}
+		c.ThreadID, c.BodyHash = thread.String, hash.String
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// SavePRComment records a posted comment, replacing the one of the same
+// issue.
+func (s *Store) SavePRComment(ctx context.Context, c *PRComment) error {
+	c.UpdatedAt = time.Now()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO pr_comments (
+		platform, repo, number, fingerprint, file_path, comment_id, thread_id, body_hash, resolved, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(platform, repo, number, fingerprint) DO UPDATE SET
+		file_path = excluded.file_path, comment_id = excluded.comment_id, thread_id = excluded.thread_id,
+		body_hash = excluded.body_hash, resolved = excluded.resolved, updated_at = excluded.updated_at`,
+		c.Platform, c.Repo, c.Number, c.Fingerprint, c.FilePath, c.CommentID, c.ThreadID, c.BodyHash, c.Resolved, c.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("saving comment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StoreDebt records debt items. An item already recorded keeps its creation
+// time and author; its line, references and commit are updated.
+func (s *Store) StoreDebt(ctx context.Context, items []*DebtItem) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO debt_items (
+		repo, file_path, line, marker, text, owner, ticket, author, commit_hash, branch, created_at, updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(repo, file_path, marker, text) DO UPDATE SET
+		line = excluded.line, owner = excluded.owner, ticket = excluded.ticket,
+		commit_hash = excluded.commit_hash, branch = excluded.branch, updated_at = excluded.updated_at`)
+	if err != nil {
+		return fmt.Errorf("preparing statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, item := range items {
+		if item.CreatedAt.IsZero() {
+			item.CreatedAt = now
+		}
+		item.UpdatedAt = now
+		if _, err := stmt.ExecContext(ctx,
+			item.Repo, item.FilePath, item.Line, item.Marker, item.Text, item.Owner, item.Ticket,
+			item.Author, item.CommitHash, item.Branch, item.CreatedAt, item.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("inserting debt item: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListDebt returns the debt items of a repository, oldest first.
+func (s *Store) ListDebt(ctx context.Context, repo string) ([]DebtItem, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, repo, file_path, line, marker, text, owner, ticket, author, commit_hash, branch, created_at, updated_at
+		FROM debt_items
+		WHERE repo = ?
+		ORDER BY created_at ASC, id ASC
+	`, repo)
+	if err != nil {
+		return nil, fmt.Errorf("querying debt items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]DebtItem, 0)
+	for rows.Next() {
+		var item DebtItem
+		var line sql.NullInt64
+		var owner, ticket, author, commit, branch sql.NullString
+		if err := rows.Scan(
+			&item.ID, &item.Repo, &item.FilePath, &line, &item.Marker, &item.Text,
+			&owner, &ticket, &author, &commit, &branch, &item.CreatedAt, &item.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning debt item: %w", err)
+		}
+		item.Line = int(line.Int64)
+		item.Owner, item.Ticket, item.Author = owner.String, ticket.String, author.String
+		item.CommitHash, item.Branch = commit.String, branch.String
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// DeleteDebt removes debt items, such as comments no longer in the code.
+func (s *Store) DeleteDebt(ctx context.Context, ids []int64) error {
+	for _, id := range ids {
+		if _, err := s.db.ExecContext(ctx, "DELETE FROM debt_items WHERE id = ?", id); err != nil {
+			return fmt.Errorf("deleting debt item: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package history
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDebtRoundTrip(t *testing.T) {
+	store, err := NewStore(StoreConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	first := []*DebtItem{
+		{Repo: "/repo", FilePath: "a.go", Line: 3, Marker: "TODO", Text: "retry", Author: "alice", CreatedAt: old},
+		{Repo: "/repo", FilePath: "b.go", Line: 9, Marker: "FIXME", Text: "leak", Owner: "bob"},
+		{Repo: "/other", FilePath: "a.go", Line: 1, Marker: "TODO", Text: "elsewhere"},
+	}
+	if err := store.StoreDebt(ctx, first); err != nil {
+		t.Fatalf("StoreDebt() error = %v", err)
+	}
+
+	// Seen again on another line and by another author: line moves, age and author stay
+	again := []*DebtItem{{Repo: "/repo", FilePath: "a.go", Line: 7, Marker: "TODO", Text: "retry", Author: "carol", Ticket: "PROJ-1"}}
+	if err := store.StoreDebt(ctx, again); err != nil {
+		t.Fatalf("StoreDebt() error = %v", err)
+	}
+
+	items, err := store.ListDebt(ctx, "/repo")
+	if err != nil {
+		t.Fatalf("ListDebt() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("items = %+v, want 2 for /repo", items)
+	}
+	got := items[0]
+	if got.FilePath != "a.go" || got.Line != 7 || got.Author != "alice" || got.Ticket != "PROJ-1" {
+		t.Errorf("updated item = %+v", got)
+	}
+	if got.CreatedAt.Sub(old).Abs() > time.Second {
+		t.Errorf("CreatedAt = %v, want first seen %v", got.CreatedAt, old)
+	}
+
+	if err := store.DeleteDebt(ctx, []int64{got.ID}); err != nil {
+		t.Fatalf("DeleteDebt() error = %v", err)
+	}
+	items, _ = store.ListDebt(ctx, "/repo")
+	if len(items) != 1 || items[0].Text != "leak" {
+		t.Errorf("items after delete = %+v", items)
+	}
+}
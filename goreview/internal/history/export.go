@@ -0,0 +1,113 @@
+package history
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/parquet"
+)
+
+// exportColumns are the columns of tabular history exports, in order
+var exportColumns = []parquet.Column{
+	{Name: "id", Type: parquet.Int64},
+	{Name: "project", Type: parquet.String},
+	{Name: "commit_hash", Type: parquet.String},
+	{Name: "branch", Type: parquet.String},
+	{Name: "author", Type: parquet.String},
+	{Name: "file_path", Type: parquet.String},
+	{Name: "line", Type: parquet.Int32, Optional: true},
+	{Name: "issue_type", Type: parquet.String},
+	{Name: "severity", Type: parquet.String},
+	{Name: "message", Type: parquet.String},
+	{Name: "suggestion", Type: parquet.String},
+	{Name: "created_at", Type: parquet.Timestamp},
+	{Name: "resolved", Type: parquet.Bool},
+	{Name: "resolved_at", Type: parquet.Timestamp, Optional: true},
+	{Name: "review_round", Type: parquet.Int32},
+}
+
+// ExportFormats lists the supported history export formats.
+var ExportFormats = []string{"csv", "parquet"}
+
+// Export writes the records in the given format (csv or parquet), one row
+// per issue. project fills the project column so exports of several
+// repositories can be combined.
+func Export(w io.Writer, format string, records []ReviewRecord, project string) error {
+	switch format {
+	case "csv":
+		return exportCSV(w, records, project)
+	case "parquet":
+		rows := make([][]interface{}, len(records))
+		for i, r := range records {
+			rows[i] = exportRow(r, project)
+		}
+		return parquet.Write(w, exportColumns, rows)
+	default:
+		return fmt.Errorf("unknown export format: %s (supported: csv, parquet)", format)
+	}
+}
+
+// exportRow returns the column values of a record.
+func exportRow(r ReviewRecord, project string) []interface{} {
+	var line, resolvedAt interface{}
+	if r.Line > 0 {
+		line = int32(r.Line) // #nosec G115 - line numbers fit in int32
+	}
+	if r.Resolved && !r.ResolvedAt.IsZero() {
+		resolvedAt = r.ResolvedAt.UTC()
+	}
+	return []interface{}{
+		r.ID, project, r.CommitHash, r.Branch, r.Author, r.FilePath, line,
+		r.IssueType, r.Severity, r.Message, r.Suggestion, r.CreatedAt.UTC(),
+		r.Resolved, resolvedAt, int32(r.ReviewRound), // #nosec G115 - small
+	}
+}
+
+func exportCSV(w io.Writer, records []ReviewRecord, project string) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(exportColumns))
+	for i, col := range exportColumns {
+		header[i] = col.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		row := exportRow(r, project)
+		fields := make([]string, len(row))
+		for i, v := range row {
+			fields[i] = csvValue(v)
+		}
+		if err := cw.Write(fields); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvValue formats a value for CSV; nil becomes an empty field and times use RFC 3339.
+func csvValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case int32:
+		return strconv.FormatInt(int64(x), 10)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case bool:
+		return strconv.FormatBool(x)
+	case time.Time:
+		return x.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(x)
+	}
+}
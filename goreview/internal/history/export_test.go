@@ -0,0 +1,75 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+)
+
+func TestExportCSV(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	records := []ReviewRecord{
+		{ID: 1, CommitHash: "abc123", FilePath: "main.go", IssueType: "bug", Severity: "error",
+			Message: "Unchecked error, \"err\" ignored", Line: 12, Author: "ana", Branch: "main",
+			CreatedAt: created, ReviewRound: 1},
+		{ID: 2, CommitHash: "abc123", FilePath: "util.go", IssueType: "style", Severity: "info",
+			Message: "Naming", CreatedAt: created, Resolved: true, ResolvedAt: created.Add(time.Hour), ReviewRound: 2},
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, "csv", records, "demo"); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 3 || len(rows[0]) != len(exportColumns) {
+		t.Fatalf("got %d rows of %d columns", len(rows), len(rows[0]))
+	}
+
+	col := make(map[string]int)
+	for i, name := range rows[0] {
+		col[name] = i
+	}
+	checks := []struct {
+		row  int
+		name string
+		want string
+	}{
+		{1, "project", "demo"},
+		{1, "line", "12"},
+		{1, "message", "Unchecked error, \"err\" ignored"},
+		{1, "created_at", "2024-01-15T10:00:00Z"},
+		{1, "resolved_at", ""},
+		{2, "line", ""},
+		{2, "resolved", "true"},
+		{2, "resolved_at", "2024-01-15T11:00:00Z"},
+		{2, "review_round", "2"},
+	}
+	for _, c := range checks {
+		if got := rows[c.row][col[c.name]]; got != c.want {
+			t.Errorf("row %d %s = %q, want %q", c.row, c.name, got, c.want)
+		}
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	records := []ReviewRecord{{ID: 1, FilePath: "main.go", Severity: "error", CreatedAt: time.Now()}}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, "parquet", records, "demo"); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("PAR1")) || !bytes.HasSuffix(buf.Bytes(), []byte("PAR1")) {
+		t.Error("output is not a Parquet file")
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if err := Export(&bytes.Buffer{}, "xlsx", nil, ""); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -3,6 +3,7 @@ package history
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -99,6 +100,39 @@ func (s *Store) migrate() error {
 		`CREATE INDEX IF NOT EXISTS idx_reviews_severity ON reviews(severity)`,
 		`CREATE INDEX IF NOT EXISTS idx_reviews_created ON reviews(created_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_reviews_resolved ON reviews(resolved)`,
+
+		// Technical debt comments, one row per comment
+		`CREATE TABLE IF NOT EXISTS debt_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			repo TEXT NOT NULL,
+			file_path TEXT NOT NULL,
+			line INTEGER,
+			marker TEXT NOT NULL,
+			text TEXT NOT NULL,
+			owner TEXT,
+			ticket TEXT,
+			author TEXT,
+			commit_hash TEXT,
+			branch TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(repo, file_path, marker, text)
+		)`,
+
+		// Review comments posted on pull and merge requests, one per issue
+		`CREATE TABLE IF NOT EXISTS pr_comments (
+			platform TEXT NOT NULL,
+			repo TEXT NOT NULL,
+			number INTEGER NOT NULL,
+			fingerprint TEXT NOT NULL,
+			file_path TEXT NOT NULL,
+			comment_id TEXT NOT NULL,
+			thread_id TEXT,
+			body_hash TEXT,
+			resolved BOOLEAN DEFAULT FALSE,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(platform, repo, number, fingerprint)
+		)`,
 	}
 
 	for _, m := range migrations {
@@ -107,21 +141,97 @@ func (s *Store) migrate() error {
 		}
 	}
 
+	// Columns added after the first release
+	for _, col := range []struct{ name, definition string }{
+		{"environment", "TEXT"},
+		{"fingerprint", "TEXT"},
+		{"status", "TEXT DEFAULT 'open'"},
+		{"assignee", "TEXT"},
+		{"notes", "TEXT"},
+		{"rule_id", "TEXT"},
+	} {
+		if err := s.addColumnIfMissing("reviews", col.name, col.definition); err != nil {
+			return err
+		}
+	}
+
+	// Issues resolved before triage existed
+	if _, err := s.db.Exec(`UPDATE reviews SET status = 'resolved' WHERE resolved AND status = 'open'`); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_reviews_fingerprint ON reviews(fingerprint)`); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
 	return nil
 }
 
-// Store saves a review record.
-func (s *Store) Store(ctx context.Context, record *ReviewRecord) error {
-	query := `INSERT INTO reviews (
-		commit_hash, file_path, issue_type, severity, message, suggestion,
-		line, author, branch, created_at, resolved, review_round
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+// addColumnIfMissing adds a column to a table created by an older version.
+func (s *Store) addColumnIfMissing(table, column, definition string) error {
+	rows, err := s.db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return fmt.Errorf("reading %s columns: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("reading %s columns: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading %s columns: %w", table, err)
+	}
+
+	// #nosec G202 - table, column and definition are constants
+	if _, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("adding %s.%s: %w", table, column, err)
+	}
+	return nil
+}
 
-	result, err := s.db.ExecContext(ctx, query,
+// insertRecordQuery inserts a review record; see recordValues.
+const insertRecordQuery = `INSERT INTO reviews (
+	commit_hash, file_path, issue_type, severity, message, suggestion,
+	line, author, branch, created_at, resolved, review_round, environment,
+	fingerprint, status, assignee, notes, rule_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// recordColumns are the columns read by scanSearchRow, in order.
+const recordColumns = `id, commit_hash, file_path, issue_type, severity, message, suggestion,
+	line, author, branch, created_at, resolved, resolved_at, review_round, environment,
+	fingerprint, status, assignee, notes, rule_id`
+
+// recordValues returns the values of insertRecordQuery for a record. A
+// record without a status is open, or resolved when marked so.
+func recordValues(record *ReviewRecord, env interface{}) []interface{} {
+	status := record.Status
+	if status == "" {
+		status = StatusOpen
+		if record.Resolved {
+			status = StatusResolved
+		}
+	}
+	return []interface{}{
 		record.CommitHash, record.FilePath, record.IssueType, record.Severity,
 		record.Message, record.Suggestion, record.Line, record.Author,
-		record.Branch, record.CreatedAt, record.Resolved, record.ReviewRound,
-	)
+		record.Branch, record.CreatedAt, record.Resolved, record.ReviewRound, env,
+		record.Fingerprint, status, record.Assignee, record.Notes, record.RuleID,
+	}
+}
+
+// Store saves a review record.
+func (s *Store) Store(ctx context.Context, record *ReviewRecord) error {
+
+	env, err := encodeEnvironment(record.Environment)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, insertRecordQuery, recordValues(record, env)...)
 	if err != nil {
 		return fmt.Errorf("inserting record: %w", err)
 	}
@@ -140,21 +250,18 @@ func (s *Store) StoreBatch(ctx context.Context, records []*ReviewRecord) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	stmt, err := tx.PrepareContext(ctx, `INSERT INTO reviews (
-		commit_hash, file_path, issue_type, severity, message, suggestion,
-		line, author, branch, created_at, resolved, review_round
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	stmt, err := tx.PrepareContext(ctx, insertRecordQuery)
 	if err != nil {
 		return fmt.Errorf("preparing statement: %w", err)
 	}
 	defer stmt.Close()
 
 	for _, record := range records {
-		result, err := stmt.ExecContext(ctx,
-			record.CommitHash, record.FilePath, record.IssueType, record.Severity,
-			record.Message, record.Suggestion, record.Line, record.Author,
-			record.Branch, record.CreatedAt, record.Resolved, record.ReviewRound,
-		)
+		env, err := encodeEnvironment(record.Environment)
+		if err != nil {
+			return err
+		}
+		result, err := stmt.ExecContext(ctx, recordValues(record, env)...)
 		if err != nil {
 			return fmt.Errorf("inserting record: %w", err)
 		}
@@ -187,6 +294,20 @@ func (s *Store) Search(ctx context.Context, q SearchQuery) (*SearchResult, error
 	}, nil
 }
 
+// All returns every stored record, most recent first.
+func (s *Store) All(ctx context.Context) ([]ReviewRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+recordColumns+`
+		FROM reviews
+		ORDER BY created_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying records: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSearchRows(rows)
+}
+
 func buildSearchConditions(q SearchQuery) ([]string, []interface{}) {
 	var args []interface{}
 	var conditions []string
@@ -228,6 +349,14 @@ func buildSearchConditions(q SearchQuery) ([]string, []interface{}) {
 		conditions = append(conditions, "r.resolved = ?")
 		args = append(args, *q.Resolved)
 	}
+	if q.Status != "" {
+		conditions = append(conditions, "r.status = ?")
+		args = append(args, q.Status)
+	}
+	if q.Assignee != "" {
+		conditions = append(conditions, "r.assignee = ?")
+		args = append(args, q.Assignee)
+	}
 
 	return conditions, args
 }
@@ -254,9 +383,7 @@ func (s *Store) fetchSearchRecords(ctx context.Context, whereClause string, args
 	}
 
 	// #nosec G202 - whereClause built with parameterized placeholders, safe from injection
-	selectQuery := `
-		SELECT id, commit_hash, file_path, issue_type, severity, message, suggestion,
-		       line, author, branch, created_at, resolved, resolved_at, review_round
+	selectQuery := `SELECT ` + recordColumns + `
 		FROM reviews r
 		` + whereClause + `
 		ORDER BY created_at DESC
@@ -288,13 +415,15 @@ func scanSearchRows(rows *sql.Rows) ([]ReviewRecord, error) {
 func scanSearchRow(rows *sql.Rows) (ReviewRecord, error) {
 	var r ReviewRecord
 	var resolvedAt sql.NullTime
-	var suggestion, author, branch sql.NullString
+	var suggestion, author, branch, env sql.NullString
+	var fingerprint, status, assignee, notes, ruleID sql.NullString
 	var line sql.NullInt64
 
 	if err := rows.Scan(
 		&r.ID, &r.CommitHash, &r.FilePath, &r.IssueType, &r.Severity,
 		&r.Message, &suggestion, &line, &author, &branch,
-		&r.CreatedAt, &r.Resolved, &resolvedAt, &r.ReviewRound,
+		&r.CreatedAt, &r.Resolved, &resolvedAt, &r.ReviewRound, &env,
+		&fingerprint, &status, &assignee, &notes, &ruleID,
 	); err != nil {
 		return ReviewRecord{}, fmt.Errorf("scanning row: %w", err)
 	}
@@ -314,10 +443,30 @@ func scanSearchRow(rows *sql.Rows) (ReviewRecord, error) {
 	if resolvedAt.Valid {
 		r.ResolvedAt = resolvedAt.Time
 	}
+	r.Fingerprint, r.Status, r.Assignee, r.Notes = fingerprint.String, status.String, assignee.String, notes.String
+	r.RuleID = ruleID.String
+	if env.Valid && env.String != "" {
+		r.Environment = &Environment{}
+		if err := json.Unmarshal([]byte(env.String), r.Environment); err != nil {
+			return ReviewRecord{}, fmt.Errorf("parsing environment: %w", err)
+		}
+	}
 
 	return r, nil
 }
 
+// encodeEnvironment stores the environment as JSON, or NULL when unset.
+func encodeEnvironment(env *Environment) (interface{}, error) {
+	if env == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("encoding environment: %w", err)
+	}
+	return string(data), nil
+}
+
 // GetFileHistory returns the review history for a file or directory.
 func (s *Store) GetFileHistory(ctx context.Context, path string) (*FileHistory, error) {
 	pattern := buildFilePattern(path)
@@ -514,7 +663,7 @@ func (s *Store) GetStats(ctx context.Context) (*Stats, error) {
 // MarkResolved marks an issue as resolved.
 func (s *Store) MarkResolved(ctx context.Context, id int64) error {
 	_, err := s.db.ExecContext(ctx, `
-		UPDATE reviews SET resolved = TRUE, resolved_at = ? WHERE id = ?
+		UPDATE reviews SET resolved = TRUE, resolved_at = ?, status = 'resolved' WHERE id = ?
 	`, time.Now(), id)
 	return err
 }
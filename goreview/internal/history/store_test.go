@@ -2,6 +2,7 @@ package history
 
 import (
 	"context"
+	"database/sql"
 	"os"
 	"path/filepath"
 	"testing"
@@ -375,3 +376,104 @@ func TestMarkResolved(t *testing.T) {
 		t.Errorf("Expected 1 resolved issue, got %d", result.TotalCount)
 	}
 }
+
+func TestAll(t *testing.T) {
+	store, err := NewStore(StoreConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+	for i := 0; i < 150; i++ {
+		record := &ReviewRecord{
+			CommitHash: "abc123",
+			FilePath:   "main.go",
+			IssueType:  "bug",
+			Severity:   "warning",
+			Message:    "issue",
+			CreatedAt:  now.Add(time.Duration(i) * time.Minute),
+		}
+		if err := store.Store(ctx, record); err != nil {
+			t.Fatalf("Failed to store record: %v", err)
+		}
+	}
+
+	records, err := store.All(ctx)
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	// Unlike Search, All is not limited to a page of results
+	if len(records) != 150 {
+		t.Errorf("Expected 150 records, got %d", len(records))
+	}
+	if !records[0].CreatedAt.After(records[len(records)-1].CreatedAt) {
+		t.Error("Expected most recent record first")
+	}
+}
+
+func TestStoreEnvironment(t *testing.T) {
+	store, err := NewStore(StoreConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	env := &Environment{Version: "1.2.0", Provider: "ollama", Model: "qwen2.5-coder:14b", Temperature: 0.1, RulesHash: "abc", CommitSHA: "def"}
+	records := []*ReviewRecord{
+		{CommitHash: "abc123", FilePath: "main.go", IssueType: "bug", Severity: "error", Message: "with env", CreatedAt: time.Now(), Environment: env},
+		{CommitHash: "abc123", FilePath: "util.go", IssueType: "bug", Severity: "error", Message: "without env", CreatedAt: time.Now().Add(-time.Minute)},
+	}
+	if err := store.StoreBatch(ctx, records); err != nil {
+		t.Fatalf("StoreBatch failed: %v", err)
+	}
+
+	all, err := store.All(ctx)
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if all[0].Environment == nil || *all[0].Environment != *env {
+		t.Errorf("Environment = %+v, want %+v", all[0].Environment, env)
+	}
+	if all[1].Environment != nil {
+		t.Errorf("Expected no environment, got %+v", all[1].Environment)
+	}
+}
+
+func TestMigrateAddsEnvironmentColumn(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old.db")
+
+	// A database created before the environment column existed
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE TABLE reviews (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, commit_hash TEXT NOT NULL, file_path TEXT NOT NULL,
+		issue_type TEXT NOT NULL, severity TEXT NOT NULL, message TEXT NOT NULL, suggestion TEXT,
+		line INTEGER, author TEXT, branch TEXT, created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		resolved BOOLEAN DEFAULT FALSE, resolved_at DATETIME, review_round INTEGER DEFAULT 1
+	)`)
+	if err == nil {
+		_, err = db.Exec(`INSERT INTO reviews (commit_hash, file_path, issue_type, severity, message) VALUES ('a', 'old.go', 'bug', 'info', 'old')`)
+	}
+	_ = db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Opening it twice checks the migration is idempotent
+	for i := 0; i < 2; i++ {
+		store, err := NewStore(StoreConfig{Path: dbPath})
+		if err != nil {
+			t.Fatalf("NewStore() on old database error = %v", err)
+		}
+		records, err := store.All(context.Background())
+		_ = store.Close()
+		if err != nil || len(records) != 1 || records[0].Environment != nil {
+			t.Fatalf("All() = %+v, %v", records, err)
+		}
+	}
+}
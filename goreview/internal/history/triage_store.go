@@ -0,0 +1,285 @@
+package history
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Triage statuses of a stored issue.
+const (
+	StatusOpen         = "open"
+	StatusAcknowledged = "acknowledged"
+	StatusWontfix      = "wontfix"
+	StatusResolved     = "resolved"
+)
+
+// TriageStatuses lists the valid triage statuses.
+var TriageStatuses = []string{StatusOpen, StatusAcknowledged, StatusWontfix, StatusResolved}
+
+// ValidStatus reports whether s is a triage status.
+func ValidStatus(s string) bool {
+	return slices.Contains(TriageStatuses, s)
+}
+
+// ErrIssueNotFound is returned when triaging an issue ID not in the store.
+var ErrIssueNotFound = errors.New("issue not found")
+
+// IssueFingerprint identifies an issue across reviews by its file, type and
+// message. Line numbers are left out so an issue keeps its triage when code
+// moves; digits, case and spacing of the message are ignored because
+// reviewers reword them between runs.
+func IssueFingerprint(file, issueType, message string) string {
+	normalized := strings.Join(strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsDigit(r)
+	}), " ")
+	sum := sha256.Sum256([]byte(file + "\n" + issueType + "\n" + normalized))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// TriageState is the triage of an issue, shared by all its records.
+type TriageState struct {
+	// ID is the latest record of the issue, to triage it by
+	ID       int64
+	Status   string
+	Assignee string
+	Notes    string
+	// Rounds is how many reviews found the issue
+	Rounds int
+}
+
+// TriageStates returns the triage of the issues with the given fingerprints.
+// Fingerprints never stored are left out.
+func (s *Store) TriageStates(ctx context.Context, fingerprints []string) (map[string]TriageState, error) {
+	states := make(map[string]TriageState)
+	for _, fp := range fingerprints {
+		if _, ok := states[fp]; ok || fp == "" {
+			continue
+		}
+		var st TriageState
+		var status, assignee, notes sql.NullString
+		err := s.db.QueryRowContext(ctx, `
+			SELECT id, status, assignee, notes, review_round FROM reviews
+			WHERE fingerprint = ? ORDER BY id DESC LIMIT 1
+		`, fp).Scan(&st.ID, &status, &assignee, &notes, &st.Rounds)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("querying triage: %w", err)
+		}
+		st.Status, st.Assignee, st.Notes = status.String, assignee.String, notes.String
+		states[fp] = st
+	}
+	return states, nil
+}
+
+// RecordIssues stores the issues found by a review. Issues already stored
+// keep their triage and count one more review round; a resolved issue found
+// again is reopened.
+func (s *Store) RecordIssues(ctx context.Context, records []*ReviewRecord) error {
+	fingerprints := make([]string, len(records))
+	for i, r := range records {
+		fingerprints[i] = r.Fingerprint
+	}
+	states, err := s.TriageStates(ctx, fingerprints)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		r.Status, r.ReviewRound = StatusOpen, 1
+		if st, ok := states[r.Fingerprint]; ok {
+			r.Assignee, r.Notes, r.ReviewRound = st.Assignee, st.Notes, st.Rounds+1
+			if st.Status != StatusResolved && st.Status != "" {
+				r.Status = st.Status
+			}
+		}
+	}
+	return s.StoreBatch(ctx, records)
+}
+
+// Assign sets the assignee of an issue and every other record of it. An
+// empty assignee unassigns it.
+func (s *Store) Assign(ctx context.Context, id int64, assignee, note string) error {
+	entry := "assigned to " + assignee
+	if assignee == "" {
+		entry = "unassigned"
+	}
+	return s.updateTriage(ctx, id, "assignee = ?", []interface{}{assignee}, entry, note)
+}
+
+// Transition moves an issue and every other record of it to a status,
+// keeping the resolved flag in sync.
+func (s *Store) Transition(ctx context.Context, id int64, status, note string) error {
+	if !ValidStatus(status) {
+		return fmt.Errorf("invalid status %q (valid: %s)", status, strings.Join(TriageStatuses, ", "))
+	}
+	var resolvedAt interface{}
+	if status == StatusResolved {
+		resolvedAt = time.Now()
+	}
+	return s.updateTriage(ctx, id, "status = ?, resolved = ?, resolved_at = ?",
+		[]interface{}{status, status == StatusResolved, resolvedAt}, status, note)
+}
+
+// updateTriage applies set to the records of the issue with the given ID and
+// appends a dated line to their notes.
+func (s *Store) updateTriage(ctx context.Context, id int64, set string, args []interface{}, entry, note string) error {
+	line := time.Now().Format("2006-01-02") + " " + entry
+	if note != "" {
+		line += ": " + note
+	}
+
+	// #nosec G202 - set is a constant of the callers
+	query := `UPDATE reviews SET ` + set + `,
+		notes = CASE WHEN COALESCE(notes, '') = '' THEN ? ELSE notes || char(10) || ? END
+		WHERE id = ? OR (COALESCE(fingerprint, '') != '' AND fingerprint = (SELECT fingerprint FROM reviews WHERE id = ?))`
+	args = append(args, line, line, id, id)
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("updating triage: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %d", ErrIssueNotFound, id)
+	}
+	return nil
+}
+
+// TriageQuery filters the issues listed for triage.
+type TriageQuery struct {
+	Status   string
+	Assignee string
+	// File filters by file path (supports glob patterns)
+	File  string
+	Limit int
+}
+
+// ListTriage returns the latest record of each issue, most recent first.
+func (s *Store) ListTriage(ctx context.Context, q TriageQuery) ([]ReviewRecord, error) {
+	conditions, args := buildSearchConditions(SearchQuery{File: q.File, Status: q.Status, Assignee: q.Assignee})
+	conditions = append(conditions,
+		"r.id IN (SELECT MAX(id) FROM reviews GROUP BY COALESCE(NULLIF(fingerprint, ''), 'id:' || id))")
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	// #nosec G202 - conditions built with parameterized placeholders
+	query := `SELECT ` + recordColumns + `
+		FROM reviews r
+		` + buildWhereClause(conditions) + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?`
+	rows, err := s.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("querying triage: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSearchRows(rows)
+}
+
+// Issue returns the record with the given ID.
+func (s *Store) Issue(ctx context.Context, id int64) (*ReviewRecord, error) {
+	return s.queryIssue(ctx, `WHERE id = ?`, id)
+}
+
+// IssueAt returns the latest record of an issue found at the line of file.
+func (s *Store) IssueAt(ctx context.Context, file string, line int) (*ReviewRecord, error) {
+	return s.queryIssue(ctx, `WHERE file_path = ? AND line = ?`, file, line)
+}
+
+func (s *Store) queryIssue(ctx context.Context, where string, args ...interface{}) (*ReviewRecord, error) {
+	// #nosec G202 - where is a constant of the callers
+	rows, err := s.db.QueryContext(ctx, `SELECT `+recordColumns+`
+		FROM reviews
+		`+where+`
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying issue: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := scanSearchRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrIssueNotFound
+	}
+	return &records[0], nil
+}
+
+// RuleOutcome counts how the triaged issues of a rule ended.
+type RuleOutcome struct {
+	// Resolved counts the issues fixed
+	Resolved int
+	// Ignored counts the issues marked won't fix
+	Ignored int
+}
+
+// Triaged is the number of issues of the rule triaged to an outcome.
+func (o RuleOutcome) Triaged() int {
+	return o.Resolved + o.Ignored
+}
+
+// IgnoreRate is the fraction of the triaged issues marked won't fix, 0
+// when none was triaged.
+func (o RuleOutcome) IgnoreRate() float64 {
+	if o.Triaged() == 0 {
+		return 0
+	}
+	return float64(o.Ignored) / float64(o.Triaged())
+}
+
+// RuleKey is the key of an issue in RuleOutcomes: its rule ID or, for
+// issues raised by the model on its own, "type:" and its issue type.
+func RuleKey(ruleID, issueType string) string {
+	if ruleID != "" {
+		return ruleID
+	}
+	return "type:" + issueType
+}
+
+// RuleOutcomes returns how the issues of each rule ended, by RuleKey. Each
+// issue counts once, by the triage of its latest record.
+func (s *Store) RuleOutcomes(ctx context.Context) (map[string]RuleOutcome, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT COALESCE(rule_id, ''), issue_type, status, COUNT(*) FROM reviews
+		WHERE status IN (?, ?) AND id IN (
+			SELECT MAX(id) FROM reviews WHERE COALESCE(fingerprint, '') != '' GROUP BY fingerprint
+		)
+		GROUP BY 1, 2, 3
+	`, StatusResolved, StatusWontfix)
+	if err != nil {
+		return nil, fmt.Errorf("querying rule outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	outcomes := make(map[string]RuleOutcome)
+	for rows.Next() {
+		var ruleID, issueType, status string
+		var n int
+		if err := rows.Scan(&ruleID, &issueType, &status, &n); err != nil {
+			return nil, fmt.Errorf("scanning rule outcome: %w", err)
+		}
+		key := RuleKey(ruleID, issueType)
+		o := outcomes[key]
+		if status == StatusResolved {
+			o.Resolved += n
+		} else {
+			o.Ignored += n
+		}
+		outcomes[key] = o
+	}
+	return outcomes, rows.Err()
+}
@@ -0,0 +1,170 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssueFingerprint(t *testing.T) {
+	a := IssueFingerprint("a.go", "bug", "Error from Close  ignored on line 12")
+	if b := IssueFingerprint("a.go", "bug", "error from close ignored on line 40"); a != b {
+		t.Error("fingerprint changed with line numbers, case or spacing")
+	}
+	if b := IssueFingerprint("b.go", "bug", "Error from Close ignored on line 12"); a == b {
+		t.Error("fingerprint ignores the file")
+	}
+}
+
+func TestTriageWorkflow(t *testing.T) {
+	store, err := NewStore(StoreConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	issue := func(msg string) *ReviewRecord {
+		return &ReviewRecord{
+			CommitHash: "abc", FilePath: "a.go", IssueType: "bug", Severity: "warning", Message: msg,
+			CreatedAt: time.Now(), Fingerprint: IssueFingerprint("a.go", "bug", msg),
+		}
+	}
+	first := []*ReviewRecord{issue("unchecked error"), issue("shadowed variable")}
+	if err := store.RecordIssues(ctx, first); err != nil {
+		t.Fatalf("RecordIssues() error = %v", err)
+	}
+
+	if err := store.Assign(ctx, first[0].ID, "alice", ""); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if err := store.Transition(ctx, first[0].ID, StatusWontfix, "deliberate"); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+	if err := store.Transition(ctx, first[1].ID, StatusResolved, ""); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+
+	// The re-review keeps the triage, and reopens the resolved issue
+	again := []*ReviewRecord{issue("unchecked error"), issue("shadowed variable")}
+	if err := store.RecordIssues(ctx, again); err != nil {
+		t.Fatalf("RecordIssues() error = %v", err)
+	}
+	if again[0].Status != StatusWontfix || again[0].Assignee != "alice" || again[0].ReviewRound != 2 {
+		t.Errorf("re-reviewed issue = %+v, want wontfix, alice, round 2", again[0])
+	}
+	if !strings.Contains(again[0].Notes, "wontfix: deliberate") {
+		t.Errorf("Notes = %q, want the transition logged", again[0].Notes)
+	}
+	if again[1].Status != StatusOpen {
+		t.Errorf("resolved issue found again has status %q, want open", again[1].Status)
+	}
+
+	latest, err := store.ListTriage(ctx, TriageQuery{})
+	if err != nil {
+		t.Fatalf("ListTriage() error = %v", err)
+	}
+	if len(latest) != 2 {
+		t.Fatalf("ListTriage() = %d records, want one per issue", len(latest))
+	}
+	wontfix, _ := store.ListTriage(ctx, TriageQuery{Status: StatusWontfix, Assignee: "alice"})
+	if len(wontfix) != 1 || wontfix[0].ID != again[0].ID {
+		t.Errorf("ListTriage(wontfix, alice) = %+v", wontfix)
+	}
+
+	// Triaging the new record applies to the old one too
+	if err := store.Transition(ctx, again[0].ID, StatusAcknowledged, ""); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+	states, _ := store.TriageStates(ctx, []string{first[0].Fingerprint})
+	if st := states[first[0].Fingerprint]; st.Status != StatusAcknowledged || st.ID != again[0].ID || st.Rounds != 2 {
+		t.Errorf("TriageStates() = %+v", st)
+	}
+
+	if err := store.Transition(ctx, 999, StatusOpen, ""); !errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("Transition() of a missing issue error = %v, want ErrIssueNotFound", err)
+	}
+	if err := store.Transition(ctx, first[0].ID, "ignored", ""); err == nil {
+		t.Error("Transition() to an invalid status succeeded")
+	}
+}
+
+func TestIssueLookup(t *testing.T) {
+	store, err := NewStore(StoreConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	records := []*ReviewRecord{
+		{FilePath: "a.go", IssueType: "bug", Severity: "error", Message: "old", Line: 12, CreatedAt: time.Now().Add(-time.Hour)},
+		{FilePath: "a.go", IssueType: "bug", Severity: "error", Message: "new", Line: 12, CreatedAt: time.Now()},
+	}
+	if err := store.RecordIssues(ctx, records); err != nil {
+		t.Fatalf("RecordIssues() error = %v", err)
+	}
+
+	if r, err := store.Issue(ctx, records[0].ID); err != nil || r.Message != "old" {
+		t.Errorf("Issue() = %+v, %v", r, err)
+	}
+	if r, err := store.IssueAt(ctx, "a.go", 12); err != nil || r.Message != "new" {
+		t.Errorf("IssueAt() = %+v, %v, want the latest record", r, err)
+	}
+	if _, err := store.IssueAt(ctx, "a.go", 13); !errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("IssueAt() of a line without issues error = %v, want ErrIssueNotFound", err)
+	}
+}
+
+func TestRuleOutcomes(t *testing.T) {
+	store, err := NewStore(StoreConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	issue := func(ruleID, issueType, msg string) *ReviewRecord {
+		return &ReviewRecord{
+			CommitHash: "abc", FilePath: "a.go", IssueType: issueType, Severity: "warning", Message: msg,
+			RuleID: ruleID, CreatedAt: time.Now(), Fingerprint: IssueFingerprint("a.go", issueType, msg),
+		}
+	}
+	records := []*ReviewRecord{
+		issue("no-magic", "style", "magic number"),
+		issue("no-magic", "style", "another magic number"),
+		issue("no-magic", "style", "yet another constant"),
+		issue("", "bug", "nil dereference"),
+		issue("", "bug", "still open"),
+	}
+	if err := store.RecordIssues(ctx, records); err != nil {
+		t.Fatalf("RecordIssues() error = %v", err)
+	}
+	for i, status := range []string{StatusWontfix, StatusWontfix, StatusResolved, StatusResolved} {
+		if err := store.Transition(ctx, records[i].ID, status, ""); err != nil {
+			t.Fatalf("Transition() error = %v", err)
+		}
+	}
+	// A second round of the won't fix issue still counts once
+	if err := store.RecordIssues(ctx, []*ReviewRecord{issue("no-magic", "style", "magic number")}); err != nil {
+		t.Fatalf("RecordIssues() error = %v", err)
+	}
+
+	outcomes, err := store.RuleOutcomes(ctx)
+	if err != nil {
+		t.Fatalf("RuleOutcomes() error = %v", err)
+	}
+	want := map[string]RuleOutcome{"no-magic": {Resolved: 1, Ignored: 2}, "type:bug": {Resolved: 1}}
+	if len(outcomes) != len(want) || outcomes["no-magic"] != want["no-magic"] || outcomes["type:bug"] != want["type:bug"] {
+		t.Errorf("RuleOutcomes() = %+v, want %+v", outcomes, want)
+	}
+	if rate := outcomes["no-magic"].IgnoreRate(); rate < 0.66 || rate > 0.67 {
+		t.Errorf("IgnoreRate() = %v, want 2/3", rate)
+	}
+	if got, err := store.Issue(ctx, records[0].ID); err != nil || got.RuleID != "no-magic" {
+		t.Errorf("Issue() = %+v, %v; want rule ID kept", got, err)
+	}
+}
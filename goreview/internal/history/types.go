@@ -20,6 +20,7 @@ type CommitAnalysis struct {
 	Files       []AnalyzedFile  `json:"files"`
 	Summary     AnalysisSummary `json:"summary"`
 	Context     AnalysisContext `json:"context"`
+	Environment *Environment    `json:"environment,omitempty"`
 }
 
 // AnalyzedFile represents analysis of a single file in a commit.
@@ -42,6 +43,34 @@ type Issue struct {
 	EndLine    int        `json:"end_line,omitempty"`
 	RuleID     string     `json:"rule_id,omitempty"`
 	RootCause  *RootCause `json:"root_cause,omitempty"`
+	Code       string     `json:"code,omitempty"`
+	FixedCode  string     `json:"fixed_code,omitempty"`
+}
+
+// BranchReview is the latest review of a branch, kept per hunk so a later
+// re-review only sends hunks that changed since.
+// Stored in .git/goreview/branches/<branch>.json
+type BranchReview struct {
+	Branch      string          `json:"branch"`
+	BaseBranch  string          `json:"base_branch"`
+	HeadCommit  string          `json:"head_commit,omitempty"`
+	ReviewedAt  time.Time       `json:"reviewed_at"`
+	Context     AnalysisContext `json:"context"`
+	Environment *Environment    `json:"environment,omitempty"`
+	Files       []ReviewedFile  `json:"files"`
+}
+
+// ReviewedFile holds the reviewed hunks of a file.
+type ReviewedFile struct {
+	Path  string         `json:"path"`
+	Hunks []ReviewedHunk `json:"hunks"`
+}
+
+// ReviewedHunk is a hunk identified by a fingerprint of its changed lines,
+// with the issues found in it.
+type ReviewedHunk struct {
+	Fingerprint string  `json:"fingerprint"`
+	Issues      []Issue `json:"issues,omitempty"`
 }
 
 // RootCause represents root cause tracing information.
@@ -72,6 +101,19 @@ type AnalysisContext struct {
 	Config        map[string]string `json:"config,omitempty"`
 }
 
+// Environment records what produced a review, so its results can be traced
+// and reproduced later.
+type Environment struct {
+	Version     string  `json:"goreview_version"`
+	Provider    string  `json:"provider"`
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature"`
+	Preset      string  `json:"preset,omitempty"`
+	RulesHash   string  `json:"rules_hash,omitempty"`
+	PromptHash  string  `json:"prompt_hash,omitempty"`
+	CommitSHA   string  `json:"commit_sha,omitempty"`
+}
+
 // RecallResult represents a search match in historical commit data.
 type RecallResult struct {
 	CommitHash string    `json:"commit_hash"`
@@ -148,6 +190,36 @@ type ReviewRecord struct {
 	Resolved    bool      `json:"resolved"`
 	ResolvedAt  time.Time `json:"resolved_at,omitempty"`
 	ReviewRound int       `json:"review_round"`
+	// Environment is the reproducibility block of the review that found the issue
+	Environment *Environment `json:"environment,omitempty"`
+	// Fingerprint identifies the issue across reviews; see IssueFingerprint
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Status is the triage status: open, acknowledged, wontfix or resolved
+	Status   string `json:"status"`
+	Assignee string `json:"assignee,omitempty"`
+	// Notes is the triage log, one dated line per change
+	Notes string `json:"notes,omitempty"`
+	// RuleID is the configured rule that produced the issue, if any
+	RuleID string `json:"rule_id,omitempty"`
+}
+
+// DebtItem is a TODO/FIXME/HACK comment found in a reviewed change. Items
+// are keyed by repository, file, marker and text, so CreatedAt is when the
+// comment was first seen.
+type DebtItem struct {
+	ID         int64     `json:"id"`
+	Repo       string    `json:"repo"`
+	FilePath   string    `json:"file_path"`
+	Line       int       `json:"line"`
+	Marker     string    `json:"marker"`
+	Text       string    `json:"text"`
+	Owner      string    `json:"owner,omitempty"`
+	Ticket     string    `json:"ticket,omitempty"`
+	Author     string    `json:"author,omitempty"`
+	CommitHash string    `json:"commit_hash,omitempty"`
+	Branch     string    `json:"branch,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // SearchQuery represents a search query for review history.
@@ -170,6 +242,10 @@ type SearchQuery struct {
 	Until time.Time
 	// Resolved filters by resolved status (nil = all)
 	Resolved *bool
+	// Status filters by triage status
+	Status string
+	// Assignee filters by triage assignee
+	Assignee string
 	// Limit restricts result count
 	Limit int
 	// Offset for pagination
@@ -0,0 +1,237 @@
+// Package ignore decides which files goreview looks at. The include
+// patterns, when any, select the files; the exclude patterns of the config
+// and then the .gitignore-style ignore file drop files from them. Review,
+// fix, doc and audit share it, and goreview check-ignore explains its
+// decisions.
+package ignore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+// Reasons a file is left out
+const (
+	NotIncluded = "not_included"
+	Excluded    = "excluded"
+	IgnoreFile  = "ignore_file"
+)
+
+// Decision tells why a file is left out.
+type Decision struct {
+	// Reason is one of the reason constants, "" when the file is kept
+	Reason string
+	// Pattern is the deciding pattern; for IgnoreFile, the ignore file
+	// line, like ".goreviewignore:3: build/"
+	Pattern string
+}
+
+// Ignored reports whether the file is left out.
+func (d Decision) Ignored() bool {
+	return d.Reason != ""
+}
+
+// rule is a line of an ignore file.
+type rule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+	// source is "file:line: pattern" as written
+	source string
+}
+
+// Matcher applies the include patterns, exclude patterns and ignore file of
+// a repository.
+type Matcher struct {
+	root     string
+	includes []string
+	excludes []string
+	rules    []rule
+}
+
+// New returns the matcher of the git config. The ignore file is read
+// relative to root, the repository root; a missing one ignores nothing.
+func New(cfg config.GitConfig, root string) (*Matcher, error) {
+	m := &Matcher{root: root, includes: cfg.IncludePatterns, excludes: cfg.IgnorePatterns}
+	if cfg.IgnoreFile == "" {
+		return m, nil
+	}
+	name := cfg.IgnoreFile
+	if !filepath.IsAbs(name) && root != "" {
+		name = filepath.Join(root, name)
+	}
+	file, err := os.Open(name) //nolint:gosec // Ignore file named by the config
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading ignore file: %w", err)
+	}
+	defer file.Close()
+	m.rules = parse(file, cfg.IgnoreFile)
+	return m, nil
+}
+
+// NewFS returns the matcher of the git config for the files of fsys, like
+// a commit of a bare repository. A relative ignore file is read from fsys.
+func NewFS(cfg config.GitConfig, fsys fs.FS) (*Matcher, error) {
+	if filepath.IsAbs(cfg.IgnoreFile) {
+		return New(cfg, "")
+	}
+	m := &Matcher{includes: cfg.IncludePatterns, excludes: cfg.IgnorePatterns}
+	if cfg.IgnoreFile == "" {
+		return m, nil
+	}
+	file, err := fsys.Open(path.Clean(filepath.ToSlash(cfg.IgnoreFile)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading ignore file: %w", err)
+	}
+	defer file.Close()
+	m.rules = parse(file, cfg.IgnoreFile)
+	return m, nil
+}
+
+// parse parses .gitignore-style lines: "#" starts a comment, "!"
+// re-includes files, a trailing "/" only matches directories, and a
+// pattern with a leading or inner "/" is anchored to the repository root.
+func parse(r io.Reader, name string) []rule {
+	var out []rule
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := rule{source: fmt.Sprintf("%s:%d: %s", name, n, line)}
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		if strings.Contains(line, "/") && !strings.HasPrefix(line, "/") && !strings.HasPrefix(line, "**/") {
+			line = "/" + line
+		}
+		r.pattern = line
+		out = append(out, r)
+	}
+	return out
+}
+
+// Match decides on a path relative to the repository root.
+func (m *Matcher) Match(path string) Decision {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+	if len(m.includes) > 0 {
+		included := false
+		for _, p := range m.includes {
+			if MatchPattern(p, path) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return Decision{Reason: NotIncluded, Pattern: strings.Join(m.includes, ", ")}
+		}
+	}
+	if excluded := m.Excluded(path); excluded.Ignored() {
+		return excluded
+	}
+	return Decision{}
+}
+
+// Excluded decides on a path relative to the repository root with the
+// exclude patterns and the ignore file only, for files that aren't part of
+// a change, like the rest of the repository read for context.
+func (m *Matcher) Excluded(path string) Decision {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+	for _, p := range m.excludes {
+		if MatchPattern(p, path) {
+			return Decision{Reason: Excluded, Pattern: p}
+		}
+	}
+	if r, ok := m.ignored(path); ok {
+		return Decision{Reason: IgnoreFile, Pattern: r.source}
+	}
+	return Decision{}
+}
+
+// MatchFile decides on a path relative to the working directory or
+// absolute, as given on the command line. Paths outside the repository
+// root are matched as given.
+func (m *Matcher) MatchFile(path string) Decision {
+	return m.Match(m.Rel(path))
+}
+
+// Rel returns the path relative to the repository root.
+func (m *Matcher) Rel(path string) string {
+	if m.root == "" {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(m.root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
+
+// ignored returns the last ignore file rule matching the path or one of its
+// directories, when it doesn't re-include it. As in git, a file inside an
+// ignored directory can't be re-included.
+func (m *Matcher) ignored(path string) (rule, bool) {
+	parts := strings.Split(path, "/")
+	for i := 1; i <= len(parts); i++ {
+		prefix := strings.Join(parts[:i], "/")
+		isDir := i < len(parts)
+		var last *rule
+		for j := range m.rules {
+			r := &m.rules[j]
+			if r.dirOnly && !isDir {
+				continue
+			}
+			if rules.MatchGlob(r.pattern, prefix) {
+				last = r
+			}
+		}
+		if last != nil && !last.negate {
+			return *last, true
+		}
+	}
+	return rule{}, false
+}
+
+// MatchPattern matches a path against an include or exclude pattern: a
+// glob with "**" for any number of directories, or, as before globs were
+// supported, a prefix ending in "*" or an exact path.
+func MatchPattern(pattern, path string) bool {
+	if rules.MatchGlob(pattern, path) {
+		return true
+	}
+	if len(pattern) > 0 && pattern[len(pattern)-1] == '*' {
+		prefix := pattern[:len(pattern)-1]
+		return len(path) >= len(prefix) && path[:len(prefix)] == prefix
+	}
+	return pattern == path
+}
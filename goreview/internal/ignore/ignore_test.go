@@ -0,0 +1,92 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+func TestMatcher(t *testing.T) {
+	root := t.TempDir()
+	ignore := `# generated code
+build/
+*.snap
+!keep.snap
+/scripts/*.sh
+internal/**/mocks
+`
+	if err := os.WriteFile(filepath.Join(root, ".goreviewignore"), []byte(ignore), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.GitConfig{
+		IncludePatterns: []string{"internal/**/*.go", "cmd/**", "scripts/*.sh", "**/*.snap", "build/**"},
+		IgnorePatterns:  []string{"**/*_gen.go", "cmd/legacy*"},
+		IgnoreFile:      ".goreviewignore",
+	}
+	m, err := New(cfg, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, reason, pattern string
+	}{
+		{"internal/review/engine.go", "", ""},
+		{"cmd/goreview/main.go", "", ""},
+		{"README.md", NotIncluded, "internal/**/*.go, cmd/**, scripts/*.sh, **/*.snap, build/**"},
+		{"internal/api/types_gen.go", Excluded, "**/*_gen.go"},
+		{"cmd/legacy/main.go", Excluded, "cmd/legacy*"},
+		{"build/out/main.go", IgnoreFile, ".goreviewignore:2: build/"},
+		{"internal/ui/view.snap", IgnoreFile, ".goreviewignore:3: *.snap"},
+		{"internal/ui/keep.snap", "", ""},
+		{"scripts/release.sh", IgnoreFile, ".goreviewignore:5: /scripts/*.sh"},
+		{"internal/store/mocks/store.go", IgnoreFile, ".goreviewignore:6: internal/**/mocks"},
+	}
+	for _, tt := range tests {
+		d := m.Match(tt.path)
+		if d.Reason != tt.reason || d.Pattern != tt.pattern {
+			t.Errorf("Match(%q) = %q, %q, want %q, %q", tt.path, d.Reason, d.Pattern, tt.reason, tt.pattern)
+		}
+	}
+
+	if d := m.Excluded("README.md"); d.Ignored() {
+		t.Errorf("Excluded(README.md) = %+v, want kept: include patterns only select changed files", d)
+	}
+	if d := m.MatchFile(filepath.Join(root, "build", "x.go")); d.Reason != IgnoreFile {
+		t.Errorf("MatchFile(absolute) = %+v, want ignore_file", d)
+	}
+}
+
+func TestMatcherMissingIgnoreFile(t *testing.T) {
+	m, err := New(config.GitConfig{IgnoreFile: ".goreviewignore"}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := m.Match("main.go"); d.Ignored() {
+		t.Errorf("Match = %+v, want kept", d)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"vendor/*", "vendor/lib/file.go", true},
+		{"vendor/*", "src/file.go", false},
+		{"vendor/**", "vendor/lib/file.go", true},
+		{"**/*.pb.go", "api/v1/service.pb.go", true},
+		{"*.md", "docs/guide.md", true},
+		{"README.md", "README.md", true},
+		{"README.md", "CHANGELOG.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -49,6 +49,26 @@ func (f *Fetcher) FetchContext(ctx context.Context, query string) (*Context, err
 		return &Context{}, nil
 	}
 
+	allDocs := f.fetchAll(ctx, query)
+
+	// Limit total documents
+	maxDocs := f.config.MaxDocs
+	if maxDocs <= 0 {
+		maxDocs = 10
+	}
+	if len(allDocs) > maxDocs {
+		allDocs = allDocs[:maxDocs]
+	}
+
+	return &Context{
+		Documents:   allDocs,
+		TotalTokens: estimateTokens(allDocs),
+	}, nil
+}
+
+// fetchAll fetches the documents matching query from every enabled source.
+// Documents are tagged with their source's name.
+func (f *Fetcher) fetchAll(ctx context.Context, query string) []Document {
 	var allDocs []Document
 
 	for _, source := range f.config.Sources {
@@ -63,22 +83,13 @@ func (f *Fetcher) FetchContext(ctx context.Context, query string) (*Context, err
 			continue
 		}
 
+		for i := range docs {
+			docs[i].SourceName = source.Name
+		}
 		allDocs = append(allDocs, docs...)
 	}
 
-	// Limit total documents
-	maxDocs := f.config.MaxDocs
-	if maxDocs <= 0 {
-		maxDocs = 10
-	}
-	if len(allDocs) > maxDocs {
-		allDocs = allDocs[:maxDocs]
-	}
-
-	return &Context{
-		Documents:   allDocs,
-		TotalTokens: estimateTokens(allDocs),
-	}, nil
+	return allDocs
 }
 
 // fetchFromSource fetches documents from a single source.
@@ -285,8 +296,8 @@ func (f *Fetcher) fetchFromLocal(source Source, query string) ([]Document, error
 			return nil
 		}
 
-		// Check pattern match
-		matched, _ := filepath.Match(pattern, filepath.Base(path))
+		// Check pattern match; "**/" matches any directory
+		matched, _ := filepath.Match(strings.TrimPrefix(pattern, "**/"), filepath.Base(path))
 		if !matched && !strings.HasSuffix(pattern, "*") {
 			return nil
 		}
@@ -363,14 +374,14 @@ func (f *Fetcher) fetchFromGitHub(ctx context.Context, source Source, query stri
 	return parseGitHubContents(ctx, f.client, body, source, query)
 }
 
-// Search searches across all configured knowledge sources.
+// Search searches across all configured knowledge sources. Unlike
+// FetchContext, every matching document is ranked before the limit applies.
 func (f *Fetcher) Search(ctx context.Context, query SearchQuery) ([]SearchResult, error) {
-	docs, err := f.FetchContext(ctx, query.Text)
-	if err != nil {
-		return nil, err
+	if !f.config.Enabled {
+		return nil, nil
 	}
 
-	results := filterAndScoreDocuments(docs.Documents, query)
+	results := filterAndScoreDocuments(f.fetchAll(ctx, query.Text), query)
 	sortResultsByScore(results)
 	return applyResultLimit(results, query.Limit), nil
 }
@@ -415,7 +426,7 @@ func hasMatchingTag(docTags, queryTags []string) bool {
 }
 
 func sortResultsByScore(results []SearchResult) {
-	sort.Slice(results, func(i, j int) bool {
+	sort.SliceStable(results, func(i, j int) bool {
 		return results[i].Score > results[j].Score
 	})
 }
@@ -0,0 +1,131 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// ConfigFrom converts the knowledge section of goreview's configuration,
+// expanding environment variables in the sources' tokens.
+func ConfigFrom(c config.KnowledgeConfig) Config {
+	sources := make([]Source, 0, len(c.Sources))
+	for _, s := range c.Sources {
+		sources = append(sources, Source{
+			Type:              SourceType(s.Type),
+			Name:              s.Name,
+			Enabled:           s.Enabled,
+			NotionToken:       os.ExpandEnv(s.NotionToken),
+			NotionDatabaseID:  s.NotionDatabaseID,
+			NotionPageID:      s.NotionPageID,
+			ConfluenceURL:     s.ConfluenceURL,
+			ConfluenceUser:    s.ConfluenceUser,
+			ConfluenceToken:   os.ExpandEnv(s.ConfluenceToken),
+			ConfluenceSpace:   s.ConfluenceSpace,
+			ObsidianVaultPath: s.ObsidianVaultPath,
+			ObsidianTags:      s.ObsidianTags,
+			LocalPath:         s.LocalPath,
+			LocalPattern:      s.LocalPattern,
+			GitHubOwner:       s.GitHubOwner,
+			GitHubRepo:        s.GitHubRepo,
+			GitHubPath:        s.GitHubPath,
+		})
+	}
+	return Config{
+		Enabled:  c.Enabled,
+		Sources:  sources,
+		CacheDir: c.CacheDir,
+		MaxDocs:  c.MaxDocs,
+	}
+}
+
+// Validate checks that the source has the settings its type needs.
+func (s Source) Validate() error {
+	var missing []string
+	require := func(name, value string) {
+		if strings.TrimSpace(value) == "" {
+			missing = append(missing, name)
+		}
+	}
+
+	switch s.Type {
+	case SourceTypeNotion:
+		require("notion_token", s.NotionToken)
+		if s.NotionDatabaseID == "" && s.NotionPageID == "" {
+			missing = append(missing, "notion_database_id or notion_page_id")
+		}
+	case SourceTypeConfluence:
+		require("confluence_url", s.ConfluenceURL)
+		require("confluence_user", s.ConfluenceUser)
+		require("confluence_token", s.ConfluenceToken)
+	case SourceTypeObsidian:
+		require("obsidian_vault_path", s.ObsidianVaultPath)
+		if s.ObsidianVaultPath != "" {
+			return checkDir(s.ObsidianVaultPath)
+		}
+	case SourceTypeLocal:
+		require("local_path", s.LocalPath)
+		if s.LocalPath != "" {
+			return checkDir(s.LocalPath)
+		}
+	case SourceTypeGitHub:
+		require("github_owner", s.GitHubOwner)
+		require("github_repo", s.GitHubRepo)
+	default:
+		return fmt.Errorf("unknown source type: %s", s.Type)
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkDir checks that path is a readable directory.
+func checkDir(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+	return nil
+}
+
+// Location describes where the source's documents come from.
+func (s Source) Location() string {
+	switch s.Type {
+	case SourceTypeNotion:
+		if s.NotionDatabaseID != "" {
+			return "database " + s.NotionDatabaseID
+		}
+		return "page " + s.NotionPageID
+	case SourceTypeConfluence:
+		if s.ConfluenceSpace != "" {
+			return s.ConfluenceURL + " (space " + s.ConfluenceSpace + ")"
+		}
+		return s.ConfluenceURL
+	case SourceTypeObsidian:
+		return s.ObsidianVaultPath
+	case SourceTypeLocal:
+		return s.LocalPath
+	case SourceTypeGitHub:
+		return strings.TrimSuffix(s.GitHubOwner+"/"+s.GitHubRepo+"/"+s.GitHubPath, "/")
+	default:
+		return ""
+	}
+}
+
+// Test validates the source and fetches its documents, returning how many
+// it has. It works on disabled sources too.
+func (f *Fetcher) Test(ctx context.Context, source Source) (int, error) {
+	if err := source.Validate(); err != nil {
+		return 0, err
+	}
+	docs, err := f.fetchFromSource(ctx, source, "")
+	return len(docs), err
+}
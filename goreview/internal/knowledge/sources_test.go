@@ -0,0 +1,86 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+func TestConfigFromExpandsTokens(t *testing.T) {
+	t.Setenv("WIKI_TOKEN", "s3cret")
+	cfg := ConfigFrom(config.KnowledgeConfig{
+		Enabled: true,
+		Sources: []config.KnowledgeSourceConfig{{Name: "wiki", Type: "confluence", ConfluenceToken: "${WIKI_TOKEN}"}},
+	})
+	if got := cfg.Sources[0]; got.Type != SourceTypeConfluence || got.ConfluenceToken != "s3cret" {
+		t.Errorf("source = %+v", got)
+	}
+}
+
+func TestSourceValidate(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{"notion", Source{Type: SourceTypeNotion, NotionToken: "t", NotionPageID: "p"}, false},
+		{"notion without page", Source{Type: SourceTypeNotion, NotionToken: "t"}, true},
+		{"confluence without token", Source{Type: SourceTypeConfluence, ConfluenceURL: "https://wiki", ConfluenceUser: "u"}, true},
+		{"local", Source{Type: SourceTypeLocal, LocalPath: dir}, false},
+		{"missing vault", Source{Type: SourceTypeObsidian, ObsidianVaultPath: filepath.Join(dir, "none")}, true},
+		{"github", Source{Type: SourceTypeGitHub, GitHubOwner: "acme", GitHubRepo: "docs"}, false},
+		{"unknown", Source{Type: "drive"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.source.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSearchRanksAllDocuments(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		content := "nothing relevant"
+		if i == 4 {
+			content = "retry retry retry"
+		}
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("note%d.md", i)), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := NewFetcher(Config{
+		Enabled:  true,
+		CacheDir: t.TempDir(),
+		MaxDocs:  1, // Limits review context, not search
+		Sources:  []Source{{Name: "notes", Type: SourceTypeLocal, Enabled: true, LocalPath: dir}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := f.Search(context.Background(), SearchQuery{Text: "", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 5 {
+		t.Errorf("Search() returned %d results, want 5", len(results))
+	}
+
+	results, _ = f.Search(context.Background(), SearchQuery{Text: "retry", Limit: 10})
+	if len(results) != 1 || results[0].Document.Title != "note4" || results[0].Document.SourceName != "notes" {
+		t.Errorf("Search(retry) = %+v", results)
+	}
+
+	if n, err := f.Test(context.Background(), f.config.Sources[0]); err != nil || n != 5 {
+		t.Errorf("Test() = %d, %v, want 5 documents", n, err)
+	}
+}
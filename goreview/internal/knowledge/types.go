@@ -51,14 +51,16 @@ type Source struct {
 
 // Document represents a document from a knowledge source.
 type Document struct {
-	ID        string            `json:"id"`
-	Title     string            `json:"title"`
-	Content   string            `json:"content"`
-	URL       string            `json:"url,omitempty"`
-	Source    SourceType        `json:"source"`
-	Tags      []string          `json:"tags,omitempty"`
-	Metadata  map[string]string `json:"metadata,omitempty"`
-	FetchedAt time.Time         `json:"fetched_at"`
+	ID      string     `json:"id"`
+	Title   string     `json:"title"`
+	Content string     `json:"content"`
+	URL     string     `json:"url,omitempty"`
+	Source  SourceType `json:"source"`
+	// SourceName is the name of the configured source it came from
+	SourceName string            `json:"source_name,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	FetchedAt  time.Time         `json:"fetched_at"`
 }
 
 // SearchQuery represents a search query for knowledge sources.
@@ -0,0 +1,265 @@
+// Package manifest records what a review run did: its inputs, the files it
+// reviewed, a digest of its configuration, phase timings, cache hits and
+// provider calls. The manifest is written as JSON next to the report, for
+// debugging CI runs and attributing provider cost per repository.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// Version is the version of the manifest format.
+const Version = "1"
+
+// Manifest describes a review run.
+type Manifest struct {
+	Version         string    `json:"manifest_version"`
+	GoreviewVersion string    `json:"goreview_version"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DurationMS      int64     `json:"duration_ms"`
+	// Error is why the run failed, if it did
+	Error string `json:"error,omitempty"`
+	// RunBy is who ran the review: the CI actor, or the git user locally
+	RunBy string `json:"run_by,omitempty"`
+
+	Repository Repository `json:"repository"`
+	Inputs     Inputs     `json:"inputs"`
+	// ConfigDigest is the SHA-256 of the effective configuration, without
+	// credentials
+	ConfigDigest string `json:"config_digest"`
+
+	Phases   []Phase       `json:"phases"`
+	Files    []File        `json:"files"`
+	Cache    CacheStats    `json:"cache"`
+	Provider ProviderStats `json:"provider"`
+	Issues   int           `json:"issues"`
+	// GatesFailed lists the CI gates that didn't pass
+	GatesFailed []string `json:"gates_failed,omitempty"`
+}
+
+// Repository identifies the reviewed repository.
+type Repository struct {
+	Name   string `json:"name,omitempty"`
+	Remote string `json:"remote,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	// Author is the author of the commit, as "name <email>"
+	Author string `json:"author,omitempty"`
+}
+
+// Inputs are what the run was asked to review, and how.
+type Inputs struct {
+	Args        []string `json:"args"`
+	Mode        string   `json:"mode"`
+	Commit      string   `json:"commit,omitempty"`
+	BaseBranch  string   `json:"base_branch,omitempty"`
+	Files       []string `json:"files,omitempty"`
+	Modes       string   `json:"modes,omitempty"`
+	Personality string   `json:"personality,omitempty"`
+	Preset      string   `json:"preset,omitempty"`
+	Format      string   `json:"format,omitempty"`
+}
+
+// Phase is the duration of a phase of the run.
+type Phase struct {
+	Name       string `json:"name"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// File is a reviewed file.
+type File struct {
+	Path       string `json:"path"`
+	Cached     bool   `json:"cached"`
+	Normalized bool   `json:"normalized,omitempty"`
+	// ReusedHunks counts hunks carried over from the previous review
+	ReusedHunks int    `json:"reused_hunks,omitempty"`
+	Issues      int    `json:"issues"`
+	TokensUsed  int    `json:"tokens_used"`
+	Error       string `json:"error,omitempty"`
+}
+
+// CacheStats counts the files answered from the review cache.
+type CacheStats struct {
+	Enabled bool `json:"enabled"`
+	Hits    int  `json:"hits"`
+	// NormalizedHits are the hits that matched only after diff normalization
+	NormalizedHits int `json:"normalized_hits"`
+	Misses         int `json:"misses"`
+}
+
+// ProviderStats are the provider requests of the run.
+type ProviderStats struct {
+	Name   string `json:"name"`
+	Model  string `json:"model"`
+	Calls  int    `json:"calls"`
+	Errors int    `json:"errors"`
+	// TokensUsed are the tokens of the uncached responses
+	TokensUsed int `json:"tokens_used"`
+	// TimeMS is the time spent waiting on the provider, summed over
+	// concurrent requests
+	TimeMS int64 `json:"time_ms"`
+}
+
+// Recorder builds the manifest of a run. A nil Recorder records nothing, so
+// callers don't need to check whether a manifest was requested.
+type Recorder struct {
+	m       Manifest
+	phase   time.Time
+	written bool
+}
+
+// NewRecorder starts recording a run.
+func NewRecorder(version string, args []string) *Recorder {
+	now := time.Now()
+	return &Recorder{
+		m: Manifest{
+			Version:         Version,
+			GoreviewVersion: version,
+			StartedAt:       now.UTC(),
+			Inputs:          Inputs{Args: args},
+		},
+		phase: now,
+	}
+}
+
+// EndPhase records a phase that ended now, starting when the previous one
+// ended or, for the first, when the run started.
+func (r *Recorder) EndPhase(name string) {
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	r.m.Phases = append(r.m.Phases, Phase{Name: name, DurationMS: now.Sub(r.phase).Milliseconds()})
+	r.phase = now
+}
+
+// SetRepository records the reviewed repository.
+func (r *Recorder) SetRepository(repo Repository) {
+	if r != nil {
+		r.m.Repository = repo
+	}
+}
+
+// SetRunBy records who ran the review.
+func (r *Recorder) SetRunBy(actor string) {
+	if r != nil {
+		r.m.RunBy = actor
+	}
+}
+
+// SetConfig records the effective configuration's inputs and digest.
+func (r *Recorder) SetConfig(cfg *config.Config, format string) {
+	if r == nil {
+		return
+	}
+	r.m.Inputs.Mode = cfg.Review.Mode
+	r.m.Inputs.Commit = cfg.Review.Commit
+	r.m.Inputs.BaseBranch = cfg.Git.BaseBranch
+	r.m.Inputs.Files = cfg.Review.Files
+	r.m.Inputs.Modes = cfg.Review.Modes
+	r.m.Inputs.Personality = cfg.Review.Personality
+	r.m.Inputs.Preset = cfg.Rules.Preset
+	r.m.Inputs.Format = format
+	r.m.ConfigDigest = Digest(cfg)
+	r.m.Cache.Enabled = cfg.Cache.Enabled
+	r.m.Provider.Name = cfg.Provider.Name
+	r.m.Provider.Model = cfg.Provider.Model
+}
+
+// SetResult records the reviewed files and the engine's counters. The
+// engine's phases are recorded as sub-phases of "review".
+func (r *Recorder) SetResult(result *review.Result, stats review.RunStats) {
+	if r == nil {
+		return
+	}
+	for _, p := range stats.Phases {
+		r.m.Phases = append(r.m.Phases, Phase{Name: "review/" + p.Name, DurationMS: p.Duration.Milliseconds()})
+	}
+	r.m.Provider.Calls = stats.ProviderCalls
+	r.m.Provider.Errors = stats.ProviderErrors
+	r.m.Provider.TimeMS = stats.ProviderTime.Milliseconds()
+	if result == nil {
+		return
+	}
+
+	r.m.Files = make([]File, 0, len(result.Files))
+	for _, f := range result.Files {
+		file := File{Path: f.File, Cached: f.Cached, Normalized: f.Normalized, ReusedHunks: f.ReusedHunks}
+		if f.Error != nil {
+			file.Error = f.Error.Error()
+		}
+		if f.Response != nil {
+			file.Issues = len(f.Response.Issues)
+			if !f.Cached {
+				file.TokensUsed = f.Response.TokensUsed
+				r.m.Provider.TokensUsed += f.Response.TokensUsed
+			}
+		}
+		switch {
+		case f.Normalized:
+			r.m.Cache.NormalizedHits++
+			r.m.Cache.Hits++
+		case f.Cached:
+			r.m.Cache.Hits++
+		case r.m.Cache.Enabled:
+			r.m.Cache.Misses++
+		}
+		r.m.Files = append(r.m.Files, file)
+	}
+}
+
+// Write finishes the manifest with the run's outcome and writes it to
+// path. Only the first call writes, so a deferred call can cover early
+// returns.
+func (r *Recorder) Write(path string, result *review.Result, runErr error) error {
+	if r == nil || r.written {
+		return nil
+	}
+	r.written = true
+
+	now := time.Now()
+	r.m.FinishedAt = now.UTC()
+	r.m.DurationMS = now.Sub(r.m.StartedAt).Milliseconds()
+	if runErr != nil {
+		r.m.Error = runErr.Error()
+	}
+	if result != nil {
+		r.m.Issues = result.TotalIssues
+		r.m.GatesFailed = result.GatesFailed()
+	}
+
+	data, err := json.MarshalIndent(r.m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	return nil
+}
+
+// Manifest returns the manifest recorded so far.
+func (r *Recorder) Manifest() Manifest {
+	return r.m
+}
+
+// Digest returns the SHA-256 of the configuration without its credentials,
+// so rotating a key doesn't change it.
+func Digest(cfg *config.Config) string {
+	c := *cfg
+	c.Provider.APIKey = ""
+	c.Export.Slack.WebhookURL = ""
+	c.Export.Webhook.Headers = nil
+	data, _ := json.Marshal(c)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
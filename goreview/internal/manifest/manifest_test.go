@@ -0,0 +1,135 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+func TestRecorderWrite(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "branch"
+	cfg.Provider.Name = "ollama"
+	cfg.Provider.Model = "qwen2.5-coder"
+
+	rec := NewRecorder("1.2.3", []string{"--branch", "main"})
+	rec.SetConfig(cfg, "json")
+	rec.SetRepository(Repository{Name: "goreview", Commit: "abc123", Author: "Ana <ana@example.com>"})
+	rec.SetRunBy("ci-bot")
+	rec.EndPhase("setup")
+
+	result := &review.Result{
+		TotalIssues: 3,
+		Files: []review.FileResult{
+			{File: "a.go", Response: &providers.ReviewResponse{Issues: make([]providers.Issue, 2), TokensUsed: 100}},
+			{File: "b.go", Cached: true, Response: &providers.ReviewResponse{Issues: make([]providers.Issue, 1), TokensUsed: 80}},
+			{File: "c.go", Cached: true, Normalized: true, Response: &providers.ReviewResponse{TokensUsed: 50}},
+			{File: "d.go", Error: errors.New("timeout")},
+		},
+		Gates: []review.GateResult{{Name: "no-critical", Passed: false}},
+	}
+	stats := review.RunStats{
+		ProviderCalls:  2,
+		ProviderErrors: 1,
+		ProviderTime:   1500 * time.Millisecond,
+		Phases:         []review.PhaseTiming{{Name: "diff", Duration: 20 * time.Millisecond}},
+	}
+	rec.SetResult(result, stats)
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := rec.Write(path, result, nil); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("manifest isn't valid JSON: %v", err)
+	}
+
+	if m.Version != Version || m.GoreviewVersion != "1.2.3" || m.Inputs.Mode != "branch" || m.Inputs.Format != "json" {
+		t.Errorf("header = %+v %+v", m, m.Inputs)
+	}
+	if m.RunBy != "ci-bot" || m.Repository.Author != "Ana <ana@example.com>" {
+		t.Errorf("run by = %q, author = %q", m.RunBy, m.Repository.Author)
+	}
+	if m.ConfigDigest != Digest(cfg) {
+		t.Errorf("ConfigDigest = %q, want %q", m.ConfigDigest, Digest(cfg))
+	}
+	if len(m.Phases) != 2 || m.Phases[0].Name != "setup" || m.Phases[1].Name != "review/diff" || m.Phases[1].DurationMS != 20 {
+		t.Errorf("Phases = %+v", m.Phases)
+	}
+	if len(m.Files) != 4 || m.Files[0].Issues != 2 || m.Files[1].TokensUsed != 0 || m.Files[3].Error != "timeout" {
+		t.Errorf("Files = %+v", m.Files)
+	}
+	wantCache := CacheStats{Enabled: cfg.Cache.Enabled, Hits: 2, NormalizedHits: 1}
+	if cfg.Cache.Enabled {
+		wantCache.Misses = 2
+	}
+	if m.Cache != wantCache {
+		t.Errorf("Cache = %+v, want %+v", m.Cache, wantCache)
+	}
+	wantProvider := ProviderStats{Name: "ollama", Model: "qwen2.5-coder", Calls: 2, Errors: 1, TokensUsed: 100, TimeMS: 1500}
+	if m.Provider != wantProvider {
+		t.Errorf("Provider = %+v, want %+v", m.Provider, wantProvider)
+	}
+	if m.Issues != 3 || len(m.GatesFailed) != 1 || m.GatesFailed[0] != "no-critical" {
+		t.Errorf("Issues = %d, GatesFailed = %v", m.Issues, m.GatesFailed)
+	}
+
+	// Later calls don't overwrite the manifest
+	if err := rec.Write(path, nil, errors.New("late")); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := os.ReadFile(path); string(again) != string(data) {
+		t.Error("second Write() rewrote the manifest")
+	}
+}
+
+func TestRecorderRecordsError(t *testing.T) {
+	rec := NewRecorder("dev", nil)
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := rec.Write(path, nil, errors.New("provider not available")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Error != "provider not available" {
+		t.Errorf("Error = %q", m.Error)
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var rec *Recorder
+	rec.EndPhase("setup")
+	rec.SetConfig(config.DefaultConfig(), "json")
+	rec.SetResult(nil, review.RunStats{})
+	if err := rec.Write(filepath.Join(t.TempDir(), "m.json"), nil, nil); err != nil {
+		t.Errorf("Write() on nil recorder error = %v", err)
+	}
+}
+
+func TestDigestIgnoresCredentials(t *testing.T) {
+	a := config.DefaultConfig()
+	b := config.DefaultConfig()
+	b.Provider.APIKey = "secret"
+	if Digest(a) != Digest(b) {
+		t.Error("Digest() changed with the API key")
+	}
+	b.Provider.Model = "other"
+	if Digest(a) == Digest(b) {
+		t.Error("Digest() ignored the model")
+	}
+}
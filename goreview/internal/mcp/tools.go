@@ -33,7 +33,7 @@ func registerReviewTools(s *Server) {
 				},
 				"mode": map[string]interface{}{
 					"type":        "string",
-					"description": "Review mode: security, perf, clean, docs, tests, or comma-separated combination",
+					"description": "Review mode: security, perf, clean, docs, tests, errors, concurrency, or comma-separated combination",
 					"enum":        []string{"security", "perf", "clean", "docs", "tests"},
 				},
 				"personality": map[string]interface{}{
@@ -46,6 +46,11 @@ func registerReviewTools(s *Server) {
 					"items":       map[string]interface{}{"type": "string"},
 					"description": "Specific files to review (optional)",
 				},
+				"focus_symbols": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Review only the changes inside these functions and types, like 'Parse' or 'Engine.Run' (optional)",
+				},
 				"trace": map[string]interface{}{
 					"type":        "boolean",
 					"description": "Enable root cause tracing",
@@ -254,6 +259,17 @@ func appendReviewOptions(args []string, params map[string]interface{}) []string
 	if trace, ok := params["trace"].(bool); ok && trace {
 		args = append(args, "--trace")
 	}
+	if symbols, ok := params["focus_symbols"].([]interface{}); ok && len(symbols) > 0 {
+		names := make([]string, 0, len(symbols))
+		for _, s := range symbols {
+			if name, ok := s.(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			args = append(args, "--focus-symbols", strings.Join(names, ","))
+		}
+	}
 	if files, ok := params["files"].([]interface{}); ok {
 		for _, f := range files {
 			if fs, ok := f.(string); ok {
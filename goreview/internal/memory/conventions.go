@@ -0,0 +1,289 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// ConventionType is the type of the long-term memory entries holding the
+// team conventions learned from goreview fix.
+const ConventionType = "convention"
+
+// conventionKeyWords is how many words of an issue message group decisions
+// on issues without a rule.
+const conventionKeyWords = 8
+
+// Convention is a team preference learned from the fixes a team accepted
+// or rejected for one kind of issue: issues of a rule, or of a type with a
+// similar message.
+type Convention struct {
+	ID        string `json:"id"`
+	IssueType string `json:"issue_type"`
+	RuleID    string `json:"rule_id,omitempty"`
+	// Message and Suggestion are from the last issue decided
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Accepted   int    `json:"accepted"`
+	Rejected   int    `json:"rejected"`
+	// Text is the convention as given to the reviewer. Edited conventions
+	// keep theirs when more decisions come in
+	Text      string    `json:"text"`
+	Edited    bool      `json:"edited,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Decisions is how many fixes were accepted or rejected.
+func (c *Convention) Decisions() int {
+	return c.Accepted + c.Rejected
+}
+
+// FixDecision is a fix accepted or rejected by the user.
+type FixDecision struct {
+	IssueType  string
+	RuleID     string
+	Message    string
+	Suggestion string
+	Accepted   bool
+}
+
+// Conventions keeps the conventions of one repository in a memory tier,
+// normally long-term memory.
+type Conventions struct {
+	mem  Memory
+	repo string
+}
+
+// NewConventions returns the conventions of the repository at repoRoot.
+func NewConventions(mem Memory, repoRoot string) *Conventions {
+	return &Conventions{mem: mem, repo: repoRoot}
+}
+
+// Record adds a fix decision to the convention it belongs to, creating it
+// on the first decision.
+func (c *Conventions) Record(ctx context.Context, d FixDecision) (*Convention, error) {
+	id := c.conventionID(d)
+	conv, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if conv == nil {
+		conv = &Convention{ID: id, IssueType: d.IssueType, RuleID: d.RuleID}
+	}
+	conv.Message, conv.Suggestion = d.Message, d.Suggestion
+	if d.Accepted {
+		conv.Accepted++
+	} else {
+		conv.Rejected++
+	}
+	if !conv.Edited {
+		conv.Text = distillConvention(conv)
+	}
+	return conv, c.store(ctx, conv)
+}
+
+// Get returns a convention by ID, or nil when there is none.
+func (c *Conventions) Get(ctx context.Context, id string) (*Convention, error) {
+	// Searched rather than got: long-term memory updates the access stats
+	// of a got entry in the background, which could undo a later store
+	results, err := c.mem.Search(ctx, &Query{ID: id})
+	if err != nil {
+		return nil, fmt.Errorf("getting convention: %w", err)
+	}
+	for _, r := range results {
+		if r.Entry.Type == ConventionType {
+			return conventionFromEntry(r.Entry), nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns the repository's conventions, the most decided first.
+func (c *Conventions) List(ctx context.Context) ([]*Convention, error) {
+	results, err := c.mem.Search(ctx, &Query{Type: ConventionType, Tags: []string{c.repoTag()}})
+	if err != nil {
+		return nil, fmt.Errorf("searching conventions: %w", err)
+	}
+	conventions := make([]*Convention, 0, len(results))
+	for _, r := range results {
+		conventions = append(conventions, conventionFromEntry(r.Entry))
+	}
+	sort.Slice(conventions, func(i, j int) bool {
+		if conventions[i].Decisions() != conventions[j].Decisions() {
+			return conventions[i].Decisions() > conventions[j].Decisions()
+		}
+		return conventions[i].ID < conventions[j].ID
+	})
+	return conventions, nil
+}
+
+// Edit replaces the text of a convention. Edited conventions are always
+// given to the reviewer, however many decisions they have.
+func (c *Conventions) Edit(ctx context.Context, id, text string) error {
+	conv, err := c.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if conv == nil {
+		return fmt.Errorf("no convention %s", id)
+	}
+	conv.Text, conv.Edited = text, true
+	return c.store(ctx, conv)
+}
+
+// Delete forgets a convention and its decisions.
+func (c *Conventions) Delete(ctx context.Context, id string) error {
+	conv, err := c.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if conv == nil {
+		return fmt.Errorf("no convention %s", id)
+	}
+	return c.mem.Delete(ctx, id)
+}
+
+// Distill returns the conventions worth giving the reviewer, from a list
+// sorted by List: edited ones, and those with at least minDecisions
+// decisions leaning one way, at most limit of them (0 for all).
+func Distill(conventions []*Convention, minDecisions, limit int) []*Convention {
+	var distilled []*Convention
+	for _, conv := range conventions {
+		if limit > 0 && len(distilled) == limit {
+			break
+		}
+		if conv.Edited || (conv.Decisions() >= minDecisions && conv.Accepted != conv.Rejected) {
+			distilled = append(distilled, conv)
+		}
+	}
+	return distilled
+}
+
+func (c *Conventions) store(ctx context.Context, conv *Convention) error {
+	conv.UpdatedAt = time.Now().UTC()
+	entry := &Entry{
+		ID:      conv.ID,
+		Content: conv.Text,
+		Type:    ConventionType,
+		Tags:    []string{c.repoTag(), "type:" + conv.IssueType},
+		Metadata: map[string]interface{}{
+			"issue_type": conv.IssueType,
+			"rule_id":    conv.RuleID,
+			"message":    conv.Message,
+			"suggestion": conv.Suggestion,
+			"accepted":   conv.Accepted,
+			"rejected":   conv.Rejected,
+			"edited":     conv.Edited,
+			"updated_at": conv.UpdatedAt.Format(time.RFC3339),
+		},
+		Strength: float64(conv.Decisions()),
+	}
+	if err := c.mem.Store(ctx, entry); err != nil {
+		return fmt.Errorf("storing convention: %w", err)
+	}
+	return nil
+}
+
+func (c *Conventions) repoTag() string {
+	return "repo:" + c.repo
+}
+
+// conventionID groups decisions by repository and rule, or by repository,
+// issue type and the first words of the message for issues without a rule.
+func (c *Conventions) conventionID(d FixDecision) string {
+	key := "rule:" + d.RuleID
+	if d.RuleID == "" {
+		key = "type:" + d.IssueType + ":" + messageKey(d.Message)
+	}
+	sum := sha256.Sum256([]byte(c.repo + "\n" + key))
+	return "conv-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// messageKey is the first words of a message, lowercased and without
+// punctuation or numbers, so rewordings of the same issue mostly match.
+func messageKey(message string) string {
+	words := strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) > conventionKeyWords {
+		words = words[:conventionKeyWords]
+	}
+	return strings.Join(words, " ")
+}
+
+// distillConvention words the convention for the reviewer from the way
+// its decisions lean.
+func distillConvention(conv *Convention) string {
+	subject := conv.Message
+	if conv.RuleID != "" {
+		subject = fmt.Sprintf("rule %s (%s)", conv.RuleID, conv.Message)
+	}
+	if conv.Rejected > conv.Accepted {
+		return fmt.Sprintf("Don't report %s issues like: %s (fix rejected %d of %d times)",
+			conv.IssueType, subject, conv.Rejected, conv.Decisions())
+	}
+	text := fmt.Sprintf("Keep reporting %s issues like: %s (fix accepted %d of %d times)",
+		conv.IssueType, subject, conv.Accepted, conv.Decisions())
+	if conv.Suggestion != "" {
+		text += "; preferred fix: " + conv.Suggestion
+	}
+	return text
+}
+
+// conventionFromEntry reads a convention back from its memory entry.
+func conventionFromEntry(e *Entry) *Convention {
+	str := func(key string) string {
+		s, _ := e.Metadata[key].(string)
+		return s
+	}
+	num := func(key string) int {
+		switch n := e.Metadata[key].(type) {
+		case int:
+			return n
+		case float64: // Decoded from JSON
+			return int(n)
+		}
+		return 0
+	}
+	edited, _ := e.Metadata["edited"].(bool)
+	updated, _ := time.Parse(time.RFC3339, str("updated_at"))
+	return &Convention{
+		ID:         e.ID,
+		IssueType:  str("issue_type"),
+		RuleID:     str("rule_id"),
+		Message:    str("message"),
+		Suggestion: str("suggestion"),
+		Accepted:   num("accepted"),
+		Rejected:   num("rejected"),
+		Text:       e.Content,
+		Edited:     edited,
+		UpdatedAt:  updated,
+	}
+}
+
+// OpenConventions opens the repository's conventions in the long-term
+// memory of the memory directory, whether or not the memory system is
+// enabled. Close them when done: the memory is locked while open.
+func OpenConventions(cfg config.MemoryConfig, repoRoot string) (*Conventions, error) {
+	ltm, err := NewLongTermMemory(LongTermOptions{
+		Dir:       filepath.Join(cfg.Dir, "longterm"),
+		MaxSizeMB: cfg.LongTerm.MaxSizeMB,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening long-term memory: %w", err)
+	}
+	return NewConventions(ltm, repoRoot), nil
+}
+
+// Close closes the memory holding the conventions.
+func (c *Conventions) Close() error {
+	return c.mem.Close()
+}
@@ -109,6 +109,18 @@ func (e *Embedder) Similarity(a, b []float32) float64 {
 	return cosineSimilarity(a, b)
 }
 
+// Keywords returns the words of the text that carry meaning: its tokens,
+// lowercased, without stopwords, in order.
+func (e *Embedder) Keywords(text string) []string {
+	var keywords []string
+	for _, token := range e.tokenize(text) {
+		if !e.isStopword(token) {
+			keywords = append(keywords, token)
+		}
+	}
+	return keywords
+}
+
 // Internal methods
 
 func (e *Embedder) tokenize(text string) []string {
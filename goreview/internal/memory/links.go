@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Link links the session to the branch and commit it is part of, so it can
+// be recalled by them. Empty values are ignored; the session keeps the last
+// branch and every commit linked.
+func (s *SessionMem) Link(branch, commit string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if branch != "" {
+		s.branch = branch
+	}
+	if commit != "" && !slices.Contains(s.commits, commit) {
+		s.commits = append(s.commits, commit)
+	}
+}
+
+// Link links the current session to the branch and commit it is part of;
+// see SessionMem.Link.
+func (s *Store) Link(branch, commit string) {
+	if s.session != nil {
+		s.session.Link(branch, commit)
+	}
+}
+
+// LinkedSession is a saved session linked to a branch or commits.
+type LinkedSession struct {
+	ID      string    `json:"id"`
+	Branch  string    `json:"branch,omitempty"`
+	Commits []string  `json:"commits,omitempty"`
+	SavedAt time.Time `json:"saved_at"`
+	// Entries are in the order they were stored
+	Entries []Entry `json:"entries"`
+}
+
+// LinkedSessions returns the saved sessions linked to the branch and, when
+// commit is set, to a commit starting with it, newest first. With neither,
+// it returns every linked session.
+func (s *SessionMem) LinkedSessions(_ context.Context, branch, commit string) ([]LinkedSession, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading sessions: %w", err)
+	}
+
+	var sessions []LinkedSession
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec // Path is listed from the trusted session directory
+		if err != nil {
+			return nil, fmt.Errorf("reading session: %w", err)
+		}
+		var session sessionData
+		if err := json.Unmarshal(data, &session); err != nil {
+			continue // Not a session file
+		}
+		if !linkMatches(session, branch, commit) {
+			continue
+		}
+		sort.SliceStable(session.Entries, func(i, j int) bool {
+			return session.Entries[i].CreatedAt.Before(session.Entries[j].CreatedAt)
+		})
+		sessions = append(sessions, LinkedSession{
+			ID: session.ID, Branch: session.Branch, Commits: session.Commits,
+			SavedAt: session.CreatedAt, Entries: session.Entries,
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].SavedAt.After(sessions[j].SavedAt) })
+	return sessions, nil
+}
+
+// linkMatches reports whether a session is linked to the branch and commit.
+func linkMatches(session sessionData, branch, commit string) bool {
+	if session.Branch == "" && len(session.Commits) == 0 {
+		return false
+	}
+	if branch != "" && session.Branch != branch {
+		return false
+	}
+	if commit == "" {
+		return true
+	}
+	return slices.ContainsFunc(session.Commits, func(c string) bool { return strings.HasPrefix(c, commit) })
+}
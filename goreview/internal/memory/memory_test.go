@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -196,6 +197,84 @@ func TestSessionMemory(t *testing.T) {
 	})
 }
 
+func TestLinkedSessions(t *testing.T) {
+	ctx := context.Background()
+	sm, err := NewSessionMemory(t.TempDir(), 5, time.Hour)
+	if err != nil {
+		t.Fatalf("NewSessionMemory() error = %v", err)
+	}
+
+	save := func(branch, commit, content string) string {
+		sm.Link(branch, commit)
+		if err := sm.Store(ctx, &Entry{Content: content, Type: "chat"}); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+		id := sm.SessionID()
+		if _, err := sm.NewSession(ctx); err != nil {
+			t.Fatalf("NewSession() error = %v", err)
+		}
+		return id
+	}
+	feature := save("feature/x", "abc123", "why the retry loop")
+	time.Sleep(10 * time.Millisecond)
+	sm.Link("feature/x", "def456")
+	feature2 := save("", "", "the cache key")
+	save("main", "fff000", "release notes")
+	save("", "", "unlinked")
+
+	got, err := sm.LinkedSessions(ctx, "feature/x", "")
+	if err != nil {
+		t.Fatalf("LinkedSessions() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != feature2 || got[1].ID != feature {
+		t.Fatalf("LinkedSessions(feature/x) = %+v, want both feature sessions, newest first", got)
+	}
+	if got[1].Entries[0].Content != "why the retry loop" || got[1].Commits[0] != "abc123" {
+		t.Errorf("linked session = %+v", got[1])
+	}
+
+	if got, _ := sm.LinkedSessions(ctx, "", "def"); len(got) != 1 || got[0].ID != feature2 {
+		t.Errorf("LinkedSessions(commit def) = %+v, want the second feature session", got)
+	}
+	if got, _ := sm.LinkedSessions(ctx, "", ""); len(got) != 3 {
+		t.Errorf("LinkedSessions() = %d sessions, want the 3 linked ones", len(got))
+	}
+}
+
+func TestStoreSchedule(t *testing.T) {
+	cfg := DefaultStoreConfig()
+	cfg.Dir = t.TempDir()
+	cfg.Working.TTL = time.Millisecond
+	store, err := NewStore(cfg)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := store.Store(ctx, &Entry{ID: "stale", Content: "stale"}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		store.Schedule(ctx, 5*time.Millisecond, func(err error) { t.Errorf("Maintain() error = %v", err) })
+		close(done)
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stats, _ := store.Stats(ctx)
+		if stats.WorkingEntries == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scheduled maintenance didn't clean expired working memory")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
 func TestLongTermMemory(t *testing.T) {
 	ctx := context.Background()
 	dir := t.TempDir()
@@ -477,3 +556,70 @@ func TestMatchScore(t *testing.T) {
 		})
 	}
 }
+
+func TestConventions(t *testing.T) {
+	ctx := context.Background()
+	ltm, err := NewLongTermMemory(LongTermOptions{Dir: filepath.Join(t.TempDir(), "longterm")})
+	if err != nil {
+		t.Fatalf("NewLongTermMemory() error = %v", err)
+	}
+	conventions := NewConventions(ltm, "/repo")
+	defer func() { _ = conventions.Close() }()
+
+	decide := func(d FixDecision) *Convention {
+		t.Helper()
+		conv, err := conventions.Record(ctx, d)
+		if err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		return conv
+	}
+	wrap := FixDecision{IssueType: "bug", Message: "Error returned without context at line 12", Suggestion: "wrap it with %w", Accepted: true}
+	decide(wrap)
+	wrap.Message = "Error returned without context at line 40"
+	errConv := decide(wrap)
+	decide(FixDecision{IssueType: "style", RuleID: "naming", Message: "Receiver name should be short"})
+	styleConv := decide(FixDecision{IssueType: "style", RuleID: "naming", Message: "Receiver name is too long"})
+	decide(FixDecision{IssueType: "performance", Message: "Preallocate the slice", Accepted: true})
+
+	// Rewordings of the same message, and issues of a rule, are one convention
+	if errConv.Accepted != 2 || styleConv.Rejected != 2 {
+		t.Fatalf("conventions = %+v, %+v; want 2 accepted, 2 rejected", errConv, styleConv)
+	}
+	if !strings.Contains(errConv.Text, "Keep reporting bug issues") || !strings.Contains(errConv.Text, "preferred fix: wrap it with %w") {
+		t.Errorf("accepted convention = %q", errConv.Text)
+	}
+	if !strings.Contains(styleConv.Text, "Don't report style issues like: rule naming") {
+		t.Errorf("rejected convention = %q", styleConv.Text)
+	}
+
+	learned, err := conventions.List(ctx)
+	if err != nil || len(learned) != 3 {
+		t.Fatalf("List() = %d conventions, %v; want 3", len(learned), err)
+	}
+	if got := Distill(learned, 2, 0); len(got) != 2 {
+		t.Errorf("Distill() = %d conventions, want the 2 with 2 decisions", len(got))
+	}
+
+	// Edited conventions keep their text and always reach the reviewer
+	perf := learned[2]
+	if err := conventions.Edit(ctx, perf.ID, "Preallocate slices of known size"); err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	decide(FixDecision{IssueType: "performance", Message: "Preallocate the slice"})
+	learned, _ = conventions.List(ctx)
+	got := Distill(learned, 3, 0)
+	if len(got) != 1 || got[0].Text != "Preallocate slices of known size" {
+		t.Errorf("Distill() = %+v, want only the edited convention", got)
+	}
+
+	if err := conventions.Delete(ctx, perf.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if learned, _ = conventions.List(ctx); len(learned) != 2 {
+		t.Errorf("List() after Delete = %d conventions, want 2", len(learned))
+	}
+	if others, _ := NewConventions(ltm, "/other").List(ctx); len(others) != 0 {
+		t.Errorf("other repository has %d conventions, want 0", len(others))
+	}
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// Maintain consolidates important working memory entries into long-term
+// memory and runs the maintenance of every tier.
+func (s *Store) Maintain(ctx context.Context) error {
+	if err := s.Consolidate(ctx); err != nil {
+		return err
+	}
+	return s.RunMaintenance(ctx)
+}
+
+// Schedule runs Maintain every interval until ctx is done, reporting its
+// errors to onError. It blocks, so long-running modes call it in their own
+// goroutine.
+func (s *Store) Schedule(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Maintain(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// scopesFile holds the learned commit scopes in the memory directory.
+const scopesFile = "scopes.json"
+
+// ScopeMemory remembers the commit scopes used for the directories of each
+// repository, so inferred scopes stay consistent over time.
+type ScopeMemory struct {
+	path string
+	repo string
+	// Repos maps repository roots to directories to scopes to how many
+	// commits touching the directory used the scope
+	Repos map[string]map[string]map[string]int `json:"repos"`
+}
+
+// LoadScopeMemory loads the scopes learned for the repository at repoRoot
+// from the memory directory. A missing file is an empty memory.
+func LoadScopeMemory(dir, repoRoot string) (*ScopeMemory, error) {
+	m := &ScopeMemory{path: filepath.Join(dir, scopesFile), repo: repoRoot}
+	data, err := os.ReadFile(m.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading learned scopes: %w", err)
+	default:
+		if err := json.Unmarshal(data, m); err != nil {
+			return nil, fmt.Errorf("parsing learned scopes: %w", err)
+		}
+	}
+	if m.Repos == nil {
+		m.Repos = make(map[string]map[string]map[string]int)
+	}
+	return m, nil
+}
+
+// Learn records a commit touching dir with the scope.
+func (m *ScopeMemory) Learn(dir, scope string) {
+	dirs := m.Repos[m.repo]
+	if dirs == nil {
+		dirs = make(map[string]map[string]int)
+		m.Repos[m.repo] = dirs
+	}
+	dir = path.Clean(dir)
+	if dirs[dir] == nil {
+		dirs[dir] = make(map[string]int)
+	}
+	dirs[dir][scope]++
+}
+
+// Scope returns the scope most used for dir, or "" when none was learned.
+// Ties go to the alphabetically first scope.
+func (m *ScopeMemory) Scope(dir string) string {
+	best, bestCount := "", 0
+	for scope, n := range m.Repos[m.repo][path.Clean(dir)] {
+		if n > bestCount || (n == bestCount && scope < best) {
+			best, bestCount = scope, n
+		}
+	}
+	return best
+}
+
+// Save writes the learned scopes back to the memory directory.
+func (m *ScopeMemory) Save() error {
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return fmt.Errorf("creating memory directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(m.path, data, 0600); err != nil {
+		return fmt.Errorf("writing learned scopes: %w", err)
+	}
+	return nil
+}
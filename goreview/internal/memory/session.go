@@ -23,6 +23,10 @@ type SessionMem struct {
 	maxSessions int
 	sessionTTL  time.Duration
 
+	// branch and commits link the session to the work it was part of
+	branch  string
+	commits []string
+
 	// Statistics
 	hits   int64
 	misses int64
@@ -216,6 +220,7 @@ func (s *SessionMem) NewSession(ctx context.Context) (string, error) {
 	// Start new session
 	s.sessionID = uuid.New().String()
 	s.entries = make(map[string]*Entry)
+	s.branch, s.commits = "", nil
 	atomic.StoreInt64(&s.hits, 0)
 	atomic.StoreInt64(&s.misses, 0)
 
@@ -244,6 +249,7 @@ func (s *SessionMem) LoadSession(ctx context.Context, sessionID string) error {
 	// Restore session
 	s.sessionID = sessionID
 	s.entries = make(map[string]*Entry)
+	s.branch, s.commits = session.Branch, session.Commits
 	for _, entry := range session.Entries {
 		entryCopy := entry // Copy to avoid pointer issues
 		s.entries[entry.ID] = &entryCopy
@@ -278,6 +284,8 @@ func (s *SessionMem) ListSessions(ctx context.Context) ([]string, error) {
 type sessionData struct {
 	ID        string    `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
+	Branch    string    `json:"branch,omitempty"`
+	Commits   []string  `json:"commits,omitempty"`
 	Entries   []Entry   `json:"entries"`
 }
 
@@ -302,6 +310,8 @@ func (s *SessionMem) saveSessionLocked() error {
 	session := sessionData{
 		ID:        s.sessionID,
 		CreatedAt: time.Now(),
+		Branch:    s.branch,
+		Commits:   s.commits,
 		Entries:   entries,
 	}
 
@@ -0,0 +1,291 @@
+// Package notebook reads Jupyter notebooks (.ipynb) as the code of their
+// cells, keeping each source line at its line in the notebook file, so
+// reviews of notebook diffs see code instead of JSON and their issues
+// point at the right lines.
+package notebook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Cell types
+const (
+	CellCode     = "code"
+	CellMarkdown = "markdown"
+	CellRaw      = "raw"
+)
+
+// ErrPacked is returned for notebooks whose source lines don't each have
+// a line of the file, like minified ones, which can't be mapped.
+var ErrPacked = errors.New("notebook source lines are not one per line")
+
+// IsNotebook reports whether the path is a Jupyter notebook.
+func IsNotebook(p string) bool {
+	return strings.EqualFold(path.Ext(p), ".ipynb")
+}
+
+// Notebook is a parsed notebook.
+type Notebook struct {
+	// Language is the kernel's language, lowercased, or "" when unknown
+	Language string
+	// Extension is the file extension of the kernel's language, like ".py"
+	Extension string
+	Cells     []Cell
+
+	lines int
+	code  map[int]string
+}
+
+// Cell is a cell of a notebook.
+type Cell struct {
+	// Number is the cell's position in the notebook, from 1
+	Number int
+	Type   string
+	// Start and End are the lines of the brackets of the cell's source
+	Start, End int
+	Lines      []Line
+}
+
+// Line is a source line of a cell.
+type Line struct {
+	// Number is the line of the notebook file holding it
+	Number int
+	Text   string
+}
+
+// notebookMetadata is the part of the notebook metadata telling the
+// kernel's language.
+type notebookMetadata struct {
+	Kernelspec struct {
+		Language string `json:"language"`
+	} `json:"kernelspec"`
+	LanguageInfo struct {
+		Name          string `json:"name"`
+		FileExtension string `json:"file_extension"`
+	} `json:"language_info"`
+}
+
+// parser walks the notebook's JSON tokens, tracking their lines.
+type parser struct {
+	dec        *json.Decoder
+	lineStarts []int
+}
+
+// Parse parses a notebook. It fails on invalid notebooks and with
+// ErrPacked on notebooks that can't be mapped line by line.
+func Parse(content string) (*Notebook, error) {
+	p := &parser{dec: json.NewDecoder(strings.NewReader(content)), lineStarts: []int{0}}
+	for i, c := range content {
+		if c == '\n' {
+			p.lineStarts = append(p.lineStarts, i+1)
+		}
+	}
+	nb := &Notebook{lines: len(p.lineStarts), code: make(map[int]string)}
+
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	for p.dec.More() {
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "cells":
+			err = p.cells(nb)
+		case "metadata":
+			var meta notebookMetadata
+			if err = p.dec.Decode(&meta); err == nil {
+				nb.Language = strings.ToLower(meta.Kernelspec.Language)
+				if nb.Language == "" {
+					nb.Language = strings.ToLower(meta.LanguageInfo.Name)
+				}
+				nb.Extension = meta.LanguageInfo.FileExtension
+			}
+		default:
+			err = p.skip()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if nb.Cells == nil {
+		return nil, errors.New("not a notebook: no cells")
+	}
+
+	for _, cell := range nb.Cells {
+		for i, l := range cell.Lines {
+			if i > 0 && l.Number == cell.Lines[i-1].Number {
+				return nil, ErrPacked
+			}
+			if cell.Type == CellCode {
+				nb.code[l.Number] = l.Text
+			}
+		}
+	}
+	return nb, nil
+}
+
+// Code returns the code at a line of the notebook file, when the line is
+// a source line of a code cell.
+func (nb *Notebook) Code(line int) (string, bool) {
+	text, ok := nb.code[line]
+	return text, ok
+}
+
+// SourceAfter returns the cell whose source a line inserted after the
+// given line of the notebook file would be part of, or nil.
+func (nb *Notebook) SourceAfter(line int) *Cell {
+	for i, c := range nb.Cells {
+		if c.Start <= line && line < c.End {
+			return &nb.Cells[i]
+		}
+	}
+	return nil
+}
+
+// CellAt returns the cell whose source holds a line of the notebook file,
+// or nil.
+func (nb *Notebook) CellAt(line int) *Cell {
+	for i, c := range nb.Cells {
+		if c.Start <= line && line <= c.End {
+			return &nb.Cells[i]
+		}
+	}
+	return nil
+}
+
+// Source returns the code of the code cells, each line at its line of the
+// notebook file and the other lines blank, so line numbers of the code are
+// those of the file.
+func (nb *Notebook) Source() string {
+	lines := make([]string, nb.lines)
+	for n, text := range nb.code {
+		lines[n-1] = text
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DecodeLine returns the source text of a line of a notebook file holding
+// a source line, like `    "x = 1\n",`.
+func DecodeLine(line string) (string, bool) {
+	line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+	if !strings.HasPrefix(line, `"`) {
+		return "", false
+	}
+	var text string
+	if err := json.Unmarshal([]byte(line), &text); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(text, "\n"), true
+}
+
+func (p *parser) cells(nb *Notebook) error {
+	if err := p.expect('['); err != nil {
+		return err
+	}
+	for p.dec.More() {
+		cell := Cell{Number: len(nb.Cells) + 1}
+		if err := p.expect('{'); err != nil {
+			return err
+		}
+		for p.dec.More() {
+			key, err := p.key()
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "cell_type":
+				err = p.dec.Decode(&cell.Type)
+			case "source":
+				err = p.source(&cell)
+			default:
+				err = p.skip()
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := p.expect('}'); err != nil {
+			return err
+		}
+		nb.Cells = append(nb.Cells, cell)
+	}
+	return p.expect(']')
+}
+
+// source reads a cell's source, a string or a list of lines.
+func (p *parser) source(cell *Cell) error {
+	tok, err := p.dec.Token()
+	if err != nil {
+		return err
+	}
+	if s, ok := tok.(string); ok {
+		cell.Start, cell.End = p.line(), p.line()
+		if s = strings.TrimSuffix(s, "\n"); s != "" {
+			for _, text := range strings.Split(s, "\n") {
+				cell.Lines = append(cell.Lines, Line{Number: cell.Start, Text: text})
+			}
+		}
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("invalid cell source: %v", tok)
+	}
+	cell.Start = p.line()
+	for p.dec.More() {
+		var text string
+		if err := p.dec.Decode(&text); err != nil {
+			return fmt.Errorf("invalid cell source: %w", err)
+		}
+		text = strings.TrimSuffix(text, "\n")
+		if strings.Contains(text, "\n") {
+			return ErrPacked
+		}
+		cell.Lines = append(cell.Lines, Line{Number: p.line(), Text: text})
+	}
+	if err := p.expect(']'); err != nil {
+		return err
+	}
+	cell.End = p.line()
+	return nil
+}
+
+// line is the line of the file where the last token read ends.
+func (p *parser) line() int {
+	offset := int(p.dec.InputOffset()) - 1
+	return sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > offset })
+}
+
+func (p *parser) expect(delim json.Delim) error {
+	tok, err := p.dec.Token()
+	if err != nil {
+		return fmt.Errorf("invalid notebook: %w", err)
+	}
+	if tok != delim {
+		return fmt.Errorf("invalid notebook: want %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+func (p *parser) key() (string, error) {
+	tok, err := p.dec.Token()
+	if err != nil {
+		return "", fmt.Errorf("invalid notebook: %w", err)
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid notebook: want a key, got %v", tok)
+	}
+	return key, nil
+}
+
+func (p *parser) skip() error {
+	var raw json.RawMessage
+	return p.dec.Decode(&raw)
+}
@@ -0,0 +1,104 @@
+package notebook
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const sample = `{
+ "cells": [
+  {
+   "cell_type": "markdown",
+   "metadata": {},
+   "source": [
+    "# Sales\n",
+    "Loads the data."
+   ]
+  },
+  {
+   "cell_type": "code",
+   "execution_count": 1,
+   "metadata": {},
+   "outputs": [
+    {
+     "name": "stdout",
+     "output_type": "stream",
+     "text": [
+      "42\n"
+     ]
+    }
+   ],
+   "source": [
+    "import pandas as pd\n",
+    "df = pd.read_csv(\"sales.csv\")\n",
+    "print(len(df))"
+   ]
+  }
+ ],
+ "metadata": {
+  "kernelspec": {"display_name": "Python 3", "language": "python", "name": "python3"},
+  "language_info": {"name": "python", "file_extension": ".py"}
+ },
+ "nbformat": 4,
+ "nbformat_minor": 5
+}
+`
+
+func TestParse(t *testing.T) {
+	nb, err := Parse(sample)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if nb.Language != "python" || nb.Extension != ".py" || len(nb.Cells) != 2 {
+		t.Fatalf("notebook = %+v", nb)
+	}
+	code := nb.Cells[1]
+	if code.Number != 2 || code.Type != CellCode || code.Start != 24 || code.End != 28 || len(code.Lines) != 3 {
+		t.Fatalf("code cell = %+v", code)
+	}
+	if text, ok := nb.Code(26); !ok || text != `df = pd.read_csv("sales.csv")` {
+		t.Errorf("Code(26) = %q, %v", text, ok)
+	}
+	for _, line := range []int{7, 20, 28} {
+		if text, ok := nb.Code(line); ok {
+			t.Errorf("Code(%d) = %q, want no code in markdown, outputs or brackets", line, text)
+		}
+	}
+	if c := nb.CellAt(7); c == nil || c.Number != 1 {
+		t.Errorf("CellAt(7) = %+v, want the markdown cell", c)
+	}
+	if c := nb.SourceAfter(27); c == nil || c.Number != 2 {
+		t.Errorf("SourceAfter(27) = %+v, want the code cell", c)
+	}
+	if c := nb.SourceAfter(28); c != nil {
+		t.Errorf("SourceAfter(28) = %+v, want none after the source", c)
+	}
+
+	source := strings.Split(nb.Source(), "\n")
+	if len(source) != strings.Count(sample, "\n")+1 || source[24] != "import pandas as pd" || source[6] != "" {
+		t.Errorf("Source() lines = %q", source)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	packed := `{"cells": [{"cell_type": "code", "source": ["a = 1\n", "b = 2"]}]}`
+	if _, err := Parse(packed); !errors.Is(err, ErrPacked) {
+		t.Errorf("Parse(minified) error = %v, want ErrPacked", err)
+	}
+	if _, err := Parse(`{"nbformat": 4}`); err == nil {
+		t.Error("Parse() of a notebook without cells succeeded")
+	}
+	if _, err := Parse(`{"cells": [`); err == nil {
+		t.Error("Parse() of truncated JSON succeeded")
+	}
+}
+
+func TestDecodeLine(t *testing.T) {
+	if text, ok := DecodeLine(`    "print(\"hi\")\n",`); !ok || text != `print("hi")` {
+		t.Errorf("DecodeLine() = %q, %v", text, ok)
+	}
+	if _, ok := DecodeLine(`   "source": [`); ok {
+		t.Error("DecodeLine() decoded a key")
+	}
+}
@@ -0,0 +1,290 @@
+// Package parquet writes small Parquet files: a single row group of flat,
+// uncompressed, PLAIN-encoded columns. It covers what tabular exports need
+// without pulling in a full Parquet implementation.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Type is a column type.
+type Type int
+
+// Column types
+const (
+	String Type = iota
+	Int32
+	Int64
+	Bool
+	Timestamp // Stored as INT64 milliseconds since the Unix epoch, UTC
+)
+
+// Parquet physical types
+const (
+	physicalBoolean   = 0
+	physicalInt32     = 1
+	physicalInt64     = 2
+	physicalByteArray = 6
+)
+
+// Parquet converted types, encodings and repetitions
+const (
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	pageTypeData = 0
+)
+
+var magic = []byte("PAR1")
+
+// Column describes a column of the file.
+type Column struct {
+	Name     string
+	Type     Type
+	Optional bool // Optional columns accept nil values
+}
+
+// Write writes rows as a Parquet file. Row values must match the column
+// types: string, int32, int64, bool or time.Time. Optional columns also
+// accept nil.
+func Write(w io.Writer, columns []Column, rows [][]interface{}) error {
+	var out bytes.Buffer
+	out.Write(magic)
+
+	chunks := make([]chunkMeta, len(columns))
+	for i, col := range columns {
+		values := make([]interface{}, len(rows))
+		for r, row := range rows {
+			if len(row) != len(columns) {
+				return fmt.Errorf("row %d has %d values, want %d", r, len(row), len(columns))
+			}
+			values[r] = row[i]
+		}
+
+		page, err := encodePage(col, values)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		header := pageHeader(len(page), len(rows))
+
+		chunks[i] = chunkMeta{offset: int64(out.Len()), size: int64(len(header) + len(page))}
+		out.Write(header)
+		out.Write(page)
+	}
+
+	footer := fileMetadata(columns, chunks, len(rows))
+	out.Write(footer)
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer))) // #nosec G115 - footer size is small
+	out.Write(length[:])
+	out.Write(magic)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// chunkMeta locates a column chunk in the file
+type chunkMeta struct {
+	offset int64
+	size   int64
+}
+
+// encodePage encodes the definition levels (for optional columns) and the
+// non-null values of a data page.
+func encodePage(col Column, values []interface{}) ([]byte, error) {
+	var page bytes.Buffer
+	present := values
+	if col.Optional {
+		levels := make([]byte, len(values))
+		present = present[:0:0]
+		for i, v := range values {
+			if v != nil {
+				levels[i] = 1
+				present = append(present, v)
+			}
+		}
+		encodeLevels(&page, levels)
+	}
+
+	switch col.Type {
+	case Bool:
+		packed := make([]byte, (len(present)+7)/8)
+		for i, v := range present {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, typeError(v, "bool")
+			}
+			if b {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		page.Write(packed)
+	default:
+		for _, v := range present {
+			if err := encodePlain(&page, col.Type, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return page.Bytes(), nil
+}
+
+func encodePlain(buf *bytes.Buffer, typ Type, v interface{}) error {
+	var b [8]byte
+	switch typ {
+	case String:
+		s, ok := v.(string)
+		if !ok {
+			return typeError(v, "string")
+		}
+		binary.LittleEndian.PutUint32(b[:4], uint32(len(s))) // #nosec G115 - values are far below 4GB
+		buf.Write(b[:4])
+		buf.WriteString(s)
+	case Int32:
+		n, ok := v.(int32)
+		if !ok {
+			return typeError(v, "int32")
+		}
+		binary.LittleEndian.PutUint32(b[:4], uint32(n)) // #nosec G115 - two's complement encoding
+		buf.Write(b[:4])
+	case Int64, Timestamp:
+		var n int64
+		switch x := v.(type) {
+		case int64:
+			n = x
+		case time.Time:
+			n = x.UnixMilli()
+		default:
+			return typeError(v, "int64 or time.Time")
+		}
+		binary.LittleEndian.PutUint64(b[:], uint64(n)) // #nosec G115 - two's complement encoding
+		buf.Write(b[:])
+	}
+	return nil
+}
+
+func typeError(v interface{}, want string) error {
+	return fmt.Errorf("value %v is %T, want %s", v, v, want)
+}
+
+// encodeLevels writes definition levels (bit width 1) with the RLE/bit-packed
+// hybrid encoding, using RLE runs only, prefixed by their byte length.
+func encodeLevels(buf *bytes.Buffer, levels []byte) {
+	var runs bytes.Buffer
+	var tmp [binary.MaxVarintLen64]byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		n := binary.PutUvarint(tmp[:], uint64(j-i)<<1)
+		runs.Write(tmp[:n])
+		runs.WriteByte(levels[i])
+		i = j
+	}
+
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(runs.Len())) // #nosec G115 - small
+	buf.Write(length[:])
+	buf.Write(runs.Bytes())
+}
+
+// pageHeader encodes the header of an uncompressed data page.
+func pageHeader(size, numValues int) []byte {
+	t := &thriftWriter{}
+	t.i32(1, pageTypeData)
+	t.i32(2, int32(size)) // #nosec G115 - page sizes are small
+	t.i32(3, int32(size)) // #nosec G115
+	t.structField(5, func() {
+		t.i32(1, int32(numValues)) // #nosec G115
+		t.i32(2, encodingPlain)
+		t.i32(3, encodingRLE)
+		t.i32(4, encodingRLE)
+	})
+	return t.finish()
+}
+
+// fileMetadata encodes the file footer.
+func fileMetadata(columns []Column, chunks []chunkMeta, numRows int) []byte {
+	t := &thriftWriter{}
+	t.i32(1, 1) // Format version
+
+	t.structList(2, len(columns)+1, func(i int) {
+		if i == 0 {
+			t.string(4, "schema")
+			t.i32(5, int32(len(columns))) // #nosec G115
+			return
+		}
+		col := columns[i-1]
+		physical, converted := physicalType(col.Type)
+		t.i32(1, physical)
+		repetition := int32(repetitionRequired)
+		if col.Optional {
+			repetition = repetitionOptional
+		}
+		t.i32(3, repetition)
+		t.string(4, col.Name)
+		if converted >= 0 {
+			t.i32(6, converted)
+		}
+	})
+
+	t.i64(3, int64(numRows))
+
+	rowGroups := 1
+	if numRows == 0 {
+		rowGroups = 0
+	}
+	t.structList(4, rowGroups, func(int) {
+		var total int64
+		for _, c := range chunks {
+			total += c.size
+		}
+		t.structList(1, len(columns), func(i int) {
+			col, chunk := columns[i], chunks[i]
+			physical, _ := physicalType(col.Type)
+			t.i64(2, chunk.offset)
+			t.structField(3, func() {
+				t.i32(1, physical)
+				t.i32List(2, encodingPlain, encodingRLE)
+				t.stringList(3, col.Name)
+				t.i32(4, 0) // Uncompressed
+				t.i64(5, int64(numRows))
+				t.i64(6, chunk.size)
+				t.i64(7, chunk.size)
+				t.i64(9, chunk.offset)
+			})
+		})
+		t.i64(2, total)
+		t.i64(3, int64(numRows))
+	})
+
+	t.string(6, "goreview")
+	return t.finish()
+}
+
+// physicalType returns the Parquet physical and converted type (-1 for none).
+func physicalType(typ Type) (physical, converted int32) {
+	switch typ {
+	case Int32:
+		return physicalInt32, -1
+	case Int64:
+		return physicalInt64, -1
+	case Bool:
+		return physicalBoolean, -1
+	case Timestamp:
+		return physicalInt64, convertedTimestampMillis
+	default:
+		return physicalByteArray, convertedUTF8
+	}
+}
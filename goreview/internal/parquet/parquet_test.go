@@ -0,0 +1,164 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// thriftReader decodes Thrift compact structs into maps keyed by field ID,
+// enough to check the footer written by Write.
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	u := r.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case thriftBoolTrue:
+		return true
+	case thriftBoolFalse:
+		return false
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := int(r.uvarint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case thriftList:
+		header := r.data[r.pos]
+		r.pos++
+		n, elem := int(header>>4), header&0x0f
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.value(elem)
+		}
+		return list
+	case thriftStruct:
+		return r.readStruct()
+	}
+	panic("unsupported thrift type")
+}
+
+func (r *thriftReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		typ := header & 0x0f
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(typ)
+		last = id
+	}
+}
+
+func TestWrite(t *testing.T) {
+	columns := []Column{
+		{Name: "file", Type: String},
+		{Name: "line", Type: Int32, Optional: true},
+		{Name: "resolved", Type: Bool},
+		{Name: "created_at", Type: Timestamp},
+	}
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	rows := [][]interface{}{
+		{"main.go", int32(12), true, created},
+		{"util.go", nil, false, created},
+		{"api.go", int32(7), true, created},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, columns, rows); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data := buf.Bytes()
+
+	if !bytes.HasPrefix(data, magic) || !bytes.HasSuffix(data, magic) {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := (&thriftReader{data: data[len(data)-8-footerLen : len(data)-8]}).readStruct()
+
+	if footer[3].(int64) != 3 {
+		t.Errorf("num_rows = %v, want 3", footer[3])
+	}
+	schema := footer[2].([]interface{})
+	if len(schema) != 5 || schema[1].(map[int16]interface{})[4] != "file" {
+		t.Fatalf("schema = %v", schema)
+	}
+	if schema[2].(map[int16]interface{})[3].(int64) != repetitionOptional {
+		t.Error("line column should be optional")
+	}
+
+	chunks := footer[4].([]interface{})[0].(map[int16]interface{})[1].([]interface{})
+	pageAt := func(col int) ([]byte, map[int16]interface{}) {
+		meta := chunks[col].(map[int16]interface{})[3].(map[int16]interface{})
+		r := &thriftReader{data: data, pos: int(meta[9].(int64))}
+		header := r.readStruct()
+		size := int(header[2].(int64))
+		return data[r.pos : r.pos+size], header
+	}
+
+	// Strings: 4-byte length prefix then bytes
+	page, header := pageAt(0)
+	if n := header[5].(map[int16]interface{})[1].(int64); n != 3 {
+		t.Errorf("num_values = %d, want 3", n)
+	}
+	if l := binary.LittleEndian.Uint32(page); l != 7 || string(page[4:11]) != "main.go" {
+		t.Errorf("first string = %q", page[4:4+l])
+	}
+
+	// Optional int32: definition levels (1, 0, 1) then the two present values
+	page, _ = pageAt(1)
+	levelsLen := int(binary.LittleEndian.Uint32(page))
+	if !bytes.Equal(page[4:4+levelsLen], []byte{2, 1, 2, 0, 2, 1}) {
+		t.Errorf("definition levels = %v", page[4:4+levelsLen])
+	}
+	values := page[4+levelsLen:]
+	if len(values) != 8 || binary.LittleEndian.Uint32(values) != 12 || binary.LittleEndian.Uint32(values[4:]) != 7 {
+		t.Errorf("int32 values = %v", values)
+	}
+
+	// Booleans are bit-packed
+	if page, _ = pageAt(2); !bytes.Equal(page, []byte{0b101}) {
+		t.Errorf("bool page = %08b", page)
+	}
+
+	// Timestamps are milliseconds
+	if page, _ = pageAt(3); int64(binary.LittleEndian.Uint64(page)) != created.UnixMilli() {
+		t.Errorf("timestamp = %d", binary.LittleEndian.Uint64(page))
+	}
+}
+
+func TestWriteTypeMismatch(t *testing.T) {
+	err := Write(&bytes.Buffer{}, []Column{{Name: "n", Type: Int64}}, [][]interface{}{{"oops"}})
+	if err == nil {
+		t.Error("expected an error for a string in an int64 column")
+	}
+	err = Write(&bytes.Buffer{}, []Column{{Name: "n", Type: Int64}}, [][]interface{}{{nil}})
+	if err == nil {
+		t.Error("expected an error for nil in a required column")
+	}
+}
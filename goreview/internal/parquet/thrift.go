@@ -0,0 +1,121 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type IDs
+const (
+	thriftBoolTrue  = 1
+	thriftBoolFalse = 2
+	thriftI32       = 5
+	thriftI64       = 6
+	thriftBinary    = 8
+	thriftList      = 9
+	thriftStruct    = 12
+)
+
+// thriftWriter encodes structs with the Thrift compact protocol, which
+// Parquet uses for page headers and file metadata.
+type thriftWriter struct {
+	buf    bytes.Buffer
+	lastID int16 // Last field ID of the struct being written
+}
+
+func (t *thriftWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	t.buf.Write(b[:n])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63) // #nosec G115 - zigzag encoding
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - t.lastID; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.uvarint(zigzag(int64(id)))
+	}
+	t.lastID = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.uvarint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.uvarint(zigzag(v))
+}
+
+func (t *thriftWriter) bool(id int16, v bool) {
+	if v {
+		t.fieldHeader(id, thriftBoolTrue)
+	} else {
+		t.fieldHeader(id, thriftBoolFalse)
+	}
+}
+
+func (t *thriftWriter) string(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.uvarint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+// structField writes a nested struct whose fields are written by fn.
+func (t *thriftWriter) structField(id int16, fn func()) {
+	t.fieldHeader(id, thriftStruct)
+	t.nested(fn)
+}
+
+// nested writes a struct body; field IDs restart from zero inside it.
+func (t *thriftWriter) nested(fn func()) {
+	saved := t.lastID
+	t.lastID = 0
+	fn()
+	t.buf.WriteByte(0) // Stop field
+	t.lastID = saved
+}
+
+func (t *thriftWriter) listHeader(id int16, elemType byte, n int) {
+	t.fieldHeader(id, thriftList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xf0 | elemType)
+		t.uvarint(uint64(n))
+	}
+}
+
+func (t *thriftWriter) structList(id int16, n int, elem func(i int)) {
+	t.listHeader(id, thriftStruct, n)
+	for i := 0; i < n; i++ {
+		t.nested(func() { elem(i) })
+	}
+}
+
+func (t *thriftWriter) i32List(id int16, values ...int32) {
+	t.listHeader(id, thriftI32, len(values))
+	for _, v := range values {
+		t.uvarint(zigzag(int64(v)))
+	}
+}
+
+func (t *thriftWriter) stringList(id int16, values ...string) {
+	t.listHeader(id, thriftBinary, len(values))
+	for _, v := range values {
+		t.uvarint(uint64(len(v)))
+		t.buf.WriteString(v)
+	}
+}
+
+// finish ends the top-level struct and returns its encoding.
+func (t *thriftWriter) finish() []byte {
+	t.buf.WriteByte(0)
+	return t.buf.Bytes()
+}
@@ -0,0 +1,90 @@
+// Package privacy redacts sensitive data from what goreview saves outside
+// its reports, such as provider transcripts, following the privacy
+// configuration.
+package privacy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// Mask replaces redacted text.
+const Mask = "[REDACTED]"
+
+// secretPatterns match credentials. Patterns with a capture group mask only
+// the group, so the surrounding key name stays readable.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bsk-(?:ant-)?[A-Za-z0-9_-]{20,}`),                                                         // OpenAI, Anthropic
+	regexp.MustCompile(`\bAIza[A-Za-z0-9_-]{35}`),                                                                  // Google API
+	regexp.MustCompile(`\bgsk_[A-Za-z0-9]{20,}`),                                                                   // Groq
+	regexp.MustCompile(`\bgh[posru]_[A-Za-z0-9]{36}`),                                                              // GitHub tokens
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{50,}`),                                                           // GitHub fine-grained
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),                                                          // Slack
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b`),                                                            // AWS access key
+	regexp.MustCompile(`(?i)\bBearer\s+([A-Za-z0-9._~+/-]{16,}=*)`),                                                // Bearer tokens
+	regexp.MustCompile(`(?i)(?:api[_-]?key|secret|token|password|passwd)["']?\s*(?::=|[:=])\s*["']?([^\s"']{8,})`), // Assignments
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),               // Private keys
+}
+
+// Redactor masks secrets and configured patterns.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// New returns the redactor for the privacy configuration.
+func New(cfg config.PrivacyConfig) (*Redactor, error) {
+	r := &Redactor{}
+	if cfg.RedactSecrets {
+		r.patterns = append(r.patterns, secretPatterns...)
+	}
+	for _, p := range cfg.RedactPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Enabled reports whether the redactor masks anything.
+func (r *Redactor) Enabled() bool {
+	return r != nil && len(r.patterns) > 0
+}
+
+// Redact returns s with every match masked. A nil Redactor returns s
+// unchanged.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, re := range r.patterns {
+		s = redactMatches(re, s)
+	}
+	return s
+}
+
+// redactMatches masks the matches of re in s: the first capture group when
+// the pattern has one, the whole match otherwise.
+func redactMatches(re *regexp.Regexp, s string) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(Mask)
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
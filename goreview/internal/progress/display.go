@@ -0,0 +1,188 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mode selects how progress is rendered.
+type Mode string
+
+const (
+	// ModeAuto picks ModeTTY on a terminal and ModeLog otherwise
+	ModeAuto Mode = "auto"
+	// ModeTTY redraws a single status line
+	ModeTTY Mode = "tty"
+	// ModeLog prints a structured line at a fixed interval
+	ModeLog Mode = "log"
+	// ModeOff disables progress output
+	ModeOff Mode = "off"
+)
+
+// Render intervals
+const (
+	ttyInterval = 100 * time.Millisecond
+	logInterval = 10 * time.Second
+)
+
+// Bar and line widths for the terminal status line
+const (
+	barWidth      = 20
+	maxFileLength = 40
+)
+
+var spinnerFrames = []rune{'⠋', '⠙', '⠹', '⠸', '⠼', '⠴', '⠦', '⠧', '⠇', '⠏'}
+
+// ParseMode parses a --progress value.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(s)); m {
+	case "", ModeAuto:
+		return ModeAuto, nil
+	case ModeTTY, ModeLog, ModeOff:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid progress mode %q (valid: auto, tty, log, off)", s)
+	}
+}
+
+// ResolveMode turns ModeAuto into ModeTTY when f is a terminal outside CI,
+// and ModeLog otherwise.
+func ResolveMode(mode Mode, f *os.File) Mode {
+	if mode != ModeAuto {
+		return mode
+	}
+	if os.Getenv("CI") == "true" || os.Getenv("GITHUB_ACTIONS") == "true" {
+		return ModeLog
+	}
+	if fi, err := f.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		return ModeTTY
+	}
+	return ModeLog
+}
+
+// Display periodically renders a Tracker until stopped.
+type Display struct {
+	tracker  *Tracker
+	w        io.Writer
+	mode     Mode
+	interval time.Duration
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	frame    int
+}
+
+// NewDisplay creates a display writing to w in the given (resolved) mode.
+func NewDisplay(tracker *Tracker, w io.Writer, mode Mode) *Display {
+	d := &Display{
+		tracker: tracker,
+		w:       w,
+		mode:    mode,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	d.interval = logInterval
+	if mode == ModeTTY {
+		d.interval = ttyInterval
+	}
+	return d
+}
+
+// Start begins rendering in the background.
+func (d *Display) Start() {
+	if d.mode == ModeOff {
+		close(d.done)
+		return
+	}
+	go d.run()
+}
+
+// Stop stops rendering. On a terminal the status line is replaced by a
+// final summary line; in log mode a last progress line is printed.
+func (d *Display) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+		<-d.done
+	})
+}
+
+func (d *Display) run() {
+	defer close(d.done)
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			d.finish()
+			return
+		case <-ticker.C:
+			d.render()
+		}
+	}
+}
+
+func (d *Display) render() {
+	s := d.tracker.Snapshot()
+	if d.mode == ModeTTY {
+		_, _ = fmt.Fprintf(d.w, "\r\033[K%s", d.statusLine(s))
+		d.frame++
+		return
+	}
+	_, _ = fmt.Fprintln(d.w, LogLine(s))
+}
+
+func (d *Display) finish() {
+	s := d.tracker.Snapshot()
+	if d.mode == ModeTTY {
+		_, _ = fmt.Fprintf(d.w, "\r\033[KReviewed %d/%d files in %s", s.Completed, s.Total, s.Elapsed.Round(time.Millisecond))
+		if s.Failed > 0 {
+			_, _ = fmt.Fprintf(d.w, " (%d failed)", s.Failed)
+		}
+		_, _ = fmt.Fprintln(d.w)
+		return
+	}
+	_, _ = fmt.Fprintln(d.w, LogLine(s))
+}
+
+// statusLine renders the terminal status line.
+func (d *Display) statusLine(s Snapshot) string {
+	spinner := spinnerFrames[d.frame%len(spinnerFrames)]
+	filled := int(s.Percent() / 100 * barWidth)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+
+	line := fmt.Sprintf("%c Reviewing [%s] %3.0f%% %d/%d", spinner, bar, s.Percent(), s.Completed, s.Total)
+	if s.InFlight > 0 {
+		line += fmt.Sprintf(" · %d in flight", s.InFlight)
+	}
+	if s.Tokens > 0 {
+		line += fmt.Sprintf(" · %d tokens", s.Tokens)
+	}
+	if s.ETA > 0 {
+		line += fmt.Sprintf(" · ETA %s", s.ETA.Round(time.Second))
+	}
+	if len(s.Active) > 0 {
+		line += " · " + shortenPath(s.Active[0])
+	}
+	return line
+}
+
+// LogLine renders a snapshot as a key=value line for CI logs.
+func LogLine(s Snapshot) string {
+	return fmt.Sprintf("progress queued=%d in_flight=%d completed=%d total=%d failed=%d tokens=%d elapsed=%s eta=%s",
+		s.Queued, s.InFlight, s.Completed, s.Total, s.Failed, s.Tokens,
+		s.Elapsed.Round(time.Second), s.ETA.Round(time.Second))
+}
+
+// shortenPath keeps the end of long paths, where the file name is.
+func shortenPath(path string) string {
+	if len(path) <= maxFileLength {
+		return path
+	}
+	return "…" + path[len(path)-(maxFileLength-1):]
+}
@@ -0,0 +1,127 @@
+// Package progress tracks the progress of concurrent file reviews and
+// renders it as an updating status line on a terminal or as periodic log
+// lines in CI.
+package progress
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tracker counts queued, in-flight and completed files. It is safe for
+// concurrent use, and a nil Tracker ignores all calls so callers don't need
+// to check whether progress is enabled.
+type Tracker struct {
+	mu        sync.Mutex
+	start     time.Time
+	total     int
+	completed int
+	failed    int
+	tokens    int
+	active    map[string]time.Time
+}
+
+// Snapshot is a consistent view of a Tracker at one point in time.
+type Snapshot struct {
+	Total     int           `json:"total"`
+	Queued    int           `json:"queued"`
+	InFlight  int           `json:"in_flight"`
+	Completed int           `json:"completed"`
+	Failed    int           `json:"failed"`
+	Tokens    int           `json:"tokens"`
+	Elapsed   time.Duration `json:"elapsed"`
+	// ETA is the estimated time left, or zero until a file completes
+	ETA time.Duration `json:"eta"`
+	// Active lists in-flight files, longest-running first
+	Active []string `json:"active,omitempty"`
+}
+
+// NewTracker creates a tracker. The clock starts at the first Queue call.
+func NewTracker() *Tracker {
+	return &Tracker{active: make(map[string]time.Time)}
+}
+
+// Queue adds n files to the total.
+func (t *Tracker) Queue(n int) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.start.IsZero() {
+		t.start = time.Now()
+	}
+	t.total += n
+}
+
+// Begin marks a file as in flight.
+func (t *Tracker) Begin(file string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active[file] = time.Now()
+}
+
+// Done marks a file as completed, adding the tokens its review used.
+func (t *Tracker) Done(file string, tokens int, failed bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.active, file)
+	t.completed++
+	t.tokens += tokens
+	if failed {
+		t.failed++
+	}
+}
+
+// Snapshot returns the current progress.
+func (t *Tracker) Snapshot() Snapshot {
+	if t == nil {
+		return Snapshot{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := Snapshot{
+		Total:     t.total,
+		InFlight:  len(t.active),
+		Completed: t.completed,
+		Failed:    t.failed,
+		Tokens:    t.tokens,
+	}
+	s.Queued = max(t.total-t.completed-s.InFlight, 0)
+	if !t.start.IsZero() {
+		s.Elapsed = time.Since(t.start)
+	}
+	if t.completed > 0 && t.completed < t.total {
+		perFile := s.Elapsed / time.Duration(t.completed)
+		s.ETA = perFile * time.Duration(t.total-t.completed)
+	}
+
+	s.Active = make([]string, 0, len(t.active))
+	for file := range t.active {
+		s.Active = append(s.Active, file)
+	}
+	sort.Slice(s.Active, func(i, j int) bool {
+		ti, tj := t.active[s.Active[i]], t.active[s.Active[j]]
+		if ti.Equal(tj) {
+			return s.Active[i] < s.Active[j]
+		}
+		return ti.Before(tj)
+	})
+	return s
+}
+
+// Percent returns the completed share of the total, from 0 to 100.
+func (s Snapshot) Percent() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Completed) / float64(s.Total) * 100
+}
@@ -0,0 +1,138 @@
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTrackerConcurrent(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Queue(100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			file := fmt.Sprintf("file%d.go", i)
+			tracker.Begin(file)
+			_ = tracker.Snapshot()
+			tracker.Done(file, 10, i%10 == 0)
+		}(i)
+	}
+	wg.Wait()
+
+	s := tracker.Snapshot()
+	if s.Completed != 100 || s.Total != 100 {
+		t.Errorf("completed = %d/%d, want 100/100", s.Completed, s.Total)
+	}
+	if s.Failed != 10 {
+		t.Errorf("failed = %d, want 10", s.Failed)
+	}
+	if s.Tokens != 1000 {
+		t.Errorf("tokens = %d, want 1000", s.Tokens)
+	}
+	if s.InFlight != 0 || s.Queued != 0 {
+		t.Errorf("in flight = %d, queued = %d, want 0", s.InFlight, s.Queued)
+	}
+}
+
+func TestTrackerSnapshot(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Queue(4)
+	tracker.Begin("a.go")
+	tracker.Begin("b.go")
+	tracker.Done("a.go", 50, false)
+
+	s := tracker.Snapshot()
+	if s.Queued != 2 || s.InFlight != 1 || s.Completed != 1 {
+		t.Errorf("queued/in flight/completed = %d/%d/%d, want 2/1/1", s.Queued, s.InFlight, s.Completed)
+	}
+	if len(s.Active) != 1 || s.Active[0] != "b.go" {
+		t.Errorf("active = %v, want [b.go]", s.Active)
+	}
+	if s.Percent() != 25 {
+		t.Errorf("percent = %v, want 25", s.Percent())
+	}
+	if s.ETA <= 0 {
+		t.Error("expected an ETA once a file completed")
+	}
+}
+
+func TestTrackerNil(t *testing.T) {
+	var tracker *Tracker
+	tracker.Queue(1)
+	tracker.Begin("a.go")
+	tracker.Done("a.go", 1, false)
+	if s := tracker.Snapshot(); s.Total != 0 {
+		t.Errorf("nil tracker snapshot = %+v", s)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Mode
+		wantErr bool
+	}{
+		{"", ModeAuto, false},
+		{"auto", ModeAuto, false},
+		{"TTY", ModeTTY, false},
+		{"log", ModeLog, false},
+		{"off", ModeOff, false},
+		{"bars", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMode(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMode(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestDisplayLogMode(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Queue(2)
+	tracker.Begin("a.go")
+	tracker.Done("a.go", 120, false)
+
+	var buf bytes.Buffer
+	d := NewDisplay(tracker, &buf, ModeLog)
+	d.Start()
+	d.Stop()
+	d.Stop() // Safe to call twice
+
+	out := buf.String()
+	for _, want := range []string{"progress ", "queued=1", "completed=1", "total=2", "tokens=120"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output %q missing %q", out, want)
+		}
+	}
+}
+
+func TestDisplayTTYMode(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Queue(2)
+	tracker.Begin("internal/review/a_very_long_directory_name/engine_with_long_name.go")
+
+	d := NewDisplay(tracker, &bytes.Buffer{}, ModeTTY)
+	line := d.statusLine(tracker.Snapshot())
+	for _, want := range []string{"0/2", "1 in flight", "engine_with_long_name.go"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("status line %q missing %q", line, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	d = NewDisplay(tracker, &buf, ModeTTY)
+	d.Start()
+	time.Sleep(2 * ttyInterval)
+	d.Stop()
+	if !strings.Contains(buf.String(), "Reviewed 0/2 files") {
+		t.Errorf("missing final summary in %q", buf.String())
+	}
+}
@@ -0,0 +1,115 @@
+// Package provenance finds added code that likely wasn't written by hand:
+// blocks carrying an AI assistant marker, and large paste-like additions.
+// Detection only looks at the added lines, so it works for any language.
+package provenance
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Block kinds
+const (
+	// KindMarker is a block with a comment saying an AI assistant wrote it
+	KindMarker = "ai_marker"
+	// KindPaste is a long run of added lines with no surrounding edits
+	KindPaste = "paste"
+)
+
+// DefaultMarkers are the default marker patterns, matched case-insensitively
+var DefaultMarkers = []string{
+	`\b(generated|written|created|produced|suggested)\s+(by|with|using)\s+(chatgpt|gpt-?\d[\w.-]*|openai|(github\s+)?copilot|claude|gemini|bard|codeium|tabnine|cursor|codex|an?\s+(ai|llm)|ai)\b`,
+	`\b(ai|llm|copilot|chatgpt)[- ]generated\b`,
+}
+
+// Block is a run of added lines that looks generated or pasted.
+type Block struct {
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Kind      string `json:"kind"`
+	// Marker is the line holding the marker, for marker blocks
+	Marker string `json:"marker,omitempty"`
+}
+
+// Line is an added line and its number in the new file.
+type Line struct {
+	Number  int
+	Content string
+}
+
+// Detector finds generated blocks in runs of added lines.
+type Detector struct {
+	markers  []*regexp.Regexp
+	minPaste int
+}
+
+// NewDetector returns a detector for the marker patterns, or the default
+// ones when empty. Runs of at least minPasteLines added lines are reported
+// as pastes; 0 disables paste detection.
+func NewDetector(markers []string, minPasteLines int) (*Detector, error) {
+	if len(markers) == 0 {
+		markers = DefaultMarkers
+	}
+	d := &Detector{minPaste: minPasteLines}
+	for _, m := range markers {
+		re, err := regexp.Compile("(?i)" + m)
+		if err != nil {
+			return nil, fmt.Errorf("invalid marker pattern %q: %w", m, err)
+		}
+		d.markers = append(d.markers, re)
+	}
+	return d, nil
+}
+
+// Detect returns the generated blocks among runs of consecutive added
+// lines. A run holding a marker is a marker block. Other long runs are
+// pastes, except in new files, which are a single run anyway.
+func (d *Detector) Detect(runs [][]Line, newFile bool) []Block {
+	var blocks []Block
+	for _, run := range runs {
+		if len(run) == 0 {
+			continue
+		}
+		block := Block{StartLine: run[0].Number, EndLine: run[len(run)-1].Number}
+		if marker, ok := d.marker(run); ok {
+			block.Kind, block.Marker = KindMarker, marker
+			blocks = append(blocks, block)
+			continue
+		}
+		if !newFile && d.minPaste > 0 && len(run) >= d.minPaste {
+			block.Kind = KindPaste
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func (d *Detector) marker(run []Line) (string, bool) {
+	for _, line := range run {
+		for _, re := range d.markers {
+			if re.MatchString(line.Content) {
+				return strings.TrimSpace(line.Content), true
+			}
+		}
+	}
+	return "", false
+}
+
+// Describe returns a short description of the block, like "lines 10-80
+// (AI marker)".
+func (b Block) Describe() string {
+	lines := fmt.Sprintf("line %d", b.StartLine)
+	if b.EndLine > b.StartLine {
+		lines = fmt.Sprintf("lines %d-%d", b.StartLine, b.EndLine)
+	}
+	if b.Kind == KindMarker {
+		return lines + " (AI marker)"
+	}
+	return lines + " (paste-like addition)"
+}
+
+// Contains reports whether the line is in the block.
+func (b Block) Contains(line int) bool {
+	return line >= b.StartLine && line <= b.EndLine
+}
@@ -0,0 +1,63 @@
+package provenance
+
+import (
+	"fmt"
+	"testing"
+)
+
+func run(start, n int, content string) []Line {
+	lines := make([]Line, n)
+	for i := range lines {
+		lines[i] = Line{Number: start + i, Content: fmt.Sprintf("x%d := %d", i, i)}
+	}
+	if content != "" {
+		lines[0].Content = content
+	}
+	return lines
+}
+
+func TestDetect(t *testing.T) {
+	d, err := NewDetector(nil, 20)
+	if err != nil {
+		t.Fatalf("NewDetector() error = %v", err)
+	}
+
+	runs := [][]Line{
+		run(3, 5, "// Generated by ChatGPT, reviewed by alice"),
+		run(20, 4, "x := 1 // AI-generated"),
+		run(40, 19, ""), // One line short of a paste
+		run(70, 25, ""),
+		run(100, 3, "// Written with GPT-4o"),
+		run(110, 3, "// generated by protoc-gen-go"), // Not an AI marker
+	}
+	want := []Block{
+		{StartLine: 3, EndLine: 7, Kind: KindMarker, Marker: "// Generated by ChatGPT, reviewed by alice"},
+		{StartLine: 20, EndLine: 23, Kind: KindMarker, Marker: "x := 1 // AI-generated"},
+		{StartLine: 70, EndLine: 94, Kind: KindPaste},
+		{StartLine: 100, EndLine: 102, Kind: KindMarker, Marker: "// Written with GPT-4o"},
+	}
+	got := d.Detect(runs, false)
+	if len(got) != len(want) {
+		t.Fatalf("Detect() = %+v, want %d blocks", got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("block %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// A new file is one long run; only markers label it
+	if got := d.Detect([][]Line{run(1, 200, "")}, true); len(got) != 0 {
+		t.Errorf("Detect() on a new file = %+v, want none", got)
+	}
+
+	if want := "lines 70-94 (paste-like addition)"; want != got[2].Describe() {
+		t.Errorf("Describe() = %q, want %q", got[2].Describe(), want)
+	}
+}
+
+func TestNewDetectorInvalidMarker(t *testing.T) {
+	if _, err := NewDetector([]string{"("}, 0); err == nil {
+		t.Error("NewDetector() accepted an invalid pattern")
+	}
+}
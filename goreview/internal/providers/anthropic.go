@@ -0,0 +1,307 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+const (
+	// AnthropicMessagesPath is the Messages API endpoint
+	AnthropicMessagesPath = "/messages"
+	// anthropicVersion is the API version sent with every request
+	anthropicVersion = "2023-06-01"
+)
+
+// AnthropicProvider implements Provider using the Anthropic Messages API.
+// Answers are always streamed, so long generations don't hit the
+// timeouts of non-streaming requests: the provider timeout bounds the wait
+// for the response headers and for each event, and the caller's context
+// bounds the whole answer.
+type AnthropicProvider struct {
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+	config  *config.ProviderConfig
+}
+
+// NewAnthropicProvider creates a new Anthropic provider.
+func NewAnthropicProvider(cfg *config.Config) (*AnthropicProvider, error) {
+	if cfg.Provider.APIKey == "" {
+		return nil, fmt.Errorf("Anthropic API key required")
+	}
+
+	baseURL := cfg.Provider.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1"
+	}
+
+	model := cfg.Provider.Model
+	if model == "" {
+		model = "claude-sonnet-4-5"
+	}
+
+	return &AnthropicProvider{
+		apiKey:  cfg.Provider.APIKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		config:  &cfg.Provider,
+		client:  &http.Client{Transport: headerTimeoutTransport(cfg.Provider.Timeout)},
+	}, nil
+}
+
+func (p *AnthropicProvider) Name() string { return "anthropic" }
+
+func (p *AnthropicProvider) Review(ctx context.Context, req *ReviewRequest) (*ReviewResponse, error) {
+	if empty, err := ValidateReviewInput(req); err != nil {
+		return nil, err
+	} else if empty {
+		return &ReviewResponse{}, nil
+	}
+
+	start := time.Now()
+	prompt := BuildReviewPrompt(req)
+	msg, err := p.stream(ctx, ReviewSystemPrompt, prompt, "")
+	if err != nil {
+		return nil, fmt.Errorf("anthropic request failed: %w", err)
+	}
+
+	return ParseReviewContent(msg.text, msg.inputTokens+msg.outputTokens, time.Since(start).Milliseconds()).withPrompt(ReviewSystemPrompt, prompt), nil
+}
+
+func (p *AnthropicProvider) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
+	return p.generate(ctx, fmt.Sprintf(CommitMessagePrompt, diff))
+}
+
+func (p *AnthropicProvider) GenerateDocumentation(ctx context.Context, diff, docContext string) (string, error) {
+	return p.generate(ctx, fmt.Sprintf(DocumentationPrompt, docContext, diff))
+}
+
+// GenerateJSON prefills the answer with "{", as the Messages API has no
+// JSON mode.
+func (p *AnthropicProvider) GenerateJSON(ctx context.Context, prompt string, _ JSONSchema) (string, error) {
+	msg, err := p.stream(ctx, StructuredSystemPrompt, prompt, "{")
+	if err != nil {
+		return "", fmt.Errorf("anthropic request failed: %w", err)
+	}
+	if msg.text == "" {
+		return "", fmt.Errorf("no response from Anthropic")
+	}
+	return "{" + msg.text, nil
+}
+
+func (p *AnthropicProvider) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/models/"+p.model, nil)
+	if err != nil {
+		return fmt.Errorf(ErrCreateRequest, err)
+	}
+	p.setHeaders(req)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("anthropic health check failed: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *AnthropicProvider) Close() error { return nil }
+
+func (p *AnthropicProvider) generate(ctx context.Context, prompt string) (string, error) {
+	msg, err := p.stream(ctx, "", prompt, "")
+	if err != nil {
+		return "", err
+	}
+	if msg.text == "" {
+		return "", fmt.Errorf("no response from Anthropic")
+	}
+	return msg.text, nil
+}
+
+// headers returns the authentication headers of the API.
+func (p *AnthropicProvider) headers() http.Header {
+	return http.Header{
+		"X-Api-Key":         {p.apiKey},
+		"Anthropic-Version": {anthropicVersion},
+	}
+}
+
+func (p *AnthropicProvider) setHeaders(req *http.Request) {
+	for k, v := range p.headers() {
+		req.Header[k] = v
+	}
+}
+
+// anthropicMessage is a message read from the stream.
+type anthropicMessage struct {
+	text         string
+	inputTokens  int
+	outputTokens int
+}
+
+// stream sends a single-turn conversation and reads the streamed answer.
+// A prefill starts the assistant's turn; the answer continues it.
+func (p *AnthropicProvider) stream(ctx context.Context, system, prompt, prefill string) (*anthropicMessage, error) {
+	opts := requestFor(p.config, system, prompt, false)
+	messages := []map[string]string{{"role": "user", "content": opts.prompt}}
+	if prefill != "" {
+		messages = append(messages, map[string]string{"role": "assistant", "content": prefill})
+	}
+	reqBody := map[string]interface{}{
+		"model":       p.model,
+		"max_tokens":  opts.maxTokens,
+		"temperature": p.config.Temperature,
+		"messages":    messages,
+		"stream":      true,
+	}
+	if opts.system != "" {
+		reqBody["system"] = opts.system
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf(ErrMarshalRequest, err)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+AnthropicMessagesPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf(ErrCreateRequest, err)
+	}
+	p.setHeaders(req)
+	req.Header.Set(HeaderContentType, ContentTypeJSON)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr anthropicError
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apiErr)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: apiErr.Error.Message}
+	}
+
+	events := io.Reader(resp.Body)
+	if timeout := p.config.Timeout; timeout > 0 {
+		idle := time.AfterFunc(timeout, func() { cancel(errStreamIdle) })
+		defer idle.Stop()
+		events = &idleReader{r: resp.Body, timer: idle, timeout: timeout}
+	}
+	msg, err := readAnthropicStream(events)
+	if err != nil && errors.Is(context.Cause(ctx), errStreamIdle) {
+		return nil, fmt.Errorf("anthropic stream idle timeout: no event for %s: %w", p.config.Timeout, context.DeadlineExceeded)
+	}
+	return msg, err
+}
+
+// errStreamIdle cancels a stream that sent nothing for the provider timeout.
+var errStreamIdle = errors.New("stream idle")
+
+// headerTimeoutTransport returns a transport that waits at most timeout
+// for the response headers, and then reads the body without a deadline.
+func headerTimeoutTransport(timeout time.Duration) http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = timeout
+	return transport
+}
+
+// idleReader restarts timer whenever data arrives.
+type idleReader struct {
+	r       io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *idleReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+// anthropicError is the error body of the API, also sent as a stream event.
+type anthropicError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// readAnthropicStream reads the server-sent events of a streamed message.
+// An overload reported mid-stream is a StatusError, so it is retried like
+// a 529 answer.
+func readAnthropicStream(r io.Reader) (*anthropicMessage, error) {
+	type usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	}
+	var event struct {
+		anthropicError
+		Type    string `json:"type"`
+		Message struct {
+			Usage usage `json:"usage"`
+		} `json:"message"`
+		Delta struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"delta"`
+		Usage usage `json:"usage"`
+	}
+
+	msg := &anthropicMessage{}
+	var text strings.Builder
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		event.Type, event.Delta.Type, event.Delta.Text = "", "", ""
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return nil, fmt.Errorf(ErrDecodeResponse, err)
+		}
+		switch event.Type {
+		case "message_start":
+			msg.inputTokens = event.Message.Usage.InputTokens
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" {
+				text.WriteString(event.Delta.Text)
+			}
+		case "message_delta":
+			msg.outputTokens = event.Usage.OutputTokens
+		case "message_stop":
+			msg.text = text.String()
+			return msg, nil
+		case "error":
+			if event.Error.Type == "overloaded_error" {
+				return nil, &StatusError{StatusCode: 529, Body: event.Error.Message}
+			}
+			return nil, fmt.Errorf("anthropic stream error: %s: %s", event.Error.Type, event.Error.Message)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading anthropic stream: %w", err)
+	}
+	return nil, fmt.Errorf("anthropic stream ended before the message was complete")
+}
@@ -0,0 +1,202 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// sseEvents writes server-sent events as the Messages API streams them.
+func sseEvents(w http.ResponseWriter, events ...string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, e := range events {
+		var typed struct {
+			Type string `json:"type"`
+		}
+		_ = json.Unmarshal([]byte(e), &typed)
+		_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typed.Type, e)
+	}
+}
+
+func TestAnthropicProvider(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "sk-ant" || r.Header.Get("anthropic-version") != anthropicVersion {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/models/claude-test":
+			_, _ = w.Write([]byte(`{"id":"claude-test","type":"model"}`))
+		case "/v1/messages":
+			got = nil
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			sseEvents(w,
+				`{"type":"message_start","message":{"usage":{"input_tokens":120,"output_tokens":1}}}`,
+				`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+				`{"type":"ping"}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"{\"issues\":[],"}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"\"summary\":\"ok\",\"score\":95}"}}`,
+				`{"type":"content_block_stop","index":0}`,
+				`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":30}}`,
+				`{"type":"message_stop"}`,
+			)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Provider: config.ProviderConfig{BaseURL: srv.URL + "/v1", APIKey: "sk-ant", Model: "claude-test", MaxTokens: 2048}}
+	p, err := NewAnthropicProvider(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := p.Review(context.Background(), &ReviewRequest{Diff: "+x := 1", FilePath: "a.go"})
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if resp.Summary != "ok" || resp.Score != 95 || resp.TokensUsed != 150 {
+		t.Errorf("Review() = %+v, want the streamed answer with 150 tokens", resp)
+	}
+	if got["model"] != "claude-test" || got["max_tokens"] != float64(2048) || got["stream"] != true || got["system"] != ReviewSystemPrompt {
+		t.Errorf("request = %v", got)
+	}
+
+	// The JSON answer continues the "{" prefill
+	out, err := p.GenerateJSON(context.Background(), "prompt", JSONSchema{})
+	if err != nil || !strings.HasPrefix(out, `{{"issues"`) {
+		t.Errorf("GenerateJSON() = %q, %v", out, err)
+	}
+	if msgs, _ := got["messages"].([]interface{}); len(msgs) != 2 {
+		t.Errorf("GenerateJSON() messages = %v, want the prefill", got["messages"])
+	}
+
+	if err := p.HealthCheck(context.Background()); err != nil {
+		t.Errorf("HealthCheck() error = %v", err)
+	}
+	if st := CheckStatus(context.Background(), p); !st.Healthy() || st.ModelAvailable == nil || !*st.ModelAvailable {
+		t.Errorf("CheckStatus() = %+v, want healthy", st)
+	}
+
+	cfg.Provider.APIKey = "sk-wrong"
+	p, _ = NewAnthropicProvider(cfg)
+	if _, err := p.GenerateCommitMessage(context.Background(), "diff"); err == nil || !strings.Contains(err.Error(), "invalid x-api-key") {
+		t.Errorf("GenerateCommitMessage() with a wrong key error = %v", err)
+	}
+
+	if _, err := NewAnthropicProvider(&config.Config{}); err == nil {
+		t.Error("NewAnthropicProvider() without an API key succeeded")
+	}
+}
+
+func TestReadAnthropicStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		stream    string
+		wantRetry bool
+		wantErr   bool
+	}{
+		{
+			name:      "overloaded mid-stream",
+			stream:    "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n",
+			wantRetry: true,
+			wantErr:   true,
+		},
+		{
+			name:    "stream error",
+			stream:  "data: {\"type\":\"error\",\"error\":{\"type\":\"api_error\",\"message\":\"boom\"}}\n",
+			wantErr: true,
+		},
+		{
+			name:    "cut stream",
+			stream:  "data: {\"type\":\"message_start\",\"message\":{\"usage\":{\"input_tokens\":3}}}\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readAnthropicStream(strings.NewReader(tt.stream))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readAnthropicStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var statusErr *StatusError
+			if errors.As(err, &statusErr) != tt.wantRetry {
+				t.Errorf("readAnthropicStream() error = %v, want retryable %v", err, tt.wantRetry)
+			}
+		})
+	}
+}
+
+// TestAnthropicStreamTimeout checks the provider timeout bounds the wait
+// for each event, not the whole streamed answer.
+func TestAnthropicStreamTimeout(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"usage":{"input_tokens":10}}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"slow "}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"but "}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"steady"}}`,
+		`{"type":"message_delta","usage":{"output_tokens":3}}`,
+		`{"type":"message_stop"}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gap := 40 * time.Millisecond
+		switch strings.TrimSuffix(r.URL.Path, AnthropicMessagesPath) {
+		case "/headers":
+			gap = 0
+			select {
+			case <-time.After(500 * time.Millisecond):
+			case <-r.Context().Done():
+				return
+			}
+		case "/stall":
+			gap = 500 * time.Millisecond
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", e)
+			w.(http.Flusher).Flush()
+			select {
+			case <-time.After(gap):
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	provider := func(mode string) *AnthropicProvider {
+		cfg := &config.Config{Provider: config.ProviderConfig{BaseURL: srv.URL + "/" + mode, APIKey: "sk-ant", Timeout: 100 * time.Millisecond}}
+		p, err := NewAnthropicProvider(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// Longer than the timeout in total, with events closer than it
+	msg, err := provider("steady").stream(context.Background(), "", "prompt", "")
+	if err != nil || msg.text != "slow but steady" {
+		t.Fatalf("stream() = %+v, %v, want the whole answer", msg, err)
+	}
+
+	_, err = provider("stall").stream(context.Background(), "", "prompt", "")
+	if err == nil || !strings.Contains(err.Error(), "idle timeout") || !errors.Is(err, context.DeadlineExceeded) || !IsRetryableError(err) {
+		t.Errorf("stream() on a stalled stream error = %v, want a retryable idle timeout", err)
+	}
+
+	if _, err = provider("headers").stream(context.Background(), "", "prompt", ""); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("stream() without headers error = %v, want a header timeout", err)
+	}
+}
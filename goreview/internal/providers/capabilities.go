@@ -0,0 +1,147 @@
+package providers
+
+import (
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/tokenizer"
+)
+
+// ModelCapabilities describes what a model can handle.
+type ModelCapabilities struct {
+	// ContextWindow is the total context window in tokens (prompt + response)
+	ContextWindow int `json:"context_window"`
+
+	// MaxOutputTokens is the maximum number of tokens the model can generate
+	MaxOutputTokens int `json:"max_output_tokens"`
+
+	// SupportsJSONMode reports whether the API offers a native JSON output mode
+	SupportsJSONMode bool `json:"supports_json_mode"`
+
+	// SupportsSystemPrompt reports whether the model accepts a system message
+	SupportsSystemPrompt bool `json:"supports_system_prompt"`
+}
+
+// Prompt overhead reserved when deriving chunk sizes (personality, modes, schema)
+const promptOverheadTokens = 1000
+
+// maxAutoChunkTokens caps auto-derived chunk sizes so huge context windows
+// don't produce unfocused reviews.
+const maxAutoChunkTokens = 16000
+
+// capabilityEntry maps a model name fragment to its capabilities.
+type capabilityEntry struct {
+	match string
+	caps  ModelCapabilities
+}
+
+// capabilityRegistry is checked in order; the first fragment contained in the
+// model name wins, so more specific fragments must come first.
+var capabilityRegistry = []capabilityEntry{
+	{"gpt-4o", ModelCapabilities{tokenizer.GPT4TurboMaxTokens, 16384, true, true}},
+	{"gpt-4-turbo", ModelCapabilities{tokenizer.GPT4TurboMaxTokens, 4096, true, true}},
+	{"gpt-4", ModelCapabilities{tokenizer.GPT4MaxTokens, 4096, false, true}},
+	{"gpt-3.5", ModelCapabilities{tokenizer.GPT35TurboMaxTokens, 4096, true, true}},
+	{"claude", ModelCapabilities{tokenizer.ClaudeMaxTokens, 8192, false, true}},
+	{"gemini-1.5-flash", ModelCapabilities{tokenizer.GeminiFlashMaxTokens, 8192, true, false}},
+	{"gemini-2", ModelCapabilities{tokenizer.GeminiFlashMaxTokens, 8192, true, false}},
+	{"gemini", ModelCapabilities{tokenizer.GeminiProMaxTokens, 8192, true, false}},
+	{"llama-3.3", ModelCapabilities{128000, 32768, true, true}},
+	{"llama", ModelCapabilities{tokenizer.LlamaMaxTokens, 4096, true, true}},
+	{"codestral", ModelCapabilities{256000, 8192, true, true}},
+	{"mistral", ModelCapabilities{tokenizer.MistralMaxTokens, 8192, true, true}},
+	{"qwen2.5-coder", ModelCapabilities{tokenizer.QwenMaxTokens, 8192, true, true}},
+	{"qwen", ModelCapabilities{tokenizer.QwenMaxTokens, 8192, true, true}},
+	{"deepseek", ModelCapabilities{64000, 8192, true, true}},
+}
+
+// defaultCapabilities is used for unknown models (conservative).
+var defaultCapabilities = ModelCapabilities{
+	ContextWindow:        tokenizer.DefaultMaxTokens,
+	MaxOutputTokens:      tokenizer.DefaultResponseReserve,
+	SupportsJSONMode:     false,
+	SupportsSystemPrompt: true,
+}
+
+// LookupCapabilities returns the registry capabilities for a model name.
+func LookupCapabilities(model string) ModelCapabilities {
+	name := strings.ToLower(model)
+	for _, entry := range capabilityRegistry {
+		if strings.Contains(name, entry.match) {
+			return entry.caps
+		}
+	}
+	return defaultCapabilities
+}
+
+// ResolveCapabilities returns the capabilities for the configured model with
+// any overrides from provider.capabilities applied.
+func ResolveCapabilities(cfg *config.ProviderConfig) ModelCapabilities {
+	caps := LookupCapabilities(cfg.Model)
+	override := cfg.Capabilities
+
+	if override.ContextWindow > 0 {
+		caps.ContextWindow = override.ContextWindow
+	}
+	if override.MaxOutputTokens > 0 {
+		caps.MaxOutputTokens = override.MaxOutputTokens
+	}
+	if override.JSONMode != nil {
+		caps.SupportsJSONMode = *override.JSONMode
+	}
+	if override.SystemPrompt != nil {
+		caps.SupportsSystemPrompt = *override.SystemPrompt
+	}
+	return caps
+}
+
+// ResponseTokens returns the response token limit to request, clamping the
+// configured value to what the model can generate.
+func (c ModelCapabilities) ResponseTokens(configured int) int {
+	if configured <= 0 || configured > c.MaxOutputTokens {
+		return c.MaxOutputTokens
+	}
+	return configured
+}
+
+// MaxChunkTokens returns how many diff tokens fit in a single request once the
+// response reserve and prompt overhead are subtracted from the context window.
+func (c ModelCapabilities) MaxChunkTokens(responseTokens int) int {
+	available := c.ContextWindow - responseTokens - promptOverheadTokens
+	if available > maxAutoChunkTokens {
+		available = maxAutoChunkTokens
+	}
+	if available < tokenizer.DefaultResponseReserve {
+		available = tokenizer.DefaultResponseReserve
+	}
+	return available
+}
+
+// requestOptions are the parts of a request that depend on the model
+// capabilities.
+type requestOptions struct {
+	// system is sent as the system prompt; it is empty when the model
+	// takes none and the system prompt leads the prompt instead
+	system    string
+	prompt    string
+	maxTokens int
+	jsonMode  bool
+}
+
+// requestFor adapts a request to the configured model: the response limit
+// is clamped to what it can generate, JSON mode is asked only when wanted
+// and supported, and a system prompt it can't take is prepended to the
+// prompt.
+func requestFor(cfg *config.ProviderConfig, system, prompt string, wantJSON bool) requestOptions {
+	caps := ResolveCapabilities(cfg)
+	opts := requestOptions{
+		system:    system,
+		prompt:    prompt,
+		maxTokens: caps.ResponseTokens(cfg.MaxTokens),
+		jsonMode:  wantJSON && caps.SupportsJSONMode,
+	}
+	if system != "" && !caps.SupportsSystemPrompt {
+		opts.system, opts.prompt = "", system+"\n\n"+prompt
+	}
+	return opts
+}
@@ -0,0 +1,131 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+func TestLookupCapabilities(t *testing.T) {
+	tests := []struct {
+		model       string
+		wantContext int
+		wantJSON    bool
+	}{
+		{"gpt-4o-mini", 128000, true},
+		{"gpt-4", 8192, false},
+		{"qwen2.5-coder:14b", 32768, true},
+		{"gemini-2.0-flash", 1048576, true},
+		{"llama-3.3-70b-versatile", 128000, true},
+		{"unknown-model", 8000, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			caps := LookupCapabilities(tt.model)
+			if caps.ContextWindow != tt.wantContext {
+				t.Errorf("ContextWindow = %d, want %d", caps.ContextWindow, tt.wantContext)
+			}
+			if caps.SupportsJSONMode != tt.wantJSON {
+				t.Errorf("SupportsJSONMode = %v, want %v", caps.SupportsJSONMode, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestResolveCapabilitiesOverrides(t *testing.T) {
+	jsonOff := false
+	cfg := &config.ProviderConfig{
+		Model: "gpt-4o",
+		Capabilities: config.CapabilitiesConfig{
+			ContextWindow: 16000,
+			JSONMode:      &jsonOff,
+		},
+	}
+
+	caps := ResolveCapabilities(cfg)
+	if caps.ContextWindow != 16000 {
+		t.Errorf("ContextWindow = %d, want 16000", caps.ContextWindow)
+	}
+	if caps.SupportsJSONMode {
+		t.Error("SupportsJSONMode = true, want override to false")
+	}
+	if caps.MaxOutputTokens != 16384 {
+		t.Errorf("MaxOutputTokens = %d, want registry value 16384", caps.MaxOutputTokens)
+	}
+}
+
+func TestModelCapabilitiesLimits(t *testing.T) {
+	caps := ModelCapabilities{ContextWindow: 8192, MaxOutputTokens: 2048}
+
+	if got := caps.ResponseTokens(4096); got != 2048 {
+		t.Errorf("ResponseTokens(4096) = %d, want 2048", got)
+	}
+	if got := caps.ResponseTokens(1024); got != 1024 {
+		t.Errorf("ResponseTokens(1024) = %d, want 1024", got)
+	}
+	if got := caps.MaxChunkTokens(2048); got != 8192-2048-promptOverheadTokens {
+		t.Errorf("MaxChunkTokens(2048) = %d, want %d", got, 8192-2048-promptOverheadTokens)
+	}
+
+	huge := ModelCapabilities{ContextWindow: 1000000, MaxOutputTokens: 8192}
+	if got := huge.MaxChunkTokens(8192); got != maxAutoChunkTokens {
+		t.Errorf("MaxChunkTokens() = %d, want cap %d", got, maxAutoChunkTokens)
+	}
+}
+
+func TestCapabilitiesShapeRequests(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"{\"issues\":[],\"summary\":\"ok\",\"score\":90}"}}],"response":"{\"issues\":[]}"}`))
+	}))
+	defer srv.Close()
+
+	off := false
+	cfg := &config.Config{Provider: config.ProviderConfig{
+		BaseURL:      srv.URL,
+		APIKey:       "key",
+		Model:        "llama-3.3-70b-versatile",
+		MaxTokens:    100000,
+		Capabilities: config.CapabilitiesConfig{JSONMode: &off, SystemPrompt: &off},
+	}}
+	groq, _ := NewGroqProvider(cfg)
+	ollama, _ := NewOllamaProvider(cfg)
+	for _, p := range []Provider{groq, ollama} {
+		if _, err := p.Review(context.Background(), &ReviewRequest{Diff: "+x := 1", FilePath: "a.go"}); err != nil {
+			t.Fatalf("%s Review() error = %v", p.Name(), err)
+		}
+		if _, ok := got["response_format"]; ok {
+			t.Errorf("%s sent JSON mode with json_mode off", p.Name())
+		}
+		if _, ok := got["format"]; ok {
+			t.Errorf("%s sent JSON format with json_mode off", p.Name())
+		}
+		if _, ok := got["system"]; ok {
+			t.Errorf("%s sent a system prompt with system_prompt off", p.Name())
+		}
+		if messages, _ := got["messages"].([]interface{}); len(messages) > 1 {
+			t.Errorf("%s sent %d messages, want the system prompt folded into the user message", p.Name(), len(messages))
+		}
+	}
+	if options, _ := got["options"].(map[string]interface{}); options["num_predict"] != float64(32768) {
+		t.Errorf("num_predict = %v, want the model's output limit", got["options"])
+	}
+
+	cfg.Provider.Capabilities = config.CapabilitiesConfig{}
+	if _, err := groq.Review(context.Background(), &ReviewRequest{Diff: "+x := 1", FilePath: "a.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if messages, _ := got["messages"].([]interface{}); len(messages) != 2 || got["response_format"] == nil {
+		t.Errorf("request = %v, want a system message and JSON mode", got)
+	}
+	if cfg.Provider.MaxTokens != 100000 {
+		t.Errorf("MaxTokens = %d, want the configuration untouched", cfg.Provider.MaxTokens)
+	}
+}
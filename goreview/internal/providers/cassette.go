@@ -0,0 +1,182 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotRecorded is returned in replay mode for a prompt without a
+// recording.
+var ErrNotRecorded = errors.New("no recorded response")
+
+// Cassette is a recorded provider answer, stored as <dir>/<kind>-<hash>.json.
+type Cassette struct {
+	Kind     string `json:"kind"`
+	Provider string `json:"provider"`
+	// Prompt is what the key was computed from, to tell why a replay missed
+	Prompt string          `json:"prompt"`
+	Review *ReviewResponse `json:"review,omitempty"`
+	// Exchanges are the review's raw exchanges, for transcripts
+	Exchanges []Exchange `json:"exchanges,omitempty"`
+	Text      string     `json:"text,omitempty"`
+}
+
+// CassetteProvider records the answers of a provider to a directory, or
+// replays them from it without any network access. Answers are keyed by a
+// hash of the prompt, so a replay only matches the exact same request.
+type CassetteProvider struct {
+	inner Provider // nil in replay mode
+	dir   string
+}
+
+// NewRecordingProvider wraps inner, saving each of its answers to dir.
+func NewRecordingProvider(inner Provider, dir string) (*CassetteProvider, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating cassette directory: %w", err)
+	}
+	return &CassetteProvider{inner: inner, dir: dir}, nil
+}
+
+// NewReplayProvider returns a provider answering from the recordings in
+// dir.
+func NewReplayProvider(dir string) *CassetteProvider {
+	return &CassetteProvider{dir: dir}
+}
+
+func (c *CassetteProvider) Name() string {
+	if c.inner == nil {
+		return "replay"
+	}
+	return fmt.Sprintf("record(%s)", c.inner.Name())
+}
+
+func (c *CassetteProvider) Review(ctx context.Context, req *ReviewRequest) (*ReviewResponse, error) {
+	prompt := ReviewSystemPrompt + "\n" + BuildReviewPrompt(req)
+	if c.inner == nil {
+		cas, err := c.load("review", prompt)
+		if err != nil {
+			return nil, err
+		}
+		resp := cas.Review
+		if resp == nil {
+			resp = &ReviewResponse{}
+		}
+		resp.Exchanges = cas.Exchanges
+		return resp, nil
+	}
+
+	resp, err := c.inner.Review(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, c.save(&Cassette{Kind: "review", Prompt: prompt, Review: resp, Exchanges: resp.Exchanges})
+}
+
+func (c *CassetteProvider) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
+	return c.text("commit", diff, func() (string, error) {
+		return c.inner.GenerateCommitMessage(ctx, diff)
+	})
+}
+
+func (c *CassetteProvider) GenerateDocumentation(ctx context.Context, diff, docContext string) (string, error) {
+	return c.text("doc", docContext+"\n"+diff, func() (string, error) {
+		return c.inner.GenerateDocumentation(ctx, diff, docContext)
+	})
+}
+
+// GenerateJSON records structured answers, using the native JSON mode of
+// the wrapped provider when it has one.
+func (c *CassetteProvider) GenerateJSON(ctx context.Context, prompt string, schema JSONSchema) (string, error) {
+	schemaJSON, _ := json.Marshal(schema)
+	return c.text("json", prompt+"\n"+string(schemaJSON), func() (string, error) {
+		return GenerateJSON(ctx, c.inner, prompt, schema)
+	})
+}
+
+// HealthCheck checks the wrapped provider, or that the recordings exist.
+func (c *CassetteProvider) HealthCheck(ctx context.Context) error {
+	if c.inner != nil {
+		return c.inner.HealthCheck(ctx)
+	}
+	if _, err := os.Stat(c.dir); err != nil {
+		return fmt.Errorf("cassette directory: %w", err)
+	}
+	return nil
+}
+
+func (c *CassetteProvider) Close() error {
+	if c.inner != nil {
+		return c.inner.Close()
+	}
+	return nil
+}
+
+// text replays or records a text answer.
+func (c *CassetteProvider) text(kind, prompt string, generate func() (string, error)) (string, error) {
+	if c.inner == nil {
+		cas, err := c.load(kind, prompt)
+		if err != nil {
+			return "", err
+		}
+		return cas.Text, nil
+	}
+	text, err := generate()
+	if err != nil {
+		return "", err
+	}
+	return text, c.save(&Cassette{Kind: kind, Prompt: prompt, Text: text})
+}
+
+func (c *CassetteProvider) load(kind, prompt string) (*Cassette, error) {
+	path := c.path(kind, prompt)
+	data, err := os.ReadFile(path) //nolint:gosec // Path built from the cassette directory and a hash
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for this %s prompt (%s)", ErrNotRecorded, kind, filepath.Base(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cas Cassette
+	if err := json.Unmarshal(data, &cas); err != nil {
+		return nil, fmt.Errorf("reading cassette %s: %w", path, err)
+	}
+	return &cas, nil
+}
+
+// save writes a recording through a temporary file, so concurrent reviews
+// never leave a partial one.
+func (c *CassetteProvider) save(cas *Cassette) error {
+	cas.Provider = c.inner.Name()
+	data, err := json.MarshalIndent(cas, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := c.path(cas.Kind, cas.Prompt)
+	tmp, err := os.CreateTemp(c.dir, ".cassette-*")
+	if err != nil {
+		return fmt.Errorf("recording response: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("recording response: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("recording response: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// path is the file of a recording: its kind and the first 16 hex digits of
+// the prompt's SHA-256.
+func (c *CassetteProvider) path(kind, prompt string) string {
+	sum := sha256.Sum256([]byte(kind + "\n" + prompt))
+	return filepath.Join(c.dir, kind+"-"+hex.EncodeToString(sum[:])[:16]+".json")
+}
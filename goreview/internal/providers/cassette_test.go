@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+// stubProvider answers every request and counts the calls.
+type stubProvider struct {
+	calls int
+}
+
+func (s *stubProvider) Name() string { return "stub" }
+
+func (s *stubProvider) Review(ctx context.Context, req *ReviewRequest) (*ReviewResponse, error) {
+	s.calls++
+	return &ReviewResponse{
+		Issues:    []Issue{{ID: "1", Message: "unchecked error in " + req.FilePath, Severity: SeverityError}},
+		Score:     80,
+		Exchanges: []Exchange{{Prompt: "p", Response: "r"}},
+	}, nil
+}
+
+func (s *stubProvider) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
+	s.calls++
+	return "fix: " + diff, nil
+}
+
+func (s *stubProvider) GenerateDocumentation(ctx context.Context, diff, docContext string) (string, error) {
+	s.calls++
+	return "docs", nil
+}
+
+func (s *stubProvider) HealthCheck(ctx context.Context) error { return errors.New("offline") }
+func (s *stubProvider) Close() error                          { return nil }
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	stub := &stubProvider{}
+	recorder, err := NewRecordingProvider(stub, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &ReviewRequest{Diff: "+x := 1", Language: "go", FilePath: "a.go"}
+	if _, err := recorder.Review(ctx, req); err != nil {
+		t.Fatalf("recording Review() error = %v", err)
+	}
+	if _, err := recorder.GenerateCommitMessage(ctx, "diff"); err != nil {
+		t.Fatalf("recording GenerateCommitMessage() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Fatalf("recorded %d files, want 2", len(entries))
+	}
+
+	replay := NewReplayProvider(dir)
+	if err := replay.HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck() error = %v", err)
+	}
+	resp, err := replay.Review(ctx, req)
+	if err != nil {
+		t.Fatalf("replayed Review() error = %v", err)
+	}
+	if resp.Score != 80 || len(resp.Issues) != 1 || resp.Issues[0].Message != "unchecked error in a.go" {
+		t.Errorf("replayed response = %+v", resp)
+	}
+	if len(resp.Exchanges) != 1 || resp.Exchanges[0].Response != "r" {
+		t.Errorf("replayed exchanges = %+v", resp.Exchanges)
+	}
+	if msg, err := replay.GenerateCommitMessage(ctx, "diff"); err != nil || msg != "fix: diff" {
+		t.Errorf("replayed commit message = %q, %v", msg, err)
+	}
+	if stub.calls != 2 {
+		t.Errorf("provider called %d times, want 2", stub.calls)
+	}
+
+	other := &ReviewRequest{Diff: "+y := 2", Language: "go", FilePath: "a.go"}
+	if _, err := replay.Review(ctx, other); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("Review() of an unrecorded prompt error = %v, want ErrNotRecorded", err)
+	}
+}
+
+func TestCassetteReplayMissingDir(t *testing.T) {
+	if err := NewReplayProvider(t.TempDir() + "/missing").HealthCheck(context.Background()); err == nil {
+		t.Error("HealthCheck() of a missing directory succeeded")
+	}
+}
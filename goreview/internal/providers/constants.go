@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 )
 
 // Common error format strings (SonarQube S1192)
@@ -91,12 +93,33 @@ func DoJSONPost(ctx context.Context, client *http.Client, url string, reqBody in
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError ||
+		resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+	}
+
 	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
 		return fmt.Errorf(ErrDecodeResponse, err)
 	}
 	return nil
 }
 
+// StatusError is returned when a provider answers with a throttling,
+// authentication or server error status. The message keeps the numeric
+// code so retry patterns match it.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("HTTP %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("HTTP %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
 // OllamaResponse represents Ollama API response structure
 type OllamaResponse struct {
 	Response string `json:"response"`
@@ -110,7 +133,14 @@ type GeminiResponse struct {
 				Text string `json:"text"`
 			} `json:"parts"`
 		} `json:"content"`
+		FinishReason  string               `json:"finishReason"`
+		SafetyRatings []GeminiSafetyRating `json:"safetyRatings"`
 	} `json:"candidates"`
+	// PromptFeedback is set when the prompt itself was blocked
+	PromptFeedback struct {
+		BlockReason   string               `json:"blockReason"`
+		SafetyRatings []GeminiSafetyRating `json:"safetyRatings"`
+	} `json:"promptFeedback"`
 	UsageMetadata struct {
 		TotalTokenCount int `json:"totalTokenCount"`
 	} `json:"usageMetadata"`
@@ -128,6 +158,41 @@ func (r *GeminiResponse) GetText() string {
 	return ""
 }
 
+// GeminiSafetyRating is the rating of a prompt or answer for one harm category
+type GeminiSafetyRating struct {
+	Category    string `json:"category"`
+	Probability string `json:"probability"`
+	Blocked     bool   `json:"blocked"`
+}
+
+// geminiBlockReasons are the finish reasons of an answer the safety
+// filters stopped
+var geminiBlockReasons = map[string]bool{
+	"SAFETY":             true,
+	"BLOCKLIST":          true,
+	"PROHIBITED_CONTENT": true,
+	"SPII":               true,
+}
+
+// Blocked returns a SafetyBlockError when the safety filters blocked the
+// prompt or the answer, or nil.
+func (r *GeminiResponse) Blocked() error {
+	reason, ratings := r.PromptFeedback.BlockReason, r.PromptFeedback.SafetyRatings
+	if reason == "" && len(r.Candidates) > 0 && geminiBlockReasons[r.Candidates[0].FinishReason] {
+		reason, ratings = r.Candidates[0].FinishReason, r.Candidates[0].SafetyRatings
+	}
+	if reason == "" {
+		return nil
+	}
+	blocked := &SafetyBlockError{Provider: "gemini", Reason: reason}
+	for _, rating := range ratings {
+		if rating.Blocked || rating.Probability == "HIGH" {
+			blocked.Categories = append(blocked.Categories, rating.Category)
+		}
+	}
+	return blocked
+}
+
 // ReviewSystemPrompt is the standard system prompt for code review
 const ReviewSystemPrompt = "You are an expert code reviewer. Return valid JSON only."
 
@@ -143,21 +208,32 @@ func ValidateReviewInput(req *ReviewRequest) (empty bool, err error) {
 func ParseReviewContent(content string, tokensUsed int, processingTime int64) *ReviewResponse {
 	var reviewResp ReviewResponse
 	if err := json.Unmarshal([]byte(content), &reviewResp); err != nil {
-		reviewResp = ReviewResponse{Summary: content}
+		reviewResp = ReviewResponse{Summary: content, ParseFailures: 1}
 	}
+	ClassifyIssues(reviewResp.Issues)
 	reviewResp.TokensUsed = tokensUsed
 	reviewResp.ProcessingTime = processingTime
+	reviewResp.Exchanges = []Exchange{{Response: content}}
 	return &reviewResp
 }
 
-// BuildChatRequest builds a standard chat completion request for OpenAI-compatible APIs
+// withPrompt records the prompt a parsed response answers.
+func (r *ReviewResponse) withPrompt(system, prompt string) *ReviewResponse {
+	r.Exchanges[0].System = system
+	r.Exchanges[0].Prompt = prompt
+	return r
+}
+
+// BuildChatRequest builds a standard chat completion request for OpenAI-compatible APIs.
+// An empty systemPrompt sends the user message alone.
 func BuildChatRequest(model string, systemPrompt string, userContent string, temp float64, maxTokens int, jsonMode bool) map[string]interface{} {
+	messages := []map[string]string{{"role": "user", "content": userContent}}
+	if systemPrompt != "" {
+		messages = append([]map[string]string{{"role": "system", "content": systemPrompt}}, messages...)
+	}
 	req := map[string]interface{}{
-		"model": model,
-		"messages": []map[string]string{
-			{"role": "system", "content": systemPrompt},
-			{"role": "user", "content": userContent},
-		},
+		"model":       model,
+		"messages":    messages,
 		"temperature": temp,
 		"max_tokens":  maxTokens,
 	}
@@ -171,7 +247,7 @@ func BuildChatRequest(model string, systemPrompt string, userContent string, tem
 func BuildGeminiRequest(text string, temp float64, maxTokens int, jsonMode bool) map[string]interface{} {
 	req := map[string]interface{}{
 		"contents": []map[string]interface{}{
-			{"parts": []map[string]string{{"text": text}}},
+			{"role": "user", "parts": []map[string]string{{"text": text}}},
 		},
 		"generationConfig": map[string]interface{}{
 			"temperature":     temp,
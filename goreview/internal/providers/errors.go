@@ -0,0 +1,71 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/failure"
+)
+
+// ClassifyError wraps the error of a provider call in a
+// failure.ProviderError coded by its cause: the answer's status, a timeout
+// or an unreachable host. Errors already coded keep their code.
+func ClassifyError(provider string, err error) error {
+	if err == nil || failure.CodeOf(err) != failure.Unclassified {
+		return err
+	}
+	perr := &failure.ProviderError{Code: failure.ProviderFailed, Provider: provider, Err: err}
+	var status *StatusError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &status):
+		perr.StatusCode = status.StatusCode
+		perr.Code = statusCode(status.StatusCode)
+	case errors.Is(err, context.DeadlineExceeded):
+		perr.Code = failure.ProviderTimeout
+	case errors.As(err, &netErr):
+		perr.Code = failure.ProviderUnavailable
+		if netErr.Timeout() {
+			perr.Code = failure.ProviderTimeout
+		}
+	}
+	return perr
+}
+
+// statusCode codes a provider's error status.
+func statusCode(status int) failure.Code {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return failure.ProviderAuth
+	case status == http.StatusTooManyRequests:
+		return failure.ProviderRateLimited
+	case status >= http.StatusInternalServerError:
+		return failure.ProviderUnavailable
+	}
+	return failure.ProviderRejected
+}
+
+// SafetyBlockError is a request the provider's safety filters refused to
+// answer. Sending it again gets the same answer, so it isn't retried; the
+// review engine skips what was blocked and goes on.
+type SafetyBlockError struct {
+	Provider string
+	// Reason is the provider's block reason, like "SAFETY"
+	Reason string
+	// Categories are the harm categories that tripped the filters
+	Categories []string
+}
+
+func (e *SafetyBlockError) Error() string {
+	msg := fmt.Sprintf("%s blocked the request: %s", e.Provider, e.Reason)
+	if len(e.Categories) > 0 {
+		msg += " (" + strings.Join(e.Categories, ", ") + ")"
+	}
+	return msg
+}
+
+func (e *SafetyBlockError) ErrorCode() failure.Code { return failure.ProviderBlocked }
@@ -0,0 +1,43 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/failure"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want failure.Code
+	}{
+		{"unauthorized", &StatusError{StatusCode: 401}, failure.ProviderAuth},
+		{"throttled after retries", fmt.Errorf("max retries (3) exceeded: %w", &StatusError{StatusCode: 429}), failure.ProviderRateLimited},
+		{"overloaded", &StatusError{StatusCode: 529}, failure.ProviderUnavailable},
+		{"bad request", &StatusError{StatusCode: 400}, failure.ProviderRejected},
+		{"deadline", fmt.Errorf("retry cancelled: %w", context.DeadlineExceeded), failure.ProviderTimeout},
+		{"unreachable", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, failure.ProviderUnavailable},
+		{"other", errors.New("no response from Gemini"), failure.ProviderFailed},
+		{"already coded", &failure.ParseError{Code: failure.ParseResponse, Err: errors.New("bad json")}, failure.ParseResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ClassifyError("openai", tt.err)
+			if got := failure.CodeOf(err); got != tt.want {
+				t.Errorf("ClassifyError() code = %q, want %q", got, tt.want)
+			}
+			if err.Error() != tt.err.Error() {
+				t.Errorf("ClassifyError() message = %q, want %q", err, tt.err)
+			}
+		})
+	}
+	if ClassifyError("openai", nil) != nil {
+		t.Error("ClassifyError(nil) != nil")
+	}
+}
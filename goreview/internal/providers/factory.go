@@ -8,10 +8,39 @@ import (
 	"time"
 
 	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/failure"
 )
 
-// NewProvider creates a new Provider based on configuration.
+// NewProvider creates a new Provider based on configuration. With
+// provider.record its answers are saved for later replays; with
+// provider.replay they are served from the recordings instead. The
+// provider.middleware chain runs around either. Errors are
+// failure.ProviderErrors coded ProviderNotConfigured.
 func NewProvider(cfg *config.Config) (Provider, error) {
+	p, err := newRecordedProvider(cfg)
+	if err != nil {
+		return nil, &failure.ProviderError{Code: failure.ProviderNotConfigured, Provider: cfg.Provider.Name, Err: err}
+	}
+	// Middleware wrap recordings too, so replays see the same prompts
+	return withMiddleware(p, cfg.Provider.Middleware)
+}
+
+// newRecordedProvider creates the configured provider, recording its
+// answers or replaying them when asked to.
+func newRecordedProvider(cfg *config.Config) (Provider, error) {
+	// Replays need neither the provider nor the network
+	if cfg.Provider.Replay != "" {
+		return NewReplayProvider(cfg.Provider.Replay), nil
+	}
+	p, err := newProvider(cfg)
+	if err != nil || cfg.Provider.Record == "" {
+		return p, err
+	}
+	return NewRecordingProvider(p, cfg.Provider.Record)
+}
+
+// newProvider creates the provider named in the configuration.
+func newProvider(cfg *config.Config) (Provider, error) {
 	switch cfg.Provider.Name {
 	case "ollama":
 		return NewOllamaProvider(cfg)
@@ -23,6 +52,8 @@ func NewProvider(cfg *config.Config) (Provider, error) {
 		return NewGroqProvider(cfg)
 	case "mistral":
 		return NewMistralProvider(cfg)
+	case "anthropic":
+		return NewAnthropicProvider(cfg)
 	case "fallback":
 		return NewFallbackFromEnv()
 	case "auto", "":
@@ -79,7 +110,7 @@ func isOllamaRunning() bool {
 
 // hasCloudAPIKeys checks if any cloud provider API keys are set.
 func hasCloudAPIKeys() bool {
-	keys := []string{"GEMINI_API_KEY", "GROQ_API_KEY", "MISTRAL_API_KEY", "OPENAI_API_KEY"}
+	keys := []string{"GEMINI_API_KEY", "GROQ_API_KEY", "MISTRAL_API_KEY", "ANTHROPIC_API_KEY", "OPENAI_API_KEY"}
 	for _, key := range keys {
 		if os.Getenv(key) != "" {
 			return true
@@ -89,8 +120,21 @@ func hasCloudAPIKeys() bool {
 }
 
 // NewFallbackFromEnv creates a fallback provider chain from environment variables.
-// Priority: Gemini (quality) -> Groq (speed) -> Mistral (code) -> OpenAI (paid)
+// Priority: Gemini (quality) -> Groq (speed) -> Mistral (code) -> Anthropic (paid) -> OpenAI (paid)
 func NewFallbackFromEnv() (Provider, error) {
+	providers := envProviders()
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no API keys found. Set one of: GEMINI_API_KEY, GROQ_API_KEY, MISTRAL_API_KEY, ANTHROPIC_API_KEY, OPENAI_API_KEY")
+	}
+	for _, p := range providers {
+		log.Printf("[fallback] Added %s provider", p.Name())
+	}
+	return NewFallbackProvider(providers...)
+}
+
+// envProviders creates the providers whose API key is set in the
+// environment, in the fallback chain's order.
+func envProviders() []Provider {
 	var providers []Provider
 
 	// Try Gemini first (best quality, free)
@@ -105,7 +149,6 @@ func NewFallbackFromEnv() (Provider, error) {
 		}
 		if p, err := NewGeminiProvider(cfg); err == nil {
 			providers = append(providers, p)
-			log.Println("[fallback] Added Gemini provider")
 		}
 	}
 
@@ -121,7 +164,6 @@ func NewFallbackFromEnv() (Provider, error) {
 		}
 		if p, err := NewGroqProvider(cfg); err == nil {
 			providers = append(providers, p)
-			log.Println("[fallback] Added Groq provider")
 		}
 	}
 
@@ -137,7 +179,21 @@ func NewFallbackFromEnv() (Provider, error) {
 		}
 		if p, err := NewMistralProvider(cfg); err == nil {
 			providers = append(providers, p)
-			log.Println("[fallback] Added Mistral provider")
+		}
+	}
+
+	// Try Anthropic fourth (paid, strong at code)
+	if key := os.Getenv("ANTHROPIC_API_KEY"); key != "" {
+		cfg := &config.Config{
+			Provider: config.ProviderConfig{
+				APIKey:      key,
+				Temperature: 0.1,
+				MaxTokens:   4096,
+				Timeout:     120 * time.Second,
+			},
+		}
+		if p, err := NewAnthropicProvider(cfg); err == nil {
+			providers = append(providers, p)
 		}
 	}
 
@@ -154,18 +210,13 @@ func NewFallbackFromEnv() (Provider, error) {
 		}
 		if p, err := NewOpenAIProvider(cfg); err == nil {
 			providers = append(providers, p)
-			log.Println("[fallback] Added OpenAI provider")
 		}
 	}
 
-	if len(providers) == 0 {
-		return nil, fmt.Errorf("no API keys found. Set one of: GEMINI_API_KEY, GROQ_API_KEY, MISTRAL_API_KEY, OPENAI_API_KEY")
-	}
-
-	return NewFallbackProvider(providers...)
+	return providers
 }
 
 // AvailableProviders returns a list of available provider names.
 func AvailableProviders() []string {
-	return []string{"ollama", "openai", "gemini", "groq", "mistral", "fallback", "auto"}
+	return []string{"ollama", "openai", "gemini", "groq", "mistral", "anthropic", "fallback", "auto"}
 }
@@ -4,29 +4,31 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/JNZader/goreview/goreview/internal/config"
 )
 
-// GeminiProvider implements Provider using Google Gemini API.
+// GeminiProvider implements Provider using Google Gemini API, with an API
+// key or on Vertex AI with a service account.
 type GeminiProvider struct {
-	apiKey  string
-	baseURL string
-	model   string
-	client  *http.Client
-	config  *config.ProviderConfig
+	apiKey      string
+	baseURL     string
+	model       string
+	client      *http.Client
+	config      *config.ProviderConfig
+	rateLimiter *RateLimiter
+	// tokens authenticates on Vertex AI; nil with an API key
+	tokens *vertexTokenSource
 }
 
 // NewGeminiProvider creates a new Gemini provider.
 func NewGeminiProvider(cfg *config.Config) (*GeminiProvider, error) {
-	if cfg.Provider.APIKey == "" {
-		return nil, fmt.Errorf("Gemini API key required (get free at aistudio.google.com)")
-	}
-
-	baseURL := cfg.Provider.BaseURL
-	if baseURL == "" {
-		baseURL = "https://generativelanguage.googleapis.com/v1beta"
+	var limiter *RateLimiter
+	if cfg.Provider.RateLimitRPS > 0 {
+		limiter = NewRateLimiter(cfg.Provider.RateLimitRPS)
 	}
 
 	model := cfg.Provider.Model
@@ -34,13 +36,66 @@ func NewGeminiProvider(cfg *config.Config) (*GeminiProvider, error) {
 		model = "gemini-2.0-flash"
 	}
 
-	return &GeminiProvider{
-		apiKey:  cfg.Provider.APIKey,
-		baseURL: baseURL,
-		model:   model,
-		config:  &cfg.Provider,
-		client:  &http.Client{Timeout: cfg.Provider.Timeout},
-	}, nil
+	p := &GeminiProvider{
+		apiKey:      cfg.Provider.APIKey,
+		baseURL:     cfg.Provider.BaseURL,
+		model:       model,
+		config:      &cfg.Provider,
+		client:      &http.Client{Timeout: cfg.Provider.Timeout},
+		rateLimiter: limiter,
+	}
+
+	if cfg.Provider.Vertex.Enabled() {
+		if err := p.useVertex(cfg.Provider.Vertex); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("Gemini API key required (get free at aistudio.google.com)")
+	}
+	if p.baseURL == "" {
+		p.baseURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	return p, nil
+}
+
+// useVertex authenticates with a service account and points the provider
+// at the Vertex AI publisher models of the project.
+func (p *GeminiProvider) useVertex(vertex config.VertexConfig) error {
+	credentials := vertex.Credentials
+	if credentials == "" {
+		credentials = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if credentials == "" {
+		return fmt.Errorf("Vertex AI needs a service account: set provider.vertex.credentials or GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	tokens, err := newVertexTokenSource(credentials, p.client)
+	if err != nil {
+		return err
+	}
+
+	project := vertex.Project
+	if project == "" {
+		project = tokens.account.ProjectID
+	}
+	if project == "" {
+		return fmt.Errorf("Vertex AI project required: set provider.vertex.project")
+	}
+	location := vertex.Location
+	if location == "" {
+		location = "us-central1"
+	}
+	host := p.baseURL
+	if host == "" {
+		host = fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1", location)
+	}
+
+	p.tokens = tokens
+	p.apiKey = ""
+	p.baseURL = fmt.Sprintf("%s/projects/%s/locations/%s/publishers/google", strings.TrimSuffix(host, "/"), project, location)
+	return nil
 }
 
 func (p *GeminiProvider) Name() string { return "gemini" }
@@ -53,62 +108,117 @@ func (p *GeminiProvider) Review(ctx context.Context, req *ReviewRequest) (*Revie
 	}
 
 	start := time.Now()
-	geminiReq := BuildGeminiRequest(buildReviewPrompt(req), p.config.Temperature, p.config.MaxTokens, true)
+	opts := requestFor(p.config, ReviewSystemPrompt, BuildReviewPrompt(req), true)
+	geminiReq := p.buildRequest(opts)
 
-	url := fmt.Sprintf(GeminiGenerateURL, p.baseURL, p.model, p.apiKey)
-	var result GeminiResponse
-	if err := DoJSONPost(ctx, p.client, url, geminiReq, "", &result); err != nil {
-		return nil, fmt.Errorf("gemini request failed: %w", err)
-	}
-
-	if result.Error != nil {
-		return nil, fmt.Errorf("gemini error %d: %s", result.Error.Code, result.Error.Message)
+	result, err := p.generate(ctx, geminiReq)
+	if err != nil {
+		return nil, err
 	}
 
-	return ParseReviewContent(result.GetText(), result.UsageMetadata.TotalTokenCount, time.Since(start).Milliseconds()), nil
+	return ParseReviewContent(result.GetText(), result.UsageMetadata.TotalTokenCount, time.Since(start).Milliseconds()).withPrompt(opts.system, opts.prompt), nil
 }
 
 func (p *GeminiProvider) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
-	geminiReq := map[string]interface{}{
+	return p.generateText(ctx, p.buildRequest(requestFor(p.config, "", fmt.Sprintf(CommitMessagePrompt, diff), false)))
+}
+
+func (p *GeminiProvider) GenerateDocumentation(ctx context.Context, diff, docContext string) (string, error) {
+	return p.generateText(ctx, p.buildRequest(requestFor(p.config, "", fmt.Sprintf(DocumentationPrompt, docContext, diff), false)))
+}
+
+// GenerateJSON uses the application/json response MIME type.
+func (p *GeminiProvider) GenerateJSON(ctx context.Context, prompt string, _ JSONSchema) (string, error) {
+	return p.generateText(ctx, p.buildRequest(requestFor(p.config, StructuredSystemPrompt, prompt, true)))
+}
+
+// buildRequest builds a generateContent request, with the system prompt as
+// the system instruction when the model takes one.
+func (p *GeminiProvider) buildRequest(opts requestOptions) map[string]interface{} {
+	req := BuildGeminiRequest(opts.prompt, p.config.Temperature, opts.maxTokens, opts.jsonMode)
+	if opts.system != "" {
+		req["systemInstruction"] = map[string]interface{}{"parts": []map[string]string{{"text": opts.system}}}
+	}
+	return req
+}
+
+// HealthCheck gets the model with an API key. Vertex AI has no model
+// lookup for publisher models, so it counts the tokens of a short prompt,
+// which checks the credentials, project and model without generating.
+func (p *GeminiProvider) HealthCheck(ctx context.Context) error {
+	if p.tokens == nil {
+		url := fmt.Sprintf("%s/models/%s?key=%s", p.baseURL, p.model, p.apiKey)
+		return DoHealthCheck(ctx, p.client, url, "", "gemini")
+	}
+
+	token, err := p.tokens.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("gemini health check failed: %w", err)
+	}
+	countReq := map[string]interface{}{
 		"contents": []map[string]interface{}{
-			{"parts": []map[string]string{{"text": fmt.Sprintf(CommitMessagePrompt, diff)}}},
+			{"role": "user", "parts": []map[string]string{{"text": "ping"}}},
 		},
 	}
-
-	url := fmt.Sprintf(GeminiGenerateURL, p.baseURL, p.model, p.apiKey)
-	var result GeminiResponse
-	if err := DoJSONPost(ctx, p.client, url, geminiReq, "", &result); err != nil {
-		return "", err
+	var result struct {
+		TotalTokens int `json:"totalTokens"`
+	}
+	if err := DoJSONPost(ctx, p.client, p.methodURL("countTokens"), countReq, token, &result); err != nil {
+		return fmt.Errorf("gemini health check failed: %w", err)
 	}
+	if result.TotalTokens == 0 {
+		return fmt.Errorf("gemini health check failed: model %s did not answer", p.model)
+	}
+	return nil
+}
 
-	if text := result.GetText(); text != "" {
-		return text, nil
+func (p *GeminiProvider) Close() error { return nil }
+
+// methodURL returns the URL of a model method, like "generateContent".
+func (p *GeminiProvider) methodURL(method string) string {
+	if p.tokens == nil && method == "generateContent" {
+		return fmt.Sprintf(GeminiGenerateURL, p.baseURL, p.model, p.apiKey)
 	}
-	return "", fmt.Errorf("no response from Gemini")
+	return fmt.Sprintf("%s/models/%s:%s", p.baseURL, p.model, method)
 }
 
-func (p *GeminiProvider) GenerateDocumentation(ctx context.Context, diff, docContext string) (string, error) {
-	geminiReq := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{"parts": []map[string]string{{"text": fmt.Sprintf(DocumentationPrompt, docContext, diff)}}},
-		},
+// generate generates content once the rate limit allows it. A blocked
+// prompt or answer is a SafetyBlockError.
+func (p *GeminiProvider) generate(ctx context.Context, body interface{}) (*GeminiResponse, error) {
+	if p.rateLimiter != nil {
+		if err := p.rateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var token string
+	if p.tokens != nil {
+		var err error
+		if token, err = p.tokens.Token(ctx); err != nil {
+			return nil, fmt.Errorf("gemini request failed: %w", err)
+		}
 	}
 
-	url := fmt.Sprintf(GeminiGenerateURL, p.baseURL, p.model, p.apiKey)
 	var result GeminiResponse
-	if err := DoJSONPost(ctx, p.client, url, geminiReq, "", &result); err != nil {
-		return "", err
+	if err := DoJSONPost(ctx, p.client, p.methodURL("generateContent"), body, token, &result); err != nil {
+		return nil, fmt.Errorf("gemini request failed: %w", err)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("gemini error %d: %s", result.Error.Code, result.Error.Message)
+	}
+	if err := result.Blocked(); err != nil {
+		return nil, err
 	}
+	return &result, nil
+}
 
+func (p *GeminiProvider) generateText(ctx context.Context, body interface{}) (string, error) {
+	result, err := p.generate(ctx, body)
+	if err != nil {
+		return "", err
+	}
 	if text := result.GetText(); text != "" {
 		return text, nil
 	}
 	return "", fmt.Errorf("no response from Gemini")
 }
-
-func (p *GeminiProvider) HealthCheck(ctx context.Context) error {
-	url := fmt.Sprintf("%s/models/%s?key=%s", p.baseURL, p.model, p.apiKey)
-	return DoHealthCheck(ctx, p.client, url, "", "gemini")
-}
-
-func (p *GeminiProvider) Close() error { return nil }
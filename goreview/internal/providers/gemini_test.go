@@ -0,0 +1,145 @@
+package providers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/failure"
+)
+
+// writeServiceAccount writes a service account key file whose tokens are
+// issued by tokenURI.
+func writeServiceAccount(t *testing.T, tokenURI string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(serviceAccount{
+		Type:        "service_account",
+		ProjectID:   "acme",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		ClientEmail: "reviewer@acme.iam.gserviceaccount.com",
+		TokenURI:    tokenURI,
+	})
+	path := filepath.Join(t.TempDir(), "sa.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGeminiVertex(t *testing.T) {
+	tokenRequests := 0
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			if r.FormValue("grant_type") != jwtBearerGrant || strings.Count(r.FormValue("assertion"), ".") != 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"ya29.token","expires_in":3600,"token_type":"Bearer"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer ya29.token" || r.URL.Query().Get("key") != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/projects/acme/locations/europe-west4/publishers/google/models/gemini-test:generateContent":
+			got = nil
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"{\"issues\":[],\"summary\":\"ok\",\"score\":90}"}]},"finishReason":"STOP"}],"usageMetadata":{"totalTokenCount":42}}`))
+		case "/v1/projects/acme/locations/europe-west4/publishers/google/models/gemini-test:countTokens":
+			_, _ = w.Write([]byte(`{"totalTokens":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Provider: config.ProviderConfig{
+		BaseURL:     srv.URL + "/v1",
+		Model:       "gemini-test",
+		Temperature: 0.3,
+		Vertex:      config.VertexConfig{Location: "europe-west4", Credentials: writeServiceAccount(t, srv.URL+"/token")},
+	}}
+	p, err := NewGeminiProvider(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := p.Review(context.Background(), &ReviewRequest{Diff: "+x := 1", FilePath: "a.go"})
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if resp.Score != 90 || resp.TokensUsed != 42 {
+		t.Errorf("Review() = %+v", resp)
+	}
+	if _, err := p.GenerateCommitMessage(context.Background(), "diff"); err != nil {
+		t.Fatalf("GenerateCommitMessage() error = %v", err)
+	}
+	if gen, _ := got["generationConfig"].(map[string]interface{}); gen["temperature"] != 0.3 {
+		t.Errorf("GenerateCommitMessage() generationConfig = %v, want the configured temperature", got["generationConfig"])
+	}
+	if tokenRequests != 1 {
+		t.Errorf("token requests = %d, want the token reused", tokenRequests)
+	}
+	if err := p.HealthCheck(context.Background()); err != nil {
+		t.Errorf("HealthCheck() error = %v", err)
+	}
+	if st := CheckStatus(context.Background(), p); !st.Healthy() {
+		t.Errorf("CheckStatus() = %+v, want healthy", st)
+	}
+
+	cfg.Provider.Vertex = config.VertexConfig{Project: "acme", Credentials: filepath.Join(t.TempDir(), "missing.json")}
+	if _, err := NewGeminiProvider(cfg); err == nil {
+		t.Error("NewGeminiProvider() with missing credentials succeeded")
+	}
+}
+
+func TestGeminiSafetyBlock(t *testing.T) {
+	answers := []string{
+		`{"promptFeedback":{"blockReason":"SAFETY","safetyRatings":[{"category":"HARM_CATEGORY_DANGEROUS_CONTENT","probability":"HIGH"},{"category":"HARM_CATEGORY_HARASSMENT","probability":"NEGLIGIBLE"}]}}`,
+		`{"candidates":[{"finishReason":"SAFETY","safetyRatings":[{"category":"HARM_CATEGORY_HATE_SPEECH","probability":"MEDIUM","blocked":true}]}]}`,
+	}
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(answers[calls%len(answers)]))
+		calls++
+	}))
+	defer srv.Close()
+
+	p, err := NewGeminiProvider(&config.Config{Provider: config.ProviderConfig{BaseURL: srv.URL, APIKey: "key", RateLimitRPS: 50}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"HARM_CATEGORY_DANGEROUS_CONTENT", "HARM_CATEGORY_HATE_SPEECH"} {
+		_, err := p.Review(context.Background(), &ReviewRequest{Diff: "+x := 1", FilePath: "a.go"})
+		var blocked *SafetyBlockError
+		if !errors.As(err, &blocked) || len(blocked.Categories) != 1 || blocked.Categories[0] != want {
+			t.Fatalf("Review() error = %v, want blocked for %s", err, want)
+		}
+		if IsRetryableError(err) || failure.CodeOf(ClassifyError("gemini", err)) != failure.ProviderBlocked {
+			t.Errorf("blocked error is retryable or coded %s", failure.CodeOf(err))
+		}
+	}
+}
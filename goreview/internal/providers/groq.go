@@ -54,7 +54,8 @@ func (p *GroqProvider) Review(ctx context.Context, req *ReviewRequest) (*ReviewR
 	}
 
 	start := time.Now()
-	groqReq := BuildChatRequest(p.model, ReviewSystemPrompt, buildReviewPrompt(req), p.config.Temperature, p.config.MaxTokens, true)
+	opts := requestFor(p.config, ReviewSystemPrompt, BuildReviewPrompt(req), true)
+	groqReq := BuildChatRequest(p.model, opts.system, opts.prompt, p.config.Temperature, opts.maxTokens, opts.jsonMode)
 
 	var result ChatCompletionResponse
 	if err := DoJSONPost(ctx, p.client, p.baseURL+ChatCompletionsPath, groqReq, p.apiKey, &result); err != nil {
@@ -65,7 +66,7 @@ func (p *GroqProvider) Review(ctx context.Context, req *ReviewRequest) (*ReviewR
 		return nil, fmt.Errorf("groq error: %s", result.Error.Message)
 	}
 
-	return ParseReviewContent(result.GetContent(), result.Usage.TotalTokens, time.Since(start).Milliseconds()), nil
+	return ParseReviewContent(result.GetContent(), result.Usage.TotalTokens, time.Since(start).Milliseconds()).withPrompt(opts.system, opts.prompt), nil
 }
 
 func (p *GroqProvider) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
@@ -102,6 +103,17 @@ func (p *GroqProvider) GenerateDocumentation(ctx context.Context, diff, docConte
 	return "", fmt.Errorf("no response from Groq")
 }
 
+// GenerateJSON uses the json_object response format.
+func (p *GroqProvider) GenerateJSON(ctx context.Context, prompt string, _ JSONSchema) (string, error) {
+	opts := requestFor(p.config, StructuredSystemPrompt, prompt, true)
+	req := BuildChatRequest(p.model, opts.system, opts.prompt, p.config.Temperature, opts.maxTokens, opts.jsonMode)
+	out, err := postChatJSON(ctx, p.client, p.baseURL+ChatCompletionsPath, p.apiKey, req)
+	if err != nil {
+		return "", fmt.Errorf("groq request failed: %w", err)
+	}
+	return out, nil
+}
+
 func (p *GroqProvider) HealthCheck(ctx context.Context) error {
 	return DoHealthCheck(ctx, p.client, p.baseURL+"/models", p.apiKey, "groq")
 }
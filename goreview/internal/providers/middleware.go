@@ -0,0 +1,296 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/privacy"
+)
+
+// Call kinds, one per Provider method that reaches the model.
+const (
+	CallReview = "review"
+	CallCommit = "commit"
+	CallDoc    = "doc"
+	CallJSON   = "json"
+)
+
+// Middleware phases.
+const (
+	PhaseBefore = "before"
+	PhaseAfter  = "after"
+)
+
+// Call is a provider call as middleware see it, and as plugins read and
+// write it in JSON. Only the fields of its kind are set.
+type Call struct {
+	Kind     string `json:"kind"`
+	Provider string `json:"provider"`
+	Phase    string `json:"phase"`
+
+	// Review is the request of review calls
+	Review *ReviewRequest `json:"review,omitempty"`
+	// Diff is the diff of commit and doc calls
+	Diff string `json:"diff,omitempty"`
+	// Context is the context of doc calls
+	Context string `json:"context,omitempty"`
+	// Prompt is the prompt of json calls
+	Prompt string `json:"prompt,omitempty"`
+
+	// Response is the answer of review calls, after the call
+	Response *ReviewResponse `json:"response,omitempty"`
+	// Text is the answer of the other calls, after the call
+	Text string `json:"text,omitempty"`
+	// Error is why the call failed; middleware still see failed calls after
+	// them, but can't recover them
+	Error string `json:"error,omitempty"`
+}
+
+// Middleware changes or inspects provider calls. Before runs before the
+// call is sent and may change its request; After runs once its answer came
+// back and may change the answer. An error from Before cancels the call.
+type Middleware interface {
+	Before(ctx context.Context, call *Call) error
+	After(ctx context.Context, call *Call) error
+}
+
+// MiddlewareProvider runs a chain of middleware around the calls of a
+// provider: Before in order, After in reverse order, so the first middleware
+// sees the request first and the answer last.
+type MiddlewareProvider struct {
+	inner Provider
+	chain []Middleware
+}
+
+// NewMiddlewareProvider wraps inner with the chain.
+func NewMiddlewareProvider(inner Provider, chain ...Middleware) *MiddlewareProvider {
+	return &MiddlewareProvider{inner: inner, chain: chain}
+}
+
+// NewMiddleware returns the middleware of a configuration entry.
+func NewMiddleware(cfg config.MiddlewareConfig) (Middleware, error) {
+	switch cfg.Type {
+	case "instructions":
+		return &instructionsMiddleware{instructions: cfg.Instructions}, nil
+	case "redact":
+		redactor, err := privacy.New(config.PrivacyConfig{RedactSecrets: true, RedactPatterns: cfg.Patterns})
+		if err != nil {
+			return nil, err
+		}
+		return &redactMiddleware{redactor: redactor}, nil
+	case "audit":
+		return &auditMiddleware{path: cfg.Path}, nil
+	case "exec":
+		return newExecMiddleware(cfg)
+	default:
+		return nil, fmt.Errorf("unknown middleware type: %s", cfg.Type)
+	}
+}
+
+// withMiddleware wraps p with the configured middleware, if any.
+func withMiddleware(p Provider, configs []config.MiddlewareConfig) (Provider, error) {
+	if len(configs) == 0 {
+		return p, nil
+	}
+	chain := make([]Middleware, 0, len(configs))
+	for i, c := range configs {
+		m, err := NewMiddleware(c)
+		if err != nil {
+			return nil, fmt.Errorf("provider.middleware[%d]: %w", i, err)
+		}
+		chain = append(chain, m)
+	}
+	return NewMiddlewareProvider(p, chain...), nil
+}
+
+func (m *MiddlewareProvider) Name() string {
+	return m.inner.Name()
+}
+
+func (m *MiddlewareProvider) Review(ctx context.Context, req *ReviewRequest) (*ReviewResponse, error) {
+	// The caller keeps using its request, to cache the answer for one
+	reqCopy := *req
+	call := &Call{Kind: CallReview, Review: &reqCopy}
+	err := m.run(ctx, call, func() error {
+		resp, err := m.inner.Review(ctx, call.Review)
+		call.Response = resp
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return call.Response, nil
+}
+
+func (m *MiddlewareProvider) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
+	call := &Call{Kind: CallCommit, Diff: diff}
+	return m.text(ctx, call, func() (string, error) {
+		return m.inner.GenerateCommitMessage(ctx, call.Diff)
+	})
+}
+
+func (m *MiddlewareProvider) GenerateDocumentation(ctx context.Context, diff, docContext string) (string, error) {
+	call := &Call{Kind: CallDoc, Diff: diff, Context: docContext}
+	return m.text(ctx, call, func() (string, error) {
+		return m.inner.GenerateDocumentation(ctx, call.Diff, call.Context)
+	})
+}
+
+// GenerateJSON runs structured calls through the chain, using the native
+// JSON mode of the wrapped provider when it has one.
+func (m *MiddlewareProvider) GenerateJSON(ctx context.Context, prompt string, schema JSONSchema) (string, error) {
+	call := &Call{Kind: CallJSON, Prompt: prompt}
+	return m.text(ctx, call, func() (string, error) {
+		return GenerateJSON(ctx, m.inner, call.Prompt, schema)
+	})
+}
+
+func (m *MiddlewareProvider) HealthCheck(ctx context.Context) error {
+	return m.inner.HealthCheck(ctx)
+}
+
+func (m *MiddlewareProvider) Close() error {
+	return m.inner.Close()
+}
+
+// text runs a call answered with text through the chain.
+func (m *MiddlewareProvider) text(ctx context.Context, call *Call, generate func() (string, error)) (string, error) {
+	err := m.run(ctx, call, func() error {
+		text, err := generate()
+		call.Text = text
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return call.Text, nil
+}
+
+// run runs the chain around send. The call's error is returned over any
+// middleware error after it.
+func (m *MiddlewareProvider) run(ctx context.Context, call *Call, send func() error) error {
+	call.Provider = m.inner.Name()
+	call.Phase = PhaseBefore
+	for _, mw := range m.chain {
+		if err := mw.Before(ctx, call); err != nil {
+			return fmt.Errorf("provider middleware: %w", err)
+		}
+	}
+
+	sendErr := send()
+	if sendErr != nil {
+		call.Error = sendErr.Error()
+	}
+	call.Phase = PhaseAfter
+	for i := len(m.chain) - 1; i >= 0; i-- {
+		if err := m.chain[i].After(ctx, call); err != nil && sendErr == nil {
+			return fmt.Errorf("provider middleware: %w", err)
+		}
+	}
+	return sendErr
+}
+
+// instructionsMiddleware adds mandatory instructions to the prompts of
+// review, doc and json calls. Commit prompts are left alone, as their
+// format is fixed.
+type instructionsMiddleware struct {
+	instructions []string
+}
+
+func (m *instructionsMiddleware) Before(_ context.Context, call *Call) error {
+	preamble := "Mandatory instructions:\n- " + strings.Join(m.instructions, "\n- ") + "\n\n"
+	switch call.Kind {
+	case CallReview:
+		call.Review.Instructions = append(append([]string{}, call.Review.Instructions...), m.instructions...)
+	case CallDoc:
+		call.Context = preamble + call.Context
+	case CallJSON:
+		call.Prompt = preamble + call.Prompt
+	}
+	return nil
+}
+
+func (m *instructionsMiddleware) After(context.Context, *Call) error { return nil }
+
+// redactMiddleware masks secrets and configured patterns in what is sent
+// to the provider and in the answer's text. The code an issue cites is
+// kept, as its location is verified against it.
+type redactMiddleware struct {
+	redactor *privacy.Redactor
+}
+
+func (m *redactMiddleware) Before(_ context.Context, call *Call) error {
+	r := m.redactor
+	if req := call.Review; req != nil {
+		req.Diff = r.Redact(req.Diff)
+		req.FileContent = r.Redact(req.FileContent)
+		req.Context = r.Redact(req.Context)
+		req.Related = r.Redact(req.Related)
+		req.ChangeSummary = r.Redact(req.ChangeSummary)
+		req.Intent = r.Redact(req.Intent)
+		focus := make([]string, len(req.Focus))
+		for i, f := range req.Focus {
+			focus[i] = r.Redact(f)
+		}
+		req.Focus = focus
+	}
+	call.Diff = r.Redact(call.Diff)
+	call.Context = r.Redact(call.Context)
+	call.Prompt = r.Redact(call.Prompt)
+	return nil
+}
+
+func (m *redactMiddleware) After(_ context.Context, call *Call) error {
+	r := m.redactor
+	if resp := call.Response; resp != nil {
+		resp.Summary = r.Redact(resp.Summary)
+		for i := range resp.Issues {
+			resp.Issues[i].Message = r.Redact(resp.Issues[i].Message)
+			resp.Issues[i].Suggestion = r.Redact(resp.Issues[i].Suggestion)
+		}
+	}
+	if call.Kind != CallJSON {
+		// Masking could break a JSON document
+		call.Text = r.Redact(call.Text)
+	}
+	return nil
+}
+
+// auditMiddleware appends every call, with its answer or error, to a JSON
+// Lines file.
+type auditMiddleware struct {
+	path string
+	mu   sync.Mutex
+}
+
+// auditEntry is a line of the audit file.
+type auditEntry struct {
+	Time time.Time `json:"time"`
+	*Call
+}
+
+func (m *auditMiddleware) Before(context.Context, *Call) error { return nil }
+
+func (m *auditMiddleware) After(_ context.Context, call *Call) error {
+	data, err := json.Marshal(auditEntry{Time: time.Now().UTC(), Call: call})
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) //nolint:gosec // Path from config
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,94 @@
+//go:build !js
+
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// defaultPluginTimeout bounds exec middleware without a timeout.
+const defaultPluginTimeout = 30 * time.Second
+
+// execMiddleware runs a plugin program on calls. The program reads the
+// call as JSON on stdin and writes it back on stdout, changed or not; no
+// output leaves the call unchanged. Exiting with an error cancels the call
+// before it is sent, or fails it after.
+type execMiddleware struct {
+	command []string
+	phase   string
+	timeout time.Duration
+}
+
+func newExecMiddleware(cfg config.MiddlewareConfig) (Middleware, error) {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultPluginTimeout
+	}
+	return &execMiddleware{command: cfg.Command, phase: cfg.Phase, timeout: timeout}, nil
+}
+
+func (m *execMiddleware) Before(ctx context.Context, call *Call) error {
+	return m.run(ctx, call, PhaseBefore)
+}
+
+func (m *execMiddleware) After(ctx context.Context, call *Call) error {
+	if call.Error != "" {
+		return nil
+	}
+	return m.run(ctx, call, PhaseAfter)
+}
+
+func (m *execMiddleware) run(ctx context.Context, call *Call, phase string) error {
+	if m.phase != "" && m.phase != phase {
+		return nil
+	}
+	input, err := json.Marshal(call)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, m.command[0], m.command[1:]...) // #nosec G204 - plugin command from config
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", m.command[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", m.command[0], err)
+	}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil
+	}
+
+	var out Call
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return fmt.Errorf("%s: invalid output: %w", m.command[0], err)
+	}
+	// Plugins change the request or the answer, not what the call is
+	out.Kind, out.Provider, out.Phase, out.Error = call.Kind, call.Provider, call.Phase, call.Error
+	if phase == PhaseBefore {
+		if call.Kind == CallReview && out.Review == nil {
+			return fmt.Errorf("%s: output has no review request", m.command[0])
+		}
+		out.Response, out.Text = call.Response, call.Text
+	} else if call.Response != nil {
+		if out.Response == nil {
+			return fmt.Errorf("%s: output has no review response", m.command[0])
+		}
+		// Exchanges aren't part of the JSON
+		out.Response.Exchanges = call.Response.Exchanges
+	}
+	*call = out
+	return nil
+}
@@ -0,0 +1,12 @@
+package providers
+
+import (
+	"errors"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// newExecMiddleware fails in WebAssembly, which can't run programs.
+func newExecMiddleware(config.MiddlewareConfig) (Middleware, error) {
+	return nil, errors.New("exec middleware is not supported in WebAssembly")
+}
@@ -0,0 +1,116 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// captureProvider keeps the last review request it was sent.
+type captureProvider struct {
+	stubProvider
+	req *ReviewRequest
+}
+
+func (c *captureProvider) Review(ctx context.Context, req *ReviewRequest) (*ReviewResponse, error) {
+	c.req = req
+	resp, err := c.stubProvider.Review(ctx, req)
+	if err == nil {
+		resp.Summary = "found token=supersecret123 in " + req.FilePath
+	}
+	return resp, err
+}
+
+func newTestMiddleware(t *testing.T, inner Provider, configs ...config.MiddlewareConfig) Provider {
+	t.Helper()
+	p, err := withMiddleware(inner, configs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	ctx := context.Background()
+	audit := filepath.Join(t.TempDir(), "audit.jsonl")
+	inner := &captureProvider{}
+	p := newTestMiddleware(t, inner,
+		config.MiddlewareConfig{Type: "instructions", Instructions: []string{"Flag any use of the legacy billing API"}},
+		config.MiddlewareConfig{Type: "redact", Patterns: []string{`[\w.]+@example\.com`}},
+		config.MiddlewareConfig{Type: "audit", Path: audit},
+	)
+
+	req := &ReviewRequest{Diff: "+// owner: jane@example.com\n+password = \"hunter22secret\"", FilePath: "a.go"}
+	resp, err := p.Review(ctx, req)
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+
+	sent := inner.req
+	if strings.Contains(sent.Diff, "jane@example.com") || strings.Contains(sent.Diff, "hunter22secret") {
+		t.Errorf("sent diff = %q, want email and password redacted", sent.Diff)
+	}
+	if len(sent.Instructions) != 1 || !strings.Contains(BuildReviewPrompt(sent), "legacy billing API") {
+		t.Errorf("sent instructions = %v, want them in the prompt", sent.Instructions)
+	}
+	if req.Diff != "+// owner: jane@example.com\n+password = \"hunter22secret\"" || req.Instructions != nil {
+		t.Errorf("caller's request was changed: %+v", req)
+	}
+	if strings.Contains(resp.Summary, "supersecret123") {
+		t.Errorf("summary = %q, want token redacted", resp.Summary)
+	}
+	if len(resp.Exchanges) != 1 {
+		t.Errorf("exchanges = %d, want 1", len(resp.Exchanges))
+	}
+
+	// The audit log is last in the chain: it sees the request as sent and
+	// the answer as received
+	data, err := os.ReadFile(audit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := string(data)
+	if strings.Count(line, "\n") != 1 || !strings.Contains(line, `"kind":"review"`) || strings.Contains(line, "jane@example.com") || !strings.Contains(line, "supersecret123") {
+		t.Errorf("audit log = %s", line)
+	}
+}
+
+func TestExecMiddleware(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("no shell")
+	}
+	ctx := context.Background()
+
+	// A plugin rewriting the commit diff before it is sent
+	rewrite := newTestMiddleware(t, &stubProvider{}, config.MiddlewareConfig{
+		Type: "exec", Phase: "before", Command: []string{"sed", "s/internal-host/HOST/"},
+	})
+	msg, err := rewrite.GenerateCommitMessage(ctx, "connect to internal-host")
+	if err != nil {
+		t.Fatalf("GenerateCommitMessage() error = %v", err)
+	}
+	if msg != "fix: connect to HOST" {
+		t.Errorf("message = %q, want the rewritten diff", msg)
+	}
+
+	// A plugin with no output leaves the call alone
+	silent := newTestMiddleware(t, &stubProvider{}, config.MiddlewareConfig{Type: "exec", Command: []string{"sh", "-c", "cat >/dev/null"}})
+	resp, err := silent.Review(ctx, &ReviewRequest{FilePath: "a.go"})
+	if err != nil || len(resp.Issues) != 1 {
+		t.Errorf("Review() = %+v, %v; want the answer unchanged", resp, err)
+	}
+
+	// A failing plugin blocks the call
+	stub := &stubProvider{}
+	block := newTestMiddleware(t, stub, config.MiddlewareConfig{Type: "exec", Command: []string{"sh", "-c", "echo forbidden path >&2; exit 1"}})
+	if _, err := block.Review(ctx, &ReviewRequest{FilePath: "secret/a.go"}); err == nil || !strings.Contains(err.Error(), "forbidden path") {
+		t.Errorf("Review() error = %v, want the plugin's message", err)
+	}
+	if stub.calls != 0 {
+		t.Errorf("provider called %d times, want 0", stub.calls)
+	}
+}
@@ -53,7 +53,8 @@ func (p *MistralProvider) Review(ctx context.Context, req *ReviewRequest) (*Revi
 	}
 
 	start := time.Now()
-	mistralReq := BuildChatRequest(p.model, ReviewSystemPrompt, buildReviewPrompt(req), p.config.Temperature, p.config.MaxTokens, true)
+	opts := requestFor(p.config, ReviewSystemPrompt, BuildReviewPrompt(req), true)
+	mistralReq := BuildChatRequest(p.model, opts.system, opts.prompt, p.config.Temperature, opts.maxTokens, opts.jsonMode)
 
 	var result ChatCompletionResponse
 	if err := DoJSONPost(ctx, p.client, p.baseURL+ChatCompletionsPath, mistralReq, p.apiKey, &result); err != nil {
@@ -64,7 +65,7 @@ func (p *MistralProvider) Review(ctx context.Context, req *ReviewRequest) (*Revi
 		return nil, fmt.Errorf("mistral error: %s", result.Error.Message)
 	}
 
-	return ParseReviewContent(result.GetContent(), result.Usage.TotalTokens, time.Since(start).Milliseconds()), nil
+	return ParseReviewContent(result.GetContent(), result.Usage.TotalTokens, time.Since(start).Milliseconds()).withPrompt(opts.system, opts.prompt), nil
 }
 
 func (p *MistralProvider) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
@@ -101,6 +102,17 @@ func (p *MistralProvider) GenerateDocumentation(ctx context.Context, diff, docCo
 	return "", fmt.Errorf("no response from Mistral")
 }
 
+// GenerateJSON uses the json_object response format.
+func (p *MistralProvider) GenerateJSON(ctx context.Context, prompt string, _ JSONSchema) (string, error) {
+	opts := requestFor(p.config, StructuredSystemPrompt, prompt, true)
+	req := BuildChatRequest(p.model, opts.system, opts.prompt, p.config.Temperature, opts.maxTokens, opts.jsonMode)
+	out, err := postChatJSON(ctx, p.client, p.baseURL+ChatCompletionsPath, p.apiKey, req)
+	if err != nil {
+		return "", fmt.Errorf("mistral request failed: %w", err)
+	}
+	return out, nil
+}
+
 func (p *MistralProvider) HealthCheck(ctx context.Context) error {
 	return DoHealthCheck(ctx, p.client, p.baseURL+"/models", p.apiKey, "mistral")
 }
@@ -23,6 +23,14 @@ const (
 
 	// ModeTests focuses on test coverage, edge cases, mocking issues.
 	ModeTests ReviewMode = "tests"
+
+	// ModeErrors focuses on Go error handling: swallowed, unwrapped and
+	// misclassified errors, and panics.
+	ModeErrors ReviewMode = "errors"
+
+	// ModeConcurrency focuses on Go concurrency: goroutine leaks, data races,
+	// copied sync values and WaitGroup/Mutex misuse.
+	ModeConcurrency ReviewMode = "concurrency"
 )
 
 // ModePrompts contains the mode-specific instructions for the reviewer.
@@ -221,6 +229,56 @@ SEVERITY GUIDELINES:
 - INFO: Test organization improvements, naming suggestions
 
 Only report testing-related issues. Ignore production code style or documentation.`,
+
+	ModeErrors: `GO ERROR HANDLING REVIEW MODE - Focus on how errors are produced, propagated and handled:
+
+Swallowed errors, errors formatted without %w, == comparisons and type assertions on
+errors, errors.Is/As misuse and panics in library code are already flagged by static
+checks. Don't repeat them; judge what needs context instead:
+
+CHECK FOR:
+- Ignored errors that are NOT safe to ignore (e.g. Close on a written file, Flush, Commit)
+- Errors that are both logged and returned (handled twice)
+- Missing context when propagating: which operation and which input failed
+- Wrapping that leaks implementation details across API boundaries (%w exposes the cause)
+- Sentinel errors vs error types: callers need to tell errors apart but can't
+- Error messages: capitalized, ending in punctuation, or starting with "failed to" at every level
+- Errors lost in goroutines, deferred calls or callbacks
+- recover() that hides bugs, or panics for conditions callers should handle
+- Returning a typed nil pointer as an error interface
+
+SEVERITY GUIDELINES:
+- ERROR: Errors lost on paths that corrupt data or hide failures (writes, commits, cleanup)
+- WARNING: Missing context, double handling, errors callers can't distinguish
+- INFO: Message style and wrapping consistency
+
+Only report error handling issues. Ignore style, performance or documentation.`,
+
+	ModeConcurrency: `GO CONCURRENCY REVIEW MODE - Focus on goroutines, shared state and synchronization:
+
+Static heuristics list the suspicious regions of the file, when they find any. Examine
+each one first and report it only if the problem is real; then look for what they miss.
+
+CHECK FOR:
+- Goroutine leaks: goroutines blocked on channels or looping with no context, done
+  channel or close to stop them; unbuffered sends nobody receives after a timeout
+- Data races: maps, slices or fields written from goroutines without a lock or channel;
+  loop variables shared by closures (before Go 1.22)
+- Copied sync values: Mutex, WaitGroup, Once or structs holding them passed or
+  received by value, or copied by assignment or range
+- WaitGroup misuse: Add inside the goroutine, Done missing on some path, negative counter
+- Mutex misuse: Lock without Unlock on every path, Unlock of an unlocked mutex,
+  RLock upgraded to Lock, locks held across blocking calls, inconsistent lock order
+- Channels: double close, close by a receiver, send on a possibly closed channel
+- Context: ignored cancellation, context.Background in request paths, cancel not called
+
+SEVERITY GUIDELINES:
+- CRITICAL: Data races on shared maps (they crash the program), deadlocks
+- ERROR: Goroutine leaks, copied locks, WaitGroup races
+- WARNING: Missing cancellation, locks held across I/O
+- INFO: Clearer synchronization alternatives (errgroup, sync.OnceValue)
+
+Only report concurrency issues. Ignore style, performance or documentation.`,
 }
 
 // ValidModes returns all valid mode names.
@@ -232,6 +290,8 @@ func ValidModes() []string {
 		string(ModeClean),
 		string(ModeDocs),
 		string(ModeTests),
+		string(ModeErrors),
+		string(ModeConcurrency),
 	}
 }
 
@@ -8,7 +8,7 @@ import (
 func TestValidModes(t *testing.T) {
 	modes := ValidModes()
 
-	expected := []string{"default", "security", "perf", "clean", "docs", "tests"}
+	expected := []string{"default", "security", "perf", "clean", "docs", "tests", "errors", "concurrency"}
 	if len(modes) != len(expected) {
 		t.Errorf("expected %d modes, got %d", len(expected), len(modes))
 	}
@@ -161,6 +161,7 @@ func TestModePromptsContainKeyContent(t *testing.T) {
 		{ModeClean, []string{"solid", "dry", "naming", "code smell"}},
 		{ModeDocs, []string{"documentation", "jsdoc", "godoc", "docstring"}},
 		{ModeTests, []string{"test coverage", "edge case", "mocking", "assertion"}},
+		{ModeConcurrency, []string{"goroutine", "suspicious regions", "waitgroup", "mutex"}},
 	}
 
 	for _, tt := range tests {
@@ -2,8 +2,11 @@ package providers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/JNZader/goreview/goreview/internal/config"
@@ -52,14 +55,15 @@ func (p *OllamaProvider) Review(ctx context.Context, req *ReviewRequest) (*Revie
 	}
 
 	start := time.Now()
-	ollamaReq := BuildOllamaRequest(p.model, buildReviewPrompt(req), p.config.Temperature, p.config.MaxTokens, true)
+	opts := requestFor(p.config, ReviewSystemPrompt, BuildReviewPrompt(req), true)
+	ollamaReq := p.buildRequest(opts)
 
 	var result OllamaResponse
 	if err := DoJSONPost(ctx, p.client, p.baseURL+APIGeneratePath, ollamaReq, "", &result); err != nil {
 		return nil, fmt.Errorf("ollama request failed: %w", err)
 	}
 
-	return ParseReviewContent(result.Response, 0, time.Since(start).Milliseconds()), nil
+	return ParseReviewContent(result.Response, 0, time.Since(start).Milliseconds()).withPrompt(opts.system, opts.prompt), nil
 }
 
 func (p *OllamaProvider) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
@@ -86,20 +90,60 @@ func (p *OllamaProvider) GenerateDocumentation(ctx context.Context, diff, docCon
 	return result.Response, nil
 }
 
+// GenerateJSON passes the schema as the structured output format.
+func (p *OllamaProvider) GenerateJSON(ctx context.Context, prompt string, schema JSONSchema) (string, error) {
+	if p.rateLimiter != nil {
+		if err := p.rateLimiter.Wait(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	opts := requestFor(p.config, StructuredSystemPrompt, prompt, true)
+	ollamaReq := p.buildRequest(opts)
+	if opts.jsonMode && schema != nil {
+		ollamaReq["format"] = schema
+	}
+
+	var result OllamaResponse
+	if err := DoJSONPost(ctx, p.client, p.baseURL+APIGeneratePath, ollamaReq, "", &result); err != nil {
+		return "", fmt.Errorf("ollama request failed: %w", err)
+	}
+	return result.Response, nil
+}
+
+// buildRequest builds a generate request, with the system prompt apart
+// when the model takes one.
+func (p *OllamaProvider) buildRequest(opts requestOptions) map[string]interface{} {
+	req := BuildOllamaRequest(p.model, opts.prompt, p.config.Temperature, opts.maxTokens, opts.jsonMode)
+	if opts.system != "" {
+		req["system"] = opts.system
+	}
+	return req
+}
+
 func (p *OllamaProvider) HealthCheck(ctx context.Context) error {
 	return DoHealthCheck(ctx, p.client, p.baseURL+"/api/tags", "", "ollama")
 }
 
 func (p *OllamaProvider) Close() error { return nil }
 
-func buildReviewPrompt(req *ReviewRequest) string {
+// BuildReviewPrompt returns the user prompt reviewing the request's file,
+// sent after ReviewSystemPrompt.
+func BuildReviewPrompt(req *ReviewRequest) string {
 	personalityPrompt := GetPersonalityPrompt(req.Personality)
 	modePrompt := CombineModePrompts(req.Modes)
 
-	issueSchema := `{"id": "1", "type": "bug|security|performance|style", "severity": "info|warning|error|critical", "message": "description", "suggestion": "how to fix"}`
+	typeList, typeInstructions := issueTypePrompt(req.IssueTypes)
+	taxonomy := taxonomyPrompt(req.IssueTypes)
+	taxonomyFields := ""
+	if taxonomy != "" {
+		typeInstructions += taxonomy
+		taxonomyFields = `, "cwe": "CWE-89", "owasp": "A03:2021"`
+	}
+	issueSchema := `{"id": "1", "type": "` + typeList + `", "severity": "info|warning|error|critical", "message": "description", "suggestion": "how to fix", "location": {"start_line": 10, "end_line": 12}, "code": "exact offending line(s) copied from the code", "rule_id": "optional"` + taxonomyFields + `}`
 
 	if req.RootCauseTracing {
-		issueSchema = `{"id": "1", "type": "bug|security|performance|style", "severity": "info|warning|error|critical", "message": "description", "suggestion": "how to fix", "root_cause": {"description": "why this issue exists", "propagation_path": ["step1", "step2"], "recommendation": "how to fix at the source"}}`
+		issueSchema = `{"id": "1", "type": "` + typeList + `", "severity": "info|warning|error|critical", "message": "description", "suggestion": "how to fix", "location": {"start_line": 10, "end_line": 12}, "code": "exact offending line(s) copied from the code", "rule_id": "optional"` + taxonomyFields + `, "root_cause": {"description": "why this issue exists", "propagation_path": ["step1", "step2"], "recommendation": "how to fix at the source"}}`
 	}
 
 	rootCauseInstructions := ""
@@ -113,12 +157,42 @@ For each issue, analyze and provide:
 - recommendation: How to fix the issue at its source, not just its symptoms`
 	}
 
+	rulesInstructions := ""
+	if len(req.Instructions) > 0 {
+		rulesInstructions = "\nMANDATORY INSTRUCTIONS (always follow these):\n- " + strings.Join(req.Instructions, "\n- ") + "\n"
+	}
+	if len(req.Conventions) > 0 {
+		rulesInstructions += "\nTEAM CONVENTIONS (learned from the fixes this team accepted and rejected; follow them):\n- " + strings.Join(req.Conventions, "\n- ") + "\n"
+	}
+	if req.Intent != "" {
+		rulesInstructions += "\nCHANGE INTENT (from the pull request and its review so far; judge the change against it, and don't report what it explains as intended):\n" + req.Intent
+	}
+	if len(req.Rules) > 0 {
+		rulesInstructions += "\nPROJECT RULES (set \"rule_id\" on issues that violate one):\n- " + strings.Join(req.Rules, "\n- ") + "\n"
+	}
+	if req.Context != "" {
+		rulesInstructions += "\nAPI KNOWLEDGE (known pitfalls of APIs this file imports; report only misuse present in the code):\n" + req.Context
+	}
+	if len(req.Focus) > 0 {
+		rulesInstructions += "\nSUSPICIOUS REGIONS (from static checks; confirm or dismiss each):\n- " + strings.Join(req.Focus, "\n- ") + "\n"
+	}
+	if req.ChangeSummary != "" {
+		rulesInstructions += "\nCHANGE SUMMARY (computed locally from the code; use it to understand the change):\n" + req.ChangeSummary
+	}
+	formatNote := ""
+	if req.Format != "" {
+		formatNote = "\nFormat: " + req.Format
+	}
+	if req.Related != "" {
+		rulesInstructions += "\nRELATED FILES (same package, for context only; don't report issues in them):\n" + req.Related
+	}
+
 	return fmt.Sprintf(`%s
 
 %s
-%s
+%s%s%s
 File: %s
-Language: %s
+Language: %s%s
 
 Code:
 %s
@@ -128,5 +202,52 @@ Return a JSON object:
   "issues": [%s],
   "summary": "brief summary",
   "score": 85
-}`, personalityPrompt, modePrompt, rootCauseInstructions, req.FilePath, req.Language, req.Diff, issueSchema)
+}`, personalityPrompt, modePrompt, rootCauseInstructions, rulesInstructions, typeInstructions, req.FilePath, req.Language, formatNote, req.Diff, issueSchema)
+}
+
+// PromptTemplateHash identifies the review prompt template produced for the
+// request's settings (personality, modes, root cause tracing, issue types).
+// The file, its code and format, the rules, the knowledge, the focus
+// regions, the related files, the change summary and the intent of the
+// pull request vary per file or per change and are left out.
+func PromptTemplateHash(req *ReviewRequest) string {
+	tmpl := *req
+	tmpl.FilePath, tmpl.Language, tmpl.Diff, tmpl.Rules = "{file}", "{language}", "{code}", nil
+	tmpl.Context, tmpl.Focus, tmpl.Related, tmpl.Format, tmpl.ChangeSummary = "", nil, "", "", ""
+	tmpl.Intent = ""
+	sum := sha256.Sum256([]byte(ReviewSystemPrompt + "\n" + BuildReviewPrompt(&tmpl)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// PromptInstructions returns the part of the review prompt that doesn't
+// depend on the file: the system prompt and the template for the request's
+// settings, without the code and its format, rules, knowledge, focus
+// regions, related files or change summary. The intent of the pull request
+// is the same for every file and is kept.
+func PromptInstructions(req *ReviewRequest) string {
+	tmpl := *req
+	tmpl.Diff, tmpl.Rules, tmpl.Context, tmpl.Focus, tmpl.Related, tmpl.Format = "", nil, "", nil, "", ""
+	tmpl.ChangeSummary = ""
+	return ReviewSystemPrompt + "\n" + BuildReviewPrompt(&tmpl)
+}
+
+// issueTypePrompt returns the issue type list for the JSON schema and, for
+// custom taxonomies, a section describing each type.
+func issueTypePrompt(types []IssueTypeInfo) (string, string) {
+	if len(types) == 0 {
+		return "bug|security|performance|style", ""
+	}
+
+	names := make([]string, len(types))
+	var sb strings.Builder
+	sb.WriteString("\nISSUE TYPES (use exactly one of these for \"type\"):\n")
+	for i, t := range types {
+		names[i] = t.Name
+		sb.WriteString("- " + t.Name)
+		if t.Description != "" {
+			sb.WriteString(": " + t.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return strings.Join(names, "|"), sb.String()
 }
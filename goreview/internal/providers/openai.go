@@ -48,14 +48,15 @@ func (p *OpenAIProvider) Review(ctx context.Context, req *ReviewRequest) (*Revie
 	}
 
 	start := time.Now()
-	openaiReq := BuildChatRequest(p.model, ReviewSystemPrompt, buildReviewPrompt(req), p.config.Temperature, p.config.MaxTokens, false)
+	opts := requestFor(p.config, ReviewSystemPrompt, BuildReviewPrompt(req), true)
+	openaiReq := BuildChatRequest(p.model, opts.system, opts.prompt, p.config.Temperature, opts.maxTokens, opts.jsonMode)
 
 	var result ChatCompletionResponse
 	if err := DoJSONPost(ctx, p.client, p.baseURL+ChatCompletionsPath, openaiReq, p.apiKey, &result); err != nil {
 		return nil, err
 	}
 
-	return ParseReviewContent(result.GetContent(), result.Usage.TotalTokens, time.Since(start).Milliseconds()), nil
+	return ParseReviewContent(result.GetContent(), result.Usage.TotalTokens, time.Since(start).Milliseconds()).withPrompt(opts.system, opts.prompt), nil
 }
 
 func (p *OpenAIProvider) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
@@ -66,6 +67,16 @@ func (p *OpenAIProvider) GenerateDocumentation(ctx context.Context, diff, docCon
 	return "", fmt.Errorf("not implemented")
 }
 
+// GenerateJSON uses structured outputs (json_schema) on models with JSON mode.
+func (p *OpenAIProvider) GenerateJSON(ctx context.Context, prompt string, schema JSONSchema) (string, error) {
+	opts := requestFor(p.config, StructuredSystemPrompt, prompt, true)
+	req := BuildChatRequest(p.model, opts.system, opts.prompt, p.config.Temperature, opts.maxTokens, opts.jsonMode)
+	if opts.jsonMode {
+		req = withJSONSchema(req, schema)
+	}
+	return postChatJSON(ctx, p.client, p.baseURL+ChatCompletionsPath, p.apiKey, req)
+}
+
 func (p *OpenAIProvider) HealthCheck(ctx context.Context) error {
 	return DoHealthCheck(ctx, p.client, p.baseURL+"/models", p.apiKey, "openai")
 }
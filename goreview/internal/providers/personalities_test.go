@@ -139,3 +139,20 @@ func containsString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestPromptTemplateHash(t *testing.T) {
+	base := &ReviewRequest{Personality: "default", FilePath: "a.go", Diff: "+x", Rules: []string{"no panics"}, Focus: []string{"line 1 (concurrency/mutex): Lock is never unlocked"}, ChangeSummary: "Functions added: func f()\n", Intent: "Pull request #9: Pool connections\n"}
+	other := &ReviewRequest{Personality: "default", FilePath: "b.go", Diff: "+y"}
+	if PromptTemplateHash(base) != PromptTemplateHash(other) {
+		t.Error("hash should not depend on the file, code, rules, focus regions, change summary or intent")
+	}
+
+	strict := &ReviewRequest{Personality: "strict", FilePath: "a.go", Diff: "+x"}
+	if PromptTemplateHash(base) == PromptTemplateHash(strict) {
+		t.Error("hash should change with the personality")
+	}
+
+	if base.FilePath != "a.go" || len(base.Rules) != 1 {
+		t.Error("PromptTemplateHash modified the request")
+	}
+}
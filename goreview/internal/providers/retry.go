@@ -2,6 +2,7 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -59,6 +60,12 @@ func IsRetryableError(err error) bool {
 		return false
 	}
 
+	// The same request is blocked again
+	var blocked *SafetyBlockError
+	if errors.As(err, &blocked) {
+		return false
+	}
+
 	errStr := err.Error()
 
 	// Check non-retryable patterns first
@@ -78,6 +85,21 @@ func IsRetryableError(err error) bool {
 	return false
 }
 
+// IsRateLimitError reports whether the provider throttled the request
+func IsRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	errStr := strings.ToLower(err.Error())
+	return strings.Contains(errStr, "429") ||
+		strings.Contains(errStr, "too many requests") ||
+		strings.Contains(errStr, "rate limit")
+}
+
 // IsRetryableStatusCode checks if HTTP status code should be retried
 func IsRetryableStatusCode(statusCode int) bool {
 	return statusCode == http.StatusTooManyRequests ||
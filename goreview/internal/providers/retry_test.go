@@ -0,0 +1,30 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsRateLimitError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"status error 429", &StatusError{StatusCode: 429}, true},
+		{"wrapped status error", fmt.Errorf("ollama request failed: %w", &StatusError{StatusCode: 429}), true},
+		{"status error 503", &StatusError{StatusCode: 503}, false},
+		{"rate limit message", errors.New("Rate limit exceeded"), true},
+		{"other error", errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRateLimitError(tt.err); got != tt.want {
+				t.Errorf("IsRateLimitError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
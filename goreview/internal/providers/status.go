@@ -0,0 +1,345 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// Status is the health of a configured provider.
+type Status struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+	// Reachable is whether the provider answered the check
+	Reachable bool `json:"reachable"`
+	// ModelAvailable is whether the provider serves the model; nil when
+	// the provider doesn't tell
+	ModelAvailable *bool `json:"model_available,omitempty"`
+	// LatencyMS is how long the check took
+	LatencyMS int64 `json:"latency_ms"`
+	// RateLimit is the request quota left, from the answer's headers
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
+	// Recent are the calls recorded by the audit middleware in the window
+	Recent *CallStats `json:"recent,omitempty"`
+	// Error is why the provider is unhealthy
+	Error string `json:"error,omitempty"`
+}
+
+// Healthy reports whether the provider answered without error and serves
+// the model, as far as it tells.
+func (s *Status) Healthy() bool {
+	return s.Reachable && s.Error == ""
+}
+
+// RateLimit is a provider's request quota.
+type RateLimit struct {
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+	// Reset is when the quota refills, as the provider writes it
+	Reset string `json:"reset,omitempty"`
+}
+
+// Headroom returns the share of the quota left, 0 to 1.
+func (r *RateLimit) Headroom() float64 {
+	if r.Limit <= 0 {
+		return 0
+	}
+	return float64(r.Remaining) / float64(r.Limit)
+}
+
+// CallStats counts the calls made to a provider.
+type CallStats struct {
+	Calls  int `json:"calls"`
+	Errors int `json:"errors"`
+	// LastError is the error of the latest failed call
+	LastError string `json:"last_error,omitempty"`
+}
+
+// ErrorRate returns the share of failed calls, 0 to 1.
+func (c *CallStats) ErrorRate() float64 {
+	if c.Calls == 0 {
+		return 0
+	}
+	return float64(c.Errors) / float64(c.Calls)
+}
+
+// rateLimitHeaders are the request quota headers of the providers, as
+// limit, remaining and reset
+var rateLimitHeaders = [][3]string{
+	{"x-ratelimit-limit-requests", "x-ratelimit-remaining-requests", "x-ratelimit-reset-requests"},
+	{"x-ratelimit-limit", "x-ratelimit-remaining", "x-ratelimit-reset"},
+	{"ratelimit-limit", "ratelimit-remaining", "ratelimit-reset"},
+	{"anthropic-ratelimit-requests-limit", "anthropic-ratelimit-requests-remaining", "anthropic-ratelimit-requests-reset"},
+}
+
+// probe is the request checking a provider.
+type probe struct {
+	client *http.Client
+	url    string
+	header http.Header
+	// listed reports whether the answer lists the model; nil when the
+	// request reads the model itself, so a 404 means it isn't served
+	listed func(body []byte, model string) bool
+}
+
+// probeFor returns the check of the built-in providers.
+func probeFor(p Provider) (probe, bool) {
+	switch p := p.(type) {
+	case *OllamaProvider:
+		return probe{client: p.client, url: p.baseURL + "/api/tags", listed: ollamaListed}, true
+	case *OpenAIProvider:
+		return probe{client: p.client, url: p.baseURL + "/models", header: bearer(p.apiKey), listed: modelsListed}, true
+	case *GroqProvider:
+		return probe{client: p.client, url: p.baseURL + "/models", header: bearer(p.apiKey), listed: modelsListed}, true
+	case *MistralProvider:
+		return probe{client: p.client, url: p.baseURL + "/models", header: bearer(p.apiKey), listed: modelsListed}, true
+	case *GeminiProvider:
+		if p.tokens != nil {
+			// Vertex AI needs a token first; HealthCheck gets one
+			return probe{}, false
+		}
+		return probe{client: p.client, url: fmt.Sprintf("%s/models/%s?key=%s", p.baseURL, p.model, p.apiKey)}, true
+	case *AnthropicProvider:
+		return probe{client: p.client, url: p.baseURL + "/models/" + p.model, header: p.headers()}, true
+	}
+	return probe{}, false
+}
+
+func bearer(apiKey string) http.Header {
+	return http.Header{"Authorization": {AuthBearerPrefix + apiKey}}
+}
+
+// providerModel returns the model a built-in provider uses.
+func providerModel(p Provider) string {
+	switch p := p.(type) {
+	case *OllamaProvider:
+		return p.model
+	case *OpenAIProvider:
+		return p.model
+	case *GroqProvider:
+		return p.model
+	case *MistralProvider:
+		return p.model
+	case *GeminiProvider:
+		return p.model
+	case *AnthropicProvider:
+		return p.model
+	}
+	return ""
+}
+
+// ollamaListed reports whether Ollama has pulled the model; models without
+// a tag are "latest".
+func ollamaListed(body []byte, model string) bool {
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if json.Unmarshal(body, &tags) != nil {
+		return false
+	}
+	if !strings.Contains(model, ":") {
+		model += ":latest"
+	}
+	for _, m := range tags.Models {
+		if m.Name == model {
+			return true
+		}
+	}
+	return false
+}
+
+// modelsListed reports whether an OpenAI-compatible models list has the
+// model.
+func modelsListed(body []byte, model string) bool {
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(body, &list) != nil {
+		return false
+	}
+	for _, m := range list.Data {
+		if m.ID == model {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckStatus checks that a provider answers, serves its model and has
+// quota left, timing the check. Providers other than the built-in ones
+// only run their health check.
+func CheckStatus(ctx context.Context, p Provider) Status {
+	st := Status{Provider: p.Name(), Model: providerModel(p)}
+	pr, ok := probeFor(p)
+	start := time.Now()
+	if !ok {
+		err := p.HealthCheck(ctx)
+		st.LatencyMS = time.Since(start).Milliseconds()
+		st.Reachable = err == nil
+		if err != nil {
+			st.Error = err.Error()
+		}
+		return st
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pr.url, nil)
+	if err != nil {
+		st.Error = fmt.Errorf(ErrCreateRequest, err).Error()
+		return st
+	}
+	for k, v := range pr.header {
+		req.Header[k] = v
+	}
+	resp, err := pr.client.Do(req)
+	st.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		// The URL of some providers holds the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		st.Error = err.Error()
+		return st
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		st.Error = fmt.Sprintf("reading response: %v", err)
+		return st
+	}
+	st.Reachable = true
+	st.RateLimit = parseRateLimit(resp.Header)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && pr.listed == nil:
+		st.ModelAvailable = new(bool)
+	case resp.StatusCode != http.StatusOK:
+		st.Error = fmt.Sprintf("%s health check failed: %d", st.Provider, resp.StatusCode)
+	default:
+		available := pr.listed == nil || pr.listed(body, st.Model)
+		st.ModelAvailable = &available
+	}
+	if st.ModelAvailable != nil && !*st.ModelAvailable {
+		st.Error = fmt.Sprintf("model %s not available", st.Model)
+	}
+	return st
+}
+
+// parseRateLimit reads the request quota from the headers of an answer,
+// nil when they have none.
+func parseRateLimit(h http.Header) *RateLimit {
+	for _, names := range rateLimitHeaders {
+		limit, err1 := strconv.Atoi(h.Get(names[0]))
+		remaining, err2 := strconv.Atoi(h.Get(names[1]))
+		if err1 == nil && err2 == nil {
+			return &RateLimit{Limit: limit, Remaining: remaining, Reset: h.Get(names[2])}
+		}
+	}
+	return nil
+}
+
+// ConfiguredProviders returns the providers a review may call: the
+// configured provider or, for fallback, the providers whose API key is set
+// and, for auto outside CI, also the local Ollama. Providers that can't be
+// created are returned as unhealthy statuses.
+func ConfiguredProviders(cfg *config.Config) ([]Provider, []Status) {
+	var providers []Provider
+	var failed []Status
+	switch cfg.Provider.Name {
+	case "fallback", "auto", "":
+		ci := os.Getenv("GITHUB_ACTIONS") == "true" || os.Getenv("CI") == "true"
+		if cfg.Provider.Name != "fallback" && !ci {
+			local := *cfg
+			local.Provider.BaseURL = "http://localhost:11434"
+			if local.Provider.Model == "" {
+				local.Provider.Model = "qwen2.5-coder:14b"
+			}
+			if p, err := NewOllamaProvider(&local); err == nil {
+				providers = append(providers, p)
+			}
+		}
+		providers = append(providers, envProviders()...)
+		if cfg.Provider.Name == "fallback" && len(providers) == 0 {
+			failed = append(failed, Status{Provider: "fallback", Error: "no API keys found"})
+		}
+	default:
+		p, err := newProvider(cfg)
+		if err != nil {
+			failed = append(failed, Status{Provider: cfg.Provider.Name, Model: cfg.Provider.Model, Error: err.Error()})
+		} else {
+			providers = append(providers, p)
+		}
+	}
+	return providers, failed
+}
+
+// AuditLogs returns the files of the configured audit middleware.
+func AuditLogs(cfg *config.ProviderConfig) []string {
+	var paths []string
+	for _, m := range cfg.Middleware {
+		if m.Type == "audit" && m.Path != "" {
+			paths = append(paths, m.Path)
+		}
+	}
+	return paths
+}
+
+// RecentCalls counts the calls recorded in audit logs since a time, by
+// provider. Calls through a fallback chain count for the provider that
+// answered. Missing logs are skipped.
+func RecentCalls(paths []string, since time.Time) (map[string]*CallStats, error) {
+	stats := map[string]*CallStats{}
+	for _, path := range paths {
+		f, err := os.Open(path) //nolint:gosec // Path from config
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 64<<20)
+		for scanner.Scan() {
+			var entry struct {
+				Time     time.Time `json:"time"`
+				Provider string    `json:"provider"`
+				Error    string    `json:"error"`
+			}
+			if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.Time.Before(since) {
+				continue
+			}
+			name := strings.TrimSuffix(strings.TrimPrefix(entry.Provider, "fallback("), ")")
+			s := stats[name]
+			if s == nil {
+				s = &CallStats{}
+				stats[name] = s
+			}
+			s.Calls++
+			if entry.Error != "" {
+				s.Errors++
+				s.LastError = entry.Error
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading audit log %s: %w", path, err)
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,88 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+func TestCheckStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			if r.Header.Get("Authorization") != "Bearer sk-test" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("x-ratelimit-limit-requests", "500")
+			w.Header().Set("x-ratelimit-remaining-requests", "125")
+			_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o"},{"id":"gpt-4o-mini"}]}`))
+		case "/api/tags":
+			_, _ = w.Write([]byte(`{"models":[{"name":"llama3:latest"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	openai := &config.Config{Provider: config.ProviderConfig{Name: "openai", BaseURL: srv.URL + "/v1", APIKey: "sk-test", Model: "gpt-4o"}}
+	p, err := NewOpenAIProvider(openai)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := CheckStatus(context.Background(), p)
+	if !st.Healthy() || st.ModelAvailable == nil || !*st.ModelAvailable {
+		t.Errorf("openai status = %+v, want healthy", st)
+	}
+	if st.RateLimit == nil || st.RateLimit.Remaining != 125 || st.RateLimit.Headroom() != 0.25 {
+		t.Errorf("rate limit = %+v, want 125 of 500 left", st.RateLimit)
+	}
+
+	openai.Provider.APIKey = "sk-wrong"
+	p, _ = NewOpenAIProvider(openai)
+	if st := CheckStatus(context.Background(), p); !st.Reachable || st.Healthy() {
+		t.Errorf("status with a wrong key = %+v, want reachable but unhealthy", st)
+	}
+
+	for model, available := range map[string]bool{"llama3": true, "qwen2.5-coder:14b": false} {
+		ollama, _ := NewOllamaProvider(&config.Config{Provider: config.ProviderConfig{BaseURL: srv.URL, Model: model}})
+		st := CheckStatus(context.Background(), ollama)
+		if st.ModelAvailable == nil || *st.ModelAvailable != available || st.Healthy() != available {
+			t.Errorf("ollama status for %s = %+v, want available %v", model, st, available)
+		}
+	}
+
+	srv.Close()
+	if st := CheckStatus(context.Background(), p); st.Reachable || st.Error == "" {
+		t.Errorf("status of a stopped server = %+v, want unreachable", st)
+	}
+}
+
+func TestRecentCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	lines := `{"time":"2026-01-01T10:00:00Z","provider":"groq"}
+{"time":"2026-01-02T10:00:00Z","provider":"fallback(groq)","error":"429 too many requests"}
+{"time":"2026-01-02T11:00:00Z","provider":"groq"}
+{"time":"2026-01-02T12:00:00Z","provider":"openai","error":"timeout"}
+`
+	if err := os.WriteFile(path, []byte(lines), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	since := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	stats, err := RecentCalls([]string{path, filepath.Join(t.TempDir(), "missing.jsonl")}, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groq := stats["groq"]
+	if groq == nil || groq.Calls != 2 || groq.Errors != 1 || groq.LastError != "429 too many requests" || groq.ErrorRate() != 0.5 {
+		t.Errorf("groq = %+v, want 1 of 2 recent calls failed", groq)
+	}
+	if openai := stats["openai"]; openai == nil || openai.Errors != 1 {
+		t.Errorf("openai = %+v, want 1 failed call", openai)
+	}
+}
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// StructuredSystemPrompt is the system prompt for structured generation
+const StructuredSystemPrompt = "You are a precise assistant. Return a single valid JSON object that matches the requested schema. No other text."
+
+// JSONSchema is a JSON Schema document describing an expected response.
+type JSONSchema map[string]interface{}
+
+// StructuredGenerator is implemented by providers that can constrain their
+// output to JSON with a native JSON mode.
+type StructuredGenerator interface {
+	// GenerateJSON returns a JSON document answering the prompt. Providers
+	// that accept schemas enforce it; others only guarantee valid JSON.
+	GenerateJSON(ctx context.Context, prompt string, schema JSONSchema) (string, error)
+}
+
+// GenerateJSON asks the provider for a JSON document, using its native JSON
+// mode when available and free-form generation otherwise. The caller is
+// responsible for validating the result.
+func GenerateJSON(ctx context.Context, p Provider, prompt string, schema JSONSchema) (string, error) {
+	if sg, ok := p.(StructuredGenerator); ok {
+		return sg.GenerateJSON(ctx, prompt, schema)
+	}
+	return p.GenerateDocumentation(ctx, "", prompt)
+}
+
+// withJSONSchema sets an OpenAI-style json_schema response format on a chat request.
+func withJSONSchema(req map[string]interface{}, schema JSONSchema) map[string]interface{} {
+	req["response_format"] = map[string]interface{}{
+		"type": "json_schema",
+		"json_schema": map[string]interface{}{
+			"name":   "response",
+			"schema": schema,
+		},
+	}
+	return req
+}
+
+// postChatJSON posts a chat completion request to an OpenAI-compatible API
+// and returns the message content.
+func postChatJSON(ctx context.Context, client *http.Client, url, apiKey string, req map[string]interface{}) (string, error) {
+	var result ChatCompletionResponse
+	if err := DoJSONPost(ctx, client, url, req, apiKey, &result); err != nil {
+		return "", err
+	}
+	if result.Error != nil {
+		return "", fmt.Errorf("API error: %s", result.Error.Message)
+	}
+	content := result.GetContent()
+	if content == "" {
+		return "", fmt.Errorf("empty response")
+	}
+	return content, nil
+}
+
+// GenerateJSON tries each provider in turn, like the other fallback methods.
+func (f *FallbackProvider) GenerateJSON(ctx context.Context, prompt string, schema JSONSchema) (string, error) {
+	f.mu.RLock()
+	startIdx := f.primary
+	f.mu.RUnlock()
+
+	var lastErr error
+	for i := 0; i < len(f.providers); i++ {
+		provider := f.providers[(startIdx+i)%len(f.providers)]
+
+		out, err := GenerateJSON(ctx, provider, prompt, schema)
+		if err == nil {
+			return out, nil
+		}
+		lastErr = err
+		log.Printf("[fallback] Provider %s failed for structured output: %v", provider.Name(), err)
+	}
+
+	return "", fmt.Errorf("all providers failed: %w", lastErr)
+}
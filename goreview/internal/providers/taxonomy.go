@@ -0,0 +1,184 @@
+package providers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OWASPCategories are the OWASP Top 10 (2021) categories by ID.
+var OWASPCategories = map[string]string{
+	"A01:2021": "Broken Access Control",
+	"A02:2021": "Cryptographic Failures",
+	"A03:2021": "Injection",
+	"A04:2021": "Insecure Design",
+	"A05:2021": "Security Misconfiguration",
+	"A06:2021": "Vulnerable and Outdated Components",
+	"A07:2021": "Identification and Authentication Failures",
+	"A08:2021": "Software and Data Integrity Failures",
+	"A09:2021": "Security Logging and Monitoring Failures",
+	"A10:2021": "Server-Side Request Forgery",
+}
+
+// CWE is a known weakness and the OWASP category it belongs to.
+type CWE struct {
+	Name  string
+	OWASP string
+}
+
+// KnownCWEs are the weaknesses security findings are classified with, by
+// ID. They cover the CWE Top 25 and the weaknesses the OWASP categories
+// list most often.
+var KnownCWEs = map[string]CWE{
+	"CWE-20":   {"Improper Input Validation", "A03:2021"},
+	"CWE-22":   {"Path Traversal", "A01:2021"},
+	"CWE-73":   {"External Control of File Name or Path", "A04:2021"},
+	"CWE-77":   {"Command Injection", "A03:2021"},
+	"CWE-78":   {"OS Command Injection", "A03:2021"},
+	"CWE-79":   {"Cross-site Scripting", "A03:2021"},
+	"CWE-89":   {"SQL Injection", "A03:2021"},
+	"CWE-90":   {"LDAP Injection", "A03:2021"},
+	"CWE-94":   {"Code Injection", "A03:2021"},
+	"CWE-113":  {"HTTP Response Splitting", "A03:2021"},
+	"CWE-116":  {"Improper Encoding or Escaping of Output", "A03:2021"},
+	"CWE-117":  {"Log Injection", "A09:2021"},
+	"CWE-190":  {"Integer Overflow", "A04:2021"},
+	"CWE-200":  {"Exposure of Sensitive Information", "A01:2021"},
+	"CWE-209":  {"Sensitive Information in Error Message", "A04:2021"},
+	"CWE-250":  {"Execution with Unnecessary Privileges", "A04:2021"},
+	"CWE-256":  {"Plaintext Storage of a Password", "A04:2021"},
+	"CWE-259":  {"Hard-coded Password", "A07:2021"},
+	"CWE-269":  {"Improper Privilege Management", "A04:2021"},
+	"CWE-276":  {"Incorrect Default Permissions", "A01:2021"},
+	"CWE-284":  {"Improper Access Control", "A01:2021"},
+	"CWE-285":  {"Improper Authorization", "A01:2021"},
+	"CWE-287":  {"Improper Authentication", "A07:2021"},
+	"CWE-295":  {"Improper Certificate Validation", "A07:2021"},
+	"CWE-306":  {"Missing Authentication for Critical Function", "A07:2021"},
+	"CWE-307":  {"Excessive Authentication Attempts", "A07:2021"},
+	"CWE-311":  {"Missing Encryption of Sensitive Data", "A02:2021"},
+	"CWE-319":  {"Cleartext Transmission of Sensitive Information", "A02:2021"},
+	"CWE-326":  {"Inadequate Encryption Strength", "A02:2021"},
+	"CWE-327":  {"Broken or Risky Cryptographic Algorithm", "A02:2021"},
+	"CWE-328":  {"Weak Hash", "A02:2021"},
+	"CWE-330":  {"Insufficiently Random Values", "A02:2021"},
+	"CWE-338":  {"Weak PRNG", "A02:2021"},
+	"CWE-345":  {"Insufficient Verification of Data Authenticity", "A08:2021"},
+	"CWE-352":  {"Cross-Site Request Forgery", "A01:2021"},
+	"CWE-362":  {"Race Condition", "A04:2021"},
+	"CWE-384":  {"Session Fixation", "A07:2021"},
+	"CWE-400":  {"Uncontrolled Resource Consumption", "A04:2021"},
+	"CWE-434":  {"Unrestricted Upload of Dangerous File", "A04:2021"},
+	"CWE-476":  {"NULL Pointer Dereference", ""},
+	"CWE-502":  {"Deserialization of Untrusted Data", "A08:2021"},
+	"CWE-521":  {"Weak Password Requirements", "A07:2021"},
+	"CWE-532":  {"Sensitive Information in Log File", "A09:2021"},
+	"CWE-601":  {"Open Redirect", "A01:2021"},
+	"CWE-611":  {"XML External Entity", "A05:2021"},
+	"CWE-613":  {"Insufficient Session Expiration", "A07:2021"},
+	"CWE-639":  {"Authorization Bypass Through User-Controlled Key", "A01:2021"},
+	"CWE-640":  {"Weak Password Recovery", "A07:2021"},
+	"CWE-665":  {"Improper Initialization", ""},
+	"CWE-732":  {"Incorrect Permission Assignment", "A01:2021"},
+	"CWE-759":  {"One-Way Hash without a Salt", "A02:2021"},
+	"CWE-770":  {"Allocation of Resources Without Limits", "A04:2021"},
+	"CWE-778":  {"Insufficient Logging", "A09:2021"},
+	"CWE-787":  {"Out-of-bounds Write", ""},
+	"CWE-798":  {"Hard-coded Credentials", "A07:2021"},
+	"CWE-829":  {"Inclusion of Functionality from Untrusted Source", "A08:2021"},
+	"CWE-862":  {"Missing Authorization", "A01:2021"},
+	"CWE-863":  {"Incorrect Authorization", "A01:2021"},
+	"CWE-915":  {"Mass Assignment", "A08:2021"},
+	"CWE-916":  {"Password Hash With Insufficient Effort", "A02:2021"},
+	"CWE-918":  {"Server-Side Request Forgery", "A10:2021"},
+	"CWE-937":  {"Using Components with Known Vulnerabilities", "A06:2021"},
+	"CWE-942":  {"Permissive CORS Policy", "A05:2021"},
+	"CWE-1004": {"Sensitive Cookie Without HttpOnly", "A05:2021"},
+	"CWE-1104": {"Use of Unmaintained Third Party Components", "A06:2021"},
+	"CWE-1321": {"Prototype Pollution", "A08:2021"},
+	"CWE-1333": {"Inefficient Regular Expression", "A04:2021"},
+}
+
+var (
+	cwePattern   = regexp.MustCompile(`(?i)^(?:cwe)?[\s:-]*(\d+)\b`)
+	owaspPattern = regexp.MustCompile(`(?i)^a(\d{1,2})(?::2021)?\b`)
+)
+
+// NormalizeCWE returns the ID of a known CWE written as "CWE-89", "cwe 89"
+// or "89", reporting whether it is known.
+func NormalizeCWE(s string) (string, bool) {
+	m := cwePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", false
+	}
+	id := "CWE-" + strings.TrimLeft(m[1], "0")
+	_, ok := KnownCWEs[id]
+	return id, ok
+}
+
+// NormalizeOWASP returns the ID of an OWASP Top 10 (2021) category written
+// as "A03:2021", "A3" or "A03:2021-Injection", reporting whether it is
+// known.
+func NormalizeOWASP(s string) (string, bool) {
+	m := owaspPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", false
+	}
+	n, _ := strconv.Atoi(m[1])
+	id := fmt.Sprintf("A%02d:2021", n)
+	_, ok := OWASPCategories[id]
+	return id, ok
+}
+
+// ClassifyIssues validates the CWE and OWASP classification of issues as
+// reported by the model. Unknown IDs are dropped rather than trusted, and a
+// known CWE fills in its OWASP category when the model left it out.
+func ClassifyIssues(issues []Issue) {
+	for i := range issues {
+		issue := &issues[i]
+		if issue.CWE != "" {
+			id, ok := NormalizeCWE(issue.CWE)
+			issue.CWE = ""
+			if ok {
+				issue.CWE = id
+			}
+		}
+		if issue.OWASP != "" {
+			id, ok := NormalizeOWASP(issue.OWASP)
+			issue.OWASP = ""
+			if ok {
+				issue.OWASP = id
+			}
+		}
+		if issue.OWASP == "" && issue.CWE != "" {
+			issue.OWASP = KnownCWEs[issue.CWE].OWASP
+		}
+	}
+}
+
+// taxonomyPrompt returns the instructions to classify security issues,
+// when security is one of the issue types.
+func taxonomyPrompt(types []IssueTypeInfo) string {
+	if len(types) > 0 {
+		security := false
+		for _, t := range types {
+			security = security || t.Name == string(IssueTypeSecurity)
+		}
+		if !security {
+			return ""
+		}
+	}
+	ids := make([]string, 0, len(OWASPCategories))
+	for id := range OWASPCategories {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	categories := make([]string, len(ids))
+	for i, id := range ids {
+		categories[i] = id + " " + OWASPCategories[id]
+	}
+	return "\nSECURITY TAXONOMY (on security issues, set \"cwe\" to the CWE ID and \"owasp\" to one of these OWASP Top 10 categories; leave both out when unsure):\n- " +
+		strings.Join(categories, "\n- ") + "\n"
+}
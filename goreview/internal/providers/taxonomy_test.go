@@ -0,0 +1,41 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyIssues(t *testing.T) {
+	issues := []Issue{
+		{CWE: "cwe-89"},
+		{CWE: "79", OWASP: "A3"},
+		{CWE: "CWE-99999", OWASP: "A03:2021-Injection"},
+		{CWE: "SQL injection", OWASP: "A11"},
+	}
+	ClassifyIssues(issues)
+
+	want := []struct{ cwe, owasp string }{
+		{"CWE-89", "A03:2021"}, // OWASP filled in from the CWE
+		{"CWE-79", "A03:2021"},
+		{"", "A03:2021"}, // Unknown CWE dropped
+		{"", ""},
+	}
+	for i, w := range want {
+		if issues[i].CWE != w.cwe || issues[i].OWASP != w.owasp {
+			t.Errorf("issue %d = %q, %q; want %q, %q", i, issues[i].CWE, issues[i].OWASP, w.cwe, w.owasp)
+		}
+	}
+}
+
+func TestReviewPromptTaxonomy(t *testing.T) {
+	prompt := BuildReviewPrompt(&ReviewRequest{Diff: "+x", FilePath: "a.go"})
+	if !strings.Contains(prompt, "SECURITY TAXONOMY") || !strings.Contains(prompt, `"cwe": "CWE-89"`) || !strings.Contains(prompt, "A10:2021 Server-Side Request Forgery") {
+		t.Errorf("prompt doesn't ask to classify security issues:\n%s", prompt)
+	}
+
+	// Without security among the issue types there is nothing to classify
+	prompt = BuildReviewPrompt(&ReviewRequest{Diff: "+x", FilePath: "a.go", IssueTypes: []IssueTypeInfo{{Name: "bug"}}})
+	if strings.Contains(prompt, "SECURITY TAXONOMY") || strings.Contains(prompt, `"cwe"`) {
+		t.Errorf("prompt asks to classify without security issues:\n%s", prompt)
+	}
+}
@@ -1,6 +1,9 @@
 package providers
 
-import "context"
+import (
+	"context"
+	"strings"
+)
 
 // Provider defines the interface for AI/LLM providers.
 type Provider interface {
@@ -34,6 +37,35 @@ type ReviewRequest struct {
 	Personality      string       `json:"personality,omitempty"`
 	Modes            []ReviewMode `json:"modes,omitempty"`
 	RootCauseTracing bool         `json:"root_cause_tracing,omitempty"`
+	// IssueTypes replaces the built-in issue type list in the prompt when set
+	IssueTypes []IssueTypeInfo `json:"issue_types,omitempty"`
+	// Focus lists regions of the file that static checks found suspicious,
+	// for the reviewer to examine first
+	Focus []string `json:"focus,omitempty"`
+	// Related holds neighboring files of the package, given as context for
+	// full-file reviews
+	Related string `json:"related,omitempty"`
+	// ChangeSummary lists the functions, signatures and dependencies the
+	// change adds, removes or modifies, computed locally from the code
+	ChangeSummary string `json:"change_summary,omitempty"`
+	// Intent is what the pull or merge request under review says about the
+	// change: its description, linked issues and review comments so far
+	Intent string `json:"intent,omitempty"`
+	// Instructions are mandatory guidelines added by the organization, such
+	// as through the instructions middleware
+	Instructions []string `json:"instructions,omitempty"`
+	// Conventions are the team's preferences learned from the fixes it
+	// accepted and rejected
+	Conventions []string `json:"conventions,omitempty"`
+	// Format tells how to read files that aren't plain source, like the
+	// code cells of a notebook or a template
+	Format string `json:"format,omitempty"`
+}
+
+// IssueTypeInfo describes an issue type the reviewer may report.
+type IssueTypeInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
 }
 
 // ReviewResponse contains the review results.
@@ -43,6 +75,23 @@ type ReviewResponse struct {
 	Score          int     `json:"score"` // 0-100
 	TokensUsed     int     `json:"tokens_used"`
 	ProcessingTime int64   `json:"processing_time_ms"`
+	// ParseFailures counts responses that were not valid JSON and were kept
+	// as a plain summary, losing any issues they described
+	ParseFailures int `json:"parse_failures,omitempty"`
+	// TruncatedChunks counts diff chunks that still exceeded the model's
+	// chunk budget after splitting, so the model may not have seen all of them
+	TruncatedChunks int `json:"truncated_chunks,omitempty"`
+	// Exchanges are the prompts sent and the raw answers received, one per
+	// request; they are never cached
+	Exchanges []Exchange `json:"-"`
+}
+
+// Exchange is a prompt exactly as sent to the model and its raw answer.
+type Exchange struct {
+	// System is the system message, for providers that send one
+	System   string `json:"system,omitempty"`
+	Prompt   string `json:"prompt"`
+	Response string `json:"response"`
 }
 
 // Issue represents a code review issue.
@@ -56,6 +105,35 @@ type Issue struct {
 	RuleID     string     `json:"rule_id,omitempty"`
 	FixedCode  string     `json:"fixed_code,omitempty"`
 	RootCause  *RootCause `json:"root_cause,omitempty"`
+	// Code is the offending code as cited by the reviewer, used to verify Location
+	Code string `json:"code,omitempty"`
+	// CWE is the weakness of a security issue, like CWE-89; only known IDs
+	// are kept
+	CWE string `json:"cwe,omitempty"`
+	// OWASP is the OWASP Top 10 category of a security issue, like A03:2021
+	OWASP string `json:"owasp,omitempty"`
+	// Transcript is the ID of the saved provider transcript the issue came
+	// from, when transcripts are saved
+	Transcript string `json:"transcript,omitempty"`
+	// Triage is the issue's triage in the history database, when review
+	// issues are recorded there
+	Triage *Triage `json:"triage,omitempty"`
+	// Calibration notes a severity adjusted, or worth adjusting, to how the
+	// team triaged earlier findings of the same rule
+	Calibration string `json:"calibration,omitempty"`
+}
+
+// Triage is the triage of an issue, kept across reviews.
+type Triage struct {
+	// IssueID is the history record to triage the issue by
+	IssueID int64 `json:"issue_id"`
+	// Status is open, acknowledged, wontfix or resolved
+	Status   string `json:"status"`
+	Assignee string `json:"assignee,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+	// Recurrence notes an issue found again after being acknowledged or
+	// marked won't fix, such as "previously acknowledged, was error"
+	Recurrence string `json:"recurrence,omitempty"`
 }
 
 // RootCause contains root cause analysis for an issue.
@@ -75,6 +153,9 @@ type Location struct {
 	EndLine   int    `json:"end_line"`
 	StartCol  int    `json:"start_col,omitempty"`
 	EndCol    int    `json:"end_col,omitempty"`
+	// Unverified is set when the cited code could not be found in the file,
+	// so the line numbers are the reviewer's unchecked guess.
+	Unverified bool `json:"unverified,omitempty"`
 }
 
 // IssueType categorizes the type of issue.
@@ -87,6 +168,10 @@ const (
 	IssueTypeStyle        IssueType = "style"
 	IssueTypeMaintenance  IssueType = "maintenance"
 	IssueTypeBestPractice IssueType = "best_practice"
+	// IssueTypeFlakyTest marks test code likely to pass or fail at random
+	IssueTypeFlakyTest IssueType = "flaky_test"
+	// IssueTypeTestGap marks behavior changes without matching test changes
+	IssueTypeTestGap IssueType = "test_gap"
 )
 
 // Severity indicates the importance of an issue.
@@ -98,3 +183,31 @@ const (
 	SeverityError    Severity = "error"
 	SeverityCritical Severity = "critical"
 )
+
+// severityRanks orders severities from least to most important
+var severityRanks = map[Severity]int{
+	SeverityInfo:     1,
+	SeverityWarning:  2,
+	SeverityError:    3,
+	SeverityCritical: 4,
+}
+
+// Rank orders severities from info (1) to critical (4); unknown severities rank 0.
+func (s Severity) Rank() int {
+	return severityRanks[s]
+}
+
+// ParseSeverity converts a configured severity name, reporting whether it is valid.
+func ParseSeverity(name string) (Severity, bool) {
+	s := Severity(strings.ToLower(strings.TrimSpace(name)))
+	_, ok := severityRanks[s]
+	return s, ok
+}
+
+// BuiltinIssueTypes lists the default issue types with their descriptions.
+var BuiltinIssueTypes = []IssueTypeInfo{
+	{Name: string(IssueTypeBug), Description: "incorrect behavior, crashes, logic errors"},
+	{Name: string(IssueTypeSecurity), Description: "vulnerabilities and unsafe handling of data or secrets"},
+	{Name: string(IssueTypePerformance), Description: "unnecessary work, allocations or blocking"},
+	{Name: string(IssueTypeStyle), Description: "formatting, naming and readability"},
+}
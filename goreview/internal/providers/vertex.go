@@ -0,0 +1,169 @@
+package providers
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// cloudPlatformScope is the OAuth scope of Vertex AI
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+	// jwtBearerGrant is the OAuth grant that trades a signed JWT for a token
+	jwtBearerGrant = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	// defaultTokenURI is Google's OAuth token endpoint
+	defaultTokenURI = "https://oauth2.googleapis.com/token"
+)
+
+// serviceAccount is a Google service account key file.
+type serviceAccount struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// vertexTokenSource gets OAuth access tokens for a service account, with
+// the JWT bearer grant, and reuses each until shortly before it expires.
+type vertexTokenSource struct {
+	account *serviceAccount
+	key     *rsa.PrivateKey
+	client  *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// newVertexTokenSource reads a service account key file.
+func newVertexTokenSource(path string, client *http.Client) (*vertexTokenSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading service account: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("parsing service account %s: %w", path, err)
+	}
+	if account.Type != "service_account" || account.ClientEmail == "" {
+		return nil, fmt.Errorf("%s is not a service account key", path)
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = defaultTokenURI
+	}
+	key, err := parsePrivateKey(account.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("service account %s: %w", path, err)
+	}
+	return &vertexTokenSource{account: &account, key: key, client: client}, nil
+}
+
+// parsePrivateKey parses a PEM RSA key, PKCS#8 as Google issues them or
+// PKCS#1.
+func parsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// Token returns a valid access token.
+func (s *vertexTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.token != "" && now.Before(s.expiry.Add(-time.Minute)) {
+		return s.token, nil
+	}
+
+	assertion, err := s.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{"grant_type": {jwtBearerGrant}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf(ErrCreateRequest, err)
+	}
+	req.Header.Set(HeaderContentType, "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching vertex access token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("fetching vertex access token: %w", &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(msg))})
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf(ErrDecodeResponse, err)
+	}
+	if tok.AccessToken == "" {
+		return "", fmt.Errorf("no access token in the token response")
+	}
+	s.token = tok.AccessToken
+	s.expiry = now.Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// assertion returns the signed JWT that asks for a token, valid for an hour.
+func (s *vertexTokenSource) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": s.account.PrivateKeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   s.account.ClientEmail,
+		"scope": cloudPlatformScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("signing token request: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
@@ -0,0 +1,195 @@
+package rag
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed packs/*.yaml
+var builtinPacks embed.FS
+
+// Pack is a knowledge pack: the known pitfalls of an API and how to use it
+// correctly, injected into reviews of files importing the API.
+type Pack struct {
+	Name        string    `yaml:"name" json:"name"`
+	Description string    `yaml:"description,omitempty" json:"description,omitempty"`
+	Languages   []string  `yaml:"languages,omitempty" json:"languages,omitempty"`
+	Imports     []string  `yaml:"imports" json:"imports"`
+	Pitfalls    []Pitfall `yaml:"pitfalls" json:"pitfalls"`
+	// Source is the file the pack was loaded from, or "builtin"
+	Source string `yaml:"-" json:"source"`
+}
+
+// Pitfall is a known misuse of an API.
+type Pitfall struct {
+	Title   string `yaml:"title" json:"title"`
+	Problem string `yaml:"problem" json:"problem"`
+	// Avoid and Prefer are optional snippets of the misuse and the correct
+	// usage
+	Avoid  string `yaml:"avoid,omitempty" json:"avoid,omitempty"`
+	Prefer string `yaml:"prefer,omitempty" json:"prefer,omitempty"`
+}
+
+// LoadPacks returns the built-in packs not excluded by name, and the packs
+// of the YAML files in dirs. A custom pack replaces the built-in pack of
+// the same name. Missing directories are skipped.
+func LoadPacks(dirs, exclude []string) ([]Pack, error) {
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+
+	byName := make(map[string]Pack)
+	entries, err := builtinPacks.ReadDir("packs")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := builtinPacks.ReadFile("packs/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		pack, err := parsePack(data, "builtin")
+		if err != nil {
+			return nil, fmt.Errorf("parsing built-in pack %s: %w", entry.Name(), err)
+		}
+		if !excluded[pack.Name] {
+			byName[pack.Name] = pack
+		}
+	}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading packs directory: %w", err)
+		}
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			data, err := os.ReadFile(path) // #nosec G304 - path from the configured packs directory
+			if err != nil {
+				return nil, err
+			}
+			pack, err := parsePack(data, path)
+			if err != nil {
+				return nil, fmt.Errorf("parsing pack %s: %w", path, err)
+			}
+			byName[pack.Name] = pack
+		}
+	}
+
+	packs := make([]Pack, 0, len(byName))
+	for _, pack := range byName {
+		packs = append(packs, pack)
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Name < packs[j].Name })
+	return packs, nil
+}
+
+func parsePack(data []byte, source string) (Pack, error) {
+	var pack Pack
+	if err := yaml.Unmarshal(data, &pack); err != nil {
+		return Pack{}, err
+	}
+	switch {
+	case pack.Name == "":
+		return Pack{}, errors.New("pack has no name")
+	case len(pack.Imports) == 0:
+		return Pack{}, fmt.Errorf("pack %s has no imports", pack.Name)
+	case len(pack.Pitfalls) == 0:
+		return Pack{}, fmt.Errorf("pack %s has no pitfalls", pack.Name)
+	}
+	pack.Source = source
+	return pack, nil
+}
+
+// MatchPacks returns the packs for a file in the language importing the
+// modules: those with one of its imports, exactly or as a parent, so
+// "database/sql" also matches "database/sql/driver".
+func MatchPacks(packs []Pack, language string, imports []string) []Pack {
+	var matched []Pack
+	for _, pack := range packs {
+		if len(pack.Languages) > 0 && !containsFold(pack.Languages, language) {
+			continue
+		}
+		if importsAny(imports, pack.Imports) {
+			matched = append(matched, pack)
+		}
+	}
+	return matched
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func importsAny(imports, wanted []string) bool {
+	for _, imp := range imports {
+		for _, want := range wanted {
+			if imp == want || strings.HasPrefix(imp, want+"/") || strings.HasPrefix(imp, want+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FormatPacks formats packs for inclusion in an LLM prompt. Pitfalls that
+// don't fit in maxLength are dropped, along with the packs after them.
+func FormatPacks(packs []Pack, maxLength int) string {
+	var sb strings.Builder
+	for _, pack := range packs {
+		section := "### " + pack.Name + "\n"
+		if sb.Len() > 0 {
+			section = "\n" + section
+		}
+		fits := 0
+		for _, p := range pack.Pitfalls {
+			formatted := formatPitfall(p)
+			if sb.Len()+len(section)+len(formatted) > maxLength {
+				break
+			}
+			section += formatted
+			fits++
+		}
+		if fits == 0 {
+			break
+		}
+		sb.WriteString(section)
+		if fits < len(pack.Pitfalls) {
+			break
+		}
+	}
+	return sb.String()
+}
+
+func formatPitfall(p Pitfall) string {
+	var sb strings.Builder
+	sb.WriteString("- " + p.Title + ": " + strings.TrimSpace(p.Problem) + "\n")
+	if p.Avoid != "" {
+		sb.WriteString("  Avoid:\n```\n" + strings.TrimRight(p.Avoid, "\n") + "\n```\n")
+	}
+	if p.Prefer != "" {
+		sb.WriteString("  Prefer:\n```\n" + strings.TrimRight(p.Prefer, "\n") + "\n```\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,73 @@
+package rag
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPacks(t *testing.T) {
+	packs, err := LoadPacks(nil, nil)
+	if err != nil {
+		t.Fatalf("LoadPacks() error = %v", err)
+	}
+	names := make([]string, len(packs))
+	for i, p := range packs {
+		names[i] = p.Name
+		if p.Source != "builtin" || len(p.Pitfalls) == 0 {
+			t.Errorf("pack %s = %+v", p.Name, p)
+		}
+	}
+	if got := strings.Join(names, ","); got != "database/sql,net/http,react-hooks,requests" {
+		t.Errorf("built-in packs = %s", got)
+	}
+
+	// Custom packs replace built-in ones by name, and exclusions drop them
+	dir := t.TempDir()
+	custom := "name: net/http\nimports: [net/http]\npitfalls:\n  - title: Use the shared client\n    problem: Build requests with httpx.Client.\n"
+	if err := os.WriteFile(filepath.Join(dir, "http.yaml"), []byte(custom), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	packs, err = LoadPacks([]string{dir, filepath.Join(dir, "missing")}, []string{"requests"})
+	if err != nil {
+		t.Fatalf("LoadPacks() error = %v", err)
+	}
+	if len(packs) != 3 || packs[1].Name != "net/http" || packs[1].Source != filepath.Join(dir, "http.yaml") {
+		t.Errorf("packs = %+v", packs)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bad.yml"), []byte("name: empty\nimports: [x]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPacks([]string{dir}, nil); err == nil || !strings.Contains(err.Error(), "no pitfalls") {
+		t.Errorf("LoadPacks() error = %v, want a pack without pitfalls rejected", err)
+	}
+}
+
+func TestMatchAndFormatPacks(t *testing.T) {
+	packs := []Pack{
+		{Name: "database/sql", Languages: []string{"go"}, Imports: []string{"database/sql"}, Pitfalls: []Pitfall{
+			{Title: "Rows left open", Problem: "Close rows.", Prefer: "defer rows.Close()\n"},
+		}},
+		{Name: "react-hooks", Languages: []string{"javascript", "typescript"}, Imports: []string{"react"}, Pitfalls: []Pitfall{
+			{Title: "Hooks called conditionally", Problem: "Call hooks at the top level."},
+		}},
+	}
+
+	matched := MatchPacks(packs, "go", []string{"context", "database/sql/driver"})
+	if len(matched) != 1 || matched[0].Name != "database/sql" {
+		t.Fatalf("MatchPacks() = %+v, want database/sql", matched)
+	}
+	if got := MatchPacks(packs, "python", []string{"react"}); len(got) != 0 {
+		t.Errorf("MatchPacks() for another language = %+v", got)
+	}
+
+	want := "### database/sql\n- Rows left open: Close rows.\n  Prefer:\n```\ndefer rows.Close()\n```\n"
+	if got := FormatPacks(matched, 1000); got != want {
+		t.Errorf("FormatPacks() = %q, want %q", got, want)
+	}
+	if got := FormatPacks(packs, len(want)+20); strings.Contains(got, "react") || !strings.HasPrefix(got, "### database/sql") {
+		t.Errorf("FormatPacks() over the limit = %q", got)
+	}
+}
@@ -0,0 +1,28 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+// CodeActionsReporter writes the fixes of a review as text edits, see
+// reviewtypes.CodeActions.
+type CodeActionsReporter struct{}
+
+func (r *CodeActionsReporter) Format() string { return "codeactions" }
+
+func (r *CodeActionsReporter) Generate(result *reviewtypes.Result) (string, error) {
+	data, err := json.MarshalIndent(reviewtypes.NewCodeActions(result), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (r *CodeActionsReporter) Write(result *reviewtypes.Result, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(reviewtypes.NewCodeActions(result))
+}
@@ -0,0 +1,411 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+// Evidence is a review run as shown to auditors: what was reviewed, by whom
+// and when, the gates applied, the outcome, and digests tying the evidence
+// to the review's report.
+type Evidence struct {
+	Title      string
+	Repository string
+	Remote     string
+	Branch     string
+	Commit     string
+	// Author is the author of the reviewed commit
+	Author string
+	// RunBy is who ran the review
+	RunBy        string
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	ConfigDigest string
+	// GeneratedAt is when the evidence was rendered
+	GeneratedAt time.Time
+	Result      *reviewtypes.Result
+	Signatures  []Signature
+	// Approvers are the people expected to sign the review off
+	Approvers []string
+}
+
+// Signature is a digest or keyed signature of a file backing the evidence.
+type Signature struct {
+	// Subject is what was signed, like the report's file name
+	Subject string
+	// Algorithm is SHA-256 or HMAC-SHA256
+	Algorithm string
+	Value     string
+}
+
+// Outcome is FAILED when a gate failed, INCOMPLETE when files couldn't be
+// reviewed, and PASSED otherwise.
+func (e *Evidence) Outcome() string {
+	if e.Result == nil {
+		return "INCOMPLETE"
+	}
+	for _, g := range e.Result.Gates {
+		if !g.Passed {
+			return "FAILED"
+		}
+	}
+	for _, f := range e.Result.Files {
+		if f.Error != "" {
+			return "INCOMPLETE"
+		}
+	}
+	return "PASSED"
+}
+
+// ComplianceFormats are the formats compliance evidence renders to.
+var ComplianceFormats = []string{"html", "pdf", "markdown"}
+
+// WriteCompliance renders evidence in the given format: html, pdf or
+// markdown.
+func WriteCompliance(e *Evidence, format string, w io.Writer) error {
+	sections := evidenceSections(e)
+	switch format {
+	case "html":
+		graph := false
+		for _, s := range sections {
+			graph = graph || s.Graph != ""
+		}
+		return complianceHTML.Execute(w, struct {
+			Title    string
+			Outcome  string
+			Sections []evidenceSection
+			Graph    bool
+		}{e.Title, e.Outcome(), sections, graph})
+	case "markdown", "md":
+		_, err := io.WriteString(w, complianceMarkdown(e.Title, sections))
+		return err
+	case "pdf":
+		return writePDF(w, e.Title, complianceText(e.Title, sections))
+	default:
+		return fmt.Errorf("unknown compliance format: %s (use %s)", format, strings.Join(ComplianceFormats, ", "))
+	}
+}
+
+// evidenceSection is a titled part of the evidence: fields, a table, or a
+// note when there is nothing to show.
+type evidenceSection struct {
+	Title  string
+	Fields [][2]string
+	Header []string
+	Rows   [][]string
+	Note   string
+	// Graph is a Mermaid graph drawn after the table, in HTML and markdown
+	Graph string
+}
+
+func evidenceSections(e *Evidence) []evidenceSection {
+	result := e.Result
+	if result == nil {
+		result = &reviewtypes.Result{}
+	}
+	env := result.Environment
+	if env == nil {
+		env = &reviewtypes.Environment{}
+	}
+	commit := e.Commit
+	if commit == "" {
+		commit = env.CommitSHA
+	}
+
+	review := evidenceSection{Title: "Review", Fields: nonEmptyFields([][2]string{
+		{"Repository", e.Repository},
+		{"Remote", e.Remote},
+		{"Branch", e.Branch},
+		{"Commit", commit},
+		{"Commit author", e.Author},
+		{"Run by", e.RunBy},
+		{"Started", formatEvidenceTime(e.StartedAt)},
+		{"Finished", formatEvidenceTime(e.FinishedAt)},
+		{"Duration", result.Duration.Round(time.Millisecond).String()},
+		{"goreview version", env.Version},
+		{"Provider", strings.TrimSuffix(env.Provider+" / "+env.Model, " / ")},
+		{"Rule preset", env.Preset},
+		{"Rules hash", env.RulesHash},
+		{"Prompt hash", env.PromptHash},
+		{"Configuration digest", e.ConfigDigest},
+	})}
+
+	outcome := evidenceSection{Title: "Outcome", Fields: [][2]string{
+		{"Result", e.Outcome()},
+		{"Files reviewed", fmt.Sprintf("%d (+%d/-%d lines)", len(result.Files), result.Stats.Additions, result.Stats.Deletions)},
+		{"Issues", fmt.Sprintf("%d", result.TotalIssues)},
+	}}
+	counts := severityCounts(result)
+	for _, sev := range []string{reviewtypes.SeverityCritical, reviewtypes.SeverityError, reviewtypes.SeverityWarning, reviewtypes.SeverityInfo} {
+		outcome.Fields = append(outcome.Fields, [2]string{strings.ToUpper(sev[:1]) + sev[1:] + " issues", fmt.Sprintf("%d", counts[sev])})
+	}
+	if q := result.Quality; q != nil {
+		quality := fmt.Sprintf("%d/100", q.Score)
+		if q.Degraded {
+			quality += " (degraded)"
+		}
+		outcome.Fields = append(outcome.Fields, [2]string{"Review quality", quality})
+	}
+
+	gates := evidenceSection{Title: "Gates", Header: []string{"Gate", "Condition", "Result"}, Note: "No gates were applied."}
+	for _, g := range result.Gates {
+		status := "passed"
+		switch {
+		case g.Error != "":
+			status = "error: " + g.Error
+		case !g.Passed:
+			status = "failed"
+		}
+		gates.Rows = append(gates.Rows, []string{g.Name, g.Expr, status})
+	}
+
+	files := evidenceSection{Title: "Reviewed files", Header: []string{"File", "Issues", "Status"}, Note: "No files were reviewed."}
+	for _, f := range result.Files {
+		status, issues := "reviewed", 0
+		switch {
+		case f.Error != "":
+			status = "not reviewed: " + f.Error
+		case f.Cached:
+			status = "reviewed (cached)"
+		}
+		if f.Response != nil {
+			issues = len(f.Response.Issues)
+		}
+		files.Rows = append(files.Rows, []string{f.File, fmt.Sprintf("%d", issues), status})
+	}
+
+	findings := evidenceSection{Title: "Findings", Header: []string{"Severity", "Location", "Type", "Finding", "Triage"}, Note: "No issues were found."}
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			location := f.File
+			if issue.Location != nil && issue.Location.StartLine > 0 {
+				location = fmt.Sprintf("%s:%d", f.File, issue.Location.StartLine)
+			}
+			kind := issue.Type
+			if issue.CWE != "" {
+				kind += " (" + issue.CWE + ")"
+			}
+			triage := "open"
+			if issue.Triage != nil {
+				triage = issue.Triage.Status
+			}
+			findings.Rows = append(findings.Rows, []string{issue.Severity, location, kind, issue.Message, triage})
+		}
+	}
+	sort.SliceStable(findings.Rows, func(i, j int) bool {
+		return severityRank(findings.Rows[i][0]) > severityRank(findings.Rows[j][0])
+	})
+
+	sections := []evidenceSection{review, outcome, gates, files, findings}
+	if causes := rootCauses(result); causes != nil {
+		sections = append(sections, *causes)
+	}
+
+	signatures := evidenceSection{Title: "Signatures", Header: []string{"Subject", "Algorithm", "Value"}, Note: "No files were signed."}
+	for _, s := range e.Signatures {
+		signatures.Rows = append(signatures.Rows, []string{s.Subject, s.Algorithm, s.Value})
+	}
+
+	approval := evidenceSection{Title: "Approval", Header: []string{"Name", "Signature", "Date"}}
+	approvers := e.Approvers
+	if len(approvers) == 0 {
+		approvers = []string{""}
+	}
+	for _, name := range approvers {
+		approval.Rows = append(approval.Rows, []string{name, "", ""})
+	}
+
+	return append(sections, signatures, approval,
+		evidenceSection{Title: "Generated", Fields: nonEmptyFields([][2]string{{"Generated at", formatEvidenceTime(e.GeneratedAt)}})})
+}
+
+// rootCauses returns the root causes of a traced review, with their graph,
+// or nil when the review wasn't traced.
+func rootCauses(result *reviewtypes.Result) *evidenceSection {
+	g := CauseGraph(result)
+	if g.Empty() {
+		return nil
+	}
+	s := &evidenceSection{Title: "Root causes", Header: []string{"Root cause", "Origin", "Finding"}, Graph: g.Mermaid()}
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			rc := issue.RootCause
+			if rc == nil {
+				continue
+			}
+			origin := rc.OriginFile
+			if origin == "" {
+				origin = f.File
+			}
+			if rc.OriginLine > 0 {
+				origin = fmt.Sprintf("%s:%d", origin, rc.OriginLine)
+			}
+			location := f.File
+			if issue.Location != nil && issue.Location.StartLine > 0 {
+				location = fmt.Sprintf("%s:%d", f.File, issue.Location.StartLine)
+			}
+			s.Rows = append(s.Rows, []string{rc.Description, origin, location + " " + issue.Message})
+		}
+	}
+	sort.SliceStable(s.Rows, func(i, j int) bool { return s.Rows[i][1] < s.Rows[j][1] })
+	return s
+}
+
+func nonEmptyFields(fields [][2]string) [][2]string {
+	out := fields[:0]
+	for _, f := range fields {
+		if f[1] != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func formatEvidenceTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04:05 MST")
+}
+
+func severityCounts(result *reviewtypes.Result) map[string]int {
+	counts := make(map[string]int)
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			counts[issue.Severity]++
+		}
+	}
+	return counts
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case reviewtypes.SeverityCritical:
+		return 4
+	case reviewtypes.SeverityError:
+		return 3
+	case reviewtypes.SeverityWarning:
+		return 2
+	case reviewtypes.SeverityInfo:
+		return 1
+	}
+	return 0
+}
+
+func complianceMarkdown(title string, sections []evidenceSection) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	for _, s := range sections {
+		fmt.Fprintf(&sb, "## %s\n\n", s.Title)
+		for _, f := range s.Fields {
+			fmt.Fprintf(&sb, "- **%s:** %s\n", f[0], f[1])
+		}
+		if len(s.Fields) > 0 {
+			sb.WriteString("\n")
+		}
+		if s.Header == nil {
+			continue
+		}
+		if len(s.Rows) == 0 {
+			fmt.Fprintf(&sb, "%s\n\n", s.Note)
+			continue
+		}
+		fmt.Fprintf(&sb, "| %s |\n|%s\n", strings.Join(s.Header, " | "), strings.Repeat("---|", len(s.Header)))
+		for _, row := range s.Rows {
+			cells := make([]string, len(row))
+			for i, c := range row {
+				cells[i] = strings.ReplaceAll(strings.ReplaceAll(c, "|", "\\|"), "\n", " ")
+			}
+			fmt.Fprintf(&sb, "| %s |\n", strings.Join(cells, " | "))
+		}
+		sb.WriteString("\n")
+		if s.Graph != "" {
+			fmt.Fprintf(&sb, "```mermaid\n%s```\n\n", s.Graph)
+		}
+	}
+	return sb.String()
+}
+
+// complianceText lays the evidence out as plain text lines, for the PDF.
+func complianceText(title string, sections []evidenceSection) []string {
+	lines := []string{title, strings.Repeat("=", len(title)), ""}
+	for _, s := range sections {
+		lines = append(lines, s.Title, strings.Repeat("-", len(s.Title)))
+		for _, f := range s.Fields {
+			lines = append(lines, fmt.Sprintf("%-22s %s", f[0]+":", f[1]))
+		}
+		if s.Header != nil {
+			if len(s.Rows) == 0 {
+				lines = append(lines, s.Note)
+			}
+			for _, row := range s.Rows {
+				parts := make([]string, 0, len(row))
+				for i, c := range row {
+					if c == "" && s.Title == "Approval" {
+						c = "____________________"
+					}
+					parts = append(parts, s.Header[i]+": "+strings.ReplaceAll(c, "\n", " "))
+				}
+				lines = append(lines, "- "+strings.Join(parts, "  "))
+			}
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+var complianceHTML = template.Must(template.New("compliance").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+h1 { border-bottom: 2px solid #222; padding-bottom: .3em; }
+h2 { margin-top: 1.6em; border-bottom: 1px solid #ccc; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: .35em .6em; text-align: left; vertical-align: top; }
+th { background: #f3f3f3; }
+dl { display: grid; grid-template-columns: 14em 1fr; gap: .3em 1em; }
+dt { font-weight: bold; }
+dd { margin: 0; word-break: break-all; }
+.outcome { display: inline-block; padding: .2em .8em; font-weight: bold; border: 2px solid; }
+.PASSED { color: #1a7f37; } .FAILED { color: #cf222e; } .INCOMPLETE { color: #9a6700; }
+.signature td { height: 2.5em; }
+@media print { body { margin: 0; max-width: none; } h2 { page-break-after: avoid; } tr { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="outcome {{.Outcome}}">{{.Outcome}}</p>
+{{range .Sections}}
+<h2>{{.Title}}</h2>
+{{if .Fields}}<dl>{{range .Fields}}<dt>{{index . 0}}</dt><dd>{{index . 1}}</dd>{{end}}</dl>{{end}}
+{{if .Header}}{{if .Rows}}<table{{if eq .Title "Approval"}} class="signature"{{end}}>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>{{else}}<p>{{.Note}}</p>{{end}}{{end}}
+{{if .Graph}}<pre class="mermaid">{{.Graph}}</pre>{{end}}
+{{end}}
+{{if .Graph}}<script type="module">
+import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@10/dist/mermaid.esm.min.mjs";
+mermaid.initialize({ startOnLoad: true, securityLevel: "strict" });
+</script>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+func TestEvidenceOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *reviewtypes.Result
+		want   string
+	}{
+		{"no result", nil, "INCOMPLETE"},
+		{"passed", &reviewtypes.Result{Gates: []reviewtypes.GateResult{{Name: "g", Passed: true}}}, "PASSED"},
+		{"gate failed", &reviewtypes.Result{Gates: []reviewtypes.GateResult{{Name: "g"}}}, "FAILED"},
+		{"file failed", &reviewtypes.Result{Files: []reviewtypes.FileResult{{File: "a.go", Error: "timeout"}}}, "INCOMPLETE"},
+	}
+	for _, tt := range tests {
+		if got := (&Evidence{Result: tt.result}).Outcome(); got != tt.want {
+			t.Errorf("%s: Outcome() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCompliance(t *testing.T) {
+	e := &Evidence{
+		Title:  "Review evidence",
+		Commit: "3f2a9c1",
+		Result: &reviewtypes.Result{TotalIssues: 1, Files: []reviewtypes.FileResult{{
+			File: "a.go",
+			Response: &reviewtypes.Response{Issues: []reviewtypes.Issue{{
+				ID: "1", Severity: reviewtypes.SeverityError, Message: "pipe | in\nmessage",
+				Location: &reviewtypes.Location{StartLine: 3},
+			}}},
+		}}},
+	}
+
+	var md bytes.Buffer
+	if err := WriteCompliance(e, "markdown", &md); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(md.String(), "# Review evidence\n") || !strings.Contains(md.String(), `pipe \| in message`) {
+		t.Errorf("markdown evidence = %s", md.String())
+	}
+
+	for _, format := range ComplianceFormats {
+		var buf bytes.Buffer
+		if err := WriteCompliance(e, format, &buf); err != nil || !strings.Contains(buf.String(), "3f2a9c1") {
+			t.Errorf("WriteCompliance(%s) error = %v, or the commit is missing", format, err)
+		}
+	}
+	if err := WriteCompliance(e, "docx", &bytes.Buffer{}); err == nil {
+		t.Error("WriteCompliance(docx) succeeded")
+	}
+}
@@ -0,0 +1,69 @@
+package report
+
+import (
+	"io"
+
+	"github.com/JNZader/goreview/goreview/internal/causegraph"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+// GraphReporter renders the root causes of a review (--trace) as a graph
+// linking issues to their causes across files, in Graphviz DOT or Mermaid.
+type GraphReporter struct {
+	// Mermaid selects Mermaid over DOT
+	Mermaid bool
+}
+
+func (r *GraphReporter) Format() string {
+	if r.Mermaid {
+		return "mermaid"
+	}
+	return "dot"
+}
+
+func (r *GraphReporter) Generate(result *reviewtypes.Result) (string, error) {
+	g := CauseGraph(result)
+	if r.Mermaid {
+		return g.Mermaid(), nil
+	}
+	return g.DOT(), nil
+}
+
+func (r *GraphReporter) Write(result *reviewtypes.Result, w io.Writer) error {
+	content, err := r.Generate(result)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+// CauseGraph returns the graph of the root causes of a review's issues.
+// It is empty when the review wasn't traced.
+func CauseGraph(result *reviewtypes.Result) *causegraph.Graph {
+	var issues []causegraph.Issue
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			if issue.RootCause == nil {
+				continue
+			}
+			gi := causegraph.Issue{ID: issue.ID, File: f.File, Severity: issue.Severity, Message: issue.Message}
+			if issue.Location != nil {
+				gi.Line = issue.Location.StartLine
+			}
+			rc := issue.RootCause
+			gi.Cause = &causegraph.Cause{
+				Description: rc.Description,
+				File:        rc.OriginFile,
+				Line:        rc.OriginLine,
+				Path:        rc.PropagationPath,
+				Related:     rc.RelatedIssues,
+			}
+			issues = append(issues, gi)
+		}
+	}
+	return causegraph.Build(issues)
+}
@@ -4,17 +4,17 @@ import (
 	"encoding/json"
 	"io"
 
-	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
 )
 
-// JSONReporter generates JSON reports.
+// JSONReporter generates JSON reports in the public result schema.
 type JSONReporter struct {
 	Indent bool
 }
 
 func (r *JSONReporter) Format() string { return "json" }
 
-func (r *JSONReporter) Generate(result *review.Result) (string, error) {
+func (r *JSONReporter) Generate(result *reviewtypes.Result) (string, error) {
 	var data []byte
 	var err error
 
@@ -30,7 +30,7 @@ func (r *JSONReporter) Generate(result *review.Result) (string, error) {
 	return string(data), nil
 }
 
-func (r *JSONReporter) Write(result *review.Result, w io.Writer) error {
+func (r *JSONReporter) Write(result *reviewtypes.Result, w io.Writer) error {
 	encoder := json.NewEncoder(w)
 	if r.Indent {
 		encoder.SetIndent("", "  ")
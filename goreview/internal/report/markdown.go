@@ -5,8 +5,8 @@ import (
 	"io"
 	"strings"
 
-	"github.com/JNZader/goreview/goreview/internal/providers"
-	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/internal/provenance"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
 )
 
 // MarkdownReporter generates Markdown reports.
@@ -14,25 +14,48 @@ type MarkdownReporter struct{}
 
 func (r *MarkdownReporter) Format() string { return "markdown" }
 
-func (r *MarkdownReporter) Generate(result *review.Result) (string, error) {
+func (r *MarkdownReporter) Generate(result *reviewtypes.Result) (string, error) {
 	var sb strings.Builder
 	_ = r.Write(result, &sb)
 	return sb.String(), nil
 }
 
-func (r *MarkdownReporter) Write(result *review.Result, w io.Writer) error {
+func (r *MarkdownReporter) Write(result *reviewtypes.Result, w io.Writer) error {
 	// Header
 	_, _ = fmt.Fprintf(w, "# Code Review Report\n\n")
 
 	// Summary
 	_, _ = fmt.Fprintf(w, "## Summary\n\n")
 	_, _ = fmt.Fprintf(w, "- **Files Reviewed:** %d\n", len(result.Files))
+	if len(result.Unreviewed) > 0 {
+		_, _ = fmt.Fprintf(w, "- **Files Not Reviewed:** %d\n", len(result.Unreviewed))
+	}
+	if result.Remainder != nil {
+		_, _ = fmt.Fprintf(w, "- **Files Summarized, Not Reviewed:** %d\n", len(result.Remainder.Files))
+	}
 	_, _ = fmt.Fprintf(w, "- **Total Issues:** %d\n", result.TotalIssues)
 	_, _ = fmt.Fprintf(w, "- **Duration:** %s\n", result.Duration)
+	if blocks := generatedBlocks(result); blocks > 0 {
+		_, _ = fmt.Fprintf(w, "- **Generated Blocks:** %d\n", blocks)
+	}
+	if result.Effort != nil {
+		_, _ = fmt.Fprintf(w, "- **Review Effort:** ~%d min\n", result.Effort.Minutes)
+	}
 	_, _ = fmt.Fprintf(w, "\n")
 
+	r.writeUnreviewed(w, result.Unreviewed)
+	r.writeRemainder(w, result.Remainder)
+	r.writeGates(w, result.Gates)
+	r.writeThemes(w, result.Themes)
+	r.writeReviewOrder(w, result.Effort)
+	r.writeScope(w, result.Scope)
+	r.writeGuarded(w, result.Files)
+	r.writeApproved(w, result.Files)
+
 	if result.TotalIssues == 0 {
 		_, _ = fmt.Fprintf(w, "No issues found.\n\n")
+		r.writeFiltered(w, result.Filtered)
+		r.writeEnvironment(w, result.Environment)
 		return nil
 	}
 
@@ -40,9 +63,9 @@ func (r *MarkdownReporter) Write(result *review.Result, w io.Writer) error {
 	_, _ = fmt.Fprintf(w, "## Issues\n\n")
 
 	for _, file := range result.Files {
-		if file.Error != nil {
+		if file.Error != "" {
 			_, _ = fmt.Fprintf(w, "### %s\n\n", file.File)
-			_, _ = fmt.Fprintf(w, "Error: %v\n\n", file.Error)
+			_, _ = fmt.Fprintf(w, "Error: %s\n\n", file.Error)
 			continue
 		}
 
@@ -52,19 +75,239 @@ func (r *MarkdownReporter) Write(result *review.Result, w io.Writer) error {
 
 		_, _ = fmt.Fprintf(w, "### %s\n\n", file.File)
 
-		if file.Cached {
+		if file.Normalized {
+			_, _ = fmt.Fprintf(w, "_Cached result (cache hit, normalized)_\n\n")
+		} else if file.Cached {
 			_, _ = fmt.Fprintf(w, "_Cached result_\n\n")
 		}
 
+		if file.ReusedHunks > 0 {
+			_, _ = fmt.Fprintf(w, "_%d unchanged hunk(s) carried over from the previous review_\n\n", file.ReusedHunks)
+		}
+
+		if file.Protected != "" {
+			_, _ = fmt.Fprintf(w, "_Protected path: %s_\n\n", file.Protected)
+		}
+
+		if len(file.RulesInScope) > 0 {
+			_, _ = fmt.Fprintf(w, "_Rules in scope: %s_\n\n", strings.Join(file.RulesInScope, ", "))
+		}
+
+		if len(file.Generated) > 0 {
+			blocks := make([]string, len(file.Generated))
+			for i, b := range file.Generated {
+				blocks[i] = provenance.Block(b).Describe()
+			}
+			_, _ = fmt.Fprintf(w, "_Generated code: %s_\n\n", strings.Join(blocks, ", "))
+		}
+
 		for _, issue := range file.Response.Issues {
 			r.writeIssue(w, issue)
 		}
 	}
 
+	r.writeIssueTypes(w, result)
+	r.writeFiltered(w, result.Filtered)
+	r.writeEnvironment(w, result.Environment)
 	return nil
 }
 
-func (r *MarkdownReporter) writeIssue(w io.Writer, issue providers.Issue) {
+// writeEnvironment writes the settings the review ran with, so it can be reproduced.
+func (r *MarkdownReporter) writeEnvironment(w io.Writer, env *reviewtypes.Environment) {
+	if env == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "## Reproducibility\n\n")
+	_, _ = fmt.Fprintf(w, "| Setting | Value |\n|---------|-------|\n")
+	rows := [][2]string{
+		{"goreview", env.Version},
+		{"Provider", env.Provider},
+		{"Model", env.Model},
+		{"Temperature", fmt.Sprintf("%g", env.Temperature)},
+		{"Preset", env.Preset},
+		{"Rules hash", env.RulesHash},
+		{"Prompt hash", env.PromptHash},
+		{"Commit", env.CommitSHA},
+	}
+	for _, row := range rows {
+		if row[1] != "" {
+			_, _ = fmt.Fprintf(w, "| %s | `%s` |\n", row[0], row[1])
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
+// writeReviewOrder writes the suggested order to review the files in, with
+// the estimated effort of each.
+func (r *MarkdownReporter) writeReviewOrder(w io.Writer, effort *reviewtypes.Effort) {
+	if effort == nil || len(effort.Files) < 2 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "## Suggested Review Order\n\n")
+	_, _ = fmt.Fprintf(w, "| # | File | Effort | Lines | Issues |\n")
+	_, _ = fmt.Fprintf(w, "|---|------|--------|-------|--------|\n")
+	for i, f := range effort.Files {
+		issues := fmt.Sprintf("%d", f.Issues)
+		if f.Severity != "" {
+			issues += " (" + f.Severity + ")"
+		}
+		_, _ = fmt.Fprintf(w, "| %d | %s | ~%d min | %d | %s |\n", i+1, f.File, f.Minutes, f.Lines, issues)
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
+// writeScope writes how to split unrelated changes out of the change.
+func (r *MarkdownReporter) writeScope(w io.Writer, scope *reviewtypes.Scope) {
+	if scope == nil || len(scope.Suggestions) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "## Change Scope\n\n")
+	_, _ = fmt.Fprintf(w, "This change bundles unrelated changes; consider splitting them out:\n\n")
+	for _, s := range scope.Suggestions {
+		_, _ = fmt.Fprintf(w, "- %s\n", s)
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
+// writeGuarded writes the files a guardrail summarized or skipped instead
+// of reviewing, with their summaries.
+func (r *MarkdownReporter) writeGuarded(w io.Writer, files []reviewtypes.FileResult) {
+	header := false
+	for _, f := range files {
+		g := f.Guardrail
+		if g == nil {
+			continue
+		}
+		if !header {
+			_, _ = fmt.Fprintf(w, "## Summarized Files\n\n")
+			_, _ = fmt.Fprintf(w, "Not reviewed in detail to keep the review focused:\n\n")
+			header = true
+		}
+		_, _ = fmt.Fprintf(w, "- **%s** (%s)", f.File, g.Detail)
+		if g.Summary != "" {
+			_, _ = fmt.Fprintf(w, ": %s", g.Summary)
+		}
+		_, _ = fmt.Fprintf(w, "\n")
+	}
+	if header {
+		_, _ = fmt.Fprintf(w, "\n")
+	}
+}
+
+// writeApproved writes the files auto-approved as trivial changes.
+func (r *MarkdownReporter) writeApproved(w io.Writer, files []reviewtypes.FileResult) {
+	header := false
+	for _, f := range files {
+		if f.Trivial == nil {
+			continue
+		}
+		if !header {
+			_, _ = fmt.Fprintf(w, "## Auto-approved Files\n\n")
+			_, _ = fmt.Fprintf(w, "Trivial changes, approved without a review by the model:\n\n")
+			header = true
+		}
+		_, _ = fmt.Fprintf(w, "- **%s** (%s)\n", f.File, f.Trivial.Detail)
+	}
+	if header {
+		_, _ = fmt.Fprintf(w, "\n")
+	}
+}
+
+// writeFiltered writes the changed files left out of the review and why.
+func (r *MarkdownReporter) writeFiltered(w io.Writer, filtered []reviewtypes.FilteredFile) {
+	if len(filtered) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "## Filtered Files\n\n")
+	_, _ = fmt.Fprintf(w, "| File | Reason | Pattern |\n|------|--------|---------|\n")
+	for _, f := range filtered {
+		pattern := ""
+		if f.Pattern != "" {
+			pattern = "`" + f.Pattern + "`"
+		}
+		_, _ = fmt.Fprintf(w, "| %s | %s | %s |\n", f.File, f.Reason, pattern)
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
+// writeUnreviewed lists the files left unreviewed, so nobody takes the
+// report for a complete review.
+func (r *MarkdownReporter) writeUnreviewed(w io.Writer, files []reviewtypes.UnreviewedFile) {
+	if len(files) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "## Not Reviewed\n\n")
+	_, _ = fmt.Fprintf(w, "> **Warning:** these files were not reviewed. Review them by hand, or rerun without `--time-budget` the ones that ran out of time.\n\n")
+	_, _ = fmt.Fprintf(w, "| File | Risk | Reason |\n|------|------|--------|\n")
+	for _, f := range files {
+		_, _ = fmt.Fprintf(w, "| %s | %d | %s |\n", f.File, f.Risk, unreviewedReason(f.Reason))
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
+// writeRemainder describes the files over the token budget, flagged as
+// summarized so the summary isn't taken for a review.
+func (r *MarkdownReporter) writeRemainder(w io.Writer, rem *reviewtypes.Remainder) {
+	if rem == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "## Summarized, Not Reviewed\n\n")
+	_, _ = fmt.Fprintf(w, "> **Warning:** the change exceeds the token budget (~%d tokens over a budget of %d). "+
+		"These lower-priority files were summarized, not reviewed. Review them by hand or raise `--token-budget`.\n\n", rem.Tokens, rem.Budget)
+	_, _ = fmt.Fprintf(w, "%s\n\n", rem.Summary)
+	for _, f := range rem.Files {
+		_, _ = fmt.Fprintf(w, "- `%s` (+%d -%d)\n", f.File, f.Additions, f.Deletions)
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
+// unreviewedReason explains why a file was left unreviewed.
+func unreviewedReason(reason string) string {
+	switch reason {
+	case "time_budget":
+		return "time budget ran out"
+	case "safety_block":
+		return "blocked by the provider's safety filters"
+	}
+	return reason
+}
+
+// writeThemes sums up the issues in groups of related ones, so reviews with
+// many findings can be taken in before reading them file by file.
+func (r *MarkdownReporter) writeThemes(w io.Writer, themes []reviewtypes.Theme) {
+	if len(themes) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "## Themes\n\n")
+	for _, t := range themes {
+		_, _ = fmt.Fprintf(w, "### %s %s\n\n", r.severityIcon(t.Severity), t.Title)
+		_, _ = fmt.Fprintf(w, "%d issues in %d file(s). For example:\n\n", t.Issues, t.Files)
+		for _, e := range t.Examples {
+			location := e.File
+			if e.Line > 0 {
+				location = fmt.Sprintf("%s:%d", e.File, e.Line)
+			}
+			_, _ = fmt.Fprintf(w, "- `%s`: %s\n", location, e.Message)
+		}
+		_, _ = fmt.Fprintf(w, "\n")
+	}
+}
+
+// writeIssueTypes writes a legend for the custom issue types used in the report.
+func (r *MarkdownReporter) writeIssueTypes(w io.Writer, result *reviewtypes.Result) {
+	used := usedIssueTypes(result)
+	if len(used) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "## Issue Types\n\n")
+	for _, t := range used {
+		_, _ = fmt.Fprintf(w, "- **%s**: %s\n", t.Name, t.Description)
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
+func (r *MarkdownReporter) writeIssue(w io.Writer, issue reviewtypes.Issue) {
 	// Severity icon
 	icon := r.severityIcon(issue.Severity)
 
@@ -83,21 +326,86 @@ func (r *MarkdownReporter) writeIssue(w io.Writer, issue providers.Issue) {
 	}
 
 	if issue.FixedCode != "" {
-		_, _ = fmt.Fprintf(w, "**Suggested Fix:**\n```\n%s\n```\n\n", issue.FixedCode)
+		// A fix for verified lines is a suggestion block, so pasting it into a
+		// GitHub review comment on those lines makes it applicable
+		fence := "```"
+		if loc := issue.Location; loc != nil && loc.StartLine > 0 && !loc.Unverified {
+			fence = "```suggestion"
+		}
+		_, _ = fmt.Fprintf(w, "**Suggested Fix:**\n%s\n%s\n```\n\n", fence, strings.TrimRight(issue.FixedCode, "\n"))
+	}
+
+	if issue.CWE != "" || issue.OWASP != "" {
+		classification := []string{}
+		for _, id := range []string{issue.CWE, issue.OWASP} {
+			if id != "" {
+				classification = append(classification, id)
+			}
+		}
+		_, _ = fmt.Fprintf(w, "**Classification:** %s\n\n", strings.Join(classification, ", "))
+	}
+
+	if issue.Transcript != "" {
+		_, _ = fmt.Fprintf(w, "**Transcript:** `%s`\n\n", issue.Transcript)
+	}
+
+	if issue.Calibration != "" {
+		_, _ = fmt.Fprintf(w, "**Calibration:** %s\n\n", issue.Calibration)
+	}
+
+	if t := issue.Triage; t != nil {
+		_, _ = fmt.Fprintf(w, "**Triage:** %s (#%d)", t.Status, t.IssueID)
+		if t.Assignee != "" {
+			_, _ = fmt.Fprintf(w, ", assigned to %s", t.Assignee)
+		}
+		if t.Recurrence != "" {
+			_, _ = fmt.Fprintf(w, " (%s)", t.Recurrence)
+		}
+		_, _ = fmt.Fprintf(w, "\n\n")
 	}
 
 	_, _ = fmt.Fprintf(w, "---\n\n")
 }
 
-func (r *MarkdownReporter) severityIcon(severity providers.Severity) string {
+func (r *MarkdownReporter) severityIcon(severity string) string {
 	switch severity {
-	case providers.SeverityCritical:
+	case reviewtypes.SeverityCritical:
 		return "[CRITICAL]"
-	case providers.SeverityError:
+	case reviewtypes.SeverityError:
 		return "[ERROR]"
-	case providers.SeverityWarning:
+	case reviewtypes.SeverityWarning:
 		return "[WARNING]"
 	default:
 		return "[INFO]"
 	}
 }
+
+// generatedBlocks counts the blocks labeled as generated across all files.
+func generatedBlocks(result *reviewtypes.Result) int {
+	n := 0
+	for _, file := range result.Files {
+		n += len(file.Generated)
+	}
+	return n
+}
+
+// writeGates writes the outcome of each CI gate.
+func (r *MarkdownReporter) writeGates(w io.Writer, gates []reviewtypes.GateResult) {
+	if len(gates) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "## Gates\n\n")
+	_, _ = fmt.Fprintf(w, "| Gate | Result | Expression |\n")
+	_, _ = fmt.Fprintf(w, "|------|--------|------------|\n")
+	for _, g := range gates {
+		outcome := "passed"
+		switch {
+		case g.Error != "":
+			outcome = "error: " + g.Error
+		case !g.Passed:
+			outcome = "**failed**"
+		}
+		_, _ = fmt.Fprintf(w, "| %s | %s | `%s` |\n", g.Name, outcome, strings.ReplaceAll(g.Expr, "|", "\\|"))
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
@@ -0,0 +1,275 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// PDF page layout, in points: A4 with margins
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+	// pdfFooter is the room kept at the bottom for the page footer
+	pdfFooter = 20.0
+)
+
+// pdfFont is one of the standard fonts every PDF reader has, so no font is
+// embedded. Its value is its resource number, /F1 to /F3.
+type pdfFont int
+
+const (
+	fontRegular pdfFont = iota + 1
+	fontBold
+	fontMono
+)
+
+var pdfFontNames = []string{fontRegular: "Helvetica", fontBold: "Helvetica-Bold", fontMono: "Courier"}
+
+// helveticaWidths are the widths of the printable ASCII characters in
+// Helvetica, in thousandths of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// textWidth returns the width of text in points.
+func textWidth(font pdfFont, size float64, s string) float64 {
+	if font == fontMono {
+		return float64(utf8.RuneCountInString(s)) * 0.6 * size
+	}
+	w := 0
+	for _, r := range s {
+		if r >= 32 && r < 127 {
+			w += helveticaWidths[r-32]
+		} else {
+			w += 556
+		}
+	}
+	if font == fontBold {
+		w = w * 108 / 100 // Close enough to Helvetica-Bold for wrapping
+	}
+	return float64(w) * size / 1000
+}
+
+// pdfColor is an RGB color with components from 0 to 1.
+type pdfColor [3]float64
+
+var (
+	colorText  = pdfColor{0.13, 0.13, 0.13}
+	colorMuted = pdfColor{0.42, 0.42, 0.42}
+	colorRule  = pdfColor{0.8, 0.8, 0.8}
+	colorCode  = pdfColor{0.95, 0.95, 0.95}
+)
+
+// pdfDocument lays text out top to bottom on A4 pages. It needs no PDF
+// library: the document is a catalog, the page tree, the fonts and one
+// content stream per page.
+type pdfDocument struct {
+	title string
+	pages []*strings.Builder
+	// y is the top of the free space on the current page
+	y float64
+}
+
+func newPDFDocument(title string) *pdfDocument {
+	d := &pdfDocument{title: title}
+	d.newPage()
+	return d
+}
+
+// newPage starts a new page.
+func (d *pdfDocument) newPage() {
+	d.pages = append(d.pages, &strings.Builder{})
+	d.y = pdfPageHeight - pdfMargin
+}
+
+func (d *pdfDocument) page() *strings.Builder {
+	return d.pages[len(d.pages)-1]
+}
+
+// ensure starts a new page unless height points fit on this one.
+func (d *pdfDocument) ensure(height float64) {
+	if d.y-height < pdfMargin+pdfFooter {
+		d.newPage()
+	}
+}
+
+// space leaves vertical space, unless at the top of a page.
+func (d *pdfDocument) space(height float64) {
+	if d.y < pdfPageHeight-pdfMargin {
+		d.y -= height
+	}
+}
+
+// text writes a paragraph at indent points from the margin, wrapping it to
+// the page width. Newlines start new lines.
+func (d *pdfDocument) text(font pdfFont, size, indent float64, color pdfColor, s string) {
+	leading := size * 1.35
+	for _, para := range strings.Split(s, "\n") {
+		for _, line := range wrapText(font, size, pdfPageWidth-2*pdfMargin-indent, para) {
+			d.ensure(leading)
+			d.y -= leading
+			d.line(font, size, pdfMargin+indent, d.y+size*0.3, color, line)
+		}
+	}
+}
+
+// code writes a block of code in Courier on a shaded background.
+func (d *pdfDocument) code(s string, indent float64) {
+	const size = 8.0
+	leading := size * 1.4
+	width := pdfPageWidth - 2*pdfMargin - indent
+	for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		for _, part := range wrapText(fontMono, size, width-8, line) {
+			d.ensure(leading)
+			d.y -= leading
+			fmt.Fprintf(d.page(), "%.2f %.2f %.2f rg %.2f %.2f %.2f %.2f re f\n",
+				colorCode[0], colorCode[1], colorCode[2], pdfMargin+indent, d.y, width, leading)
+			d.line(fontMono, size, pdfMargin+indent+4, d.y+size*0.4, colorText, part)
+		}
+	}
+}
+
+// rule draws a horizontal line across the page.
+func (d *pdfDocument) rule() {
+	d.ensure(10)
+	d.y -= 5
+	fmt.Fprintf(d.page(), "%.2f %.2f %.2f RG 0.5 w %.2f %.2f m %.2f %.2f l S\n",
+		colorRule[0], colorRule[1], colorRule[2], pdfMargin, d.y, pdfPageWidth-pdfMargin, d.y)
+	d.y -= 5
+}
+
+// line writes one line of text with its baseline at y.
+func (d *pdfDocument) line(font pdfFont, size, x, y float64, color pdfColor, s string) {
+	fmt.Fprintf(d.page(), "%.2f %.2f %.2f rg BT /F%d %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		color[0], color[1], color[2], font, size, x, y, pdfString(s))
+}
+
+// writeTo writes the document, with the title and page number in the
+// footer of every page.
+func (d *pdfDocument) writeTo(w io.Writer) error {
+	// Objects: 1 catalog, 2 page tree, 3-5 fonts, 6 info, then each page
+	// and its content
+	const firstPage = 7
+	var buf bytes.Buffer
+	offsets := []int{0}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets)-1, body)
+	}
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, name := range pdfFontNames[1:] {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	object(fmt.Sprintf("<< /Title (%s) /Producer (goreview) >>", pdfString(d.title)))
+
+	for i, page := range d.pages {
+		number := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		content := page.String() + footer(d.title, number)
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, off := range offsets[1:] {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// footer returns the content drawing a page's footer: the title on the
+// left, the page number on the right.
+func footer(title, number string) string {
+	const size = 8.0
+	y := pdfMargin - 10
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%.2f %.2f %.2f rg BT /F%d %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		colorMuted[0], colorMuted[1], colorMuted[2], fontRegular, size, pdfMargin, y, pdfString(title))
+	fmt.Fprintf(&sb, "BT /F%d %.1f Tf %.2f %.2f Td (%s) Tj ET",
+		fontRegular, size, pdfPageWidth-pdfMargin-textWidth(fontRegular, size, number), y, pdfString(number))
+	return sb.String()
+}
+
+// wrapText splits text into lines no wider than width, at spaces when it
+// can. Empty text is one empty line.
+func wrapText(font pdfFont, size, width float64, s string) []string {
+	if textWidth(font, size, s) <= width {
+		return []string{s}
+	}
+	var lines []string
+	line := ""
+	for _, word := range strings.Split(s, " ") {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if textWidth(font, size, candidate) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		// Break words wider than the line
+		line = ""
+		for _, r := range word {
+			if line != "" && textWidth(font, size, line+string(r)) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			line += string(r)
+		}
+	}
+	return append(lines, line)
+}
+
+// writePDF writes text lines as a PDF document in Courier.
+func writePDF(w io.Writer, title string, lines []string) error {
+	d := newPDFDocument(title)
+	for _, line := range lines {
+		d.text(fontMono, 9, 0, colorText, line)
+	}
+	return d.writeTo(w)
+}
+
+// pdfString escapes text for a PDF string literal in WinAnsiEncoding.
+// Characters outside Latin-1 become "?".
+func pdfString(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '\t':
+			sb.WriteString("    ")
+		case r >= 0x20 && r < 0x7f:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&sb, "\\%03o", r)
+		default:
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
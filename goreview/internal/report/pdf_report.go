@@ -0,0 +1,263 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+// PDFReporter generates PDF reports for readers who don't live in the
+// terminal: a summary page, the findings file by file and an appendix with
+// the suggested fixes.
+type PDFReporter struct{}
+
+func (r *PDFReporter) Format() string { return "pdf" }
+
+func (r *PDFReporter) Generate(result *reviewtypes.Result) (string, error) {
+	var sb strings.Builder
+	if err := r.Write(result, &sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func (r *PDFReporter) Write(result *reviewtypes.Result, w io.Writer) error {
+	d := newPDFDocument("Code Review Report")
+	r.writeSummary(d, result)
+	fixes := r.writeFindings(d, result)
+	r.writeFixes(d, fixes)
+	return d.writeTo(w)
+}
+
+var severityColors = map[string]pdfColor{
+	reviewtypes.SeverityCritical: {0.62, 0.07, 0.07},
+	reviewtypes.SeverityError:    {0.84, 0.19, 0.15},
+	reviewtypes.SeverityWarning:  {0.8, 0.47, 0},
+	reviewtypes.SeverityInfo:     {0.15, 0.4, 0.72},
+}
+
+func severityColor(severity string) pdfColor {
+	if c, ok := severityColors[severity]; ok {
+		return c
+	}
+	return severityColors[reviewtypes.SeverityInfo]
+}
+
+// pdfFix is an issue with a suggested fix, listed in the appendix.
+type pdfFix struct {
+	file  string
+	issue reviewtypes.Issue
+}
+
+func (r *PDFReporter) writeSummary(d *pdfDocument, result *reviewtypes.Result) {
+	d.text(fontBold, 22, 0, colorText, "Code Review Report")
+	if env := result.Environment; env != nil && env.CommitSHA != "" {
+		d.text(fontRegular, 10, 0, colorMuted, "Commit "+env.CommitSHA)
+	}
+	d.space(8)
+	d.rule()
+	d.space(6)
+
+	d.text(fontBold, 13, 0, colorText, "Summary")
+	d.space(4)
+	r.writeFields(d, [][2]string{
+		{"Files reviewed", fmt.Sprintf("%d", len(result.Files))},
+		{"Total issues", fmt.Sprintf("%d", result.TotalIssues)},
+		{"Duration", result.Duration.String()},
+	})
+	if q := result.Quality; q != nil {
+		quality := fmt.Sprintf("%d/100", q.Score)
+		if q.Degraded {
+			quality += " (degraded)"
+		}
+		r.writeFields(d, [][2]string{{"Quality", quality}})
+	}
+	if result.Effort != nil {
+		r.writeFields(d, [][2]string{{"Review effort", fmt.Sprintf("~%d min", result.Effort.Minutes)}})
+	}
+
+	if result.TotalIssues > 0 {
+		d.space(10)
+		d.text(fontBold, 13, 0, colorText, "Issues by Severity")
+		d.space(4)
+		counts := severityCounts(result)
+		for _, sev := range []string{reviewtypes.SeverityCritical, reviewtypes.SeverityError, reviewtypes.SeverityWarning, reviewtypes.SeverityInfo} {
+			d.ensure(14)
+			d.y -= 14
+			d.line(fontBold, 10, pdfMargin, d.y+3, severityColor(sev), strings.ToUpper(sev))
+			d.line(fontRegular, 10, pdfMargin+120, d.y+3, colorText, fmt.Sprintf("%d", counts[sev]))
+		}
+	}
+
+	if len(result.Gates) > 0 {
+		d.space(10)
+		d.text(fontBold, 13, 0, colorText, "Gates")
+		d.space(4)
+		for _, g := range result.Gates {
+			outcome, color := "passed", colorText
+			switch {
+			case g.Error != "":
+				outcome, color = "error: "+g.Error, severityColor(reviewtypes.SeverityWarning)
+			case !g.Passed:
+				outcome, color = "FAILED", severityColor(reviewtypes.SeverityError)
+			}
+			d.text(fontBold, 10, 0, color, g.Name+": "+outcome)
+			d.text(fontMono, 8, 12, colorMuted, g.Expr)
+		}
+	}
+
+	if effort := result.Effort; effort != nil && len(effort.Files) > 1 {
+		d.space(10)
+		d.text(fontBold, 13, 0, colorText, "Suggested Review Order")
+		d.space(4)
+		for i, f := range effort.Files {
+			d.text(fontRegular, 10, 0, colorText, fmt.Sprintf("%d. %s (~%d min, %d lines, %d issues)", i+1, f.File, f.Minutes, f.Lines, f.Issues))
+		}
+	}
+
+	if scope := result.Scope; scope != nil && len(scope.Suggestions) > 0 {
+		d.space(10)
+		d.text(fontBold, 13, 0, colorText, "Change Scope")
+		d.space(4)
+		for _, s := range scope.Suggestions {
+			d.text(fontRegular, 10, 0, colorText, "- "+s)
+		}
+	}
+
+	if result.Summary != "" {
+		d.space(10)
+		d.text(fontBold, 13, 0, colorText, "Overview")
+		d.space(4)
+		d.text(fontRegular, 10, 0, colorText, result.Summary)
+	}
+
+	if env := result.Environment; env != nil {
+		d.space(10)
+		d.text(fontBold, 13, 0, colorText, "Reproducibility")
+		d.space(4)
+		r.writeFields(d, nonEmptyFields([][2]string{
+			{"goreview", env.Version},
+			{"Provider", env.Provider},
+			{"Model", env.Model},
+			{"Temperature", fmt.Sprintf("%g", env.Temperature)},
+			{"Preset", env.Preset},
+			{"Rules hash", env.RulesHash},
+			{"Prompt hash", env.PromptHash},
+		}))
+	}
+}
+
+// writeFields writes label-value pairs in two columns.
+func (r *PDFReporter) writeFields(d *pdfDocument, fields [][2]string) {
+	for _, f := range fields {
+		d.ensure(14)
+		d.y -= 14
+		d.line(fontRegular, 10, pdfMargin, d.y+3, colorMuted, f[0])
+		value := wrapText(fontRegular, 10, pdfPageWidth-2*pdfMargin-120, f[1])[0]
+		d.line(fontRegular, 10, pdfMargin+120, d.y+3, colorText, value)
+	}
+}
+
+// writeFindings writes the issues of each file and returns the ones with a
+// suggested fix.
+func (r *PDFReporter) writeFindings(d *pdfDocument, result *reviewtypes.Result) []pdfFix {
+	var fixes []pdfFix
+	if result.TotalIssues == 0 && !hasFileErrors(result) {
+		d.space(16)
+		d.text(fontRegular, 11, 0, colorText, "No issues found.")
+		return nil
+	}
+
+	d.newPage()
+	d.text(fontBold, 18, 0, colorText, "Findings")
+	for _, file := range result.Files {
+		if file.Error == "" && (file.Response == nil || len(file.Response.Issues) == 0) {
+			continue
+		}
+		d.space(14)
+		d.ensure(40)
+		d.text(fontBold, 12, 0, colorText, file.File)
+		d.rule()
+		if file.Error != "" {
+			d.text(fontRegular, 10, 0, severityColor(reviewtypes.SeverityError), "Not reviewed: "+file.Error)
+			continue
+		}
+		for _, issue := range file.Response.Issues {
+			if issue.FixedCode != "" {
+				fixes = append(fixes, pdfFix{file: file.File, issue: issue})
+			}
+			r.writeIssue(d, issue, len(fixes))
+		}
+	}
+	return fixes
+}
+
+// writeIssue writes one finding; fix is its number in the appendix, when
+// it has a suggested fix.
+func (r *PDFReporter) writeIssue(d *pdfDocument, issue reviewtypes.Issue, fix int) {
+	d.space(6)
+	d.ensure(36)
+	d.text(fontBold, 9, 0, severityColor(issue.Severity), strings.ToUpper(issue.Severity))
+	d.text(fontBold, 10, 0, colorText, issue.Message)
+
+	details := []string{}
+	if loc := issue.Location; loc != nil && loc.StartLine > 0 {
+		lines := fmt.Sprintf("Line %d", loc.StartLine)
+		if loc.EndLine > loc.StartLine {
+			lines = fmt.Sprintf("Lines %d-%d", loc.StartLine, loc.EndLine)
+		}
+		details = append(details, lines)
+	}
+	for _, detail := range []string{issue.Type, issue.CWE, issue.OWASP} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	if len(details) > 0 {
+		d.text(fontRegular, 9, 0, colorMuted, strings.Join(details, " | "))
+	}
+
+	if issue.Code != "" {
+		d.space(3)
+		d.code(issue.Code, 0)
+	}
+	if issue.Suggestion != "" {
+		d.space(3)
+		d.text(fontRegular, 10, 0, colorText, "Suggestion: "+issue.Suggestion)
+	}
+	if issue.FixedCode != "" {
+		d.text(fontRegular, 9, 0, colorMuted, fmt.Sprintf("Suggested fix: see A.%d", fix))
+	}
+}
+
+// writeFixes writes the appendix with the suggested fixes.
+func (r *PDFReporter) writeFixes(d *pdfDocument, fixes []pdfFix) {
+	if len(fixes) == 0 {
+		return
+	}
+	d.newPage()
+	d.text(fontBold, 18, 0, colorText, "Appendix: Suggested Fixes")
+	for i, fix := range fixes {
+		d.space(14)
+		d.ensure(40)
+		location := fix.file
+		if loc := fix.issue.Location; loc != nil && loc.StartLine > 0 {
+			location = fmt.Sprintf("%s:%d", fix.file, loc.StartLine)
+		}
+		d.text(fontBold, 10, 0, colorText, fmt.Sprintf("A.%d  %s", i+1, location))
+		d.text(fontRegular, 9, 0, colorMuted, fix.issue.Message)
+		d.space(3)
+		d.code(fix.issue.FixedCode, 0)
+	}
+}
+
+func hasFileErrors(result *reviewtypes.Result) bool {
+	for _, file := range result.Files {
+		if file.Error != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+func TestWrapText(t *testing.T) {
+	const width = 100.0
+	if got := wrapText(fontRegular, 10, width, "short"); len(got) != 1 || got[0] != "short" {
+		t.Errorf("wrapText(short) = %q", got)
+	}
+	if got := wrapText(fontRegular, 10, width, ""); len(got) != 1 || got[0] != "" {
+		t.Errorf("wrapText(empty) = %q, want one empty line", got)
+	}
+
+	text := "the quick brown fox jumps over the lazy dog and keeps running far away"
+	lines := wrapText(fontRegular, 10, width, text)
+	if len(lines) < 2 {
+		t.Fatalf("wrapText() = %q, want several lines", lines)
+	}
+	for _, l := range lines {
+		if textWidth(fontRegular, 10, l) > width || strings.HasPrefix(l, " ") || strings.HasSuffix(l, " ") {
+			t.Errorf("line %q is too wide or not split at a space", l)
+		}
+	}
+	if strings.Join(lines, " ") != text {
+		t.Errorf("wrapped lines %q lose text", lines)
+	}
+
+	word := strings.Repeat("x", 80)
+	lines = wrapText(fontMono, 10, width, word)
+	if strings.Join(lines, "") != word {
+		t.Errorf("wrapText(long word) = %q, want it split across lines", lines)
+	}
+	for _, l := range lines {
+		if textWidth(fontMono, 10, l) > width {
+			t.Errorf("line %q is wider than %v", l, width)
+		}
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`f(x) \ y`, `f\(x\) \\ y`},
+		{"a\tb", "a    b"},
+		{"café ñ", `caf\351 \361`},
+		{"日本 ok", "?? ok"},
+		{"\x01", "?"},
+	}
+	for _, tt := range tests {
+		if got := pdfString(tt.in); got != tt.want {
+			t.Errorf("pdfString(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+var (
+	xrefEntry = regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`)
+	objLength = regexp.MustCompile(`<< /Length (\d+) >>\nstream\n`)
+)
+
+// TestPDFStructure checks the document a reader navigates: the xref
+// offsets point at their objects and the stream lengths are exact.
+func TestPDFStructure(t *testing.T) {
+	result := &reviewtypes.Result{TotalIssues: 40}
+	for i := 0; i < 40; i++ {
+		result.Files = append(result.Files, reviewtypes.FileResult{
+			File: fmt.Sprintf("pkg/file%d.go", i),
+			Response: &reviewtypes.Response{Issues: []reviewtypes.Issue{{
+				ID: "1", Type: "bug", Severity: reviewtypes.SeverityError,
+				Message:   "Unbalanced (parenthesis) and back\\slash in café — naïve 日本",
+				Location:  &reviewtypes.Location{StartLine: i + 1},
+				FixedCode: "if (x) {\n\treturn \"\\\\\"\n}",
+			}}},
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := (&PDFReporter{}).Write(result, &buf); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+
+	start := bytes.LastIndex(pdf, []byte("startxref\n"))
+	xref, err := strconv.Atoi(strings.Fields(string(pdf[start+len("startxref\n"):]))[0])
+	if err != nil || !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	entries := xrefEntry.FindAllSubmatch(pdf[xref:], -1)
+	size := regexp.MustCompile(`/Size (\d+)`).FindSubmatch(pdf)
+	if size == nil || string(size[1]) != strconv.Itoa(len(entries)+1) {
+		t.Errorf("/Size = %s, want %d", size, len(entries)+1)
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(pdf[off:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want object %d", i+1, pdf[off:min(off+12, len(pdf))], i+1)
+		}
+	}
+
+	streams := objLength.FindAllSubmatchIndex(pdf, -1)
+	if len(streams) < 2 {
+		t.Fatalf("%d content streams, want several pages", len(streams))
+	}
+	if pages := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(pdf); pages == nil || string(pages[1]) != strconv.Itoa(len(streams)) {
+		t.Errorf("/Count = %s, want %d pages", pages, len(streams))
+	}
+	for _, s := range streams {
+		length, _ := strconv.Atoi(string(pdf[s[2]:s[3]]))
+		body := pdf[s[1]:]
+		if !bytes.HasPrefix(body[length:], []byte("\nendstream")) {
+			t.Errorf("/Length %d does not end at endstream", length)
+		}
+	}
+
+	if bytes.ContainsRune(pdf, 'é') || !bytes.Contains(pdf, []byte(`Unbalanced \(parenthesis\) and back\\slash in caf\351`)) {
+		t.Error("issue text is not escaped for WinAnsiEncoding")
+	}
+}
@@ -4,16 +4,18 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
 )
 
-// Reporter defines the interface for generating review reports.
+// Reporter defines the interface for generating review reports. Reporters
+// render the public result schema, so they depend on nothing that runs git
+// or opens databases and build for WebAssembly.
 type Reporter interface {
 	// Generate creates a report from review results.
-	Generate(result *review.Result) (string, error)
+	Generate(result *reviewtypes.Result) (string, error)
 
 	// Write writes the report to a writer.
-	Write(result *review.Result, w io.Writer) error
+	Write(result *reviewtypes.Result, w io.Writer) error
 
 	// Format returns the format name.
 	Format() string
@@ -28,6 +30,14 @@ func NewReporter(format string) (Reporter, error) {
 		return &JSONReporter{Indent: true}, nil
 	case "sarif":
 		return &SARIFReporter{}, nil
+	case "pdf":
+		return &PDFReporter{}, nil
+	case "codeactions":
+		return &CodeActionsReporter{}, nil
+	case "dot":
+		return &GraphReporter{}, nil
+	case "mermaid":
+		return &GraphReporter{Mermaid: true}, nil
 	default:
 		return nil, fmt.Errorf("unknown format: %s", format)
 	}
@@ -35,5 +45,31 @@ func NewReporter(format string) (Reporter, error) {
 
 // AvailableFormats returns the list of supported formats.
 func AvailableFormats() []string {
-	return []string{"markdown", "json", "sarif"}
+	return []string{"markdown", "json", "sarif", "pdf", "codeactions", "dot", "mermaid"}
+}
+
+// usedIssueTypes returns the configured issue types (with descriptions) that
+// appear in the result, in taxonomy order.
+func usedIssueTypes(result *reviewtypes.Result) []reviewtypes.IssueTypeInfo {
+	if len(result.IssueTypes) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			seen[issue.Type] = true
+		}
+	}
+
+	used := make([]reviewtypes.IssueTypeInfo, 0, len(seen))
+	for _, t := range result.IssueTypes {
+		if seen[t.Name] && t.Description != "" {
+			used = append(used, t)
+		}
+	}
+	return used
 }
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+func TestNewReporter(t *testing.T) {
+	for _, format := range AvailableFormats() {
+		r, err := NewReporter(format)
+		if err != nil || r.Format() != format {
+			t.Errorf("NewReporter(%s) = %v, %v", format, r, err)
+		}
+	}
+	if _, err := NewReporter("html"); err == nil {
+		t.Error("NewReporter(html) succeeded")
+	}
+}
+
+func TestGraphReporter(t *testing.T) {
+	result := &reviewtypes.Result{Files: []reviewtypes.FileResult{{
+		File: "api/handler.go",
+		Response: &reviewtypes.Response{Issues: []reviewtypes.Issue{
+			{ID: "1", Severity: reviewtypes.SeverityError, Message: "unchecked input",
+				RootCause: &reviewtypes.RootCause{Description: "no validation", OriginFile: "api/parse.go", OriginLine: 12}},
+			{ID: "2", Severity: reviewtypes.SeverityInfo, Message: "untraced"},
+		}},
+	}}}
+
+	dot, err := (&GraphReporter{}).Generate(result)
+	if err != nil || !strings.HasPrefix(dot, "digraph causes {") || !strings.Contains(dot, "api/parse.go") {
+		t.Errorf("DOT = %s, %v", dot, err)
+	}
+	if strings.Contains(dot, "untraced") {
+		t.Error("DOT includes an issue without a root cause")
+	}
+	mermaid, err := (&GraphReporter{Mermaid: true}).Generate(result)
+	if err != nil || !strings.Contains(mermaid, "unchecked input") {
+		t.Errorf("Mermaid = %s, %v", mermaid, err)
+	}
+}
+
+func TestCodeActionsReporter(t *testing.T) {
+	result := &reviewtypes.Result{Files: []reviewtypes.FileResult{{
+		File: "a.go",
+		Response: &reviewtypes.Response{Issues: []reviewtypes.Issue{{
+			ID: "1", Severity: reviewtypes.SeverityWarning, Message: "simplify",
+			Location: &reviewtypes.Location{StartLine: 2, EndLine: 2}, FixedCode: "x := 2",
+		}}},
+	}}}
+	out, err := (&CodeActionsReporter{}).Generate(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil || !strings.Contains(out, "x := 2") {
+		t.Errorf("code actions = %s, %v", out, err)
+	}
+}
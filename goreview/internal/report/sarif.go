@@ -3,9 +3,9 @@ package report
 import (
 	"encoding/json"
 	"io"
+	"strings"
 
-	"github.com/JNZader/goreview/goreview/internal/providers"
-	"github.com/JNZader/goreview/goreview/internal/review"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
 )
 
 // SARIFReporter generates SARIF 2.1.0 reports.
@@ -23,6 +23,9 @@ type sarifReport struct {
 type sarifRun struct {
 	Tool    sarifTool     `json:"tool"`
 	Results []sarifResult `json:"results"`
+	// Properties holds the review environment, for reproducibility, and
+	// the estimated review effort
+	Properties map[string]interface{} `json:"properties,omitempty"`
 }
 
 type sarifTool struct {
@@ -48,6 +51,18 @@ type sarifResult struct {
 	Level     string          `json:"level"`
 	Message   sarifMessage    `json:"message"`
 	Locations []sarifLocation `json:"locations,omitempty"`
+	// Properties holds the ID of the provider transcript behind the result
+	// and its triage
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	// Suppressions mark issues acknowledged or won't fix in triage, so code
+	// scanning doesn't raise them again
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification,omitempty"`
 }
 
 type sarifMessage struct {
@@ -70,7 +85,7 @@ type sarifRegion struct {
 	EndColumn   int `json:"endColumn,omitempty"`
 }
 
-func (r *SARIFReporter) Generate(result *review.Result) (string, error) {
+func (r *SARIFReporter) Generate(result *reviewtypes.Result) (string, error) {
 	report := r.buildReport(result)
 	data, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
@@ -79,14 +94,14 @@ func (r *SARIFReporter) Generate(result *review.Result) (string, error) {
 	return string(data), nil
 }
 
-func (r *SARIFReporter) Write(result *review.Result, w io.Writer) error {
+func (r *SARIFReporter) Write(result *reviewtypes.Result, w io.Writer) error {
 	report := r.buildReport(result)
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(report)
 }
 
-func (r *SARIFReporter) buildReport(result *review.Result) *sarifReport {
+func (r *SARIFReporter) buildReport(result *reviewtypes.Result) *sarifReport {
 	report := &sarifReport{
 		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
 		Version: "2.1.0",
@@ -101,6 +116,31 @@ func (r *SARIFReporter) buildReport(result *review.Result) *sarifReport {
 		}},
 	}
 
+	if env := result.Environment; env != nil {
+		if env.Version != "" {
+			report.Runs[0].Tool.Driver.Version = env.Version
+		}
+		report.Runs[0].Properties = map[string]interface{}{"environment": env}
+	}
+	if result.Effort != nil {
+		if report.Runs[0].Properties == nil {
+			report.Runs[0].Properties = make(map[string]interface{})
+		}
+		report.Runs[0].Properties["review_effort"] = result.Effort
+	}
+	if result.Scope != nil {
+		if report.Runs[0].Properties == nil {
+			report.Runs[0].Properties = make(map[string]interface{})
+		}
+		report.Runs[0].Properties["change_scope"] = result.Scope
+	}
+
+	for _, t := range usedIssueTypes(result) {
+		rule := sarifRule{ID: t.Name, Name: t.Name}
+		rule.Description.Text = t.Description
+		report.Runs[0].Tool.Driver.Rules = append(report.Runs[0].Tool.Driver.Rules, rule)
+	}
+
 	for _, file := range result.Files {
 		if file.Response == nil {
 			continue
@@ -108,7 +148,7 @@ func (r *SARIFReporter) buildReport(result *review.Result) *sarifReport {
 
 		for _, issue := range file.Response.Issues {
 			res := sarifResult{
-				RuleID:  string(issue.Type),
+				RuleID:  issue.Type,
 				Level:   r.mapLevel(issue.Severity),
 				Message: sarifMessage{Text: issue.Message},
 			}
@@ -124,6 +164,17 @@ func (r *SARIFReporter) buildReport(result *review.Result) *sarifReport {
 				}
 				res.Locations = append(res.Locations, loc)
 			}
+			if issue.Transcript != "" {
+				res.Properties = map[string]interface{}{"transcript": issue.Transcript}
+			}
+			if issue.Calibration != "" {
+				if res.Properties == nil {
+					res.Properties = make(map[string]interface{})
+				}
+				res.Properties["calibration"] = issue.Calibration
+			}
+			addTaxonomy(&res, issue)
+			addTriage(&res, issue.Triage)
 
 			report.Runs[0].Results = append(report.Runs[0].Results, res)
 		}
@@ -132,11 +183,57 @@ func (r *SARIFReporter) buildReport(result *review.Result) *sarifReport {
 	return report
 }
 
-func (r *SARIFReporter) mapLevel(severity providers.Severity) string {
+// addTaxonomy records the CWE and OWASP classification of an issue in its
+// result, with the tags code scanning filters security results by.
+func addTaxonomy(res *sarifResult, issue reviewtypes.Issue) {
+	if issue.CWE == "" && issue.OWASP == "" {
+		return
+	}
+	if res.Properties == nil {
+		res.Properties = make(map[string]interface{})
+	}
+	tags := []string{"security"}
+	if issue.CWE != "" {
+		res.Properties["cwe"] = issue.CWE
+		tags = append(tags, "external/cwe/"+strings.ToLower(issue.CWE))
+	}
+	if issue.OWASP != "" {
+		res.Properties["owasp"] = issue.OWASP
+		tags = append(tags, "external/owasp/"+strings.ToLower(issue.OWASP))
+	}
+	res.Properties["tags"] = tags
+}
+
+// addTriage records the triage of an issue in its result, suppressing
+// issues acknowledged or won't fix.
+func addTriage(res *sarifResult, triage *reviewtypes.Triage) {
+	if triage == nil {
+		return
+	}
+	if res.Properties == nil {
+		res.Properties = make(map[string]interface{})
+	}
+	res.Properties["triage_status"] = triage.Status
+	if triage.Assignee != "" {
+		res.Properties["triage_assignee"] = triage.Assignee
+	}
+	if triage.Recurrence != "" {
+		res.Properties["triage_recurrence"] = triage.Recurrence
+	}
+	if triage.Status == "acknowledged" || triage.Status == "wontfix" {
+		res.Suppressions = append(res.Suppressions, sarifSuppression{
+			Kind:          "external",
+			Status:        "accepted",
+			Justification: triage.Notes,
+		})
+	}
+}
+
+func (r *SARIFReporter) mapLevel(severity string) string {
 	switch severity {
-	case providers.SeverityCritical, providers.SeverityError:
+	case reviewtypes.SeverityCritical, reviewtypes.SeverityError:
 		return "error"
-	case providers.SeverityWarning:
+	case reviewtypes.SeverityWarning:
 		return "warning"
 	default:
 		return "note"
@@ -0,0 +1,26 @@
+package review
+
+import (
+	"github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// DiagnoseAST reports in each file's result how much structure the AST
+// parser extracted from it, see ast.Coverage.
+func (e *Engine) DiagnoseAST() {
+	e.astDiagnostics = true
+}
+
+// astCoverage returns the AST extraction coverage of the reviewed file,
+// nil when diagnostics are off or the file was deleted.
+func (e *Engine) astCoverage(file git.FileDiff) *ast.Coverage {
+	if !e.astDiagnostics || file.Status == git.FileDeleted || file.IsBinary {
+		return nil
+	}
+	parser := ast.NewParser(file.Language)
+	content, ok := e.readRepoFile(file.Path)
+	if !ok {
+		return &ast.Coverage{Parser: parser.Name(), Errors: []string{"file content unavailable, not parsed"}}
+	}
+	return parser.Diagnose(content, file.Path)
+}
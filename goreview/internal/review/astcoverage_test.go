@@ -0,0 +1,38 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+func TestASTCoverage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n\nfunc F() {\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine(config.DefaultConfig(), nil, nil, nil, nil)
+	engine.repoRoot = dir
+	file := git.FileDiff{Path: "a.go", Language: "go", Status: git.FileModified}
+
+	if c := engine.astCoverage(file); c != nil {
+		t.Errorf("astCoverage() without DiagnoseAST = %+v, want nil", c)
+	}
+	engine.DiagnoseAST()
+	c := engine.astCoverage(file)
+	if c == nil || c.Parser != "go" || c.Functions != 1 || len(c.Errors) != 1 {
+		t.Errorf("astCoverage() = %+v, want one function and an unclosed brace", c)
+	}
+
+	missing := git.FileDiff{Path: "b.ex", Language: "elixir", Status: git.FileModified}
+	if c := engine.astCoverage(missing); c == nil || c.Parser != "generic" || len(c.Errors) != 1 {
+		t.Errorf("astCoverage() of an unreadable file = %+v", c)
+	}
+	missing.Status = git.FileDeleted
+	if c := engine.astCoverage(missing); c != nil {
+		t.Errorf("astCoverage() of a deleted file = %+v, want nil", c)
+	}
+}
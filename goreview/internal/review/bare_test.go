@@ -0,0 +1,84 @@
+package review
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestEngineBareRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	work := filepath.Join(dir, "work")
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Dev", "-c", "user.email=dev@example.com"}, args...)...)
+		cmd.Dir = work
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(work, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write(".goreviewignore", "gen/\n")
+	run("init", "-q")
+	run("add", ".")
+	run("commit", "-qm", "initial")
+	write("main.go", "package main\n\nfunc main() {\n\tpassword := \"hunter2\"\n\t_ = password\n}\n")
+	write("gen/out.go", "package gen\n")
+	run("add", ".")
+	run("commit", "-qm", "add password")
+	run("clone", "-q", "--bare", work, filepath.Join(dir, "repo.git"))
+
+	repo, err := git.NewRepo(filepath.Join(dir, "repo.git"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "commit"
+	cfg.Review.Commit = "HEAD"
+
+	var mu sync.Mutex
+	var reviewed []string
+	provider := &MockProvider{ReviewFunc: func(_ context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+		mu.Lock()
+		reviewed = append(reviewed, req.FilePath)
+		mu.Unlock()
+		return &providers.ReviewResponse{Issues: []providers.Issue{
+			{ID: "secret", Message: "hardcoded password", Code: `password := "hunter2"`},
+		}}, nil
+	}}
+	result, err := NewEngine(cfg, repo, provider, nil, nil).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// The ignore file and the file contents come from the commit
+	if len(reviewed) != 1 || reviewed[0] != "main.go" {
+		t.Errorf("reviewed = %v, want main.go alone", reviewed)
+	}
+	if len(result.Files) != 1 || len(result.Files[0].Response.Issues) != 1 {
+		t.Fatalf("result = %+v", result.Files)
+	}
+	if loc := result.Files[0].Response.Issues[0].Location; loc == nil || loc.StartLine != 4 || loc.Unverified {
+		t.Errorf("issue location = %+v, want line 4 read from the commit", loc)
+	}
+}
@@ -0,0 +1,90 @@
+package review
+
+import (
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/tokenizer"
+)
+
+// ContextBudget tells how a file's review requests spent the model's context
+// window, in estimated tokens. Instructions, rules, knowledge, focus, related
+// files and the change summary are repeated in every request when the diff is
+// split into chunks.
+type ContextBudget struct {
+	// ContextWindow is the model's context window
+	ContextWindow int `json:"context_window"`
+	// ResponseTokens is the part of the window reserved for the response
+	ResponseTokens int `json:"response_tokens"`
+	// ChunkLimit is the most diff tokens sent in one request
+	ChunkLimit int `json:"chunk_limit"`
+
+	// Instructions are the system prompt, personality, modes and schema
+	Instructions int `json:"instructions"`
+	// Diff is the whole diff, across chunks
+	Diff int `json:"diff"`
+	// Rules is the guidance of the rules in scope
+	Rules int `json:"rules"`
+	// Knowledge is the text of the matching knowledge packs
+	Knowledge int `json:"knowledge"`
+	// Focus are the suspicious regions found by static checks
+	Focus int `json:"focus"`
+	// Related is the text of the neighboring files sent with full-file
+	// reviews
+	Related int `json:"related,omitempty"`
+	// Summary is the structured summary of the change
+	Summary int `json:"summary,omitempty"`
+
+	// Chunks is the number of requests sent for the file
+	Chunks int `json:"chunks"`
+	// Prompt is the size of the largest request's prompt
+	Prompt int `json:"prompt"`
+	// Overflow is how far the largest request plus the response reserve
+	// exceeds the context window
+	Overflow int `json:"overflow,omitempty"`
+	// Truncated lists the parts that were cut to fit: "knowledge" when the
+	// packs exceeded their limit, "related" when the neighboring files did,
+	// "diff" when a chunk exceeded ChunkLimit
+	Truncated []string `json:"truncated,omitempty"`
+}
+
+// contextBudget estimates the fixed parts of the request's prompt. The
+// chunk counts are filled in by callProvider.
+func (e *Engine) contextBudget(req *providers.ReviewRequest) *ContextBudget {
+	return &ContextBudget{
+		ContextWindow:  e.caps.ContextWindow,
+		ResponseTokens: e.responseTokens,
+		ChunkLimit:     e.maxChunkTokens,
+		Instructions:   e.estimator.EstimateTokens(providers.PromptInstructions(req)),
+		Diff:           e.estimator.EstimateTokens(req.Diff),
+		Rules:          e.estimator.EstimateTokens(strings.Join(req.Rules, "\n")),
+		Knowledge:      e.estimator.EstimateTokens(req.Context),
+		Focus:          e.estimator.EstimateTokens(strings.Join(req.Focus, "\n")),
+		Related:        e.estimator.EstimateTokens(req.Related),
+		Summary:        e.estimator.EstimateTokens(req.ChangeSummary),
+	}
+}
+
+// recordChunks completes the budget with the chunks sent, or with the whole
+// diff when chunks is nil.
+func (b *ContextBudget) recordChunks(chunks []tokenizer.Chunk) {
+	largest := b.Diff
+	b.Chunks = 1
+	if chunks != nil {
+		largest = 0
+		b.Chunks = len(chunks)
+		for _, c := range chunks {
+			largest = max(largest, c.TokenCount)
+		}
+		if largest > b.ChunkLimit {
+			b.truncate("diff")
+		}
+	}
+
+	b.Prompt = b.Instructions + b.Rules + b.Knowledge + b.Focus + b.Related + b.Summary + largest
+	b.Overflow = max(0, b.Prompt+b.ResponseTokens-b.ContextWindow)
+}
+
+func (b *ContextBudget) truncate(part string) {
+	b.Truncated = append(b.Truncated, part)
+}
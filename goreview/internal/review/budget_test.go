@@ -0,0 +1,47 @@
+package review
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/tokenizer"
+)
+
+func TestContextBudget(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.MaxChunkTokens = 100
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+
+	req := &providers.ReviewRequest{
+		Diff:    "+x := 1",
+		Rules:   []string{"SEC-001: no hardcoded secrets"},
+		Context: "### database/sql\n- Rows not closed",
+	}
+	b := engine.contextBudget(req)
+	if b.Instructions == 0 || b.Diff == 0 || b.Rules == 0 || b.Knowledge == 0 || b.Focus != 0 {
+		t.Errorf("contextBudget() = %+v, want all parts but focus counted", b)
+	}
+	if b.ContextWindow == 0 || b.ChunkLimit != 100 {
+		t.Errorf("contextBudget() limits = %d window, %d chunk", b.ContextWindow, b.ChunkLimit)
+	}
+
+	b.recordChunks(nil)
+	if b.Chunks != 1 || b.Prompt != b.Instructions+b.Diff+b.Rules+b.Knowledge {
+		t.Errorf("recordChunks(nil) = %d chunks, prompt %d", b.Chunks, b.Prompt)
+	}
+
+	// A chunk over the limit is truncated, and the window can overflow
+	b.ContextWindow = 500
+	b.recordChunks([]tokenizer.Chunk{{TokenCount: 80}, {TokenCount: 400}})
+	if b.Chunks != 2 || b.Prompt != b.Instructions+b.Rules+b.Knowledge+400 {
+		t.Errorf("recordChunks() = %d chunks, prompt %d", b.Chunks, b.Prompt)
+	}
+	if want := []string{"diff"}; !reflect.DeepEqual(b.Truncated, want) {
+		t.Errorf("Truncated = %v, want %v", b.Truncated, want)
+	}
+	if want := b.Prompt + b.ResponseTokens - 500; b.Overflow != want {
+		t.Errorf("Overflow = %d, want %d", b.Overflow, want)
+	}
+}
@@ -0,0 +1,76 @@
+package review
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// OutcomeLookup returns how the triaged issues of each rule ended, such as
+// the history store.
+type OutcomeLookup interface {
+	RuleOutcomes(ctx context.Context) (map[string]history.RuleOutcome, error)
+}
+
+// UseCalibration makes the review calibrate the severity of the findings of
+// rules the team mostly marks won't fix, per review.triage.calibration.
+func (e *Engine) UseCalibration(l OutcomeLookup) {
+	e.calibration = l
+}
+
+// lowerSeverity is the severity one level below each
+var lowerSeverity = map[providers.Severity]providers.Severity{
+	providers.SeverityCritical: providers.SeverityError,
+	providers.SeverityError:    providers.SeverityWarning,
+	providers.SeverityWarning:  providers.SeverityInfo,
+}
+
+// applyCalibration lowers or annotates the findings of rules whose triaged
+// issues were mostly marked won't fix. It runs after applyTriage, and
+// leaves recurrences of triaged issues to it.
+func (e *Engine) applyCalibration(ctx context.Context, result *Result) {
+	cfg := e.cfg.Review.Triage.Calibration
+	if e.calibration == nil || !cfg.Enabled {
+		return
+	}
+	outcomes, err := e.calibration.RuleOutcomes(ctx)
+	if err != nil {
+		e.log.Warn("Severities not calibrated: %v", err)
+		return
+	}
+
+	calibrated := 0
+	for i := range result.Files {
+		f := &result.Files[i]
+		if f.Response == nil {
+			continue
+		}
+		for j := range f.Response.Issues {
+			issue := &f.Response.Issues[j]
+			if issue.Triage != nil && issue.Triage.Recurrence != "" {
+				continue
+			}
+			o := outcomes[history.RuleKey(issue.RuleID, string(issue.Type))]
+			if o.Triaged() < cfg.MinTriaged || o.IgnoreRate() < cfg.IgnoreRate {
+				continue
+			}
+			tally := fmt.Sprintf("%d of %d triaged findings of the rule marked won't fix", o.Ignored, o.Triaged())
+			lower, ok := lowerSeverity[issue.Severity]
+			switch {
+			case cfg.Action == "downgrade" && ok:
+				issue.Calibration = fmt.Sprintf("lowered from %s: %s", issue.Severity, tally)
+				issue.Severity = lower
+			case cfg.Action == "downgrade":
+				issue.Calibration = tally
+			default:
+				issue.Calibration = fmt.Sprintf("usually not fixed: %s", tally)
+			}
+			calibrated++
+		}
+	}
+	if calibrated > 0 {
+		e.log.Info("Calibrated %d issues to the team's triage history", calibrated)
+	}
+}
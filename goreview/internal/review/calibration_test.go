@@ -0,0 +1,58 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// ruleOutcomes is an OutcomeLookup over fixed outcomes.
+type ruleOutcomes map[string]history.RuleOutcome
+
+func (o ruleOutcomes) RuleOutcomes(context.Context) (map[string]history.RuleOutcome, error) {
+	return o, nil
+}
+
+func TestApplyCalibration(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Triage.Calibration.Enabled = true
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.UseCalibration(ruleOutcomes{
+		"no-magic":   {Resolved: 1, Ignored: 9},
+		"type:style": {Ignored: 3},
+		"type:bug":   {Resolved: 8, Ignored: 4},
+	})
+
+	result := &Result{Files: []FileResult{{File: "a.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{
+		{Type: providers.IssueTypeStyle, RuleID: "no-magic", Severity: providers.SeverityError, Message: "magic number"},
+		{Type: providers.IssueTypeStyle, RuleID: "no-magic", Severity: providers.SeverityInfo, Message: "magic string"},
+		{Type: providers.IssueTypeStyle, Severity: providers.SeverityWarning, Message: "too few triaged"},
+		{Type: providers.IssueTypeBug, Severity: providers.SeverityError, Message: "mostly fixed"},
+		{Type: providers.IssueTypeStyle, RuleID: "no-magic", Severity: providers.SeverityInfo, Message: "recurrence",
+			Triage: &providers.Triage{Status: history.StatusWontfix, Recurrence: "previously wontfix, was error"}},
+	}}}}}
+	engine.applyCalibration(context.Background(), result)
+
+	got := result.Files[0].Response.Issues
+	if got[0].Severity != providers.SeverityWarning || got[0].Calibration != "lowered from error: 9 of 10 triaged findings of the rule marked won't fix" {
+		t.Errorf("calibrated issue = %+v", got[0])
+	}
+	if got[1].Severity != providers.SeverityInfo || got[1].Calibration == "" {
+		t.Errorf("calibrated info issue = %+v, want kept at info with a note", got[1])
+	}
+	for _, i := range []int{2, 3, 4} {
+		if got[i].Calibration != "" {
+			t.Errorf("issue %q calibrated: %q", got[i].Message, got[i].Calibration)
+		}
+	}
+
+	cfg.Review.Triage.Calibration.Action = "note"
+	issues := []providers.Issue{{Type: providers.IssueTypeStyle, RuleID: "no-magic", Severity: providers.SeverityError}}
+	engine.applyCalibration(context.Background(), &Result{Files: []FileResult{{Response: &providers.ReviewResponse{Issues: issues}}}})
+	if issues[0].Severity != providers.SeverityError || issues[0].Calibration == "" {
+		t.Errorf("noted issue = %+v, want its severity kept with a note", issues[0])
+	}
+}
@@ -0,0 +1,17 @@
+package review
+
+import (
+	"github.com/JNZader/goreview/goreview/internal/diffsummary"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// changeSummary returns the structured summary of the file's change for
+// the prompt, "" when disabled or when the change adds, removes or
+// modifies no function nor dependency.
+func (e *Engine) changeSummary(file git.FileDiff) string {
+	if !e.cfg.Review.ChangeSummary {
+		return ""
+	}
+	content, _ := e.readRepoFile(file.Path)
+	return diffsummary.Summarize(file, content).String()
+}
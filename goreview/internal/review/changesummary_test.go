@@ -0,0 +1,54 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestEngineChangeSummary(t *testing.T) {
+	lines := []git.Line{
+		{Type: git.LineContext, Content: "package a", OldNumber: 1, NewNumber: 1},
+		{Type: git.LineContext, Content: "", OldNumber: 2, NewNumber: 2},
+		{Type: git.LineAddition, Content: "func Added(n int) error {", NewNumber: 3},
+		{Type: git.LineAddition, Content: "\treturn nil", NewNumber: 4},
+		{Type: git.LineAddition, Content: "}", NewNumber: 5},
+	}
+	repo := &MockRepository{
+		StagedDiff: &git.Diff{Files: []git.FileDiff{
+			{Path: "a.go", Language: "go", Status: git.FileModified, Additions: 3, Hunks: []git.Hunk{{Lines: lines}}},
+		}},
+	}
+
+	for _, enabled := range []bool{true, false} {
+		cfg := config.DefaultConfig()
+		cfg.Review.Mode = "staged"
+		cfg.Git.IgnorePatterns = nil
+		cfg.Review.ChangeSummary = enabled
+
+		var summary string
+		provider := &MockProvider{
+			ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+				summary = req.ChangeSummary
+				return &providers.ReviewResponse{Score: 90}, nil
+			},
+		}
+		result, err := NewEngine(cfg, repo, provider, nil, nil).Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		want := ""
+		if enabled {
+			want = "Functions added: func Added(n int) error\n"
+		}
+		if summary != want {
+			t.Errorf("ChangeSummary with change_summary=%v = %q, want %q", enabled, summary, want)
+		}
+		if b := result.Files[0].Budget; b == nil || (b.Summary > 0) != enabled {
+			t.Errorf("Budget with change_summary=%v = %+v", enabled, b)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package review
+
+import (
+	"fmt"
+
+	"github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// Rule IDs of complexity issues
+const (
+	ruleCyclomatic     = "complexity/cyclomatic"
+	ruleCognitive      = "complexity/cognitive"
+	ruleFunctionLength = "complexity/function-length"
+)
+
+// checkComplexity measures the functions changed by the diff, records their
+// metrics in the result and adds an issue for each configured threshold a
+// function exceeds.
+func (e *Engine) checkComplexity(file git.FileDiff, result *FileResult) {
+	cc := e.cfg.Review.Complexity
+	if !cc.Enabled || result.Response == nil {
+		return
+	}
+	content, ok := e.readRepoFile(file.Path)
+	if !ok {
+		return
+	}
+	ctx, err := ast.NewParser(file.Language).Parse(content, file.Path)
+	if err != nil {
+		return
+	}
+
+	changed := changedLines(file)
+	var issues []providers.Issue
+	for _, m := range ast.Measure(ctx, content) {
+		if !touches(changed, m.StartLine, m.EndLine) {
+			continue
+		}
+		result.Metrics = append(result.Metrics, m)
+		issues = append(issues, complexityIssues(file.Path, m, cc)...)
+	}
+	if len(issues) == 0 {
+		return
+	}
+	checked := e.severity.apply(&providers.ReviewResponse{Issues: issues})
+	result.Response.Issues = append(result.Response.Issues, checked.Issues...)
+}
+
+// changedLines returns the new line numbers of the added lines.
+func changedLines(file git.FileDiff) map[int]bool {
+	lines := make(map[int]bool)
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type == git.LineAddition {
+				lines[line.NewNumber] = true
+			}
+		}
+	}
+	return lines
+}
+
+func touches(lines map[int]bool, start, end int) bool {
+	for n := range lines {
+		if n >= start && n <= end {
+			return true
+		}
+	}
+	return false
+}
+
+func complexityIssues(path string, m ast.FunctionMetrics, cc config.ComplexityConfig) []providers.Issue {
+	checks := []struct {
+		rule       string
+		value, max int
+		what       string
+		suggestion string
+	}{
+		{ruleCyclomatic, m.Cyclomatic, cc.MaxCyclomatic, "cyclomatic complexity",
+			"Split the function or replace branching with lookups or early returns"},
+		{ruleCognitive, m.Cognitive, cc.MaxCognitive, "cognitive complexity",
+			"Reduce nesting with early returns and extract nested blocks into functions"},
+		{ruleFunctionLength, m.Length, cc.MaxFunctionLines, "length in lines",
+			"Extract parts of the function into smaller, named functions"},
+	}
+
+	var issues []providers.Issue
+	for _, c := range checks {
+		if c.max <= 0 || c.value <= c.max {
+			continue
+		}
+		issues = append(issues, providers.Issue{
+			ID:         fmt.Sprintf("%s:%s:%d", c.rule, path, m.StartLine),
+			Type:       providers.IssueTypeMaintenance,
+			Severity:   providers.SeverityWarning,
+			Message:    fmt.Sprintf("Function %s has a %s of %d (max %d)", m.Name, c.what, c.value, c.max),
+			Suggestion: c.suggestion,
+			RuleID:     c.rule,
+			Location:   &providers.Location{File: path, StartLine: m.StartLine, EndLine: m.EndLine},
+		})
+	}
+	return issues
+}
@@ -0,0 +1,76 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestCheckComplexity(t *testing.T) {
+	code := `package main
+
+func small() int {
+	return 1
+}
+
+func branchy(a, b, c int) int {
+	if a > 0 {
+		if b > 0 {
+			if c > 0 {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+`
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lines 4 (small) and 10 (branchy) changed
+	file := git.FileDiff{Path: "main.go", Language: "go", Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineAddition, Content: "\treturn 1", NewNumber: 4},
+		{Type: git.LineAddition, Content: "\t\t\tif c > 0 {", NewNumber: 10},
+	}}}}
+
+	cfg := config.DefaultConfig()
+	cfg.Review.Complexity = config.ComplexityConfig{Enabled: true, MaxCyclomatic: 3, MaxCognitive: 5, MaxFunctionLines: 0}
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+
+	result := &FileResult{File: "main.go", Response: &providers.ReviewResponse{}}
+	engine.checkComplexity(file, result)
+
+	if len(result.Metrics) != 2 {
+		t.Fatalf("metrics = %+v, want both changed functions", result.Metrics)
+	}
+	if m := result.Metrics[1]; m.Name != "branchy" || m.Cyclomatic != 4 || m.Cognitive != 6 || m.Length != 10 {
+		t.Errorf("branchy metrics = %+v", m)
+	}
+
+	var rules []string
+	for _, issue := range result.Response.Issues {
+		rules = append(rules, issue.RuleID)
+		if issue.Location.StartLine != 7 || !strings.Contains(issue.Message, "branchy") {
+			t.Errorf("issue = %+v, want it on branchy", issue)
+		}
+	}
+	if strings.Join(rules, ",") != ruleCyclomatic+","+ruleCognitive {
+		t.Errorf("rules = %v, want cyclomatic and cognitive, length disabled", rules)
+	}
+
+	// Unchanged functions are neither measured nor reported
+	file.Hunks[0].Lines = file.Hunks[0].Lines[:1]
+	result = &FileResult{File: "main.go", Response: &providers.ReviewResponse{}}
+	engine.checkComplexity(file, result)
+	if len(result.Metrics) != 1 || len(result.Response.Issues) != 0 {
+		t.Errorf("metrics = %+v, issues = %+v, want only small measured", result.Metrics, result.Response.Issues)
+	}
+}
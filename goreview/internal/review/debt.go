@@ -0,0 +1,63 @@
+package review
+
+import (
+	"fmt"
+
+	"github.com/JNZader/goreview/goreview/internal/debt"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+	"github.com/JNZader/goreview/goreview/internal/spelling"
+)
+
+// ruleUntrackedDebt is the rule ID of debt comments missing a reference
+const ruleUntrackedDebt = "debt/untracked"
+
+// checkDebt records the debt comments added by the diff in the result and
+// adds an issue for each one lacking the owner or ticket the policy requires.
+func (e *Engine) checkDebt(file git.FileDiff, result *FileResult) {
+	if e.debt == nil || result.Response == nil {
+		return
+	}
+	marker := rules.LineCommentMarker(file.Language)
+	if marker == "" {
+		return
+	}
+
+	var issues []providers.Issue
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type != git.LineAddition {
+				continue
+			}
+			_, comment, _ := spelling.SplitComment(line.Content, marker)
+			item, ok := e.debt.Scan(comment)
+			if !ok {
+				continue
+			}
+			item.File, item.Line = file.Path, line.NewNumber
+			result.Debt = append(result.Debt, item)
+			if missing := e.debt.Missing(item); missing != "" {
+				issues = append(issues, debtIssue(item, missing, line.Content))
+			}
+		}
+	}
+	if len(issues) == 0 {
+		return
+	}
+	checked := e.severity.apply(&providers.ReviewResponse{Issues: issues})
+	result.Response.Issues = append(result.Response.Issues, checked.Issues...)
+}
+
+func debtIssue(item debt.Item, missing, code string) providers.Issue {
+	return providers.Issue{
+		ID:         fmt.Sprintf("%s:%s:%d", ruleUntrackedDebt, item.File, item.Line),
+		Type:       providers.IssueTypeMaintenance,
+		Severity:   providers.SeverityWarning,
+		Message:    fmt.Sprintf("%s comment has no %s reference", item.Marker, missing),
+		Suggestion: fmt.Sprintf("Reference an owner or ticket, e.g. %s(alice): or %s(PROJ-123):", item.Marker, item.Marker),
+		RuleID:     ruleUntrackedDebt,
+		Location:   &providers.Location{File: item.File, StartLine: item.Line, EndLine: item.Line},
+		Code:       code,
+	}
+}
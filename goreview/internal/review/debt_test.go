@@ -0,0 +1,45 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestCheckDebt(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Debt.Enabled = true
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+
+	file := git.FileDiff{Path: "main.go", Language: "go", Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineContext, Content: "// TODO: old debt", NewNumber: 1},
+		{Type: git.LineAddition, Content: "x := 1 // TODO: handle overflow", NewNumber: 2},
+		{Type: git.LineAddition, Content: "// FIXME(alice): slow", NewNumber: 3},
+		{Type: git.LineAddition, Content: `s := "TODO: not a comment"`, NewNumber: 4},
+		{Type: git.LineDeletion, Content: "// HACK: removed", OldNumber: 2},
+	}}}}
+	result := &FileResult{File: "main.go", Response: &providers.ReviewResponse{}}
+	engine.checkDebt(file, result)
+
+	if len(result.Debt) != 2 {
+		t.Fatalf("debt = %+v, want the 2 added comments", result.Debt)
+	}
+	if d := result.Debt[1]; d.Line != 3 || d.Marker != "FIXME" || d.Owner != "alice" {
+		t.Errorf("debt[1] = %+v", d)
+	}
+	issues := result.Response.Issues
+	if len(issues) != 1 || issues[0].RuleID != ruleUntrackedDebt || issues[0].Location.StartLine != 2 {
+		t.Errorf("issues = %+v, want one for the untracked TODO on line 2", issues)
+	}
+
+	// Disabled tracking records nothing
+	cfg.Review.Debt.Enabled = false
+	engine = NewEngine(cfg, nil, nil, nil, nil)
+	result = &FileResult{File: "main.go", Response: &providers.ReviewResponse{}}
+	engine.checkDebt(file, result)
+	if len(result.Debt) != 0 || len(result.Response.Issues) != 0 {
+		t.Errorf("disabled tracker found %+v", result)
+	}
+}
@@ -0,0 +1,83 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/docstyle"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/testcheck"
+)
+
+// docStyleFindings returns the documentation findings on the lines added
+// to a file, in the docs review mode, and the file's lines.
+func (e *Engine) docStyleFindings(file git.FileDiff) ([]docstyle.Finding, []string) {
+	if !e.docChecks || testcheck.IsTestFile(file.Path) {
+		return nil, nil
+	}
+	content, ok := e.readRepoFile(file.Path)
+	if !ok {
+		return nil, nil
+	}
+	style := e.cfg.Review.DocStyle.Python
+	if style == "" {
+		style = docstyle.StyleGoogle
+	}
+	findings, err := docstyle.Check(file.Path, file.Language, []byte(content), style)
+	if err != nil {
+		e.log.Debug("Documentation checks skipped for %s: %v", file.Path, err)
+		return nil, nil
+	}
+
+	changed := changedLines(file)
+	var touched []docstyle.Finding
+	for _, f := range findings {
+		if changed[f.Line] {
+			touched = append(touched, f)
+		}
+	}
+	return touched, strings.Split(content, "\n")
+}
+
+// docStyleHints tells the model about the documentation findings, which
+// checkDocStyle reports, so it reviews the rest of the documentation.
+func (e *Engine) docStyleHints(file git.FileDiff) []string {
+	var hints []string
+	findings, _ := e.docStyleFindings(file)
+	for _, f := range findings {
+		hints = append(hints, fmt.Sprintf("line %d (%s, already reported): %s", f.Line, f.Rule, f.Message))
+	}
+	return hints
+}
+
+// checkDocStyle adds the documentation findings on the lines added to a
+// file. It runs in the docs review mode, alongside the model, which judges
+// whether the documentation is accurate and sufficient.
+func (e *Engine) checkDocStyle(file git.FileDiff, result *FileResult) {
+	if result.Response == nil {
+		return
+	}
+	findings, lines := e.docStyleFindings(file)
+	var issues []providers.Issue
+	for _, f := range findings {
+		issue := providers.Issue{
+			ID:         fmt.Sprintf("%s:%s:%d", f.Rule, file.Path, f.Line),
+			Type:       providers.IssueTypeStyle,
+			Severity:   f.Severity,
+			Message:    f.Message,
+			Suggestion: f.Suggestion,
+			RuleID:     f.Rule,
+			Location:   &providers.Location{File: file.Path, StartLine: f.Line, EndLine: f.Line},
+		}
+		if f.Line <= len(lines) {
+			issue.Code = lines[f.Line-1]
+		}
+		issues = append(issues, issue)
+	}
+	if len(issues) == 0 {
+		return
+	}
+	checked := e.severity.apply(&providers.ReviewResponse{Issues: issues})
+	result.Response.Issues = append(result.Response.Issues, checked.Issues...)
+}
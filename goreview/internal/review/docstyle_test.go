@@ -0,0 +1,55 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestCheckDocStyle(t *testing.T) {
+	dir := t.TempDir()
+	src := "def load(path):\n    return open(path)\n\n\ndef save(data):\n    pass\n"
+	if err := os.WriteFile(filepath.Join(dir, "io.py"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Review.Modes = "docs"
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+
+	// Only save is added by the diff
+	file := git.FileDiff{Path: "io.py", Language: "python", Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineAddition, Content: "def save(data):", NewNumber: 5},
+	}}}}
+
+	hints := engine.docStyleHints(file)
+	if len(hints) != 1 || !strings.Contains(hints[0], "line 5 (docs/python-docstring, already reported)") {
+		t.Errorf("hints = %q", hints)
+	}
+	result := &FileResult{File: "io.py", Response: &providers.ReviewResponse{}}
+	engine.checkDocStyle(file, result)
+	issues := result.Response.Issues
+	if len(issues) != 1 {
+		t.Fatalf("issues = %+v, want 1", issues)
+	}
+	if issues[0].RuleID != "docs/python-docstring" || issues[0].Location.StartLine != 5 || issues[0].Code != "def save(data):" ||
+		issues[0].Message != "public function save has no docstring" {
+		t.Errorf("issue = %+v", issues[0])
+	}
+
+	// Without the docs mode nothing is checked
+	cfg.Review.Modes = "security"
+	engine = NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+	result = &FileResult{File: "io.py", Response: &providers.ReviewResponse{}}
+	engine.checkDocStyle(file, result)
+	if len(result.Response.Issues) != 0 || len(engine.docStyleHints(file)) != 0 {
+		t.Errorf("issues = %+v, want none outside the docs mode", result.Response.Issues)
+	}
+}
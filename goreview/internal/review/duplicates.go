@@ -0,0 +1,149 @@
+package review
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"math"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/clones"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+// ruleDuplicateCode is the rule ID of duplicate code issues
+const ruleDuplicateCode = "duplicate-code"
+
+// maxIndexedFileSize skips large files, usually generated, when indexing
+const maxIndexedFileSize = 512 << 10
+
+// buildCloneIndex indexes the repository files in the languages of the
+// reviewed files, so added code can be compared against them. It runs once
+// per review, before the files are reviewed.
+func (e *Engine) buildCloneIndex(files []git.FileDiff) {
+	fsys := e.repoFiles()
+	if !e.cfg.Review.Duplicates.Enabled || fsys == nil {
+		return
+	}
+	languages := make(map[string]bool)
+	for _, f := range files {
+		if f.Language != "" && f.Language != "unknown" {
+			languages[f.Language] = true
+		}
+	}
+	if len(languages) == 0 {
+		return
+	}
+
+	index := clones.NewIndex()
+	indexed := 0
+	_ = fs.WalkDir(fsys, ".", func(rel string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if rel != "." && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if info, infoErr := d.Info(); infoErr != nil || info.Size() > maxIndexedFileSize || e.shouldIgnore(rel) {
+			return nil
+		}
+		data, readErr := fs.ReadFile(fsys, rel)
+		if readErr != nil || bytes.IndexByte(data, 0) >= 0 {
+			return nil
+		}
+		content := string(data)
+		if lang := git.DetectLanguage(rel, content); languages[lang] {
+			index.Add(rel, lang, rules.LineCommentMarker(lang), content)
+			indexed++
+		}
+		return nil
+	})
+	e.log.Debug("Indexed %d files for duplicate code detection", indexed)
+	e.cloneIndex = index
+}
+
+// addedBlock is a run of added lines, uninterrupted by context lines.
+type addedBlock struct {
+	startLine int
+	endLine   int
+	lines     []string
+}
+
+// addedBlocks returns the runs of added lines of the file.
+func addedBlocks(file git.FileDiff) []addedBlock {
+	var blocks []addedBlock
+	for _, hunk := range file.Hunks {
+		var current *addedBlock
+		for _, line := range hunk.Lines {
+			switch line.Type {
+			case git.LineAddition:
+				if current == nil {
+					blocks = append(blocks, addedBlock{startLine: line.NewNumber})
+					current = &blocks[len(blocks)-1]
+				}
+				current.lines = append(current.lines, line.Content)
+				current.endLine = line.NewNumber
+			case git.LineDeletion:
+				// Replaced lines don't split the added block
+			default:
+				current = nil
+			}
+		}
+	}
+	return blocks
+}
+
+// checkDuplicates adds an issue for each block of added code that closely
+// duplicates existing code elsewhere in the repository. The issue names the
+// existing location, so the reviewer can suggest extracting shared code.
+func (e *Engine) checkDuplicates(file git.FileDiff, result *FileResult) {
+	if e.cloneIndex == nil || result.Response == nil {
+		return
+	}
+
+	dc := e.cfg.Review.Duplicates
+	marker := rules.LineCommentMarker(file.Language)
+	var issues []providers.Issue
+	for _, block := range addedBlocks(file) {
+		match := e.cloneIndex.Find(clones.Query{
+			File:          file.Path,
+			Language:      file.Language,
+			Marker:        marker,
+			Code:          strings.Join(block.lines, "\n"),
+			StartLine:     block.startLine,
+			MinTokens:     dc.MinTokens,
+			MinSimilarity: dc.Similarity,
+		})
+		if match != nil {
+			issues = append(issues, duplicateIssue(file.Path, block, match))
+		}
+	}
+	if len(issues) == 0 {
+		return
+	}
+	checked := e.severity.apply(&providers.ReviewResponse{Issues: issues})
+	result.Response.Issues = append(result.Response.Issues, checked.Issues...)
+}
+
+func duplicateIssue(path string, block addedBlock, match *clones.Match) providers.Issue {
+	where := fmt.Sprintf("%s:%d-%d", match.File, match.StartLine, match.EndLine)
+	return providers.Issue{
+		ID:         fmt.Sprintf("%s:%s:%d", ruleDuplicateCode, path, block.startLine),
+		Type:       providers.IssueTypeMaintenance,
+		Severity:   providers.SeverityWarning,
+		Message:    fmt.Sprintf("Added code duplicates existing code in %s (%d%% similar)", where, int(math.Round(match.Similarity*100))),
+		Suggestion: fmt.Sprintf("Extract the shared code into a function used both here and in %s", where),
+		RuleID:     ruleDuplicateCode,
+		Location:   &providers.Location{File: path, StartLine: block.startLine, EndLine: block.endLine},
+		Code:       strings.Join(block.lines, "\n"),
+	}
+}
@@ -0,0 +1,99 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestCheckDuplicates(t *testing.T) {
+	body := []string{
+		"func sum(items []Item) (int, error) {",
+		"	total := 0",
+		"	for _, item := range items {",
+		"		if item.Count < 0 {",
+		"			return 0, fmt.Errorf(\"negative count for %s\", item.Name)",
+		"		}",
+		"		total += item.Price * item.Count",
+		"	}",
+		"	return total, nil",
+		"}",
+	}
+	dir := t.TempDir()
+	existing := "package shop\n\n" + strings.Join(body, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "shop.go"), []byte(existing), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "vendor"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "vendor", "copy.go"), []byte(existing), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := []git.Line{{Type: git.LineContext, Content: "package cart", NewNumber: 1}}
+	for i, content := range body {
+		lines = append(lines, git.Line{Type: git.LineAddition, Content: content, NewNumber: i + 2})
+	}
+	file := git.FileDiff{Path: "cart.go", Language: "go", Hunks: []git.Hunk{{Lines: lines}}}
+
+	cfg := config.DefaultConfig()
+	cfg.Review.Duplicates = config.DuplicatesConfig{Enabled: true, MinTokens: 20, Similarity: 0.8}
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+	engine.buildCloneIndex([]git.FileDiff{file})
+
+	result := &FileResult{File: "cart.go", Response: &providers.ReviewResponse{}}
+	engine.checkDuplicates(file, result)
+
+	issues := result.Response.Issues
+	if len(issues) != 1 {
+		t.Fatalf("issues = %+v, want 1", issues)
+	}
+	issue := issues[0]
+	if issue.RuleID != ruleDuplicateCode || issue.Type != providers.IssueTypeMaintenance {
+		t.Errorf("issue = %+v", issue)
+	}
+	if !strings.Contains(issue.Message, "shop.go:3-") {
+		t.Errorf("message = %q, want the existing location", issue.Message)
+	}
+	if issue.Location.StartLine != 2 || issue.Location.EndLine != 11 {
+		t.Errorf("location = %+v, want lines 2-11", issue.Location)
+	}
+
+	// Disabled detection builds no index
+	cfg.Review.Duplicates.Enabled = false
+	engine = NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+	engine.buildCloneIndex([]git.FileDiff{file})
+	result = &FileResult{File: "cart.go", Response: &providers.ReviewResponse{}}
+	engine.checkDuplicates(file, result)
+	if len(result.Response.Issues) != 0 {
+		t.Errorf("issues = %d, want none when disabled", len(result.Response.Issues))
+	}
+}
+
+func TestAddedBlocks(t *testing.T) {
+	file := git.FileDiff{Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineAddition, Content: "a", NewNumber: 1},
+		{Type: git.LineDeletion, Content: "old"},
+		{Type: git.LineAddition, Content: "b", NewNumber: 2},
+		{Type: git.LineContext, Content: "c", NewNumber: 3},
+		{Type: git.LineAddition, Content: "d", NewNumber: 4},
+	}}}}
+	blocks := addedBlocks(file)
+	if len(blocks) != 2 {
+		t.Fatalf("blocks = %+v, want 2", blocks)
+	}
+	if blocks[0].startLine != 1 || blocks[0].endLine != 2 || len(blocks[0].lines) != 2 {
+		t.Errorf("first block = %+v, want lines 1-2", blocks[0])
+	}
+	if blocks[1].startLine != 4 || blocks[1].endLine != 4 {
+		t.Errorf("second block = %+v, want line 4", blocks[1])
+	}
+}
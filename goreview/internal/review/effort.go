@@ -0,0 +1,102 @@
+package review
+
+import (
+	"math"
+	"sort"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// Review effort model, in minutes of a human reviewer's time
+const (
+	// effortPerFile is the cost of switching to a file and reading its context
+	effortPerFile = 1.0
+	// effortLinesPerMinute is a careful review pace, about 300 lines an hour
+	effortLinesPerMinute = 5.0
+	// effortPerComplexity is added per cyclomatic point of a changed
+	// function above effortSimpleFunction
+	effortPerComplexity  = 0.5
+	effortSimpleFunction = 5
+)
+
+// effortPerIssue is the time to confirm and discuss a finding
+var effortPerIssue = map[providers.Severity]float64{
+	providers.SeverityCritical: 5,
+	providers.SeverityError:    3,
+	providers.SeverityWarning:  1,
+	providers.SeverityInfo:     0.5,
+}
+
+// Effort estimates the human effort of reviewing the change, to plan review
+// assignments. Files are listed in the suggested review order.
+type Effort struct {
+	Minutes int          `json:"minutes"`
+	Files   []FileEffort `json:"files"`
+}
+
+// FileEffort is the estimated effort of reviewing one file.
+type FileEffort struct {
+	File    string `json:"file"`
+	Minutes int    `json:"minutes"`
+	// Lines counts the added and deleted lines
+	Lines int `json:"lines"`
+	// Complexity is the cyclomatic complexity of the changed functions,
+	// when measured
+	Complexity int `json:"complexity,omitempty"`
+	Issues     int `json:"issues"`
+	// Severity is the worst severity among the file's issues
+	Severity string `json:"severity,omitempty"`
+}
+
+// estimateEffort estimates the review effort from each file's diff size,
+// the complexity of its changed functions and its issues. The suggested
+// order puts the files with the worst findings first, then the biggest.
+func estimateEffort(files []git.FileDiff, result *Result) *Effort {
+	lines := make(map[string]int, len(files))
+	for _, f := range files {
+		lines[f.Path] = f.Additions + f.Deletions
+	}
+
+	effort := &Effort{}
+	total := 0.0
+	for _, file := range result.Files {
+		fe := FileEffort{File: file.File, Lines: lines[file.File]}
+		minutes := effortPerFile + float64(fe.Lines)/effortLinesPerMinute
+		for _, m := range file.Metrics {
+			fe.Complexity += m.Cyclomatic
+			minutes += float64(max(m.Cyclomatic-effortSimpleFunction, 0)) * effortPerComplexity
+		}
+		var worst providers.Severity
+		if file.Response != nil {
+			for _, issue := range file.Response.Issues {
+				fe.Issues++
+				minutes += effortPerIssue[issue.Severity]
+				if issue.Severity.Rank() > worst.Rank() {
+					worst = issue.Severity
+				}
+			}
+		}
+		fe.Severity = string(worst)
+		fe.Minutes = int(math.Ceil(minutes))
+		total += minutes
+		effort.Files = append(effort.Files, fe)
+	}
+	effort.Minutes = int(math.Ceil(total))
+	sortEffort(effort)
+	return effort
+}
+
+// sortEffort puts the files with the worst findings first, then the biggest.
+func sortEffort(effort *Effort) {
+	sort.SliceStable(effort.Files, func(i, j int) bool {
+		a, b := effort.Files[i], effort.Files[j]
+		if ra, rb := providers.Severity(a.Severity).Rank(), providers.Severity(b.Severity).Rank(); ra != rb {
+			return ra > rb
+		}
+		if a.Minutes != b.Minutes {
+			return a.Minutes > b.Minutes
+		}
+		return a.File < b.File
+	})
+}
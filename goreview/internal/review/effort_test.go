@@ -0,0 +1,49 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestEstimateEffort(t *testing.T) {
+	files := []git.FileDiff{
+		{Path: "big.go", Additions: 90, Deletions: 10},
+		{Path: "auth.go", Additions: 8, Deletions: 2},
+		{Path: "small.go", Additions: 4},
+	}
+	result := &Result{Files: []FileResult{
+		{File: "big.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{
+			{Severity: providers.SeverityWarning},
+		}}, Metrics: []ast.FunctionMetrics{{Name: "Parse", Cyclomatic: 15}}},
+		{File: "auth.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{
+			{Severity: providers.SeverityInfo},
+			{Severity: providers.SeverityCritical},
+		}}},
+		{File: "small.go", Response: &providers.ReviewResponse{}},
+	}}
+
+	effort := estimateEffort(files, result)
+
+	// big.go: 1 + 100/5 + (15-5)*0.5 + 1 = 27
+	// auth.go: 1 + 10/5 + 0.5 + 5 = 8.5
+	// small.go: 1 + 4/5 = 1.8
+	if effort.Minutes != 38 {
+		t.Errorf("Minutes = %d, want 38", effort.Minutes)
+	}
+	want := []FileEffort{
+		{File: "auth.go", Minutes: 9, Lines: 10, Issues: 2, Severity: "critical"},
+		{File: "big.go", Minutes: 27, Lines: 100, Complexity: 15, Issues: 1, Severity: "warning"},
+		{File: "small.go", Minutes: 2, Lines: 4},
+	}
+	if len(effort.Files) != len(want) {
+		t.Fatalf("Files = %+v, want %+v", effort.Files, want)
+	}
+	for i := range want {
+		if effort.Files[i] != want[i] {
+			t.Errorf("Files[%d] = %+v, want %+v", i, effort.Files[i], want[i])
+		}
+	}
+}
@@ -2,16 +2,32 @@ package review
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/JNZader/goreview/goreview/internal/ast"
 	"github.com/JNZader/goreview/goreview/internal/cache"
+	"github.com/JNZader/goreview/goreview/internal/clones"
 	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/debt"
+	"github.com/JNZader/goreview/goreview/internal/failure"
 	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/ignore"
 	"github.com/JNZader/goreview/goreview/internal/logger"
+	"github.com/JNZader/goreview/goreview/internal/progress"
+	"github.com/JNZader/goreview/goreview/internal/provenance"
 	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rag"
 	"github.com/JNZader/goreview/goreview/internal/rules"
+	"github.com/JNZader/goreview/goreview/internal/scope"
+	"github.com/JNZader/goreview/goreview/internal/spelling"
+	"github.com/JNZader/goreview/goreview/internal/tokenizer"
 	"github.com/JNZader/goreview/goreview/internal/worker"
 )
 
@@ -25,6 +41,91 @@ type Engine struct {
 	cache    cache.Cache
 	rules    []rules.Rule
 	log      *logger.Logger
+
+	// Model-derived limits
+	caps           providers.ModelCapabilities
+	responseTokens int
+	maxChunkTokens int
+	estimator      *tokenizer.Estimator
+
+	// repoRoot is the working tree, or the directory of a bare repository
+	repoRoot string
+	// files holds the reviewed commit of a bare repository, see repoFiles
+	files fs.FS
+
+	// prior holds the previous branch review's issues by file and hunk
+	// fingerprint, for incremental re-review
+	prior map[string]map[string][]providers.Issue
+
+	// Severity remapping and the custom issue type taxonomy
+	severity   severityPolicy
+	issueTypes []providers.IssueTypeInfo
+
+	// limiter tunes provider concurrency when adaptive concurrency is enabled
+	limiter *worker.AdaptiveLimiter
+
+	// version is the goreview version recorded in the result environment
+	version string
+	// conventions are the team conventions given to the reviewer
+	conventions []string
+	// intent is what the pull request under review says about the change
+	intent string
+
+	// progress tracks queued, in-flight and completed files; nil disables it
+	progress *progress.Tracker
+	// speller checks spelling and terminology when enabled; nil disables it
+	speller *spelling.Checker
+	// cloneIndex holds the repository's code for duplicate detection; nil
+	// disables it
+	cloneIndex *clones.Index
+	// debt finds debt comments when the tracker is enabled; nil disables it
+	debt *debt.Scanner
+	// testChecks enables the flakiness and assertion gap checks of the
+	// tests review mode
+	testChecks bool
+	// errorChecks enables the Go error handling checks of the errors
+	// review mode
+	errorChecks bool
+	// packs are the knowledge packs matched against each file's imports;
+	// empty when disabled
+	packs []rag.Pack
+	// concurrencyFocus points the model at the regions the Go concurrency
+	// heuristics find suspicious, in the concurrency review mode
+	concurrencyFocus bool
+	// taintChecks follows user input to injection sinks in the security
+	// review mode
+	taintChecks bool
+	// docChecks enables the documentation style checks of the docs review
+	// mode
+	docChecks bool
+	// astDiagnostics records the AST extraction coverage of each file,
+	// see DiagnoseAST
+	astDiagnostics bool
+	// generated finds AI-generated and pasted blocks when enabled; nil
+	// disables it
+	generated *provenance.Detector
+	// protected are the protected path areas whose issues get escalated
+	protected []protectedArea
+	// ignore selects the changed files to review; filtered records the
+	// files it left out, see filter.go
+	ignore   *ignore.Matcher
+	filtered []FilteredFile
+	// formats notes the files not reviewed as plain source, like notebooks
+	// and templates, see formats.go
+	formats map[string]string
+	// transcripts saves the provider exchanges of each file; nil disables it
+	transcripts TranscriptWriter
+	// triage recognizes recurrences of triaged issues; nil disables it
+	triage TriageLookup
+	// calibration lowers the findings of rules the team ignores; nil
+	// disables it
+	calibration OutcomeLookup
+	// deadline ends the review with review.time_budget; zero without one
+	deadline time.Time
+
+	// stats counts provider calls and times the phases of the run
+	stats   RunStats
+	statsMu sync.Mutex
 }
 
 // NewEngine creates a new review engine.
@@ -35,14 +136,107 @@ func NewEngine(
 	c cache.Cache,
 	r []rules.Rule,
 ) *Engine {
-	return &Engine{
-		cfg:      cfg,
-		gitRepo:  gitRepo,
-		provider: provider,
-		cache:    c,
-		rules:    r,
-		log:      logger.Default().WithPrefix("ENGINE"),
+	e := &Engine{
+		cfg:        cfg,
+		gitRepo:    gitRepo,
+		provider:   provider,
+		cache:      c,
+		rules:      r,
+		log:        logger.Default().WithPrefix("ENGINE"),
+		estimator:  tokenizer.NewEstimatorForModel(cfg.Provider.Model),
+		severity:   newSeverityPolicy(cfg.Review),
+		issueTypes: issueTypes(cfg.Review),
+		protected:  protectedAreas(cfg.Review),
+	}
+	// Run reads the ignore file once the repository root is known
+	e.ignore, _ = ignore.New(config.GitConfig{IncludePatterns: cfg.Git.IncludePatterns, IgnorePatterns: cfg.Git.IgnorePatterns}, "")
+	if sc := cfg.Review.Spelling; sc.Enabled {
+		e.speller = spelling.New(sc.Dictionary, sc.Terms)
+	}
+	if dc := cfg.Review.Debt; dc.Enabled {
+		scanner, err := debt.NewScanner(dc.Markers, dc.Require, dc.TicketPattern)
+		if err != nil {
+			e.log.Warn("Debt tracking disabled: %v", err)
+		}
+		e.debt = scanner
+	}
+	if pc := cfg.RAG.Packs; pc.Enabled {
+		packs, err := rag.LoadPacks(pc.Dirs, pc.Exclude)
+		if err != nil {
+			e.log.Warn("Knowledge packs disabled: %v", err)
+		}
+		e.packs = packs
+	}
+	if gc := cfg.Review.Generated; gc.Enabled {
+		detector, err := provenance.NewDetector(gc.Markers, gc.MinPasteLines)
+		if err != nil {
+			e.log.Warn("Generated code detection disabled: %v", err)
+		}
+		e.generated = detector
+	}
+	for _, m := range providers.ParseModes(cfg.Review.Modes) {
+		e.testChecks = e.testChecks || m == providers.ModeTests
+		e.errorChecks = e.errorChecks || m == providers.ModeErrors
+		e.concurrencyFocus = e.concurrencyFocus || m == providers.ModeConcurrency
+		e.taintChecks = e.taintChecks || m == providers.ModeSecurity
+		e.docChecks = e.docChecks || m == providers.ModeDocs
+	}
+	e.applyModelLimits()
+	e.limiter = e.newAdaptiveLimiter()
+	return e
+}
+
+// SetConventions gives the team conventions learned from goreview fix to
+// the reviewer.
+func (e *Engine) SetConventions(conventions []string) {
+	e.conventions = conventions
+}
+
+// SetIntent gives the reviewer the intent of the change: the description,
+// linked issues and review comments of its pull request.
+func (e *Engine) SetIntent(intent string) {
+	e.intent = intent
+}
+
+// SetProgress reports per-file review progress to the tracker.
+func (e *Engine) SetProgress(t *progress.Tracker) {
+	e.progress = t
+}
+
+// newAdaptiveLimiter returns the provider concurrency limiter, or nil when
+// adaptive concurrency is disabled.
+func (e *Engine) newAdaptiveLimiter() *worker.AdaptiveLimiter {
+	ac := e.cfg.Review.AdaptiveConcurrency
+	if !ac.Enabled {
+		return nil
+	}
+	var maxMemory uint64
+	if ac.MaxMemoryMB > 0 {
+		maxMemory = uint64(ac.MaxMemoryMB) << 20
 	}
+	return worker.NewAdaptiveLimiter(worker.AdaptiveConfig{
+		Initial:       e.calculateOptimalConcurrency(),
+		Min:           ac.MinWorkers,
+		Max:           ac.MaxWorkers,
+		TargetLatency: ac.TargetLatency,
+		MaxMemory:     maxMemory,
+		IsThrottled:   providers.IsRateLimitError,
+	})
+}
+
+// applyModelLimits consults the model capabilities registry to derive the
+// response token limit and the maximum diff chunk size per request.
+// Explicit review.max_chunk_tokens always wins over the derived value.
+func (e *Engine) applyModelLimits() {
+	e.caps = providers.ResolveCapabilities(&e.cfg.Provider)
+	e.responseTokens = e.caps.ResponseTokens(e.cfg.Provider.MaxTokens)
+
+	e.maxChunkTokens = e.cfg.Review.MaxChunkTokens
+	if e.maxChunkTokens <= 0 {
+		e.maxChunkTokens = e.caps.MaxChunkTokens(e.responseTokens)
+	}
+	e.log.Debug("Model %q: context=%d, response=%d, chunk=%d tokens",
+		e.cfg.Provider.Model, e.caps.ContextWindow, e.responseTokens, e.maxChunkTokens)
 }
 
 // Result contains the complete review results.
@@ -52,6 +246,52 @@ type Result struct {
 	Files       []FileResult  `json:"files"`
 	Stats       git.DiffStats `json:"stats"`
 	Summary     string        `json:"summary,omitempty"`
+	// Filtered lists the changed files left out of the review and why
+	Filtered []FilteredFile `json:"filtered_files,omitempty"`
+	// IssueTypes is the custom issue type taxonomy, when configured
+	IssueTypes []providers.IssueTypeInfo `json:"issue_types,omitempty"`
+	// Environment records what produced the review, for reproducibility
+	Environment *history.Environment `json:"environment,omitempty"`
+	// Quality tells how reliable the results are, see Quality
+	Quality *Quality `json:"quality,omitempty"`
+	// Effort estimates the human review effort, see Effort
+	Effort *Effort `json:"review_effort,omitempty"`
+	// Scope flags unrelated changes bundled together, see internal/scope
+	Scope *scope.Report `json:"change_scope,omitempty"`
+	// Gates are the outcomes of the configured CI gates
+	Gates []GateResult `json:"gates,omitempty"`
+	// Shard is the part of the changed files reviewed, with review.shard
+	Shard *Shard `json:"shard,omitempty"`
+	// Unreviewed lists the files left unreviewed when review.time_budget
+	// ran out, riskiest first
+	Unreviewed []UnreviewedFile `json:"unreviewed_files,omitempty"`
+	// Remainder are the files over review.token_budget, summarized
+	// instead of reviewed
+	Remainder *Remainder `json:"summarized_remainder,omitempty"`
+	// Themes group related issues when there are many, see AssignThemes
+	Themes []Theme `json:"themes,omitempty"`
+	// Errors are the review's failures with their codes, see collectErrors
+	Errors []failure.Info `json:"errors,omitempty"`
+}
+
+// GateResult is the outcome of a CI gate, see internal/gate.
+type GateResult struct {
+	Name   string `json:"name"`
+	Expr   string `json:"expr"`
+	Passed bool   `json:"passed"`
+	// Error is why the expression couldn't be evaluated; the gate fails
+	Error string `json:"error,omitempty"`
+}
+
+// GatesFailed returns the names of the gates that didn't pass.
+func (r *Result) GatesFailed() []string {
+	var failed []string
+	for _, g := range r.Gates {
+		if !g.Passed {
+			failed = append(failed, g.Name)
+		}
+	}
+	return failed
 }
 
 // FileResult contains review results for a single file.
@@ -60,6 +300,42 @@ type FileResult struct {
 	Response *providers.ReviewResponse `json:"response,omitempty"`
 	Error    error                     `json:"error,omitempty"`
 	Cached   bool                      `json:"cached"`
+	// Normalized is set when the cache hit matched only after diff normalization
+	Normalized bool `json:"normalized,omitempty"`
+	// RulesInScope lists the IDs of the rules that applied to the file
+	RulesInScope []string `json:"rules_in_scope,omitempty"`
+	// ReusedHunks counts hunks unchanged since the previous branch review,
+	// whose findings were carried over instead of sent to the provider
+	ReusedHunks int `json:"reused_hunks,omitempty"`
+	// Metrics holds the size and complexity of the changed functions
+	Metrics []ast.FunctionMetrics `json:"metrics,omitempty"`
+	// Debt lists the TODO/FIXME/HACK comments added to the file
+	Debt []debt.Item `json:"debt,omitempty"`
+	// Generated lists the added blocks that look AI-generated or pasted
+	Generated []provenance.Block `json:"generated,omitempty"`
+	// Protected names the protected path area containing the file
+	Protected string `json:"protected,omitempty"`
+	// Budget tells how the file's review requests spent the context
+	// window; files answered from the cache have none
+	Budget *ContextBudget `json:"context_budget,omitempty"`
+	// Guardrail is set when a guardrail summarized or skipped the file
+	// instead of reviewing it
+	Guardrail *Guardrail `json:"guardrail,omitempty"`
+	// Trivial is set when the file's change was trivial and approved
+	// without a review by the model
+	Trivial *Trivial `json:"trivial,omitempty"`
+	// AST tells how much structure the parser extracted from the file,
+	// with DiagnoseAST
+	AST *ast.Coverage `json:"ast,omitempty"`
+	// Hunks are the new-side line ranges of the file's diff, for anchoring
+	// review comments
+	Hunks []LineRange `json:"-"`
+
+	// hunkIssues maps hunk fingerprints to their issues in incremental mode
+	hunkIssues map[string][]providers.Issue
+	// unreviewed is the reason the file wasn't reviewed, like the time
+	// budget running out before its review finished; see UnreviewedFile
+	unreviewed string
 }
 
 // reviewTask implements worker.Task for file reviews
@@ -84,7 +360,30 @@ func (t *reviewTask) ID() string {
 }
 
 func (t *reviewTask) Execute(ctx context.Context) error {
-	result := t.engine.reviewFile(ctx, t.file)
+	if deadline := t.engine.deadline; !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	t.engine.progress.Begin(t.file.Path)
+	var result *FileResult
+	if ctx.Err() == nil {
+		result = t.engine.reviewFile(ctx, t.file)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && (result == nil || result.Error != nil) {
+		result = &FileResult{File: t.file.Path, unreviewed: UnreviewedTimeBudget}
+	}
+	if result == nil {
+		// Cancelled before the review started
+		result = &FileResult{File: t.file.Path, Error: ctx.Err()}
+	}
+	result.Hunks = hunkRanges(t.file)
+	result.AST = t.engine.astCoverage(t.file)
+	tokens := 0
+	if result.Response != nil && !result.Cached {
+		tokens = result.Response.TokensUsed
+	}
+	t.engine.progress.Done(t.file.Path, tokens, result.Error != nil)
 	t.resultMu.Lock()
 	t.result = result
 	t.resultMu.Unlock()
@@ -105,55 +404,121 @@ func (e *Engine) Run(ctx context.Context) (*Result, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get diff: %w", err)
 	}
+	phase := e.recordPhase("diff", start)
 
 	if len(diff.Files) == 0 {
 		e.log.Info("No changes found to review")
 		return &Result{Summary: "No changes found to review."}, nil
 	}
 
-	filesToReview := e.filterFiles(diff.Files)
-	if len(filesToReview) == 0 {
+	if root, rootErr := e.gitRepo.GetRepoRoot(ctx); rootErr == nil {
+		e.repoRoot = root
+	}
+	if repo, ok := e.gitRepo.(treeRepository); ok && repo.IsBare() {
+		e.files = repo.Files(e.reviewedRevision())
+		e.ignore, err = ignore.NewFS(e.cfg.Git, e.files)
+	} else {
+		e.ignore, err = ignore.New(e.cfg.Git, e.repoRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	allFiles := e.focusFiles(e.prepareFormats(e.filterFiles(diff.Files)))
+	if len(allFiles) == 0 {
 		e.log.Info("No reviewable files in changes")
-		return &Result{Summary: "No reviewable files in changes."}, nil
+		return &Result{Summary: "No reviewable files in changes.", Filtered: e.filtered}, nil
+	}
+	// Checks across files, like clones and test changes, still see the
+	// whole change in a shard
+	filesToReview, shard := shardFiles(e.cfg.Review.Shard, allFiles)
+	if len(filesToReview) == 0 {
+		e.log.Info("No files in shard %s", e.cfg.Review.Shard)
+		return &Result{
+			Summary: fmt.Sprintf("No files in shard %s.", e.cfg.Review.Shard), Stats: diff.Stats,
+			Filtered: e.filtered, Shard: shard, Scope: scope.Analyze(allFiles),
+		}, nil
+	}
+	e.buildCloneIndex(allFiles)
+	phase = e.recordPhase("index", phase)
+	if budget := e.cfg.Review.TimeBudget; budget > 0 {
+		e.deadline = start.Add(budget)
+		filesToReview = e.prioritizeFiles(filesToReview)
 	}
 
-	pool, tasks := e.startReviewPool(filesToReview)
+	reviewed, remainder := e.splitTokenBudget(filesToReview)
+	pool, tasks := e.startReviewPool(reviewed)
 
 	finalResult := &Result{
-		Stats: diff.Stats,
-		Files: make([]FileResult, 0, len(filesToReview)),
+		Stats:      diff.Stats,
+		Files:      make([]FileResult, 0, len(reviewed)),
+		Filtered:   e.filtered,
+		IssueTypes: e.issueTypes,
+		Shard:      shard,
 	}
+	finalResult.Environment = e.environment()
 
 	if err := e.collectResults(ctx, pool, tasks, finalResult); err != nil {
 		return nil, err
 	}
 
 	pool.StopWait()
+	finalResult.Remainder = e.summarizeRemainder(ctx, remainder)
+	phase = e.recordPhase("review", phase)
+	e.checkAssertionGaps(allFiles, finalResult)
+	e.checkGeneratedTests(finalResult)
+	e.escalateProtected(finalResult)
+	e.applyTriage(ctx, finalResult)
+	e.applyCalibration(ctx, finalResult)
+	e.recordPhase("checks", phase)
 	finalResult.Duration = time.Since(start)
+	finalResult.Quality = assessQuality(finalResult)
+	finalResult.Errors = collectErrors(finalResult)
+	finalResult.Effort = estimateEffort(filesToReview, finalResult)
+	finalResult.Scope = scope.Analyze(allFiles)
+	AssignThemes(finalResult, e.cfg.Review.Themes)
 
 	e.log.Info("Review completed: %d files, %d issues, %d errors in %v",
 		len(finalResult.Files), finalResult.TotalIssues, pool.Stats().Errors, finalResult.Duration)
+	if len(finalResult.Unreviewed) > 0 {
+		sortUnreviewed(finalResult.Unreviewed)
+		if n := countUnreviewed(finalResult.Unreviewed, UnreviewedTimeBudget); n > 0 {
+			e.log.Warn("Time budget of %v ran out: %d files not reviewed", e.cfg.Review.TimeBudget, n)
+		}
+	}
+	if e.limiter != nil {
+		e.log.Info("Adaptive concurrency: %s", e.limiter.Stats())
+	}
 
 	return finalResult, nil
 }
 
 // startReviewPool initializes the worker pool and submits all review tasks
 func (e *Engine) startReviewPool(files []git.FileDiff) (*worker.Pool, []*reviewTask) {
-	e.log.Info("Reviewing %d files with %d workers", len(files), e.calculateOptimalConcurrency())
+	workers := e.calculateOptimalConcurrency()
+	if e.limiter != nil {
+		// Start enough workers for the upper bound; the limiter gates provider calls
+		e.log.Info("Reviewing %d files with adaptive concurrency (%d, max %d)",
+			len(files), e.limiter.Limit(), e.cfg.Review.AdaptiveConcurrency.MaxWorkers)
+		workers = max(e.cfg.Review.AdaptiveConcurrency.MaxWorkers, workers)
+	} else {
+		e.log.Info("Reviewing %d files with %d workers", len(files), workers)
+	}
 
 	poolCfg := worker.Config{
-		Workers:   e.calculateOptimalConcurrency(),
+		Workers:   workers,
 		QueueSize: len(files),
 	}
 	pool := worker.NewPool(poolCfg)
 	pool.Start()
 
+	e.progress.Queue(len(files))
 	tasks := make([]*reviewTask, 0, len(files))
 	for _, file := range files {
 		task := newReviewTask(file, e)
 		tasks = append(tasks, task)
 		if err := pool.Submit(task); err != nil {
 			e.log.Error("Failed to submit task for %s: %v", file.Path, err)
+			e.progress.Done(file.Path, 0, true)
 		}
 	}
 	return pool, tasks
@@ -185,65 +550,95 @@ func (e *Engine) processTaskResult(tasks []*reviewTask, taskID string, result *R
 		if fileResult == nil {
 			break
 		}
+		if fileResult.unreviewed != "" {
+			result.Unreviewed = append(result.Unreviewed, UnreviewedFile{
+				File: fileResult.File, Reason: fileResult.unreviewed, Risk: e.riskScore(task.file),
+			})
+			break
+		}
 		result.Files = append(result.Files, *fileResult)
 		if fileResult.Response != nil {
 			result.TotalIssues += len(fileResult.Response.Issues)
 		}
-		if fileResult.Cached {
+		if fileResult.Normalized {
+			e.log.Debug("Cache hit (normalized) for %s", fileResult.File)
+		} else if fileResult.Cached {
 			e.log.Debug("Cache hit for %s", fileResult.File)
 		}
 		break
 	}
 }
 
+// treeRepository is a repository without a working tree, whose files are
+// read from commits, like a git.Repo of a bare repository.
+type treeRepository interface {
+	IsBare() bool
+	Files(rev string) *git.TreeFS
+}
+
+// repoFiles returns the reviewed files: the reviewed commit of a bare
+// repository, or the working tree. It is nil without a repository.
+func (e *Engine) repoFiles() fs.FS {
+	if e.files != nil {
+		return e.files
+	}
+	if e.repoRoot != "" {
+		return os.DirFS(e.repoRoot)
+	}
+	return nil
+}
+
+// reviewedRevision is the commit whose files are reviewed in a repository
+// without a working tree: the reviewed commit, or the end of a range.
+func (e *Engine) reviewedRevision() string {
+	rev := ""
+	if e.cfg.Review.Mode == "commit" {
+		rev = e.cfg.Review.Commit
+		if i := strings.LastIndex(rev, ".."); i >= 0 {
+			rev = strings.TrimPrefix(rev[i+2:], ".")
+		}
+	}
+	if rev == "" {
+		return "HEAD"
+	}
+	return rev
+}
+
 func (e *Engine) getDiff(ctx context.Context) (*git.Diff, error) {
 	switch e.cfg.Review.Mode {
 	case "staged":
-		return e.gitRepo.GetStagedDiff(ctx)
+		diff, err := e.gitRepo.GetStagedDiff(ctx)
+		if err != nil || !e.cfg.Review.IncludeUntracked {
+			return diff, err
+		}
+		return git.AddUntracked(ctx, e.gitRepo, diff)
+	case "worktree":
+		repo, ok := e.gitRepo.(git.WorktreeRepository)
+		if !ok {
+			return nil, git.ErrNoWorktree
+		}
+		return repo.GetWorktreeDiff(ctx)
 	case "commit":
 		return e.gitRepo.GetCommitDiff(ctx, e.cfg.Review.Commit)
 	case "branch":
 		return e.gitRepo.GetBranchDiff(ctx, e.cfg.Git.BaseBranch)
 	case "files":
-		return e.gitRepo.GetFileDiff(ctx, e.cfg.Review.Files)
-	default:
-		return nil, fmt.Errorf("unknown review mode: %s", e.cfg.Review.Mode)
-	}
-}
-
-func (e *Engine) filterFiles(files []git.FileDiff) []git.FileDiff {
-	result := make([]git.FileDiff, 0, len(files))
-	for _, f := range files {
-		// Skip deleted and binary files
-		if f.Status == git.FileDeleted || f.IsBinary {
-			continue
-		}
-		// Skip ignored patterns
-		if e.shouldIgnore(f.Path) {
-			e.log.Debug("Ignoring file: %s", f.Path)
-			continue
+		if e.cfg.Review.Full {
+			return e.fullFileDiff(ctx, e.cfg.Review.Files)
 		}
-		result = append(result, f)
-	}
-	return result
-}
-
-func (e *Engine) shouldIgnore(path string) bool {
-	for _, pattern := range e.cfg.Git.IgnorePatterns {
-		if matchPattern(pattern, path) {
-			return true
+		// Untracked files have no diff; named ones are reviewed whole
+		diff, err := e.gitRepo.GetFileDiff(ctx, e.cfg.Review.Files)
+		if err != nil {
+			return nil, err
 		}
+		return git.AddUntracked(ctx, e.gitRepo, diff, e.cfg.Review.Files...)
+	case "patch":
+		// The repository is a git.PatchRepo, which serves the patch as
+		// staged changes
+		return e.gitRepo.GetStagedDiff(ctx)
+	default:
+		return nil, fmt.Errorf("unknown review mode: %s", e.cfg.Review.Mode)
 	}
-	return false
-}
-
-func matchPattern(pattern, path string) bool {
-	// Simple glob matching
-	if len(pattern) > 0 && pattern[len(pattern)-1] == '*' {
-		prefix := pattern[:len(pattern)-1]
-		return len(path) >= len(prefix) && path[:len(prefix)] == prefix
-	}
-	return pattern == path
 }
 
 func (e *Engine) calculateOptimalConcurrency() int {
@@ -254,30 +649,78 @@ func (e *Engine) calculateOptimalConcurrency() int {
 }
 
 func (e *Engine) reviewFile(ctx context.Context, file git.FileDiff) *FileResult {
+	inScope := e.rulesFor(file)
+	result := e.approveTrivial(file, inScope)
+	if result == nil {
+		tokens := func() int { return e.estimator.EstimateTokens(formatDiff(file)) }
+		if g := guardrail(e.cfg.Review.Guardrails, file, tokens); g != nil {
+			return e.guardFile(ctx, file, g)
+		}
+		if e.cfg.Review.Incremental {
+			result = e.reviewIncremental(ctx, file, inScope)
+		} else {
+			result = e.reviewDiff(ctx, file, inScope)
+		}
+	}
+	e.checkLicenseHeaders(file, inScope, result)
+	e.checkSpelling(file, result)
+	e.checkDuplicates(file, result)
+	e.checkComplexity(file, result)
+	e.checkDebt(file, result)
+	e.checkFlakiness(file, result)
+	e.checkGoErrors(file, result)
+	e.checkTaint(file, result)
+	e.checkDocStyle(file, result)
+	e.labelGenerated(file, result)
+	return result
+}
+
+// reviewDiff reviews all hunks of the file with the given rules in scope.
+func (e *Engine) reviewDiff(ctx context.Context, file git.FileDiff, inScope []rules.Rule) *FileResult {
 	// Build review request
+	policy, generatedFocus := e.generatedPolicy(file)
+	focus := append(e.concurrencyRegions(file), e.taintHints(file)...)
+	focus = append(focus, e.docStyleHints(file)...)
+	knowledge, knowledgeCut := e.knowledgeFor(file)
+	related, relatedCut := e.relatedFor(file)
 	req := &providers.ReviewRequest{
 		Diff:             formatDiff(file),
 		Language:         file.Language,
 		FilePath:         file.Path,
+		Rules:            ruleGuidance(inScope),
 		Personality:      e.cfg.Review.Personality,
 		Modes:            providers.ParseModes(e.cfg.Review.Modes),
 		RootCauseTracing: e.cfg.Review.RootCauseTracing,
+		IssueTypes:       e.issueTypes,
+		Context:          knowledge,
+		Focus:            append(focus, generatedFocus...),
+		Related:          related,
+		Conventions:      e.conventions,
+		Format:           e.formats[file.Path],
+		ChangeSummary:    e.changeSummary(file),
+		Intent:           e.intent,
 	}
 
 	// Check cache
-	if e.cache != nil {
-		key := e.cache.ComputeKey(req)
-		if cached, found, _ := e.cache.Get(key); found {
-			return &FileResult{
-				File:     file.Path,
-				Response: cached,
-				Cached:   true,
-			}
-		}
+	if cached := e.lookupCache(file, req, policy); cached != nil {
+		cached.RulesInScope = ruleIDs(inScope)
+		return cached
 	}
 
 	// Call provider
-	resp, err := e.provider.Review(ctx, req)
+	budget := e.contextBudget(req)
+	if knowledgeCut {
+		budget.truncate("knowledge")
+	}
+	if relatedCut {
+		budget.truncate("related")
+	}
+	resp, err := e.callProvider(ctx, req, budget)
+	var blocked *providers.SafetyBlockError
+	if errors.As(err, &blocked) {
+		e.log.Warn("Skipping %s: %v", file.Path, err)
+		return &FileResult{File: file.Path, unreviewed: UnreviewedBlocked, Budget: budget}
+	}
 	if err != nil {
 		e.log.Error("Review failed for %s (lang=%s, size=%d bytes): %v",
 			file.Path, file.Language, len(req.Diff), err)
@@ -285,20 +728,214 @@ func (e *Engine) reviewFile(ctx context.Context, file git.FileDiff) *FileResult
 			File: file.Path,
 			Error: fmt.Errorf("review failed for %s (lang=%s, size=%d bytes): %w",
 				file.Path, file.Language, len(req.Diff), err),
+			Budget: budget,
 		}
 	}
 
+	// Correct line numbers before caching so cached results are verified too
+	e.relocateIssues(file, resp)
+
 	// Store in cache
 	if e.cache != nil {
-		key := e.cache.ComputeKey(req)
-		_ = e.cache.Set(key, resp)
+		_ = cache.SetForFile(e.cache, e.cache.ComputeKey(req), req.FilePath, resp)
+		if e.cfg.Cache.Normalize {
+			_ = cache.SetForFile(e.cache, cache.ComputeNormalizedKey(req), req.FilePath, resp)
+		}
 	}
+	// After caching, so cached issues don't point at this run's transcripts
+	e.saveTranscript(file.Path, resp)
 
 	return &FileResult{
-		File:     file.Path,
-		Response: resp,
-		Cached:   false,
+		File:         file.Path,
+		Response:     policy.apply(resp),
+		Cached:       false,
+		RulesInScope: ruleIDs(inScope),
+		Budget:       budget,
+	}
+}
+
+// lookupCache returns the cached result for the request, trying the exact
+// diff first and then the normalized diff. Issues from a normalized hit are
+// relocated, since trivial edits may have shifted their lines.
+func (e *Engine) lookupCache(file git.FileDiff, req *providers.ReviewRequest, policy severityPolicy) *FileResult {
+	if e.cache == nil {
+		return nil
+	}
+
+	if cached, found, _ := e.cache.Get(e.cache.ComputeKey(req)); found {
+		return &FileResult{File: file.Path, Response: policy.apply(cached), Cached: true}
+	}
+	if !e.cfg.Cache.Normalize {
+		return nil
+	}
+
+	cached, found, _ := e.cache.Get(cache.ComputeNormalizedKey(req))
+	if !found {
+		return nil
+	}
+	resp := policy.apply(cached)
+	for i, issue := range resp.Issues {
+		if issue.Location != nil {
+			loc := *issue.Location // Don't modify the cached response
+			resp.Issues[i].Location = &loc
+		}
+	}
+	e.relocateIssues(file, resp)
+	return &FileResult{File: file.Path, Response: resp, Cached: true, Normalized: true}
+}
+
+// callProvider sends the review request, splitting diffs that exceed the
+// model's chunk budget into several requests and merging the responses.
+// The requests sent are recorded in budget. Chunks the provider's safety
+// filters block are left out and noted in the summary; the error is
+// returned only when all of them are.
+func (e *Engine) callProvider(ctx context.Context, req *providers.ReviewRequest, budget *ContextBudget) (*providers.ReviewResponse, error) {
+	if budget.Diff <= e.maxChunkTokens {
+		budget.recordChunks(nil)
+		return e.reviewLimited(ctx, req)
+	}
+
+	chunker := tokenizer.NewChunker(tokenizer.ChunkerConfig{
+		MaxChunkTokens: e.maxChunkTokens,
+		Language:       req.Language,
+		Estimator:      e.estimator,
+	})
+	chunks := chunker.ChunkDiff(req.Diff)
+	budget.recordChunks(chunks)
+	e.log.Debug("Splitting %s into %d chunks (max %d tokens)", req.FilePath, len(chunks), e.maxChunkTokens)
+
+	merged := &providers.ReviewResponse{}
+	scoreTotal, reviewed := 0, 0
+	var blockedNotes []string
+	var blockedErr error
+	for i, chunk := range chunks {
+		chunkReq := *req
+		chunkReq.Diff = chunk.Content
+		resp, err := e.reviewLimited(ctx, &chunkReq)
+		var blocked *providers.SafetyBlockError
+		if errors.As(err, &blocked) {
+			e.log.Warn("Skipping chunk %d/%d of %s: %v", i+1, len(chunks), req.FilePath, err)
+			blockedNotes = append(blockedNotes, fmt.Sprintf("Chunk %d/%d (lines %d-%d) was blocked by the provider's safety filters and not reviewed.", i+1, len(chunks), chunk.StartLine, chunk.EndLine))
+			blockedErr = fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		merged.Issues = append(merged.Issues, resp.Issues...)
+		if resp.Summary != "" {
+			if merged.Summary != "" {
+				merged.Summary += "\n"
+			}
+			merged.Summary += resp.Summary
+		}
+		merged.TokensUsed += resp.TokensUsed
+		merged.ProcessingTime += resp.ProcessingTime
+		merged.ParseFailures += resp.ParseFailures
+		merged.Exchanges = append(merged.Exchanges, resp.Exchanges...)
+		if chunk.TokenCount > e.maxChunkTokens {
+			merged.TruncatedChunks++
+		}
+		scoreTotal += resp.Score
+		reviewed++
+	}
+	if reviewed == 0 && blockedErr != nil {
+		return nil, blockedErr
+	}
+	for _, note := range blockedNotes {
+		if merged.Summary != "" {
+			merged.Summary += "\n"
+		}
+		merged.Summary += note
+	}
+	if reviewed > 0 {
+		merged.Score = scoreTotal / reviewed
+	}
+	return merged, nil
+}
+
+// reviewLimited sends a request to the provider and counts it in the run
+// stats. When adaptive concurrency is on, it holds a limiter slot and
+// reports the request's latency and outcome. Errors are coded by
+// providers.ClassifyError.
+func (e *Engine) reviewLimited(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+	if e.limiter != nil {
+		if err := e.limiter.Acquire(ctx); err != nil {
+			return nil, err
+		}
+	}
+	start := time.Now()
+	resp, err := e.provider.Review(ctx, req)
+	elapsed := time.Since(start)
+	if e.limiter != nil {
+		e.limiter.Release(elapsed, err)
+	}
+	e.recordCall(elapsed, err)
+	return resp, providers.ClassifyError(e.provider.Name(), err)
+}
+
+// rulesFor returns the active rules in scope for the file: matching its
+// detected language and path, and satisfying any conditions.
+func (e *Engine) rulesFor(file git.FileDiff) []rules.Rule {
+	if len(e.rules) == 0 {
+		return nil
+	}
+
+	content, ok := e.readRepoFile(file.Path)
+	if !ok {
+		content = newSideContent(file)
+	}
+	inScope := rules.Select(e.rules, &rules.FileContext{
+		Path:     file.Path,
+		Language: file.Language,
+		Content:  content,
+	})
+	e.log.Debug("Rules in scope for %s: %v", file.Path, ruleIDs(inScope))
+	return inScope
+}
+
+func ruleIDs(rs []rules.Rule) []string {
+	if len(rs) == 0 {
+		return nil
+	}
+	ids := make([]string, len(rs))
+	for i, r := range rs {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func ruleGuidance(rs []rules.Rule) []string {
+	if len(rs) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(rs))
+	for _, r := range rs {
+		// License header rules are checked by the engine, not the model
+		if r.License != nil {
+			continue
+		}
+		lines = append(lines, r.Guidance())
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return lines
+}
+
+// newSideContent returns the added and context lines of a diff.
+func newSideContent(file git.FileDiff) string {
+	var sb strings.Builder
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type == git.LineDeletion {
+				continue
+			}
+			sb.WriteString(line.Content)
+			sb.WriteByte('\n')
+		}
 	}
+	return sb.String()
 }
 
 func formatDiff(file git.FileDiff) string {
@@ -2,11 +2,17 @@ package review
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/JNZader/goreview/goreview/internal/cache"
 	"github.com/JNZader/goreview/goreview/internal/config"
 	"github.com/JNZader/goreview/goreview/internal/git"
 	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
 )
 
 // MockProvider for testing
@@ -167,23 +173,317 @@ func TestCalculateOptimalConcurrency(t *testing.T) {
 	}
 }
 
-func TestMatchPattern(t *testing.T) {
-	tests := []struct {
-		pattern string
-		path    string
-		want    bool
-	}{
-		{"vendor/*", "vendor/lib/file.go", true},
-		{"vendor/*", "src/file.go", false},
-		{"*.md", "*.md", true},
-		{"README.md", "README.md", true},
-		{"README.md", "CHANGELOG.md", false},
+func TestEngineChunksLargeDiffs(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+	cfg.Review.MaxChunkTokens = 50
+
+	var lines []git.Line
+	for i := 0; i < 200; i++ {
+		lines = append(lines, git.Line{Type: git.LineAddition, Content: "x := computeSomething(a, b, c) // value"})
+	}
+	repo := &MockRepository{
+		StagedDiff: &git.Diff{
+			Files: []git.FileDiff{{
+				Path: "big.go", Language: "go", Status: git.FileModified,
+				Hunks: []git.Hunk{{Header: "@@ -1,0 +1,200 @@", Lines: lines}},
+			}},
+		},
+	}
+
+	calls := 0
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			calls++
+			return &providers.ReviewResponse{Issues: []providers.Issue{{ID: "1"}}, Score: 80}, nil
+		},
+	}
+
+	engine := NewEngine(cfg, repo, provider, nil, nil)
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if calls < 2 {
+		t.Fatalf("provider calls = %d, want diff split into multiple chunks", calls)
+	}
+	if result.TotalIssues != calls {
+		t.Errorf("TotalIssues = %d, want %d (one per chunk)", result.TotalIssues, calls)
+	}
+	if result.Files[0].Response.Score != 80 {
+		t.Errorf("Score = %d, want averaged 80", result.Files[0].Response.Score)
+	}
+	if b := result.Files[0].Budget; b == nil || b.Chunks != calls || b.ChunkLimit != 50 {
+		t.Errorf("Budget = %+v, want %d chunks of max 50 tokens", b, calls)
+	}
+}
+
+func TestEngineReportsRulesInScope(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+
+	repo := &MockRepository{
+		StagedDiff: &git.Diff{
+			Files: []git.FileDiff{
+				{Path: "main.go", Language: "go", Status: git.FileModified},
+				{Path: "app.py", Language: "python", Status: git.FileModified},
+			},
+		},
+	}
+	activeRules := []rules.Rule{
+		{ID: "GO-001", Languages: []string{"go"}, Enabled: true},
+		{ID: "ANY-001", Enabled: true},
+	}
+
+	engine := NewEngine(cfg, repo, &MockProvider{}, nil, activeRules)
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := map[string]int{"main.go": 2, "app.py": 1}
+	for _, f := range result.Files {
+		if len(f.RulesInScope) != want[f.File] {
+			t.Errorf("%s RulesInScope = %v, want %d rules", f.File, f.RulesInScope, want[f.File])
+		}
 	}
+}
 
-	for _, tt := range tests {
-		got := matchPattern(tt.pattern, tt.path)
-		if got != tt.want {
-			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+func TestEngineNormalizedCacheHit(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+
+	fileWith := func(added string) git.FileDiff {
+		return git.FileDiff{
+			Path: "main.go", Language: "go", Status: git.FileModified,
+			Hunks: []git.Hunk{{
+				Header: "@@ -1,1 +1,2 @@",
+				Lines: []git.Line{
+					{Type: git.LineContext, Content: "x := 1"},
+					{Type: git.LineAddition, Content: added},
+				},
+			}},
 		}
 	}
+
+	calls := 0
+	provider := &MockProvider{ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+		calls++
+		return &providers.ReviewResponse{Issues: []providers.Issue{{ID: "1", Severity: providers.SeverityWarning}}}, nil
+	}}
+	repo := &MockRepository{StagedDiff: &git.Diff{Files: []git.FileDiff{fileWith("y := x + 1")}}}
+	engine := NewEngine(cfg, repo, provider, cache.NewLRUCache(10, time.Hour), nil)
+
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Whitespace and a trailing comment only
+	repo.StagedDiff = &git.Diff{Files: []git.FileDiff{fileWith("y := x  +  1 // next")}}
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("provider calls = %d, want 1", calls)
+	}
+	if f := result.Files[0]; !f.Cached || !f.Normalized {
+		t.Errorf("Cached = %v, Normalized = %v, want a normalized cache hit", f.Cached, f.Normalized)
+	}
+
+	cfg.Cache.Normalize = false
+	repo.StagedDiff = &git.Diff{Files: []git.FileDiff{fileWith("y := x + 1 // again")}}
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("provider calls with normalization disabled = %d, want 2", calls)
+	}
+}
+
+func TestEngineIncrementalReview(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "branch"
+	cfg.Review.Incremental = true
+
+	hunk := func(start int, added string) git.Hunk {
+		return git.Hunk{
+			Header: fmt.Sprintf("@@ -%d,0 +%d,1 @@", start, start), NewStart: start, NewLines: 1,
+			Lines: []git.Line{{Type: git.LineAddition, Content: added}},
+		}
+	}
+	file := func(hunks ...git.Hunk) *git.Diff {
+		return &git.Diff{Files: []git.FileDiff{{Path: "main.go", Language: "go", Status: git.FileModified, Hunks: hunks}}}
+	}
+
+	var diffs []string
+	provider := &MockProvider{ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+		diffs = append(diffs, req.Diff)
+		return &providers.ReviewResponse{Issues: []providers.Issue{{
+			ID: fmt.Sprint(len(diffs)), Message: "issue " + fmt.Sprint(len(diffs)),
+			Location: &providers.Location{StartLine: 10 * len(diffs)},
+		}}}, nil
+	}}
+
+	repo := &MockRepository{StagedDiff: file(hunk(10, "a := 1"))}
+	engine := NewEngine(cfg, repo, provider, nil, nil)
+	first, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	snapshot := engine.BranchReview(first, "feature")
+
+	// A new commit adds a hunk; the first one is unchanged but shifted
+	repo.StagedDiff = file(hunk(12, "a := 1"), hunk(20, "b := 2"))
+	engine = NewEngine(cfg, repo, provider, nil, nil)
+	if !engine.SetPriorReview(snapshot) {
+		t.Fatal("SetPriorReview() = false, want true")
+	}
+	second, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(diffs) != 2 || strings.Contains(diffs[1], "a := 1") || !strings.Contains(diffs[1], "b := 2") {
+		t.Errorf("second review diff = %q, want only the new hunk", diffs[len(diffs)-1])
+	}
+	f := second.Files[0]
+	if f.ReusedHunks != 1 {
+		t.Errorf("ReusedHunks = %d, want 1", f.ReusedHunks)
+	}
+	if len(f.Response.Issues) != 2 || second.TotalIssues != 2 {
+		t.Errorf("issues = %d (total %d), want the new and the carried-over issue", len(f.Response.Issues), second.TotalIssues)
+	}
+
+	// Settings changes invalidate the prior review
+	cfg.Provider.Model = "other-model"
+	if NewEngine(cfg, repo, provider, nil, nil).SetPriorReview(snapshot) {
+		t.Error("SetPriorReview() with another model = true, want false")
+	}
+}
+
+func TestEngineEnvironment(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+	cfg.Provider.Model = "qwen2.5-coder:14b"
+	cfg.Rules.Preset = "strict"
+
+	repo := &MockRepository{
+		StagedDiff: &git.Diff{Files: []git.FileDiff{{Path: "main.go", Language: "go", Status: git.FileModified}}},
+	}
+	active := []rules.Rule{{ID: "SEC-001", Enabled: true}, {ID: "BUG-001", Enabled: true}}
+	engine := NewEngine(cfg, repo, &MockProvider{}, nil, active)
+	engine.SetVersion("1.2.3")
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	env := result.Environment
+	if env == nil {
+		t.Fatal("expected an environment block")
+	}
+	if env.Version != "1.2.3" || env.Model != "qwen2.5-coder:14b" || env.Preset != "strict" || env.Provider != cfg.Provider.Name {
+		t.Errorf("environment = %+v", env)
+	}
+	if env.PromptHash == "" || env.RulesHash == "" {
+		t.Errorf("expected prompt and rules hashes, got %+v", env)
+	}
+
+	// The hash ignores rule order but not rule content
+	reordered := []rules.Rule{active[1], active[0]}
+	if rulesHash(reordered) != env.RulesHash {
+		t.Error("rules hash depends on rule order")
+	}
+	edited := []rules.Rule{active[0], {ID: "BUG-001", Enabled: false}}
+	if rulesHash(edited) == env.RulesHash {
+		t.Error("rules hash did not change when a rule changed")
+	}
+}
+
+func TestEngineRunStats(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+
+	repo := &MockRepository{
+		StagedDiff: &git.Diff{
+			Files: []git.FileDiff{
+				{Path: "a.go", Language: "go", Status: git.FileModified},
+				{Path: "b.go", Language: "go", Status: git.FileModified},
+			},
+		},
+	}
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			if req.FilePath == "b.go" {
+				return nil, fmt.Errorf("provider down")
+			}
+			return &providers.ReviewResponse{Summary: "ok"}, nil
+		},
+	}
+	engine := NewEngine(cfg, repo, provider, nil, nil)
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	stats := engine.RunStats()
+	if stats.ProviderCalls != 2 || stats.ProviderErrors != 1 {
+		t.Errorf("calls = %d, errors = %d, want 2 and 1", stats.ProviderCalls, stats.ProviderErrors)
+	}
+	var phases []string
+	for _, p := range stats.Phases {
+		phases = append(phases, p.Name)
+	}
+	if got, want := strings.Join(phases, ","), "diff,index,review,checks"; got != want {
+		t.Errorf("phases = %s, want %s", got, want)
+	}
+}
+
+// mapTranscripts records transcripts in memory.
+type mapTranscripts struct {
+	mu    sync.Mutex
+	saved map[string][]providers.Exchange
+}
+
+func (m *mapTranscripts) Write(file string, exchanges []providers.Exchange) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saved[file] = exchanges
+	return "t-" + file, nil
+}
+
+func TestEngineSavesTranscripts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+
+	repo := &MockRepository{
+		StagedDiff: &git.Diff{
+			Files: []git.FileDiff{{Path: "a.go", Language: "go", Status: git.FileModified}},
+		},
+	}
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			return &providers.ReviewResponse{
+				Issues:    []providers.Issue{{ID: "1", Message: "unchecked error"}},
+				Exchanges: []providers.Exchange{{Prompt: "review a.go", Response: `{"issues":[]}`}},
+			}, nil
+		},
+	}
+	transcripts := &mapTranscripts{saved: map[string][]providers.Exchange{}}
+	engine := NewEngine(cfg, repo, provider, nil, nil)
+	engine.SaveTranscripts(transcripts)
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := transcripts.saved["a.go"]; len(got) != 1 || got[0].Prompt != "review a.go" {
+		t.Errorf("saved exchanges = %+v", got)
+	}
+	if got := result.Files[0].Response.Issues[0].Transcript; got != "t-a.go" {
+		t.Errorf("issue transcript = %q, want t-a.go", got)
+	}
 }
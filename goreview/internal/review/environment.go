@@ -0,0 +1,59 @@
+package review
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+// SetVersion sets the goreview version recorded in the result environment.
+func (e *Engine) SetVersion(version string) {
+	e.version = version
+}
+
+// environment captures the settings a review ran with. Rules and the prompt
+// template are recorded as hashes, so two results can be compared without
+// storing the full configuration.
+func (e *Engine) environment() *history.Environment {
+	env := &history.Environment{
+		Version:     e.version,
+		Provider:    e.cfg.Provider.Name,
+		Model:       e.cfg.Provider.Model,
+		Temperature: e.cfg.Provider.Temperature,
+		Preset:      e.cfg.Rules.Preset,
+		RulesHash:   rulesHash(e.rules),
+		PromptHash: providers.PromptTemplateHash(&providers.ReviewRequest{
+			Personality:      e.cfg.Review.Personality,
+			Modes:            providers.ParseModes(e.cfg.Review.Modes),
+			RootCauseTracing: e.cfg.Review.RootCauseTracing,
+			IssueTypes:       e.issueTypes,
+		}),
+	}
+	if e.repoRoot != "" {
+		env.CommitSHA = history.GetHeadCommit(e.repoRoot)
+	}
+	return env
+}
+
+// rulesHash hashes the active rules in ID order, so it changes when a rule
+// is added, removed or edited but not when rules load in another order.
+func rulesHash(active []rules.Rule) string {
+	if len(active) == 0 {
+		return ""
+	}
+	sorted := make([]rules.Rule, len(active))
+	copy(sorted, active)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	hash := sha256.New()
+	enc := json.NewEncoder(hash)
+	for _, r := range sorted {
+		_ = enc.Encode(r) // Rules only hold plain values
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
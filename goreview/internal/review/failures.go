@@ -0,0 +1,28 @@
+package review
+
+import (
+	"fmt"
+
+	"github.com/JNZader/goreview/goreview/internal/failure"
+)
+
+// collectErrors lists the failures of a review with their codes: the files
+// that couldn't be reviewed and those whose provider answers weren't valid
+// JSON, so their issues may be missing.
+func collectErrors(result *Result) []failure.Info {
+	var errs []failure.Info
+	for _, f := range result.Files {
+		switch {
+		case f.Error != nil:
+			errs = append(errs, failure.Describe(f.Error, f.File))
+		case f.Response != nil && f.Response.ParseFailures > 0:
+			err := &failure.ParseError{
+				Code: failure.ParseResponse,
+				File: f.File,
+				Err:  fmt.Errorf("%d provider responses were not valid JSON", f.Response.ParseFailures),
+			}
+			errs = append(errs, failure.Describe(err, f.File))
+		}
+	}
+	return errs
+}
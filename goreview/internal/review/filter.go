@@ -0,0 +1,76 @@
+package review
+
+import (
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/ignore"
+)
+
+// Reasons a changed file was not reviewed
+const (
+	FilterDeleted     = "deleted"
+	FilterBinary      = "binary"
+	FilterNotIncluded = ignore.NotIncluded
+	FilterExcluded    = ignore.Excluded
+	FilterIgnoreFile  = ignore.IgnoreFile
+	// FilterNotebookOutputs is a notebook where only outputs or metadata
+	// changed, see prepareFormats
+	FilterNotebookOutputs = "notebook_outputs"
+	// FilterNotFlagged is a file outside review.only_files, like the files
+	// without issues in the report given to --only-flagged
+	FilterNotFlagged = "not_flagged"
+	// FilterNotFocused is a file without changes inside the symbols of
+	// review.focus_symbols, see focusFiles
+	FilterNotFocused = "not_focused"
+)
+
+// FilteredFile is a changed file left out of the review.
+type FilteredFile struct {
+	File string `json:"file"`
+	// Reason is one of the Filter constants
+	Reason string `json:"reason"`
+	// Pattern is the pattern that decided, with the ignore file line for
+	// FilterIgnoreFile, like ".goreviewignore:3: build/"
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// filterFiles returns the files to review and records the filtered ones in
+// e.filtered. Deleted and binary files are never reviewed; the others go
+// through review.only_files, the include patterns, exclude patterns and
+// ignore file, see internal/ignore.
+func (e *Engine) filterFiles(files []git.FileDiff) []git.FileDiff {
+	result := make([]git.FileDiff, 0, len(files))
+	e.filtered = nil
+	var only map[string]bool
+	if len(e.cfg.Review.OnlyFiles) > 0 {
+		only = make(map[string]bool, len(e.cfg.Review.OnlyFiles))
+		for _, path := range e.cfg.Review.OnlyFiles {
+			only[path] = true
+		}
+	}
+	for _, f := range files {
+		var d ignore.Decision
+		switch {
+		case f.Status == git.FileDeleted:
+			d.Reason = FilterDeleted
+		case f.IsBinary:
+			d.Reason = FilterBinary
+		case only != nil && !only[f.Path]:
+			d.Reason = FilterNotFlagged
+		default:
+			d = e.ignore.Match(f.Path)
+		}
+		if d.Ignored() {
+			e.log.Debug("Skipping %s: %s %s", f.Path, d.Reason, d.Pattern)
+			e.filtered = append(e.filtered, FilteredFile{File: f.Path, Reason: d.Reason, Pattern: d.Pattern})
+			continue
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// shouldIgnore reports whether a repository file is excluded by the exclude
+// patterns or the ignore file, for checks that read files outside the diff.
+func (e *Engine) shouldIgnore(path string) bool {
+	return e.ignore.Excluded(path).Ignored()
+}
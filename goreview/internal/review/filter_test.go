@@ -0,0 +1,47 @@
+package review
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+func TestFilterFilesRecordsReasons(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Git.IgnorePatterns = []string{"vendor/**"}
+	e := NewEngine(cfg, nil, nil, nil, nil)
+
+	files := e.filterFiles([]git.FileDiff{
+		{Path: "main.go"},
+		{Path: "old.go", Status: git.FileDeleted},
+		{Path: "logo.png", IsBinary: true},
+		{Path: "vendor/lib/lib.go"},
+	})
+	if len(files) != 1 || files[0].Path != "main.go" {
+		t.Errorf("files = %+v, want main.go", files)
+	}
+	var got []string
+	for _, f := range e.filtered {
+		got = append(got, f.File+" "+f.Reason+" "+f.Pattern)
+	}
+	want := "old.go deleted |logo.png binary |vendor/lib/lib.go excluded vendor/**"
+	if strings.Join(got, "|") != want {
+		t.Errorf("filtered = %q, want %q", strings.Join(got, "|"), want)
+	}
+}
+
+func TestFilterFilesOnlyFiles(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.OnlyFiles = []string{"a.go", "gone.go"}
+	e := NewEngine(cfg, nil, nil, nil, nil)
+
+	files := e.filterFiles([]git.FileDiff{{Path: "a.go"}, {Path: "b.go"}, {Path: "gone.go", Status: git.FileDeleted}})
+	if len(files) != 1 || files[0].Path != "a.go" {
+		t.Errorf("files = %+v, want a.go", files)
+	}
+	if len(e.filtered) != 2 || e.filtered[0].Reason != FilterNotFlagged || e.filtered[1].Reason != FilterDeleted {
+		t.Errorf("filtered = %+v, want b.go not flagged and gone.go deleted", e.filtered)
+	}
+}
@@ -0,0 +1,187 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// lineRange is a range of lines of the new side of a file, inclusive.
+type lineRange struct {
+	start, end int
+}
+
+// goReceiverType matches the receiver type of a Go method
+var goReceiverType = regexp.MustCompile(`^func\s*\(\s*(?:\w+\s+)?\*?\s*(\w+)`)
+
+// focusFiles restricts the files to the changes inside the symbols of
+// review.focus_symbols and records the files left without changes in
+// e.filtered. Symbols are resolved with the AST parser on the files as
+// they are: "Parse" names a function or a type, with its Go methods, and
+// "Engine.Run" a method of a type.
+func (e *Engine) focusFiles(files []git.FileDiff) []git.FileDiff {
+	symbols := e.cfg.Review.FocusSymbols
+	if len(symbols) == 0 {
+		return files
+	}
+
+	found := make(map[string]bool, len(symbols))
+	result := make([]git.FileDiff, 0, len(files))
+	for _, f := range files {
+		var ranges []lineRange
+		if content, ok := e.readRepoFile(f.Path); ok {
+			if ctx, err := ast.NewParser(f.Language).Parse(content, f.Path); err == nil {
+				ranges = symbolRanges(ctx, strings.Split(content, "\n"), symbols, found)
+			}
+		}
+		focused := focusHunks(f, ranges)
+		if len(focused.Hunks) == 0 {
+			e.log.Debug("Skipping %s: %s", f.Path, FilterNotFocused)
+			e.filtered = append(e.filtered, FilteredFile{File: f.Path, Reason: FilterNotFocused})
+			continue
+		}
+		result = append(result, focused)
+	}
+	for _, s := range symbols {
+		if !found[s] {
+			e.log.Warn("Focus symbol %s not found in the changed files", s)
+		}
+	}
+	return result
+}
+
+// symbolRanges returns the lines of the symbols declared in a parsed file,
+// marking in found the symbols it declares.
+func symbolRanges(ctx *ast.Context, lines []string, symbols []string, found map[string]bool) []lineRange {
+	var ranges []lineRange
+	for _, symbol := range symbols {
+		owner, member, isMember := strings.Cut(symbol, ".")
+		before := len(ranges)
+		classes := typeRanges(ctx, owner)
+		if !isMember {
+			ranges = append(ranges, classes...)
+		}
+		for _, fn := range ctx.Functions {
+			receiver := receiverType(fn, lines)
+			match := fn.Name == owner || receiver == owner // a function, or a method of the type
+			if isMember {
+				match = fn.Name == member && (receiver == owner || within(classes, fn.StartLine))
+			}
+			if match {
+				ranges = append(ranges, span(fn.StartLine, fn.EndLine))
+			}
+		}
+		if len(ranges) > before {
+			found[symbol] = true
+		}
+	}
+	return ranges
+}
+
+// typeRanges returns the lines of the classes, structs and interfaces with
+// the name.
+func typeRanges(ctx *ast.Context, name string) []lineRange {
+	var ranges []lineRange
+	for _, c := range ctx.Classes {
+		if c.Name == name {
+			ranges = append(ranges, span(c.StartLine, c.EndLine))
+		}
+	}
+	for _, i := range ctx.Interfaces {
+		if i.Name == name {
+			ranges = append(ranges, span(i.StartLine, i.EndLine))
+		}
+	}
+	return ranges
+}
+
+// span returns the range of a declaration; one-line declarations may have
+// no end line.
+func span(start, end int) lineRange {
+	return lineRange{start: start, end: max(start, end)}
+}
+
+// receiverType returns the type a method belongs to, "" for functions.
+func receiverType(fn ast.Function, lines []string) string {
+	if fn.StartLine >= 1 && fn.StartLine <= len(lines) {
+		if m := goReceiverType.FindStringSubmatch(strings.TrimSpace(lines[fn.StartLine-1])); m != nil {
+			return m[1]
+		}
+	}
+	return fn.Receiver
+}
+
+func within(ranges []lineRange, line int) bool {
+	for _, r := range ranges {
+		if line >= r.start && line <= r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// focusHunks returns the file with each hunk cut to its runs of lines
+// within the ranges; runs without changes are dropped. Deleted lines are
+// placed at the new line that follows them.
+func focusHunks(file git.FileDiff, ranges []lineRange) git.FileDiff {
+	out := file
+	out.Hunks = nil
+	out.Additions, out.Deletions = 0, 0
+	for _, h := range file.Hunks {
+		var run []git.Line
+		flush := func() {
+			for _, l := range run {
+				if l.Type != git.LineContext {
+					out.Hunks = append(out.Hunks, subHunk(run))
+					break
+				}
+			}
+			run = nil
+		}
+		next := h.NewStart
+		for _, l := range h.Lines {
+			at := next
+			if l.Type != git.LineDeletion {
+				at = l.NewNumber
+				next = l.NewNumber + 1
+			}
+			if !within(ranges, at) {
+				flush()
+				continue
+			}
+			run = append(run, l)
+			switch l.Type {
+			case git.LineAddition:
+				out.Additions++
+			case git.LineDeletion:
+				out.Deletions++
+			}
+		}
+		flush()
+	}
+	return out
+}
+
+// subHunk returns a hunk of consecutive lines of another hunk.
+func subHunk(lines []git.Line) git.Hunk {
+	h := git.Hunk{Lines: lines}
+	for _, l := range lines {
+		if l.Type != git.LineAddition {
+			if h.OldLines == 0 {
+				h.OldStart = l.OldNumber
+			}
+			h.OldLines++
+		}
+		if l.Type != git.LineDeletion {
+			if h.NewLines == 0 {
+				h.NewStart = l.NewNumber
+			}
+			h.NewLines++
+		}
+	}
+	h.Header = fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	return h
+}
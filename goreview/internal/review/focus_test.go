@@ -0,0 +1,70 @@
+package review
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+func TestFocusFiles(t *testing.T) {
+	code := `package review
+
+type Engine struct {
+	cfg int
+}
+
+func (e *Engine) Run() int {
+	return e.cfg
+}
+
+func helper() int {
+	return 2
+}
+`
+	// The whole file is added, plus a deletion in helper
+	hunk := git.Hunk{OldStart: 1, NewStart: 1}
+	for i, line := range strings.Split(strings.TrimSuffix(code, "\n"), "\n") {
+		hunk.Lines = append(hunk.Lines, git.Line{Type: git.LineAddition, Content: line, NewNumber: i + 1})
+		if i+1 == 11 {
+			hunk.Lines = append(hunk.Lines, git.Line{Type: git.LineDeletion, Content: "\treturn 1", OldNumber: 1})
+		}
+	}
+	files := []git.FileDiff{
+		{Path: "engine.go", Language: "go", Hunks: []git.Hunk{hunk}},
+		{Path: "other.go", Language: "go", Hunks: []git.Hunk{{NewStart: 1, Lines: []git.Line{{Type: git.LineAddition, Content: "package review", NewNumber: 1}}}}},
+	}
+
+	cases := []struct {
+		symbols []string
+		want    string
+		dels    int
+	}{
+		{[]string{"Engine.Run"}, "@@ -0,0 +7,3 @@", 0},
+		{[]string{"Engine"}, "@@ -0,0 +3,3 @@|@@ -0,0 +7,3 @@", 0},
+		{[]string{"helper", "Missing"}, "@@ -1,1 +11,3 @@", 1},
+	}
+	for _, tc := range cases {
+		cfg := config.DefaultConfig()
+		cfg.Review.FocusSymbols = tc.symbols
+		e := NewEngine(cfg, nil, nil, nil, nil)
+		e.files = fstest.MapFS{"engine.go": {Data: []byte(code)}, "other.go": {Data: []byte("package review\n")}}
+
+		got := e.focusFiles(files)
+		if len(got) != 1 || got[0].Path != "engine.go" {
+			t.Fatalf("focusFiles(%v) = %+v, want engine.go", tc.symbols, got)
+		}
+		var headers []string
+		for _, h := range got[0].Hunks {
+			headers = append(headers, h.Header)
+		}
+		if strings.Join(headers, "|") != tc.want || got[0].Deletions != tc.dels {
+			t.Errorf("focusFiles(%v) hunks = %v, %d deletions; want %s, %d", tc.symbols, headers, got[0].Deletions, tc.want, tc.dels)
+		}
+		if len(e.filtered) != 1 || e.filtered[0] != (FilteredFile{File: "other.go", Reason: FilterNotFocused}) {
+			t.Errorf("filtered = %+v, want other.go not focused", e.filtered)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package review
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/notebook"
+)
+
+// Template engines of template files
+const (
+	TemplateGo    = "go-template"
+	TemplateJinja = "jinja"
+)
+
+// Format notes telling the reviewer how to read files that aren't plain
+// source
+const (
+	formatNotebook = "Jupyter notebook, shown as the code of its changed code cells; line numbers are those of the .ipynb file and each hunk header names its cell. " +
+		"Cells share state and run in order: flag cells relying on state set by later cells. Markdown cells and outputs are left out"
+	formatGoTemplate = "Go template: review its actions and pipelines and the escaping of its output. " +
+		"Flag template.HTML, template.JS and template.URL conversions of untrusted values, text/template output into HTML, and unbalanced {{end}}"
+	formatJinja = "Jinja template: review its blocks, filters and macros and the escaping of its output. " +
+		"Flag |safe, Markup and autoescape false on untrusted values, and values put into scripts, URLs or unquoted attributes"
+)
+
+// templateExtensions are the extensions of files that may be templates;
+// files under a templates directory may be too
+var templateExtensions = map[string]bool{
+	".html": true, ".htm": true, ".xhtml": true, ".gohtml": true, ".tmpl": true, ".tpl": true,
+	".j2": true, ".jinja": true, ".jinja2": true, ".njk": true,
+}
+
+var (
+	jinjaBlock    = regexp.MustCompile(`\{%-?\s*(if|for|block|extends|include|import|from|macro|set|with|raw|autoescape|call|filter)\b`)
+	goTemplateTag = regexp.MustCompile(`\{\{-?\s*(\.|\$|define\b|template\b|range\b|with\b|end\b|block\b|if\s+[.$(])`)
+	jinjaFilter   = regexp.MustCompile(`\{\{[^}]*\|\s*\w+`)
+)
+
+// templateEngine returns the template engine of a file, or "" when it
+// isn't a template.
+func templateEngine(p, content string) string {
+	ext := strings.ToLower(path.Ext(p))
+	if !templateExtensions[ext] && !strings.Contains("/"+path.Dir(p)+"/", "/templates/") {
+		return ""
+	}
+	switch {
+	case ext == ".j2" || ext == ".jinja" || ext == ".jinja2" || jinjaBlock.MatchString(content):
+		return TemplateJinja
+	case goTemplateTag.MatchString(content):
+		return TemplateGo
+	case jinjaFilter.MatchString(content):
+		return TemplateJinja
+	}
+	return ""
+}
+
+// prepareFormats readies the files in formats that don't review well as
+// they are. Notebooks are turned into the code of their changed code cells;
+// those where only outputs or metadata changed are filtered. Templates keep
+// their diff and are noted as templates, see e.formats.
+func (e *Engine) prepareFormats(files []git.FileDiff) []git.FileDiff {
+	e.formats = make(map[string]string)
+	result := make([]git.FileDiff, 0, len(files))
+	for _, f := range files {
+		if notebook.IsNotebook(f.Path) {
+			code, ok := e.notebookCode(f)
+			if ok && len(code.Hunks) == 0 {
+				e.log.Debug("Skipping %s: only outputs or metadata changed", f.Path)
+				e.filtered = append(e.filtered, FilteredFile{File: f.Path, Reason: FilterNotebookOutputs})
+				continue
+			}
+			if ok {
+				f = code
+				e.formats[f.Path] = formatNotebook
+			}
+			result = append(result, f)
+			continue
+		}
+
+		content, ok := e.readRepoFile(f.Path)
+		if !ok {
+			content = newSideContent(f)
+		}
+		switch templateEngine(f.Path, content) {
+		case TemplateGo:
+			e.formats[f.Path] = formatGoTemplate
+			if f.Language == "unknown" {
+				f.Language = TemplateGo
+			}
+		case TemplateJinja:
+			e.formats[f.Path] = formatJinja
+			if f.Language == "unknown" {
+				f.Language = TemplateJinja
+			}
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// notebookCode returns the notebook's diff as the code of its code cells.
+// It fails, and the notebook is reviewed as JSON, when the notebook can't
+// be mapped line by line or the working tree doesn't hold the diff's side.
+func (e *Engine) notebookCode(file git.FileDiff) (git.FileDiff, bool) {
+	content, ok := e.readRawFile(file.Path)
+	if !ok {
+		return file, false
+	}
+	nb, err := notebook.Parse(content)
+	if err != nil {
+		e.log.Debug("Reviewing %s as JSON: %v", file.Path, err)
+		return file, false
+	}
+	lines := strings.Split(content, "\n")
+	for _, h := range file.Hunks {
+		for _, l := range h.Lines {
+			if l.Type != git.LineDeletion && (l.NewNumber > len(lines) || lines[l.NewNumber-1] != l.Content) {
+				e.log.Debug("Reviewing %s as JSON: the working tree differs from the diff", file.Path)
+				return file, false
+			}
+		}
+	}
+	return notebookDiff(file, nb), true
+}
+
+// notebookDiff keeps the lines of the diff in the source of code cells,
+// decoded, at their line numbers in the notebook file. Each hunk holds
+// lines of one cell, named in its header.
+func notebookDiff(file git.FileDiff, nb *notebook.Notebook) git.FileDiff {
+	out := file
+	out.Language = notebookLanguage(nb)
+	out.Hunks, out.Additions, out.Deletions = nil, 0, 0
+
+	for _, h := range file.Hunks {
+		var lines []git.Line
+		var cell *notebook.Cell
+		flush := func() {
+			if len(lines) > 0 {
+				out.Hunks = append(out.Hunks, cellHunk(cell, lines))
+			}
+			lines, cell = nil, nil
+		}
+
+		lastNew := h.NewStart - 1
+		for _, l := range h.Lines {
+			var c *notebook.Cell
+			text, ok := "", false
+			if l.Type == git.LineDeletion {
+				if c = nb.SourceAfter(lastNew); c != nil && c.Type == notebook.CellCode {
+					text, ok = notebook.DecodeLine(l.Content)
+				}
+			} else {
+				lastNew = l.NewNumber
+				if text, ok = nb.Code(l.NewNumber); ok {
+					c = nb.CellAt(l.NewNumber)
+				}
+			}
+			if !ok {
+				flush()
+				continue
+			}
+			if cell != nil && c.Number != cell.Number {
+				flush()
+			}
+			cell = c
+			l.Content = text
+			lines = append(lines, l)
+			switch l.Type {
+			case git.LineAddition:
+				out.Additions++
+			case git.LineDeletion:
+				out.Deletions++
+			}
+		}
+		flush()
+	}
+
+	// Hunks of context only are left out, like those of changed outputs
+	hunks := out.Hunks[:0]
+	for _, h := range out.Hunks {
+		for _, l := range h.Lines {
+			if l.Type != git.LineContext {
+				hunks = append(hunks, h)
+				break
+			}
+		}
+	}
+	out.Hunks = hunks
+	return out
+}
+
+// cellHunk returns a hunk of the lines of a cell.
+func cellHunk(cell *notebook.Cell, lines []git.Line) git.Hunk {
+	h := git.Hunk{Lines: lines}
+	for _, l := range lines {
+		if l.Type != git.LineAddition {
+			if h.OldLines == 0 {
+				h.OldStart = l.OldNumber
+			}
+			h.OldLines++
+		}
+		if l.Type != git.LineDeletion {
+			if h.NewLines == 0 {
+				h.NewStart = l.NewNumber
+			}
+			h.NewLines++
+		}
+	}
+	h.Header = fmt.Sprintf("@@ -%d,%d +%d,%d @@ cell %d", h.OldStart, h.OldLines, h.NewStart, h.NewLines, cell.Number)
+	return h
+}
+
+// notebookLanguage returns the language of a notebook's code cells,
+// Python when the notebook doesn't say.
+func notebookLanguage(nb *notebook.Notebook) string {
+	if nb.Extension != "" {
+		if lang := git.DetectLanguage("cell"+nb.Extension, ""); lang != "unknown" {
+			return lang
+		}
+	}
+	if nb.Language != "" {
+		return nb.Language
+	}
+	return "python"
+}
@@ -0,0 +1,117 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+const salesNotebook = `{
+ "cells": [
+  {
+   "cell_type": "code",
+   "metadata": {},
+   "outputs": [
+    {
+     "output_type": "stream",
+     "text": [
+      "42\n"
+     ]
+    }
+   ],
+   "source": [
+    "import pandas as pd\n",
+    "df = pd.read_csv(path)\n",
+    "print(len(df))"
+   ]
+  }
+ ],
+ "metadata": {"language_info": {"name": "python", "file_extension": ".py"}},
+ "nbformat": 4
+}
+`
+
+func TestPrepareFormatsNotebook(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sales.ipynb"), []byte(salesNotebook), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "outputs.ipynb"), []byte(salesNotebook), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine(config.DefaultConfig(), nil, nil, nil, nil)
+	e.repoRoot = dir
+
+	hunk := func(oldStart, newStart int, lines ...git.Line) git.Hunk {
+		h := git.Hunk{OldStart: oldStart, NewStart: newStart, Lines: lines}
+		h.NumberLines()
+		return h
+	}
+	ctx := func(s string) git.Line { return git.Line{Type: git.LineContext, Content: s} }
+	add := func(s string) git.Line { return git.Line{Type: git.LineAddition, Content: s} }
+	del := func(s string) git.Line { return git.Line{Type: git.LineDeletion, Content: s} }
+	output := hunk(9, 9, ctx(`     "text": [`), del(`      "41\n"`), add(`      "42\n"`), ctx(`     ]`))
+
+	files := e.prepareFormats([]git.FileDiff{
+		{Path: "sales.ipynb", Language: "json", Hunks: []git.Hunk{
+			output,
+			hunk(14, 14,
+				ctx(`   "source": [`),
+				ctx(`    "import pandas as pd\n",`),
+				del(`    "df = pd.read_csv(\"sales.csv\")\n",`),
+				add(`    "df = pd.read_csv(path)\n",`),
+				ctx(`    "print(len(df))"`),
+				ctx(`   ]`),
+			),
+		}},
+		{Path: "outputs.ipynb", Language: "json", Hunks: []git.Hunk{output}},
+	})
+
+	if len(files) != 1 || len(e.filtered) != 1 || e.filtered[0] != (FilteredFile{File: "outputs.ipynb", Reason: FilterNotebookOutputs}) {
+		t.Fatalf("files = %+v, filtered = %+v; want outputs.ipynb filtered", files, e.filtered)
+	}
+	got := files[0]
+	if got.Language != "python" || got.Additions != 1 || got.Deletions != 1 || e.formats["sales.ipynb"] != formatNotebook {
+		t.Errorf("notebook = %+v, format %q", got, e.formats["sales.ipynb"])
+	}
+	want := "@@ -15,3 +15,3 @@ cell 1\n import pandas as pd\n-df = pd.read_csv(\"sales.csv\")\n+df = pd.read_csv(path)\n print(len(df))\n"
+	if diff := formatDiff(got); diff != want {
+		t.Errorf("diff =\n%s\nwant\n%s", diff, want)
+	}
+
+	// The checks read the code at the lines of the notebook file
+	source, ok := e.readRepoFile("sales.ipynb")
+	if lines := strings.Split(source, "\n"); !ok || lines[15] != "df = pd.read_csv(path)" || lines[10] != "" {
+		t.Errorf("readRepoFile() = %q", source)
+	}
+
+	// Without the notebook in the working tree, it is reviewed as JSON
+	files = e.prepareFormats([]git.FileDiff{{Path: "gone.ipynb", Language: "json", Hunks: []git.Hunk{output}}})
+	if len(files) != 1 || formatDiff(files[0]) != formatDiff(git.FileDiff{Hunks: []git.Hunk{output}}) {
+		t.Errorf("files = %+v, want the JSON diff", files)
+	}
+}
+
+func TestTemplateEngine(t *testing.T) {
+	tests := []struct {
+		path, content, want string
+	}{
+		{"web/templates/index.html", "<p>{{ .User.Name }}</p>\n{{ range .Items }}{{ end }}", TemplateGo},
+		{"page.gohtml", `{{define "page"}}{{template "head" .}}{{end}}`, TemplateGo},
+		{"charts/api/templates/deployment.yaml", "replicas: {{ .Values.replicas }}", TemplateGo},
+		{"templates/base.html", "{% block content %}{% endblock %}", TemplateJinja},
+		{"email.html", "<p>Hello {{ name | title }}</p>", TemplateJinja},
+		{"config.yaml.j2", "port: {{ port }}", TemplateJinja},
+		{"index.html", "<p>{{ message }}</p>", ""},
+		{"main.go", "fmt.Println(\"{{ .Name }}\")", ""},
+	}
+	for _, tt := range tests {
+		if got := templateEngine(tt.path, tt.content); got != tt.want {
+			t.Errorf("templateEngine(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
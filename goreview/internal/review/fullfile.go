@@ -0,0 +1,116 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/testcheck"
+)
+
+// maxRelatedLength bounds the text of the related files added to a prompt,
+// like maxKnowledgeLength.
+const maxRelatedLength = 12000
+
+// fullFileDiff presents whole files as diffs adding every line, so full
+// reviews go through the same pipeline as diffs. Paths are made relative to
+// the repository root, as in git output.
+func (e *Engine) fullFileDiff(ctx context.Context, files []string) (*git.Diff, error) {
+	root, err := e.gitRepo.GetRepoRoot(ctx)
+	if err != nil {
+		if root, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+
+	diff := &git.Diff{Files: make([]git.FileDiff, 0, len(files))}
+	for _, f := range files {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(abs) //nolint:gosec // Files are the user's review arguments
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f, err)
+		}
+		path := f
+		if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			path = filepath.ToSlash(rel)
+		}
+		file := git.WholeFile(path, string(data))
+		file.Status = git.FileModified
+		diff.Files = append(diff.Files, file)
+	}
+	diff.CalculateStats()
+	return diff, nil
+}
+
+// relatedFor returns the neighbors of a fully reviewed file, formatted for
+// the review prompt: up to review.context_radius files on each side in name
+// order, from the same directory and with the same extension. Test files
+// are only neighbors of test files. It also reports whether the text was
+// cut to maxRelatedLength.
+func (e *Engine) relatedFor(file git.FileDiff) (string, bool) {
+	radius := e.cfg.Review.ContextRadius
+	fsys := e.repoFiles()
+	if !e.cfg.Review.Full || radius <= 0 || fsys == nil {
+		return "", false
+	}
+
+	dir := path.Dir(file.Path)
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return "", false
+	}
+	ext, isTest := filepath.Ext(file.Path), testcheck.IsTestFile(file.Path)
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ext || testcheck.IsTestFile(name) != isTest {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	self := sort.SearchStrings(names, filepath.Base(file.Path))
+	var b strings.Builder
+	for _, i := range neighborOrder(self, len(names), radius) {
+		data, err := fs.ReadFile(fsys, path.Join(dir, names[i]))
+		if err != nil {
+			continue
+		}
+		b.WriteString("--- " + path.Join(dir, names[i]) + "\n")
+		b.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			b.WriteString("\n")
+		}
+	}
+
+	text := b.String()
+	if len(text) > maxRelatedLength {
+		return text[:maxRelatedLength] + "\n[truncated]\n", true
+	}
+	return text, false
+}
+
+// neighborOrder returns the indexes within radius of self, nearest first,
+// so truncation drops the farthest neighbors.
+func neighborOrder(self, n, radius int) []int {
+	var order []int
+	for d := 1; d <= radius; d++ {
+		if i := self - d; i >= 0 {
+			order = append(order, i)
+		}
+		if i := self + d; i < n {
+			order = append(order, i)
+		}
+	}
+	return order
+}
@@ -0,0 +1,87 @@
+package review
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestEngineFullFileReview(t *testing.T) {
+	dir := t.TempDir()
+	pkg := filepath.Join(dir, "billing")
+	if err := os.Mkdir(pkg, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"a.go":      "package billing\n\nfunc A() {}\n",
+		"b.go":      "package billing\n\nfunc B() {}\n",
+		"c.go":      "package billing\n\nfunc C() {\n\tA()\n}\n",
+		"d.go":      "package billing\n\nfunc D() {}\n",
+		"e.go":      "package billing\n\nfunc E() {}\n",
+		"c_test.go": "package billing\n",
+		"notes.txt": "not go\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(pkg, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "files"
+	cfg.Review.Files = []string{filepath.Join(pkg, "c.go")}
+	cfg.Review.Full = true
+	cfg.Review.ContextRadius = 1
+
+	var req *providers.ReviewRequest
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, r *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			req = r
+			return &providers.ReviewResponse{Summary: "ok"}, nil
+		},
+	}
+	engine := NewEngine(cfg, git.NewPatchRepo(nil, dir), provider, nil, nil)
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].File != "billing/c.go" {
+		t.Fatalf("reviewed files = %+v", result.Files)
+	}
+	if !strings.Contains(req.Diff, "A()") || !strings.Contains(req.Diff, "package billing") {
+		t.Errorf("diff doesn't hold the whole file:\n%s", req.Diff)
+	}
+	for _, want := range []string{"--- billing/b.go", "func B()", "--- billing/d.go"} {
+		if !strings.Contains(req.Related, want) {
+			t.Errorf("related files missing %q:\n%s", want, req.Related)
+		}
+	}
+	for _, unwanted := range []string{"a.go", "e.go", "c_test.go", "notes.txt"} {
+		if strings.Contains(req.Related, unwanted) {
+			t.Errorf("related files include %s:\n%s", unwanted, req.Related)
+		}
+	}
+	if b := result.Files[0].Budget; b == nil || b.Related == 0 {
+		t.Errorf("budget = %+v, want related tokens", b)
+	}
+}
+
+func TestNeighborOrder(t *testing.T) {
+	got := neighborOrder(1, 5, 2)
+	want := []int{0, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("neighborOrder() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("neighborOrder() = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package review
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/provenance"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/testcheck"
+)
+
+// ruleGeneratedUntested is the rule ID of files with generated blocks and no
+// tests under the strict policy
+const ruleGeneratedUntested = "generated/untested"
+
+// generatedBlocks returns the blocks added by the diff that look generated
+// or pasted. Each run of consecutive added lines in a hunk is checked on its
+// own.
+func (e *Engine) generatedBlocks(file git.FileDiff) []provenance.Block {
+	if e.generated == nil || file.IsBinary {
+		return nil
+	}
+	var runs [][]provenance.Line
+	for _, hunk := range file.Hunks {
+		var run []provenance.Line
+		for _, line := range hunk.Lines {
+			if line.Type == git.LineAddition {
+				run = append(run, provenance.Line{Number: line.NewNumber, Content: line.Content})
+				continue
+			}
+			if len(run) > 0 {
+				runs = append(runs, run)
+				run = nil
+			}
+		}
+		if len(run) > 0 {
+			runs = append(runs, run)
+		}
+	}
+	return e.generated.Detect(runs, file.Status == git.FileAdded)
+}
+
+// strictGenerated reports whether generated blocks get the strict policy.
+func (e *Engine) strictGenerated() bool {
+	return e.generated != nil && e.cfg.Review.Generated.Policy == config.GeneratedPolicyStrict
+}
+
+// generatedPolicy returns the severity policy for the file's review, and
+// the focus entries pointing the model at its generated blocks. Only the
+// strict policy changes them.
+func (e *Engine) generatedPolicy(file git.FileDiff) (severityPolicy, []string) {
+	if !e.strictGenerated() {
+		return e.severity, nil
+	}
+	blocks := e.generatedBlocks(file)
+	if len(blocks) == 0 {
+		return e.severity, nil
+	}
+	focus := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		focus = append(focus, fmt.Sprintf("%s: likely generated; check correctness, edge cases and error handling as strictly as untrusted code", b.Describe()))
+	}
+	minimum, _ := providers.ParseSeverity(e.cfg.Review.Generated.MinSeverity)
+	return e.severity.within(blocks, minimum), focus
+}
+
+// labelGenerated records the file's generated blocks for the report.
+func (e *Engine) labelGenerated(file git.FileDiff, result *FileResult) {
+	result.Generated = e.generatedBlocks(file)
+}
+
+// checkGeneratedTests adds an issue to each source file with generated
+// blocks that has no tests, neither changed by the diff nor in the
+// repository. It runs under the strict policy with require_tests.
+func (e *Engine) checkGeneratedTests(result *Result) {
+	if !e.strictGenerated() || !e.cfg.Review.Generated.RequireTests {
+		return
+	}
+
+	tests := make(map[string]bool)
+	for _, f := range result.Files {
+		if testcheck.IsTestFile(f.File) {
+			tests[filepath.ToSlash(f.File)] = true
+		}
+	}
+
+	for i := range result.Files {
+		f := &result.Files[i]
+		if len(f.Generated) == 0 || f.Response == nil || testcheck.IsTestFile(f.File) {
+			continue
+		}
+		if _, _, ok := e.findTest(f.File, tests); ok {
+			continue
+		}
+		block := f.Generated[0]
+		issue := providers.Issue{
+			ID:         fmt.Sprintf("%s:%s", ruleGeneratedUntested, f.File),
+			Type:       providers.IssueTypeTestGap,
+			Severity:   providers.SeverityError,
+			Message:    fmt.Sprintf("Generated code at %s has no tests", block.Describe()),
+			Suggestion: "Add tests covering the generated code before merging it",
+			RuleID:     ruleGeneratedUntested,
+			Location:   &providers.Location{File: f.File, StartLine: block.StartLine, EndLine: block.EndLine},
+		}
+		checked := e.severity.apply(&providers.ReviewResponse{Issues: []providers.Issue{issue}})
+		f.Response.Issues = append(f.Response.Issues, checked.Issues...)
+		result.TotalIssues += len(checked.Issues)
+	}
+}
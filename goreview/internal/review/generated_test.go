@@ -0,0 +1,107 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/provenance"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func generatedDiff(path string) git.FileDiff {
+	lines := []git.Line{
+		{Type: git.LineContext, Content: "func f() {", NewNumber: 1},
+		{Type: git.LineAddition, Content: "\t// Generated by Copilot", NewNumber: 2},
+		{Type: git.LineAddition, Content: "\treturn nil", NewNumber: 3},
+		{Type: git.LineContext, Content: "}", NewNumber: 4},
+	}
+	for i := 0; i < 5; i++ {
+		lines = append(lines, git.Line{Type: git.LineAddition, Content: fmt.Sprintf("var v%d = %d", i, i), NewNumber: 10 + i})
+	}
+	return git.FileDiff{Path: path, Language: "go", Status: git.FileModified, Hunks: []git.Hunk{{Lines: lines}}}
+}
+
+func TestGeneratedBlocks(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Generated.Enabled = true
+	cfg.Review.Generated.MinPasteLines = 5
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+
+	file := generatedDiff("pkg/a.go")
+	result := &FileResult{File: file.Path, Response: &providers.ReviewResponse{}}
+	engine.labelGenerated(file, result)
+	want := []provenance.Block{
+		{StartLine: 2, EndLine: 3, Kind: provenance.KindMarker, Marker: "// Generated by Copilot"},
+		{StartLine: 10, EndLine: 14, Kind: provenance.KindPaste},
+	}
+	if len(result.Generated) != len(want) || result.Generated[0] != want[0] || result.Generated[1] != want[1] {
+		t.Errorf("Generated = %+v, want %+v", result.Generated, want)
+	}
+
+	// The label policy doesn't change the review
+	if policy, focus := engine.generatedPolicy(file); focus != nil || policy.strict != nil {
+		t.Errorf("generatedPolicy() = %+v, %v under the label policy", policy, focus)
+	}
+}
+
+func TestGeneratedStrictPolicy(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.MinSeverity = "error"
+	cfg.Review.Generated.Enabled = true
+	cfg.Review.Generated.Policy = config.GeneratedPolicyStrict
+	cfg.Review.Generated.MinPasteLines = 0
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+
+	policy, focus := engine.generatedPolicy(generatedDiff("pkg/a.go"))
+	if len(focus) != 1 {
+		t.Fatalf("focus = %v, want the marker block", focus)
+	}
+	issue := func(line int) providers.Issue {
+		return providers.Issue{Severity: providers.SeverityInfo, Location: &providers.Location{StartLine: line}}
+	}
+	resp := policy.apply(&providers.ReviewResponse{Issues: []providers.Issue{issue(3), issue(12)}})
+	if len(resp.Issues) != 1 || resp.Issues[0].Location.StartLine != 3 {
+		t.Errorf("issues = %+v, want only the one in the generated block", resp.Issues)
+	}
+}
+
+func TestCheckGeneratedTests(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pkg/b_test.go"), []byte("package pkg\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Review.Generated.Enabled = true
+	cfg.Review.Generated.Policy = config.GeneratedPolicyStrict
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+
+	block := []provenance.Block{{StartLine: 2, EndLine: 3, Kind: provenance.KindMarker}}
+	result := &Result{}
+	for _, path := range []string{"pkg/a.go", "pkg/b.go", "pkg/c.go", "pkg/c_test.go", "pkg/d.go"} {
+		f := FileResult{File: path, Response: &providers.ReviewResponse{}}
+		if path != "pkg/d.go" {
+			f.Generated = block
+		}
+		result.Files = append(result.Files, f)
+	}
+	engine.checkGeneratedTests(result)
+
+	// b.go has a test in the repository, c.go one in the diff, d.go no
+	// generated code
+	if result.TotalIssues != 1 {
+		t.Fatalf("TotalIssues = %d, want 1", result.TotalIssues)
+	}
+	issues := result.Files[0].Response.Issues
+	if len(issues) != 1 || issues[0].RuleID != ruleGeneratedUntested || issues[0].Location.StartLine != 2 {
+		t.Errorf("issues = %+v", issues)
+	}
+}
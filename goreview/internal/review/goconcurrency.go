@@ -0,0 +1,42 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/goconcurrency"
+)
+
+// concurrencyRegions returns the suspicious concurrency regions of a Go
+// file that touch the lines added by the diff, formatted for the review
+// prompt. The model confirms or dismisses them; they aren't issues by
+// themselves.
+func (e *Engine) concurrencyRegions(file git.FileDiff) []string {
+	if !e.concurrencyFocus || file.Language != "go" || strings.HasSuffix(file.Path, "_test.go") {
+		return nil
+	}
+	content, ok := e.readRepoFile(file.Path)
+	if !ok {
+		return nil
+	}
+	regions, err := goconcurrency.Check(file.Path, []byte(content))
+	if err != nil {
+		e.log.Debug("Concurrency heuristics skipped for %s: %v", file.Path, err)
+		return nil
+	}
+
+	changed := changedLines(file)
+	var focus []string
+	for _, r := range regions {
+		if !touches(changed, r.StartLine, r.EndLine) {
+			continue
+		}
+		lines := fmt.Sprintf("line %d", r.StartLine)
+		if r.EndLine > r.StartLine {
+			lines = fmt.Sprintf("lines %d-%d", r.StartLine, r.EndLine)
+		}
+		focus = append(focus, fmt.Sprintf("%s (%s): %s", lines, r.Rule, r.Message))
+	}
+	return focus
+}
@@ -0,0 +1,41 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+func TestConcurrencyRegions(t *testing.T) {
+	dir := t.TempDir()
+	src := "package store\n\nimport \"sync\"\n\nfunc Get(mu *sync.Mutex) {\n\tmu.Lock()\n}\n\nfunc Put(mu *sync.Mutex) {\n\tmu.Lock()\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "store.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Review.Modes = "concurrency"
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+
+	// Only the lock in Put is on an added line
+	file := git.FileDiff{Path: "store.go", Language: "go", Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineContext, Content: "\tmu.Lock()", NewNumber: 6},
+		{Type: git.LineAddition, Content: "\tmu.Lock()", NewNumber: 10},
+	}}}}
+	focus := engine.concurrencyRegions(file)
+	if want := "line 10 (concurrency/mutex): Lock is never unlocked in this function"; len(focus) != 1 || focus[0] != want {
+		t.Errorf("concurrencyRegions() = %q, want [%q]", focus, want)
+	}
+
+	// Without the concurrency mode the prompt isn't focused
+	cfg.Review.Modes = "errors"
+	engine = NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+	if focus := engine.concurrencyRegions(file); focus != nil {
+		t.Errorf("concurrencyRegions() = %q, want none outside the concurrency mode", focus)
+	}
+}
@@ -0,0 +1,55 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/goerrors"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// checkGoErrors adds the error handling findings on the lines added to a Go
+// file. It runs in the errors review mode, alongside the model, which is
+// asked to judge the cases that need context.
+func (e *Engine) checkGoErrors(file git.FileDiff, result *FileResult) {
+	if !e.errorChecks || result.Response == nil || file.Language != "go" || strings.HasSuffix(file.Path, "_test.go") {
+		return
+	}
+	content, ok := e.readRepoFile(file.Path)
+	if !ok {
+		return
+	}
+	findings, err := goerrors.Check(file.Path, []byte(content))
+	if err != nil {
+		e.log.Debug("Error handling checks skipped for %s: %v", file.Path, err)
+		return
+	}
+
+	changed := changedLines(file)
+	lines := strings.Split(content, "\n")
+	var issues []providers.Issue
+	for _, f := range findings {
+		if !changed[f.Line] {
+			continue
+		}
+		issue := providers.Issue{
+			ID:         fmt.Sprintf("%s:%s:%d", f.Rule, file.Path, f.Line),
+			Type:       providers.IssueTypeBug,
+			Severity:   f.Severity,
+			Message:    f.Message,
+			Suggestion: f.Suggestion,
+			RuleID:     f.Rule,
+			Location:   &providers.Location{File: file.Path, StartLine: f.Line, EndLine: f.Line},
+		}
+		if f.Line <= len(lines) {
+			issue.Code = lines[f.Line-1]
+		}
+		issues = append(issues, issue)
+	}
+	if len(issues) == 0 {
+		return
+	}
+	checked := e.severity.apply(&providers.ReviewResponse{Issues: issues})
+	result.Response.Issues = append(result.Response.Issues, checked.Issues...)
+}
@@ -0,0 +1,51 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestCheckGoErrors(t *testing.T) {
+	dir := t.TempDir()
+	src := "package store\n\nfunc Save() error {\n\tif err := write(); err != nil {\n\t\treturn nil\n\t}\n\tif err := flush(); err != nil {\n\t\treturn nil\n\t}\n\treturn nil\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "store.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Review.Modes = "errors"
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+
+	// Only the second swallowed error is on an added line
+	file := git.FileDiff{Path: "store.go", Language: "go", Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineContext, Content: "\tif err := write(); err != nil {", NewNumber: 4},
+		{Type: git.LineAddition, Content: "\tif err := flush(); err != nil {", NewNumber: 7},
+		{Type: git.LineAddition, Content: "\t\treturn nil", NewNumber: 8},
+	}}}}
+	result := &FileResult{File: "store.go", Response: &providers.ReviewResponse{}}
+	engine.checkGoErrors(file, result)
+
+	issues := result.Response.Issues
+	if len(issues) != 1 {
+		t.Fatalf("issues = %+v, want 1", issues)
+	}
+	if issues[0].RuleID != "errors/swallowed" || issues[0].Location.StartLine != 8 || issues[0].Code != "\t\treturn nil" {
+		t.Errorf("issue = %+v", issues[0])
+	}
+
+	// Without the errors mode nothing is checked
+	cfg.Review.Modes = "security"
+	engine = NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+	result = &FileResult{File: "store.go", Response: &providers.ReviewResponse{}}
+	engine.checkGoErrors(file, result)
+	if len(result.Response.Issues) != 0 {
+		t.Errorf("issues = %+v, want none outside the errors mode", result.Response.Issues)
+	}
+}
@@ -0,0 +1,102 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// Guardrail reasons
+const (
+	GuardLockfile  = "lockfile"
+	GuardVendored  = "vendored"
+	GuardMaxLines  = "max_lines"
+	GuardMaxTokens = "max_tokens"
+)
+
+// guardSummaryChars caps the diff sent to summarize a guarded file
+const guardSummaryChars = 12000
+
+// Guardrail tells why a file was summarized or skipped instead of reviewed.
+type Guardrail struct {
+	// Reason is one of the Guard constants
+	Reason string `json:"reason"`
+	// Detail explains the reason, like "2400 changed lines, limit 2000"
+	Detail string `json:"detail"`
+	// Action is summarize or skip
+	Action string `json:"action"`
+	// Summary is the model's one-paragraph summary of the change, for the
+	// summarize action
+	Summary string `json:"summary,omitempty"`
+}
+
+// lockfiles are dependency lockfiles, generated by package managers
+var lockfiles = map[string]bool{
+	"go.sum": true, "go.work.sum": true, "package-lock.json": true, "npm-shrinkwrap.json": true,
+	"yarn.lock": true, "pnpm-lock.yaml": true, "bun.lockb": true, "pipfile.lock": true,
+	"poetry.lock": true, "uv.lock": true, "pdm.lock": true, "cargo.lock": true, "gemfile.lock": true,
+	"composer.lock": true, "packages.lock.json": true, "pubspec.lock": true, "mix.lock": true,
+	"flake.lock": true, "podfile.lock": true, "gradle.lockfile": true, "package.resolved": true,
+}
+
+// guardrail returns the guard that catches the file, or nil when the file
+// is reviewed.
+func guardrail(cfg config.GuardrailsConfig, file git.FileDiff, tokens func() int) *Guardrail {
+	if !cfg.Enabled {
+		return nil
+	}
+	action := cfg.Action
+	if action == "" {
+		action = config.GuardrailSummarize
+	}
+	p := strings.ToLower(path.Clean(strings.ReplaceAll(file.Path, "\\", "/")))
+	if cfg.Lockfiles && lockfiles[path.Base(p)] {
+		return &Guardrail{Reason: GuardLockfile, Detail: "dependency lockfile", Action: action}
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		for _, vendor := range cfg.VendorDirs {
+			if dir == strings.ToLower(vendor) {
+				return &Guardrail{Reason: GuardVendored, Detail: "under " + vendor + "/", Action: action}
+			}
+		}
+	}
+	if lines := file.Additions + file.Deletions; cfg.MaxLines > 0 && lines > cfg.MaxLines {
+		return &Guardrail{Reason: GuardMaxLines, Detail: fmt.Sprintf("%d changed lines, limit %d", lines, cfg.MaxLines), Action: action}
+	}
+	if cfg.MaxTokens > 0 {
+		if n := tokens(); n > cfg.MaxTokens {
+			return &Guardrail{Reason: GuardMaxTokens, Detail: fmt.Sprintf("~%d diff tokens, limit %d", n, cfg.MaxTokens), Action: action}
+		}
+	}
+	return nil
+}
+
+// guardFile returns the result of a file a guard caught: with the summarize
+// action, a one-paragraph summary of the change from the model. A failed
+// summary falls back to the diff's size, since the file is not reviewed
+// either way.
+func (e *Engine) guardFile(ctx context.Context, file git.FileDiff, g *Guardrail) *FileResult {
+	e.log.Debug("Guardrail %s for %s: %s", g.Reason, file.Path, g.Detail)
+	result := &FileResult{File: file.Path, Guardrail: g}
+	if g.Action != config.GuardrailSummarize {
+		return result
+	}
+	diff := formatDiff(file)
+	if len(diff) > guardSummaryChars {
+		diff = diff[:guardSummaryChars] + "\n... (truncated)"
+	}
+	instructions := fmt.Sprintf("Summarize what this change to %s does in one short paragraph of plain text, "+
+		"without headings, lists or code. The file is not reviewed in detail (%s).", file.Path, g.Detail)
+	summary, err := e.provider.GenerateDocumentation(ctx, diff, instructions)
+	if err != nil || strings.TrimSpace(summary) == "" {
+		e.log.Warn("Summary failed for %s: %v", file.Path, err)
+		g.Summary = fmt.Sprintf("%d lines added, %d deleted.", file.Additions, file.Deletions)
+		return result
+	}
+	g.Summary = strings.Join(strings.Fields(summary), " ")
+	return result
+}
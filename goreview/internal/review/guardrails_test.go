@@ -0,0 +1,101 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestGuardrail(t *testing.T) {
+	cfg := config.DefaultConfig().Review.Guardrails
+	cfg.Enabled = true
+	cfg.MaxLines = 100
+	cfg.MaxTokens = 500
+	tokens := func(n int) func() int { return func() int { return n } }
+
+	tests := []struct {
+		file   git.FileDiff
+		tokens int
+		reason string
+		detail string
+	}{
+		{git.FileDiff{Path: "main.go", Additions: 10}, 50, "", ""},
+		{git.FileDiff{Path: "web/yarn.lock", Additions: 10}, 50, GuardLockfile, "dependency lockfile"},
+		{git.FileDiff{Path: "Cargo.lock"}, 50, GuardLockfile, "dependency lockfile"},
+		{git.FileDiff{Path: "vendor/github.com/x/y.go"}, 50, GuardVendored, "under vendor/"},
+		{git.FileDiff{Path: "web/node_modules/a/index.js"}, 50, GuardVendored, "under node_modules/"},
+		{git.FileDiff{Path: "big.go", Additions: 80, Deletions: 40}, 50, GuardMaxLines, "120 changed lines, limit 100"},
+		{git.FileDiff{Path: "dense.go", Additions: 20}, 900, GuardMaxTokens, "~900 diff tokens, limit 500"},
+	}
+	for _, tt := range tests {
+		g := guardrail(cfg, tt.file, tokens(tt.tokens))
+		if tt.reason == "" {
+			if g != nil {
+				t.Errorf("guardrail(%s) = %+v, want none", tt.file.Path, g)
+			}
+			continue
+		}
+		if g == nil || g.Reason != tt.reason || g.Detail != tt.detail || g.Action != config.GuardrailSummarize {
+			t.Errorf("guardrail(%s) = %+v, want %s (%s)", tt.file.Path, g, tt.reason, tt.detail)
+		}
+	}
+
+	cfg.Enabled = false
+	if g := guardrail(cfg, git.FileDiff{Path: "go.sum"}, tokens(0)); g != nil {
+		t.Errorf("disabled guardrail = %+v, want none", g)
+	}
+}
+
+func TestEngineGuardrails(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+	cfg.Git.IgnorePatterns = nil
+	cfg.Review.Guardrails.Enabled = true
+
+	lines := []git.Line{{Type: git.LineAddition, Content: "github.com/x/y v1.0.0 h1:abc="}}
+	repo := &MockRepository{
+		StagedDiff: &git.Diff{
+			Files: []git.FileDiff{
+				{Path: "go.sum", Status: git.FileModified, Additions: 1, Hunks: []git.Hunk{{Lines: lines}}},
+				{Path: "main.go", Language: "go", Status: git.FileModified, Additions: 1, Hunks: []git.Hunk{{Lines: lines}}},
+			},
+		},
+	}
+	reviewed := 0
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			reviewed++
+			return &providers.ReviewResponse{Score: 90}, nil
+		},
+	}
+
+	result, err := NewEngine(cfg, repo, provider, nil, nil).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if reviewed != 1 {
+		t.Errorf("reviews = %d, want only main.go reviewed", reviewed)
+	}
+	for _, f := range result.Files {
+		if f.File != "go.sum" {
+			continue
+		}
+		if g := f.Guardrail; g == nil || g.Reason != GuardLockfile || g.Summary != "# Doc" || f.Response != nil {
+			t.Errorf("go.sum = %+v, guardrail %+v, want summarized lockfile", f, f.Guardrail)
+		}
+	}
+
+	cfg.Review.Guardrails.Action = config.GuardrailSkip
+	result, err = NewEngine(cfg, repo, provider, nil, nil).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for _, f := range result.Files {
+		if f.File == "go.sum" && (f.Guardrail == nil || f.Guardrail.Summary != "") {
+			t.Errorf("skipped go.sum guardrail = %+v, want no summary", f.Guardrail)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package review
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+// reusedHunk is a hunk already reviewed in the previous branch review
+type reusedHunk struct {
+	fingerprint string
+	issues      []providers.Issue
+}
+
+// SetPriorReview loads the previous review of the branch so unchanged hunks
+// are not sent to the provider again. It returns false, ignoring the prior
+// review, when it was made against another base branch or with another
+// provider, model, personality or modes.
+func (e *Engine) SetPriorReview(prior *history.BranchReview) bool {
+	if prior == nil || prior.BaseBranch != e.cfg.Git.BaseBranch || !sameContext(prior.Context, e.analysisContext()) {
+		return false
+	}
+
+	e.prior = make(map[string]map[string][]providers.Issue, len(prior.Files))
+	for _, f := range prior.Files {
+		hunks := make(map[string][]providers.Issue, len(f.Hunks))
+		for _, h := range f.Hunks {
+			hunks[h.Fingerprint] = fromHistoryIssues(h.Issues, f.Path)
+		}
+		e.prior[f.Path] = hunks
+	}
+	return true
+}
+
+// BranchReview builds the snapshot stored for the next incremental review.
+// Files that failed are left out so they are reviewed again.
+func (e *Engine) BranchReview(result *Result, branch string) *history.BranchReview {
+	snapshot := &history.BranchReview{
+		Branch:      branch,
+		BaseBranch:  e.cfg.Git.BaseBranch,
+		ReviewedAt:  time.Now(),
+		Context:     e.analysisContext(),
+		Environment: result.Environment,
+	}
+	for _, f := range result.Files {
+		if f.Error != nil || f.hunkIssues == nil {
+			continue
+		}
+		file := history.ReviewedFile{Path: f.File}
+		for fingerprint, issues := range f.hunkIssues {
+			file.Hunks = append(file.Hunks, history.ReviewedHunk{
+				Fingerprint: fingerprint,
+				Issues:      toHistoryIssues(issues),
+			})
+		}
+		sort.Slice(file.Hunks, func(i, j int) bool { return file.Hunks[i].Fingerprint < file.Hunks[j].Fingerprint })
+		snapshot.Files = append(snapshot.Files, file)
+	}
+	return snapshot
+}
+
+// reviewIncremental reviews only the hunks not covered by the prior review
+// and merges the carried-over findings of the others.
+func (e *Engine) reviewIncremental(ctx context.Context, file git.FileDiff, inScope []rules.Rule) *FileResult {
+	pending, reused := e.splitReviewed(file)
+
+	var result *FileResult
+	if len(pending.Hunks) > 0 || len(reused) == 0 {
+		result = e.reviewDiff(ctx, pending, inScope)
+		if result.Error != nil || result.unreviewed != "" {
+			return result
+		}
+	} else {
+		e.log.Debug("No new hunks in %s since the last review", file.Path)
+		result = &FileResult{
+			File:         file.Path,
+			Response:     &providers.ReviewResponse{Summary: "No changes since the last review."},
+			RulesInScope: ruleIDs(inScope),
+		}
+	}
+
+	result.hunkIssues = assignToHunks(pending.Hunks, result.Response.Issues)
+	for _, r := range reused {
+		issues := cloneIssues(r.issues)
+		// The carried-over issues may have moved with the new commits
+		e.relocateIssues(file, &providers.ReviewResponse{Issues: issues})
+		result.hunkIssues[r.fingerprint] = issues
+		result.Response.Issues = append(result.Response.Issues, issues...)
+	}
+	result.ReusedHunks = len(reused)
+	return result
+}
+
+// splitReviewed returns the file restricted to hunks not seen in the prior
+// review, and the prior findings of the others.
+func (e *Engine) splitReviewed(file git.FileDiff) (git.FileDiff, []reusedHunk) {
+	seen := e.prior[file.Path]
+	if len(seen) == 0 {
+		return file, nil
+	}
+
+	pending := file
+	pending.Hunks = nil
+	var reused []reusedHunk
+	for _, h := range file.Hunks {
+		fingerprint := hunkFingerprint(h)
+		if issues, ok := seen[fingerprint]; ok {
+			reused = append(reused, reusedHunk{fingerprint: fingerprint, issues: issues})
+			continue
+		}
+		pending.Hunks = append(pending.Hunks, h)
+	}
+	return pending, reused
+}
+
+// hunkFingerprint identifies a hunk by its changed lines, so it is
+// recognized even after commits shift it within the file.
+func hunkFingerprint(h git.Hunk) string {
+	hash := sha256.New()
+	for _, line := range h.Lines {
+		switch line.Type {
+		case git.LineAddition:
+			hash.Write([]byte{'+'})
+		case git.LineDeletion:
+			hash.Write([]byte{'-'})
+		default:
+			continue
+		}
+		hash.Write([]byte(line.Content))
+		hash.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// assignToHunks groups issues by the hunk containing (or nearest to) their
+// start line. Issues without a location go to the first hunk.
+func assignToHunks(hunks []git.Hunk, issues []providers.Issue) map[string][]providers.Issue {
+	byHunk := make(map[string][]providers.Issue, len(hunks))
+	if len(hunks) == 0 {
+		return byHunk
+	}
+
+	fingerprints := make([]string, len(hunks))
+	for i, h := range hunks {
+		fingerprints[i] = hunkFingerprint(h)
+		byHunk[fingerprints[i]] = nil // Hunks without issues are still recorded as reviewed
+	}
+
+	for _, issue := range issues {
+		best := 0
+		if issue.Location != nil {
+			bestDistance := -1
+			for i, h := range hunks {
+				d := distanceToHunk(h, issue.Location.StartLine)
+				if bestDistance < 0 || d < bestDistance {
+					best, bestDistance = i, d
+				}
+			}
+		}
+		byHunk[fingerprints[best]] = append(byHunk[fingerprints[best]], issue)
+	}
+	return byHunk
+}
+
+func distanceToHunk(h git.Hunk, line int) int {
+	end := h.NewStart + max(h.NewLines, 1) - 1
+	switch {
+	case line < h.NewStart:
+		return h.NewStart - line
+	case line > end:
+		return line - end
+	default:
+		return 0
+	}
+}
+
+func cloneIssues(issues []providers.Issue) []providers.Issue {
+	out := make([]providers.Issue, len(issues))
+	copy(out, issues)
+	for i := range out {
+		if out[i].Location != nil {
+			loc := *out[i].Location
+			out[i].Location = &loc
+		}
+	}
+	return out
+}
+
+func (e *Engine) analysisContext() history.AnalysisContext {
+	return history.AnalysisContext{
+		Provider:    e.cfg.Provider.Name,
+		Model:       e.cfg.Provider.Model,
+		Personality: e.cfg.Review.Personality,
+		Modes:       modeNames(providers.ParseModes(e.cfg.Review.Modes)),
+	}
+}
+
+func modeNames(modes []providers.ReviewMode) []string {
+	names := make([]string, len(modes))
+	for i, m := range modes {
+		names[i] = string(m)
+	}
+	return names
+}
+
+func sameContext(a, b history.AnalysisContext) bool {
+	if a.Provider != b.Provider || a.Model != b.Model || a.Personality != b.Personality || len(a.Modes) != len(b.Modes) {
+		return false
+	}
+	for i := range a.Modes {
+		if a.Modes[i] != b.Modes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func toHistoryIssues(issues []providers.Issue) []history.Issue {
+	out := make([]history.Issue, 0, len(issues))
+	for _, issue := range issues {
+		h := history.Issue{
+			ID:         issue.ID,
+			Type:       string(issue.Type),
+			Severity:   string(issue.Severity),
+			Message:    issue.Message,
+			Suggestion: issue.Suggestion,
+			RuleID:     issue.RuleID,
+			Code:       issue.Code,
+			FixedCode:  issue.FixedCode,
+		}
+		if issue.Location != nil {
+			h.Line = issue.Location.StartLine
+			h.EndLine = issue.Location.EndLine
+		}
+		if issue.RootCause != nil {
+			h.RootCause = &history.RootCause{
+				Description: issue.RootCause.Description,
+				SourceLine:  issue.RootCause.OriginLine,
+				Propagation: issue.RootCause.PropagationPath,
+			}
+		}
+		out = append(out, h)
+	}
+	return out
+}
+
+func fromHistoryIssues(issues []history.Issue, path string) []providers.Issue {
+	out := make([]providers.Issue, 0, len(issues))
+	for _, h := range issues {
+		issue := providers.Issue{
+			ID:         h.ID,
+			Type:       providers.IssueType(h.Type),
+			Severity:   providers.Severity(h.Severity),
+			Message:    h.Message,
+			Suggestion: h.Suggestion,
+			RuleID:     h.RuleID,
+			Code:       h.Code,
+			FixedCode:  h.FixedCode,
+		}
+		if h.Line > 0 {
+			issue.Location = &providers.Location{File: path, StartLine: h.Line, EndLine: h.EndLine}
+		}
+		if h.RootCause != nil {
+			issue.RootCause = &providers.RootCause{
+				Description:     h.RootCause.Description,
+				OriginLine:      h.RootCause.SourceLine,
+				PropagationPath: h.RootCause.Propagation,
+			}
+		}
+		out = append(out, issue)
+	}
+	return out
+}
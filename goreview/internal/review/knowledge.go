@@ -0,0 +1,37 @@
+package review
+
+import (
+	"math"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/rag"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+// maxKnowledgeLength bounds the knowledge pack text added to a prompt, well
+// under the request's context limit
+const maxKnowledgeLength = 6000
+
+// knowledgeFor returns the knowledge packs for the APIs the file imports,
+// formatted for the review prompt, or "" when none match. It also reports
+// whether the packs were cut to maxKnowledgeLength.
+func (e *Engine) knowledgeFor(file git.FileDiff) (string, bool) {
+	if len(e.packs) == 0 {
+		return "", false
+	}
+	content, ok := e.readRepoFile(file.Path)
+	if !ok {
+		content = newSideContent(file)
+	}
+	fc := &rules.FileContext{Path: file.Path, Language: file.Language, Content: content}
+	matched := rag.MatchPacks(e.packs, file.Language, fc.Imports())
+	if len(matched) > 0 {
+		names := make([]string, len(matched))
+		for i, p := range matched {
+			names[i] = p.Name
+		}
+		e.log.Debug("Knowledge packs for %s: %v", file.Path, names)
+	}
+	text := rag.FormatPacks(matched, maxKnowledgeLength)
+	return text, len(text) < len(rag.FormatPacks(matched, math.MaxInt))
+}
@@ -0,0 +1,38 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+func TestKnowledgeFor(t *testing.T) {
+	dir := t.TempDir()
+	src := "package store\n\nimport (\n\t\"context\"\n\t\"database/sql\"\n)\n\nfunc Load(ctx context.Context, db *sql.DB) {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "store.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file := git.FileDiff{Path: "store.go", Language: "go"}
+
+	cfg := config.DefaultConfig()
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+	if got, _ := engine.knowledgeFor(file); got != "" {
+		t.Errorf("knowledgeFor() = %q, want nothing with packs disabled", got)
+	}
+
+	cfg.RAG.Packs.Enabled = true
+	engine = NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+	got, truncated := engine.knowledgeFor(file)
+	if truncated {
+		t.Error("knowledgeFor() truncated a single pack")
+	}
+	if !strings.HasPrefix(got, "### database/sql\n") || strings.Contains(got, "net/http") || len(got) > maxKnowledgeLength {
+		t.Errorf("knowledgeFor() = %q, want the database/sql pack", got)
+	}
+}
@@ -0,0 +1,71 @@
+package review
+
+import (
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+// checkLicenseHeaders adds an issue for each license header rule in scope
+// whose header is missing or outdated in the file. The check doesn't use the
+// provider, so it also runs on cached and incremental results. Each issue
+// carries the corrected header as FixedCode for 'goreview fix'.
+func (e *Engine) checkLicenseHeaders(file git.FileDiff, inScope []rules.Rule, result *FileResult) {
+	if result.Response == nil {
+		return
+	}
+
+	var headerRules []rules.Rule
+	for _, rule := range inScope {
+		if rule.License != nil && rule.License.Supports(file.Language) {
+			headerRules = append(headerRules, rule)
+		}
+	}
+	if len(headerRules) == 0 {
+		return
+	}
+	content, ok := e.readRepoFile(file.Path)
+	if !ok {
+		return
+	}
+
+	var issues []providers.Issue
+	for _, rule := range headerRules {
+		if finding := rule.License.Check(content, file.Language, time.Now().Year()); finding != nil {
+			issues = append(issues, licenseIssue(file.Path, rule, finding))
+		}
+	}
+	if len(issues) == 0 {
+		return
+	}
+	checked := e.severity.apply(&providers.ReviewResponse{Issues: issues})
+	result.Response.Issues = append(result.Response.Issues, checked.Issues...)
+}
+
+func licenseIssue(path string, rule rules.Rule, finding *rules.HeaderFinding) providers.Issue {
+	message, suggestion := rule.Message, rule.Suggestion
+	switch {
+	case message != "":
+	case finding.Missing:
+		message = "Missing license header"
+	default:
+		message = "Outdated license header"
+	}
+	if suggestion == "" {
+		suggestion = "Use the license header required by " + rule.ID
+	}
+
+	return providers.Issue{
+		ID:         rule.ID + ":" + path,
+		Type:       providers.IssueType(rule.Category),
+		Severity:   providers.Severity(rule.Severity),
+		Message:    message,
+		Suggestion: suggestion,
+		RuleID:     rule.ID,
+		Location:   &providers.Location{File: path, StartLine: finding.StartLine, EndLine: finding.EndLine},
+		Code:       finding.Existing,
+		FixedCode:  finding.Fixed,
+	}
+}
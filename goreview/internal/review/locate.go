@@ -0,0 +1,323 @@
+package review
+
+import (
+	"io/fs"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/notebook"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// minLineSimilarity is the minimum similarity for a fuzzy line match (0-1).
+const minLineSimilarity = 0.8
+
+// sourceLine is a line of the reviewed file with its 1-based line number.
+type sourceLine struct {
+	number int
+	text   string // normalized
+}
+
+// relocateIssues corrects issue line numbers by matching the code each issue
+// cites against the actual file. Issues whose code can't be found are kept
+// but flagged with Location.Unverified.
+func (e *Engine) relocateIssues(file git.FileDiff, resp *providers.ReviewResponse) {
+	if resp == nil || len(resp.Issues) == 0 {
+		return
+	}
+
+	source := e.loadSourceLines(file)
+	for i := range resp.Issues {
+		relocateIssue(&resp.Issues[i], file.Path, source)
+	}
+}
+
+// loadSourceLines reads the file from the working tree, falling back to the
+// new-side lines of the diff when the file is not available on disk.
+func (e *Engine) loadSourceLines(file git.FileDiff) []sourceLine {
+	if data, ok := e.readRepoFile(file.Path); ok {
+		raw := strings.Split(data, "\n")
+		lines := make([]sourceLine, 0, len(raw))
+		for i, l := range raw {
+			lines = append(lines, sourceLine{number: i + 1, text: normalizeCodeLine(l)})
+		}
+		return lines
+	}
+
+	var lines []sourceLine
+	for _, hunk := range file.Hunks {
+		for _, l := range hunk.Lines {
+			if l.Type == git.LineDeletion || l.NewNumber == 0 {
+				continue
+			}
+			lines = append(lines, sourceLine{number: l.NewNumber, text: normalizeCodeLine(l.Content)})
+		}
+	}
+	return lines
+}
+
+// readRepoFile reads a file from the working tree. Notebooks are read as
+// the code of their code cells, at their lines of the notebook file, so
+// the checks see code where the diff has it.
+func (e *Engine) readRepoFile(path string) (string, bool) {
+	content, ok := e.readRawFile(path)
+	if ok && notebook.IsNotebook(path) {
+		if nb, err := notebook.Parse(content); err == nil {
+			return nb.Source(), true
+		}
+	}
+	return content, ok
+}
+
+// readRawFile reads a reviewed file as it is.
+func (e *Engine) readRawFile(path string) (string, bool) {
+	fsys := e.repoFiles()
+	if fsys == nil {
+		return "", false
+	}
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// relocateIssue fixes a single issue's location using its cited code.
+func relocateIssue(issue *providers.Issue, path string, source []sourceLine) {
+	snippets := snippetLines(issue.Code)
+	if len(snippets) == 0 {
+		return // Nothing to verify against
+	}
+
+	if issue.Location == nil {
+		issue.Location = &providers.Location{File: path}
+	}
+	hint := issue.Location.StartLine
+
+	start, end, ok := 0, 0, false
+	for _, snippet := range snippets {
+		if start, end, ok = findExactMatch(snippet, source, hint); ok {
+			break
+		}
+	}
+	if !ok {
+		snippet := snippets[0]
+		if start, ok = findFuzzyMatch(snippet[0], source, hint); ok {
+			end = blockEnd(source, start, len(snippet))
+		}
+	}
+	if !ok {
+		issue.Location.Unverified = true
+		return
+	}
+
+	issue.Location.StartLine = start
+	issue.Location.EndLine = end
+	issue.Location.Unverified = false
+}
+
+// findExactMatch looks for the snippet as a contiguous block of lines,
+// preferring the occurrence closest to the reported line. It returns the
+// first and last lines of the block, blank lines within it included.
+func findExactMatch(snippet []string, source []sourceLine, hint int) (start, end int, found bool) {
+	for i := 0; i+len(snippet) <= len(source); i++ {
+		if source[i].text == "" {
+			continue // Blocks start at the first matched line
+		}
+		last, ok := blockMatches(snippet, source[i:])
+		if !ok {
+			continue
+		}
+		if !found || closer(source[i].number, start, hint) {
+			start, end, found = source[i].number, last, true
+		}
+	}
+	return start, end, found
+}
+
+// blockMatches reports whether source starts with the snippet, and the
+// number of the last line matched.
+func blockMatches(snippet []string, source []sourceLine) (int, bool) {
+	j, last := 0, 0
+	for _, want := range snippet {
+		// Skip blank source lines, the snippet has none
+		for j < len(source) && source[j].text == "" {
+			j++
+		}
+		if j >= len(source) || source[j].text != want {
+			return 0, false
+		}
+		last = source[j].number
+		j++
+	}
+	return last, true
+}
+
+// blockEnd returns the number of the last line of a block of n non-blank
+// lines from line start, skipping blank lines as blockMatches does. When
+// the file ends first, the block ends at its last non-blank line.
+func blockEnd(source []sourceLine, start, n int) int {
+	end := start
+	for _, l := range source {
+		if l.number < start || l.text == "" {
+			continue
+		}
+		end = l.number
+		if n--; n == 0 {
+			break
+		}
+	}
+	return end
+}
+
+// findFuzzyMatch finds the line most similar to the first snippet line.
+func findFuzzyMatch(first string, source []sourceLine, hint int) (int, bool) {
+	best, bestScore := 0, 0.0
+	for _, l := range source {
+		if l.text == "" {
+			continue
+		}
+		score := lineSimilarity(first, l.text)
+		if score < minLineSimilarity {
+			continue
+		}
+		if score > bestScore || (score == bestScore && closer(l.number, best, hint)) {
+			best, bestScore = l.number, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// closer reports whether candidate is nearer to hint than current.
+func closer(candidate, current, hint int) bool {
+	if hint <= 0 {
+		return false // Keep the first occurrence
+	}
+	return absInt(candidate-hint) < absInt(current-hint)
+}
+
+// lineSimilarity returns 1 - normalized Levenshtein distance.
+func lineSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if strings.Contains(b, a) || strings.Contains(a, b) {
+		shorter, longer := len(a), len(b)
+		if shorter > longer {
+			shorter, longer = longer, shorter
+		}
+		return float64(shorter) / float64(longer)
+	}
+	ra, rb := []rune(a), []rune(b)
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(maxLen)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// snippetLines splits cited code into normalized, non-empty lines. When
+// every line starts with a diff marker the model may have copied along,
+// the lines without the markers come first, then the lines as cited, since
+// code like "-x" may start with one too.
+func snippetLines(code string) [][]string {
+	var cited, stripped []string
+	marked := true
+	for _, l := range strings.Split(code, "\n") {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		cited = append(cited, normalizeCodeLine(l))
+		if l[0] != '+' && l[0] != '-' {
+			marked = false
+		} else if n := normalizeCodeLine(l[1:]); n != "" {
+			stripped = append(stripped, n)
+		}
+	}
+	if len(cited) == 0 {
+		return nil
+	}
+	if marked && len(stripped) > 0 {
+		return [][]string{stripped, cited}
+	}
+	return [][]string{cited}
+}
+
+// normalizeCodeLine trims and collapses whitespace for comparison.
+func normalizeCodeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// LineRange is a range of lines, 1-based and inclusive, in the new version
+// of a file.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// hunkRanges returns the new-side line ranges of the file's hunks, the
+// lines review comments can be attached to. Hunks that only delete lines
+// have none.
+func hunkRanges(file git.FileDiff) []LineRange {
+	ranges := []LineRange{}
+	for _, h := range file.Hunks {
+		n := h.NewLines
+		if n == 0 {
+			for _, l := range h.Lines {
+				if l.Type != git.LineDeletion {
+					n++
+				}
+			}
+		}
+		if n > 0 {
+			ranges = append(ranges, LineRange{Start: h.NewStart, End: h.NewStart + n - 1})
+		}
+	}
+	return ranges
+}
+
+// InDiff reports whether lines start to end are in a single hunk of the
+// file's diff, where review comments and suggestions can be attached. It
+// reports true when the hunks are unknown, like for results read back from
+// JSON.
+func (f *FileResult) InDiff(start, end int) bool {
+	if f.Hunks == nil {
+		return true
+	}
+	for _, r := range f.Hunks {
+		if start >= r.Start && end <= r.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+func TestRelocateIssues(t *testing.T) {
+	dir := t.TempDir()
+	content := "package main\n\nfunc main() {\n\tx := 1\n\tpassword := \"hunter2\"\n\t_ = x\n}\n\n" +
+		"func neg(y int) int {\n\ty = -y\n\n\treturn y +\n\t\t-1\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := NewEngine(config.DefaultConfig(), nil, nil, nil, nil)
+	engine.repoRoot = dir
+
+	resp := &providers.ReviewResponse{Issues: []providers.Issue{
+		{ID: "exact", Code: `password := "hunter2"`, Location: &providers.Location{StartLine: 2}},
+		{ID: "fuzzy", Code: `+ password := "hunter"`},
+		{ID: "block", Code: "x := 1\npassword := \"hunter2\""},
+		{ID: "missing", Code: "os.Exit(1)", Location: &providers.Location{StartLine: 3, EndLine: 3}},
+		{ID: "nocode", Location: &providers.Location{StartLine: 42}},
+		{ID: "blank", Code: "y = -y\nreturn y +"},
+		{ID: "minus", Code: "return y +\n-1"},
+		{ID: "marked", Code: "-1"},
+		{ID: "diff", Code: "+\ty = -y\n+\treturn y +"},
+		{ID: "fuzzy-blank", Code: "y = -x\nreturn y +"},
+		{ID: "fuzzy-eof", Code: "retrun y +\n-1\n}\nneg(2)\nneg(3)"},
+	}}
+
+	engine.relocateIssues(git.FileDiff{Path: "main.go"}, resp)
+
+	tests := []struct {
+		idx        int
+		start, end int
+		unverified bool
+	}{
+		{0, 5, 5, false},
+		{1, 5, 5, false},
+		{2, 4, 5, false},
+		{3, 3, 3, true},
+		{4, 42, 0, false},
+		{5, 10, 12, false},
+		{6, 12, 13, false},
+		{7, 13, 13, false},
+		{8, 10, 12, false},
+		{9, 10, 12, false},
+		{10, 12, 14, false},
+	}
+	for _, tt := range tests {
+		loc := resp.Issues[tt.idx].Location
+		if loc.StartLine != tt.start || loc.EndLine != tt.end || loc.Unverified != tt.unverified {
+			t.Errorf("issue %s: got %d-%d unverified=%v, want %d-%d unverified=%v",
+				resp.Issues[tt.idx].ID, loc.StartLine, loc.EndLine, loc.Unverified, tt.start, tt.end, tt.unverified)
+		}
+	}
+}
+
+func TestRelocateIssuesFromDiff(t *testing.T) {
+	engine := NewEngine(config.DefaultConfig(), nil, nil, nil, nil)
+
+	hunk := git.Hunk{OldStart: 10, NewStart: 10, Lines: []git.Line{
+		{Type: git.LineContext, Content: "a := 1"},
+		{Type: git.LineDeletion, Content: "b := 2"},
+		{Type: git.LineAddition, Content: "b := compute()"},
+	}}
+	hunk.NumberLines()
+
+	resp := &providers.ReviewResponse{Issues: []providers.Issue{{Code: "b := compute()"}}}
+	engine.relocateIssues(git.FileDiff{Path: "x.go", Hunks: []git.Hunk{hunk}}, resp)
+
+	if got := resp.Issues[0].Location.StartLine; got != 11 {
+		t.Errorf("StartLine = %d, want 11", got)
+	}
+}
+
+func TestHunkRanges(t *testing.T) {
+	file := git.FileDiff{Hunks: []git.Hunk{
+		{NewStart: 3, NewLines: 4},
+		{NewStart: 20, Lines: []git.Line{{Type: git.LineContext}, {Type: git.LineDeletion}, {Type: git.LineAddition}}},
+		{NewStart: 30, Lines: []git.Line{{Type: git.LineDeletion}}},
+	}}
+	f := FileResult{Hunks: hunkRanges(file)}
+	if len(f.Hunks) != 2 || f.Hunks[0] != (LineRange{3, 6}) || f.Hunks[1] != (LineRange{20, 21}) {
+		t.Fatalf("hunkRanges() = %+v", f.Hunks)
+	}
+	for _, tt := range []struct {
+		start, end int
+		want       bool
+	}{{3, 6, true}, {21, 21, true}, {6, 20, false}, {7, 7, false}, {30, 30, false}} {
+		if got := f.InDiff(tt.start, tt.end); got != tt.want {
+			t.Errorf("InDiff(%d, %d) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+	if !(&FileResult{}).InDiff(100, 200) {
+		t.Error("InDiff() without hunks = false, want true")
+	}
+}
+
+func TestCheckLicenseHeaders(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go": "package main\n",
+		"ok.go":   "// Copyright 2020 Acme\n\npackage main\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := NewEngine(config.DefaultConfig(), nil, nil, nil, nil)
+	engine.repoRoot = dir
+	inScope := []rules.Rule{{
+		ID: "LIC-001", Category: rules.CategoryMaintenance, Severity: rules.SeverityWarning,
+		License: &rules.LicenseHeader{Text: "Copyright {year} Acme"},
+	}}
+
+	result := &FileResult{File: "main.go", Response: &providers.ReviewResponse{}}
+	engine.checkLicenseHeaders(git.FileDiff{Path: "main.go", Language: "go"}, inScope, result)
+	if len(result.Response.Issues) != 1 {
+		t.Fatalf("issues = %d, want 1", len(result.Response.Issues))
+	}
+	issue := result.Response.Issues[0]
+	if issue.RuleID != "LIC-001" || issue.Location.StartLine != 1 || !strings.HasPrefix(issue.FixedCode, "// Copyright ") {
+		t.Errorf("issue = %+v", issue)
+	}
+
+	result = &FileResult{File: "ok.go", Response: &providers.ReviewResponse{}}
+	engine.checkLicenseHeaders(git.FileDiff{Path: "ok.go", Language: "go"}, inScope, result)
+	if len(result.Response.Issues) != 0 {
+		t.Errorf("unexpected issues for a current header: %+v", result.Response.Issues)
+	}
+
+	if got := ruleGuidance(inScope); got != nil {
+		t.Errorf("ruleGuidance() = %v, want license rules excluded", got)
+	}
+}
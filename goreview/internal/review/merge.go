@@ -0,0 +1,154 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Merge combines the results of several reviews into one, like the shards
+// of a review split across CI jobs with review.shard. Shards must come from
+// the same diff and cover it whole: each index once, none missing. Results
+// without shards are combined as they are, a file in several of them keeping
+// its last review.
+//
+// Quality, effort and errors are recomputed over all files, the duration is the
+// longest run's, and gates are left out, to be evaluated on the merged
+// result.
+func Merge(results ...*Result) (*Result, error) {
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no results to merge")
+	}
+	if err := checkShards(results); err != nil {
+		return nil, err
+	}
+
+	merged := &Result{Stats: results[0].Stats}
+	files := make(map[string]int)
+	filtered := make(map[string]bool)
+	var summaries []string
+	var unreviewed []UnreviewedFile
+	for _, r := range results {
+		merged.Duration = max(merged.Duration, r.Duration)
+		for _, f := range r.Files {
+			if i, ok := files[f.File]; ok {
+				merged.Files[i] = f
+				continue
+			}
+			files[f.File] = len(merged.Files)
+			merged.Files = append(merged.Files, f)
+		}
+		unreviewed = append(unreviewed, r.Unreviewed...)
+		for _, f := range r.Filtered {
+			if !filtered[f.File] {
+				filtered[f.File] = true
+				merged.Filtered = append(merged.Filtered, f)
+			}
+		}
+		if r.Summary != "" && r.Shard == nil {
+			summaries = append(summaries, r.Summary)
+		}
+		if merged.IssueTypes == nil {
+			merged.IssueTypes = r.IssueTypes
+		}
+		if merged.Environment == nil {
+			merged.Environment = r.Environment
+		}
+		if merged.Scope == nil {
+			merged.Scope = r.Scope
+		}
+	}
+	merged.Summary = strings.Join(summaries, "\n")
+
+	for _, f := range merged.Files {
+		if f.Response != nil {
+			merged.TotalIssues += len(f.Response.Issues)
+		}
+	}
+	// A file unreviewed in one result may be reviewed in another
+	for _, u := range unreviewed {
+		if _, ok := files[u.File]; !ok {
+			merged.Unreviewed = append(merged.Unreviewed, u)
+		}
+	}
+	sortUnreviewed(merged.Unreviewed)
+	merged.Remainder = mergeRemainders(results, files)
+	merged.Quality = assessQuality(merged)
+	merged.Errors = collectErrors(merged)
+	merged.Effort = mergeEffort(results, files)
+	return merged, nil
+}
+
+// checkShards checks that sharded results are all the shards of one diff.
+func checkShards(results []*Result) error {
+	sharded := 0
+	for _, r := range results {
+		if r.Shard != nil {
+			sharded++
+		}
+	}
+	if sharded == 0 {
+		return nil
+	}
+	if sharded < len(results) {
+		return fmt.Errorf("cannot merge sharded and unsharded results")
+	}
+
+	total := results[0].Shard.Total
+	seen := make(map[int]bool)
+	for _, r := range results {
+		switch {
+		case r.Shard.Total != total:
+			return fmt.Errorf("shard %d/%d is from a review split in %d shards", r.Shard.Index, r.Shard.Total, total)
+		case seen[r.Shard.Index]:
+			return fmt.Errorf("shard %d/%d given twice", r.Shard.Index, total)
+		case r.Stats != results[0].Stats:
+			return fmt.Errorf("shard %d/%d reviewed a different diff", r.Shard.Index, total)
+		}
+		seen[r.Shard.Index] = true
+	}
+	for i := 1; i <= total; i++ {
+		if !seen[i] {
+			return fmt.Errorf("shard %d/%d is missing", i, total)
+		}
+	}
+
+	owner := make(map[string]int)
+	for _, r := range results {
+		for _, f := range r.Files {
+			if other, ok := owner[f.File]; ok {
+				return fmt.Errorf("%s is in shards %d and %d of %d", f.File, other, r.Shard.Index, total)
+			}
+			owner[f.File] = r.Shard.Index
+		}
+	}
+	return nil
+}
+
+// mergeEffort combines the effort estimates of the results, keeping each
+// file's estimate from the result whose review of it was kept.
+func mergeEffort(results []*Result, files map[string]int) *Effort {
+	byFile := make(map[string]FileEffort)
+	found := false
+	for _, r := range results {
+		if r.Effort == nil {
+			continue
+		}
+		found = true
+		for _, fe := range r.Effort.Files {
+			byFile[fe.File] = fe
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	effort := &Effort{}
+	for file := range files {
+		if fe, ok := byFile[file]; ok {
+			effort.Files = append(effort.Files, fe)
+			effort.Minutes += fe.Minutes
+		}
+	}
+	sortEffort(effort)
+	return effort
+}
@@ -0,0 +1,99 @@
+package review
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestShardFiles(t *testing.T) {
+	files := []git.FileDiff{
+		{Path: "a.go", Additions: 500},
+		{Path: "b.go", Additions: 10},
+		{Path: "c.go", Additions: 300},
+		{Path: "d.go", Additions: 250},
+		{Path: "e.go", Additions: 40},
+	}
+	if got, shard := shardFiles("", files); len(got) != len(files) || shard != nil {
+		t.Errorf("shardFiles(\"\") = %d files, shard %+v, want all files", len(got), shard)
+	}
+
+	seen := make(map[string]int)
+	var names []string
+	for _, s := range []string{"1/2", "2/2"} {
+		got, shard := shardFiles(s, files)
+		if shard == nil || shard.Files != len(got) || shard.Total != 2 {
+			t.Fatalf("shardFiles(%s) shard = %+v", s, shard)
+		}
+		var paths []string
+		for _, f := range got {
+			seen[f.Path]++
+			paths = append(paths, f.Path)
+		}
+		names = append(names, strings.Join(paths, ","))
+	}
+	// Biggest first to the lightest shard: a | c, d, then e and b to a's
+	// shard, which stays lighter
+	if want := "a.go,b.go,e.go|c.go,d.go"; strings.Join(names, "|") != want {
+		t.Errorf("shards = %q, want %q", strings.Join(names, "|"), want)
+	}
+	if len(seen) != len(files) {
+		t.Errorf("shards cover %d files, want %d", len(seen), len(files))
+	}
+}
+
+func TestMerge(t *testing.T) {
+	stats := git.DiffStats{FilesChanged: 3, Additions: 30}
+	issue := providers.Issue{Type: providers.IssueTypeBug, Severity: providers.SeverityError, Message: "nil deref"}
+	shard := func(index int, duration time.Duration, files ...FileResult) *Result {
+		effort := &Effort{}
+		for _, f := range files {
+			effort.Files = append(effort.Files, FileEffort{File: f.File, Minutes: 5})
+			effort.Minutes += 5
+		}
+		return &Result{
+			Stats: stats, Duration: duration, Files: files, Effort: effort,
+			Filtered: []FilteredFile{{File: "logo.png", Reason: FilterBinary}},
+			Shard:    &Shard{Index: index, Total: 2, Files: len(files)},
+			Gates:    []GateResult{{Name: "partial", Passed: false}},
+		}
+	}
+	one := shard(1, time.Second, FileResult{File: "a.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{issue}}})
+	two := shard(2, 3*time.Second,
+		FileResult{File: "b.go", Response: &providers.ReviewResponse{}},
+		FileResult{File: "c.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{issue, issue}}})
+
+	merged, err := Merge(two, one)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if merged.TotalIssues != 3 || len(merged.Files) != 3 || merged.Duration != 3*time.Second || merged.Stats != stats {
+		t.Errorf("merged = %d issues, %d files, %v, %+v", merged.TotalIssues, len(merged.Files), merged.Duration, merged.Stats)
+	}
+	if merged.Shard != nil || merged.Gates != nil || len(merged.Filtered) != 1 {
+		t.Errorf("merged shard = %+v, gates = %+v, filtered = %+v", merged.Shard, merged.Gates, merged.Filtered)
+	}
+	if merged.Quality == nil || merged.Quality.Issues != 3 || merged.Effort == nil || merged.Effort.Minutes != 15 || len(merged.Effort.Files) != 3 {
+		t.Errorf("merged quality = %+v, effort = %+v", merged.Quality, merged.Effort)
+	}
+
+	other := shard(2, 0, FileResult{File: "b.go"})
+	other.Stats.Additions = 99
+	unsharded := &Result{Stats: stats}
+	overlap := shard(2, 0, FileResult{File: "a.go"})
+	for name, results := range map[string][]*Result{
+		"shard 2/2 is missing": {one},
+		"given twice":          {one, one, two},
+		"different diff":       {one, other},
+		"sharded and":          {one, unsharded},
+		"a.go is in shards":    {one, overlap},
+		"no results to merge":  nil,
+	} {
+		if _, err := Merge(results...); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("Merge(%s) error = %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package review
+
+import (
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+)
+
+// protectedArea is a protected path entry with its severities parsed.
+type protectedArea struct {
+	label    string
+	paths    []string
+	escalate map[providers.Severity]providers.Severity
+}
+
+func protectedAreas(cfg config.ReviewConfig) []protectedArea {
+	areas := make([]protectedArea, 0, len(cfg.ProtectedPaths))
+	for _, p := range cfg.ProtectedPaths {
+		area := protectedArea{label: p.Label(), paths: p.Paths, escalate: make(map[providers.Severity]providers.Severity)}
+		for from, to := range p.Escalate {
+			f, okFrom := providers.ParseSeverity(from)
+			t, okTo := providers.ParseSeverity(to)
+			if okFrom && okTo {
+				area.escalate[f] = t
+			}
+		}
+		areas = append(areas, area)
+	}
+	return areas
+}
+
+// protectedArea returns the first protected area containing the path.
+func (e *Engine) protectedArea(path string) (protectedArea, bool) {
+	for _, area := range e.protected {
+		for _, pattern := range area.paths {
+			if rules.MatchGlob(pattern, path) {
+				return area, true
+			}
+		}
+	}
+	return protectedArea{}, false
+}
+
+// escalateProtected labels the files in protected paths and escalates the
+// severity of their issues. It runs after every check, so the escalated
+// severities decide the exit code.
+func (e *Engine) escalateProtected(result *Result) {
+	for i := range result.Files {
+		f := &result.Files[i]
+		area, ok := e.protectedArea(f.File)
+		if !ok {
+			continue
+		}
+		f.Protected = area.label
+		if f.Response == nil {
+			continue
+		}
+		for j, issue := range f.Response.Issues {
+			severity, _ := providers.ParseSeverity(string(issue.Severity))
+			if to, ok := area.escalate[severity]; ok {
+				f.Response.Issues[j].Severity = to
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestEscalateProtected(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.ProtectedPaths = []config.ProtectedPathConfig{
+		{Paths: []string{"auth/**", "billing/**"}, Escalate: map[string]string{"warning": "error"}},
+		{Name: "payments", Paths: []string{"**/payments/*.go"}, Escalate: map[string]string{"info": "warning", "error": "critical"}},
+	}
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+
+	issues := func(severities ...providers.Severity) *providers.ReviewResponse {
+		resp := &providers.ReviewResponse{}
+		for _, s := range severities {
+			resp.Issues = append(resp.Issues, providers.Issue{Severity: s})
+		}
+		return resp
+	}
+	result := &Result{Files: []FileResult{
+		{File: "auth/session/token.go", Response: issues(providers.SeverityInfo, "WARNING", providers.SeverityError)},
+		{File: "internal/payments/charge.go", Response: issues(providers.SeverityInfo, providers.SeverityError)},
+		{File: "api/handler.go", Response: issues(providers.SeverityWarning)},
+	}}
+	engine.escalateProtected(result)
+
+	want := []struct {
+		protected  string
+		severities []providers.Severity
+	}{
+		{"auth/**", []providers.Severity{providers.SeverityInfo, providers.SeverityError, providers.SeverityError}},
+		{"payments", []providers.Severity{providers.SeverityWarning, providers.SeverityCritical}},
+		{"", []providers.Severity{providers.SeverityWarning}},
+	}
+	for i, w := range want {
+		f := result.Files[i]
+		if f.Protected != w.protected {
+			t.Errorf("%s: Protected = %q, want %q", f.File, f.Protected, w.protected)
+		}
+		for j, s := range w.severities {
+			if got := f.Response.Issues[j].Severity; got != s {
+				t.Errorf("%s: issue %d severity = %s, want %s", f.File, j, got, s)
+			}
+		}
+	}
+	if !result.ExceedsSeverity("critical") {
+		t.Error("escalated issues don't reach the fail_on threshold")
+	}
+}
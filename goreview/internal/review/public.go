@@ -0,0 +1,344 @@
+package review
+
+import (
+	"github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/debt"
+	"github.com/JNZader/goreview/goreview/internal/failure"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/provenance"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/scope"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+// Public converts the result to the public, versioned form written by the
+// JSON report. Types with the same fields are converted directly, so any
+// drift between an internal type and its public form fails to compile.
+func (r *Result) Public() *reviewtypes.Result {
+	out := &reviewtypes.Result{
+		SchemaVersion: reviewtypes.SchemaVersion,
+		TotalIssues:   r.TotalIssues,
+		Duration:      r.Duration,
+		Files:         make([]reviewtypes.FileResult, 0, len(r.Files)),
+		Stats:         reviewtypes.DiffStats(r.Stats),
+		Summary:       r.Summary,
+	}
+	for _, f := range r.Filtered {
+		out.Filtered = append(out.Filtered, reviewtypes.FilteredFile(f))
+	}
+	for _, t := range r.IssueTypes {
+		out.IssueTypes = append(out.IssueTypes, reviewtypes.IssueTypeInfo(t))
+	}
+	if r.Environment != nil {
+		env := reviewtypes.Environment(*r.Environment)
+		out.Environment = &env
+	}
+	if r.Quality != nil {
+		q := reviewtypes.Quality(*r.Quality)
+		out.Quality = &q
+	}
+	if r.Effort != nil {
+		out.Effort = &reviewtypes.Effort{Minutes: r.Effort.Minutes}
+		for _, f := range r.Effort.Files {
+			out.Effort.Files = append(out.Effort.Files, reviewtypes.FileEffort(f))
+		}
+	}
+	if r.Scope != nil {
+		out.Scope = publicScope(r.Scope)
+	}
+	for _, g := range r.Gates {
+		out.Gates = append(out.Gates, reviewtypes.GateResult(g))
+	}
+	if r.Shard != nil {
+		s := reviewtypes.Shard(*r.Shard)
+		out.Shard = &s
+	}
+	for _, u := range r.Unreviewed {
+		out.Unreviewed = append(out.Unreviewed, reviewtypes.UnreviewedFile(u))
+	}
+	if r.Remainder != nil {
+		out.Remainder = &reviewtypes.Remainder{
+			Budget: r.Remainder.Budget, Tokens: r.Remainder.Tokens, Summary: r.Remainder.Summary,
+		}
+		for _, f := range r.Remainder.Files {
+			out.Remainder.Files = append(out.Remainder.Files, reviewtypes.RemainderFile(f))
+		}
+	}
+	for _, t := range r.Themes {
+		pt := reviewtypes.Theme{
+			Title: t.Title, Type: t.Type, Severity: t.Severity, Issues: t.Issues, Files: t.Files,
+			Keywords: t.Keywords, Dir: t.Dir,
+		}
+		for _, e := range t.Examples {
+			pt.Examples = append(pt.Examples, reviewtypes.ThemeExample(e))
+		}
+		out.Themes = append(out.Themes, pt)
+	}
+	for _, e := range r.Errors {
+		out.Errors = append(out.Errors, reviewtypes.Failure(e))
+	}
+
+	for _, f := range r.Files {
+		pf := reviewtypes.FileResult{
+			File:         f.File,
+			Cached:       f.Cached,
+			Normalized:   f.Normalized,
+			RulesInScope: f.RulesInScope,
+			ReusedHunks:  f.ReusedHunks,
+			Protected:    f.Protected,
+		}
+		if f.Error != nil {
+			pf.Error = f.Error.Error()
+			pf.ErrorCode = string(failure.CodeOf(f.Error))
+		}
+		if f.Response != nil {
+			pf.Response = publicResponse(f.Response)
+		}
+		for _, m := range f.Metrics {
+			pf.Metrics = append(pf.Metrics, reviewtypes.FunctionMetrics(m))
+		}
+		for _, d := range f.Debt {
+			pf.Debt = append(pf.Debt, reviewtypes.DebtItem(d))
+		}
+		for _, b := range f.Generated {
+			pf.Generated = append(pf.Generated, reviewtypes.GeneratedBlock(b))
+		}
+		if f.Budget != nil {
+			b := reviewtypes.ContextBudget(*f.Budget)
+			pf.Budget = &b
+		}
+		if f.Guardrail != nil {
+			g := reviewtypes.Guardrail(*f.Guardrail)
+			pf.Guardrail = &g
+		}
+		if f.Trivial != nil {
+			t := reviewtypes.Trivial(*f.Trivial)
+			pf.Trivial = &t
+		}
+		if f.AST != nil {
+			c := reviewtypes.ASTCoverage(*f.AST)
+			pf.AST = &c
+		}
+		out.Files = append(out.Files, pf)
+	}
+	return out
+}
+
+func publicResponse(resp *providers.ReviewResponse) *reviewtypes.Response {
+	out := &reviewtypes.Response{
+		Issues:          make([]reviewtypes.Issue, 0, len(resp.Issues)),
+		Summary:         resp.Summary,
+		Score:           resp.Score,
+		TokensUsed:      resp.TokensUsed,
+		ProcessingTime:  resp.ProcessingTime,
+		ParseFailures:   resp.ParseFailures,
+		TruncatedChunks: resp.TruncatedChunks,
+	}
+	for _, issue := range resp.Issues {
+		pi := reviewtypes.Issue{
+			ID:          issue.ID,
+			Type:        string(issue.Type),
+			Severity:    string(issue.Severity),
+			Message:     issue.Message,
+			Suggestion:  issue.Suggestion,
+			RuleID:      issue.RuleID,
+			FixedCode:   issue.FixedCode,
+			Code:        issue.Code,
+			Transcript:  issue.Transcript,
+			CWE:         issue.CWE,
+			OWASP:       issue.OWASP,
+			Calibration: issue.Calibration,
+		}
+		if issue.Location != nil {
+			loc := reviewtypes.Location(*issue.Location)
+			pi.Location = &loc
+		}
+		if issue.RootCause != nil {
+			rc := reviewtypes.RootCause(*issue.RootCause)
+			pi.RootCause = &rc
+		}
+		if issue.Triage != nil {
+			tr := reviewtypes.Triage(*issue.Triage)
+			pi.Triage = &tr
+		}
+		out.Issues = append(out.Issues, pi)
+	}
+	return out
+}
+
+// FromPublic converts a public result, such as a decoded JSON report, back
+// to a result.
+func FromPublic(p *reviewtypes.Result) *Result {
+	out := &Result{
+		TotalIssues: p.TotalIssues,
+		Duration:    p.Duration,
+		Files:       make([]FileResult, 0, len(p.Files)),
+		Stats:       git.DiffStats(p.Stats),
+		Summary:     p.Summary,
+	}
+	for _, f := range p.Filtered {
+		out.Filtered = append(out.Filtered, FilteredFile(f))
+	}
+	for _, t := range p.IssueTypes {
+		out.IssueTypes = append(out.IssueTypes, providers.IssueTypeInfo(t))
+	}
+	if p.Environment != nil {
+		env := history.Environment(*p.Environment)
+		out.Environment = &env
+	}
+	if p.Quality != nil {
+		q := Quality(*p.Quality)
+		out.Quality = &q
+	}
+	if p.Effort != nil {
+		out.Effort = &Effort{Minutes: p.Effort.Minutes}
+		for _, f := range p.Effort.Files {
+			out.Effort.Files = append(out.Effort.Files, FileEffort(f))
+		}
+	}
+	if p.Scope != nil {
+		out.Scope = scopeFromPublic(p.Scope)
+	}
+	for _, g := range p.Gates {
+		out.Gates = append(out.Gates, GateResult(g))
+	}
+	if p.Shard != nil {
+		s := Shard(*p.Shard)
+		out.Shard = &s
+	}
+	for _, u := range p.Unreviewed {
+		out.Unreviewed = append(out.Unreviewed, UnreviewedFile(u))
+	}
+	if p.Remainder != nil {
+		out.Remainder = &Remainder{Budget: p.Remainder.Budget, Tokens: p.Remainder.Tokens, Summary: p.Remainder.Summary}
+		for _, f := range p.Remainder.Files {
+			out.Remainder.Files = append(out.Remainder.Files, RemainderFile(f))
+		}
+	}
+	for _, t := range p.Themes {
+		theme := Theme{
+			Title: t.Title, Type: t.Type, Severity: t.Severity, Issues: t.Issues, Files: t.Files,
+			Keywords: t.Keywords, Dir: t.Dir,
+		}
+		for _, e := range t.Examples {
+			theme.Examples = append(theme.Examples, ThemeExample(e))
+		}
+		out.Themes = append(out.Themes, theme)
+	}
+	for _, e := range p.Errors {
+		out.Errors = append(out.Errors, failure.Info(e))
+	}
+
+	for _, pf := range p.Files {
+		f := FileResult{
+			File:         pf.File,
+			Cached:       pf.Cached,
+			Normalized:   pf.Normalized,
+			RulesInScope: pf.RulesInScope,
+			ReusedHunks:  pf.ReusedHunks,
+			Protected:    pf.Protected,
+		}
+		if pf.Error != "" {
+			f.Error = failure.FromCode(failure.Code(pf.ErrorCode), pf.Error)
+		}
+		if pf.Response != nil {
+			f.Response = responseFromPublic(pf.Response)
+		}
+		for _, m := range pf.Metrics {
+			f.Metrics = append(f.Metrics, ast.FunctionMetrics(m))
+		}
+		for _, d := range pf.Debt {
+			f.Debt = append(f.Debt, debt.Item(d))
+		}
+		for _, b := range pf.Generated {
+			f.Generated = append(f.Generated, provenance.Block(b))
+		}
+		if pf.Budget != nil {
+			b := ContextBudget(*pf.Budget)
+			f.Budget = &b
+		}
+		if pf.Guardrail != nil {
+			g := Guardrail(*pf.Guardrail)
+			f.Guardrail = &g
+		}
+		if pf.Trivial != nil {
+			t := Trivial(*pf.Trivial)
+			f.Trivial = &t
+		}
+		if pf.AST != nil {
+			c := ast.Coverage(*pf.AST)
+			f.AST = &c
+		}
+		out.Files = append(out.Files, f)
+	}
+	return out
+}
+
+func responseFromPublic(p *reviewtypes.Response) *providers.ReviewResponse {
+	out := &providers.ReviewResponse{
+		Issues:          make([]providers.Issue, 0, len(p.Issues)),
+		Summary:         p.Summary,
+		Score:           p.Score,
+		TokensUsed:      p.TokensUsed,
+		ProcessingTime:  p.ProcessingTime,
+		ParseFailures:   p.ParseFailures,
+		TruncatedChunks: p.TruncatedChunks,
+	}
+	for _, pi := range p.Issues {
+		issue := providers.Issue{
+			ID:          pi.ID,
+			Type:        providers.IssueType(pi.Type),
+			Severity:    providers.Severity(pi.Severity),
+			Message:     pi.Message,
+			Suggestion:  pi.Suggestion,
+			RuleID:      pi.RuleID,
+			FixedCode:   pi.FixedCode,
+			Code:        pi.Code,
+			Transcript:  pi.Transcript,
+			CWE:         pi.CWE,
+			OWASP:       pi.OWASP,
+			Calibration: pi.Calibration,
+		}
+		if pi.Location != nil {
+			loc := providers.Location(*pi.Location)
+			issue.Location = &loc
+		}
+		if pi.RootCause != nil {
+			rc := providers.RootCause(*pi.RootCause)
+			issue.RootCause = &rc
+		}
+		if pi.Triage != nil {
+			tr := providers.Triage(*pi.Triage)
+			issue.Triage = &tr
+		}
+		out.Issues = append(out.Issues, issue)
+	}
+	return out
+}
+
+func publicScope(s *scope.Report) *reviewtypes.Scope {
+	out := &reviewtypes.Scope{Unrelated: s.Unrelated, Suggestions: s.Suggestions}
+	for _, c := range s.Categories {
+		out.Categories = append(out.Categories, reviewtypes.ScopeCategory{
+			Category: string(c.Category), Files: c.Files, Lines: c.Lines, Unrelated: c.Unrelated,
+		})
+	}
+	for _, h := range s.Hunks {
+		out.Hunks = append(out.Hunks, reviewtypes.ScopeHunk(h))
+	}
+	return out
+}
+
+func scopeFromPublic(p *reviewtypes.Scope) *scope.Report {
+	out := &scope.Report{Unrelated: p.Unrelated, Suggestions: p.Suggestions}
+	for _, c := range p.Categories {
+		out.Categories = append(out.Categories, scope.CategoryChange{
+			Category: scope.Category(c.Category), Files: c.Files, Lines: c.Lines, Unrelated: c.Unrelated,
+		})
+	}
+	for _, h := range p.Hunks {
+		out.Hunks = append(out.Hunks, scope.Hunk(h))
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package review
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/debt"
+	"github.com/JNZader/goreview/goreview/internal/failure"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/provenance"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+func TestPublicRoundTrip(t *testing.T) {
+	result := &Result{
+		TotalIssues: 1,
+		Duration:    3 * time.Second,
+		Stats:       git.DiffStats{FilesChanged: 2, Additions: 10, Deletions: 1},
+		IssueTypes:  []providers.IssueTypeInfo{{Name: "compliance"}},
+		Environment: &history.Environment{Version: "1.2.0", Provider: "ollama", Model: "m"},
+		Quality:     &Quality{Score: 90, Issues: 1},
+		Gates:       []GateResult{{Name: "g", Expr: "true", Passed: true}},
+		Files: []FileResult{
+			{
+				File: "a.go",
+				Response: &providers.ReviewResponse{Score: 80, Issues: []providers.Issue{{
+					ID: "1", Type: providers.IssueTypeBug, Severity: providers.SeverityError, Message: "m",
+					Location:  &providers.Location{File: "a.go", StartLine: 3, EndLine: 4},
+					RootCause: &providers.RootCause{Description: "d"},
+				}}},
+				Metrics:   []ast.FunctionMetrics{{Name: "f", Cyclomatic: 3}},
+				Debt:      []debt.Item{{File: "a.go", Line: 2, Marker: "TODO"}},
+				Generated: []provenance.Block{{StartLine: 1, EndLine: 9, Kind: provenance.KindPaste}},
+				Protected: "auth",
+			},
+			{File: "b.go", Error: &failure.ProviderError{Code: failure.ProviderTimeout, Err: errors.New("timeout")}},
+		},
+	}
+	result.Errors = collectErrors(result)
+
+	public := result.Public()
+	if public.SchemaVersion != reviewtypes.SchemaVersion || public.Files[1].Error != "timeout" || public.Files[1].ErrorCode != "provider.timeout" {
+		t.Errorf("Public() = %+v", public)
+	}
+	want := []reviewtypes.Failure{{Code: "provider.timeout", Category: "provider", Message: "timeout", File: "b.go"}}
+	if !reflect.DeepEqual(public.Errors, want) {
+		t.Errorf("Public() errors = %+v, want %+v", public.Errors, want)
+	}
+	back := FromPublic(public)
+	if back.Files[1].Error == nil || back.Files[1].Error.Error() != "timeout" || failure.CodeOf(back.Files[1].Error) != failure.ProviderTimeout {
+		t.Errorf("FromPublic() error = %v", back.Files[1].Error)
+	}
+	back.Files[1].Error = result.Files[1].Error
+	if !reflect.DeepEqual(back, result) {
+		t.Errorf("FromPublic(Public()) = %+v, want %+v", back, result)
+	}
+}
@@ -0,0 +1,133 @@
+package review
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// Quality score penalties, applied to the share of affected issues or files
+const (
+	locationWeight  = 30
+	fixWeight       = 10
+	parseWeight     = 30
+	truncatedWeight = 20
+	failedWeight    = 10
+)
+
+// degradedScore is the score below which results are flagged as degraded
+const degradedScore = 70
+
+// Quality is the self-assessed reliability of a review. It tells users when
+// results are degraded by unparseable responses or context limits.
+type Quality struct {
+	// Score is 0-100, where 100 means nothing was lost
+	Score    int  `json:"score"`
+	Degraded bool `json:"degraded"`
+
+	Issues        int `json:"issues"`
+	LocatedIssues int `json:"located_issues"`
+	IssuesWithFix int `json:"issues_with_fix"`
+	// LocationRate and FixRate are percentages of Issues
+	LocationRate float64 `json:"location_rate"`
+	FixRate      float64 `json:"fix_rate"`
+
+	ParseFailures   int `json:"parse_failures"`
+	TruncatedChunks int `json:"truncated_chunks"`
+	FailedFiles     int `json:"failed_files"`
+	// UnreviewedFiles counts the files left unreviewed, see UnreviewedFile
+	UnreviewedFiles int `json:"unreviewed_files,omitempty"`
+	// SummarizedFiles counts the files over the token budget, summarized
+	// instead of reviewed, see Remainder
+	SummarizedFiles int `json:"summarized_files,omitempty"`
+
+	// Warnings explains each problem found, for verbose output
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// assessQuality computes the quality metadata of a finished review.
+func assessQuality(result *Result) *Quality {
+	q := &Quality{}
+	reviewed := 0
+	for _, file := range result.Files {
+		if file.Error != nil {
+			q.FailedFiles++
+			continue
+		}
+		if file.Response == nil {
+			continue
+		}
+		reviewed++
+		q.ParseFailures += file.Response.ParseFailures
+		q.TruncatedChunks += file.Response.TruncatedChunks
+		for _, issue := range file.Response.Issues {
+			q.Issues++
+			if hasUsableLocation(issue.Location) {
+				q.LocatedIssues++
+			}
+			if issue.FixedCode != "" {
+				q.IssuesWithFix++
+			}
+		}
+	}
+
+	q.LocationRate, q.FixRate = 100, 100
+	if q.Issues > 0 {
+		q.LocationRate = percent(q.LocatedIssues, q.Issues)
+		q.FixRate = percent(q.IssuesWithFix, q.Issues)
+	}
+
+	score := 100.0
+	score -= (100 - q.LocationRate) / 100 * locationWeight
+	score -= (100 - q.FixRate) / 100 * fixWeight
+	if reviewed > 0 {
+		score -= math.Min(float64(q.ParseFailures)/float64(reviewed), 1) * parseWeight
+		score -= math.Min(float64(q.TruncatedChunks)/float64(reviewed), 1) * truncatedWeight
+	}
+	if len(result.Files) > 0 {
+		score -= float64(q.FailedFiles) / float64(len(result.Files)) * failedWeight
+	}
+	q.Score = max(int(math.Round(score)), 0)
+	q.UnreviewedFiles = len(result.Unreviewed)
+	if result.Remainder != nil {
+		q.SummarizedFiles = len(result.Remainder.Files)
+	}
+	q.Degraded = q.Score < degradedScore || q.ParseFailures > 0 || q.TruncatedChunks > 0 || q.UnreviewedFiles > 0 ||
+		q.SummarizedFiles > 0
+
+	q.Warnings = qualityWarnings(q)
+	return q
+}
+
+func qualityWarnings(q *Quality) []string {
+	var warnings []string
+	if q.ParseFailures > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d provider responses were not valid JSON; their issues may be missing", q.ParseFailures))
+	}
+	if q.TruncatedChunks > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d diff chunks exceeded the model's token budget and may have been cut off; try a model with a larger context", q.TruncatedChunks))
+	}
+	if q.FailedFiles > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d files could not be reviewed", q.FailedFiles))
+	}
+	if q.UnreviewedFiles > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d files were not reviewed, for running out of time or being blocked by the provider", q.UnreviewedFiles))
+	}
+	if q.SummarizedFiles > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d lower-priority files exceeded the token budget and were summarized, not reviewed", q.SummarizedFiles))
+	}
+	if q.Issues > 0 && q.LocationRate < 50 {
+		warnings = append(warnings, fmt.Sprintf("only %.0f%% of issues have a verified location", q.LocationRate))
+	}
+	return warnings
+}
+
+// hasUsableLocation reports whether a location points at a verified line.
+func hasUsableLocation(loc *providers.Location) bool {
+	return loc != nil && loc.StartLine > 0 && !loc.Unverified
+}
+
+func percent(n, total int) float64 {
+	return float64(n) / float64(total) * 100
+}
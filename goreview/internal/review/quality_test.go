@@ -0,0 +1,104 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestAssessQuality(t *testing.T) {
+	located := &providers.Location{StartLine: 10}
+	unverified := &providers.Location{StartLine: 10, Unverified: true}
+
+	tests := []struct {
+		name         string
+		files        []FileResult
+		wantScore    int
+		wantDegraded bool
+		wantWarnings int
+	}{
+		{
+			name:      "no issues",
+			files:     []FileResult{{File: "a.go", Response: &providers.ReviewResponse{}}},
+			wantScore: 100,
+		},
+		{
+			name: "located issues with fixes",
+			files: []FileResult{{File: "a.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{
+				{Location: located, FixedCode: "x"},
+				{Location: located, FixedCode: "y"},
+			}}}},
+			wantScore: 100,
+		},
+		{
+			name: "unverified locations without fixes",
+			files: []FileResult{{File: "a.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{
+				{Location: unverified},
+				{},
+			}}}},
+			wantScore:    60,
+			wantDegraded: true,
+			wantWarnings: 1,
+		},
+		{
+			name: "parse failure",
+			files: []FileResult{
+				{File: "a.go", Response: &providers.ReviewResponse{ParseFailures: 1}},
+				{File: "b.go", Response: &providers.ReviewResponse{}},
+			},
+			wantScore:    85,
+			wantDegraded: true,
+			wantWarnings: 1,
+		},
+		{
+			name: "truncated chunks and failed file",
+			files: []FileResult{
+				{File: "a.go", Response: &providers.ReviewResponse{TruncatedChunks: 2}},
+				{File: "b.go", Error: errors.New("timeout")},
+			},
+			wantScore:    75,
+			wantDegraded: true,
+			wantWarnings: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := assessQuality(&Result{Files: tt.files})
+			if q.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d", q.Score, tt.wantScore)
+			}
+			if q.Degraded != tt.wantDegraded {
+				t.Errorf("Degraded = %v, want %v", q.Degraded, tt.wantDegraded)
+			}
+			if len(q.Warnings) != tt.wantWarnings {
+				t.Errorf("Warnings = %v, want %d", q.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestEngineRunAssessesQuality(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+	repo := &MockRepository{StagedDiff: &git.Diff{Files: []git.FileDiff{
+		{Path: "main.go", Language: "go", Status: git.FileModified},
+	}}}
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			return providers.ParseReviewContent("not json", 10, 0), nil
+		},
+	}
+
+	result, err := NewEngine(cfg, repo, provider, nil, nil).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Quality == nil || result.Quality.ParseFailures != 1 || !result.Quality.Degraded {
+		t.Errorf("Quality = %+v, want one parse failure and degraded", result.Quality)
+	}
+}
@@ -0,0 +1,169 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// remainderFileChars caps the diff of each file sent to summarize the
+// remainder; the summary only needs the gist of each change
+const remainderFileChars = 1500
+
+// Remainder is the part of a change over review.token_budget. Its files,
+// the lowest-priority ones, are summarized together instead of reviewed,
+// so the report tells what they change without passing them off as
+// reviewed.
+type Remainder struct {
+	// Budget is review.token_budget
+	Budget int `json:"budget"`
+	// Tokens is the estimated size of the summarized files' diffs
+	Tokens int `json:"tokens"`
+	// Summary describes what the files change, in a short paragraph
+	Summary string `json:"summary"`
+	// Files are the summarized files, highest priority first
+	Files []RemainderFile `json:"files"`
+}
+
+// RemainderFile is a file summarized, not reviewed.
+type RemainderFile struct {
+	File      string `json:"file"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	// Tokens is the estimated size of the file's diff
+	Tokens int `json:"tokens"`
+}
+
+// splitTokenBudget keeps the files to review within review.token_budget,
+// in priority order as prioritizeFiles, and returns the rest. The first
+// file is always kept, since chunking fits it in any context window.
+// Files a guardrail catches are kept without counting, as they are
+// summarized or skipped anyway.
+func (e *Engine) splitTokenBudget(files []git.FileDiff) (kept, rest []git.FileDiff) {
+	budget := e.cfg.Review.TokenBudget
+	if budget <= 0 {
+		return files, nil
+	}
+
+	spent := 0
+	for _, f := range e.prioritizeFiles(files) {
+		tokens := e.estimator.EstimateTokens(formatDiff(f))
+		if guardrail(e.cfg.Review.Guardrails, f, func() int { return tokens }) != nil {
+			kept = append(kept, f)
+			continue
+		}
+		if len(rest) == 0 && (spent == 0 || spent+tokens <= budget) {
+			spent += tokens
+			kept = append(kept, f)
+			continue
+		}
+		rest = append(rest, f)
+	}
+	return kept, rest
+}
+
+// summarizeRemainder summarizes the files over the token budget
+// hierarchically: the files are summarized in groups that fit one request,
+// and the group summaries are combined into one. When the model fails, the
+// summary falls back to the size of the change.
+func (e *Engine) summarizeRemainder(ctx context.Context, files []git.FileDiff) *Remainder {
+	if len(files) == 0 {
+		return nil
+	}
+
+	r := &Remainder{Budget: e.cfg.Review.TokenBudget}
+	var groups []string
+	var group strings.Builder
+	additions, deletions := 0, 0
+	for _, f := range files {
+		diff := formatDiff(f)
+		tokens := e.estimator.EstimateTokens(diff)
+		r.Tokens += tokens
+		r.Files = append(r.Files, RemainderFile{File: f.Path, Additions: f.Additions, Deletions: f.Deletions, Tokens: tokens})
+		additions += f.Additions
+		deletions += f.Deletions
+
+		if len(diff) > remainderFileChars {
+			diff = diff[:remainderFileChars] + "\n... (truncated)"
+		}
+		digest := fmt.Sprintf("=== %s (+%d -%d)\n%s\n", f.Path, f.Additions, f.Deletions, diff)
+		if group.Len() > 0 && group.Len()+len(digest) > guardSummaryChars {
+			groups = append(groups, group.String())
+			group.Reset()
+		}
+		group.WriteString(digest)
+	}
+	groups = append(groups, group.String())
+	e.log.Warn("Token budget of %d exceeded: summarizing %d files (~%d tokens) instead of reviewing them",
+		r.Budget, len(r.Files), r.Tokens)
+
+	summary, err := e.summarizeGroups(ctx, groups)
+	if err != nil {
+		e.log.Warn("Summary of the files over the token budget failed: %v", err)
+		summary = fmt.Sprintf("%d files, %d lines added, %d deleted.", len(files), additions, deletions)
+	}
+	r.Summary = summary
+	return r
+}
+
+// summarizeGroups summarizes each group of file diffs, then combines the
+// summaries when there are several.
+func (e *Engine) summarizeGroups(ctx context.Context, groups []string) (string, error) {
+	const instructions = "Summarize what these changes do in one short paragraph of plain text, " +
+		"without headings, lists or code. The files are not reviewed in detail, only described."
+	summaries := make([]string, 0, len(groups))
+	for _, g := range groups {
+		summary, err := e.provider.GenerateDocumentation(ctx, g, instructions)
+		if err != nil {
+			return "", err
+		}
+		summaries = append(summaries, strings.Join(strings.Fields(summary), " "))
+	}
+	if len(summaries) == 1 {
+		return nonEmpty(summaries[0])
+	}
+
+	summary, err := e.provider.GenerateDocumentation(ctx, strings.Join(summaries, "\n\n"),
+		"Combine these summaries of parts of one change into one short paragraph of plain text, without headings, lists or code.")
+	if err != nil {
+		return "", err
+	}
+	return nonEmpty(strings.Join(strings.Fields(summary), " "))
+}
+
+func nonEmpty(summary string) (string, error) {
+	if summary == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+	return summary, nil
+}
+
+// mergeRemainders combines the remainders of several results, leaving out
+// the files reviewed in any of them.
+func mergeRemainders(results []*Result, reviewed map[string]int) *Remainder {
+	var merged *Remainder
+	for _, r := range results {
+		if r.Remainder == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &Remainder{Budget: r.Remainder.Budget}
+		}
+		for _, f := range r.Remainder.Files {
+			if _, ok := reviewed[f.File]; !ok {
+				merged.Files = append(merged.Files, f)
+				merged.Tokens += f.Tokens
+			}
+		}
+		if merged.Summary != "" {
+			merged.Summary += "\n\n"
+		}
+		merged.Summary += r.Remainder.Summary
+	}
+	if merged == nil || len(merged.Files) == 0 {
+		return nil
+	}
+	return merged
+}
@@ -0,0 +1,83 @@
+package review
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestEngineTokenBudget(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+	cfg.Cache.Enabled = false
+
+	file := func(path string, added int) git.FileDiff {
+		var lines []git.Line
+		for i := 0; i < added; i++ {
+			lines = append(lines, git.Line{Type: git.LineAddition, Content: "x := computeSomething(a, b, c)"})
+		}
+		return git.FileDiff{Path: path, Language: "go", Status: git.FileModified, Additions: added, Hunks: []git.Hunk{{Lines: lines}}}
+	}
+	files := []git.FileDiff{file("util/strings.go", 20), file("util/strings_test.go", 30), file("auth/login.go", 10), file("server/handler.go", 40)}
+	e := NewEngine(cfg, nil, nil, nil, nil)
+	cfg.Review.TokenBudget = e.estimator.EstimateTokens(formatDiff(files[2])) + e.estimator.EstimateTokens(formatDiff(files[3]))
+
+	repo := &MockRepository{StagedDiff: &git.Diff{Files: files}}
+	var reviewed []string
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			reviewed = append(reviewed, req.FilePath)
+			return &providers.ReviewResponse{Score: 90}, nil
+		},
+	}
+	cfg.Review.MaxConcurrency = 1
+
+	result, err := NewEngine(cfg, repo, provider, nil, nil).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := strings.Join(reviewed, " "); got != "auth/login.go server/handler.go" {
+		t.Errorf("reviewed = %q, want the riskiest source files within the budget", got)
+	}
+	rem := result.Remainder
+	if rem == nil || rem.Summary != "# Doc" || rem.Budget != cfg.Review.TokenBudget || len(rem.Files) != 2 {
+		t.Fatalf("remainder = %+v, want the other two files summarized", rem)
+	}
+	if rem.Files[0].File != "util/strings.go" || rem.Files[1].File != "util/strings_test.go" || rem.Tokens != rem.Files[0].Tokens+rem.Files[1].Tokens {
+		t.Errorf("remainder files = %+v", rem.Files)
+	}
+	if q := result.Quality; q.SummarizedFiles != 2 || !q.Degraded {
+		t.Errorf("quality = %+v, want 2 summarized files, degraded", q)
+	}
+
+	// The public form keeps the remainder
+	if back := FromPublic(result.Public()); back.Remainder == nil || len(back.Remainder.Files) != 2 {
+		t.Errorf("FromPublic(Public()) remainder = %+v", back.Remainder)
+	}
+}
+
+func TestSummarizeGroups(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.TokenBudget = 1000
+	e := NewEngine(cfg, nil, &MockProvider{}, nil, nil)
+	var files []git.FileDiff
+	for _, path := range []string{"a.go", "b.go", "c.go", "d.go", "e.go", "f.go", "g.go", "h.go", "i.go", "j.go"} {
+		lines := make([]git.Line, 100)
+		for i := range lines {
+			lines[i] = git.Line{Type: git.LineAddition, Content: strings.Repeat("y", 40)}
+		}
+		files = append(files, git.FileDiff{Path: path, Additions: 100, Hunks: []git.Hunk{{Lines: lines}}})
+	}
+
+	rem := e.summarizeRemainder(context.Background(), files)
+	if rem == nil || rem.Summary != "# Doc" || len(rem.Files) != 10 {
+		t.Errorf("summarizeRemainder() = %+v, want the group summaries combined", rem)
+	}
+	if e.summarizeRemainder(context.Background(), nil) != nil {
+		t.Error("summarizeRemainder() of no files is not nil")
+	}
+}
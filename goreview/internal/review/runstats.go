@@ -0,0 +1,52 @@
+package review
+
+import "time"
+
+// RunStats are the counters and phase timings of an engine run, recorded in
+// the run manifest.
+type RunStats struct {
+	// ProviderCalls counts requests sent to the provider, including each
+	// chunk of a split diff and failed requests
+	ProviderCalls  int
+	ProviderErrors int
+	// ProviderTime is the time spent waiting on the provider, summed over
+	// concurrent requests
+	ProviderTime time.Duration
+	Phases       []PhaseTiming
+}
+
+// PhaseTiming is the duration of a phase of the run.
+type PhaseTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+// RunStats returns the counters and phase timings of the last run.
+func (e *Engine) RunStats() RunStats {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	stats := e.stats
+	stats.Phases = append([]PhaseTiming(nil), e.stats.Phases...)
+	return stats
+}
+
+// recordCall counts a provider request.
+func (e *Engine) recordCall(d time.Duration, err error) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	e.stats.ProviderCalls++
+	e.stats.ProviderTime += d
+	if err != nil {
+		e.stats.ProviderErrors++
+	}
+}
+
+// recordPhase records a phase that started at start and just ended, and
+// returns the current time as the start of the next one.
+func (e *Engine) recordPhase(name string, start time.Time) time.Time {
+	now := time.Now()
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	e.stats.Phases = append(e.stats.Phases, PhaseTiming{Name: name, Duration: now.Sub(start)})
+	return now
+}
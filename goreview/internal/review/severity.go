@@ -0,0 +1,158 @@
+package review
+
+import (
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/provenance"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// severityPolicy remaps issue severities and drops issues below the
+// configured minimum. Keys are lowercase since viper lowercases map keys.
+type severityPolicy struct {
+	byType      map[string]providers.Severity
+	byRule      map[string]providers.Severity
+	minByType   map[string]providers.Severity
+	minSeverity providers.Severity
+	// strict blocks use strictMin instead of minSeverity
+	strict    []provenance.Block
+	strictMin providers.Severity
+}
+
+func newSeverityPolicy(cfg config.ReviewConfig) severityPolicy {
+	p := severityPolicy{
+		byType:    parseSeverityMap(cfg.SeverityOverrides.Types),
+		byRule:    parseSeverityMap(cfg.SeverityOverrides.Rules),
+		minByType: parseSeverityMap(cfg.SeverityOverrides.Minimum),
+	}
+	if s, ok := providers.ParseSeverity(cfg.MinSeverity); ok {
+		p.minSeverity = s
+	}
+	return p
+}
+
+func parseSeverityMap(m map[string]string) map[string]providers.Severity {
+	if len(m) == 0 {
+		return nil
+	}
+	parsed := make(map[string]providers.Severity, len(m))
+	for key, value := range m {
+		if s, ok := providers.ParseSeverity(value); ok {
+			parsed[strings.ToLower(key)] = s
+		}
+	}
+	return parsed
+}
+
+// apply returns a copy of resp with the policy applied, leaving resp (which
+// may be shared through the cache) untouched.
+func (p severityPolicy) apply(resp *providers.ReviewResponse) *providers.ReviewResponse {
+	if resp == nil {
+		return nil
+	}
+
+	out := *resp
+	out.Issues = make([]providers.Issue, 0, len(resp.Issues))
+	for _, issue := range resp.Issues {
+		issue.Severity = p.severityFor(issue)
+		// Issues without a recognized severity are never filtered out
+		if minimum := p.minimumFor(issue); minimum != "" && issue.Severity.Rank() > 0 && issue.Severity.Rank() < minimum.Rank() {
+			continue
+		}
+		out.Issues = append(out.Issues, issue)
+	}
+	return &out
+}
+
+// within returns a copy of the policy that filters the issues starting in
+// the blocks by min rather than the configured minimum.
+func (p severityPolicy) within(blocks []provenance.Block, minimum providers.Severity) severityPolicy {
+	p.strict, p.strictMin = blocks, minimum
+	return p
+}
+
+// minimumFor returns the minimum severity reported for the issue.
+func (p severityPolicy) minimumFor(issue providers.Issue) providers.Severity {
+	if issue.Location != nil {
+		for _, b := range p.strict {
+			if b.Contains(issue.Location.StartLine) {
+				return p.strictMin
+			}
+		}
+	}
+	return p.minSeverity
+}
+
+// severityFor resolves an issue's severity: rule override, then type
+// override, then the type minimum.
+func (p severityPolicy) severityFor(issue providers.Issue) providers.Severity {
+	severity := issue.Severity
+	issueType := strings.ToLower(string(issue.Type))
+
+	if s, ok := p.byRule[strings.ToLower(issue.RuleID)]; ok && issue.RuleID != "" {
+		severity = s
+	} else if s, ok := p.byType[issueType]; ok {
+		severity = s
+	}
+
+	if floor, ok := p.minByType[issueType]; ok && severity.Rank() < floor.Rank() {
+		severity = floor
+	}
+	return severity
+}
+
+// issueTypes returns the taxonomy sent to the reviewer: the built-in types
+// plus configured custom ones. Nil when no custom types are configured, so
+// the default prompt is unchanged.
+func issueTypes(cfg config.ReviewConfig) []providers.IssueTypeInfo {
+	if len(cfg.IssueTypes) == 0 {
+		return nil
+	}
+
+	types := make([]providers.IssueTypeInfo, 0, len(providers.BuiltinIssueTypes)+len(cfg.IssueTypes))
+	index := make(map[string]int)
+	for _, t := range providers.BuiltinIssueTypes {
+		index[t.Name] = len(types)
+		types = append(types, t)
+	}
+	for _, t := range cfg.IssueTypes {
+		name := strings.ToLower(strings.TrimSpace(t.Name))
+		info := providers.IssueTypeInfo{Name: name, Description: t.Description}
+		if i, ok := index[name]; ok {
+			types[i] = info // Redefine a built-in type's description
+			continue
+		}
+		index[name] = len(types)
+		types = append(types, info)
+	}
+	return types
+}
+
+// FlaggedFiles returns the files with an issue at or above the threshold,
+// in the result's order. An invalid threshold falls back to critical.
+func (r *Result) FlaggedFiles(threshold string) []string {
+	limit, ok := providers.ParseSeverity(threshold)
+	if !ok {
+		limit = providers.SeverityCritical
+	}
+	var files []string
+	for _, f := range r.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			if issue.Severity.Rank() >= limit.Rank() {
+				files = append(files, f.File)
+				break
+			}
+		}
+	}
+	return files
+}
+
+// ExceedsSeverity reports whether any issue is at or above the threshold.
+// An invalid threshold falls back to critical.
+func (r *Result) ExceedsSeverity(threshold string) bool {
+	return len(r.FlaggedFiles(threshold)) > 0
+}
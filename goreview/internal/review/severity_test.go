@@ -0,0 +1,116 @@
+package review
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestSeverityPolicyApply(t *testing.T) {
+	cfg := config.ReviewConfig{
+		MinSeverity: "warning",
+		SeverityOverrides: config.SeverityOverridesConfig{
+			Types:   map[string]string{"style": "info", "performance": "error"},
+			Rules:   map[string]string{"perf-001": "warning"}, // viper lowercases keys
+			Minimum: map[string]string{"security": "error"},
+		},
+	}
+	policy := newSeverityPolicy(cfg)
+
+	resp := &providers.ReviewResponse{Issues: []providers.Issue{
+		{ID: "style", Type: providers.IssueTypeStyle, Severity: providers.SeverityError},
+		{ID: "sec", Type: providers.IssueTypeSecurity, Severity: providers.SeverityWarning},
+		{ID: "sec-critical", Type: providers.IssueTypeSecurity, Severity: providers.SeverityCritical},
+		{ID: "perf", Type: providers.IssueTypePerformance, Severity: providers.SeverityInfo},
+		{ID: "perf-rule", Type: providers.IssueTypePerformance, Severity: providers.SeverityInfo, RuleID: "PERF-001"},
+		{ID: "unknown", Type: providers.IssueTypeBug},
+	}}
+
+	got := policy.apply(resp)
+
+	want := map[string]providers.Severity{
+		"sec":          providers.SeverityError,    // Raised to the type minimum
+		"sec-critical": providers.SeverityCritical, // Already above the minimum
+		"perf":         providers.SeverityError,    // Type override
+		"perf-rule":    providers.SeverityWarning,  // Rule override wins over type
+		"unknown":      "",                         // Unknown severities are kept
+	}
+	if len(got.Issues) != len(want) {
+		t.Fatalf("len(Issues) = %d, want %d (style remapped to info and filtered)", len(got.Issues), len(want))
+	}
+	for _, issue := range got.Issues {
+		if issue.Severity != want[issue.ID] {
+			t.Errorf("%s severity = %q, want %q", issue.ID, issue.Severity, want[issue.ID])
+		}
+	}
+
+	if resp.Issues[0].Severity != providers.SeverityError || len(resp.Issues) != 6 {
+		t.Error("apply must not modify the original (possibly cached) response")
+	}
+}
+
+func TestIssueTypes(t *testing.T) {
+	if types := issueTypes(config.ReviewConfig{}); types != nil {
+		t.Errorf("issueTypes() = %v, want nil without custom types", types)
+	}
+
+	types := issueTypes(config.ReviewConfig{IssueTypes: []config.IssueTypeConfig{
+		{Name: "Accessibility", Description: "a11y problems in UI code"},
+		{Name: "style", Description: "only team style guide violations"},
+	}})
+
+	if len(types) != len(providers.BuiltinIssueTypes)+1 {
+		t.Fatalf("len(types) = %d, want built-ins plus one", len(types))
+	}
+	last := types[len(types)-1]
+	if last.Name != "accessibility" {
+		t.Errorf("custom type name = %q, want normalized 'accessibility'", last.Name)
+	}
+	for _, ty := range types {
+		if ty.Name == "style" && ty.Description != "only team style guide violations" {
+			t.Errorf("built-in style description not overridden: %q", ty.Description)
+		}
+	}
+}
+
+func TestResultExceedsSeverity(t *testing.T) {
+	result := &Result{Files: []FileResult{{
+		Response: &providers.ReviewResponse{Issues: []providers.Issue{{Severity: providers.SeverityError}}},
+	}}}
+
+	tests := []struct {
+		threshold string
+		want      bool
+	}{
+		{"warning", true},
+		{"error", true},
+		{"critical", false},
+		{"", false}, // Falls back to critical
+	}
+	for _, tt := range tests {
+		if got := result.ExceedsSeverity(tt.threshold); got != tt.want {
+			t.Errorf("ExceedsSeverity(%q) = %v, want %v", tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestResultFlaggedFiles(t *testing.T) {
+	result := &Result{Files: []FileResult{
+		{File: "a.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{{Severity: providers.SeverityInfo}, {Severity: providers.SeverityError}}}},
+		{File: "b.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{{Severity: providers.SeverityWarning}}}},
+		{File: "c.go", Response: &providers.ReviewResponse{}},
+		{File: "d.go", Error: errors.New("timeout")},
+	}}
+	if got := result.FlaggedFiles("warning"); !reflect.DeepEqual(got, []string{"a.go", "b.go"}) {
+		t.Errorf("FlaggedFiles(warning) = %v", got)
+	}
+	if got := result.FlaggedFiles("error"); !reflect.DeepEqual(got, []string{"a.go"}) {
+		t.Errorf("FlaggedFiles(error) = %v", got)
+	}
+	if got := result.FlaggedFiles("critical"); got != nil {
+		t.Errorf("FlaggedFiles(critical) = %v, want none", got)
+	}
+}
@@ -0,0 +1,64 @@
+package review
+
+import (
+	"sort"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// Shard tells which part of the changed files a review covered.
+type Shard struct {
+	// Index is the 1-based shard number
+	Index int `json:"index"`
+	Total int `json:"total"`
+	// Files counts the files assigned to the shard
+	Files int `json:"files"`
+}
+
+// shardFiles returns the files of review.shard, or all files without one.
+// Every job sees the same diff, so each computes the same assignment: the
+// files go from biggest to smallest to the shard with the fewest changed
+// lines so far, which balances the shards when a few files dominate.
+func shardFiles(shard string, files []git.FileDiff) ([]git.FileDiff, *Shard) {
+	if shard == "" {
+		return files, nil
+	}
+	index, total, err := config.ParseShard(shard)
+	if err != nil {
+		// Validated with the config
+		return files, nil
+	}
+
+	order := make([]git.FileDiff, len(files))
+	copy(order, files)
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i].Additions+order[i].Deletions, order[j].Additions+order[j].Deletions
+		if a != b {
+			return a > b
+		}
+		return order[i].Path < order[j].Path
+	})
+	load := make([]int, total)
+	assigned := make(map[string]bool)
+	for _, f := range order {
+		least := 0
+		for i := range load {
+			if load[i] < load[least] {
+				least = i
+			}
+		}
+		load[least] += f.Additions + f.Deletions + 1
+		if least == index-1 {
+			assigned[f.Path] = true
+		}
+	}
+
+	var out []git.FileDiff
+	for _, f := range files {
+		if assigned[f.Path] {
+			out = append(out, f)
+		}
+	}
+	return out, &Shard{Index: index, Total: total, Files: len(out)}
+}
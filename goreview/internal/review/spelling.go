@@ -0,0 +1,119 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+	"github.com/JNZader/goreview/goreview/internal/spelling"
+)
+
+// Rule IDs of spelling issues
+const (
+	ruleSpelling    = "spelling"
+	ruleTerminology = "terminology"
+)
+
+// markdownNoise masks the parts of a markdown line that aren't prose:
+// inline code and URLs
+var markdownNoise = regexp.MustCompile("`[^`]*`|https?://\\S+")
+
+// checkSpelling adds info-level issues for misspellings and banned terms in
+// the comments, identifiers and markdown added by the diff. Comment and
+// markdown issues carry the corrected line as FixedCode.
+func (e *Engine) checkSpelling(file git.FileDiff, result *FileResult) {
+	if e.speller == nil || result.Response == nil {
+		return
+	}
+
+	markdown := file.Language == "markdown"
+	marker := rules.LineCommentMarker(file.Language)
+	var issues []providers.Issue
+	for _, hunk := range file.Hunks {
+		inFence := false
+		for _, line := range hunk.Lines {
+			if line.Type == git.LineDeletion {
+				continue
+			}
+			if markdown && strings.HasPrefix(strings.TrimSpace(line.Content), "```") {
+				inFence = !inFence
+				continue
+			}
+			if line.Type != git.LineAddition || inFence {
+				continue
+			}
+
+			var prose, code []spelling.Finding
+			if markdown {
+				masked := markdownNoise.ReplaceAllStringFunc(line.Content, func(s string) string { return strings.Repeat(" ", len(s)) })
+				prose = e.speller.CheckText(masked)
+			} else {
+				codePart, comment, col := spelling.SplitComment(line.Content, marker)
+				for _, f := range e.speller.CheckText(comment) {
+					f.Column += col
+					prose = append(prose, f)
+				}
+				code = e.speller.CheckIdentifiers(codePart)
+			}
+			issues = append(issues, spellingIssues(file.Path, line, prose, code)...)
+		}
+	}
+	if len(issues) == 0 {
+		return
+	}
+	checked := e.severity.apply(&providers.ReviewResponse{Issues: issues})
+	result.Response.Issues = append(result.Response.Issues, checked.Issues...)
+}
+
+// spellingIssues turns the findings of a line into issues. Every prose
+// issue gets the line with all prose fixes applied, so applying any of them
+// gives the same result.
+func spellingIssues(path string, line git.Line, prose, code []spelling.Finding) []providers.Issue {
+	fixed := line.Content
+	for i := len(prose) - 1; i >= 0; i-- {
+		fixed = spelling.Replace(fixed, prose[i])
+	}
+
+	issues := make([]providers.Issue, 0, len(prose)+len(code))
+	for _, f := range prose {
+		issue := spellingIssue(path, line, f)
+		if fixed != line.Content {
+			issue.FixedCode = fixed
+		}
+		issues = append(issues, issue)
+	}
+	for _, f := range code {
+		issues = append(issues, spellingIssue(path, line, f))
+	}
+	return issues
+}
+
+func spellingIssue(path string, line git.Line, f spelling.Finding) providers.Issue {
+	issue := providers.Issue{
+		Type:     providers.IssueTypeStyle,
+		Severity: providers.SeverityInfo,
+		Location: &providers.Location{
+			File: path, StartLine: line.NewNumber, EndLine: line.NewNumber,
+			StartCol: f.Column + 1, EndCol: f.Column + len(f.Word),
+		},
+		Code: line.Content,
+	}
+
+	if f.Kind == spelling.KindTerm {
+		issue.RuleID = ruleTerminology
+		issue.Message = fmt.Sprintf("%q is a banned term", f.Word)
+		issue.Suggestion = "Avoid this term"
+		if f.Suggestion != "" {
+			issue.Suggestion = fmt.Sprintf("Use %q instead", f.Suggestion)
+		}
+	} else {
+		issue.RuleID = ruleSpelling
+		issue.Message = fmt.Sprintf("%q is misspelled", f.Word)
+		issue.Suggestion = fmt.Sprintf("Use %q", f.Suggestion)
+	}
+	issue.ID = fmt.Sprintf("%s:%s:%d:%d", issue.RuleID, path, line.NewNumber, f.Column+1)
+	return issue
+}
@@ -0,0 +1,76 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestCheckSpelling(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.MinSeverity = "info"
+	cfg.Review.Spelling = config.SpellingConfig{Enabled: true, Terms: map[string]string{"whitelist": "allowlist"}}
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+
+	file := git.FileDiff{Path: "main.go", Language: "go", Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineContext, Content: "// teh old comment", NewNumber: 1},
+		{Type: git.LineAddition, Content: "x := 1 // recieve the whitelist", NewNumber: 2},
+		{Type: git.LineAddition, Content: "var paramterCount int", NewNumber: 3},
+		{Type: git.LineDeletion, Content: "// seperate", OldNumber: 3},
+	}}}}
+	result := &FileResult{File: "main.go", Response: &providers.ReviewResponse{}}
+	engine.checkSpelling(file, result)
+
+	issues := result.Response.Issues
+	if len(issues) != 3 {
+		t.Fatalf("issues = %+v, want 3", issues)
+	}
+	if issues[0].RuleID != ruleSpelling || issues[1].RuleID != ruleTerminology {
+		t.Errorf("rule IDs = %s, %s", issues[0].RuleID, issues[1].RuleID)
+	}
+	wantFix := "x := 1 // receive the allowlist"
+	if issues[0].FixedCode != wantFix || issues[1].FixedCode != wantFix {
+		t.Errorf("FixedCode = %q, %q, want %q", issues[0].FixedCode, issues[1].FixedCode, wantFix)
+	}
+	if issues[2].Location.StartLine != 3 || issues[2].FixedCode != "" {
+		t.Errorf("identifier issue = %+v, want line 3 without a fix", issues[2])
+	}
+	for _, issue := range issues {
+		if issue.Severity != providers.SeverityInfo {
+			t.Errorf("severity = %s, want info", issue.Severity)
+		}
+	}
+
+	// Info issues are dropped below the configured minimum
+	cfg.Review.MinSeverity = "warning"
+	engine = NewEngine(cfg, nil, nil, nil, nil)
+	result = &FileResult{File: "main.go", Response: &providers.ReviewResponse{}}
+	engine.checkSpelling(file, result)
+	if len(result.Response.Issues) != 0 {
+		t.Errorf("issues = %d, want none below min_severity", len(result.Response.Issues))
+	}
+}
+
+func TestCheckSpellingMarkdown(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.MinSeverity = "info"
+	cfg.Review.Spelling.Enabled = true
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+
+	file := git.FileDiff{Path: "README.md", Language: "markdown", Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineAddition, Content: "Run `recieve` to sync, see https://example.com/seperate", NewNumber: 1},
+		{Type: git.LineAddition, Content: "```", NewNumber: 2},
+		{Type: git.LineAddition, Content: "occured inside code", NewNumber: 3},
+		{Type: git.LineAddition, Content: "```", NewNumber: 4},
+		{Type: git.LineAddition, Content: "It occured twice.", NewNumber: 5},
+	}}}}
+	result := &FileResult{File: "README.md", Response: &providers.ReviewResponse{}}
+	engine.checkSpelling(file, result)
+
+	issues := result.Response.Issues
+	if len(issues) != 1 || issues[0].Location.StartLine != 5 || issues[0].FixedCode != "It occurred twice." {
+		t.Errorf("issues = %+v, want one fixable issue on line 5", issues)
+	}
+}
@@ -0,0 +1,116 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/gotaint"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// taintClasses name the injection class of each taint rule
+var taintClasses = map[string]string{
+	gotaint.RuleSQL:     "SQL injection",
+	gotaint.RuleCommand: "Command injection",
+	gotaint.RuleHTML:    "Cross-site scripting",
+	gotaint.RulePath:    "Path traversal",
+}
+
+// taintCWEs classify each injection class
+var taintCWEs = map[string]string{
+	gotaint.RuleSQL:     "CWE-89",
+	gotaint.RuleCommand: "CWE-78",
+	gotaint.RuleHTML:    "CWE-79",
+	gotaint.RulePath:    "CWE-22",
+}
+
+// taintSuggestions tell how to fix each injection class
+var taintSuggestions = map[string]string{
+	gotaint.RuleSQL:     "Pass the input as a query parameter (?, $1) instead of building the query from it.",
+	gotaint.RuleCommand: "Don't run input through a shell or as the program; validate it against an allowlist and pass it as a separate argument.",
+	gotaint.RuleHTML:    "Render the output with html/template, or escape the input with html.EscapeString.",
+	gotaint.RulePath:    "Clean the path and check it stays inside the intended directory, or map the input to known file names.",
+}
+
+// taintFlows returns the flows of user input to sinks in a Go file that
+// touch the lines added by the diff, in the security review mode, and the
+// file's lines.
+func (e *Engine) taintFlows(file git.FileDiff) ([]gotaint.Flow, []string) {
+	if !e.taintChecks || file.Language != "go" || strings.HasSuffix(file.Path, "_test.go") {
+		return nil, nil
+	}
+	content, ok := e.readRepoFile(file.Path)
+	if !ok {
+		return nil, nil
+	}
+	flows, err := gotaint.Check(file.Path, []byte(content))
+	if err != nil {
+		e.log.Debug("Taint tracking skipped for %s: %v", file.Path, err)
+		return nil, nil
+	}
+
+	changed := changedLines(file)
+	var touched []gotaint.Flow
+	for _, f := range flows {
+		if touches(changed, min(f.SourceLine, f.SinkLine), max(f.SourceLine, f.SinkLine)) {
+			touched = append(touched, f)
+		}
+	}
+	return touched, strings.Split(content, "\n")
+}
+
+// taintHints formats the flows of a file for the review prompt. Certain
+// flows are reported by checkTaint, so the model is told not to repeat
+// them; the others need its judgement.
+func (e *Engine) taintHints(file git.FileDiff) []string {
+	var hints []string
+	flows, _ := e.taintFlows(file)
+	for _, f := range flows {
+		status := "possible, check for validation"
+		if f.Certain {
+			status = "unchecked, already reported"
+		}
+		hints = append(hints, fmt.Sprintf("line %d (%s, %s): %s", f.SinkLine, f.Rule, status, f.Describe()))
+	}
+	return hints
+}
+
+// checkTaint reports the flows of user input that reach a sink unchecked.
+// Flows that may be validated on the way are left to the model.
+func (e *Engine) checkTaint(file git.FileDiff, result *FileResult) {
+	if result.Response == nil {
+		return
+	}
+	flows, lines := e.taintFlows(file)
+	var issues []providers.Issue
+	for _, f := range flows {
+		if !f.Certain {
+			continue
+		}
+		severity := providers.SeverityCritical
+		if f.Rule == gotaint.RuleHTML {
+			severity = providers.SeverityError
+		}
+		issue := providers.Issue{
+			ID:         fmt.Sprintf("%s:%s:%d", f.Rule, file.Path, f.SinkLine),
+			Type:       providers.IssueTypeSecurity,
+			Severity:   severity,
+			Message:    fmt.Sprintf("%s: %s", taintClasses[f.Rule], f.Describe()),
+			Suggestion: taintSuggestions[f.Rule],
+			RuleID:     f.Rule,
+			Location:   &providers.Location{File: file.Path, StartLine: f.SinkLine, EndLine: f.SinkLine},
+			CWE:        taintCWEs[f.Rule],
+			OWASP:      providers.KnownCWEs[taintCWEs[f.Rule]].OWASP,
+		}
+		if f.SinkLine <= len(lines) {
+			issue.Code = lines[f.SinkLine-1]
+		}
+		issues = append(issues, issue)
+	}
+	if len(issues) == 0 {
+		return
+	}
+	checked := e.severity.apply(&providers.ReviewResponse{Issues: issues})
+	result.Response.Issues = append(result.Response.Issues, checked.Issues...)
+}
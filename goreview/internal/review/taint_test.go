@@ -0,0 +1,57 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestCheckTaint(t *testing.T) {
+	dir := t.TempDir()
+	src := "package api\n\nfunc Get(db *sql.DB, r *http.Request) {\n\tid := r.FormValue(\"id\")\n\tdb.Query(\"SELECT * FROM t WHERE id = \" + id)\n" +
+		"\tname := r.FormValue(\"name\")\n\tif name == \"\" {\n\t\treturn\n\t}\n\tdb.Exec(\"DELETE FROM t WHERE name = '\" + name + \"'\")\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Review.Modes = "security"
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+
+	file := git.FileDiff{Path: "api.go", Language: "go", Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineAddition, Content: "\tdb.Query(\"SELECT * FROM t WHERE id = \" + id)", NewNumber: 5},
+		{Type: git.LineAddition, Content: "\tdb.Exec(\"DELETE FROM t WHERE name = '\" + name + \"'\")", NewNumber: 10},
+	}}}}
+
+	// Both flows are hints; only the unchecked one is also an issue
+	hints := engine.taintHints(file)
+	if len(hints) != 2 || !strings.Contains(hints[0], "already reported") || !strings.Contains(hints[1], "check for validation") {
+		t.Errorf("hints = %q", hints)
+	}
+	result := &FileResult{File: "api.go", Response: &providers.ReviewResponse{}}
+	engine.checkTaint(file, result)
+	issues := result.Response.Issues
+	if len(issues) != 1 {
+		t.Fatalf("issues = %+v, want 1", issues)
+	}
+	if issues[0].RuleID != "taint/sql" || issues[0].Type != providers.IssueTypeSecurity || issues[0].Location.StartLine != 5 || issues[0].CWE != "CWE-89" || issues[0].OWASP != "A03:2021" ||
+		!strings.HasPrefix(issues[0].Message, "SQL injection: user input from r.FormValue(\"id\")") {
+		t.Errorf("issue = %+v", issues[0])
+	}
+
+	// Without the security mode nothing is tracked
+	cfg.Review.Modes = "errors"
+	engine = NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+	result = &FileResult{File: "api.go", Response: &providers.ReviewResponse{}}
+	engine.checkTaint(file, result)
+	if len(result.Response.Issues) != 0 || len(engine.taintHints(file)) != 0 {
+		t.Errorf("issues = %+v, want none outside the security mode", result.Response.Issues)
+	}
+}
@@ -0,0 +1,202 @@
+package review
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+	"github.com/JNZader/goreview/goreview/internal/spelling"
+	"github.com/JNZader/goreview/goreview/internal/testcheck"
+)
+
+// ruleAssertionGap is the rule ID of behavior changes whose tests' assertions
+// didn't change
+const ruleAssertionGap = "tests/assertion-gap"
+
+// checkFlakiness adds an issue for each flakiness pattern in the test code
+// added by the diff. It runs in the tests review mode.
+func (e *Engine) checkFlakiness(file git.FileDiff, result *FileResult) {
+	if !e.testChecks || result.Response == nil || !testcheck.IsTestFile(file.Path) {
+		return
+	}
+	marker := rules.LineCommentMarker(file.Language)
+
+	var issues []providers.Issue
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type != git.LineAddition {
+				continue
+			}
+			code, _, _ := spelling.SplitComment(line.Content, marker)
+			for _, f := range testcheck.Flaky(file.Language, code) {
+				issues = append(issues, providers.Issue{
+					ID:         fmt.Sprintf("%s:%s:%d", f.Rule, file.Path, line.NewNumber),
+					Type:       providers.IssueTypeFlakyTest,
+					Severity:   providers.SeverityWarning,
+					Message:    f.Message,
+					Suggestion: f.Suggestion,
+					RuleID:     f.Rule,
+					Location:   &providers.Location{File: file.Path, StartLine: line.NewNumber, EndLine: line.NewNumber},
+					Code:       line.Content,
+				})
+			}
+		}
+	}
+	if len(issues) == 0 {
+		return
+	}
+	checked := e.severity.apply(&providers.ReviewResponse{Issues: issues})
+	result.Response.Issues = append(result.Response.Issues, checked.Issues...)
+}
+
+// checkAssertionGaps adds an issue to each source file whose behavior the
+// diff changes while no assertion of its tests changes: neither in its test
+// file nor in another test of its directory. Files without tests are left
+// to the --require-tests check. It runs in the tests review mode.
+func (e *Engine) checkAssertionGaps(files []git.FileDiff, result *Result) {
+	if !e.testChecks {
+		return
+	}
+
+	// Changed test files, and whether any of their assertions changed
+	tests := make(map[string]bool)
+	for _, f := range files {
+		if testcheck.IsTestFile(f.Path) {
+			tests[f.Path] = assertionsChanged(f)
+		}
+	}
+
+	for _, f := range files {
+		if testcheck.IsTestFile(f.Path) {
+			continue
+		}
+		line := firstBehaviorChange(f)
+		if line == 0 {
+			continue
+		}
+		testFile, changed, ok := e.findTest(f.Path, tests)
+		if !ok || assertionsChangedNear(f.Path, tests) {
+			continue
+		}
+
+		message := fmt.Sprintf("Behavior changed, but %s has no matching changes", testFile)
+		if changed {
+			message = fmt.Sprintf("Behavior changed, but the changes to %s don't touch any assertion", testFile)
+		}
+		issue := providers.Issue{
+			ID:         fmt.Sprintf("%s:%s", ruleAssertionGap, f.Path),
+			Type:       providers.IssueTypeTestGap,
+			Severity:   providers.SeverityWarning,
+			Message:    message,
+			Suggestion: "Add or update assertions that pin down the new behavior",
+			RuleID:     ruleAssertionGap,
+			Location:   &providers.Location{File: f.Path, StartLine: line, EndLine: line},
+		}
+		checked := e.severity.apply(&providers.ReviewResponse{Issues: []providers.Issue{issue}})
+		for i := range result.Files {
+			if result.Files[i].File == f.Path && result.Files[i].Response != nil {
+				result.Files[i].Response.Issues = append(result.Files[i].Response.Issues, checked.Issues...)
+				result.TotalIssues += len(checked.Issues)
+				break
+			}
+		}
+	}
+}
+
+// findTest returns the test file of a source file, preferring one changed by
+// the diff, and whether it was changed. ok is false when it has none.
+func (e *Engine) findTest(file string, tests map[string]bool) (testFile string, changed, ok bool) {
+	candidates := testcheck.TestPaths(file)
+	for _, c := range candidates {
+		if _, found := tests[filepath.ToSlash(c)]; found {
+			return filepath.ToSlash(c), true, true
+		}
+	}
+	fsys := e.repoFiles()
+	if fsys == nil {
+		return "", false, false
+	}
+	for _, c := range candidates {
+		if _, err := fs.Stat(fsys, filepath.ToSlash(c)); err == nil {
+			return filepath.ToSlash(c), false, true
+		}
+	}
+	return "", false, false
+}
+
+// assertionsChangedNear reports whether the diff changes an assertion of a
+// test of the file: in one of its test paths or in its directory.
+func assertionsChangedNear(file string, tests map[string]bool) bool {
+	for _, c := range testcheck.TestPaths(file) {
+		if tests[filepath.ToSlash(c)] {
+			return true
+		}
+	}
+	dir := path.Dir(file)
+	for t, changed := range tests {
+		if changed && path.Dir(t) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// assertionsChanged reports whether the diff adds or removes an assertion.
+func assertionsChanged(file git.FileDiff) bool {
+	marker := rules.LineCommentMarker(file.Language)
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type == git.LineContext {
+				continue
+			}
+			code, _, _ := spelling.SplitComment(line.Content, marker)
+			if testcheck.IsAssertion(code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// firstBehaviorChange returns the line of the first added or removed code
+// line in the new file, or 0 when the diff only touches comments, blank
+// lines and imports.
+func firstBehaviorChange(file git.FileDiff) int {
+	marker := rules.LineCommentMarker(file.Language)
+	if marker == "" {
+		return 0 // Not code the tests could cover
+	}
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type == git.LineContext {
+				continue
+			}
+			code, _, _ := spelling.SplitComment(line.Content, marker)
+			code = strings.TrimSpace(code)
+			if code == "" || isImport(code) || strings.HasPrefix(code, "/*") || strings.HasPrefix(code, "*") {
+				continue
+			}
+			if line.Type == git.LineAddition {
+				return line.NewNumber
+			}
+			return max(hunk.NewStart, 1)
+		}
+	}
+	return 0
+}
+
+// isImport reports whether a code line is a package, import or require
+// statement, which don't change behavior by themselves.
+func isImport(code string) bool {
+	for _, prefix := range []string{"package ", "import ", "import(", "from ", "require ", "use ", "using "} {
+		if strings.HasPrefix(code, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestCheckFlakiness(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Modes = "security,tests"
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+
+	file := git.FileDiff{Path: "pkg/client_test.go", Language: "go", Hunks: []git.Hunk{{Lines: []git.Line{
+		{Type: git.LineContext, Content: "\ttime.Sleep(time.Second)", NewNumber: 1},
+		{Type: git.LineAddition, Content: "\ttime.Sleep(time.Second) // wait for the worker", NewNumber: 2},
+		{Type: git.LineAddition, Content: "\t// time.Sleep(time.Second)", NewNumber: 3},
+		{Type: git.LineAddition, Content: `	os.Setenv("TZ", "UTC")`, NewNumber: 4},
+	}}}}
+	result := &FileResult{File: file.Path, Response: &providers.ReviewResponse{}}
+	engine.checkFlakiness(file, result)
+
+	issues := result.Response.Issues
+	if len(issues) != 2 {
+		t.Fatalf("issues = %+v, want sleep and setenv", issues)
+	}
+	if issues[0].Type != providers.IssueTypeFlakyTest || issues[0].Location.StartLine != 2 || issues[1].Location.StartLine != 4 {
+		t.Errorf("issues = %+v", issues)
+	}
+
+	// Source files and other modes aren't checked
+	source := file
+	source.Path = "pkg/client.go"
+	result = &FileResult{File: source.Path, Response: &providers.ReviewResponse{}}
+	engine.checkFlakiness(source, result)
+	cfg.Review.Modes = "security"
+	NewEngine(cfg, nil, nil, nil, nil).checkFlakiness(file, result)
+	if len(result.Response.Issues) != 0 {
+		t.Errorf("issues = %+v, want none", result.Response.Issues)
+	}
+}
+
+func TestCheckAssertionGaps(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"calc/sum.go", "calc/sum_test.go", "calc/mul.go", "calc/mul_test.go", "calc/util.go", "conv/div.go", "conv/div_test.go"} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(f)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("package x\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Review.Modes = "tests"
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+	engine.repoRoot = dir
+
+	change := func(path, content string) git.FileDiff {
+		return git.FileDiff{Path: path, Language: "go", Hunks: []git.Hunk{{NewStart: 10, Lines: []git.Line{
+			{Type: git.LineContext, Content: "func f() {", NewNumber: 10},
+			{Type: git.LineAddition, Content: content, NewNumber: 11},
+		}}}}
+	}
+	files := []git.FileDiff{
+		change("calc/sum.go", "\treturn a + b + 1"),      // Test untouched
+		change("calc/mul.go", "\treturn a * b"),          // Test changed, no assertion
+		change("calc/mul_test.go", "\tgot := Mul(2, 3)"), //
+		change("calc/util.go", "\treturn nil"),           // No test file
+		change("calc/doc.go", "// Package calc adds"),    // Comment only
+		change("conv/div.go", "\treturn a / b"),          // Assertion changed
+		change("conv/div_test.go", `	if got != 2 { t.Errorf("got %d", got) }`),
+	}
+	result := &Result{}
+	for _, f := range files {
+		result.Files = append(result.Files, FileResult{File: f.Path, Response: &providers.ReviewResponse{}})
+	}
+	engine.checkAssertionGaps(files, result)
+
+	if result.TotalIssues != 2 {
+		t.Fatalf("TotalIssues = %d, want 2", result.TotalIssues)
+	}
+	sum := result.Files[0].Response.Issues
+	if len(sum) != 1 || sum[0].Type != providers.IssueTypeTestGap || sum[0].Location.StartLine != 11 ||
+		sum[0].Message != "Behavior changed, but calc/sum_test.go has no matching changes" {
+		t.Errorf("sum.go issues = %+v", sum)
+	}
+	mul := result.Files[1].Response.Issues
+	if len(mul) != 1 || mul[0].Message != "Behavior changed, but the changes to calc/mul_test.go don't touch any assertion" {
+		t.Errorf("mul.go issues = %+v", mul)
+	}
+
+	// An assertion change in another test of the package also covers it
+	files = append(files, change("calc/extra_test.go", "\tassert.Equal(t, 6, Mul(2, 3))"))
+	result.Files, result.TotalIssues = nil, 0
+	for _, f := range files {
+		result.Files = append(result.Files, FileResult{File: f.Path, Response: &providers.ReviewResponse{}})
+	}
+	engine.checkAssertionGaps(files, result)
+	if result.TotalIssues != 0 {
+		t.Errorf("TotalIssues = %d, want 0", result.TotalIssues)
+	}
+}
@@ -0,0 +1,255 @@
+package review
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/memory"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+const (
+	// themeExamples is how many representative issues a theme shows
+	themeExamples = 3
+	// themeKeywords is the most keywords in a theme's title
+	themeKeywords = 3
+)
+
+// themeFillers are words too common in review messages to tell a theme
+var themeFillers = map[string]bool{
+	"code": true, "consider": true, "could": true, "line": true, "may": true,
+	"might": true, "use": true, "using": true, "would": true,
+}
+
+// Theme is a group of related issues, like the unchecked errors of a
+// package, so reviews with many findings can be taken in at a glance.
+type Theme struct {
+	// Title names the theme from its issue type, keywords and directory
+	Title string `json:"title"`
+	// Type is the most common issue type of the theme
+	Type string `json:"type"`
+	// Severity is the worst severity among its issues
+	Severity string `json:"severity"`
+	Issues   int    `json:"issues"`
+	Files    int    `json:"files"`
+	// Keywords are the words most of its issue messages share
+	Keywords []string `json:"keywords,omitempty"`
+	// Dir is the directory holding all its files, when not the root
+	Dir string `json:"dir,omitempty"`
+	// Examples are its most representative issues, the worst first
+	Examples []ThemeExample `json:"examples"`
+}
+
+// ThemeExample is an issue shown as an example of its theme.
+type ThemeExample struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// themeIssue is an issue being grouped, with the embedding of its message.
+type themeIssue struct {
+	file   string
+	issue  providers.Issue
+	vector []float32
+}
+
+// themeCluster is a group of issues and the sum of their embeddings.
+type themeCluster struct {
+	centroid []float32
+	members  []themeIssue
+}
+
+// AssignThemes groups the result's issues into themes when it has at least
+// cfg.MinIssues of them, replacing any themes it had. Issues related to no
+// other are left out of themes.
+func AssignThemes(result *Result, cfg config.ThemesConfig) {
+	result.Themes = nil
+	if !cfg.Enabled {
+		return
+	}
+	var issues []themeIssue
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			issues = append(issues, themeIssue{file: f.File, issue: issue})
+		}
+	}
+	if len(issues) < cfg.MinIssues {
+		return
+	}
+	result.Themes = clusterIssues(issues, cfg.Similarity)
+}
+
+// clusterIssues assigns each issue, the worst first, to the closest group
+// within similarity of it, or starts a group with it. Groups of two or more
+// issues become themes, the biggest first.
+func clusterIssues(issues []themeIssue, similarity float64) []Theme {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.issue.Severity.Rank() != b.issue.Severity.Rank() {
+			return a.issue.Severity.Rank() > b.issue.Severity.Rank()
+		}
+		if a.file != b.file {
+			return a.file < b.file
+		}
+		return issueLine(a.issue) < issueLine(b.issue)
+	})
+
+	embedder := memory.NewEmbedder()
+	var clusters []*themeCluster
+	for _, ti := range issues {
+		ti.vector = embedder.Embed(string(ti.issue.Type) + " " + ti.issue.Message)
+		var best *themeCluster
+		bestScore := similarity
+		for _, c := range clusters {
+			if score := embedder.Similarity(c.centroid, ti.vector); score >= bestScore {
+				best, bestScore = c, score
+			}
+		}
+		if best == nil {
+			best = &themeCluster{centroid: make([]float32, len(ti.vector))}
+			clusters = append(clusters, best)
+		}
+		for i, v := range ti.vector {
+			best.centroid[i] += v
+		}
+		best.members = append(best.members, ti)
+	}
+
+	var themes []Theme
+	for _, c := range clusters {
+		if len(c.members) >= 2 {
+			themes = append(themes, c.theme(embedder))
+		}
+	}
+	sort.SliceStable(themes, func(i, j int) bool {
+		if themes[i].Issues != themes[j].Issues {
+			return themes[i].Issues > themes[j].Issues
+		}
+		return providers.Severity(themes[i].Severity).Rank() > providers.Severity(themes[j].Severity).Rank()
+	})
+	return themes
+}
+
+// theme describes the cluster. Its members are sorted worst first.
+func (c *themeCluster) theme(embedder *memory.Embedder) Theme {
+	types := make(map[string]int)
+	files := make(map[string]bool)
+	var dirs []string
+	for _, m := range c.members {
+		types[string(m.issue.Type)]++
+		if !files[m.file] {
+			files[m.file] = true
+			dirs = append(dirs, path.Dir(m.file))
+		}
+	}
+
+	t := Theme{
+		Type:     mostCommon(types),
+		Severity: string(c.members[0].issue.Severity),
+		Issues:   len(c.members),
+		Files:    len(files),
+		Keywords: c.keywords(embedder),
+		Dir:      commonDir(dirs),
+	}
+	t.Title = t.Type + " issues"
+	if len(t.Keywords) > 0 {
+		t.Title += " about " + strings.Join(t.Keywords, ", ")
+	}
+	if t.Dir != "" {
+		t.Title += " in " + t.Dir
+	}
+
+	// The examples are the members closest to the theme as a whole
+	closest := make([]themeIssue, len(c.members))
+	copy(closest, c.members)
+	sort.SliceStable(closest, func(i, j int) bool {
+		return embedder.Similarity(c.centroid, closest[i].vector) > embedder.Similarity(c.centroid, closest[j].vector)
+	})
+	closest = closest[:min(themeExamples, len(closest))]
+	sort.SliceStable(closest, func(i, j int) bool {
+		return closest[i].issue.Severity.Rank() > closest[j].issue.Severity.Rank()
+	})
+	for _, m := range closest {
+		t.Examples = append(t.Examples, ThemeExample{
+			File: m.file, Line: issueLine(m.issue), Severity: string(m.issue.Severity), Message: m.issue.Message,
+		})
+	}
+	return t
+}
+
+// keywords returns the words in at least half the members' messages, the
+// most frequent first.
+func (c *themeCluster) keywords(embedder *memory.Embedder) []string {
+	counts := make(map[string]int)
+	for _, m := range c.members {
+		seen := make(map[string]bool)
+		for _, word := range embedder.Keywords(m.issue.Message) {
+			if !seen[word] && !themeFillers[word] && len(word) > 2 {
+				seen[word] = true
+				counts[word]++
+			}
+		}
+	}
+	var words []string
+	for word, n := range counts {
+		if n*2 >= len(c.members) {
+			words = append(words, word)
+		}
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	return words[:min(themeKeywords, len(words))]
+}
+
+// mostCommon returns the key with the highest count, the alphabetically
+// first on ties.
+func mostCommon(counts map[string]int) string {
+	best, bestCount := "", 0
+	for key, n := range counts {
+		if n > bestCount || (n == bestCount && key < best) {
+			best, bestCount = key, n
+		}
+	}
+	return best
+}
+
+// commonDir returns the deepest directory holding all of dirs, or "" when
+// it is the root.
+func commonDir(dirs []string) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	common := strings.Split(dirs[0], "/")
+	for _, d := range dirs[1:] {
+		parts := strings.Split(d, "/")
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	dir := strings.Join(common, "/")
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// issueLine is the line an issue starts on, or 0 when it has no location.
+func issueLine(issue providers.Issue) int {
+	if issue.Location == nil {
+		return 0
+	}
+	return issue.Location.StartLine
+}
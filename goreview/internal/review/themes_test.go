@@ -0,0 +1,81 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestAssignThemes(t *testing.T) {
+	issue := func(typ providers.IssueType, severity providers.Severity, line int, message string) providers.Issue {
+		return providers.Issue{Type: typ, Severity: severity, Message: message, Location: &providers.Location{StartLine: line}}
+	}
+	file := func(path string, issues ...providers.Issue) FileResult {
+		return FileResult{File: path, Response: &providers.ReviewResponse{Issues: issues}}
+	}
+	result := &Result{Files: []FileResult{
+		file("internal/store/users.go",
+			issue(providers.IssueTypeBug, providers.SeverityError, 12, "Error returned by db.Exec is not checked"),
+			issue(providers.IssueTypeBug, providers.SeverityWarning, 30, "Error returned by rows.Close is not checked"),
+			issue(providers.IssueTypeStyle, providers.SeverityInfo, 5, "Exported function Save has no doc comment"),
+		),
+		file("internal/store/orders.go",
+			issue(providers.IssueTypeBug, providers.SeverityWarning, 8, "Error returned by tx.Commit is not checked"),
+			issue(providers.IssueTypeSecurity, providers.SeverityCritical, 40, "SQL query built by string concatenation allows injection"),
+		),
+		file("cmd/api/main.go",
+			issue(providers.IssueTypeStyle, providers.SeverityInfo, 3, "Exported function Run has no doc comment"),
+		),
+	}}
+	cfg := config.ThemesConfig{Enabled: true, MinIssues: 5, Similarity: 0.3}
+
+	AssignThemes(result, cfg)
+	if len(result.Themes) != 2 {
+		t.Fatalf("themes = %+v, want 2", result.Themes)
+	}
+	errs := result.Themes[0]
+	if errs.Type != "bug" || errs.Severity != "error" || errs.Issues != 3 || errs.Files != 2 || errs.Dir != "internal/store" {
+		t.Errorf("first theme = %+v, want the 3 unchecked errors", errs)
+	}
+	for _, word := range []string{"checked", "error", "returned"} {
+		if !strings.Contains(errs.Title, word) {
+			t.Errorf("title = %q, want it to mention %q", errs.Title, word)
+		}
+	}
+	if len(errs.Examples) != 3 || errs.Examples[0].Severity != "error" || errs.Examples[0].Line != 12 {
+		t.Errorf("examples = %+v, want the error first", errs.Examples)
+	}
+	if docs := result.Themes[1]; docs.Issues != 2 || docs.Dir != "" || !strings.Contains(docs.Title, "comment") {
+		t.Errorf("second theme = %+v, want the 2 missing doc comments", docs)
+	}
+
+	// Too few issues, or disabled, and there are no themes
+	cfg.MinIssues = 10
+	AssignThemes(result, cfg)
+	if result.Themes != nil {
+		t.Errorf("themes = %+v, want none below min_issues", result.Themes)
+	}
+	cfg.MinIssues, cfg.Enabled = 0, false
+	AssignThemes(result, cfg)
+	if result.Themes != nil {
+		t.Errorf("themes = %+v, want none when disabled", result.Themes)
+	}
+}
+
+func TestThemesCapExamples(t *testing.T) {
+	var issues []providers.Issue
+	for i := 0; i < 6; i++ {
+		issues = append(issues, providers.Issue{
+			Type: providers.IssueTypePerformance, Severity: providers.SeverityWarning,
+			Message: fmt.Sprintf("Slice allocated in loop %d without preallocated capacity", i),
+		})
+	}
+	result := &Result{Files: []FileResult{{File: "a.go", Response: &providers.ReviewResponse{Issues: issues}}}}
+	AssignThemes(result, config.ThemesConfig{Enabled: true, Similarity: 0.3})
+	if len(result.Themes) != 1 || result.Themes[0].Issues != 6 || len(result.Themes[0].Examples) != themeExamples {
+		t.Errorf("themes = %+v, want one theme of 6 with %d examples", result.Themes, themeExamples)
+	}
+}
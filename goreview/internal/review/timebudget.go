@@ -0,0 +1,108 @@
+package review
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/testcheck"
+	"github.com/JNZader/goreview/goreview/internal/tokenizer"
+)
+
+// Reasons of files left unreviewed
+const (
+	// UnreviewedTimeBudget is the reason of files left out when the time
+	// budget ran out
+	UnreviewedTimeBudget = "time_budget"
+	// UnreviewedBlocked is the reason of files the provider's safety
+	// filters refused to review
+	UnreviewedBlocked = "safety_block"
+)
+
+// Risk score points
+const (
+	riskMaxLines  = 500
+	riskProtected = 50
+	riskSensitive = 25
+)
+
+// sensitiveWords mark paths whose changes are riskier than their size says
+var sensitiveWords = []string{
+	"auth", "crypto", "security", "secret", "password", "token", "session",
+	"permission", "payment", "billing", "migration",
+}
+
+// UnreviewedFile is a file that would have been reviewed, but wasn't.
+type UnreviewedFile struct {
+	File string `json:"file"`
+	// Reason is UnreviewedTimeBudget or UnreviewedBlocked
+	Reason string `json:"reason"`
+	// Risk is the file's risk score, see riskScore
+	Risk int `json:"risk"`
+}
+
+// riskScore rates how much a file's change needs review: a point per 10
+// changed lines up to 500, plus points for protected paths and for paths
+// about authentication, secrets, payments or migrations.
+func (e *Engine) riskScore(f git.FileDiff) int {
+	risk := min(f.Additions+f.Deletions, riskMaxLines) / 10
+	if _, ok := e.protectedArea(f.Path); ok {
+		risk += riskProtected
+	}
+	p := strings.ToLower(f.Path)
+	for _, word := range sensitiveWords {
+		if strings.Contains(p, word) {
+			risk += riskSensitive
+			break
+		}
+	}
+	return risk
+}
+
+// prioritizeFiles orders the files for a time-boxed review: source code
+// first, then tests, configuration and documentation, as
+// tokenizer.PrioritizeFiles, and the riskiest first within each kind.
+func (e *Engine) prioritizeFiles(files []git.FileDiff) []git.FileDiff {
+	byRisk := make([]git.FileDiff, len(files))
+	copy(byRisk, files)
+	risk := make(map[string]int, len(files))
+	for _, f := range files {
+		risk[f.Path] = e.riskScore(f)
+	}
+	sort.SliceStable(byRisk, func(i, j int) bool {
+		return risk[byRisk[i].Path] > risk[byRisk[j].Path]
+	})
+
+	infos := make([]tokenizer.FileInfo, len(byRisk))
+	byPath := make(map[string]git.FileDiff, len(byRisk))
+	for i, f := range byRisk {
+		infos[i] = tokenizer.FileInfo{Path: f.Path, Language: f.Language, IsTest: testcheck.IsTestFile(f.Path)}
+		byPath[f.Path] = f
+	}
+	ordered := make([]git.FileDiff, 0, len(files))
+	for _, info := range tokenizer.PrioritizeFiles(infos) {
+		ordered = append(ordered, byPath[info.Path])
+	}
+	return ordered
+}
+
+// sortUnreviewed puts the riskiest unreviewed files first.
+func sortUnreviewed(files []UnreviewedFile) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Risk != files[j].Risk {
+			return files[i].Risk > files[j].Risk
+		}
+		return files[i].File < files[j].File
+	})
+}
+
+// countUnreviewed counts the files left unreviewed for a reason.
+func countUnreviewed(files []UnreviewedFile, reason string) int {
+	n := 0
+	for _, f := range files {
+		if f.Reason == reason {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,160 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestPrioritizeFiles(t *testing.T) {
+	e := NewEngine(config.DefaultConfig(), nil, nil, nil, nil)
+	files := []git.FileDiff{
+		{Path: "README.md", Additions: 900},
+		{Path: "util/strings.go", Additions: 20},
+		{Path: "auth/login_test.go", Additions: 40},
+		{Path: "config.yaml", Additions: 5},
+		{Path: "auth/login.go", Additions: 10},
+		{Path: "server/handler.go", Additions: 400},
+	}
+	var got []string
+	for _, f := range e.prioritizeFiles(files) {
+		got = append(got, f.Path)
+	}
+	want := "server/handler.go auth/login.go util/strings.go auth/login_test.go config.yaml README.md"
+	if strings.Join(got, " ") != want {
+		t.Errorf("prioritizeFiles() = %q, want %q", strings.Join(got, " "), want)
+	}
+	if risk := e.riskScore(git.FileDiff{Path: "auth/login.go", Additions: 2000}); risk != 75 {
+		t.Errorf("riskScore() = %d, want 75", risk)
+	}
+}
+
+func TestEngineTimeBudget(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+	cfg.Review.MaxConcurrency = 1
+	cfg.Review.TimeBudget = 200 * time.Millisecond
+	cfg.Cache.Enabled = false
+
+	lines := []git.Line{{Type: git.LineAddition, Content: "x := 1"}}
+	file := func(path string, added int) git.FileDiff {
+		return git.FileDiff{Path: path, Language: "go", Status: git.FileModified, Additions: added, Hunks: []git.Hunk{{Lines: lines}}}
+	}
+	repo := &MockRepository{StagedDiff: &git.Diff{Files: []git.FileDiff{
+		file("util/slow.go", 50), file("auth/login.go", 10), file("util/late.go", 1),
+	}}}
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			if strings.Contains(req.FilePath, "slow") {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return &providers.ReviewResponse{Score: 90}, nil
+		},
+	}
+
+	result, err := NewEngine(cfg, repo, provider, nil, nil).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].File != "auth/login.go" {
+		t.Errorf("reviewed = %+v, want auth/login.go only", result.Files)
+	}
+	var unreviewed []string
+	for _, u := range result.Unreviewed {
+		unreviewed = append(unreviewed, u.File+" "+u.Reason)
+	}
+	if want := "util/slow.go time_budget|util/late.go time_budget"; strings.Join(unreviewed, "|") != want {
+		t.Errorf("unreviewed = %q, want %q", strings.Join(unreviewed, "|"), want)
+	}
+	if q := result.Quality; q == nil || q.UnreviewedFiles != 2 || !q.Degraded {
+		t.Errorf("quality = %+v, want 2 unreviewed files, degraded", q)
+	}
+}
+
+func TestEngineCancelled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+	cfg.Review.MaxConcurrency = 4
+	cfg.Cache.Enabled = false
+
+	var files []git.FileDiff
+	for i := 0; i < 40; i++ {
+		files = append(files, git.FileDiff{Path: fmt.Sprintf("pkg/f%d.go", i), Language: "go", Status: git.FileModified, Additions: 1,
+			Hunks: []git.Hunk{{Lines: []git.Line{{Type: git.LineAddition, Content: "x := 1"}}}}})
+	}
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(20 * time.Millisecond):
+				return &providers.ReviewResponse{Score: 90}, nil
+			}
+		},
+	}
+
+	// Workers still picking up queued tasks after the cancellation must
+	// not crash the run
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		repo := &MockRepository{StagedDiff: &git.Diff{Files: files}}
+		result, err := NewEngine(cfg, repo, provider, nil, nil).Run(ctx)
+		cancel()
+		if err == nil && len(result.Files) == len(files) {
+			t.Errorf("Run() reviewed all %d files despite the cancellation", len(files))
+		}
+	}
+}
+
+func TestEngineSkipsBlockedFiles(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+	cfg.Review.MaxConcurrency = 1
+	cfg.Review.MaxChunkTokens = 50
+	cfg.Cache.Enabled = false
+
+	var big []git.Line
+	for i := 0; i < 200; i++ {
+		big = append(big, git.Line{Type: git.LineAddition, Content: "x := computeSomething(a, b, c) // value"})
+	}
+	repo := &MockRepository{StagedDiff: &git.Diff{Files: []git.FileDiff{
+		{Path: "exploit.go", Language: "go", Status: git.FileAdded, Additions: 1, Hunks: []git.Hunk{{Lines: []git.Line{{Type: git.LineAddition, Content: "payload()"}}}}},
+		{Path: "big.go", Language: "go", Status: git.FileModified, Additions: 200, Hunks: []git.Hunk{{Header: "@@ -1,0 +1,200 @@", Lines: big}}},
+	}}}
+	blocked := &providers.SafetyBlockError{Provider: "gemini", Reason: "SAFETY"}
+	bigCalls := 0
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			if req.FilePath == "exploit.go" {
+				return nil, blocked
+			}
+			if bigCalls++; bigCalls == 1 {
+				return nil, blocked
+			}
+			return &providers.ReviewResponse{Issues: []providers.Issue{{ID: "1"}}, Score: 80}, nil
+		},
+	}
+
+	result, err := NewEngine(cfg, repo, provider, nil, nil).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Unreviewed) != 1 || result.Unreviewed[0].File != "exploit.go" || result.Unreviewed[0].Reason != UnreviewedBlocked {
+		t.Errorf("unreviewed = %+v, want exploit.go blocked", result.Unreviewed)
+	}
+	if len(result.Files) != 1 || result.Files[0].Error != nil {
+		t.Fatalf("files = %+v, want big.go reviewed", result.Files)
+	}
+	resp := result.Files[0].Response
+	if resp.Score != 80 || len(resp.Issues) != bigCalls-1 || !strings.Contains(resp.Summary, "Chunk 1/") {
+		t.Errorf("big.go = %+v, want the other chunks reviewed and the blocked one noted", resp)
+	}
+}
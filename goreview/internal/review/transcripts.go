@@ -0,0 +1,32 @@
+package review
+
+import "github.com/JNZader/goreview/goreview/internal/providers"
+
+// TranscriptWriter saves the provider exchanges of a reviewed file. It
+// returns the ID the file's issues reference the transcript by. Files are
+// reviewed concurrently, so Write must be safe for concurrent use.
+type TranscriptWriter interface {
+	Write(file string, exchanges []providers.Exchange) (string, error)
+}
+
+// SaveTranscripts saves the prompt and raw answer of every provider request
+// through w, and tags the issues found with the transcript's ID. Cached
+// files made no request and get no transcript.
+func (e *Engine) SaveTranscripts(w TranscriptWriter) {
+	e.transcripts = w
+}
+
+// saveTranscript saves the exchanges behind a provider response.
+func (e *Engine) saveTranscript(file string, resp *providers.ReviewResponse) {
+	if e.transcripts == nil || len(resp.Exchanges) == 0 {
+		return
+	}
+	id, err := e.transcripts.Write(file, resp.Exchanges)
+	if err != nil {
+		e.log.Warn("Transcript of %s not saved: %v", file, err)
+		return
+	}
+	for i := range resp.Issues {
+		resp.Issues[i].Transcript = id
+	}
+}
@@ -0,0 +1,84 @@
+package review
+
+import (
+	"context"
+
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// TriageLookup returns the triage of issues by fingerprint, such as the
+// history store.
+type TriageLookup interface {
+	TriageStates(ctx context.Context, fingerprints []string) (map[string]history.TriageState, error)
+}
+
+// UseTriage makes the review recognize issues acknowledged or marked won't
+// fix in earlier reviews, and handle them per review.triage.recurrences.
+func (e *Engine) UseTriage(l TriageLookup) {
+	e.triage = l
+}
+
+// applyTriage reports, downgrades or suppresses the recurrences of triaged
+// issues. It runs after the protected path escalation, so the final severity
+// decides the handling.
+func (e *Engine) applyTriage(ctx context.Context, result *Result) {
+	if e.triage == nil {
+		return
+	}
+
+	var fingerprints []string
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			fingerprints = append(fingerprints, history.IssueFingerprint(f.File, string(issue.Type), issue.Message))
+		}
+	}
+	if len(fingerprints) == 0 {
+		return
+	}
+	states, err := e.triage.TriageStates(ctx, fingerprints)
+	if err != nil {
+		e.log.Warn("Triaged issues not recognized: %v", err)
+		return
+	}
+
+	suppressed := 0
+	for i := range result.Files {
+		f := &result.Files[i]
+		if f.Response == nil {
+			continue
+		}
+		kept := f.Response.Issues[:0]
+		for _, issue := range f.Response.Issues {
+			st, ok := states[history.IssueFingerprint(f.File, string(issue.Type), issue.Message)]
+			if !ok || (st.Status != history.StatusAcknowledged && st.Status != history.StatusWontfix) {
+				kept = append(kept, issue)
+				continue
+			}
+
+			note := "previously " + st.Status
+			switch e.cfg.Review.Triage.Recurrences.For(string(issue.Severity)) {
+			case "suppress":
+				suppressed++
+				continue
+			case "downgrade":
+				if issue.Severity != providers.SeverityInfo {
+					note += ", was " + string(issue.Severity)
+					issue.Severity = providers.SeverityInfo
+				}
+			}
+			issue.Triage = &providers.Triage{
+				IssueID: st.ID, Status: st.Status, Assignee: st.Assignee, Notes: st.Notes, Recurrence: note,
+			}
+			kept = append(kept, issue)
+		}
+		f.Response.Issues = kept
+	}
+	result.TotalIssues -= suppressed
+	if suppressed > 0 {
+		e.log.Info("Suppressed %d previously triaged issues", suppressed)
+	}
+}
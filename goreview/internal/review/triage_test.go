@@ -0,0 +1,59 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/history"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// triageStates is a TriageLookup over fixed states.
+type triageStates map[string]history.TriageState
+
+func (s triageStates) TriageStates(_ context.Context, _ []string) (map[string]history.TriageState, error) {
+	return s, nil
+}
+
+func TestApplyTriage(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Triage.Recurrences = config.RecurrenceConfig{Critical: "report", Warning: "suppress"}
+	engine := NewEngine(cfg, nil, nil, nil, nil)
+
+	issue := func(severity providers.Severity, msg string) providers.Issue {
+		return providers.Issue{Type: providers.IssueTypeBug, Severity: severity, Message: msg}
+	}
+	fp := func(msg string) string { return history.IssueFingerprint("a.go", "bug", msg) }
+	engine.UseTriage(triageStates{
+		fp("critical wontfix"): {ID: 1, Status: history.StatusWontfix},
+		fp("error acked"):      {ID: 2, Status: history.StatusAcknowledged, Assignee: "alice"},
+		fp("warning acked"):    {ID: 3, Status: history.StatusAcknowledged},
+		fp("error open"):       {ID: 4, Status: history.StatusOpen},
+	})
+
+	result := &Result{TotalIssues: 5, Files: []FileResult{{File: "a.go", Response: &providers.ReviewResponse{Issues: []providers.Issue{
+		issue(providers.SeverityCritical, "critical wontfix"),
+		issue(providers.SeverityError, "error acked"),
+		issue(providers.SeverityWarning, "warning acked"),
+		issue(providers.SeverityError, "error open"),
+		issue(providers.SeverityError, "new"),
+	}}}}}
+	engine.applyTriage(context.Background(), result)
+
+	got := result.Files[0].Response.Issues
+	if len(got) != 4 || result.TotalIssues != 4 {
+		t.Fatalf("issues = %+v, total %d; want the acknowledged warning suppressed", got, result.TotalIssues)
+	}
+	if got[0].Severity != providers.SeverityCritical || got[0].Triage == nil || got[0].Triage.Recurrence != "previously wontfix" {
+		t.Errorf("reported recurrence = %+v, triage %+v", got[0], got[0].Triage)
+	}
+	if got[1].Severity != providers.SeverityInfo || got[1].Triage.Recurrence != "previously acknowledged, was error" || got[1].Triage.Assignee != "alice" {
+		t.Errorf("downgraded recurrence = %+v, triage %+v", got[1], got[1].Triage)
+	}
+	for _, i := range []int{2, 3} {
+		if got[i].Severity != providers.SeverityError || got[i].Triage != nil {
+			t.Errorf("untriaged issue %q = %+v", got[i].Message, got[i])
+		}
+	}
+}
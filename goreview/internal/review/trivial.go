@@ -0,0 +1,41 @@
+package review
+
+import (
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/rules"
+	"github.com/JNZader/goreview/goreview/internal/trivial"
+)
+
+// Trivial tells why a file was auto-approved instead of reviewed.
+type Trivial struct {
+	// Kind is "comments", "formatting" or "version_bump"
+	Kind string `json:"kind"`
+	// Detail describes the change, like "comment-only change"
+	Detail string `json:"detail"`
+}
+
+// approveTrivial returns the result of a file whose change is trivial,
+// approved without calling the provider, or nil when the file needs a
+// review.
+func (e *Engine) approveTrivial(file git.FileDiff, inScope []rules.Rule) *FileResult {
+	cfg := e.cfg.Review.Trivial
+	if !cfg.Enabled {
+		return nil
+	}
+	change := trivial.Classify(file, cfg.Kinds)
+	if change == nil {
+		return nil
+	}
+	e.log.Debug("Auto-approved %s: %s", file.Path, change.Detail)
+	return &FileResult{
+		File: file.Path,
+		Response: &providers.ReviewResponse{
+			Issues:  []providers.Issue{},
+			Summary: "Auto-approved (trivial): " + change.Detail + ".",
+			Score:   100,
+		},
+		RulesInScope: ruleIDs(inScope),
+		Trivial:      &Trivial{Kind: change.Kind, Detail: change.Detail},
+	}
+}
@@ -0,0 +1,76 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+func TestEngineTrivial(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Review.Mode = "staged"
+	cfg.Git.IgnorePatterns = nil
+	cfg.Review.Trivial.Enabled = true
+	cfg.Review.Debt.Enabled = true
+
+	comment := []git.Line{
+		{Type: git.LineDeletion, Content: "\treturn nil // done", OldNumber: 3},
+		{Type: git.LineAddition, Content: "\treturn nil // TODO: wrap the error", NewNumber: 3},
+	}
+	code := []git.Line{
+		{Type: git.LineDeletion, Content: "\treturn nil", OldNumber: 3},
+		{Type: git.LineAddition, Content: "\treturn err", NewNumber: 3},
+	}
+	repo := &MockRepository{
+		StagedDiff: &git.Diff{
+			Files: []git.FileDiff{
+				{Path: "a.go", Language: "go", Status: git.FileModified, Additions: 1, Deletions: 1, Hunks: []git.Hunk{{Lines: comment}}},
+				{Path: "b.go", Language: "go", Status: git.FileModified, Additions: 1, Deletions: 1, Hunks: []git.Hunk{{Lines: code}}},
+			},
+		},
+	}
+	var reviewed []string
+	provider := &MockProvider{
+		ReviewFunc: func(ctx context.Context, req *providers.ReviewRequest) (*providers.ReviewResponse, error) {
+			reviewed = append(reviewed, req.FilePath)
+			return &providers.ReviewResponse{Score: 90}, nil
+		},
+	}
+
+	result, err := NewEngine(cfg, repo, provider, nil, nil).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(reviewed) != 1 || reviewed[0] != "b.go" {
+		t.Errorf("reviewed = %v, want only b.go", reviewed)
+	}
+	for _, f := range result.Files {
+		switch f.File {
+		case "a.go":
+			if f.Trivial == nil || f.Trivial.Kind != "comments" || f.Response == nil {
+				t.Errorf("a.go = %+v, want auto-approved comment change", f)
+			}
+			// Local checks still run on trivial changes
+			if len(f.Debt) != 1 || len(f.Response.Issues) != 1 {
+				t.Errorf("a.go debt = %v, issues = %v, want the untracked TODO", f.Debt, f.Response.Issues)
+			}
+		case "b.go":
+			if f.Trivial != nil {
+				t.Errorf("b.go auto-approved as %+v", f.Trivial)
+			}
+		}
+	}
+
+	// Disabled, every file is reviewed
+	cfg.Review.Trivial.Enabled = false
+	reviewed = nil
+	if _, err := NewEngine(cfg, repo, provider, nil, nil).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(reviewed) != 2 {
+		t.Errorf("reviewed = %v, want both files", reviewed)
+	}
+}
@@ -0,0 +1,302 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScaffoldOptions configures a new rule scaffold.
+type ScaffoldOptions struct {
+	ID        string
+	Name      string
+	Category  Category
+	Severity  Severity
+	Languages []string
+}
+
+// ruleTemplate is the YAML written by "goreview rules new"
+var ruleTemplate = template.Must(template.New("rule").Parse(`name: {{.Name}}
+description: Custom rule pack
+
+rules:
+  - id: {{.ID}}
+    name: {{.Name}}
+    description: Describe what this rule detects and why it matters.
+    category: {{.Category}}   # security, performance, best_practice, style, bug, maintenance
+    severity: {{.Severity}}   # info, warning, error, critical
+    enabled: true
+
+    # Prompt guidance sent to the reviewer when the rule is in scope.
+    # Be specific: what to flag, what NOT to flag, and what a fix looks like.
+    prompt: |
+      Flag ... because ...
+      Do not flag ... (common false positive).
+
+    message: Short message shown with each finding.
+    suggestion: How to fix it.
+
+    # Matchers: limit where the rule applies.
+    languages: [{{.LanguageList}}]
+    patterns: []          # path globs, e.g. ["internal/**/*.go"]
+    exclude_patterns: []  # e.g. ["*_test.go"]
+    # when:
+    #   imports: ["database/sql"]        # only in files importing any of these
+    #   contains: ['"SELECT .*" \+']     # only in files matching any of these regexps
+`))
+
+// testTemplate is the fixture spec written alongside a new rule
+var testTemplate = template.Must(template.New("test").Parse(`# Fixtures for {{.ID}}. Run with: goreview rules test {{.SpecFile}}
+rules: {{.RulesFile}}
+
+cases:
+  - name: flags the violation
+    rule: {{.ID}}
+    file: testdata/{{.FixtureBase}}_bad{{.FixtureExt}}
+    expect:
+      in_scope: true
+      min_findings: 1
+      # lines: [3]
+      # message_contains: ["..."]
+
+  - name: accepts compliant code
+    rule: {{.ID}}
+    file: testdata/{{.FixtureBase}}_good{{.FixtureExt}}
+    expect:
+      max_findings: 0
+`))
+
+// ScaffoldRule renders a commented rule YAML for authors to fill in.
+func ScaffoldRule(opts ScaffoldOptions) ([]byte, error) {
+	if opts.ID == "" {
+		return nil, fmt.Errorf("rule id is required")
+	}
+	if opts.Name == "" {
+		opts.Name = opts.ID
+	}
+	if opts.Category == "" {
+		opts.Category = CategoryBestPractice
+	}
+	if opts.Severity == "" {
+		opts.Severity = SeverityWarning
+	}
+
+	var buf bytes.Buffer
+	err := ruleTemplate.Execute(&buf, struct {
+		ScaffoldOptions
+		LanguageList string
+	}{opts, strings.Join(opts.Languages, ", ")})
+	return buf.Bytes(), err
+}
+
+// ScaffoldTest renders a fixture spec for a rule file. Fixtures are named
+// testdata/<id>_bad<ext> and testdata/<id>_good<ext>.
+func ScaffoldTest(id, rulesFile, specFile, fixtureExt string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := testTemplate.Execute(&buf, map[string]string{
+		"ID":          id,
+		"RulesFile":   rulesFile,
+		"SpecFile":    specFile,
+		"FixtureBase": FixtureBase(id),
+		"FixtureExt":  fixtureExt,
+	})
+	return buf.Bytes(), err
+}
+
+// FixtureBase returns the fixture file prefix for a rule ID.
+func FixtureBase(id string) string {
+	return strings.ToLower(id)
+}
+
+// TestSpec describes fixtures and expected findings for one or more rules.
+type TestSpec struct {
+	// Rules is the rules YAML under test, relative to the spec file
+	Rules string     `yaml:"rules"`
+	Cases []TestCase `yaml:"cases"`
+}
+
+// TestCase runs a single rule against a fixture file.
+type TestCase struct {
+	Name   string      `yaml:"name"`
+	Rule   string      `yaml:"rule"`
+	File   string      `yaml:"file"`
+	Path   string      `yaml:"path"` // Path used for scope matching (defaults to File)
+	Expect Expectation `yaml:"expect"`
+}
+
+// Expectation lists the assertions for a test case. Unset fields are not checked.
+type Expectation struct {
+	InScope         *bool    `yaml:"in_scope"`
+	MinFindings     *int     `yaml:"min_findings"`
+	MaxFindings     *int     `yaml:"max_findings"`
+	Lines           []int    `yaml:"lines"`
+	MessageContains []string `yaml:"message_contains"`
+}
+
+// Finding is a rule violation reported for a fixture.
+type Finding struct {
+	StartLine int
+	EndLine   int
+	Message   string
+}
+
+// ReviewFunc reviews a fixture file against a single rule.
+type ReviewFunc func(ctx context.Context, file *FileContext, rule Rule) ([]Finding, error)
+
+// CaseResult is the outcome of a test case.
+type CaseResult struct {
+	Name     string
+	Rule     string
+	File     string
+	Skipped  bool // Review not run (scope-only mode or rule out of scope)
+	Failures []string
+}
+
+// Passed reports whether all assertions held.
+func (r CaseResult) Passed() bool { return len(r.Failures) == 0 }
+
+// LoadTestSpec reads a fixture spec and the rules it references.
+func LoadTestSpec(path string) (*TestSpec, []Rule, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - spec path given by the user
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var spec TestSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if spec.Rules == "" {
+		return nil, nil, fmt.Errorf("%s: \"rules\" is required", path)
+	}
+
+	base := filepath.Dir(path)
+	rulesData, err := os.ReadFile(filepath.Join(base, spec.Rules)) // #nosec G304 - relative to the spec
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading rules: %w", err)
+	}
+	rules, err := parseRulesYAML(rulesData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", spec.Rules, err)
+	}
+
+	for i := range spec.Cases {
+		spec.Cases[i].File = filepath.Join(base, spec.Cases[i].File)
+	}
+	return &spec, rules, nil
+}
+
+// RunTests evaluates every case. Scope is always checked; review is nil in
+// scope-only mode, in which case finding assertions are skipped.
+func RunTests(ctx context.Context, spec *TestSpec, rules []Rule, detect func(path, content string) string, review ReviewFunc) []CaseResult {
+	byID := make(map[string]Rule, len(rules))
+	for _, r := range rules {
+		byID[r.ID] = r
+	}
+
+	results := make([]CaseResult, 0, len(spec.Cases))
+	for _, tc := range spec.Cases {
+		results = append(results, runTestCase(ctx, tc, rules, byID, detect, review))
+	}
+	return results
+}
+
+func runTestCase(ctx context.Context, tc TestCase, rules []Rule, byID map[string]Rule, detect func(path, content string) string, review ReviewFunc) CaseResult {
+	res := CaseResult{Name: tc.Name, Rule: tc.Rule, File: tc.File}
+
+	rule, ok := byID[tc.Rule]
+	if !ok && tc.Rule == "" && len(rules) == 1 {
+		rule, ok = rules[0], true
+		res.Rule = rule.ID
+	}
+	if !ok {
+		res.Failures = append(res.Failures, fmt.Sprintf("rule %q not found", tc.Rule))
+		return res
+	}
+
+	data, err := os.ReadFile(tc.File) // #nosec G304 - fixture path from the spec
+	if err != nil {
+		res.Failures = append(res.Failures, fmt.Sprintf("reading fixture: %v", err))
+		return res
+	}
+	content := string(data)
+
+	scopePath := tc.Path
+	if scopePath == "" {
+		scopePath = tc.File
+	}
+	file := &FileContext{Path: scopePath, Language: detect(scopePath, content), Content: content}
+	rule.Enabled = true // Test the rule even if it ships disabled
+	inScope := len(Select([]Rule{rule}, file)) == 1
+
+	wantScope := tc.Expect.InScope == nil || *tc.Expect.InScope
+	if inScope != wantScope {
+		res.Failures = append(res.Failures, fmt.Sprintf("in scope = %v, want %v (language %s)", inScope, wantScope, file.Language))
+		return res
+	}
+	if !inScope || review == nil {
+		res.Skipped = true
+		return res
+	}
+
+	findings, err := review(ctx, file, rule)
+	if err != nil {
+		res.Failures = append(res.Failures, fmt.Sprintf("review failed: %v", err))
+		return res
+	}
+	res.Failures = append(res.Failures, checkFindings(tc.Expect, findings)...)
+	return res
+}
+
+// checkFindings applies the finding assertions of an expectation.
+func checkFindings(expect Expectation, findings []Finding) []string {
+	var failures []string
+
+	if expect.MinFindings != nil && len(findings) < *expect.MinFindings {
+		failures = append(failures, fmt.Sprintf("got %d findings, want at least %d", len(findings), *expect.MinFindings))
+	}
+	if expect.MaxFindings != nil && len(findings) > *expect.MaxFindings {
+		failures = append(failures, fmt.Sprintf("got %d findings, want at most %d", len(findings), *expect.MaxFindings))
+	}
+
+	for _, line := range expect.Lines {
+		if !findingCoversLine(findings, line) {
+			failures = append(failures, fmt.Sprintf("no finding on line %d", line))
+		}
+	}
+
+	for _, want := range expect.MessageContains {
+		if !findingMentions(findings, want) {
+			failures = append(failures, fmt.Sprintf("no finding message contains %q", want))
+		}
+	}
+	return failures
+}
+
+func findingCoversLine(findings []Finding, line int) bool {
+	for _, f := range findings {
+		end := f.EndLine
+		if end < f.StartLine {
+			end = f.StartLine
+		}
+		if line >= f.StartLine && line <= end {
+			return true
+		}
+	}
+	return false
+}
+
+func findingMentions(findings []Finding, text string) bool {
+	for _, f := range findings {
+		if strings.Contains(strings.ToLower(f.Message), strings.ToLower(text)) {
+			return true
+		}
+	}
+	return false
+}
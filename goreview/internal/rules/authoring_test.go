@@ -0,0 +1,105 @@
+package rules
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScaffoldRuleIsValidYAML(t *testing.T) {
+	data, err := ScaffoldRule(ScaffoldOptions{ID: "DB-001", Name: "No raw SQL", Languages: []string{"go"}})
+	if err != nil {
+		t.Fatalf("ScaffoldRule() error = %v", err)
+	}
+
+	rules, err := parseRulesYAML(data)
+	if err != nil {
+		t.Fatalf("scaffold is not valid YAML: %v\n%s", err, data)
+	}
+	if len(rules) != 1 {
+		t.Fatalf("len(rules) = %d, want 1", len(rules))
+	}
+	r := rules[0]
+	if r.ID != "DB-001" || r.Severity != SeverityWarning || r.Prompt == "" || len(r.Languages) != 1 {
+		t.Errorf("unexpected scaffolded rule: %+v", r)
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("db.yaml", `rules:
+  - id: DB-001
+    name: No raw SQL
+    languages: [go]
+    when:
+      imports: ["database/sql"]
+`)
+	write("testdata/bad.go", "package db\n\nimport \"database/sql\"\n\nfunc q(db *sql.DB, id string) {\n\tdb.Query(\"SELECT * FROM t WHERE id=\" + id)\n}\n")
+	write("testdata/other.go", "package db\n\nfunc f() {}\n")
+	write("db.test.yaml", `rules: db.yaml
+cases:
+  - name: flags concatenation
+    file: testdata/bad.go
+    expect:
+      min_findings: 1
+      lines: [6]
+      message_contains: ["sql"]
+  - name: not in scope without import
+    rule: DB-001
+    file: testdata/other.go
+    expect:
+      in_scope: false
+  - name: wrong line
+    rule: DB-001
+    file: testdata/bad.go
+    expect:
+      lines: [2]
+`)
+
+	spec, rules, err := LoadTestSpec(filepath.Join(dir, "db.test.yaml"))
+	if err != nil {
+		t.Fatalf("LoadTestSpec() error = %v", err)
+	}
+
+	review := func(_ context.Context, file *FileContext, rule Rule) ([]Finding, error) {
+		if strings.Contains(file.Content, "\" + id") {
+			return []Finding{{StartLine: 6, EndLine: 6, Message: "Raw SQL concatenation"}}, nil
+		}
+		return nil, nil
+	}
+	detect := func(path, _ string) string { return "go" }
+
+	results := RunTests(context.Background(), spec, rules, detect, review)
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(results))
+	}
+	if !results[0].Passed() || results[0].Rule != "DB-001" {
+		t.Errorf("case 1 should pass with the only rule: %+v", results[0])
+	}
+	if !results[1].Passed() || !results[1].Skipped {
+		t.Errorf("case 2 should pass as out of scope: %+v", results[1])
+	}
+	if results[2].Passed() || !strings.Contains(results[2].Failures[0], "line 2") {
+		t.Errorf("case 3 should fail on line 2: %+v", results[2])
+	}
+
+	// Scope-only mode never calls the reviewer
+	for _, res := range RunTests(context.Background(), spec, rules, detect, nil) {
+		if !res.Passed() || !res.Skipped {
+			t.Errorf("scope-only %q = %+v, want skipped pass", res.Name, res)
+		}
+	}
+}
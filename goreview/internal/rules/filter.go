@@ -1,7 +1,6 @@
 package rules
 
 import (
-	"path/filepath"
 	"strings"
 )
 
@@ -23,6 +22,9 @@ func Filter(rules []Rule, language, filePath string) []Rule {
 		if len(rule.Patterns) > 0 && !matchesAnyPattern(rule.Patterns, filePath) {
 			continue
 		}
+		if len(rule.Excludes) > 0 && matchesAnyPattern(rule.Excludes, filePath) {
+			continue
+		}
 
 		filtered = append(filtered, rule)
 	}
@@ -98,11 +100,7 @@ func containsString(slice []string, s string) bool {
 
 func matchesAnyPattern(patterns []string, path string) bool {
 	for _, pattern := range patterns {
-		matched, err := filepath.Match(pattern, filepath.Base(path))
-		if err != nil {
-			continue // Skip invalid patterns
-		}
-		if matched {
+		if matchGlob(pattern, path) {
 			return true
 		}
 	}
@@ -215,6 +215,12 @@ func applyRuleOverride(rule Rule, overrides map[string]interface{}) Rule {
 			rule.Patterns[i] = fmt.Sprintf("%v", p)
 		}
 	}
+	if excludes, ok := overrides["exclude_patterns"].([]interface{}); ok {
+		rule.Excludes = make([]string, len(excludes))
+		for i, p := range excludes {
+			rule.Excludes[i] = fmt.Sprintf("%v", p)
+		}
+	}
 	return rule
 }
 
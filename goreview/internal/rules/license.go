@@ -0,0 +1,209 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LicenseHeader turns a rule into a deterministic license header check:
+// files in the rule's scope must start with Text, written in the comment
+// style of their language. "{year}" in Text is rendered as the current year
+// and matches any year or year range in existing headers.
+type LicenseHeader struct {
+	Text string `yaml:"text" json:"text"`
+}
+
+// yearPlaceholder is replaced with the year in license header texts
+const yearPlaceholder = "{year}"
+
+// commentStyle describes how a language writes a header comment. Languages
+// with line comments set Line; the others use a Start/End block whose inner
+// lines begin with Prefix.
+type commentStyle struct {
+	Line   string
+	Start  string
+	Prefix string
+	End    string
+}
+
+var commentStyles = map[string]commentStyle{
+	"go": {Line: "//"}, "java": {Line: "//"}, "javascript": {Line: "//"}, "typescript": {Line: "//"},
+	"c": {Line: "//"}, "cpp": {Line: "//"}, "csharp": {Line: "//"}, "rust": {Line: "//"},
+	"kotlin": {Line: "//"}, "swift": {Line: "//"}, "scala": {Line: "//"}, "php": {Line: "//"},
+	"groovy": {Line: "//"}, "scss": {Line: "//"}, "objectivec": {Line: "//"}, "dart": {Line: "//"},
+
+	"python": {Line: "#"}, "ruby": {Line: "#"}, "shell": {Line: "#"}, "yaml": {Line: "#"},
+	"perl": {Line: "#"}, "makefile": {Line: "#"}, "dockerfile": {Line: "#"}, "r": {Line: "#"},
+	"toml": {Line: "#"}, "elixir": {Line: "#"},
+
+	"sql": {Line: "--"}, "lua": {Line: "--"}, "haskell": {Line: "--"},
+
+	"css":      {Start: "/*", Prefix: " * ", End: " */"},
+	"html":     {Start: "<!--", Prefix: "  ", End: "-->"},
+	"xml":      {Start: "<!--", Prefix: "  ", End: "-->"},
+	"markdown": {Start: "<!--", Prefix: "  ", End: "-->"},
+}
+
+// licenseKeywords identify an existing comment block as a license header
+var licenseKeywords = regexp.MustCompile(`(?i)copyright|license|spdx-license-identifier|\(c\)`)
+
+// HeaderFinding describes a missing or outdated license header, with the
+// lines to replace and their replacement.
+type HeaderFinding struct {
+	// Missing is true when the file has no license header at all
+	Missing bool
+	// StartLine and EndLine are the 1-based lines to replace with Fixed
+	StartLine int
+	EndLine   int
+	// Existing is the current content of those lines
+	Existing string
+	// Fixed is the content that replaces them
+	Fixed string
+}
+
+// LineCommentMarker returns the line comment marker of a language, such as
+// "//" or "#", or "" when it is unknown or only has block comments.
+func LineCommentMarker(language string) string {
+	return commentStyles[language].Line
+}
+
+// Supports reports whether headers can be written for the language.
+func (h *LicenseHeader) Supports(language string) bool {
+	_, ok := commentStyles[language]
+	return ok
+}
+
+// Render returns the header as a comment in the language's style.
+func (h *LicenseHeader) Render(language string, year int) string {
+	style := commentStyles[language]
+	text := strings.ReplaceAll(strings.TrimSpace(h.Text), yearPlaceholder, fmt.Sprint(year))
+	lines := strings.Split(text, "\n")
+
+	var out []string
+	if style.Line == "" {
+		out = append(out, style.Start)
+	}
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		switch {
+		case style.Line != "" && line == "":
+			out = append(out, style.Line)
+		case style.Line != "":
+			out = append(out, style.Line+" "+line)
+		case line == "":
+			out = append(out, strings.TrimRight(style.Prefix, " "))
+		default:
+			out = append(out, style.Prefix+line)
+		}
+	}
+	if style.Line == "" {
+		out = append(out, style.End)
+	}
+	return strings.Join(out, "\n")
+}
+
+// Check verifies the header of a file. It returns nil when the header is
+// present and current, or when the language has no known comment style.
+func (h *LicenseHeader) Check(content, language string, year int) *HeaderFinding {
+	style, ok := commentStyles[language]
+	if !ok {
+		return nil
+	}
+	lines := strings.Split(content, "\n")
+	start := preambleLines(lines)
+	end := commentBlockEnd(lines, start, style)
+
+	if end > start {
+		block := lines[start:end]
+		if h.pattern().MatchString(commentText(block, style)) {
+			return nil
+		}
+		if licenseKeywords.MatchString(strings.Join(block, "\n")) {
+			return &HeaderFinding{
+				StartLine: start + 1,
+				EndLine:   end,
+				Existing:  strings.Join(block, "\n"),
+				Fixed:     h.Render(language, year),
+			}
+		}
+	}
+
+	// Insert before the first line after the preamble
+	finding := &HeaderFinding{Missing: true, StartLine: start + 1, EndLine: start + 1, Fixed: h.Render(language, year)}
+	if start < len(lines) {
+		finding.Existing = lines[start]
+		if strings.TrimSpace(lines[start]) != "" {
+			finding.Fixed += "\n"
+		}
+		finding.Fixed += "\n" + lines[start]
+	}
+	return finding
+}
+
+// pattern matches the header text at the start of a comment block.
+func (h *LicenseHeader) pattern() *regexp.Regexp {
+	var parts []string
+	for _, line := range strings.Split(strings.TrimSpace(h.Text), "\n") {
+		parts = append(parts, strings.TrimSpace(line))
+	}
+	quoted := regexp.QuoteMeta(strings.Join(parts, "\n"))
+	year := `\d{4}(?:\s*[-,]\s*\d{4})*`
+	return regexp.MustCompile("^" + strings.ReplaceAll(quoted, regexp.QuoteMeta(yearPlaceholder), year) + `(?:\n|$)`)
+}
+
+// preambleLines counts the lines that must stay above a header: shebangs
+// and XML/PHP openers.
+func preambleLines(lines []string) int {
+	n := 0
+	for n < len(lines) {
+		line := strings.TrimSpace(lines[n])
+		if !strings.HasPrefix(line, "#!") && !strings.HasPrefix(line, "<?xml") && !strings.HasPrefix(line, "<?php") {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// commentBlockEnd returns the index after the comment block starting at
+// lines[start], or start when there is none.
+func commentBlockEnd(lines []string, start int, style commentStyle) int {
+	i := start
+	if style.Line != "" {
+		for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), style.Line) {
+			i++
+		}
+		return i
+	}
+
+	if i >= len(lines) || !strings.HasPrefix(strings.TrimSpace(lines[i]), style.Start) {
+		return start
+	}
+	end := strings.TrimSpace(style.End)
+	for ; i < len(lines); i++ {
+		if strings.Contains(lines[i], end) {
+			return i + 1
+		}
+	}
+	return start // Unterminated comment
+}
+
+// commentText strips the comment markers from a comment block.
+func commentText(block []string, style commentStyle) string {
+	markers := []string{style.Line, strings.TrimSpace(style.Start), strings.TrimSpace(style.End), strings.TrimSpace(style.Prefix)}
+	var text []string
+	for _, line := range block {
+		line = strings.TrimSpace(line)
+		for _, m := range markers {
+			if m != "" {
+				line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(line, m), m))
+			}
+		}
+		if line == "" && len(text) == 0 {
+			continue // Opening line of a block comment
+		}
+		text = append(text, line)
+	}
+	return strings.Join(text, "\n")
+}
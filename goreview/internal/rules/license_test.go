@@ -0,0 +1,74 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLicenseHeaderRender(t *testing.T) {
+	h := &LicenseHeader{Text: "Copyright {year} Acme Inc.\n\nSPDX-License-Identifier: MIT"}
+
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{"go", "// Copyright 2025 Acme Inc.\n//\n// SPDX-License-Identifier: MIT"},
+		{"python", "# Copyright 2025 Acme Inc.\n#\n# SPDX-License-Identifier: MIT"},
+		{"sql", "-- Copyright 2025 Acme Inc.\n--\n-- SPDX-License-Identifier: MIT"},
+		{"css", "/*\n * Copyright 2025 Acme Inc.\n *\n * SPDX-License-Identifier: MIT\n */"},
+		{"html", "<!--\n  Copyright 2025 Acme Inc.\n\n  SPDX-License-Identifier: MIT\n-->"},
+	}
+	for _, tt := range tests {
+		if got := h.Render(tt.language, 2025); got != tt.want {
+			t.Errorf("Render(%s) =\n%s\nwant\n%s", tt.language, got, tt.want)
+		}
+	}
+}
+
+func TestLicenseHeaderCheck(t *testing.T) {
+	h := &LicenseHeader{Text: "Copyright {year} Acme Inc.\nSPDX-License-Identifier: MIT"}
+	header := "// Copyright 2025 Acme Inc.\n// SPDX-License-Identifier: MIT"
+
+	tests := []struct {
+		name      string
+		language  string
+		content   string
+		wantNil   bool
+		missing   bool
+		start     int
+		end       int
+		fixedHead string
+	}{
+		{name: "current header", language: "go", content: header + "\n\npackage main\n", wantNil: true},
+		{name: "older year range", language: "go", content: "// Copyright 2019-2023 Acme Inc.\n// SPDX-License-Identifier: MIT\npackage main\n", wantNil: true},
+		{name: "unsupported language", language: "json", content: "{}", wantNil: true},
+		{name: "missing", language: "go", content: "package main\n", missing: true, start: 1, end: 1, fixedHead: header + "\n\npackage main"},
+		{name: "missing with build tag", language: "go", content: "//go:build linux\n\npackage main\n", missing: true, start: 1, end: 1},
+		{name: "outdated", language: "go", content: "// Copyright 2020 Other Corp.\n// Licensed under GPL\n\npackage main\n", start: 1, end: 2, fixedHead: header},
+		{name: "after shebang", language: "python", content: "#!/usr/bin/env python\nimport os\n", missing: true, start: 2, end: 2},
+		{name: "block comment current", language: "css", content: "/*\n * Copyright 2024 Acme Inc.\n * SPDX-License-Identifier: MIT\n */\nbody {}\n", wantNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := h.Check(tt.content, tt.language, 2025)
+			if tt.wantNil {
+				if f != nil {
+					t.Fatalf("Check() = %+v, want nil", f)
+				}
+				return
+			}
+			if f == nil {
+				t.Fatal("Check() = nil, want a finding")
+			}
+			if f.Missing != tt.missing || f.StartLine != tt.start || f.EndLine != tt.end {
+				t.Errorf("Check() = missing %v lines %d-%d, want missing %v lines %d-%d", f.Missing, f.StartLine, f.EndLine, tt.missing, tt.start, tt.end)
+			}
+			if tt.fixedHead != "" && f.Fixed != tt.fixedHead {
+				t.Errorf("Fixed =\n%s\nwant\n%s", f.Fixed, tt.fixedHead)
+			}
+			if !strings.Contains(f.Fixed, "Copyright 2025 Acme Inc.") {
+				t.Errorf("Fixed lacks rendered header: %q", f.Fixed)
+			}
+		})
+	}
+}
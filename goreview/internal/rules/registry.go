@@ -0,0 +1,321 @@
+package rules
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Rule pack defaults
+const (
+	defaultPackPath    = "rules.yaml"
+	packRulesFile      = "rules.yaml"
+	packInfoFile       = "pack.json"
+	unpinnedVersion    = "latest"
+	checksumPrefix     = "sha256:"
+	maxPackSize        = 1 << 20 // 1MB, same limit as inherited sources
+	packVersionPattern = "{version}"
+)
+
+// ErrPackNotInstalled is returned when a pack hasn't been fetched yet.
+var ErrPackNotInstalled = errors.New("rule pack not installed")
+
+// packNameRegex restricts pack names to safe directory names
+var packNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// PackSpec describes a pinned remote rule pack.
+type PackSpec struct {
+	Name     string `yaml:"name" json:"name"`
+	Source   string `yaml:"source" json:"source"`
+	Version  string `yaml:"version" json:"version"`
+	Checksum string `yaml:"checksum,omitempty" json:"checksum,omitempty"`
+	Path     string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// PackInfo records an installed pack in the local cache.
+type PackInfo struct {
+	Name      string    `json:"name"`
+	Source    string    `json:"source"`
+	Version   string    `json:"version"`
+	Checksum  string    `json:"checksum"`
+	RuleCount int       `json:"rule_count"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Registry fetches rule packs and caches them under dir/<name>/<version>.
+type Registry struct {
+	dir        string
+	httpClient *http.Client
+	gitClone   func(ctx context.Context, repo, ref, dest string) error
+}
+
+// NewRegistry creates a rule pack registry rooted at dir.
+func NewRegistry(dir string) *Registry {
+	return &Registry{
+		dir: dir,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		gitClone: gitShallowClone,
+	}
+}
+
+// ValidatePackSpec checks a pack specification before fetching.
+func ValidatePackSpec(spec PackSpec) error {
+	if !packNameRegex.MatchString(spec.Name) {
+		return fmt.Errorf("invalid pack name %q", spec.Name)
+	}
+	if spec.Source == "" {
+		return fmt.Errorf("pack %s: source is required", spec.Name)
+	}
+	if strings.Contains(spec.Source, "http://") || (!isGitSource(spec.Source) && !strings.HasPrefix(spec.Source, "https://")) {
+		return fmt.Errorf("pack %s: source must use HTTPS or git: %s", spec.Name, spec.Source)
+	}
+	if spec.Version != "" && !packNameRegex.MatchString(spec.Version) {
+		return fmt.Errorf("pack %s: invalid version %q", spec.Name, spec.Version)
+	}
+	if spec.Checksum != "" && !strings.HasPrefix(spec.Checksum, checksumPrefix) {
+		return fmt.Errorf("pack %s: checksum must start with %q", spec.Name, checksumPrefix)
+	}
+	return nil
+}
+
+// Update fetches a pack, verifies its checksum and stores it in the cache.
+func (r *Registry) Update(ctx context.Context, spec PackSpec) (*PackInfo, error) {
+	if err := ValidatePackSpec(spec); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	var err error
+	if isGitSource(spec.Source) {
+		data, err = r.fetchGit(ctx, spec)
+	} else {
+		data, err = r.fetchHTTPS(ctx, spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching pack %s: %w", spec.Name, err)
+	}
+
+	checksum := computeChecksum(data)
+	if err := verifyChecksum(spec, checksum); err != nil {
+		return nil, err
+	}
+
+	rules, err := parseRulesYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing pack %s: %w", spec.Name, err)
+	}
+
+	info := &PackInfo{
+		Name:      spec.Name,
+		Source:    spec.Source,
+		Version:   packVersion(spec),
+		Checksum:  checksum,
+		RuleCount: len(rules),
+		FetchedAt: time.Now(),
+	}
+	if err := r.store(info, data); err != nil {
+		return nil, fmt.Errorf("caching pack %s: %w", spec.Name, err)
+	}
+	return info, nil
+}
+
+// Load returns the rules of an installed pack, tagged with the pack name.
+// The cached file is re-verified against the pinned checksum.
+func (r *Registry) Load(spec PackSpec) ([]Rule, error) {
+	if err := ValidatePackSpec(spec); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.packDir(spec.Name, packVersion(spec)), packRulesFile)) // #nosec G304 - name and version validated
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s@%s (run 'goreview rules update')", ErrPackNotInstalled, spec.Name, packVersion(spec))
+		}
+		return nil, err
+	}
+	if err := verifyChecksum(spec, computeChecksum(data)); err != nil {
+		return nil, err
+	}
+
+	rules, err := parseRulesYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing pack %s: %w", spec.Name, err)
+	}
+	for i := range rules {
+		rules[i].Pack = spec.Name
+	}
+	return rules, nil
+}
+
+// LoadAll loads every installed pack in order; later packs take precedence.
+// Packs that fail to load are reported as warnings and skipped.
+func (r *Registry) LoadAll(specs []PackSpec) ([]Rule, []error) {
+	var sets [][]Rule
+	var warnings []error
+	for _, spec := range specs {
+		rules, err := r.Load(spec)
+		if err != nil {
+			warnings = append(warnings, err)
+			continue
+		}
+		sets = append(sets, rules)
+	}
+	return MergeRuleSets(sets...), warnings
+}
+
+// Installed lists all packs present in the cache.
+func (r *Registry) Installed() ([]PackInfo, error) {
+	matches, err := filepath.Glob(filepath.Join(r.dir, "*", "*", packInfoFile))
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]PackInfo, 0, len(matches))
+	for _, path := range matches {
+		data, err := os.ReadFile(path) // #nosec G304 - path from cache directory glob
+		if err != nil {
+			continue
+		}
+		var info PackInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			continue
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Name != infos[j].Name {
+			return infos[i].Name < infos[j].Name
+		}
+		return infos[i].Version < infos[j].Version
+	})
+	return infos, nil
+}
+
+func (r *Registry) store(info *PackInfo, data []byte) error {
+	dir := r.packDir(info.Name, info.Version)
+	if err := os.MkdirAll(dir, 0750); err != nil { // #nosec G301
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, packRulesFile), data, 0600); err != nil {
+		return err
+	}
+
+	meta, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, packInfoFile), meta, 0600)
+}
+
+func (r *Registry) packDir(name, version string) string {
+	return filepath.Join(r.dir, name, version)
+}
+
+// fetchHTTPS downloads a pack file, substituting {version} in the URL.
+func (r *Registry) fetchHTTPS(ctx context.Context, spec PackSpec) ([]byte, error) {
+	url := strings.ReplaceAll(spec.Source, packVersionPattern, spec.Version)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "GoReview/1.0")
+	req.Header.Set("Accept", "application/yaml, text/yaml, application/x-yaml")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxPackSize))
+}
+
+// fetchGit shallow-clones the pinned ref and reads the pack file from it.
+func (r *Registry) fetchGit(ctx context.Context, spec PackSpec) ([]byte, error) {
+	tmp, err := os.MkdirTemp("", "goreview-pack-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	dest := filepath.Join(tmp, "repo")
+	if err := r.gitClone(ctx, strings.TrimPrefix(spec.Source, "git+"), spec.Version, dest); err != nil {
+		return nil, err
+	}
+
+	path := spec.Path
+	if path == "" {
+		path = defaultPackPath
+	}
+	full := filepath.Join(dest, filepath.Clean("/"+path))
+	info, err := os.Stat(full)
+	if err != nil {
+		return nil, fmt.Errorf("pack file %s: %w", path, err)
+	}
+	if info.Size() > maxPackSize {
+		return nil, fmt.Errorf("pack file %s exceeds %d bytes", path, maxPackSize)
+	}
+	return os.ReadFile(full) // #nosec G304 - path confined to the clone
+}
+
+// gitShallowClone clones a single ref of a repository.
+func gitShallowClone(ctx context.Context, repo, ref, dest string) error {
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, "--", repo, dest)
+
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 - repo and ref validated
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git clone: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func verifyChecksum(spec PackSpec, actual string) error {
+	if spec.Checksum == "" {
+		return nil
+	}
+	if !strings.EqualFold(spec.Checksum, actual) {
+		return fmt.Errorf("pack %s: checksum mismatch (expected %s, got %s)", spec.Name, spec.Checksum, actual)
+	}
+	return nil
+}
+
+func computeChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return checksumPrefix + hex.EncodeToString(sum[:])
+}
+
+func packVersion(spec PackSpec) string {
+	if spec.Version == "" {
+		return unpinnedVersion
+	}
+	return spec.Version
+}
+
+// isGitSource reports whether a source should be fetched with git.
+func isGitSource(source string) bool {
+	return strings.HasPrefix(source, "git+") || strings.HasPrefix(source, "git@") ||
+		strings.HasPrefix(source, "ssh://") || strings.HasSuffix(source, ".git")
+}
@@ -0,0 +1,156 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPackYAML = `name: Test Pack
+rules:
+  - id: PACK-001
+    name: No panics
+    category: bug
+    severity: error
+    enabled: true
+`
+
+func newTestRegistry(t *testing.T) (*Registry, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0.0/rules.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testPackYAML))
+	}))
+	t.Cleanup(server.Close)
+
+	reg := NewRegistry(t.TempDir())
+	reg.httpClient = server.Client()
+	return reg, server
+}
+
+func TestRegistryUpdateAndLoad(t *testing.T) {
+	reg, server := newTestRegistry(t)
+	spec := PackSpec{
+		Name:     "team",
+		Source:   server.URL + "/{version}/rules.yaml",
+		Version:  "v1.0.0",
+		Checksum: computeChecksum([]byte(testPackYAML)),
+	}
+
+	info, err := reg.Update(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if info.RuleCount != 1 || info.Version != "v1.0.0" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+
+	rules, err := reg.Load(spec)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(rules) != 1 || rules[0].ID != "PACK-001" || rules[0].Pack != "team" {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+
+	installed, err := reg.Installed()
+	if err != nil || len(installed) != 1 || installed[0].Name != "team" {
+		t.Errorf("Installed() = %+v, %v", installed, err)
+	}
+}
+
+func TestRegistryChecksumMismatch(t *testing.T) {
+	reg, server := newTestRegistry(t)
+	spec := PackSpec{
+		Name:     "team",
+		Source:   server.URL + "/{version}/rules.yaml",
+		Version:  "v1.0.0",
+		Checksum: "sha256:0000",
+	}
+
+	if _, err := reg.Update(context.Background(), spec); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Update() error = %v, want checksum mismatch", err)
+	}
+	if _, err := reg.Load(spec); !errors.Is(err, ErrPackNotInstalled) {
+		t.Errorf("Load() error = %v, want ErrPackNotInstalled", err)
+	}
+}
+
+func TestRegistryLoadDetectsTampering(t *testing.T) {
+	reg, server := newTestRegistry(t)
+	spec := PackSpec{Name: "team", Source: server.URL + "/{version}/rules.yaml", Version: "v1.0.0"}
+	info, err := reg.Update(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	path := filepath.Join(reg.packDir("team", "v1.0.0"), packRulesFile)
+	if err := os.WriteFile(path, []byte("rules: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	spec.Checksum = info.Checksum
+	if _, err := reg.Load(spec); err == nil {
+		t.Error("Load() should fail when the cached pack no longer matches its checksum")
+	}
+}
+
+func TestRegistryGitSource(t *testing.T) {
+	reg := NewRegistry(t.TempDir())
+	var gotRepo, gotRef string
+	reg.gitClone = func(_ context.Context, repo, ref, dest string) error {
+		gotRepo, gotRef = repo, ref
+		if err := os.MkdirAll(filepath.Join(dest, "packs"), 0750); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dest, "packs", "go.yaml"), []byte(testPackYAML), 0600)
+	}
+
+	spec := PackSpec{
+		Name:    "go",
+		Source:  "git+https://example.com/rules.git",
+		Version: "v2.1.0",
+		Path:    "packs/go.yaml",
+	}
+	if _, err := reg.Update(context.Background(), spec); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if gotRepo != "https://example.com/rules.git" || gotRef != "v2.1.0" {
+		t.Errorf("clone(%q, %q), want stripped repo URL and pinned ref", gotRepo, gotRef)
+	}
+	if rules, err := reg.Load(spec); err != nil || len(rules) != 1 {
+		t.Errorf("Load() = %v, %v", rules, err)
+	}
+}
+
+func TestValidatePackSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    PackSpec
+		wantErr bool
+	}{
+		{"https", PackSpec{Name: "a", Source: "https://x/rules.yaml", Version: "v1"}, false},
+		{"git", PackSpec{Name: "a", Source: "git@github.com:org/rules.git", Version: "v1"}, false},
+		{"http rejected", PackSpec{Name: "a", Source: "http://x/rules.yaml"}, true},
+		{"git over http rejected", PackSpec{Name: "a", Source: "git+http://x/rules.git"}, true},
+		{"local path rejected", PackSpec{Name: "a", Source: "./rules.yaml"}, true},
+		{"path traversal name", PackSpec{Name: "../a", Source: "https://x"}, true},
+		{"path traversal version", PackSpec{Name: "a", Source: "https://x", Version: "../v1"}, true},
+		{"bad checksum", PackSpec{Name: "a", Source: "https://x", Checksum: "md5:abc"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidatePackSpec(tt.spec); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePackSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
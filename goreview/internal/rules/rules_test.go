@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -81,3 +82,79 @@ func TestContainsString(t *testing.T) {
 		t.Error("Should not find 'rust'")
 	}
 }
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "internal/db/store.go", true},
+		{"*_test.go", "internal/db/store.go", false},
+		{"internal/**/*.go", "internal/db/store.go", true},
+		{"internal/**/*.go", "internal/store.go", true},
+		{"internal/**", "internal/db/sql/query.go", true},
+		{"cmd/*.go", "cmd/sub/main.go", false},
+		{"**/migrations/*.sql", "db/migrations/001.sql", true},
+		{"/vendor/**", "vendor/x/y.go", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	rules := []Rule{
+		{ID: "SQL", Languages: []string{"go"}, Enabled: true, When: &Condition{Imports: []string{"database/sql"}}},
+		{ID: "HTTP", Enabled: true, When: &Condition{Contains: []string{`http\.(Get|Post)\(`}}},
+		{ID: "INTERNAL", Enabled: true, Patterns: []string{"internal/**"}, Excludes: []string{"*_test.go"}},
+		{ID: "ALWAYS", Enabled: true},
+	}
+
+	sqlFile := `package store
+
+import (
+	"context"
+	"database/sql"
+)
+
+func Find(db *sql.DB) {}
+`
+	tests := []struct {
+		name string
+		file *FileContext
+		want []string
+	}{
+		{
+			name: "imports database/sql",
+			file: &FileContext{Path: "internal/store/find.go", Language: "go", Content: sqlFile},
+			want: []string{"SQL", "INTERNAL", "ALWAYS"},
+		},
+		{
+			name: "no sql import, test file excluded",
+			file: &FileContext{Path: "internal/api/client_test.go", Language: "go", Content: "package api\n\nfunc f() { http.Get(url) }\n"},
+			want: []string{"HTTP", "ALWAYS"},
+		},
+		{
+			name: "outside internal",
+			file: &FileContext{Path: "cmd/main.go", Language: "go", Content: "package main\n"},
+			want: []string{"ALWAYS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Select(rules, tt.file)
+			ids := make([]string, len(got))
+			for i, r := range got {
+				ids[i] = r.ID
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Select() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package rules
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/JNZader/goreview/goreview/internal/ast"
+)
+
+// Condition activates a rule only in files matching all of its non-empty
+// criteria, e.g. "no raw SQL concatenation" only where database/sql is imported.
+type Condition struct {
+	// Imports matches if the file imports any of these packages/modules
+	// (exact or as a parent, so "database/sql" also matches "database/sql/driver")
+	Imports []string `yaml:"imports" json:"imports,omitempty"`
+
+	// Contains matches if the file content matches any of these regexps
+	Contains []string `yaml:"contains" json:"contains,omitempty"`
+}
+
+// FileContext describes the file a rule is evaluated against.
+type FileContext struct {
+	Path     string
+	Language string
+	Content  string // Full file content when available, otherwise the changed code
+
+	imports     []string
+	importsOnce sync.Once
+}
+
+// Imports returns the modules imported by the file.
+func (f *FileContext) Imports() []string {
+	f.importsOnce.Do(func() {
+		if f.Content == "" {
+			return
+		}
+		ctx, err := ast.NewParser(f.Language).Parse(f.Content, f.Path)
+		if err != nil {
+			return
+		}
+		for _, imp := range ctx.Imports {
+			f.imports = append(f.imports, imp.Path)
+		}
+	})
+	return f.imports
+}
+
+// containsPatternCache avoids recompiling condition regexps for every file
+var containsPatternCache sync.Map
+
+// Select returns the rules in scope for a file: enabled, matching its
+// language and path globs, and satisfying any activation conditions.
+func Select(rules []Rule, file *FileContext) []Rule {
+	candidates := Filter(rules, file.Language, file.Path)
+	selected := candidates[:0]
+	for _, rule := range candidates {
+		if rule.When.Matches(file) {
+			selected = append(selected, rule)
+		}
+	}
+	return selected
+}
+
+// Matches reports whether a file satisfies the condition. A nil or empty
+// condition always matches.
+func (c *Condition) Matches(file *FileContext) bool {
+	if c == nil {
+		return true
+	}
+	if len(c.Imports) > 0 && !importsAny(file.Imports(), c.Imports) {
+		return false
+	}
+	if len(c.Contains) > 0 && !containsAny(file.Content, c.Contains) {
+		return false
+	}
+	return true
+}
+
+func importsAny(imports, wanted []string) bool {
+	for _, imp := range imports {
+		for _, want := range wanted {
+			if imp == want || strings.HasPrefix(imp, want+"/") || strings.HasPrefix(imp, want+".") || strings.HasPrefix(imp, want+"::") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsAny(content string, patterns []string) bool {
+	for _, p := range patterns {
+		re := compileContainsPattern(p)
+		if re == nil {
+			continue // Skip invalid patterns
+		}
+		if re.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileContainsPattern(pattern string) *regexp.Regexp {
+	if cached, ok := containsPatternCache.Load(pattern); ok {
+		re, _ := cached.(*regexp.Regexp)
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	containsPatternCache.Store(pattern, re)
+	return re
+}
+
+// MatchGlob reports whether a path matches a rule-style glob, for other
+// packages that accept the same patterns as rule paths.
+func MatchGlob(pattern, filePath string) bool {
+	return matchGlob(pattern, filePath)
+}
+
+// matchGlob matches a path against a glob. Patterns without a slash match
+// the file name only; patterns with a slash match the whole path and support
+// "**" for any number of directories.
+func matchGlob(pattern, filePath string) bool {
+	filePath = strings.TrimPrefix(strings.ReplaceAll(filePath, "\\", "/"), "./")
+	if !strings.Contains(pattern, "/") {
+		matched, err := path.Match(pattern, path.Base(filePath))
+		return err == nil && matched
+	}
+	return matchSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(filePath, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		matched, err := path.Match(pattern[0], parts[0])
+		if err != nil || !matched {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
@@ -2,16 +2,24 @@ package rules
 
 // Rule defines a code review rule.
 type Rule struct {
-	ID          string   `yaml:"id" json:"id"`
-	Name        string   `yaml:"name" json:"name"`
-	Description string   `yaml:"description" json:"description"`
-	Category    Category `yaml:"category" json:"category"`
-	Severity    Severity `yaml:"severity" json:"severity"`
-	Languages   []string `yaml:"languages" json:"languages"`
-	Patterns    []string `yaml:"patterns" json:"patterns"` // File patterns
-	Enabled     bool     `yaml:"enabled" json:"enabled"`
-	Message     string   `yaml:"message" json:"message"`
-	Suggestion  string   `yaml:"suggestion" json:"suggestion"`
+	ID          string     `yaml:"id" json:"id"`
+	Name        string     `yaml:"name" json:"name"`
+	Description string     `yaml:"description" json:"description"`
+	Category    Category   `yaml:"category" json:"category"`
+	Severity    Severity   `yaml:"severity" json:"severity"`
+	Languages   []string   `yaml:"languages" json:"languages"`
+	Patterns    []string   `yaml:"patterns" json:"patterns"` // File patterns
+	Excludes    []string   `yaml:"exclude_patterns" json:"exclude_patterns,omitempty"`
+	When        *Condition `yaml:"when" json:"when,omitempty"` // Conditional activation
+	Enabled     bool       `yaml:"enabled" json:"enabled"`
+	Message     string     `yaml:"message" json:"message"`
+	Suggestion  string     `yaml:"suggestion" json:"suggestion"`
+	Prompt      string     `yaml:"prompt" json:"prompt,omitempty"` // Guidance for the reviewer model
+	Pack        string     `yaml:"-" json:"pack,omitempty"`        // Remote pack the rule came from
+
+	// License makes the rule a deterministic license header check instead
+	// of guidance for the reviewer model
+	License *LicenseHeader `yaml:"license" json:"license,omitempty"`
 }
 
 // Category categorizes rules.
@@ -50,3 +58,15 @@ type Preset struct {
 	Includes    []string `yaml:"includes" json:"includes"` // Rule IDs
 	Excludes    []string `yaml:"excludes" json:"excludes"` // Rule IDs
 }
+
+// Guidance returns the instruction sent to the reviewer for this rule.
+func (r Rule) Guidance() string {
+	text := r.Prompt
+	if text == "" {
+		text = r.Description
+	}
+	if text == "" {
+		text = r.Message
+	}
+	return r.ID + " (" + r.Name + "): " + text
+}
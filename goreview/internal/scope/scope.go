@@ -0,0 +1,346 @@
+// Package scope checks that a change does one thing. It flags unrelated
+// changes bundled into it, formatting-only hunks and drive-by renames mixed
+// with logic changes, and files of categories unrelated to the main one,
+// and suggests how to split them out. The analysis only reads the diff.
+package scope
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/testcheck"
+)
+
+// Category is the kind of a changed file.
+type Category string
+
+// File categories
+const (
+	CategorySource       Category = "source"
+	CategoryTest         Category = "test"
+	CategoryDocs         Category = "docs"
+	CategoryConfig       Category = "config"
+	CategoryDependencies Category = "dependencies"
+	CategoryBuild        Category = "build"
+	CategoryCI           Category = "ci"
+)
+
+// Kinds of flagged hunks
+const (
+	HunkFormatting = "formatting"
+	HunkRename     = "rename"
+)
+
+// Report is the scope analysis of a change.
+type Report struct {
+	// Categories lists the changed file categories, the main one first
+	Categories []CategoryChange `json:"categories"`
+	// Unrelated counts the categories unrelated to the main one
+	Unrelated int `json:"unrelated"`
+	// Hunks lists the formatting-only and rename hunks mixed with logic
+	// changes
+	Hunks []Hunk `json:"hunks,omitempty"`
+	// Suggestions tell how to split the change
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// CategoryChange is the part of the change in one file category.
+type CategoryChange struct {
+	Category Category `json:"category"`
+	Files    []string `json:"files"`
+	// Lines counts the added and deleted lines
+	Lines     int  `json:"lines"`
+	Unrelated bool `json:"unrelated,omitempty"`
+}
+
+// Hunk is a hunk that doesn't belong with the logic changes.
+type Hunk struct {
+	File string `json:"file"`
+	// Line is the first line of the hunk in the new file
+	Line int `json:"line"`
+	// Kind is HunkFormatting or HunkRename
+	Kind string `json:"kind"`
+	// Rename is "old -> new" for HunkRename
+	Rename string `json:"rename,omitempty"`
+}
+
+// related lists the category pairs that usually change together
+var related = map[[2]Category]bool{
+	{CategorySource, CategoryTest}:         true,
+	{CategorySource, CategoryDocs}:         true,
+	{CategorySource, CategoryDependencies}: true,
+	{CategoryTest, CategoryDependencies}:   true,
+	{CategoryBuild, CategoryCI}:            true,
+	{CategoryBuild, CategoryDependencies}:  true,
+}
+
+func isRelated(a, b Category) bool {
+	return a == b || related[[2]Category{a, b}] || related[[2]Category{b, a}]
+}
+
+// Analyze returns the scope analysis of the changed files.
+//
+// The main category is source when source files changed, otherwise the
+// category with the most changed lines. Hunks are only classified in
+// source and test files, and formatting-only and rename hunks are only
+// flagged when other hunks change logic: a change that only reformats or
+// renames does one thing.
+func Analyze(files []git.FileDiff) *Report {
+	report := &Report{}
+	byCategory := make(map[Category]*CategoryChange)
+	var hunks []Hunk
+	logic := 0
+	for _, f := range files {
+		category := Classify(f.Path)
+		c := byCategory[category]
+		if c == nil {
+			c = &CategoryChange{Category: category}
+			byCategory[category] = c
+		}
+		c.Files = append(c.Files, f.Path)
+		c.Lines += f.Additions + f.Deletions
+
+		if category != CategorySource && category != CategoryTest {
+			continue
+		}
+		for _, h := range f.Hunks {
+			switch kind, rename := classifyHunk(h); kind {
+			case "":
+				logic++
+			default:
+				hunks = append(hunks, Hunk{File: f.Path, Line: h.NewStart, Kind: kind, Rename: rename})
+			}
+		}
+	}
+	if len(byCategory) == 0 {
+		return report
+	}
+
+	for _, c := range byCategory {
+		report.Categories = append(report.Categories, *c)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		a, b := report.Categories[i], report.Categories[j]
+		if (a.Category == CategorySource) != (b.Category == CategorySource) {
+			return a.Category == CategorySource
+		}
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		return a.Category < b.Category
+	})
+	primary := report.Categories[0].Category
+	for i := range report.Categories[1:] {
+		c := &report.Categories[i+1]
+		if !isRelated(primary, c.Category) {
+			c.Unrelated = true
+			report.Unrelated++
+		}
+	}
+
+	if logic > 0 {
+		report.Hunks = hunks
+	}
+	report.Suggestions = suggest(report)
+	return report
+}
+
+// suggest returns how to split the change.
+func suggest(report *Report) []string {
+	var suggestions []string
+	var formatting []Hunk
+	renames := make(map[string][]Hunk)
+	var renameOrder []string
+	for _, h := range report.Hunks {
+		if h.Kind == HunkFormatting {
+			formatting = append(formatting, h)
+			continue
+		}
+		if _, ok := renames[h.Rename]; !ok {
+			renameOrder = append(renameOrder, h.Rename)
+		}
+		renames[h.Rename] = append(renames[h.Rename], h)
+	}
+
+	if len(formatting) > 0 {
+		suggestions = append(suggestions, fmt.Sprintf(
+			"Move the %s (%s) to a separate formatting commit, or revert them",
+			plural(len(formatting), "formatting-only hunk"), strings.Join(hunkFiles(formatting), ", ")))
+	}
+	for _, rename := range renameOrder {
+		hs := renames[rename]
+		files := hunkFiles(hs)
+		suggestions = append(suggestions, fmt.Sprintf(
+			"Move the rename %s (%s in %s) to a separate refactoring change",
+			rename, plural(len(hs), "hunk"), plural(len(files), "file")))
+	}
+	for _, c := range report.Categories {
+		if c.Unrelated {
+			suggestions = append(suggestions, fmt.Sprintf(
+				"Split the %s changes (%s) into a separate change",
+				c.Category, strings.Join(c.Files, ", ")))
+		}
+	}
+	return suggestions
+}
+
+// hunkFiles returns the files of the hunks, without duplicates.
+func hunkFiles(hunks []Hunk) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, h := range hunks {
+		if !seen[h.File] {
+			seen[h.File] = true
+			files = append(files, h.File)
+		}
+	}
+	return files
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// dependencyFiles are package manifests and lockfiles
+var dependencyFiles = map[string]bool{
+	"go.mod": true, "go.sum": true, "go.work": true, "go.work.sum": true,
+	"package.json": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true, "bun.lockb": true,
+	"requirements.txt": true, "pipfile": true, "pipfile.lock": true, "poetry.lock": true, "pyproject.toml": true, "uv.lock": true,
+	"cargo.toml": true, "cargo.lock": true, "gemfile": true, "gemfile.lock": true,
+	"composer.json": true, "composer.lock": true, "pom.xml": true, "build.gradle": true, "build.gradle.kts": true,
+}
+
+// buildFiles are build and container definitions
+var buildFiles = map[string]bool{
+	"makefile": true, "dockerfile": true, "containerfile": true, "cmakelists.txt": true,
+	"docker-compose.yml": true, "docker-compose.yaml": true, "compose.yml": true, "compose.yaml": true,
+	"justfile": true, "taskfile.yml": true, "magefile.go": true, ".goreleaser.yml": true, ".goreleaser.yaml": true,
+}
+
+// ciFiles are CI pipeline definitions outside a CI directory
+var ciFiles = map[string]bool{
+	".gitlab-ci.yml": true, ".travis.yml": true, "jenkinsfile": true, "azure-pipelines.yml": true,
+	"bitbucket-pipelines.yml": true, "appveyor.yml": true, ".drone.yml": true,
+}
+
+var ciDirs = []string{".github/workflows/", ".circleci/", ".buildkite/", ".gitlab/ci/"}
+
+var docExtensions = map[string]bool{".md": true, ".mdx": true, ".rst": true, ".adoc": true, ".txt": true}
+
+var docFiles = map[string]bool{"license": true, "notice": true, "authors": true, "contributors": true, "codeowners": true}
+
+var configExtensions = map[string]bool{
+	".yml": true, ".yaml": true, ".json": true, ".toml": true, ".ini": true, ".cfg": true,
+	".conf": true, ".properties": true, ".env": true, ".xml": true,
+}
+
+// Classify returns the category of a file from its path.
+func Classify(file string) Category {
+	p := strings.ToLower(path.Clean(strings.ReplaceAll(file, "\\", "/")))
+	base := path.Base(p)
+	ext := path.Ext(base)
+	switch {
+	case dependencyFiles[base], strings.HasPrefix(base, "requirements") && ext == ".txt":
+		return CategoryDependencies
+	case ciFiles[base], hasAnyPrefix(p, ciDirs):
+		return CategoryCI
+	case buildFiles[base], strings.HasPrefix(base, "dockerfile."), ext == ".mk":
+		return CategoryBuild
+	case testcheck.IsTestFile(file):
+		return CategoryTest
+	case docExtensions[ext], docFiles[strings.TrimSuffix(base, ext)], strings.HasPrefix(p, "docs/"):
+		return CategoryDocs
+	case configExtensions[ext], strings.HasPrefix(base, ".") && ext == base:
+		return CategoryConfig
+	}
+	return CategorySource
+}
+
+func hasAnyPrefix(p string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(p, prefix) || strings.Contains(p, "/"+prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyHunk returns HunkFormatting when the hunk only changes whitespace
+// and line breaks, HunkRename with "old -> new" when it only replaces one
+// identifier with another, and "" when it changes logic.
+func classifyHunk(h git.Hunk) (kind, rename string) {
+	var deleted, added []string
+	for _, l := range h.Lines {
+		switch l.Type {
+		case git.LineDeletion:
+			deleted = append(deleted, l.Content)
+		case git.LineAddition:
+			added = append(added, l.Content)
+		}
+	}
+	if len(deleted) == 0 && len(added) == 0 {
+		return "", ""
+	}
+	if stripSpace(strings.Join(deleted, "")) == stripSpace(strings.Join(added, "")) {
+		return HunkFormatting, ""
+	}
+	if from, to, ok := renamed(deleted, added); ok {
+		return HunkRename, from + " -> " + to
+	}
+	return "", ""
+}
+
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// tokenPattern matches string literals whole, so that changing a string
+// isn't taken for a rename
+var tokenPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|` + "`[^`]*`" + `|[\p{L}_][\p{L}\p{N}_]*|\S`)
+
+// renamed reports whether the added lines are the deleted ones with a
+// single identifier consistently replaced by another.
+func renamed(deleted, added []string) (from, to string, ok bool) {
+	if len(deleted) != len(added) {
+		return "", "", false
+	}
+	for i := range deleted {
+		before := tokenPattern.FindAllString(deleted[i], -1)
+		after := tokenPattern.FindAllString(added[i], -1)
+		if len(before) != len(after) {
+			return "", "", false
+		}
+		for j := range before {
+			if before[j] == after[j] {
+				continue
+			}
+			if !isIdentifier(before[j]) || !isIdentifier(after[j]) {
+				return "", "", false
+			}
+			if from == "" {
+				from, to = before[j], after[j]
+			} else if before[j] != from || after[j] != to {
+				return "", "", false
+			}
+		}
+	}
+	return from, to, from != ""
+}
+
+func isIdentifier(token string) bool {
+	r := []rune(token)[0]
+	return unicode.IsLetter(r) || r == '_'
+}
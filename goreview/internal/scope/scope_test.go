@@ -0,0 +1,134 @@
+package scope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// hunk builds a hunk from diff lines prefixed with "-", "+" or " ".
+func hunk(start int, lines ...string) git.Hunk {
+	h := git.Hunk{OldStart: start, NewStart: start}
+	for _, l := range lines {
+		t := git.LineContext
+		switch l[0] {
+		case '-':
+			t = git.LineDeletion
+		case '+':
+			t = git.LineAddition
+		}
+		h.Lines = append(h.Lines, git.Line{Type: t, Content: l[1:]})
+	}
+	return h
+}
+
+func TestClassify(t *testing.T) {
+	tests := map[string]Category{
+		"internal/review/engine.go":      CategorySource,
+		"internal/review/engine_test.go": CategoryTest,
+		"README.md":                      CategoryDocs,
+		"docs/guide/setup.html":          CategoryDocs,
+		"LICENSE":                        CategoryDocs,
+		"config/app.yaml":                CategoryConfig,
+		".editorconfig":                  CategoryConfig,
+		"go.sum":                         CategoryDependencies,
+		"web/package-lock.json":          CategoryDependencies,
+		"requirements-dev.txt":           CategoryDependencies,
+		"Makefile":                       CategoryBuild,
+		"deploy/Dockerfile.prod":         CategoryBuild,
+		".github/workflows/ci.yml":       CategoryCI,
+		".gitlab-ci.yml":                 CategoryCI,
+	}
+	for file, want := range tests {
+		if got := Classify(file); got != want {
+			t.Errorf("Classify(%q) = %s, want %s", file, got, want)
+		}
+	}
+}
+
+func TestClassifyHunk(t *testing.T) {
+	tests := []struct {
+		name       string
+		hunk       git.Hunk
+		kind, from string
+	}{
+		{"reindent", hunk(1, "-if x {", "-  y()", "+if x {", "+\ty()"), HunkFormatting, ""},
+		{"reflow", hunk(1, "-call(a, b)", "+call(", "+\ta,", "+\tb,", "+)"), "", ""},
+		{"join", hunk(1, "-call(a,", "-\tb)", "+call(a, b)"), HunkFormatting, ""},
+		{"rename", hunk(1, " ctx := context.Background()", "-cfg := load(path)", "-use(cfg)", "+conf := load(path)", "+use(conf)"), HunkRename, "cfg -> conf"},
+		{"two renames", hunk(1, "-a := b", "+c := d"), "", ""},
+		{"logic", hunk(1, "-return x", "+return x + 1"), "", ""},
+		{"literal", hunk(1, `-log("start")`, `+log("stop")`), "", ""},
+		{"operator", hunk(1, "-x < y", "+x <= y"), "", ""},
+		{"context only", hunk(1, " x"), "", ""},
+	}
+	for _, tt := range tests {
+		kind, rename := classifyHunk(tt.hunk)
+		want := ""
+		if tt.kind == HunkRename {
+			want = tt.from
+		}
+		if kind != tt.kind || rename != want {
+			t.Errorf("%s: classifyHunk = %q, %q, want %q, %q", tt.name, kind, rename, tt.kind, want)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	files := []git.FileDiff{
+		{Path: "store/store.go", Additions: 20, Deletions: 6, Hunks: []git.Hunk{
+			hunk(10, "-return nil", "+return s.flush()"),
+			hunk(40, "-func a( x int ) {", "+func a(x int) {"),
+			hunk(60, "-cfg.Load()", "+conf.Load()"),
+		}},
+		{Path: "store/cache.go", Additions: 2, Deletions: 2, Hunks: []git.Hunk{
+			hunk(5, "-if cfg == nil {", "+if conf == nil {"),
+		}},
+		{Path: "store/store_test.go", Additions: 15},
+		{Path: "README.md", Additions: 3},
+		{Path: ".github/workflows/ci.yml", Additions: 4, Deletions: 1},
+		{Path: "config/app.yaml", Additions: 1},
+	}
+
+	report := Analyze(files)
+
+	var categories []string
+	for _, c := range report.Categories {
+		s := string(c.Category)
+		if c.Unrelated {
+			s += "*"
+		}
+		categories = append(categories, s)
+	}
+	if got := strings.Join(categories, " "); got != "source test ci* docs config*" {
+		t.Errorf("categories = %s", got)
+	}
+	if report.Unrelated != 2 {
+		t.Errorf("Unrelated = %d, want 2", report.Unrelated)
+	}
+	if len(report.Hunks) != 3 {
+		t.Errorf("Hunks = %+v, want 3", report.Hunks)
+	}
+	want := []string{
+		"Move the 1 formatting-only hunk (store/store.go) to a separate formatting commit, or revert them",
+		"Move the rename cfg -> conf (2 hunks in 2 files) to a separate refactoring change",
+		"Split the ci changes (.github/workflows/ci.yml) into a separate change",
+		"Split the config changes (config/app.yaml) into a separate change",
+	}
+	if strings.Join(report.Suggestions, "\n") != strings.Join(want, "\n") {
+		t.Errorf("suggestions:\n%s\nwant:\n%s", strings.Join(report.Suggestions, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAnalyzeSingleConcern(t *testing.T) {
+	// A change that only reformats does one thing
+	files := []git.FileDiff{
+		{Path: "a.go", Additions: 1, Deletions: 1, Hunks: []git.Hunk{hunk(1, "-x:=1", "+x := 1")}},
+		{Path: "b_test.go", Additions: 1, Deletions: 1, Hunks: []git.Hunk{hunk(1, "-y:=2", "+y := 2")}},
+	}
+	report := Analyze(files)
+	if report.Unrelated != 0 || len(report.Hunks) != 0 || len(report.Suggestions) != 0 {
+		t.Errorf("Analyze = %+v, want nothing to split", report)
+	}
+}
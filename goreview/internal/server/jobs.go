@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+
+	"github.com/JNZader/goreview/goreview/internal/progress"
+)
+
+// Job statuses.
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// ErrJobNotFound is returned for job IDs not in the store, or of another
+// tenant.
+var ErrJobNotFound = errors.New("job not found")
+
+// Job is an asynchronous review.
+type Job struct {
+	ID     string `json:"id"`
+	Tenant string `json:"tenant"`
+	Status string `json:"status"`
+	// Progress is the progress of a running job
+	Progress *progress.Snapshot `json:"progress,omitempty"`
+	// Result is the JSON report of a done job
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+
+	diff string
+}
+
+// finished reports whether the job is done or failed.
+func (j *Job) finished() bool {
+	return j.Status == JobDone || j.Status == JobFailed
+}
+
+// JobStore persists jobs in SQLite, so queued and interrupted reviews
+// survive restarts.
+type JobStore struct {
+	db *sql.DB
+}
+
+// OpenJobStore opens the job database at path. Jobs left running by a
+// previous process are queued again.
+func OpenJobStore(path string) (*JobStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening job database: %w", err)
+	}
+	// One connection serializes claims, so no two workers get the same job
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range []string{
+		`PRAGMA journal_mode=WAL`,
+		`CREATE TABLE IF NOT EXISTS jobs (
+			id TEXT PRIMARY KEY,
+			tenant TEXT NOT NULL,
+			status TEXT NOT NULL,
+			diff TEXT NOT NULL,
+			result TEXT,
+			error TEXT,
+			created_at DATETIME NOT NULL,
+			started_at DATETIME,
+			finished_at DATETIME
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status, created_at)`,
+		`UPDATE jobs SET status = 'queued', started_at = NULL WHERE status = 'running'`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			_ = db.Close() // #nosec G104 - best effort cleanup
+			return nil, fmt.Errorf("preparing job database: %w", err)
+		}
+	}
+	return &JobStore{db: db}, nil
+}
+
+// Close closes the database.
+func (s *JobStore) Close() error {
+	return s.db.Close()
+}
+
+// Enqueue stores a new job reviewing diff for the tenant.
+func (s *JobStore) Enqueue(ctx context.Context, tenant, diff string) (*Job, error) {
+	job := &Job{ID: uuid.New().String(), Tenant: tenant, Status: JobQueued, CreatedAt: time.Now().UTC(), diff: diff}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO jobs (id, tenant, status, diff, created_at) VALUES (?, ?, ?, ?, ?)`,
+		job.ID, job.Tenant, job.Status, job.diff, job.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("queuing job: %w", err)
+	}
+	return job, nil
+}
+
+// Claim marks the oldest queued job as running and returns it, or nil when
+// none is queued.
+func (s *JobStore) Claim(ctx context.Context) (*Job, error) {
+	now := time.Now().UTC()
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE jobs SET status = 'running', started_at = ?
+		WHERE id = (SELECT id FROM jobs WHERE status = 'queued' ORDER BY created_at, id LIMIT 1)
+		RETURNING id, tenant, status, diff, created_at
+	`, now)
+	job := &Job{StartedAt: &now}
+	err := row.Scan(&job.ID, &job.Tenant, &job.Status, &job.diff, &job.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claiming job: %w", err)
+	}
+	return job, nil
+}
+
+// Finish stores the outcome of a job: its JSON report, or the error that
+// failed it.
+func (s *JobStore) Finish(ctx context.Context, id string, result []byte, jobErr error) error {
+	status, errMsg, report := JobDone, "", sql.NullString{String: string(result), Valid: result != nil}
+	if jobErr != nil {
+		status, errMsg = JobFailed, jobErr.Error()
+	}
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = ?, result = ?, error = ?, finished_at = ? WHERE id = ?`,
+		status, report, errMsg, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("finishing job: %w", err)
+	}
+	return nil
+}
+
+// Get returns a job of the tenant.
+func (s *JobStore) Get(ctx context.Context, tenant, id string) (*Job, error) {
+	job := &Job{}
+	var result, errMsg sql.NullString
+	var started, finished sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, tenant, status, result, error, created_at, started_at, finished_at
+		FROM jobs WHERE id = ? AND tenant = ?
+	`, id, tenant).Scan(&job.ID, &job.Tenant, &job.Status, &result, &errMsg, &job.CreatedAt, &started, &finished)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying job: %w", err)
+	}
+	if result.Valid {
+		job.Result = json.RawMessage(result.String)
+	}
+	job.Error = errMsg.String
+	if started.Valid {
+		job.StartedAt = &started.Time
+	}
+	if finished.Valid {
+		job.FinishedAt = &finished.Time
+	}
+	return job, nil
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/logger"
+	"github.com/JNZader/goreview/goreview/internal/progress"
+)
+
+// pollInterval is how often idle workers look for jobs queued by another
+// process or left over from a restart.
+const pollInterval = 5 * time.Second
+
+// Queue runs asynchronous reviews on a pool of workers.
+type Queue struct {
+	store   *JobStore
+	tenants map[string]*Tenant
+	review  ReviewFunc
+	workers int
+	log     *logger.Logger
+
+	wake chan struct{}
+	wg   sync.WaitGroup
+
+	mu      sync.Mutex
+	running map[string]*progress.Tracker
+}
+
+// NewQueue creates a queue running the jobs in store with the given number
+// of workers.
+func NewQueue(store *JobStore, tenants []*Tenant, review ReviewFunc, workers int) *Queue {
+	q := &Queue{
+		store:   store,
+		tenants: make(map[string]*Tenant, len(tenants)),
+		review:  review,
+		workers: max(workers, 1),
+		log:     logger.Default().WithPrefix("QUEUE"),
+		wake:    make(chan struct{}, 1),
+		running: make(map[string]*progress.Tracker),
+	}
+	for _, t := range tenants {
+		q.tenants[t.ID] = t
+	}
+	return q
+}
+
+// Start starts the workers. They stop when ctx is done; Wait waits for them.
+func (q *Queue) Start(ctx context.Context) {
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.work(ctx)
+		}()
+	}
+}
+
+// Wait waits for the workers to stop.
+func (q *Queue) Wait() {
+	q.wg.Wait()
+}
+
+// Submit queues a review of diff for the tenant.
+func (q *Queue) Submit(ctx context.Context, t *Tenant, diff string) (*Job, error) {
+	job, err := q.store.Enqueue(ctx, t.ID, diff)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Get returns a job of the tenant, with its progress while running.
+func (q *Queue) Get(ctx context.Context, t *Tenant, id string) (*Job, error) {
+	job, err := q.store.Get(ctx, t.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == JobRunning {
+		q.mu.Lock()
+		tracker := q.running[id]
+		q.mu.Unlock()
+		if tracker != nil {
+			snapshot := tracker.Snapshot()
+			job.Progress = &snapshot
+		}
+	}
+	return job, nil
+}
+
+// work runs jobs until ctx is done.
+func (q *Queue) work(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		job, err := q.store.Claim(ctx)
+		if err != nil && ctx.Err() == nil {
+			q.log.Error("Claiming job: %v", err)
+		}
+		if job != nil {
+			q.run(ctx, job)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// run reviews a job and stores its outcome. A job interrupted by shutdown
+// is left running, so the next start queues it again.
+func (q *Queue) run(ctx context.Context, job *Job) {
+	tracker := progress.NewTracker()
+	q.mu.Lock()
+	q.running[job.ID] = tracker
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		delete(q.running, job.ID)
+		q.mu.Unlock()
+	}()
+
+	report, err := q.reviewJob(ctx, job, tracker)
+	if ctx.Err() != nil {
+		q.log.Info("Job %s interrupted, it will run again on restart", job.ID)
+		return
+	}
+	if err != nil {
+		q.log.Error("Job %s for tenant %s failed: %v", job.ID, job.Tenant, err)
+	}
+	// The outcome is stored even if shutdown starts meanwhile
+	if err := q.store.Finish(context.WithoutCancel(ctx), job.ID, report, err); err != nil {
+		q.log.Error("Storing job %s: %v", job.ID, err)
+	}
+}
+
+func (q *Queue) reviewJob(ctx context.Context, job *Job, tracker *progress.Tracker) ([]byte, error) {
+	t := q.tenants[job.Tenant]
+	if t == nil {
+		return nil, fmt.Errorf("tenant %s no longer configured", job.Tenant)
+	}
+	diff, err := git.ParsePatch(job.diff)
+	if err != nil {
+		return nil, fmt.Errorf("invalid diff: %w", err)
+	}
+	result, err := q.review(ctx, t, diff, tracker)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(result.Public())
+}
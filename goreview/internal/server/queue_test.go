@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/progress"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+func TestJobStoreRequeuesInterruptedJobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	store, err := OpenJobStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	first, _ := store.Enqueue(ctx, "team-a", testDiff)
+	second, _ := store.Enqueue(ctx, "team-a", testDiff)
+
+	claimed, err := store.Claim(ctx)
+	if err != nil || claimed == nil || claimed.ID != first.ID || claimed.diff != testDiff {
+		t.Fatalf("Claim = %+v, %v; want %s", claimed, err, first.ID)
+	}
+	if err := store.Finish(ctx, second.ID, nil, errors.New("boom")); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Close()
+
+	// The running job is queued again after a restart
+	store, err = OpenJobStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if job, _ := store.Get(ctx, "team-a", first.ID); job.Status != JobQueued {
+		t.Errorf("interrupted job status = %s, want queued", job.Status)
+	}
+	if job, _ := store.Get(ctx, "team-a", second.ID); job.Status != JobFailed || job.Error != "boom" {
+		t.Errorf("failed job = %+v", job)
+	}
+	if _, err := store.Get(ctx, "team-b", first.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("other tenant's job: err = %v", err)
+	}
+}
+
+func TestServerRunsQueuedReviews(t *testing.T) {
+	dataDir := t.TempDir()
+	var tenants []*Tenant
+	for _, tc := range []config.TenantConfig{
+		{ID: "team-a", APIKeys: []string{"key-a"}},
+		{ID: "team-b", APIKeys: []string{"key-b"}},
+	} {
+		tenant, err := NewTenant(tc, config.DefaultConfig(), dataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tenants = append(tenants, tenant)
+	}
+	reviewFn := func(_ context.Context, tenant *Tenant, diff *git.Diff, tracker *progress.Tracker) (*review.Result, error) {
+		tracker.Queue(len(diff.Files))
+		return &review.Result{Summary: "reviewed for " + tenant.ID}, nil
+	}
+
+	store, err := OpenJobStore(filepath.Join(dataDir, "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	queue := NewQueue(store, tenants, reviewFn, 2)
+	s := New(tenants, reviewFn, 0)
+	s.UseQueue(queue)
+	s.streamEvery = 10 * time.Millisecond
+
+	rec := do(s, http.MethodPost, "/v1/reviews", "key-a", testDiff)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("submit = %d %s", rec.Code, rec.Body)
+	}
+	var job Job
+	_ = json.Unmarshal(rec.Body.Bytes(), &job)
+	if job.Status != JobQueued || rec.Header().Get("Location") != "/v1/reviews/"+job.ID {
+		t.Fatalf("submitted job = %+v", job)
+	}
+	if rec := do(s, http.MethodGet, "/v1/reviews/"+job.ID, "key-b", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("other tenant = %d, want 404", rec.Code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		queue.Wait()
+	}()
+	queue.Start(ctx)
+
+	// The stream reports progress until the job finishes
+	req, _ := http.NewRequest(http.MethodGet, "/v1/reviews/"+job.ID, nil)
+	req.Header.Set("Authorization", "Bearer key-a")
+	req.Header.Set("Accept", "text/event-stream")
+	stream := httptest.NewRecorder()
+	s.Handler().ServeHTTP(stream, req)
+	body := stream.Body.String()
+	if !strings.Contains(body, "event: done") || !strings.Contains(body, "reviewed for team-a") {
+		t.Errorf("stream =\n%s", body)
+	}
+
+	rec = do(s, http.MethodGet, "/v1/reviews/"+job.ID, "key-a", "")
+	_ = json.Unmarshal(rec.Body.Bytes(), &job)
+	if job.Status != JobDone || job.FinishedAt == nil || len(job.Result) == 0 {
+		t.Errorf("finished job = %+v", job)
+	}
+}
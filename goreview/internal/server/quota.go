@@ -0,0 +1,70 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// quota counts a tenant's requests in fixed minute and day windows.
+type quota struct {
+	cfg config.QuotaConfig
+
+	mu     sync.Mutex
+	minute window
+	day    window
+}
+
+// window counts the requests since start.
+type window struct {
+	start time.Time
+	count int
+}
+
+func newQuota(cfg config.QuotaConfig) *quota {
+	return &quota{cfg: cfg}
+}
+
+// Usage is a tenant's request count in the current windows.
+type Usage struct {
+	Minute int `json:"minute"`
+	Day    int `json:"day"`
+}
+
+// allow counts a request made at now, unless it exceeds a limit, in which
+// case it returns how long until the exceeded window resets.
+func (q *quota) allow(now time.Time) (bool, time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.roll(now)
+	if limit := q.cfg.RequestsPerMinute; limit > 0 && q.minute.count >= limit {
+		return false, q.minute.start.Add(time.Minute).Sub(now)
+	}
+	if limit := q.cfg.RequestsPerDay; limit > 0 && q.day.count >= limit {
+		return false, q.day.start.Add(24 * time.Hour).Sub(now)
+	}
+	q.minute.count++
+	q.day.count++
+	return true, 0
+}
+
+// usage returns the request counts of the windows containing now.
+func (q *quota) usage(now time.Time) Usage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.roll(now)
+	return Usage{Minute: q.minute.count, Day: q.day.count}
+}
+
+// roll starts new windows once now leaves the current ones. Days are UTC.
+func (q *quota) roll(now time.Time) {
+	if start := now.Truncate(time.Minute); !start.Equal(q.minute.start) {
+		q.minute = window{start: start}
+	}
+	if start := now.UTC().Truncate(24 * time.Hour); !start.Equal(q.day.start) {
+		q.day = window{start: start}
+	}
+}
@@ -0,0 +1,267 @@
+// Package server implements the HTTP review service started by "goreview
+// serve". Requests authenticate with a tenant's API key; each tenant reviews
+// with its own configuration, keeps its data in its own directory and is
+// held to its own quota.
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/logger"
+	"github.com/JNZader/goreview/goreview/internal/progress"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// ReviewFunc reviews a diff with the configuration of a tenant, reporting
+// per-file progress to tracker (which may be nil).
+type ReviewFunc func(ctx context.Context, t *Tenant, diff *git.Diff, tracker *progress.Tracker) (*review.Result, error)
+
+// streamInterval is how often a streamed job reports its progress.
+const streamInterval = time.Second
+
+// Server serves reviews to its tenants.
+type Server struct {
+	tenants      []*Tenant
+	review       ReviewFunc
+	maxBodyBytes int64
+	jobs         *Queue
+	log          *logger.Logger
+	now          func() time.Time
+	// streamEvery is the progress interval of streamed jobs
+	streamEvery time.Duration
+}
+
+// New creates a server reviewing with review. Submitted diffs over
+// maxBodyBytes are rejected; zero means no limit.
+func New(tenants []*Tenant, review ReviewFunc, maxBodyBytes int64) *Server {
+	return &Server{
+		tenants:      tenants,
+		review:       review,
+		maxBodyBytes: maxBodyBytes,
+		log:          logger.Default().WithPrefix("SERVE"),
+		now:          time.Now,
+		streamEvery:  streamInterval,
+	}
+}
+
+// UseQueue enables the asynchronous review API, running jobs on q.
+func (s *Server) UseQueue(q *Queue) {
+	s.jobs = q
+}
+
+// Handler returns the HTTP API:
+//
+//	GET  /healthz           liveness, without authentication
+//	GET  /v1/tenant         the calling tenant and its quota usage
+//	POST /v1/review         review a unified diff, returning the JSON report
+//	POST /v1/reviews        queue a review, returning its job (with a queue)
+//	GET  /v1/reviews/{id}   a job's status, progress and report (with a queue)
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /v1/tenant", s.authenticated(s.handleTenant))
+	mux.HandleFunc("POST /v1/review", s.authenticated(s.handleReview))
+	if s.jobs != nil {
+		mux.HandleFunc("POST /v1/reviews", s.authenticated(s.handleSubmit))
+		mux.HandleFunc("GET /v1/reviews/{id}", s.authenticated(s.handleJob))
+	}
+	return mux
+}
+
+// authenticated resolves the tenant of a request from its API key, given as
+// a bearer token or in X-API-Key.
+func (s *Server) authenticated(next func(http.ResponseWriter, *http.Request, *Tenant)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if auth := r.Header.Get("Authorization"); key == "" && strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if key == "" {
+			writeError(w, http.StatusUnauthorized, "API key required")
+			return
+		}
+		t := s.tenantFor(key)
+		if t == nil {
+			writeError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+		next(w, r, t)
+	}
+}
+
+// tenantFor returns the tenant owning key, or nil.
+func (s *Server) tenantFor(key string) *Tenant {
+	digest := sha256.Sum256([]byte(key))
+	var owner *Tenant
+	for _, t := range s.tenants {
+		if t.matches(digest) && owner == nil {
+			owner = t
+		}
+	}
+	return owner
+}
+
+func (s *Server) handleTenant(w http.ResponseWriter, _ *http.Request, t *Tenant) {
+	q := t.quota.cfg
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id": t.ID,
+		"quota": map[string]int{
+			"requests_per_minute": q.RequestsPerMinute,
+			"requests_per_day":    q.RequestsPerDay,
+			"max_files":           q.MaxFiles,
+		},
+		"usage": t.quota.usage(s.now()),
+	})
+}
+
+// handleReview reviews the diff in the request body: a raw unified diff, or
+// a JSON object with the diff in "diff".
+func (s *Server) handleReview(w http.ResponseWriter, r *http.Request, t *Tenant) {
+	diff, _, ok := s.readDiff(w, r, t)
+	if !ok {
+		return
+	}
+
+	start := s.now()
+	result, err := s.review(r.Context(), t, diff, nil)
+	if err != nil {
+		s.log.Error("Review for tenant %s failed: %v", t.ID, err)
+		writeError(w, http.StatusInternalServerError, "review failed: "+err.Error())
+		return
+	}
+	s.log.Info("Reviewed %d files for tenant %s in %v", len(diff.Files), t.ID, s.now().Sub(start))
+	writeJSON(w, http.StatusOK, result.Public())
+}
+
+// handleSubmit queues a review of the diff in the request body, accepted
+// as by handleReview.
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request, t *Tenant) {
+	_, patch, ok := s.readDiff(w, r, t)
+	if !ok {
+		return
+	}
+	job, err := s.jobs.Submit(r.Context(), t, patch)
+	if err != nil {
+		s.log.Error("Queuing review for tenant %s failed: %v", t.ID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Location", "/v1/reviews/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleJob returns a job, or streams its progress as server-sent events
+// until it finishes when the client accepts text/event-stream.
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request, t *Tenant) {
+	job, err := s.jobs.Get(r.Context(), t, r.PathValue("id"))
+	if errors.Is(err, ErrJobNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	flusher, canFlush := w.(http.Flusher)
+	if !canFlush || !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		writeJSON(w, http.StatusOK, job)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	ticker := time.NewTicker(s.streamEvery)
+	defer ticker.Stop()
+	for {
+		event := "progress"
+		if job.finished() {
+			event = job.Status
+		}
+		data, _ := json.Marshal(job)
+		_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+		if job.finished() {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+		if job, err = s.jobs.Get(r.Context(), t, job.ID); err != nil {
+			return
+		}
+	}
+}
+
+// readDiff reads the diff of a review request after checking the tenant's
+// quota, returning it parsed and as text. On failure it writes the error
+// response and returns false.
+func (s *Server) readDiff(w http.ResponseWriter, r *http.Request, t *Tenant) (*git.Diff, string, bool) {
+	if ok, retry := t.quota.allow(s.now()); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, "request quota exceeded")
+		return nil, "", false
+	}
+
+	body := io.Reader(r.Body)
+	if s.maxBodyBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("diff larger than %d bytes", s.maxBodyBytes))
+			return nil, "", false
+		}
+		writeError(w, http.StatusBadRequest, "reading request: "+err.Error())
+		return nil, "", false
+	}
+
+	patch := string(data)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var req struct {
+			Diff string `json:"diff"`
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+			return nil, "", false
+		}
+		patch = req.Diff
+	}
+	diff, err := git.ParsePatch(patch)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid diff: "+err.Error())
+		return nil, "", false
+	}
+	if limit := t.quota.cfg.MaxFiles; limit > 0 && len(diff.Files) > limit {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("diff has %d files, the limit is %d", len(diff.Files), limit))
+		return nil, "", false
+	}
+	return diff, patch, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+	"github.com/JNZader/goreview/goreview/internal/git"
+	"github.com/JNZader/goreview/goreview/internal/progress"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+const testDiff = `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@
+ package main
++import "fmt"
+ func main() {
+ }
+`
+
+func newTestServer(t *testing.T, tenants ...config.TenantConfig) (*Server, *[]string) {
+	t.Helper()
+	dataDir := t.TempDir()
+	var list []*Tenant
+	for _, tc := range tenants {
+		tenant, err := NewTenant(tc, config.DefaultConfig(), dataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, tenant)
+	}
+	var reviewed []string
+	s := New(list, func(_ context.Context, tenant *Tenant, diff *git.Diff, _ *progress.Tracker) (*review.Result, error) {
+		reviewed = append(reviewed, tenant.ID)
+		return &review.Result{Summary: tenant.Config.Cache.Dir}, nil
+	}, 1024)
+	return s, &reviewed
+}
+
+func do(s *Server, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerAuthenticatesTenants(t *testing.T) {
+	digest := sha256.Sum256([]byte("key-b"))
+	t.Setenv("TEAM_A_KEY", "key-a")
+	s, reviewed := newTestServer(t,
+		config.TenantConfig{ID: "team-a", APIKeys: []string{"${TEAM_A_KEY}"}},
+		config.TenantConfig{ID: "team-b", APIKeys: []string{"sha256:" + hex.EncodeToString(digest[:])}},
+	)
+
+	if rec := do(s, http.MethodGet, "/healthz", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("healthz = %d", rec.Code)
+	}
+	if rec := do(s, http.MethodPost, "/v1/review", "", testDiff); rec.Code != http.StatusUnauthorized {
+		t.Errorf("no key = %d, want 401", rec.Code)
+	}
+	if rec := do(s, http.MethodPost, "/v1/review", "wrong", testDiff); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong key = %d, want 401", rec.Code)
+	}
+
+	recA := do(s, http.MethodPost, "/v1/review", "key-a", testDiff)
+	recB := do(s, http.MethodPost, "/v1/review", "key-b", testDiff)
+	if recA.Code != http.StatusOK || recB.Code != http.StatusOK {
+		t.Fatalf("reviews = %d %s, %d %s", recA.Code, recA.Body, recB.Code, recB.Body)
+	}
+	if strings.Join(*reviewed, ",") != "team-a,team-b" {
+		t.Errorf("reviewed for %v", *reviewed)
+	}
+	// Each tenant caches in its own directory
+	if !strings.Contains(recA.Body.String(), "team-a") || !strings.Contains(recB.Body.String(), "team-b") {
+		t.Errorf("tenant data not namespaced:\n%s\n%s", recA.Body, recB.Body)
+	}
+}
+
+func TestServerEnforcesQuota(t *testing.T) {
+	s, _ := newTestServer(t, config.TenantConfig{
+		ID: "team-a", APIKeys: []string{"key-a"},
+		Quota: config.QuotaConfig{RequestsPerMinute: 2, MaxFiles: 1},
+	})
+	now := time.Date(2026, 3, 1, 10, 0, 30, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if rec := do(s, http.MethodPost, "/v1/review", "key-a", testDiff); rec.Code != http.StatusOK {
+			t.Fatalf("request %d = %d", i, rec.Code)
+		}
+	}
+	rec := do(s, http.MethodPost, "/v1/review", "key-a", testDiff)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "30" {
+		t.Errorf("over quota = %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	now = now.Add(time.Minute)
+	twoFiles := testDiff + strings.ReplaceAll(testDiff, "main.go", "other.go")
+	if rec := do(s, http.MethodPost, "/v1/review", "key-a", twoFiles); rec.Code != http.StatusBadRequest {
+		t.Errorf("too many files = %d, want 400", rec.Code)
+	}
+	if rec := do(s, http.MethodPost, "/v1/review", "key-a", strings.Repeat("x", 2048)); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("large body = %d, want 413", rec.Code)
+	}
+
+	// Rejected requests count against the quota too
+	rec = do(s, http.MethodGet, "/v1/tenant", "key-a", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"usage":{"minute":2,"day":4}`) {
+		t.Errorf("tenant = %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestNewTenantRejectsEmptyKeys(t *testing.T) {
+	_, err := NewTenant(config.TenantConfig{ID: "team-a", APIKeys: []string{"${UNSET_GOREVIEW_KEY}"}},
+		config.DefaultConfig(), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "empty key") {
+		t.Errorf("err = %v, want empty key", err)
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/config"
+)
+
+// Tenant is a team served by the service, with its own configuration, data
+// and quota.
+type Tenant struct {
+	ID string
+	// Config is the tenant's configuration, with its cache and memory kept
+	// under Dir
+	Config *config.Config
+	// Dir holds the tenant's data
+	Dir string
+
+	keys  [][sha256.Size]byte
+	quota *quota
+}
+
+// NewTenant creates a tenant reviewing with cfg, its base configuration with
+// the tenant's profile applied. The directories of cfg holding data are moved
+// under the tenant's directory in dataDir.
+func NewTenant(tc config.TenantConfig, cfg *config.Config, dataDir string) (*Tenant, error) {
+	t := &Tenant{
+		ID:    tc.ID,
+		Dir:   filepath.Join(dataDir, tc.ID),
+		quota: newQuota(tc.Quota),
+	}
+	for i, key := range tc.APIKeys {
+		digest, err := parseKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: API key %d: %w", tc.ID, i+1, err)
+		}
+		t.keys = append(t.keys, digest)
+	}
+
+	scoped := *cfg
+	scoped.Cache.Dir = filepath.Join(t.Dir, "cache")
+	scoped.Memory.Dir = filepath.Join(t.Dir, "memory")
+	scoped.Knowledge.CacheDir = filepath.Join(t.Dir, "knowledge")
+	scoped.RAG.CacheDir = filepath.Join(t.Dir, "rag")
+	t.Config = &scoped
+
+	if err := os.MkdirAll(t.Dir, 0750); err != nil {
+		return nil, fmt.Errorf("tenant %s: creating data directory: %w", tc.ID, err)
+	}
+	return t, nil
+}
+
+// HistoryPath is the tenant's history database.
+func (t *Tenant) HistoryPath() string {
+	return filepath.Join(t.Dir, "history.db")
+}
+
+// matches reports whether key is one of the tenant's API keys.
+func (t *Tenant) matches(digest [sha256.Size]byte) bool {
+	found := 0
+	for _, k := range t.keys {
+		// Every key is compared, so the time taken doesn't tell which matched
+		found |= subtle.ConstantTimeCompare(k[:], digest[:])
+	}
+	return found == 1
+}
+
+// parseKey returns the digest of a configured key: "sha256:<hex>", ${VAR}
+// naming an environment variable holding the key, or the key itself.
+func parseKey(key string) ([sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+	if hexDigest, ok := strings.CutPrefix(key, "sha256:"); ok {
+		raw, err := hex.DecodeString(hexDigest)
+		if err != nil || len(raw) != sha256.Size {
+			return digest, fmt.Errorf("invalid sha256 digest")
+		}
+		copy(digest[:], raw)
+		return digest, nil
+	}
+	key = os.ExpandEnv(key)
+	if key == "" {
+		return digest, fmt.Errorf("empty key")
+	}
+	return sha256.Sum256([]byte(key)), nil
+}
@@ -0,0 +1,174 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/history"
+)
+
+// Chart geometry, in SVG units
+const (
+	chartWidth   = 720
+	chartHeight  = 220
+	chartPadding = 30
+	maxWeeks     = 52
+)
+
+// severityColors are the chart colors per severity
+var severityColors = map[string]string{
+	"critical": "#cf222e",
+	"error":    "#e16f24",
+	"warning":  "#d4a72c",
+	"info":     "#218bff",
+}
+
+// weekOf returns the start of the week (Monday, UTC) containing t.
+func weekOf(t time.Time) time.Time {
+	t = t.UTC()
+	day := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-day, 0, 0, 0, 0, time.UTC)
+}
+
+// weeks returns the weeks spanned by the records, capped to the last maxWeeks.
+func weeks(records []history.ReviewRecord) []time.Time {
+	if len(records) == 0 {
+		return nil
+	}
+	first, last := weekOf(records[0].CreatedAt), weekOf(records[0].CreatedAt)
+	for _, r := range records {
+		w := weekOf(r.CreatedAt)
+		if w.Before(first) {
+			first = w
+		}
+		if w.After(last) {
+			last = w
+		}
+	}
+	if limit := last.AddDate(0, 0, -7*(maxWeeks-1)); first.Before(limit) {
+		first = limit
+	}
+
+	var out []time.Time
+	for w := first; !w.After(last); w = w.AddDate(0, 0, 7) {
+		out = append(out, w)
+	}
+	return out
+}
+
+// issuesChart renders the issues found per week as bars stacked by severity.
+func issuesChart(records []history.ReviewRecord) template.HTML {
+	ws := weeks(records)
+	if len(ws) == 0 {
+		return ""
+	}
+
+	index := make(map[time.Time]int, len(ws))
+	for i, w := range ws {
+		index[w] = i
+	}
+	counts := make([]map[string]int, len(ws))
+	for i := range counts {
+		counts[i] = make(map[string]int)
+	}
+	for _, r := range records {
+		if i, ok := index[weekOf(r.CreatedAt)]; ok {
+			counts[i][severityOf(r.Severity)]++
+		}
+	}
+
+	maxTotal := 1
+	for _, c := range counts {
+		total := 0
+		for _, n := range c {
+			total += n
+		}
+		maxTotal = max(maxTotal, total)
+	}
+
+	var sb strings.Builder
+	writeChartStart(&sb, "Issues found per week", maxTotal)
+	slot := float64(chartWidth-2*chartPadding) / float64(len(ws))
+	barWidth := max(slot*0.7, 1)
+	scale := float64(chartHeight-2*chartPadding) / float64(maxTotal)
+	for i, w := range ws {
+		x := chartPadding + float64(i)*slot + (slot-barWidth)/2
+		y := float64(chartHeight - chartPadding)
+		for _, s := range severities {
+			n := counts[i][s]
+			if n == 0 {
+				continue
+			}
+			h := float64(n) * scale
+			y -= h
+			fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %d %s</title></rect>`,
+				x, y, barWidth, h, severityColors[s], w.Format("2006-01-02"), n, s)
+		}
+	}
+	writeChartAxis(&sb, ws)
+	sb.WriteString("</svg>")
+	return template.HTML(sb.String()) // #nosec G203 - built only from numbers, dates and fixed strings
+}
+
+// openChart renders the number of unresolved issues at the end of each week.
+func openChart(records []history.ReviewRecord) template.HTML {
+	ws := weeks(records)
+	if len(ws) == 0 {
+		return ""
+	}
+
+	open := make([]int, len(ws))
+	for i, w := range ws {
+		end := w.AddDate(0, 0, 7)
+		for _, r := range records {
+			if !r.CreatedAt.Before(end) {
+				continue
+			}
+			if !r.Resolved || r.ResolvedAt.IsZero() || !r.ResolvedAt.Before(end) {
+				open[i]++
+			}
+		}
+	}
+
+	maxOpen := 1
+	for _, n := range open {
+		maxOpen = max(maxOpen, n)
+	}
+
+	var sb strings.Builder
+	writeChartStart(&sb, "Open issues", maxOpen)
+	slot := float64(chartWidth-2*chartPadding) / float64(len(ws))
+	scale := float64(chartHeight-2*chartPadding) / float64(maxOpen)
+	points := make([]string, len(ws))
+	for i, n := range open {
+		x := chartPadding + float64(i)*slot + slot/2
+		y := float64(chartHeight-chartPadding) - float64(n)*scale
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+		fmt.Fprintf(&sb, `<circle cx="%.1f" cy="%.1f" r="3" fill="#8250df"><title>%s: %d open</title></circle>`,
+			x, y, ws[i].Format("2006-01-02"), n)
+	}
+	fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="#8250df" stroke-width="2"/>`, strings.Join(points, " "))
+	writeChartAxis(&sb, ws)
+	sb.WriteString("</svg>")
+	return template.HTML(sb.String()) // #nosec G203 - built only from numbers, dates and fixed strings
+}
+
+func writeChartStart(sb *strings.Builder, title string, maxValue int) {
+	fmt.Fprintf(sb, `<svg class="chart" viewBox="0 0 %d %d" role="img" aria-label="%s">`, chartWidth, chartHeight, title)
+	fmt.Fprintf(sb, `<line x1="%d" y1="%d" x2="%d" y2="%d" class="axis"/>`,
+		chartPadding, chartHeight-chartPadding, chartWidth-chartPadding, chartHeight-chartPadding)
+	fmt.Fprintf(sb, `<text x="%d" y="%d" class="label">%d</text>`, 2, chartPadding+4, maxValue)
+	fmt.Fprintf(sb, `<text x="%d" y="%d" class="label">0</text>`, 2, chartHeight-chartPadding)
+}
+
+// writeChartAxis labels the first and last weeks.
+func writeChartAxis(sb *strings.Builder, ws []time.Time) {
+	fmt.Fprintf(sb, `<text x="%d" y="%d" class="label">%s</text>`,
+		chartPadding, chartHeight-8, ws[0].Format("2006-01-02"))
+	if len(ws) > 1 {
+		fmt.Fprintf(sb, `<text x="%d" y="%d" class="label" text-anchor="end">%s</text>`,
+			chartWidth-chartPadding, chartHeight-8, ws[len(ws)-1].Format("2006-01-02"))
+	}
+}
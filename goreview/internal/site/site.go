@@ -0,0 +1,290 @@
+// Package site renders the review history into a static website that can be
+// published (e.g. with GitHub Pages) as a code-health portal.
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/history"
+)
+
+// severities lists the known severities, most important first
+var severities = []string{"critical", "error", "warning", "info"}
+
+// Options configures site generation.
+type Options struct {
+	// Title is shown in the page header (default: "GoReview Code Health")
+	Title string
+
+	// Generated is the generation time shown in the footer (default: now)
+	Generated time.Time
+}
+
+// Review is a group of history records from the same commit and review round.
+type Review struct {
+	ID         string
+	Commit     string
+	Round      int
+	Author     string
+	Branch     string
+	Date       time.Time
+	Issues     []history.ReviewRecord
+	BySeverity map[string]int
+	Open       int
+}
+
+// Page returns the review page path relative to the site root.
+func (r *Review) Page() string {
+	return "reviews/" + r.ID + ".html"
+}
+
+// ShortCommit returns the abbreviated commit hash.
+func (r *Review) ShortCommit() string {
+	if len(r.Commit) > 7 {
+		return r.Commit[:7]
+	}
+	return r.Commit
+}
+
+// Group aggregates issues and reviews under a name (file, author, month).
+type Group struct {
+	Name    string
+	Issues  int
+	Open    int
+	Reviews []*Review
+}
+
+// SeverityGroup lists the issues of a severity.
+type SeverityGroup struct {
+	Name   string
+	Issues []IssueRef
+}
+
+// IssueRef is an issue with the review it belongs to.
+type IssueRef struct {
+	history.ReviewRecord
+	Review *Review
+}
+
+// model is the data shared by all pages.
+type model struct {
+	Title       string
+	Generated   time.Time
+	Reviews     []*Review // Newest first
+	Months      []*Group
+	Files       []*Group
+	Authors     []*Group
+	Severities  []SeverityGroup
+	TotalIssues int
+	Open        int
+	Resolved    int
+	IssuesChart template.HTML
+	OpenChart   template.HTML
+}
+
+// pageData is passed to every page template.
+type pageData struct {
+	*model
+	Root   string // Relative path to the site root
+	Page   string
+	Review *Review
+}
+
+// Build renders the records into a static site in outDir.
+func Build(records []history.ReviewRecord, outDir string, opts Options) error {
+	if opts.Title == "" {
+		opts.Title = "GoReview Code Health"
+	}
+	if opts.Generated.IsZero() {
+		opts.Generated = time.Now()
+	}
+
+	m := buildModel(records, opts)
+	pages, err := parseTemplates()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Join(outDir, "reviews"), 0750); err != nil {
+		return fmt.Errorf("creating site directory: %w", err)
+	}
+
+	files := map[string]string{
+		"style.css": styleCSS,
+		".nojekyll": "", // Serve files as-is on GitHub Pages
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(outDir, name), []byte(content), 0600); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+
+	for _, name := range []string{"index", "by-date", "by-file", "by-severity", "by-author"} {
+		data := &pageData{model: m, Page: name}
+		if err := renderPage(pages[name], filepath.Join(outDir, name+".html"), data); err != nil {
+			return err
+		}
+	}
+	for _, r := range m.Reviews {
+		data := &pageData{model: m, Root: "../", Page: "review", Review: r}
+		if err := renderPage(pages["review"], filepath.Join(outDir, filepath.FromSlash(r.Page())), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderPage(tmpl *template.Template, path string, data *pageData) error {
+	f, err := os.Create(path) // #nosec G304 - path is built from the output directory
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := tmpl.ExecuteTemplate(f, "layout", data); err != nil {
+		return fmt.Errorf("rendering %s: %w", path, err)
+	}
+	return nil
+}
+
+// buildModel groups the records into reviews and builds the indexes.
+func buildModel(records []history.ReviewRecord, opts Options) *model {
+	m := &model{Title: opts.Title, Generated: opts.Generated}
+
+	byKey := make(map[string]*Review)
+	for _, rec := range records {
+		key := fmt.Sprintf("%s-%d", rec.CommitHash, rec.ReviewRound)
+		r, ok := byKey[key]
+		if !ok {
+			r = &Review{
+				Commit:     rec.CommitHash,
+				Round:      rec.ReviewRound,
+				Author:     rec.Author,
+				Branch:     rec.Branch,
+				Date:       rec.CreatedAt,
+				BySeverity: make(map[string]int),
+			}
+			byKey[key] = r
+			m.Reviews = append(m.Reviews, r)
+		}
+		if rec.CreatedAt.Before(r.Date) {
+			r.Date = rec.CreatedAt
+		}
+		r.Issues = append(r.Issues, rec)
+		r.BySeverity[severityOf(rec.Severity)]++
+		if !rec.Resolved {
+			r.Open++
+			m.Open++
+		} else {
+			m.Resolved++
+		}
+		m.TotalIssues++
+	}
+
+	sort.SliceStable(m.Reviews, func(i, j int) bool { return m.Reviews[i].Date.After(m.Reviews[j].Date) })
+	for i, r := range m.Reviews {
+		r.ID = reviewID(r, i)
+		sort.SliceStable(r.Issues, func(a, b int) bool {
+			if r.Issues[a].FilePath != r.Issues[b].FilePath {
+				return r.Issues[a].FilePath < r.Issues[b].FilePath
+			}
+			return r.Issues[a].Line < r.Issues[b].Line
+		})
+	}
+
+	m.Months = groupReviews(m.Reviews, func(r *Review, _ history.ReviewRecord) string { return r.Date.Format("January 2006") })
+	m.Files = sortByIssues(groupReviews(m.Reviews, func(_ *Review, rec history.ReviewRecord) string { return rec.FilePath }))
+	m.Authors = sortByIssues(groupReviews(m.Reviews, func(r *Review, _ history.ReviewRecord) string {
+		if r.Author == "" {
+			return "unknown"
+		}
+		return r.Author
+	}))
+	m.Severities = groupSeverities(m.Reviews)
+	m.IssuesChart = issuesChart(records)
+	m.OpenChart = openChart(records)
+	return m
+}
+
+var unsafeIDChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// reviewID returns a stable, filename-safe page name for a review.
+func reviewID(r *Review, index int) string {
+	commit := unsafeIDChars.ReplaceAllString(r.ShortCommit(), "")
+	if commit == "" {
+		return fmt.Sprintf("review-%d", index+1)
+	}
+	return fmt.Sprintf("%s-r%d", commit, r.Round)
+}
+
+// groupReviews groups issues under the name returned by key, keeping the
+// order in which names are first seen and listing each review once per group.
+func groupReviews(reviews []*Review, key func(*Review, history.ReviewRecord) string) []*Group {
+	var groups []*Group
+	byName := make(map[string]*Group)
+	for _, r := range reviews {
+		for _, rec := range r.Issues {
+			name := key(r, rec)
+			g, ok := byName[name]
+			if !ok {
+				g = &Group{Name: name}
+				byName[name] = g
+				groups = append(groups, g)
+			}
+			g.Issues++
+			if !rec.Resolved {
+				g.Open++
+			}
+			if len(g.Reviews) == 0 || g.Reviews[len(g.Reviews)-1] != r {
+				g.Reviews = append(g.Reviews, r)
+			}
+		}
+	}
+	return groups
+}
+
+func sortByIssues(groups []*Group) []*Group {
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Issues != groups[j].Issues {
+			return groups[i].Issues > groups[j].Issues
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+func groupSeverities(reviews []*Review) []SeverityGroup {
+	bySeverity := make(map[string][]IssueRef)
+	for _, r := range reviews {
+		for _, rec := range r.Issues {
+			s := severityOf(rec.Severity)
+			bySeverity[s] = append(bySeverity[s], IssueRef{ReviewRecord: rec, Review: r})
+		}
+	}
+
+	groups := make([]SeverityGroup, 0, len(severities))
+	for _, s := range severities {
+		if len(bySeverity[s]) > 0 {
+			groups = append(groups, SeverityGroup{Name: s, Issues: bySeverity[s]})
+		}
+	}
+	return groups
+}
+
+// severityOf maps a stored severity to a known one; unknown values count as info.
+func severityOf(s string) string {
+	s = strings.ToLower(s)
+	for _, known := range severities {
+		if s == known {
+			return s
+		}
+	}
+	return "info"
+}
@@ -0,0 +1,101 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/history"
+)
+
+func testRecords() []history.ReviewRecord {
+	day := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	return []history.ReviewRecord{
+		{ID: 1, CommitHash: "abcdef1234", FilePath: "auth/login.go", IssueType: "security", Severity: "critical",
+			Message: "SQL <injection>", Line: 42, Author: "ana", Branch: "main", CreatedAt: day, ReviewRound: 1},
+		{ID: 2, CommitHash: "abcdef1234", FilePath: "auth/login.go", IssueType: "style", Severity: "info",
+			Message: "Naming", Author: "ana", CreatedAt: day, ReviewRound: 1, Resolved: true, ResolvedAt: day.AddDate(0, 0, 8)},
+		{ID: 3, CommitHash: "1234567890", FilePath: "api/handler.go", IssueType: "bug", Severity: "error",
+			Message: "Nil dereference", Author: "bo", CreatedAt: day.AddDate(0, 0, 14), ReviewRound: 1},
+	}
+}
+
+func TestBuildModel(t *testing.T) {
+	m := buildModel(testRecords(), Options{})
+
+	if len(m.Reviews) != 2 {
+		t.Fatalf("reviews = %d, want 2", len(m.Reviews))
+	}
+	if m.Reviews[0].ID != "1234567-r1" {
+		t.Errorf("newest review = %s, want 1234567-r1", m.Reviews[0].ID)
+	}
+	if m.TotalIssues != 3 || m.Open != 2 || m.Resolved != 1 {
+		t.Errorf("totals = %d/%d/%d, want 3/2/1", m.TotalIssues, m.Open, m.Resolved)
+	}
+	if m.Files[0].Name != "auth/login.go" || m.Files[0].Issues != 2 {
+		t.Errorf("top file = %+v", m.Files[0])
+	}
+	if len(m.Severities) != 3 || m.Severities[0].Name != "critical" {
+		t.Errorf("severities = %+v", m.Severities)
+	}
+	if len(m.Months) != 1 || m.Months[0].Name != "March 2024" {
+		t.Errorf("months = %+v", m.Months)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	out := t.TempDir()
+	if err := Build(testRecords(), out, Options{Title: "Team Health"}); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	for _, name := range []string{"index.html", "by-date.html", "by-file.html", "by-severity.html", "by-author.html",
+		"style.css", ".nojekyll", "reviews/abcdef1-r1.html", "reviews/1234567-r1.html"} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Errorf("missing %s", name)
+		}
+	}
+
+	index, _ := os.ReadFile(filepath.Join(out, "index.html"))
+	for _, want := range []string{"Team Health", "<svg", `href="reviews/1234567-r1.html"`} {
+		if !strings.Contains(string(index), want) {
+			t.Errorf("index.html missing %q", want)
+		}
+	}
+
+	page, _ := os.ReadFile(filepath.Join(out, "reviews", "abcdef1-r1.html"))
+	if !strings.Contains(string(page), "SQL &lt;injection&gt;") {
+		t.Error("review page does not escape issue messages")
+	}
+	if !strings.Contains(string(page), `href="../style.css"`) {
+		t.Error("review page links are not relative to the site root")
+	}
+}
+
+func TestBuildEmptyHistory(t *testing.T) {
+	out := t.TempDir()
+	if err := Build(nil, out, Options{}); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	index, _ := os.ReadFile(filepath.Join(out, "index.html"))
+	if !strings.Contains(string(index), "No review history found") {
+		t.Error("empty history not reported")
+	}
+}
+
+func TestOpenChartCountsResolvedLater(t *testing.T) {
+	records := testRecords()
+	ws := weeks(records)
+	if len(ws) != 3 {
+		t.Fatalf("weeks = %d, want 3", len(ws))
+	}
+	chart := string(openChart(records))
+	// Week 1: 2 open; week 2: the info issue was resolved; week 3: a new error
+	for _, want := range []string{"2024-03-04: 2 open", "2024-03-11: 1 open", "2024-03-18: 2 open"} {
+		if !strings.Contains(chart, want) {
+			t.Errorf("open chart missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"time"
+)
+
+// layoutTemplate is the page frame shared by all pages
+const layoutTemplate = `{{define "layout"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if .Review}}Review {{.Review.ShortCommit}} · {{end}}{{.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<header>
+<h1><a href="{{.Root}}index.html">{{.Title}}</a></h1>
+<nav>
+<a href="{{.Root}}index.html"{{if eq .Page "index"}} class="active"{{end}}>Overview</a>
+<a href="{{.Root}}by-date.html"{{if eq .Page "by-date"}} class="active"{{end}}>By date</a>
+<a href="{{.Root}}by-file.html"{{if eq .Page "by-file"}} class="active"{{end}}>By file</a>
+<a href="{{.Root}}by-severity.html"{{if eq .Page "by-severity"}} class="active"{{end}}>By severity</a>
+<a href="{{.Root}}by-author.html"{{if eq .Page "by-author"}} class="active"{{end}}>By author</a>
+</nav>
+</header>
+<main>
+{{template "content" .}}
+</main>
+<footer>Generated by GoReview on {{date .Generated}}</footer>
+</body>
+</html>
+{{end}}
+
+{{define "severity"}}<span class="sev sev-{{.}}">{{.}}</span>{{end}}
+
+{{define "status"}}{{if .}}<span class="resolved">resolved</span>{{else}}<span class="open">open</span>{{end}}{{end}}
+
+{{define "reviews-table"}}
+<table>
+<thead><tr><th>Date</th><th>Commit</th><th>Branch</th><th>Author</th><th>Issues</th>{{range severities}}<th>{{.}}</th>{{end}}<th>Open</th></tr></thead>
+<tbody>
+{{- range .}}
+<tr>
+<td>{{date .Date}}</td>
+<td><a href="{{.Page}}"><code>{{.ShortCommit}}</code></a>{{if gt .Round 1}} (round {{.Round}}){{end}}</td>
+<td>{{.Branch}}</td>
+<td>{{.Author}}</td>
+<td>{{len .Issues}}</td>
+{{- $r := .}}{{range severities}}<td>{{index $r.BySeverity .}}</td>{{end}}
+<td>{{.Open}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{end}}`
+
+// pageTemplates holds the "content" template of each page
+var pageTemplates = map[string]string{
+	"index": `{{define "content"}}
+<section class="cards">
+<div class="card"><span class="value">{{len .Reviews}}</span><span class="name">Reviews</span></div>
+<div class="card"><span class="value">{{.TotalIssues}}</span><span class="name">Issues</span></div>
+<div class="card"><span class="value">{{.Open}}</span><span class="name">Open</span></div>
+<div class="card"><span class="value">{{.Resolved}}</span><span class="name">Resolved</span></div>
+</section>
+{{- if .Reviews}}
+<section>
+<h2>Trends</h2>
+<div class="charts">
+<figure>{{.IssuesChart}}<figcaption>Issues found per week{{range severities}} {{template "severity" .}}{{end}}</figcaption></figure>
+<figure>{{.OpenChart}}<figcaption>Open issues at the end of each week</figcaption></figure>
+</div>
+</section>
+<section>
+<h2>Recent reviews</h2>
+{{template "reviews-table" (recent .Reviews 20)}}
+</section>
+{{- else}}
+<p>No review history found. Run some reviews first.</p>
+{{- end}}
+{{end}}`,
+
+	"by-date": `{{define "content"}}
+<h2>Reviews by date</h2>
+{{- range .Months}}
+<h3>{{.Name}}</h3>
+{{template "reviews-table" .Reviews}}
+{{- end}}
+{{end}}`,
+
+	"by-file": `{{define "content"}}
+<h2>Issues by file</h2>
+<table>
+<thead><tr><th>File</th><th>Issues</th><th>Open</th><th>Reviews</th></tr></thead>
+<tbody>
+{{- range .Files}}
+<tr>
+<td><code>{{.Name}}</code></td>
+<td>{{.Issues}}</td>
+<td>{{.Open}}</td>
+<td>{{range .Reviews}}<a href="{{.Page}}"><code>{{.ShortCommit}}</code></a> {{end}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{end}}`,
+
+	"by-severity": `{{define "content"}}
+<h2>Issues by severity</h2>
+{{- range .Severities}}
+<h3>{{template "severity" .Name}} {{len .Issues}}</h3>
+<table>
+<thead><tr><th>Location</th><th>Type</th><th>Message</th><th>Review</th><th>Status</th></tr></thead>
+<tbody>
+{{- range .Issues}}
+<tr>
+<td><code>{{.FilePath}}{{if .Line}}:{{.Line}}{{end}}</code></td>
+<td>{{.IssueType}}</td>
+<td>{{.Message}}</td>
+<td><a href="{{.Review.Page}}"><code>{{.Review.ShortCommit}}</code></a></td>
+<td>{{template "status" .Resolved}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{end}}`,
+
+	"by-author": `{{define "content"}}
+<h2>Issues by author</h2>
+<table>
+<thead><tr><th>Author</th><th>Issues</th><th>Open</th><th>Reviews</th></tr></thead>
+<tbody>
+{{- range .Authors}}
+<tr>
+<td>{{.Name}}</td>
+<td>{{.Issues}}</td>
+<td>{{.Open}}</td>
+<td>{{range .Reviews}}<a href="{{.Page}}"><code>{{.ShortCommit}}</code></a> {{end}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{end}}`,
+
+	"review": `{{define "content"}}
+{{- with .Review}}
+<h2>Review <code>{{.ShortCommit}}</code>{{if gt .Round 1}} (round {{.Round}}){{end}}</h2>
+<dl class="meta">
+<dt>Date</dt><dd>{{date .Date}}</dd>
+<dt>Commit</dt><dd><code>{{.Commit}}</code></dd>
+{{- if .Branch}}<dt>Branch</dt><dd>{{.Branch}}</dd>{{end}}
+{{- if .Author}}<dt>Author</dt><dd>{{.Author}}</dd>{{end}}
+<dt>Issues</dt><dd>{{len .Issues}} ({{.Open}} open)</dd>
+</dl>
+<table>
+<thead><tr><th>Location</th><th>Severity</th><th>Type</th><th>Message</th><th>Status</th></tr></thead>
+<tbody>
+{{- range .Issues}}
+<tr>
+<td><code>{{.FilePath}}{{if .Line}}:{{.Line}}{{end}}</code></td>
+<td>{{template "severity" .Severity}}</td>
+<td>{{.IssueType}}</td>
+<td>{{.Message}}{{if .Suggestion}}<p class="suggestion">{{.Suggestion}}</p>{{end}}</td>
+<td>{{template "status" .Resolved}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{end}}`,
+}
+
+// parseTemplates builds a template set per page, each with the shared layout.
+func parseTemplates() (map[string]*template.Template, error) {
+	funcs := template.FuncMap{
+		"date":       func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+		"severities": func() []string { return severities },
+		"recent": func(reviews []*Review, n int) []*Review {
+			if len(reviews) > n {
+				return reviews[:n]
+			}
+			return reviews
+		},
+	}
+
+	base, err := template.New("layout").Funcs(funcs).Parse(layoutTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing layout template: %w", err)
+	}
+
+	pages := make(map[string]*template.Template, len(pageTemplates))
+	for name, content := range pageTemplates {
+		t, err := template.Must(base.Clone()).Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s template: %w", name, err)
+		}
+		pages[name] = t
+	}
+	return pages, nil
+}
+
+// styleCSS is the site stylesheet
+const styleCSS = `:root { --fg: #1f2328; --muted: #656d76; --border: #d0d7de; --bg: #f6f8fa; --accent: #0969da; }
+* { box-sizing: border-box; }
+body { margin: 0; font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: var(--fg); }
+header { padding: 16px 32px; border-bottom: 1px solid var(--border); background: var(--bg); }
+header h1 { margin: 0 0 8px; font-size: 20px; }
+header h1 a { color: inherit; text-decoration: none; }
+nav a { margin-right: 16px; color: var(--muted); text-decoration: none; }
+nav a.active, nav a:hover { color: var(--accent); }
+main { padding: 16px 32px; max-width: 1200px; }
+footer { padding: 16px 32px; color: var(--muted); font-size: 12px; }
+a { color: var(--accent); }
+table { width: 100%; border-collapse: collapse; margin-bottom: 24px; }
+th, td { padding: 6px 8px; border-bottom: 1px solid var(--border); text-align: left; vertical-align: top; }
+th { background: var(--bg); text-transform: capitalize; }
+.cards { display: flex; gap: 16px; margin: 8px 0 24px; }
+.card { flex: 1; padding: 16px; border: 1px solid var(--border); border-radius: 6px; }
+.card .value { display: block; font-size: 28px; font-weight: 600; }
+.card .name { color: var(--muted); }
+.charts { display: grid; grid-template-columns: repeat(auto-fit, minmax(360px, 1fr)); gap: 16px; }
+figure { margin: 0; padding: 8px; border: 1px solid var(--border); border-radius: 6px; }
+figcaption { color: var(--muted); font-size: 12px; }
+.chart { width: 100%; height: auto; }
+.chart .axis { stroke: var(--border); }
+.chart .label { font-size: 10px; fill: var(--muted); }
+.sev { display: inline-block; padding: 0 6px; border-radius: 10px; color: #fff; font-size: 12px; }
+.sev-critical { background: #cf222e; }
+.sev-error { background: #e16f24; }
+.sev-warning { background: #d4a72c; }
+.sev-info { background: #218bff; }
+.open { color: #cf222e; }
+.resolved { color: #1a7f37; }
+.suggestion { margin: 4px 0 0; color: var(--muted); }
+.meta { display: grid; grid-template-columns: max-content 1fr; gap: 4px 16px; }
+.meta dt { color: var(--muted); }
+.meta dd { margin: 0; }
+`
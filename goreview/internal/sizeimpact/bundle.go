@@ -0,0 +1,92 @@
+package sizeimpact
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// analyzerChunk is an entry of a webpack-bundle-analyzer JSON report
+// (analyzerMode: "json").
+type analyzerChunk struct {
+	Label      string `json:"label"`
+	StatSize   int64  `json:"statSize"`
+	ParsedSize int64  `json:"parsedSize"`
+}
+
+// webpackStats is the part of a webpack stats file (--json) listing the
+// emitted assets.
+type webpackStats struct {
+	Assets []struct {
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+	} `json:"assets"`
+}
+
+// ParseBundleReport returns the size of each chunk or asset in a
+// webpack-bundle-analyzer report or a webpack stats file.
+func ParseBundleReport(data []byte) (map[string]int64, error) {
+	var chunks []analyzerChunk
+	if err := json.Unmarshal(data, &chunks); err == nil {
+		sizes := make(map[string]int64, len(chunks))
+		for _, c := range chunks {
+			size := c.ParsedSize
+			if size == 0 {
+				size = c.StatSize
+			}
+			sizes[c.Label] += size
+		}
+		return sizes, nil
+	}
+
+	var stats webpackStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("parsing bundle report: %w", err)
+	}
+	if stats.Assets == nil {
+		return nil, errors.New("parsing bundle report: no chunks or assets")
+	}
+	sizes := make(map[string]int64, len(stats.Assets))
+	for _, a := range stats.Assets {
+		sizes[a.Name] += a.Size
+	}
+	return sizes, nil
+}
+
+// MeasureBundle compares the total size in a bundle report with its
+// baseline, both relative to root. It returns nil without an error when
+// either file is missing, since bundle reports are optional build outputs.
+func MeasureBundle(root, report, baseline string) (*Delta, error) {
+	after, err := readBundleTotal(filepath.Join(root, report))
+	if err != nil || after < 0 {
+		return nil, err
+	}
+	before, err := readBundleTotal(filepath.Join(root, baseline))
+	if err != nil || before < 0 {
+		return nil, err
+	}
+	return &Delta{Kind: KindBundle, Target: filepath.ToSlash(report), Before: before, After: after}, nil
+}
+
+// readBundleTotal returns the total size in a report, or -1 when the file
+// doesn't exist.
+func readBundleTotal(path string) (int64, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Path comes from the configuration
+	if errors.Is(err, os.ErrNotExist) {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	sizes, err := ParseBundleReport(data)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	return total, nil
+}
@@ -0,0 +1,255 @@
+package sizeimpact
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Runner runs a command in a directory and returns its standard output.
+type Runner func(ctx context.Context, dir, name string, args ...string) ([]byte, error)
+
+// ExecRunner runs commands with os/exec, including stderr in errors.
+func ExecRunner(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 - git and go with controlled args
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// GoOptions configures the measurement of Go binaries.
+type GoOptions struct {
+	// Root is the repository root
+	Root string
+	// BeforeRef is the git ref the change is compared against
+	BeforeRef string
+	// AfterRef is the git ref with the change, "" for the working tree
+	AfterRef string
+	// Changed lists the changed files, relative to Root
+	Changed []string
+	// Run runs git and go; nil uses ExecRunner
+	Run Runner
+}
+
+// moduleChanges holds the changed package directories of a Go module,
+// relative to the module; all is set when go.mod or go.sum changed.
+type moduleChanges struct {
+	dirs map[string]bool
+	all  bool
+}
+
+// mainPackage is a main package of a module.
+type mainPackage struct {
+	importPath string
+	dir        string // Relative to the module
+}
+
+// MeasureGo builds the main packages affected by the changed files before
+// and after the change and returns their sizes. Each ref is checked out in
+// a temporary git worktree; the working tree is used as is.
+func MeasureGo(ctx context.Context, opts GoOptions) ([]Delta, error) {
+	if opts.Run == nil {
+		opts.Run = ExecRunner
+	}
+	modules := changedModules(opts.Root, opts.Changed)
+	if len(modules) == 0 {
+		return nil, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "goreview-size-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	beforeTree, err := addWorktree(ctx, opts, filepath.Join(tmp, "before"), opts.BeforeRef)
+	if err != nil {
+		return nil, err
+	}
+	defer removeWorktree(opts, beforeTree)
+	afterTree := opts.Root
+	if opts.AfterRef != "" {
+		if afterTree, err = addWorktree(ctx, opts, filepath.Join(tmp, "after"), opts.AfterRef); err != nil {
+			return nil, err
+		}
+		defer removeWorktree(opts, afterTree)
+	}
+
+	b := &builder{run: opts.Run, out: tmp}
+	var deltas []Delta
+	for _, module := range sortedModules(modules) {
+		mains, err := affectedMains(ctx, opts.Run, filepath.Join(afterTree, module), modules[module])
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range mains {
+			d := Delta{Kind: KindBinary, Target: path.Join(module, m.dir)}
+			if d.After, err = b.size(ctx, filepath.Join(afterTree, module), m); err != nil {
+				return nil, err
+			}
+			// A main package the change adds has nothing to compare against
+			if _, statErr := os.Stat(filepath.Join(beforeTree, module, m.dir)); statErr == nil {
+				if d.Before, err = b.size(ctx, filepath.Join(beforeTree, module), m); err != nil {
+					return nil, err
+				}
+			}
+			deltas = append(deltas, d)
+		}
+	}
+	return deltas, nil
+}
+
+// changedModules groups the changed Go files by the module they belong to,
+// keyed by the module directory relative to root ("." for the root).
+func changedModules(root string, changed []string) map[string]*moduleChanges {
+	modules := make(map[string]*moduleChanges)
+	for _, f := range changed {
+		f = filepath.ToSlash(f)
+		base := path.Base(f)
+		if path.Ext(f) != ".go" && base != "go.mod" && base != "go.sum" {
+			continue
+		}
+		module, ok := findModule(root, path.Dir(f))
+		if !ok {
+			continue
+		}
+		mc := modules[module]
+		if mc == nil {
+			mc = &moduleChanges{dirs: make(map[string]bool)}
+			modules[module] = mc
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(path.Dir(f), module), "/")
+		if module == "." {
+			rel = path.Dir(f)
+		}
+		if rel == "" {
+			rel = "."
+		}
+		if base == "go.mod" || base == "go.sum" {
+			mc.all = true
+		}
+		mc.dirs[rel] = true
+	}
+	return modules
+}
+
+// findModule returns the nearest directory holding a go.mod, from dir up
+// to the repository root.
+func findModule(root, dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(dir), "go.mod")); err == nil {
+			return dir, true
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return "", false
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+func sortedModules(modules map[string]*moduleChanges) []string {
+	keys := make([]string, 0, len(modules))
+	for k := range modules {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// affectedMains lists the main packages of the module in moduleDir that are
+// in, or depend on, a changed package.
+func affectedMains(ctx context.Context, run Runner, moduleDir string, changes *moduleChanges) ([]mainPackage, error) {
+	out, err := run(ctx, moduleDir, "go", "list", "-e", "-f", "{{.Name}}\t{{.ImportPath}}\t{{.Dir}}\t{{join .Deps \" \"}}", "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	type pkg struct {
+		name, importPath, dir string
+		deps                  []string
+	}
+	var pkgs []pkg
+	dirs := make(map[string]string) // Import path -> directory relative to the module
+	absModule, _ := filepath.Abs(moduleDir)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) < 3 {
+			continue
+		}
+		rel, err := filepath.Rel(absModule, fields[2])
+		if err != nil {
+			rel = fields[2]
+		}
+		p := pkg{name: fields[0], importPath: fields[1], dir: filepath.ToSlash(rel)}
+		if len(fields) == 4 {
+			p.deps = strings.Fields(fields[3])
+		}
+		pkgs = append(pkgs, p)
+		dirs[p.importPath] = p.dir
+	}
+
+	var mains []mainPackage
+	for _, p := range pkgs {
+		if p.name != "main" {
+			continue
+		}
+		affected := changes.all || changes.dirs[p.dir]
+		for _, dep := range p.deps {
+			if dir, ok := dirs[dep]; ok && changes.dirs[dir] {
+				affected = true
+				break
+			}
+		}
+		if affected {
+			mains = append(mains, mainPackage{importPath: p.importPath, dir: p.dir})
+		}
+	}
+	return mains, nil
+}
+
+// builder builds binaries into numbered files of a temporary directory.
+type builder struct {
+	run Runner
+	out string
+	n   int
+}
+
+func (b *builder) size(ctx context.Context, moduleDir string, m mainPackage) (int64, error) {
+	b.n++
+	output := filepath.Join(b.out, fmt.Sprintf("bin-%d", b.n))
+	if _, err := b.run(ctx, moduleDir, "go", "build", "-trimpath", "-o", output, "./"+m.dir); err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(output)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func addWorktree(ctx context.Context, opts GoOptions, dir, ref string) (string, error) {
+	if ref == "" {
+		return "", errors.New("no git ref to compare against")
+	}
+	if _, err := opts.Run(ctx, opts.Root, "git", "worktree", "add", "--detach", dir, ref); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// removeWorktree removes a temporary worktree, even after cancellation.
+func removeWorktree(opts GoOptions, dir string) {
+	_, _ = opts.Run(context.Background(), opts.Root, "git", "worktree", "remove", "--force", dir)
+}
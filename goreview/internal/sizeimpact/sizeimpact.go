@@ -0,0 +1,100 @@
+// Package sizeimpact estimates how much a change grows its build outputs:
+// the Go binaries affected by the changed packages, built before and after
+// the change, and JavaScript bundles described by bundle analyzer reports.
+// Growth above a threshold is reported as a review issue.
+package sizeimpact
+
+import (
+	"fmt"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+// Kinds of measured outputs
+const (
+	KindBinary = "binary"
+	KindBundle = "bundle"
+)
+
+// Delta is the size of a build output before and after the change.
+type Delta struct {
+	Kind string `json:"kind"`
+	// Target is the main package directory or the bundle report path,
+	// relative to the repository root
+	Target string `json:"target"`
+	// Before is 0 for outputs the change adds
+	Before int64 `json:"before"`
+	After  int64 `json:"after"`
+}
+
+// Growth is the size the change adds, negative when it shrinks the output.
+func (d Delta) Growth() int64 {
+	return d.After - d.Before
+}
+
+// Issue describes the growth as a review issue.
+func (d Delta) Issue() providers.Issue {
+	what := "the binary " + d.Target
+	if d.Kind == KindBundle {
+		what = "the JS bundle"
+	}
+	message := fmt.Sprintf("This change adds %s to %s (%s → %s)", FormatBytes(d.Growth()), what, FormatBytes(d.Before), FormatBytes(d.After))
+	if d.Before == 0 && d.Kind == KindBinary {
+		message = fmt.Sprintf("This change adds the binary %s (%s)", d.Target, FormatBytes(d.After))
+	}
+	return providers.Issue{
+		ID:         fmt.Sprintf("size/%s:%s", d.Kind, d.Target),
+		Type:       providers.IssueTypePerformance,
+		Severity:   providers.SeverityWarning,
+		Message:    message,
+		Suggestion: "Check for new dependencies, embedded assets or generated code that could be avoided or loaded lazily",
+		RuleID:     "size/" + d.Kind,
+	}
+}
+
+// FormatBytes renders a size with a binary unit, like "2.3 MB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < 0 {
+		return "-" + FormatBytes(-n)
+	}
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, exp := float64(n)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "KMGT"[exp])
+}
+
+// Apply adds an issue to the review result for each delta that grows by at
+// least minGrowth bytes, under the delta's target.
+func Apply(result *review.Result, deltas []Delta, minGrowth int64) int {
+	added := 0
+	for _, d := range deltas {
+		if d.Growth() <= 0 || d.Growth() < minGrowth {
+			continue
+		}
+		idx := -1
+		for i := range result.Files {
+			if result.Files[i].File == d.Target && result.Files[i].Response != nil {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			result.Files = append(result.Files, review.FileResult{
+				File:     d.Target,
+				Response: &providers.ReviewResponse{Summary: "Build size impact"},
+			})
+			idx = len(result.Files) - 1
+		}
+		result.Files[idx].Response.Issues = append(result.Files[idx].Response.Issues, d.Issue())
+		result.TotalIssues++
+		added++
+	}
+	return added
+}
@@ -0,0 +1,185 @@
+package sizeimpact
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/review"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{512, "512 B"},
+		{2048, "2.0 KB"},
+		{2411725, "2.3 MB"},
+		{-1536, "-1.5 KB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	result := &review.Result{Files: []review.FileResult{
+		{File: "cmd/app", Response: &providers.ReviewResponse{}},
+	}}
+	deltas := []Delta{
+		{Kind: KindBinary, Target: "cmd/app", Before: 10 << 20, After: 12 << 20},
+		{Kind: KindBinary, Target: "cmd/tool", Before: 5 << 20, After: 5<<20 + 10},
+		{Kind: KindBinary, Target: "cmd/new", After: 3 << 20},
+		{Kind: KindBundle, Target: "dist/report.json", Before: 4 << 20, After: 3 << 20},
+	}
+	if added := Apply(result, deltas, 100<<10); added != 2 {
+		t.Fatalf("Apply() added %d issues, want 2", added)
+	}
+	if result.TotalIssues != 2 || len(result.Files) != 2 {
+		t.Fatalf("result = %d issues in %d files, want 2 in 2", result.TotalIssues, len(result.Files))
+	}
+
+	issue := result.Files[0].Response.Issues[0]
+	if issue.RuleID != "size/binary" || issue.Severity != providers.SeverityWarning {
+		t.Errorf("issue = %+v", issue)
+	}
+	if want := "This change adds 2.0 MB to the binary cmd/app (10.0 MB → 12.0 MB)"; issue.Message != want {
+		t.Errorf("message = %q, want %q", issue.Message, want)
+	}
+	if msg := result.Files[1].Response.Issues[0].Message; !strings.Contains(msg, "adds the binary cmd/new (3.0 MB)") {
+		t.Errorf("new binary message = %q", msg)
+	}
+}
+
+func TestParseBundleReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]int64
+		wantErr bool
+	}{
+		{
+			name: "bundle analyzer",
+			data: `[{"label":"main.js","statSize":900,"parsedSize":500},{"label":"vendor.js","statSize":300}]`,
+			want: map[string]int64{"main.js": 500, "vendor.js": 300},
+		},
+		{
+			name: "webpack stats",
+			data: `{"assets":[{"name":"main.js","size":700},{"name":"main.css","size":50}]}`,
+			want: map[string]int64{"main.js": 700, "main.css": 50},
+		},
+		{name: "unrelated json", data: `{"version":1}`, wantErr: true},
+		{name: "invalid", data: `not json`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBundleReport([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBundleReport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("size[%s] = %d, want %d", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestMeasureBundle(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("report.json", `[{"label":"a.js","parsedSize":3000},{"label":"b.js","parsedSize":1000}]`)
+
+	// Missing baseline: nothing to compare
+	if d, err := MeasureBundle(root, "report.json", "base.json"); d != nil || err != nil {
+		t.Errorf("MeasureBundle() = %+v, %v; want nil without baseline", d, err)
+	}
+
+	write("base.json", `[{"label":"a.js","parsedSize":1500}]`)
+	d, err := MeasureBundle(root, "report.json", "base.json")
+	if err != nil || d == nil {
+		t.Fatalf("MeasureBundle() = %+v, %v", d, err)
+	}
+	if d.Before != 1500 || d.After != 4000 || d.Target != "report.json" {
+		t.Errorf("delta = %+v", d)
+	}
+}
+
+func TestMeasureGo(t *testing.T) {
+	// A module in a subdirectory with two binaries; only app uses lib
+	root := t.TempDir()
+	for _, dir := range []string{"svc/cmd/app", "svc/cmd/tool", "svc/lib"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "svc", "go.mod"), []byte("module example.com/svc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	run := func(_ context.Context, dir, name string, args ...string) ([]byte, error) {
+		calls = append(calls, name+" "+args[0])
+		switch {
+		case name == "git" && args[1] == "add":
+			// The base has the app and the library, but not the tool
+			for _, d := range []string{"svc/cmd/app", "svc/lib"} {
+				if err := os.MkdirAll(filepath.Join(args[3], d), 0o755); err != nil {
+					return nil, err
+				}
+			}
+		case name == "go" && args[0] == "list":
+			return []byte("main\texample.com/svc/cmd/app\t" + filepath.Join(dir, "cmd/app") + "\texample.com/svc/lib fmt\n" +
+				"main\texample.com/svc/cmd/tool\t" + filepath.Join(dir, "cmd/tool") + "\tfmt\n" +
+				"lib\texample.com/svc/lib\t" + filepath.Join(dir, "lib") + "\tstrings\n"), nil
+		case name == "go" && args[0] == "build":
+			size := 2000
+			if strings.HasPrefix(dir, root) {
+				size = 3000 // The working tree is bigger
+			}
+			return nil, os.WriteFile(args[3], make([]byte, size), 0o600)
+		}
+		return nil, nil
+	}
+
+	deltas, err := MeasureGo(context.Background(), GoOptions{
+		Root: root, BeforeRef: "HEAD", Changed: []string{"svc/lib/lib.go", "README.md"}, Run: run,
+	})
+	if err != nil {
+		t.Fatalf("MeasureGo() error = %v", err)
+	}
+	if len(deltas) != 1 {
+		t.Fatalf("deltas = %+v, want only the app", deltas)
+	}
+	if d := deltas[0]; d.Target != "svc/cmd/app" || d.Before != 2000 || d.After != 3000 {
+		t.Errorf("delta = %+v", d)
+	}
+	if last := calls[len(calls)-1]; last != "git worktree" {
+		t.Errorf("last call = %q, want the worktree removed", last)
+	}
+
+	// go.mod changes affect every binary; the tool is new
+	deltas, err = MeasureGo(context.Background(), GoOptions{Root: root, BeforeRef: "HEAD", Changed: []string{"svc/go.mod"}, Run: run})
+	if err != nil || len(deltas) != 2 {
+		t.Fatalf("MeasureGo() = %+v, %v; want both binaries", deltas, err)
+	}
+	if d := deltas[1]; d.Target != "svc/cmd/tool" || d.Before != 0 {
+		t.Errorf("new binary delta = %+v", d)
+	}
+
+	// Changes outside Go modules build nothing
+	if deltas, err := MeasureGo(context.Background(), GoOptions{Root: root, Changed: []string{"docs/a.md"}, Run: run}); deltas != nil || err != nil {
+		t.Errorf("MeasureGo() = %+v, %v; want nothing", deltas, err)
+	}
+}
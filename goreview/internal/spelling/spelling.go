@@ -0,0 +1,226 @@
+// Package spelling is a local spell and terminology checker for comments,
+// identifiers and documentation. It flags common misspellings from a
+// built-in list and banned terms from the configuration, without calling
+// a model.
+package spelling
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Kind tells what a finding is about.
+type Kind string
+
+const (
+	// KindMisspelling is a word from the built-in misspellings list
+	KindMisspelling Kind = "misspelling"
+	// KindTerm is a banned term from the configuration
+	KindTerm Kind = "term"
+)
+
+// Finding is a misspelled word or banned term found in a text.
+type Finding struct {
+	Kind Kind
+	// Word is the text as found
+	Word string
+	// Suggestion is the replacement, empty for banned terms without one
+	Suggestion string
+	// Column is the byte offset of Word in the checked text
+	Column int
+}
+
+// Checker finds misspellings and banned terms.
+type Checker struct {
+	dictionary map[string]bool
+	terms      []term
+}
+
+type term struct {
+	banned    string
+	preferred string
+	re        *regexp.Regexp
+}
+
+var (
+	wordPattern       = regexp.MustCompile(`[A-Za-z]+(?:'[A-Za-z]+)?`)
+	identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// New creates a checker. Words in dictionary are never reported; terms maps
+// banned terms (words or phrases) to their preferred replacement.
+func New(dictionary []string, terms map[string]string) *Checker {
+	c := &Checker{dictionary: make(map[string]bool, len(dictionary))}
+	for _, w := range dictionary {
+		c.dictionary[strings.ToLower(strings.TrimSpace(w))] = true
+	}
+
+	banned := make([]string, 0, len(terms))
+	for t := range terms {
+		banned = append(banned, t)
+	}
+	// Longest first, so "master branch" wins over "master"
+	sort.Slice(banned, func(i, j int) bool {
+		if len(banned[i]) != len(banned[j]) {
+			return len(banned[i]) > len(banned[j])
+		}
+		return banned[i] < banned[j]
+	})
+	for _, b := range banned {
+		words := strings.Fields(b)
+		if len(words) == 0 {
+			continue
+		}
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		c.terms = append(c.terms, term{
+			banned:    strings.ToLower(b),
+			preferred: terms[b],
+			re:        regexp.MustCompile(`(?i)\b` + strings.Join(words, `\s+`) + `\b`),
+		})
+	}
+	return c
+}
+
+// CheckText checks prose, such as a comment or a markdown line.
+func (c *Checker) CheckText(text string) []Finding {
+	var findings []Finding
+	covered := make(map[int]bool)
+	for _, t := range c.terms {
+		for _, loc := range t.re.FindAllStringIndex(text, -1) {
+			if covered[loc[0]] {
+				continue
+			}
+			covered[loc[0]] = true
+			findings = append(findings, Finding{Kind: KindTerm, Word: text[loc[0]:loc[1]], Suggestion: t.preferred, Column: loc[0]})
+		}
+	}
+
+	for _, loc := range wordPattern.FindAllStringIndex(text, -1) {
+		if covered[loc[0]] {
+			continue
+		}
+		word := text[loc[0]:loc[1]]
+		if f, ok := c.checkWord(word); ok {
+			f.Column = loc[0]
+			findings = append(findings, f)
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Column < findings[j].Column })
+	return findings
+}
+
+// CheckIdentifiers checks the words of the identifiers in a line of code,
+// splitting camelCase and snake_case names. String literals are skipped.
+func (c *Checker) CheckIdentifiers(code string) []Finding {
+	code = stringLiterals.ReplaceAllStringFunc(code, func(s string) string { return strings.Repeat(" ", len(s)) })
+
+	var findings []Finding
+	for _, loc := range identifierPattern.FindAllStringIndex(code, -1) {
+		ident := code[loc[0]:loc[1]]
+		for _, part := range splitIdentifier(ident) {
+			if f, ok := c.checkWord(part.word); ok {
+				f.Column = loc[0] + part.offset
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings
+}
+
+// checkWord checks a single word against the dictionary, the single-word
+// banned terms and the misspellings list.
+func (c *Checker) checkWord(word string) (Finding, bool) {
+	lower := strings.ToLower(word)
+	if c.dictionary[lower] {
+		return Finding{}, false
+	}
+	for _, t := range c.terms {
+		if t.banned == lower {
+			return Finding{Kind: KindTerm, Word: word, Suggestion: t.preferred}, true
+		}
+	}
+	if fix, ok := misspellings[lower]; ok {
+		return Finding{Kind: KindMisspelling, Word: word, Suggestion: matchCase(word, fix)}, true
+	}
+	return Finding{}, false
+}
+
+// Replace returns text with the finding replaced by its suggestion.
+func Replace(text string, f Finding) string {
+	end := f.Column + len(f.Word)
+	if f.Suggestion == "" || end > len(text) || text[f.Column:end] != f.Word {
+		return text
+	}
+	return text[:f.Column] + f.Suggestion + text[end:]
+}
+
+// matchCase applies the capitalization of word to its replacement.
+func matchCase(word, fix string) string {
+	switch {
+	case len(word) > 1 && strings.ToUpper(word) == word:
+		return strings.ToUpper(fix)
+	case unicode.IsUpper(rune(word[0])):
+		return strings.ToUpper(fix[:1]) + fix[1:]
+	default:
+		return fix
+	}
+}
+
+type identPart struct {
+	word   string
+	offset int
+}
+
+// splitIdentifier splits an identifier into its words: "parseHTTPResponce"
+// gives "parse", "HTTP" and "Responce".
+func splitIdentifier(ident string) []identPart {
+	var parts []identPart
+	start := -1
+	flush := func(end int) {
+		if start >= 0 && end > start {
+			parts = append(parts, identPart{word: ident[start:end], offset: start})
+		}
+		start = -1
+	}
+
+	for i := 0; i < len(ident); i++ {
+		ch := rune(ident[i])
+		switch {
+		case !unicode.IsLetter(ch):
+			flush(i)
+		case start < 0:
+			start = i
+		case unicode.IsUpper(ch) && !unicode.IsUpper(rune(ident[i-1])):
+			// fooBar: new word at B
+			flush(i)
+			start = i
+		case unicode.IsUpper(ch) && i+1 < len(ident) && unicode.IsLower(rune(ident[i+1])) && unicode.IsUpper(rune(ident[i-1])):
+			// HTTPResponse: new word at R
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(ident))
+	return parts
+}
+
+var stringLiterals = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|'(?:[^'\\\\]|\\\\.)*'|`[^`]*`")
+
+// SplitComment splits a line of code into its code and the text of its
+// trailing line comment, ignoring markers inside string literals.
+func SplitComment(line, marker string) (code, comment string, commentColumn int) {
+	if marker == "" {
+		return line, "", -1
+	}
+	masked := stringLiterals.ReplaceAllStringFunc(line, func(s string) string { return strings.Repeat(" ", len(s)) })
+	i := strings.Index(masked, marker)
+	if i < 0 {
+		return line, "", -1
+	}
+	return line[:i], line[i+len(marker):], i + len(marker)
+}
@@ -0,0 +1,72 @@
+package spelling
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckText(t *testing.T) {
+	c := New([]string{"teh"}, map[string]string{"whitelist": "allowlist", "master branch": "main branch", "simply": ""})
+
+	tests := []struct {
+		name string
+		text string
+		want []Finding
+	}{
+		{"clean", "returns the value", nil},
+		{"misspelling", "we recieve data", []Finding{{Kind: KindMisspelling, Word: "recieve", Suggestion: "receive", Column: 3}}},
+		{"keeps case", "Seperate it", []Finding{{Kind: KindMisspelling, Word: "Seperate", Suggestion: "Separate", Column: 0}}},
+		{"dictionary word", "teh project word", nil},
+		{"banned term", "add to the Whitelist", []Finding{{Kind: KindTerm, Word: "Whitelist", Suggestion: "allowlist", Column: 11}}},
+		{"phrase", "merge to master  branch", []Finding{{Kind: KindTerm, Word: "master  branch", Suggestion: "main branch", Column: 9}}},
+		{"term without replacement", "simply call it", []Finding{{Kind: KindTerm, Word: "simply", Column: 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.CheckText(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckText(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckIdentifiers(t *testing.T) {
+	c := New(nil, map[string]string{"blacklist": "denylist"})
+
+	got := c.CheckIdentifiers(`func parseHTTPResponce(ip_blacklist []string) string { return "recieve" }`)
+	want := []Finding{
+		{Kind: KindMisspelling, Word: "Responce", Suggestion: "Response", Column: 14},
+		{Kind: KindTerm, Word: "blacklist", Suggestion: "denylist", Column: 26},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckIdentifiers() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSplitComment(t *testing.T) {
+	tests := []struct {
+		line, marker  string
+		code, comment string
+	}{
+		{`x := 1 // set x`, "//", `x := 1 `, " set x"},
+		{`url := "http://example.com" // home`, "//", `url := "http://example.com" `, " home"},
+		{`name = "a#b"`, "#", `name = "a#b"`, ""},
+		{`plain text`, "", `plain text`, ""},
+	}
+	for _, tt := range tests {
+		code, comment, _ := SplitComment(tt.line, tt.marker)
+		if code != tt.code || comment != tt.comment {
+			t.Errorf("SplitComment(%q) = %q, %q, want %q, %q", tt.line, code, comment, tt.code, tt.comment)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	f := Finding{Word: "recieve", Suggestion: "receive", Column: 3}
+	if got := Replace("// recieve it", f); got != "// receive it" {
+		t.Errorf("Replace() = %q", got)
+	}
+	if got := Replace("// other text", f); got != "// other text" {
+		t.Errorf("Replace() with stale finding = %q", got)
+	}
+}
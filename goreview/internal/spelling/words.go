@@ -0,0 +1,166 @@
+package spelling
+
+// misspellings maps common English misspellings to their correction. The
+// list favors words found in code comments and documentation, and only
+// holds words that are never valid spellings, so it has no false positives.
+var misspellings = map[string]string{
+	"accesible":        "accessible",
+	"accomodate":       "accommodate",
+	"accross":          "across",
+	"acheive":          "achieve",
+	"adress":           "address",
+	"agressive":        "aggressive",
+	"algorith":         "algorithm",
+	"algoritm":         "algorithm",
+	"allready":         "already",
+	"alot":             "a lot",
+	"alredy":           "already",
+	"amoung":           "among",
+	"apparant":         "apparent",
+	"appearence":       "appearance",
+	"arguement":        "argument",
+	"asynchonous":      "asynchronous",
+	"asyncronous":      "asynchronous",
+	"atleast":          "at least",
+	"attribtue":        "attribute",
+	"authentification": "authentication",
+	"availabe":         "available",
+	"availible":        "available",
+	"becasue":          "because",
+	"becuase":          "because",
+	"beggining":        "beginning",
+	"begining":         "beginning",
+	"beleive":          "believe",
+	"boundry":          "boundary",
+	"buisness":         "business",
+	"calender":         "calendar",
+	"capabilites":      "capabilities",
+	"charachter":       "character",
+	"charater":         "character",
+	"choosen":          "chosen",
+	"commited":         "committed",
+	"commiting":        "committing",
+	"comparision":      "comparison",
+	"compatability":    "compatibility",
+	"compatable":       "compatible",
+	"compatiblity":     "compatibility",
+	"completly":        "completely",
+	"concurent":        "concurrent",
+	"configuation":     "configuration",
+	"conjuction":       "conjunction",
+	"connnection":      "connection",
+	"consistant":       "consistent",
+	"containg":         "containing",
+	"continous":        "continuous",
+	"corresponing":     "corresponding",
+	"curent":           "current",
+	"currenty":         "currently",
+	"defualt":          "default",
+	"definately":       "definitely",
+	"definitly":        "definitely",
+	"delimeter":        "delimiter",
+	"dependancy":       "dependency",
+	"depricated":       "deprecated",
+	"descripton":       "description",
+	"destory":          "destroy",
+	"diffrent":         "different",
+	"dissapear":        "disappear",
+	"efficent":         "efficient",
+	"embarassing":      "embarrassing",
+	"enviroment":       "environment",
+	"enviornment":      "environment",
+	"equivalant":       "equivalent",
+	"existance":        "existence",
+	"explicitely":      "explicitly",
+	"facilitiy":        "facility",
+	"fucntion":         "function",
+	"funtion":          "function",
+	"garantee":         "guarantee",
+	"guarentee":        "guarantee",
+	"happend":          "happened",
+	"heirarchy":        "hierarchy",
+	"identifer":        "identifier",
+	"immediatly":       "immediately",
+	"implemenation":    "implementation",
+	"implmentation":    "implementation",
+	"incomming":        "incoming",
+	"independant":      "independent",
+	"indentifier":      "identifier",
+	"infomation":       "information",
+	"initalize":        "initialize",
+	"intial":           "initial",
+	"intialize":        "initialize",
+	"invokation":       "invocation",
+	"lenght":           "length",
+	"libary":           "library",
+	"maintainance":     "maintenance",
+	"maintenence":      "maintenance",
+	"managment":        "management",
+	"mesage":           "message",
+	"messsage":         "message",
+	"millenium":        "millennium",
+	"neccessary":       "necessary",
+	"necesary":         "necessary",
+	"occured":          "occurred",
+	"occurence":        "occurrence",
+	"occuring":         "occurring",
+	"ommited":          "omitted",
+	"optinal":          "optional",
+	"overriden":        "overridden",
+	"paramter":         "parameter",
+	"parrallel":        "parallel",
+	"paramters":        "parameters",
+	"particuarly":      "particularly",
+	"performace":       "performance",
+	"persistant":       "persistent",
+	"posible":          "possible",
+	"preceeding":       "preceding",
+	"prefered":         "preferred",
+	"presense":         "presence",
+	"priviledge":       "privilege",
+	"privilige":        "privilege",
+	"proccess":         "process",
+	"propogate":        "propagate",
+	"provde":           "provide",
+	"reciever":         "receiver",
+	"recieve":          "receive",
+	"recieved":         "received",
+	"recursivly":       "recursively",
+	"refered":          "referred",
+	"refering":         "referring",
+	"relevent":         "relevant",
+	"remaing":          "remaining",
+	"repositry":        "repository",
+	"reponse":          "response",
+	"resouce":          "resource",
+	"responce":         "response",
+	"retreive":         "retrieve",
+	"retrive":          "retrieve",
+	"seperate":         "separate",
+	"seperated":        "separated",
+	"seperator":        "separator",
+	"sequencial":       "sequential",
+	"similiar":         "similar",
+	"specifc":          "specific",
+	"specifed":         "specified",
+	"succesful":        "successful",
+	"successfull":      "successful",
+	"sucess":           "success",
+	"supress":          "suppress",
+	"syncronous":       "synchronous",
+	"targetted":        "targeted",
+	"teh":              "the",
+	"threshhold":       "threshold",
+	"transfered":       "transferred",
+	"truely":           "truly",
+	"unecessary":       "unnecessary",
+	"unneccessary":     "unnecessary",
+	"untill":           "until",
+	"usefull":          "useful",
+	"usuall":           "usual",
+	"vaild":            "valid",
+	"varaible":         "variable",
+	"verison":          "version",
+	"wich":             "which",
+	"writting":         "writing",
+}
@@ -0,0 +1,217 @@
+// Package supportbundle builds the zip attached to bug reports: the
+// configuration, version and environment details, logs and the manifest of
+// a failing run, with secrets redacted before anything is written.
+package supportbundle
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/JNZader/goreview/goreview/internal/privacy"
+)
+
+// sensitiveKey matches configuration keys and environment variables holding
+// credentials; their string values are masked whatever they contain.
+var sensitiveKey = regexp.MustCompile(`(?i)(api[_-]?key|access[_-]?key|private[_-]?key|^key$|token|secret|password|passwd|credential|webhook|dsn|^auth|_auth)`)
+
+// Bundle collects redacted files for the zip.
+type Bundle struct {
+	redactor *privacy.Redactor
+	created  time.Time
+	files    []entry
+}
+
+type entry struct {
+	name string
+	data []byte
+}
+
+// New returns an empty bundle redacting with redactor.
+func New(redactor *privacy.Redactor) *Bundle {
+	return &Bundle{redactor: redactor, created: time.Now().UTC()}
+}
+
+// Add adds a text file, redacted.
+func (b *Bundle) Add(name string, data []byte) {
+	b.files = append(b.files, entry{name: name, data: []byte(b.redactor.Redact(string(data)))})
+}
+
+// AddJSON adds v as an indented JSON file, redacted.
+func (b *Bundle) AddJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", name, err)
+	}
+	b.Add(name, append(data, '\n'))
+	return nil
+}
+
+// AddYAML adds a YAML document with the values of sensitive keys masked.
+// Text that doesn't parse as YAML is only redacted.
+func (b *Bundle) AddYAML(name string, data []byte) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err == nil {
+		maskNode(&doc)
+		if masked, err := yaml.Marshal(&doc); err == nil {
+			data = masked
+		}
+	}
+	b.Add(name, data)
+}
+
+// AddFileTail adds the last maxLines lines of a file, redacted.
+func (b *Bundle) AddFileTail(name, path string, maxLines int) error {
+	f, err := os.Open(path) //nolint:gosec // Files named on the command line
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > maxLines {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	b.Add(name, []byte(strings.Join(lines, "\n")+"\n"))
+	return nil
+}
+
+// Names returns the names of the files in the bundle.
+func (b *Bundle) Names() []string {
+	names := make([]string, len(b.files))
+	for i, f := range b.files {
+		names[i] = f.name
+	}
+	return names
+}
+
+// Write writes the bundle as a zip archive.
+func (b *Bundle) Write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, f := range b.files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: b.created})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// maskNode masks the scalar values of sensitive keys in a YAML tree.
+func maskNode(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if value.Kind == yaml.ScalarNode && value.Tag == "!!str" && value.Value != "" && sensitiveKey.MatchString(key.Value) {
+				value.Value = privacy.Mask
+				value.Style = 0
+			}
+		}
+	}
+	for _, c := range n.Content {
+		maskNode(c)
+	}
+}
+
+// Environment describes the machine and build goreview runs on.
+type Environment struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	CPUs      int    `json:"cpus"`
+	// Git is the output of "git --version", empty without git
+	Git string `json:"git,omitempty"`
+	// CI names the CI system, when running in one
+	CI string `json:"ci,omitempty"`
+	// ConfigFile is the configuration file used, if any
+	ConfigFile string `json:"config_file,omitempty"`
+	Profile    string `json:"profile,omitempty"`
+	// Variables are the environment variables that affect goreview, with
+	// credentials masked
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// ciVariables tell which CI system a run is in, checked in order.
+var ciVariables = []struct{ env, name string }{
+	{"GITHUB_ACTIONS", "github-actions"},
+	{"GITLAB_CI", "gitlab"},
+	{"BUILDKITE", "buildkite"},
+	{"CIRCLECI", "circleci"},
+	{"JENKINS_URL", "jenkins"},
+	{"TF_BUILD", "azure-pipelines"},
+	{"CI", "unknown"},
+}
+
+// DetectCI returns the name of the CI system from the environment, or "".
+func DetectCI(getenv func(string) string) string {
+	for _, v := range ciVariables {
+		if getenv(v.env) != "" {
+			return v.name
+		}
+	}
+	return ""
+}
+
+// relevantVariable matches the environment variables recorded in a bundle.
+var relevantVariable = regexp.MustCompile(`^(GOREVIEW_|OLLAMA_|OPENAI_|GEMINI_|GOOGLE_|GROQ_|MISTRAL_|ANTHROPIC_|HTTPS?_PROXY$|NO_PROXY$|https?_proxy$|no_proxy$)`)
+
+// Variables returns the environment variables that affect goreview, from
+// environ entries ("KEY=value"), with the values of credentials masked.
+func Variables(environ []string) map[string]string {
+	vars := make(map[string]string)
+	for _, kv := range environ {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !relevantVariable.MatchString(key) {
+			continue
+		}
+		if value != "" && sensitiveKey.MatchString(key) {
+			value = privacy.Mask
+		} else if u, err := url.Parse(value); err == nil && u.User != nil {
+			// Proxy URLs may carry credentials
+			u.User = nil
+			value = u.String()
+		}
+		vars[key] = value
+	}
+	return vars
+}
+
+// Index lists the bundle's files, written last as index.json.
+type Index struct {
+	CreatedAt time.Time `json:"created_at"`
+	Files     []string  `json:"files"`
+	// Missing explains what couldn't be collected
+	Missing []string `json:"missing,omitempty"`
+}
+
+// AddIndex adds index.json, listing the files added so far and what was
+// missing.
+func (b *Bundle) AddIndex(missing []string) error {
+	names := b.Names()
+	sort.Strings(names)
+	return b.AddJSON("index.json", Index{CreatedAt: b.created, Files: names, Missing: missing})
+}
@@ -0,0 +1,104 @@
+package testcheck
+
+import "regexp"
+
+// Rule IDs of the flakiness patterns
+const (
+	RuleSleep       = "flaky/sleep"
+	RuleNetwork     = "flaky/network"
+	RuleGlobalState = "flaky/global-state"
+	RuleRandom      = "flaky/random"
+)
+
+// Finding is a flakiness pattern found in a line of test code.
+type Finding struct {
+	Rule       string
+	Message    string
+	Suggestion string
+}
+
+// pattern is a flakiness pattern of a language.
+type pattern struct {
+	rule       string
+	re         *regexp.Regexp
+	suggestion string
+}
+
+// messages describes each flakiness pattern, for all languages
+var messages = map[string]string{
+	RuleSleep:       "Sleeping in a test makes it depend on timing",
+	RuleNetwork:     "Test calls a real network address",
+	RuleGlobalState: "Test modifies process-wide state shared with other tests",
+	RuleRandom:      "Test uses random values without a fixed seed, so failures can't be reproduced",
+}
+
+// remoteURL matches a quoted http(s) URL to a host other than the local
+// machine or a reserved example domain
+var remoteURL = regexp.MustCompile("[\"'`]https?://([^\\s\"'`/:]+)")
+
+// localHosts are hosts a test may call without leaving the machine
+var localHosts = regexp.MustCompile(`^(localhost|127\.\d+\.\d+\.\d+|0\.0\.0\.0|\[::1\]|(\w+\.)*example\.(com|org|net)|[\w.-]+\.(test|local|invalid))$`)
+
+// patterns holds the flakiness patterns by language. Network patterns only
+// match calls; the line must also hold a remote URL literal.
+var patterns = map[string][]pattern{
+	"go": {
+		{RuleSleep, regexp.MustCompile(`\btime\.Sleep\(`), "Wait on a channel or sync.WaitGroup, or poll with a deadline, instead of sleeping"},
+		{RuleNetwork, regexp.MustCompile(`\bhttp\.(Get|Head|Post|PostForm|NewRequest\w*)\(|\bnet\.Dial\w*\(`), "Serve the response from an httptest.Server instead"},
+		{RuleGlobalState, regexp.MustCompile(`\bos\.(Setenv|Unsetenv|Chdir)\(|\bhttp\.Default(Client|Transport)\s*=[^=]`), "Use t.Setenv or t.Chdir, or restore the value with t.Cleanup"},
+		{RuleRandom, regexp.MustCompile(`\brand\.(Seed|NewSource)\(\s*time\.Now\(|\brand\.(Int|Intn|Int31n|Int63n|Float64|Perm|Shuffle)\(`), "Use rand.New(rand.NewSource(42)) with a constant seed, or log the seed"},
+	},
+	"javascript": jsPatterns,
+	"typescript": jsPatterns,
+	"python": {
+		{RuleSleep, regexp.MustCompile(`\bsleep\(`), "Wait for the condition with a timeout, or use a fake clock such as freezegun"},
+		{RuleNetwork, regexp.MustCompile(`\b(requests|httpx)\.\w+\(|\burlopen\(`), "Mock the response with responses, respx or unittest.mock"},
+		{RuleGlobalState, regexp.MustCompile(`\bos\.environ(\[[^\]]+\])?\s*(=[^=]|\.(update|setdefault|pop)\()|^\s*global\s+\w+`), "Use monkeypatch.setenv or mock.patch.dict so the change is undone"},
+		{RuleRandom, regexp.MustCompile(`\brandom\.(random|randint|randrange|choice|choices|shuffle|sample|uniform)\(|\brandom\.seed\(\s*\)`), "Call random.seed with a constant, or use a seeded random.Random"},
+	},
+	"java": {
+		{RuleSleep, regexp.MustCompile(`\bThread\.sleep\(|\bTimeUnit\.\w+\.sleep\(`), "Wait for the condition with Awaitility or a latch instead of sleeping"},
+		{RuleNetwork, regexp.MustCompile(`\bnew URL\(|\bHttpClient\b|\.openConnection\(|\bRestTemplate\b`), "Serve the response from WireMock or MockWebServer instead"},
+		{RuleGlobalState, regexp.MustCompile(`\bSystem\.(setProperty|clearProperty|setOut|setErr)\(`), "Restore the value in an @AfterEach method"},
+		{RuleRandom, regexp.MustCompile(`\bnew Random\(\s*\)|\bMath\.random\(\)|\bThreadLocalRandom\.current\(\)`), "Use new Random(42) with a constant seed"},
+	},
+	"ruby": {
+		{RuleSleep, regexp.MustCompile(`\bsleep[\s(]`), "Wait for the condition with a timeout, or freeze time with Timecop"},
+		{RuleNetwork, regexp.MustCompile(`\bNet::HTTP\b|\bHTTParty\b|\bFaraday\b|\bURI\.open\(`), "Stub the request with WebMock or VCR"},
+		{RuleGlobalState, regexp.MustCompile(`\bENV\[[^\]]+\]\s*=[^=]|^\s*\$\w+\s*=[^=]`), "Restore the value in an after block, or use climate_control"},
+		{RuleRandom, regexp.MustCompile(`\brand\(|\bRandom\.new\(\s*\)`), "Use Random.new(42) with a constant seed"},
+	},
+}
+
+var jsPatterns = []pattern{
+	{RuleSleep, regexp.MustCompile(`\bsetTimeout\(|\bsleep\(|\bwaitForTimeout\(`), "Await the condition, or use fake timers (jest.useFakeTimers, vi.useFakeTimers)"},
+	{RuleNetwork, regexp.MustCompile(`\bfetch\(|\baxios(\.\w+)?\(|\bhttps?\.(get|request)\(`), "Mock the request with msw or nock"},
+	{RuleGlobalState, regexp.MustCompile(`\bprocess\.env(\.\w+|\[[^\]]+\])\s*=[^=]|\b(global|globalThis|window)\.\w+\s*=[^=]`), "Restore the value in afterEach, or use jest.replaceProperty / vi.stubEnv"},
+	{RuleRandom, regexp.MustCompile(`\bMath\.random\(\)`), "Use a seeded generator, or stub Math.random"},
+}
+
+// Flaky returns the flakiness patterns found in a line of test code in the
+// language. Comments should be removed from the line first.
+func Flaky(language, code string) []Finding {
+	var findings []Finding
+	for _, p := range patterns[language] {
+		if !p.re.MatchString(code) {
+			continue
+		}
+		if p.rule == RuleNetwork && !hasRemoteURL(code) {
+			continue
+		}
+		findings = append(findings, Finding{Rule: p.rule, Message: messages[p.rule], Suggestion: p.suggestion})
+	}
+	return findings
+}
+
+// hasRemoteURL reports whether the code holds a URL literal to a remote host.
+func hasRemoteURL(code string) bool {
+	for _, m := range remoteURL.FindAllStringSubmatch(code, -1) {
+		if !localHosts.MatchString(m[1]) {
+			return true
+		}
+	}
+	return false
+}
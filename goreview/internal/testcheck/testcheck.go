@@ -0,0 +1,113 @@
+// Package testcheck analyzes test code without a model: it finds patterns
+// that make tests flaky in changed test files, and tells test files and
+// assertions apart so source changes can be matched with test changes.
+package testcheck
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IsTestFile reports whether the path is a test file, by name or by being
+// in a test directory.
+func IsTestFile(path string) bool {
+	base := filepath.Base(path)
+	ext := filepath.Ext(path)
+	nameWithoutExt := strings.TrimSuffix(base, ext)
+
+	// Go tests
+	if strings.HasSuffix(base, "_test.go") {
+		return true
+	}
+
+	// JavaScript/TypeScript tests
+	jsTestSuffixes := []string{".test", ".spec", "_test", "_spec"}
+	for _, suffix := range jsTestSuffixes {
+		if strings.HasSuffix(nameWithoutExt, suffix) {
+			return true
+		}
+	}
+
+	// Test directories - normalize path separators for cross-platform
+	normalizedPath := filepath.ToSlash(path)
+	testDirs := []string{"test", "tests", "__tests__", "spec", "specs"}
+	for _, d := range testDirs {
+		// Check if directory is in path
+		if strings.Contains(normalizedPath, "/"+d+"/") ||
+			strings.HasPrefix(normalizedPath, d+"/") ||
+			strings.Contains(normalizedPath, d+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TestPaths returns the possible test file paths for a source file, the
+// most likely first.
+func TestPaths(path string) []string {
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+	ext := filepath.Ext(path)
+	nameWithoutExt := strings.TrimSuffix(base, ext)
+
+	var variants []string
+
+	switch ext {
+	case ".go":
+		// Go: file.go -> file_test.go
+		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test.go"))
+
+	case ".js", ".jsx", ".ts", ".tsx":
+		// JS/TS: file.js -> file.test.js, file.spec.js
+		variants = append(variants, filepath.Join(dir, nameWithoutExt+".test"+ext))
+		variants = append(variants, filepath.Join(dir, nameWithoutExt+".spec"+ext))
+		variants = append(variants, filepath.Join(dir, "__tests__", base))
+		// Also check for .ts tests if .js file
+		if ext == ".js" || ext == ".jsx" {
+			tsExt := strings.Replace(ext, ".js", ".ts", 1)
+			variants = append(variants, filepath.Join(dir, nameWithoutExt+".test"+tsExt))
+			variants = append(variants, filepath.Join(dir, nameWithoutExt+".spec"+tsExt))
+		}
+
+	case ".py":
+		// Python: file.py -> test_file.py, file_test.py
+		variants = append(variants, filepath.Join(dir, "test_"+base))
+		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test.py"))
+		variants = append(variants, filepath.Join(dir, "tests", "test_"+base))
+
+	case ".java":
+		// Java: File.java -> FileTest.java
+		variants = append(variants, filepath.Join(dir, nameWithoutExt+"Test.java"))
+		variants = append(variants, strings.Replace(path, "/main/", "/test/", 1))
+
+	case ".rs":
+		// Rust: usually in same file or mod tests
+		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test.rs"))
+
+	case ".rb":
+		// Ruby: file.rb -> file_spec.rb, file_test.rb
+		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_spec.rb"))
+		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test.rb"))
+		variants = append(variants, filepath.Join(dir, "spec", base))
+
+	default:
+		// Generic: try _test suffix
+		variants = append(variants, filepath.Join(dir, nameWithoutExt+"_test"+ext))
+	}
+
+	return variants
+}
+
+// assertionPattern matches the assertions of common test frameworks, and
+// the want/expected values of table-driven tests
+var assertionPattern = regexp.MustCompile(
+	`\b(assert\w*|[Ee]xpect\w*|want\w*|should\w*|require\.\w+)\b` +
+		`|\.(Error|Errorf|Fatal|Fatalf|Fail|FailNow)\(` +
+		`|\.(to|not)\.\w+|\.to[A-Z]\w*\(|\bverify\(`)
+
+// IsAssertion reports whether a line of test code checks a result.
+func IsAssertion(code string) bool {
+	return assertionPattern.MatchString(code)
+}
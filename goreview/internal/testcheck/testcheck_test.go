@@ -0,0 +1,62 @@
+package testcheck
+
+import "testing"
+
+func TestFlaky(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		code     string
+		want     []string
+	}{
+		{"go sleep", "go", "time.Sleep(100 * time.Millisecond)", []string{RuleSleep}},
+		{"go remote call", "go", `resp, err := http.Get("https://api.github.com/repos")`, []string{RuleNetwork}},
+		{"go local server", "go", "resp, err := http.Get(srv.URL)", nil},
+		{"go localhost", "go", `resp, err := http.Get("http://localhost:8080/health")`, nil},
+		{"go url in expectation", "go", `want := "https://github.com/org/repo"`, nil},
+		{"go setenv", "go", `os.Setenv("HOME", dir)`, []string{RuleGlobalState}},
+		{"go t.Setenv", "go", `t.Setenv("HOME", dir)`, nil},
+		{"go clock seed", "go", "rand.Seed(time.Now().UnixNano())", []string{RuleRandom}},
+		{"go seeded source", "go", "r := rand.New(rand.NewSource(42))", nil},
+		{"js timeout and random", "typescript", "setTimeout(done, Math.random() * 10)", []string{RuleSleep, RuleRandom}},
+		{"js fetch", "javascript", `await fetch('https://example.org/api')`, nil},
+		{"js env", "javascript", "process.env.API_KEY = 'x'", []string{RuleGlobalState}},
+		{"python requests", "python", `requests.get("https://pypi.org/simple")`, []string{RuleNetwork}},
+		{"python environ", "python", `os.environ["DEBUG"] = "1"`, []string{RuleGlobalState}},
+		{"java random", "java", "Random r = new Random();", []string{RuleRandom}},
+		{"unknown language", "haskell", "threadDelay 1000", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Flaky(tt.language, tt.code)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Flaky() = %+v, want %v", got, tt.want)
+			}
+			for i, f := range got {
+				if f.Rule != tt.want[i] || f.Message == "" || f.Suggestion == "" {
+					t.Errorf("finding %d = %+v, want %s", i, f, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIsAssertion(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{`t.Errorf("got %d, want %d", got, 2)`, true},
+		{"assert.Equal(t, 2, got)", true},
+		{`{name: "empty", input: "", want: 0},`, true},
+		{"expect(result).toBe(3);", true},
+		{"self.assertEqual(total, 3)", true},
+		{"x := compute(1)", false},
+		{"result = client.get('/')", false},
+	}
+	for _, tt := range tests {
+		if got := IsAssertion(tt.code); got != tt.want {
+			t.Errorf("IsAssertion(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
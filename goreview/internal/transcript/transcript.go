@@ -0,0 +1,84 @@
+// Package transcript saves the exact prompts a review sent to the provider
+// and the raw answers it got back, one JSON file per reviewed file, so a bad
+// finding can be traced to what the model was asked and what it said.
+package transcript
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/privacy"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+)
+
+// Transcript is the provider exchanges of a reviewed file.
+type Transcript struct {
+	ID        string    `json:"id"`
+	File      string    `json:"file"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+	// Redacted is set when secrets or configured patterns were masked
+	Redacted bool `json:"redacted"`
+	// Exchanges holds one prompt and answer per request; large diffs are
+	// split into several requests
+	Exchanges []providers.Exchange `json:"exchanges"`
+}
+
+// Writer writes transcripts to a directory.
+type Writer struct {
+	dir      string
+	provider string
+	model    string
+	redactor *privacy.Redactor
+}
+
+// NewWriter creates dir and returns a writer saving transcripts there,
+// redacted by redactor.
+func NewWriter(dir, provider, model string, redactor *privacy.Redactor) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating transcript directory: %w", err)
+	}
+	return &Writer{dir: dir, provider: provider, model: model, redactor: redactor}, nil
+}
+
+// Write saves the exchanges of a file as <dir>/<id>.json and returns the ID.
+func (w *Writer) Write(file string, exchanges []providers.Exchange) (string, error) {
+	t := Transcript{
+		ID:        ID(file),
+		File:      file,
+		Provider:  w.provider,
+		Model:     w.model,
+		CreatedAt: time.Now().UTC(),
+		Redacted:  w.redactor.Enabled(),
+		Exchanges: make([]providers.Exchange, 0, len(exchanges)),
+	}
+	for _, ex := range exchanges {
+		t.Exchanges = append(t.Exchanges, providers.Exchange{
+			System:   w.redactor.Redact(ex.System),
+			Prompt:   w.redactor.Redact(ex.Prompt),
+			Response: w.redactor.Redact(ex.Response),
+		})
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(w.dir, t.ID+".json"), append(data, '\n'), 0o600); err != nil {
+		return "", fmt.Errorf("writing transcript: %w", err)
+	}
+	return t.ID, nil
+}
+
+// ID is the transcript ID of a file: its base name and a hash of its path,
+// readable and unique within a review.
+func ID(file string) string {
+	sum := sha256.Sum256([]byte(file))
+	return filepath.Base(file) + "-" + hex.EncodeToString(sum[:])[:8]
+}
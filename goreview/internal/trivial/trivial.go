@@ -0,0 +1,417 @@
+// Package trivial classifies changes that need no review by a model:
+// comment-only edits, formatting-only edits and version bumps. The checks
+// are heuristics tuned to stay on the safe side: a change they can't prove
+// trivial is reviewed as usual.
+package trivial
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// Kinds of trivial changes
+const (
+	KindComments    = "comments"
+	KindFormatting  = "formatting"
+	KindVersionBump = "version_bump"
+)
+
+// Kinds lists the kinds of trivial changes.
+var Kinds = []string{KindComments, KindFormatting, KindVersionBump}
+
+// Change is a trivial change of a file.
+type Change struct {
+	// Kind is one of the Kind constants
+	Kind string
+	// Detail describes the change, like "comment-only change"
+	Detail string
+}
+
+var details = map[string]string{
+	KindComments:    "comment-only change",
+	KindFormatting:  "formatting-only change",
+	KindVersionBump: "version bump",
+}
+
+// Classify returns the trivial change the diff of a file makes, or nil
+// when the file needs a review. Only the given kinds are detected, all of
+// them when kinds is empty. Added, deleted and renamed files are never
+// trivial.
+func Classify(file git.FileDiff, kinds []string) *Change {
+	if file.Status != git.FileModified || file.IsBinary || len(file.Hunks) == 0 {
+		return nil
+	}
+	enabled := func(kind string) bool {
+		if len(kinds) == 0 {
+			return true
+		}
+		for _, k := range kinds {
+			if k == kind {
+				return true
+			}
+		}
+		return false
+	}
+
+	if enabled(KindVersionBump) && isVersionFile(file.Path) && versionBump(file.Hunks) {
+		return &Change{Kind: KindVersionBump, Detail: details[KindVersionBump]}
+	}
+	syn, ok := syntaxes[file.Language]
+	if !ok {
+		return nil
+	}
+	kind := KindFormatting
+	for _, hunk := range file.Hunks {
+		switch hunkKind(hunk, syn) {
+		case KindFormatting:
+		case KindComments:
+			kind = KindComments
+		default:
+			return nil
+		}
+	}
+	if !enabled(kind) {
+		return nil
+	}
+	return &Change{Kind: kind, Detail: details[kind]}
+}
+
+// hunkKind returns the kind of trivial change of a hunk, "" when it changes
+// code.
+func hunkKind(hunk git.Hunk, syn syntax) string {
+	var before, after []string
+	for _, l := range hunk.Lines {
+		if l.Type != git.LineAddition {
+			before = append(before, l.Content)
+		}
+		if l.Type != git.LineDeletion {
+			after = append(after, l.Content)
+		}
+	}
+	if syn.normalize(before, true) == syn.normalize(after, true) {
+		return KindFormatting
+	}
+	if syn.normalize(before, false) != syn.normalize(after, false) {
+		return ""
+	}
+	for _, l := range hunk.Lines {
+		if l.Type != git.LineContext && directive.MatchString(l.Content) {
+			return ""
+		}
+	}
+	return KindComments
+}
+
+// directive matches comments that change how code builds, runs or is
+// checked, like build constraints and linter suppressions
+var directive = regexp.MustCompile(`(?i)^#!|//go:|//\s*\+build|//export\s|//line\s|nolint|lint:|eslint|tslint|jshint|prettier-ignore|@ts-|@flow|istanbul\s|c8\s+ignore|noqa|type:\s*ignore|pylint:|mypy:|rubocop:|frozen_string_literal|-\*-|nosec|nosonar|swiftlint:|ktlint|clang-format|nocheck|shellcheck|coverage:`)
+
+// syntax is what the normalization knows of a language.
+type syntax struct {
+	// line are the line comment markers; a "#" starts a comment only at
+	// the start of a line or after whitespace
+	line []string
+	// blockStart and blockEnd delimit block comments
+	blockStart, blockEnd string
+	// raw are the delimiters of strings that may span lines
+	raw []string
+	// collapse is set for languages where whitespace between tokens only
+	// separates them, so formatting can be told apart from code changes
+	collapse bool
+	// indent is set when indentation is significant
+	indent bool
+	// trailingComma is set when a comma before a closing bracket means
+	// nothing
+	trailingComma bool
+}
+
+var (
+	cSyntax    = syntax{line: []string{"//"}, blockStart: "/*", blockEnd: "*/", collapse: true, trailingComma: true}
+	hashSyntax = syntax{line: []string{"#"}}
+)
+
+// syntaxes maps languages to their syntax. Languages where whitespace is
+// significant, like shell or YAML, only get comment-only changes.
+var syntaxes = map[string]syntax{
+	"go":         {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", raw: []string{"`"}, collapse: true, trailingComma: true},
+	"javascript": {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", raw: []string{"`"}, collapse: true, trailingComma: true},
+	"typescript": {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", raw: []string{"`"}, collapse: true, trailingComma: true},
+	"java":       cSyntax,
+	"kotlin":     {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", raw: []string{`"""`}, collapse: true, trailingComma: true},
+	"swift":      {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", raw: []string{`"""`}, collapse: true, trailingComma: true},
+	"c":          cSyntax,
+	"cpp":        cSyntax,
+	"csharp":     cSyntax,
+	"rust":       cSyntax,
+	"scala":      {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", raw: []string{`"""`}, collapse: true, trailingComma: true},
+	"dart":       {line: []string{"//"}, blockStart: "/*", blockEnd: "*/", raw: []string{`"""`, `'''`}, collapse: true, trailingComma: true},
+	"objectivec": cSyntax,
+	"php":        {line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/", collapse: true, trailingComma: true},
+	"python":     {line: []string{"#"}, raw: []string{`"""`, `'''`}, collapse: true, indent: true},
+	"json":       {collapse: true},
+	"ruby":       hashSyntax,
+	"shell":      hashSyntax,
+	"yaml":       hashSyntax,
+	"toml":       hashSyntax,
+	"dockerfile": hashSyntax,
+	"makefile":   hashSyntax,
+	"perl":       hashSyntax,
+	"r":          hashSyntax,
+	"sql":        {line: []string{"--"}, blockStart: "/*", blockEnd: "*/"},
+	"lua":        {line: []string{"--"}},
+}
+
+// normalize reduces lines of code to what matters to the language: without
+// comments unless keepComments is set and, for languages that collapse
+// whitespace, with whitespace only where it separates tokens and lines
+// joined where a bracket or comma continues them. String literals are kept
+// as they are.
+func (s syntax) normalize(lines []string, keepComments bool) string {
+	var out []string
+	sc := scanner{syntax: s}
+	open := false // the last line ended inside a multi-line string
+	for _, line := range lines {
+		inString := sc.inRaw != ""
+		code, comment := sc.scan(line)
+		if s.indent && !inString && strings.TrimSpace(code) != "" {
+			code = line[:len(line)-len(strings.TrimLeft(line, " \t"))] + code
+		}
+		if keepComments && comment != "" {
+			code += "\x01" + strings.Join(strings.Fields(comment), " ")
+		}
+		if strings.TrimSpace(code) == "" && !inString {
+			continue
+		}
+		join := s.collapse && !s.indent && !open && len(out) > 0 && continues(out[len(out)-1], code)
+		open = sc.inRaw != ""
+		if !join {
+			out = append(out, code)
+			continue
+		}
+		prev := out[len(out)-1]
+		if s.trailingComma && strings.HasSuffix(prev, ",") && strings.ContainsAny(code[:1], ")]}") {
+			prev = strings.TrimSuffix(prev, ",")
+		}
+		out[len(out)-1] = prev + code
+	}
+	return strings.Join(out, "\n")
+}
+
+// continues reports whether a line break between two normalized lines is
+// only formatting: the first ends with an opening bracket or a comma, or
+// the second starts with a closing parenthesis or bracket.
+func continues(prev, next string) bool {
+	if strings.Contains(prev, "\x01") || next == "" {
+		return false // A comment ends the line
+	}
+	return strings.ContainsAny(prev[len(prev)-1:], "([{,") || strings.ContainsAny(next[:1], ")]")
+}
+
+// scanner splits lines into code and comments, keeping the state of block
+// comments and strings spanning lines.
+type scanner struct {
+	syntax
+	inBlock bool
+	inRaw   string // closing delimiter of an open multi-line string
+}
+
+// scan returns the normalized code of a line and the text of its comments.
+func (sc *scanner) scan(line string) (code, comment string) {
+	var sb, cb strings.Builder
+	space := false // whitespace since the last code byte
+	write := func(c byte) {
+		if space && sb.Len() > 0 && separates(sb.String()[sb.Len()-1], c) {
+			sb.WriteByte(' ')
+		}
+		space = false
+		sb.WriteByte(c)
+	}
+
+	for i := 0; i < len(line); i++ {
+		rest := line[i:]
+		switch {
+		case sc.inBlock:
+			if strings.HasPrefix(rest, sc.blockEnd) {
+				sc.inBlock = false
+				i += len(sc.blockEnd) - 1
+				cb.WriteByte(' ')
+				continue
+			}
+			cb.WriteByte(line[i])
+			continue
+		case sc.inRaw != "":
+			if strings.HasPrefix(rest, sc.inRaw) {
+				sb.WriteString(sc.inRaw)
+				i += len(sc.inRaw) - 1
+				sc.inRaw = ""
+				continue
+			}
+			sb.WriteByte(line[i])
+			continue
+		}
+
+		c := line[i]
+		if delim := sc.rawStart(rest); delim != "" {
+			write(delim[0])
+			sb.WriteString(delim[1:])
+			i += len(delim) - 1
+			sc.inRaw = delim
+			continue
+		}
+		switch {
+		case c == '"' || c == '\'':
+			end := stringEnd(line, i)
+			write(c)
+			sb.WriteString(line[i+1 : end])
+			i = end - 1
+		case sc.blockStart != "" && strings.HasPrefix(rest, sc.blockStart):
+			sc.inBlock = true
+			i += len(sc.blockStart) - 1
+		case sc.lineComment(line, i):
+			cb.WriteString(rest)
+			return sc.trim(sb.String()), cb.String()
+		case c == ' ' || c == '\t' || c == '\r':
+			if sc.collapse {
+				space = true
+			} else {
+				sb.WriteByte(c)
+			}
+		case sc.trailingComma && strings.IndexByte(")]}", c) >= 0 && strings.HasSuffix(sb.String(), ","):
+			trimmed := strings.TrimSuffix(sb.String(), ",")
+			sb.Reset()
+			sb.WriteString(trimmed)
+			write(c)
+		default:
+			write(c)
+		}
+	}
+	return sc.trim(sb.String()), cb.String()
+}
+
+// trim drops the trailing whitespace of the code of a line, which only
+// matters inside a multi-line string.
+func (sc *scanner) trim(code string) string {
+	if sc.inRaw != "" {
+		return code
+	}
+	return strings.TrimRight(code, " \t\r")
+}
+
+// rawStart returns the multi-line string delimiter at the start of s.
+func (sc *scanner) rawStart(s string) string {
+	for _, delim := range sc.raw {
+		if strings.HasPrefix(s, delim) {
+			return delim
+		}
+	}
+	return ""
+}
+
+// lineComment reports whether a line comment starts at line[i].
+func (sc *scanner) lineComment(line string, i int) bool {
+	for _, marker := range sc.line {
+		if !strings.HasPrefix(line[i:], marker) {
+			continue
+		}
+		if marker != "#" || i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+			return true
+		}
+	}
+	return false
+}
+
+// stringEnd returns the index after the string literal starting at
+// line[start], or the end of the line when it isn't closed.
+func stringEnd(line string, start int) int {
+	quote := line[start]
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(line)
+}
+
+// separates reports whether whitespace between two bytes matters: between
+// two word bytes, or two operator bytes that would read as another
+// operator, like "- -" and "--".
+func separates(a, b byte) bool {
+	return isWord(a) && isWord(b) || isOperator(a) && isOperator(b)
+}
+
+func isWord(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isOperator(c byte) bool {
+	return strings.IndexByte("+-*/%&|^<>=!~?:.", c) >= 0
+}
+
+// versionFiles are manifests whose version fields are bumped on releases
+var versionFiles = map[string]bool{
+	"package.json": true, "composer.json": true, "manifest.json": true, "go.mod": true,
+	"cargo.toml": true, "pyproject.toml": true, "setup.cfg": true, "setup.py": true,
+	"pom.xml": true, "build.gradle": true, "build.gradle.kts": true, "gradle.properties": true,
+	"chart.yaml": true, "pubspec.yaml": true, "mix.exs": true, "version": true,
+	".version": true, ".tool-versions": true, "_version.py": true, "__version__.py": true,
+}
+
+// isVersionFile reports whether a file holds versions: a manifest, or a
+// file named after versions, like VERSION or version.go.
+func isVersionFile(p string) bool {
+	base := strings.ToLower(path.Base(strings.ReplaceAll(p, "\\", "/")))
+	if versionFiles[base] {
+		return true
+	}
+	return strings.TrimSuffix(base, path.Ext(base)) == "version"
+}
+
+// version matches version numbers, like 1.2, v1.2.3 or 2.0.0-rc.1
+var version = regexp.MustCompile(`v?\d+(\.\d+)+([-+][0-9A-Za-z.-]*[0-9A-Za-z])?`)
+
+// versionBump reports whether every changed line only changes version
+// numbers: each hunk replaces lines one for one, and the lines are the
+// same once their versions are masked.
+func versionBump(hunks []git.Hunk) bool {
+	for _, hunk := range hunks {
+		var removed, added []string
+		flush := func() bool {
+			if len(removed) != len(added) {
+				return false
+			}
+			for i := range removed {
+				if removed[i] == added[i] || maskVersions(removed[i]) != maskVersions(added[i]) {
+					return false
+				}
+			}
+			removed, added = nil, nil
+			return true
+		}
+		for _, l := range hunk.Lines {
+			switch l.Type {
+			case git.LineDeletion:
+				removed = append(removed, l.Content)
+			case git.LineAddition:
+				added = append(added, l.Content)
+			default:
+				if !flush() {
+					return false
+				}
+			}
+		}
+		if !flush() {
+			return false
+		}
+	}
+	return true
+}
+
+func maskVersions(line string) string {
+	return version.ReplaceAllString(line, "\x00")
+}
@@ -0,0 +1,147 @@
+package trivial
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/internal/git"
+)
+
+// fileDiff parses a diff of a modified file.
+func fileDiff(t *testing.T, path, language, body string) git.FileDiff {
+	t.Helper()
+	diff, err := git.ParseDiff("diff --git a/" + path + " b/" + path + "\n--- a/" + path + "\n+++ b/" + path + "\n" + body)
+	if err != nil || len(diff.Files) != 1 {
+		t.Fatalf("ParseDiff() = %v, %v", diff, err)
+	}
+	file := diff.Files[0]
+	file.Language = language
+	return file
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		language string
+		diff     string
+		want     string
+	}{
+		{
+			name: "go comment", path: "a.go", language: "go", want: KindComments,
+			diff: "@@ -1,3 +1,3 @@\n func f() {\n-\treturn 1 // one\n+\treturn 1 // the answer\n }\n",
+		},
+		{
+			name: "go doc comment added", path: "a.go", language: "go", want: KindComments,
+			diff: "@@ -1,2 +1,4 @@\n+// f returns one.\n+/* It never fails. */\n func f() int {\n \treturn 1\n",
+		},
+		{
+			name: "go code", path: "a.go", language: "go", want: "",
+			diff: "@@ -1,3 +1,3 @@\n func f() {\n-\treturn 1 // one\n+\treturn 2 // one\n }\n",
+		},
+		{
+			name: "go build constraint", path: "a.go", language: "go", want: "",
+			diff: "@@ -1,2 +1,2 @@\n-//go:build linux\n+//go:build darwin\n package a\n",
+		},
+		{
+			name: "go linter suppression", path: "a.go", language: "go", want: "",
+			diff: "@@ -1,2 +1,2 @@\n-\tx := f()\n+\tx := f() //nolint:errcheck\n }\n",
+		},
+		{
+			name: "comment marker in string", path: "a.go", language: "go", want: "",
+			diff: "@@ -1 +1 @@\n-\turl := \"http://a\"\n+\turl := \"http://b\"\n",
+		},
+		{
+			name: "go gofmt alignment", path: "a.go", language: "go", want: KindFormatting,
+			diff: "@@ -1,4 +1,4 @@\n type T struct {\n-\tA int\n-\tLong string\n+\tA    int\n+\tLong string\n }\n",
+		},
+		{
+			name: "go wrapped call with trailing comma", path: "a.go", language: "go", want: KindFormatting,
+			diff: "@@ -1,3 +1,6 @@\n func f() {\n-\tcall(a, b)\n+\tcall(\n+\t\ta,\n+\t\tb,\n+\t)\n }\n",
+		},
+		{
+			name: "whitespace inside string", path: "a.go", language: "go", want: "",
+			diff: "@@ -1 +1 @@\n-\ts := \"a b\"\n+\ts := \"a  b\"\n",
+		},
+		{
+			name: "operators merged", path: "a.js", language: "javascript", want: "",
+			diff: "@@ -1 +1 @@\n-x = a + +b\n+x = a ++b\n",
+		},
+		{
+			name: "words merged", path: "a.go", language: "go", want: "",
+			diff: "@@ -1 +1 @@\n-\treturn x\n+\treturnx\n",
+		},
+		{
+			name: "python reindent", path: "a.py", language: "python", want: "",
+			diff: "@@ -1,3 +1,3 @@\n if x:\n     a()\n-    b()\n+b()\n",
+		},
+		{
+			name: "python spacing", path: "a.py", language: "python", want: KindFormatting,
+			diff: "@@ -1,2 +1,2 @@\n def f():\n-    return f(a,b)\n+    return f(a, b)\n",
+		},
+		{
+			name: "yaml hash in value", path: "a.yaml", language: "yaml", want: "",
+			diff: "@@ -1 +1 @@\n-url: http://a#x\n+url: http://a#y\n",
+		},
+		{
+			name: "yaml comment", path: "a.yaml", language: "yaml", want: KindComments,
+			diff: "@@ -1 +1 @@\n-port: 80 # http\n+port: 80 # plain http\n",
+		},
+		{
+			name: "shell spacing is code", path: "a.sh", language: "shell", want: "",
+			diff: "@@ -1 +1 @@\n-[ -f x ]\n+[-f x]\n",
+		},
+		{
+			name: "package.json version", path: "web/package.json", language: "json", want: KindVersionBump,
+			diff: "@@ -1,3 +1,3 @@\n {\n-  \"version\": \"1.2.3\",\n+  \"version\": \"1.3.0-rc.1\",\n   \"name\": \"web\"\n",
+		},
+		{
+			name: "go.mod dependency", path: "go.mod", language: "", want: KindVersionBump,
+			diff: "@@ -3 +3 @@\n-\tgithub.com/a/b v1.2.3\n+\tgithub.com/a/b v1.4.0\n",
+		},
+		{
+			name: "version constant", path: "internal/version.go", language: "go", want: KindVersionBump,
+			diff: "@@ -1 +1 @@\n-const Version = \"0.9.1\"\n+const Version = \"1.0.0\"\n",
+		},
+		{
+			name: "version and more", path: "package.json", language: "json", want: "",
+			diff: "@@ -1,2 +1,2 @@\n-  \"version\": \"1.2.3\",\n-  \"main\": \"a.js\"\n+  \"version\": \"1.2.4\",\n+  \"main\": \"b.js\"\n",
+		},
+		{
+			name: "numbers outside version files", path: "a.go", language: "go", want: "",
+			diff: "@@ -1 +1 @@\n-\tratio := 1.5\n+\tratio := 2.5\n",
+		},
+		{
+			name: "unknown language", path: "a.txt", language: "unknown", want: "",
+			diff: "@@ -1 +1 @@\n-a  b\n+a b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := Classify(fileDiff(t, tt.path, tt.language, tt.diff), nil)
+			got := ""
+			if change != nil {
+				got = change.Kind
+			}
+			if got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyKinds(t *testing.T) {
+	file := fileDiff(t, "a.go", "go", "@@ -1 +1 @@\n-\treturn 1 // one\n+\treturn 1 // uno\n")
+	if c := Classify(file, []string{KindFormatting}); c != nil {
+		t.Errorf("Classify() with formatting only = %+v, want nil", c)
+	}
+	c := Classify(file, []string{KindComments})
+	if c == nil || !strings.Contains(c.Detail, "comment") {
+		t.Errorf("Classify() with comments = %+v", c)
+	}
+
+	file.Status = git.FileAdded
+	if c := Classify(file, nil); c != nil {
+		t.Errorf("Classify() of an added file = %+v, want nil", c)
+	}
+}
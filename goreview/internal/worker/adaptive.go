@@ -0,0 +1,212 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/profiler"
+)
+
+// latencySmoothing is the weight of the newest sample in the latency average
+const latencySmoothing = 0.3
+
+// AdaptiveConfig configures an AdaptiveLimiter.
+type AdaptiveConfig struct {
+	Initial int // Starting limit (default: Min)
+	Min     int // Lower bound (default: 1)
+	Max     int // Upper bound (default: Initial)
+
+	// TargetLatency is the smoothed latency above which the limit shrinks.
+	// When zero, twice the fastest smoothed latency seen is used.
+	TargetLatency time.Duration
+
+	// MaxMemory is the heap size in bytes above which the limit shrinks (0 = unlimited)
+	MaxMemory uint64
+
+	// IsThrottled reports whether an error means the backend is rate limiting
+	IsThrottled func(error) bool
+
+	// MemStats reads memory statistics (default: profiler.Stats)
+	MemStats func() profiler.MemStats
+}
+
+// AdaptiveLimiter bounds the number of concurrent operations and tunes the
+// bound from feedback (AIMD): after a window of successful calls it grows by
+// one, it shrinks by one on rising latency or memory pressure, and it halves
+// on throttling.
+type AdaptiveLimiter struct {
+	cfg AdaptiveConfig
+
+	mu        sync.Mutex
+	limit     int
+	inFlight  int
+	wake      chan struct{}
+	latency   time.Duration // Smoothed latency
+	fastest   time.Duration
+	successes int // Successful calls since the last adjustment
+	stats     AdaptiveStats
+}
+
+// AdaptiveStats summarizes the limiter's adjustments.
+type AdaptiveStats struct {
+	Limit     int
+	Peak      int
+	Increases int
+	Decreases int
+	Throttled int
+}
+
+// String returns a string representation of the stats.
+func (s AdaptiveStats) String() string {
+	return fmt.Sprintf("limit=%d peak=%d increases=%d decreases=%d throttled=%d",
+		s.Limit, s.Peak, s.Increases, s.Decreases, s.Throttled)
+}
+
+// NewAdaptiveLimiter creates a new adaptive limiter.
+func NewAdaptiveLimiter(cfg AdaptiveConfig) *AdaptiveLimiter {
+	if cfg.Min <= 0 {
+		cfg.Min = 1
+	}
+	if cfg.Initial < cfg.Min {
+		cfg.Initial = cfg.Min
+	}
+	if cfg.Max < cfg.Initial {
+		cfg.Max = cfg.Initial
+	}
+	if cfg.MemStats == nil {
+		cfg.MemStats = profiler.Stats
+	}
+
+	return &AdaptiveLimiter{
+		cfg:   cfg,
+		limit: cfg.Initial,
+		wake:  make(chan struct{}),
+		stats: AdaptiveStats{Peak: cfg.Initial},
+	}
+}
+
+// Acquire blocks until a slot is available under the current limit.
+func (l *AdaptiveLimiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < l.limit {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		wake := l.wake
+		l.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release frees a slot and adjusts the limit from the call's outcome.
+func (l *AdaptiveLimiter) Release(latency time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	defer l.broadcast()
+
+	if err != nil {
+		if l.cfg.IsThrottled != nil && l.cfg.IsThrottled(err) {
+			l.stats.Throttled++
+			l.setLimit(l.limit / 2)
+		}
+		// Other failures say nothing about load
+		return
+	}
+
+	l.observe(latency)
+	if l.memoryPressure() {
+		l.setLimit(l.limit - 1)
+		return
+	}
+
+	// Decide once per window so a change can take effect before the next one
+	l.successes++
+	if l.successes < l.limit {
+		return
+	}
+	if l.overloaded() {
+		l.setLimit(l.limit - 1)
+	} else {
+		l.setLimit(l.limit + 1)
+	}
+}
+
+// Limit returns the current concurrency limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// Stats returns the limiter statistics.
+func (l *AdaptiveLimiter) Stats() AdaptiveStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := l.stats
+	stats.Limit = l.limit
+	return stats
+}
+
+func (l *AdaptiveLimiter) observe(latency time.Duration) {
+	if l.latency == 0 {
+		l.latency = latency
+	} else {
+		l.latency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(l.latency))
+	}
+	if l.fastest == 0 || l.latency < l.fastest {
+		l.fastest = l.latency
+	}
+}
+
+func (l *AdaptiveLimiter) overloaded() bool {
+	target := l.cfg.TargetLatency
+	if target <= 0 {
+		target = 2 * l.fastest
+	}
+	return l.latency > target
+}
+
+func (l *AdaptiveLimiter) memoryPressure() bool {
+	return l.cfg.MaxMemory > 0 && l.cfg.MemStats().HeapAlloc > l.cfg.MaxMemory
+}
+
+// setLimit clamps and applies a new limit. Must be called with mu held.
+func (l *AdaptiveLimiter) setLimit(n int) {
+	if n < l.cfg.Min {
+		n = l.cfg.Min
+	}
+	if n > l.cfg.Max {
+		n = l.cfg.Max
+	}
+	l.successes = 0
+
+	switch {
+	case n > l.limit:
+		l.stats.Increases++
+	case n < l.limit:
+		l.stats.Decreases++
+	default:
+		return
+	}
+	l.limit = n
+	if n > l.stats.Peak {
+		l.stats.Peak = n
+	}
+}
+
+// broadcast wakes all goroutines waiting in Acquire. Must be called with mu held.
+func (l *AdaptiveLimiter) broadcast() {
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JNZader/goreview/goreview/internal/profiler"
+)
+
+var errThrottled = errors.New("HTTP 429 Too Many Requests")
+
+func newTestLimiter(cfg AdaptiveConfig) *AdaptiveLimiter {
+	cfg.IsThrottled = func(err error) bool { return errors.Is(err, errThrottled) }
+	return NewAdaptiveLimiter(cfg)
+}
+
+// complete runs n successful calls of the given latency through the limiter
+func complete(t *testing.T, l *AdaptiveLimiter, n int, latency time.Duration) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := l.Acquire(context.Background()); err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		l.Release(latency, nil)
+	}
+}
+
+func TestAdaptiveLimiter_GrowsWhileHealthy(t *testing.T) {
+	l := newTestLimiter(AdaptiveConfig{Initial: 2, Min: 1, Max: 4})
+
+	complete(t, l, 2, 100*time.Millisecond)
+	if got := l.Limit(); got != 3 {
+		t.Errorf("Limit() after one window = %d, want 3", got)
+	}
+
+	complete(t, l, 20, 100*time.Millisecond)
+	if got := l.Limit(); got != 4 {
+		t.Errorf("Limit() = %d, want capped at 4", got)
+	}
+}
+
+func TestAdaptiveLimiter_HalvesOnThrottling(t *testing.T) {
+	l := newTestLimiter(AdaptiveConfig{Initial: 8, Min: 3, Max: 8})
+
+	_ = l.Acquire(context.Background())
+	l.Release(time.Second, errThrottled)
+	if got := l.Limit(); got != 4 {
+		t.Errorf("Limit() after 429 = %d, want 4", got)
+	}
+
+	_ = l.Acquire(context.Background())
+	l.Release(time.Second, errThrottled)
+	if got := l.Limit(); got != 3 {
+		t.Errorf("Limit() = %d, want clamped to min 3", got)
+	}
+
+	// Other errors leave the limit alone
+	_ = l.Acquire(context.Background())
+	l.Release(time.Second, errors.New("decode response"))
+	if stats := l.Stats(); stats.Limit != 3 || stats.Throttled != 2 {
+		t.Errorf("Stats() = %+v, want limit 3 and 2 throttled", stats)
+	}
+}
+
+func TestAdaptiveLimiter_ShrinksOnLatency(t *testing.T) {
+	l := newTestLimiter(AdaptiveConfig{Initial: 2, Min: 1, Max: 8, TargetLatency: time.Second})
+
+	complete(t, l, 2, 5*time.Second)
+	if got := l.Limit(); got != 1 {
+		t.Errorf("Limit() above target latency = %d, want 1", got)
+	}
+}
+
+func TestAdaptiveLimiter_ShrinksOnMemoryPressure(t *testing.T) {
+	heap := uint64(100)
+	l := newTestLimiter(AdaptiveConfig{
+		Initial:   4,
+		Max:       8,
+		MaxMemory: 200,
+		MemStats:  func() profiler.MemStats { return profiler.MemStats{HeapAlloc: heap} },
+	})
+
+	complete(t, l, 4, time.Millisecond)
+	if got := l.Limit(); got != 5 {
+		t.Fatalf("Limit() = %d, want 5", got)
+	}
+
+	heap = 500
+	complete(t, l, 2, time.Millisecond)
+	if got := l.Limit(); got != 3 {
+		t.Errorf("Limit() under memory pressure = %d, want 3", got)
+	}
+}
+
+func TestAdaptiveLimiter_BlocksAtLimit(t *testing.T) {
+	l := newTestLimiter(AdaptiveConfig{Initial: 1, Max: 1})
+
+	if err := l.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire() at limit error = %v, want deadline exceeded", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() { acquired <- l.Acquire(context.Background()) }()
+	l.Release(time.Millisecond, nil)
+
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Errorf("Acquire() after release error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Acquire() not woken by Release()")
+	}
+}
@@ -0,0 +1,68 @@
+// Package core is the part of goreview that runs anywhere, including
+// WebAssembly: it builds review prompts, splits diffs into chunks, parses
+// code structure and renders reports. It never runs git or other commands
+// and opens no databases, so browser playgrounds and editor extensions can
+// embed it; cmd/goreview-wasm exposes it to JavaScript.
+//
+// Everything else (reading repositories, calling providers, caching,
+// history) stays in the CLI.
+package core
+
+import (
+	"github.com/JNZader/goreview/goreview/internal/ast"
+	"github.com/JNZader/goreview/goreview/internal/providers"
+	"github.com/JNZader/goreview/goreview/internal/report"
+	"github.com/JNZader/goreview/goreview/internal/tokenizer"
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+// ReviewRequest is a file to review and the settings to review it with.
+type ReviewRequest = providers.ReviewRequest
+
+// Chunk is a part of a diff small enough to review in one request.
+type Chunk = tokenizer.Chunk
+
+// FileContext is the structure of a source file: its imports, functions,
+// types and variables.
+type FileContext = ast.Context
+
+// BuildPrompt returns the system and user prompts goreview sends to review
+// the request's file.
+func BuildPrompt(req *ReviewRequest) (system, user string) {
+	return providers.ReviewSystemPrompt, providers.BuildReviewPrompt(req)
+}
+
+// ChunkDiff splits a file's diff into chunks of at most maxTokens estimated
+// tokens, on function and class boundaries where possible. maxTokens <= 0
+// uses the default of 2000.
+func ChunkDiff(diff, language string, maxTokens int) []Chunk {
+	return tokenizer.NewChunker(tokenizer.ChunkerConfig{
+		MaxChunkTokens: maxTokens,
+		Language:       language,
+	}).ChunkDiff(diff)
+}
+
+// EstimateTokens estimates how many tokens text takes for the model.
+func EstimateTokens(text, model string) int {
+	return tokenizer.NewEstimatorForModel(model).EstimateTokens(text)
+}
+
+// ParseFile extracts the structure of a source file in the language.
+func ParseFile(code, filePath, language string) (*FileContext, error) {
+	return ast.NewParser(language).Parse(code, filePath)
+}
+
+// Report renders a review result as "markdown", "json", "sarif", "pdf",
+// "codeactions", or "dot" or "mermaid" for the graph of its root causes.
+func Report(result *reviewtypes.Result, format string) (string, error) {
+	reporter, err := report.NewReporter(format)
+	if err != nil {
+		return "", err
+	}
+	return reporter.Generate(result)
+}
+
+// ReportFormats returns the formats Report renders.
+func ReportFormats() []string {
+	return report.AvailableFormats()
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/JNZader/goreview/goreview/pkg/reviewtypes"
+)
+
+// TestBuildsForWebAssembly checks that neither the core nor the wasm command
+// pull in packages that run commands or open databases.
+func TestBuildsForWebAssembly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go list")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go tool not found")
+	}
+	cmd := exec.Command("go", "list", "-deps", ".", "../../cmd/goreview-wasm")
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go list: %v\n%s", err, out)
+	}
+	for _, dep := range strings.Fields(string(out)) {
+		for _, banned := range []string{"os/exec", "database/sql", "modernc.org/sqlite", "github.com/dgraph-io/badger"} {
+			if dep == banned || strings.HasPrefix(dep, banned+"/") {
+				t.Errorf("core depends on %s", dep)
+			}
+		}
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	system, user := BuildPrompt(&ReviewRequest{FilePath: "main.go", Language: "go", Diff: "+func main() {}"})
+	if system == "" || !strings.Contains(user, "File: main.go") || !strings.Contains(user, "+func main() {}") {
+		t.Errorf("prompt = %q, %q", system, user)
+	}
+}
+
+func TestParseAndChunk(t *testing.T) {
+	code := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(1)\n}\n"
+	ctx, err := ParseFile(code, "main.go", "go")
+	if err != nil || ctx.Package != "main" || len(ctx.Functions) != 1 {
+		t.Errorf("ParseFile = %+v, %v", ctx, err)
+	}
+	if chunks := ChunkDiff(code, "go", 0); len(chunks) == 0 {
+		t.Error("ChunkDiff returned no chunks")
+	}
+}
+
+func TestReport(t *testing.T) {
+	result := &reviewtypes.Result{
+		TotalIssues: 1,
+		Files: []reviewtypes.FileResult{{
+			File: "main.go",
+			Response: &reviewtypes.Response{Issues: []reviewtypes.Issue{{
+				Type: "bug", Severity: reviewtypes.SeverityError, Message: "nil dereference",
+				Location: &reviewtypes.Location{StartLine: 3}, FixedCode: "if p != nil {",
+			}}},
+		}},
+	}
+	md, err := Report(result, "markdown")
+	if err != nil || !strings.Contains(md, "[ERROR] [bug] nil dereference") {
+		t.Errorf("markdown = %q, %v", md, err)
+	}
+	sarif, err := Report(result, "sarif")
+	if err != nil || !strings.Contains(sarif, `"ruleId": "bug"`) {
+		t.Errorf("sarif = %q, %v", sarif, err)
+	}
+	pdf, err := Report(result, "pdf")
+	if err != nil || !strings.HasPrefix(pdf, "%PDF-1.4") || !strings.HasSuffix(pdf, "%%EOF\n") ||
+		!strings.Contains(pdf, "(main.go)") || !strings.Contains(pdf, "(Suggested fix: see A.1)") ||
+		!strings.Contains(pdf, "(A.1  main.go:3)") {
+		t.Errorf("pdf = %q, %v", pdf, err)
+	}
+	if _, err := Report(result, "html"); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
@@ -0,0 +1,90 @@
+package reviewtypes
+
+import "strings"
+
+// CodeActionsVersion is the version of the code actions schema written by
+// this release.
+const CodeActionsVersion = "1.0"
+
+// CodeActions are the machine-applicable fixes of a review, written by
+// goreview review --format codeactions for editor plugins and bots to apply
+// without running the review again.
+type CodeActions struct {
+	SchemaVersion string       `json:"schema_version"`
+	Actions       []CodeAction `json:"actions"`
+	// Skipped counts the issues with a fix left out because their location
+	// wasn't verified against the file
+	Skipped int `json:"skipped"`
+}
+
+// CodeAction is the fix of one issue.
+type CodeAction struct {
+	// Title is the text for quick-fix menus
+	Title string `json:"title"`
+	// Kind is "quickfix", as in the Language Server Protocol
+	Kind     string `json:"kind"`
+	IssueID  string `json:"issue_id"`
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	RuleID   string `json:"rule_id,omitempty"`
+	// Edits are applied together, each on the file as it was reviewed
+	Edits []TextEdit `json:"edits"`
+}
+
+// TextEdit replaces a range of a file with new text.
+type TextEdit struct {
+	// File is relative to the repository root
+	File    string `json:"file"`
+	Range   Range  `json:"range"`
+	NewText string `json:"new_text"`
+}
+
+// Range is the part of a file between two positions, the end excluded, as
+// in the Language Server Protocol.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Position is a 0-based line and character offset. Fixes replace whole
+// lines, so the character is 0.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// NewCodeActions returns the fixes of a result's issues that have one and a
+// verified location: each replaces the issue's lines with its fixed code.
+func NewCodeActions(result *Result) *CodeActions {
+	out := &CodeActions{SchemaVersion: CodeActionsVersion, Actions: []CodeAction{}}
+	for _, f := range result.Files {
+		if f.Response == nil {
+			continue
+		}
+		for _, issue := range f.Response.Issues {
+			if issue.FixedCode == "" {
+				continue
+			}
+			loc := issue.Location
+			if loc == nil || loc.StartLine <= 0 || loc.Unverified {
+				out.Skipped++
+				continue
+			}
+			end := max(loc.EndLine, loc.StartLine)
+			out.Actions = append(out.Actions, CodeAction{
+				Title: "Fix: " + issue.Message, Kind: "quickfix", IssueID: issue.ID,
+				Type: issue.Type, Severity: issue.Severity, Message: issue.Message, RuleID: issue.RuleID,
+				Edits: []TextEdit{{
+					File: f.File,
+					Range: Range{
+						Start: Position{Line: loc.StartLine - 1},
+						End:   Position{Line: end},
+					},
+					NewText: strings.TrimRight(issue.FixedCode, "\r\n") + "\n",
+				}},
+			})
+		}
+	}
+	return out
+}
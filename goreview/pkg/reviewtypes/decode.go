@@ -0,0 +1,60 @@
+package reviewtypes
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//go:embed schema/*.json
+var schemas embed.FS
+
+// Decode reads a result document. Documents of another major schema version
+// are rejected, since their fields may have changed meaning.
+func Decode(r io.Reader) (*Result, error) {
+	var result Result
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, err
+	}
+	if err := CheckVersion(result.SchemaVersion); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CheckVersion reports whether documents of the schema version can be
+// decoded with this package. An empty version is the unversioned 1.0.
+func CheckVersion(version string) error {
+	if major(version) != major(SchemaVersion) {
+		return fmt.Errorf("unsupported schema version %s (supported: %s.x)", version, major(SchemaVersion))
+	}
+	return nil
+}
+
+// Schema returns the JSON Schema of the result document for the schema
+// version, or for SchemaVersion when empty.
+func Schema(version string) ([]byte, error) {
+	if version == "" {
+		version = SchemaVersion
+	}
+	data, err := schemas.ReadFile("schema/result-v" + major(version) + ".schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("no schema for version %s", version)
+	}
+	return data, nil
+}
+
+// CodeActionsSchema returns the JSON Schema of the code actions document.
+func CodeActionsSchema() ([]byte, error) {
+	return schemas.ReadFile("schema/codeactions-v" + major(CodeActionsVersion) + ".schema.json")
+}
+
+func major(version string) string {
+	if version == "" {
+		return "1"
+	}
+	m, _, _ := strings.Cut(version, ".")
+	return m
+}
@@ -0,0 +1,162 @@
+package reviewtypes
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestSchemaCoversTypes checks that every JSON field of the types is in the
+// schema and the other way around.
+func TestSchemaCoversTypes(t *testing.T) {
+	data, err := Schema("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSchema(t, data, map[string]any{
+		"":                 Result{},
+		"file_result":      FileResult{},
+		"response":         Response{},
+		"issue":            Issue{},
+		"location":         Location{},
+		"root_cause":       RootCause{},
+		"issue_type":       IssueTypeInfo{},
+		"diff_stats":       DiffStats{},
+		"environment":      Environment{},
+		"quality":          Quality{},
+		"review_effort":    Effort{},
+		"file_effort":      FileEffort{},
+		"filtered_file":    FilteredFile{},
+		"change_scope":     Scope{},
+		"scope_category":   ScopeCategory{},
+		"scope_hunk":       ScopeHunk{},
+		"function_metrics": FunctionMetrics{},
+		"debt_item":        DebtItem{},
+		"generated_block":  GeneratedBlock{},
+		"gate_result":      GateResult{},
+		"shard":            Shard{},
+		"unreviewed_file":  UnreviewedFile{},
+		"remainder":        Remainder{},
+		"remainder_file":   RemainderFile{},
+		"theme":            Theme{},
+		"theme_example":    ThemeExample{},
+		"context_budget":   ContextBudget{},
+		"guardrail":        Guardrail{},
+		"trivial":          Trivial{},
+		"ast_coverage":     ASTCoverage{},
+		"triage":           Triage{},
+		"failure":          Failure{},
+	})
+}
+
+func TestCodeActionsSchemaCoversTypes(t *testing.T) {
+	data, err := CodeActionsSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSchema(t, data, map[string]any{
+		"":            CodeActions{},
+		"code_action": CodeAction{},
+		"text_edit":   TextEdit{},
+		"range":       Range{},
+		"position":    Position{},
+	})
+}
+
+// checkSchema checks that the schema has a definition for each type, under
+// its key, with the type's JSON fields; "" is the document itself.
+func checkSchema(t *testing.T, data []byte, types map[string]any) {
+	t.Helper()
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Defs       map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	if len(types) != len(schema.Defs)+1 {
+		t.Errorf("schema has %d definitions, want %d", len(schema.Defs), len(types)-1)
+	}
+	for def, v := range types {
+		props := schema.Properties
+		if def != "" {
+			props = schema.Defs[def].Properties
+		}
+		if got, want := keys(props), jsonFields(reflect.TypeOf(v)); !reflect.DeepEqual(got, want) {
+			t.Errorf("schema %q properties = %v, want %v", def, got, want)
+		}
+	}
+}
+
+func keys(m map[string]json.RawMessage) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func jsonFields(t reflect.Type) []string {
+	var out []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestDecode(t *testing.T) {
+	doc := `{"schema_version":"1.19","total_issues":1,"duration":5,"stats":{"files_changed":1,"additions":2,"deletions":0},
+		"files":[{"file":"a.go","cached":false,"error":"timeout","response":{"issues":[{"id":"1","type":"bug","severity":"error","message":"m"}],"summary":"","score":70,"tokens_used":0,"processing_time_ms":0}}],
+		"future_field":true}`
+	result, err := Decode(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if result.Files[0].Error != "timeout" || result.Files[0].Response.Issues[0].Severity != SeverityError {
+		t.Errorf("Decode() = %+v", result)
+	}
+
+	// Unversioned documents are 1.0; other majors are rejected
+	if _, err := Decode(strings.NewReader(`{"files":[]}`)); err != nil {
+		t.Errorf("Decode() of an unversioned document error = %v", err)
+	}
+	if _, err := Decode(strings.NewReader(`{"schema_version":"2.0"}`)); err == nil {
+		t.Error("Decode() accepted schema version 2.0")
+	}
+	if _, err := Schema("2.0"); err == nil {
+		t.Error("Schema() returned a schema for version 2.0")
+	}
+}
+
+func TestNewCodeActions(t *testing.T) {
+	fix := func(id string, loc *Location) Issue {
+		return Issue{ID: id, Type: "bug", Severity: SeverityError, Message: "close rows", FixedCode: "defer rows.Close()\n\n", Location: loc}
+	}
+	result := &Result{Files: []FileResult{{File: "db/query.go", Response: &Response{Issues: []Issue{
+		fix("a", &Location{StartLine: 12, EndLine: 13}),
+		fix("b", &Location{StartLine: 20}),
+		fix("c", &Location{StartLine: 30, Unverified: true}),
+		fix("d", nil),
+		{ID: "e", Message: "no fix", Location: &Location{StartLine: 5}},
+	}}}}}
+
+	actions := NewCodeActions(result)
+	if actions.SchemaVersion != CodeActionsVersion || len(actions.Actions) != 2 || actions.Skipped != 2 {
+		t.Fatalf("NewCodeActions() = %+v", actions)
+	}
+	edit := actions.Actions[0].Edits[0]
+	want := TextEdit{File: "db/query.go", Range: Range{Start: Position{Line: 11}, End: Position{Line: 13}}, NewText: "defer rows.Close()\n"}
+	if edit != want || actions.Actions[0].Kind != "quickfix" {
+		t.Errorf("edit = %+v, want %+v", edit, want)
+	}
+	if r := actions.Actions[1].Edits[0].Range; r.Start.Line != 19 || r.End.Line != 20 {
+		t.Errorf("single-line range = %+v, want lines 19-20", r)
+	}
+}
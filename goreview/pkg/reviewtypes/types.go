@@ -0,0 +1,480 @@
+// Package reviewtypes is the public, versioned form of goreview's review
+// results: the document written by "goreview review --format json". Tools
+// and plugins consuming that output should decode it with this package
+// instead of depending on goreview's internal packages.
+//
+// The schema follows SchemaVersion. Minor versions only add optional
+// fields; a new major version may rename or remove fields. The JSON Schema
+// of each version is available through Schema.
+package reviewtypes
+
+import "time"
+
+// SchemaVersion is the version of the result schema written by this release.
+const SchemaVersion = "1.19"
+
+// Result is a complete review.
+type Result struct {
+	// SchemaVersion is the version of the schema the document follows.
+	// Documents written before versioning have none and follow 1.0.
+	SchemaVersion string `json:"schema_version,omitempty"`
+
+	TotalIssues int `json:"total_issues"`
+	// Duration is the review's duration in nanoseconds
+	Duration time.Duration `json:"duration"`
+	Files    []FileResult  `json:"files"`
+	Stats    DiffStats     `json:"stats"`
+	Summary  string        `json:"summary,omitempty"`
+	// Filtered lists the changed files left out of the review and why
+	// (since 1.5)
+	Filtered []FilteredFile `json:"filtered_files,omitempty"`
+	// IssueTypes is the custom issue type taxonomy, when configured
+	IssueTypes []IssueTypeInfo `json:"issue_types,omitempty"`
+	// Environment records what produced the review
+	Environment *Environment `json:"environment,omitempty"`
+	// Quality tells how reliable the results are
+	Quality *Quality `json:"quality,omitempty"`
+	// Effort estimates the human review effort (since 1.3)
+	Effort *Effort `json:"review_effort,omitempty"`
+	// Scope flags unrelated changes bundled together (since 1.4)
+	Scope *Scope `json:"change_scope,omitempty"`
+	// Gates are the outcomes of the configured CI gates
+	Gates []GateResult `json:"gates,omitempty"`
+	// Shard is the part of the changed files reviewed, for reviews split
+	// across CI jobs (since 1.7)
+	Shard *Shard `json:"shard,omitempty"`
+	// Unreviewed lists the files left unreviewed when the time budget ran
+	// out (since 1.8) or the provider blocked them (since 1.18), riskiest
+	// first
+	Unreviewed []UnreviewedFile `json:"unreviewed_files,omitempty"`
+	// Remainder are the lowest-priority files of a change over the token
+	// budget, summarized instead of reviewed (since 1.19)
+	Remainder *Remainder `json:"summarized_remainder,omitempty"`
+	// Themes group related issues of reviews with many, the biggest first
+	// (since 1.9)
+	Themes []Theme `json:"themes,omitempty"`
+	// Errors are the review's failures with stable codes; a run that
+	// failed before reviewing writes a document with only its error
+	// (since 1.17)
+	Errors []Failure `json:"errors,omitempty"`
+}
+
+// FileResult is the review of a single file.
+type FileResult struct {
+	File     string    `json:"file"`
+	Response *Response `json:"response,omitempty"`
+	// Error is why the file couldn't be reviewed
+	Error string `json:"error,omitempty"`
+	// ErrorCode is the Error's code, see Failure (since 1.17)
+	ErrorCode string `json:"error_code,omitempty"`
+	Cached    bool   `json:"cached"`
+	// Normalized is set when the cache hit matched only after diff normalization
+	Normalized bool `json:"normalized,omitempty"`
+	// RulesInScope lists the IDs of the rules that applied to the file
+	RulesInScope []string `json:"rules_in_scope,omitempty"`
+	// ReusedHunks counts hunks whose findings were carried over from the
+	// previous branch review
+	ReusedHunks int `json:"reused_hunks,omitempty"`
+	// Metrics holds the size and complexity of the changed functions
+	Metrics []FunctionMetrics `json:"metrics,omitempty"`
+	// Debt lists the TODO/FIXME/HACK comments added to the file
+	Debt []DebtItem `json:"debt,omitempty"`
+	// Generated lists the added blocks that look AI-generated or pasted
+	Generated []GeneratedBlock `json:"generated,omitempty"`
+	// Protected names the protected path area containing the file
+	Protected string `json:"protected,omitempty"`
+	// Budget tells how the file's review requests spent the model's context
+	// window (since 1.1)
+	Budget *ContextBudget `json:"context_budget,omitempty"`
+	// Guardrail is set when a guardrail summarized or skipped the file
+	// instead of reviewing it (since 1.6)
+	Guardrail *Guardrail `json:"guardrail,omitempty"`
+	// Trivial is set when the file's change was trivial and approved
+	// without a review by the model (since 1.11)
+	Trivial *Trivial `json:"trivial,omitempty"`
+	// AST tells how much structure the parser extracted from the file, when
+	// requested with --ast-diagnostics (since 1.13)
+	AST *ASTCoverage `json:"ast,omitempty"`
+}
+
+// Guardrail tells why a file was summarized or skipped instead of reviewed.
+type Guardrail struct {
+	// Reason is "lockfile", "vendored", "max_lines" or "max_tokens"
+	Reason string `json:"reason"`
+	// Detail explains the reason, like "2400 changed lines, limit 2000"
+	Detail string `json:"detail"`
+	// Action is "summarize" or "skip"
+	Action string `json:"action"`
+	// Summary is the model's one-paragraph summary of the change
+	Summary string `json:"summary,omitempty"`
+}
+
+// Trivial tells why a file was auto-approved instead of reviewed.
+type Trivial struct {
+	// Kind is "comments", "formatting" or "version_bump"
+	Kind string `json:"kind"`
+	// Detail describes the change, like "comment-only change"
+	Detail string `json:"detail"`
+}
+
+// Response holds the issues found in a file.
+type Response struct {
+	Issues  []Issue `json:"issues"`
+	Summary string  `json:"summary"`
+	// Score is 0-100
+	Score      int `json:"score"`
+	TokensUsed int `json:"tokens_used"`
+	// ProcessingTime is in milliseconds
+	ProcessingTime int64 `json:"processing_time_ms"`
+	// ParseFailures counts provider responses that weren't valid JSON
+	ParseFailures int `json:"parse_failures,omitempty"`
+	// TruncatedChunks counts diff chunks the model may not have fully seen
+	TruncatedChunks int `json:"truncated_chunks,omitempty"`
+}
+
+// Severities, from lowest to highest
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityError    = "error"
+	SeverityCritical = "critical"
+)
+
+// Issue is a review finding.
+type Issue struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Severity is one of the Severity constants
+	Severity   string     `json:"severity"`
+	Message    string     `json:"message"`
+	Suggestion string     `json:"suggestion,omitempty"`
+	Location   *Location  `json:"location,omitempty"`
+	RuleID     string     `json:"rule_id,omitempty"`
+	FixedCode  string     `json:"fixed_code,omitempty"`
+	RootCause  *RootCause `json:"root_cause,omitempty"`
+	// Code is the offending code as cited by the reviewer
+	Code string `json:"code,omitempty"`
+	// Transcript is the ID of the saved provider transcript the issue came
+	// from (since 1.1)
+	Transcript string `json:"transcript,omitempty"`
+	// Triage is the issue's triage in the history database (since 1.1)
+	Triage *Triage `json:"triage,omitempty"`
+	// CWE is the weakness of a security issue, like CWE-89 (since 1.2)
+	CWE string `json:"cwe,omitempty"`
+	// OWASP is the OWASP Top 10 category of a security issue, like
+	// A03:2021 (since 1.2)
+	OWASP string `json:"owasp,omitempty"`
+	// Calibration notes a severity adjusted to how the team triaged earlier
+	// findings of the same rule, like "lowered from error: 9 of 10 triaged
+	// findings of the rule marked won't fix" (since 1.15)
+	Calibration string `json:"calibration,omitempty"`
+}
+
+// Triage is the triage of an issue, kept across reviews.
+type Triage struct {
+	// IssueID is the history record to triage the issue by
+	IssueID int64 `json:"issue_id"`
+	// Status is open, acknowledged, wontfix or resolved
+	Status   string `json:"status"`
+	Assignee string `json:"assignee,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+	// Recurrence notes an issue found again after being acknowledged or
+	// marked won't fix, such as "previously acknowledged, was error"
+	Recurrence string `json:"recurrence,omitempty"`
+}
+
+// Location is a position in a file.
+type Location struct {
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	StartCol  int    `json:"start_col,omitempty"`
+	EndCol    int    `json:"end_col,omitempty"`
+	// Unverified is set when the line numbers are the reviewer's unchecked
+	// guess
+	Unverified bool `json:"unverified,omitempty"`
+}
+
+// RootCause is the root cause analysis of an issue.
+type RootCause struct {
+	Description     string   `json:"description"`
+	OriginFile      string   `json:"origin_file,omitempty"`
+	OriginLine      int      `json:"origin_line,omitempty"`
+	PropagationPath []string `json:"propagation_path,omitempty"`
+	RelatedIssues   []string `json:"related_issues,omitempty"`
+	Recommendation  string   `json:"recommendation,omitempty"`
+}
+
+// IssueTypeInfo describes an issue type of the taxonomy.
+type IssueTypeInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// DiffStats summarizes the reviewed diff.
+type DiffStats struct {
+	FilesChanged int `json:"files_changed"`
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+}
+
+// Environment records what produced a review.
+type Environment struct {
+	Version     string  `json:"goreview_version"`
+	Provider    string  `json:"provider"`
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature"`
+	Preset      string  `json:"preset,omitempty"`
+	RulesHash   string  `json:"rules_hash,omitempty"`
+	PromptHash  string  `json:"prompt_hash,omitempty"`
+	CommitSHA   string  `json:"commit_sha,omitempty"`
+}
+
+// Quality tells how reliable a review's results are.
+type Quality struct {
+	// Score is 0-100, where 100 means nothing was lost
+	Score    int  `json:"score"`
+	Degraded bool `json:"degraded"`
+
+	Issues        int `json:"issues"`
+	LocatedIssues int `json:"located_issues"`
+	IssuesWithFix int `json:"issues_with_fix"`
+	// LocationRate and FixRate are percentages of Issues
+	LocationRate float64 `json:"location_rate"`
+	FixRate      float64 `json:"fix_rate"`
+
+	ParseFailures   int `json:"parse_failures"`
+	TruncatedChunks int `json:"truncated_chunks"`
+	FailedFiles     int `json:"failed_files"`
+	// UnreviewedFiles counts the files left unreviewed (since 1.8)
+	UnreviewedFiles int `json:"unreviewed_files,omitempty"`
+	// SummarizedFiles counts the files over the token budget, summarized
+	// instead of reviewed (since 1.19)
+	SummarizedFiles int `json:"summarized_files,omitempty"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ASTCoverage tells how much structure the parser extracted from a file.
+type ASTCoverage struct {
+	// Parser is the language parser used, "generic" for the fallback of
+	// languages without one
+	Parser    string `json:"parser"`
+	Functions int    `json:"functions"`
+	// Classes counts classes, structs and interfaces
+	Classes int `json:"classes"`
+	// Errors are the problems that make the extraction unreliable
+	Errors []string `json:"errors,omitempty"`
+}
+
+// FunctionMetrics is the size and complexity of a changed function.
+type FunctionMetrics struct {
+	Name       string `json:"name"`
+	StartLine  int    `json:"start_line"`
+	EndLine    int    `json:"end_line"`
+	Length     int    `json:"length"`
+	Cyclomatic int    `json:"cyclomatic"`
+	Cognitive  int    `json:"cognitive"`
+}
+
+// DebtItem is a TODO/FIXME/HACK comment.
+type DebtItem struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Marker string `json:"marker"`
+	Text   string `json:"text"`
+	Owner  string `json:"owner,omitempty"`
+	Ticket string `json:"ticket,omitempty"`
+}
+
+// GeneratedBlock is an added block that looks AI-generated or pasted.
+type GeneratedBlock struct {
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+	// Kind is "ai_marker" or "paste"
+	Kind   string `json:"kind"`
+	Marker string `json:"marker,omitempty"`
+}
+
+// Effort estimates the human effort of reviewing a change. Files are
+// listed in the suggested review order.
+type Effort struct {
+	Minutes int          `json:"minutes"`
+	Files   []FileEffort `json:"files"`
+}
+
+// FileEffort is the estimated effort of reviewing one file.
+type FileEffort struct {
+	File       string `json:"file"`
+	Minutes    int    `json:"minutes"`
+	Lines      int    `json:"lines"`
+	Complexity int    `json:"complexity,omitempty"`
+	Issues     int    `json:"issues"`
+	// Severity is the worst severity among the file's issues
+	Severity string `json:"severity,omitempty"`
+}
+
+// Scope flags unrelated changes bundled into a change and suggests how to
+// split them out.
+type Scope struct {
+	// Categories lists the changed file categories, the main one first
+	Categories []ScopeCategory `json:"categories"`
+	// Unrelated counts the categories unrelated to the main one
+	Unrelated int `json:"unrelated"`
+	// Hunks lists the formatting-only and rename hunks mixed with logic
+	// changes
+	Hunks       []ScopeHunk `json:"hunks,omitempty"`
+	Suggestions []string    `json:"suggestions,omitempty"`
+}
+
+// ScopeCategory is the part of a change in one file category: source,
+// test, docs, config, dependencies, build or ci.
+type ScopeCategory struct {
+	Category  string   `json:"category"`
+	Files     []string `json:"files"`
+	Lines     int      `json:"lines"`
+	Unrelated bool     `json:"unrelated,omitempty"`
+}
+
+// ScopeHunk is a hunk that doesn't belong with the logic changes.
+type ScopeHunk struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Kind is "formatting" or "rename"
+	Kind string `json:"kind"`
+	// Rename is "old -> new" for renames
+	Rename string `json:"rename,omitempty"`
+}
+
+// FilteredFile is a changed file left out of the review.
+type FilteredFile struct {
+	File string `json:"file"`
+	// Reason is "deleted", "binary", "not_included" (no include pattern
+	// matched), "excluded" (an exclude pattern matched), "ignore_file" or,
+	// since 1.10, "notebook_outputs" (only a notebook's outputs or metadata
+	// changed) or, since 1.14, "not_flagged" (outside the files given to
+	// --only-flagged) or, since 1.16, "not_focused" (no changes inside the
+	// symbols given to --focus-symbols)
+	Reason string `json:"reason"`
+	// Pattern is the deciding pattern; for "ignore_file", the ignore file
+	// line, like ".goreviewignore:3: build/"
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// Shard tells which part of the changed files a review covered.
+type Shard struct {
+	// Index is the 1-based shard number
+	Index int `json:"index"`
+	Total int `json:"total"`
+	// Files counts the files assigned to the shard
+	Files int `json:"files"`
+}
+
+// UnreviewedFile is a file that would have been reviewed, but wasn't.
+type UnreviewedFile struct {
+	File string `json:"file"`
+	// Reason is "time_budget", or "safety_block" for files the provider's
+	// safety filters refused to review (since 1.18)
+	Reason string `json:"reason"`
+	// Risk rates how much the change needed review: a point per 10 changed
+	// lines up to 500, plus 50 in protected paths and 25 in paths about
+	// authentication, secrets, payments or migrations
+	Risk int `json:"risk"`
+}
+
+// Remainder is the part of a change over the token budget: its files are
+// summarized together, not reviewed.
+type Remainder struct {
+	// Budget is the token budget of the review
+	Budget int `json:"budget"`
+	// Tokens is the estimated size of the summarized files' diffs
+	Tokens int `json:"tokens"`
+	// Summary describes what the files change, in a short paragraph
+	Summary string `json:"summary"`
+	// Files are the summarized files, highest priority first
+	Files []RemainderFile `json:"files"`
+}
+
+// RemainderFile is a file summarized, not reviewed.
+type RemainderFile struct {
+	File      string `json:"file"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	// Tokens is the estimated size of the file's diff
+	Tokens int `json:"tokens"`
+}
+
+// Theme is a group of related issues, like the unchecked errors of a
+// package.
+type Theme struct {
+	// Title names the theme from its issue type, keywords and directory
+	Title string `json:"title"`
+	// Type is the most common issue type of the theme
+	Type string `json:"type"`
+	// Severity is the worst severity among its issues
+	Severity string `json:"severity"`
+	Issues   int    `json:"issues"`
+	Files    int    `json:"files"`
+	// Keywords are the words most of its issue messages share
+	Keywords []string `json:"keywords,omitempty"`
+	// Dir is the directory holding all its files, when not the root
+	Dir string `json:"dir,omitempty"`
+	// Examples are its most representative issues, the worst first
+	Examples []ThemeExample `json:"examples"`
+}
+
+// ThemeExample is an issue shown as an example of its theme.
+type ThemeExample struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Failure is an error of the review. Code is stable across releases, like
+// "provider.rate_limited"; Category is its first part: config, provider,
+// git, parse or unclassified.
+type Failure struct {
+	Code     string `json:"code"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+	// File is the file the failure is about; empty for the whole run
+	File string `json:"file,omitempty"`
+}
+
+// GateResult is the outcome of a CI gate.
+type GateResult struct {
+	Name   string `json:"name"`
+	Expr   string `json:"expr"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ContextBudget tells how a file's review requests spent the model's context
+// window, in estimated tokens.
+type ContextBudget struct {
+	ContextWindow  int `json:"context_window"`
+	ResponseTokens int `json:"response_tokens"`
+	// ChunkLimit is the most diff tokens sent in one request
+	ChunkLimit int `json:"chunk_limit"`
+
+	Instructions int `json:"instructions"`
+	Diff         int `json:"diff"`
+	Rules        int `json:"rules"`
+	Knowledge    int `json:"knowledge"`
+	Focus        int `json:"focus"`
+	// Related is the text of neighboring files in full-file reviews
+	// (since 1.1)
+	Related int `json:"related,omitempty"`
+	// Summary is the structured summary of the change (since 1.12)
+	Summary int `json:"summary,omitempty"`
+
+	Chunks int `json:"chunks"`
+	// Prompt is the size of the largest request's prompt
+	Prompt int `json:"prompt"`
+	// Overflow is how far the largest request plus the response reserve
+	// exceeds the context window
+	Overflow int `json:"overflow,omitempty"`
+	// Truncated lists the parts cut to fit: "knowledge", "related" or "diff"
+	Truncated []string `json:"truncated,omitempty"`
+}